@@ -0,0 +1,63 @@
+// Package chat posts summary cards to Slack and Microsoft Teams channels through their
+// incoming webhooks.
+package chat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// DefaultTimeout bounds one post to a chat service
+const DefaultTimeout = 10 * time.Second
+
+// maxErrorBody bounds how much of a refused post's response is kept in the error
+const maxErrorBody = 256
+
+// Notifiers returns a notifier for each supported chat service, posting with client
+func Notifiers(client *http.Client) map[models.NotificationKind]ports.Notifier {
+	return map[models.NotificationKind]ports.Notifier{
+		models.NotifySlack: NewSlackNotifier(client),
+		models.NotifyTeams: NewTeamsNotifier(client),
+	}
+}
+
+// postJSON posts payload to an incoming webhook, failing unless it answers 2xx
+func postJSON(ctx context.Context, client *http.Client, webhookURL string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("chat service answered %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
+
+// clientOrDefault is client, or the default client when nil
+func clientOrDefault(client *http.Client) *http.Client {
+	if client == nil {
+		return http.DefaultClient
+	}
+	return client
+}
@@ -0,0 +1,118 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gohypo/models"
+)
+
+var testNotification = models.Notification{
+	Title:    "Hypothesis validated",
+	Text:     "Discounts <10% grow basket size",
+	Facts:    []models.NotificationFact{{Label: "Rigor", Value: "decision"}, {Label: "Referees passed", Value: "3 of 3"}},
+	LinkURL:  "https://gohypo.example.com/hypotheses/h1",
+	LinkText: "Open validation",
+}
+
+// capture serves one post, decoding its body into v
+func capture(t *testing.T, status int, v interface{}) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+			t.Errorf("failed to decode post: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+}
+
+// TestSlackNotifierPostsBlocks verifies the card's header, escaped text, fields and link button
+func TestSlackNotifierPostsBlocks(t *testing.T) {
+	var msg struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type string `json:"type"`
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+			Fields   []struct{ Text string } `json:"fields"`
+			Elements []struct{ URL string }  `json:"elements"`
+		} `json:"blocks"`
+	}
+	server := capture(t, http.StatusOK, &msg)
+	defer server.Close()
+
+	if err := NewSlackNotifier(server.Client()).Notify(context.Background(), server.URL, testNotification); err != nil {
+		t.Fatal(err)
+	}
+	if len(msg.Blocks) != 4 || msg.Blocks[0].Type != "header" || msg.Blocks[3].Type != "actions" {
+		t.Fatalf("unexpected blocks %+v", msg.Blocks)
+	}
+	if got := msg.Blocks[1].Text.Text; got != "Discounts &lt;10% grow basket size" {
+		t.Errorf("expected escaped mrkdwn, got %q", got)
+	}
+	if len(msg.Blocks[2].Fields) != 2 || msg.Blocks[2].Fields[0].Text != "*Rigor*\ndecision" {
+		t.Errorf("unexpected fields %+v", msg.Blocks[2].Fields)
+	}
+	if msg.Blocks[3].Elements[0].URL != testNotification.LinkURL || !strings.HasPrefix(msg.Text, "Hypothesis validated") {
+		t.Errorf("unexpected link or fallback text: %+v", msg)
+	}
+}
+
+// TestTeamsNotifierPostsAdaptiveCard verifies the card's text blocks, fact set and open action
+func TestTeamsNotifierPostsAdaptiveCard(t *testing.T) {
+	var msg struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Type  string              `json:"type"`
+					Text  string              `json:"text"`
+					Facts []map[string]string `json:"facts"`
+				} `json:"body"`
+				Actions []map[string]string `json:"actions"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	server := capture(t, http.StatusAccepted, &msg)
+	defer server.Close()
+
+	if err := NewTeamsNotifier(server.Client()).Notify(context.Background(), server.URL, testNotification); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Type != "message" || len(msg.Attachments) != 1 || msg.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Fatalf("unexpected envelope %+v", msg)
+	}
+	card := msg.Attachments[0].Content
+	if card.Type != "AdaptiveCard" || len(card.Body) != 3 || card.Body[1].Text != testNotification.Text {
+		t.Fatalf("unexpected card body %+v", card.Body)
+	}
+	if facts := card.Body[2].Facts; len(facts) != 2 || facts[1]["title"] != "Referees passed" || facts[1]["value"] != "3 of 3" {
+		t.Errorf("unexpected facts %+v", facts)
+	}
+	if len(card.Actions) != 1 || card.Actions[0]["type"] != "Action.OpenUrl" || card.Actions[0]["url"] != testNotification.LinkURL {
+		t.Errorf("unexpected actions %+v", card.Actions)
+	}
+}
+
+// TestNotifierReportsRefusedPost surfaces the chat service's answer when it refuses a post
+func TestNotifierReportsRefusedPost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := NewSlackNotifier(server.Client()).Notify(context.Background(), server.URL, testNotification)
+	if err == nil || !strings.Contains(err.Error(), "403: invalid_token") {
+		t.Fatalf("expected the refusal in the error, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package chat
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// SlackNotifier posts notifications as Block Kit messages
+type SlackNotifier struct {
+	client *http.Client
+}
+
+// NewSlackNotifier creates a Slack notifier posting with client, or the default client
+func NewSlackNotifier(client *http.Client) ports.Notifier {
+	return &SlackNotifier{client: clientOrDefault(client)}
+}
+
+// Notify posts the notification to a Slack incoming webhook: a header, the text, the facts
+// as fields and a button linking back
+func (n *SlackNotifier) Notify(ctx context.Context, webhookURL string, notification models.Notification) error {
+	return postJSON(ctx, n.client, webhookURL, slackMessage(notification))
+}
+
+// slackMessage lays a notification out as Block Kit blocks, with plain text for clients
+// that show no blocks
+func slackMessage(notification models.Notification) map[string]interface{} {
+	blocks := []map[string]interface{}{
+		{"type": "header", "text": map[string]interface{}{"type": "plain_text", "text": notification.Title}},
+	}
+	if notification.Text != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": slackEscape(notification.Text)},
+		})
+	}
+	if len(notification.Facts) > 0 {
+		fields := make([]map[string]interface{}, 0, len(notification.Facts))
+		for _, fact := range notification.Facts {
+			fields = append(fields, map[string]interface{}{
+				"type": "mrkdwn",
+				"text": "*" + slackEscape(fact.Label) + "*\n" + slackEscape(fact.Value),
+			})
+		}
+		// Slack shows at most ten fields in a section
+		for len(fields) > 0 {
+			batch := fields[:min(10, len(fields))]
+			fields = fields[len(batch):]
+			blocks = append(blocks, map[string]interface{}{"type": "section", "fields": batch})
+		}
+	}
+	if notification.LinkURL != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "actions",
+			"elements": []map[string]interface{}{{
+				"type": "button",
+				"text": map[string]interface{}{"type": "plain_text", "text": notification.LinkText},
+				"url":  notification.LinkURL,
+			}},
+		})
+	}
+	return map[string]interface{}{
+		"text":   notification.Title + ": " + notification.Text,
+		"blocks": blocks,
+	}
+}
+
+// slackEscape escapes the characters Slack's mrkdwn treats as control sequences
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
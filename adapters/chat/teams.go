@@ -0,0 +1,59 @@
+package chat
+
+import (
+	"context"
+	"net/http"
+
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// TeamsNotifier posts notifications as Adaptive Cards
+type TeamsNotifier struct {
+	client *http.Client
+}
+
+// NewTeamsNotifier creates a Microsoft Teams notifier posting with client, or the default client
+func NewTeamsNotifier(client *http.Client) ports.Notifier {
+	return &TeamsNotifier{client: clientOrDefault(client)}
+}
+
+// Notify posts the notification to a Teams incoming webhook or workflow: a title, the text,
+// the facts as a fact set and an action linking back
+func (n *TeamsNotifier) Notify(ctx context.Context, webhookURL string, notification models.Notification) error {
+	return postJSON(ctx, n.client, webhookURL, teamsMessage(notification))
+}
+
+// teamsMessage wraps a notification's Adaptive Card in the message envelope Teams webhooks take
+func teamsMessage(notification models.Notification) map[string]interface{} {
+	body := []map[string]interface{}{
+		{"type": "TextBlock", "text": notification.Title, "weight": "Bolder", "size": "Medium", "wrap": true},
+	}
+	if notification.Text != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": notification.Text, "wrap": true})
+	}
+	if len(notification.Facts) > 0 {
+		facts := make([]map[string]string, 0, len(notification.Facts))
+		for _, fact := range notification.Facts {
+			facts = append(facts, map[string]string{"title": fact.Label, "value": fact.Value})
+		}
+		body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+	}
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if notification.LinkURL != "" {
+		card["actions"] = []map[string]interface{}{
+			{"type": "Action.OpenUrl", "title": notification.LinkText, "url": notification.LinkURL},
+		}
+	}
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	}
+}
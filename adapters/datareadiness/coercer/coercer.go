@@ -8,12 +8,16 @@ import (
 	"strings"
 	"time"
 
+	"gohypo/domain/core"
 	"gohypo/domain/datareadiness/ingestion"
+	"gohypo/domain/dataset"
 )
 
 // TypeCoercer handles deterministic type coercion with versioned rules
 type TypeCoercer struct {
 	config CoercionConfig
+	locale *dataset.Locale // nil until a locale is named or detected
+	zone   core.TimeZone   // zone of timestamps written without an offset
 }
 
 // CoercionConfig defines the coercion thresholds and rules
@@ -23,6 +27,8 @@ type CoercionConfig struct {
 	TimestampThreshold float64 `json:"timestamp_threshold"` // % of values that must parse as timestamps
 	MaxCategories      int     `json:"max_categories"`      // Max categories before truncation
 	NormalizeStrings   bool    `json:"normalize_strings"`   // Whether to trim/lower strings
+	Locale             string  `json:"locale,omitempty"`    // Locale of numbers and dates, or "auto" to detect it
+	TimeZone           string  `json:"time_zone,omitempty"` // IANA zone of timestamps without an offset; UTC when empty
 }
 
 // DefaultCoercionConfig returns sensible defaults
@@ -33,12 +39,40 @@ func DefaultCoercionConfig() CoercionConfig {
 		TimestampThreshold: 0.8, // 80% must parse as timestamps
 		MaxCategories:      100,
 		NormalizeStrings:   true,
+		Locale:             dataset.LocaleAuto,
 	}
 }
 
 // NewTypeCoercer creates a coercer with the given config
 func NewTypeCoercer(config CoercionConfig) *TypeCoercer {
-	return &TypeCoercer{config: config}
+	c := &TypeCoercer{config: config, zone: core.UTCZone()}
+	if locale, ok := dataset.LookupLocale(config.Locale); ok {
+		c.locale = &locale
+	}
+	if zone, err := core.ResolveTimeZone(config.TimeZone, core.TimeZoneFromSpec); err == nil {
+		c.zone = zone
+	}
+	return c
+}
+
+// TimeZone returns the zone timestamps without an offset are read in. Parsed timestamps
+// are converted to UTC, so equal instants compare equal whatever zone wrote them.
+func (c *TypeCoercer) TimeZone() core.TimeZone {
+	return c.zone
+}
+
+// UseLocale makes the coercer read numbers and dates in the given locale, typically one
+// detected from the dataset once its values are read
+func (c *TypeCoercer) UseLocale(locale dataset.Locale) {
+	c.locale = &locale
+}
+
+// Locale returns the locale numbers and dates are read in, if one is set
+func (c *TypeCoercer) Locale() (dataset.Locale, bool) {
+	if c.locale == nil {
+		return dataset.Locale{}, false
+	}
+	return *c.locale, true
 }
 
 // CoerceValue deterministically converts an unknown value to a typed Value
@@ -51,7 +85,7 @@ func (c *TypeCoercer) CoerceValue(rawValue interface{}) ingestion.Value {
 	strVal := c.toString(rawValue)
 
 	// Try numeric first (most restrictive)
-	if numericVal, ok := c.tryParseNumeric(strVal); ok {
+	if numericVal, ok := c.parseNumeric(rawValue, strVal); ok {
 		return numericVal
 	}
 
@@ -82,7 +116,7 @@ func (c *TypeCoercer) AnalyzeTypeDistribution(values []interface{}) TypeAnalysis
 			strVal := c.toString(val)
 
 			// Count how many values can be coerced to each type
-			if _, ok := c.tryParseNumeric(strVal); ok {
+			if _, ok := c.parseNumeric(val, strVal); ok {
 				analysis.NumericCount++
 			}
 			if _, ok := c.tryParseBoolean(strVal); ok {
@@ -123,6 +157,28 @@ func (c *TypeCoercer) coerceToString(strVal string) ingestion.Value {
 	return ingestion.NewStringValue(strVal)
 }
 
+// parseNumeric coerces a raw value to a number. Go numbers, such as values read back from
+// profiled rows, carry no locale and are taken as they are rather than re-parsed from text.
+func (c *TypeCoercer) parseNumeric(rawValue interface{}, strVal string) (ingestion.Value, bool) {
+	var num float64
+	switch v := rawValue.(type) {
+	case float64:
+		num = v
+	case float32:
+		num = float64(v)
+	case int:
+		num = float64(v)
+	case int64:
+		num = float64(v)
+	default:
+		return c.tryParseNumeric(strVal)
+	}
+	if math.IsInf(num, 0) || math.IsNaN(num) {
+		return ingestion.Value{}, false
+	}
+	return ingestion.NewNumericValue(num), true
+}
+
 // tryParseNumeric attempts to parse as numeric with strict rules
 // Handles international formats: parentheses for negatives, European decimals, currency symbols
 func (c *TypeCoercer) tryParseNumeric(strVal string) (ingestion.Value, bool) {
@@ -130,6 +186,14 @@ func (c *TypeCoercer) tryParseNumeric(strVal string) (ingestion.Value, bool) {
 		return ingestion.Value{}, false
 	}
 
+	// With a known locale the separators are not guessed per value
+	if c.locale != nil {
+		if val, ok := c.locale.ParseNumber(strVal); ok && !math.IsInf(val, 0) && !math.IsNaN(val) {
+			return ingestion.NewNumericValue(val), true
+		}
+		return ingestion.Value{}, false
+	}
+
 	// Trim whitespace
 	cleanVal := strings.TrimSpace(strVal)
 
@@ -163,7 +227,7 @@ func (c *TypeCoercer) tryParseNumeric(strVal string) (ingestion.Value, bool) {
 		// Count digits after comma - if <= 2, likely European decimal
 		commaIdx := strings.LastIndex(cleanVal, ",")
 		afterComma := cleanVal[commaIdx+1:]
-		if len(afterComma) <= 3 && strings.Count(afterComma, "0123456789") == len(afterComma) {
+		if len(afterComma) <= 3 && strings.Trim(afterComma, "0123456789") == "" {
 			// Replace comma with period for decimal, remove periods/spaces as thousands separators
 			cleanVal = strings.ReplaceAll(cleanVal, ".", "")
 			cleanVal = strings.ReplaceAll(cleanVal, " ", "")
@@ -223,27 +287,35 @@ func (c *TypeCoercer) tryParseTimestamp(strVal string) (ingestion.Value, bool) {
 		return ingestion.Value{}, false
 	}
 
-	// Common timestamp formats to try
-	formats := []string{
-		time.RFC3339,
-		"2006-01-02T15:04:05",
-		"2006-01-02 15:04:05",
-		"2006-01-02",
-		"01/02/2006",
-		"2006/01/02",
-		"02-Jan-2006",
-	}
+	// A known locale decides whether 03/04/2024 is March or April, so the month-first
+	// format below must not get a second try
+	if c.locale != nil {
+		if t, ok := c.locale.ParseDateIn(strVal, c.zone.Location()); ok {
+			return ingestion.NewTimestampValue(t.UTC()), true
+		}
+	} else {
+		// Common timestamp formats to try
+		formats := []string{
+			time.RFC3339,
+			"2006-01-02T15:04:05",
+			"2006-01-02 15:04:05",
+			"2006-01-02",
+			"01/02/2006",
+			"2006/01/02",
+			"02-Jan-2006",
+		}
 
-	for _, format := range formats {
-		if t, err := time.Parse(format, strVal); err == nil {
-			return ingestion.NewTimestampValue(t), true
+		for _, format := range formats {
+			if t, err := time.ParseInLocation(format, strVal, c.zone.Location()); err == nil {
+				return ingestion.NewTimestampValue(t.UTC()), true
+			}
 		}
 	}
 
 	// Try Unix timestamp
 	if unixVal, err := strconv.ParseInt(strVal, 10, 64); err == nil {
 		if unixVal > 0 && unixVal < 2147483647 { // Reasonable Unix timestamp range
-			t := time.Unix(unixVal, 0)
+			t := time.Unix(unixVal, 0).UTC()
 			return ingestion.NewTimestampValue(t), true
 		}
 	}
@@ -0,0 +1,211 @@
+package jsonevents
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"gohypo/adapters/datareadiness"
+	"gohypo/adapters/datareadiness/coercer"
+	"gohypo/adapters/datareadiness/synthesizer"
+	"gohypo/domain/core"
+	"gohypo/domain/datareadiness/ingestion"
+	"gohypo/domain/datareadiness/profiling"
+)
+
+// Flattened keys tried, in order, when no entity or timestamp key is configured
+var (
+	entityKeyCandidates = []string{
+		"entity_id", "user_id", "customer_id", "account_id", "device_id", "session_id",
+		"user.id", "customer.id", "account.id", "entity.id",
+	}
+	timestampKeyCandidates = []string{
+		"observed_at", "timestamp", "event_time", "occurred_at", "created_at", "time", "ts", "date",
+	}
+)
+
+// Options tell the ingestor what the events are about
+type Options struct {
+	Source       string // source name carried on events and profiles
+	EntityKey    string // flattened key naming the entity; detected when empty
+	TimestampKey string // flattened key holding the event time; detected when empty
+}
+
+// Result is a parsed event file with the contracts its variables were given
+type Result struct {
+	Document     *Document                   `json:"document"`
+	EntityKey    string                      `json:"entity_key"`    // empty when events were numbered instead
+	TimestampKey string                      `json:"timestamp_key"` // empty when events are stamped at ingestion
+	Events       []ingestion.CanonicalEvent  `json:"events"`
+	Skipped      int                         `json:"skipped"` // events without an entity
+	Profiles     []profiling.FieldProfile    `json:"profiles"`
+	Contracts    []synthesizer.ContractDraft `json:"contracts"`
+}
+
+// Ingestor runs JSON and JSONL event files through the readiness pipeline: flattening,
+// canonical events, profiling and contract synthesis
+type Ingestor struct {
+	coercer         *coercer.TypeCoercer
+	profiler        *datareadiness.ProfilerAdapter
+	synthesizer     *synthesizer.ContractSynthesizer
+	profilingConfig profiling.ProfilingConfig
+}
+
+// NewIngestor creates an ingestor with the given pipeline configuration
+func NewIngestor(coercionConfig coercer.CoercionConfig, profilingConfig profiling.ProfilingConfig, synthesisConfig synthesizer.SynthesisConfig) *Ingestor {
+	coercerInstance := coercer.NewTypeCoercer(coercionConfig)
+	return &Ingestor{
+		coercer:         coercerInstance,
+		profiler:        datareadiness.NewProfilerAdapter(coercerInstance),
+		synthesizer:     synthesizer.NewContractSynthesizer(synthesisConfig),
+		profilingConfig: profilingConfig,
+	}
+}
+
+// NewDefaultIngestor creates an ingestor with the pipeline's default configuration
+func NewDefaultIngestor() *Ingestor {
+	return NewIngestor(coercer.DefaultCoercionConfig(), profiling.DefaultProfilingConfig(), synthesizer.DefaultSynthesisConfig())
+}
+
+// Ingest parses an event file and synthesizes contracts for its flattened variables
+func (i *Ingestor) Ingest(ctx context.Context, r io.Reader, opts Options) (*Result, error) {
+	doc, err := Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Source == "" {
+		opts.Source = "json"
+	}
+
+	result := &Result{Document: doc, EntityKey: opts.EntityKey, TimestampKey: opts.TimestampKey}
+	if result.EntityKey == "" {
+		result.EntityKey = detectKey(doc, entityKeyCandidates, nil)
+	} else if !hasKey(doc, result.EntityKey) {
+		return nil, fmt.Errorf("entity key %q not found in events", result.EntityKey)
+	}
+	if result.TimestampKey == "" {
+		result.TimestampKey = detectKey(doc, timestampKeyCandidates, i.isTimestamp)
+	} else if !hasKey(doc, result.TimestampKey) {
+		return nil, fmt.Errorf("timestamp key %q not found in events", result.TimestampKey)
+	}
+
+	result.Events, result.Skipped = i.Events(doc, opts.Source, result.EntityKey, result.TimestampKey)
+	if len(result.Events) == 0 {
+		return nil, fmt.Errorf("no event carries entity key %q", result.EntityKey)
+	}
+
+	profilingResult, err := i.profiler.ProfileSource(ctx, opts.Source, result.Events, i.profilingConfig)
+	if err != nil {
+		return nil, fmt.Errorf("profiling failed: %w", err)
+	}
+	result.Profiles = profilingResult.Profiles
+	sort.Slice(result.Profiles, func(a, b int) bool { return result.Profiles[a].FieldKey < result.Profiles[b].FieldKey })
+	result.Contracts, err = i.synthesizer.SynthesizeContracts(result.Profiles)
+	if err != nil {
+		return nil, fmt.Errorf("contract synthesis failed: %w", err)
+	}
+	return result, nil
+}
+
+// Events turns flattened records into row-level canonical events whose payload holds every
+// variable but the entity and timestamp. Without an entity key, events are numbered as
+// entities of their own; records missing the entity are skipped and counted. Events without
+// a usable timestamp are stamped with the ingestion time.
+func (i *Ingestor) Events(doc *Document, source, entityKey, timestampKey string) ([]ingestion.CanonicalEvent, int) {
+	now := core.Now()
+	events := make([]ingestion.CanonicalEvent, 0, len(doc.Records))
+	skipped := 0
+	for n, record := range doc.Records {
+		entityID := core.ID(fmt.Sprintf("entity_%d", n+1))
+		if entityKey != "" {
+			if record[entityKey] == nil {
+				skipped++
+				continue
+			}
+			entityID = core.ID(Format(record[entityKey]))
+		}
+		observedAt := now
+		if timestampKey != "" {
+			if t, ok := i.timestamp(record[timestampKey]); ok {
+				observedAt = core.NewTimestamp(t)
+			}
+		}
+
+		payload := make(map[string]interface{}, len(record))
+		for key, value := range record {
+			if key != entityKey && key != timestampKey {
+				payload[key] = value
+			}
+		}
+		events = append(events, ingestion.CanonicalEvent{
+			EntityID:   entityID,
+			ObservedAt: observedAt,
+			Source:     source,
+			RawPayload: payload,
+		})
+	}
+	return events, skipped
+}
+
+// timestamp reads an event time from a date string or Unix seconds or milliseconds
+func (i *Ingestor) timestamp(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, false
+	case int64:
+		return unixTime(float64(v)), v > 0
+	case float64:
+		return unixTime(v), v > 0
+	}
+	coerced := i.coercer.CoerceValue(value)
+	if coerced.Type == ingestion.ValueTypeTimestamp && coerced.TimestampVal != nil {
+		return *coerced.TimestampVal, true
+	}
+	return time.Time{}, false
+}
+
+func (i *Ingestor) isTimestamp(value interface{}) bool {
+	_, ok := i.timestamp(value)
+	return ok
+}
+
+// unixTime reads values past the year 5000 in seconds as milliseconds
+func unixTime(v float64) time.Time {
+	if v > 1e11 {
+		return time.UnixMilli(int64(v)).UTC()
+	}
+	return time.Unix(int64(v), 0).UTC()
+}
+
+// detectKey is the first candidate every event carries, with each value accepted by valid
+// when given
+func detectKey(doc *Document, candidates []string, valid func(interface{}) bool) string {
+	for _, candidate := range candidates {
+		if !hasKey(doc, candidate) {
+			continue
+		}
+		ok := true
+		for _, record := range doc.Records {
+			value := record[candidate]
+			if value == nil || (valid != nil && !valid(value)) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func hasKey(doc *Document, key string) bool {
+	for _, k := range doc.Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,315 @@
+package jsonevents
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// ArrayPolicy is one way an array field is turned into scalar variables
+type ArrayPolicy string
+
+const (
+	ArrayCount  ArrayPolicy = "count"  // <path>.count: number of elements
+	ArrayLatest ArrayPolicy = "latest" // <path>.latest: the last element, flattened
+	ArrayExists ArrayPolicy = "exists" // <path>.exists: whether the array has any element
+)
+
+// ArrayField records how an array found in the events was flattened
+type ArrayField struct {
+	Path      string        `json:"path"`
+	Policies  []ArrayPolicy `json:"policies"`
+	MaxLength int           `json:"max_length"`
+	NonEmpty  int           `json:"non_empty"` // events holding at least one element
+}
+
+// Document is a JSON or JSONL event file flattened into one record per event. Nested
+// objects become dot-path keys ("device.os.name") and arrays become the count, latest and
+// exists variables their contents call for.
+type Document struct {
+	Keys    []string                 `json:"keys"`    // every flattened key, sorted
+	Records []map[string]interface{} `json:"records"` // one per event, holding every key; nil where absent
+	Arrays  []ArrayField             `json:"arrays"`
+}
+
+// Parse reads events from a JSON array of objects, a JSONL (newline-delimited) stream of
+// objects, or a single object wrapping its events in one array field such as
+// {"events": [...]}, and flattens them
+func Parse(r io.Reader) (*Document, error) {
+	raw, err := decodeRecords(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("file contains no events")
+	}
+
+	stats := make(map[string]*arrayStats)
+	for _, record := range raw {
+		observe("", record, stats)
+	}
+	policies := make(map[string][]ArrayPolicy, len(stats))
+	arrays := make([]ArrayField, 0, len(stats))
+	for path, st := range stats {
+		field := ArrayField{Path: path, Policies: st.policies(len(raw)), MaxLength: st.maxLen, NonEmpty: st.nonEmpty}
+		policies[path] = field.Policies
+		arrays = append(arrays, field)
+	}
+	sort.Slice(arrays, func(i, j int) bool { return arrays[i].Path < arrays[j].Path })
+
+	keySet := make(map[string]bool)
+	records := make([]map[string]interface{}, len(raw))
+	for i, record := range raw {
+		flat := make(map[string]interface{})
+		flatten("", record, flat, policies)
+		// An array an event does not carry has no elements
+		for path, ps := range policies {
+			for _, policy := range ps {
+				key := path + "." + string(policy)
+				if _, ok := flat[key]; ok {
+					continue
+				}
+				switch policy {
+				case ArrayCount:
+					flat[key] = int64(0)
+				case ArrayExists:
+					flat[key] = false
+				}
+			}
+		}
+		for key := range flat {
+			keySet[key] = true
+		}
+		records[i] = flat
+	}
+
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, record := range records {
+		for _, key := range keys {
+			if _, ok := record[key]; !ok {
+				record[key] = nil
+			}
+		}
+	}
+	return &Document{Keys: keys, Records: records, Arrays: arrays}, nil
+}
+
+// decodeRecords reads the top-level event objects of a file
+func decodeRecords(r io.Reader) ([]map[string]interface{}, error) {
+	br := bufio.NewReader(r)
+	first, err := firstByte(br)
+	if err == io.EOF {
+		return nil, fmt.Errorf("file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	decoder := json.NewDecoder(br)
+	decoder.UseNumber()
+	if first == '[' {
+		var values []interface{}
+		if err := decoder.Decode(&values); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return objects(values)
+	}
+	if first != '{' {
+		return nil, fmt.Errorf("expected a JSON array or objects, found %q", first)
+	}
+
+	var records []map[string]interface{}
+	for {
+		var record map[string]interface{}
+		err := decoder.Decode(&record)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON in event %d: %w", len(records)+1, err)
+		}
+		if record == nil {
+			return nil, fmt.Errorf("event %d is not a JSON object", len(records)+1)
+		}
+		records = append(records, record)
+	}
+	if len(records) == 1 {
+		if wrapped, ok := wrappedEvents(records[0]); ok {
+			return wrapped, nil
+		}
+	}
+	return records, nil
+}
+
+// firstByte peeks at the first byte past whitespace and a byte order mark
+func firstByte(br *bufio.Reader) (byte, error) {
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		br.Discard(3)
+	}
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
+
+// wrappedEvents unwraps an object holding its events in its only array of objects. An
+// object with scalar fields of its own is an event, not a wrapper.
+func wrappedEvents(record map[string]interface{}) ([]map[string]interface{}, bool) {
+	var events []map[string]interface{}
+	found := 0
+	for _, value := range record {
+		switch v := value.(type) {
+		case map[string]interface{}:
+		case []interface{}:
+			if list, err := objects(v); err == nil && len(list) > 0 {
+				events = list
+				found++
+			}
+		default:
+			return nil, false
+		}
+	}
+	return events, found == 1
+}
+
+// objects checks that every value of a top-level array is an event object
+func objects(values []interface{}) ([]map[string]interface{}, error) {
+	records := make([]map[string]interface{}, len(values))
+	for i, value := range values {
+		record, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("event %d is not a JSON object", i+1)
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// arrayStats describes one array path across all events
+type arrayStats struct {
+	nonEmpty int
+	maxLen   int
+}
+
+// policies picks the variables an array becomes: its length when that varies beyond one
+// element, its last element whenever it has one, and whether it has any when some events
+// lack it. Arrays that are always empty carry nothing and are dropped.
+func (s *arrayStats) policies(events int) []ArrayPolicy {
+	if s.nonEmpty == 0 {
+		return nil
+	}
+	var policies []ArrayPolicy
+	if s.maxLen > 1 {
+		policies = append(policies, ArrayCount)
+	}
+	policies = append(policies, ArrayLatest)
+	if s.nonEmpty < events {
+		policies = append(policies, ArrayExists)
+	}
+	return policies
+}
+
+// observe gathers array statistics under prefix. Elements other than the last are never
+// flattened, so only the last is walked.
+func observe(prefix string, value interface{}, stats map[string]*arrayStats) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			observe(join(prefix, key), child, stats)
+		}
+	case []interface{}:
+		st, ok := stats[prefix]
+		if !ok {
+			st = &arrayStats{}
+			stats[prefix] = st
+		}
+		if len(v) == 0 {
+			return
+		}
+		st.nonEmpty++
+		if len(v) > st.maxLen {
+			st.maxLen = len(v)
+		}
+		observe(prefix+"."+string(ArrayLatest), v[len(v)-1], stats)
+	}
+}
+
+// flatten writes value's scalars into out under dot-path keys
+func flatten(prefix string, value interface{}, out map[string]interface{}, policies map[string][]ArrayPolicy) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flatten(join(prefix, key), child, out, policies)
+		}
+	case []interface{}:
+		for _, policy := range policies[prefix] {
+			switch policy {
+			case ArrayCount:
+				out[prefix+".count"] = int64(len(v))
+			case ArrayExists:
+				out[prefix+".exists"] = len(v) > 0
+			case ArrayLatest:
+				if len(v) > 0 {
+					flatten(prefix+".latest", v[len(v)-1], out, policies)
+				}
+			}
+		}
+	case json.Number:
+		out[prefix] = number(v)
+	default:
+		out[prefix] = v
+	}
+}
+
+// number keeps integers exact, so long identifiers survive, and reads the rest as floats
+func number(n json.Number) interface{} {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil || errors.Is(err, strconv.ErrRange) {
+		return f
+	}
+	return string(n)
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// Format renders a flattened value as the text a spreadsheet cell would hold; absent values
+// are empty
+func Format(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
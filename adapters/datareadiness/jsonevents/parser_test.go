@@ -0,0 +1,153 @@
+package jsonevents
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleJSONL = `{"user_id": "u1", "timestamp": "2024-01-01T10:00:00Z", "device": {"os": {"name": "ios"}}, "items": [{"sku": "a", "price": 3}, {"sku": "b", "price": 5}], "tags": ["new"]}
+{"user_id": "u2", "timestamp": "2024-01-02T10:00:00Z", "device": {"os": {"name": "android"}}, "items": [{"sku": "c", "price": 7}], "tags": []}
+
+{"user_id": "u3", "timestamp": "2024-01-03T10:00:00Z", "device": {"os": {"name": "ios"}, "model": "x"}, "tags": ["old"]}
+`
+
+func TestParseFlattensJSONL(t *testing.T) {
+	doc, err := Parse(strings.NewReader(sampleJSONL))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(doc.Records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(doc.Records))
+	}
+
+	wantKeys := []string{
+		"device.model", "device.os.name",
+		"items.count", "items.exists", "items.latest.price", "items.latest.sku",
+		"tags.exists", "tags.latest",
+		"timestamp", "user_id",
+	}
+	if !reflect.DeepEqual(doc.Keys, wantKeys) {
+		t.Errorf("keys = %v, want %v", doc.Keys, wantKeys)
+	}
+
+	first, third := doc.Records[0], doc.Records[2]
+	if first["device.os.name"] != "ios" || first["items.count"] != int64(2) || first["items.latest.sku"] != "b" || first["items.latest.price"] != int64(5) {
+		t.Errorf("unexpected first record: %v", first)
+	}
+	if first["device.model"] != nil {
+		t.Errorf("absent key should be nil, got %v", first["device.model"])
+	}
+	if third["items.count"] != int64(0) || third["items.exists"] != false || third["items.latest.sku"] != nil {
+		t.Errorf("events without an array should count zero elements: %v", third)
+	}
+	if doc.Records[1]["tags.exists"] != false || third["tags.latest"] != "old" {
+		t.Errorf("unexpected tags: %v / %v", doc.Records[1], third)
+	}
+}
+
+func TestArrayPolicies(t *testing.T) {
+	doc, err := Parse(strings.NewReader(sampleJSONL))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	policies := make(map[string][]ArrayPolicy)
+	for _, field := range doc.Arrays {
+		policies[field.Path] = field.Policies
+	}
+	// Items vary in number and some events have none; tags never hold more than one
+	if want := []ArrayPolicy{ArrayCount, ArrayLatest, ArrayExists}; !reflect.DeepEqual(policies["items"], want) {
+		t.Errorf("items policies = %v, want %v", policies["items"], want)
+	}
+	if want := []ArrayPolicy{ArrayLatest, ArrayExists}; !reflect.DeepEqual(policies["tags"], want) {
+		t.Errorf("tags policies = %v, want %v", policies["tags"], want)
+	}
+}
+
+func TestParseTopLevelShapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		records int
+		wantErr bool
+	}{
+		{"array", `[{"id": 1}, {"id": 2}]`, 2, false},
+		{"wrapped", `{"events": [{"id": 1}, {"id": 2}, {"id": 3}], "meta": {"page": 1}}`, 3, false},
+		{"single event with an array of objects", `{"id": 1, "items": [{"sku": "a"}]}`, 1, false},
+		{"byte order mark", "\ufeff{\"id\": 1}\n{\"id\": 2}", 2, false},
+		{"empty", "  \n", 0, true},
+		{"array of scalars", `[1, 2]`, 0, true},
+		{"broken line", "{\"id\": 1}\n{\"id\": \n", 0, true},
+		{"csv", "id,value\n1,2\n", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(doc.Records) != tt.records {
+				t.Errorf("expected %d records, got %d", tt.records, len(doc.Records))
+			}
+		})
+	}
+}
+
+func TestParseKeepsLongIntegers(t *testing.T) {
+	doc, err := Parse(strings.NewReader(`{"account_id": 9007199254740993, "amount": 12.5}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := Format(doc.Records[0]["account_id"]); got != "9007199254740993" {
+		t.Errorf("account_id = %s", got)
+	}
+	if got := Format(doc.Records[0]["amount"]); got != "12.5" {
+		t.Errorf("amount = %s", got)
+	}
+}
+
+func TestIngestSynthesizesContracts(t *testing.T) {
+	result, err := NewDefaultIngestor().Ingest(context.Background(), strings.NewReader(sampleJSONL), Options{Source: "events"})
+	if err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+	if result.EntityKey != "user_id" || result.TimestampKey != "timestamp" {
+		t.Errorf("detected entity %q and timestamp %q", result.EntityKey, result.TimestampKey)
+	}
+	if len(result.Events) != 3 || result.Events[1].EntityID != "u2" {
+		t.Fatalf("unexpected events: %+v", result.Events)
+	}
+	if got := result.Events[2].ObservedAt.Time().Format("2006-01-02"); got != "2024-01-03" {
+		t.Errorf("observed at %s", got)
+	}
+	if _, ok := result.Events[0].RawPayload["user_id"]; ok {
+		t.Errorf("entity key should not be a variable")
+	}
+
+	contracts := make(map[string]bool)
+	for _, draft := range result.Contracts {
+		contracts[draft.VariableKey] = true
+		if draft.Source != "events" {
+			t.Errorf("contract %s has source %q", draft.VariableKey, draft.Source)
+		}
+	}
+	for _, key := range []string{"device.os.name", "items.count", "items.latest.price"} {
+		if !contracts[key] {
+			t.Errorf("expected a contract for %s, got %v", key, contracts)
+		}
+	}
+}
+
+func TestIngestRejectsUnknownEntityKey(t *testing.T) {
+	_, err := NewDefaultIngestor().Ingest(context.Background(), strings.NewReader(sampleJSONL), Options{EntityKey: "account_id"})
+	if err == nil {
+		t.Fatal("expected an error for an entity key the events do not carry")
+	}
+}
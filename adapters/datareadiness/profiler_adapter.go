@@ -36,6 +36,11 @@ func (p *ProfilerAdapter) ProfileSource(ctx context.Context, sourceName string,
 		}, nil
 	}
 
+	// Cell-level events (one field per event, no payload) are pivoted into rows
+	if events[0].RawPayload == nil {
+		events = pivotCellEvents(events)
+	}
+
 	// Extract field names from first event's raw payload
 	firstEvent := events[0]
 	if firstEvent.RawPayload == nil {
@@ -77,6 +82,43 @@ func (p *ProfilerAdapter) ProfileSource(ctx context.Context, sourceName string,
 	}, nil
 }
 
+// pivotCellEvents groups per-cell events by entity into row events whose RawPayload
+// holds every field, preserving first-seen entity order
+func pivotCellEvents(events []ingestion.CanonicalEvent) []ingestion.CanonicalEvent {
+	rowIndex := make(map[string]int)
+	var rows []ingestion.CanonicalEvent
+
+	for _, event := range events {
+		if event.FieldKey == "" {
+			continue
+		}
+		idx, ok := rowIndex[string(event.EntityID)]
+		if !ok {
+			idx = len(rows)
+			rowIndex[string(event.EntityID)] = idx
+			rows = append(rows, ingestion.CanonicalEvent{
+				EntityID:   event.EntityID,
+				ObservedAt: event.ObservedAt,
+				Source:     event.Source,
+				RawPayload: make(map[string]interface{}),
+			})
+		}
+		switch {
+		case event.Value.IsMissing:
+			rows[idx].RawPayload[event.FieldKey] = nil
+		case event.Value.IsNumeric():
+			rows[idx].RawPayload[event.FieldKey] = event.Value.AsFloat64()
+		default:
+			rows[idx].RawPayload[event.FieldKey] = event.Value.String()
+		}
+	}
+
+	if len(rows) == 0 {
+		return events
+	}
+	return rows
+}
+
 // profileField analyzes a single field across all events
 func (p *ProfilerAdapter) profileField(fieldName, sourceName string, events []interface{}, config profiling.ProfilingConfig) profiling.FieldProfile {
 	totalCount := len(events)
@@ -122,6 +164,13 @@ func (p *ProfilerAdapter) profileField(fieldName, sourceName string, events []in
 		profile.TypeSpecific.NumericStats = p.computeNumericStats(values)
 	case profiling.TypeCategorical:
 		profile.TypeSpecific.CategoricalStats = p.computeCategoricalStats(values)
+		// Categories drawn from a known ordered scale are ordinal, not nominal
+		if stats := profile.TypeSpecific.CategoricalStats; stats != nil {
+			if levels := p.ordinalLevels(values); levels != nil {
+				stats.Levels = levels
+				profile.InferredType = profiling.TypeOrdinal
+			}
+		}
 	case profiling.TypeText:
 		profile.TypeSpecific.TextStats = p.computeTextStats(values)
 	}
@@ -443,6 +492,20 @@ func (p *ProfilerAdapter) computeCategoricalStats(values []interface{}) *profili
 	}
 }
 
+// ordinalLevels returns the field's levels in order when its string values form a known
+// ordinal scale
+func (p *ProfilerAdapter) ordinalLevels(values []interface{}) []string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		strs = append(strs, str)
+	}
+	return profiling.OrdinalLevels(strs)
+}
+
 // computeTextStats calculates statistics for text fields
 func (p *ProfilerAdapter) computeTextStats(values []interface{}) *profiling.TextStats {
 	if len(values) == 0 {
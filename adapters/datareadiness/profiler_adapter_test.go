@@ -1,9 +1,13 @@
 package datareadiness
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"gohypo/adapters/datareadiness/coercer"
+	"gohypo/domain/core"
+	"gohypo/domain/datareadiness/ingestion"
 	"gohypo/domain/datareadiness/profiling"
 )
 
@@ -113,3 +117,67 @@ func TestCategoricalCodeDetection(t *testing.T) {
 
 
 
+
+func TestOrdinalScaleDetection(t *testing.T) {
+	profiler := NewProfilerAdapter(coercer.NewTypeCoercer(coercer.DefaultCoercionConfig()))
+	config := profiling.DefaultProfilingConfig()
+
+	events := []interface{}{}
+	for _, v := range []string{"High", "low", "Medium", "High", "Low", "medium", "high"} {
+		events = append(events, map[string]interface{}{"priority": v, "region": "North"})
+	}
+
+	profile := profiler.profileField("priority", "test", events, config)
+	if profile.InferredType != profiling.TypeOrdinal {
+		t.Fatalf("Expected ordinal type, got %s", profile.InferredType)
+	}
+	levels := profile.TypeSpecific.CategoricalStats.Levels
+	if len(levels) != 3 || levels[0] != "low" || levels[2] != "High" {
+		t.Errorf("Expected levels in scale order, got %v", levels)
+	}
+
+	if nominal := profiler.profileField("region", "test", events, config); nominal.InferredType == profiling.TypeOrdinal {
+		t.Errorf("Expected unordered categories to stay categorical")
+	}
+}
+
+func TestProfileSourcePivotsCellEvents(t *testing.T) {
+	profiler := NewProfilerAdapter(coercer.NewTypeCoercer(coercer.DefaultCoercionConfig()))
+	config := profiling.DefaultProfilingConfig()
+
+	var events []ingestion.CanonicalEvent
+	for i, spend := range []float64{12.5, 30, 18.25, 44} {
+		entity := core.ID(fmt.Sprintf("customer-%d", i))
+		events = append(events,
+			ingestion.CanonicalEvent{EntityID: entity, Source: "test", FieldKey: "spend", Value: ingestion.NewNumericValue(spend)},
+			ingestion.CanonicalEvent{EntityID: entity, Source: "test", FieldKey: "region", Value: ingestion.NewStringValue("North")},
+		)
+	}
+	events = append(events, ingestion.CanonicalEvent{EntityID: "customer-0", Source: "test", FieldKey: "churned", Value: ingestion.NewMissingValue()})
+
+	rows := pivotCellEvents(events)
+	if len(rows) != 4 {
+		t.Fatalf("Expected one row per entity, got %d", len(rows))
+	}
+	if rows[0].EntityID != "customer-0" || rows[3].EntityID != "customer-3" {
+		t.Errorf("Expected rows in first-seen entity order, got %s..%s", rows[0].EntityID, rows[3].EntityID)
+	}
+	if value, ok := rows[0].RawPayload["churned"]; !ok || value != nil {
+		t.Errorf("Expected a missing cell to pivot to nil, got %v", value)
+	}
+	if rows[2].RawPayload["spend"] != 18.25 || rows[2].RawPayload["region"] != "North" {
+		t.Errorf("Expected cell values in the row payload, got %v", rows[2].RawPayload)
+	}
+
+	result, err := profiler.ProfileSource(context.Background(), "test", events, config)
+	if err != nil {
+		t.Fatalf("Expected cell events to profile, got %v", err)
+	}
+	types := make(map[string]profiling.InferredType)
+	for _, profile := range result.Profiles {
+		types[profile.FieldKey] = profile.InferredType
+	}
+	if types["spend"] != profiling.TypeNumeric {
+		t.Errorf("Expected spend profiled as numeric, got %s", types["spend"])
+	}
+}
@@ -21,6 +21,10 @@ type SynthesisConfig struct {
 	SumThreshold      float64 `json:"sum_threshold"`      // Min ratio for sum mode
 	ExistsThreshold   float64 `json:"exists_threshold"`   // Max ratio for exists mode
 	VarianceThreshold float64 `json:"variance_threshold"` // Min variance for latest mode
+
+	// OrdinalFields declares fields ordinal with their levels, lowest first, whatever the
+	// profile inferred; for scales that are not recognized automatically
+	OrdinalFields map[string][]string `json:"ordinal_fields,omitempty"`
 }
 
 // DefaultSynthesisConfig returns sensible defaults
@@ -100,6 +104,14 @@ func (s *ContractSynthesizer) synthesizeContract(profile profiling.FieldProfile)
 		draft.CategoricalEncoding = s.synthesizeCategoricalEncoding(profile)
 	}
 
+	// Ordinal variables are encoded by rank so their order survives into the matrix
+	if levels := s.ordinalLevels(profile); levels != nil {
+		draft.StatisticalType = "ordinal"
+		draft.Reasoning.StatisticalType = s.explainOrdinal(profile, levels)
+		draft.OrdinalLevels = levels
+		draft.CategoricalEncoding = profiling.OrdinalEncoding(levels)
+	}
+
 	// Set window days if applicable
 	draft.WindowDays = s.synthesizeWindowDays(profile, draft.AsOfMode)
 
@@ -225,6 +237,8 @@ func (s *ContractSynthesizer) synthesizeStatisticalType(profile profiling.FieldP
 		return "numeric"
 	case profiling.TypeCategorical:
 		return "categorical"
+	case profiling.TypeOrdinal:
+		return "ordinal"
 	case profiling.TypeBoolean:
 		return "binary"
 	case profiling.TypeTimestamp:
@@ -311,6 +325,24 @@ func (s *ContractSynthesizer) explainStatisticalType(profile profiling.FieldProf
 		statType, profile.SampleSize, profile.QualityScore*100)
 }
 
+// ordinalLevels returns the field's levels when it is declared or inferred ordinal
+func (s *ContractSynthesizer) ordinalLevels(profile profiling.FieldProfile) []string {
+	if levels, ok := s.config.OrdinalFields[profile.FieldKey]; ok && len(levels) > 0 {
+		return levels
+	}
+	if profile.InferredType == profiling.TypeOrdinal && profile.TypeSpecific.CategoricalStats != nil {
+		return profile.TypeSpecific.CategoricalStats.Levels
+	}
+	return nil
+}
+
+func (s *ContractSynthesizer) explainOrdinal(profile profiling.FieldProfile, levels []string) string {
+	if _, declared := s.config.OrdinalFields[profile.FieldKey]; declared {
+		return fmt.Sprintf("Declared ordinal with levels %s", strings.Join(levels, " < "))
+	}
+	return fmt.Sprintf("Ordinal: values form the ordered scale %s", strings.Join(levels, " < "))
+}
+
 func (s *ContractSynthesizer) explainScalarGuarantee(profile profiling.FieldProfile, mode string, guaranteed bool) string {
 	if guaranteed {
 		return fmt.Sprintf("%s mode guarantees exactly one scalar value per entity per snapshot", mode)
@@ -324,6 +356,7 @@ type ContractDraft struct {
 	Source              string                 `json:"source"`
 	AsOfMode            string                 `json:"as_of_mode"`
 	StatisticalType     string                 `json:"statistical_type"`
+	OrdinalLevels       []string               `json:"ordinal_levels,omitempty"`
 	ImputationPolicy    string                 `json:"imputation_policy"`
 	WindowDays          *int                   `json:"window_days,omitempty"`
 	LagDays             int                    `json:"lag_days"`
@@ -535,5 +568,6 @@ func (d *ContractDraft) ToVariableContract() *dataset.VariableContract {
 		ImputationPolicy:    dataset.ImputationPolicy(d.ImputationPolicy),
 		ScalarGuarantee:     d.ScalarGuarantee,
 		CategoricalEncoding: d.CategoricalEncoding,
+		OrdinalLevels:       d.OrdinalLevels,
 	}
 }
@@ -3,8 +3,10 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"gohypo/domain/core"
@@ -15,11 +17,20 @@ import (
 // MatrixResolverAdapter implements MatrixResolverPort for PostgreSQL
 type MatrixResolverAdapter struct {
 	db *sql.DB
+
+	// Query approval gate (see query_preview.go)
+	requireApproval bool
+	approvals       ports.QueryApprovalStore
+	approvalMu      sync.RWMutex
 }
 
-// NewMatrixResolverAdapter creates a new matrix resolver adapter
+// NewMatrixResolverAdapter creates a new matrix resolver adapter whose query approvals are
+// stored in the same database
 func NewMatrixResolverAdapter(db *sql.DB) *MatrixResolverAdapter {
-	return &MatrixResolverAdapter{db: db}
+	return &MatrixResolverAdapter{
+		db:        db,
+		approvals: NewQueryApprovalRepository(db),
+	}
 }
 
 // ResolveMatrix produces a MatrixBundle for the given snapshot and variables
@@ -29,10 +40,11 @@ func (a *MatrixResolverAdapter) ResolveMatrix(ctx context.Context, req ports.Mat
 	if err != nil {
 		return nil, fmt.Errorf("failed to get snapshot: %w", err)
 	}
-	cutoffAt := snapshot.SnapshotAt.ApplyLag(snapshot.Lag)
+	cutoffAt := snapshot.Cutoff()
 
 	// Create the matrix bundle
 	bundle := dataset.NewMatrixBundle(req.SnapshotID, req.ViewID, "", cutoffAt, snapshot.Lag)
+	bundle.TimeZone = snapshot.TimeZone.Decision(cutoffAt)
 
 	// Resolve each variable using cohort-driven approach
 	for _, varKey := range req.VarKeys {
@@ -64,32 +76,12 @@ func (a *MatrixResolverAdapter) resolveVariableCohortDriven(ctx context.Context,
 		return nil, nil, fmt.Errorf("failed to get contract for %s: %w", varKey, err)
 	}
 
-	// Build cohort CTE safely using array literal
-	entityIDStrings := make([]string, len(entityIDs))
-	for i, id := range entityIDs {
-		// Basic escaping for array literal (assumes IDs don't contain } or ,)
-		// In production, use lib/pq.Array or a proper escaping function
-		entityIDStrings[i] = string(id)
-	}
-	arrayLiteral := "{" + strings.Join(entityIDStrings, ",") + "}"
+	query := a.compileVariableQuery(varKey, contract, cutoffAt, snapshot.TimeZone)
+	arrayLiteral := cohortArrayLiteral(entityIDs)
 
-	cohortCTE := "SELECT unnest($1::text[]) AS entity_id"
-
-	// Build resolution subquery (inherently scalar per entity)
-	resolutionQuery := a.buildScalarResolutionQuery(varKey, contract, cutoffAt)
-
-	// Combine with LEFT JOIN
-	query := fmt.Sprintf(`
-		WITH cohort AS (%s),
-		     resolved AS (%s)
-		SELECT
-			cohort.entity_id,
-			COALESCE(resolved.value, %s) as final_value,
-			resolved.observed_at
-		FROM cohort
-		LEFT JOIN resolved USING (entity_id)
-		ORDER BY cohort.entity_id
-	`, cohortCTE, resolutionQuery, a.getImputationSQL(contract.ImputationPolicy))
+	if err := a.checkApproval(ctx, query); err != nil {
+		return nil, nil, err
+	}
 
 	// Execute query with parameters
 	rows, err := a.db.QueryContext(ctx, query, arrayLiteral)
@@ -112,8 +104,10 @@ func (a *MatrixResolverAdapter) resolveVariableCohortDriven(ctx context.Context,
 		}
 
 		values[i] = value
-		if observedAt.Valid && observedAt.Time.After(maxTimestamp) {
-			maxTimestamp = observedAt.Time
+		if observedAt.Valid {
+			if observed := snapshot.TimeZone.Wall(observedAt.Time); observed.After(maxTimestamp) {
+				maxTimestamp = observed
+			}
 		}
 	}
 
@@ -130,25 +124,64 @@ func (a *MatrixResolverAdapter) resolveVariableCohortDriven(ctx context.Context,
 		ScalarGuarantee:   true, // Guaranteed by SQL structure
 		AsOfMode:          dataset.AsOfMode(contract.AsOfMode),
 		WindowDays:        contract.WindowDays,
+		LagDays:           contract.LagDays,
+		DayCount:          contract.DayCount,
 	}
 
 	return values, audit, nil
 }
 
+// compileVariableQuery produces the complete push-down SQL for one variable.
+// The cohort is bound as $1 (a text[] literal) so the same SQL text can be
+// previewed, approved, and executed. observed_at holds the dataset's wall-clock time, so
+// the cutoff is written in the dataset's zone. A contract's lag moves the cutoff back,
+// counting days on the dataset's calendar or in business days.
+func (a *MatrixResolverAdapter) compileVariableQuery(varKey core.VariableKey, contract *VariableContract, cutoffAt core.CutoffAt, zone core.TimeZone) string {
+	cohortCTE := "SELECT unnest($1::text[]) AS entity_id"
+	cutoffAt = core.NewCutoffAt(contract.domain().AsOf(cutoffAt.Time().In(zone.Location())).UTC())
+
+	// Build resolution subquery (inherently scalar per entity)
+	resolutionQuery := a.buildScalarResolutionQuery(varKey, contract, cutoffAt, zone)
+
+	// Combine with LEFT JOIN
+	return fmt.Sprintf(`
+		WITH cohort AS (%s),
+		     resolved AS (%s)
+		SELECT
+			cohort.entity_id,
+			COALESCE(resolved.value, %s) as final_value,
+			resolved.observed_at
+		FROM cohort
+		LEFT JOIN resolved USING (entity_id)
+		ORDER BY cohort.entity_id
+	`, cohortCTE, resolutionQuery, a.getImputationSQL(contract.ImputationPolicy))
+}
+
+// cohortArrayLiteral builds the text[] literal bound to the cohort CTE
+func cohortArrayLiteral(entityIDs []core.ID) string {
+	entityIDStrings := make([]string, len(entityIDs))
+	for i, id := range entityIDs {
+		// Basic escaping for array literal (assumes IDs don't contain } or ,)
+		// In production, use lib/pq.Array or a proper escaping function
+		entityIDStrings[i] = string(id)
+	}
+	return "{" + strings.Join(entityIDStrings, ",") + "}"
+}
+
 // buildScalarResolutionQuery creates SQL that guarantees one row per entity
-func (a *MatrixResolverAdapter) buildScalarResolutionQuery(varKey core.VariableKey, contract *VariableContract, cutoffAt core.CutoffAt) string {
+func (a *MatrixResolverAdapter) buildScalarResolutionQuery(varKey core.VariableKey, contract *VariableContract, cutoffAt core.CutoffAt, zone core.TimeZone) string {
 	switch contract.AsOfMode {
 	case "latest_value_as_of":
-		return a.buildLatestValueQuery(varKey, cutoffAt)
+		return a.buildLatestValueQuery(varKey, cutoffAt, zone)
 
 	case "count_over_window":
-		return a.buildCountWindowQuery(varKey, contract.WindowDays, cutoffAt)
+		return a.buildCountWindowQuery(varKey, contract, cutoffAt, zone)
 
 	case "sum_over_window":
-		return a.buildSumWindowQuery(varKey, contract.WindowDays, cutoffAt)
+		return a.buildSumWindowQuery(varKey, contract, cutoffAt, zone)
 
 	case "exists_as_of":
-		return a.buildExistsQuery(varKey, cutoffAt)
+		return a.buildExistsQuery(varKey, cutoffAt, zone)
 
 	default:
 		panic(fmt.Sprintf("unsupported as-of mode: %s", contract.AsOfMode))
@@ -156,7 +189,7 @@ func (a *MatrixResolverAdapter) buildScalarResolutionQuery(varKey core.VariableK
 }
 
 // buildLatestValueQuery - DISTINCT ON guarantees scalar per entity
-func (a *MatrixResolverAdapter) buildLatestValueQuery(varKey core.VariableKey, cutoffAt core.CutoffAt) string {
+func (a *MatrixResolverAdapter) buildLatestValueQuery(varKey core.VariableKey, cutoffAt core.CutoffAt, zone core.TimeZone) string {
 	return fmt.Sprintf(`
 		SELECT DISTINCT ON (entity_id)
 			entity_id,
@@ -166,15 +199,12 @@ func (a *MatrixResolverAdapter) buildLatestValueQuery(varKey core.VariableKey, c
 		WHERE payload ? '%s'
 		  AND observed_at <= '%s'
 		ORDER BY entity_id, observed_at DESC
-	`, varKey, varKey, cutoffAt.Time().Format("2006-01-02 15:04:05"))
+	`, varKey, varKey, zone.Local(cutoffAt.Time()))
 }
 
 // buildCountWindowQuery - GROUP BY guarantees scalar per entity
-func (a *MatrixResolverAdapter) buildCountWindowQuery(varKey core.VariableKey, windowDays *int, cutoffAt core.CutoffAt) string {
-	windowStart := cutoffAt
-	if windowDays != nil {
-		windowStart = core.NewCutoffAt(cutoffAt.Time().AddDate(0, 0, -(*windowDays)))
-	}
+func (a *MatrixResolverAdapter) buildCountWindowQuery(varKey core.VariableKey, contract *VariableContract, cutoffAt core.CutoffAt, zone core.TimeZone) string {
+	windowStart := contract.domain().WindowStart(cutoffAt.Time().In(zone.Location()))
 
 	return fmt.Sprintf(`
 		SELECT
@@ -186,16 +216,13 @@ func (a *MatrixResolverAdapter) buildCountWindowQuery(varKey core.VariableKey, w
 		  AND observed_at <= '%s'
 		  AND observed_at >= '%s'
 		GROUP BY entity_id
-	`, varKey, cutoffAt.Time().Format("2006-01-02 15:04:05"),
-		windowStart.Time().Format("2006-01-02 15:04:05"))
+	`, varKey, zone.Local(cutoffAt.Time()),
+		zone.Local(windowStart))
 }
 
 // buildSumWindowQuery - GROUP BY guarantees scalar per entity
-func (a *MatrixResolverAdapter) buildSumWindowQuery(varKey core.VariableKey, windowDays *int, cutoffAt core.CutoffAt) string {
-	windowStart := cutoffAt
-	if windowDays != nil {
-		windowStart = core.NewCutoffAt(cutoffAt.Time().AddDate(0, 0, -(*windowDays)))
-	}
+func (a *MatrixResolverAdapter) buildSumWindowQuery(varKey core.VariableKey, contract *VariableContract, cutoffAt core.CutoffAt, zone core.TimeZone) string {
+	windowStart := contract.domain().WindowStart(cutoffAt.Time().In(zone.Location()))
 
 	return fmt.Sprintf(`
 		SELECT
@@ -207,12 +234,12 @@ func (a *MatrixResolverAdapter) buildSumWindowQuery(varKey core.VariableKey, win
 		  AND observed_at <= '%s'
 		  AND observed_at >= '%s'
 		GROUP BY entity_id
-	`, varKey, varKey, cutoffAt.Time().Format("2006-01-02 15:04:05"),
-		windowStart.Time().Format("2006-01-02 15:04:05"))
+	`, varKey, varKey, zone.Local(cutoffAt.Time()),
+		zone.Local(windowStart))
 }
 
 // buildExistsQuery - GROUP BY guarantees scalar per entity
-func (a *MatrixResolverAdapter) buildExistsQuery(varKey core.VariableKey, cutoffAt core.CutoffAt) string {
+func (a *MatrixResolverAdapter) buildExistsQuery(varKey core.VariableKey, cutoffAt core.CutoffAt, zone core.TimeZone) string {
 	return fmt.Sprintf(`
 		SELECT
 			entity_id,
@@ -222,7 +249,7 @@ func (a *MatrixResolverAdapter) buildExistsQuery(varKey core.VariableKey, cutoff
 		WHERE payload ? '%s'
 		  AND observed_at <= '%s'
 		GROUP BY entity_id
-	`, varKey, cutoffAt.Time().Format("2006-01-02 15:04:05"))
+	`, varKey, zone.Local(cutoffAt.Time()))
 }
 
 // getImputationSQL returns the SQL for default imputation
@@ -255,42 +282,61 @@ func (a *MatrixResolverAdapter) determineImputationApplied(values []float64, pol
 	return "none"
 }
 
-// getSnapshot retrieves snapshot details
+// getSnapshot retrieves snapshot details. snapshot_at is a naive TIMESTAMP in the
+// dataset's zone, so it is converted to UTC here, once, before anything compares it.
 func (a *MatrixResolverAdapter) getSnapshot(ctx context.Context, snapshotID core.SnapshotID) (*Snapshot, error) {
 	query := `
-		SELECT id, dataset, snapshot_at, lag_buffer, registry_hash
+		SELECT id, dataset, snapshot_at, lag_buffer, registry_hash, time_zone
 		FROM snapshots WHERE id = $1`
 
 	var s Snapshot
 	var lagSeconds int
+	var snapshotAt time.Time
+	var zoneName string
 	err := a.db.QueryRowContext(ctx, query, snapshotID).Scan(
-		&s.ID, &s.Dataset, &s.SnapshotAt, &lagSeconds, &s.RegistryHash)
+		&s.ID, &s.Dataset, &snapshotAt, &lagSeconds, &s.RegistryHash, &zoneName)
 	if err != nil {
 		return nil, err
 	}
 
+	s.TimeZone, err = core.ResolveTimeZone(zoneName, core.TimeZoneFromDataset)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s: %w", snapshotID, err)
+	}
+	s.SnapshotAt = core.NewSnapshotAt(s.TimeZone.Wall(snapshotAt))
 	s.Lag = core.NewLag(time.Duration(lagSeconds) * time.Second)
 	return &s, nil
 }
 
 // getVariableContract retrieves the contract for a variable
 func (a *MatrixResolverAdapter) getVariableContract(ctx context.Context, varKey core.VariableKey) (*VariableContract, error) {
+	return loadVariableContract(ctx, a.db, varKey)
+}
+
+// loadVariableContract reads a variable's contract from the registry
+func loadVariableContract(ctx context.Context, db *sql.DB, varKey core.VariableKey) (*VariableContract, error) {
 	query := `
 		SELECT var_key, as_of_mode, statistical_type, window_days,
-		       imputation_policy, scalar_guarantee
+		       imputation_policy, scalar_guarantee,
+		       lag_days, day_count, calendar
 		FROM variable_contracts
 		WHERE var_key = $1`
 
 	var contract VariableContract
-	var windowDays sql.NullInt32
+	var windowDays, lagDays sql.NullInt32
+	var dayCount string
+	var calendar []byte
 
-	err := a.db.QueryRowContext(ctx, query, varKey).Scan(
+	err := db.QueryRowContext(ctx, query, varKey).Scan(
 		&contract.VarKey,
 		&contract.AsOfMode,
 		&contract.StatisticalType,
 		&windowDays,
 		&contract.ImputationPolicy,
 		&contract.ScalarGuarantee,
+		&lagDays,
+		&dayCount,
+		&calendar,
 	)
 
 	if err != nil {
@@ -301,6 +347,22 @@ func (a *MatrixResolverAdapter) getVariableContract(ctx context.Context, varKey
 		days := int(windowDays.Int32)
 		contract.WindowDays = &days
 	}
+	if lagDays.Valid {
+		days := int(lagDays.Int32)
+		contract.LagDays = &days
+	}
+	if contract.DayCount, err = dataset.ParseDayCount(dayCount); err != nil {
+		return nil, fmt.Errorf("contract %s: %w", varKey, err)
+	}
+	if len(calendar) > 0 {
+		contract.Calendar = &dataset.BusinessCalendar{}
+		if err := json.Unmarshal(calendar, contract.Calendar); err != nil {
+			return nil, fmt.Errorf("contract %s: invalid calendar: %w", varKey, err)
+		}
+	}
+	if err := contract.domain().Days().Validate(); err != nil {
+		return nil, fmt.Errorf("contract %s: %w", varKey, err)
+	}
 
 	return &contract, nil
 }
@@ -309,9 +371,15 @@ func (a *MatrixResolverAdapter) getVariableContract(ctx context.Context, varKey
 type Snapshot struct {
 	ID           core.SnapshotID
 	Dataset      string
-	SnapshotAt   core.SnapshotAt
+	SnapshotAt   core.SnapshotAt // UTC
 	Lag          core.Lag
 	RegistryHash core.RegistryHash
+	TimeZone     core.TimeZone // zone of the dataset's wall-clock timestamps
+}
+
+// Cutoff is the snapshot time less the lag, stepped on the dataset's calendar
+func (s *Snapshot) Cutoff() core.CutoffAt {
+	return s.TimeZone.ApplyLag(s.SnapshotAt, s.Lag)
 }
 
 // VariableContract represents a variable contract (internal to adapter)
@@ -322,4 +390,22 @@ type VariableContract struct {
 	WindowDays       *int
 	ImputationPolicy string
 	ScalarGuarantee  bool
+	LagDays          *int
+	DayCount         dataset.DayCount
+	Calendar         *dataset.BusinessCalendar
+}
+
+// domain is the contract as the domain declares it, so its window and lag count the same way
+func (c *VariableContract) domain() *dataset.VariableContract {
+	return &dataset.VariableContract{
+		VarKey:           c.VarKey,
+		AsOfMode:         dataset.AsOfMode(c.AsOfMode),
+		StatisticalType:  dataset.StatisticalType(c.StatisticalType),
+		WindowDays:       c.WindowDays,
+		LagDays:          c.LagDays,
+		DayCount:         c.DayCount,
+		Calendar:         c.Calendar,
+		ImputationPolicy: dataset.ImputationPolicy(c.ImputationPolicy),
+		ScalarGuarantee:  c.ScalarGuarantee,
+	}
 }
@@ -0,0 +1,8 @@
+-- Migration 007: Time zone of each snapshot's dataset
+-- snapshot_at and raw_events.observed_at are naive TIMESTAMPs holding the dataset's wall-clock
+-- time; the resolver reads them in this zone so a cutoff means the same instant everywhere
+
+ALTER TABLE snapshots ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT 'UTC';
+
+-- Datasets record their zone in metadata->>'time_zone'; nothing to backfill, UTC was assumed
+COMMENT ON COLUMN snapshots.time_zone IS 'IANA zone of snapshot_at and the dataset''s observed_at values';
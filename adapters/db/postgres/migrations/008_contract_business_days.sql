@@ -0,0 +1,12 @@
+-- Migration 008: Business-day windows and lags for variable contracts
+-- window_days and lag_days count calendar days unless day_count is 'business', in which
+-- case only business days of the contract's calendar count
+
+ALTER TABLE variable_contracts ADD COLUMN IF NOT EXISTS lag_days INTEGER;
+ALTER TABLE variable_contracts ADD COLUMN IF NOT EXISTS day_count TEXT NOT NULL DEFAULT 'calendar'
+    CHECK (day_count IN ('calendar', 'business'));
+-- {"include_weekends": false, "holidays": [{"date": "2024-12-25T00:00:00Z", "name": "Christmas Day"}]}
+ALTER TABLE variable_contracts ADD COLUMN IF NOT EXISTS calendar JSONB;
+
+ALTER TABLE variable_contracts ADD CONSTRAINT business_day_count_needs_calendar
+    CHECK (day_count <> 'business' OR calendar IS NOT NULL);
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"gohypo/domain/core"
+	"gohypo/ports"
+)
+
+// QueryApprovalRepository stores push-down query approvals in resolution_query_approvals
+type QueryApprovalRepository struct {
+	db *sql.DB
+}
+
+// NewQueryApprovalRepository creates a new query approval repository
+func NewQueryApprovalRepository(db *sql.DB) *QueryApprovalRepository {
+	return &QueryApprovalRepository{db: db}
+}
+
+// Approve records an approval, keeping the first one when the query was approved before
+func (r *QueryApprovalRepository) Approve(ctx context.Context, approval ports.QueryApproval) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO resolution_query_approvals (query_hash, approved_by, approved_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (query_hash) DO NOTHING`,
+		string(approval.QueryHash), approval.ApprovedBy, approval.ApprovedAt.Time())
+	if err != nil {
+		return fmt.Errorf("failed to record query approval: %w", err)
+	}
+	return nil
+}
+
+// IsApproved reports whether the query with hash was approved
+func (r *QueryApprovalRepository) IsApproved(ctx context.Context, queryHash core.Hash) (bool, error) {
+	var approved bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM resolution_query_approvals WHERE query_hash = $1)`,
+		string(queryHash)).Scan(&approved)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up query approval: %w", err)
+	}
+	return approved, nil
+}
+
+// Ensure QueryApprovalRepository implements QueryApprovalStore
+var _ ports.QueryApprovalStore = (*QueryApprovalRepository)(nil)
@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gohypo/domain/core"
+	"gohypo/ports"
+)
+
+// RequireApproval turns on the approval gate: ResolveMatrix refuses to execute
+// any compiled query whose hash has not been approved via ApproveQuery
+func (a *MatrixResolverAdapter) RequireApproval(enabled bool) {
+	a.approvalMu.Lock()
+	defer a.approvalMu.Unlock()
+	a.requireApproval = enabled
+}
+
+// SetApprovalStore replaces where approvals are recorded and looked up
+func (a *MatrixResolverAdapter) SetApprovalStore(store ports.QueryApprovalStore) {
+	a.approvalMu.Lock()
+	defer a.approvalMu.Unlock()
+	a.approvals = store
+}
+
+// ApproveQuery records a compiled query as reviewed and safe to execute
+func (a *MatrixResolverAdapter) ApproveQuery(ctx context.Context, queryHash core.Hash, approvedBy string) error {
+	if queryHash.IsEmpty() {
+		return core.NewValidationError("query_hash", "cannot be empty")
+	}
+	if approvedBy == "" {
+		return core.NewValidationError("approved_by", "cannot be empty")
+	}
+	return a.approvalStore().Approve(ctx, ports.QueryApproval{
+		QueryHash:  queryHash,
+		ApprovedBy: approvedBy,
+		ApprovedAt: core.Now(),
+	})
+}
+
+// PreviewResolution compiles the push-down SQL for every requested variable and
+// asks the planner for a cost estimate without executing anything
+func (a *MatrixResolverAdapter) PreviewResolution(ctx context.Context, req ports.MatrixResolutionRequest) (*ports.ResolutionPreview, error) {
+	snapshot, err := a.getSnapshot(ctx, req.SnapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	cutoffAt := snapshot.Cutoff()
+	arrayLiteral := cohortArrayLiteral(req.EntityIDs)
+
+	a.approvalMu.RLock()
+	gated := a.requireApproval
+	a.approvalMu.RUnlock()
+
+	preview := &ports.ResolutionPreview{
+		SnapshotID:    req.SnapshotID,
+		Variables:     make([]ports.VariableQueryPreview, 0, len(req.VarKeys)),
+		GeneratedAt:   core.Now(),
+		ApprovalGated: gated,
+	}
+
+	for _, varKey := range req.VarKeys {
+		varPreview := ports.VariableQueryPreview{VariableKey: varKey}
+
+		contract, err := a.getVariableContract(ctx, varKey)
+		if err != nil {
+			varPreview.Error = fmt.Sprintf("failed to get contract: %v", err)
+			preview.Variables = append(preview.Variables, varPreview)
+			continue
+		}
+		varPreview.AsOfMode = contract.AsOfMode
+
+		query, err := a.safeCompile(varKey, contract, cutoffAt, snapshot.TimeZone)
+		if err != nil {
+			varPreview.Error = err.Error()
+			preview.Variables = append(preview.Variables, varPreview)
+			continue
+		}
+		varPreview.SQL = normalizeSQL(query)
+		varPreview.QueryHash = queryHash(query)
+		if varPreview.Approved, err = a.approvalStore().IsApproved(ctx, varPreview.QueryHash); err != nil {
+			return nil, fmt.Errorf("failed to check approval of %s: %w", varKey, err)
+		}
+
+		cost, rows, err := a.explainCost(ctx, query, arrayLiteral)
+		if err != nil {
+			varPreview.Error = fmt.Sprintf("explain failed: %v", err)
+		} else {
+			varPreview.EstimatedCost = cost
+			varPreview.EstimatedRows = rows
+			preview.TotalCost += cost
+		}
+
+		preview.Variables = append(preview.Variables, varPreview)
+	}
+
+	return preview, nil
+}
+
+// safeCompile compiles a variable query, converting unsupported as-of modes into errors
+func (a *MatrixResolverAdapter) safeCompile(varKey core.VariableKey, contract *VariableContract, cutoffAt core.CutoffAt, zone core.TimeZone) (query string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", core.ErrAsOfModeUnsupported, r)
+		}
+	}()
+	return a.compileVariableQuery(varKey, contract, cutoffAt, zone), nil
+}
+
+// explainCost runs EXPLAIN (FORMAT JSON) and extracts the planner's total cost and row estimate
+func (a *MatrixResolverAdapter) explainCost(ctx context.Context, query string, arrayLiteral string) (float64, float64, error) {
+	var raw []byte
+	if err := a.db.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+query, arrayLiteral).Scan(&raw); err != nil {
+		return 0, 0, err
+	}
+
+	var plans []struct {
+		Plan struct {
+			TotalCost float64 `json:"Total Cost"`
+			PlanRows  float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plans); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse explain output: %w", err)
+	}
+	if len(plans) == 0 {
+		return 0, 0, fmt.Errorf("explain returned no plan")
+	}
+
+	return plans[0].Plan.TotalCost, plans[0].Plan.PlanRows, nil
+}
+
+// checkApproval enforces the approval gate before a query is executed
+func (a *MatrixResolverAdapter) checkApproval(ctx context.Context, query string) error {
+	a.approvalMu.RLock()
+	gated := a.requireApproval
+	a.approvalMu.RUnlock()
+
+	if !gated {
+		return nil
+	}
+	hash := queryHash(query)
+	approved, err := a.approvalStore().IsApproved(ctx, hash)
+	if err != nil {
+		return fmt.Errorf("failed to check approval of query %s: %w", hash, err)
+	}
+	if !approved {
+		return fmt.Errorf("%w: query %s", core.ErrQueryNotApproved, hash)
+	}
+	return nil
+}
+
+func (a *MatrixResolverAdapter) approvalStore() ports.QueryApprovalStore {
+	a.approvalMu.RLock()
+	defer a.approvalMu.RUnlock()
+	return a.approvals
+}
+
+// queryHash fingerprints compiled SQL independent of indentation
+func queryHash(query string) core.Hash {
+	return core.NewHash([]byte(normalizeSQL(query)))
+}
+
+// normalizeSQL collapses whitespace so previews are readable and hashes are stable
+func normalizeSQL(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// Ensure MatrixResolverAdapter implements the resolver and preview ports
+var (
+	_ ports.MatrixResolverPort = (*MatrixResolverAdapter)(nil)
+	_ ports.QueryPreviewPort   = (*MatrixResolverAdapter)(nil)
+)
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gohypo/domain/core"
+	"gohypo/ports"
+)
+
+// memoryApprovalStore keeps approvals in a map in place of resolution_query_approvals
+type memoryApprovalStore map[core.Hash]ports.QueryApproval
+
+func (m memoryApprovalStore) Approve(_ context.Context, approval ports.QueryApproval) error {
+	if _, ok := m[approval.QueryHash]; !ok {
+		m[approval.QueryHash] = approval
+	}
+	return nil
+}
+
+func (m memoryApprovalStore) IsApproved(_ context.Context, queryHash core.Hash) (bool, error) {
+	_, ok := m[queryHash]
+	return ok, nil
+}
+
+// TestQueryApprovalGate verifies that with approval required only queries approved through
+// the store run, that approvals match however the SQL is indented and record the approver,
+// and that without the gate every query runs
+func TestQueryApprovalGate(t *testing.T) {
+	ctx := context.Background()
+	store := memoryApprovalStore{}
+	resolver := NewMatrixResolverAdapter(nil)
+	resolver.SetApprovalStore(store)
+	query := "SELECT entity_id, value\n\tFROM observations\n\tWHERE entity_id = ANY($1)"
+
+	if err := resolver.checkApproval(ctx, query); err != nil {
+		t.Fatalf("expected queries to run without the gate, got %v", err)
+	}
+
+	resolver.RequireApproval(true)
+	if err := resolver.checkApproval(ctx, query); !errors.Is(err, core.ErrQueryNotApproved) {
+		t.Fatalf("expected an unapproved query to be refused, got %v", err)
+	}
+
+	hash := queryHash("SELECT entity_id, value FROM observations WHERE entity_id = ANY($1)")
+	if err := resolver.ApproveQuery(ctx, hash, ""); err == nil {
+		t.Error("expected an approval without an approver to be rejected")
+	}
+	if err := resolver.ApproveQuery(ctx, "", "reviewer"); err == nil {
+		t.Error("expected an approval without a hash to be rejected")
+	}
+	if err := resolver.ApproveQuery(ctx, hash, "reviewer"); err != nil {
+		t.Fatalf("ApproveQuery: %v", err)
+	}
+	if err := resolver.checkApproval(ctx, query); err != nil {
+		t.Errorf("expected the approved query to run, got %v", err)
+	}
+	if approval := store[hash]; approval.ApprovedBy != "reviewer" || approval.ApprovedAt.Time().IsZero() {
+		t.Errorf("expected the approver and time recorded, got %+v", approval)
+	}
+
+	if err := resolver.checkApproval(ctx, query+" AND value > 0"); !errors.Is(err, core.ErrQueryNotApproved) {
+		t.Errorf("expected a changed query to need its own approval, got %v", err)
+	}
+}
@@ -2,30 +2,91 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
 
 	"gohypo/domain/core"
 	"gohypo/domain/dataset"
 	"gohypo/ports"
 )
 
-// RegistryAdapter implements RegistryPort for PostgreSQL
+// RegistryAdapter implements ContractStore over the variable_contracts table
 type RegistryAdapter struct {
-	// TODO: implement
+	db *sql.DB
+}
+
+// NewRegistryAdapter creates a registry adapter
+func NewRegistryAdapter(db *sql.DB) *RegistryAdapter {
+	return &RegistryAdapter{db: db}
 }
 
 // GetContract retrieves a variable contract by key
 func (r *RegistryAdapter) GetContract(ctx context.Context, varKey string) (*dataset.VariableContract, error) {
-	// TODO: implement database lookup
-	// For now, return a stub contract
-	return &dataset.VariableContract{
-		VarKey:           core.VariableKey(varKey),
-		AsOfMode:         dataset.AsOfLatestValue,
-		StatisticalType:  dataset.TypeNumeric,
-		WindowDays:       nil,
-		ImputationPolicy: "zero_fill",
-		ScalarGuarantee:  true,
-	}, nil
+	contract, err := loadVariableContract(ctx, r.db, core.VariableKey(varKey))
+	if err != nil {
+		return nil, err
+	}
+	return contract.domain(), nil
+}
+
+// SaveContract creates or replaces a variable contract
+func (r *RegistryAdapter) SaveContract(ctx context.Context, contract *dataset.VariableContract) error {
+	if err := contract.Days().Validate(); err != nil {
+		return fmt.Errorf("contract %s: %w", contract.VarKey, err)
+	}
+	dayCount, err := dataset.ParseDayCount(string(contract.DayCount))
+	if err != nil {
+		return fmt.Errorf("contract %s: %w", contract.VarKey, err)
+	}
+	var calendar []byte
+	if contract.Calendar != nil {
+		if calendar, err = json.Marshal(contract.Calendar); err != nil {
+			return fmt.Errorf("contract %s: invalid calendar: %w", contract.VarKey, err)
+		}
+	}
+	imputation := string(contract.ImputationPolicy)
+	if imputation == "" {
+		imputation = "zero_fill"
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO variable_contracts (var_key, as_of_mode, statistical_type, window_days,
+		                                imputation_policy, scalar_guarantee, lag_days, day_count, calendar)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (var_key) DO UPDATE SET
+			as_of_mode = EXCLUDED.as_of_mode,
+			statistical_type = EXCLUDED.statistical_type,
+			window_days = EXCLUDED.window_days,
+			imputation_policy = EXCLUDED.imputation_policy,
+			scalar_guarantee = EXCLUDED.scalar_guarantee,
+			lag_days = EXCLUDED.lag_days,
+			day_count = EXCLUDED.day_count,
+			calendar = EXCLUDED.calendar`,
+		string(contract.VarKey), string(contract.AsOfMode), string(contract.StatisticalType),
+		nullDays(contract.WindowDays), imputation, contract.ScalarGuarantee,
+		nullDays(contract.LagDays), string(dayCount), calendar)
+	if err != nil {
+		return fmt.Errorf("failed to save contract %s: %w", contract.VarKey, err)
+	}
+	return nil
+}
+
+// DeleteContract removes a variable contract
+func (r *RegistryAdapter) DeleteContract(ctx context.Context, varKey string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM variable_contracts WHERE var_key = $1`, varKey); err != nil {
+		return fmt.Errorf("failed to delete contract %s: %w", varKey, err)
+	}
+	return nil
+}
+
+// nullDays stores an unset window or lag as NULL
+func nullDays(days *int) sql.NullInt32 {
+	if days == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: int32(*days), Valid: true}
 }
 
-// Ensure RegistryAdapter implements RegistryPort
-var _ ports.RegistryPort = (*RegistryAdapter)(nil)
+// Ensure RegistryAdapter implements ContractStore
+var _ ports.ContractStore = (*RegistryAdapter)(nil)
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	"gohypo/adapters/datareadiness/synthesizer"
 	"gohypo/domain/core"
 	"gohypo/domain/datareadiness/ingestion"
+	"gohypo/domain/datareadiness/profiling"
 	"gohypo/domain/dataset"
 	"gohypo/ports"
 )
@@ -119,6 +122,15 @@ func (a *ExcelMatrixResolverAdapter) ResolveMatrix(ctx context.Context, req port
 		log.Printf("[ExcelMatrixResolver] Cache hit - data ready in %.2fms", float64(dataReadTime.Nanoseconds())/1e6)
 	}
 
+	// Read numbers and dates in the configured locale, or the one detected from the file
+	if err := a.applyLocale(rawData); err != nil {
+		return nil, err
+	}
+	// Timestamps without an offset are read in the configured zone
+	if _, err := core.ResolveTimeZone(a.config.CoercionConfig.TimeZone, core.TimeZoneFromSpec); err != nil {
+		return nil, fmt.Errorf("invalid coercion config: %w", err)
+	}
+
 	// Step 2: Auto-detect entity column
 	entityColumn, err := a.reader.DetectEntityColumn(rawData)
 	if err != nil {
@@ -147,8 +159,10 @@ func (a *ExcelMatrixResolverAdapter) ResolveMatrix(ctx context.Context, req port
 		return nil, fmt.Errorf("contract synthesis failed: %w", err)
 	}
 
-	// Step 6: Filter to requested variables
-	availableDrafts := a.filterRequestedVariables(contractDrafts, req.VarKeys)
+	// Step 6: Filter to requested variables, in header order so the matrix and its
+	// fingerprint do not depend on the order profiles came back in
+	availableDrafts := a.filterRequestedVariables(contractDrafts, req.VarKeys, rawData.Columns)
+	orderByHeaders(availableDrafts, rawData.Headers)
 
 	// Step 7: Create MatrixBundle using standardized contracts
 	bundle, err := a.buildMatrixBundle(rawData, availableDrafts, req)
@@ -163,6 +177,26 @@ func (a *ExcelMatrixResolverAdapter) ResolveMatrix(ctx context.Context, req port
 	return bundle, nil
 }
 
+// applyLocale sets the locale the coercer reads values in. A locale named in the config wins
+// over the detected one; JSON data without a named locale keeps the coercer's own rules.
+func (a *ExcelMatrixResolverAdapter) applyLocale(data *ExcelData) error {
+	if name := a.config.CoercionConfig.Locale; name != "" && name != dataset.LocaleAuto {
+		locale, err := dataset.ResolveLocale(name, nil)
+		if err != nil {
+			return fmt.Errorf("invalid coercion config: %w", err)
+		}
+		data.Locale = locale
+	}
+	if data.Locale.Name == "" {
+		return nil
+	}
+	if current, ok := a.coercer.Locale(); !ok || current != data.Locale {
+		log.Printf("[ExcelMatrixResolver] Reading numbers and dates as %s", data.Locale)
+	}
+	a.coercer.UseLocale(data.Locale)
+	return nil
+}
+
 // convertToCanonicalEvents creates events for profiling using standardized coercer
 func (a *ExcelMatrixResolverAdapter) convertToCanonicalEvents(rawData *ExcelData) ([]ingestion.CanonicalEvent, error) {
 	var events []ingestion.CanonicalEvent
@@ -244,7 +278,9 @@ func (a *ExcelMatrixResolverAdapter) buildMatrixBundle(
 		"entity_column": a.entityColumn,
 	})
 
-	bundle := dataset.NewMatrixBundle(req.SnapshotID, req.ViewID, cohortHash, core.NewCutoffAt(core.Now().Time()), core.Lag(0))
+	cutoff := core.NewCutoffAt(core.Now().Time().UTC())
+	bundle := dataset.NewMatrixBundle(req.SnapshotID, req.ViewID, cohortHash, cutoff, core.Lag(0))
+	bundle.TimeZone = a.coercer.TimeZone().Decision(cutoff)
 
 	bundle.Matrix.EntityIDs = entityIDs
 	bundle.Matrix.Data = make([][]float64, len(entityIDs))
@@ -294,6 +330,7 @@ func (a *ExcelMatrixResolverAdapter) buildMatrixBundle(
 		bundle.ColumnMeta = append(bundle.ColumnMeta, meta)
 		bundle.Audits = append(bundle.Audits, meta.ResolutionAudit)
 	}
+	bundle.Columnarize()
 
 	// Compute fingerprint
 	bundle.Fingerprint = core.Hash(fmt.Sprintf("excel-%s-%d-%d", a.config.FilePath, len(entityIDs), len(drafts)))
@@ -333,6 +370,16 @@ func (a *ExcelMatrixResolverAdapter) contractValueToFloat64(value ingestion.Valu
 			}
 			return float64(hash % 100)
 		}
+	case dataset.TypeOrdinal:
+		// Levels are encoded by rank; a level outside the scale has no rank
+		level := value.String()
+		if value.Type == ingestion.ValueTypeNumeric && value.NumericVal != nil {
+			level = strconv.FormatFloat(*value.NumericVal, 'f', -1, 64)
+		}
+		if rank, ok := profiling.LevelRank(contract.OrdinalLevels, level); ok {
+			return float64(rank)
+		}
+		return math.NaN()
 	case dataset.TypeNumeric:
 		if value.Type == ingestion.ValueTypeNumeric && value.NumericVal != nil {
 			return *value.NumericVal
@@ -351,14 +398,19 @@ func (a *ExcelMatrixResolverAdapter) contractValueToFloat64(value ingestion.Valu
 
 // Helper methods
 
-func (a *ExcelMatrixResolverAdapter) filterRequestedVariables(drafts []synthesizer.ContractDraft, requested []core.VariableKey) []synthesizer.ContractDraft {
+func (a *ExcelMatrixResolverAdapter) filterRequestedVariables(drafts []synthesizer.ContractDraft, requested []core.VariableKey, columns dataset.ColumnNames) []synthesizer.ContractDraft {
 	if len(requested) == 0 {
 		return drafts // Return all if no filter specified
 	}
 
+	// Variables may be requested by their original header, as stored before headers
+	// were normalized
 	reqSet := make(map[string]bool)
 	for _, key := range requested {
 		reqSet[string(key)] = true
+		if resolved, ok := columns.Resolve(string(key)); ok {
+			reqSet[resolved] = true
+		}
 	}
 
 	var filtered []synthesizer.ContractDraft
@@ -370,6 +422,27 @@ func (a *ExcelMatrixResolverAdapter) filterRequestedVariables(drafts []synthesiz
 	return filtered
 }
 
+// orderByHeaders sorts drafts by their column's position in the file, variables not among
+// the headers last by key
+func orderByHeaders(drafts []synthesizer.ContractDraft, headers []string) {
+	position := make(map[string]int, len(headers))
+	for i, header := range headers {
+		position[header] = i
+	}
+	sort.SliceStable(drafts, func(i, j int) bool {
+		pi, okI := position[drafts[i].VariableKey]
+		pj, okJ := position[drafts[j].VariableKey]
+		switch {
+		case okI && okJ:
+			return pi < pj
+		case okI != okJ:
+			return okI
+		default:
+			return drafts[i].VariableKey < drafts[j].VariableKey
+		}
+	})
+}
+
 func (a *ExcelMatrixResolverAdapter) shouldIncludeEntity(entityID string, requested []core.ID) bool {
 	if len(requested) == 0 {
 		return true // Include all if no filter
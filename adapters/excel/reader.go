@@ -13,15 +13,17 @@ import (
 	"time"
 
 	"gohypo/adapters/datareadiness/coercer"
+	"gohypo/adapters/datareadiness/jsonevents"
 	"gohypo/domain/datareadiness/ingestion"
+	"gohypo/domain/dataset"
 
 	"github.com/xuri/excelize/v2"
 )
 
-// DataReader handles reading Excel and CSV files
+// DataReader handles reading Excel, CSV and JSON/JSONL event files
 type DataReader struct {
 	filePath string
-	fileType string // "xlsx" or "csv"
+	fileType string // "xlsx", "csv" or "json"
 }
 
 // ExcelReader is an alias for DataReader for backward compatibility
@@ -31,8 +33,11 @@ type ExcelReader = DataReader
 func NewDataReader(filePath string) *DataReader {
 	ext := strings.ToLower(filepath.Ext(filePath))
 	fileType := "xlsx"
-	if ext == ".csv" {
+	switch ext {
+	case ".csv":
 		fileType = "csv"
+	case ".json", ".jsonl", ".ndjson":
+		fileType = "json"
 	}
 	return &DataReader{filePath: filePath, fileType: fileType}
 }
@@ -56,6 +61,8 @@ func (r *DataReader) ReadData() (*ExcelData, error) {
 		return r.readCSVData()
 	case "xlsx":
 		return r.readExcelData()
+	case "json":
+		return r.readJSONData()
 	default:
 		return nil, fmt.Errorf("unsupported file type: %s", r.fileType)
 	}
@@ -112,13 +119,50 @@ func (r *DataReader) readCSVData() (*ExcelData, error) {
 	return r.processRows(rows)
 }
 
+// readJSONData reads JSON or JSONL events, flattened into one column per variable
+func (r *DataReader) readJSONData() (*ExcelData, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JSON file: %w", err)
+	}
+	defer file.Close()
+
+	readStart := time.Now()
+	doc, err := jsonevents.Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON file: %w", err)
+	}
+	log.Printf("[DataReader] JSON file read in %.2fms (%d events, %d variables)",
+		float64(time.Since(readStart).Nanoseconds())/1e6, len(doc.Records), len(doc.Keys))
+	return JSONData(doc), nil
+}
+
+// JSONData lays flattened events out as rows, leaving absent values out of each row
+func JSONData(doc *jsonevents.Document) *ExcelData {
+	data := &ExcelData{
+		Headers: append([]string(nil), doc.Keys...),
+		Rows:    make([]RawRowData, len(doc.Records)),
+	}
+	for i, record := range doc.Records {
+		row := make(RawRowData, len(record))
+		for key, value := range record {
+			if value != nil {
+				row[key] = jsonevents.Format(value)
+			}
+		}
+		data.Rows[i] = row
+	}
+	return data
+}
+
 // processRows converts raw string rows into ExcelData format
 func (r *DataReader) processRows(rows [][]string) (*ExcelData, error) {
-	// Extract headers from first row
-	headerRow := rows[0]
-	headers := make([]string, len(headerRow))
-	for i, header := range headerRow {
-		headers[i] = strings.TrimSpace(header)
+	// Extract headers from first row, normalized so keys survive units, punctuation and
+	// duplicate names
+	columns := dataset.NormalizeColumnNames(rows[0])
+	headers := columns.Keys()
+	if renamed := columns.Renamed(); len(renamed) > 0 {
+		log.Printf("[DataReader] Normalized %d of %d column names", len(renamed), len(columns))
 	}
 
 	// Extract data rows
@@ -142,9 +186,20 @@ func (r *DataReader) processRows(rows [][]string) (*ExcelData, error) {
 	return &ExcelData{
 		Headers: headers,
 		Rows:    dataRows,
+		Columns: columns,
+		Locale:  dataset.DetectTableLocale(rows[1:]),
 	}, nil
 }
 
+// newCoercer creates a coercer reading values in the data's locale, when it has one
+func newCoercer(data *ExcelData) *coercer.TypeCoercer {
+	c := coercer.NewTypeCoercer(coercer.DefaultCoercionConfig())
+	if data.Locale.Name != "" {
+		c.UseLocale(data.Locale)
+	}
+	return c
+}
+
 // DetectEntityColumn automatically detects the entity column
 func (r *DataReader) DetectEntityColumn(data *ExcelData) (string, error) {
 	if len(data.Rows) == 0 {
@@ -231,7 +286,7 @@ func (r *DataReader) InferColumnTypes(data *ExcelData) (map[string]string, error
 	}
 	defer f.Close()
 
-	coercer := coercer.NewTypeCoercer(coercer.DefaultCoercionConfig())
+	coercer := newCoercer(data)
 	columnTypes := make(map[string]string)
 	sheetName := "Sheet1"
 
@@ -402,7 +457,7 @@ func (r *DataReader) inferTypeWithExcelHints(
 
 // inferColumnTypesFromStrings fallback method using only string values
 func (r *DataReader) inferColumnTypesFromStrings(data *ExcelData) (map[string]string, error) {
-	coercer := coercer.NewTypeCoercer(coercer.DefaultCoercionConfig())
+	coercer := newCoercer(data)
 	columnTypes := make(map[string]string)
 
 	maxSampleSize := 500
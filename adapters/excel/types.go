@@ -1,13 +1,14 @@
 package excel
 
+import "gohypo/domain/dataset"
+
 // RawRowData represents a row of raw Excel data as string key-value pairs
 type RawRowData map[string]string
 
 // ExcelData represents the complete Excel dataset
 type ExcelData struct {
-	Headers []string     // Column headers
-	Rows    []RawRowData // Data rows
+	Headers []string            // Column headers, normalized keys for spreadsheet files
+	Rows    []RawRowData        // Data rows
+	Columns dataset.ColumnNames // How each header was normalized; nil for JSON, whose keys are field paths
+	Locale  dataset.Locale      // Locale detected from the cells; zero for JSON, whose numbers are not localized
 }
-
-
-
@@ -0,0 +1,310 @@
+package excel
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"gohypo/domain/core"
+	"gohypo/domain/stats"
+	"gohypo/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Sheets of a sweep workbook, in tab order
+const (
+	SheetRelationships = "Relationships"
+	SheetProfiles      = "Profiles"
+	SheetWarnings      = "Warnings"
+	SheetManifest      = "Manifest"
+)
+
+// WorkbookContentType is the MIME type of a written workbook
+const WorkbookContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// sweepRelationship is the part of a relationship payload the workbook shows
+type sweepRelationship struct {
+	ID                    string   `json:"-"`
+	CauseKey              string   `json:"cause_key"`
+	EffectKey             string   `json:"effect_key"`
+	VariableX             string   `json:"variable_x"`
+	VariableY             string   `json:"variable_y"`
+	TestType              string   `json:"test_type"`
+	Correlation           *float64 `json:"correlation"`
+	EffectSize            *float64 `json:"effect_size"`
+	PValue                float64  `json:"p_value"`
+	QValue                float64  `json:"q_value"`
+	SampleSize            int      `json:"sample_size"`
+	PracticalSignificance string   `json:"practical_significance"`
+	ConfidenceLevel       string   `json:"confidence_level"`
+	Warnings              []string `json:"warnings"`
+}
+
+// pair returns the relationship's variables, whichever naming its payload uses
+func (r sweepRelationship) pair() (string, string) {
+	if r.CauseKey != "" || r.EffectKey != "" {
+		return r.CauseKey, r.EffectKey
+	}
+	return r.VariableX, r.VariableY
+}
+
+// effect is the relationship's effect size: eta squared for group differences, the
+// correlation otherwise
+func (r sweepRelationship) effect() float64 {
+	switch {
+	case r.EffectSize != nil:
+		return *r.EffectSize
+	case r.Correlation != nil:
+		return *r.Correlation
+	}
+	return math.NaN()
+}
+
+// variableProfile is a variable's row in the profiles sheet
+type variableProfile struct {
+	Variable    string   `json:"variable_key"`
+	Rows        *int     `json:"rows"`
+	SampleSize  *int     `json:"sample_size"`
+	MissingRate *float64 `json:"missing_rate"`
+	Cardinality *int     `json:"cardinality"`
+	Variance    *float64 `json:"variance"`
+	Skewness    *float64 `json:"skewness"`
+
+	pairs, significant, warnings int
+	partner                      string
+	strongest                    float64
+}
+
+// WriteSweepWorkbook writes a sweep's artifacts as an Excel workbook: one row per
+// relationship, a profile per variable, each warning with its catalog entry and the
+// sweep manifest. Artifacts of other kinds are left out.
+func WriteSweepWorkbook(w io.Writer, artifacts []core.Artifact) error {
+	var relationships []sweepRelationship
+	profiles := map[string]*variableProfile{}
+	profile := func(variable string) *variableProfile {
+		if p, ok := profiles[variable]; ok {
+			return p
+		}
+		p := &variableProfile{Variable: variable, strongest: math.NaN()}
+		profiles[variable] = p
+		return p
+	}
+	var manifest map[string]interface{}
+
+	for _, artifact := range artifacts {
+		switch artifact.Kind {
+		case "association", core.ArtifactRelationship:
+			var rel sweepRelationship
+			if err := decodeArtifact(artifact, &rel); err != nil {
+				return err
+			}
+			rel.ID = string(artifact.ID)
+			relationships = append(relationships, rel)
+		case core.ArtifactVariableProfile:
+			var p variableProfile
+			if err := decodeArtifact(artifact, &p); err != nil {
+				return err
+			}
+			if p.Variable == "" {
+				p.Variable = string(artifact.ID)
+			}
+			merged := profile(p.Variable)
+			merged.Rows, merged.SampleSize, merged.MissingRate = p.Rows, p.SampleSize, p.MissingRate
+			merged.Cardinality, merged.Variance, merged.Skewness = p.Cardinality, p.Variance, p.Skewness
+		case core.ArtifactSweepManifest:
+			if err := decodeArtifact(artifact, &manifest); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Strongest first, as the run pages list them
+	sort.SliceStable(relationships, func(i, j int) bool {
+		return math.Abs(relationships[i].effect()) > math.Abs(relationships[j].effect())
+	})
+	for _, rel := range relationships {
+		x, y := rel.pair()
+		for _, side := range [][2]string{{x, y}, {y, x}} {
+			p := profile(side[0])
+			p.pairs++
+			p.warnings += len(rel.Warnings)
+			if rel.QValue < models.SummarySignificanceLevel {
+				p.significant++
+			}
+			if effect := rel.effect(); !math.IsNaN(effect) && (math.IsNaN(p.strongest) || math.Abs(effect) > math.Abs(p.strongest)) {
+				p.strongest, p.partner = effect, side[1]
+			}
+		}
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	header, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("failed to create header style: %w", err)
+	}
+	sheet := newSheetWriter(f, header)
+
+	rows := [][]interface{}{}
+	for _, rel := range relationships {
+		x, y := rel.pair()
+		rows = append(rows, []interface{}{x, y, rel.TestType, number(rel.effect()), rel.PValue, rel.QValue,
+			rel.SampleSize, rel.PracticalSignificance, rel.ConfidenceLevel, strings.Join(rel.Warnings, ", "), rel.ID})
+	}
+	sheet.write(SheetRelationships, []string{"Cause", "Effect", "Test", "Effect size", "p-value", "q-value",
+		"n", "Practical significance", "Evidence", "Warnings", "Artifact ID"}, rows)
+
+	variables := make([]string, 0, len(profiles))
+	for variable := range profiles {
+		variables = append(variables, variable)
+	}
+	sort.Strings(variables)
+	rows = rows[:0]
+	for _, variable := range variables {
+		p := profiles[variable]
+		rows = append(rows, []interface{}{p.Variable, optionalInt(p.Rows), optionalInt(p.SampleSize), optionalFloat(p.MissingRate),
+			optionalInt(p.Cardinality), optionalFloat(p.Variance), optionalFloat(p.Skewness),
+			p.pairs, p.significant, p.partner, number(p.strongest), p.warnings})
+	}
+	sheet.write(SheetProfiles, []string{"Variable", "Rows", "Sample size", "Missing rate", "Cardinality", "Variance", "Skewness",
+		"Pairs tested", "Significant pairs", "Strongest partner", "Strongest effect", "Warnings"}, rows)
+
+	rows = rows[:0]
+	for _, rel := range relationships {
+		x, y := rel.pair()
+		infos := make([]stats.WarningInfo, 0, len(rel.Warnings))
+		for _, code := range rel.Warnings {
+			infos = append(infos, stats.DescribeWarning(stats.WarningCode(code)))
+		}
+		stats.SortWarnings(infos)
+		for _, info := range infos {
+			rows = append(rows, []interface{}{x, y, string(info.Code), string(info.Severity), info.Title, info.Description, info.Remediation})
+		}
+	}
+	sheet.write(SheetWarnings, []string{"Cause", "Effect", "Code", "Severity", "Title", "Description", "Remediation"}, rows)
+
+	keys := make([]string, 0, len(manifest))
+	for key := range manifest {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	rows = rows[:0]
+	for _, key := range keys {
+		rows = append(rows, []interface{}{key, cellValue(manifest[key])})
+	}
+	sheet.write(SheetManifest, []string{"Field", "Value"}, rows)
+
+	if sheet.err != nil {
+		return sheet.err
+	}
+	f.DeleteSheet("Sheet1")
+	f.SetActiveSheet(0)
+	if _, err := f.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write workbook: %w", err)
+	}
+	return nil
+}
+
+// sheetWriter adds sheets to a workbook, keeping the first error
+type sheetWriter struct {
+	f      *excelize.File
+	header int
+	err    error
+}
+
+func newSheetWriter(f *excelize.File, header int) *sheetWriter {
+	return &sheetWriter{f: f, header: header}
+}
+
+// write adds a sheet with a bold, frozen, filterable header row
+func (s *sheetWriter) write(name string, columns []string, rows [][]interface{}) {
+	if s.err != nil {
+		return
+	}
+	if _, err := s.f.NewSheet(name); err != nil {
+		s.err = fmt.Errorf("failed to add sheet %s: %w", name, err)
+		return
+	}
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	if err := s.f.SetSheetRow(name, "A1", &header); err != nil {
+		s.err = fmt.Errorf("failed to write %s header: %w", name, err)
+		return
+	}
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := s.f.SetSheetRow(name, cell, &row); err != nil {
+			s.err = fmt.Errorf("failed to write %s row %d: %w", name, i+1, err)
+			return
+		}
+	}
+	last, _ := excelize.CoordinatesToCellName(len(columns), 1)
+	if err := s.f.SetCellStyle(name, "A1", last, s.header); err != nil {
+		s.err = fmt.Errorf("failed to style %s header: %w", name, err)
+		return
+	}
+	if err := s.f.SetPanes(name, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		s.err = fmt.Errorf("failed to freeze %s header: %w", name, err)
+		return
+	}
+	last, _ = excelize.CoordinatesToCellName(len(columns), len(rows)+1)
+	if err := s.f.AutoFilter(name, "A1:"+last, nil); err != nil {
+		s.err = fmt.Errorf("failed to add %s filter: %w", name, err)
+	}
+}
+
+// decodeArtifact decodes an artifact's payload into v, whether it is typed or was read
+// back from JSON
+func decodeArtifact(artifact core.Artifact, v interface{}) error {
+	data, err := json.Marshal(artifact.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s artifact %s: %w", artifact.Kind, artifact.ID, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s artifact %s: %w", artifact.Kind, artifact.ID, err)
+	}
+	return nil
+}
+
+// cellValue writes scalars as they are and anything nested as JSON
+func cellValue(value interface{}) interface{} {
+	switch value.(type) {
+	case nil:
+		return ""
+	case string, bool, float64, int, int64:
+		return value
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// number leaves a cell empty for a missing value rather than writing NaN
+func number(value float64) interface{} {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return ""
+	}
+	return value
+}
+
+func optionalInt(value *int) interface{} {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func optionalFloat(value *float64) interface{} {
+	if value == nil {
+		return ""
+	}
+	return number(*value)
+}
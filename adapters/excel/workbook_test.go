@@ -0,0 +1,61 @@
+package excel
+
+import (
+	"bytes"
+	"testing"
+
+	"gohypo/domain/core"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// TestWriteSweepWorkbook verifies each sheet holds its rows: relationships strongest first,
+// a profile per variable, warnings from the catalog and the manifest fields
+func TestWriteSweepWorkbook(t *testing.T) {
+	artifacts := []core.Artifact{
+		{ID: "corr_a_b", Kind: "association", Payload: map[string]interface{}{
+			"cause_key": "a", "effect_key": "b", "test_type": "pearson_correlation", "correlation": 0.2,
+			"p_value": 0.2, "q_value": 0.3, "sample_size": 40,
+		}},
+		{ID: "corr_a_c", Kind: "association", Payload: map[string]interface{}{
+			"cause_key": "a", "effect_key": "c", "test_type": "pearson_correlation", "correlation": -0.7,
+			"p_value": 0.001, "q_value": 0.002, "sample_size": 25, "warnings": []string{"OUTLIER_DRIVEN", "LOW_N"},
+		}},
+		{ID: "a", Kind: core.ArtifactVariableProfile, Payload: map[string]interface{}{"variable_key": "a", "missing_rate": 0.1, "cardinality": 12}},
+		{ID: "stats_sweep_manifest", Kind: core.ArtifactSweepManifest, Payload: map[string]interface{}{
+			"fdr_method": "BH", "bundle_fingerprint": "fp-1", "analysis_policy": map[string]interface{}{"alpha": 0.05},
+		}},
+		{ID: "h1", Kind: core.ArtifactResearchDirective, Payload: map[string]interface{}{"claim": "ignored"}},
+	}
+	var buf bytes.Buffer
+	if err := WriteSweepWorkbook(&buf, artifacts); err != nil {
+		t.Fatalf("WriteSweepWorkbook: %v", err)
+	}
+
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("failed to read the workbook back: %v", err)
+	}
+	defer f.Close()
+	if sheets := f.GetSheetList(); len(sheets) != 4 || sheets[0] != SheetRelationships || sheets[3] != SheetManifest {
+		t.Fatalf("unexpected sheets %v", sheets)
+	}
+
+	rows, _ := f.GetRows(SheetRelationships)
+	if len(rows) != 3 || rows[1][1] != "c" || rows[1][3] != "-0.7" || rows[1][9] != "OUTLIER_DRIVEN, LOW_N" {
+		t.Errorf("unexpected relationships %v", rows)
+	}
+	rows, _ = f.GetRows(SheetProfiles)
+	if len(rows) != 4 || rows[1][0] != "a" || rows[1][3] != "0.1" || rows[1][7] != "2" || rows[1][8] != "1" || rows[1][9] != "c" {
+		t.Errorf("unexpected profiles %v", rows)
+	}
+	rows, _ = f.GetRows(SheetWarnings)
+	// Most serious first; both are cautions, in catalog order
+	if len(rows) != 3 || rows[1][2] == "" || rows[1][3] != "caution" || rows[1][6] == "" {
+		t.Errorf("unexpected warnings %v", rows)
+	}
+	rows, _ = f.GetRows(SheetManifest)
+	if len(rows) != 4 || rows[1][0] != "analysis_policy" || rows[1][1] != `{"alpha":0.05}` || rows[2][1] != "fp-1" {
+		t.Errorf("unexpected manifest %v", rows)
+	}
+}
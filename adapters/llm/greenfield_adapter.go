@@ -6,20 +6,41 @@ import (
 	"fmt"
 	"gohypo/ai"
 	"gohypo/domain/core"
+	"gohypo/domain/discovery"
 	"gohypo/domain/greenfield"
 	"gohypo/internal/analysis"
 	"gohypo/models"
 	"gohypo/ports"
+	"log"
+	"math"
 	"strings"
 )
 
 type GreenfieldAdapter struct {
 	StructuredClient *ai.StructuredClient[models.GreenfieldResearchOutput]
+	Sampler          *ai.StructuredClient[models.GreenfieldResearchOutput] // higher temperature, for self-consistency samples
 	LogicalAuditor   *LogicalAuditorAdapter
 	Scout            *ai.ForensicScout
+	provider         string
+	exemplars        ports.ExemplarStore // curated few-shot examples; nil disables them
+	batch            BatchOptions
+
+	// Kept to rebuild the adapter for workspaces with their own LLM settings
+	config *models.AIConfig
+	cache  ports.LLMResponseCache
 }
 
 func NewGreenfieldAdapter(config *models.AIConfig) *GreenfieldAdapter {
+	return newGreenfieldAdapter(config, nil)
+}
+
+// NewCachedGreenfieldAdapter builds the adapter with provider calls served from cache when
+// the same prompt was already answered under the same provider settings
+func NewCachedGreenfieldAdapter(config *models.AIConfig, cache ports.LLMResponseCache) *GreenfieldAdapter {
+	return newGreenfieldAdapter(config, cache)
+}
+
+func newGreenfieldAdapter(config *models.AIConfig, cache ports.LLMResponseCache) *GreenfieldAdapter {
 	// Create a reasonable token limit for hypothesis generation
 	// gpt-5.2 has 8192 token context limit, so limit completion to ~5000 tokens
 	reasonableConfig := *config // copy config
@@ -27,11 +48,25 @@ func NewGreenfieldAdapter(config *models.AIConfig) *GreenfieldAdapter {
 		reasonableConfig.MaxTokens = 5000 // Reasonable limit for hypothesis generation
 	}
 
-	return &GreenfieldAdapter{
+	samplingConfig := reasonableConfig
+	samplingConfig.Temperature = selfConsistencyTemperature
+
+	ga := &GreenfieldAdapter{
 		StructuredClient: ai.NewStructuredClientLegacy[models.GreenfieldResearchOutput](&reasonableConfig, config.PromptsDir),
+		Sampler:          ai.NewStructuredClientLegacy[models.GreenfieldResearchOutput](&samplingConfig, config.PromptsDir),
 		LogicalAuditor:   NewLogicalAuditorAdapter(config),
 		Scout:            ai.NewForensicScout(config),
+		provider:         config.Mode(),
+		batch:            BatchOptionsFromEnv(),
+		config:           config,
+		cache:            cache,
 	}
+
+	// Metering wraps the cache so cache hits are counted as free calls
+	ga.StructuredClient.LLMClient = ai.NewMeteredLLMClient(ai.NewCachingLLMClient(ga.StructuredClient.LLMClient, cache, config))
+	ga.Sampler.LLMClient = ai.NewMeteredLLMClient(ai.NewCachingLLMClient(ga.Sampler.LLMClient, cache, &samplingConfig))
+	ga.LogicalAuditor.StructuredClient.LLMClient = ai.NewMeteredLLMClient(ai.NewCachingLLMClient(ga.LogicalAuditor.StructuredClient.LLMClient, cache, config))
+	return ga
 }
 
 // GetScout returns the Forensic Scout instance (for direct access)
@@ -39,7 +74,39 @@ func (ga *GreenfieldAdapter) GetScout() *ai.ForensicScout {
 	return ga.Scout
 }
 
+// forSettings returns the adapter to use for a request's workspace and run LLM settings: this
+// one when there are none, otherwise one rebuilt on the global config with them applied
+func (ga *GreenfieldAdapter) forSettings(settings *models.LLMSettings) *GreenfieldAdapter {
+	if settings == nil || settings.IsZero() || ga.config == nil {
+		return ga
+	}
+	derived := newGreenfieldAdapter(settings.Apply(ga.config), ga.cache)
+	derived.exemplars = ga.exemplars
+	derived.batch = ga.batch
+	if settings.Model != "" {
+		derived.StructuredClient.Model = settings.Model
+		derived.Sampler.Model = settings.Model
+		derived.LogicalAuditor.StructuredClient.Model = settings.Model
+	}
+	if settings.MaxTokens > 0 {
+		maxTokens := settings.MaxTokens
+		if maxTokens > 5000 {
+			maxTokens = 5000 // same cap as the global config
+		}
+		derived.StructuredClient.MaxTokens = maxTokens
+		derived.Sampler.MaxTokens = maxTokens
+	}
+	log.Printf("[GreenfieldAdapter] ⚙️ Using workspace LLM settings: provider=%s model=%q temperature=%g",
+		derived.provider, settings.Model, derived.config.Temperature)
+	return derived
+}
+
 func (ga *GreenfieldAdapter) GenerateResearchDirectives(ctx context.Context, req ports.GreenfieldResearchRequest) (*ports.GreenfieldResearchResponse, error) {
+	ga = ga.forSettings(req.LLM)
+
+	// Account tokens and cost for every LLM call this run makes
+	ctx, meter := ai.WithUsageMeter(ctx, string(req.RunID))
+
 	// Extract field names for scout analysis
 	fieldNames := make([]string, len(req.FieldMetadata))
 	for i, field := range req.FieldMetadata {
@@ -51,26 +118,22 @@ func (ga *GreenfieldAdapter) GenerateResearchDirectives(ctx context.Context, req
 		scoutResponse = nil
 	}
 
-	// Build evidence orchestration
-	orchestrator := analysis.NewEvidenceOrchestrator()
-	outcomeCol := ga.determineOutcomeColumn(req.FieldMetadata)
-
-	evidenceBrief := orchestrator.OrchestrateEvidence(
-		req.FieldMetadata,
-		req.StatisticalArtifacts,
-		outcomeCol,
-		[]string{},
-		map[string]string{},
-	)
-
-	dynamicPrompt := ga.buildDynamicResearchPrompt(evidenceBrief, req.FieldMetadata)
-
-	systemMessage := "You are a statistical research assistant. For dynamic e-value validation, you must select at least 1 referee from the approved list based on the hypothesis requirements. Output valid JSON only."
+	// Few-shot examples follow the detected domain unless the caller picked a pack
+	if req.DomainPack == "" && scoutResponse != nil {
+		req.DomainPack = scoutResponse.Domain
+	}
 
-	llmResponse, err := ga.StructuredClient.GetJsonResponseWithContext(ctx, "openai", dynamicPrompt, systemMessage)
+	// Large sweeps are split so each call sees a manageable slice of the relationships
+	batches := splitRelationshipBatches(req.StatisticalArtifacts, ga.batch.RelationshipsPerCall)
+	if len(batches) > 1 {
+		log.Printf("[GreenfieldAdapter] 📦 Generating over %d batches of up to %d relationships, %d calls at a time",
+			len(batches), ga.batch.RelationshipsPerCall, ga.batch.MaxConcurrency)
+	}
+	llmResponse, budget, prompts, err := mergeBatchResults(ga.generateBatches(ctx, req, batches))
 	if err != nil {
 		return nil, fmt.Errorf("LLM call failed: %w", err)
 	}
+	dynamicPrompt := strings.Join(prompts, "\n\n----- NEXT BATCH -----\n\n")
 
 	// Use industry context from scout result if available
 	if llmResponse.IndustryContext == "" && scoutResponse != nil {
@@ -89,15 +152,19 @@ func (ga *GreenfieldAdapter) GenerateResearchDirectives(ctx context.Context, req
 	// Generate engineering backlog from the directives
 	engineeringBacklog := ga.generateEngineeringBacklog(directives)
 
+	model, _ := ga.StructuredClient.RequestSettings()
+
 	return &ports.GreenfieldResearchResponse{
 		Directives:         directives,
 		EngineeringBacklog: engineeringBacklog,
 		RawLLMResponse:     llmResponse,   // Preserve raw LLM response for worker
 		RenderedPrompt:     dynamicPrompt, // Preserve dynamic prompt for debugging
+		Usage:              meter.Snapshot(),
+		PromptBudget:       budget,
 		Audit: ports.GreenfieldAudit{
 			GeneratorType: "llm",
-			Model:         "gpt-5.2", // TODO: Get from LLMClient
-			Temperature:   0.1,       // TODO: Get from LLMClient
+			Model:         model,
+			Temperature:   ga.config.Temperature,
 		},
 	}, nil
 }
@@ -123,6 +190,7 @@ func (ga *GreenfieldAdapter) convertToDomainDirectives(llmDirectives []models.Re
 				PermutationRuns: llmDir.RefereeGates.PermutationRuns,
 			},
 			ExplanationMarkdown: llmDir.ExplanationMarkdown,
+			SelfConsistency:     llmDir.SelfConsistency,
 			CreatedAt:           core.Now(),
 		}
 	}
@@ -254,8 +322,162 @@ func (ga *GreenfieldAdapter) estimateEffort(capability string) string {
 	return "medium" // Default
 }
 
+// greenfieldSystemMessage is sent ahead of every research prompt
+const greenfieldSystemMessage = "You are a statistical research assistant. For dynamic e-value validation, you must select at least 1 referee from the approved list based on the hypothesis requirements. Output valid JSON only."
+
+// PreviewResearchPrompt assembles exactly what GenerateResearchDirectives would send for req,
+// without calling the LLM, so prompt changes can be inspected and iterated on cheaply
+func (ga *GreenfieldAdapter) PreviewResearchPrompt(ctx context.Context, req ports.GreenfieldResearchRequest) (*ports.GreenfieldPromptPreview, error) {
+	ga = ga.forSettings(req.LLM)
+	prompt, sections, budget := ga.assembleResearchPrompt(ctx, req, "")
+	model, maxTokens := ga.StructuredClient.RequestSettings()
+	fullPrompt := ga.StructuredClient.AssemblePrompt(prompt, greenfieldSystemMessage)
+
+	return &ports.GreenfieldPromptPreview{
+		Provider:      ga.provider,
+		Model:         model,
+		MaxTokens:     maxTokens,
+		SystemMessage: greenfieldSystemMessage,
+		Prompt:        prompt,
+		FullPrompt:    fullPrompt,
+		Sections:      sections,
+		TotalChars:    len(fullPrompt),
+		Budget:        budget,
+	}, nil
+}
+
+// assembleResearchPrompt renders the research prompt for req and reports the size of each
+// injected context section. Associations and discovery anchors are optional context: when
+// they would overflow the model's window, the lowest-ranked ones are dropped and the
+// returned budget report says which. Few-shot examples from the request's domain pack are
+// budgeted the same way. A non-empty instruction is appended as fixed cost.
+func (ga *GreenfieldAdapter) assembleResearchPrompt(ctx context.Context, req ports.GreenfieldResearchRequest, instruction string) (string, []ports.PromptSection, *models.PromptBudgetReport) {
+	orchestrator := analysis.NewEvidenceOrchestrator()
+	outcomeCol := ga.determineOutcomeColumn(req.FieldMetadata)
+
+	evidenceBrief := orchestrator.OrchestrateEvidence(
+		req.FieldMetadata,
+		req.StatisticalArtifacts,
+		outcomeCol,
+		[]string{},
+		map[string]string{},
+	)
+	anchors := discoveryBriefFragments(req.DiscoveryBriefs)
+	examples := ga.fewShotExamples(ctx, req.DomainPack)
+
+	// Everything except associations and anchors is fixed cost
+	base := *evidenceBrief
+	base.Associations = []analysis.AssociationResult{}
+	basePrompt, _ := ga.buildDynamicResearchPrompt(&base, req.FieldMetadata, req.ValidatedHypothesisSummary, nil, "")
+	fixedTokens := ai.EstimateTokens(greenfieldSystemMessage) + ai.EstimateTokens(basePrompt)
+	if len(anchors) > 0 {
+		fixedTokens += ai.EstimateTokens("\n\nDISCOVERY BRIEF ANCHORS:")
+	}
+	if instruction != "" {
+		fixedTokens += ai.EstimateTokens("\n\n" + instruction)
+	}
+	if len(examples) > 0 {
+		fixedTokens += ai.EstimateTokens("\n\n" + fewShotHeader)
+	}
+
+	associationIDs := make([]string, len(evidenceBrief.Associations))
+	candidates := make([]ai.BudgetFragment, 0, len(evidenceBrief.Associations)+len(anchors))
+	for i, association := range evidenceBrief.Associations {
+		associationIDs[i] = associationFragmentID(association, i)
+		candidates = append(candidates, ai.BudgetFragment{
+			ID:       associationIDs[i],
+			Priority: associationPriority(association),
+			Strength: associationStrength(association),
+			Tokens:   associationTokens(association),
+		})
+	}
+	for _, anchor := range anchors {
+		candidates = append(candidates, ai.BudgetFragment{
+			ID:       anchor.id,
+			Priority: anchor.priority,
+			Strength: anchor.strength,
+			Tokens:   ai.EstimateTokens("\n- " + anchor.text),
+		})
+	}
+	for _, example := range examples {
+		candidates = append(candidates, ai.BudgetFragment{
+			ID:       "exemplar:" + example.id,
+			Priority: fewShotPriority,
+			Strength: example.strength,
+			Tokens:   ai.EstimateTokens(fmt.Sprintf("\n\nExample 0 (%s):\n%s", example.pack, example.body)),
+		})
+	}
+
+	model, maxTokens := ga.StructuredClient.RequestSettings()
+	budget, included := ai.NewPromptBudget(model, maxTokens).Fit(fixedTokens, candidates)
+
+	// Keep the original order so surviving evidence reads the same as in an untruncated prompt
+	trimmed := *evidenceBrief
+	trimmed.Associations = make([]analysis.AssociationResult, 0, len(evidenceBrief.Associations))
+	for i, association := range evidenceBrief.Associations {
+		if included[associationIDs[i]] {
+			trimmed.Associations = append(trimmed.Associations, association)
+		}
+	}
+	var anchorTexts []string
+	for _, anchor := range anchors {
+		if included[anchor.id] {
+			anchorTexts = append(anchorTexts, anchor.text)
+		}
+	}
+
+	var includedExamples []fewShotExample
+	for _, example := range examples {
+		if included["exemplar:"+example.id] {
+			includedExamples = append(includedExamples, example)
+		}
+	}
+
+	// Generation and dry runs both render here, so a real run sends the request's validated
+	// hypothesis summary and discovery brief anchors, not only the preview
+	prompt, sections := ga.buildDynamicResearchPrompt(&trimmed, req.FieldMetadata, req.ValidatedHypothesisSummary, anchorTexts, renderFewShotSection(includedExamples))
+	if instruction != "" {
+		prompt += "\n\n" + instruction
+	}
+	return prompt, sections, budget
+}
+
+// associationFragmentID names an association for the budget report, by evidence ID when the
+// sweep assigned one
+func associationFragmentID(a analysis.AssociationResult, index int) string {
+	if a.EvidenceID != "" {
+		return "association:" + a.EvidenceID
+	}
+	return fmt.Sprintf("association:%s->%s#%d", a.Feature, a.Outcome, index)
+}
+
+// associationPriority ranks FDR-significant associations above the rest
+func associationPriority(a analysis.AssociationResult) int {
+	if a.PValueAdj > 0 && a.PValueAdj <= 0.05 {
+		return 6
+	}
+	return 4
+}
+
+// associationStrength is the sweep's screening score, or the absolute effect when unscored
+func associationStrength(a analysis.AssociationResult) float64 {
+	if a.ScreeningScore > 0 {
+		return a.ScreeningScore
+	}
+	return math.Min(math.Abs(a.RawEffect), 1)
+}
+
+// associationTokens estimates an association's cost at its nesting depth in the evidence JSON
+func associationTokens(a analysis.AssociationResult) int {
+	data, err := json.MarshalIndent(a, "    ", "  ")
+	if err != nil {
+		return 0
+	}
+	return ai.EstimateTokens(",\n    " + string(data))
+}
+
 // buildDynamicResearchPrompt creates prompt content from evidence
-func (ga *GreenfieldAdapter) buildDynamicResearchPrompt(evidenceBrief *analysis.EvidenceBrief, fieldMetadata []greenfield.FieldMetadata) string {
+func (ga *GreenfieldAdapter) buildDynamicResearchPrompt(evidenceBrief *analysis.EvidenceBrief, fieldMetadata []greenfield.FieldMetadata, validatedSummary interface{}, fragments []string, fewShot string) (string, []ports.PromptSection) {
 
 	evidenceJSON, err := json.MarshalIndent(evidenceBrief, "", "  ")
 	if err != nil {
@@ -267,19 +489,104 @@ func (ga *GreenfieldAdapter) buildDynamicResearchPrompt(evidenceBrief *analysis.
 		fieldMetadataJSON = []byte(fmt.Sprintf("Error marshaling field metadata: %v", err))
 	}
 
+	summary := "No validated hypotheses available for feedback learning."
+	if validatedSummary != nil {
+		if summaryJSON, err := json.MarshalIndent(validatedSummary, "", "  "); err == nil {
+			summary = string(summaryJSON)
+		}
+	}
+
 	replacements := map[string]string{
 		"FIELD_METADATA_JSON":          string(fieldMetadataJSON),
 		"INDUSTRY_CONTEXT_INJECTION":   "Industry context will be injected by the adapter.",
 		"STATISTICAL_EVIDENCE_JSON":    string(evidenceJSON),
-		"VALIDATED_HYPOTHESIS_SUMMARY": "No validated hypotheses available for feedback learning.",
+		"VALIDATED_HYPOTHESIS_SUMMARY": summary,
 	}
 
 	prompt, err := ga.StructuredClient.PromptManager.RenderPrompt("greenfield", replacements)
 	if err != nil {
-		return fmt.Sprintf("INPUT DATA:\n%s\n\nGenerate 3 research hypotheses as JSON.", string(evidenceJSON))
+		prompt = fmt.Sprintf("INPUT DATA:\n%s\n\nGenerate 3 research hypotheses as JSON.", string(evidenceJSON))
+	}
+
+	if fewShot != "" {
+		prompt += "\n\n" + fewShot
+	}
+
+	anchors := ""
+	if len(fragments) > 0 {
+		anchors = "DISCOVERY BRIEF ANCHORS:\n- " + strings.Join(fragments, "\n- ")
+		prompt += "\n\n" + anchors
 	}
 
-	return prompt
+	sections := []ports.PromptSection{
+		{Name: "system_message", Chars: len(greenfieldSystemMessage)},
+		{Name: "field_metadata", Chars: len(fieldMetadataJSON)},
+		{Name: "statistical_evidence", Chars: len(evidenceJSON)},
+		{Name: "validated_hypothesis_summary", Chars: len(summary)},
+		{Name: "few_shot_examples", Chars: len(fewShot)},
+		{Name: "discovery_brief_anchors", Chars: len(anchors)},
+	}
+	return prompt, sections
+}
+
+// anchorFragment is one discovery brief line offered to the prompt budget
+type anchorFragment struct {
+	id       string
+	text     string
+	priority int
+	strength float64
+}
+
+// compiledAnchorPriority ranks compiled directive anchors just below the highest-priority
+// brief fragments, since they carry the lag and non-linearity guidance
+const compiledAnchorPriority = 7
+
+// discoveryBriefFragments compiles prompt anchors from whichever brief shape the caller passed,
+// along with each brief's own prompt fragments
+func discoveryBriefFragments(briefs interface{}) []anchorFragment {
+	var list []discovery.DiscoveryBrief
+	switch b := briefs.(type) {
+	case []discovery.DiscoveryBrief:
+		list = b
+	case []*discovery.DiscoveryBrief:
+		for _, brief := range b {
+			if brief != nil {
+				list = append(list, *brief)
+			}
+		}
+	}
+
+	var fragments []anchorFragment
+	seen := make(map[string]bool)
+	add := func(fragment anchorFragment) {
+		if !seen[fragment.text] {
+			seen[fragment.text] = true
+			fragments = append(fragments, fragment)
+		}
+	}
+	for _, brief := range list {
+		strength := brief.LLMContext.EvidenceStrength.OverallScore
+		for i, fragment := range ai.CompileResearchDirectiveFragments(brief) {
+			add(anchorFragment{
+				id:       fmt.Sprintf("anchor:%s#%d", brief.VariableKey, i),
+				text:     fmt.Sprintf("%s: %s", brief.VariableKey, fragment),
+				priority: compiledAnchorPriority,
+				strength: strength,
+			})
+		}
+		for i, fragment := range brief.LLMContext.PromptFragments {
+			if fragment.Content == "" {
+				continue
+			}
+			add(anchorFragment{
+				id:       fmt.Sprintf("fragment:%s:%s#%d", brief.VariableKey, fragment.Type, i),
+				text:     fmt.Sprintf("%s: %s", brief.VariableKey, fragment.Content),
+				priority: fragment.Priority,
+				strength: strength,
+			})
+		}
+	}
+	return fragments
 }
 
 // determineOutcomeColumn identifies the most likely outcome column from field metadata
@@ -321,18 +628,21 @@ func (ga *GreenfieldAdapter) enhanceDirectivesWithReferees(
 
 	enhanced := make([]models.ResearchDirectiveResponse, len(directives))
 
-	for i, directive := range directives {
-		// Convert field metadata to JSON string
-		fieldMetadataJSON, err := json.Marshal(fieldMetadata)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal field metadata: %w", err)
-		}
+	// Convert field metadata to JSON string
+	fieldMetadataJSON, err := json.Marshal(fieldMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal field metadata: %w", err)
+	}
 
-		// Convert statistical artifacts to JSON string
-		statisticalJSON, err := json.Marshal(statisticalArtifacts)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal statistical artifacts: %w", err)
-		}
+	// Convert statistical artifacts to JSON string
+	statisticalJSON, err := json.Marshal(statisticalArtifacts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal statistical artifacts: %w", err)
+	}
+
+	// One auditor call per directive, under the same concurrency cap as generation
+	runConcurrently(len(directives), ga.batch.MaxConcurrency, func(i int) {
+		directive := directives[i]
 
 		// Call logical auditor
 		auditorReq := ports.LogicalAuditorRequest{
@@ -365,7 +675,7 @@ func (ga *GreenfieldAdapter) enhanceDirectivesWithReferees(
 		}
 
 		enhanced[i] = directive
-	}
+	})
 
 	return enhanced, nil
 }
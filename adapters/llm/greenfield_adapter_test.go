@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gohypo/domain/discovery"
+	"gohypo/domain/greenfield"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// recordingLLMClient answers every call with no directives and keeps the prompts it was sent
+type recordingLLMClient struct {
+	prompts []string
+}
+
+func (c *recordingLLMClient) ChatCompletion(ctx context.Context, model string, prompt string, maxTokens int) (string, error) {
+	c.prompts = append(c.prompts, prompt)
+	return `{"research_directives": []}`, nil
+}
+
+func (c *recordingLLMClient) ChatCompletionWithUsage(ctx context.Context, model string, prompt string, maxTokens int) (*ports.LLMResponse, error) {
+	return c.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, nil)
+}
+
+func (c *recordingLLMClient) ChatCompletionWithUsageAndFormat(ctx context.Context, model string, prompt string, maxTokens int, responseFormat *ports.ResponseFormat) (*ports.LLMResponse, error) {
+	content, _ := c.ChatCompletion(ctx, model, prompt, maxTokens)
+	return &ports.LLMResponse{Content: content, Usage: &ports.UsageData{}}, nil
+}
+
+// TestPreviewResearchPromptMatchesSentPrompt verifies a dry run previews exactly the text a
+// generation run sends, validated hypothesis summary and discovery brief anchors included
+func TestPreviewResearchPromptMatchesSentPrompt(t *testing.T) {
+	promptsDir := t.TempDir()
+	template := "FIELDS:\n{FIELD_METADATA_JSON}\n\nEVIDENCE:\n{STATISTICAL_EVIDENCE_JSON}\n\nVALIDATED:\n{VALIDATED_HYPOTHESIS_SUMMARY}"
+	if err := os.WriteFile(filepath.Join(promptsDir, "greenfield.txt"), []byte(template), 0o600); err != nil {
+		t.Fatalf("failed to write prompt template: %v", err)
+	}
+
+	ga := NewGreenfieldAdapter(&models.AIConfig{PromptsDir: promptsDir})
+	client := &recordingLLMClient{}
+	ga.StructuredClient.LLMClient = client
+
+	brief := discovery.DiscoveryBrief{VariableKey: "discount"}
+	brief.LLMContext.PromptFragments = []discovery.PromptFragment{
+		{Type: "behavioral", Content: "discount responds to revenue with a one-week lag", Priority: 8},
+	}
+	req := ports.GreenfieldResearchRequest{
+		RunID: "run-preview",
+		FieldMetadata: []greenfield.FieldMetadata{
+			{Name: "discount", DataType: "numeric"},
+			{Name: "revenue", DataType: "numeric"},
+		},
+		StatisticalArtifacts: []map[string]interface{}{
+			{"kind": "relationship", "payload": map[string]interface{}{
+				"variable_x": "discount", "variable_y": "revenue", "test_type": "pearson", "effect_size": 0.42, "p_value": 0.001,
+			}},
+		},
+		DiscoveryBriefs:            []discovery.DiscoveryBrief{brief},
+		ValidatedHypothesisSummary: map[string]interface{}{"validated": []string{"discount drives revenue"}},
+		DomainPack:                 models.GeneralDomainPack,
+	}
+
+	preview, err := ga.PreviewResearchPrompt(context.Background(), req)
+	if err != nil {
+		t.Fatalf("PreviewResearchPrompt: %v", err)
+	}
+	if _, err := ga.GenerateResearchDirectives(context.Background(), req); err != nil {
+		t.Fatalf("GenerateResearchDirectives: %v", err)
+	}
+
+	if len(client.prompts) != 1 {
+		t.Fatalf("expected one generation call, got %d", len(client.prompts))
+	}
+	if client.prompts[0] != preview.FullPrompt {
+		t.Errorf("preview differs from the prompt sent\npreview:\n%s\n\nsent:\n%s", preview.FullPrompt, client.prompts[0])
+	}
+	for _, want := range []string{"discount drives revenue", "DISCOVERY BRIEF ANCHORS:", "one-week lag"} {
+		if !strings.Contains(client.prompts[0], want) {
+			t.Errorf("expected the sent prompt to contain %q", want)
+		}
+	}
+	if preview.TotalChars != len(client.prompts[0]) {
+		t.Errorf("expected the preview to report %d chars, got %d", len(client.prompts[0]), preview.TotalChars)
+	}
+}
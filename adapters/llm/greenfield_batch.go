@@ -0,0 +1,228 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// Batch defaults: how many relationship artifacts share one generation call, and how many
+// generation or auditor calls may be in flight at once. LLM_BATCH_SIZE and
+// LLM_MAX_CONCURRENCY override them; provider pacing is RESILIENCE_<UPSTREAM>_REQUESTS_PER_MINUTE.
+const (
+	defaultRelationshipsPerCall = 25
+	defaultMaxConcurrency       = 3
+)
+
+// BatchOptions controls how generation fans out over many relationships
+type BatchOptions struct {
+	RelationshipsPerCall int // relationship artifacts per prompt; the rest of the evidence goes with every batch
+	MaxConcurrency       int // LLM calls in flight at once
+}
+
+// BatchOptionsFromEnv returns the defaults overridden by LLM_BATCH_SIZE and LLM_MAX_CONCURRENCY
+func BatchOptionsFromEnv() BatchOptions {
+	opts := BatchOptions{RelationshipsPerCall: defaultRelationshipsPerCall, MaxConcurrency: defaultMaxConcurrency}
+	if n, err := strconv.Atoi(os.Getenv("LLM_BATCH_SIZE")); err == nil && n > 0 {
+		opts.RelationshipsPerCall = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("LLM_MAX_CONCURRENCY")); err == nil && n > 0 {
+		opts.MaxConcurrency = n
+	}
+	return opts
+}
+
+// SetBatchOptions replaces the adapter's batching settings
+func (ga *GreenfieldAdapter) SetBatchOptions(opts BatchOptions) {
+	ga.batch = opts
+}
+
+// relationshipBatch is one generation call's share of the statistical artifacts
+type relationshipBatch struct {
+	index     int
+	count     int
+	artifacts []map[string]interface{}
+	size      int // relationship artifacts in this batch
+}
+
+// splitRelationshipBatches chunks relationship artifacts into groups of perCall. Sweep
+// manifests, FDR families and other non-relationship artifacts are context for every
+// relationship, so each batch carries all of them.
+func splitRelationshipBatches(artifacts []map[string]interface{}, perCall int) []relationshipBatch {
+	var shared, relationships []map[string]interface{}
+	for _, artifact := range artifacts {
+		if kind, _ := artifact["kind"].(string); kind == "relationship" {
+			relationships = append(relationships, artifact)
+		} else {
+			shared = append(shared, artifact)
+		}
+	}
+	if perCall <= 0 || len(relationships) <= perCall {
+		return []relationshipBatch{{index: 0, count: 1, artifacts: artifacts, size: len(relationships)}}
+	}
+
+	count := (len(relationships) + perCall - 1) / perCall
+	batches := make([]relationshipBatch, 0, count)
+	for start := 0; start < len(relationships); start += perCall {
+		end := min(start+perCall, len(relationships))
+		batch := append(append([]map[string]interface{}(nil), shared...), relationships[start:end]...)
+		batches = append(batches, relationshipBatch{index: len(batches), count: count, artifacts: batch, size: end - start})
+	}
+	return batches
+}
+
+// batchInstruction tells the model which slice of the relationships it is looking at and
+// that the answer is a list, one directive per relationship worth testing
+func batchInstruction(batch relationshipBatch) string {
+	if batch.count <= 1 {
+		return ""
+	}
+	return fmt.Sprintf("BATCH %d OF %d: the statistical evidence above covers %d relationships. Return research_directives as a list with one directive for each relationship worth testing; skip the rest rather than repeating a cause/effect pair.",
+		batch.index+1, batch.count, batch.size)
+}
+
+// batchResult is one generation call's output
+type batchResult struct {
+	output *models.GreenfieldResearchOutput
+	budget *models.PromptBudgetReport
+	prompt string
+	err    error
+}
+
+// generateBatches runs the generation calls for every batch, at most MaxConcurrency at a
+// time. With self-consistency each batch is sampled several times and reduced to its
+// consensus. Provider pacing and Retry-After pauses are applied underneath by the resilience
+// layer, so a throttled call holds the others back instead of burning their retries.
+func (ga *GreenfieldAdapter) generateBatches(ctx context.Context, req ports.GreenfieldResearchRequest, batches []relationshipBatch) []batchResult {
+	samples := max(req.SelfConsistencySamples, 1)
+	prompts := make([]string, len(batches))
+	budgets := make([]*models.PromptBudgetReport, len(batches))
+	for i, batch := range batches {
+		batchReq := req
+		batchReq.StatisticalArtifacts = batch.artifacts
+		prompts[i], _, budgets[i] = ga.assembleResearchPrompt(ctx, batchReq, batchInstruction(batch))
+		if budgets[i].Truncated() {
+			log.Printf("[GreenfieldAdapter] ✂️ Prompt budget (batch %d/%d): dropped %d of %d evidence fragments to fit %d tokens (%s)",
+				batch.index+1, batch.count, len(budgets[i].Dropped), len(budgets[i].Dropped)+len(budgets[i].Included), budgets[i].LimitTokens, budgets[i].Model)
+		}
+	}
+
+	outputs := make([][]*models.GreenfieldResearchOutput, len(batches))
+	errs := make([][]error, len(batches))
+	for i := range batches {
+		outputs[i] = make([]*models.GreenfieldResearchOutput, samples)
+		errs[i] = make([]error, samples)
+	}
+	runConcurrently(len(batches)*samples, ga.batch.MaxConcurrency, func(job int) {
+		i, sample := job/samples, job%samples
+		if samples == 1 {
+			outputs[i][0], errs[i][0] = ga.StructuredClient.GetJsonResponseWithContext(ctx, "openai", prompts[i], greenfieldSystemMessage)
+			return
+		}
+		outputs[i][sample], errs[i][sample] = ga.Sampler.GetJsonResponseWithContext(ctx, "openai", samplePrompt(prompts[i], sample, samples), greenfieldSystemMessage)
+	})
+
+	results := make([]batchResult, len(batches))
+	for i := range batches {
+		results[i] = batchResult{budget: budgets[i], prompt: prompts[i]}
+		if samples == 1 {
+			results[i].output, results[i].err = outputs[i][0], errs[i][0]
+			continue
+		}
+		results[i].output, results[i].err = consensusDirectives(outputs[i], errs[i])
+	}
+	return results
+}
+
+// mergeBatchResults combines batch outputs into one response. Each directive keeps the
+// budget of the prompt that produced it; a cause/effect pair proposed by two batches is kept
+// once; IDs are prefixed by batch so they stay unique. It fails only when every batch did.
+func mergeBatchResults(results []batchResult) (*models.GreenfieldResearchOutput, *models.PromptBudgetReport, []string, error) {
+	if len(results) == 1 {
+		r := results[0]
+		return r.output, r.budget, []string{r.prompt}, r.err
+	}
+
+	merged := &models.GreenfieldResearchOutput{}
+	var budget *models.PromptBudgetReport
+	var prompts []string
+	var firstErr error
+	failed := 0
+	seen := make(map[string]bool)
+	for i, r := range results {
+		if r.err != nil {
+			log.Printf("[GreenfieldAdapter] ⚠️ Batch %d/%d failed: %v", i+1, len(results), r.err)
+			failed++
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			continue
+		}
+		prompts = append(prompts, r.prompt)
+		if merged.IndustryContext == "" {
+			merged.IndustryContext = r.output.IndustryContext
+		}
+		budget = mergePromptBudgets(budget, r.budget)
+		for _, directive := range r.output.ResearchDirectives {
+			pair := directive.CauseKey + "->" + directive.EffectKey
+			if seen[pair] {
+				continue
+			}
+			seen[pair] = true
+			directive.ID = fmt.Sprintf("B%d-%s", i+1, directive.ID)
+			directive.PromptBudget = r.budget
+			merged.ResearchDirectives = append(merged.ResearchDirectives, directive)
+		}
+	}
+	if failed == len(results) {
+		return nil, nil, nil, firstErr
+	}
+	if failed > 0 {
+		log.Printf("[GreenfieldAdapter] ⚠️ %d of %d batches failed; keeping %d directives from the rest", failed, len(results), len(merged.ResearchDirectives))
+	}
+	return merged, budget, prompts, nil
+}
+
+// mergePromptBudgets sums a run's batch budgets: fragment lists are concatenated and token
+// counts report the fullest prompt
+func mergePromptBudgets(total, batch *models.PromptBudgetReport) *models.PromptBudgetReport {
+	if batch == nil {
+		return total
+	}
+	if total == nil {
+		copied := *batch
+		copied.Included = append([]string{}, batch.Included...)
+		copied.Dropped = append([]string{}, batch.Dropped...)
+		return &copied
+	}
+	total.FixedTokens = max(total.FixedTokens, batch.FixedTokens)
+	total.UsedTokens = max(total.UsedTokens, batch.UsedTokens)
+	total.Included = append(total.Included, batch.Included...)
+	total.Dropped = append(total.Dropped, batch.Dropped...)
+	return total
+}
+
+// runConcurrently calls fn for every index in [0, n) with at most limit calls in flight
+func runConcurrently(n, limit int, fn func(i int)) {
+	if limit <= 0 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"errors"
+	"testing"
+
+	"gohypo/models"
+)
+
+// TestSplitRelationshipBatchesSharesContext verifies relationships are chunked while other
+// artifacts go with every batch
+func TestSplitRelationshipBatchesSharesContext(t *testing.T) {
+	artifacts := []map[string]interface{}{{"kind": "sweep_manifest"}}
+	for i := 0; i < 5; i++ {
+		artifacts = append(artifacts, map[string]interface{}{"kind": "relationship"})
+	}
+
+	batches := splitRelationshipBatches(artifacts, 2)
+	if len(batches) != 3 {
+		t.Fatalf("expected 3 batches, got %d", len(batches))
+	}
+	if batches[2].size != 1 || len(batches[2].artifacts) != 2 || batches[2].artifacts[0]["kind"] != "sweep_manifest" {
+		t.Fatalf("expected the last batch to hold the manifest and one relationship, got %+v", batches[2])
+	}
+	if single := splitRelationshipBatches(artifacts, 10); len(single) != 1 || batchInstruction(single[0]) != "" {
+		t.Fatal("a sweep that fits one call must not be batched")
+	}
+}
+
+// TestMergeBatchResultsKeepsSurvivingBatches verifies duplicates are dropped, IDs stay unique
+// and a failed batch does not fail the run
+func TestMergeBatchResultsKeepsSurvivingBatches(t *testing.T) {
+	output := func(pairs ...string) *models.GreenfieldResearchOutput {
+		out := &models.GreenfieldResearchOutput{}
+		for i := 0; i < len(pairs); i += 2 {
+			out.ResearchDirectives = append(out.ResearchDirectives, models.ResearchDirectiveResponse{ID: "HYP-001", CauseKey: pairs[i], EffectKey: pairs[i+1]})
+		}
+		return out
+	}
+	results := []batchResult{
+		{output: output("a", "y"), budget: &models.PromptBudgetReport{Included: []string{"association:1"}}},
+		{err: errors.New("rate limited")},
+		{output: output("a", "y", "b", "y"), budget: &models.PromptBudgetReport{Dropped: []string{"association:9"}}},
+	}
+
+	merged, budget, _, err := mergeBatchResults(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged.ResearchDirectives) != 2 || merged.ResearchDirectives[0].ID == merged.ResearchDirectives[1].ID {
+		t.Fatalf("expected two uniquely named directives, got %+v", merged.ResearchDirectives)
+	}
+	if merged.ResearchDirectives[1].PromptBudget != results[2].budget || !budget.Truncated() {
+		t.Fatal("expected each directive to keep its batch budget and the run budget to show the drop")
+	}
+
+	if _, _, _, err := mergeBatchResults([]batchResult{{err: errors.New("x")}, {err: errors.New("y")}}); err == nil {
+		t.Fatal("expected an error when every batch failed")
+	}
+}
@@ -0,0 +1,146 @@
+package llm
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"gohypo/models"
+)
+
+// selfConsistencyTemperature is the sampling temperature for self-consistency samples: warm
+// enough that samples differ, so agreement between them means something
+const selfConsistencyTemperature = 0.8
+
+// samplePrompt labels one sample of a prompt. The label makes each sample its own cache
+// entry, so samples are independent draws yet a replayed run gets the same ones back.
+func samplePrompt(prompt string, sample, samples int) string {
+	return fmt.Sprintf("%s\n\n(Independent sample %d of %d.)", prompt, sample+1, samples)
+}
+
+// directiveCluster is every sampled directive proposing one cause/effect pair
+type directiveCluster struct {
+	key        string
+	first      int // order of first appearance, to break agreement ties
+	members    []models.ResearchDirectiveResponse
+	agreeingIn map[int]bool // samples that proposed the pair
+}
+
+// consensusDirectives clusters the directives of several samples by cause/effect pair and
+// keeps the pairs a majority of the usable samples proposed, each represented by its most
+// typical wording and scored by the share of samples that agree. When no pair reaches a
+// majority the best-supported one is kept so the run still yields a candidate, with its low
+// score on record. It fails only when no sample returned an answer.
+func consensusDirectives(outputs []*models.GreenfieldResearchOutput, errs []error) (*models.GreenfieldResearchOutput, error) {
+	var usable []*models.GreenfieldResearchOutput
+	var firstErr error
+	for i, output := range outputs {
+		if errs[i] != nil || output == nil {
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
+		}
+		usable = append(usable, output)
+	}
+	if len(usable) == 0 {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("no self-consistency sample returned an answer")
+		}
+		return nil, firstErr
+	}
+
+	clusters := map[string]*directiveCluster{}
+	var order []*directiveCluster
+	for sample, output := range usable {
+		for _, directive := range output.ResearchDirectives {
+			key := strings.ToLower(strings.TrimSpace(directive.CauseKey)) + "->" + strings.ToLower(strings.TrimSpace(directive.EffectKey))
+			cluster, ok := clusters[key]
+			if !ok {
+				cluster = &directiveCluster{key: key, first: len(order), agreeingIn: map[int]bool{}}
+				clusters[key] = cluster
+				order = append(order, cluster)
+			}
+			cluster.members = append(cluster.members, directive)
+			cluster.agreeingIn[sample] = true
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if len(order[i].agreeingIn) != len(order[j].agreeingIn) {
+			return len(order[i].agreeingIn) > len(order[j].agreeingIn)
+		}
+		return order[i].first < order[j].first
+	})
+
+	consensus := &models.GreenfieldResearchOutput{}
+	for _, output := range usable {
+		if output.IndustryContext != "" {
+			consensus.IndustryContext = output.IndustryContext
+			break
+		}
+	}
+	for i, cluster := range order {
+		agreeing := len(cluster.agreeingIn)
+		if agreeing*2 <= len(usable) && i > 0 {
+			break
+		}
+		directive := representativeDirective(cluster.members)
+		directive.ID = fmt.Sprintf("HYP-%03d", len(consensus.ResearchDirectives)+1)
+		directive.SelfConsistency = &models.SelfConsistency{
+			Score:    float64(agreeing) / float64(len(usable)),
+			Agreeing: agreeing,
+			Samples:  len(usable),
+			Variants: len(order),
+		}
+		consensus.ResearchDirectives = append(consensus.ResearchDirectives, directive)
+	}
+	log.Printf("[GreenfieldAdapter] 🗳️ Self-consistency: %d of %d proposed pairs kept across %d samples",
+		len(consensus.ResearchDirectives), len(order), len(usable))
+	return consensus, nil
+}
+
+// representativeDirective picks the member whose hypothesis text shares the most words with
+// the other members, the medoid under Jaccard similarity
+func representativeDirective(members []models.ResearchDirectiveResponse) models.ResearchDirectiveResponse {
+	words := make([]map[string]bool, len(members))
+	for i, member := range members {
+		words[i] = wordSet(member.BusinessHypothesis + " " + member.ScienceHypothesis)
+	}
+	best, bestScore := 0, -1.0
+	for i := range members {
+		score := 0.0
+		for j := range members {
+			if i != j {
+				score += jaccard(words[i], words[j])
+			}
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return members[best]
+}
+
+func wordSet(text string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_')
+	}) {
+		set[word] = true
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
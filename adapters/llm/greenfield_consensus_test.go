@@ -0,0 +1,48 @@
+package llm
+
+import (
+	"errors"
+	"testing"
+
+	"gohypo/models"
+)
+
+// TestConsensusDirectivesKeepsMajorityPairs verifies pairs most samples agree on survive with
+// their self-consistency score, and one-off pairs are dropped
+func TestConsensusDirectivesKeepsMajorityPairs(t *testing.T) {
+	sample := func(pairs ...string) *models.GreenfieldResearchOutput {
+		out := &models.GreenfieldResearchOutput{}
+		for i := 0; i < len(pairs); i += 2 {
+			out.ResearchDirectives = append(out.ResearchDirectives, models.ResearchDirectiveResponse{
+				CauseKey:           pairs[i],
+				EffectKey:          pairs[i+1],
+				BusinessHypothesis: "higher " + pairs[i] + " lowers " + pairs[i+1],
+			})
+		}
+		return out
+	}
+	outputs := []*models.GreenfieldResearchOutput{
+		sample("price", "churn", "tenure", "churn"),
+		sample("Price", "churn"),
+		sample("price", "churn", "tenure", "churn"),
+		sample("support_calls", "churn"),
+		nil,
+	}
+	errs := []error{nil, nil, nil, nil, errors.New("timeout")}
+
+	consensus, err := consensusDirectives(outputs, errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(consensus.ResearchDirectives) != 1 {
+		t.Fatalf("expected only the majority pair, got %+v", consensus.ResearchDirectives)
+	}
+	sc := consensus.ResearchDirectives[0].SelfConsistency
+	if sc == nil || sc.Agreeing != 3 || sc.Samples != 4 || sc.Score != 0.75 || sc.Variants != 3 {
+		t.Fatalf("unexpected self-consistency %+v", sc)
+	}
+
+	if _, err := consensusDirectives([]*models.GreenfieldResearchOutput{nil}, []error{errors.New("down")}); err == nil {
+		t.Fatal("expected an error when no sample answered")
+	}
+}
@@ -0,0 +1,86 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+const (
+	// maxFewShotExemplars caps the few-shot examples per prompt; pack-specific ones go first
+	maxFewShotExemplars = 3
+
+	// fewShotPriority ranks examples level with ordinary evidence in the prompt budget
+	fewShotPriority = 5
+
+	fewShotHeader = "FEW-SHOT EXAMPLES (approved hypotheses from past runs; match their structure and rigor, not their content):"
+)
+
+// fewShotExample is one approved exemplar rendered for the prompt
+type fewShotExample struct {
+	id       string
+	pack     string
+	body     string
+	strength float64
+}
+
+// SetExemplarStore enables few-shot examples from the curated store
+func (ga *GreenfieldAdapter) SetExemplarStore(store ports.ExemplarStore) {
+	ga.exemplars = store
+}
+
+// fewShotExamples loads the approved exemplars for the request's domain pack, then tops up
+// from the general pack. A store failure costs the examples, never the generation run.
+func (ga *GreenfieldAdapter) fewShotExamples(ctx context.Context, domainPack string) []fewShotExample {
+	if ga.exemplars == nil {
+		return nil
+	}
+	packs := []string{models.NormalizeDomainPack(domainPack)}
+	if packs[0] != models.GeneralDomainPack {
+		packs = append(packs, models.GeneralDomainPack)
+	}
+
+	var examples []fewShotExample
+	for i, pack := range packs {
+		exemplars, err := ga.exemplars.List(ctx, pack, models.ExemplarApproved)
+		if err != nil {
+			log.Printf("[GreenfieldAdapter] ⚠️ Few-shot exemplars for pack %s unavailable: %v", pack, err)
+			continue
+		}
+		for _, exemplar := range exemplars {
+			if len(examples) >= maxFewShotExemplars {
+				return examples
+			}
+			body, err := json.MarshalIndent(map[string]interface{}{
+				"evidence":           exemplar.Evidence,
+				"research_directive": exemplar.Directive,
+			}, "", "  ")
+			if err != nil {
+				continue
+			}
+			strength := 1.0
+			if i > 0 {
+				strength = 0.5 // general examples yield to the domain's own
+			}
+			examples = append(examples, fewShotExample{id: exemplar.ID, pack: pack, body: string(body), strength: strength})
+		}
+	}
+	return examples
+}
+
+// renderFewShotSection formats the included examples, numbered in order
+func renderFewShotSection(examples []fewShotExample) string {
+	if len(examples) == 0 {
+		return ""
+	}
+	parts := make([]string, len(examples))
+	for i, example := range examples {
+		parts[i] = fmt.Sprintf("Example %d (%s):\n%s", i+1, example.pack, example.body)
+	}
+	return fewShotHeader + "\n\n" + strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,325 @@
+package notebook
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/models"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Files of a notebook export; the matrix and validation files are only written when the
+// run has them
+const (
+	RelationshipsFile = "relationships.parquet"
+	MatrixFile        = "matrix.parquet"
+	ValidationFile    = "validation.parquet"
+	LoaderFile        = "load.py"
+)
+
+// EntityColumn names the matrix column holding entity IDs, which the loader makes the index
+const EntityColumn = "entity_id"
+
+// Export describes what WriteExport wrote
+type Export struct {
+	RunID         string   `json:"run_id"`
+	Dir           string   `json:"dir"`
+	Files         []string `json:"files"`
+	Relationships int      `json:"relationships"`
+	Entities      int      `json:"entities"`
+	Variables     int      `json:"variables"`
+	Validations   int      `json:"validations"`
+}
+
+// relationshipPayload is the part of a relationship payload the export keeps
+type relationshipPayload struct {
+	CauseKey              string   `json:"cause_key"`
+	EffectKey             string   `json:"effect_key"`
+	VariableX             string   `json:"variable_x"`
+	VariableY             string   `json:"variable_y"`
+	TestType              string   `json:"test_type"`
+	Correlation           *float64 `json:"correlation"`
+	EffectSize            *float64 `json:"effect_size"`
+	PValue                float64  `json:"p_value"`
+	QValue                float64  `json:"q_value"`
+	SampleSize            int64    `json:"sample_size"`
+	PracticalSignificance string   `json:"practical_significance"`
+	ConfidenceLevel       string   `json:"confidence_level"`
+	Warnings              []string `json:"warnings"`
+}
+
+// relationshipRow is a row of the relationships file
+type relationshipRow struct {
+	ArtifactID            string   `parquet:"artifact_id"`
+	Cause                 string   `parquet:"cause"`
+	Effect                string   `parquet:"effect"`
+	TestType              string   `parquet:"test_type"`
+	EffectSize            *float64 `parquet:"effect_size,optional"`
+	Correlation           *float64 `parquet:"correlation,optional"`
+	PValue                float64  `parquet:"p_value"`
+	QValue                float64  `parquet:"q_value"`
+	SampleSize            int64    `parquet:"sample_size"`
+	Significant           bool     `parquet:"significant"`
+	PracticalSignificance string   `parquet:"practical_significance"`
+	ConfidenceLevel       string   `parquet:"confidence_level"`
+	Warnings              []string `parquet:"warnings,list"`
+}
+
+// hypothesisPayload is a validated hypothesis as `gohypo-cli run` records it
+type hypothesisPayload struct {
+	ID        string                 `json:"id"`
+	CauseKey  string                 `json:"cause_key"`
+	EffectKey string                 `json:"effect_key"`
+	Claim     string                 `json:"claim"`
+	Passed    bool                   `json:"passed"`
+	Reason    string                 `json:"reason"`
+	Referees  []models.RefereeResult `json:"referees"`
+}
+
+// validationRow is one referee's result for a hypothesis; a hypothesis no referee ran on
+// has a single row without referee fields
+type validationRow struct {
+	HypothesisID     string   `parquet:"hypothesis_id"`
+	Cause            string   `parquet:"cause"`
+	Effect           string   `parquet:"effect"`
+	Claim            string   `parquet:"claim"`
+	HypothesisPassed bool     `parquet:"hypothesis_passed"`
+	Reason           string   `parquet:"reason"`
+	Referee          *string  `parquet:"referee,optional"`
+	RefereePassed    *bool    `parquet:"referee_passed,optional"`
+	Statistic        *float64 `parquet:"statistic,optional"`
+	PValue           *float64 `parquet:"p_value,optional"`
+	EValue           *float64 `parquet:"e_value,optional"`
+	FailureReason    string   `parquet:"failure_reason"`
+}
+
+// WriteExport writes a run's relationships, resolved matrix and validation results as
+// Parquet files under dir, with a Python snippet that loads them into pandas. The matrix
+// is left out when bundle is nil.
+func WriteExport(dir, runID string, artifacts []core.Artifact, bundle *dataset.MatrixBundle) (*Export, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	export := &Export{RunID: runID, Dir: dir}
+
+	var relationships []relationshipRow
+	var validations []validationRow
+	for _, artifact := range artifacts {
+		switch artifact.Kind {
+		case "association", core.ArtifactRelationship:
+			var payload relationshipPayload
+			if err := decodeArtifact(artifact, &payload); err != nil {
+				return nil, err
+			}
+			relationships = append(relationships, newRelationshipRow(string(artifact.ID), payload))
+		case core.ArtifactHypothesis:
+			var payload hypothesisPayload
+			if err := decodeArtifact(artifact, &payload); err != nil {
+				return nil, err
+			}
+			validations = append(validations, newValidationRows(payload)...)
+			export.Validations++
+		}
+	}
+	export.Relationships = len(relationships)
+
+	if err := writeRows(filepath.Join(dir, RelationshipsFile), relationships); err != nil {
+		return nil, err
+	}
+	export.Files = append(export.Files, RelationshipsFile)
+
+	var variables []string
+	entityColumn := EntityColumn
+	if bundle != nil {
+		variables = make([]string, len(bundle.Matrix.VariableKeys))
+		for i, key := range bundle.Matrix.VariableKeys {
+			variables[i] = string(key)
+			if variables[i] == entityColumn {
+				entityColumn = "_" + EntityColumn
+			}
+		}
+		if err := writeMatrix(filepath.Join(dir, MatrixFile), entityColumn, variables, bundle.Matrix); err != nil {
+			return nil, err
+		}
+		export.Files = append(export.Files, MatrixFile)
+		export.Entities, export.Variables = len(bundle.Matrix.EntityIDs), len(variables)
+	}
+
+	if len(validations) > 0 {
+		if err := writeRows(filepath.Join(dir, ValidationFile), validations); err != nil {
+			return nil, err
+		}
+		export.Files = append(export.Files, ValidationFile)
+	}
+
+	loader := loaderSnippet(runID, export.Files, entityColumn, variables)
+	if err := os.WriteFile(filepath.Join(dir, LoaderFile), []byte(loader), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write loader: %w", err)
+	}
+	export.Files = append(export.Files, LoaderFile)
+	return export, nil
+}
+
+func newRelationshipRow(id string, payload relationshipPayload) relationshipRow {
+	cause, effect := payload.CauseKey, payload.EffectKey
+	if cause == "" && effect == "" {
+		cause, effect = payload.VariableX, payload.VariableY
+	}
+	return relationshipRow{
+		ArtifactID:            id,
+		Cause:                 cause,
+		Effect:                effect,
+		TestType:              payload.TestType,
+		EffectSize:            payload.EffectSize,
+		Correlation:           payload.Correlation,
+		PValue:                payload.PValue,
+		QValue:                payload.QValue,
+		SampleSize:            payload.SampleSize,
+		Significant:           payload.QValue < models.SummarySignificanceLevel,
+		PracticalSignificance: payload.PracticalSignificance,
+		ConfidenceLevel:       payload.ConfidenceLevel,
+		Warnings:              payload.Warnings,
+	}
+}
+
+func newValidationRows(payload hypothesisPayload) []validationRow {
+	base := validationRow{
+		HypothesisID:     payload.ID,
+		Cause:            payload.CauseKey,
+		Effect:           payload.EffectKey,
+		Claim:            payload.Claim,
+		HypothesisPassed: payload.Passed,
+		Reason:           payload.Reason,
+	}
+	if len(payload.Referees) == 0 {
+		return []validationRow{base}
+	}
+	rows := make([]validationRow, 0, len(payload.Referees))
+	for _, result := range payload.Referees {
+		row := base
+		row.Referee, row.RefereePassed = &result.GateName, &result.Passed
+		row.Statistic, row.PValue, row.EValue = &result.Statistic, &result.PValue, &result.EValue
+		row.FailureReason = result.FailureReason
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// writeRows writes rows to a Snappy-compressed Parquet file with the row type's schema
+func writeRows[T any](path string, rows []T) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Base(path), err)
+	}
+	defer file.Close()
+	w := parquet.NewGenericWriter[T](file, parquet.Compression(&parquet.Snappy))
+	if _, err := w.Write(rows); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return file.Close()
+}
+
+// writeMatrix writes the matrix with one row per entity and a nullable column per variable;
+// missing values are written as nulls rather than NaN
+func writeMatrix(path, entityColumn string, variables []string, matrix dataset.Matrix) error {
+	group := parquet.Group{entityColumn: parquet.String()}
+	for _, variable := range variables {
+		group[variable] = parquet.Optional(parquet.Leaf(parquet.DoubleType))
+	}
+	schema := parquet.NewSchema("matrix", group)
+	// A group orders its columns by name
+	index := make(map[string]int, len(group))
+	for i, column := range schema.Columns() {
+		index[column[0]] = i
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Base(path), err)
+	}
+	defer file.Close()
+	w := parquet.NewWriter(file, schema, parquet.Compression(&parquet.Snappy))
+	rows := make([]parquet.Row, 0, len(matrix.EntityIDs))
+	for r, entity := range matrix.EntityIDs {
+		row := make(parquet.Row, len(group))
+		row[index[entityColumn]] = parquet.ByteArrayValue([]byte(entity)).Level(0, 0, index[entityColumn])
+		for c, variable := range variables {
+			i := index[variable]
+			if r < len(matrix.Data) && c < len(matrix.Data[r]) && !math.IsNaN(matrix.Data[r][c]) {
+				row[i] = parquet.DoubleValue(matrix.Data[r][c]).Level(0, 1, i)
+			} else {
+				row[i] = parquet.NullValue().Level(0, 0, i)
+			}
+		}
+		rows = append(rows, row)
+	}
+	if _, err := w.WriteRows(rows); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return file.Close()
+}
+
+// loaderSnippet is a Python module that loads the export into pandas DataFrames, with the
+// matrix indexed by entity and its columns in the run's order
+func loaderSnippet(runID string, files []string, entityColumn string, variables []string) string {
+	written := make(map[string]bool, len(files))
+	for _, file := range files {
+		written[file] = true
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\"\"\"Load GoHypo run %s into pandas (needs pandas with pyarrow).\n\n", runID)
+	b.WriteString("    from load import *\n")
+	b.WriteString("    relationships.sort_values(\"q_value\").head()\n\"\"\"\n")
+	b.WriteString("from pathlib import Path\n\nimport pandas as pd\n\n")
+	b.WriteString("HERE = Path(__file__).resolve().parent\n")
+	fmt.Fprintf(&b, "RUN_ID = %s\n\n", pythonString(runID))
+	b.WriteString("# One row per relationship the sweep tested; warnings is a list of codes\n")
+	fmt.Fprintf(&b, "relationships = pd.read_parquet(HERE / %s)\n", pythonString(RelationshipsFile))
+	if written[MatrixFile] {
+		quoted := make([]string, len(variables))
+		for i, variable := range variables {
+			quoted[i] = pythonString(variable)
+		}
+		fmt.Fprintf(&b, "\nVARIABLES = [%s]\n", strings.Join(quoted, ", "))
+		b.WriteString("# The resolved matrix: one row per entity, categorical variables as their codes\n")
+		fmt.Fprintf(&b, "matrix = pd.read_parquet(HERE / %s).set_index(%s)[VARIABLES]\n",
+			pythonString(MatrixFile), pythonString(entityColumn))
+	}
+	if written[ValidationFile] {
+		b.WriteString("\n# One row per referee result of each validated hypothesis\n")
+		fmt.Fprintf(&b, "validation = pd.read_parquet(HERE / %s)\n", pythonString(ValidationFile))
+	}
+	return b.String()
+}
+
+// pythonString quotes s as a Python string literal; a JSON string is one
+func pythonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// decodeArtifact decodes an artifact's payload into v, whether it is typed or was read
+// back from JSON
+func decodeArtifact(artifact core.Artifact, v interface{}) error {
+	data, err := json.Marshal(artifact.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s artifact %s: %w", artifact.Kind, artifact.ID, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s artifact %s: %w", artifact.Kind, artifact.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package notebook
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/models"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// TestWriteExport verifies the relationships, matrix and validation files read back as
+// written, with missing matrix values as nulls and a loader naming every file
+func TestWriteExport(t *testing.T) {
+	artifacts := []core.Artifact{
+		{ID: "corr_a_b", Kind: "association", Payload: map[string]interface{}{
+			"cause_key": "a", "effect_key": "b", "test_type": "pearson_correlation", "correlation": -0.7,
+			"p_value": 0.001, "q_value": 0.002, "sample_size": 25, "warnings": []string{"LOW_N"},
+		}},
+		{ID: "verdict_h1", Kind: core.ArtifactHypothesis, Payload: map[string]interface{}{
+			"id": "h1", "cause_key": "a", "effect_key": "b", "claim": "a lowers b", "passed": true,
+			"referees": []models.RefereeResult{{GateName: "permutation", Passed: true, PValue: 0.01}, {GateName: "bootstrap", PValue: 0.4}},
+		}},
+		{ID: "stats_sweep_manifest", Kind: core.ArtifactSweepManifest, Payload: map[string]interface{}{"fdr_method": "BH"}},
+	}
+	bundle := &dataset.MatrixBundle{Matrix: dataset.Matrix{
+		EntityIDs:    []core.ID{"e1", "e2"},
+		VariableKeys: []core.VariableKey{"b", "a"},
+		Data:         [][]float64{{1, 2}, {math.NaN(), 4}},
+	}}
+	dir := t.TempDir()
+	export, err := WriteExport(dir, "run-1", artifacts, bundle)
+	if err != nil {
+		t.Fatalf("WriteExport: %v", err)
+	}
+	if len(export.Files) != 4 || export.Relationships != 1 || export.Entities != 2 || export.Variables != 2 || export.Validations != 1 {
+		t.Fatalf("unexpected export %+v", export)
+	}
+
+	relationships, err := parquet.ReadFile[relationshipRow](filepath.Join(dir, RelationshipsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(relationships) != 1 || relationships[0].Cause != "a" || *relationships[0].Correlation != -0.7 ||
+		relationships[0].EffectSize != nil || !relationships[0].Significant || len(relationships[0].Warnings) != 1 {
+		t.Errorf("unexpected relationships %+v", relationships)
+	}
+
+	validation, err := parquet.ReadFile[validationRow](filepath.Join(dir, ValidationFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(validation) != 2 || *validation[0].Referee != "permutation" || !validation[0].HypothesisPassed || *validation[1].RefereePassed || *validation[1].PValue != 0.4 {
+		t.Errorf("unexpected validation rows %+v", validation)
+	}
+
+	type matrixRow struct {
+		EntityID string   `parquet:"entity_id"`
+		A        *float64 `parquet:"a,optional"`
+		B        *float64 `parquet:"b,optional"`
+	}
+	matrix, err := parquet.ReadFile[matrixRow](filepath.Join(dir, MatrixFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matrix) != 2 || matrix[0].EntityID != "e1" || *matrix[0].A != 2 || *matrix[0].B != 1 || matrix[1].B != nil || *matrix[1].A != 4 {
+		t.Errorf("unexpected matrix rows %+v", matrix)
+	}
+
+	loader, err := os.ReadFile(filepath.Join(dir, LoaderFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`VARIABLES = ["b", "a"]`, `set_index("entity_id")`, ValidationFile} {
+		if !strings.Contains(string(loader), want) {
+			t.Errorf("loader is missing %s:\n%s", want, loader)
+		}
+	}
+}
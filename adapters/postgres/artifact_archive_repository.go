@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ArtifactArchiveRepositoryImpl implements ArtifactArchiveStore for PostgreSQL
+type ArtifactArchiveRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewArtifactArchiveRepository creates a new PostgreSQL artifact archive index
+func NewArtifactArchiveRepository(db *sqlx.DB) ports.ArtifactArchiveStore {
+	return &ArtifactArchiveRepositoryImpl{db: db}
+}
+
+// ListArchivable returns sessions whose hot artifacts saw no activity since before. A
+// rehydrated session counts as active when it was rehydrated.
+func (r *ArtifactArchiveRepositoryImpl) ListArchivable(ctx context.Context, before time.Time, limit int) ([]models.ArchiveCandidate, error) {
+	var candidates []models.ArchiveCandidate
+	err := r.db.SelectContext(ctx, &candidates, `
+		SELECT h.session_id::text AS session_id,
+		       MAX(h.workspace_id::text) AS workspace_id,
+		       COUNT(*) AS artifacts,
+		       GREATEST(MAX(h.created_at), MAX(COALESCE(s.completed_at, s.updated_at, h.created_at)),
+		                MAX(COALESCE(a.rehydrated_at, h.created_at))) AS last_activity
+		FROM hypothesis_results h
+		JOIN research_sessions s ON s.id = h.session_id
+		LEFT JOIN artifact_archives a ON a.session_id = h.session_id
+		WHERE h.archive_id IS NULL
+		GROUP BY h.session_id
+		HAVING GREATEST(MAX(h.created_at), MAX(COALESCE(s.completed_at, s.updated_at, h.created_at)),
+		                MAX(COALESCE(a.rehydrated_at, h.created_at))) < $1
+		ORDER BY last_activity
+		LIMIT $2
+	`, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archivable sessions: %w", err)
+	}
+	return candidates, nil
+}
+
+// SessionArtifacts returns the payload columns of a session's hot hypothesis results
+func (r *ArtifactArchiveRepositoryImpl) SessionArtifacts(ctx context.Context, sessionID string) ([]models.ArchivedArtifact, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, referee_results, tri_gate_result, execution_metadata, data_topology
+		FROM hypothesis_results
+		WHERE session_id = $1 AND archive_id IS NULL
+		ORDER BY created_at, id
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifacts of session %s: %w", sessionID, err)
+	}
+	defer rows.Close()
+
+	var artifacts []models.ArchivedArtifact
+	for rows.Next() {
+		var artifact models.ArchivedArtifact
+		var referee, triGate, metadata, topology []byte
+		if err := rows.Scan(&artifact.ID, &referee, &triGate, &metadata, &topology); err != nil {
+			return nil, fmt.Errorf("failed to scan artifact of session %s: %w", sessionID, err)
+		}
+		artifact.RefereeResults = referee
+		artifact.TriGateResult = triGate
+		artifact.ExecutionMetadata = metadata
+		artifact.DataTopology = topology
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, rows.Err()
+}
+
+// MarkArchived upserts the session's archive row and clears the archived payloads in one
+// transaction, so a failure leaves the artifacts hot
+func (r *ArtifactArchiveRepositoryImpl) MarkArchived(ctx context.Context, archive *models.ArtifactArchive, artifactIDs []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin archive transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if archive.ArchivedAt.IsZero() {
+		archive.ArchivedAt = time.Now()
+	}
+	err = tx.QueryRowxContext(ctx, `
+		INSERT INTO artifact_archives (session_id, workspace_id, blob_key, artifact_count, raw_bytes, compressed_bytes, archived_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (session_id) DO UPDATE SET
+			workspace_id = EXCLUDED.workspace_id,
+			blob_key = EXCLUDED.blob_key,
+			artifact_count = EXCLUDED.artifact_count,
+			raw_bytes = EXCLUDED.raw_bytes,
+			compressed_bytes = EXCLUDED.compressed_bytes,
+			archived_at = EXCLUDED.archived_at,
+			rehydrated_at = NULL
+		RETURNING id::text
+	`, archive.SessionID, archive.WorkspaceID, archive.BlobKey, archive.ArtifactCount, archive.RawBytes,
+		archive.CompressedBytes, archive.ArchivedAt).Scan(&archive.ID)
+	if err != nil {
+		return fmt.Errorf("failed to index archive of session %s: %w", archive.SessionID, err)
+	}
+	archive.RehydratedAt = nil
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE hypothesis_results
+		SET referee_results = NULL, tri_gate_result = NULL, execution_metadata = NULL, data_topology = NULL,
+		    archive_id = $1
+		WHERE session_id = $2 AND id = ANY($3)
+	`, archive.ID, archive.SessionID, pq.Array(artifactIDs))
+	if err != nil {
+		return fmt.Errorf("failed to clear archived artifacts of session %s: %w", archive.SessionID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit archive of session %s: %w", archive.SessionID, err)
+	}
+	return nil
+}
+
+// GetSessionArchive returns a session's archive row, or nil when there is none
+func (r *ArtifactArchiveRepositoryImpl) GetSessionArchive(ctx context.Context, sessionID string) (*models.ArtifactArchive, error) {
+	var archive models.ArtifactArchive
+	err := r.db.GetContext(ctx, &archive, `
+		SELECT id::text AS id, session_id::text AS session_id, workspace_id::text AS workspace_id, blob_key,
+		       artifact_count, raw_bytes, compressed_bytes, archived_at, rehydrated_at
+		FROM artifact_archives WHERE session_id = $1
+	`, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archive of session %s: %w", sessionID, err)
+	}
+	return &archive, nil
+}
+
+// Restore writes the payloads back to the archived rows and marks the archive rehydrated
+func (r *ArtifactArchiveRepositoryImpl) Restore(ctx context.Context, archive *models.ArtifactArchive, artifacts []models.ArchivedArtifact) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin rehydration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, artifact := range artifacts {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE hypothesis_results
+			SET referee_results = $1, tri_gate_result = $2, execution_metadata = $3, data_topology = $4, archive_id = NULL
+			WHERE id = $5 AND archive_id = $6
+		`, nullableJSON(artifact.RefereeResults), nullableJSON(artifact.TriGateResult),
+			nullableJSON(artifact.ExecutionMetadata), nullableJSON(artifact.DataTopology), artifact.ID, archive.ID)
+		if err != nil {
+			return fmt.Errorf("failed to restore artifact %s: %w", artifact.ID, err)
+		}
+	}
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, `UPDATE artifact_archives SET rehydrated_at = $1 WHERE id = $2`, now, archive.ID); err != nil {
+		return fmt.Errorf("failed to mark archive of session %s rehydrated: %w", archive.SessionID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rehydration of session %s: %w", archive.SessionID, err)
+	}
+	archive.RehydratedAt = &now
+	return nil
+}
+
+// WorkspaceUsage sums a workspace's hot payload sizes and its cold archives
+func (r *ArtifactArchiveRepositoryImpl) WorkspaceUsage(ctx context.Context, workspaceID string) (*models.WorkspaceStorageUsage, error) {
+	usage := &models.WorkspaceStorageUsage{WorkspaceID: workspaceID}
+	err := r.db.GetContext(ctx, usage, `
+		SELECT COUNT(*) AS hot_artifacts, COALESCE(SUM(pg_column_size(h.*)), 0) AS hot_bytes
+		FROM hypothesis_results h
+		WHERE h.workspace_id::text = $1 AND h.archive_id IS NULL
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure hot artifacts of workspace %s: %w", workspaceID, err)
+	}
+	err = r.db.GetContext(ctx, usage, `
+		SELECT COUNT(*) AS archived_sessions,
+		       COALESCE(SUM(artifact_count), 0) AS archived_artifacts,
+		       COALESCE(SUM(raw_bytes), 0) AS archived_raw_bytes,
+		       COALESCE(SUM(compressed_bytes), 0) AS archived_bytes
+		FROM artifact_archives
+		WHERE workspace_id::text = $1 AND rehydrated_at IS NULL
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure archived artifacts of workspace %s: %w", workspaceID, err)
+	}
+	return usage, nil
+}
+
+// nullableJSON stores an absent payload as NULL rather than an empty JSONB value
+func nullableJSON(raw []byte) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return []byte(raw)
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/ports"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CatalogRepositoryImpl implements CatalogReader for PostgreSQL
+type CatalogRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewCatalogRepository creates a new PostgreSQL catalog reader
+func NewCatalogRepository(db *sqlx.DB) ports.CatalogReader {
+	return &CatalogRepositoryImpl{db: db}
+}
+
+// GetCatalog reads a workspace's datasets, the relations between them and the hypotheses
+// naming their fields, and assembles them into the catalog
+func (r *CatalogRepositoryImpl) GetCatalog(ctx context.Context, workspaceID core.ID) (*dataset.Catalog, error) {
+	datasets, err := r.catalogDatasets(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	relations, err := r.catalogRelations(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	references, err := r.fieldReferences(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.BuildCatalog(workspaceID, datasets, relations, references), nil
+}
+
+// catalogDatasets reads the datasets of a workspace with their field metadata
+func (r *CatalogRepositoryImpl) catalogDatasets(ctx context.Context, workspaceID core.ID) ([]*dataset.Dataset, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, original_filename, COALESCE(display_name, ''), COALESCE(domain, ''), COALESCE(description, ''),
+			COALESCE(record_count, 0), status, metadata, updated_at
+		FROM datasets
+		WHERE workspace_id = $1
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query catalog datasets: %w", err)
+	}
+	defer rows.Close()
+
+	var datasets []*dataset.Dataset
+	for rows.Next() {
+		ds := &dataset.Dataset{WorkspaceID: workspaceID}
+		var metadataJSON []byte
+		if err := rows.Scan(&ds.ID, &ds.OriginalFilename, &ds.DisplayName, &ds.Domain, &ds.Description,
+			&ds.RecordCount, &ds.Status, &metadataJSON, &ds.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan catalog dataset: %w", err)
+		}
+		if len(metadataJSON) > 0 {
+			if err := json.Unmarshal(metadataJSON, &ds.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal metadata of dataset %s: %w", ds.ID, err)
+			}
+		}
+		datasets = append(datasets, ds)
+	}
+	return datasets, rows.Err()
+}
+
+// catalogRelations reads the relations of a workspace whose both ends are still in it
+func (r *CatalogRepositoryImpl) catalogRelations(ctx context.Context, workspaceID core.ID) ([]*dataset.DatasetRelation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT rel.id, rel.source_dataset_id, rel.target_dataset_id, rel.relation_type,
+			COALESCE(rel.confidence, 0), rel.metadata, rel.discovered_at
+		FROM workspace_dataset_relations rel
+		JOIN datasets src ON src.id = rel.source_dataset_id AND src.workspace_id = rel.workspace_id
+		JOIN datasets dst ON dst.id = rel.target_dataset_id AND dst.workspace_id = rel.workspace_id
+		WHERE rel.workspace_id = $1
+		ORDER BY rel.discovered_at DESC
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query catalog relations: %w", err)
+	}
+	defer rows.Close()
+
+	var relations []*dataset.DatasetRelation
+	for rows.Next() {
+		rel := &dataset.DatasetRelation{WorkspaceID: workspaceID}
+		var metadataJSON []byte
+		if err := rows.Scan(&rel.ID, &rel.SourceDatasetID, &rel.TargetDatasetID, &rel.RelationType,
+			&rel.Confidence, &metadataJSON, &rel.DiscoveredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan catalog relation: %w", err)
+		}
+		if len(metadataJSON) > 0 {
+			if err := json.Unmarshal(metadataJSON, &rel.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal metadata of relation %s: %w", rel.ID, err)
+			}
+		}
+		relations = append(relations, rel)
+	}
+	return relations, rows.Err()
+}
+
+// fieldReferences reads the cause and effect variables of a workspace's hypotheses from
+// their execution metadata
+func (r *CatalogRepositoryImpl) fieldReferences(ctx context.Context, workspaceID core.ID) ([]dataset.FieldReference, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, business_hypothesis, passed,
+			COALESCE(execution_metadata->>'cause_key', ''), COALESCE(execution_metadata->>'effect_key', '')
+		FROM hypothesis_results
+		WHERE workspace_id::text = $1
+			AND (execution_metadata->>'cause_key' IS NOT NULL OR execution_metadata->>'effect_key' IS NOT NULL)
+		ORDER BY created_at DESC
+	`, string(workspaceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query field references: %w", err)
+	}
+	defer rows.Close()
+
+	var references []dataset.FieldReference
+	for rows.Next() {
+		var ref dataset.FieldReference
+		if err := rows.Scan(&ref.HypothesisID, &ref.Title, &ref.Passed, &ref.CauseKey, &ref.EffectKey); err != nil {
+			return nil, fmt.Errorf("failed to scan field reference: %w", err)
+		}
+		references = append(references, ref)
+	}
+	return references, rows.Err()
+}
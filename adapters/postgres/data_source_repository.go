@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// SQLDataSourceRepositoryImpl implements SQLDataSourceStore for PostgreSQL
+type SQLDataSourceRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewSQLDataSourceRepository creates a new PostgreSQL SQL data source store
+func NewSQLDataSourceRepository(db *sqlx.DB) ports.SQLDataSourceStore {
+	return &SQLDataSourceRepositoryImpl{db: db}
+}
+
+const sqlSourceColumns = `id, workspace_id, user_id, name, driver, dsn, query, table_name, max_rows,
+	refresh_minutes, last_refreshed_at, last_dataset_id, last_error, created_at`
+
+// Create stores a source, assigning its ID when empty
+func (r *SQLDataSourceRepositoryImpl) Create(ctx context.Context, source *models.SQLDataSource) error {
+	if source.ID == "" {
+		source.ID = uuid.New().String()
+	}
+	if source.CreatedAt.IsZero() {
+		source.CreatedAt = time.Now()
+	}
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO sql_data_sources (`+sqlSourceColumns+`)
+		VALUES (:id, :workspace_id, :user_id, :name, :driver, :dsn, :query, :table_name, :max_rows,
+			:refresh_minutes, :last_refreshed_at, :last_dataset_id, :last_error, :created_at)
+	`, source)
+	if err != nil {
+		return fmt.Errorf("failed to save SQL data source %q: %w", source.Name, err)
+	}
+	return nil
+}
+
+// Get returns one of a workspace's sources
+func (r *SQLDataSourceRepositoryImpl) Get(ctx context.Context, workspaceID, id string) (*models.SQLDataSource, error) {
+	var source models.SQLDataSource
+	err := r.db.GetContext(ctx, &source, `SELECT `+sqlSourceColumns+` FROM sql_data_sources WHERE id = $1 AND workspace_id = $2`, id, workspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("SQL data source")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SQL data source %s: %w", id, err)
+	}
+	return &source, nil
+}
+
+// ListByWorkspace returns a workspace's sources, oldest first
+func (r *SQLDataSourceRepositoryImpl) ListByWorkspace(ctx context.Context, workspaceID string) ([]*models.SQLDataSource, error) {
+	var sources []*models.SQLDataSource
+	err := r.db.SelectContext(ctx, &sources, `
+		SELECT `+sqlSourceColumns+` FROM sql_data_sources WHERE workspace_id = $1 ORDER BY created_at
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SQL data sources for workspace %s: %w", workspaceID, err)
+	}
+	return sources, nil
+}
+
+// ListScheduled returns every source refreshed on a schedule
+func (r *SQLDataSourceRepositoryImpl) ListScheduled(ctx context.Context) ([]*models.SQLDataSource, error) {
+	var sources []*models.SQLDataSource
+	err := r.db.SelectContext(ctx, &sources, `
+		SELECT `+sqlSourceColumns+` FROM sql_data_sources WHERE refresh_minutes > 0 ORDER BY last_refreshed_at NULLS FIRST
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled SQL data sources: %w", err)
+	}
+	return sources, nil
+}
+
+// RecordRefresh stores the outcome of a pull. A failed pull keeps the last dataset it registered.
+func (r *SQLDataSourceRepositoryImpl) RecordRefresh(ctx context.Context, id string, at time.Time, datasetID, errMsg string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE sql_data_sources
+		SET last_refreshed_at = $2, last_error = $4,
+			last_dataset_id = CASE WHEN $3 = '' THEN last_dataset_id ELSE $3 END
+		WHERE id = $1
+	`, id, at, datasetID, errMsg)
+	if err != nil {
+		return fmt.Errorf("failed to record refresh of SQL data source %s: %w", id, err)
+	}
+	return nil
+}
+
+// Delete removes one of a workspace's sources. Datasets it registered are kept.
+func (r *SQLDataSourceRepositoryImpl) Delete(ctx context.Context, workspaceID, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM sql_data_sources WHERE id = $1 AND workspace_id = $2`, id, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete SQL data source %s: %w", id, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return apperrors.NotFound("SQL data source")
+	}
+	return nil
+}
+
+// URLDataSourceRepositoryImpl implements URLDataSourceStore for PostgreSQL
+type URLDataSourceRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewURLDataSourceRepository creates a new PostgreSQL URL data source store
+func NewURLDataSourceRepository(db *sqlx.DB) ports.URLDataSourceStore {
+	return &URLDataSourceRepositoryImpl{db: db}
+}
+
+const urlSourceColumns = `id, workspace_id, user_id, name, url, kind, token, refresh_minutes,
+	last_refreshed_at, last_dataset_id, last_checksum, last_error, created_at`
+
+// Create stores a source, assigning its ID when empty
+func (r *URLDataSourceRepositoryImpl) Create(ctx context.Context, source *models.URLDataSource) error {
+	if source.ID == "" {
+		source.ID = uuid.New().String()
+	}
+	if source.CreatedAt.IsZero() {
+		source.CreatedAt = time.Now()
+	}
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO url_data_sources (`+urlSourceColumns+`)
+		VALUES (:id, :workspace_id, :user_id, :name, :url, :kind, :token, :refresh_minutes,
+			:last_refreshed_at, :last_dataset_id, :last_checksum, :last_error, :created_at)
+	`, source)
+	if err != nil {
+		return fmt.Errorf("failed to save URL data source %q: %w", source.Name, err)
+	}
+	return nil
+}
+
+// Get returns one of a workspace's sources
+func (r *URLDataSourceRepositoryImpl) Get(ctx context.Context, workspaceID, id string) (*models.URLDataSource, error) {
+	var source models.URLDataSource
+	err := r.db.GetContext(ctx, &source, `SELECT `+urlSourceColumns+` FROM url_data_sources WHERE id = $1 AND workspace_id = $2`, id, workspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("URL data source")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load URL data source %s: %w", id, err)
+	}
+	return &source, nil
+}
+
+// ListByWorkspace returns a workspace's sources, oldest first
+func (r *URLDataSourceRepositoryImpl) ListByWorkspace(ctx context.Context, workspaceID string) ([]*models.URLDataSource, error) {
+	var sources []*models.URLDataSource
+	err := r.db.SelectContext(ctx, &sources, `
+		SELECT `+urlSourceColumns+` FROM url_data_sources WHERE workspace_id = $1 ORDER BY created_at
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list URL data sources for workspace %s: %w", workspaceID, err)
+	}
+	return sources, nil
+}
+
+// ListScheduled returns every source re-fetched on a schedule
+func (r *URLDataSourceRepositoryImpl) ListScheduled(ctx context.Context) ([]*models.URLDataSource, error) {
+	var sources []*models.URLDataSource
+	err := r.db.SelectContext(ctx, &sources, `
+		SELECT `+urlSourceColumns+` FROM url_data_sources WHERE refresh_minutes > 0 ORDER BY last_refreshed_at NULLS FIRST
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled URL data sources: %w", err)
+	}
+	return sources, nil
+}
+
+// RecordRefresh stores the outcome of a fetch. A failed or unchanged fetch keeps the last
+// dataset and checksum.
+func (r *URLDataSourceRepositoryImpl) RecordRefresh(ctx context.Context, id string, at time.Time, datasetID, checksum, errMsg string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE url_data_sources
+		SET last_refreshed_at = $2, last_error = $5,
+			last_dataset_id = CASE WHEN $3 = '' THEN last_dataset_id ELSE $3 END,
+			last_checksum = CASE WHEN $4 = '' THEN last_checksum ELSE $4 END
+		WHERE id = $1
+	`, id, at, datasetID, checksum, errMsg)
+	if err != nil {
+		return fmt.Errorf("failed to record refresh of URL data source %s: %w", id, err)
+	}
+	return nil
+}
+
+// Delete removes one of a workspace's sources. Datasets it registered are kept.
+func (r *URLDataSourceRepositoryImpl) Delete(ctx context.Context, workspaceID, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM url_data_sources WHERE id = $1 AND workspace_id = $2`, id, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete URL data source %s: %w", id, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return apperrors.NotFound("URL data source")
+	}
+	return nil
+}
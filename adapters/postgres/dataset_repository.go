@@ -14,12 +14,13 @@ import (
 
 // datasetRepository implements the DatasetRepository interface
 type datasetRepository struct {
-	db *sqlx.DB
+	db    *sqlx.DB
+	table string // schema-qualified table name
 }
 
 // NewDatasetRepository creates a new dataset repository
 func NewDatasetRepository(db *sqlx.DB) ports.DatasetRepository {
-	return &datasetRepository{db: db}
+	return &datasetRepository{db: db, table: "datasets"}
 }
 
 // Create inserts a new dataset into the database
@@ -29,7 +30,7 @@ func (r *datasetRepository) Create(ctx context.Context, ds *dataset.Dataset) err
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	query := `INSERT INTO datasets (
+	query := `INSERT INTO ` + r.table + ` (
 		id, user_id, workspace_id, original_filename, file_path, file_size, mime_type,
 		display_name, domain, description, record_count, field_count, missing_rate,
 		source, status, error_message, metadata, created_at, updated_at
@@ -56,7 +57,7 @@ func (r *datasetRepository) GetByID(ctx context.Context, id core.ID) (*dataset.D
 		id, user_id, workspace_id, original_filename, COALESCE(file_path, '') as file_path, COALESCE(file_size, 0) as file_size, COALESCE(mime_type, '') as mime_type,
 		display_name, domain, description, COALESCE(record_count, 0) as record_count, COALESCE(field_count, 0) as field_count, COALESCE(missing_rate, 0.0) as missing_rate,
 		source, status, COALESCE(error_message, '') as error_message, metadata, created_at, updated_at
-	FROM datasets WHERE id = $1`
+	FROM ` + r.table + ` WHERE id = $1`
 
 	var ds dataset.Dataset
 	var metadataJSON []byte
@@ -91,7 +92,7 @@ func (r *datasetRepository) GetByUserID(ctx context.Context, userID core.ID, lim
 		id, user_id, workspace_id, original_filename, COALESCE(file_path, '') as file_path, COALESCE(file_size, 0) as file_size, COALESCE(mime_type, '') as mime_type,
 		display_name, domain, description, COALESCE(record_count, 0) as record_count, COALESCE(field_count, 0) as field_count, COALESCE(missing_rate, 0.0) as missing_rate,
 		source, status, COALESCE(error_message, '') as error_message, metadata, created_at, updated_at
-	FROM datasets
+	FROM ` + r.table + `
 	WHERE user_id = $1
 	ORDER BY created_at DESC
 	LIMIT $2 OFFSET $3`
@@ -137,7 +138,7 @@ func (r *datasetRepository) Update(ctx context.Context, ds *dataset.Dataset) err
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	query := `UPDATE datasets SET
+	query := `UPDATE ` + r.table + ` SET
 		original_filename = $2, file_path = $3, file_size = $4, mime_type = $5,
 		display_name = $6, domain = $7, description = $8, record_count = $9,
 		field_count = $10, missing_rate = $11, source = $12, status = $13,
@@ -168,7 +169,7 @@ func (r *datasetRepository) Update(ctx context.Context, ds *dataset.Dataset) err
 
 // Delete removes a dataset from the database
 func (r *datasetRepository) Delete(ctx context.Context, id core.ID) error {
-	query := `DELETE FROM datasets WHERE id = $1`
+	query := `DELETE FROM ` + r.table + ` WHERE id = $1`
 
 	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
@@ -193,7 +194,7 @@ func (r *datasetRepository) GetCurrent(ctx context.Context) (*dataset.Dataset, e
 		id, user_id, original_filename, COALESCE(file_path, '') as file_path, COALESCE(file_size, 0) as file_size, COALESCE(mime_type, '') as mime_type,
 		display_name, domain, description, COALESCE(record_count, 0) as record_count, COALESCE(field_count, 0) as field_count, COALESCE(missing_rate, 0.0) as missing_rate,
 		source, status, COALESCE(error_message, '') as error_message, metadata, created_at, updated_at
-	FROM datasets WHERE id = $1`
+	FROM ` + r.table + ` WHERE id = $1`
 
 	var ds dataset.Dataset
 	var metadataJSON []byte
@@ -228,7 +229,7 @@ func (r *datasetRepository) ListByStatus(ctx context.Context, status dataset.Dat
 		id, user_id, workspace_id, original_filename, COALESCE(file_path, '') as file_path, COALESCE(file_size, 0) as file_size, COALESCE(mime_type, '') as mime_type,
 		display_name, domain, description, COALESCE(record_count, 0) as record_count, COALESCE(field_count, 0) as field_count, COALESCE(missing_rate, 0.0) as missing_rate,
 		source, status, COALESCE(error_message, '') as error_message, metadata, created_at, updated_at
-	FROM datasets WHERE status = $1 ORDER BY created_at DESC`
+	FROM ` + r.table + ` WHERE status = $1 ORDER BY created_at DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, status)
 	if err != nil {
@@ -245,7 +246,7 @@ func (r *datasetRepository) ListByDomain(ctx context.Context, domain string) ([]
 		id, user_id, workspace_id, original_filename, COALESCE(file_path, '') as file_path, COALESCE(file_size, 0) as file_size, COALESCE(mime_type, '') as mime_type,
 		display_name, domain, description, COALESCE(record_count, 0) as record_count, COALESCE(field_count, 0) as field_count, COALESCE(missing_rate, 0.0) as missing_rate,
 		source, status, COALESCE(error_message, '') as error_message, metadata, created_at, updated_at
-	FROM datasets WHERE domain = $1 ORDER BY created_at DESC`
+	FROM ` + r.table + ` WHERE domain = $1 ORDER BY created_at DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, domain)
 	if err != nil {
@@ -258,7 +259,7 @@ func (r *datasetRepository) ListByDomain(ctx context.Context, domain string) ([]
 
 // UpdateStatus updates only the status and error message of a dataset
 func (r *datasetRepository) UpdateStatus(ctx context.Context, id core.ID, status dataset.DatasetStatus, errorMsg string) error {
-	query := `UPDATE datasets SET status = $2, error_message = $3, updated_at = NOW() WHERE id = $1`
+	query := `UPDATE ` + r.table + ` SET status = $2, error_message = $3, updated_at = NOW() WHERE id = $1`
 
 	result, err := r.db.ExecContext(ctx, query, id, status, errorMsg)
 	if err != nil {
@@ -283,7 +284,7 @@ func (r *datasetRepository) GetByWorkspace(ctx context.Context, workspaceID core
 		id, user_id, workspace_id, original_filename, COALESCE(file_path, '') as file_path, COALESCE(file_size, 0) as file_size, COALESCE(mime_type, '') as mime_type,
 		display_name, domain, description, COALESCE(record_count, 0) as record_count, COALESCE(field_count, 0) as field_count, COALESCE(missing_rate, 0.0) as missing_rate,
 		source, status, COALESCE(error_message, '') as error_message, metadata, created_at, updated_at
-	FROM datasets
+	FROM ` + r.table + `
 	WHERE workspace_id = $1
 	ORDER BY created_at DESC
 	LIMIT $2 OFFSET $3`
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// DecisionRepositoryImpl implements DecisionLog for PostgreSQL
+type DecisionRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewDecisionRepository creates a new PostgreSQL decision log
+func NewDecisionRepository(db *sqlx.DB) ports.DecisionLog {
+	return &DecisionRepositoryImpl{db: db}
+}
+
+// Record adds a pending decision, assigning its ID when empty
+func (r *DecisionRepositoryImpl) Record(ctx context.Context, decision *models.Decision) error {
+	if decision.ID == "" {
+		decision.ID = uuid.New().String()
+	}
+	if decision.DecidedAt.IsZero() {
+		decision.DecidedAt = time.Now()
+	}
+	decision.Outcome = models.OutcomePending
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO decisions (id, workspace_id, hypothesis_id, action, expected, decided_by, decided_at, outcome, gates)
+		VALUES (:id, :workspace_id, :hypothesis_id, :action, :expected, :decided_by, :decided_at, :outcome, :gates)
+	`, decision)
+	if err != nil {
+		return fmt.Errorf("failed to record decision for hypothesis %s: %w", decision.HypothesisID, err)
+	}
+	return nil
+}
+
+// RecordOutcome sets the outcome of one of a workspace's decisions
+func (r *DecisionRepositoryImpl) RecordOutcome(ctx context.Context, workspaceID, id, outcome, notes string) (*models.Decision, error) {
+	var decision models.Decision
+	err := r.db.GetContext(ctx, &decision, `
+		UPDATE decisions SET outcome = $3, outcome_notes = $4, outcome_at = NOW()
+		WHERE id = $1 AND workspace_id = $2
+		RETURNING *
+	`, id, workspaceID, outcome, notes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("Decision")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to record outcome of decision %s: %w", id, err)
+	}
+	return &decision, nil
+}
+
+// ListByWorkspace returns a workspace's decisions, newest first
+func (r *DecisionRepositoryImpl) ListByWorkspace(ctx context.Context, workspaceID string) ([]*models.Decision, error) {
+	var decisions []*models.Decision
+	err := r.db.SelectContext(ctx, &decisions, `
+		SELECT * FROM decisions WHERE workspace_id = $1 ORDER BY decided_at DESC
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list decisions for workspace %s: %w", workspaceID, err)
+	}
+	return decisions, nil
+}
+
+// ListResolved returns every decision with a recorded outcome, across workspaces
+func (r *DecisionRepositoryImpl) ListResolved(ctx context.Context) ([]*models.Decision, error) {
+	var decisions []*models.Decision
+	err := r.db.SelectContext(ctx, &decisions, `
+		SELECT * FROM decisions WHERE outcome <> $1 ORDER BY outcome_at
+	`, models.OutcomePending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resolved decisions: %w", err)
+	}
+	return decisions, nil
+}
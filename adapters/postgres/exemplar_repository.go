@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ExemplarRepositoryImpl implements ExemplarStore for PostgreSQL
+type ExemplarRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewExemplarRepository creates a new PostgreSQL few-shot exemplar store
+func NewExemplarRepository(db *sqlx.DB) ports.ExemplarStore {
+	return &ExemplarRepositoryImpl{db: db}
+}
+
+const exemplarColumns = `id, domain_pack, directive, evidence, source_hypothesis_id, status, proposed_by, approved_by, created_at, approved_at`
+
+// Propose stores a new exemplar awaiting approval
+func (r *ExemplarRepositoryImpl) Propose(ctx context.Context, exemplar *models.Exemplar) error {
+	if exemplar.ID == "" {
+		exemplar.ID = uuid.New().String()
+	}
+	exemplar.DomainPack = models.NormalizeDomainPack(exemplar.DomainPack)
+	exemplar.Status = models.ExemplarProposed
+
+	directiveJSON, err := json.Marshal(exemplar.Directive)
+	if err != nil {
+		return err
+	}
+	evidenceJSON, err := json.Marshal(exemplar.Evidence)
+	if err != nil {
+		return err
+	}
+
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO few_shot_exemplars (id, domain_pack, directive, evidence, source_hypothesis_id, status, proposed_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING created_at
+	`, exemplar.ID, exemplar.DomainPack, directiveJSON, evidenceJSON, exemplar.SourceHypothesisID, exemplar.Status, exemplar.ProposedBy).Scan(&exemplar.CreatedAt)
+}
+
+// Get returns one exemplar by ID
+func (r *ExemplarRepositoryImpl) Get(ctx context.Context, id string) (*models.Exemplar, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+exemplarColumns+` FROM few_shot_exemplars WHERE id = $1`, id)
+	exemplar, err := scanExemplar(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("Exemplar")
+	}
+	return exemplar, err
+}
+
+// List returns a pack's exemplars, newest approval first
+func (r *ExemplarRepositoryImpl) List(ctx context.Context, domainPack, status string) ([]*models.Exemplar, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+exemplarColumns+`
+		FROM few_shot_exemplars
+		WHERE ($1 = '' OR domain_pack = $1) AND ($2 = '' OR status = $2)
+		ORDER BY approved_at DESC NULLS LAST, created_at DESC, id
+	`, domainPack, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exemplars []*models.Exemplar
+	for rows.Next() {
+		exemplar, err := scanExemplar(rows)
+		if err != nil {
+			return nil, err
+		}
+		exemplars = append(exemplars, exemplar)
+	}
+	return exemplars, rows.Err()
+}
+
+// Approve makes a proposed exemplar eligible for generation prompts
+func (r *ExemplarRepositoryImpl) Approve(ctx context.Context, id, approvedBy string) error {
+	return r.transition(ctx, id, models.ExemplarApproved, approvedBy)
+}
+
+// Retire removes an exemplar from prompts but keeps it on record
+func (r *ExemplarRepositoryImpl) Retire(ctx context.Context, id string) error {
+	return r.transition(ctx, id, models.ExemplarRetired, "")
+}
+
+func (r *ExemplarRepositoryImpl) transition(ctx context.Context, id, status, approvedBy string) error {
+	var result sql.Result
+	var err error
+	if status == models.ExemplarApproved {
+		result, err = r.db.ExecContext(ctx, `
+			UPDATE few_shot_exemplars SET status = $2, approved_by = $3, approved_at = NOW()
+			WHERE id = $1 AND status <> $2
+		`, id, status, approvedBy)
+	} else {
+		result, err = r.db.ExecContext(ctx, `UPDATE few_shot_exemplars SET status = $2 WHERE id = $1`, id, status)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set exemplar %s %s: %w", id, status, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		if _, err := r.Get(ctx, id); err != nil {
+			return err
+		}
+		return apperrors.Conflict(fmt.Sprintf("Exemplar %s is already %s", id, status))
+	}
+	return nil
+}
+
+// DomainPacks lists the packs with at least one approved exemplar
+func (r *ExemplarRepositoryImpl) DomainPacks(ctx context.Context) ([]string, error) {
+	var packs []string
+	err := r.db.SelectContext(ctx, &packs, `
+		SELECT DISTINCT domain_pack FROM few_shot_exemplars WHERE status = $1 ORDER BY domain_pack
+	`, models.ExemplarApproved)
+	return packs, err
+}
+
+type exemplarScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanExemplar(row exemplarScanner) (*models.Exemplar, error) {
+	var exemplar models.Exemplar
+	var directiveJSON, evidenceJSON []byte
+	var source, proposedBy, approvedBy sql.NullString
+	var approvedAt sql.NullTime
+	if err := row.Scan(&exemplar.ID, &exemplar.DomainPack, &directiveJSON, &evidenceJSON, &source,
+		&exemplar.Status, &proposedBy, &approvedBy, &exemplar.CreatedAt, &approvedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(directiveJSON, &exemplar.Directive); err != nil {
+		return nil, fmt.Errorf("exemplar %s: invalid directive: %w", exemplar.ID, err)
+	}
+	if len(evidenceJSON) > 0 {
+		if err := json.Unmarshal(evidenceJSON, &exemplar.Evidence); err != nil {
+			return nil, fmt.Errorf("exemplar %s: invalid evidence: %w", exemplar.ID, err)
+		}
+	}
+	exemplar.SourceHypothesisID = source.String
+	exemplar.ProposedBy = proposedBy.String
+	exemplar.ApprovedBy = approvedBy.String
+	if approvedAt.Valid {
+		exemplar.ApprovedAt = &approvedAt.Time
+	}
+	return &exemplar, nil
+}
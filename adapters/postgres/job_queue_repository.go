@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// JobQueueRepositoryImpl implements JobQueue for PostgreSQL
+type JobQueueRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewJobQueueRepository creates a new PostgreSQL job queue
+func NewJobQueueRepository(db *sqlx.DB) ports.JobQueue {
+	return &JobQueueRepositoryImpl{db: db}
+}
+
+// Enqueue adds a job, filling in its ID
+func (r *JobQueueRepositoryImpl) Enqueue(ctx context.Context, job *models.Job) error {
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = models.DefaultJobMaxAttempts
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	err := r.db.QueryRowxContext(ctx, `
+		INSERT INTO jobs (kind, payload, status, max_attempts, run_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, job.Kind, []byte(job.Payload), models.JobStatusQueued, job.MaxAttempts, job.RunAt).Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue %s job: %w", job.Kind, err)
+	}
+	job.Status = models.JobStatusQueued
+	return nil
+}
+
+// Claim marks the oldest queued job of kind that is due as running under workerID for
+// lease, counting the attempt, and returns it; nil when no job is due
+func (r *JobQueueRepositoryImpl) Claim(ctx context.Context, kind, workerID string, lease time.Duration) (*models.Job, error) {
+	// SKIP LOCKED lets concurrent workers each pick a different job instead of queueing on
+	// the row lock of the same one
+	var job models.Job
+	err := r.db.GetContext(ctx, &job, `
+		UPDATE jobs
+		SET status = $3, attempts = attempts + 1, locked_by = $2, locked_at = NOW(),
+		    lease_expires_at = NOW() + $4 * INTERVAL '1 millisecond', updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE kind = $1 AND status = 'queued' AND run_at <= NOW()
+			ORDER BY run_at, created_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING *
+	`, kind, workerID, models.JobStatusRunning, lease.Milliseconds())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim %s job: %w", kind, err)
+	}
+	return &job, nil
+}
+
+// Heartbeat extends a running job's lease by lease from now. It reports false when the
+// job is no longer held by workerID, because its lease expired and it was recovered.
+func (r *JobQueueRepositoryImpl) Heartbeat(ctx context.Context, jobID, workerID string, lease time.Duration) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET lease_expires_at = NOW() + $3 * INTERVAL '1 millisecond', updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND status = 'running'
+	`, jobID, workerID, lease.Milliseconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to extend lease of job %s: %w", jobID, err)
+	}
+	return held(result, jobID)
+}
+
+// Release queues a job held by workerID again to run at runAt, without counting the
+// attempt, for a job the worker could not start
+func (r *JobQueueRepositoryImpl) Release(ctx context.Context, jobID, workerID string, runAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = $3, attempts = GREATEST(attempts - 1, 0), run_at = $4,
+		    locked_by = NULL, locked_at = NULL, lease_expires_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2
+	`, jobID, workerID, models.JobStatusQueued, runAt)
+	if err != nil {
+		return fmt.Errorf("failed to release job %s: %w", jobID, err)
+	}
+	return nil
+}
+
+// Complete marks a job held by workerID as succeeded
+func (r *JobQueueRepositoryImpl) Complete(ctx context.Context, jobID, workerID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = $3, locked_by = NULL, locked_at = NULL, lease_expires_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2
+	`, jobID, workerID, models.JobStatusSucceeded)
+	if err != nil {
+		return fmt.Errorf("failed to complete job %s: %w", jobID, err)
+	}
+	return nil
+}
+
+// Fail records a failed attempt of a job held by workerID. The job is queued again to run
+// at retryAt when it has attempts left, and marked failed otherwise.
+func (r *JobQueueRepositoryImpl) Fail(ctx context.Context, jobID, workerID string, errMsg string, retryAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = CASE WHEN attempts < max_attempts THEN $4 ELSE $5 END,
+		    run_at = CASE WHEN attempts < max_attempts THEN $6 ELSE run_at END,
+		    last_error = $3, locked_by = NULL, locked_at = NULL, lease_expires_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2
+	`, jobID, workerID, errMsg, models.JobStatusQueued, models.JobStatusFailed, retryAt)
+	if err != nil {
+		return fmt.Errorf("failed to record failure of job %s: %w", jobID, err)
+	}
+	return nil
+}
+
+// RecoverOrphaned queues again the running jobs whose lease expired, whose worker is
+// taken to be gone, and returns how many there were
+func (r *JobQueueRepositoryImpl) RecoverOrphaned(ctx context.Context) (int, error) {
+	// The interrupted attempt still counts, so a job that keeps taking its worker down
+	// runs out of attempts instead of being recovered forever
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = CASE WHEN attempts < max_attempts THEN $1 ELSE $2 END,
+		    run_at = NOW(), last_error = 'worker stopped heartbeating',
+		    locked_by = NULL, locked_at = NULL, lease_expires_at = NULL, updated_at = NOW()
+		WHERE status = 'running' AND lease_expires_at < NOW()
+	`, models.JobStatusQueued, models.JobStatusFailed)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recover orphaned jobs: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovered jobs: %w", err)
+	}
+	return int(n), nil
+}
+
+// GetJob returns a job, or nil when there is none with the ID
+func (r *JobQueueRepositoryImpl) GetJob(ctx context.Context, jobID string) (*models.Job, error) {
+	var job models.Job
+	err := r.db.GetContext(ctx, &job, `SELECT * FROM jobs WHERE id = $1`, jobID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s: %w", jobID, err)
+	}
+	return &job, nil
+}
+
+// ListJobs returns up to limit jobs of kind, newest first, optionally only those in status
+func (r *JobQueueRepositoryImpl) ListJobs(ctx context.Context, kind string, status *models.JobStatus, limit int) ([]*models.Job, error) {
+	var jobs []*models.Job
+	var err error
+	if status != nil {
+		err = r.db.SelectContext(ctx, &jobs, `
+			SELECT * FROM jobs WHERE kind = $1 AND status = $2 ORDER BY created_at DESC LIMIT $3
+		`, kind, *status, limit)
+	} else {
+		err = r.db.SelectContext(ctx, &jobs, `
+			SELECT * FROM jobs WHERE kind = $1 ORDER BY created_at DESC LIMIT $2
+		`, kind, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s jobs: %w", kind, err)
+	}
+	return jobs, nil
+}
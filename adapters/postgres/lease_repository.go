@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gohypo/ports"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// LeaseRepositoryImpl implements LeaseStore for PostgreSQL
+type LeaseRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewLeaseRepository creates a new PostgreSQL lease store
+func NewLeaseRepository(db *sqlx.DB) ports.LeaseStore {
+	return &LeaseRepositoryImpl{db: db}
+}
+
+// AcquireLease takes the lease on key for holder for ttl, reporting false when another
+// holder has it. Acquiring a lease the holder already has renews it.
+func (r *LeaseRepositoryImpl) AcquireLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	// The primary key makes this a single atomic decision: the insert wins for a free key,
+	// the update only for an expired lease or one the holder already has
+	var got string
+	err := r.db.GetContext(ctx, &got, `
+		INSERT INTO leases (key, holder, acquired_at, expires_at)
+		VALUES ($1, $2, NOW(), NOW() + $3 * INTERVAL '1 millisecond')
+		ON CONFLICT (key) DO UPDATE
+		SET holder = EXCLUDED.holder, acquired_at = EXCLUDED.acquired_at, expires_at = EXCLUDED.expires_at
+		WHERE leases.expires_at < NOW() OR leases.holder = EXCLUDED.holder
+		RETURNING holder
+	`, key, holder, ttl.Milliseconds())
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// RenewLease extends holder's lease on key by ttl from now, reporting false when holder
+// no longer has it
+func (r *LeaseRepositoryImpl) RenewLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE leases SET expires_at = NOW() + $3 * INTERVAL '1 millisecond'
+		WHERE key = $1 AND holder = $2
+	`, key, holder, ttl.Milliseconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to renew lease %s: %w", key, err)
+	}
+	return held(result, key)
+}
+
+// ReleaseLease gives up holder's lease on key; releasing a lease held by another holder
+// does nothing
+func (r *LeaseRepositoryImpl) ReleaseLease(ctx context.Context, key, holder string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM leases WHERE key = $1 AND holder = $2`, key, holder)
+	if err != nil {
+		return fmt.Errorf("failed to release lease %s: %w", key, err)
+	}
+	return nil
+}
+
+// held reports whether an update matched the row of a lock still held
+func held(result sql.Result, key string) (bool, error) {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check lock on %s: %w", key, err)
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"gohypo/ports"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// LLMResponseCacheImpl implements LLMResponseCache for PostgreSQL so cached responses are
+// shared by every process pointed at the same database
+type LLMResponseCacheImpl struct {
+	db *sqlx.DB
+}
+
+// NewLLMResponseCache creates a new PostgreSQL LLM response cache
+func NewLLMResponseCache(db *sqlx.DB) ports.LLMResponseCache {
+	return &LLMResponseCacheImpl{db: db}
+}
+
+// Get returns the cached response for key and counts the hit
+func (r *LLMResponseCacheImpl) Get(ctx context.Context, key string) (*ports.LLMResponse, bool, error) {
+	var row struct {
+		Content string `db:"content"`
+		Usage   []byte `db:"usage"`
+	}
+	err := r.db.GetContext(ctx, &row, `
+		UPDATE llm_response_cache
+		SET hit_count = hit_count + 1, last_hit_at = NOW()
+		WHERE cache_key = $1
+		RETURNING content, usage
+	`, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp := &ports.LLMResponse{Content: row.Content}
+	if len(row.Usage) > 0 {
+		var usage ports.UsageData
+		if err := json.Unmarshal(row.Usage, &usage); err != nil {
+			return nil, false, err
+		}
+		resp.Usage = &usage
+	}
+	return resp, true, nil
+}
+
+// Put stores a response; the first writer for a key wins
+func (r *LLMResponseCacheImpl) Put(ctx context.Context, key string, response *ports.LLMResponse) error {
+	usageJSON, err := json.Marshal(response.Usage)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO llm_response_cache (cache_key, content, usage, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (cache_key) DO NOTHING
+	`, key, response.Content, usageJSON)
+	return err
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// NotificationChannelRepositoryImpl implements NotificationChannelStore for PostgreSQL
+type NotificationChannelRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewNotificationChannelRepository creates a new PostgreSQL notification channel store
+func NewNotificationChannelRepository(db *sqlx.DB) ports.NotificationChannelStore {
+	return &NotificationChannelRepositoryImpl{db: db}
+}
+
+const notificationChannelColumns = `id, workspace_id, kind, name, webhook_url, min_rigor, active, created_by, created_at`
+
+// CreateChannel stores a new channel, assigning its ID when empty
+func (r *NotificationChannelRepositoryImpl) CreateChannel(ctx context.Context, channel *models.NotificationChannel) error {
+	if channel.ID == "" {
+		channel.ID = uuid.New().String()
+	}
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO notification_channels (`+notificationChannelColumns+`)
+		VALUES (:id, :workspace_id, :kind, :name, :webhook_url, :min_rigor, :active, :created_by, :created_at)
+	`, channel)
+	if err != nil {
+		return fmt.Errorf("failed to create notification channel for workspace %s: %w", channel.WorkspaceID, err)
+	}
+	return nil
+}
+
+// GetChannel returns one of a workspace's channels
+func (r *NotificationChannelRepositoryImpl) GetChannel(ctx context.Context, workspaceID, id string) (*models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	err := r.db.GetContext(ctx, &channel, `
+		SELECT `+notificationChannelColumns+` FROM notification_channels WHERE id = $1 AND workspace_id = $2
+	`, id, workspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("Notification channel")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification channel %s: %w", id, err)
+	}
+	return &channel, nil
+}
+
+// ListChannels returns a workspace's channels, oldest first
+func (r *NotificationChannelRepositoryImpl) ListChannels(ctx context.Context, workspaceID string) ([]*models.NotificationChannel, error) {
+	var channels []*models.NotificationChannel
+	err := r.db.SelectContext(ctx, &channels, `
+		SELECT `+notificationChannelColumns+` FROM notification_channels WHERE workspace_id = $1 ORDER BY created_at
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification channels for workspace %s: %w", workspaceID, err)
+	}
+	return channels, nil
+}
+
+// DeleteChannel removes one of a workspace's channels
+func (r *NotificationChannelRepositoryImpl) DeleteChannel(ctx context.Context, workspaceID, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM notification_channels WHERE id = $1 AND workspace_id = $2`, id, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification channel %s: %w", id, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return apperrors.NotFound("Notification channel")
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// RefereeRunRepositoryImpl implements RefereeRunStore for PostgreSQL
+type RefereeRunRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewRefereeRunRepository creates a new PostgreSQL referee run store
+func NewRefereeRunRepository(db *sqlx.DB) ports.RefereeRunStore {
+	return &RefereeRunRepositoryImpl{db: db}
+}
+
+// StartRun records a running referee, assigning its ID when empty
+func (r *RefereeRunRepositoryImpl) StartRun(ctx context.Context, run *models.RefereeRun) error {
+	if run.ID == "" {
+		run.ID = uuid.New().String()
+	}
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO referee_runs (id, session_id, hypothesis_id, referee, sample_size, status, started_at)
+		VALUES (:id, :session_id, :hypothesis_id, :referee, :sample_size, :status, :started_at)
+	`, run)
+	if err != nil {
+		return fmt.Errorf("failed to record start of referee %s for hypothesis %s: %w", run.Referee, run.HypothesisID, err)
+	}
+	return nil
+}
+
+// FinishRun records a run's outcome, finish time and duration
+func (r *RefereeRunRepositoryImpl) FinishRun(ctx context.Context, run *models.RefereeRun) error {
+	_, err := r.db.NamedExecContext(ctx, `
+		UPDATE referee_runs
+		SET status = :status, passed = :passed, finished_at = :finished_at, duration_ms = :duration_ms
+		WHERE id = :id
+	`, run)
+	if err != nil {
+		return fmt.Errorf("failed to record finish of referee %s for hypothesis %s: %w", run.Referee, run.HypothesisID, err)
+	}
+	return nil
+}
+
+// ListSessionRuns returns a session's runs, oldest first
+func (r *RefereeRunRepositoryImpl) ListSessionRuns(ctx context.Context, sessionID string) ([]*models.RefereeRun, error) {
+	var runs []*models.RefereeRun
+	err := r.db.SelectContext(ctx, &runs, `
+		SELECT * FROM referee_runs WHERE session_id = $1 ORDER BY started_at
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list referee runs for session %s: %w", sessionID, err)
+	}
+	return runs, nil
+}
+
+// RecentRuns returns up to limit of a referee's most recent finished runs
+func (r *RefereeRunRepositoryImpl) RecentRuns(ctx context.Context, referee string, limit int) ([]*models.RefereeRun, error) {
+	var runs []*models.RefereeRun
+	err := r.db.SelectContext(ctx, &runs, `
+		SELECT * FROM referee_runs
+		WHERE referee = $1 AND status IN ('completed', 'failed')
+		ORDER BY finished_at DESC LIMIT $2
+	`, referee, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent runs of referee %s: %w", referee, err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// RelationshipCheckRepositoryImpl implements RelationshipCheckStore for PostgreSQL
+type RelationshipCheckRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewRelationshipCheckRepository creates a new PostgreSQL relationship check store
+func NewRelationshipCheckRepository(db *sqlx.DB) ports.RelationshipCheckStore {
+	return &RelationshipCheckRepositoryImpl{db: db}
+}
+
+// SaveCheck records a check, assigning its ID when empty. NaN estimates from failed checks
+// are stored as zero; the error column says why.
+func (r *RelationshipCheckRepositoryImpl) SaveCheck(ctx context.Context, check *models.RelationshipCheck) error {
+	if check.ID == "" {
+		check.ID = uuid.New().String()
+	}
+	row := *check
+	for _, v := range []*float64{&row.Correlation, &row.DirectionalEffect, &row.PValue} {
+		if math.IsNaN(*v) {
+			*v = 0
+		}
+	}
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO relationship_checks (
+			id, hypothesis_id, workspace_id, dataset_id, dataset_version, cause, effect,
+			validated_correlation, correlation, directional_effect, p_value, sample_size,
+			floor, degraded, alerted, error, checked_at
+		) VALUES (
+			:id, :hypothesis_id, :workspace_id, :dataset_id, :dataset_version, :cause, :effect,
+			:validated_correlation, :correlation, :directional_effect, :p_value, :sample_size,
+			:floor, :degraded, :alerted, :error, :checked_at
+		)
+	`, row)
+	if err != nil {
+		return fmt.Errorf("failed to save relationship check for hypothesis %s: %w", check.HypothesisID, err)
+	}
+	return nil
+}
+
+// ListChecks returns a workspace's checks ordered by dataset version, then check time
+func (r *RelationshipCheckRepositoryImpl) ListChecks(ctx context.Context, workspaceID string) ([]*models.RelationshipCheck, error) {
+	var checks []*models.RelationshipCheck
+	err := r.db.SelectContext(ctx, &checks, `
+		SELECT * FROM relationship_checks WHERE workspace_id = $1 ORDER BY dataset_version, checked_at
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list relationship checks for workspace %s: %w", workspaceID, err)
+	}
+	return checks, nil
+}
+
+// LatestCheck returns a hypothesis' most recent check, or nil when it was never checked
+func (r *RelationshipCheckRepositoryImpl) LatestCheck(ctx context.Context, hypothesisID string) (*models.RelationshipCheck, error) {
+	var check models.RelationshipCheck
+	err := r.db.GetContext(ctx, &check, `
+		SELECT * FROM relationship_checks WHERE hypothesis_id = $1 ORDER BY checked_at DESC LIMIT 1
+	`, hypothesisID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load latest check for hypothesis %s: %w", hypothesisID, err)
+	}
+	return &check, nil
+}
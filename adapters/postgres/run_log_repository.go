@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RunLogRepositoryImpl implements RunLogStore for PostgreSQL
+type RunLogRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewRunLogRepository creates a new PostgreSQL run log store
+func NewRunLogRepository(db *sqlx.DB) ports.RunLogStore {
+	return &RunLogRepositoryImpl{db: db}
+}
+
+// AppendRunLogs stores log lines, of one or more runs
+func (r *RunLogRepositoryImpl) AppendRunLogs(ctx context.Context, entries []models.RunLogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO run_logs (run_id, seq, logged_at, level, severity, message, attrs)
+		VALUES (:run_id, :seq, :logged_at, :level, :severity, :message, :attrs)
+	`, entries)
+	if err != nil {
+		return fmt.Errorf("failed to store %d run log lines: %w", len(entries), err)
+	}
+	return nil
+}
+
+// ListRunLogs returns up to limit of a run's lines at minSeverity or above, oldest first
+func (r *RunLogRepositoryImpl) ListRunLogs(ctx context.Context, runID string, minSeverity int, limit int) ([]models.RunLogEntry, error) {
+	// Ordered by time rather than seq, which restarts when a run is retried after a restart
+	entries := []models.RunLogEntry{}
+	err := r.db.SelectContext(ctx, &entries, `
+		SELECT run_id, seq, logged_at, level, severity, message, attrs
+		FROM run_logs
+		WHERE run_id = $1 AND severity >= $2
+		ORDER BY logged_at, id
+		LIMIT $3
+	`, runID, minSeverity, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list logs of run %s: %w", runID, err)
+	}
+	return entries, nil
+}
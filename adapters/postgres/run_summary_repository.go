@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RunSummaryRepositoryImpl implements RunSummaryStore for PostgreSQL
+type RunSummaryRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewRunSummaryRepository creates a new PostgreSQL run summary store
+func NewRunSummaryRepository(db *sqlx.DB) ports.RunSummaryStore {
+	return &RunSummaryRepositoryImpl{db: db}
+}
+
+// SaveRunSummary writes a run's summary with its test type counts and top effects,
+// replacing any earlier summary of the run
+func (r *RunSummaryRepositoryImpl) SaveRunSummary(ctx context.Context, summary *models.RunSummary) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin run summary transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Deleting the run cascades to its test type counts and top effects
+	if _, err := tx.ExecContext(ctx, `DELETE FROM run_summaries WHERE run_id = $1`, summary.RunID); err != nil {
+		return fmt.Errorf("failed to replace summary of run %s: %w", summary.RunID, err)
+	}
+	_, err = tx.NamedExecContext(ctx, `
+		INSERT INTO run_summaries (run_id, session_id, workspace_id, dataset_id, snapshot_id, fdr_method,
+		                           variables_analyzed, relationships, significant_pairs, created_at)
+		VALUES (:run_id, :session_id, :workspace_id, :dataset_id, :snapshot_id, :fdr_method,
+		        :variables_analyzed, :relationships, :significant_pairs, :created_at)
+	`, summary)
+	if err != nil {
+		return fmt.Errorf("failed to save summary of run %s: %w", summary.RunID, err)
+	}
+	for _, count := range summary.TestTypes {
+		count.RunID = summary.RunID
+		_, err := tx.NamedExecContext(ctx, `
+			INSERT INTO run_summary_test_types (run_id, test_type, relationships, significant)
+			VALUES (:run_id, :test_type, :relationships, :significant)
+		`, count)
+		if err != nil {
+			return fmt.Errorf("failed to save %s counts of run %s: %w", count.TestType, summary.RunID, err)
+		}
+	}
+	for _, effect := range summary.TopEffects {
+		effect.RunID = summary.RunID
+		_, err := tx.NamedExecContext(ctx, `
+			INSERT INTO run_summary_top_effects (run_id, rank, variable_x, variable_y, test_type,
+			                                     effect_size, p_value, q_value, sample_size, noise_percentile)
+			VALUES (:run_id, :rank, :variable_x, :variable_y, :test_type,
+			        :effect_size, :p_value, :q_value, :sample_size, :noise_percentile)
+		`, effect)
+		if err != nil {
+			return fmt.Errorf("failed to save top effect %d of run %s: %w", effect.Rank, summary.RunID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteRunSummary removes a run's summary; its test type counts and top effects cascade
+func (r *RunSummaryRepositoryImpl) DeleteRunSummary(ctx context.Context, runID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM run_summaries WHERE run_id = $1`, runID); err != nil {
+		return fmt.Errorf("failed to delete summary of run %s: %w", runID, err)
+	}
+	return nil
+}
+
+// GetRunSummary returns a run's summary, or nil when the run has none
+func (r *RunSummaryRepositoryImpl) GetRunSummary(ctx context.Context, runID string) (*models.RunSummary, error) {
+	return r.getSummary(ctx, `SELECT * FROM run_summaries WHERE run_id = $1`, runID)
+}
+
+// LatestSessionSummary returns the summary of a session's most recent run, or nil
+func (r *RunSummaryRepositoryImpl) LatestSessionSummary(ctx context.Context, sessionID string) (*models.RunSummary, error) {
+	return r.getSummary(ctx, `
+		SELECT * FROM run_summaries WHERE session_id = $1 ORDER BY created_at DESC LIMIT 1
+	`, sessionID)
+}
+
+// ListWorkspaceSummaries returns up to limit of a workspace's run summaries, newest first,
+// without their test type counts and top effects
+func (r *RunSummaryRepositoryImpl) ListWorkspaceSummaries(ctx context.Context, workspaceID string, limit int) ([]*models.RunSummary, error) {
+	var summaries []*models.RunSummary
+	err := r.db.SelectContext(ctx, &summaries, `
+		SELECT * FROM run_summaries WHERE workspace_id = $1 ORDER BY created_at DESC LIMIT $2
+	`, workspaceID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list run summaries for workspace %s: %w", workspaceID, err)
+	}
+	return summaries, nil
+}
+
+// ListDatasetRuns returns up to limit of the runs that swept a dataset, newest first,
+// without their test type counts and top effects
+func (r *RunSummaryRepositoryImpl) ListDatasetRuns(ctx context.Context, datasetID string, limit int) ([]*models.RunSummary, error) {
+	var summaries []*models.RunSummary
+	err := r.db.SelectContext(ctx, &summaries, `
+		SELECT * FROM run_summaries WHERE dataset_id = $1 ORDER BY created_at DESC LIMIT $2
+	`, datasetID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs of dataset %s: %w", datasetID, err)
+	}
+	return summaries, nil
+}
+
+// getSummary loads the summary row the query selects with its test type counts and top effects
+func (r *RunSummaryRepositoryImpl) getSummary(ctx context.Context, query string, arg string) (*models.RunSummary, error) {
+	var summary models.RunSummary
+	err := r.db.GetContext(ctx, &summary, query, arg)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run summary for %s: %w", arg, err)
+	}
+
+	summary.TestTypes = []models.RunTestTypeCount{}
+	err = r.db.SelectContext(ctx, &summary.TestTypes, `
+		SELECT * FROM run_summary_test_types WHERE run_id = $1 ORDER BY test_type
+	`, summary.RunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test type counts of run %s: %w", summary.RunID, err)
+	}
+	summary.TopEffects = []models.RunEffect{}
+	err = r.db.SelectContext(ctx, &summary.TopEffects, `
+		SELECT * FROM run_summary_top_effects WHERE run_id = $1 ORDER BY rank
+	`, summary.RunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top effects of run %s: %w", summary.RunID, err)
+	}
+	return &summary, nil
+}
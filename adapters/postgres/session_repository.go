@@ -2,6 +2,8 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"gohypo/models"
@@ -172,6 +174,12 @@ func (r *SessionRepositoryImpl) GetActiveUserSessions(ctx context.Context, userI
 	return sessions, rows.Err()
 }
 
+// DeleteSession removes a session; its prompts and hypotheses cascade
+func (r *SessionRepositoryImpl) DeleteSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM research_sessions WHERE user_id = $1 AND id = $2`, userID, sessionID)
+	return err
+}
+
 // SetSessionError sets an error state for a session
 func (r *SessionRepositoryImpl) SetSessionError(ctx context.Context, userID, sessionID uuid.UUID, errorMsg string) error {
 	_, err := r.db.ExecContext(ctx, `
@@ -181,3 +189,39 @@ func (r *SessionRepositoryImpl) SetSessionError(ctx context.Context, userID, ses
 	`, userID, sessionID, errorMsg)
 	return err
 }
+
+// MergeSessionMetadata rewrites one metadata key from its current value under the session's
+// row lock
+func (r *SessionRepositoryImpl) MergeSessionMetadata(ctx context.Context, userID, sessionID uuid.UUID, key string, merge func(current json.RawMessage) (interface{}, error)) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin session metadata transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var current []byte
+	if err := tx.GetContext(ctx, &current, `
+		SELECT metadata->$3::text
+		FROM research_sessions
+		WHERE user_id = $1 AND id = $2
+		FOR UPDATE
+	`, userID, sessionID, key); err != nil {
+		return fmt.Errorf("failed to lock session %s: %w", sessionID, err)
+	}
+	value, err := merge(current)
+	if err != nil {
+		return err
+	}
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE research_sessions
+		SET metadata = COALESCE(metadata, '{}'::jsonb) || jsonb_build_object($3::text, $4::jsonb), updated_at = NOW()
+		WHERE user_id = $1 AND id = $2
+	`, userID, sessionID, key, valueJSON); err != nil {
+		return fmt.Errorf("failed to update session %s metadata: %w", sessionID, err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ShareLinkRepositoryImpl implements ShareLinkStore for PostgreSQL
+type ShareLinkRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewShareLinkRepository creates a new PostgreSQL share link store
+func NewShareLinkRepository(db *sqlx.DB) ports.ShareLinkStore {
+	return &ShareLinkRepositoryImpl{db: db}
+}
+
+const shareLinkColumns = `id, token_hash, workspace_id, hypothesis_id, created_by, created_at, expires_at, revoked_at`
+
+// Create stores a new link, assigning its ID when empty
+func (r *ShareLinkRepositoryImpl) Create(ctx context.Context, link *models.ShareLink) error {
+	if link.ID == "" {
+		link.ID = uuid.New().String()
+	}
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO share_links (`+shareLinkColumns+`)
+		VALUES (:id, :token_hash, :workspace_id, :hypothesis_id, :created_by, :created_at, :expires_at, :revoked_at)
+	`, link)
+	if err != nil {
+		return fmt.Errorf("failed to create share link for hypothesis %s: %w", link.HypothesisID, err)
+	}
+	return nil
+}
+
+// GetByToken returns the link for a token
+func (r *ShareLinkRepositoryImpl) GetByToken(ctx context.Context, token string) (*models.ShareLink, error) {
+	var link models.ShareLink
+	err := r.db.GetContext(ctx, &link, `SELECT `+shareLinkColumns+` FROM share_links WHERE token_hash = $1`, models.HashShareToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("Share link")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load share link: %w", err)
+	}
+	return &link, nil
+}
+
+// ListByWorkspace returns a workspace's links, newest first
+func (r *ShareLinkRepositoryImpl) ListByWorkspace(ctx context.Context, workspaceID string) ([]*models.ShareLink, error) {
+	var links []*models.ShareLink
+	err := r.db.SelectContext(ctx, &links, `
+		SELECT `+shareLinkColumns+` FROM share_links WHERE workspace_id = $1 ORDER BY created_at DESC
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share links for workspace %s: %w", workspaceID, err)
+	}
+	return links, nil
+}
+
+// Revoke ends one of a workspace's links now
+func (r *ShareLinkRepositoryImpl) Revoke(ctx context.Context, workspaceID, id string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE share_links SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1 AND workspace_id = $2
+	`, id, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke share link %s: %w", id, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return apperrors.NotFound("Share link")
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// TriageRepositoryImpl implements TriageStore for PostgreSQL
+type TriageRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewTriageRepository creates a new PostgreSQL triage store
+func NewTriageRepository(db *sqlx.DB) ports.TriageStore {
+	return &TriageRepositoryImpl{db: db}
+}
+
+// Mark records the marks in one transaction, replacing earlier marks of the same items
+func (r *TriageRepositoryImpl) Mark(ctx context.Context, marks []*models.TriageMark) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin triage transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, mark := range marks {
+		if mark.MarkedAt.IsZero() {
+			mark.MarkedAt = time.Now()
+		}
+		_, err := tx.NamedExecContext(ctx, `
+			INSERT INTO triage_marks (workspace_id, list, item_id, status, marked_by, marked_at)
+			VALUES (:workspace_id, :list, :item_id, :status, :marked_by, :marked_at)
+			ON CONFLICT (workspace_id, list, item_id)
+			DO UPDATE SET status = EXCLUDED.status, marked_by = EXCLUDED.marked_by, marked_at = EXCLUDED.marked_at
+		`, mark)
+		if err != nil {
+			return fmt.Errorf("failed to mark %s %s: %w", mark.List, mark.ItemID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit triage marks: %w", err)
+	}
+	return nil
+}
+
+// ListMarks returns the marks on one of a workspace's lists
+func (r *TriageRepositoryImpl) ListMarks(ctx context.Context, workspaceID, list string) ([]*models.TriageMark, error) {
+	var marks []*models.TriageMark
+	err := r.db.SelectContext(ctx, &marks, `
+		SELECT workspace_id, list, item_id, status, marked_by, marked_at
+		FROM triage_marks WHERE workspace_id = $1 AND list = $2
+	`, workspaceID, list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s marks for workspace %s: %w", list, workspaceID, err)
+	}
+	return marks, nil
+}
+
+const savedViewColumns = `id, user_id, list, name, filters, created_at`
+
+// CreateView stores a saved view, assigning its ID when empty
+func (r *TriageRepositoryImpl) CreateView(ctx context.Context, view *models.SavedView) error {
+	if view.ID == "" {
+		view.ID = uuid.New().String()
+	}
+	if view.CreatedAt.IsZero() {
+		view.CreatedAt = time.Now()
+	}
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO saved_views (`+savedViewColumns+`)
+		VALUES (:id, :user_id, :list, :name, :filters, :created_at)
+	`, view)
+	if err != nil {
+		return fmt.Errorf("failed to save view %q: %w", view.Name, err)
+	}
+	return nil
+}
+
+// ListViews returns a user's saved views of a list, oldest first
+func (r *TriageRepositoryImpl) ListViews(ctx context.Context, userID, list string) ([]*models.SavedView, error) {
+	var views []*models.SavedView
+	err := r.db.SelectContext(ctx, &views, `
+		SELECT `+savedViewColumns+` FROM saved_views
+		WHERE user_id = $1 AND ($2 = '' OR list = $2) ORDER BY created_at
+	`, userID, list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved views: %w", err)
+	}
+	return views, nil
+}
+
+// GetView returns one of a user's saved views
+func (r *TriageRepositoryImpl) GetView(ctx context.Context, userID, id string) (*models.SavedView, error) {
+	var view models.SavedView
+	err := r.db.GetContext(ctx, &view, `SELECT `+savedViewColumns+` FROM saved_views WHERE id = $1 AND user_id = $2`, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("Saved view")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load saved view %s: %w", id, err)
+	}
+	return &view, nil
+}
+
+// DeleteView removes one of a user's saved views
+func (r *TriageRepositoryImpl) DeleteView(ctx context.Context, userID, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM saved_views WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved view %s: %w", id, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return apperrors.NotFound("Saved view")
+	}
+	return nil
+}
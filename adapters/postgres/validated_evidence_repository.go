@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"gohypo/ports"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ValidatedEvidenceRepositoryImpl implements ValidatedEvidenceSource for PostgreSQL
+type ValidatedEvidenceRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewValidatedEvidenceRepository creates a new PostgreSQL validated evidence source
+func NewValidatedEvidenceRepository(db *sqlx.DB) ports.ValidatedEvidenceSource {
+	return &ValidatedEvidenceRepositoryImpl{db: db}
+}
+
+// ValidatedSessions returns the sessions with at least one hypothesis that passed validation
+func (r *ValidatedEvidenceRepositoryImpl) ValidatedSessions(ctx context.Context) ([]string, error) {
+	sessionIDs := []string{}
+	err := r.db.SelectContext(ctx, &sessionIDs, `
+		SELECT DISTINCT session_id::text FROM hypothesis_results WHERE passed = true
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list validated sessions: %w", err)
+	}
+	return sessionIDs, nil
+}
@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// WebhookRepositoryImpl implements WebhookStore for PostgreSQL
+type WebhookRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewWebhookRepository creates a new PostgreSQL webhook store
+func NewWebhookRepository(db *sqlx.DB) ports.WebhookStore {
+	return &WebhookRepositoryImpl{db: db}
+}
+
+const webhookColumns = `id, workspace_id, url, secret, events, active, created_by, created_at`
+
+const webhookDeliveryColumns = `id, webhook_id, workspace_id, event, payload, status, attempts, response_status,
+	last_error, next_attempt_at, delivered_at, created_at, updated_at`
+
+// CreateWebhook stores a new webhook, assigning its ID when empty
+func (r *WebhookRepositoryImpl) CreateWebhook(ctx context.Context, hook *models.Webhook) error {
+	if hook.ID == "" {
+		hook.ID = uuid.New().String()
+	}
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO webhooks (`+webhookColumns+`)
+		VALUES (:id, :workspace_id, :url, :secret, :events, :active, :created_by, :created_at)
+	`, hook)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook for workspace %s: %w", hook.WorkspaceID, err)
+	}
+	return nil
+}
+
+// GetWebhook returns one of a workspace's webhooks
+func (r *WebhookRepositoryImpl) GetWebhook(ctx context.Context, workspaceID, id string) (*models.Webhook, error) {
+	var hook models.Webhook
+	err := r.db.GetContext(ctx, &hook, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1 AND workspace_id = $2`, id, workspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("Webhook")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhook %s: %w", id, err)
+	}
+	return &hook, nil
+}
+
+// ListWebhooks returns a workspace's webhooks, newest first
+func (r *WebhookRepositoryImpl) ListWebhooks(ctx context.Context, workspaceID string) ([]*models.Webhook, error) {
+	var hooks []*models.Webhook
+	err := r.db.SelectContext(ctx, &hooks, `
+		SELECT `+webhookColumns+` FROM webhooks WHERE workspace_id = $1 ORDER BY created_at DESC
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks for workspace %s: %w", workspaceID, err)
+	}
+	return hooks, nil
+}
+
+// SetWebhookActive pauses or resumes one of a workspace's webhooks
+func (r *WebhookRepositoryImpl) SetWebhookActive(ctx context.Context, workspaceID, id string, active bool) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE webhooks SET active = $3 WHERE id = $1 AND workspace_id = $2`, id, workspaceID, active)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook %s: %w", id, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return apperrors.NotFound("Webhook")
+	}
+	return nil
+}
+
+// DeleteWebhook removes one of a workspace's webhooks; its deliveries go with it
+func (r *WebhookRepositoryImpl) DeleteWebhook(ctx context.Context, workspaceID, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1 AND workspace_id = $2`, id, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook %s: %w", id, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return apperrors.NotFound("Webhook")
+	}
+	return nil
+}
+
+// CreateDelivery stores a pending delivery, assigning its ID when empty
+func (r *WebhookRepositoryImpl) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.ID == "" {
+		delivery.ID = uuid.New().String()
+	}
+	if delivery.Status == "" {
+		delivery.Status = models.WebhookDeliveryPending
+	}
+	now := time.Now()
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = now
+	}
+	delivery.CreatedAt, delivery.UpdatedAt = now, now
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (id, webhook_id, workspace_id, event, payload, status, attempts, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+	`, delivery.ID, delivery.WebhookID, delivery.WorkspaceID, delivery.Event, []byte(delivery.Payload),
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, now)
+	if err != nil {
+		return fmt.Errorf("failed to queue %s delivery to webhook %s: %w", delivery.Event, delivery.WebhookID, err)
+	}
+	return nil
+}
+
+// ClaimDueDeliveries returns up to limit pending deliveries that are due, leasing them to the
+// caller by pushing their next attempt lease into the future
+func (r *WebhookRepositoryImpl) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	// SKIP LOCKED lets concurrent dispatchers each take different deliveries; the lease keeps
+	// a claimed delivery from being claimed again until its attempt is recorded or abandoned
+	var deliveries []*models.WebhookDelivery
+	err := r.db.SelectContext(ctx, &deliveries, `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond', updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT $1
+		)
+		RETURNING `+webhookDeliveryColumns, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery records the outcome of a delivery attempt
+func (r *WebhookRepositoryImpl) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	delivery.UpdatedAt = time.Now()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, response_status = $4, last_error = $5, next_attempt_at = $6,
+		    delivered_at = $7, updated_at = $8
+		WHERE id = $1
+	`, delivery.ID, delivery.Status, delivery.Attempts, delivery.ResponseStatus, delivery.LastError,
+		delivery.NextAttemptAt, delivery.DeliveredAt, delivery.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery %s: %w", delivery.ID, err)
+	}
+	return nil
+}
+
+// GetDelivery returns one of a workspace's deliveries
+func (r *WebhookRepositoryImpl) GetDelivery(ctx context.Context, workspaceID, id string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.GetContext(ctx, &delivery, `
+		SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE id = $1 AND workspace_id = $2
+	`, id, workspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("Webhook delivery")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhook delivery %s: %w", id, err)
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries returns up to limit of a workspace's deliveries, newest first, optionally
+// only those of one webhook
+func (r *WebhookRepositoryImpl) ListDeliveries(ctx context.Context, workspaceID, webhookID string, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	var err error
+	if webhookID != "" {
+		err = r.db.SelectContext(ctx, &deliveries, `
+			SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries
+			WHERE workspace_id = $1 AND webhook_id = $2 ORDER BY created_at DESC LIMIT $3
+		`, workspaceID, webhookID, limit)
+	} else {
+		err = r.db.SelectContext(ctx, &deliveries, `
+			SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries
+			WHERE workspace_id = $1 ORDER BY created_at DESC LIMIT $2
+		`, workspaceID, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries for workspace %s: %w", workspaceID, err)
+	}
+	return deliveries, nil
+}
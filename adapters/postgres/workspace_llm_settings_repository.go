@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// WorkspaceLLMSettingsRepositoryImpl implements WorkspaceLLMSettingsStore for PostgreSQL
+type WorkspaceLLMSettingsRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewWorkspaceLLMSettingsRepository creates a new PostgreSQL workspace LLM settings store
+func NewWorkspaceLLMSettingsRepository(db *sqlx.DB) ports.WorkspaceLLMSettingsStore {
+	return &WorkspaceLLMSettingsRepositoryImpl{db: db}
+}
+
+// Get returns the workspace's settings, or empty unlocked settings when none are stored
+func (r *WorkspaceLLMSettingsRepositoryImpl) Get(ctx context.Context, workspaceID string) (*models.WorkspaceLLMSettings, error) {
+	var settings models.WorkspaceLLMSettings
+	var provider, model, lockedBy sql.NullString
+	var temperature sql.NullFloat64
+	var lockedAt, updatedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT workspace_id, provider, model, temperature, max_tokens, locked, locked_by, locked_at, updated_at
+		FROM workspace_llm_settings WHERE workspace_id = $1
+	`, workspaceID).Scan(&settings.WorkspaceID, &provider, &model, &temperature, &settings.MaxTokens,
+		&settings.Locked, &lockedBy, &lockedAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.WorkspaceLLMSettings{WorkspaceID: workspaceID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load LLM settings for workspace %s: %w", workspaceID, err)
+	}
+	settings.Provider = provider.String
+	settings.Model = model.String
+	if temperature.Valid {
+		settings.Temperature = &temperature.Float64
+	}
+	settings.LockedBy = lockedBy.String
+	if lockedAt.Valid {
+		settings.LockedAt = &lockedAt.Time
+	}
+	if updatedAt.Valid {
+		settings.UpdatedAt = &updatedAt.Time
+	}
+	return &settings, nil
+}
+
+// Put replaces the workspace's defaults unless an admin has locked them
+func (r *WorkspaceLLMSettingsRepositoryImpl) Put(ctx context.Context, workspaceID string, settings models.LLMSettings) error {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO workspace_llm_settings (workspace_id, provider, model, temperature, max_tokens, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (workspace_id) DO UPDATE SET
+			provider = EXCLUDED.provider, model = EXCLUDED.model, temperature = EXCLUDED.temperature,
+			max_tokens = EXCLUDED.max_tokens, updated_at = NOW()
+		WHERE NOT workspace_llm_settings.locked
+	`, workspaceID, settings.Provider, settings.Model, settings.Temperature, settings.MaxTokens)
+	if err != nil {
+		return fmt.Errorf("failed to save LLM settings for workspace %s: %w", workspaceID, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return apperrors.Forbidden(fmt.Sprintf("LLM settings for workspace %s are locked by an admin", workspaceID))
+	}
+	return nil
+}
+
+// Lock pins the workspace's settings, replacing them first when settings is non-nil
+func (r *WorkspaceLLMSettingsRepositoryImpl) Lock(ctx context.Context, workspaceID string, settings *models.LLMSettings, lockedBy string) error {
+	var err error
+	if settings != nil {
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO workspace_llm_settings (workspace_id, provider, model, temperature, max_tokens, locked, locked_by, locked_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, true, $6, NOW(), NOW())
+			ON CONFLICT (workspace_id) DO UPDATE SET
+				provider = EXCLUDED.provider, model = EXCLUDED.model, temperature = EXCLUDED.temperature,
+				max_tokens = EXCLUDED.max_tokens, locked = true, locked_by = EXCLUDED.locked_by,
+				locked_at = NOW(), updated_at = NOW()
+		`, workspaceID, settings.Provider, settings.Model, settings.Temperature, settings.MaxTokens, lockedBy)
+	} else {
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO workspace_llm_settings (workspace_id, locked, locked_by, locked_at, updated_at)
+			VALUES ($1, true, $2, NOW(), NOW())
+			ON CONFLICT (workspace_id) DO UPDATE SET locked = true, locked_by = EXCLUDED.locked_by, locked_at = NOW()
+		`, workspaceID, lockedBy)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock LLM settings for workspace %s: %w", workspaceID, err)
+	}
+	return nil
+}
+
+// Unlock lets the workspace's settings be edited and overridden again
+func (r *WorkspaceLLMSettingsRepositoryImpl) Unlock(ctx context.Context, workspaceID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE workspace_llm_settings SET locked = false, locked_by = NULL, locked_at = NULL WHERE workspace_id = $1
+	`, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to unlock LLM settings for workspace %s: %w", workspaceID, err)
+	}
+	return nil
+}
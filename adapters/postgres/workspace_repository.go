@@ -14,7 +14,8 @@ import (
 
 // workspaceRepository implements the WorkspaceRepository interface
 type workspaceRepository struct {
-	db *sqlx.DB
+	db                 *sqlx.DB
+	schemaPerWorkspace bool // datasets live in per-workspace schemas
 }
 
 // NewWorkspaceRepository creates a new workspace repository
@@ -22,6 +23,11 @@ func NewWorkspaceRepository(db *sqlx.DB) ports.WorkspaceRepository {
 	return &workspaceRepository{db: db}
 }
 
+// NewSchemaIsolatedWorkspaceRepository creates a workspace repository for schema-per-workspace mode
+func NewSchemaIsolatedWorkspaceRepository(db *sqlx.DB) ports.WorkspaceRepository {
+	return &workspaceRepository{db: db, schemaPerWorkspace: true}
+}
+
 // Create inserts a new workspace into the database
 func (r *workspaceRepository) Create(ctx context.Context, workspace *dataset.Workspace) error {
 	metadataJSON, err := json.Marshal(workspace.Metadata)
@@ -224,6 +230,35 @@ func (r *workspaceRepository) GetDefaultForUser(ctx context.Context, userID core
 	return &workspace, nil
 }
 
+// Reassign hands a workspace and its datasets to another user in one transaction
+func (r *workspaceRepository) Reassign(ctx context.Context, id, userID core.ID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE workspaces SET user_id = $2, is_default = false, updated_at = NOW() WHERE id = $1`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to reassign workspace: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rows == 0 {
+		return fmt.Errorf("workspace not found: %s", id)
+	}
+
+	// Datasets are listed by owner, so they follow the workspace
+	table := "datasets"
+	if r.schemaPerWorkspace {
+		table = WorkspaceSchemaName(id) + ".datasets"
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE `+table+` SET user_id = $2, updated_at = NOW() WHERE workspace_id = $1`, id, userID); err != nil {
+		return fmt.Errorf("failed to reassign datasets: %w", err)
+	}
+	return tx.Commit()
+}
+
 // GetWithDatasets retrieves a workspace with all its datasets and relations
 func (r *workspaceRepository) GetWithDatasets(ctx context.Context, id core.ID) (*ports.WorkspaceWithDatasets, error) {
 	// Get the workspace
@@ -233,8 +268,12 @@ func (r *workspaceRepository) GetWithDatasets(ctx context.Context, id core.ID) (
 	}
 
 	// Get datasets in this workspace
-	datasetRepo := NewDatasetRepository(r.db)
-	datasets, err := datasetRepo.GetByUserID(ctx, workspace.UserID, 1000, 0) // Get all datasets for user
+	var datasets []*dataset.Dataset
+	if r.schemaPerWorkspace {
+		datasets, err = NewWorkspaceDatasetRepository(r.db, id).GetByWorkspace(ctx, id, 1000, 0)
+	} else {
+		datasets, err = NewDatasetRepository(r.db).GetByUserID(ctx, workspace.UserID, 1000, 0) // Get all datasets for user
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get datasets: %w", err)
 	}
@@ -0,0 +1,452 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/ports"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Schema-per-workspace tenancy: each workspace's rows live in their own Postgres
+// schema (ws_<id>). Workspace-scoped repositories qualify every table with that
+// schema and refuse to touch rows that belong to another workspace.
+
+// ErrCrossWorkspaceAccess is returned when a workspace-scoped repository is asked for another workspace's data
+var ErrCrossWorkspaceAccess = errors.New("cross-workspace access forbidden")
+
+// ErrWorkspaceNotProvisioned is returned when a workspace has no schema yet
+var ErrWorkspaceNotProvisioned = errors.New("workspace schema not provisioned")
+
+// workspaceScopedTables are cloned into every workspace schema from their public definitions
+var workspaceScopedTables = []string{"datasets"}
+
+var nonIdentChars = regexp.MustCompile(`[^a-z0-9_]`)
+
+// WorkspaceSchemaName returns the Postgres schema that holds a workspace's data
+func WorkspaceSchemaName(workspaceID core.ID) string {
+	return "ws_" + nonIdentChars.ReplaceAllString(strings.ToLower(string(workspaceID)), "")
+}
+
+// workspaceScopeKey is the context key for the active workspace scope
+type workspaceScopeKey struct{}
+
+// WithWorkspaceScope binds a request to a single workspace; schema-routed repositories
+// reject any access outside it
+func WithWorkspaceScope(ctx context.Context, workspaceID core.ID) context.Context {
+	return context.WithValue(ctx, workspaceScopeKey{}, workspaceID)
+}
+
+// WorkspaceScopeFromContext returns the workspace bound to the request, if any
+func WorkspaceScopeFromContext(ctx context.Context) (core.ID, bool) {
+	id, ok := ctx.Value(workspaceScopeKey{}).(core.ID)
+	return id, ok && id != ""
+}
+
+// ownerScopeKey is the context key for the user a request acts for
+type ownerScopeKey struct{}
+
+// WithOwnerScope binds a request to the user it acts for; schema-routed repositories reject
+// datasets of workspaces another user owns, even when no single workspace is bound
+func WithOwnerScope(ctx context.Context, userID core.ID) context.Context {
+	return context.WithValue(ctx, ownerScopeKey{}, userID)
+}
+
+// OwnerScopeFromContext returns the user bound to the request, if any
+func OwnerScopeFromContext(ctx context.Context) (core.ID, bool) {
+	id, ok := ctx.Value(ownerScopeKey{}).(core.ID)
+	return id, ok && id != ""
+}
+
+// WorkspaceSchemaProvisioner creates and drops per-workspace schemas
+type WorkspaceSchemaProvisioner struct {
+	db *sqlx.DB
+}
+
+// NewWorkspaceSchemaProvisioner creates a new provisioner
+func NewWorkspaceSchemaProvisioner(db *sqlx.DB) *WorkspaceSchemaProvisioner {
+	return &WorkspaceSchemaProvisioner{db: db}
+}
+
+// EnsureIndex creates the public lookup table mapping dataset IDs to their workspace, and the
+// record of workspaces whose public datasets were migrated
+func (p *WorkspaceSchemaProvisioner) EnsureIndex(ctx context.Context) error {
+	query := `CREATE TABLE IF NOT EXISTS workspace_dataset_index (
+		dataset_id UUID PRIMARY KEY,
+		workspace_id UUID NOT NULL,
+		user_id UUID NOT NULL
+	)`
+	if _, err := p.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create workspace dataset index: %w", err)
+	}
+	query = `CREATE TABLE IF NOT EXISTS workspace_dataset_migrations (
+		workspace_id UUID PRIMARY KEY,
+		migrated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`
+	if _, err := p.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create workspace dataset migrations: %w", err)
+	}
+	return nil
+}
+
+// Provision creates the workspace schema and its tables; safe to call repeatedly
+func (p *WorkspaceSchemaProvisioner) Provision(ctx context.Context, workspaceID core.ID) error {
+	if workspaceID == "" {
+		return core.NewValidationError("workspace_id", "cannot be empty")
+	}
+	schema := WorkspaceSchemaName(workspaceID)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin provisioning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var existed bool
+	if err := tx.GetContext(ctx, &existed,
+		`SELECT EXISTS (SELECT 1 FROM information_schema.schemata WHERE schema_name = $1)`, schema); err != nil {
+		return fmt.Errorf("failed to check schema %s: %w", schema, err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", schema)); err != nil {
+		return fmt.Errorf("failed to create schema %s: %w", schema, err)
+	}
+	for _, table := range workspaceScopedTables {
+		// LIKE copies columns, defaults, and indexes but not foreign keys, so the
+		// workspace schema never references rows outside itself
+		query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (LIKE public.%s INCLUDING ALL)", schema, table, table)
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to create %s.%s: %w", schema, table, err)
+		}
+	}
+
+	// Datasets created before the workspace had a schema stay in public.datasets, where the
+	// routed repository never looks. Copy them in and index them; the public rows are kept
+	// because dataset relations still reference them. The copy runs once per workspace, as
+	// recorded in workspace_dataset_migrations, so a dataset deleted later is not copied back
+	// on the next boot or when the schema is provisioned again. A schema that already existed
+	// was migrated when it was created.
+	recorded, err := tx.ExecContext(ctx, `INSERT INTO workspace_dataset_migrations (workspace_id) VALUES ($1)
+		ON CONFLICT (workspace_id) DO NOTHING`, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to record dataset migration: %w", err)
+	}
+	if first, err := recorded.RowsAffected(); err == nil && first == 1 && !existed {
+		if err := migratePublicDatasets(ctx, tx, schema, workspaceID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit provisioning: %w", err)
+	}
+	return nil
+}
+
+// migratePublicDatasets copies a workspace's datasets from the public schema into its own
+// schema and indexes them
+func migratePublicDatasets(ctx context.Context, tx *sqlx.Tx, schema string, workspaceID core.ID) error {
+	copyRows := fmt.Sprintf(`INSERT INTO %s.datasets SELECT * FROM public.datasets WHERE workspace_id = $1
+		ON CONFLICT (id) DO NOTHING`, schema)
+	if _, err := tx.ExecContext(ctx, copyRows, workspaceID); err != nil {
+		return fmt.Errorf("failed to migrate public datasets into %s: %w", schema, err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO workspace_dataset_index (dataset_id, workspace_id, user_id)
+		SELECT id, workspace_id, user_id FROM public.datasets WHERE workspace_id = $1
+		ON CONFLICT (dataset_id) DO NOTHING`, workspaceID); err != nil {
+		return fmt.Errorf("failed to index migrated datasets: %w", err)
+	}
+	return nil
+}
+
+// ProvisionAll provisions the schema of every workspace, migrating the datasets each kept in
+// the public schema, and returns how many workspaces it provisioned. It runs at startup so a
+// deployment switched to schema tenancy keeps its existing data.
+func (p *WorkspaceSchemaProvisioner) ProvisionAll(ctx context.Context) (int, error) {
+	var workspaceIDs []core.ID
+	if err := p.db.SelectContext(ctx, &workspaceIDs, `SELECT id FROM workspaces`); err != nil {
+		return 0, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	for _, workspaceID := range workspaceIDs {
+		if err := p.Provision(ctx, workspaceID); err != nil {
+			return 0, err
+		}
+	}
+	return len(workspaceIDs), nil
+}
+
+// Deprovision drops the workspace schema and all data in it
+func (p *WorkspaceSchemaProvisioner) Deprovision(ctx context.Context, workspaceID core.ID) error {
+	if workspaceID == "" {
+		return core.NewValidationError("workspace_id", "cannot be empty")
+	}
+	schema := WorkspaceSchemaName(workspaceID)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin deprovisioning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schema)); err != nil {
+		return fmt.Errorf("failed to drop schema %s: %w", schema, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM workspace_dataset_index WHERE workspace_id = $1`, workspaceID); err != nil {
+		return fmt.Errorf("failed to clear dataset index: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// IsProvisioned reports whether the workspace schema exists
+func (p *WorkspaceSchemaProvisioner) IsProvisioned(ctx context.Context, workspaceID core.ID) (bool, error) {
+	var exists bool
+	err := p.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM information_schema.schemata WHERE schema_name = $1)`,
+		WorkspaceSchemaName(workspaceID)).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check schema: %w", err)
+	}
+	return exists, nil
+}
+
+// workspaceDatasetRepository is a dataset repository bound to a single workspace schema
+type workspaceDatasetRepository struct {
+	*datasetRepository
+	workspaceID core.ID
+}
+
+// NewWorkspaceDatasetRepository creates a dataset repository that only sees one workspace's schema
+func NewWorkspaceDatasetRepository(db *sqlx.DB, workspaceID core.ID) ports.DatasetRepository {
+	return &workspaceDatasetRepository{
+		datasetRepository: &datasetRepository{db: db, table: WorkspaceSchemaName(workspaceID) + ".datasets"},
+		workspaceID:       workspaceID,
+	}
+}
+
+func (r *workspaceDatasetRepository) guard(workspaceID core.ID) error {
+	if workspaceID != r.workspaceID {
+		return fmt.Errorf("%w: repository bound to %s, got %s", ErrCrossWorkspaceAccess, r.workspaceID, workspaceID)
+	}
+	return nil
+}
+
+// Create inserts a dataset, rejecting rows for other workspaces
+func (r *workspaceDatasetRepository) Create(ctx context.Context, ds *dataset.Dataset) error {
+	if err := r.guard(ds.WorkspaceID); err != nil {
+		return err
+	}
+	return r.datasetRepository.Create(ctx, ds)
+}
+
+// Update modifies a dataset, rejecting rows for other workspaces
+func (r *workspaceDatasetRepository) Update(ctx context.Context, ds *dataset.Dataset) error {
+	if ds.WorkspaceID != "" {
+		if err := r.guard(ds.WorkspaceID); err != nil {
+			return err
+		}
+	}
+	return r.datasetRepository.Update(ctx, ds)
+}
+
+// GetByWorkspace lists datasets, rejecting requests for other workspaces
+func (r *workspaceDatasetRepository) GetByWorkspace(ctx context.Context, workspaceID core.ID, limit, offset int) ([]*dataset.Dataset, error) {
+	if err := r.guard(workspaceID); err != nil {
+		return nil, err
+	}
+	return r.datasetRepository.GetByWorkspace(ctx, workspaceID, limit, offset)
+}
+
+// datasetIndex resolves which workspace, and which of its owner's, a dataset belongs to
+type datasetIndex interface {
+	lookup(ctx context.Context, id core.ID) (workspaceID, userID core.ID, err error)
+}
+
+// dbDatasetIndex reads workspace_dataset_index
+type dbDatasetIndex struct {
+	db *sqlx.DB
+}
+
+func (i dbDatasetIndex) lookup(ctx context.Context, id core.ID) (core.ID, core.ID, error) {
+	var workspaceID, userID core.ID
+	err := i.db.QueryRowContext(ctx, `SELECT workspace_id, user_id FROM workspace_dataset_index WHERE dataset_id = $1`, id).
+		Scan(&workspaceID, &userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", "", fmt.Errorf("dataset not found: %s", id)
+		}
+		return "", "", fmt.Errorf("failed to resolve dataset workspace: %w", err)
+	}
+	return workspaceID, userID, nil
+}
+
+// schemaRoutedDatasetRepository dispatches each call to the owning workspace's schema
+type schemaRoutedDatasetRepository struct {
+	db     *sqlx.DB
+	public ports.DatasetRepository
+	index  datasetIndex
+}
+
+// NewSchemaRoutedDatasetRepository creates the dataset repository used in schema-per-workspace mode.
+// Calls are routed by the dataset's workspace (looked up in workspace_dataset_index when only an
+// ID is known); a workspace bound via WithWorkspaceScope can never reach another workspace's rows,
+// and a user bound via WithOwnerScope never reaches the rows of a workspace they do not own.
+func NewSchemaRoutedDatasetRepository(db *sqlx.DB) ports.DatasetRepository {
+	return &schemaRoutedDatasetRepository{db: db, public: NewDatasetRepository(db), index: dbDatasetIndex{db: db}}
+}
+
+// scoped returns the repository for a workspace after checking it against the request scope
+func (r *schemaRoutedDatasetRepository) scoped(ctx context.Context, workspaceID core.ID) (ports.DatasetRepository, error) {
+	if scope, ok := WorkspaceScopeFromContext(ctx); ok && scope != workspaceID {
+		return nil, fmt.Errorf("%w: request scoped to %s, target %s", ErrCrossWorkspaceAccess, scope, workspaceID)
+	}
+	return NewWorkspaceDatasetRepository(r.db, workspaceID), nil
+}
+
+// byDatasetID returns the repository of the workspace owning a dataset, after checking the
+// workspace against the request scope and its owner against the request's user
+func (r *schemaRoutedDatasetRepository) byDatasetID(ctx context.Context, id core.ID) (ports.DatasetRepository, error) {
+	workspaceID, userID, err := r.index.lookup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if owner, ok := OwnerScopeFromContext(ctx); ok && owner != userID {
+		return nil, fmt.Errorf("%w: dataset %s belongs to another user", ErrCrossWorkspaceAccess, id)
+	}
+	return r.scoped(ctx, workspaceID)
+}
+
+// Create inserts into the workspace schema and records the dataset in the index
+func (r *schemaRoutedDatasetRepository) Create(ctx context.Context, ds *dataset.Dataset) error {
+	if ds.WorkspaceID == "" {
+		return core.NewValidationError("workspace_id", "required in schema-per-workspace mode")
+	}
+	repo, err := r.scoped(ctx, ds.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if err := repo.Create(ctx, ds); err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO workspace_dataset_index (dataset_id, workspace_id, user_id) VALUES ($1, $2, $3)
+		 ON CONFLICT (dataset_id) DO UPDATE SET workspace_id = EXCLUDED.workspace_id`,
+		ds.ID, ds.WorkspaceID, ds.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to index dataset: %w", err)
+	}
+	return nil
+}
+
+// GetByID reads from the owning workspace's schema
+func (r *schemaRoutedDatasetRepository) GetByID(ctx context.Context, id core.ID) (*dataset.Dataset, error) {
+	repo, err := r.byDatasetID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetByID(ctx, id)
+}
+
+// GetByUserID aggregates the user's datasets across the workspaces they own
+func (r *schemaRoutedDatasetRepository) GetByUserID(ctx context.Context, userID core.ID, limit, offset int) ([]*dataset.Dataset, error) {
+	if owner, ok := OwnerScopeFromContext(ctx); ok && owner != userID {
+		return nil, fmt.Errorf("%w: request acts for %s, target %s", ErrCrossWorkspaceAccess, owner, userID)
+	}
+	if scope, ok := WorkspaceScopeFromContext(ctx); ok {
+		return NewWorkspaceDatasetRepository(r.db, scope).GetByUserID(ctx, userID, limit, offset)
+	}
+
+	var workspaceIDs []core.ID
+	if err := r.db.SelectContext(ctx, &workspaceIDs,
+		`SELECT DISTINCT workspace_id FROM workspace_dataset_index WHERE user_id = $1`, userID); err != nil {
+		return nil, fmt.Errorf("failed to list user workspaces: %w", err)
+	}
+
+	var all []*dataset.Dataset
+	for _, workspaceID := range workspaceIDs {
+		datasets, err := NewWorkspaceDatasetRepository(r.db, workspaceID).GetByUserID(ctx, userID, limit+offset, 0)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, datasets...)
+	}
+	if offset >= len(all) {
+		return nil, nil
+	}
+	all = all[offset:]
+	if limit > 0 && len(all) > limit {
+		all = all[:limit]
+	}
+	return all, nil
+}
+
+// GetByWorkspace reads from the requested workspace's schema
+func (r *schemaRoutedDatasetRepository) GetByWorkspace(ctx context.Context, workspaceID core.ID, limit, offset int) ([]*dataset.Dataset, error) {
+	repo, err := r.scoped(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetByWorkspace(ctx, workspaceID, limit, offset)
+}
+
+// Update writes to the dataset's workspace schema
+func (r *schemaRoutedDatasetRepository) Update(ctx context.Context, ds *dataset.Dataset) error {
+	repo, err := r.byDatasetID(ctx, ds.ID)
+	if err != nil {
+		return err
+	}
+	return repo.Update(ctx, ds)
+}
+
+// Delete removes the dataset from its workspace schema and the index
+func (r *schemaRoutedDatasetRepository) Delete(ctx context.Context, id core.ID) error {
+	repo, err := r.byDatasetID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM workspace_dataset_index WHERE dataset_id = $1`, id); err != nil {
+		return fmt.Errorf("failed to remove dataset from index: %w", err)
+	}
+	return nil
+}
+
+// GetCurrent reads the legacy "current" dataset from the public schema
+func (r *schemaRoutedDatasetRepository) GetCurrent(ctx context.Context) (*dataset.Dataset, error) {
+	return r.public.GetCurrent(ctx)
+}
+
+// ListByStatus requires a workspace scope; unscoped listings would span tenants
+func (r *schemaRoutedDatasetRepository) ListByStatus(ctx context.Context, status dataset.DatasetStatus) ([]*dataset.Dataset, error) {
+	scope, ok := WorkspaceScopeFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: listing by status requires a workspace scope", ErrCrossWorkspaceAccess)
+	}
+	return NewWorkspaceDatasetRepository(r.db, scope).ListByStatus(ctx, status)
+}
+
+// ListByDomain requires a workspace scope; unscoped listings would span tenants
+func (r *schemaRoutedDatasetRepository) ListByDomain(ctx context.Context, domain string) ([]*dataset.Dataset, error) {
+	scope, ok := WorkspaceScopeFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: listing by domain requires a workspace scope", ErrCrossWorkspaceAccess)
+	}
+	return NewWorkspaceDatasetRepository(r.db, scope).ListByDomain(ctx, domain)
+}
+
+// UpdateStatus writes to the dataset's workspace schema
+func (r *schemaRoutedDatasetRepository) UpdateStatus(ctx context.Context, id core.ID, status dataset.DatasetStatus, errorMsg string) error {
+	repo, err := r.byDatasetID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return repo.UpdateStatus(ctx, id, status, errorMsg)
+}
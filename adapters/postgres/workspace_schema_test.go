@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+)
+
+// fakeDatasetIndex maps dataset IDs to their workspace and owner without a database
+type fakeDatasetIndex map[core.ID][2]core.ID
+
+func (i fakeDatasetIndex) lookup(_ context.Context, id core.ID) (core.ID, core.ID, error) {
+	entry, ok := i[id]
+	if !ok {
+		return "", "", fmt.Errorf("dataset not found: %s", id)
+	}
+	return entry[0], entry[1], nil
+}
+
+// TestSchemaRoutedDatasetIsolation verifies a request bound to a workspace or a user is
+// refused datasets of other tenants before any query runs, and routed to the owning
+// workspace's schema otherwise
+func TestSchemaRoutedDatasetIsolation(t *testing.T) {
+	repo := &schemaRoutedDatasetRepository{index: fakeDatasetIndex{
+		"ds-a": {"ws-a", "user-a"},
+		"ds-b": {"ws-b", "user-b"},
+	}}
+	ctx := context.Background()
+
+	routed, err := repo.byDatasetID(ctx, "ds-b")
+	if err != nil {
+		t.Fatalf("expected an unscoped lookup to route, got %v", err)
+	}
+	if table := routed.(*workspaceDatasetRepository).table; table != "ws_wsb.datasets" {
+		t.Errorf("expected ds-b routed to its workspace schema, got %s", table)
+	}
+
+	cases := map[string]context.Context{
+		"other workspace": WithWorkspaceScope(ctx, "ws-a"),
+		"other owner":     WithOwnerScope(ctx, "user-a"),
+		"both":            WithOwnerScope(WithWorkspaceScope(ctx, "ws-a"), "user-a"),
+	}
+	for name, scoped := range cases {
+		if _, err := repo.GetByID(scoped, "ds-b"); !errors.Is(err, ErrCrossWorkspaceAccess) {
+			t.Errorf("%s: expected cross-workspace access to be refused, got %v", name, err)
+		}
+		if err := repo.Delete(scoped, "ds-b"); !errors.Is(err, ErrCrossWorkspaceAccess) {
+			t.Errorf("%s: expected a cross-workspace delete to be refused, got %v", name, err)
+		}
+	}
+	if _, err := repo.byDatasetID(WithOwnerScope(WithWorkspaceScope(ctx, "ws-a"), "user-a"), "ds-a"); err != nil {
+		t.Errorf("expected a request's own dataset to route, got %v", err)
+	}
+
+	if _, err := repo.GetByWorkspace(WithWorkspaceScope(ctx, "ws-a"), "ws-b", 10, 0); !errors.Is(err, ErrCrossWorkspaceAccess) {
+		t.Errorf("expected listing another workspace to be refused, got %v", err)
+	}
+	if _, err := repo.GetByUserID(WithOwnerScope(ctx, "user-a"), "user-b", 10, 0); !errors.Is(err, ErrCrossWorkspaceAccess) {
+		t.Errorf("expected listing another user's datasets to be refused, got %v", err)
+	}
+	if _, err := repo.ListByStatus(ctx, dataset.StatusReady); !errors.Is(err, ErrCrossWorkspaceAccess) {
+		t.Errorf("expected an unscoped listing to be refused, got %v", err)
+	}
+	if err := repo.Create(WithWorkspaceScope(ctx, "ws-a"), &dataset.Dataset{ID: "ds-c", WorkspaceID: "ws-b"}); !errors.Is(err, ErrCrossWorkspaceAccess) {
+		t.Errorf("expected creating in another workspace to be refused, got %v", err)
+	}
+
+	bound := NewWorkspaceDatasetRepository(nil, "ws-a")
+	if err := bound.Create(ctx, &dataset.Dataset{ID: "ds-c", WorkspaceID: "ws-b"}); !errors.Is(err, ErrCrossWorkspaceAccess) {
+		t.Errorf("expected a workspace repository to refuse another workspace's row, got %v", err)
+	}
+}
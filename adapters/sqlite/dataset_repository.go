@@ -0,0 +1,199 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/ports"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// currentDatasetID is the special "current" Excel dataset
+const currentDatasetID = "550e8400-e29b-41d4-a716-446655440000"
+
+// datasetColumns are the dataset columns in the order scanDataset reads them
+const datasetColumns = `id, user_id, COALESCE(workspace_id, ''), original_filename, COALESCE(file_path, ''), COALESCE(file_size, 0), COALESCE(mime_type, ''),
+	COALESCE(display_name, ''), COALESCE(domain, ''), COALESCE(description, ''), COALESCE(record_count, 0), COALESCE(field_count, 0), COALESCE(missing_rate, 0.0),
+	COALESCE(source, ''), COALESCE(status, ''), COALESCE(error_message, ''), metadata, created_at, updated_at`
+
+// datasetRepository implements the DatasetRepository interface
+type datasetRepository struct {
+	db *sqlx.DB
+}
+
+// NewDatasetRepository creates a new SQLite dataset repository
+func NewDatasetRepository(db *sqlx.DB) ports.DatasetRepository {
+	return &datasetRepository{db: db}
+}
+
+// Create inserts a new dataset into the database
+func (r *datasetRepository) Create(ctx context.Context, ds *dataset.Dataset) error {
+	metadataJSON, err := json.Marshal(ds.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `INSERT INTO datasets (
+		id, user_id, workspace_id, original_filename, file_path, file_size, mime_type,
+		display_name, domain, description, record_count, field_count, missing_rate,
+		source, status, error_message, metadata, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		ds.ID, ds.UserID, ds.WorkspaceID, ds.OriginalFilename, ds.FilePath, ds.FileSize, ds.MimeType,
+		ds.DisplayName, ds.Domain, ds.Description, ds.RecordCount, ds.FieldCount, ds.MissingRate,
+		ds.Source, ds.Status, ds.ErrorMessage, string(metadataJSON), ds.CreatedAt, ds.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create dataset: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a dataset by its ID
+func (r *datasetRepository) GetByID(ctx context.Context, id core.ID) (*dataset.Dataset, error) {
+	ds, err := scanDataset(r.db.QueryRowContext(ctx, `SELECT `+datasetColumns+` FROM datasets WHERE id = ?`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("dataset not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get dataset: %w", err)
+	}
+	return ds, nil
+}
+
+// GetByUserID retrieves datasets for a specific user with pagination
+func (r *datasetRepository) GetByUserID(ctx context.Context, userID core.ID, limit, offset int) ([]*dataset.Dataset, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+datasetColumns+` FROM datasets
+	WHERE user_id = ?
+	ORDER BY created_at DESC
+	LIMIT ? OFFSET ?`, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query datasets: %w", err)
+	}
+	defer rows.Close()
+	return scanDatasets(rows)
+}
+
+// GetByWorkspace retrieves datasets for a specific workspace
+func (r *datasetRepository) GetByWorkspace(ctx context.Context, workspaceID core.ID, limit, offset int) ([]*dataset.Dataset, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+datasetColumns+` FROM datasets
+	WHERE workspace_id = ?
+	ORDER BY created_at DESC
+	LIMIT ? OFFSET ?`, workspaceID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query datasets by workspace: %w", err)
+	}
+	defer rows.Close()
+	return scanDatasets(rows)
+}
+
+// Update modifies an existing dataset
+func (r *datasetRepository) Update(ctx context.Context, ds *dataset.Dataset) error {
+	metadataJSON, err := json.Marshal(ds.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `UPDATE datasets SET
+		original_filename = ?, file_path = ?, file_size = ?, mime_type = ?,
+		display_name = ?, domain = ?, description = ?, record_count = ?,
+		field_count = ?, missing_rate = ?, source = ?, status = ?,
+		error_message = ?, metadata = ?, updated_at = ?
+	WHERE id = ?`,
+		ds.OriginalFilename, ds.FilePath, ds.FileSize, ds.MimeType,
+		ds.DisplayName, ds.Domain, ds.Description, ds.RecordCount, ds.FieldCount,
+		ds.MissingRate, ds.Source, ds.Status, ds.ErrorMessage, string(metadataJSON), ds.UpdatedAt,
+		ds.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update dataset: %w", err)
+	}
+	return expectRow(result, "dataset", ds.ID)
+}
+
+// Delete removes a dataset from the database
+func (r *datasetRepository) Delete(ctx context.Context, id core.ID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM datasets WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete dataset: %w", err)
+	}
+	return expectRow(result, "dataset", id)
+}
+
+// GetCurrent retrieves the special "current" Excel dataset
+func (r *datasetRepository) GetCurrent(ctx context.Context) (*dataset.Dataset, error) {
+	ds, err := scanDataset(r.db.QueryRowContext(ctx, `SELECT `+datasetColumns+` FROM datasets WHERE id = ?`, currentDatasetID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("current dataset not found")
+		}
+		return nil, fmt.Errorf("failed to get current dataset: %w", err)
+	}
+	return ds, nil
+}
+
+// ListByStatus retrieves datasets by processing status
+func (r *datasetRepository) ListByStatus(ctx context.Context, status dataset.DatasetStatus) ([]*dataset.Dataset, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+datasetColumns+` FROM datasets WHERE status = ? ORDER BY created_at DESC`, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query datasets by status: %w", err)
+	}
+	defer rows.Close()
+	return scanDatasets(rows)
+}
+
+// ListByDomain retrieves datasets by business domain
+func (r *datasetRepository) ListByDomain(ctx context.Context, domain string) ([]*dataset.Dataset, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+datasetColumns+` FROM datasets WHERE domain = ? ORDER BY created_at DESC`, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query datasets by domain: %w", err)
+	}
+	defer rows.Close()
+	return scanDatasets(rows)
+}
+
+// UpdateStatus updates only the status and error message of a dataset
+func (r *datasetRepository) UpdateStatus(ctx context.Context, id core.ID, status dataset.DatasetStatus, errorMsg string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE datasets SET status = ?, error_message = ?, updated_at = ? WHERE id = ?`,
+		status, errorMsg, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update dataset status: %w", err)
+	}
+	return expectRow(result, "dataset", id)
+}
+
+// scanDatasets reads every row selected with datasetColumns
+func scanDatasets(rows *sql.Rows) ([]*dataset.Dataset, error) {
+	var datasets []*dataset.Dataset
+	for rows.Next() {
+		ds, err := scanDataset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dataset: %w", err)
+		}
+		datasets = append(datasets, ds)
+	}
+	return datasets, rows.Err()
+}
+
+// scanDataset reads a row selected with datasetColumns
+func scanDataset(row rowScanner) (*dataset.Dataset, error) {
+	var ds dataset.Dataset
+	var metadataJSON sql.NullString
+	err := row.Scan(
+		&ds.ID, &ds.UserID, &ds.WorkspaceID, &ds.OriginalFilename, &ds.FilePath, &ds.FileSize, &ds.MimeType,
+		&ds.DisplayName, &ds.Domain, &ds.Description, &ds.RecordCount, &ds.FieldCount, &ds.MissingRate,
+		&ds.Source, &ds.Status, &ds.ErrorMessage, &metadataJSON, &ds.CreatedAt, &ds.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := unmarshalColumn(metadataJSON, &ds.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	return &ds, nil
+}
@@ -0,0 +1,87 @@
+// Package sqlite stores datasets, workspaces, hypotheses and the artifact ledger in a
+// single SQLite file, so the server can run without PostgreSQL. It implements the same
+// ports as the postgres adapters; the schema comes from migration.NewSQLiteRunner.
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	sqlite3 "github.com/mattn/go-sqlite3"
+)
+
+// DriverName is the database/sql driver SQLite databases are opened with
+const DriverName = "sqlite3"
+
+// busyTimeout is how long a write waits for another process holding the database lock
+const busyTimeout = 5 * time.Second
+
+// IsURL reports whether a DATABASE_URL names a SQLite database, as in sqlite://./gohypo.db,
+// sqlite:///var/lib/gohypo/gohypo.db or sqlite::memory:
+func IsURL(url string) bool {
+	return strings.HasPrefix(url, "sqlite:")
+}
+
+// IsDB reports whether db was opened by Open
+func IsDB(db *sqlx.DB) bool {
+	return db != nil && db.DriverName() == DriverName
+}
+
+// Open opens the SQLite database a URL names, creating the file and its directory when
+// missing. Foreign keys are enforced and the journal is write-ahead. The pool holds one
+// connection, which serializes writers and keeps an in-memory database alive.
+func Open(ctx context.Context, url string) (*sqlx.DB, error) {
+	path, err := pathFromURL(url)
+	if err != nil {
+		return nil, err
+	}
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=%d", path, busyTimeout.Milliseconds())
+	if path != ":memory:" {
+		if dir := filepath.Dir(path); dir != "." {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return nil, fmt.Errorf("failed to create database directory: %w", err)
+			}
+		}
+		dsn += "&_journal_mode=WAL"
+	}
+
+	db, err := sqlx.Open(DriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open SQLite database %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// pathFromURL extracts the file path from a sqlite: URL
+func pathFromURL(url string) (string, error) {
+	if !IsURL(url) {
+		return "", fmt.Errorf("not a SQLite URL: %s", url)
+	}
+	path := strings.TrimPrefix(strings.TrimPrefix(url, "sqlite:"), "//")
+	if path == "" {
+		return "", fmt.Errorf("SQLite URL names no file: %s", url)
+	}
+	return path, nil
+}
+
+// parseTime reads a time SQLite returned as text, as aggregates over TIMESTAMP columns do
+func parseTime(value string) (time.Time, error) {
+	for _, layout := range sqlite3.SQLiteTimestampFormats {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized SQLite time %q", value)
+}
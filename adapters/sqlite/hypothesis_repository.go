@@ -0,0 +1,225 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// hypothesisColumns are the hypothesis columns in the order scanHypothesis reads them
+const hypothesisColumns = `id, session_id, COALESCE(workspace_id, ''), business_hypothesis, science_hypothesis, COALESCE(null_case, ''),
+	COALESCE(explanation_markdown, ''), referee_results, passed, validation_timestamp,
+	COALESCE(standards_version, ''), execution_metadata, created_at,
+	phase_e_values, COALESCE(feasibility_score, 0), COALESCE(risk_level, ''), data_topology,
+	COALESCE(current_e_value, 0), COALESCE(normalized_e_value, 0), COALESCE(confidence, 0), COALESCE(status, ''),
+	COALESCE(evidence_sid, 0), COALESCE(hypothesis_sid, 0)`
+
+// hypothesisRepository implements HypothesisRepository for SQLite
+type hypothesisRepository struct {
+	db *sqlx.DB
+}
+
+// NewHypothesisRepository creates a new SQLite hypothesis repository
+func NewHypothesisRepository(db *sqlx.DB) ports.HypothesisRepository {
+	return &hypothesisRepository{db: db}
+}
+
+// NewValidatedEvidenceRepository creates the validated evidence source ledger compaction
+// consults, reading the same table as the hypothesis repository
+func NewValidatedEvidenceRepository(db *sqlx.DB) ports.ValidatedEvidenceSource {
+	return &hypothesisRepository{db: db}
+}
+
+// SaveHypothesis saves a hypothesis result for a user and session
+func (r *hypothesisRepository) SaveHypothesis(ctx context.Context, userID, sessionID uuid.UUID, result *models.HypothesisResult) error {
+	refereeResultsJSON, _ := json.Marshal(result.RefereeResults)
+	executionMetadataJSON, _ := json.Marshal(result.ExecutionMetadata)
+	dataTopologyJSON, _ := json.Marshal(result.DataTopology)
+	explanationMarkdownJSON, _ := json.Marshal(result.ExplanationMarkdown)
+	phaseEValues := result.PhaseEValues
+	if phaseEValues == nil {
+		phaseEValues = []float64{}
+	}
+	phaseEValuesJSON, _ := json.Marshal(phaseEValues)
+
+	// Only a valid workspace ID is stored, as in PostgreSQL's UUID column
+	var workspaceID *string
+	if parsed, err := uuid.Parse(result.WorkspaceID); err == nil {
+		id := parsed.String()
+		workspaceID = &id
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO hypothesis_results (
+			id, session_id, user_id, workspace_id, business_hypothesis, science_hypothesis, null_case, explanation_markdown,
+			referee_results, passed, validation_timestamp,
+			standards_version, execution_metadata, created_at,
+			phase_e_values, feasibility_score, risk_level, data_topology,
+			current_e_value, normalized_e_value, confidence, status,
+			evidence_sid, hypothesis_sid
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			workspace_id = excluded.workspace_id,
+			explanation_markdown = COALESCE(excluded.explanation_markdown, hypothesis_results.explanation_markdown),
+			referee_results = excluded.referee_results,
+			passed = excluded.passed,
+			validation_timestamp = excluded.validation_timestamp,
+			standards_version = excluded.standards_version,
+			execution_metadata = excluded.execution_metadata,
+			phase_e_values = excluded.phase_e_values,
+			feasibility_score = excluded.feasibility_score,
+			risk_level = excluded.risk_level,
+			data_topology = excluded.data_topology,
+			current_e_value = excluded.current_e_value,
+			normalized_e_value = excluded.normalized_e_value,
+			confidence = excluded.confidence,
+			status = excluded.status,
+			evidence_sid = excluded.evidence_sid,
+			hypothesis_sid = excluded.hypothesis_sid`,
+		result.ID, sessionID, userID, workspaceID, result.BusinessHypothesis, result.ScienceHypothesis,
+		result.NullCase, string(explanationMarkdownJSON), string(refereeResultsJSON), result.Passed,
+		result.ValidationTimestamp, result.StandardsVersion, string(executionMetadataJSON), time.Now(),
+		string(phaseEValuesJSON), result.FeasibilityScore, result.RiskLevel, string(dataTopologyJSON),
+		result.CurrentEValue, result.NormalizedEValue, result.Confidence, result.Status,
+		result.EvidenceSID, result.HypothesisSID)
+	return err
+}
+
+// GetHypothesis retrieves a hypothesis by user ID and hypothesis ID
+func (r *hypothesisRepository) GetHypothesis(ctx context.Context, userID uuid.UUID, hypothesisID string) (*models.HypothesisResult, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+hypothesisColumns+` FROM hypothesis_results WHERE user_id = ? AND id = ?`, userID, hypothesisID)
+	return scanHypothesis(row)
+}
+
+// ListUserHypotheses returns hypotheses for a user, newest first, optionally limited
+func (r *hypothesisRepository) ListUserHypotheses(ctx context.Context, userID uuid.UUID, limit int) ([]*models.HypothesisResult, error) {
+	return r.list(ctx, `user_id = ?`, `created_at DESC`, limit, userID)
+}
+
+// ListSessionHypotheses returns all hypotheses for a specific session, oldest first
+func (r *hypothesisRepository) ListSessionHypotheses(ctx context.Context, userID, sessionID uuid.UUID) ([]*models.HypothesisResult, error) {
+	return r.list(ctx, `user_id = ? AND session_id = ?`, `created_at ASC`, 0, userID, sessionID)
+}
+
+// ListByValidationState returns hypotheses filtered by validation state
+func (r *hypothesisRepository) ListByValidationState(ctx context.Context, userID uuid.UUID, validated bool, limit int) ([]*models.HypothesisResult, error) {
+	return r.list(ctx, `user_id = ? AND passed = ?`, `created_at DESC`, limit, userID, validated)
+}
+
+// ListByWorkspace returns hypotheses for a specific workspace
+func (r *hypothesisRepository) ListByWorkspace(ctx context.Context, userID uuid.UUID, workspaceID string, limit int) ([]*models.HypothesisResult, error) {
+	return r.list(ctx, `user_id = ? AND workspace_id = ?`, `created_at DESC`, limit, userID, workspaceID)
+}
+
+// GetUserStats returns statistics about a user's hypotheses
+func (r *hypothesisRepository) GetUserStats(ctx context.Context, userID uuid.UUID) (*models.UserHypothesisStats, error) {
+	var stats models.UserHypothesisStats
+	var earliest, latest sql.NullString
+
+	// MIN and MAX lose the column type, so the times come back as text
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(CASE WHEN passed THEN 1 END),
+			COUNT(CASE WHEN NOT passed THEN 1 END),
+			MIN(created_at),
+			MAX(created_at)
+		FROM hypothesis_results
+		WHERE user_id = ?
+	`, userID).Scan(&stats.TotalHypotheses, &stats.ValidatedCount, &stats.RejectedCount, &earliest, &latest)
+	if err != nil {
+		return nil, err
+	}
+
+	if stats.TotalHypotheses > 0 {
+		stats.ValidationRate = float64(stats.ValidatedCount) / float64(stats.TotalHypotheses) * 100
+	}
+	for _, bound := range []struct {
+		value sql.NullString
+		dest  **time.Time
+	}{{earliest, &stats.EarliestHypothesis}, {latest, &stats.LatestHypothesis}} {
+		if !bound.value.Valid {
+			continue
+		}
+		t, err := parseTime(bound.value.String)
+		if err != nil {
+			return nil, err
+		}
+		*bound.dest = &t
+	}
+	return &stats, nil
+}
+
+// ValidatedSessions returns the sessions with at least one hypothesis that passed validation
+func (r *hypothesisRepository) ValidatedSessions(ctx context.Context) ([]string, error) {
+	sessionIDs := []string{}
+	if err := r.db.SelectContext(ctx, &sessionIDs, `SELECT DISTINCT session_id FROM hypothesis_results WHERE passed`); err != nil {
+		return nil, fmt.Errorf("failed to list validated sessions: %w", err)
+	}
+	return sessionIDs, nil
+}
+
+// list selects the hypotheses matching where in order, up to limit when positive
+func (r *hypothesisRepository) list(ctx context.Context, where, order string, limit int, args ...interface{}) ([]*models.HypothesisResult, error) {
+	query := `SELECT ` + hypothesisColumns + ` FROM hypothesis_results WHERE ` + where + ` ORDER BY ` + order
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*models.HypothesisResult
+	for rows.Next() {
+		result, err := scanHypothesis(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// scanHypothesis reads a row selected with hypothesisColumns
+func scanHypothesis(row rowScanner) (*models.HypothesisResult, error) {
+	var result models.HypothesisResult
+	var refereeResultsJSON, executionMetadataJSON, dataTopologyJSON, phaseEValuesJSON sql.NullString
+	var explanationMarkdownJSON string
+	var validationTimestamp sql.NullTime
+
+	err := row.Scan(
+		&result.ID, &result.SessionID, &result.WorkspaceID, &result.BusinessHypothesis, &result.ScienceHypothesis, &result.NullCase,
+		&explanationMarkdownJSON, &refereeResultsJSON, &result.Passed, &validationTimestamp,
+		&result.StandardsVersion, &executionMetadataJSON, &result.CreatedAt,
+		&phaseEValuesJSON, &result.FeasibilityScore, &result.RiskLevel, &dataTopologyJSON,
+		&result.CurrentEValue, &result.NormalizedEValue, &result.Confidence, &result.Status,
+		&result.EvidenceSID, &result.HypothesisSID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	result.ValidationTimestamp = validationTimestamp.Time
+
+	if err := unmarshalColumn(phaseEValuesJSON, &result.PhaseEValues); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal phase_e_values: %w", err)
+	}
+	unmarshalColumn(refereeResultsJSON, &result.RefereeResults)
+	unmarshalColumn(executionMetadataJSON, &result.ExecutionMetadata)
+	unmarshalColumn(dataTopologyJSON, &result.DataTopology)
+	// The explanation is stored JSON-encoded, as in PostgreSQL
+	if explanationMarkdownJSON != "" {
+		json.Unmarshal([]byte(explanationMarkdownJSON), &result.ExplanationMarkdown)
+	}
+	return &result, nil
+}
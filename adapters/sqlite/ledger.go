@@ -0,0 +1,194 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gohypo/domain/core"
+	"gohypo/domain/run"
+	"gohypo/ports"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Ledger keeps the artifact ledger in SQLite, so artifacts survive restarts. Artifacts are
+// stored as JSON, so payloads read back as generic values, as they do from the archive.
+type Ledger struct {
+	db *sqlx.DB
+}
+
+// NewLedger creates a SQLite artifact ledger
+func NewLedger(db *sqlx.DB) *Ledger {
+	return &Ledger{db: db}
+}
+
+var _ ports.LedgerCompactionPort = (*Ledger)(nil)
+
+// StoreArtifact records an artifact under a run; storing an ID again replaces the artifact
+func (l *Ledger) StoreArtifact(ctx context.Context, runID string, artifact core.Artifact) error {
+	body, err := json.Marshal(core.StampSchemaVersion(artifact))
+	if err != nil {
+		return fmt.Errorf("failed to marshal artifact %s: %w", artifact.ID, err)
+	}
+
+	tx, err := l.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO ledger_artifacts (id, kind, body) VALUES (?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET kind = excluded.kind, body = excluded.body`,
+		artifact.ID, artifact.Kind, string(body)); err != nil {
+		return fmt.Errorf("failed to store artifact %s: %w", artifact.ID, err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO ledger_run_artifacts (run_id, artifact_id) VALUES (?, ?)`, runID, artifact.ID); err != nil {
+		return fmt.Errorf("failed to link artifact %s to run %s: %w", artifact.ID, runID, err)
+	}
+	return tx.Commit()
+}
+
+// ListArtifacts returns the artifacts matching filters in the order they were stored
+func (l *Ledger) ListArtifacts(ctx context.Context, filters ports.ArtifactFilters) ([]core.Artifact, error) {
+	var where []string
+	var args []interface{}
+	if filters.Kind != nil {
+		where = append(where, `a.kind = ?`)
+		args = append(args, *filters.Kind)
+	}
+	if filters.RunID != nil {
+		where = append(where, `a.id IN (SELECT artifact_id FROM ledger_run_artifacts WHERE run_id = ?)`)
+		args = append(args, *filters.RunID)
+	}
+
+	query := `SELECT a.body FROM ledger_artifacts a`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	query += ` ORDER BY a.rowid`
+	if filters.Limit > 0 || filters.Offset > 0 {
+		limit := filters.Limit
+		if limit <= 0 {
+			limit = -1 // SQLite's "no limit"
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, filters.Offset)
+	}
+	return l.query(ctx, query, args...)
+}
+
+// GetArtifact returns one artifact by ID
+func (l *Ledger) GetArtifact(ctx context.Context, artifactID core.ArtifactID) (*core.Artifact, error) {
+	var body string
+	err := l.db.QueryRowContext(ctx, `SELECT body FROM ledger_artifacts WHERE id = ?`, artifactID).Scan(&body)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("artifact not found: %s", artifactID)
+		}
+		return nil, fmt.Errorf("failed to get artifact: %w", err)
+	}
+	var artifact core.Artifact
+	if err := json.Unmarshal([]byte(body), &artifact); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal artifact %s: %w", artifactID, err)
+	}
+	return &artifact, nil
+}
+
+// GetArtifactsByRun returns a run's artifacts in the order they were stored
+func (l *Ledger) GetArtifactsByRun(ctx context.Context, runID core.RunID) ([]core.Artifact, error) {
+	artifacts, err := l.query(ctx, `SELECT a.body FROM ledger_run_artifacts r
+		JOIN ledger_artifacts a ON a.id = r.artifact_id
+		WHERE r.run_id = ?
+		ORDER BY r.seq`, runID)
+	if err != nil {
+		return nil, err
+	}
+	if artifacts == nil {
+		artifacts = []core.Artifact{}
+	}
+	return artifacts, nil
+}
+
+// GetArtifactsByKind returns up to limit artifacts of a kind
+func (l *Ledger) GetArtifactsByKind(ctx context.Context, kind core.ArtifactKind, limit int) ([]core.Artifact, error) {
+	return l.ListArtifacts(ctx, ports.ArtifactFilters{Kind: &kind, Limit: limit})
+}
+
+// GetRunManifest decodes the run artifact stored under a run
+func (l *Ledger) GetRunManifest(ctx context.Context, runID core.RunID) (*run.RunManifestArtifact, error) {
+	kind := core.ArtifactRun
+	artifacts, err := l.ListArtifacts(ctx, ports.ArtifactFilters{RunID: &runID, Kind: &kind, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(artifacts) == 0 {
+		return nil, fmt.Errorf("run manifest not found: %s", runID)
+	}
+	data, err := json.Marshal(artifacts[0].Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run manifest: %w", err)
+	}
+	var manifest run.RunManifestArtifact
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode run manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// ListRunIDs returns every run with artifacts in the ledger
+func (l *Ledger) ListRunIDs(ctx context.Context) ([]core.RunID, error) {
+	runIDs := []core.RunID{}
+	if err := l.db.SelectContext(ctx, &runIDs, `SELECT DISTINCT run_id FROM ledger_run_artifacts ORDER BY run_id`); err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	return runIDs, nil
+}
+
+// RemoveArtifacts drops artifacts from a run, and from the ledger once no run holds them
+func (l *Ledger) RemoveArtifacts(ctx context.Context, runID core.RunID, artifactIDs []core.ArtifactID) error {
+	if len(artifactIDs) == 0 {
+		return nil
+	}
+	tx, err := l.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, artifactID := range artifactIDs {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM ledger_run_artifacts WHERE run_id = ? AND artifact_id = ?`, runID, artifactID); err != nil {
+			return fmt.Errorf("failed to remove artifact %s from run %s: %w", artifactID, runID, err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM ledger_artifacts WHERE id = ?
+			AND NOT EXISTS (SELECT 1 FROM ledger_run_artifacts WHERE artifact_id = ?)`, artifactID, artifactID); err != nil {
+			return fmt.Errorf("failed to remove artifact %s: %w", artifactID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// query decodes the artifact bodies a query selects
+func (l *Ledger) query(ctx context.Context, query string, args ...interface{}) ([]core.Artifact, error) {
+	rows, err := l.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query artifacts: %w", err)
+	}
+	defer rows.Close()
+
+	var artifacts []core.Artifact
+	for rows.Next() {
+		var body string
+		if err := rows.Scan(&body); err != nil {
+			return nil, fmt.Errorf("failed to scan artifact: %w", err)
+		}
+		var artifact core.Artifact
+		if err := json.Unmarshal([]byte(body), &artifact); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal artifact: %w", err)
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, rows.Err()
+}
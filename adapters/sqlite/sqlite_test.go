@@ -0,0 +1,247 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/internal/migration"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const defaultUserID = core.ID("550e8400-e29b-41d4-a716-446655440000")
+
+// openTestDB opens a migrated SQLite database in a temporary directory
+func openTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+	ctx := context.Background()
+	db, err := Open(ctx, "sqlite://"+filepath.Join(t.TempDir(), "data", "gohypo.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := migration.ForDriver(db.DriverName()).Up(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// TestMigrationsRoundTrip verifies the SQLite runner records every migration, and reverts and
+// reapplies them
+func TestMigrationsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	runner := migration.ForDriver(db.DriverName())
+
+	statuses, err := runner.Status(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != len(runner.Migrations()) {
+		t.Fatalf("expected every migration listed, got %+v", statuses)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.Dirty || status.AppliedAt == nil {
+			t.Fatalf("expected every migration applied, got %+v", statuses)
+		}
+	}
+	if err := runner.Up(ctx, db); err != nil {
+		t.Fatalf("a second Up should be a no-op: %v", err)
+	}
+	if err := runner.Down(ctx, db, 1); err != nil {
+		t.Fatal(err)
+	}
+	if pending, err := runner.Pending(ctx, db); err != nil || len(pending) != 1 {
+		t.Fatalf("expected the newest migration pending after Down, got %v (%v)", pending, err)
+	}
+	if err := runner.Reset(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewWorkspaceRepository(db).GetByUserID(ctx, defaultUserID); err != nil {
+		t.Fatalf("expected the schema back after Reset: %v", err)
+	}
+}
+
+// TestWorkspaceAndDatasetRepositories covers the workspace and dataset round trip, including
+// metadata, the default-workspace guard and reassignment
+func TestWorkspaceAndDatasetRepositories(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	workspaces := NewWorkspaceRepository(db)
+	datasets := NewDatasetRepository(db)
+	now := time.Now().Truncate(time.Second)
+
+	home := &dataset.Workspace{ID: core.NewID(), UserID: defaultUserID, Name: "Home", Color: "#3B82F6", IsDefault: true,
+		Metadata: map[string]interface{}{"max_datasets": 50}, CreatedAt: now, UpdatedAt: now}
+	lab := &dataset.Workspace{ID: core.NewID(), UserID: defaultUserID, Name: "Lab", CreatedAt: now.Add(time.Minute), UpdatedAt: now}
+	for _, ws := range []*dataset.Workspace{home, lab} {
+		if err := workspaces.Create(ctx, ws); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, err := workspaces.GetDefaultForUser(ctx, defaultUserID); err != nil || got.ID != home.ID || got.Metadata["max_datasets"] != 50.0 {
+		t.Fatalf("unexpected default workspace %+v (%v)", got, err)
+	}
+	if err := workspaces.Delete(ctx, home.ID); err == nil {
+		t.Error("the default workspace must not be deletable")
+	}
+
+	ds := &dataset.Dataset{ID: core.NewID(), UserID: defaultUserID, WorkspaceID: lab.ID, OriginalFilename: "orders.csv",
+		DisplayName: "customer_orders", Status: dataset.StatusReady, RecordCount: 10,
+		Metadata: dataset.DatasetMetadata{Fields: []dataset.FieldInfo{{Name: "basket_size"}}}, CreatedAt: now, UpdatedAt: now}
+	if err := datasets.Create(ctx, ds); err != nil {
+		t.Fatal(err)
+	}
+	got, err := datasets.GetByID(ctx, ds.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.DisplayName != "customer_orders" || len(got.Metadata.Fields) != 1 || !got.CreatedAt.Equal(now) {
+		t.Errorf("unexpected dataset %+v", got)
+	}
+	if err := datasets.UpdateStatus(ctx, ds.ID, dataset.StatusFailed, "bad header"); err != nil {
+		t.Fatal(err)
+	}
+	if failed, err := datasets.ListByStatus(ctx, dataset.StatusFailed); err != nil || len(failed) != 1 || failed[0].ErrorMessage != "bad header" {
+		t.Errorf("expected the failed dataset listed, got %v (%v)", failed, err)
+	}
+
+	other := core.ID(uuid.NewString())
+	if _, err := db.ExecContext(ctx, `INSERT INTO users (id, email) VALUES (?, ?)`, other, "other@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := workspaces.Reassign(ctx, lab.ID, other); err != nil {
+		t.Fatal(err)
+	}
+	if moved, err := datasets.GetByUserID(ctx, other, 10, 0); err != nil || len(moved) != 1 {
+		t.Errorf("expected the dataset to follow its workspace, got %v (%v)", moved, err)
+	}
+	withDatasets, err := workspaces.GetWithDatasets(ctx, lab.ID)
+	if err != nil || len(withDatasets.Datasets) != 1 || withDatasets.Workspace.UserID != other {
+		t.Errorf("unexpected workspace with datasets %+v (%v)", withDatasets, err)
+	}
+	if err := datasets.Delete(ctx, core.NewID()); err == nil {
+		t.Error("deleting a missing dataset should fail")
+	}
+}
+
+// TestHypothesisRepository covers saving, upserting, listing and the user statistics
+func TestHypothesisRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewHypothesisRepository(openTestDB(t))
+	userID, sessionID, workspaceID := uuid.MustParse(string(defaultUserID)), uuid.New(), uuid.NewString()
+
+	result := &models.HypothesisResult{ID: "hyp-1", WorkspaceID: workspaceID, BusinessHypothesis: "Basket size drives churn",
+		ScienceHypothesis: "basket_size ~ churn", ExplanationMarkdown: "**why**", PhaseEValues: []float64{1.5, 2},
+		ExecutionMetadata: map[string]interface{}{"seed": 42.0}, ValidationTimestamp: time.Now()}
+	if err := repo.SaveHypothesis(ctx, userID, sessionID, result); err != nil {
+		t.Fatal(err)
+	}
+	result.Passed = true
+	if err := repo.SaveHypothesis(ctx, userID, sessionID, result); err != nil {
+		t.Fatalf("saving again should update the hypothesis: %v", err)
+	}
+	if err := repo.SaveHypothesis(ctx, userID, sessionID, &models.HypothesisResult{ID: "hyp-2", BusinessHypothesis: "b", ScienceHypothesis: "s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.GetHypothesis(ctx, userID, "hyp-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Passed || got.WorkspaceID != workspaceID || got.ExplanationMarkdown != "**why**" || len(got.PhaseEValues) != 2 || got.ExecutionMetadata["seed"] != 42.0 {
+		t.Errorf("unexpected hypothesis %+v", got)
+	}
+	if session, err := repo.ListSessionHypotheses(ctx, userID, sessionID); err != nil || len(session) != 2 || session[0].ID != "hyp-1" {
+		t.Errorf("expected both hypotheses oldest first, got %v (%v)", session, err)
+	}
+	if scoped, err := repo.ListByWorkspace(ctx, userID, workspaceID, 10); err != nil || len(scoped) != 1 {
+		t.Errorf("expected one hypothesis in the workspace, got %v (%v)", scoped, err)
+	}
+	if passed, err := repo.ListByValidationState(ctx, userID, true, 0); err != nil || len(passed) != 1 {
+		t.Errorf("expected one validated hypothesis, got %v (%v)", passed, err)
+	}
+
+	stats, err := repo.GetUserStats(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.TotalHypotheses != 2 || stats.ValidatedCount != 1 || stats.ValidationRate != 50 || stats.EarliestHypothesis == nil {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	sessions, err := repo.(ports.ValidatedEvidenceSource).ValidatedSessions(ctx)
+	if err != nil || len(sessions) != 1 || sessions[0] != sessionID.String() {
+		t.Errorf("expected the session with a validated hypothesis, got %v (%v)", sessions, err)
+	}
+}
+
+// TestLedger covers storing artifacts under runs, filtering them and compaction
+func TestLedger(t *testing.T) {
+	ctx := context.Background()
+	ledger := NewLedger(openTestDB(t))
+
+	relationship := core.Artifact{ID: "a-1", Kind: core.ArtifactRelationship, Payload: map[string]interface{}{"variable_x": "x"}, CreatedAt: core.Now()}
+	profile := core.Artifact{ID: "a-2", Kind: core.ArtifactVariableProfile, Payload: map[string]interface{}{"n": 3}, CreatedAt: core.Now()}
+	for _, stored := range []struct {
+		run      string
+		artifact core.Artifact
+	}{{"run-1", relationship}, {"run-1", profile}, {"run-2", relationship}} {
+		if err := ledger.StoreArtifact(ctx, stored.run, stored.artifact); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	byRun, err := ledger.GetArtifactsByRun(ctx, "run-1")
+	if err != nil || len(byRun) != 2 || byRun[0].ID != "a-1" || byRun[1].SchemaVersion == 0 {
+		t.Fatalf("unexpected run artifacts %+v (%v)", byRun, err)
+	}
+	kind := core.ArtifactRelationship
+	if listed, err := ledger.ListArtifacts(ctx, ports.ArtifactFilters{Kind: &kind}); err != nil || len(listed) != 1 {
+		t.Errorf("expected one relationship artifact, got %v (%v)", listed, err)
+	}
+	if got, err := ledger.GetArtifact(ctx, "a-1"); err != nil || got.Payload.(map[string]interface{})["variable_x"] != "x" {
+		t.Errorf("unexpected artifact %+v (%v)", got, err)
+	}
+	if runs, err := ledger.ListRunIDs(ctx); err != nil || len(runs) != 2 {
+		t.Errorf("expected two runs, got %v (%v)", runs, err)
+	}
+
+	if err := ledger.RemoveArtifacts(ctx, "run-1", []core.ArtifactID{"a-1", "a-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ledger.GetArtifact(ctx, "a-2"); err == nil {
+		t.Error("an artifact no run holds should be gone")
+	}
+	if kept, err := ledger.GetArtifactsByRun(ctx, "run-2"); err != nil || len(kept) != 1 {
+		t.Errorf("an artifact another run holds should stay readable, got %v (%v)", kept, err)
+	}
+	if empty, err := ledger.GetArtifactsByRun(ctx, "run-1"); err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("expected an empty run, got %v (%v)", empty, err)
+	}
+}
+
+// TestPathFromURL verifies the forms of sqlite: URLs DATABASE_URL accepts
+func TestPathFromURL(t *testing.T) {
+	for url, want := range map[string]string{
+		"sqlite://./gohypo.db":        "./gohypo.db",
+		"sqlite:///var/lib/gohypo.db": "/var/lib/gohypo.db",
+		"sqlite::memory:":             ":memory:",
+	} {
+		if got, err := pathFromURL(url); err != nil || got != want {
+			t.Errorf("pathFromURL(%q) = %q, %v; want %q", url, got, err, want)
+		}
+	}
+	if _, err := pathFromURL("sqlite://"); err == nil {
+		t.Error("expected a URL without a file to be refused")
+	}
+	if IsURL("postgres://localhost/gohypo") {
+		t.Error("a PostgreSQL URL is not a SQLite URL")
+	}
+}
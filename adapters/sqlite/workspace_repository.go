@@ -0,0 +1,284 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/ports"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// workspaceColumns are the workspace columns in the order scanWorkspace reads them
+const workspaceColumns = `id, user_id, name, COALESCE(description, ''), COALESCE(color, ''), is_default, metadata, created_at, updated_at`
+
+// workspaceRepository implements the WorkspaceRepository interface
+type workspaceRepository struct {
+	db *sqlx.DB
+}
+
+// NewWorkspaceRepository creates a new SQLite workspace repository
+func NewWorkspaceRepository(db *sqlx.DB) ports.WorkspaceRepository {
+	return &workspaceRepository{db: db}
+}
+
+// Create inserts a new workspace into the database
+func (r *workspaceRepository) Create(ctx context.Context, workspace *dataset.Workspace) error {
+	metadataJSON, err := json.Marshal(workspace.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `INSERT INTO workspaces (
+		id, user_id, name, description, color, is_default, metadata, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		workspace.ID, workspace.UserID, workspace.Name, workspace.Description,
+		workspace.Color, workspace.IsDefault, string(metadataJSON),
+		workspace.CreatedAt, workspace.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create workspace: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a workspace by its ID
+func (r *workspaceRepository) GetByID(ctx context.Context, id core.ID) (*dataset.Workspace, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+workspaceColumns+` FROM workspaces WHERE id = ?`, id)
+	workspace, err := scanWorkspace(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("workspace not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+// GetByUserID retrieves all workspaces for a user
+func (r *workspaceRepository) GetByUserID(ctx context.Context, userID core.ID) ([]*dataset.Workspace, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+workspaceColumns+` FROM workspaces
+	WHERE user_id = ?
+	ORDER BY is_default DESC, created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	workspaces := make([]*dataset.Workspace, 0)
+	for rows.Next() {
+		workspace, err := scanWorkspace(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workspace: %w", err)
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	return workspaces, rows.Err()
+}
+
+// Update modifies an existing workspace
+func (r *workspaceRepository) Update(ctx context.Context, workspace *dataset.Workspace) error {
+	metadataJSON, err := json.Marshal(workspace.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `UPDATE workspaces SET
+		name = ?, description = ?, color = ?, is_default = ?, metadata = ?, updated_at = ?
+	WHERE id = ?`,
+		workspace.Name, workspace.Description, workspace.Color, workspace.IsDefault,
+		string(metadataJSON), workspace.UpdatedAt, workspace.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update workspace: %w", err)
+	}
+	return expectRow(result, "workspace", workspace.ID)
+}
+
+// Delete removes a workspace from the database; the default workspace cannot be deleted
+func (r *workspaceRepository) Delete(ctx context.Context, id core.ID) error {
+	var isDefault bool
+	err := r.db.QueryRowContext(ctx, `SELECT is_default FROM workspaces WHERE id = ?`, id).Scan(&isDefault)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("workspace not found: %s", id)
+		}
+		return fmt.Errorf("failed to check workspace: %w", err)
+	}
+	if isDefault {
+		return fmt.Errorf("cannot delete default workspace")
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM workspaces WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete workspace: %w", err)
+	}
+	return expectRow(result, "workspace", id)
+}
+
+// GetDefaultForUser retrieves the default workspace for a user
+func (r *workspaceRepository) GetDefaultForUser(ctx context.Context, userID core.ID) (*dataset.Workspace, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+workspaceColumns+` FROM workspaces WHERE user_id = ? AND is_default`, userID)
+	workspace, err := scanWorkspace(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("default workspace not found for user: %s", userID)
+		}
+		return nil, fmt.Errorf("failed to get default workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+// Reassign hands a workspace and its datasets to another user in one transaction
+func (r *workspaceRepository) Reassign(ctx context.Context, id, userID core.ID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `UPDATE workspaces SET user_id = ?, is_default = FALSE, updated_at = ? WHERE id = ?`, userID, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to reassign workspace: %w", err)
+	}
+	if err := expectRow(result, "workspace", id); err != nil {
+		return err
+	}
+
+	// Datasets are listed by owner, so they follow the workspace
+	if _, err := tx.ExecContext(ctx, `UPDATE datasets SET user_id = ?, updated_at = ? WHERE workspace_id = ?`, userID, now, id); err != nil {
+		return fmt.Errorf("failed to reassign datasets: %w", err)
+	}
+	return tx.Commit()
+}
+
+// GetWithDatasets retrieves a workspace with all its datasets and relations
+func (r *workspaceRepository) GetWithDatasets(ctx context.Context, id core.ID) (*ports.WorkspaceWithDatasets, error) {
+	workspace, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	datasets, err := NewDatasetRepository(r.db).GetByWorkspace(ctx, id, 1000, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get datasets: %w", err)
+	}
+	relations, err := r.GetRelations(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get relations: %w", err)
+	}
+	return &ports.WorkspaceWithDatasets{
+		Workspace: workspace,
+		Datasets:  datasets,
+		Relations: relations,
+	}, nil
+}
+
+// CreateRelation creates a new dataset relationship
+func (r *workspaceRepository) CreateRelation(ctx context.Context, relation *dataset.DatasetRelation) error {
+	metadataJSON, err := json.Marshal(relation.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `INSERT INTO workspace_dataset_relations (
+		id, workspace_id, source_dataset_id, target_dataset_id,
+		relation_type, confidence, metadata, discovered_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		relation.ID, relation.WorkspaceID, relation.SourceDatasetID, relation.TargetDatasetID,
+		relation.RelationType, relation.Confidence, string(metadataJSON), relation.DiscoveredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create relation: %w", err)
+	}
+	return nil
+}
+
+// GetRelations retrieves all relationships for a workspace
+func (r *workspaceRepository) GetRelations(ctx context.Context, workspaceID core.ID) ([]*dataset.DatasetRelation, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT
+		id, workspace_id, source_dataset_id, target_dataset_id,
+		relation_type, COALESCE(confidence, 0), metadata, discovered_at
+	FROM workspace_dataset_relations
+	WHERE workspace_id = ?
+	ORDER BY discovered_at DESC`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query relations: %w", err)
+	}
+	defer rows.Close()
+
+	var relations []*dataset.DatasetRelation
+	for rows.Next() {
+		var relation dataset.DatasetRelation
+		var metadataJSON sql.NullString
+		err := rows.Scan(
+			&relation.ID, &relation.WorkspaceID, &relation.SourceDatasetID, &relation.TargetDatasetID,
+			&relation.RelationType, &relation.Confidence, &metadataJSON, &relation.DiscoveredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan relation: %w", err)
+		}
+		if err := unmarshalColumn(metadataJSON, &relation.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+		relations = append(relations, &relation)
+	}
+	return relations, rows.Err()
+}
+
+// DeleteRelation removes a dataset relationship
+func (r *workspaceRepository) DeleteRelation(ctx context.Context, id core.ID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM workspace_dataset_relations WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete relation: %w", err)
+	}
+	return expectRow(result, "relation", id)
+}
+
+// rowScanner is a *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanWorkspace reads a row selected with workspaceColumns
+func scanWorkspace(row rowScanner) (*dataset.Workspace, error) {
+	var workspace dataset.Workspace
+	var metadataJSON sql.NullString
+	err := row.Scan(
+		&workspace.ID, &workspace.UserID, &workspace.Name, &workspace.Description,
+		&workspace.Color, &workspace.IsDefault, &metadataJSON,
+		&workspace.CreatedAt, &workspace.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := unmarshalColumn(metadataJSON, &workspace.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	return &workspace, nil
+}
+
+// unmarshalColumn decodes a JSON TEXT column, leaving v untouched when it is NULL or empty
+func unmarshalColumn(column sql.NullString, v interface{}) error {
+	if !column.Valid || column.String == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(column.String), v)
+}
+
+// expectRow reports a not-found error when a statement changed no row
+func expectRow(result sql.Result, entity string, id interface{}) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s not found: %v", entity, id)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+// Package sqlsource pulls rows from a SQL database as CSV, so warehouse tables enter the
+// readiness pipeline the same way uploaded files do.
+package sqlsource
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gohypo/models"
+
+	"github.com/lib/pq"
+)
+
+// Extract is the CSV rendering of a pulled result
+type Extract struct {
+	CSV       []byte
+	Columns   []string
+	Rows      int
+	Truncated bool   // the source returned more rows than its limit
+	Statement string // the SQL that was run, without the limit
+	PulledAt  time.Time
+}
+
+// Statement is the SQL that reads a source: its query, or every row of its table
+func Statement(source *models.SQLDataSource) string {
+	if source.Query != "" {
+		return source.Query
+	}
+	parts := strings.Split(source.Table, ".")
+	for i, part := range parts {
+		parts[i] = pq.QuoteIdentifier(part)
+	}
+	return "SELECT * FROM " + strings.Join(parts, ".")
+}
+
+// Pull runs the source's statement in a read-only transaction and renders up to its row
+// limit as CSV with a header row
+func Pull(ctx context.Context, source *models.SQLDataSource) (*Extract, error) {
+	dsn, err := source.ResolveDSN()
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(source.Driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s connection: %w", source.Driver, err)
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", models.RedactDSN(source.DSN), err)
+	}
+	defer tx.Rollback()
+
+	statement := Statement(source)
+	// One row past the limit tells a complete result from a truncated one
+	limited := fmt.Sprintf("SELECT * FROM (%s) AS source LIMIT %d", statement, source.MaxRows+1)
+	rows, err := tx.QueryContext(ctx, limited)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run source query: %w", err)
+	}
+	defer rows.Close()
+
+	extract, err := writeCSV(rows, source.MaxRows)
+	if err != nil {
+		return nil, err
+	}
+	extract.Statement = statement
+	extract.PulledAt = time.Now()
+	return extract, nil
+}
+
+// resultRows is the part of *sql.Rows the CSV writer reads
+type resultRows interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+// writeCSV renders at most maxRows rows, noting whether more were available
+func writeCSV(rows resultRows, maxRows int) (*Extract, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read result columns: %w", err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("source query returned no columns")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	extract := &Extract{Columns: columns}
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	record := make([]string, len(columns))
+	for rows.Next() {
+		if extract.Rows == maxRows {
+			extract.Truncated = true
+			break
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("failed to read row %d: %w", extract.Rows+1, err)
+		}
+		for i, v := range values {
+			record[i] = formatValue(v)
+		}
+		if err := w.Write(record); err != nil {
+			return nil, fmt.Errorf("failed to write CSV row: %w", err)
+		}
+		extract.Rows++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read source rows: %w", err)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	extract.CSV = buf.Bytes()
+	return extract, nil
+}
+
+// formatValue renders a scanned value the way the CSV parser reads it back: NULL as an
+// empty cell, times as RFC 3339 and booleans as true/false
+func formatValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(value)
+	case string:
+		return value
+	case time.Time:
+		return value.Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'f', -1, 32)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// File wraps an extract's CSV as the multipart file the dataset processor reads
+type File struct {
+	*bytes.Reader
+}
+
+// NewFile returns the extract's CSV as a file
+func NewFile(extract *Extract) *File {
+	return &File{bytes.NewReader(extract.CSV)}
+}
+
+// Close is a no-op for in-memory content
+func (f *File) Close() error {
+	return nil
+}
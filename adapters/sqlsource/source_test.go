@@ -0,0 +1,66 @@
+package sqlsource
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gohypo/models"
+)
+
+// fakeRows serves fixed rows through the resultRows interface
+type fakeRows struct {
+	columns []string
+	rows    [][]interface{}
+	next    int
+}
+
+func (f *fakeRows) Columns() ([]string, error) { return f.columns, nil }
+func (f *fakeRows) Err() error                 { return nil }
+
+func (f *fakeRows) Next() bool {
+	f.next++
+	return f.next <= len(f.rows)
+}
+
+func (f *fakeRows) Scan(dest ...interface{}) error {
+	for i, v := range f.rows[f.next-1] {
+		*dest[i].(*interface{}) = v
+	}
+	return nil
+}
+
+// TestWriteCSV verifies scanned values render as the CSV parser expects and the row limit
+// is reported when the source had more rows
+func TestWriteCSV(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rows := &fakeRows{
+		columns: []string{"region", "revenue", "churned", "day"},
+		rows: [][]interface{}{
+			{[]byte("north, east"), 12.5, false, day},
+			{"south", int64(7), nil, nil},
+			{"west", 3.0, true, day},
+		},
+	}
+	extract, err := writeCSV(rows, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "region,revenue,churned,day\n\"north, east\",12.5,false,2024-03-01T00:00:00Z\nsouth,7,,\n"
+	if string(extract.CSV) != want {
+		t.Errorf("unexpected CSV:\n%s", extract.CSV)
+	}
+	if extract.Rows != 2 || !extract.Truncated {
+		t.Errorf("expected 2 rows and truncation, got %d rows, truncated %v", extract.Rows, extract.Truncated)
+	}
+}
+
+// TestStatement verifies table names are quoted part by part
+func TestStatement(t *testing.T) {
+	if got := Statement(&models.SQLDataSource{Table: "sales.Orders"}); got != `SELECT * FROM "sales"."Orders"` {
+		t.Errorf("unexpected statement %q", got)
+	}
+	if got := Statement(&models.SQLDataSource{Query: "SELECT 1"}); !strings.HasPrefix(got, "SELECT 1") {
+		t.Errorf("expected the query to be used as given, got %q", got)
+	}
+}
@@ -0,0 +1,147 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gohypo/ports"
+)
+
+const (
+	defaultAnthropicBaseURL = "https://api.anthropic.com"
+	defaultAnthropicModel   = "claude-3-5-sonnet-latest"
+	anthropicAPIVersion     = "2023-06-01"
+)
+
+// AnthropicClient calls Anthropic's messages API
+type AnthropicClient struct {
+	APIKey      string
+	BaseURL     string
+	Timeout     time.Duration
+	Temperature float64
+	MaxTokens   int
+	Model       string
+}
+
+func (c *AnthropicClient) ChatCompletion(ctx context.Context, model string, prompt string, maxTokens int) (string, error) {
+	resp, err := c.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+func (c *AnthropicClient) ChatCompletionWithUsage(ctx context.Context, model string, prompt string, maxTokens int) (*ports.LLMResponse, error) {
+	return c.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, nil)
+}
+
+// ChatCompletionWithUsageAndFormat sends one user message. The configured model always wins
+// because callers pass OpenAI model names; JSON mode is requested through the system prompt.
+func (c *AnthropicClient) ChatCompletionWithUsageAndFormat(ctx context.Context, model string, prompt string, maxTokens int, responseFormat *ports.ResponseFormat) (*ports.LLMResponse, error) {
+	model = resolveProviderModel(c.Model, model, defaultAnthropicModel)
+	if maxTokens <= 0 {
+		maxTokens = c.MaxTokens
+	}
+	if maxTokens <= 0 {
+		maxTokens = 2000 // max_tokens is required by the messages API
+	}
+
+	system := "You are a careful assistant. Output exactly what the user asks for."
+	if responseFormat != nil && responseFormat.Type == "json_object" {
+		system += " Respond with a single valid JSON object and nothing else."
+	}
+
+	type msg struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	type reqBody struct {
+		Model       string  `json:"model"`
+		System      string  `json:"system,omitempty"`
+		Messages    []msg   `json:"messages"`
+		MaxTokens   int     `json:"max_tokens"`
+		Temperature float64 `json:"temperature,omitempty"`
+	}
+	raw, err := json.Marshal(reqBody{
+		Model:       model,
+		System:      system,
+		Messages:    []msg{{Role: "user", Content: prompt}},
+		MaxTokens:   maxTokens,
+		Temperature: c.Temperature,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = defaultAnthropicBaseURL
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(baseURL, "/")+"/v1/messages", bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	httpReq.Header.Set("x-api-key", c.APIKey)
+	httpReq.Header.Set("anthropic-version", anthropicAPIVersion)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: c.Timeout}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respRaw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newProviderHTTPError("anthropic", resp, respRaw)
+	}
+
+	type contentBlock struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	type usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	}
+	type respBody struct {
+		Content []contentBlock `json:"content"`
+		Usage   usage          `json:"usage"`
+		Model   string         `json:"model"`
+	}
+	var decoded respBody
+	if err := json.Unmarshal(respRaw, &decoded); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	var text strings.Builder
+	for _, block := range decoded.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return nil, fmt.Errorf("anthropic response missing text content")
+	}
+
+	return &ports.LLMResponse{
+		Content: text.String(),
+		Usage: &ports.UsageData{
+			PromptTokens:     decoded.Usage.InputTokens,
+			CompletionTokens: decoded.Usage.OutputTokens,
+			TotalTokens:      decoded.Usage.InputTokens + decoded.Usage.OutputTokens,
+			Model:            decoded.Model,
+			Provider:         "anthropic",
+		},
+	}, nil
+}
@@ -0,0 +1,274 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+const (
+	defaultOpenAIEmbeddingModel = "text-embedding-3-small"
+	defaultOllamaEmbeddingModel = "nomic-embed-text"
+
+	// embeddingRequestTimeout bounds a single embedding call; batches are small
+	embeddingRequestTimeout = 60 * time.Second
+
+	// hashingDimensions is the width of local vectors; collisions between the few hundred
+	// features of a column stay rare at this size
+	hashingDimensions = 512
+)
+
+// NewColumnEmbedder builds the embedder relationship discovery matches columns with. The
+// provider mode uses the embedding API of the configured OpenAI or Ollama provider; it falls
+// back to local hashed embeddings when that provider is not configured, has no embedding
+// API (Anthropic), or LLM calls are replayed from fixtures. Off returns nil.
+func NewColumnEmbedder(config *models.AIConfig) ports.TextEmbedder {
+	switch config.ColumnEmbeddings {
+	case models.ColumnEmbeddingsOff:
+		return nil
+	case models.ColumnEmbeddingsProvider:
+		if embedder := newProviderEmbedder(config); embedder != nil {
+			return embedder
+		}
+		log.Printf("[Embeddings] No embedding API for generator mode %q, using local column embeddings", config.Mode())
+	}
+	return NewHashingEmbedder()
+}
+
+func newProviderEmbedder(config *models.AIConfig) ports.TextEmbedder {
+	if !config.LLMConfigured() || config.FixtureMode == LLMFixturesReplay {
+		return nil
+	}
+	switch config.Mode() {
+	case models.GeneratorModeOpenAI:
+		return &OpenAIEmbedder{
+			APIKey:    config.OpenAIKey,
+			BaseURL:   "https://api.openai.com/v1",
+			Timeout:   embeddingRequestTimeout,
+			ModelName: firstNonEmpty(config.EmbeddingModel, defaultOpenAIEmbeddingModel),
+		}
+	case models.GeneratorModeOllama:
+		model := firstNonEmpty(config.EmbeddingModel, defaultOllamaEmbeddingModel)
+		if config.OllamaAPI == "openai" {
+			return &OpenAIEmbedder{
+				BaseURL:   strings.TrimRight(config.OllamaBaseURL, "/") + "/v1",
+				Timeout:   embeddingRequestTimeout,
+				ModelName: model,
+			}
+		}
+		return &OllamaEmbedder{BaseURL: config.OllamaBaseURL, Timeout: embeddingRequestTimeout, ModelName: model}
+	default:
+		return nil
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// OpenAIEmbedder calls the /embeddings endpoint of OpenAI or an OpenAI-compatible server
+type OpenAIEmbedder struct {
+	APIKey    string
+	BaseURL   string
+	Timeout   time.Duration
+	ModelName string
+}
+
+func (e *OpenAIEmbedder) Model() string { return e.ModelName }
+
+// Embed sends all texts in one request; the response is reordered by its indexes
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	body := map[string]interface{}{"model": e.ModelName, "input": texts}
+	var decoded struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := postEmbeddingRequest(ctx, "openai", strings.TrimRight(e.BaseURL, "/")+"/embeddings", e.APIKey, e.Timeout, body, &decoded); err != nil {
+		return nil, err
+	}
+	vectors := make([][]float32, len(texts))
+	for _, item := range decoded.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding response index %d out of range", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	for i, vector := range vectors {
+		if len(vector) == 0 {
+			return nil, fmt.Errorf("embedding response missing vector %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+// OllamaEmbedder calls the native /api/embed endpoint of a local Ollama server
+type OllamaEmbedder struct {
+	BaseURL   string
+	Timeout   time.Duration
+	ModelName string
+}
+
+func (e *OllamaEmbedder) Model() string { return e.ModelName }
+
+func (e *OllamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	baseURL := e.BaseURL
+	if baseURL == "" {
+		baseURL = defaultOllamaBaseURL
+	}
+	body := map[string]interface{}{"model": e.ModelName, "input": texts}
+	var decoded struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := postEmbeddingRequest(ctx, "ollama", strings.TrimRight(baseURL, "/")+"/api/embed", "", e.Timeout, body, &decoded); err != nil {
+		return nil, err
+	}
+	if len(decoded.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d texts", len(decoded.Embeddings), len(texts))
+	}
+	return decoded.Embeddings, nil
+}
+
+func postEmbeddingRequest(ctx context.Context, provider, url, apiKey string, timeout time.Duration, body interface{}, decoded interface{}) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("%s embedding request failed: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	respRaw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newProviderHTTPError(provider, resp, respRaw)
+	}
+	if err := json.Unmarshal(respRaw, decoded); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// HashingEmbedder embeds text locally by hashing its words and their character trigrams
+// into a fixed-width vector. It knows no synonyms, but trigrams match abbreviations and
+// shared values (cust_id and customer_id, C-1042 in both columns) without any network call.
+type HashingEmbedder struct {
+	dimensions int
+}
+
+// NewHashingEmbedder returns the local embedder
+func NewHashingEmbedder() *HashingEmbedder {
+	return &HashingEmbedder{dimensions: hashingDimensions}
+}
+
+func (e *HashingEmbedder) Model() string { return fmt.Sprintf("hashing-trigram-%d", e.dimensions) }
+
+func (e *HashingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.embed(text)
+	}
+	return vectors, nil
+}
+
+func (e *HashingEmbedder) embed(text string) []float32 {
+	vector := make([]float32, e.dimensions)
+	for _, word := range embeddingWords(text) {
+		e.add(vector, "w:"+word, 1)
+		padded := []rune("#" + word + "#")
+		for i := 0; i+3 <= len(padded); i++ {
+			e.add(vector, "t:"+string(padded[i:i+3]), 0.5)
+		}
+	}
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		return vector
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vector {
+		vector[i] *= scale
+	}
+	return vector
+}
+
+// add hashes a feature to a dimension; a second hash bit signs it, so colliding features
+// cancel out on average instead of inflating similarity
+func (e *HashingEmbedder) add(vector []float32, feature string, weight float32) {
+	h := fnv.New64a()
+	h.Write([]byte(feature))
+	sum := h.Sum64()
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	vector[sum%uint64(e.dimensions)] += weight
+}
+
+// embeddingWords splits text into lower-case words at punctuation, camelCase humps and
+// letter-digit boundaries
+func embeddingWords(text string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+	var prev rune
+	for _, r := range text {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case len(current) > 0 && (unicode.IsUpper(r) && unicode.IsLower(prev) ||
+			unicode.IsDigit(r) != unicode.IsDigit(prev)):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+		prev = r
+	}
+	flush()
+	return words
+}
@@ -0,0 +1,95 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gohypo/models"
+)
+
+// TestNewColumnEmbedderProviders verifies provider mode calls the configured provider's
+// embedding API and falls back to local embeddings where there is none
+func TestNewColumnEmbedderProviders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/embed" || body.Model != defaultOllamaEmbeddingModel || len(body.Input) != 2 {
+			t.Errorf("unexpected request %s %+v", r.URL.Path, body)
+		}
+		w.Write([]byte(`{"embeddings":[[1,0],[0,1]]}`))
+	}))
+	defer server.Close()
+
+	embedder := NewColumnEmbedder(&models.AIConfig{
+		GeneratorMode:    models.GeneratorModeOllama,
+		OllamaBaseURL:    server.URL,
+		ColumnEmbeddings: models.ColumnEmbeddingsProvider,
+	})
+	vectors, err := embedder.Embed(context.Background(), []string{"customer_id", "cust_ref"})
+	if err != nil {
+		t.Fatalf("ollama embed failed: %v", err)
+	}
+	if len(vectors) != 2 || vectors[1][1] != 1 {
+		t.Errorf("unexpected vectors %v", vectors)
+	}
+
+	anthropic := NewColumnEmbedder(&models.AIConfig{
+		GeneratorMode:    models.GeneratorModeAnthropic,
+		AnthropicKey:     "key",
+		ColumnEmbeddings: models.ColumnEmbeddingsProvider,
+	})
+	if _, ok := anthropic.(*HashingEmbedder); !ok {
+		t.Errorf("expected local embeddings for anthropic, got %T", anthropic)
+	}
+	if off := NewColumnEmbedder(&models.AIConfig{ColumnEmbeddings: models.ColumnEmbeddingsOff}); off != nil {
+		t.Errorf("expected no embedder when off, got %T", off)
+	}
+}
+
+// TestOpenAIEmbedderOrdersByIndex verifies vectors are returned in input order whatever
+// order the response lists them in
+func TestOpenAIEmbedderOrdersByIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" || r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("unexpected request %s with headers %v", r.URL.Path, r.Header)
+		}
+		w.Write([]byte(`{"data":[{"index":1,"embedding":[0,2]},{"index":0,"embedding":[3,0]}]}`))
+	}))
+	defer server.Close()
+
+	embedder := &OpenAIEmbedder{APIKey: "test-key", BaseURL: server.URL, ModelName: "text-embedding-test"}
+	vectors, err := embedder.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("openai embed failed: %v", err)
+	}
+	if vectors[0][0] != 3 || vectors[1][1] != 2 {
+		t.Errorf("vectors out of order: %v", vectors)
+	}
+}
+
+// TestHashingEmbedderSplitsNames verifies naming conventions do not change the words a
+// column name is embedded from
+func TestHashingEmbedderSplitsNames(t *testing.T) {
+	embedder := NewHashingEmbedder()
+	vectors, _ := embedder.Embed(context.Background(), []string{"CustomerID", "customer_id", "order total"})
+	if got := dot(vectors[0], vectors[1]); got < 0.999 {
+		t.Errorf("expected CustomerID and customer_id to embed alike, similarity %v", got)
+	}
+	if got := dot(vectors[0], vectors[2]); got > 0.3 {
+		t.Errorf("expected unrelated names to embed apart, similarity %v", got)
+	}
+}
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
@@ -33,8 +33,9 @@ type ScoutResponse struct {
 
 // NewForensicScout creates a new Forensic Scout
 func NewForensicScout(config *models.AIConfig) *ForensicScout {
-	// Use improved mock client that provides intelligent responses
-	mockClient := &mockLLMClient{}
+	// Use improved mock client that provides intelligent responses; its answers are
+	// recorded and replayed with the rest of the run's LLM calls
+	mockClient := WithLLMFixtures(&mockLLMClient{}, config)
 
 	// Create a low-token config for simple domain identification
 	lowTokenConfig := *config      // copy config
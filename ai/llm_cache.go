@@ -0,0 +1,204 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// CachingLLMClient serves repeated prompts from a response cache. The key covers everything
+// that shapes the provider's answer, so deterministic replays cost nothing.
+type CachingLLMClient struct {
+	Inner     ports.LLMClient
+	Cache     ports.LLMResponseCache
+	Namespace string // provider, configured model and sampling settings
+}
+
+// NewCachingLLMClient wraps inner with cache. The heuristic mock is never cached because it
+// is free, and a nil cache returns inner unchanged.
+func NewCachingLLMClient(inner ports.LLMClient, cache ports.LLMResponseCache, config *models.AIConfig) ports.LLMClient {
+	if cache == nil || inner == nil {
+		return inner
+	}
+	if _, ok := inner.(*mockLLMClient); ok {
+		return inner
+	}
+	return &CachingLLMClient{Inner: inner, Cache: cache, Namespace: LLMCacheNamespace(config)}
+}
+
+// LLMCacheNamespace identifies the provider configuration a cached response came from
+func LLMCacheNamespace(config *models.AIConfig) string {
+	model := config.OpenAIModel
+	switch config.Mode() {
+	case models.GeneratorModeAnthropic:
+		model = config.AnthropicModel
+	case models.GeneratorModeOllama:
+		model = config.OllamaBaseURL + "|" + config.OllamaModel
+	}
+	return fmt.Sprintf("%s|%s|t=%g|max=%d", config.Mode(), model, config.Temperature, config.MaxTokens)
+}
+
+// LLMCacheKey hashes the full request into a content address
+func LLMCacheKey(namespace, model, prompt string, maxTokens int, responseFormat *ports.ResponseFormat) string {
+	format := ""
+	if responseFormat != nil {
+		format = responseFormat.Type
+	}
+	h := sha256.New()
+	for _, part := range []string{namespace, model, fmt.Sprint(maxTokens), format, prompt} {
+		fmt.Fprintf(h, "%d:%s;", len(part), part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *CachingLLMClient) ChatCompletion(ctx context.Context, model string, prompt string, maxTokens int) (string, error) {
+	resp, err := c.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+func (c *CachingLLMClient) ChatCompletionWithUsage(ctx context.Context, model string, prompt string, maxTokens int) (*ports.LLMResponse, error) {
+	return c.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, nil)
+}
+
+// ChatCompletionWithUsageAndFormat returns the cached response when present, otherwise calls
+// the provider and stores the answer. Cache failures only cost a provider call.
+func (c *CachingLLMClient) ChatCompletionWithUsageAndFormat(ctx context.Context, model string, prompt string, maxTokens int, responseFormat *ports.ResponseFormat) (*ports.LLMResponse, error) {
+	key := LLMCacheKey(c.Namespace, model, prompt, maxTokens, responseFormat)
+
+	cached, ok, err := c.Cache.Get(ctx, key)
+	if err != nil {
+		log.Printf("[LLMCache] ⚠️ Lookup failed, calling provider: %v", err)
+	} else if ok {
+		usage := ports.UsageData{}
+		if cached.Usage != nil {
+			usage = *cached.Usage
+		}
+		usage.Cached = true
+		return &ports.LLMResponse{Content: cached.Content, Usage: &usage}, nil
+	}
+
+	resp, err := c.Inner.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, responseFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	// Never pin a malformed structured answer; the next call gets a fresh attempt
+	if responseFormat != nil && responseFormat.Type == "json_object" && !json.Valid([]byte(cleanJSONContent(resp.Content))) {
+		return resp, nil
+	}
+	if err := c.Cache.Put(ctx, key, resp); err != nil {
+		log.Printf("[LLMCache] ⚠️ Store failed: %v", err)
+	}
+	return resp, nil
+}
+
+// MemoryLLMCache keeps responses for the life of the process
+type MemoryLLMCache struct {
+	mu      sync.RWMutex
+	entries map[string]ports.LLMResponse
+}
+
+// NewMemoryLLMCache creates an empty in-process cache
+func NewMemoryLLMCache() *MemoryLLMCache {
+	return &MemoryLLMCache{entries: make(map[string]ports.LLMResponse)}
+}
+
+func (m *MemoryLLMCache) Get(ctx context.Context, key string) (*ports.LLMResponse, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return copyLLMResponse(&entry), true, nil
+}
+
+func (m *MemoryLLMCache) Put(ctx context.Context, key string, response *ports.LLMResponse) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = *copyLLMResponse(response)
+	return nil
+}
+
+// DiskLLMCache stores one JSON file per key under Dir, sharded by the first two hex digits
+type DiskLLMCache struct {
+	Dir string
+}
+
+// NewDiskLLMCache creates the cache directory if needed
+func NewDiskLLMCache(dir string) (*DiskLLMCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create llm cache dir: %w", err)
+	}
+	return &DiskLLMCache{Dir: dir}, nil
+}
+
+func (d *DiskLLMCache) path(key string) string {
+	if len(key) < 2 {
+		return filepath.Join(d.Dir, key+".json")
+	}
+	return filepath.Join(d.Dir, key[:2], key+".json")
+}
+
+func (d *DiskLLMCache) Get(ctx context.Context, key string) (*ports.LLMResponse, bool, error) {
+	raw, err := os.ReadFile(d.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var resp ports.LLMResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, false, fmt.Errorf("decode cached response %s: %w", key, err)
+	}
+	return &resp, true, nil
+}
+
+// Put writes through a temp file so concurrent readers never see a partial entry
+func (d *DiskLLMCache) Put(ctx context.Context, key string, response *ports.LLMResponse) error {
+	path := d.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".llm-cache-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func copyLLMResponse(resp *ports.LLMResponse) *ports.LLMResponse {
+	out := &ports.LLMResponse{Content: resp.Content}
+	if resp.Usage != nil {
+		usage := *resp.Usage
+		out.Usage = &usage
+	}
+	return out
+}
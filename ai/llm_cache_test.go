@@ -0,0 +1,103 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// countingLLMClient answers every prompt with the same JSON and counts provider calls
+type countingLLMClient struct {
+	calls int
+}
+
+func (c *countingLLMClient) ChatCompletion(ctx context.Context, model string, prompt string, maxTokens int) (string, error) {
+	return `{"ok":true}`, nil
+}
+
+func (c *countingLLMClient) ChatCompletionWithUsage(ctx context.Context, model string, prompt string, maxTokens int) (*ports.LLMResponse, error) {
+	return c.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, nil)
+}
+
+func (c *countingLLMClient) ChatCompletionWithUsageAndFormat(ctx context.Context, model string, prompt string, maxTokens int, responseFormat *ports.ResponseFormat) (*ports.LLMResponse, error) {
+	c.calls++
+	return &ports.LLMResponse{
+		Content: `{"ok":true}`,
+		Usage:   &ports.UsageData{PromptTokens: 10, CompletionTokens: 4, TotalTokens: 14, Provider: "openai"},
+	}, nil
+}
+
+// TestCachingLLMClientServesRepeatedPrompts verifies replays skip the provider on every backend
+func TestCachingLLMClientServesRepeatedPrompts(t *testing.T) {
+	disk, err := NewDiskLLMCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("disk cache: %v", err)
+	}
+	config := &models.AIConfig{OpenAIKey: "test", OpenAIModel: "gpt-test"}
+	format := &ports.ResponseFormat{Type: "json_object"}
+
+	for name, cache := range map[string]ports.LLMResponseCache{"memory": NewMemoryLLMCache(), "disk": disk} {
+		inner := &countingLLMClient{}
+		client := NewCachingLLMClient(inner, cache, config)
+
+		first, _ := client.ChatCompletionWithUsageAndFormat(context.Background(), "gpt-test", "prompt", 100, format)
+		second, _ := client.ChatCompletionWithUsageAndFormat(context.Background(), "gpt-test", "prompt", 100, format)
+		if inner.calls != 1 {
+			t.Fatalf("%s: expected one provider call, got %d", name, inner.calls)
+		}
+		if first.Usage.Cached || !second.Usage.Cached || second.Content != first.Content || second.Usage.TotalTokens != 14 {
+			t.Fatalf("%s: unexpected responses %+v / %+v", name, first.Usage, second.Usage)
+		}
+
+		client.ChatCompletionWithUsageAndFormat(context.Background(), "gpt-test", "other prompt", 100, format)
+		if inner.calls != 2 {
+			t.Fatalf("%s: a different prompt must miss the cache", name)
+		}
+	}
+}
+
+// TestLLMCacheKeySeparatesProviderSettings verifies a model or temperature change is a new key
+func TestLLMCacheKeySeparatesProviderSettings(t *testing.T) {
+	base := &models.AIConfig{OpenAIModel: "gpt-a", Temperature: 0.1}
+	warmer := &models.AIConfig{OpenAIModel: "gpt-a", Temperature: 0.9}
+	anthropic := &models.AIConfig{GeneratorMode: models.GeneratorModeAnthropic, AnthropicModel: "gpt-a", Temperature: 0.1}
+
+	key := LLMCacheKey(LLMCacheNamespace(base), "m", "p", 10, nil)
+	if key != LLMCacheKey(LLMCacheNamespace(base), "m", "p", 10, nil) {
+		t.Fatal("cache key must be deterministic")
+	}
+	for _, other := range []*models.AIConfig{warmer, anthropic} {
+		if key == LLMCacheKey(LLMCacheNamespace(other), "m", "p", 10, nil) {
+			t.Fatalf("namespace %q collided with %q", LLMCacheNamespace(other), LLMCacheNamespace(base))
+		}
+	}
+}
+
+// TestFixtureLLMClientRecordsThenReplaysOffline verifies a recording replays without a provider
+func TestFixtureLLMClientRecordsThenReplaysOffline(t *testing.T) {
+	config := &models.AIConfig{OpenAIKey: "test", OpenAIModel: "gpt-test", Temperature: 0.2, FixtureMode: LLMFixturesRecord, FixtureDir: t.TempDir()}
+	format := &ports.ResponseFormat{Type: "json_object"}
+
+	inner := &countingLLMClient{}
+	recorded, err := WithLLMFixtures(inner, config).ChatCompletionWithUsageAndFormat(context.Background(), "gpt-test", "prompt", 100, format)
+	if err != nil || inner.calls != 1 {
+		t.Fatalf("record: err=%v calls=%d", err, inner.calls)
+	}
+
+	replayConfig := &models.AIConfig{Temperature: 0.2, FixtureMode: LLMFixturesReplay, FixtureDir: config.FixtureDir}
+	replay := NewLLMClient(replayConfig)
+	replayed, err := replay.ChatCompletionWithUsageAndFormat(context.Background(), "gpt-test", "prompt", 100, format)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayed.Content != recorded.Content || !replayed.Usage.Cached || replayed.Usage.TotalTokens != 14 {
+		t.Fatalf("unexpected replay %+v", replayed.Usage)
+	}
+
+	if _, err := replay.ChatCompletionWithUsageAndFormat(context.Background(), "gpt-test", "changed prompt", 100, format); apperrors.GetCode(err) != apperrors.CodeDeterminismViolation {
+		t.Fatalf("a prompt with no recording must fail replay, got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// llmRequestTimeout bounds a single provider call
+const llmRequestTimeout = 180 * time.Second
+
+// NewLLMClient builds the client for the configured generator mode. When the selected
+// provider is not configured it returns the heuristic mock client, so callers keep working
+// without credentials exactly as they did before provider selection existed. Real providers
+// are wrapped with the shared retry and circuit-breaker guard for their mode, around the
+// metrics of each attempt. With LLM_FIXTURES=replay no provider is built at all: every
+// answer comes from the fixtures.
+func NewLLMClient(config *models.AIConfig) ports.LLMClient {
+	if config.FixtureMode == LLMFixturesReplay {
+		return WithLLMFixtures(nil, config)
+	}
+	provider := NewInstrumentedLLMClient(newProviderClient(config), config)
+	return WithLLMFixtures(NewResilientLLMClient(provider, config), config)
+}
+
+func newProviderClient(config *models.AIConfig) ports.LLMClient {
+	if !config.LLMConfigured() {
+		return &mockLLMClient{}
+	}
+
+	switch config.Mode() {
+	case models.GeneratorModeAnthropic:
+		return &AnthropicClient{
+			APIKey:      config.AnthropicKey,
+			BaseURL:     config.AnthropicBaseURL,
+			Timeout:     llmRequestTimeout,
+			Temperature: config.Temperature,
+			MaxTokens:   config.MaxTokens,
+			Model:       config.AnthropicModel,
+		}
+	case models.GeneratorModeOllama:
+		if config.OllamaAPI == "openai" {
+			// llama.cpp and other OpenAI-compatible local servers need no key
+			return &OpenAIClient{
+				BaseURL:     strings.TrimRight(config.OllamaBaseURL, "/") + "/v1",
+				Timeout:     llmRequestTimeout,
+				Temperature: config.Temperature,
+				MaxTokens:   config.MaxTokens,
+				Model:       config.OllamaModel,
+			}
+		}
+		return &OllamaClient{
+			BaseURL:     config.OllamaBaseURL,
+			Timeout:     llmRequestTimeout,
+			Temperature: config.Temperature,
+			MaxTokens:   config.MaxTokens,
+			Model:       config.OllamaModel,
+		}
+	case models.GeneratorModeOpenAI:
+		return &OpenAIClient{
+			APIKey:      config.OpenAIKey,
+			BaseURL:     "https://api.openai.com/v1",
+			Timeout:     llmRequestTimeout,
+			Temperature: config.Temperature,
+			MaxTokens:   config.MaxTokens,
+			Model:       config.OpenAIModel,
+		}
+	default:
+		log.Printf("[LLM] Unknown GENERATOR_MODE %q, using heuristic fallback", config.GeneratorMode)
+		return &mockLLMClient{}
+	}
+}
+
+// resolveProviderModel picks the model for a non-OpenAI provider. Callers pass OpenAI model
+// names, so the configured model wins and gpt-* names are never forwarded.
+func resolveProviderModel(configured, requested, fallback string) string {
+	if configured != "" {
+		return configured
+	}
+	if requested != "" && !strings.HasPrefix(requested, "gpt-") {
+		return requested
+	}
+	return fallback
+}
+
+// ErrLLMKeyRejected is returned by PingLLM when the provider refuses the configured key
+var ErrLLMKeyRejected = apperrors.New(apperrors.CodeUpstreamLLM, "API key rejected by provider")
+
+// PingLLM checks that the configured provider is reachable and accepts its credentials by
+// listing models, which costs no tokens
+func PingLLM(ctx context.Context, config *models.AIConfig) error {
+	var url string
+	header := http.Header{}
+	switch config.Mode() {
+	case models.GeneratorModeAnthropic:
+		baseURL := config.AnthropicBaseURL
+		if baseURL == "" {
+			baseURL = defaultAnthropicBaseURL
+		}
+		url = strings.TrimRight(baseURL, "/") + "/v1/models"
+		header.Set("x-api-key", config.AnthropicKey)
+		header.Set("anthropic-version", anthropicAPIVersion)
+	case models.GeneratorModeOllama:
+		baseURL := config.OllamaBaseURL
+		if baseURL == "" {
+			baseURL = defaultOllamaBaseURL
+		}
+		url = strings.TrimRight(baseURL, "/") + "/api/tags"
+		if config.OllamaAPI == "openai" {
+			url = strings.TrimRight(baseURL, "/") + "/v1/models"
+		}
+	default:
+		url = "https://api.openai.com/v1/models"
+		header.Set("Authorization", "Bearer "+config.OpenAIKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header = header
+
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return fmt.Errorf("%s unreachable: %w", config.Mode(), err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return ErrLLMKeyRejected
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("%s http %d from %s", config.Mode(), resp.StatusCode, url)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// TestAnthropicClientMessagesAPI verifies request shape, model override and usage mapping
+func TestAnthropicClientMessagesAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" || r.Header.Get("x-api-key") != "test-key" || r.Header.Get("anthropic-version") == "" {
+			t.Errorf("unexpected request %s with headers %v", r.URL.Path, r.Header)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["model"] != "claude-test" {
+			t.Errorf("expected configured model, got %v", body["model"])
+		}
+		w.Write([]byte(`{"model":"claude-test","content":[{"type":"text","text":"{\"ok\":true}"}],"usage":{"input_tokens":12,"output_tokens":5}}`))
+	}))
+	defer server.Close()
+
+	client := NewLLMClient(&models.AIConfig{
+		GeneratorMode:    models.GeneratorModeAnthropic,
+		AnthropicKey:     "test-key",
+		AnthropicModel:   "claude-test",
+		AnthropicBaseURL: server.URL,
+	})
+	resp, err := client.ChatCompletionWithUsageAndFormat(context.Background(), "gpt-5.2", "hello", 100, &ports.ResponseFormat{Type: "json_object"})
+	if err != nil {
+		t.Fatalf("anthropic call failed: %v", err)
+	}
+	if resp.Content != `{"ok":true}` || resp.Usage.TotalTokens != 17 || resp.Usage.Provider != "anthropic" {
+		t.Fatalf("unexpected response %+v usage %+v", resp, resp.Usage)
+	}
+}
+
+// TestOllamaClientChatAPI verifies JSON mode maps to format=json and usage counts are read
+func TestOllamaClientChatAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/chat" || body["format"] != "json" || body["stream"] != false {
+			t.Errorf("unexpected request %s %v", r.URL.Path, body)
+		}
+		w.Write([]byte(`{"model":"llama3.1","message":{"role":"assistant","content":"{}"},"prompt_eval_count":7,"eval_count":3}`))
+	}))
+	defer server.Close()
+
+	client := NewLLMClient(&models.AIConfig{GeneratorMode: models.GeneratorModeOllama, OllamaBaseURL: server.URL})
+	resp, err := client.ChatCompletionWithUsageAndFormat(context.Background(), "gpt-5.2", "hello", 0, &ports.ResponseFormat{Type: "json_object"})
+	if err != nil {
+		t.Fatalf("ollama call failed: %v", err)
+	}
+	if resp.Usage.TotalTokens != 10 || resp.Usage.Provider != "ollama" {
+		t.Fatalf("unexpected usage %+v", resp.Usage)
+	}
+}
+
+// TestNewLLMClientFallsBackToHeuristics verifies an unconfigured provider keeps the mock contract
+func TestNewLLMClientFallsBackToHeuristics(t *testing.T) {
+	client := NewLLMClient(&models.AIConfig{GeneratorMode: models.GeneratorModeAnthropic})
+	if _, ok := client.(*mockLLMClient); !ok {
+		t.Fatalf("expected heuristic mock client without an Anthropic key, got %T", client)
+	}
+}
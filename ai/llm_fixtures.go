@@ -0,0 +1,162 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// LLM fixture modes (LLM_FIXTURES): record writes every provider answer to the fixture
+// directory; replay answers every call from it and never reaches a provider
+const (
+	LLMFixturesOff    = "off"
+	LLMFixturesRecord = "record"
+	LLMFixturesReplay = "replay"
+)
+
+// LLMFixture is one recorded call. The request is kept next to the answer so a fixture
+// diff in review shows which prompt changed.
+type LLMFixture struct {
+	Key            string           `json:"key"`
+	Model          string           `json:"model"`
+	MaxTokens      int              `json:"max_tokens"`
+	ResponseFormat string           `json:"response_format,omitempty"`
+	Temperature    float64          `json:"temperature"`
+	Prompt         string           `json:"prompt"`
+	Content        string           `json:"content"`
+	Usage          *ports.UsageData `json:"usage,omitempty"`
+}
+
+// FixtureLLMClient records provider calls to, or replays them from, one JSON file per
+// prompt hash. Keys leave out the provider and configured model so a recording made against
+// any provider replays without credentials; the sampling temperature stays in the key
+// because the same prompt is sent at different temperatures by self-consistency sampling.
+type FixtureLLMClient struct {
+	Inner       ports.LLMClient // nil in replay mode
+	Dir         string
+	Mode        string
+	Temperature float64
+}
+
+// WithLLMFixtures wraps inner for the configured fixture mode; with fixtures off it returns
+// inner unchanged. In replay mode inner is never called and may be nil.
+func WithLLMFixtures(inner ports.LLMClient, config *models.AIConfig) ports.LLMClient {
+	switch config.FixtureMode {
+	case LLMFixturesRecord, LLMFixturesReplay:
+		return &FixtureLLMClient{Inner: inner, Dir: config.FixtureDir, Mode: config.FixtureMode, Temperature: config.Temperature}
+	default:
+		return inner
+	}
+}
+
+// LLMFixtureKey hashes a request the way fixtures are named
+func LLMFixtureKey(temperature float64, model, prompt string, maxTokens int, responseFormat *ports.ResponseFormat) string {
+	return LLMCacheKey(fmt.Sprintf("fixture|t=%g", temperature), model, prompt, maxTokens, responseFormat)
+}
+
+func (c *FixtureLLMClient) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
+
+func (c *FixtureLLMClient) ChatCompletion(ctx context.Context, model string, prompt string, maxTokens int) (string, error) {
+	resp, err := c.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+func (c *FixtureLLMClient) ChatCompletionWithUsage(ctx context.Context, model string, prompt string, maxTokens int) (*ports.LLMResponse, error) {
+	return c.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, nil)
+}
+
+// ChatCompletionWithUsageAndFormat replays the recorded answer, or calls the provider and
+// records it. A replay miss is an error: the run has drifted from its recording.
+func (c *FixtureLLMClient) ChatCompletionWithUsageAndFormat(ctx context.Context, model string, prompt string, maxTokens int, responseFormat *ports.ResponseFormat) (*ports.LLMResponse, error) {
+	key := LLMFixtureKey(c.Temperature, model, prompt, maxTokens, responseFormat)
+
+	if c.Mode == LLMFixturesReplay {
+		fixture, err := c.load(key)
+		if err != nil {
+			return nil, err
+		}
+		usage := ports.UsageData{}
+		if fixture.Usage != nil {
+			usage = *fixture.Usage
+		}
+		usage.Cached = true // nothing is billed on replay
+		return &ports.LLMResponse{Content: fixture.Content, Usage: &usage}, nil
+	}
+
+	resp, err := c.Inner.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, responseFormat)
+	if err != nil {
+		return nil, err
+	}
+	fixture := LLMFixture{
+		Key:         key,
+		Model:       model,
+		MaxTokens:   maxTokens,
+		Temperature: c.Temperature,
+		Prompt:      prompt,
+		Content:     resp.Content,
+		Usage:       resp.Usage,
+	}
+	if responseFormat != nil {
+		fixture.ResponseFormat = responseFormat.Type
+	}
+	if err := c.store(fixture); err != nil {
+		log.Printf("[LLMFixtures] ⚠️ Failed to record %s: %v", key, err)
+	}
+	return resp, nil
+}
+
+func (c *FixtureLLMClient) load(key string) (*LLMFixture, error) {
+	raw, err := os.ReadFile(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, &apperrors.AppError{
+			Code:    apperrors.CodeDeterminismViolation,
+			Message: fmt.Sprintf("no recorded LLM response %s in %s; re-record with LLM_FIXTURES=record", key, c.Dir),
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	var fixture LLMFixture
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		return nil, fmt.Errorf("decode LLM fixture %s: %w", key, err)
+	}
+	return &fixture, nil
+}
+
+// store writes through a temp file so a concurrent replay never reads a partial fixture
+func (c *FixtureLLMClient) store(fixture LLMFixture) error {
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.Dir, ".llm-fixture-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(fixture.Key))
+}
@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"context"
+	"time"
+
+	"gohypo/internal/metrics"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// InstrumentedLLMClient records each provider call's latency and failures in the server's
+// metrics. It sits inside the resilience guard, so every attempt of a retried call counts.
+type InstrumentedLLMClient struct {
+	Inner    ports.LLMClient
+	Provider string
+}
+
+// NewInstrumentedLLMClient wraps inner for the configured provider; the mock is returned
+// unchanged so it does not show up as a provider
+func NewInstrumentedLLMClient(inner ports.LLMClient, config *models.AIConfig) ports.LLMClient {
+	if inner == nil {
+		return inner
+	}
+	if _, ok := inner.(*mockLLMClient); ok {
+		return inner
+	}
+	return &InstrumentedLLMClient{Inner: inner, Provider: config.Mode()}
+}
+
+func (c *InstrumentedLLMClient) ChatCompletion(ctx context.Context, model string, prompt string, maxTokens int) (string, error) {
+	start := time.Now()
+	content, err := c.Inner.ChatCompletion(ctx, model, prompt, maxTokens)
+	c.observe(model, start, err)
+	return content, err
+}
+
+func (c *InstrumentedLLMClient) ChatCompletionWithUsage(ctx context.Context, model string, prompt string, maxTokens int) (*ports.LLMResponse, error) {
+	start := time.Now()
+	resp, err := c.Inner.ChatCompletionWithUsage(ctx, model, prompt, maxTokens)
+	c.observe(model, start, err)
+	return resp, err
+}
+
+func (c *InstrumentedLLMClient) ChatCompletionWithUsageAndFormat(ctx context.Context, model string, prompt string, maxTokens int, responseFormat *ports.ResponseFormat) (*ports.LLMResponse, error) {
+	start := time.Now()
+	resp, err := c.Inner.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, responseFormat)
+	c.observe(model, start, err)
+	return resp, err
+}
+
+func (c *InstrumentedLLMClient) observe(model string, start time.Time, err error) {
+	if model == "" {
+		model = "default"
+	}
+	metrics.LLMRequestDuration.Observe(metrics.Since(start), c.Provider, model)
+	if err != nil {
+		metrics.LLMErrors.Inc(c.Provider, model)
+	}
+}
@@ -0,0 +1,94 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gohypo/internal/resilience"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// ProviderHTTPError is a non-2xx provider response. It exposes the status and any
+// Retry-After hint so the resilience layer can tell throttling from bad requests.
+type ProviderHTTPError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+	retryAfter time.Duration
+}
+
+func newProviderHTTPError(provider string, resp *http.Response, body []byte) *ProviderHTTPError {
+	err := &ProviderHTTPError{Provider: provider, StatusCode: resp.StatusCode, Body: string(body)}
+	if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && seconds > 0 {
+		err.retryAfter = time.Duration(seconds) * time.Second
+	}
+	return err
+}
+
+func (e *ProviderHTTPError) Error() string {
+	return fmt.Sprintf("%s http %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// HTTPStatus returns the provider's response status
+func (e *ProviderHTTPError) HTTPStatus() int {
+	return e.StatusCode
+}
+
+// RetryAfter returns the provider's requested wait, 0 when it sent none
+func (e *ProviderHTTPError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// ResilientLLMClient retries transient provider failures and stops calling a provider that
+// keeps failing. The guard is shared per provider, so every client for it sees one breaker.
+type ResilientLLMClient struct {
+	Inner    ports.LLMClient
+	Upstream *resilience.Upstream
+}
+
+// NewResilientLLMClient wraps inner with the shared guard for the configured provider. It
+// should sit inside the cache so cache hits never touch the breaker. The mock is returned
+// unchanged because it cannot fail transiently.
+func NewResilientLLMClient(inner ports.LLMClient, config *models.AIConfig) ports.LLMClient {
+	if inner == nil {
+		return inner
+	}
+	if _, ok := inner.(*mockLLMClient); ok {
+		return inner
+	}
+	return &ResilientLLMClient{Inner: inner, Upstream: resilience.For("llm-" + config.Mode())}
+}
+
+func (c *ResilientLLMClient) ChatCompletion(ctx context.Context, model string, prompt string, maxTokens int) (string, error) {
+	var content string
+	err := c.Upstream.Do(ctx, func(ctx context.Context) error {
+		var err error
+		content, err = c.Inner.ChatCompletion(ctx, model, prompt, maxTokens)
+		return err
+	})
+	return content, err
+}
+
+func (c *ResilientLLMClient) ChatCompletionWithUsage(ctx context.Context, model string, prompt string, maxTokens int) (*ports.LLMResponse, error) {
+	var resp *ports.LLMResponse
+	err := c.Upstream.Do(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.Inner.ChatCompletionWithUsage(ctx, model, prompt, maxTokens)
+		return err
+	})
+	return resp, err
+}
+
+func (c *ResilientLLMClient) ChatCompletionWithUsageAndFormat(ctx context.Context, model string, prompt string, maxTokens int, responseFormat *ports.ResponseFormat) (*ports.LLMResponse, error) {
+	var resp *ports.LLMResponse
+	err := c.Upstream.Do(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.Inner.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, responseFormat)
+		return err
+	})
+	return resp, err
+}
@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gohypo/ports"
+)
+
+const (
+	defaultOllamaBaseURL = "http://localhost:11434"
+	defaultOllamaModel   = "llama3.1"
+)
+
+// OllamaClient calls a local Ollama server through its native chat API
+type OllamaClient struct {
+	BaseURL     string
+	Timeout     time.Duration
+	Temperature float64
+	MaxTokens   int
+	Model       string
+}
+
+func (c *OllamaClient) ChatCompletion(ctx context.Context, model string, prompt string, maxTokens int) (string, error) {
+	resp, err := c.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+func (c *OllamaClient) ChatCompletionWithUsage(ctx context.Context, model string, prompt string, maxTokens int) (*ports.LLMResponse, error) {
+	return c.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, nil)
+}
+
+// ChatCompletionWithUsageAndFormat sends one non-streaming chat turn; JSON mode maps to format=json
+func (c *OllamaClient) ChatCompletionWithUsageAndFormat(ctx context.Context, model string, prompt string, maxTokens int, responseFormat *ports.ResponseFormat) (*ports.LLMResponse, error) {
+	model = resolveProviderModel(c.Model, model, defaultOllamaModel)
+	if maxTokens <= 0 {
+		maxTokens = c.MaxTokens
+	}
+
+	type msg struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	type options struct {
+		Temperature float64 `json:"temperature"`
+		NumPredict  int     `json:"num_predict,omitempty"`
+	}
+	type reqBody struct {
+		Model    string  `json:"model"`
+		Messages []msg   `json:"messages"`
+		Stream   bool    `json:"stream"`
+		Format   string  `json:"format,omitempty"`
+		Options  options `json:"options"`
+	}
+	body := reqBody{
+		Model: model,
+		Messages: []msg{
+			{Role: "system", Content: "You are a careful assistant. Output exactly what the user asks for."},
+			{Role: "user", Content: prompt},
+		},
+		Options: options{Temperature: c.Temperature, NumPredict: maxTokens},
+	}
+	if responseFormat != nil && responseFormat.Type == "json_object" {
+		body.Format = "json"
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = defaultOllamaBaseURL
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(baseURL, "/")+"/api/chat", bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: c.Timeout}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("ollama request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respRaw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newProviderHTTPError("ollama", resp, respRaw)
+	}
+
+	type respBody struct {
+		Model   string `json:"model"`
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		PromptEvalCount int `json:"prompt_eval_count"`
+		EvalCount       int `json:"eval_count"`
+	}
+	var decoded respBody
+	if err := json.Unmarshal(respRaw, &decoded); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	if decoded.Message.Content == "" {
+		return nil, fmt.Errorf("ollama response missing message content")
+	}
+
+	return &ports.LLMResponse{
+		Content: decoded.Message.Content,
+		Usage: &ports.UsageData{
+			PromptTokens:     decoded.PromptEvalCount,
+			CompletionTokens: decoded.EvalCount,
+			TotalTokens:      decoded.PromptEvalCount + decoded.EvalCount,
+			Model:            decoded.Model,
+			Provider:         "ollama",
+		},
+	}, nil
+}
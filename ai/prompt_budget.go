@@ -0,0 +1,70 @@
+package ai
+
+import (
+	"sort"
+
+	"gohypo/models"
+)
+
+// BudgetFragment is one optional block of prompt context competing for the context window
+type BudgetFragment struct {
+	ID       string  // stable identifier recorded in the budget report
+	Priority int     // 1-10, as PromptFragment.Priority
+	Strength float64 // evidence strength 0..1, breaks priority ties
+	Tokens   int     // estimated cost, see EstimateTokens
+}
+
+// EstimateTokens approximates the token count of text at four characters per token,
+// rounded up. It errs high for JSON-heavy prompts, which is the safe side for a budget.
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// PromptBudget fits optional prompt fragments into what the model's context leaves after
+// the fixed prompt and the completion reserve
+type PromptBudget struct {
+	Model       string
+	LimitTokens int
+}
+
+// NewPromptBudget budgets for model, reserving maxCompletionTokens for the reply
+func NewPromptBudget(model string, maxCompletionTokens int) PromptBudget {
+	return PromptBudget{Model: model, LimitTokens: models.ContextWindow(model) - maxCompletionTokens}
+}
+
+// Fit ranks fragments by priority, then strength, then ID, and admits them greedily while
+// they fit next to fixedTokens. A fragment too large for the space left is dropped and
+// smaller, lower-ranked ones may still go in. The result depends only on the inputs, so the
+// same evidence always yields the same prompt. The returned set holds the included IDs.
+func (b PromptBudget) Fit(fixedTokens int, fragments []BudgetFragment) (*models.PromptBudgetReport, map[string]bool) {
+	ranked := append([]BudgetFragment(nil), fragments...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Priority != ranked[j].Priority {
+			return ranked[i].Priority > ranked[j].Priority
+		}
+		if ranked[i].Strength != ranked[j].Strength {
+			return ranked[i].Strength > ranked[j].Strength
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+
+	report := &models.PromptBudgetReport{
+		Model:       b.Model,
+		LimitTokens: b.LimitTokens,
+		FixedTokens: fixedTokens,
+		UsedTokens:  fixedTokens,
+		Included:    []string{},
+		Dropped:     []string{},
+	}
+	included := make(map[string]bool, len(ranked))
+	for _, fragment := range ranked {
+		if report.UsedTokens+fragment.Tokens > b.LimitTokens {
+			report.Dropped = append(report.Dropped, fragment.ID)
+			continue
+		}
+		report.UsedTokens += fragment.Tokens
+		report.Included = append(report.Included, fragment.ID)
+		included[fragment.ID] = true
+	}
+	return report, included
+}
@@ -0,0 +1,36 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestPromptBudgetFitRanksAndDropsDeterministically verifies priority, then strength, then ID
+// decide what survives, and that smaller fragments still fill leftover space
+func TestPromptBudgetFitRanksAndDropsDeterministically(t *testing.T) {
+	budget := PromptBudget{Model: "test", LimitTokens: 100}
+	fragments := []BudgetFragment{
+		{ID: "weak", Priority: 5, Strength: 0.1, Tokens: 30},
+		{ID: "strong", Priority: 5, Strength: 0.9, Tokens: 30},
+		{ID: "anchor", Priority: 7, Strength: 0.2, Tokens: 20},
+		{ID: "huge", Priority: 9, Strength: 1, Tokens: 500},
+		{ID: "tiny-b", Priority: 1, Strength: 0, Tokens: 5},
+		{ID: "tiny-a", Priority: 1, Strength: 0, Tokens: 5},
+	}
+
+	report, included := budget.Fit(41, fragments)
+	if want := []string{"anchor", "strong", "tiny-a"}; !reflect.DeepEqual(report.Included, want) {
+		t.Fatalf("included %v, want %v", report.Included, want)
+	}
+	if want := []string{"huge", "weak", "tiny-b"}; !reflect.DeepEqual(report.Dropped, want) {
+		t.Fatalf("dropped %v, want %v", report.Dropped, want)
+	}
+	if report.UsedTokens != 96 || !included["tiny-a"] || included["weak"] {
+		t.Fatalf("unexpected usage %d / %v", report.UsedTokens, included)
+	}
+
+	again, _ := budget.Fit(41, []BudgetFragment{fragments[5], fragments[4], fragments[3], fragments[2], fragments[1], fragments[0]})
+	if !reflect.DeepEqual(again, report) {
+		t.Fatalf("fit depends on input order: %+v vs %+v", again, report)
+	}
+}
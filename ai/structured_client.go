@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	apperrors "gohypo/internal/errors"
 	"gohypo/internal/usage"
 	"gohypo/models"
 	"gohypo/ports"
@@ -25,6 +26,10 @@ type StructuredClient[T any] struct {
 	UsageService  *usage.Service
 	UserID        *uuid.UUID // Optional user context for tracking
 	SessionID     *uuid.UUID // Optional session context for tracking
+
+	// Per-workspace pins; empty keeps the structured call defaults
+	Model     string
+	MaxTokens int
 }
 
 
@@ -41,24 +46,8 @@ func NewStructuredClient[T any](llmClient ports.LLMClient, usageService *usage.S
 // NewStructuredClientLegacy creates a new structured client (legacy signature for backward compatibility)
 // DEPRECATED: Use NewStructuredClient with proper LLMClient and usage service
 func NewStructuredClientLegacy[T any](config *models.AIConfig, promptsDir string) *StructuredClient[T] {
-	var llmClient ports.LLMClient
-
-	// If we have a real API key, create a real OpenAI client
-	if config.OpenAIKey != "" {
-		// Create real OpenAI client using the adapters package
-		openaiClient := &OpenAIClient{
-			APIKey:      config.OpenAIKey,
-			BaseURL:     "https://api.openai.com/v1",
-			Timeout:     180000000000, // 180 seconds in nanoseconds
-			Temperature: config.Temperature,
-			MaxTokens:   config.MaxTokens,
-			Model:       config.OpenAIModel,
-		}
-		llmClient = openaiClient
-	} else {
-		// Create mock LLM client for backward compatibility
-		llmClient = &mockLLMClient{}
-	}
+	// Real provider client for GENERATOR_MODE, or the mock when it is not configured
+	llmClient := NewLLMClient(config)
 
 	return &StructuredClient[T]{
 		LLMClient:     llmClient,
@@ -80,18 +69,26 @@ func (client *StructuredClient[T]) WithSessionContext(sessionID uuid.UUID) *Stru
 	return client
 }
 
-// GetJsonResponse makes a typed LLM call and parses JSON response
-func (client *StructuredClient[T]) GetJsonResponse(provider, prompt string) (*T, error) {
-	return client.GetJsonResponseWithContext(context.Background(), provider, prompt, "")
-}
+// Model and completion budget requested for every structured call (using default model for now)
+const (
+	structuredModel     = "gpt-5.2"
+	structuredMaxTokens = 2000
+)
 
-// GetJsonResponseWithContext makes a typed LLM call with context support
-func (client *StructuredClient[T]) GetJsonResponseWithContext(ctx context.Context, provider, prompt string, systemMessage string) (*T, error) {
-	if provider != "openai" {
-		log.Printf("[StructuredClient] ERROR: Unsupported provider: %s", provider)
-		return nil, fmt.Errorf("only openai provider supported")
+// RequestSettings returns the model and completion token budget structured calls request
+func (client *StructuredClient[T]) RequestSettings() (string, int) {
+	model, maxTokens := structuredModel, structuredMaxTokens
+	if client.Model != "" {
+		model = client.Model
+	}
+	if client.MaxTokens > 0 {
+		maxTokens = client.MaxTokens
 	}
+	return model, maxTokens
+}
 
+// AssemblePrompt returns the exact text sent to the provider for prompt and systemMessage
+func (client *StructuredClient[T]) AssemblePrompt(prompt, systemMessage string) string {
 	// Use provided system message or fall back to default
 	systemContent := systemMessage
 	if systemContent == "" {
@@ -105,17 +102,33 @@ func (client *StructuredClient[T]) GetJsonResponseWithContext(ctx context.Contex
 	}
 
 	// Build the full prompt with system context
-	fullPrompt := fmt.Sprintf("%s\n\n%s", systemContent, prompt)
+	return fmt.Sprintf("%s\n\n%s", systemContent, prompt)
+}
+
+// GetJsonResponse makes a typed LLM call and parses JSON response
+func (client *StructuredClient[T]) GetJsonResponse(provider, prompt string) (*T, error) {
+	return client.GetJsonResponseWithContext(context.Background(), provider, prompt, "")
+}
+
+// GetJsonResponseWithContext makes a typed LLM call with context support
+func (client *StructuredClient[T]) GetJsonResponseWithContext(ctx context.Context, provider, prompt string, systemMessage string) (*T, error) {
+	if provider != "openai" {
+		log.Printf("[StructuredClient] ERROR: Unsupported provider: %s", provider)
+		return nil, fmt.Errorf("only openai provider supported")
+	}
+
+	fullPrompt := client.AssemblePrompt(prompt, systemMessage)
 
 	// Call LLM with usage tracking and JSON response format
-	response, err := client.LLMClient.ChatCompletionWithUsageAndFormat(ctx, "gpt-5.2", fullPrompt, 2000, &ports.ResponseFormat{Type: "json_object"}) // Using default model for now
+	model, maxTokens := client.RequestSettings()
+	response, err := client.LLMClient.ChatCompletionWithUsageAndFormat(ctx, model, fullPrompt, maxTokens, &ports.ResponseFormat{Type: "json_object"})
 	if err != nil {
 		log.Printf("[StructuredClient] ERROR: LLM call failed: %v", err)
-		return nil, fmt.Errorf("LLM call failed: %w", err)
+		return nil, &apperrors.AppError{Code: apperrors.CodeUpstreamLLM, Message: "LLM call failed", Cause: err}
 	}
 
-	// Track usage if service is available
-	if client.UsageService != nil && client.UserID != nil {
+	// Track usage if service is available; cache hits were not billed
+	if client.UsageService != nil && client.UserID != nil && !response.Usage.Cached {
 		usageData := &models.UsageData{
 			PromptTokens:     response.Usage.PromptTokens,
 			CompletionTokens: response.Usage.CompletionTokens,
@@ -454,7 +467,7 @@ func (c *OpenAIClient) ChatCompletion(ctx context.Context, model string, prompt
 		return "", fmt.Errorf("read response: %w", err)
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("openai http %d: %s", resp.StatusCode, string(respRaw))
+		return "", newProviderHTTPError("openai", resp, respRaw)
 	}
 
 	type choice struct {
@@ -542,7 +555,7 @@ func (c *OpenAIClient) ChatCompletionWithUsageAndFormat(ctx context.Context, mod
 		return nil, fmt.Errorf("read response: %w", err)
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("openai http %d: %s", resp.StatusCode, string(respRaw))
+		return nil, newProviderHTTPError("openai", resp, respRaw)
 	}
 
 	type choice struct {
@@ -0,0 +1,110 @@
+package ai
+
+import (
+	"context"
+	"sync"
+
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// UsageMeter accumulates token usage and estimated cost for one generation run. Attach it
+// to the run's context with WithUsageMeter; MeteredLLMClient records into it.
+type UsageMeter struct {
+	mu    sync.Mutex
+	usage models.RunUsage
+}
+
+type usageMeterKey struct{}
+
+// WithUsageMeter returns a context carrying a fresh meter
+func WithUsageMeter(ctx context.Context, runID string) (context.Context, *UsageMeter) {
+	meter := &UsageMeter{usage: models.RunUsage{RunID: runID}}
+	return context.WithValue(ctx, usageMeterKey{}, meter), meter
+}
+
+// UsageMeterFrom returns the meter attached to ctx, or nil
+func UsageMeterFrom(ctx context.Context) *UsageMeter {
+	meter, _ := ctx.Value(usageMeterKey{}).(*UsageMeter)
+	return meter
+}
+
+// Record adds one provider call. Cache hits count as calls but cost nothing.
+func (m *UsageMeter) Record(usage *ports.UsageData) {
+	if m == nil || usage == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.usage.Calls++
+	if usage.Cached {
+		m.usage.CachedCalls++
+		return
+	}
+	cost := models.EstimateLLMCost(usage.Provider, usage.Model, usage.PromptTokens, usage.CompletionTokens)
+	m.usage.PromptTokens += usage.PromptTokens
+	m.usage.CompletionTokens += usage.CompletionTokens
+	m.usage.TotalTokens += usage.PromptTokens + usage.CompletionTokens
+	m.usage.EstimatedCostUSD += cost
+
+	if m.usage.ByModel == nil {
+		m.usage.ByModel = make(map[string]*models.ModelRunUsage)
+	}
+	key := usage.Provider + "/" + usage.Model
+	entry, ok := m.usage.ByModel[key]
+	if !ok {
+		entry = &models.ModelRunUsage{Provider: usage.Provider, Model: usage.Model}
+		m.usage.ByModel[key] = entry
+	}
+	entry.Calls++
+	entry.PromptTokens += usage.PromptTokens
+	entry.CompletionTokens += usage.CompletionTokens
+	entry.EstimatedCostUSD += cost
+}
+
+// Snapshot returns a copy of the usage recorded so far
+func (m *UsageMeter) Snapshot() *models.RunUsage {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := models.RunUsage{RunID: m.usage.RunID}
+	snapshot.Add(&m.usage)
+	return &snapshot
+}
+
+// MeteredLLMClient records every response's usage into the meter on the call's context
+type MeteredLLMClient struct {
+	Inner ports.LLMClient
+}
+
+// NewMeteredLLMClient wraps inner; it should be the outermost wrapper so cache hits are seen
+func NewMeteredLLMClient(inner ports.LLMClient) ports.LLMClient {
+	if inner == nil {
+		return nil
+	}
+	return &MeteredLLMClient{Inner: inner}
+}
+
+func (c *MeteredLLMClient) ChatCompletion(ctx context.Context, model string, prompt string, maxTokens int) (string, error) {
+	resp, err := c.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+func (c *MeteredLLMClient) ChatCompletionWithUsage(ctx context.Context, model string, prompt string, maxTokens int) (*ports.LLMResponse, error) {
+	return c.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, nil)
+}
+
+func (c *MeteredLLMClient) ChatCompletionWithUsageAndFormat(ctx context.Context, model string, prompt string, maxTokens int, responseFormat *ports.ResponseFormat) (*ports.LLMResponse, error) {
+	resp, err := c.Inner.ChatCompletionWithUsageAndFormat(ctx, model, prompt, maxTokens, responseFormat)
+	if err != nil {
+		return nil, err
+	}
+	UsageMeterFrom(ctx).Record(resp.Usage)
+	return resp, nil
+}
@@ -0,0 +1,26 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"gohypo/models"
+)
+
+// TestMeteredClientCountsCacheHitsAsFree verifies a replayed prompt adds a call but no tokens or cost
+func TestMeteredClientCountsCacheHitsAsFree(t *testing.T) {
+	config := &models.AIConfig{OpenAIKey: "test", OpenAIModel: "gpt-4o"}
+	client := NewMeteredLLMClient(NewCachingLLMClient(&countingLLMClient{}, NewMemoryLLMCache(), config))
+
+	ctx, meter := WithUsageMeter(context.Background(), "run-1")
+	client.ChatCompletionWithUsageAndFormat(ctx, "gpt-4o", "prompt", 100, nil)
+	client.ChatCompletionWithUsageAndFormat(ctx, "gpt-4o", "prompt", 100, nil)
+
+	usage := meter.Snapshot()
+	if usage.Calls != 2 || usage.CachedCalls != 1 || usage.TotalTokens != 14 {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+	if usage.RunID != "run-1" || len(usage.ByModel) != 1 {
+		t.Fatalf("expected one model entry for run-1, got %+v", usage)
+	}
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"testing"
+
+	"gohypo/domain/stage"
+	"gohypo/domain/stats"
+)
+
+// TestResolveFDRMethod verifies rigor profiles keep Benjamini-Hochberg unless the run is a
+// decision run, and that an explicit method wins over the profile
+func TestResolveFDRMethod(t *testing.T) {
+	cases := []struct {
+		req  StatsSweepRequest
+		want stats.FDRMethod
+	}{
+		{StatsSweepRequest{}, stats.FDRBenjaminiHochberg},
+		{StatsSweepRequest{Rigor: stage.RigorBasic}, stats.FDRBenjaminiHochberg},
+		{StatsSweepRequest{Rigor: stage.RigorStandard}, stats.FDRBenjaminiHochberg},
+		{StatsSweepRequest{Rigor: stage.RigorDecision}, stats.FDRBenjaminiYekutiel},
+		{StatsSweepRequest{Rigor: stage.RigorBasic, FDRMethod: stats.FDRStorey}, stats.FDRStorey},
+	}
+	for _, tc := range cases {
+		got, err := tc.req.resolveFDRMethod()
+		if err != nil {
+			t.Fatalf("rigor %q: %v", tc.req.Rigor, err)
+		}
+		if got != tc.want {
+			t.Errorf("rigor %q method %q: expected %s, got %s", tc.req.Rigor, tc.req.FDRMethod, tc.want, got)
+		}
+	}
+}
@@ -67,6 +67,19 @@ func (s *GreenfieldService) ExecuteGreenfieldFlow(
 		}
 	}
 
+	// Record the run's LLM token usage and estimated cost
+	if response.Usage != nil {
+		usageArtifact := core.Artifact{
+			ID:        core.ID(fmt.Sprintf("llm_usage_%s", runID)),
+			Kind:      core.ArtifactLLMUsage,
+			Payload:   response.Usage,
+			CreatedAt: core.Now(),
+		}
+		if err := s.ledgerPort.StoreArtifact(ctx, string(runID), usageArtifact); err != nil {
+			storeErrors = append(storeErrors, fmt.Errorf("failed to store usage artifact: %w", err))
+		}
+	}
+
 	// 4. Analyze hypotheses for risk assessment (if AI analyzer is available)
 	var riskProfiles []interface{}
 	if s.hypothesisAnalyzer != nil {
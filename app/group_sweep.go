@@ -0,0 +1,152 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/domain/stats"
+)
+
+// maxGroupLevels is the most levels a categorical column may have to be compared across;
+// beyond it the column behaves like an identifier and every level is a handful of rows
+const maxGroupLevels = 50
+
+// GroupDifferenceResult holds the ANOVA and Kruskal-Wallis comparison of a numeric variable
+// across the levels of a categorical one
+type GroupDifferenceResult struct {
+	Categorical string
+	Numeric     string
+	GroupMeans  map[string]float64 // keyed by the level's encoded value
+	Comparison  stats.GroupComparison
+}
+
+// categoricalVariables returns the columns typed categorical. Their numeric codes are
+// arbitrary, so they are compared across levels rather than correlated.
+func categoricalVariables(bundle *dataset.MatrixBundle) map[string]bool {
+	categorical := make(map[string]bool)
+	for _, meta := range bundle.ColumnMeta {
+		if meta.StatisticalType == dataset.TypeCategorical {
+			if _, ok := bundle.GetColumn(meta.VariableKey); ok {
+				categorical[string(meta.VariableKey)] = true
+			}
+		}
+	}
+	return categorical
+}
+
+// analyzeGroupDifferences compares every numeric variable across the levels of every
+// categorical one. Each pair is a single pass over the rows, so pairs are always recomputed
+// rather than checkpointed or reused from a baseline. A level takes part when it has at
+// least the categorical variable's minimum group size of rows, and a difference is kept when
+// its η² is as strong as the policy's minimum effect is for a correlation.
+func (s *StatsSweepService) analyzeGroupDifferences(bundle *dataset.MatrixBundle, categorical map[string]bool, defaults map[string]stats.TestDefaults, policy stats.AnalysisPolicy) []GroupDifferenceResult {
+	if len(categorical) == 0 {
+		return nil
+	}
+	var categories, numerics []string
+	for _, key := range bundle.Matrix.VariableKeys {
+		name := string(key)
+		switch {
+		case categorical[name]:
+			categories = append(categories, name)
+		case s.isLikelyNumeric(name):
+			numerics = append(numerics, name)
+		}
+	}
+	fmt.Printf("[StatsSweepService] 🧮 Comparing %d numeric variables across %d categorical variables\n", len(numerics), len(categories))
+
+	results := []GroupDifferenceResult{}
+	for _, category := range categories {
+		codes, _ := bundle.GetColumnData(core.VariableKey(category))
+		for _, numeric := range numerics {
+			values, _ := bundle.GetColumnData(core.VariableKey(numeric))
+			result, reason := compareAcrossLevels(codes, values, defaults[category].MinGroupSize)
+			if result == nil {
+				fmt.Printf("[StatsSweepService]     ⏭️ %s by %s skipped: %s\n", numeric, category, reason)
+				continue
+			}
+			result.Categorical, result.Numeric = category, numeric
+			if result.Comparison.EtaSquared >= policy.MinEtaSquared() {
+				results = append(results, *result)
+			}
+		}
+	}
+	return results
+}
+
+// compareAcrossLevels splits values by the level codes and compares the groups of at least
+// minGroupSize rows
+func compareAcrossLevels(codes, values []float64, minGroupSize int) (*GroupDifferenceResult, string) {
+	byLevel := make(map[float64][]float64)
+	for i := range codes {
+		if i >= len(values) {
+			break
+		}
+		code, v := codes[i], values[i]
+		if math.IsNaN(code) || math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		byLevel[code] = append(byLevel[code], v)
+	}
+	if len(byLevel) > maxGroupLevels {
+		return nil, fmt.Sprintf("%d levels (max %d)", len(byLevel), maxGroupLevels)
+	}
+
+	levels := make([]float64, 0, len(byLevel))
+	for code, group := range byLevel {
+		if len(group) >= minGroupSize {
+			levels = append(levels, code)
+		}
+	}
+	sort.Float64s(levels)
+	groups := make([][]float64, len(levels))
+	means := make(map[string]float64, len(levels))
+	n := 0
+	for i, code := range levels {
+		groups[i] = byLevel[code]
+		n += len(groups[i])
+		sum := 0.0
+		for _, v := range groups[i] {
+			sum += v
+		}
+		means[fmt.Sprintf("%g", code)] = sum / float64(len(groups[i]))
+	}
+	if n < 10 {
+		return nil, fmt.Sprintf("insufficient sample size: %d (need ≥10)", n)
+	}
+	comparison, err := stats.CompareGroups(groups)
+	if err != nil {
+		return nil, err.Error()
+	}
+	return &GroupDifferenceResult{GroupMeans: means, Comparison: *comparison}, ""
+}
+
+// groupDifferencePayload renders a group comparison as an association artifact payload. The
+// ANOVA p-value is the one corrected alongside the correlations; the correlation field holds
+// the correlation ratio η = √η², which is on the same scale as |r| for ranking.
+func (s *StatsSweepService) groupDifferencePayload(policy stats.AnalysisPolicy, group GroupDifferenceResult, evidenceID string, qValue float64) map[string]interface{} {
+	eta := math.Sqrt(group.Comparison.EtaSquared)
+	return map[string]interface{}{
+		"evidence_id":            evidenceID,
+		"cause_key":              group.Categorical,
+		"effect_key":             group.Numeric,
+		"correlation":            eta,
+		"effect_size":            group.Comparison.EtaSquared,
+		"eta_squared":            group.Comparison.EtaSquared,
+		"f_statistic":            group.Comparison.FStatistic,
+		"p_value":                group.Comparison.ANOVAPValue,
+		"q_value":                qValue,
+		"kruskal_h":              group.Comparison.HStatistic,
+		"kruskal_p_value":        group.Comparison.KruskalPValue,
+		"eta_squared_h":          group.Comparison.EtaSquaredH,
+		"groups":                 group.Comparison.Groups,
+		"group_means":            group.GroupMeans,
+		"sample_size":            group.Comparison.SampleSize,
+		"confidence_level":       policy.EvidenceLevel(group.Comparison.ANOVAPValue),
+		"practical_significance": policy.PracticalSignificance(eta),
+		"test_type":              string(stats.TestANOVA),
+	}
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+)
+
+// TestSweepComparesNumericAcrossCategoricalLevels verifies a categorical column is tested with
+// ANOVA instead of being correlated through its arbitrary codes, whose level means here are
+// not monotonic so Pearson on the codes would miss the difference
+func TestSweepComparesNumericAcrossCategoricalLevels(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	levelMeans := []float64{10, 30, 5}
+	columns := map[string][]float64{}
+	for i := 0; i < 90; i++ {
+		level := i % 3
+		columns["region"] = append(columns["region"], float64(level))
+		columns["revenue"] = append(columns["revenue"], levelMeans[level]+2*rng.NormFloat64())
+		columns["price"] = append(columns["price"], rng.NormFloat64())
+	}
+	bundle := sweepTestBundle(columns, []string{"region", "revenue", "price"})
+	bundle.ColumnMeta = []dataset.ColumnMeta{{VariableKey: core.VariableKey("region"), StatisticalType: dataset.TypeCategorical}}
+
+	resp, err := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil).RunStatsSweep(context.Background(), StatsSweepRequest{MatrixBundle: bundle})
+	if err != nil {
+		t.Fatalf("sweep failed: %v", err)
+	}
+
+	var anova map[string]interface{}
+	for _, rel := range resp.Relationships {
+		payload := rel.Payload.(map[string]interface{})
+		if payload["cause_key"] == "region" && payload["test_type"] == "pearson_correlation" {
+			t.Errorf("categorical codes were correlated with %v", payload["effect_key"])
+		}
+		if payload["cause_key"] == "region" && payload["effect_key"] == "revenue" {
+			anova = payload
+		}
+	}
+	if anova == nil {
+		t.Fatal("expected revenue to differ across regions")
+	}
+	if anova["test_type"] != "anova" || anova["eta_squared"].(float64) < 0.8 || anova["kruskal_p_value"].(float64) > 0.001 {
+		t.Fatalf("unexpected group comparison: %+v", anova)
+	}
+	if groups := resp.Manifest.Payload.(map[string]interface{})["group_comparisons"]; groups != 1 {
+		t.Fatalf("expected one group comparison in the manifest, got %v", groups)
+	}
+}
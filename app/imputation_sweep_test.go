@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestImputedSweepPoolsAcrossImputations verifies that a matrix with heavy missingness is swept
+// over m imputed matrices, reporting the pooled effect with its between-imputation variance,
+// and that the same seed reproduces the same pooled results
+func TestImputedSweepPoolsAcrossImputations(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	columns := map[string][]float64{}
+	for i := 0; i < 200; i++ {
+		load := rng.NormFloat64()
+		temperature := 0.8*load + 0.6*rng.NormFloat64()
+		if rng.Float64() < 0.4 {
+			temperature = math.NaN()
+		}
+		columns["load"] = append(columns["load"], load)
+		columns["temperature"] = append(columns["temperature"], temperature)
+		columns["noise"] = append(columns["noise"], rng.NormFloat64())
+	}
+	bundle := sweepTestBundle(columns, []string{"load", "temperature", "noise"})
+	svc := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil)
+
+	run := func() map[string]interface{} {
+		resp, err := svc.RunStatsSweep(context.Background(), StatsSweepRequest{MatrixBundle: bundle, Imputations: 10, Seed: 3})
+		if err != nil {
+			t.Fatalf("sweep failed: %v", err)
+		}
+		for _, rel := range resp.Relationships {
+			payload := rel.Payload.(map[string]interface{})
+			if payload["cause_key"] == "load" && payload["effect_key"] == "temperature" {
+				return payload
+			}
+		}
+		t.Fatalf("load vs temperature not found in %d relationships", len(resp.Relationships))
+		return nil
+	}
+
+	payload := run()
+	if payload["imputations"] != 10 || payload["sample_size"] != 200 {
+		t.Errorf("expected 10 imputations over all 200 rows, got %v and %v", payload["imputations"], payload["sample_size"])
+	}
+	if r := payload["correlation"].(float64); r < 0.6 || r > 0.95 {
+		t.Errorf("pooled correlation %.3f far from the generating 0.8", r)
+	}
+	if b := payload["between_imputation_variance"].(float64); b <= 0 {
+		t.Errorf("expected positive between-imputation variance, got %v", b)
+	}
+	if again := run(); again["correlation"] != payload["correlation"] || again["p_value"] != payload["p_value"] {
+		t.Errorf("same seed gave different pooled results: %v vs %v", payload["correlation"], again["correlation"])
+	}
+
+	if _, err := svc.RunStatsSweep(context.Background(), StatsSweepRequest{MatrixBundle: bundle, Imputations: 1}); err == nil {
+		t.Error("expected an error for a single imputation")
+	}
+}
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/domain/stats"
+	"gohypo/internal/buildinfo"
+)
+
+// SweepBaseline is a prior sweep's output that an incremental sweep can reuse
+type SweepBaseline struct {
+	Manifest      core.Artifact   `json:"manifest"`
+	Relationships []core.Artifact `json:"relationships"`
+}
+
+// sweepBaseline is the resolved reuse plan for an incremental sweep
+type sweepBaseline struct {
+	changed map[string]bool              // variables whose column fingerprint differs from the prior run
+	results map[string]CorrelationResult // prior results keyed by unorderedPairKey
+}
+
+// newSweepBaseline compares current column fingerprints against the prior manifest.
+// It returns nil when the prior run has no fingerprints to compare or kept pairs under a
+// different analysis policy, which forces a full sweep, and refuses a prior run from
+// incompatible method versions unless allowIncompatible is set.
+func newSweepBaseline(prior *SweepBaseline, fingerprints map[core.VariableKey]core.Hash, policy stats.AnalysisPolicy, allowIncompatible bool) (*sweepBaseline, error) {
+	if prior == nil {
+		return nil, nil
+	}
+	payload, ok := prior.Manifest.Payload.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	priorFingerprints := stringMap(payload["column_fingerprints"])
+	if len(priorFingerprints) == 0 {
+		return nil, nil
+	}
+	// The prior run only kept pairs that met its own cutoffs, so under another policy the
+	// pairs it dropped would be missing rather than recomputed
+	if manifestPolicy(payload) != policy {
+		fmt.Printf("[StatsSweepService] ⚠️ Baseline sweep ran under a different analysis policy, running full sweep\n")
+		return nil, nil
+	}
+	if err := buildinfo.CheckCompatible(stringMap(payload["method_versions"])); err != nil {
+		if !allowIncompatible {
+			return nil, fmt.Errorf("baseline sweep %w", err)
+		}
+		fmt.Printf("[StatsSweepService] ⚠️ Reusing baseline despite version mismatch: %v\n", err)
+	}
+
+	baseline := &sweepBaseline{
+		changed: make(map[string]bool),
+		results: make(map[string]CorrelationResult),
+	}
+	for key, hash := range fingerprints {
+		if priorFingerprints[string(key)] != string(hash) {
+			baseline.changed[string(key)] = true
+		}
+	}
+
+	for _, artifact := range prior.Relationships {
+		result, ok := correlationFromArtifact(artifact)
+		if !ok {
+			continue
+		}
+		baseline.results[unorderedPairKey(result.Variable1, result.Variable2)] = result
+	}
+	return baseline, nil
+}
+
+// reusable reports whether a pair can be copied from the prior run instead of recomputed
+func (b *sweepBaseline) reusable(task PairTask) bool {
+	return b != nil && !b.changed[task.VarX] && !b.changed[task.VarY]
+}
+
+// result returns the prior result for a reusable pair; pairs absent from the prior
+// relationships were filtered out last time and stay filtered. Column order may differ
+// between resolutions, so the prior result is re-oriented to the task (r is symmetric).
+func (b *sweepBaseline) result(task PairTask) *CorrelationResult {
+	prior, ok := b.results[unorderedPairKey(task.VarX, task.VarY)]
+	if !ok {
+		return nil
+	}
+	prior.Variable1, prior.Variable2 = task.VarX, task.VarY
+	return &prior
+}
+
+// unorderedPairKey identifies a pair regardless of which variable comes first
+func unorderedPairKey(a, b string) string {
+	if b < a {
+		a, b = b, a
+	}
+	return a + "|" + b
+}
+
+// changedVariables returns the changed variable names in stable order
+func (b *sweepBaseline) changedVariables() []string {
+	if b == nil {
+		return nil
+	}
+	names := make([]string, 0, len(b.changed))
+	for name := range b.changed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fingerprintPayload renders column fingerprints for the sweep manifest
+func fingerprintPayload(fingerprints map[core.VariableKey]core.Hash) map[string]string {
+	payload := make(map[string]string, len(fingerprints))
+	for key, hash := range fingerprints {
+		payload[string(key)] = string(hash)
+	}
+	return payload
+}
+
+// bundleFingerprint combines column fingerprints into one hash for the whole bundle
+func bundleFingerprint(bundle *dataset.MatrixBundle, fingerprints map[core.VariableKey]core.Hash) core.Hash {
+	data := ""
+	for _, key := range bundle.Matrix.VariableKeys {
+		data += fmt.Sprintf("%s=%s;", key, fingerprints[key])
+	}
+	return core.NewHash([]byte(data))
+}
+
+// correlationFromArtifact reads a relationship artifact back into a correlation result.
+// Payloads may have been round-tripped through JSON, so numbers arrive as float64.
+func correlationFromArtifact(artifact core.Artifact) (CorrelationResult, bool) {
+	payload, ok := artifact.Payload.(map[string]interface{})
+	if !ok {
+		return CorrelationResult{}, false
+	}
+	varX, okX := payload["cause_key"].(string)
+	varY, okY := payload["effect_key"].(string)
+	if !okX || !okY {
+		return CorrelationResult{}, false
+	}
+	testType, _ := payload["test_type"].(string)
+	if testType == pearsonTestType {
+		testType = ""
+	}
+	return CorrelationResult{
+		Variable1:   varX,
+		Variable2:   varY,
+		Coefficient: numberValue(payload["correlation"]),
+		PValue:      numberValue(payload["p_value"]),
+		SampleSize:  int(numberValue(payload["sample_size"])),
+		TestType:    testType,
+	}, true
+}
+
+// manifestPolicy reads the analysis policy a sweep manifest records; manifests from before
+// policies were recorded ran under the defaults
+func manifestPolicy(payload map[string]interface{}) stats.AnalysisPolicy {
+	var policy stats.AnalysisPolicy
+	if data, err := json.Marshal(payload["analysis_policy"]); err == nil {
+		json.Unmarshal(data, &policy)
+	}
+	return policy.WithDefaults()
+}
+
+func numberValue(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case float32:
+		return float64(n)
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	default:
+		return 0
+	}
+}
+
+func stringMap(v interface{}) map[string]string {
+	switch m := v.(type) {
+	case map[string]string:
+		return m
+	case map[string]interface{}:
+		out := make(map[string]string, len(m))
+		for key, value := range m {
+			if s, ok := value.(string); ok {
+				out[key] = s
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,215 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/domain/stats"
+	"gohypo/internal/buildinfo"
+)
+
+func sweepTestBundle(columns map[string][]float64, order []string) *dataset.MatrixBundle {
+	rows := len(columns[order[0]])
+	bundle := &dataset.MatrixBundle{}
+	for i := 0; i < rows; i++ {
+		bundle.Matrix.EntityIDs = append(bundle.Matrix.EntityIDs, core.ID(fmt.Sprintf("e%d", i)))
+		row := make([]float64, len(order))
+		for j, name := range order {
+			row[j] = columns[name][i]
+		}
+		bundle.Matrix.Data = append(bundle.Matrix.Data, row)
+	}
+	for _, name := range order {
+		bundle.Matrix.VariableKeys = append(bundle.Matrix.VariableKeys, core.VariableKey(name))
+	}
+	return bundle
+}
+
+// TestIncrementalSweepReusesUnchangedPairs verifies only pairs touching a new column are computed
+func TestIncrementalSweepReusesUnchangedPairs(t *testing.T) {
+	columns := map[string][]float64{}
+	for i := 0; i < 30; i++ {
+		x := float64(i)
+		columns["price"] = append(columns["price"], x)
+		columns["total"] = append(columns["total"], 2*x+float64(i%3))
+		columns["count"] = append(columns["count"], float64((i*7)%11))
+		columns["score"] = append(columns["score"], 30-x+float64(i%4))
+	}
+
+	svc := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil)
+	ctx := context.Background()
+
+	first, err := svc.RunStatsSweep(ctx, StatsSweepRequest{
+		MatrixBundle: sweepTestBundle(columns, []string{"price", "total", "count"}),
+	})
+	if err != nil {
+		t.Fatalf("full sweep failed: %v", err)
+	}
+
+	second, err := svc.RunStatsSweep(ctx, StatsSweepRequest{
+		MatrixBundle: sweepTestBundle(columns, []string{"price", "total", "count", "score"}),
+		Baseline:     &SweepBaseline{Manifest: first.Manifest, Relationships: first.Relationships},
+	})
+	if err != nil {
+		t.Fatalf("incremental sweep failed: %v", err)
+	}
+
+	manifest := second.Manifest.Payload.(map[string]interface{})
+	if manifest["mode"] != "incremental" {
+		t.Fatalf("expected incremental mode, got %v", manifest["mode"])
+	}
+	if manifest["pairs_reused"] != 3 || manifest["pairs_computed"] != 3 {
+		t.Fatalf("expected 3 reused and 3 computed pairs, got %v reused, %v computed",
+			manifest["pairs_reused"], manifest["pairs_computed"])
+	}
+
+	full, err := svc.RunStatsSweep(ctx, StatsSweepRequest{
+		MatrixBundle: sweepTestBundle(columns, []string{"price", "total", "count", "score"}),
+	})
+	if err != nil {
+		t.Fatalf("full sweep failed: %v", err)
+	}
+	if len(full.Relationships) != len(second.Relationships) {
+		t.Fatalf("incremental found %d relationships, full found %d", len(second.Relationships), len(full.Relationships))
+	}
+	for i := range full.Relationships {
+		want := full.Relationships[i].Payload.(map[string]interface{})
+		got := second.Relationships[i].Payload.(map[string]interface{})
+		if want["cause_key"] != got["cause_key"] || want["effect_key"] != got["effect_key"] || want["correlation"] != got["correlation"] {
+			t.Fatalf("relationship %d differs: incremental %v vs full %v", i, got, want)
+		}
+	}
+}
+
+// TestIncrementalSweepToleratesColumnReorder verifies reused pairs survive a different column order
+func TestIncrementalSweepToleratesColumnReorder(t *testing.T) {
+	columns := map[string][]float64{}
+	for i := 0; i < 30; i++ {
+		x := float64(i)
+		columns["price"] = append(columns["price"], x)
+		columns["total"] = append(columns["total"], 2*x+float64(i%3))
+	}
+
+	svc := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil)
+	ctx := context.Background()
+
+	first, err := svc.RunStatsSweep(ctx, StatsSweepRequest{
+		MatrixBundle: sweepTestBundle(columns, []string{"price", "total"}),
+	})
+	if err != nil {
+		t.Fatalf("full sweep failed: %v", err)
+	}
+	reordered, err := svc.RunStatsSweep(ctx, StatsSweepRequest{
+		MatrixBundle: sweepTestBundle(columns, []string{"total", "price"}),
+		Baseline:     &SweepBaseline{Manifest: first.Manifest, Relationships: first.Relationships},
+	})
+	if err != nil {
+		t.Fatalf("incremental sweep failed: %v", err)
+	}
+	if got := reordered.Manifest.Payload.(map[string]interface{})["pairs_reused"]; got != 1 {
+		t.Fatalf("expected the pair to be reused, got %v", got)
+	}
+	if len(reordered.Relationships) != len(first.Relationships) {
+		t.Fatalf("reordered sweep found %d relationships, first found %d", len(reordered.Relationships), len(first.Relationships))
+	}
+}
+
+// TestIncrementalSweepRefusesIncompatibleBaseline verifies a baseline from another method major
+// version is refused unless the request overrides the check
+func TestIncrementalSweepRefusesIncompatibleBaseline(t *testing.T) {
+	columns := map[string][]float64{}
+	for i := 0; i < 30; i++ {
+		columns["price"] = append(columns["price"], float64(i))
+		columns["total"] = append(columns["total"], 2*float64(i)+float64(i%3))
+	}
+
+	svc := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil)
+	ctx := context.Background()
+	bundle := sweepTestBundle(columns, []string{"price", "total"})
+
+	first, err := svc.RunStatsSweep(ctx, StatsSweepRequest{MatrixBundle: bundle})
+	if err != nil {
+		t.Fatalf("full sweep failed: %v", err)
+	}
+	first.Manifest.Payload.(map[string]interface{})["method_versions"] = map[string]string{"pairwise_correlation": "0.1.0"}
+	baseline := &SweepBaseline{Manifest: first.Manifest, Relationships: first.Relationships}
+
+	if _, err := svc.RunStatsSweep(ctx, StatsSweepRequest{MatrixBundle: bundle, Baseline: baseline}); !errors.Is(err, buildinfo.ErrIncompatible) {
+		t.Fatalf("expected ErrIncompatible, got %v", err)
+	}
+	if _, err := svc.RunStatsSweep(ctx, StatsSweepRequest{MatrixBundle: bundle, Baseline: baseline, AllowIncompatible: true}); err != nil {
+		t.Fatalf("override should reuse the baseline: %v", err)
+	}
+}
+
+// TestIncrementalSweepRecomputesUnderNewPolicy verifies a baseline run under other cutoffs is
+// not reused, since the pairs it dropped would otherwise be missing
+func TestIncrementalSweepRecomputesUnderNewPolicy(t *testing.T) {
+	columns := map[string][]float64{}
+	for i := 0; i < 30; i++ {
+		columns["price"] = append(columns["price"], float64(i))
+		columns["total"] = append(columns["total"], 2*float64(i)+float64(i%3))
+	}
+
+	svc := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil)
+	ctx := context.Background()
+	bundle := sweepTestBundle(columns, []string{"price", "total"})
+
+	first, err := svc.RunStatsSweep(ctx, StatsSweepRequest{MatrixBundle: bundle})
+	if err != nil {
+		t.Fatalf("full sweep failed: %v", err)
+	}
+	strict := stats.AnalysisPolicy{Alpha: 0.01, MinEffect: 0.4}
+	second, err := svc.RunStatsSweep(ctx, StatsSweepRequest{
+		MatrixBundle: bundle,
+		Policy:       strict,
+		Baseline:     &SweepBaseline{Manifest: first.Manifest, Relationships: first.Relationships},
+	})
+	if err != nil {
+		t.Fatalf("sweep under the new policy failed: %v", err)
+	}
+	payload := second.Manifest.Payload.(map[string]interface{})
+	if payload["mode"] != "full" || payload["pairs_reused"] != 0 {
+		t.Fatalf("expected a full sweep, got mode %v with %v pairs reused", payload["mode"], payload["pairs_reused"])
+	}
+	if got := payload["analysis_policy"]; got != strict.WithDefaults() {
+		t.Fatalf("expected the manifest to record the policy, got %v", got)
+	}
+
+	if _, err := svc.RunStatsSweep(ctx, StatsSweepRequest{MatrixBundle: bundle, Policy: stats.AnalysisPolicy{MinEffect: 0.8}}); err == nil {
+		t.Fatal("expected a policy with cutoffs out of order to be rejected")
+	}
+}
+
+// TestSweepBaselineOrientsReversedPairs verifies a prior result is found whichever variable a
+// task lists first, and comes back oriented to the task rather than to the prior run
+func TestSweepBaselineOrientsReversedPairs(t *testing.T) {
+	if unorderedPairKey("price", "total") != unorderedPairKey("total", "price") {
+		t.Fatal("expected the pair key to ignore variable order")
+	}
+	baseline := &sweepBaseline{
+		changed: map[string]bool{},
+		results: map[string]CorrelationResult{
+			unorderedPairKey("price", "total"): {Variable1: "price", Variable2: "total", Coefficient: 0.9},
+		},
+	}
+
+	task := PairTask{VarX: "total", VarY: "price"}
+	if !baseline.reusable(task) {
+		t.Fatal("expected an unchanged reversed pair to be reusable")
+	}
+	result := baseline.result(task)
+	if result == nil {
+		t.Fatal("expected the prior result for the reversed pair")
+	}
+	if result.Variable1 != "total" || result.Variable2 != "price" || result.Coefficient != 0.9 {
+		t.Errorf("expected the prior result re-oriented to the task, got %+v", result)
+	}
+	if stored := baseline.results[unorderedPairKey("price", "total")]; stored.Variable1 != "price" {
+		t.Errorf("expected re-orienting to leave the stored result alone, got %+v", stored)
+	}
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"fmt"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/domain/stats"
+)
+
+// sweepPanel is the entity and time structure of a panel matrix the mixed-model estimates use
+type sweepPanel struct {
+	entity, time core.VariableKey
+	entities     []float64 // each row's entity code
+}
+
+// panel returns the matrix's panel structure when mixed-model estimates were requested
+func (r StatsSweepRequest) panel() *sweepPanel {
+	if !r.MixedEffects {
+		return nil
+	}
+	entity, time, ok := r.MatrixBundle.PanelIndex()
+	if !ok {
+		return nil
+	}
+	entities, _ := r.MatrixBundle.GetColumnData(entity)
+	return &sweepPanel{entity: entity, time: time, entities: entities}
+}
+
+// indexes reports whether a variable is one of the panel's index columns, which are not
+// themselves modelled
+func (p *sweepPanel) indexes(variable string) bool {
+	return core.VariableKey(variable) == p.entity || core.VariableKey(variable) == p.time
+}
+
+// mixedComparison fits the random-intercept model of a Pearson pair on the panel and compares
+// it with the pooled coefficient
+func (s *StatsSweepService) mixedComparison(bundle *dataset.MatrixBundle, corr CorrelationResult, panel *sweepPanel) *stats.MixedComparison {
+	x, okX := bundle.GetColumnData(core.VariableKey(corr.Variable1))
+	y, okY := bundle.GetColumnData(core.VariableKey(corr.Variable2))
+	if !okX || !okY {
+		return nil
+	}
+	comparison, err := stats.CompareMixed(x, y, panel.entities, corr.Coefficient)
+	if err != nil {
+		fmt.Printf("[StatsSweepService]     ⚠️ Mixed model for %s vs %s: %v\n", corr.Variable1, corr.Variable2, err)
+		return nil
+	}
+	return comparison
+}
+
+// addMixedPayload records the mixed-model estimate of a pair next to its pooled effect;
+// diverging pairs carry the MIXED_DIVERGENCE warning
+func addMixedPayload(payload map[string]interface{}, comparison *stats.MixedComparison, panel *sweepPanel) {
+	payload["pooled_correlation"] = comparison.Pooled
+	payload["mixed_correlation"] = comparison.Mixed.Standardized
+	payload["mixed_p_value"] = comparison.Mixed.PValue
+	payload["mixed_model"] = comparison.Mixed
+	payload["mixed_entity"] = string(panel.entity)
+	payload["mixed_diverges"] = comparison.Diverges
+	if comparison.Diverges {
+		warnings, _ := payload["warnings"].([]string)
+		payload["warnings"] = append(warnings, string(stats.WarningMixedDivergence))
+	}
+}
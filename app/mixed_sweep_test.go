@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+// TestSweepComparesPooledAndMixedEstimatesOnPanels verifies that on a panel of stores observed
+// weekly the mixed model flags a pair whose pooled effect is a difference between stores,
+// while a pair that holds within stores agrees
+func TestSweepComparesPooledAndMixedEstimatesOnPanels(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	columns := map[string][]float64{}
+	for store := 0; store < 25; store++ {
+		size := rng.NormFloat64() * 3
+		baseline := 1.5*size + 3*rng.NormFloat64()
+		for week := 0; week < 8; week++ {
+			staff := size + rng.NormFloat64()
+			promo := rng.NormFloat64()
+			columns["store_id"] = append(columns["store_id"], float64(store))
+			columns["week"] = append(columns["week"], float64(week))
+			columns["staff"] = append(columns["staff"], staff)
+			columns["promo"] = append(columns["promo"], promo)
+			// Bigger stores sell more, but an extra shift within a store does not help
+			columns["sales"] = append(columns["sales"], baseline+3*promo+0.5*rng.NormFloat64())
+		}
+	}
+	bundle := sweepTestBundle(columns, []string{"store_id", "week", "staff", "promo", "sales"})
+	if entity, time, ok := bundle.PanelIndex(); !ok || entity != "store_id" || time != "week" {
+		t.Fatalf("panel index = %s, %s, %v", entity, time, ok)
+	}
+
+	svc := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil)
+	resp, err := svc.RunStatsSweep(context.Background(), StatsSweepRequest{MatrixBundle: bundle, MixedEffects: true})
+	if err != nil {
+		t.Fatalf("sweep failed: %v", err)
+	}
+
+	diverges := map[string]bool{}
+	for _, rel := range resp.Relationships {
+		payload := rel.Payload.(map[string]interface{})
+		pair := payload["cause_key"].(string) + "|" + payload["effect_key"].(string)
+		if flag, ok := payload["mixed_diverges"].(bool); ok {
+			diverges[pair] = flag
+		}
+	}
+	if flag, ok := diverges["staff|sales"]; !ok || !flag {
+		t.Errorf("between-store pair not flagged: %v", diverges)
+	}
+	if flag, ok := diverges["promo|sales"]; !ok || flag {
+		t.Errorf("within-store pair flagged or missing: %v", diverges)
+	}
+	mixed, ok := resp.Manifest.Payload.(map[string]interface{})["mixed_effects"].(map[string]interface{})
+	if !ok || mixed["entity"] != "store_id" || mixed["divergent_pairs"] != 1 {
+		t.Errorf("manifest mixed_effects = %v", mixed)
+	}
+
+	resp, err = svc.RunStatsSweep(context.Background(), StatsSweepRequest{MatrixBundle: bundle})
+	if err != nil {
+		t.Fatalf("sweep failed: %v", err)
+	}
+	for _, rel := range resp.Relationships {
+		if _, ok := rel.Payload.(map[string]interface{})["mixed_model"]; ok {
+			t.Fatalf("pair %v has a mixed model without the option", rel.ID)
+		}
+	}
+}
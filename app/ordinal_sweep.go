@@ -0,0 +1,85 @@
+package app
+
+import (
+	"fmt"
+	"math"
+
+	"gohypo/domain/dataset"
+	"gohypo/domain/stats"
+)
+
+// Test types of pairwise associations in the sweep manifest and artifacts
+const (
+	pearsonTestType = "pearson_correlation"
+	kendallTestType = "kendall_tau_b"
+	trendTestType   = "cochran_armitage_trend"
+)
+
+// ordinalVariables returns the columns typed ordinal. Their codes are ranks: the order is
+// meaningful but the spacing is not, so they are tested by rank rather than by Pearson.
+func ordinalVariables(bundle *dataset.MatrixBundle) map[string]bool {
+	ordinal := make(map[string]bool)
+	for _, meta := range bundle.ColumnMeta {
+		if meta.StatisticalType == dataset.TypeOrdinal {
+			if _, ok := bundle.GetColumn(meta.VariableKey); ok {
+				ordinal[string(meta.VariableKey)] = true
+			}
+		}
+	}
+	return ordinal
+}
+
+// pairTestType picks the test for a pair: Pearson unless a variable is ordinal, then the
+// trend test against a binary partner and Kendall's tau-b against anything else
+func pairTestType(xOrdinal, yOrdinal, xBinary, yBinary bool) string {
+	switch {
+	case !xOrdinal && !yOrdinal:
+		return pearsonTestType
+	case (xOrdinal && yBinary) || (yOrdinal && xBinary):
+		return trendTestType
+	default:
+		return kendallTestType
+	}
+}
+
+// calculateOrdinalAssociation tests a pair with an ordinal variable by the given test. For
+// the trend test the ordinal variable supplies the scores and the other the outcome.
+func (s *StatsSweepService) calculateOrdinalAssociation(bundle *dataset.MatrixBundle, col1, col2 int, testType string, xOrdinal bool) *CorrelationResult {
+	var values1, values2 []float64
+	for _, row := range bundle.Matrix.Data {
+		if col1 < len(row) && col2 < len(row) {
+			v1, v2 := row[col1], row[col2]
+			if !math.IsNaN(v1) && !math.IsNaN(v2) && !math.IsInf(v1, 0) && !math.IsInf(v2, 0) {
+				values1 = append(values1, v1)
+				values2 = append(values2, v2)
+			}
+		}
+	}
+	n := len(values1)
+	if n < 10 {
+		fmt.Printf("[StatsSweepService]     ❌ Insufficient sample size: %d (need ≥10)\n", n)
+		return nil
+	}
+
+	var association *stats.OrdinalAssociation
+	var err error
+	switch {
+	case testType == trendTestType && xOrdinal:
+		association, err = stats.TrendTest(values1, values2)
+	case testType == trendTestType:
+		association, err = stats.TrendTest(values2, values1)
+	default:
+		association, err = stats.KendallTauB(values1, values2)
+	}
+	if err != nil {
+		fmt.Printf("[StatsSweepService]     ❌ %s: %v\n", testType, err)
+		return &CorrelationResult{Coefficient: 0, PValue: 1.0, SampleSize: n, TestType: testType}
+	}
+	fmt.Printf("[StatsSweepService]     • %s: %.3f, p=%.6f, n=%d\n", testType, association.Coefficient, association.PValue, n)
+	return &CorrelationResult{
+		Coefficient: association.Coefficient,
+		PValue:      association.PValue,
+		SampleSize:  n,
+		TestType:    testType,
+	}
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+)
+
+// TestSweepRoutesOrdinalPairsToRankTests verifies pairs with an ordinal column are tested with
+// Kendall's tau-b, or the trend test against a binary column, rather than Pearson
+func TestSweepRoutesOrdinalPairsToRankTests(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	columns := map[string][]float64{}
+	for i := 0; i < 120; i++ {
+		level := float64(i % 4)
+		columns["satisfaction"] = append(columns["satisfaction"], level)
+		columns["revenue"] = append(columns["revenue"], 10*level+3*rng.NormFloat64())
+		churned := 0.0
+		if rng.Float64() < 0.8-0.2*level {
+			churned = 1
+		}
+		columns["churned"] = append(columns["churned"], churned)
+	}
+	bundle := sweepTestBundle(columns, []string{"satisfaction", "revenue", "churned"})
+	bundle.ColumnMeta = []dataset.ColumnMeta{
+		{VariableKey: core.VariableKey("satisfaction"), StatisticalType: dataset.TypeOrdinal},
+		{VariableKey: core.VariableKey("churned"), StatisticalType: dataset.TypeBinary},
+	}
+
+	resp, err := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil).RunStatsSweep(context.Background(), StatsSweepRequest{MatrixBundle: bundle})
+	if err != nil {
+		t.Fatalf("sweep failed: %v", err)
+	}
+
+	tests := map[string]string{}
+	for _, rel := range resp.Relationships {
+		payload := rel.Payload.(map[string]interface{})
+		tests[payload["cause_key"].(string)+"|"+payload["effect_key"].(string)] = payload["test_type"].(string)
+	}
+	if got := tests["satisfaction|revenue"]; got != kendallTestType {
+		t.Errorf("satisfaction vs revenue tested with %q, want %q", got, kendallTestType)
+	}
+	if got := tests["satisfaction|churned"]; got != trendTestType {
+		t.Errorf("satisfaction vs churned tested with %q, want %q", got, trendTestType)
+	}
+}
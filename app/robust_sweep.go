@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+	"math"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/domain/stats"
+)
+
+// robustComparison computes the robust estimates of a Pearson pair over the rows where both
+// variables are present, winsorizing by the pair's trim
+func (s *StatsSweepService) robustComparison(bundle *dataset.MatrixBundle, corr CorrelationResult, method stats.RobustMethod, trim float64) *stats.RobustComparison {
+	x, okX := bundle.GetColumnData(core.VariableKey(corr.Variable1))
+	y, okY := bundle.GetColumnData(core.VariableKey(corr.Variable2))
+	if !okX || !okY {
+		return nil
+	}
+	var xs, ys []float64
+	for i := range x {
+		if i < len(y) && !math.IsNaN(x[i]) && !math.IsNaN(y[i]) && !math.IsInf(x[i], 0) && !math.IsInf(y[i], 0) {
+			xs = append(xs, x[i])
+			ys = append(ys, y[i])
+		}
+	}
+	comparison, err := stats.CompareRobustTrimmed(xs, ys, corr.Coefficient, method, trim)
+	if err != nil {
+		fmt.Printf("[StatsSweepService]     ⚠️ Robust correlation for %s vs %s: %v\n", corr.Variable1, corr.Variable2, err)
+		return nil
+	}
+	return comparison
+}
+
+// addRobustPayload records the raw and robust effect sizes of a pair. robust_correlation is
+// the weakest robust estimate; outlier-driven pairs carry the OUTLIER_DRIVEN warning for the
+// stability stage to act on.
+func addRobustPayload(payload map[string]interface{}, comparison *stats.RobustComparison) {
+	weakest := comparison.Weakest()
+	payload["raw_correlation"] = comparison.Raw
+	payload["robust_correlation"] = weakest.Coefficient
+	payload["robust_p_value"] = weakest.PValue
+	payload["robust_estimates"] = comparison.Estimates
+	payload["outlier_driven"] = comparison.OutlierDriven
+	if comparison.OutlierDriven {
+		payload["warnings"] = []string{string(stats.WarningOutlierDriven)}
+	}
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"gohypo/domain/stats"
+)
+
+// TestSweepFlagsOutlierDrivenPairs verifies that with robust estimates requested a pair whose
+// Pearson effect comes from a few sensor spikes is flagged, while a genuine one is not
+func TestSweepFlagsOutlierDrivenPairs(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	columns := map[string][]float64{}
+	for i := 0; i < 100; i++ {
+		temperature := rng.NormFloat64()
+		pressure, vibration := rng.NormFloat64(), rng.NormFloat64()
+		if i%50 == 0 { // simultaneous spikes on both sensors
+			pressure, vibration = 40, 40
+		}
+		columns["temperature"] = append(columns["temperature"], temperature)
+		columns["humidity"] = append(columns["humidity"], 2*temperature+0.5*rng.NormFloat64())
+		columns["pressure"] = append(columns["pressure"], pressure)
+		columns["vibration"] = append(columns["vibration"], vibration)
+	}
+	bundle := sweepTestBundle(columns, []string{"temperature", "humidity", "pressure", "vibration"})
+
+	resp, err := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil).RunStatsSweep(context.Background(),
+		StatsSweepRequest{MatrixBundle: bundle, Robust: stats.RobustBoth})
+	if err != nil {
+		t.Fatalf("sweep failed: %v", err)
+	}
+
+	flagged := map[string]bool{}
+	for _, rel := range resp.Relationships {
+		payload := rel.Payload.(map[string]interface{})
+		if _, ok := payload["robust_correlation"]; !ok {
+			t.Fatalf("pair %v is missing robust effect sizes", rel.ID)
+		}
+		flagged[payload["cause_key"].(string)+"|"+payload["effect_key"].(string)] = payload["outlier_driven"].(bool)
+	}
+	if driven, ok := flagged["pressure|vibration"]; !ok || !driven {
+		t.Errorf("spike-driven pair not flagged: %v", flagged)
+	}
+	if driven, ok := flagged["temperature|humidity"]; !ok || driven {
+		t.Errorf("genuine pair flagged or missing: %v", flagged)
+	}
+	if n := resp.Manifest.Payload.(map[string]interface{})["outlier_driven_pairs"]; n != 1 {
+		t.Errorf("outlier_driven_pairs = %v, want 1", n)
+	}
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"math"
+	"time"
+
+	"gohypo/models"
+)
+
+// SummarizeSweep aggregates a completed sweep into the summary materialized for its run:
+// its relationships counted by test type, how many survive FDR correction, and the topN
+// strongest. Relationships without both variables are not counted.
+func SummarizeSweep(runID string, resp *StatsSweepResponse, topN int) *models.RunSummary {
+	manifest, _ := resp.Manifest.Payload.(map[string]interface{})
+	summary := &models.RunSummary{
+		RunID:             runID,
+		VariablesAnalyzed: int(numberValue(manifest["variables_analyzed"])),
+		CreatedAt:         time.Now(),
+	}
+	summary.SnapshotID, _ = manifest["snapshot_id"].(string)
+	summary.FDRMethod, _ = manifest["fdr_method"].(string)
+
+	effects := make([]models.RunEffect, 0, len(resp.Relationships))
+	for _, artifact := range resp.Relationships {
+		payload, ok := artifact.Payload.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		varX, okX := payload["cause_key"].(string)
+		varY, okY := payload["effect_key"].(string)
+		if !okX || !okY {
+			continue
+		}
+		testType, _ := payload["test_type"].(string)
+		if testType == "" {
+			testType = pearsonTestType
+		}
+		effect := models.RunEffect{
+			VariableX:  varX,
+			VariableY:  varY,
+			TestType:   testType,
+			EffectSize: numberValue(payload["correlation"]),
+			PValue:     numberValue(payload["p_value"]),
+			QValue:     numberValue(payload["q_value"]),
+			SampleSize: int(numberValue(payload["sample_size"])),
+		}
+		if percentile, ok := payload["noise_percentile"]; ok {
+			if value := numberValue(percentile); !math.IsNaN(value) {
+				effect.NoisePercentile = &value
+			}
+		}
+		effects = append(effects, effect)
+	}
+	summary.Summarize(effects, topN)
+	return summary
+}
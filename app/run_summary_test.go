@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"gohypo/domain/stats"
+)
+
+// TestSummarizeSweep verifies a sweep's summary counts every relationship it found
+func TestSummarizeSweep(t *testing.T) {
+	columns := map[string][]float64{}
+	for i := 0; i < 40; i++ {
+		x := float64(i)
+		columns["price"] = append(columns["price"], x)
+		columns["total"] = append(columns["total"], 2*x+float64(i%3))
+		columns["count"] = append(columns["count"], float64((i*7)%11))
+	}
+	svc := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil)
+	resp, err := svc.RunStatsSweep(context.Background(), StatsSweepRequest{MatrixBundle: sweepTestBundle(columns, []string{"price", "total", "count"})})
+	if err != nil {
+		t.Fatalf("sweep failed: %v", err)
+	}
+
+	summary := SummarizeSweep("sweep-test", resp, 1)
+	if summary.Relationships != len(resp.Relationships) || summary.VariablesAnalyzed != 3 {
+		t.Fatalf("summary = %+v, sweep found %d relationships", summary, len(resp.Relationships))
+	}
+	if len(summary.TestTypes) != 1 || summary.TestTypes[0].TestType != pearsonTestType {
+		t.Errorf("test types = %+v", summary.TestTypes)
+	}
+	if len(summary.TopEffects) != 1 || summary.TopEffects[0].VariableX != "price" || summary.TopEffects[0].VariableY != "total" {
+		t.Errorf("top effects = %+v", summary.TopEffects)
+	}
+}
+
+// TestSummarizeSweepPlacesEffectsAgainstNoiseFloor verifies a sweep with a calibrated noise
+// floor carries each correlation's percentile through to the summary's top effects
+func TestSummarizeSweepPlacesEffectsAgainstNoiseFloor(t *testing.T) {
+	columns := map[string][]float64{}
+	for i := 0; i < 40; i++ {
+		x := float64(i)
+		columns["price"] = append(columns["price"], x)
+		columns["total"] = append(columns["total"], 2*x+float64(i%3))
+		columns["count"] = append(columns["count"], float64((i*7)%11))
+	}
+	keys := []string{"price", "total", "count"}
+	floor, err := stats.CalibrateNoiseFloor([][]float64{columns["price"], columns["total"], columns["count"]}, stats.NoiseFloorOptions{Permutations: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil)
+	resp, err := svc.RunStatsSweep(context.Background(), StatsSweepRequest{MatrixBundle: sweepTestBundle(columns, keys), NoiseFloor: floor})
+	if err != nil {
+		t.Fatalf("sweep failed: %v", err)
+	}
+	if manifest := resp.Manifest.Payload.(map[string]interface{}); manifest["noise_floor"] == nil {
+		t.Error("expected the manifest to record the noise floor")
+	}
+
+	summary := SummarizeSweep("sweep-test", resp, 1)
+	if len(summary.TopEffects) != 1 || summary.TopEffects[0].NoisePercentile == nil || *summary.TopEffects[0].NoisePercentile != 100 {
+		t.Fatalf("expected the price-total effect above the whole noise floor, got %+v", summary.TopEffects)
+	}
+}
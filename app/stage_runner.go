@@ -1,6 +1,12 @@
 package app
 
 import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"gohypo/domain/dataset"
 	"gohypo/ports"
 )
 
@@ -17,3 +23,98 @@ func NewStageRunner(ledgerPort ports.LedgerPort, rngPort ports.RNGPort) *StageRu
 		rngPort:    rngPort,
 	}
 }
+
+// PairTask identifies one variable pair within a pairwise stage
+type PairTask struct {
+	Index int    // Position in the deterministic (i < j) pair ordering
+	VarX  string // First variable key
+	VarY  string // Second variable key
+	ColX  int    // Column index of VarX in the matrix
+	ColY  int    // Column index of VarY in the matrix
+}
+
+// Key returns the stable relationship key checkpoints record the pair under
+func (t PairTask) Key() string {
+	return t.VarX + "|" + t.VarY
+}
+
+// RunPairs fans fn out over a bounded worker pool. Callers write results into a
+// slice at task.Index, so output order never depends on scheduling. The pairwise tests
+// draw no random numbers, so no per-pair RNG is handed out.
+func (r *StageRunner) RunPairs(ctx context.Context, tasks []PairTask, numWorkers int, fn func(task PairTask)) error {
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+	if numWorkers > len(tasks) {
+		numWorkers = len(tasks)
+	}
+
+	jobs := make(chan PairTask)
+	var wg sync.WaitGroup
+
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range jobs {
+				fn(task)
+			}
+		}()
+	}
+
+	var dispatchErr error
+dispatch:
+	for _, task := range tasks {
+		select {
+		case <-ctx.Done():
+			dispatchErr = ctx.Err()
+			break dispatch
+		case jobs <- task:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if dispatchErr == nil {
+		dispatchErr = ctx.Err()
+	}
+	return dispatchErr
+}
+
+// StreamPairs runs fn over the pairs like RunPairs, acquiring each pair's columns from
+// source just before fn and releasing them right after it. With a disk-backed source only
+// the columns of pairs in flight, plus whatever the source caches, are held in memory.
+// The first column that cannot be acquired stops the run and is returned.
+func (r *StageRunner) StreamPairs(ctx context.Context, source dataset.ColumnSource, tasks []PairTask, numWorkers int, fn func(task PairTask, x, y []float64)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var errOnce sync.Once
+	var acquireErr error
+	fail := func(err error) {
+		errOnce.Do(func() {
+			acquireErr = err
+			cancel()
+		})
+	}
+
+	err := r.RunPairs(ctx, tasks, numWorkers, func(task PairTask) {
+		x, releaseX, err := source.AcquireColumn(task.ColX)
+		if err != nil {
+			fail(fmt.Errorf("failed to read %s: %w", task.VarX, err))
+			return
+		}
+		defer releaseX()
+		y, releaseY, err := source.AcquireColumn(task.ColY)
+		if err != nil {
+			fail(fmt.Errorf("failed to read %s: %w", task.VarY, err))
+			return
+		}
+		defer releaseY()
+		fn(task, x, y)
+	})
+	if acquireErr != nil {
+		return acquireErr
+	}
+	return err
+}
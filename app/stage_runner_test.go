@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"testing"
+)
+
+// TestRunPairsDeterministic verifies results land at their pair's index whatever the worker count
+func TestRunPairsDeterministic(t *testing.T) {
+	tasks := make([]PairTask, 0)
+	vars := []string{"a", "b", "c", "d", "e", "f"}
+	for i := 0; i < len(vars); i++ {
+		for j := i + 1; j < len(vars); j++ {
+			tasks = append(tasks, PairTask{Index: len(tasks), VarX: vars[i], VarY: vars[j], ColX: i, ColY: j})
+		}
+	}
+
+	run := func(workers int) []string {
+		runner := NewStageRunner(nil, nil)
+		out := make([]string, len(tasks))
+		err := runner.RunPairs(context.Background(), tasks, workers, func(task PairTask) {
+			out[task.Index] = task.Key()
+		})
+		if err != nil {
+			t.Fatalf("RunPairs failed: %v", err)
+		}
+		return out
+	}
+
+	serial := run(1)
+	parallel := run(8)
+	for i := range serial {
+		if serial[i] != parallel[i] {
+			t.Fatalf("pair %d: serial result %s != parallel result %s", i, serial[i], parallel[i])
+		}
+	}
+}
+
+// TestRunPairsCancelled verifies a cancelled context stops dispatch
+func TestRunPairsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	runner := NewStageRunner(nil, nil)
+	tasks := []PairTask{{Index: 0, VarX: "a", VarY: "b"}}
+	err := runner.RunPairs(ctx, tasks, 1, func(task PairTask) {})
+	if err == nil {
+		t.Fatal("expected context error")
+	}
+}
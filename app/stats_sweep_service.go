@@ -5,14 +5,103 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"sync"
+	"time"
+
 	"gohypo/domain/core"
 	"gohypo/domain/dataset"
+	"gohypo/domain/stage"
+	"gohypo/domain/stats"
+	"gohypo/internal/buildinfo"
+	"gohypo/internal/metrics"
 	"gohypo/ports"
 )
 
 // StatsSweepRequest represents a request to run statistical analysis
 type StatsSweepRequest struct {
 	MatrixBundle *dataset.MatrixBundle `json:"matrix_bundle"`
+	Rigor        stage.RigorProfile    `json:"rigor,omitempty"`       // selects the default FDR method
+	FDRMethod    stats.FDRMethod       `json:"fdr_method,omitempty"`  // explicit override of the rigor default
+	Inference    stats.InferenceMode   `json:"inference,omitempty"`   // frequentist, bayesian or both; overrides the rigor default
+	NumWorkers   int                   `json:"num_workers,omitempty"` // pairwise worker pool size (0 = NumCPU)
+	Seed         int64                 `json:"seed,omitempty"`        // base seed for per-pair RNG streams
+
+	// Robust adds outlier-resistant estimates (winsorized, biweight or both) next to each
+	// Pearson effect and flags pairs whose effect the bulk of the data does not carry
+	Robust stats.RobustMethod `json:"robust,omitempty"`
+
+	// MixedEffects adds, on panel matrices with an entity and a time column, a linear mixed
+	// model with a random intercept per entity next to each Pearson effect, and flags pairs
+	// whose pooled and within-entity effects diverge
+	MixedEffects bool `json:"mixed_effects,omitempty"`
+
+	// Imputations runs Pearson pairs on that many imputed copies of the matrix and pools them
+	// by Rubin's rules, for matrices with too many gaps for complete cases (0 = complete cases
+	// per pair). Imputed sweeps never reuse baseline pairs.
+	Imputations int `json:"imputations,omitempty"`
+
+	// TimeSeries runs the stationarity-checked lagged sweep when the matrix has a time index
+	// (auto, the default) or never (off); MaxLag bounds the lags searched (0 = defaultMaxLag)
+	TimeSeries TimeSeriesMode `json:"time_series,omitempty"`
+	MaxLag     int            `json:"max_lag,omitempty"`
+
+	// RunID enables checkpointing: completed pairs are written to the ledger in batches of
+	// CheckpointEvery, and a rerun with the same run ID, bundle and seed resumes from them
+	RunID           core.RunID `json:"run_id,omitempty"`
+	CheckpointEvery int        `json:"checkpoint_every,omitempty"`
+
+	// Baseline enables incremental mode: pairs whose variables are unchanged since the
+	// baseline sweep reuse its relationship artifacts instead of being recomputed
+	Baseline *SweepBaseline `json:"baseline,omitempty"`
+
+	// AllowIncompatible reuses baseline results and checkpoints even when they were produced
+	// by method versions this build considers incompatible
+	AllowIncompatible bool `json:"allow_incompatible,omitempty"`
+
+	// Profiles are the readiness profiles of the variables, from which each variable's test
+	// parameters are chosen; variables without one are profiled from the matrix
+	Profiles map[core.VariableKey]stats.VariableProfile `json:"profiles,omitempty"`
+
+	// Policy holds the workspace's significance and effect-size cutoffs; zero fields take
+	// the defaults
+	Policy stats.AnalysisPolicy `json:"policy"`
+
+	// NoiseFloor is the workspace's calibrated null distribution of |r|; when set, each
+	// correlation records its percentile against it
+	NoiseFloor *stats.NoiseFloor `json:"noise_floor,omitempty"`
+
+	// OnProgress is called after each pair completes (serialized; safe to draw UI from)
+	OnProgress func(completed, total int) `json:"-"`
+}
+
+// resolveFDRMethod picks the correction method: explicit option first, then rigor profile
+func (r StatsSweepRequest) resolveFDRMethod() (stats.FDRMethod, error) {
+	if r.FDRMethod != "" {
+		return stats.ParseFDRMethod(string(r.FDRMethod))
+	}
+	return r.Rigor.FDRMethod(), nil
+}
+
+// resolveInferenceMode picks frequentist, Bayesian or both: explicit option first, then rigor profile
+func (r StatsSweepRequest) resolveInferenceMode() (stats.InferenceMode, error) {
+	if r.Inference != "" {
+		return stats.ParseInferenceMode(string(r.Inference))
+	}
+	return r.Rigor.InferenceMode(), nil
+}
+
+// resolvePolicy fills the analysis policy's unset cutoffs with the defaults and checks it
+func (r StatsSweepRequest) resolvePolicy() (stats.AnalysisPolicy, error) {
+	policy := r.Policy.WithDefaults()
+	if err := policy.Validate(); err != nil {
+		return policy, fmt.Errorf("invalid analysis policy: %w", err)
+	}
+	return policy, nil
+}
+
+// resolveRobustMethod picks the robust estimators; none unless requested
+func (r StatsSweepRequest) resolveRobustMethod() (stats.RobustMethod, error) {
+	return stats.ParseRobustMethod(string(r.Robust))
 }
 
 // StatsSweepResponse represents the result of statistical analysis
@@ -38,10 +127,36 @@ func NewStatsSweepService(stageRunner *StageRunner, ledgerPort ports.LedgerPort,
 }
 
 // RunStatsSweep executes statistical analysis on the provided matrix bundle
-func (s *StatsSweepService) RunStatsSweep(ctx context.Context, req StatsSweepRequest) (*StatsSweepResponse, error) {
+func (s *StatsSweepService) RunStatsSweep(ctx context.Context, req StatsSweepRequest) (resp *StatsSweepResponse, err error) {
 	if req.MatrixBundle == nil {
 		return nil, fmt.Errorf("matrix bundle cannot be nil")
 	}
+	fdrMethod, err := req.resolveFDRMethod()
+	if err != nil {
+		return nil, err
+	}
+	inference, err := req.resolveInferenceMode()
+	if err != nil {
+		return nil, err
+	}
+	robust, err := req.resolveRobustMethod()
+	if err != nil {
+		return nil, err
+	}
+	if req.Policy, err = req.resolvePolicy(); err != nil {
+		return nil, err
+	}
+	if req.Imputations != 0 && (req.Imputations < 2 || req.Imputations > maxImputations) {
+		return nil, fmt.Errorf("imputations must be between 2 and %d, got %d", maxImputations, req.Imputations)
+	}
+
+	start := time.Now()
+	mode := "full"
+	defer func() { observeSweep(mode, start, err) }()
+	if timeKey, ok := req.timeIndex(); ok {
+		mode = "time_series"
+		return s.runTimeSeriesSweep(ctx, req, timeKey, fdrMethod, inference)
+	}
 
 	fmt.Printf("[StatsSweepService] 🔬 Starting statistical analysis\n")
 	fmt.Printf("[StatsSweepService]   • Matrix entities: %d\n", len(req.MatrixBundle.Matrix.EntityIDs))
@@ -59,44 +174,186 @@ func (s *StatsSweepService) RunStatsSweep(ctx context.Context, req StatsSweepReq
 		}
 	}
 
+	// Build the column store before the workers share the bundle, so pairs read
+	// contiguous columns without copying
+	req.MatrixBundle.Columnarize()
+
+	// Compare column fingerprints against the baseline to find reusable pairs
+	fingerprints := req.MatrixBundle.ColumnFingerprints()
+	fingerprint := bundleFingerprint(req.MatrixBundle, fingerprints)
+	baselineManifest := req.Baseline
+	if req.Imputations > 0 && baselineManifest != nil {
+		fmt.Printf("[StatsSweepService] ⚠️ Imputed sweeps pool every pair afresh, ignoring the baseline\n")
+		baselineManifest = nil
+	}
+	baseline, err := newSweepBaseline(baselineManifest, fingerprints, req.Policy, req.AllowIncompatible)
+	if err != nil {
+		return nil, err
+	}
+	if baseline != nil {
+		mode = "incremental"
+		fmt.Printf("[StatsSweepService] ♻️ Incremental sweep: %d changed variables\n", len(baseline.changed))
+	} else if baselineManifest != nil {
+		fmt.Printf("[StatsSweepService] ⚠️ Baseline manifest cannot be reused, running full sweep\n")
+	}
+
 	// Perform correlation analysis between numeric variables
-	correlations := s.analyzeCorrelations(req.MatrixBundle)
-	fmt.Printf("[StatsSweepService] 📊 Found %d correlations\n", len(correlations))
+	pairsStart := time.Now()
+	correlations, counts, err := s.analyzeCorrelations(ctx, req, baseline, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("pairwise sweep failed: %w", err)
+	}
+	observePairs(mode, counts, time.Since(pairsStart))
+	fmt.Printf("[StatsSweepService] 📊 Found %d correlations (%d pairs computed, %d reused, %d resumed)\n", len(correlations), counts.computed, counts.reused, counts.resumed)
+
+	// Compare numeric variables across the levels of categorical ones
+	defaults := variableDefaults(req.MatrixBundle, req.Profiles)
+	groups := s.analyzeGroupDifferences(req.MatrixBundle, categoricalVariables(req.MatrixBundle), defaults, req.Policy)
+	if len(groups) > 0 {
+		fmt.Printf("[StatsSweepService] 📊 Found %d group differences\n", len(groups))
+	}
+	totalComparisons := len(correlations) + len(groups)
 
+	// Correlations and group differences are corrected as one family
+	pValues := make([]float64, 0, totalComparisons)
 	for _, corr := range correlations {
-		fmt.Printf("[StatsSweepService]   • Correlation: %s vs %s = %.3f (p=%.6f, n=%d)\n",
-			corr.Variable1, corr.Variable2, corr.Coefficient, corr.PValue, corr.SampleSize)
+		pValues = append(pValues, corr.PValue)
+	}
+	for _, group := range groups {
+		pValues = append(pValues, group.Comparison.ANOVAPValue)
+	}
+	qValues := stats.AdjustPValues(fdrMethod, pValues)
+
+	outlierDriven, mixedDivergent := 0, 0
+	panel := req.panel()
+	for i, corr := range correlations {
+		fmt.Printf("[StatsSweepService]   • Correlation (%s): %s vs %s = %.3f (p=%.6f, n=%d)\n",
+			corr.testType(), corr.Variable1, corr.Variable2, corr.Coefficient, corr.PValue, corr.SampleSize)
+		payload := map[string]interface{}{
+			"evidence_id":       fmt.Sprintf("assoc_%03d", len(relationships)+1),
+			"cause_key":         corr.Variable1,
+			"effect_key":        corr.Variable2,
+			"correlation":       corr.Coefficient,
+			"p_value":           corr.PValue,
+			"q_value":           qValues[i],
+			"sample_size":       corr.SampleSize,
+			"confidence_level":  req.Policy.EvidenceLevel(corr.PValue),
+			"practical_significance": req.Policy.PracticalSignificance(corr.Coefficient),
+			"test_type":         corr.testType(),
+			"fdr_method":        string(fdrMethod),
+			"total_comparisons": totalComparisons,
+		}
+		payload["inference_mode"] = string(inference)
+		payload["test_params"] = correlationTestParams(defaults[corr.Variable1], defaults[corr.Variable2])
+		if req.NoiseFloor != nil {
+			if percentile := req.NoiseFloor.Percentile(corr.Coefficient); !math.IsNaN(percentile) {
+				payload["noise_percentile"] = percentile
+			}
+		}
+		if corr.Pooled != nil {
+			addPooledPayload(payload, corr.Pooled)
+		}
+		if robust != stats.RobustOff && corr.testType() == pearsonTestType {
+			if comparison := s.robustComparison(req.MatrixBundle, corr, robust, stats.PairWinsorTrim(defaults[corr.Variable1], defaults[corr.Variable2])); comparison != nil {
+				addRobustPayload(payload, comparison)
+				if comparison.OutlierDriven {
+					outlierDriven++
+				}
+			}
+		}
+		if panel != nil && corr.testType() == pearsonTestType && !panel.indexes(corr.Variable1) && !panel.indexes(corr.Variable2) {
+			if comparison := s.mixedComparison(req.MatrixBundle, corr, panel); comparison != nil {
+				addMixedPayload(payload, comparison, panel)
+				if comparison.Diverges {
+					mixedDivergent++
+				}
+			}
+		}
+		if inference.Bayesian() && corr.testType() != kendallTestType {
+			if estimate := s.bayesianEstimate(req.MatrixBundle, corr); estimate != nil {
+				payload["bayesian"] = estimate
+				payload["bayes_factor_10"] = estimate.BayesFactor10
+				if inference == stats.InferenceBayesian {
+					payload["confidence_level"] = s.calculateBayesianConfidenceLevel(estimate.BayesFactor10)
+				}
+			}
+		}
 		relationships = append(relationships, core.Artifact{
-			ID:   core.ID(fmt.Sprintf("corr_%s_%s", corr.Variable1, corr.Variable2)),
-			Kind: "association",
-			Payload: map[string]interface{}{
-				"evidence_id":       fmt.Sprintf("assoc_%03d", len(relationships)+1),
-				"cause_key":         corr.Variable1,
-				"effect_key":        corr.Variable2,
-				"correlation":       corr.Coefficient,
-				"p_value":           corr.PValue,
-				"sample_size":       corr.SampleSize,
-				"confidence_level":  s.calculateConfidenceLevel(corr.PValue),
-				"practical_significance": s.calculatePracticalSignificance(math.Abs(corr.Coefficient)),
-				"test_type":         "pearson_correlation",
-				"fdr_method":        "bh", // Benjamini-Hochberg
-				"total_comparisons": len(correlations),
-			},
+			ID:        core.ID(fmt.Sprintf("corr_%s_%s", corr.Variable1, corr.Variable2)),
+			Kind:      "association",
+			Payload:   payload,
+			CreatedAt: core.Now(),
+		})
+	}
+
+	for i, group := range groups {
+		fmt.Printf("[StatsSweepService]   • Group difference: %s by %s, η²=%.3f (ANOVA p=%.6f, Kruskal-Wallis p=%.6f, n=%d)\n",
+			group.Numeric, group.Categorical, group.Comparison.EtaSquared, group.Comparison.ANOVAPValue,
+			group.Comparison.KruskalPValue, group.Comparison.SampleSize)
+		payload := s.groupDifferencePayload(req.Policy, group, fmt.Sprintf("assoc_%03d", len(relationships)+1), qValues[len(correlations)+i])
+		payload["fdr_method"] = string(fdrMethod)
+		payload["total_comparisons"] = totalComparisons
+		payload["inference_mode"] = string(inference)
+		payload["test_params"] = groupTestParams(defaults[group.Categorical], defaults[group.Numeric])
+		relationships = append(relationships, core.Artifact{
+			ID:        core.ID(fmt.Sprintf("anova_%s_%s", group.Categorical, group.Numeric)),
+			Kind:      "association",
+			Payload:   payload,
 			CreatedAt: core.Now(),
 		})
 	}
 
 	// Create manifest
+	manifestPayload := map[string]interface{}{
+		"status": "completed",
+		"relationships_found": len(relationships),
+		"variables_analyzed": len(req.MatrixBundle.Matrix.VariableKeys),
+		"entities_analyzed": len(req.MatrixBundle.Matrix.EntityIDs),
+		"fdr_method": string(fdrMethod),
+		"inference_mode": string(inference),
+		"robust_method": string(robust),
+		"outlier_driven_pairs": outlierDriven,
+		"imputations": req.Imputations,
+		"seed": req.Seed,
+		"analysis_policy": req.Policy,
+		"total_comparisons": totalComparisons,
+		"group_comparisons": len(groups),
+		"mode": mode,
+		"pairs_computed": counts.computed,
+		"pairs_reused": counts.reused,
+		"pairs_resumed": counts.resumed,
+		"run_id": string(req.RunID),
+		"changed_variables": baseline.changedVariables(),
+		"column_fingerprints": fingerprintPayload(fingerprints),
+		"bundle_fingerprint": string(fingerprint),
+		"method_versions": buildinfo.Methods(),
+		"code_version": buildinfo.Get().Version,
+		"analysis_timestamp": core.Now(),
+	}
+	// Columns of a matrix joined from several datasets carry the dataset each came from
+	if origins := req.MatrixBundle.ColumnOrigins(); origins != nil {
+		manifestPayload["column_origins"] = origins
+	}
+	if panel != nil {
+		manifestPayload["mixed_effects"] = map[string]interface{}{
+			"entity":          string(panel.entity),
+			"time":            string(panel.time),
+			"divergent_pairs": mixedDivergent,
+		}
+	}
+	if req.NoiseFloor != nil {
+		manifestPayload["noise_floor"] = map[string]interface{}{
+			"dataset_id":    req.NoiseFloor.DatasetID,
+			"calibrated_at": req.NoiseFloor.CalibratedAt,
+			"permutations":  req.NoiseFloor.Permutations,
+			"samples":       req.NoiseFloor.Samples,
+			"p95":           req.NoiseFloor.Level(95),
+		}
+	}
 	manifest := core.Artifact{
-		ID:   core.ID("stats_sweep_manifest"),
-		Kind: "sweep_manifest",
-		Payload: map[string]interface{}{
-			"status": "completed",
-			"relationships_found": len(relationships),
-			"variables_analyzed": len(req.MatrixBundle.Matrix.VariableKeys),
-			"entities_analyzed": len(req.MatrixBundle.Matrix.EntityIDs),
-			"analysis_timestamp": core.Now(),
-		},
+		ID:        core.ID("stats_sweep_manifest"),
+		Kind:      "sweep_manifest",
+		Payload:   manifestPayload,
 		CreatedAt: core.Now(),
 	}
 
@@ -106,6 +363,25 @@ func (s *StatsSweepService) RunStatsSweep(ctx context.Context, req StatsSweepReq
 	}, nil
 }
 
+// observeSweep records a finished sweep's duration by mode and outcome
+func observeSweep(mode string, start time.Time, err error) {
+	outcome := "completed"
+	if err != nil {
+		outcome = "failed"
+	}
+	metrics.SweepDuration.Observe(metrics.Since(start), mode, outcome)
+}
+
+// observePairs records how a sweep's pairs were obtained and how fast they were computed
+func observePairs(mode string, counts pairCounts, elapsed time.Duration) {
+	metrics.SweepPairs.Add(float64(counts.computed), "computed")
+	metrics.SweepPairs.Add(float64(counts.reused), "reused")
+	metrics.SweepPairs.Add(float64(counts.resumed), "resumed")
+	if counts.computed > 0 && elapsed > 0 {
+		metrics.SweepPairsPerSecond.Set(float64(counts.computed)/elapsed.Seconds(), mode)
+	}
+}
+
 // CorrelationResult holds the result of correlation analysis between two variables
 type CorrelationResult struct {
 	Variable1    string
@@ -113,23 +389,53 @@ type CorrelationResult struct {
 	Coefficient  float64
 	PValue       float64
 	SampleSize   int
+	TestType     string // pearson_correlation when empty; see pairTestType
+	Pooled       *stats.PooledEstimate // set when pooled over imputations
 }
 
-// analyzeCorrelations performs Pearson correlation analysis on numeric variables
-func (s *StatsSweepService) analyzeCorrelations(bundle *dataset.MatrixBundle) []CorrelationResult {
-	results := []CorrelationResult{}
+// testType returns the test the pair was analyzed with
+func (c CorrelationResult) testType() string {
+	if c.TestType == "" {
+		return pearsonTestType
+	}
+	return c.TestType
+}
 
+// meaningful reports whether the association is strong enough to keep under the policy
+func (c CorrelationResult) meaningful(policy stats.AnalysisPolicy) bool {
+	if c.testType() == kendallTestType {
+		return math.Abs(c.Coefficient) > policy.MinKendallTau()
+	}
+	return policy.Meaningful(c.Coefficient)
+}
+
+// pairCounts records how many pairs were computed, reused from a baseline, or
+// restored from checkpoints of an interrupted run
+type pairCounts struct {
+	computed int
+	reused   int
+	resumed  int
+}
+
+// analyzeCorrelations performs Pearson correlation analysis on numeric variables,
+// fanning pairs out over the stage runner's worker pool. With a baseline, pairs
+// between unchanged variables are taken from the prior run; with a run ID, pairs
+// already checkpointed for the same bundle and seed are restored from the ledger.
+func (s *StatsSweepService) analyzeCorrelations(ctx context.Context, req StatsSweepRequest, baseline *sweepBaseline, fingerprint core.Hash) ([]CorrelationResult, pairCounts, error) {
+	bundle := req.MatrixBundle
 	fmt.Printf("[StatsSweepService] 🔍 Analyzing correlations...\n")
 
-	// Get numeric columns only
+	// Get numeric and ordinal columns; categorical codes are compared across levels instead
 	numericVars := []string{}
 	varIndices := make(map[string]int)
+	categorical := categoricalVariables(bundle)
+	ordinal := ordinalVariables(bundle)
 
 	fmt.Printf("[StatsSweepService]   • Checking %d variables for numeric types:\n", len(bundle.Matrix.VariableKeys))
 	for i, key := range bundle.Matrix.VariableKeys {
 		// Simple heuristic: check if variable name suggests numeric data
 		varName := string(key)
-		isNumeric := s.isLikelyNumeric(varName)
+		isNumeric := !categorical[varName] && (ordinal[varName] || s.isLikelyNumeric(varName))
 		fmt.Printf("[StatsSweepService]     - %s: %s\n", varName, map[bool]string{true: "numeric", false: "non-numeric"}[isNumeric])
 		if isNumeric {
 			numericVars = append(numericVars, varName)
@@ -139,22 +445,130 @@ func (s *StatsSweepService) analyzeCorrelations(bundle *dataset.MatrixBundle) []
 
 	fmt.Printf("[StatsSweepService]   • Found %d potentially numeric variables\n", len(numericVars))
 
-	// Analyze correlations between numeric variables
+	// Pairs with an ordinal variable are tested by rank; binary partners get a trend test
+	binary := make(map[string]bool)
+	if len(ordinal) > 0 {
+		for _, meta := range bundle.ColumnMeta {
+			if meta.StatisticalType == dataset.TypeBinary {
+				binary[string(meta.VariableKey)] = true
+			}
+		}
+		for _, name := range numericVars {
+			if column, ok := bundle.GetColumnData(core.VariableKey(name)); ok && !ordinal[name] {
+				if _, isBinary := binaryHigh(column); isBinary {
+					binary[name] = true
+				}
+			}
+		}
+	}
+
+	// Enumerate pairs in upper-triangle order; this order is the output order
+	tasks := make([]PairTask, 0, len(numericVars)*(len(numericVars)-1)/2)
 	for i := 0; i < len(numericVars); i++ {
 		for j := i + 1; j < len(numericVars); j++ {
-			var1 := numericVars[i]
-			var2 := numericVars[j]
-
-			result := s.calculateCorrelation(bundle, varIndices[var1], varIndices[var2])
-			if result != nil && math.Abs(result.Coefficient) > 0.3 { // Only include meaningful correlations
-				result.Variable1 = var1
-				result.Variable2 = var2
-				results = append(results, *result)
+			tasks = append(tasks, PairTask{
+				Index: len(tasks),
+				VarX:  numericVars[i],
+				VarY:  numericVars[j],
+				ColX:  varIndices[numericVars[i]],
+				ColY:  varIndices[numericVars[j]],
+			})
+		}
+	}
+
+	runner := s.stageRunner
+	if runner == nil {
+		runner = NewStageRunner(s.ledgerPort, s.rngPort)
+	}
+
+	// Imputed runs test every Pearson pair on the same m completed matrices
+	var imputed [][][]float64
+	checkpointStage := "pairwise"
+	if req.Imputations > 0 {
+		discrete := make(map[string]bool)
+		for name := range categorical {
+			discrete[name] = true
+		}
+		for name := range ordinal {
+			discrete[name] = true
+		}
+		for _, meta := range bundle.ColumnMeta {
+			if meta.StatisticalType == dataset.TypeBinary {
+				discrete[string(meta.VariableKey)] = true
 			}
 		}
+		imputed = imputeMatrices(bundle, req.Imputations, req.Seed, discrete)
+		checkpointStage = fmt.Sprintf("pairwise_mi%d", req.Imputations)
+		fmt.Printf("[StatsSweepService] 🎲 Drew %d imputed matrices\n", req.Imputations)
+	}
+
+	checkpointer := newSweepCheckpointer(s.ledgerPort, req.RunID, checkpointStage, fingerprint, req.Seed, len(tasks), req.CheckpointEvery, req.AllowIncompatible)
+	restored, err := checkpointer.restore(ctx)
+	if err != nil {
+		return nil, pairCounts{}, err
+	}
+	if len(restored) > 0 {
+		fmt.Printf("[StatsSweepService] ⏯️ Resuming run %s with %d checkpointed pairs\n", req.RunID, len(restored))
+	}
+
+	pairResults := make([]*CorrelationResult, len(tasks))
+	pending := make([]PairTask, 0, len(tasks))
+	var counts pairCounts
+	for _, task := range tasks {
+		if baseline.reusable(task) {
+			pairResults[task.Index] = baseline.result(task)
+			counts.reused++
+			continue
+		}
+		if result, ok := restored[task.Index]; ok {
+			pairResults[task.Index] = result
+			counts.resumed++
+			continue
+		}
+		pending = append(pending, task)
+	}
+	counts.computed = len(pending)
+
+	onProgress := req.OnProgress
+	var progressMu sync.Mutex
+	completed := 0
+	err = runner.RunPairs(ctx, pending, req.NumWorkers, func(task PairTask) {
+		var result *CorrelationResult
+		if testType := pairTestType(ordinal[task.VarX], ordinal[task.VarY], binary[task.VarX], binary[task.VarY]); testType == pearsonTestType && imputed != nil {
+			result = s.calculatePooledCorrelation(imputed, task.ColX, task.ColY)
+		} else if testType == pearsonTestType {
+			result = s.calculateCorrelation(bundle, task.ColX, task.ColY)
+		} else {
+			result = s.calculateOrdinalAssociation(bundle, task.ColX, task.ColY, testType, ordinal[task.VarX])
+		}
+		if result != nil {
+			result.Variable1 = task.VarX
+			result.Variable2 = task.VarY
+		}
+		pairResults[task.Index] = result
+
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		checkpointer.record(ctx, task, result)
+		completed++
+		if onProgress != nil {
+			onProgress(completed, len(pending))
+		}
+	})
+	// Flush the partial batch even when interrupted so a resume loses nothing
+	checkpointer.flush(context.WithoutCancel(ctx))
+	if err != nil {
+		return nil, counts, err
 	}
 
-	return results
+	results := []CorrelationResult{}
+	for _, result := range pairResults {
+		if result != nil && result.meaningful(req.Policy) { // Only include meaningful correlations
+			results = append(results, *result)
+		}
+	}
+
+	return results, counts, nil
 }
 
 // calculateCorrelation computes Pearson correlation between two columns
@@ -164,39 +578,38 @@ func (s *StatsSweepService) calculateCorrelation(bundle *dataset.MatrixBundle, c
 		return nil
 	}
 
-	// Extract values for both columns, filtering out NaN/null values
-	values1 := []float64{}
-	values2 := []float64{}
-
 	fmt.Printf("[StatsSweepService]     • Processing %d rows for columns %d and %d\n", len(bundle.Matrix.Data), col1, col2)
 
-	validRows := 0
-	for i, row := range bundle.Matrix.Data {
-		if i >= 5 { // Only check first few rows for debugging
-			break
-		}
-		if col1 < len(row) && col2 < len(row) {
-			v1 := row[col1]
-			v2 := row[col2]
-			fmt.Printf("[StatsSweepService]       Row %d: col%d=%.3f, col%d=%.3f\n", i, col1, v1, col2, v2)
-		}
+	// Short rows read as NaN in the column views and are skipped by correlateColumns
+	x, y := bundle.ColumnAt(col1), bundle.ColumnAt(col2)
+	if x == nil || y == nil {
+		return nil
 	}
 
-	for _, row := range bundle.Matrix.Data {
-		if col1 < len(row) && col2 < len(row) {
-			v1 := row[col1]
-			v2 := row[col2]
+	for i := 0; i < len(x) && i < 5; i++ { // Only check first few rows for debugging
+		fmt.Printf("[StatsSweepService]       Row %d: col%d=%.3f, col%d=%.3f\n", i, col1, x[i], col2, y[i])
+	}
+	return s.correlateColumns(x, y)
+}
 
-			// Skip if either value is NaN or invalid
-			if !math.IsNaN(v1) && !math.IsNaN(v2) && !math.IsInf(v1, 0) && !math.IsInf(v2, 0) {
-				values1 = append(values1, v1)
-				values2 = append(values2, v2)
-				validRows++
-			}
+// correlateColumns computes Pearson correlation over the rows where both columns hold a
+// finite value
+func (s *StatsSweepService) correlateColumns(x, y []float64) *CorrelationResult {
+	values1 := []float64{}
+	values2 := []float64{}
+	validRows := 0
+	for i, v1 := range x {
+		v2 := y[i]
+
+		// Skip if either value is NaN or invalid
+		if !math.IsNaN(v1) && !math.IsNaN(v2) && !math.IsInf(v1, 0) && !math.IsInf(v2, 0) {
+			values1 = append(values1, v1)
+			values2 = append(values2, v2)
+			validRows++
 		}
 	}
 
-	fmt.Printf("[StatsSweepService]     • Found %d valid data points out of %d rows\n", validRows, len(bundle.Matrix.Data))
+	fmt.Printf("[StatsSweepService]     • Found %d valid data points out of %d rows\n", validRows, len(x))
 
 	n := len(values1)
 	if n < 10 { // Need minimum sample size
@@ -285,28 +698,74 @@ func (s *StatsSweepService) calculatePValue(tStat float64, df int) float64 {
 	return 2.0 * (1.0 - p)
 }
 
-// calculateConfidenceLevel determines confidence level from p-value
-func (s *StatsSweepService) calculateConfidenceLevel(pValue float64) string {
+// bayesianEstimate computes the Bayesian counterpart of a pair's test: a beta-binomial
+// comparison of proportions when both variables are binary, otherwise the JZS Bayes factor
+// for the correlation
+func (s *StatsSweepService) bayesianEstimate(bundle *dataset.MatrixBundle, corr CorrelationResult) *stats.BayesianEstimate {
+	x, okX := bundle.GetColumnData(core.VariableKey(corr.Variable1))
+	y, okY := bundle.GetColumnData(core.VariableKey(corr.Variable2))
+	if okX && okY {
+		if xHigh, ok := binaryHigh(x); ok {
+			if yHigh, ok := binaryHigh(y); ok {
+				var s1, n1, s2, n2 int
+				for i := range x {
+					if i >= len(y) || math.IsNaN(x[i]) || math.IsNaN(y[i]) {
+						continue
+					}
+					success := 0
+					if y[i] == yHigh {
+						success = 1
+					}
+					if x[i] == xHigh {
+						s1, n1 = s1+success, n1+1
+					} else {
+						s2, n2 = s2+success, n2+1
+					}
+				}
+				if estimate, err := stats.BetaBinomial(s1, n1, s2, n2); err == nil {
+					return estimate
+				}
+			}
+		}
+	}
+	estimate, err := stats.JZSCorrelation(corr.Coefficient, corr.SampleSize)
+	if err != nil {
+		return nil
+	}
+	return estimate
+}
+
+// binaryHigh returns the larger value of a column with exactly two distinct non-missing values
+func binaryHigh(column []float64) (float64, bool) {
+	var values []float64
+	for _, v := range column {
+		if math.IsNaN(v) {
+			continue
+		}
+		if len(values) == 0 || (v != values[0] && (len(values) == 1 || v != values[1])) {
+			values = append(values, v)
+			if len(values) > 2 {
+				return 0, false
+			}
+		}
+	}
+	if len(values) != 2 {
+		return 0, false
+	}
+	return math.Max(values[0], values[1]), true
+}
+
+// calculateBayesianConfidenceLevel maps BF10 onto the same labels as AnalysisPolicy.EvidenceLevel
+func (s *StatsSweepService) calculateBayesianConfidenceLevel(bf10 float64) string {
 	switch {
-	case pValue < 0.001:
+	case bf10 > 100:
 		return "very_strong"
-	case pValue < 0.01:
+	case bf10 > 10:
 		return "strong"
-	case pValue < 0.05:
+	case bf10 > 3:
 		return "moderate"
 	default:
 		return "weak"
 	}
 }
 
-// calculatePracticalSignificance determines practical significance from correlation magnitude
-func (s *StatsSweepService) calculatePracticalSignificance(correlationAbs float64) string {
-	switch {
-	case correlationAbs >= 0.5:
-		return "large"
-	case correlationAbs >= 0.3:
-		return "medium"
-	default:
-		return "small"
-	}
-}
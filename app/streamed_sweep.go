@@ -0,0 +1,158 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/domain/stage"
+	"gohypo/domain/stats"
+	"gohypo/internal/buildinfo"
+)
+
+// StreamedSweepRequest asks for a Pearson sweep over a matrix served column by column, such
+// as a memory-mapped matrix store too large to resolve into a MatrixBundle. Only the columns
+// of the pairs in flight are read at a time, so the sweep's memory does not grow with the
+// matrix. Ordinal, imputed, robust, Bayesian and group comparisons need whole rows and are
+// left to RunStatsSweep.
+type StreamedSweepRequest struct {
+	Source     dataset.ColumnSource
+	SnapshotID core.SnapshotID
+	Rigor      stage.RigorProfile
+	FDRMethod  stats.FDRMethod
+	NumWorkers int                  // pairwise worker pool size (0 = NumCPU)
+	Seed       int64                // base seed for per-pair RNG streams
+	Policy     stats.AnalysisPolicy // significance and effect-size cutoffs; zero fields take the defaults
+
+	// OnProgress is called after each pair completes (serialized)
+	OnProgress func(completed, total int)
+}
+
+// typedColumnSource is a column source that knows its columns' statistical types
+type typedColumnSource interface {
+	ColumnType(j int) dataset.StatisticalType
+}
+
+// RunStreamedSweep correlates every pair of non-categorical columns of the source
+func (s *StatsSweepService) RunStreamedSweep(ctx context.Context, req StreamedSweepRequest) (*StatsSweepResponse, error) {
+	if req.Source == nil {
+		return nil, fmt.Errorf("column source cannot be nil")
+	}
+	fdrMethod, err := StatsSweepRequest{Rigor: req.Rigor, FDRMethod: req.FDRMethod}.resolveFDRMethod()
+	if err != nil {
+		return nil, err
+	}
+	policy, err := StatsSweepRequest{Policy: req.Policy}.resolvePolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	variables := req.Source.Variables()
+	typed, _ := req.Source.(typedColumnSource)
+	var columns []int
+	for j := range variables {
+		if typed != nil && typed.ColumnType(j) == dataset.TypeCategorical {
+			continue
+		}
+		columns = append(columns, j)
+	}
+	fmt.Printf("[StatsSweepService] 🔬 Streaming %d of %d variables from the column source\n", len(columns), len(variables))
+
+	tasks := make([]PairTask, 0, len(columns)*(len(columns)-1)/2)
+	for a := 0; a < len(columns); a++ {
+		for b := a + 1; b < len(columns); b++ {
+			tasks = append(tasks, PairTask{
+				Index: len(tasks),
+				VarX:  string(variables[columns[a]]),
+				VarY:  string(variables[columns[b]]),
+				ColX:  columns[a],
+				ColY:  columns[b],
+			})
+		}
+	}
+
+	runner := s.stageRunner
+	if runner == nil {
+		runner = NewStageRunner(s.ledgerPort, s.rngPort)
+	}
+	pairResults := make([]*CorrelationResult, len(tasks))
+	var progressMu sync.Mutex
+	completed := 0
+	err = runner.StreamPairs(ctx, req.Source, tasks, req.NumWorkers, func(task PairTask, x, y []float64) {
+		result := s.correlateColumns(x, y)
+		if result != nil {
+			result.Variable1 = task.VarX
+			result.Variable2 = task.VarY
+		}
+		pairResults[task.Index] = result
+
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		completed++
+		if req.OnProgress != nil {
+			req.OnProgress(completed, len(tasks))
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("streamed pairwise sweep failed: %w", err)
+	}
+
+	correlations := []CorrelationResult{}
+	for _, result := range pairResults {
+		if result != nil && result.meaningful(policy) {
+			correlations = append(correlations, *result)
+		}
+	}
+	pValues := make([]float64, len(correlations))
+	for i, corr := range correlations {
+		pValues[i] = corr.PValue
+	}
+	qValues := stats.AdjustPValues(fdrMethod, pValues)
+
+	relationships := make([]core.Artifact, 0, len(correlations))
+	for i, corr := range correlations {
+		relationships = append(relationships, core.Artifact{
+			ID:   core.ID(fmt.Sprintf("corr_%s_%s", corr.Variable1, corr.Variable2)),
+			Kind: "association",
+			Payload: map[string]interface{}{
+				"evidence_id":            fmt.Sprintf("assoc_%03d", i+1),
+				"cause_key":              corr.Variable1,
+				"effect_key":             corr.Variable2,
+				"correlation":            corr.Coefficient,
+				"p_value":                corr.PValue,
+				"q_value":                qValues[i],
+				"sample_size":            corr.SampleSize,
+				"confidence_level":       policy.EvidenceLevel(corr.PValue),
+				"practical_significance": policy.PracticalSignificance(corr.Coefficient),
+				"test_type":              pearsonTestType,
+				"fdr_method":             string(fdrMethod),
+				"total_comparisons":      len(correlations),
+			},
+			CreatedAt: core.Now(),
+		})
+	}
+
+	manifest := core.Artifact{
+		ID:   core.ID("stats_sweep_manifest"),
+		Kind: "sweep_manifest",
+		Payload: map[string]interface{}{
+			"status":              "completed",
+			"mode":                "streamed",
+			"snapshot_id":         string(req.SnapshotID),
+			"relationships_found": len(relationships),
+			"variables_analyzed":  len(columns),
+			"fdr_method":          string(fdrMethod),
+			"seed":                req.Seed,
+			"analysis_policy":     policy,
+			"total_comparisons":   len(correlations),
+			"pairs_computed":      len(tasks),
+			"method_versions":     buildinfo.Methods(),
+			"code_version":        buildinfo.Get().Version,
+			"analysis_timestamp":  core.Now(),
+		},
+		CreatedAt: core.Now(),
+	}
+	return &StatsSweepResponse{Relationships: relationships, Manifest: manifest}, nil
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"gohypo/internal/matrixstore"
+)
+
+// TestStreamedSweepMatchesInMemorySweep verifies a sweep streamed from a matrix store finds
+// the same correlations as the in-memory sweep of the bundle it was written from
+func TestStreamedSweepMatchesInMemorySweep(t *testing.T) {
+	columns := map[string][]float64{}
+	for i := 0; i < 40; i++ {
+		x := float64(i)
+		columns["price"] = append(columns["price"], x)
+		columns["total"] = append(columns["total"], 2*x+float64(i%3))
+		columns["count"] = append(columns["count"], float64((i*7)%11))
+		columns["score"] = append(columns["score"], 40-x+float64(i%4))
+	}
+	order := []string{"price", "total", "count", "score"}
+	bundle := sweepTestBundle(columns, order)
+
+	dir := t.TempDir()
+	if err := matrixstore.WriteBundle(dir, bundle); err != nil {
+		t.Fatalf("failed to write store: %v", err)
+	}
+	store, err := matrixstore.Open(dir, 1)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Close()
+
+	svc := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil)
+	ctx := context.Background()
+	inMemory, err := svc.RunStatsSweep(ctx, StatsSweepRequest{MatrixBundle: sweepTestBundle(columns, order)})
+	if err != nil {
+		t.Fatalf("in-memory sweep failed: %v", err)
+	}
+	streamed, err := svc.RunStreamedSweep(ctx, StreamedSweepRequest{Source: store, NumWorkers: 4})
+	if err != nil {
+		t.Fatalf("streamed sweep failed: %v", err)
+	}
+
+	if len(streamed.Relationships) == 0 || len(streamed.Relationships) != len(inMemory.Relationships) {
+		t.Fatalf("streamed %d relationships, in-memory %d", len(streamed.Relationships), len(inMemory.Relationships))
+	}
+	for i, artifact := range streamed.Relationships {
+		got := artifact.Payload.(map[string]interface{})
+		want := inMemory.Relationships[i].Payload.(map[string]interface{})
+		for _, key := range []string{"cause_key", "effect_key", "correlation", "p_value", "q_value", "sample_size"} {
+			if got[key] != want[key] {
+				t.Errorf("relationship %d %s: streamed %v, in-memory %v", i, key, got[key], want[key])
+			}
+		}
+	}
+	if resident := store.Resident(); resident > 1 {
+		t.Errorf("%d columns left mapped, limit is 1", resident)
+	}
+}
@@ -0,0 +1,180 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"gohypo/domain/core"
+	"gohypo/internal/buildinfo"
+	"gohypo/ports"
+)
+
+// defaultCheckpointEvery is the number of completed pairs per checkpoint batch
+const defaultCheckpointEvery = 50
+
+// SweepCheckpoint is one batch of completed pairs persisted to the ledger. A resumed
+// sweep merges every batch for its run whose fingerprint and seed match.
+type SweepCheckpoint struct {
+	RunID             core.RunID         `json:"run_id"`
+	Stage             string             `json:"stage"`
+	Batch             int                `json:"batch"`
+	BundleFingerprint core.Hash          `json:"bundle_fingerprint"`
+	Seed              int64              `json:"seed"`
+	TotalPairs        int                `json:"total_pairs"`
+	MethodVersions    map[string]string  `json:"method_versions,omitempty"`
+	Pairs             []CheckpointedPair `json:"pairs"`
+}
+
+// CheckpointedPair is a completed pair; Result is nil when the pair produced no correlation
+type CheckpointedPair struct {
+	Index  int                `json:"index"`
+	Key    string             `json:"key"`
+	Result *CorrelationResult `json:"result,omitempty"`
+}
+
+// sweepCheckpointer writes pair batches to the ledger and restores them on resume
+type sweepCheckpointer struct {
+	ledger      ports.LedgerPort
+	runID       core.RunID
+	stage       string
+	fingerprint core.Hash
+	seed        int64
+	totalPairs  int
+	every       int
+
+	allowIncompatible bool
+
+	batch   int
+	pending []CheckpointedPair
+}
+
+// newSweepCheckpointer returns nil when checkpointing is not possible (no ledger or run ID)
+func newSweepCheckpointer(ledger ports.LedgerPort, runID core.RunID, stage string, fingerprint core.Hash, seed int64, totalPairs, every int, allowIncompatible bool) *sweepCheckpointer {
+	if ledger == nil || runID == "" {
+		return nil
+	}
+	if every <= 0 {
+		every = defaultCheckpointEvery
+	}
+	return &sweepCheckpointer{
+		ledger:      ledger,
+		runID:       runID,
+		stage:       stage,
+		fingerprint: fingerprint,
+		seed:        seed,
+		totalPairs:  totalPairs,
+		every:       every,
+
+		allowIncompatible: allowIncompatible,
+	}
+}
+
+// restore loads completed pairs from matching checkpoints, keyed by task index.
+// Checkpoints from a different bundle, seed or pair layout are ignored; checkpoints from
+// incompatible method versions fail the resume unless the request allows them.
+func (c *sweepCheckpointer) restore(ctx context.Context) (map[int]*CorrelationResult, error) {
+	if c == nil {
+		return nil, nil
+	}
+	artifacts, err := c.ledger.GetArtifactsByRun(ctx, c.runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sweep checkpoints: %w", err)
+	}
+
+	var checkpoints []SweepCheckpoint
+	for _, artifact := range artifacts {
+		if artifact.Kind != core.ArtifactSweepCheckpoint {
+			continue
+		}
+		checkpoint, ok := decodeSweepCheckpoint(artifact.Payload)
+		if !ok {
+			continue
+		}
+		if checkpoint.Stage != c.stage || checkpoint.BundleFingerprint != c.fingerprint ||
+			checkpoint.Seed != c.seed || checkpoint.TotalPairs != c.totalPairs {
+			continue
+		}
+		if err := buildinfo.CheckCompatible(checkpoint.MethodVersions); err != nil && !c.allowIncompatible {
+			return nil, fmt.Errorf("checkpoint batch %d of run %s %w", checkpoint.Batch, c.runID, err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	sort.Slice(checkpoints, func(i, j int) bool { return checkpoints[i].Batch < checkpoints[j].Batch })
+
+	completed := make(map[int]*CorrelationResult)
+	for _, checkpoint := range checkpoints {
+		for _, pair := range checkpoint.Pairs {
+			completed[pair.Index] = pair.Result
+		}
+		if checkpoint.Batch >= c.batch {
+			c.batch = checkpoint.Batch + 1
+		}
+	}
+	return completed, nil
+}
+
+// record queues a completed pair and flushes a batch when it is full. Callers serialize calls.
+func (c *sweepCheckpointer) record(ctx context.Context, task PairTask, result *CorrelationResult) {
+	if c == nil {
+		return
+	}
+	c.pending = append(c.pending, CheckpointedPair{Index: task.Index, Key: task.Key(), Result: result})
+	if len(c.pending) >= c.every {
+		c.flush(ctx)
+	}
+}
+
+// flush writes any queued pairs as a new checkpoint batch. Failures are logged, not fatal:
+// a lost checkpoint only means more work on resume.
+func (c *sweepCheckpointer) flush(ctx context.Context) {
+	if c == nil || len(c.pending) == 0 {
+		return
+	}
+	checkpoint := SweepCheckpoint{
+		RunID:             c.runID,
+		Stage:             c.stage,
+		Batch:             c.batch,
+		BundleFingerprint: c.fingerprint,
+		Seed:              c.seed,
+		TotalPairs:        c.totalPairs,
+		MethodVersions:    buildinfo.Methods(),
+		Pairs:             c.pending,
+	}
+	artifact := core.Artifact{
+		ID:        core.ID(fmt.Sprintf("sweep_checkpoint_%s_%s_%04d", c.runID, c.stage, c.batch)),
+		Kind:      core.ArtifactSweepCheckpoint,
+		Payload:   checkpoint,
+		CreatedAt: core.Now(),
+	}
+	if err := c.ledger.StoreArtifact(ctx, string(c.runID), artifact); err != nil {
+		log.Printf("[StatsSweepService] ⚠️ Failed to store checkpoint batch %d for run %s: %v", c.batch, c.runID, err)
+		return
+	}
+	c.batch++
+	c.pending = nil
+}
+
+// decodeSweepCheckpoint accepts the payload as stored in memory or round-tripped through JSON
+func decodeSweepCheckpoint(payload interface{}) (SweepCheckpoint, bool) {
+	switch p := payload.(type) {
+	case SweepCheckpoint:
+		return p, true
+	case *SweepCheckpoint:
+		if p == nil {
+			return SweepCheckpoint{}, false
+		}
+		return *p, true
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return SweepCheckpoint{}, false
+	}
+	var checkpoint SweepCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil || checkpoint.Stage == "" {
+		return SweepCheckpoint{}, false
+	}
+	return checkpoint, true
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"gohypo/domain/core"
+	"gohypo/domain/run"
+	"gohypo/ports"
+)
+
+// memoryLedger is a minimal LedgerPort that round-trips payloads through JSON like a database would
+type memoryLedger struct {
+	byRun map[core.RunID][]core.Artifact
+}
+
+func (l *memoryLedger) StoreArtifact(ctx context.Context, runID string, artifact core.Artifact) error {
+	data, err := json.Marshal(artifact.Payload)
+	if err != nil {
+		return err
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+	artifact.Payload = payload
+	l.byRun[core.RunID(runID)] = append(l.byRun[core.RunID(runID)], artifact)
+	return nil
+}
+
+func (l *memoryLedger) ListArtifacts(ctx context.Context, filters ports.ArtifactFilters) ([]core.Artifact, error) {
+	return nil, nil
+}
+
+func (l *memoryLedger) GetArtifact(ctx context.Context, artifactID core.ArtifactID) (*core.Artifact, error) {
+	return nil, nil
+}
+
+func (l *memoryLedger) GetArtifactsByRun(ctx context.Context, runID core.RunID) ([]core.Artifact, error) {
+	return l.byRun[runID], nil
+}
+
+func (l *memoryLedger) GetArtifactsByKind(ctx context.Context, kind core.ArtifactKind, limit int) ([]core.Artifact, error) {
+	return nil, nil
+}
+
+func (l *memoryLedger) GetRunManifest(ctx context.Context, runID core.RunID) (*run.RunManifestArtifact, error) {
+	return nil, nil
+}
+
+// TestSweepResumesFromCheckpoints verifies a rerun with the same run, bundle and seed skips checkpointed pairs
+func TestSweepResumesFromCheckpoints(t *testing.T) {
+	columns := map[string][]float64{}
+	for i := 0; i < 30; i++ {
+		x := float64(i)
+		columns["price"] = append(columns["price"], x)
+		columns["total"] = append(columns["total"], 2*x+float64(i%3))
+		columns["count"] = append(columns["count"], float64((i*7)%11))
+		columns["score"] = append(columns["score"], 30-x+float64(i%4))
+	}
+	order := []string{"price", "total", "count", "score"}
+
+	ledger := &memoryLedger{byRun: map[core.RunID][]core.Artifact{}}
+	svc := NewStatsSweepService(NewStageRunner(nil, nil), ledger, nil)
+	ctx := context.Background()
+	req := StatsSweepRequest{
+		MatrixBundle:    sweepTestBundle(columns, order),
+		RunID:           core.RunID("run-1"),
+		Seed:            7,
+		CheckpointEvery: 4,
+	}
+
+	first, err := svc.RunStatsSweep(ctx, req)
+	if err != nil {
+		t.Fatalf("first sweep failed: %v", err)
+	}
+	if got := len(ledger.byRun["run-1"]); got != 2 {
+		t.Fatalf("expected 2 checkpoint batches for 6 pairs, got %d", got)
+	}
+
+	resumed, err := svc.RunStatsSweep(ctx, req)
+	if err != nil {
+		t.Fatalf("resumed sweep failed: %v", err)
+	}
+	manifest := resumed.Manifest.Payload.(map[string]interface{})
+	if manifest["pairs_resumed"] != 6 || manifest["pairs_computed"] != 0 {
+		t.Fatalf("expected 6 resumed and 0 computed pairs, got %v resumed, %v computed",
+			manifest["pairs_resumed"], manifest["pairs_computed"])
+	}
+	if len(resumed.Relationships) != len(first.Relationships) {
+		t.Fatalf("resumed sweep found %d relationships, first found %d", len(resumed.Relationships), len(first.Relationships))
+	}
+
+	req.Seed = 8
+	reseeded, err := svc.RunStatsSweep(ctx, req)
+	if err != nil {
+		t.Fatalf("reseeded sweep failed: %v", err)
+	}
+	if got := reseeded.Manifest.Payload.(map[string]interface{})["pairs_resumed"]; got != 0 {
+		t.Fatalf("checkpoints from another seed must not be reused, resumed %v", got)
+	}
+}
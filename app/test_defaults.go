@@ -0,0 +1,40 @@
+package app
+
+import (
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/domain/stats"
+)
+
+// variableDefaults chooses the test parameters of every matrix variable from its readiness
+// profile, profiling the matrix column for variables the request has no profile of
+func variableDefaults(bundle *dataset.MatrixBundle, profiles map[core.VariableKey]stats.VariableProfile) map[string]stats.TestDefaults {
+	defaults := make(map[string]stats.TestDefaults, len(bundle.Matrix.VariableKeys))
+	for _, key := range bundle.Matrix.VariableKeys {
+		profile, ok := profiles[key]
+		if !ok {
+			values, _ := bundle.GetColumnData(key)
+			profile = stats.ProfileValues(values)
+		}
+		defaults[string(key)] = stats.DefaultsFor(profile)
+	}
+	return defaults
+}
+
+// correlationTestParams records the parameters a correlation pair was tested with
+func correlationTestParams(cause, effect stats.TestDefaults) map[string]interface{} {
+	return map[string]interface{}{
+		"winsor_trim": stats.PairWinsorTrim(cause, effect),
+		"cause_bins":  cause.Bins,
+		"effect_bins": effect.Bins,
+	}
+}
+
+// groupTestParams records the parameters a group comparison was tested with: the levels
+// of the categorical cause are sized by its minimum group size
+func groupTestParams(categorical, numeric stats.TestDefaults) map[string]interface{} {
+	return map[string]interface{}{
+		"min_group_size": categorical.MinGroupSize,
+		"effect_bins":    numeric.Bins,
+	}
+}
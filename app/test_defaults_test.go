@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/domain/stats"
+)
+
+// TestSweepRecordsProfileGuidedTestParams verifies each relationship records the parameters
+// chosen from its variables' profiles, with a readiness profile taking precedence over the
+// matrix column, and that rare levels below the chosen minimum are left out of comparisons
+func TestSweepRecordsProfileGuidedTestParams(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	columns := map[string][]float64{}
+	for i := 0; i < 400; i++ {
+		level := i % 2
+		if i%100 == 0 {
+			level = 2 // four rows: too rare a level at 400 rows over 3 levels
+		}
+		x := rng.NormFloat64()
+		columns["segment"] = append(columns["segment"], float64(level))
+		columns["spend"] = append(columns["spend"], 10*float64(level%2)+rng.NormFloat64())
+		columns["visits"] = append(columns["visits"], x)
+		columns["pages"] = append(columns["pages"], x+0.3*rng.NormFloat64())
+	}
+	bundle := sweepTestBundle(columns, []string{"segment", "spend", "visits", "pages"})
+	bundle.ColumnMeta = []dataset.ColumnMeta{{VariableKey: core.VariableKey("segment"), StatisticalType: dataset.TypeCategorical}}
+
+	resp, err := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil).RunStatsSweep(context.Background(), StatsSweepRequest{
+		MatrixBundle: bundle,
+		Robust:       stats.RobustWinsorized,
+		Profiles:     map[core.VariableKey]stats.VariableProfile{"pages": {Rows: 400, Cardinality: 400, Skewness: 3}},
+	})
+	if err != nil {
+		t.Fatalf("sweep failed: %v", err)
+	}
+
+	var correlation, anova map[string]interface{}
+	for _, rel := range resp.Relationships {
+		payload := rel.Payload.(map[string]interface{})
+		switch {
+		case payload["cause_key"] == "visits" && payload["effect_key"] == "pages":
+			correlation = payload
+		case payload["cause_key"] == "segment" && payload["effect_key"] == "spend":
+			anova = payload
+		}
+	}
+	if correlation == nil || anova == nil {
+		t.Fatalf("expected visits ~ pages and spend by segment, got %d relationships", len(resp.Relationships))
+	}
+
+	params := correlation["test_params"].(map[string]interface{})
+	if params["winsor_trim"] != 0.2 {
+		t.Errorf("winsor_trim = %v, want 0.2 from the skewed readiness profile of pages", params["winsor_trim"])
+	}
+	if params["cause_bins"].(int) < 2 || params["effect_bins"].(int) < 2 {
+		t.Errorf("unexpected bins: %+v", params)
+	}
+
+	params = anova["test_params"].(map[string]interface{})
+	if params["min_group_size"] != 13 {
+		t.Errorf("min_group_size = %v, want a tenth of 133 rows per level", params["min_group_size"])
+	}
+	if groups := anova["groups"]; groups != 2 {
+		t.Errorf("groups = %v, want the four-row level left out", groups)
+	}
+}
@@ -0,0 +1,292 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"gohypo/domain/core"
+	"gohypo/domain/stats"
+	"gohypo/internal/buildinfo"
+)
+
+// TimeSeriesMode controls the time-series sweep. Auto runs it whenever the matrix has a
+// time index; off always runs the cross-sectional sweep.
+type TimeSeriesMode string
+
+const (
+	TimeSeriesAuto TimeSeriesMode = ""
+	TimeSeriesOff  TimeSeriesMode = "off"
+)
+
+// defaultMaxLag is how many periods either variable may lead the other by
+const defaultMaxLag = 3
+
+// seriesColumn is one variable ordered by time and made stationary
+type seriesColumn struct {
+	name      string
+	values    []float64 // aligned with time order; differencing leaves NaN at the start
+	transform stats.SeriesTransformation
+}
+
+// lagResult is the strongest lagged correlation of one pair
+type lagResult struct {
+	leader, follower string
+	lag              int
+	correlation      float64
+	pValue           float64 // adjusted for the number of lags searched
+	sampleSize       int
+	lagsTested       int
+}
+
+// timeIndex returns the matrix's time index when the time-series sweep applies
+func (r StatsSweepRequest) timeIndex() (core.VariableKey, bool) {
+	if r.TimeSeries == TimeSeriesOff {
+		return "", false
+	}
+	return r.MatrixBundle.TimeIndex()
+}
+
+// runTimeSeriesSweep orders the rows by the time index, runs ADF and KPSS on every variable,
+// differences the non-stationary ones and then searches lags in both directions for each
+// pair. Correlating trending series finds trends, not relationships; every relationship's
+// audit records the transformations behind its numbers. Checkpoints and incremental
+// baselines apply to the cross-sectional sweep only.
+func (s *StatsSweepService) runTimeSeriesSweep(ctx context.Context, req StatsSweepRequest, timeKey core.VariableKey, fdrMethod stats.FDRMethod, inference stats.InferenceMode) (*StatsSweepResponse, error) {
+	bundle := req.MatrixBundle
+	maxLag := req.MaxLag
+	if maxLag <= 0 {
+		maxLag = defaultMaxLag
+	}
+	fmt.Printf("[StatsSweepService] 📈 Time-series sweep on time index %s (max lag %d)\n", timeKey, maxLag)
+
+	timeValues, _ := bundle.GetColumnData(timeKey)
+	order := make([]int, len(timeValues))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return timeValues[order[a]] < timeValues[order[b]] })
+
+	var columns []seriesColumn
+	var skipped []string
+	for _, key := range bundle.Matrix.VariableKeys {
+		if key == timeKey || !s.isLikelyNumeric(string(key)) {
+			continue
+		}
+		raw, _ := bundle.GetColumnData(key)
+		ordered := make([]float64, len(order))
+		for i, row := range order {
+			ordered[i] = raw[row]
+		}
+		column, err := stationaryColumn(string(key), ordered)
+		if err != nil {
+			fmt.Printf("[StatsSweepService]   • %s skipped: %v\n", key, err)
+			skipped = append(skipped, string(key))
+			continue
+		}
+		fmt.Printf("[StatsSweepService]   • %s: differenced %d time(s), stationary=%v\n", key, column.transform.Differences, column.transform.Stationary)
+		columns = append(columns, column)
+	}
+
+	tasks := make([]PairTask, 0, len(columns)*(len(columns)-1)/2)
+	for i := 0; i < len(columns); i++ {
+		for j := i + 1; j < len(columns); j++ {
+			tasks = append(tasks, PairTask{Index: len(tasks), VarX: columns[i].name, VarY: columns[j].name, ColX: i, ColY: j})
+		}
+	}
+	runner := s.stageRunner
+	if runner == nil {
+		runner = NewStageRunner(s.ledgerPort, s.rngPort)
+	}
+	results := make([]*lagResult, len(tasks))
+	err := runner.RunPairs(ctx, tasks, req.NumWorkers, func(task PairTask) {
+		results[task.Index] = s.strongestLag(columns[task.ColX], columns[task.ColY], maxLag)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []*lagResult
+	for _, result := range results {
+		if result != nil && req.Policy.Meaningful(result.correlation) { // same bar as the cross-sectional sweep
+			kept = append(kept, result)
+		}
+	}
+	pValues := make([]float64, len(kept))
+	for i, result := range kept {
+		pValues[i] = result.pValue
+	}
+	qValues := stats.AdjustPValues(fdrMethod, pValues)
+
+	transforms := make(map[string]stats.SeriesTransformation, len(columns))
+	for _, column := range columns {
+		transforms[column.name] = column.transform
+	}
+	relationships := make([]core.Artifact, 0, len(kept))
+	for i, result := range kept {
+		payload := map[string]interface{}{
+			"evidence_id":            fmt.Sprintf("assoc_%03d", len(relationships)+1),
+			"cause_key":              result.leader,
+			"effect_key":             result.follower,
+			"correlation":            result.correlation,
+			"p_value":                result.pValue,
+			"q_value":                qValues[i],
+			"sample_size":            result.sampleSize,
+			"lag":                    result.lag,
+			"confidence_level":       req.Policy.EvidenceLevel(result.pValue),
+			"practical_significance": req.Policy.PracticalSignificance(result.correlation),
+			"test_type":              "lagged_pearson_correlation",
+			"fdr_method":             string(fdrMethod),
+			"total_comparisons":      len(kept),
+			"inference_mode":         string(inference),
+			"audit": map[string]interface{}{
+				"time_index":      string(timeKey),
+				"lags_tested":     result.lagsTested,
+				"lag_adjustment":  "bonferroni",
+				"transformations": []stats.SeriesTransformation{transforms[result.leader], transforms[result.follower]},
+			},
+		}
+		if inference.Bayesian() {
+			if estimate, err := stats.JZSCorrelation(result.correlation, result.sampleSize); err == nil {
+				payload["bayesian"] = estimate
+				payload["bayes_factor_10"] = estimate.BayesFactor10
+				if inference == stats.InferenceBayesian {
+					payload["confidence_level"] = s.calculateBayesianConfidenceLevel(estimate.BayesFactor10)
+				}
+			}
+		}
+		relationships = append(relationships, core.Artifact{
+			ID:        core.ID(fmt.Sprintf("lagcorr_%s_%s", result.leader, result.follower)),
+			Kind:      "association",
+			Payload:   payload,
+			CreatedAt: core.Now(),
+		})
+	}
+	fmt.Printf("[StatsSweepService] 📈 Time-series sweep found %d lagged relationships over %d series\n", len(relationships), len(columns))
+
+	transformList := make([]stats.SeriesTransformation, 0, len(columns))
+	for _, column := range columns {
+		transformList = append(transformList, column.transform)
+	}
+	// No column fingerprints: lagged results must not be reused as a cross-sectional baseline
+	fingerprints := bundle.ColumnFingerprints()
+	manifest := core.Artifact{
+		ID:   core.ID("stats_sweep_manifest"),
+		Kind: "sweep_manifest",
+		Payload: map[string]interface{}{
+			"status":              "completed",
+			"relationships_found": len(relationships),
+			"variables_analyzed":  len(columns),
+			"entities_analyzed":   len(order),
+			"fdr_method":          string(fdrMethod),
+			"inference_mode":      string(inference),
+			"seed":                req.Seed,
+			"analysis_policy":     req.Policy,
+			"total_comparisons":   len(kept),
+			"mode":                "time_series",
+			"time_index":          string(timeKey),
+			"max_lag":             maxLag,
+			"transformations":     transformList,
+			"skipped_variables":   skipped,
+			"pairs_computed":      len(tasks),
+			"run_id":              string(req.RunID),
+			"bundle_fingerprint":  string(bundleFingerprint(bundle, fingerprints)),
+			"method_versions":     buildinfo.Methods(),
+			"code_version":        buildinfo.Get().Version,
+			"analysis_timestamp":  core.Now(),
+		},
+		CreatedAt: core.Now(),
+	}
+	return &StatsSweepResponse{Relationships: relationships, Manifest: manifest}, nil
+}
+
+// stationaryColumn runs the stationarity checks on a time-ordered column. Missing values are
+// dropped for the tests; the differenced series is written back aligned to the original time
+// points, with NaN where a difference spans a gap or precedes the first observation.
+func stationaryColumn(name string, ordered []float64) (seriesColumn, error) {
+	var observed []float64
+	for _, v := range ordered {
+		if !math.IsNaN(v) {
+			observed = append(observed, v)
+		}
+	}
+	_, transform, err := stats.MakeStationary(name, observed)
+	if err != nil {
+		return seriesColumn{}, err
+	}
+	values := ordered
+	for d := 0; d < transform.Differences; d++ {
+		next := make([]float64, len(values))
+		next[0] = math.NaN()
+		for t := 1; t < len(values); t++ {
+			next[t] = values[t] - values[t-1] // NaN propagates across gaps
+		}
+		values = next
+	}
+	return seriesColumn{name: name, values: values, transform: transform}, nil
+}
+
+// strongestLag correlates x[t] with y[t+lag] and y[t] with x[t+lag] for lags 0..maxLag and
+// keeps the strongest, so the leading variable is reported as the cause. Its p-value is
+// Bonferroni-adjusted for the lags searched.
+func (s *StatsSweepService) strongestLag(x, y seriesColumn, maxLag int) *lagResult {
+	var best *lagResult
+	tested := 0
+	try := func(leader, follower seriesColumn, lag int) {
+		var a, b []float64
+		for t := 0; t+lag < len(leader.values) && t+lag < len(follower.values); t++ {
+			u, v := leader.values[t], follower.values[t+lag]
+			if math.IsNaN(u) || math.IsNaN(v) {
+				continue
+			}
+			a = append(a, u)
+			b = append(b, v)
+		}
+		tested++
+		r, ok := pearson(a, b)
+		if !ok {
+			return
+		}
+		if best == nil || math.Abs(r) > math.Abs(best.correlation) {
+			best = &lagResult{leader: leader.name, follower: follower.name, lag: lag, correlation: r, sampleSize: len(a)}
+		}
+	}
+	for lag := 0; lag <= maxLag; lag++ {
+		try(x, y, lag)
+		if lag > 0 {
+			try(y, x, lag)
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	best.lagsTested = tested
+	tStat := best.correlation * math.Sqrt(float64(best.sampleSize-2)) / math.Sqrt(math.Max(1-best.correlation*best.correlation, 1e-12))
+	best.pValue = math.Min(1, s.calculatePValue(tStat, best.sampleSize-2)*float64(tested))
+	return best
+}
+
+// pearson returns the correlation of two equal-length samples of at least 10 points
+func pearson(a, b []float64) (float64, bool) {
+	n := len(a)
+	if n < 10 {
+		return 0, false
+	}
+	var sumA, sumB float64
+	for i := range a {
+		sumA += a[i]
+		sumB += b[i]
+	}
+	meanA, meanB := sumA/float64(n), sumB/float64(n)
+	var cov, varA, varB float64
+	for i := range a {
+		cov += (a[i] - meanA) * (b[i] - meanB)
+		varA += (a[i] - meanA) * (a[i] - meanA)
+		varB += (b[i] - meanB) * (b[i] - meanB)
+	}
+	if varA == 0 || varB == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varA*varB), true
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"gohypo/domain/stats"
+)
+
+// TestTimeSeriesSweepDifferencesTrendsAndFindsLeadingSeries verifies independent random
+// walks are not reported as related once differenced, while a series that follows another
+// two periods later is found at lag 2, even with the rows out of time order
+func TestTimeSeriesSweepDifferencesTrendsAndFindsLeadingSeries(t *testing.T) {
+	const n = 200
+	rng := rand.New(rand.NewSource(3))
+	walk := func() []float64 {
+		out := make([]float64, n)
+		for i := range out {
+			out[i] = rng.NormFloat64()
+			if i > 0 {
+				out[i] += out[i-1]
+			}
+		}
+		return out
+	}
+	trendA, trendB, spend := walk(), walk(), walk()
+	sales := make([]float64, n)
+	for i := range sales {
+		sales[i] = 0.1 * rng.NormFloat64()
+		if i >= 2 {
+			sales[i] += spend[i-2]
+		}
+	}
+
+	columns := map[string][]float64{}
+	for _, row := range rng.Perm(n) {
+		columns["date"] = append(columns["date"], float64(row))
+		columns["trend_a"] = append(columns["trend_a"], trendA[row])
+		columns["trend_b"] = append(columns["trend_b"], trendB[row])
+		columns["spend"] = append(columns["spend"], spend[row])
+		columns["sales"] = append(columns["sales"], sales[row])
+	}
+	bundle := sweepTestBundle(columns, []string{"date", "trend_a", "trend_b", "spend", "sales"})
+
+	resp, err := NewStatsSweepService(NewStageRunner(nil, nil), nil, nil).RunStatsSweep(context.Background(), StatsSweepRequest{MatrixBundle: bundle})
+	if err != nil {
+		t.Fatalf("sweep failed: %v", err)
+	}
+	if mode := resp.Manifest.Payload.(map[string]interface{})["mode"]; mode != "time_series" {
+		t.Fatalf("expected the time-series sweep, got mode %v", mode)
+	}
+
+	found := false
+	for _, rel := range resp.Relationships {
+		payload := rel.Payload.(map[string]interface{})
+		cause, effect := payload["cause_key"], payload["effect_key"]
+		if (cause == "trend_a" && effect == "trend_b") || (cause == "trend_b" && effect == "trend_a") {
+			t.Errorf("independent trends reported as related: r=%v", payload["correlation"])
+		}
+		if cause == "spend" && effect == "sales" {
+			found = true
+			if payload["lag"] != 2 {
+				t.Errorf("expected spend to lead sales by 2, got lag %v", payload["lag"])
+			}
+			transforms := payload["audit"].(map[string]interface{})["transformations"].([]stats.SeriesTransformation)
+			if transforms[0].Differences != 1 || transforms[1].Differences != 1 {
+				t.Errorf("both random walks should be differenced once: %+v", transforms)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("spend -> sales was not found among %d relationships", len(resp.Relationships))
+	}
+
+	resp, err = NewStatsSweepService(NewStageRunner(nil, nil), nil, nil).RunStatsSweep(context.Background(), StatsSweepRequest{MatrixBundle: bundle, TimeSeries: TimeSeriesOff})
+	if err != nil {
+		t.Fatalf("cross-sectional sweep failed: %v", err)
+	}
+	if mode := resp.Manifest.Payload.(map[string]interface{})["mode"]; mode != "full" {
+		t.Fatalf("time_series: off should run the cross-sectional sweep, got mode %v", mode)
+	}
+}
@@ -0,0 +1,439 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gohypo/domain/dataset"
+	"gohypo/internal/config"
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+)
+
+var (
+	adminServer *string
+	adminAPIKey *string
+	adminJSON   *bool
+)
+
+// adminCommands are the admin subcommands, by name
+var adminCommands = map[string]*adminCommand{}
+
+// adminCommand is one `gohypo-cli admin` subcommand
+type adminCommand struct {
+	Summary string
+	Flags   *flag.FlagSet
+	Run     func(ctx context.Context, client *adminClient, fs *flag.FlagSet) error
+}
+
+func init() {
+	fs := flag.NewFlagSet("admin", flag.ExitOnError)
+	adminServer = fs.String("server", "http://localhost:8080", "base URL of the GoHypo server")
+	adminAPIKey = fs.String("api-key", "", "admin API key, one of the server's ADMIN_API_KEYS")
+	adminJSON = fs.Bool("json", false, "print responses as JSON")
+
+	registerAdmin("users", "List the users workspaces can be assigned to", nil, runAdminUsers)
+	registerAdmin("workspaces", "List a user's workspaces", func(fs *flag.FlagSet) {
+		fs.String("user", "", "user ID (default: the default user)")
+	}, runAdminWorkspaces)
+	registerAdmin("create-workspace", "Create a workspace, optionally with a quota", func(fs *flag.FlagSet) {
+		fs.String("name", "", "workspace name (required)")
+		fs.String("description", "", "workspace description")
+		fs.String("color", "", "hex color for the UI")
+		fs.String("user", "", "owner's user ID (default: the default user)")
+		fs.Int("max-datasets", 0, "most datasets the workspace may hold (0: unlimited)")
+		fs.Int64("max-storage-mb", 0, "most megabytes of dataset files the workspace may hold (0: unlimited)")
+	}, runAdminCreateWorkspace)
+	registerAdmin("datasets", "List a workspace's datasets", func(fs *flag.FlagSet) {
+		fs.String("workspace", "", "workspace ID (required)")
+		fs.Int("limit", 100, "number of datasets to list")
+	}, runAdminDatasets)
+	registerAdmin("assign", "Hand a workspace and its datasets to another user", func(fs *flag.FlagSet) {
+		fs.String("workspace", "", "workspace ID (required)")
+		fs.String("user", "", "new owner's user ID (required)")
+	}, runAdminAssign)
+	registerAdmin("quota", "Show a workspace's quota, or set it with the limit flags", func(fs *flag.FlagSet) {
+		fs.String("workspace", "", "workspace ID (required)")
+		fs.Int("max-datasets", 0, "most datasets the workspace may hold (0: unlimited)")
+		fs.Int64("max-storage-mb", 0, "most megabytes of dataset files the workspace may hold (0: unlimited)")
+	}, runAdminQuota)
+	registerAdmin("discover", "Start relationship discovery and hypothesis research on a workspace", func(fs *flag.FlagSet) {
+		fs.String("workspace", "", "workspace ID (required)")
+	}, runAdminDiscover)
+
+	register(&command{
+		Name:    "admin",
+		Summary: "Administer workspaces and datasets on a running server through its admin API",
+		Long: fmt.Sprintf("Administer workspaces and datasets on a running server through its admin API.\n\n"+
+			"The server and key default to $%s and $%s, or server.url and server.api_key in the config file.",
+			config.ServerEnv, config.APIKeyEnv),
+		Flags:       fs,
+		Subcommands: adminSubcommands(),
+	})
+}
+
+// registerAdmin adds an admin subcommand; flags defines its flags, if it has any
+func registerAdmin(name, summary string, flags func(fs *flag.FlagSet), run func(context.Context, *adminClient, *flag.FlagSet) error) {
+	fs := flag.NewFlagSet("admin "+name, flag.ExitOnError)
+	if flags != nil {
+		flags(fs)
+	}
+	adminCommands[name] = &adminCommand{Summary: summary, Flags: fs, Run: run}
+}
+
+// adminCommandNames returns the admin subcommand names in stable order
+func adminCommandNames() []string {
+	names := make([]string, 0, len(adminCommands))
+	for name := range adminCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// adminSubcommands wraps each admin subcommand as a CLI command that connects to the server first
+func adminSubcommands() []*command {
+	var subcommands []*command
+	for _, name := range adminCommandNames() {
+		sub := adminCommands[name]
+		subcommands = append(subcommands, &command{
+			Name:    name,
+			Summary: sub.Summary,
+			Flags:   sub.Flags,
+			Run: func(ctx context.Context, fs *flag.FlagSet) error {
+				client, err := newAdminClient(*adminServer, *adminAPIKey)
+				if err != nil {
+					return err
+				}
+				return sub.Run(ctx, client, fs)
+			},
+		})
+	}
+	return subcommands
+}
+
+func runAdminUsers(ctx context.Context, client *adminClient, fs *flag.FlagSet) error {
+	var resp struct {
+		Users []*models.User `json:"users"`
+	}
+	if err := client.do(ctx, http.MethodGet, "/api/admin/users", nil, &resp); err != nil {
+		return err
+	}
+	if *adminJSON {
+		return printJSON(resp.Users)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tUSERNAME\tEMAIL\tACTIVE")
+	for _, u := range resp.Users {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\n", u.ID, u.Username, u.Email, u.IsActive)
+	}
+	return tw.Flush()
+}
+
+func runAdminWorkspaces(ctx context.Context, client *adminClient, fs *flag.FlagSet) error {
+	path := "/api/admin/workspaces"
+	if user := flagString(fs, "user"); user != "" {
+		path += "?user_id=" + url.QueryEscape(user)
+	}
+	var resp struct {
+		Workspaces []*dataset.Workspace `json:"workspaces"`
+	}
+	if err := client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return err
+	}
+	if *adminJSON {
+		return printJSON(resp.Workspaces)
+	}
+	if len(resp.Workspaces) == 0 {
+		fmt.Println("No workspaces for this user.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tDEFAULT\tQUOTA\tCREATED")
+	for _, w := range resp.Workspaces {
+		fmt.Fprintf(tw, "%s\t%s\t%t\t%s\t%s\n", w.ID, w.Name, w.IsDefault,
+			formatQuota(models.WorkspaceQuotaFrom(w.Metadata)), w.CreatedAt.Format("2006-01-02"))
+	}
+	return tw.Flush()
+}
+
+func runAdminCreateWorkspace(ctx context.Context, client *adminClient, fs *flag.FlagSet) error {
+	name := flagString(fs, "name")
+	if name == "" {
+		return apperrors.InvalidInput("--name is required")
+	}
+	body := map[string]interface{}{
+		"name":        name,
+		"description": flagString(fs, "description"),
+		"color":       flagString(fs, "color"),
+		"user_id":     flagString(fs, "user"),
+	}
+	if quota := quotaFlags(fs); !quota.Unlimited() {
+		body["quota"] = quota
+	}
+	var workspace dataset.Workspace
+	if err := client.do(ctx, http.MethodPost, "/api/admin/workspaces", body, &workspace); err != nil {
+		return err
+	}
+	if *adminJSON {
+		return printJSON(workspace)
+	}
+	fmt.Printf("Created workspace %s (%s) for user %s\n", workspace.ID, workspace.Name, workspace.UserID)
+	return nil
+}
+
+func runAdminDatasets(ctx context.Context, client *adminClient, fs *flag.FlagSet) error {
+	workspaceID, err := requiredWorkspace(fs)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/api/admin/workspaces/%s/datasets?limit=%s", url.PathEscape(workspaceID), fs.Lookup("limit").Value)
+	var resp struct {
+		Datasets []*dataset.Dataset `json:"datasets"`
+	}
+	if err := client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return err
+	}
+	if *adminJSON {
+		return printJSON(resp.Datasets)
+	}
+	if len(resp.Datasets) == 0 {
+		fmt.Println("No datasets in this workspace.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tSTATUS\tRECORDS\tFIELDS\tSIZE")
+	for _, ds := range resp.Datasets {
+		name := ds.DisplayName
+		if name == "" {
+			name = ds.OriginalFilename
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%.1f MB\n", ds.ID, name, ds.Status, ds.RecordCount, ds.FieldCount,
+			float64(ds.FileSize)/(1024*1024))
+	}
+	return tw.Flush()
+}
+
+func runAdminAssign(ctx context.Context, client *adminClient, fs *flag.FlagSet) error {
+	workspaceID, err := requiredWorkspace(fs)
+	if err != nil {
+		return err
+	}
+	user := flagString(fs, "user")
+	if user == "" {
+		return apperrors.InvalidInput("--user is required")
+	}
+	var resp struct {
+		WorkspaceID    string `json:"workspace_id"`
+		UserID         string `json:"user_id"`
+		PreviousUserID string `json:"previous_user_id"`
+	}
+	path := fmt.Sprintf("/api/admin/workspaces/%s/owner", url.PathEscape(workspaceID))
+	if err := client.do(ctx, http.MethodPut, path, map[string]string{"user_id": user}, &resp); err != nil {
+		return err
+	}
+	if *adminJSON {
+		return printJSON(resp)
+	}
+	fmt.Printf("Assigned workspace %s to user %s (was %s)\n", resp.WorkspaceID, resp.UserID, resp.PreviousUserID)
+	return nil
+}
+
+func runAdminQuota(ctx context.Context, client *adminClient, fs *flag.FlagSet) error {
+	workspaceID, err := requiredWorkspace(fs)
+	if err != nil {
+		return err
+	}
+	setting := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "max-datasets" || f.Name == "max-storage-mb" {
+			setting = true
+		}
+	})
+
+	var resp struct {
+		WorkspaceID string                `json:"workspace_id"`
+		Quota       models.WorkspaceQuota `json:"quota"`
+		Datasets    int                   `json:"datasets"`
+		StoredBytes int64                 `json:"stored_bytes"`
+	}
+	path := fmt.Sprintf("/api/admin/workspaces/%s/quota", url.PathEscape(workspaceID))
+	if setting {
+		err = client.do(ctx, http.MethodPut, path, quotaFlags(fs), &resp)
+	} else {
+		err = client.do(ctx, http.MethodGet, path, nil, &resp)
+	}
+	if err != nil {
+		return err
+	}
+	if *adminJSON {
+		return printJSON(resp)
+	}
+	fmt.Printf("Workspace %s\n  quota:    %s\n  datasets: %d\n  stored:   %.1f MB\n", resp.WorkspaceID,
+		formatQuota(resp.Quota), resp.Datasets, float64(resp.StoredBytes)/(1024*1024))
+	return nil
+}
+
+func runAdminDiscover(ctx context.Context, client *adminClient, fs *flag.FlagSet) error {
+	workspaceID, err := requiredWorkspace(fs)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		SessionID string `json:"session_id"`
+		Status    string `json:"status"`
+	}
+	if err := client.do(ctx, http.MethodPost, "/api/research/initiate", map[string]string{"workspace_id": workspaceID}, &resp); err != nil {
+		return err
+	}
+	if *adminJSON {
+		return printJSON(resp)
+	}
+	fmt.Printf("Discovery %s for workspace %s: session %s\n", resp.Status, workspaceID, resp.SessionID)
+	return nil
+}
+
+// flagString returns the value of a string flag defined in registerAdmin
+func flagString(fs *flag.FlagSet, name string) string {
+	return strings.TrimSpace(fs.Lookup(name).Value.String())
+}
+
+// requiredWorkspace returns --workspace, which most subcommands require
+func requiredWorkspace(fs *flag.FlagSet) (string, error) {
+	workspaceID := flagString(fs, "workspace")
+	if workspaceID == "" {
+		return "", apperrors.InvalidInput("--workspace is required")
+	}
+	return workspaceID, nil
+}
+
+// quotaFlags reads --max-datasets and --max-storage-mb into a quota
+func quotaFlags(fs *flag.FlagSet) models.WorkspaceQuota {
+	maxDatasets := fs.Lookup("max-datasets").Value.(flag.Getter).Get().(int)
+	maxStorageMB := fs.Lookup("max-storage-mb").Value.(flag.Getter).Get().(int64)
+	return models.WorkspaceQuota{MaxDatasets: maxDatasets, MaxStorageBytes: maxStorageMB << 20}
+}
+
+func formatQuota(quota models.WorkspaceQuota) string {
+	if quota.Unlimited() {
+		return "unlimited"
+	}
+	var parts []string
+	if quota.MaxDatasets > 0 {
+		parts = append(parts, fmt.Sprintf("%d datasets", quota.MaxDatasets))
+	}
+	if quota.MaxStorageBytes > 0 {
+		parts = append(parts, fmt.Sprintf("%d MB", quota.MaxStorageBytes>>20))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// adminClient calls a GoHypo server's REST API with an admin API key
+type adminClient struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+func newAdminClient(server, apiKey string) (*adminClient, error) {
+	base, err := url.Parse(strings.TrimRight(server, "/"))
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, apperrors.InvalidInput(fmt.Sprintf("--server must be an http(s) URL, got %q", server))
+	}
+	return &adminClient{
+		baseURL: base.String(),
+		apiKey:  apiKey,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// do sends body as JSON and decodes the response into out. Error responses come back as
+// AppErrors carrying the server's code, so the CLI exits with the matching exit code.
+func (c *adminClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return apperrors.ExternalServiceError("gohypo server", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return apperrors.ExternalServiceError("gohypo server", err)
+	}
+	if resp.StatusCode >= 300 {
+		return responseError(resp.StatusCode, data)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return apperrors.ExternalServiceError("gohypo server", fmt.Errorf("unexpected response: %w", err))
+	}
+	return nil
+}
+
+// responseError turns an error response into an AppError: a problem document keeps its code,
+// other bodies are classified by status
+func responseError(status int, data []byte) error {
+	var problem apperrors.Problem
+	if json.Unmarshal(data, &problem) == nil && problem.Code != "" {
+		detail := problem.Detail
+		if detail == "" {
+			detail = problem.Title
+		}
+		return apperrors.New(problem.Code, detail)
+	}
+	var plain struct {
+		Error string `json:"error"`
+	}
+	message := http.StatusText(status)
+	if json.Unmarshal(data, &plain) == nil && plain.Error != "" {
+		message = plain.Error
+	}
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return apperrors.InvalidInput(message)
+	case http.StatusUnauthorized:
+		return apperrors.Unauthorized(message)
+	case http.StatusForbidden:
+		return apperrors.Forbidden(message)
+	case http.StatusNotFound:
+		return apperrors.New(apperrors.CodeNotFound, message)
+	case http.StatusConflict:
+		return apperrors.Conflict(message)
+	case http.StatusTooManyRequests:
+		return apperrors.Capacity(message)
+	default:
+		return apperrors.New(apperrors.CodeExternalService, fmt.Sprintf("server returned %d: %s", status, message))
+	}
+}
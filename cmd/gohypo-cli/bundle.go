@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gohypo/adapters/excel"
+	"gohypo/adapters/notebook"
+	"gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/reproduce"
+)
+
+var (
+	exportRunsDir *string
+	exportOut     *string
+	exportFormat  *string
+	exportJSON    *bool
+	importRunsDir *string
+	importForce   *bool
+	importJSON    *bool
+)
+
+func init() {
+	fs := flag.NewFlagSet("export-run", flag.ExitOnError)
+	exportRunsDir = fs.String("runs", "./runs", "directory the run was written under")
+	exportOut = fs.String("out", "", "file or directory to write (default <run-id>.tar.gz, <run-id>.xlsx or <run-id>-notebook; - for stdout)")
+	exportFormat = fs.String("format", "bundle", "bundle (reproducible tarball), xlsx (sweep results workbook) or parquet (notebook files)")
+	exportJSON = fs.Bool("json", false, "print the bundle index as JSON")
+
+	register(&command{
+		Name:    "export-run",
+		Summary: "Export a run as a reproducible tarball, an Excel workbook or Parquet files for notebooks",
+		Flags:   fs,
+		Args:    []string{"<run-id>"},
+		Run:     runExportRun,
+	})
+
+	fs = flag.NewFlagSet("import-run", flag.ExitOnError)
+	importRunsDir = fs.String("runs", "./runs", "directory the run is restored under")
+	importForce = fs.Bool("force", false, "replace a run with the same ID")
+	importJSON = fs.Bool("json", false, "print the bundle index as JSON")
+
+	register(&command{
+		Name:    "import-run",
+		Summary: "Restore a run exported with export-run, checking every file against its fingerprint",
+		Flags:   fs,
+		Args:    []string{"<bundle.tar.gz>"},
+		Run:     runImportRun,
+	})
+}
+
+// runExportRun bundles a run directory written by `gohypo-cli run` into one tarball that
+// another instance can import for review, or writes its sweep results as a workbook
+func runExportRun(ctx context.Context, fs *flag.FlagSet) error {
+	runID := fs.Arg(0)
+	if runID == "" {
+		return apperrors.InvalidInput("usage: gohypo-cli export-run [--runs dir] [--out file] [--format bundle|xlsx|parquet] <run-id>")
+	}
+	runDir := filepath.Join(*exportRunsDir, runID)
+	if info, err := os.Stat(runDir); err != nil || !info.IsDir() {
+		return apperrors.NotFound(fmt.Sprintf("run %s under %s", runID, *exportRunsDir))
+	}
+	switch *exportFormat {
+	case "bundle":
+	case "xlsx":
+		return exportWorkbook(runID, runDir)
+	case "parquet":
+		return exportNotebook(ctx, runID, runDir)
+	default:
+		return apperrors.InvalidInput(fmt.Sprintf("unknown format %q (want bundle, xlsx or parquet)", *exportFormat))
+	}
+
+	target := *exportOut
+	if target == "" {
+		target = runID + ".tar.gz"
+	}
+	var w io.Writer = os.Stdout
+	if target != "-" {
+		file, err := os.Create(target)
+		if err != nil {
+			return fmt.Errorf("failed to create bundle: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+	index, err := reproduce.ExportBundle(w, runDir)
+	if err != nil {
+		if target != "-" {
+			os.Remove(target)
+		}
+		return err
+	}
+
+	// The bundle itself may be on stdout
+	out := io.Writer(os.Stdout)
+	if target == "-" {
+		out = os.Stderr
+	}
+	if *exportJSON {
+		return printBundleIndex(out, index)
+	}
+	fmt.Fprintf(out, "Exported run %s\n\n", index.RunID)
+	printBundleSummary(out, index)
+	if target != "-" {
+		fmt.Fprintf(out, "\nBundle: %s\n", target)
+	}
+	return nil
+}
+
+// exportWorkbook writes the relationships, profiles, warnings and manifest a run's ledger
+// recorded as an Excel workbook
+func exportWorkbook(runID, runDir string) error {
+	run, err := readRecordedRun(filepath.Join(runDir, fileLedgerName))
+	if err != nil {
+		return err
+	}
+	target := *exportOut
+	if target == "" {
+		target = runID + ".xlsx"
+	}
+	var w io.Writer = os.Stdout
+	if target != "-" {
+		file, err := os.Create(target)
+		if err != nil {
+			return fmt.Errorf("failed to create workbook: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := excel.WriteSweepWorkbook(w, run.Artifacts); err != nil {
+		if target != "-" {
+			os.Remove(target)
+		}
+		return err
+	}
+	if target != "-" {
+		fmt.Printf("Exported the sweep results of run %s\n\nWorkbook: %s\n", runID, target)
+	}
+	return nil
+}
+
+// exportNotebook writes a run's relationships, matrix and validation results as Parquet
+// files with a pandas loader. The matrix is resolved again from the recorded dataset as
+// replay does, and left out when the dataset cannot be found.
+func exportNotebook(ctx context.Context, runID, runDir string) error {
+	run, err := readRecordedRun(filepath.Join(runDir, fileLedgerName))
+	if err != nil {
+		return err
+	}
+	target := *exportOut
+	if target == "" {
+		target = runID + "-notebook"
+	}
+	if target == "-" {
+		return apperrors.InvalidInput("a parquet export is a directory; pass --out <dir>")
+	}
+
+	var bundle *dataset.MatrixBundle
+	var missing error
+	if inputs, _, err := run.inputs(); err != nil {
+		missing = err
+	} else if bundle, err = recordedMatrix(ctx, inputs, runDir); err != nil {
+		missing = err
+	}
+	export, err := notebook.WriteExport(target, runID, run.Artifacts, bundle)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported run %s for notebooks\n\n", runID)
+	fmt.Printf("  relationships  %d\n", export.Relationships)
+	if bundle != nil {
+		fmt.Printf("  matrix         %d entities x %d variables\n", export.Entities, export.Variables)
+	} else {
+		fmt.Printf("  matrix         left out: %v\n", missing)
+	}
+	fmt.Printf("  validation     %d hypotheses\n", export.Validations)
+	fmt.Printf("\nFiles in %s: %s\n", target, strings.Join(export.Files, ", "))
+	fmt.Printf("In a notebook: %%run %s\n", filepath.Join(target, notebook.LoaderFile))
+	return nil
+}
+
+// recordedMatrix resolves the matrix a run swept from its recorded dataset and config
+func recordedMatrix(ctx context.Context, inputs *reproduce.RunInputs, runDir string) (*dataset.MatrixBundle, error) {
+	var spec runSpec
+	if err := json.Unmarshal(inputs.Config, &spec); err != nil {
+		return nil, fmt.Errorf("failed to decode run config: %w", err)
+	}
+	path, err := inputs.LocateDataset(runDir)
+	if err != nil {
+		return nil, err
+	}
+	_, restore := quietLibraryOutput(false)
+	defer restore()
+	headers, columns, err := readColumns(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return loadBundle(ctx, path, spec.resolveVariables(headers, columns), spec.Locale, spec.TimeZone)
+}
+
+// runImportRun restores an exported run under the runs directory, where replay, tui and
+// reproduce find it
+func runImportRun(ctx context.Context, fs *flag.FlagSet) error {
+	path := fs.Arg(0)
+	if path == "" {
+		return apperrors.InvalidInput("usage: gohypo-cli import-run [--runs dir] [--force] <bundle.tar.gz>")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	index, runDir, err := reproduce.ImportBundle(file, *importRunsDir, *importForce)
+	if err != nil {
+		return err
+	}
+	if *importJSON {
+		return printBundleIndex(os.Stdout, index)
+	}
+	fmt.Printf("Imported run %s into %s\n\n", index.RunID, runDir)
+	printBundleSummary(os.Stdout, index)
+	// The bundle references the dataset rather than carrying it; replay looks for it at its
+	// recorded path, then inside the run directory
+	if index.Dataset != nil {
+		if _, err := (reproduce.RunInputs{Dataset: *index.Dataset}).LocateDataset(runDir); err != nil {
+			fmt.Printf("\nTo replay it, copy %s (sha256 %s) into %s\n", index.Dataset.Name, shortHash(index.Dataset.SHA256), runDir)
+		}
+	}
+	return nil
+}
+
+// printBundleSummary writes a bundle's fingerprints and section counts
+func printBundleSummary(w io.Writer, index *reproduce.BundleIndex) {
+	if index.Dataset != nil {
+		fmt.Fprintf(w, "  dataset      %s (sha256 %s, %d bytes)\n", index.Dataset.Name, shortHash(index.Dataset.SHA256), index.Dataset.Size)
+	}
+	if index.Fingerprints.Matrix != "" {
+		fmt.Fprintf(w, "  matrix       %s\n", shortHash(index.Fingerprints.Matrix))
+	}
+	if index.Fingerprints.Config != "" {
+		fmt.Fprintf(w, "  config       %s\n", shortHash(index.Fingerprints.Config))
+	}
+	if index.Fingerprints.Results != "" {
+		fmt.Fprintf(w, "  results      %s\n", shortHash(index.Fingerprints.Results))
+	}
+	sections := make([]string, 0, len(index.Artifacts))
+	for section := range index.Artifacts {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	for _, section := range sections {
+		fmt.Fprintf(w, "  %-12s %d artifacts\n", section, index.Artifacts[section])
+	}
+	fmt.Fprintf(w, "  files        %d, each checked against its sha256\n", len(index.Files))
+}
+
+// printBundleIndex writes a bundle index as indented JSON
+func printBundleIndex(w io.Writer, index *reproduce.BundleIndex) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(index)
+}
+
+// shortHash abbreviates a hash for display
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
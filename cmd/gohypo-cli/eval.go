@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/joho/godotenv"
+
+	"gohypo/adapters/llm"
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/eval"
+)
+
+var (
+	evalFixtures *string
+	evalRecorded *bool
+	evalJudge    *bool
+	evalLabel    *string
+	evalOut      *string
+	evalBaseline *string
+	evalJSON     *bool
+	evalVerbose  *bool
+)
+
+func init() {
+	fs := flag.NewFlagSet("eval", flag.ExitOnError)
+	evalFixtures = fs.String("fixtures", "internal/eval/testdata", "directory of recorded relationship fixtures (*.json)")
+	evalRecorded = fs.Bool("recorded", false, "score the fixtures' recorded outputs instead of generating")
+	evalJudge = fs.Bool("judge", false, "also grade each hypothesis with the LLM judge")
+	evalLabel = fs.String("label", "", "name for this run in the report, e.g. the prompt or model under test")
+	evalOut = fs.String("out", "", "write the report as JSON to this file")
+	evalBaseline = fs.String("baseline", "", "compare against a report written earlier with --out")
+	evalJSON = fs.Bool("json", false, "print the report as JSON")
+	evalVerbose = fs.Bool("verbose", false, "show service debug output")
+
+	register(&command{
+		Name:    "eval",
+		Summary: "Score generated hypotheses on recorded fixtures for grounding, specificity and testability",
+		Flags:   fs,
+		Run:     runEval,
+	})
+}
+
+func runEval(ctx context.Context, fs *flag.FlagSet) error {
+	fixtures, err := eval.LoadFixtures(*evalFixtures)
+	if err != nil {
+		return apperrors.InvalidInput(err.Error())
+	}
+	var baseline *eval.Report
+	if *evalBaseline != "" {
+		if baseline, err = readEvalReport(*evalBaseline); err != nil {
+			return err
+		}
+	}
+	env, _ := loadDoctorEnv(godotenv.Load())
+
+	out, restore := quietLibraryOutput(*evalVerbose)
+	defer restore()
+
+	harness := &eval.Harness{Label: *evalLabel}
+	if !*evalRecorded {
+		harness.Generator = llm.NewGreenfieldAdapter(env.ai)
+	}
+	if *evalJudge {
+		harness.Judge = eval.NewLLMJudge(env.ai)
+	}
+	report, err := harness.Run(ctx, fixtures)
+	if err != nil {
+		return err
+	}
+
+	if *evalOut != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*evalOut, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+	if *evalJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printEvalReport(out, report)
+	if baseline != nil {
+		printEvalComparison(out, baseline, report)
+	}
+	return nil
+}
+
+// readEvalReport loads a report written by an earlier --out
+func readEvalReport(path string) (*eval.Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var report eval.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, apperrors.InvalidInput(fmt.Sprintf("baseline %s is not an eval report: %v", path, err))
+	}
+	return &report, nil
+}
+
+// printEvalReport prints per-fixture scores, then the run summary
+func printEvalReport(w io.Writer, report *eval.Report) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIXTURE\tHYPOTHESES\tGROUNDING\tSPECIFICITY\tTESTABILITY\tOVERALL\tJUDGE")
+	for _, fr := range report.Fixtures {
+		if fr.Error != "" {
+			fmt.Fprintf(tw, "%s\tfailed: %s\t\t\t\t\t\n", fr.Fixture, fr.Error)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.2f\t%.2f\t%.2f\t%.2f\t%s\n", fr.Fixture, fr.Scores.Directives,
+			fr.Scores.Grounding, fr.Scores.Specificity, fr.Scores.Testability, fr.Scores.Overall, judgeColumn(fr.Scores))
+	}
+	s := report.Summary
+	fmt.Fprintf(tw, "ALL\t%d\t%.2f\t%.2f\t%.2f\t%.2f\t%s\n", s.Directives, s.Grounding, s.Specificity, s.Testability, s.Overall, judgeColumn(s))
+	tw.Flush()
+
+	for _, fr := range report.Fixtures {
+		for _, ds := range fr.Directives {
+			for _, issue := range ds.Issues {
+				fmt.Fprintf(w, "  %s %s: %s\n", fr.Fixture, ds.ID, issue)
+			}
+		}
+	}
+	if report.Usage != nil && report.Usage.Calls > 0 {
+		fmt.Fprintf(w, "\n%d LLM calls, %d tokens, est. $%.4f\n", report.Usage.Calls, report.Usage.TotalTokens, report.Usage.EstimatedCostUSD)
+	}
+}
+
+func judgeColumn(s eval.Scores) string {
+	if s.Judged == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", s.JudgeOverall)
+}
+
+// printEvalComparison prints the summary change against a baseline report
+func printEvalComparison(w io.Writer, baseline, report *eval.Report) {
+	fmt.Fprintf(w, "\nAgainst baseline %q:\n", baseline.Label)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METRIC\tBASELINE\tCANDIDATE\tCHANGE")
+	for _, d := range eval.Compare(baseline, report) {
+		fmt.Fprintf(tw, "%s\t%.2f\t%.2f\t%+.2f\n", d.Metric, d.Baseline, d.Candidate, d.Change)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gohypo/internal/buildinfo"
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/golden"
+)
+
+var (
+	goldenDir      *string
+	goldenCase     *string
+	goldenUpdate   *bool
+	goldenAbsolute *float64
+	goldenRelative *float64
+	goldenJSON     *bool
+	goldenVerbose  *bool
+)
+
+func init() {
+	fs := flag.NewFlagSet("golden", flag.ExitOnError)
+	goldenDir = fs.String("dir", "internal/golden/testdata", "directory of golden cases, one directory with a data.* file each")
+	goldenCase = fs.String("case", "", "run only this case")
+	goldenUpdate = fs.Bool("update", false, "record the current results as expected instead of comparing")
+	goldenAbsolute = fs.Float64("abs", golden.DefaultTolerance.Absolute, "absolute tolerance of a value")
+	goldenRelative = fs.Float64("rel", golden.DefaultTolerance.Relative, "tolerance of a value relative to its expected value")
+	goldenJSON = fs.Bool("json", false, "print the reports as JSON")
+	goldenVerbose = fs.Bool("verbose", false, "show service debug output")
+
+	register(&command{
+		Name:    "golden",
+		Summary: "Sweep the golden datasets and report which stat engines drifted from the recorded results",
+		Flags:   fs,
+		Run:     runGolden,
+	})
+}
+
+// goldenSeed seeds cases whose spec sets none, so their results are repeatable
+const goldenSeed = 42
+
+// runGolden sweeps every golden case and compares the relationships with the recorded ones,
+// failing when any case drifted. With --update it records them instead, which is how a
+// deliberate change of results is accepted.
+func runGolden(ctx context.Context, fs *flag.FlagSet) error {
+	cases, err := golden.LoadCases(*goldenDir)
+	if err != nil {
+		return apperrors.InvalidInput(err.Error())
+	}
+	tolerance := golden.Tolerance{Absolute: *goldenAbsolute, Relative: *goldenRelative}
+
+	out, restore := quietLibraryOutput(*goldenVerbose)
+	defer restore()
+
+	var reports []*golden.Report
+	ran := 0
+	for _, c := range cases {
+		if *goldenCase != "" && c.Name != *goldenCase {
+			continue
+		}
+		ran++
+		relationships, err := sweepGoldenCase(ctx, c)
+		if err != nil {
+			return fmt.Errorf("golden case %s: %w", c.Name, err)
+		}
+		if *goldenUpdate {
+			if err := c.Record(relationships, buildinfo.Methods()); err != nil {
+				return fmt.Errorf("golden case %s: %w", c.Name, err)
+			}
+			fmt.Fprintf(out, "recorded %s: %d relationships\n", c.Name, len(relationships))
+			continue
+		}
+		report, err := c.Compare(relationships, buildinfo.Methods(), tolerance)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, report)
+	}
+	if ran == 0 {
+		return apperrors.NotFound("golden case " + *goldenCase)
+	}
+	if *goldenUpdate {
+		return nil
+	}
+
+	if *goldenJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		printGoldenReports(out, reports)
+	}
+	failed := 0
+	for _, report := range reports {
+		if !report.Passed() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d golden cases drifted (re-record deliberate changes with --update)",
+			golden.ErrDrift, failed, len(reports))
+	}
+	return nil
+}
+
+// sweepGoldenCase sweeps a case's dataset with its spec
+func sweepGoldenCase(ctx context.Context, c *golden.Case) ([]golden.Relationship, error) {
+	spec, err := loadRunSpec(c.SpecPath)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Seed == 0 {
+		spec.Seed = goldenSeed
+	}
+	headers, columns, err := readColumns(c.DataPath)
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := loadBundle(ctx, c.DataPath, spec.resolveVariables(headers, columns), spec.Locale, spec.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := newSweepService().RunStatsSweep(ctx, spec.sweepRequest(bundle))
+	if err != nil {
+		return nil, err
+	}
+	return golden.Relationships(resp.Relationships), nil
+}
+
+// printGoldenReports prints one line per case, then every drifted value
+func printGoldenReports(w io.Writer, reports []*golden.Report) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CASE\tRESULT\tDETAIL")
+	for _, report := range reports {
+		result := "✓ pass"
+		if !report.Passed() {
+			result = "✗ drift"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", report.Case, result, report.Summary())
+	}
+	tw.Flush()
+
+	for _, report := range reports {
+		if report.Passed() {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s\n", report.Case)
+		for _, change := range report.MethodChanges {
+			fmt.Fprintf(w, "  method version %s\n", change)
+		}
+		for _, id := range report.Missing {
+			fmt.Fprintf(w, "  - %s: expected, not produced\n", id)
+		}
+		for _, id := range report.Extra {
+			fmt.Fprintf(w, "  + %s: produced, not expected\n", id)
+		}
+		for _, drift := range report.Drifts {
+			fmt.Fprintf(w, "  ~ [%s] %s %s: %s → %s\n", drift.Engine, drift.Relationship, drift.Field,
+				goldenValue(drift.Expected), goldenValue(drift.Actual))
+		}
+	}
+}
+
+func goldenValue(v *float64) string {
+	if v == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%.10g", *v)
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"gohypo/adapters/llm"
+	"gohypo/app"
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/domain/greenfield"
+	"gohypo/domain/stage"
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+var (
+	hypothesesIn         *string
+	hypothesesSpec       *string
+	hypothesesShowPrompt bool
+	hypothesesJSON       *bool
+	hypothesesVerbose    *bool
+	hypothesesSamples    *int
+)
+
+func init() {
+	fs := flag.NewFlagSet("hypotheses", flag.ExitOnError)
+	hypothesesIn = fs.String("in", "", "CSV or Excel file to generate hypotheses for")
+	hypothesesSpec = fs.String("spec", "", "YAML run spec (variables, rigor, fdr_method, inference, workers, seed)")
+	fs.BoolVar(&hypothesesShowPrompt, "show-prompt", false, "print the assembled prompt without calling the LLM")
+	fs.BoolVar(&hypothesesShowPrompt, "dry-run", false, "alias for --show-prompt")
+	hypothesesJSON = fs.Bool("json", false, "print the result (or the prompt preview) as JSON")
+	hypothesesVerbose = fs.Bool("verbose", false, "show service debug output")
+	hypothesesSamples = fs.Int("samples", -1, "self-consistency samples per generation call (default 5 for rigor: decision, else 1)")
+
+	register(&command{
+		Name:    "hypotheses",
+		Summary: "Sweep a dataset and generate research hypotheses, or show the prompt with --show-prompt",
+		Flags:   fs,
+		Run:     runHypotheses,
+	})
+}
+
+func runHypotheses(ctx context.Context, fs *flag.FlagSet) error {
+	if *hypothesesIn == "" {
+		return apperrors.InvalidInput("--in is required")
+	}
+	spec, err := loadRunSpec(*hypothesesSpec)
+	if err != nil {
+		return err
+	}
+	if err := spec.applyDefaultSeed(); err != nil {
+		return err
+	}
+	env, _ := loadDoctorEnv(godotenv.Load())
+
+	out, restore := quietLibraryOutput(*hypothesesVerbose)
+	defer restore()
+
+	req, err := buildHypothesesRequest(ctx, spec, *hypothesesIn)
+	if err != nil {
+		return err
+	}
+	req.SelfConsistencySamples = *hypothesesSamples
+	if req.SelfConsistencySamples < 0 {
+		req.SelfConsistencySamples = 1
+		if spec.Rigor == stage.RigorDecision {
+			req.SelfConsistencySamples = models.DecisionRigorSamples
+		}
+	}
+	adapter := llm.NewGreenfieldAdapter(env.ai)
+
+	if hypothesesShowPrompt {
+		preview, err := adapter.PreviewResearchPrompt(ctx, req)
+		if err != nil {
+			return err
+		}
+		if *hypothesesJSON {
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			return enc.Encode(preview)
+		}
+		printPromptPreview(out, preview)
+		return nil
+	}
+
+	resp, err := adapter.GenerateResearchDirectives(ctx, req)
+	if err != nil {
+		return err
+	}
+	if *hypothesesJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(resp)
+	}
+	printDirectives(out, resp)
+	return nil
+}
+
+// buildHypothesesRequest sweeps the file and packages fields and relationships the way the
+// research worker does, so the CLI prompt matches what the server would send
+func buildHypothesesRequest(ctx context.Context, spec *runSpec, path string) (ports.GreenfieldResearchRequest, error) {
+	headers, err := readHeaders(path)
+	if err != nil {
+		return ports.GreenfieldResearchRequest{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	bundle, err := loadBundle(ctx, path, spec.variableKeys(headers), spec.Locale, spec.TimeZone)
+	if err != nil {
+		return ports.GreenfieldResearchRequest{}, err
+	}
+	resp, err := newSweepService().RunStatsSweep(ctx, spec.sweepRequest(bundle))
+	if err != nil {
+		return ports.GreenfieldResearchRequest{}, fmt.Errorf("sweep failed: %w", err)
+	}
+	return sweepHypothesesRequest(core.RunID(fmt.Sprintf("cli-%d", time.Now().Unix())), path, bundle, resp), nil
+}
+
+// sweepHypothesesRequest packages a finished sweep's fields and relationships as a
+// generation request
+func sweepHypothesesRequest(runID core.RunID, path string, bundle *dataset.MatrixBundle, resp *app.StatsSweepResponse) ports.GreenfieldResearchRequest {
+	artifacts := make([]map[string]interface{}, 0, len(resp.Relationships)+1)
+	for _, a := range append(resp.Relationships, resp.Manifest) {
+		artifacts = append(artifacts, map[string]interface{}{
+			"kind":       string(a.Kind),
+			"id":         a.ID,
+			"payload":    a.Payload,
+			"created_at": a.CreatedAt,
+		})
+	}
+
+	return ports.GreenfieldResearchRequest{
+		RunID:                runID,
+		SnapshotID:           core.SnapshotID(filepath.Base(path)),
+		FieldMetadata:        bundleFieldMetadata(bundle),
+		StatisticalArtifacts: artifacts,
+		Directives:           3,
+	}
+}
+
+// bundleFieldMetadata describes the resolved matrix columns
+func bundleFieldMetadata(bundle *dataset.MatrixBundle) []greenfield.FieldMetadata {
+	types := make(map[core.VariableKey]string, len(bundle.ColumnMeta))
+	for _, meta := range bundle.ColumnMeta {
+		types[meta.VariableKey] = string(meta.StatisticalType)
+	}
+	fields := make([]greenfield.FieldMetadata, len(bundle.Matrix.VariableKeys))
+	for i, key := range bundle.Matrix.VariableKeys {
+		fields[i] = greenfield.FieldMetadata{Name: string(key), DataType: types[key]}
+	}
+	return fields
+}
+
+// printPromptPreview prints the section sizes, then the exact prompt text
+func printPromptPreview(w io.Writer, preview *ports.GreenfieldPromptPreview) {
+	fmt.Fprintf(w, "Dry run: %s model %s, max %d completion tokens (LLM not called)\n\n", preview.Provider, preview.Model, preview.MaxTokens)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "SECTION\tCHARS\t")
+	for _, section := range preview.Sections {
+		fmt.Fprintf(tw, "%s\t%d\t\n", section.Name, section.Chars)
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\t\n", preview.TotalChars)
+	tw.Flush()
+	if budget := preview.Budget; budget != nil {
+		fmt.Fprintf(w, "\nBudget: ~%d of %d tokens, %d fragments included, %d dropped\n",
+			budget.UsedTokens, budget.LimitTokens, len(budget.Included), len(budget.Dropped))
+		for _, id := range budget.Dropped {
+			fmt.Fprintf(w, "  dropped %s\n", id)
+		}
+	}
+	fmt.Fprintln(w, "\n----- prompt -----")
+	fmt.Fprintln(w, preview.FullPrompt)
+}
+
+// printDirectives lists generated hypotheses with their usage
+func printDirectives(w io.Writer, resp *ports.GreenfieldResearchResponse) {
+	if len(resp.Directives) == 0 {
+		fmt.Fprintln(w, "No hypotheses generated.")
+		return
+	}
+	for i, directive := range resp.Directives {
+		fmt.Fprintf(w, "%d. %s → %s\n   %s\n", i+1, directive.CauseKey, directive.EffectKey, directive.Claim)
+		if sc := directive.SelfConsistency; sc != nil {
+			fmt.Fprintf(w, "   self-consistency %.0f%% (%d of %d samples)\n", sc.Score*100, sc.Agreeing, sc.Samples)
+		}
+	}
+	if resp.Usage != nil {
+		fmt.Fprintf(w, "\n%d LLM calls, %d tokens, est. $%.4f\n", resp.Usage.Calls, resp.Usage.TotalTokens, resp.Usage.EstimatedCostUSD)
+	}
+}
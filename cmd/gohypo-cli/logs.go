@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/logging"
+)
+
+var (
+	logsServer *string
+	logsAPIKey *string
+	logsLevel  *string
+	logsFollow *bool
+	logsJSON   *bool
+)
+
+func init() {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	logsServer = fs.String("server", "http://localhost:8080", "base URL of the GoHypo server")
+	logsAPIKey = fs.String("api-key", "", "API key, when the server requires one")
+	logsLevel = fs.String("level", "info", "lowest level shown: error, warn, info, debug or trace")
+	logsFollow = fs.Bool("f", false, "keep printing new lines as the run logs them (Ctrl+C to stop)")
+	logsJSON = fs.Bool("json", false, "print each line as a JSON object")
+
+	register(&command{
+		Name:    "logs",
+		Summary: "Print a research run's log lines from a running server, optionally following them",
+		Flags:   fs,
+		Args:    []string{"<run-id>"},
+		Run:     runLogs,
+	})
+}
+
+// runLogs prints the lines of a run (a research session ID) logged so far, then with -f
+// streams each new one until interrupted
+func runLogs(ctx context.Context, fs *flag.FlagSet) error {
+	if fs.NArg() < 1 {
+		return apperrors.InvalidInput("a run ID is required: gohypo-cli logs <run-id> [-f]")
+	}
+	runID := fs.Arg(0)
+	// Flags may also follow the run ID, as in `gohypo-cli logs <run-id> -f`
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return apperrors.InvalidInput(err.Error())
+	}
+	if fs.NArg() > 0 {
+		return apperrors.InvalidInput(fmt.Sprintf("unexpected argument %q", fs.Arg(0)))
+	}
+	if _, err := logging.ParseLevel(*logsLevel); err != nil {
+		return apperrors.InvalidInput(err.Error())
+	}
+	client, err := newAdminClient(*logsServer, *logsAPIKey)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/api/research/sessions/%s/logs", url.PathEscape(runID))
+	query := "?level=" + url.QueryEscape(*logsLevel)
+
+	if !*logsFollow {
+		var resp struct {
+			Entries []logging.Entry `json:"entries"`
+			Dropped int             `json:"dropped"`
+		}
+		if err := client.do(ctx, http.MethodGet, path+query, nil, &resp); err != nil {
+			return err
+		}
+		if resp.Dropped > 0 && !*logsJSON {
+			fmt.Fprintf(os.Stderr, "(%d older lines no longer kept)\n", resp.Dropped)
+		}
+		for _, e := range resp.Entries {
+			printLogEntry(os.Stdout, e)
+		}
+		return nil
+	}
+
+	err = client.stream(ctx, path+"/stream"+query, func(event, data string) error {
+		if event != "log" {
+			return nil
+		}
+		var e logging.Entry
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			return apperrors.ExternalServiceError("gohypo server", fmt.Errorf("unexpected log line: %w", err))
+		}
+		printLogEntry(os.Stdout, e)
+		return nil
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil // interrupted
+	}
+	return err
+}
+
+// printLogEntry prints a line as "15:04:05 LEVEL [stage] message key=value ...", or as JSON
+func printLogEntry(w io.Writer, e logging.Entry) {
+	if *logsJSON {
+		data, _ := json.Marshal(e)
+		fmt.Fprintln(w, string(data))
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-5s ", e.Time.Local().Format("15:04:05"), e.Level)
+	if stage, ok := e.Attrs[logging.StageKey].(string); ok {
+		fmt.Fprintf(&b, "[%s] ", stage)
+	}
+	b.WriteString(e.Message)
+	keys := make([]string, 0, len(e.Attrs))
+	for key := range e.Attrs {
+		if key != logging.StageKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%v", key, e.Attrs[key])
+	}
+	fmt.Fprintln(w, b.String())
+}
+
+// stream reads the server-sent events at path, calling handle with each event's name and
+// data, until the server closes the stream, handle fails or ctx is done
+func (c *adminClient) stream(ctx context.Context, path string, handle func(event, data string) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	// The client's timeout would cut a stream that is meant to stay open
+	resp, err := (&http.Client{Transport: c.http.Transport}).Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return apperrors.ExternalServiceError("gohypo server", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return responseError(resp.StatusCode, data)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if event != "" || len(data) > 0 {
+				if err := handle(event, strings.Join(data, "\n")); err != nil {
+					return err
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := scanner.Err(); err != nil {
+		return apperrors.ExternalServiceError("gohypo server", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// Command gohypo-cli runs GoHypo sweeps and tooling from the terminal.
+//
+// Usage:
+//
+//	gohypo-cli [--config file] <command> [flags]
+//
+// Settings come from ~/.gohypo/config.yaml (or --config, or $GOHYPO_CONFIG), with
+// environment variables and a .env file overriding it and flags overriding both.
+//
+// Run `gohypo-cli help` for the list of commands and `gohypo-cli completion --help` for
+// shell completions.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"gohypo/internal/config"
+	apperrors "gohypo/internal/errors"
+)
+
+// command is a single CLI subcommand. Its flags are declared on a standard FlagSet and
+// parsed by cobra, so `-f` and `--flag` forms work and flags may follow positional args.
+type command struct {
+	Name    string
+	Summary string
+	Long    string
+	Flags   *flag.FlagSet
+	Args    []string // Positional argument choices, used for shell completion
+	Run     func(ctx context.Context, fs *flag.FlagSet) error
+
+	// Subcommands have flags of their own, such as `admin users --limit 5`; the parent's
+	// flags apply to each of them
+	Subcommands []*command
+}
+
+// commands is the registry populated by each command file's init
+var commands = map[string]*command{}
+
+// register adds a command to the registry
+func register(cmd *command) {
+	if cmd.Flags == nil {
+		cmd.Flags = flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	}
+	commands[cmd.Name] = cmd
+}
+
+// envFlags are the flags that default to an environment variable, by command. The config
+// file sets these variables, so a flag wins over the environment, which wins over the file.
+var envFlags = map[string]map[string]string{
+	"admin":      {"server": config.ServerEnv, "api-key": config.APIKeyEnv},
+	"logs":       {"server": config.ServerEnv, "api-key": config.APIKeyEnv},
+	"run":        {"ledger": config.LedgerEnv, "out": config.RunsDirEnv},
+	"replay":     {"runs": config.RunsDirEnv},
+	"export-run": {"runs": config.RunsDirEnv},
+	"import-run": {"runs": config.RunsDirEnv},
+	"tui":        {"runs": config.RunsDirEnv, "data": config.DataDirEnv},
+	"watch":      {"runs": config.RunsDirEnv, "in": config.DataDirEnv},
+}
+
+// configFlag is the --config flag; configPath is the config file that was applied, "" when
+// there was none
+var configFlag, configPath string
+
+// loadConfig applies the config file to the environment and the environment to the flags of
+// the named command that were not given on the command line
+func loadConfig(name string, flags *pflag.FlagSet) error {
+	godotenv.Load() // .env overrides the config file, as the process environment does
+	file, loaded, err := config.LoadFile(configFlag)
+	if err != nil {
+		return err
+	}
+	file.Apply()
+	configPath = loaded
+
+	for flagName, key := range envFlags[name] {
+		value := os.Getenv(key)
+		f := flags.Lookup(flagName)
+		if value == "" || f == nil || f.Changed {
+			continue
+		}
+		if err := f.Value.Set(value); err != nil {
+			return apperrors.ConfigInvalid(fmt.Sprintf("%s: %v", key, err))
+		}
+	}
+	return nil
+}
+
+// commandNames returns registered command names in stable order
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newRootCommand builds the cobra command tree from the registry
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "gohypo-cli",
+		Short:         "Run GoHypo sweeps and tooling from the terminal",
+		Long:          fmt.Sprintf("Run GoHypo sweeps and tooling from the terminal.\n\nThe config file defaults to %s.", config.DefaultFilePath()),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVar(&configFlag, "config", "", "YAML config file (default $GOHYPO_CONFIG or ~/.gohypo/config.yaml)")
+	root.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return apperrors.InvalidInput(err.Error())
+	})
+	for _, name := range commandNames() {
+		root.AddCommand(newCobraCommand(commands[name], name))
+	}
+	return root
+}
+
+// newCobraCommand wraps a registered command; top is the name of the top-level command it
+// belongs to, which selects its environment defaults
+func newCobraCommand(cmd *command, top string) *cobra.Command {
+	c := &cobra.Command{
+		Use:       cmd.Name,
+		Short:     cmd.Summary,
+		Long:      cmd.Long,
+		ValidArgs: cmd.Args,
+	}
+	if len(cmd.Subcommands) > 0 {
+		c.PersistentFlags().AddGoFlagSet(cmd.Flags)
+		for _, sub := range cmd.Subcommands {
+			c.AddCommand(newCobraCommand(sub, top))
+		}
+		return c
+	}
+
+	c.Flags().AddGoFlagSet(cmd.Flags)
+	c.RunE = func(c *cobra.Command, args []string) error {
+		if err := loadConfig(top, c.Flags()); err != nil {
+			return err
+		}
+		// Flags are already set; this hands the positional arguments to the FlagSet
+		if err := cmd.Flags.Parse(append([]string{"--"}, args...)); err != nil {
+			return apperrors.InvalidInput(err.Error())
+		}
+		return cmd.Run(c.Context(), cmd.Flags)
+	}
+	return c
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if cmd, err := newRootCommand().ExecuteContextC(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.CommandPath(), err)
+		os.Exit(apperrors.ExitCode(err))
+	}
+}
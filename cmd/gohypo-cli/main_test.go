@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"gohypo/internal/config"
+)
+
+// probeCommand registers a throwaway command whose flag defaults to $GOHYPO_SERVER and
+// records what it ran with
+func probeCommand(t *testing.T) (server *string, verbose *bool, args *[]string) {
+	t.Helper()
+	empty := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.FileEnv, empty)
+
+	fs := flag.NewFlagSet("probe", flag.ContinueOnError)
+	server = fs.String("server", "http://localhost:8080", "server")
+	verbose = fs.Bool("v", false, "verbose")
+	args = new([]string)
+	register(&command{Name: "probe", Summary: "probe", Flags: fs, Run: func(ctx context.Context, fs *flag.FlagSet) error {
+		*args = fs.Args()
+		return nil
+	}})
+	envFlags["probe"] = map[string]string{"server": config.ServerEnv}
+	t.Cleanup(func() {
+		delete(commands, "probe")
+		delete(envFlags, "probe")
+	})
+	return server, verbose, args
+}
+
+// TestFlagsOverrideEnvironmentDefaults verifies a flag given on the command line wins over
+// its environment default, the environment applies otherwise, and flags may follow
+// positional arguments
+func TestFlagsOverrideEnvironmentDefaults(t *testing.T) {
+	server, verbose, args := probeCommand(t)
+	t.Setenv(config.ServerEnv, "http://from-env")
+
+	root := newRootCommand()
+	root.SetArgs([]string{"probe", "run-1", "-v", "--server", "http://from-flag", "run-2"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if *server != "http://from-flag" || !*verbose {
+		t.Errorf("expected the flags to apply, got server=%s verbose=%v", *server, *verbose)
+	}
+	if strings.Join(*args, " ") != "run-1 run-2" {
+		t.Errorf("expected positional args run-1 run-2, got %v", *args)
+	}
+
+	*server = "http://localhost:8080"
+	root = newRootCommand()
+	root.SetArgs([]string{"probe"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if *server != "http://from-env" {
+		t.Errorf("expected the environment default, got %s", *server)
+	}
+}
+
+// TestAdminSubcommandsTakeParentFlags verifies admin subcommands are real commands with their
+// own flags, and that the admin connection flags are accepted on either side of them
+func TestAdminSubcommandsTakeParentFlags(t *testing.T) {
+	probeCommand(t)
+	for _, argv := range [][]string{
+		{"admin", "--server", "http://example", "datasets", "--help"},
+		{"admin", "datasets", "--server", "http://example", "--help"},
+	} {
+		root := newRootCommand()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs(argv)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("%v: %v", argv, err)
+		}
+		if !strings.Contains(out.String(), "--workspace") || !strings.Contains(out.String(), "--api-key") {
+			t.Errorf("%v: expected datasets help to list its own and admin's flags, got:\n%s", argv, out.String())
+		}
+	}
+	if *adminServer != "http://example" {
+		t.Errorf("expected --server to reach the admin client, got %s", *adminServer)
+	}
+}
+
+// TestCompletionCoversCommands verifies the generated completion scripts know every
+// registered command
+func TestCompletionCoversCommands(t *testing.T) {
+	probeCommand(t)
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		root := newRootCommand()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs([]string{"completion", shell})
+		if err := root.Execute(); err != nil {
+			t.Fatalf("completion %s: %v", shell, err)
+		}
+		if !strings.Contains(out.String(), "gohypo-cli") {
+			t.Errorf("completion %s: expected a script for gohypo-cli", shell)
+		}
+	}
+
+	root := newRootCommand()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs([]string{cobra.ShellCompRequestCmd, "mig"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if !strings.Contains(out.String(), "migrate") {
+		t.Errorf("expected migrate among the completions of mig, got:\n%s", out.String())
+	}
+}
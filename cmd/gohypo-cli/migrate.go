@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/migration"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	migrateSteps *int
+	migrateJSON  *bool
+)
+
+func init() {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	migrateSteps = fs.Int("steps", 1, "number of migrations down reverts")
+	migrateJSON = fs.Bool("json", false, "print status as JSON")
+
+	register(&command{
+		Name:    "migrate",
+		Summary: "Apply, revert or inspect the database schema migrations the server runs at startup",
+		Flags:   fs,
+		Args:    []string{"up", "down", "status", "force", "reset"},
+		Run:     runMigrate,
+	})
+}
+
+// runMigrate runs one migration action against DATABASE_URL with the server's migrations:
+// up applies the pending ones, down reverts the latest, status lists them, force records a
+// version after a failed migration was repaired by hand, and reset rebuilds an empty schema
+// on a development database
+func runMigrate(ctx context.Context, fs *flag.FlagSet) error {
+	if fs.NArg() < 1 {
+		return apperrors.InvalidInput("an action is required: gohypo-cli migrate <up|down|status|force|reset>")
+	}
+	action := fs.Arg(0)
+	// Flags may also follow the action, as in `gohypo-cli migrate down --steps 2`
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return apperrors.InvalidInput(err.Error())
+	}
+	switch action {
+	case "up", "down", "status", "force", "reset":
+	default:
+		return apperrors.InvalidInput(fmt.Sprintf("unknown action %q: use up, down, status, force or reset", action))
+	}
+
+	db, err := connectDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	runner := migration.ForDriver(db.DriverName())
+
+	switch action {
+	case "up":
+		if err := runner.Up(ctx, db); err != nil {
+			return err
+		}
+	case "down":
+		if err := runner.Down(ctx, db, *migrateSteps); err != nil {
+			return err
+		}
+	case "force":
+		if fs.NArg() < 1 {
+			return apperrors.InvalidInput("a version is required: gohypo-cli migrate force <version>")
+		}
+		version, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+		if err != nil {
+			return apperrors.InvalidInput(fmt.Sprintf("invalid version %q", fs.Arg(0)))
+		}
+		if err := runner.Force(ctx, db, version); err != nil {
+			return err
+		}
+	case "reset":
+		if os.Getenv("APP_ENV") != "development" {
+			return apperrors.Forbidden("reset drops all data and requires APP_ENV=development")
+		}
+		if err := runner.Reset(ctx, db); err != nil {
+			return err
+		}
+	}
+	return printMigrationStatus(ctx, runner, db)
+}
+
+// printMigrationStatus lists every migration and whether the database has it
+func printMigrationStatus(ctx context.Context, runner *migration.MigrationRunner, db *sqlx.DB) error {
+	statuses, err := runner.Status(ctx, db)
+	if err != nil {
+		return err
+	}
+	if *migrateJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED")
+	for _, s := range statuses {
+		state, appliedAt := "pending", "-"
+		if s.Applied {
+			state = "applied"
+			appliedAt = s.AppliedAt.Local().Format(time.RFC3339)
+		}
+		if s.Dirty {
+			state = "DIRTY (repair, then 'gohypo-cli migrate force <version>')"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
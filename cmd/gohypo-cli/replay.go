@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gohypo/domain/core"
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/reproduce"
+	"gohypo/internal/testkit"
+	"gohypo/models"
+)
+
+var (
+	replayRunsDir *string
+	replayJSON    *bool
+	replayVerbose *bool
+)
+
+func init() {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	replayRunsDir = fs.String("runs", "./runs", "directory the runs' file ledgers were written under")
+	replayJSON = fs.Bool("json", false, "print the divergence report as JSON")
+	replayVerbose = fs.Bool("verbose", false, "show service debug output")
+
+	register(&command{
+		Name:    "replay",
+		Summary: "Rerun a recorded run from its ledger and diff every artifact against the original",
+		Flags:   fs,
+		Run:     runReplay,
+	})
+}
+
+// replayReport is the outcome of a replay
+type replayReport struct {
+	RunID             string                `json:"run_id"`
+	BundleFingerprint string                `json:"bundle_fingerprint"`
+	Dataset           string                `json:"dataset"`
+	Seed              int64                 `json:"seed"`
+	Reproduced        bool                  `json:"reproduced"`
+	Divergence        *reproduce.Divergence `json:"divergence"`
+}
+
+// recordedRun is a run read back from its file ledger
+type recordedRun struct {
+	ID          string
+	Dir         string
+	Fingerprint string
+	Artifacts   []core.Artifact
+}
+
+// runReplay finds the run whose matrix has the given fingerprint, reruns the pipeline from
+// the inputs its ledger recorded and diffs every artifact against the recorded ones. The
+// recorded directives are validated again rather than regenerated, so the replay does not
+// depend on the LLM.
+func runReplay(ctx context.Context, fs *flag.FlagSet) error {
+	fingerprint := fs.Arg(0)
+	if fingerprint == "" {
+		return apperrors.InvalidInput("usage: gohypo-cli replay [--runs dir] [--json] <fingerprint>")
+	}
+	recorded, err := findRecordedRun(*replayRunsDir, fingerprint)
+	if err != nil {
+		return err
+	}
+	inputs, directives, err := recorded.inputs()
+	if err != nil {
+		return err
+	}
+	var spec runSpec
+	if err := json.Unmarshal(inputs.Config, &spec); err != nil {
+		return fmt.Errorf("failed to decode run config: %w", err)
+	}
+	path, err := inputs.LocateDataset(recorded.Dir)
+	if err != nil {
+		return err
+	}
+
+	out, restore := quietLibraryOutput(*replayVerbose)
+	defer restore()
+	progress := io.Writer(out)
+	if *replayJSON {
+		progress = io.Discard
+	}
+	fmt.Fprintf(progress, "Replaying run %s over %s (seed %d)\n\n", recorded.ID, filepath.Base(path), inputs.Seed)
+
+	ledger := testkit.NewInMemoryLedgerAdapter()
+	manifest := &pipelineManifest{RunID: recorded.ID, Dataset: path, Seed: spec.Seed, StartedAt: time.Now()}
+	opts := pipelineOptions{skipHypotheses: directives == nil, recorded: directives}
+	if err := executePipeline(ctx, progress, &spec, path, manifest, ledger, doctorEnv{}, opts); err != nil {
+		return err
+	}
+	replayed, err := ledger.GetArtifactsByRun(ctx, core.RunID(recorded.ID))
+	if err != nil {
+		return err
+	}
+	divergence, err := reproduce.DiffArtifacts(recorded.Artifacts, replayed, reproduce.VolatileFields...)
+	if err != nil {
+		return err
+	}
+
+	report := replayReport{
+		RunID:             recorded.ID,
+		BundleFingerprint: recorded.Fingerprint,
+		Dataset:           path,
+		Seed:              inputs.Seed,
+		Reproduced:        divergence.Empty(),
+		Divergence:        divergence,
+	}
+	if *replayJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printDivergence(out, divergence)
+	}
+	if !report.Reproduced {
+		return fmt.Errorf("%w: %d of %d artifacts diverged", reproduce.ErrNotReproducible,
+			len(divergence.Changed)+len(divergence.Missing)+len(divergence.Extra), len(recorded.Artifacts))
+	}
+	return nil
+}
+
+// findRecordedRun finds the run under dir whose sweep manifest has a bundle fingerprint
+// starting with prefix. Runs of the same matrix share its fingerprint; the latest is used.
+func findRecordedRun(dir, prefix string) (*recordedRun, error) {
+	ledgers, err := filepath.Glob(filepath.Join(dir, "*", fileLedgerName))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ledgers) // run IDs start with their UTC start time
+	var matches []*recordedRun
+	fingerprints := map[string]bool{}
+	for _, path := range ledgers {
+		run, err := readRecordedRun(path)
+		if err != nil {
+			return nil, err
+		}
+		if run.Fingerprint != "" && strings.HasPrefix(run.Fingerprint, prefix) {
+			matches = append(matches, run)
+			fingerprints[run.Fingerprint] = true
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		return nil, apperrors.NotFound(fmt.Sprintf("run with fingerprint %s under %s", prefix, dir))
+	case len(fingerprints) > 1:
+		return nil, apperrors.InvalidInput(fmt.Sprintf("fingerprint %s matches %d matrices; give more of it", prefix, len(fingerprints)))
+	}
+	return matches[len(matches)-1], nil
+}
+
+// readRecordedRun reads a run's artifacts back from its file ledger
+func readRecordedRun(path string) (*recordedRun, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ledger: %w", err)
+	}
+	defer file.Close()
+
+	run := &recordedRun{ID: filepath.Base(filepath.Dir(path)), Dir: filepath.Dir(path)}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var line struct {
+			RunID    string        `json:"run_id"`
+			Artifact core.Artifact `json:"artifact"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		run.Artifacts = append(run.Artifacts, line.Artifact)
+		if line.Artifact.Kind != core.ArtifactSweepManifest {
+			continue
+		}
+		if payload, ok := line.Artifact.Payload.(map[string]interface{}); ok {
+			run.Fingerprint, _ = payload["bundle_fingerprint"].(string)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return run, nil
+}
+
+// inputs decodes the run's recorded inputs and the directives it validated, nil when it
+// generated none
+func (r *recordedRun) inputs() (*reproduce.RunInputs, []models.ResearchDirectiveResponse, error) {
+	var inputs *reproduce.RunInputs
+	var directives []models.ResearchDirectiveResponse
+	for _, artifact := range r.Artifacts {
+		switch artifact.Kind {
+		case core.ArtifactRunInputs:
+			inputs = &reproduce.RunInputs{}
+			if err := decodePayload(artifact.Payload, inputs); err != nil {
+				return nil, nil, err
+			}
+		case core.ArtifactResearchDirective:
+			var directive models.ResearchDirectiveResponse
+			if err := decodePayload(artifact.Payload, &directive); err != nil {
+				return nil, nil, err
+			}
+			directives = append(directives, directive)
+		}
+	}
+	if inputs == nil {
+		return nil, nil, fmt.Errorf("%w: run %s recorded no run_inputs artifact", reproduce.ErrNotReproducible, r.ID)
+	}
+	return inputs, directives, nil
+}
+
+// decodePayload decodes a payload read back from JSON into v
+func decodePayload(payload interface{}, v interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// printDivergence writes the replay's outcome, one line per differing field
+func printDivergence(w io.Writer, d *reproduce.Divergence) {
+	fmt.Fprintln(w)
+	if d.Empty() {
+		fmt.Fprintf(w, "✓ Reproduced: %d artifacts match the recorded run\n", d.Compared)
+		return
+	}
+	for _, ref := range d.Missing {
+		fmt.Fprintf(w, "- %s %s: recorded, not replayed\n", ref.Kind, ref.ID)
+	}
+	for _, ref := range d.Extra {
+		fmt.Fprintf(w, "+ %s %s: replayed, not recorded\n", ref.Kind, ref.ID)
+	}
+	for _, artifact := range d.Changed {
+		fmt.Fprintf(w, "~ %s %s\n", artifact.Kind, artifact.ID)
+		for _, field := range artifact.Fields {
+			fmt.Fprintf(w, "    %s: %v → %v\n", field.Path, field.Recorded, field.Replayed)
+		}
+	}
+}
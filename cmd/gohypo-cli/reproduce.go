@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gohypo/app"
+	"gohypo/domain/core"
+	"gohypo/internal/buildinfo"
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/reproduce"
+)
+
+var (
+	reproduceCheckOnly *bool
+	reproduceForce     *bool
+	reproduceVerbose   *bool
+)
+
+func init() {
+	fs := flag.NewFlagSet("reproduce", flag.ExitOnError)
+	reproduceCheckOnly = fs.Bool("check", false, "only check the environment, do not replay")
+	reproduceForce = fs.Bool("force", false, "replay even when a check blocks it")
+	reproduceVerbose = fs.Bool("verbose", false, "show service debug output")
+
+	register(&command{
+		Name:    "reproduce",
+		Summary: "Check the environment against a run descriptor, then replay the run",
+		Flags:   fs,
+		Run:     runReproduce,
+	})
+}
+
+// runReproduce validates that this binary and the recorded datasets can reproduce a run,
+// then replays its sweep and compares the results with the recorded ones
+func runReproduce(ctx context.Context, fs *flag.FlagSet) error {
+	path := fs.Arg(0)
+	if path == "" {
+		return apperrors.InvalidInput("usage: gohypo-cli reproduce [-check] [-force] <descriptor.json>")
+	}
+	descriptor, err := reproduce.Read(path)
+	if err != nil {
+		return err
+	}
+	out, restore := quietLibraryOutput(*reproduceVerbose)
+	defer restore()
+
+	fmt.Fprintf(out, "Run %s, recorded %s\n\n", descriptor.RunID, descriptor.CreatedAt.Format(time.RFC3339))
+	searchDir := filepath.Dir(path)
+	findings := descriptor.Check(buildinfo.Get(), searchDir)
+	printFindings(out, findings)
+	if reproduce.Blocking(findings) && !*reproduceForce {
+		return fmt.Errorf("%w: fix the blocking checks or pass --force", reproduce.ErrNotReproducible)
+	}
+	if *reproduceCheckOnly {
+		fmt.Fprintln(out, "\nEnvironment can reproduce the run.")
+		return nil
+	}
+
+	var spec runSpec
+	if err := json.Unmarshal(descriptor.Config, &spec); err != nil {
+		return fmt.Errorf("failed to decode run config: %w", err)
+	}
+	if len(descriptor.Datasets) != 1 {
+		return apperrors.InvalidInput(fmt.Sprintf("expected one dataset in the descriptor, found %d", len(descriptor.Datasets)))
+	}
+	dataset := descriptor.Datasets[0]
+	dataPath, ok := descriptor.Locate(dataset, searchDir)
+	if !ok {
+		return apperrors.NotFound("dataset " + dataset.Name)
+	}
+
+	fmt.Fprintf(out, "\nReplaying sweep over %s...\n", dataset.Name)
+	bundle, err := loadBundle(ctx, dataPath, spec.variableKeys(nil), spec.Locale, spec.TimeZone)
+	if err != nil {
+		return err
+	}
+	resp, err := newSweepService().RunStatsSweep(ctx, spec.sweepRequest(bundle))
+	if err != nil {
+		return err
+	}
+	if fingerprint := manifestString(resp, "bundle_fingerprint"); dataset.BundleFingerprint != "" && fingerprint != dataset.BundleFingerprint {
+		fmt.Fprintf(out, "! resolved matrix differs from the run (fingerprint %s, recorded %s)\n", fingerprint, dataset.BundleFingerprint)
+	}
+
+	rows := relationshipRows(resp.Relationships)
+	if reproduce.HashResults(descriptorResults(rows)) == descriptor.ResultHash {
+		fmt.Fprintf(out, "✓ Reproduced: %d relationships match the recorded run exactly\n", len(rows))
+		return nil
+	}
+	diff := diffRelationships(resultRows(descriptor.Results), rows, spec.Policy)
+	if diff.empty() {
+		fmt.Fprintln(out, "Results differ below the reported precision.")
+	} else {
+		printRelationshipDiff(out, diff)
+	}
+	return fmt.Errorf("%w: replayed results differ from the recorded run", reproduce.ErrNotReproducible)
+}
+
+// printFindings writes one line per environment check
+func printFindings(w io.Writer, findings []reproduce.Finding) {
+	symbols := map[reproduce.Severity]string{
+		reproduce.SeverityOK:       "✓",
+		reproduce.SeverityWarning:  "!",
+		reproduce.SeverityBlocking: "✗",
+	}
+	for _, finding := range findings {
+		fmt.Fprintf(w, "%s %-16s %s\n", symbols[finding.Severity], finding.Check, finding.Detail)
+	}
+}
+
+// writeRunDescriptor exports the environment descriptor of a finished sweep into dir and
+// returns its path. The variables the run requested are pinned in the recorded config, so
+// a replay does not depend on the columns a changed file might have gained.
+func writeRunDescriptor(dir string, spec *runSpec, path string, variables []core.VariableKey, resp *app.StatsSweepResponse, rows []relationshipRow) (string, error) {
+	dataset, err := reproduce.DescribeDataset(path, manifestString(resp, "bundle_fingerprint"))
+	if err != nil {
+		return "", err
+	}
+	pinned := *spec
+	pinned.Variables = variableNames(variables)
+
+	runID := manifestString(resp, "run_id")
+	if runID == "" {
+		stem := strings.TrimSuffix(dataset.Name, filepath.Ext(dataset.Name))
+		runID = fmt.Sprintf("%s-%s", time.Now().UTC().Format("20060102T150405Z"), stem)
+	}
+	descriptor, err := reproduce.New(runID, pinned, []reproduce.DatasetVersion{dataset},
+		map[string]int64{"sweep": spec.Seed}, descriptorResults(rows))
+	if err != nil {
+		return "", err
+	}
+	descriptorPath := filepath.Join(dir, runID+".json")
+	if err := descriptor.Write(descriptorPath); err != nil {
+		return "", err
+	}
+	return descriptorPath, nil
+}
+
+// manifestString reads a string field of a sweep manifest
+func manifestString(resp *app.StatsSweepResponse, key string) string {
+	manifest, ok := resp.Manifest.Payload.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	value, _ := manifest[key].(string)
+	return value
+}
+
+// descriptorResults converts display rows into the results a descriptor records
+func descriptorResults(rows []relationshipRow) []reproduce.Result {
+	results := make([]reproduce.Result, len(rows))
+	for i, row := range rows {
+		results[i] = reproduce.Result{
+			VariableX:  row.VariableX,
+			VariableY:  row.VariableY,
+			EffectSize: row.EffectSize,
+			PValue:     row.PValue,
+			QValue:     row.QValue,
+			SampleSize: row.SampleSize,
+		}
+	}
+	return results
+}
+
+// resultRows converts recorded results back into display rows
+func resultRows(results []reproduce.Result) []relationshipRow {
+	rows := make([]relationshipRow, len(results))
+	for i, result := range results {
+		rows[i] = relationshipRow{
+			VariableX:  result.VariableX,
+			VariableY:  result.VariableY,
+			EffectSize: result.EffectSize,
+			PValue:     result.PValue,
+			QValue:     result.QValue,
+			SampleSize: result.SampleSize,
+		}
+	}
+	return rows
+}
+
+// variableNames lists a matrix's variable keys as strings
+func variableNames(keys []core.VariableKey) []string {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = string(key)
+	}
+	return names
+}
@@ -0,0 +1,577 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"gohypo/adapters/llm"
+	"gohypo/adapters/sqlite"
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/internal/buildinfo"
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/migration"
+	refereePkg "gohypo/internal/referee"
+	"gohypo/internal/reproduce"
+	"gohypo/internal/testkit"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+var (
+	runSpecPath       *string
+	runSeed           *int64
+	runLedger         *string
+	runOutDir         *string
+	runDirectives     *int
+	runSkipHypotheses *bool
+	runJSON           *bool
+	runVerbose        *bool
+)
+
+func init() {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	runSpecPath = fs.String("spec", "", "YAML run spec (variables, rigor, fdr_method, inference, workers, seed, top)")
+	runSeed = fs.Int64("seed", 0, "seed for the whole run; overrides the spec's seed when set")
+	runLedger = fs.String("ledger", ledgerFile, "where artifacts are written: file (JSONL under --out), sqlite (the SQLite DATABASE_URL the server reads) or memory (manifest only)")
+	runOutDir = fs.String("out", "./runs", "directory the run's manifest, descriptor and file ledger are written under")
+	runDirectives = fs.Int("hypotheses", 3, "number of hypotheses to generate")
+	runSkipHypotheses = fs.Bool("skip-hypotheses", false, "stop after the sweep, without generating or validating hypotheses")
+	runJSON = fs.Bool("json", false, "print the run manifest as JSON")
+	runVerbose = fs.Bool("verbose", false, "show service debug output")
+
+	register(&command{
+		Name:    "run",
+		Summary: "Run readiness, sweep, hypothesis generation and validation over one dataset file",
+		Flags:   fs,
+		Run:     runPipeline,
+	})
+}
+
+// Ledger backends of the run command
+const (
+	ledgerFile   = "file"
+	ledgerSQLite = "sqlite"
+	ledgerMemory = "memory"
+)
+
+// Pipeline stage outcomes
+const (
+	stageOK      = "ok"
+	stageSkipped = "skipped"
+	stageFailed  = "failed"
+)
+
+// pipelineManifest is the consolidated record of one run: what went in, what each stage
+// did and where its artifacts went
+type pipelineManifest struct {
+	RunID             string                 `json:"run_id"`
+	Dataset           string                 `json:"dataset"`
+	Seed              int64                  `json:"seed"`
+	Spec              runSpec                `json:"spec"`
+	Build             buildinfo.Info         `json:"build"`
+	Ledger            string                 `json:"ledger"`
+	LedgerPath        string                 `json:"ledger_path,omitempty"`
+	DescriptorPath    string                 `json:"descriptor_path,omitempty"`
+	BundleFingerprint string                 `json:"bundle_fingerprint,omitempty"`
+	TimeZone          *core.TimeZoneDecision `json:"time_zone,omitempty"` // how timestamps were read
+	StartedAt         time.Time              `json:"started_at"`
+	CompletedAt       time.Time              `json:"completed_at"`
+	Stages            []pipelineStage        `json:"stages"`
+	Variables         []string               `json:"variables"`
+	Dropped           []string               `json:"dropped,omitempty"`
+	Columns           dataset.ColumnNames    `json:"columns,omitempty"` // variables renamed from their headers
+	Relationships     int                    `json:"relationships"`
+	Significant       int                    `json:"significant"`
+	Hypotheses        []pipelineHypothesis   `json:"hypotheses,omitempty"`
+	Usage             *models.RunUsage       `json:"usage,omitempty"`
+	TopRelationships  []pipelineRelationRow  `json:"top_relationships,omitempty"`
+}
+
+// pipelineStage is what one stage of the run did
+type pipelineStage struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Detail     string  `json:"detail,omitempty"`
+	Artifacts  int     `json:"artifacts"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// pipelineHypothesis is a generated hypothesis with its validation verdict
+type pipelineHypothesis struct {
+	ID        string                 `json:"id"`
+	CauseKey  string                 `json:"cause_key"`
+	EffectKey string                 `json:"effect_key"`
+	Claim     string                 `json:"claim"`
+	Passed    bool                   `json:"passed"`
+	Reason    string                 `json:"reason,omitempty"`
+	Referees  []models.RefereeResult `json:"referees,omitempty"`
+}
+
+// pipelineRelationRow is a relationship as the manifest lists it
+type pipelineRelationRow struct {
+	VariableX  string  `json:"variable_x"`
+	VariableY  string  `json:"variable_y"`
+	EffectSize float64 `json:"effect_size"`
+	PValue     float64 `json:"p_value"`
+	QValue     float64 `json:"q_value"`
+	SampleSize int     `json:"sample_size"`
+}
+
+// runPipeline chains readiness, matrix resolution, the sweep, hypothesis generation and
+// validation over one file with one seed, writing every artifact to the chosen ledger and a
+// consolidated manifest next to it. A stage that fails stops the run; the manifest is still
+// written so the failure is on record.
+func runPipeline(ctx context.Context, fs *flag.FlagSet) error {
+	path := fs.Arg(0)
+	if path == "" {
+		return apperrors.InvalidInput("usage: gohypo-cli run [flags] <dataset-file>")
+	}
+	spec, err := loadRunSpec(*runSpecPath)
+	if err != nil {
+		return err
+	}
+	if *runSeed != 0 {
+		spec.Seed = *runSeed
+	}
+	if err := spec.applyDefaultSeed(); err != nil {
+		return err
+	}
+	if spec.Seed == 0 {
+		spec.Seed = time.Now().UnixNano()
+	}
+	switch *runLedger {
+	case ledgerFile, ledgerSQLite, ledgerMemory:
+	default:
+		return apperrors.InvalidInput(fmt.Sprintf("unknown ledger %q (use file, sqlite or memory)", *runLedger))
+	}
+	env, _ := loadDoctorEnv(godotenv.Load())
+
+	out, restore := quietLibraryOutput(*runVerbose)
+	defer restore()
+
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	manifest := &pipelineManifest{
+		RunID:     fmt.Sprintf("%s-%s", time.Now().UTC().Format("20060102T150405Z"), stem),
+		Dataset:   path,
+		Seed:      spec.Seed,
+		Build:     buildinfo.Get(),
+		Ledger:    *runLedger,
+		StartedAt: time.Now(),
+	}
+	runDir := filepath.Join(*runOutDir, manifest.RunID)
+	if err := os.MkdirAll(runDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create run directory: %w", err)
+	}
+	ledger, ledgerPath, closeLedger, err := openRunLedger(ctx, *runLedger, runDir)
+	if err != nil {
+		return err
+	}
+	manifest.LedgerPath = ledgerPath
+
+	// Progress lines would corrupt the JSON output
+	progress := io.Writer(out)
+	if *runJSON {
+		progress = io.Discard
+	}
+	fmt.Fprintf(progress, "Run %s over %s (seed %d)\n\n", manifest.RunID, filepath.Base(path), spec.Seed)
+	opts := pipelineOptions{
+		outDir:         *runOutDir,
+		directives:     *runDirectives,
+		skipHypotheses: *runSkipHypotheses,
+	}
+	runErr := executePipeline(ctx, progress, spec, path, manifest, ledger, env, opts)
+	manifest.Spec = *spec
+	manifest.CompletedAt = time.Now()
+
+	if err := closeLedger(); err != nil && runErr == nil {
+		runErr = fmt.Errorf("failed to write ledger: %w", err)
+	}
+	manifestPath := filepath.Join(runDir, "manifest.json")
+	if err := writeJSONFile(manifestPath, manifest); err != nil && runErr == nil {
+		runErr = err
+	}
+
+	if *runJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(manifest); err != nil {
+			return err
+		}
+	} else {
+		printPipelineManifest(out, manifest)
+		fmt.Fprintf(out, "\nManifest: %s\n", manifestPath)
+	}
+	return runErr
+}
+
+// pipelineOptions are the choices of a pipeline run beyond its spec
+type pipelineOptions struct {
+	outDir         string // where the run descriptor is written; empty writes none
+	directives     int
+	skipHypotheses bool
+	// recorded are the directives of an earlier run, validated in place of generating new
+	// ones so a replay does not depend on the LLM
+	recorded []models.ResearchDirectiveResponse
+}
+
+// executePipeline runs the stages in order, recording each in the manifest
+func executePipeline(ctx context.Context, out io.Writer, spec *runSpec, path string, manifest *pipelineManifest, ledger ports.LedgerWriterPort, env doctorEnv, opts pipelineOptions) error {
+	runID := manifest.RunID
+	store := func(artifacts ...core.Artifact) error {
+		for _, artifact := range artifacts {
+			if err := ledger.StoreArtifact(ctx, runID, artifact); err != nil {
+				return fmt.Errorf("failed to store %s artifact: %w", artifact.Kind, err)
+			}
+		}
+		return nil
+	}
+
+	// Readiness and resolution: profile the file and resolve the usable variables
+	started := time.Now()
+	headers, columns, err := readColumns(path)
+	if err != nil {
+		return manifest.fail("readiness", started, fmt.Errorf("failed to read %s: %w", path, err))
+	}
+	requested := spec.resolveVariables(headers, columns)
+	bundle, err := loadBundle(ctx, path, requested, spec.Locale, spec.TimeZone)
+	if err != nil {
+		return manifest.fail("readiness", started, err)
+	}
+	// The resolved columns are pinned, so the recorded spec replays the same matrix
+	spec.Variables = variableNames(bundle.Matrix.VariableKeys)
+	manifest.TimeZone = &bundle.TimeZone
+	manifest.Variables = spec.Variables
+	manifest.Dropped = droppedVariables(requested, bundle.Matrix.VariableKeys)
+	for _, column := range columns.Renamed() {
+		if _, ok := bundle.GetColumn(core.VariableKey(column.Key)); ok {
+			manifest.Columns = append(manifest.Columns, column)
+		}
+	}
+	inputs, err := runInputs(spec, path, bundle)
+	if err != nil {
+		return manifest.fail("readiness", started, err)
+	}
+	if err := store(inputs); err != nil {
+		return manifest.fail("readiness", started, err)
+	}
+	manifest.stage("readiness", stageOK, started, 1,
+		fmt.Sprintf("%d of %d variables usable", len(bundle.Matrix.VariableKeys), len(requested)))
+	fmt.Fprintf(out, "  readiness   %d of %d variables usable\n", len(bundle.Matrix.VariableKeys), len(requested))
+
+	// Sweep
+	started = time.Now()
+	resp, err := newSweepService().RunStatsSweep(ctx, spec.sweepRequest(bundle))
+	if err != nil {
+		return manifest.fail("sweep", started, err)
+	}
+	if err := store(append(resp.Relationships, resp.Manifest)...); err != nil {
+		return manifest.fail("sweep", started, err)
+	}
+	rows := relationshipRows(resp.Relationships)
+	manifest.BundleFingerprint = manifestString(resp, "bundle_fingerprint")
+	manifest.Relationships = len(rows)
+	for _, row := range rows {
+		if row.QValue < models.SummarySignificanceLevel {
+			manifest.Significant++
+		}
+	}
+	manifest.TopRelationships = topRelationRows(rows, spec.Top)
+	manifest.stage("sweep", stageOK, started, len(resp.Relationships)+1,
+		fmt.Sprintf("%d relationships, %d significant", manifest.Relationships, manifest.Significant))
+	fmt.Fprintf(out, "  sweep       %d relationships, %d significant\n", manifest.Relationships, manifest.Significant)
+	if opts.outDir != "" {
+		if descriptorPath, err := writeRunDescriptor(filepath.Join(opts.outDir, runID), spec, path, bundle.Matrix.VariableKeys, resp, rows); err != nil {
+			fmt.Fprintf(out, "  ! failed to write run descriptor: %v\n", err)
+		} else {
+			manifest.DescriptorPath = descriptorPath
+		}
+	}
+
+	// Hypotheses
+	started = time.Now()
+	var directives []models.ResearchDirectiveResponse
+	switch {
+	case opts.skipHypotheses:
+		manifest.stage("hypotheses", stageSkipped, started, 0, "--skip-hypotheses")
+		manifest.stage("validation", stageSkipped, started, 0, "no hypotheses")
+		return nil
+	case opts.recorded != nil:
+		directives = opts.recorded
+	case env.ai == nil || !env.ai.LLMConfigured():
+		manifest.stage("hypotheses", stageSkipped, started, 0, "no LLM configured (run gohypo-cli doctor)")
+		manifest.stage("validation", stageSkipped, started, 0, "no hypotheses")
+		fmt.Fprintln(out, "  hypotheses  skipped: no LLM configured")
+		return nil
+	default:
+		req := sweepHypothesesRequest(core.RunID(runID), path, bundle, resp)
+		req.Directives = opts.directives
+		req.SelfConsistencySamples = 1
+		generated, err := llm.NewGreenfieldAdapter(env.ai).GenerateResearchDirectives(ctx, req)
+		if err != nil {
+			return manifest.fail("hypotheses", started, err)
+		}
+		directives = pipelineDirectives(generated)
+		manifest.Usage = generated.Usage
+	}
+	for _, directive := range directives {
+		if err := store(core.Artifact{ID: core.ID(directive.ID), Kind: core.ArtifactResearchDirective, Payload: directive, CreatedAt: core.Now()}); err != nil {
+			return manifest.fail("hypotheses", started, err)
+		}
+	}
+	manifest.stage("hypotheses", stageOK, started, len(directives), fmt.Sprintf("%d generated", len(directives)))
+	fmt.Fprintf(out, "  hypotheses  %d generated\n", len(directives))
+
+	// Validation
+	started = time.Now()
+	passed := 0
+	for _, directive := range directives {
+		hypothesis := validateDirective(bundle, directive)
+		if hypothesis.Passed {
+			passed++
+		}
+		manifest.Hypotheses = append(manifest.Hypotheses, hypothesis)
+		if err := store(core.Artifact{ID: core.ID(models.VerdictArtifactID(hypothesis.ID)), Kind: core.ArtifactHypothesis, Payload: hypothesis, CreatedAt: core.Now()}); err != nil {
+			return manifest.fail("validation", started, err)
+		}
+	}
+	manifest.stage("validation", stageOK, started, len(directives), fmt.Sprintf("%d of %d passed", passed, len(directives)))
+	fmt.Fprintf(out, "  validation  %d of %d passed\n", passed, len(directives))
+	return nil
+}
+
+// runInputs records what the run is produced from: the dataset version, the pinned spec
+// with its seed and how each variable was resolved
+func runInputs(spec *runSpec, path string, bundle *dataset.MatrixBundle) (core.Artifact, error) {
+	version, err := reproduce.DescribeDataset(path, "")
+	if err != nil {
+		return core.Artifact{}, err
+	}
+	config, err := json.Marshal(spec)
+	if err != nil {
+		return core.Artifact{}, fmt.Errorf("failed to encode run spec: %w", err)
+	}
+	inputs := reproduce.RunInputs{Dataset: version, Config: config, Seed: spec.Seed}
+	for _, meta := range bundle.ColumnMeta {
+		inputs.Contracts = append(inputs.Contracts, reproduce.VariableContract{
+			VariableKey:     string(meta.VariableKey),
+			StatisticalType: string(meta.StatisticalType),
+			Imputation:      meta.ResolutionAudit.ImputationApplied,
+		})
+	}
+	return core.Artifact{ID: core.ID(core.ArtifactRunInputs), Kind: core.ArtifactRunInputs, Payload: inputs, CreatedAt: core.Now()}, nil
+}
+
+// pipelineDirectives returns the generated directives with their referee selections, which
+// only the raw LLM output carries
+func pipelineDirectives(resp *ports.GreenfieldResearchResponse) []models.ResearchDirectiveResponse {
+	if output, ok := resp.RawLLMResponse.(*models.GreenfieldResearchOutput); ok {
+		return output.ResearchDirectives
+	}
+	directives := make([]models.ResearchDirectiveResponse, len(resp.Directives))
+	for i, directive := range resp.Directives {
+		directives[i] = models.ResearchDirectiveResponse{
+			ID:                 directive.ID.String(),
+			BusinessHypothesis: directive.Claim,
+			CauseKey:           string(directive.CauseKey),
+			EffectKey:          string(directive.EffectKey),
+			Claim:              directive.Claim,
+		}
+	}
+	return directives
+}
+
+// validateDirective runs a directive's selected referees and the counterfactual gate over
+// the resolved matrix, accepting it by the research worker's rule: at least one gate passes
+func validateDirective(bundle *dataset.MatrixBundle, directive models.ResearchDirectiveResponse) pipelineHypothesis {
+	hypothesis := pipelineHypothesis{
+		ID:        directive.ID,
+		CauseKey:  directive.CauseKey,
+		EffectKey: directive.EffectKey,
+		Claim:     directive.BusinessHypothesis,
+	}
+	if hypothesis.Claim == "" {
+		hypothesis.Claim = directive.Claim
+	}
+	if err := directive.RefereeGates.Validate(); err != nil {
+		hypothesis.Reason = fmt.Sprintf("Invalid referee selection: %v", err)
+		return hypothesis
+	}
+	cause, effect := core.VariableKey(directive.CauseKey), core.VariableKey(directive.EffectKey)
+	x, okX := bundle.GetColumnData(cause)
+	y, okY := bundle.GetColumnData(effect)
+	if !okX || !okY {
+		hypothesis.Reason = fmt.Sprintf("Variable data not found: cause=%s, effect=%s", cause, effect)
+		return hypothesis
+	}
+
+	counterfactual := false
+	for _, selection := range directive.RefereeGates.SelectedReferees {
+		referee, err := refereePkg.GetRefereeFactory(selection.Name)
+		if err != nil {
+			hypothesis.Referees = append(hypothesis.Referees, models.RefereeResult{
+				GateName:      selection.Name,
+				PValue:        1.0,
+				FailureReason: fmt.Sprintf("Referee creation failed: %v", err),
+			})
+			continue
+		}
+		if intervention, ok := referee.(*refereePkg.SyntheticIntervention); ok {
+			counterfactual = true
+			hypothesis.Referees = append(hypothesis.Referees, intervention.ExecuteOnBundle(bundle, cause, effect))
+			continue
+		}
+		hypothesis.Referees = append(hypothesis.Referees, referee.Execute(x, y, nil))
+	}
+	if !counterfactual {
+		intervention, _ := refereePkg.GetRefereeFactory("synthetic_intervention")
+		hypothesis.Referees = append(hypothesis.Referees, intervention.(*refereePkg.SyntheticIntervention).ExecuteOnBundle(bundle, cause, effect))
+	}
+	hypothesis.Passed = refereePkg.Decide(hypothesis.Referees).Passed
+	return hypothesis
+}
+
+// stage records a finished stage
+func (m *pipelineManifest) stage(name, status string, started time.Time, artifacts int, detail string) {
+	m.Stages = append(m.Stages, pipelineStage{
+		Name:       name,
+		Status:     status,
+		Detail:     detail,
+		Artifacts:  artifacts,
+		DurationMs: float64(time.Since(started).Microseconds()) / 1000,
+	})
+}
+
+// fail records a failed stage and returns its error
+func (m *pipelineManifest) fail(name string, started time.Time, err error) error {
+	m.stage(name, stageFailed, started, 0, err.Error())
+	return fmt.Errorf("%s failed: %w", name, err)
+}
+
+// topRelationRows lists the first top display rows for the manifest
+func topRelationRows(rows []relationshipRow, top int) []pipelineRelationRow {
+	if top > 0 && len(rows) > top {
+		rows = rows[:top]
+	}
+	listed := make([]pipelineRelationRow, len(rows))
+	for i, row := range rows {
+		listed[i] = pipelineRelationRow(row)
+	}
+	return listed
+}
+
+// printPipelineManifest renders the stages, top relationships and verdicts of a run
+func printPipelineManifest(w io.Writer, m *pipelineManifest) {
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STAGE\tSTATUS\tARTIFACTS\tTIME\tDETAIL")
+	for _, stage := range m.Stages {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.0fms\t%s\n", stage.Name, stage.Status, stage.Artifacts, stage.DurationMs, stage.Detail)
+	}
+	tw.Flush()
+
+	if len(m.Columns) > 0 {
+		fmt.Fprintln(w)
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VARIABLE\tHEADER\tUNIT")
+		for _, column := range m.Columns {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", column.Key, column.Original, column.Unit)
+		}
+		tw.Flush()
+	}
+	if len(m.TopRelationships) > 0 {
+		rows := make([]relationshipRow, len(m.TopRelationships))
+		for i, row := range m.TopRelationships {
+			rows[i] = relationshipRow(row)
+		}
+		fmt.Fprintln(w)
+		printRelationshipTable(w, rows, 0)
+	}
+	if len(m.Hypotheses) > 0 {
+		fmt.Fprintln(w)
+		for i, hypothesis := range m.Hypotheses {
+			verdict := "✗ rejected"
+			if hypothesis.Passed {
+				verdict = "✓ validated"
+			}
+			fmt.Fprintf(w, "%d. %s → %s  %s\n   %s\n", i+1, hypothesis.CauseKey, hypothesis.EffectKey, verdict, hypothesis.Claim)
+			if hypothesis.Reason != "" {
+				fmt.Fprintf(w, "   %s\n", hypothesis.Reason)
+			}
+		}
+	}
+	if m.Usage != nil {
+		fmt.Fprintf(w, "\n%d LLM calls, %d tokens, est. $%.4f\n", m.Usage.Calls, m.Usage.TotalTokens, m.Usage.EstimatedCostUSD)
+	}
+	if m.LedgerPath != "" {
+		fmt.Fprintf(w, "\nLedger: %s\n", m.LedgerPath)
+	}
+}
+
+// fileLedgerName is the file ledger's file within the run directory
+const fileLedgerName = "ledger.jsonl"
+
+// openRunLedger opens the ledger the run writes its artifacts to, with where it is and a
+// func that flushes and closes it
+func openRunLedger(ctx context.Context, backend, runDir string) (ports.LedgerWriterPort, string, func() error, error) {
+	switch backend {
+	case ledgerMemory:
+		return testkit.NewInMemoryLedgerAdapter(), "", func() error { return nil }, nil
+	case ledgerSQLite:
+		// The server keeps its ledger in a SQLite DATABASE_URL, so the run shows up there
+		url := os.Getenv("DATABASE_URL")
+		if !sqlite.IsURL(url) {
+			return nil, "", nil, apperrors.InvalidInput("--ledger sqlite needs DATABASE_URL to name a SQLite database, as in sqlite://./data/gohypo.db")
+		}
+		db, err := sqlite.Open(ctx, url)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if err := migration.NewSQLiteRunner().Up(ctx, db); err != nil {
+			db.Close()
+			return nil, "", nil, fmt.Errorf("failed to migrate ledger database: %w", err)
+		}
+		return sqlite.NewLedger(db), url, db.Close, nil
+	}
+	path := filepath.Join(runDir, fileLedgerName)
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to create ledger: %w", err)
+	}
+	return &fileLedger{enc: json.NewEncoder(file)}, path, file.Close, nil
+}
+
+// fileLedger appends artifacts to a JSONL file, one {run_id, artifact} object per line
+type fileLedger struct {
+	enc *json.Encoder
+}
+
+// StoreArtifact appends an artifact to the file
+func (l *fileLedger) StoreArtifact(ctx context.Context, runID string, artifact core.Artifact) error {
+	return l.enc.Encode(struct {
+		RunID    string        `json:"run_id"`
+		Artifact core.Artifact `json:"artifact"`
+	}{runID, core.StampSchemaVersion(artifact)})
+}
+
+// writeJSONFile writes v as indented JSON
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
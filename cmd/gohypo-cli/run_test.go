@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gohypo/adapters/sqlite"
+	"gohypo/domain/core"
+	"gohypo/internal/config"
+)
+
+// runFixture is the retail golden case: a few numeric columns and one categorical
+const runFixture = "../../internal/golden/testdata/retail/data.csv"
+
+// runCommand runs `gohypo-cli run` with no LLM configured and returns the run's manifest.
+// Every run flag is given, since the flag variables outlive one execution.
+func runCommand(t *testing.T, ledger string) *pipelineManifest {
+	t.Helper()
+	empty := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.FileEnv, empty)
+	t.Setenv("GENERATOR_MODE", "openai")
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv(config.LedgerEnv, "")
+	t.Setenv(config.RunsDirEnv, "")
+
+	out := t.TempDir()
+	root := newRootCommand()
+	root.SetArgs([]string{"run", "--seed", "42", "--ledger", ledger, "--out", out,
+		"--skip-hypotheses=false", "--json=false", "--hypotheses", "3", runFixture})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("run --ledger %s: %v", ledger, err)
+	}
+
+	runs, err := filepath.Glob(filepath.Join(out, "*", "manifest.json"))
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("expected one manifest under %s, got %v (%v)", out, runs, err)
+	}
+	data, err := os.ReadFile(runs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest pipelineManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("manifest.json does not decode: %v", err)
+	}
+	return &manifest
+}
+
+// TestRunPipelineWritesManifest runs the whole pipeline over a small fixture without an LLM
+// and checks the manifest records every stage, the pinned inputs and the file ledger
+func TestRunPipelineWritesManifest(t *testing.T) {
+	manifest := runCommand(t, ledgerFile)
+
+	if manifest.Seed != 42 || manifest.Spec.Seed != 42 {
+		t.Errorf("expected seed 42 recorded, got %d (spec %d)", manifest.Seed, manifest.Spec.Seed)
+	}
+	if manifest.CompletedAt.Before(manifest.StartedAt) {
+		t.Errorf("expected the run to complete after it started")
+	}
+	want := map[string]string{"readiness": stageOK, "sweep": stageOK, "hypotheses": stageSkipped, "validation": stageSkipped}
+	if len(manifest.Stages) != len(want) {
+		t.Fatalf("expected %d stages, got %+v", len(want), manifest.Stages)
+	}
+	for _, stage := range manifest.Stages {
+		if want[stage.Name] != stage.Status {
+			t.Errorf("stage %s: expected %s, got %s (%s)", stage.Name, want[stage.Name], stage.Status, stage.Detail)
+		}
+	}
+	if len(manifest.Variables) < 2 || len(manifest.Spec.Variables) != len(manifest.Variables) {
+		t.Errorf("expected the resolved variables pinned in the spec, got %v and %v", manifest.Variables, manifest.Spec.Variables)
+	}
+	if manifest.Relationships == 0 || manifest.BundleFingerprint == "" {
+		t.Errorf("expected sweep results, got %d relationships, fingerprint %q", manifest.Relationships, manifest.BundleFingerprint)
+	}
+	if manifest.Usage != nil || len(manifest.Hypotheses) != 0 {
+		t.Errorf("expected no LLM use without an LLM configured")
+	}
+
+	// One line per stored artifact: the run inputs, the relationships and the sweep manifest
+	data, err := os.ReadFile(manifest.LedgerPath)
+	if err != nil {
+		t.Fatalf("expected the file ledger at %s: %v", manifest.LedgerPath, err)
+	}
+	lines := 0
+	for _, b := range data {
+		if b == '\n' {
+			lines++
+		}
+	}
+	if artifacts := manifest.Stages[0].Artifacts + manifest.Stages[1].Artifacts; lines != artifacts {
+		t.Errorf("expected %d ledger lines, got %d", artifacts, lines)
+	}
+}
+
+// TestRunPipelineSQLiteLedger verifies the sqlite backend stores the run's artifacts where
+// the server reads them
+func TestRunPipelineSQLiteLedger(t *testing.T) {
+	url := "sqlite://" + filepath.Join(t.TempDir(), "gohypo.db")
+	t.Setenv("DATABASE_URL", url)
+	manifest := runCommand(t, ledgerSQLite)
+	if manifest.LedgerPath != url {
+		t.Errorf("expected the ledger recorded as %s, got %s", url, manifest.LedgerPath)
+	}
+
+	db, err := sqlite.Open(context.Background(), url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	artifacts, err := sqlite.NewLedger(db).GetArtifactsByRun(context.Background(), core.RunID(manifest.RunID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := manifest.Stages[0].Artifacts + manifest.Stages[1].Artifacts; len(artifacts) != want {
+		t.Errorf("expected %d artifacts in the ledger, got %d", want, len(artifacts))
+	}
+	if len(artifacts) == 0 || artifacts[0].Kind != core.ArtifactRunInputs {
+		t.Errorf("expected the run inputs stored first")
+	}
+
+	t.Setenv("DATABASE_URL", "postgres://localhost/gohypo")
+	root := newRootCommand()
+	root.SetArgs([]string{"run", "--ledger", ledgerSQLite, "--out", t.TempDir(), runFixture})
+	if err := root.Execute(); err == nil {
+		t.Errorf("expected --ledger sqlite to refuse a PostgreSQL DATABASE_URL")
+	}
+}
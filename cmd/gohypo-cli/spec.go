@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"gohypo/app"
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/domain/stage"
+	"gohypo/domain/stats"
+	"gohypo/internal/config"
+	apperrors "gohypo/internal/errors"
+)
+
+// runSpec describes a sweep run; it is loaded from YAML so runs can be repeated exactly
+//
+//	variables: [price, quantity, total_amount]  # omit for every column
+//	rigor: standard                             # basic | standard | decision
+//	fdr_method: BY                              # overrides the rigor default
+//	inference: both                             # frequentist | bayesian | both; overrides the rigor default
+//	robust: biweight                            # off | winsorized | biweight | both
+//	mixed_effects: true                         # random-intercept estimate per entity on panel data
+//	imputations: 20                             # pool over imputed matrices instead of dropping gaps
+//	time_series: auto                           # auto (lagged sweep when there is a time index) | off
+//	max_lag: 3
+//	policy: {alpha: 0.01, min_effect: 0.2}     # cutoffs; unset ones default to 0.05 / 0.3 / 0.5 / 0.7
+//	workers: 4
+//	seed: 42
+//	top: 20
+//	locale: de-DE                               # detected when omitted
+//	time_zone: Europe/Berlin                    # zone of timestamps without an offset; UTC when omitted
+type runSpec struct {
+	Variables   []string             `yaml:"variables" json:"variables,omitempty"`
+	Rigor       stage.RigorProfile   `yaml:"rigor" json:"rigor,omitempty"`
+	FDRMethod   string               `yaml:"fdr_method" json:"fdr_method,omitempty"`
+	Inference   string               `yaml:"inference" json:"inference,omitempty"`
+	Robust      string               `yaml:"robust" json:"robust,omitempty"`
+	Mixed       bool                 `yaml:"mixed_effects" json:"mixed_effects,omitempty"`
+	Imputations int                  `yaml:"imputations" json:"imputations,omitempty"`
+	TimeSeries  string               `yaml:"time_series" json:"time_series,omitempty"`
+	MaxLag      int                  `yaml:"max_lag" json:"max_lag,omitempty"`
+	Policy      stats.AnalysisPolicy `yaml:"policy" json:"policy"`
+	Workers     int                  `yaml:"workers" json:"workers,omitempty"`
+	Seed        int64                `yaml:"seed" json:"seed,omitempty"`
+	Top         int                  `yaml:"top" json:"top,omitempty"`
+	Locale      string               `yaml:"locale" json:"locale,omitempty"`       // e.g. de-DE; detected when empty
+	TimeZone    string               `yaml:"time_zone" json:"time_zone,omitempty"` // e.g. Europe/Berlin; UTC when empty
+}
+
+// applyDefaultSeed seeds a spec that sets no seed with the configured default seed, if any
+func (s *runSpec) applyDefaultSeed() error {
+	if s.Seed != 0 {
+		return nil
+	}
+	seed, err := config.Seed()
+	if err != nil {
+		return err
+	}
+	s.Seed = seed
+	return nil
+}
+
+// defaultRunSpec is used when no spec file is given
+func defaultRunSpec() *runSpec {
+	return &runSpec{Rigor: stage.RigorStandard, Top: 20}
+}
+
+// loadRunSpec reads a YAML run spec, filling unset fields from the defaults
+func loadRunSpec(path string) (*runSpec, error) {
+	spec := defaultRunSpec()
+	if path == "" {
+		return spec, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, &apperrors.AppError{Code: apperrors.CodeInvalidInput, Message: fmt.Sprintf("failed to parse spec %s", path), Cause: err}
+	}
+
+	switch spec.Rigor {
+	case "":
+		spec.Rigor = stage.RigorStandard
+	case stage.RigorBasic, stage.RigorStandard, stage.RigorDecision:
+	default:
+		return nil, apperrors.InvalidInput(fmt.Sprintf("spec %s: unknown rigor %q (use basic, standard or decision)", path, spec.Rigor))
+	}
+	if spec.FDRMethod != "" {
+		if _, err := stats.ParseFDRMethod(spec.FDRMethod); err != nil {
+			return nil, fmt.Errorf("spec %s: %w", path, err)
+		}
+	}
+	if spec.Inference != "" {
+		if _, err := stats.ParseInferenceMode(spec.Inference); err != nil {
+			return nil, fmt.Errorf("spec %s: %w", path, err)
+		}
+	}
+	if spec.Robust != "" {
+		if _, err := stats.ParseRobustMethod(spec.Robust); err != nil {
+			return nil, fmt.Errorf("spec %s: %w", path, err)
+		}
+	}
+	if err := spec.Policy.Validate(); err != nil {
+		return nil, apperrors.InvalidInput(fmt.Sprintf("spec %s: policy: %v", path, err))
+	}
+	if _, err := dataset.ResolveLocale(spec.Locale, nil); err != nil {
+		return nil, apperrors.InvalidInput(fmt.Sprintf("spec %s: %v", path, err))
+	}
+	if _, err := core.ResolveTimeZone(spec.TimeZone, core.TimeZoneFromSpec); err != nil {
+		return nil, apperrors.InvalidInput(fmt.Sprintf("spec %s: %v", path, err))
+	}
+	switch spec.TimeSeries {
+	case "", "auto", "off":
+	default:
+		return nil, apperrors.InvalidInput(fmt.Sprintf("spec %s: unknown time_series %q (use auto or off)", path, spec.TimeSeries))
+	}
+	return spec, nil
+}
+
+// variableKeys returns the spec's variables, or every header when none are listed
+func (s *runSpec) variableKeys(headers []string) []core.VariableKey {
+	names := s.Variables
+	if len(names) == 0 {
+		names = headers
+	}
+	keys := make([]core.VariableKey, len(names))
+	for i, name := range names {
+		keys[i] = core.VariableKey(name)
+	}
+	return keys
+}
+
+// resolveVariables returns the spec's variables as column keys, so variables named by their
+// original header resolve to the normalized column
+func (s *runSpec) resolveVariables(headers []string, columns dataset.ColumnNames) []core.VariableKey {
+	keys := s.variableKeys(headers)
+	for i, key := range keys {
+		if resolved, ok := columns.Resolve(string(key)); ok {
+			keys[i] = core.VariableKey(resolved)
+		}
+	}
+	return keys
+}
+
+// sweepRequest builds the stats sweep request described by the spec
+func (s *runSpec) sweepRequest(bundle *dataset.MatrixBundle) app.StatsSweepRequest {
+	timeSeries := app.TimeSeriesAuto
+	if s.TimeSeries == "off" {
+		timeSeries = app.TimeSeriesOff
+	}
+	return app.StatsSweepRequest{
+		MatrixBundle: bundle,
+		Rigor:        s.Rigor,
+		FDRMethod:    stats.FDRMethod(s.FDRMethod),
+		Inference:    stats.InferenceMode(s.Inference),
+		Robust:       stats.RobustMethod(s.Robust),
+		MixedEffects: s.Mixed,
+		Imputations:  s.Imputations,
+		TimeSeries:   timeSeries,
+		MaxLag:       s.MaxLag,
+		Policy:       s.Policy,
+		NumWorkers:   s.Workers,
+		Seed:         s.Seed,
+	}
+}
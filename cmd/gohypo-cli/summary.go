@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"gohypo/adapters/postgres"
+	"gohypo/models"
+)
+
+var (
+	summaryRun       *string
+	summarySession   *string
+	summaryWorkspace *string
+	summaryLimit     *int
+	summaryJSON      *bool
+)
+
+func init() {
+	fs := flag.NewFlagSet("summary", flag.ExitOnError)
+	summaryRun = fs.String("run", "", "show the summary of a sweep run")
+	summarySession = fs.String("session", "", "show the summary of a research session's latest sweep")
+	summaryWorkspace = fs.String("workspace", "", "list a workspace's most recent run summaries")
+	summaryLimit = fs.Int("limit", 20, "number of run summaries to list")
+	summaryJSON = fs.Bool("json", false, "print the summary as JSON")
+
+	register(&command{
+		Name:    "summary",
+		Summary: "Show the relationship summaries materialized after each sweep run",
+		Flags:   fs,
+		Run:     runSummary,
+	})
+}
+
+func runSummary(ctx context.Context, fs *flag.FlagSet) error {
+	if *summaryRun == "" && *summarySession == "" && *summaryWorkspace == "" {
+		return fmt.Errorf("one of --run, --session or --workspace is required")
+	}
+	db, err := connectDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	store := postgres.NewRunSummaryRepository(db)
+
+	if *summaryWorkspace != "" {
+		summaries, err := store.ListWorkspaceSummaries(ctx, *summaryWorkspace, max(*summaryLimit, 1))
+		if err != nil {
+			return err
+		}
+		if *summaryJSON {
+			return printJSON(summaries)
+		}
+		if len(summaries) == 0 {
+			fmt.Println("No run summaries recorded for this workspace.")
+			return nil
+		}
+		printRunSummaryList(os.Stdout, summaries)
+		return nil
+	}
+
+	var summary *models.RunSummary
+	if *summaryRun != "" {
+		summary, err = store.GetRunSummary(ctx, *summaryRun)
+	} else {
+		summary, err = store.LatestSessionSummary(ctx, *summarySession)
+	}
+	if err != nil {
+		return err
+	}
+	if summary == nil {
+		return fmt.Errorf("no summary recorded for this run")
+	}
+	if *summaryJSON {
+		return printJSON(summary)
+	}
+	printRunSummary(os.Stdout, summary)
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printRunSummaryList(w io.Writer, summaries []*models.RunSummary) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUN\tCREATED\tVARIABLES\tRELATIONSHIPS\tSIGNIFICANT\tFDR")
+	for _, summary := range summaries {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%s\n",
+			summary.RunID, summary.CreatedAt.Local().Format("2006-01-02 15:04"), summary.VariablesAnalyzed,
+			summary.Relationships, summary.SignificantPairs, summary.FDRMethod)
+	}
+	tw.Flush()
+}
+
+func printRunSummary(w io.Writer, summary *models.RunSummary) {
+	fmt.Fprintf(w, "Run %s (%s)\n", summary.RunID, summary.CreatedAt.Local().Format("2006-01-02 15:04"))
+	fmt.Fprintf(w, "%d relationships among %d variables, %d significant at q < %.2f (%s)\n\n",
+		summary.Relationships, summary.VariablesAnalyzed, summary.SignificantPairs, models.SummarySignificanceLevel, summary.FDRMethod)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TEST TYPE\tRELATIONSHIPS\tSIGNIFICANT")
+	for _, count := range summary.TestTypes {
+		fmt.Fprintf(tw, "%s\t%d\t%d\n", count.TestType, count.Relationships, count.Significant)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+
+	rows := make([]relationshipRow, len(summary.TopEffects))
+	for i, effect := range summary.TopEffects {
+		rows[i] = relationshipRow{
+			VariableX:  effect.VariableX,
+			VariableY:  effect.VariableY,
+			EffectSize: effect.EffectSize,
+			PValue:     effect.PValue,
+			QValue:     effect.QValue,
+			SampleSize: effect.SampleSize,
+		}
+	}
+	printRelationshipTable(w, rows, 0)
+}
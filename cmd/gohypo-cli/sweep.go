@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gohypo/adapters/excel"
+	"gohypo/app"
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/ports"
+)
+
+// supportedDataExtensions are the file types the Excel/CSV/JSON reader can load
+var supportedDataExtensions = []string{".csv", ".xlsx", ".xls", ".json", ".jsonl", ".ndjson"}
+
+// findDataFiles lists loadable dataset files in dir, sorted by name
+func findDataFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		for _, supported := range supportedDataExtensions {
+			if ext == supported {
+				files = append(files, filepath.Join(dir, entry.Name()))
+				break
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readHeaders returns the column names of a dataset file
+func readHeaders(path string) ([]string, error) {
+	headers, _, err := readColumns(path)
+	return headers, err
+}
+
+// readColumns returns the column names of a dataset file with the headers they were
+// normalized from, which JSON files have none of
+func readColumns(path string) ([]string, dataset.ColumnNames, error) {
+	data, err := excel.NewDataReader(path).ReadData()
+	if err != nil {
+		return nil, nil, err
+	}
+	return data.Headers, data.Columns, nil
+}
+
+// loadBundle resolves a matrix bundle from a local dataset file, reading its values in the
+// named locale, or the one detected from the file when locale is empty
+func loadBundle(ctx context.Context, path string, varKeys []core.VariableKey, locale, timeZone string) (*dataset.MatrixBundle, error) {
+	config := excel.DefaultExcelConfig()
+	config.FilePath = path
+	config.Enabled = true
+	if locale != "" {
+		config.CoercionConfig.Locale = locale
+	}
+	config.CoercionConfig.TimeZone = timeZone
+
+	resolver := excel.NewExcelMatrixResolverAdapter(config)
+	bundle, err := resolver.ResolveMatrix(ctx, ports.MatrixResolutionRequest{
+		ViewID:     core.ID("cli"),
+		SnapshotID: core.SnapshotID(filepath.Base(path)),
+		VarKeys:    varKeys,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	return bundle, nil
+}
+
+// newSweepService builds a stats sweep service that needs no database
+func newSweepService() *app.StatsSweepService {
+	return app.NewStatsSweepService(app.NewStageRunner(nil, nil), nil, nil)
+}
+
+// quietLibraryOutput silences the service layer's debug printing while the CLI owns
+// the terminal; it returns the real stdout for CLI output and a restore func
+func quietLibraryOutput(verbose bool) (*os.File, func()) {
+	stdout := os.Stdout
+	if verbose {
+		return stdout, func() {}
+	}
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return stdout, func() {}
+	}
+	logWriter := log.Writer()
+	os.Stdout = devNull
+	log.SetOutput(io.Discard)
+
+	return stdout, func() {
+		os.Stdout = stdout
+		log.SetOutput(logWriter)
+		devNull.Close()
+	}
+}
+
+// relationshipRow is a flattened relationship artifact for display
+type relationshipRow struct {
+	VariableX  string
+	VariableY  string
+	EffectSize float64
+	PValue     float64
+	QValue     float64
+	SampleSize int
+}
+
+// relationshipRows extracts display rows from sweep artifacts, strongest evidence first
+func relationshipRows(artifacts []core.Artifact) []relationshipRow {
+	rows := make([]relationshipRow, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		payload, ok := artifact.Payload.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		row := relationshipRow{}
+		row.VariableX, _ = payload["cause_key"].(string)
+		row.VariableY, _ = payload["effect_key"].(string)
+		row.EffectSize, _ = payload["correlation"].(float64)
+		row.PValue, _ = payload["p_value"].(float64)
+		row.QValue, _ = payload["q_value"].(float64)
+		row.SampleSize, _ = payload["sample_size"].(int)
+		rows = append(rows, row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].QValue != rows[j].QValue {
+			return rows[i].QValue < rows[j].QValue
+		}
+		return math.Abs(rows[i].EffectSize) > math.Abs(rows[j].EffectSize)
+	})
+	return rows
+}
+
+// printRelationshipTable renders the top relationships as an aligned table
+func printRelationshipTable(w io.Writer, rows []relationshipRow, top int) {
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "No relationships found.")
+		return
+	}
+	if top > 0 && len(rows) > top {
+		rows = rows[:top]
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tVARIABLE X\tVARIABLE Y\tr\tp\tq\tn")
+	for i, row := range rows {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%+.3f\t%.2g\t%.2g\t%d\n",
+			i+1, row.VariableX, row.VariableY, row.EffectSize, row.PValue, row.QValue, row.SampleSize)
+	}
+	tw.Flush()
+}
+
+// progressBar renders a single-line progress bar, redrawn in place
+func progressBar(w io.Writer, label string, completed, total int) {
+	const width = 30
+	if total <= 0 {
+		return
+	}
+	filled := completed * width / total
+	fmt.Fprintf(w, "\r%s [%s%s] %d/%d", label, strings.Repeat("█", filled), strings.Repeat("░", width-filled), completed, total)
+	if completed == total {
+		fmt.Fprintln(w)
+	}
+}
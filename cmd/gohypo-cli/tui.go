@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gohypo/domain/core"
+	"gohypo/domain/stage"
+	apperrors "gohypo/internal/errors"
+)
+
+var (
+	tuiDataDir *string
+	tuiTop     *int
+	tuiWorkers *int
+	tuiVerbose *bool
+	tuiRunsDir *string
+)
+
+func init() {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	tuiDataDir = fs.String("data", "./data", "directory containing CSV/Excel datasets")
+	tuiTop = fs.Int("top", 20, "number of relationships to show")
+	tuiWorkers = fs.Int("workers", 0, "pairwise worker pool size (0 = NumCPU)")
+	tuiVerbose = fs.Bool("verbose", false, "show service debug output")
+	tuiRunsDir = fs.String("runs", "./runs", "directory for run reproduction descriptors (empty to skip)")
+
+	register(&command{
+		Name:    "tui",
+		Summary: "Interactive sweep: pick a dataset, variables and rigor, then view results",
+		Flags:   fs,
+		Run:     runTUI,
+	})
+}
+
+// prompter reads interactive answers from stdin
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (p *prompter) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+// choose shows a numbered menu and returns the selected index
+func (p *prompter) choose(title string, options []string, def int) (int, error) {
+	fmt.Fprintf(p.out, "\n%s\n", title)
+	for i, option := range options {
+		fmt.Fprintf(p.out, "  %2d) %s\n", i+1, option)
+	}
+	for {
+		answer, err := p.ask("Select", strconv.Itoa(def+1))
+		if err != nil {
+			return 0, err
+		}
+		n, convErr := strconv.Atoi(answer)
+		if convErr == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+		fmt.Fprintf(p.out, "  please enter a number between 1 and %d\n", len(options))
+	}
+}
+
+// parseSelection parses "all" or a list like "1,3,5-8" into zero-based indexes
+func parseSelection(answer string, count int) ([]int, error) {
+	if strings.EqualFold(strings.TrimSpace(answer), "all") {
+		indexes := make([]int, count)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes, nil
+	}
+
+	seen := make(map[int]bool)
+	var indexes []int
+	for _, part := range strings.Split(answer, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi := part, part
+		if before, after, ok := strings.Cut(part, "-"); ok {
+			lo, hi = before, after
+		}
+		start, err1 := strconv.Atoi(strings.TrimSpace(lo))
+		end, err2 := strconv.Atoi(strings.TrimSpace(hi))
+		if err1 != nil || err2 != nil || start < 1 || end > count || start > end {
+			return nil, fmt.Errorf("invalid selection %q", part)
+		}
+		for i := start; i <= end; i++ {
+			if !seen[i-1] {
+				seen[i-1] = true
+				indexes = append(indexes, i-1)
+			}
+		}
+	}
+	if len(indexes) < 2 {
+		return nil, fmt.Errorf("select at least two variables")
+	}
+	return indexes, nil
+}
+
+// runTUI walks the user through dataset, variable and rigor selection and runs a sweep
+func runTUI(ctx context.Context, fs *flag.FlagSet) error {
+	out, restore := quietLibraryOutput(*tuiVerbose)
+	defer restore()
+
+	p := &prompter{in: bufio.NewReader(os.Stdin), out: out}
+	fmt.Fprintln(p.out, "GoHypo interactive sweep")
+
+	// 1. Dataset
+	files, err := findDataFiles(*tuiDataDir)
+	if err != nil {
+		return fmt.Errorf("failed to list datasets in %s: %w", *tuiDataDir, err)
+	}
+	if len(files) == 0 {
+		return apperrors.New(apperrors.CodeNotFound, fmt.Sprintf("no CSV or Excel files found in %s", *tuiDataDir))
+	}
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = filepath.Base(file)
+	}
+	fileIdx, err := p.choose("Datasets", names, 0)
+	if err != nil {
+		return err
+	}
+	path := files[fileIdx]
+
+	// 2. Variables
+	headers, err := readHeaders(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	fmt.Fprintf(p.out, "\nVariables in %s\n", names[fileIdx])
+	for i, header := range headers {
+		fmt.Fprintf(p.out, "  %2d) %s\n", i+1, header)
+	}
+	var selected []int
+	for {
+		answer, err := p.ask("Variables (e.g. all, 1,3,5-8)", "all")
+		if err != nil {
+			return err
+		}
+		if selected, err = parseSelection(answer, len(headers)); err == nil {
+			break
+		}
+		fmt.Fprintf(p.out, "  %v\n", err)
+	}
+	varKeys := make([]core.VariableKey, len(selected))
+	for i, idx := range selected {
+		varKeys[i] = core.VariableKey(headers[idx])
+	}
+
+	// 3. Rigor
+	rigors := []stage.RigorProfile{stage.RigorBasic, stage.RigorStandard, stage.RigorDecision}
+	rigorNames := make([]string, len(rigors))
+	for i, rigor := range rigors {
+		rigorNames[i] = fmt.Sprintf("%s (FDR: %s)", rigor, rigor.FDRMethod())
+	}
+	rigorIdx, err := p.choose("Rigor profile", rigorNames, 1)
+	if err != nil {
+		return err
+	}
+
+	// 4. Sweep with live progress
+	fmt.Fprintf(out, "\nResolving %d variables from %s...\n", len(varKeys), names[fileIdx])
+	bundle, err := loadBundle(ctx, path, varKeys, "", "")
+	if err != nil {
+		return err
+	}
+
+	spec := &runSpec{Rigor: rigors[rigorIdx], Workers: *tuiWorkers, Top: *tuiTop}
+	if err := spec.applyDefaultSeed(); err != nil {
+		return err
+	}
+	req := spec.sweepRequest(bundle)
+	req.OnProgress = func(completed, total int) {
+		progressBar(out, "Sweeping pairs", completed, total)
+	}
+	resp, err := newSweepService().RunStatsSweep(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	// 5. Results
+	rows := relationshipRows(resp.Relationships)
+	fmt.Fprintf(out, "\nTop relationships (%s rigor)\n\n", rigors[rigorIdx])
+	printRelationshipTable(out, rows, *tuiTop)
+	if *tuiRunsDir != "" {
+		descriptorPath, err := writeRunDescriptor(*tuiRunsDir, spec, path, varKeys, resp, rows)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\nReproduce with: gohypo-cli reproduce %s\n", descriptorPath)
+	}
+	return nil
+}
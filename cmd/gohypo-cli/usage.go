@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"gohypo/adapters/sqlite"
+	"gohypo/models"
+)
+
+var (
+	usageSession *string
+	usageLimit   *int
+	usageJSON    *bool
+)
+
+func init() {
+	fs := flag.NewFlagSet("usage", flag.ExitOnError)
+	usageSession = fs.String("session", "", "show a single research session")
+	usageLimit = fs.Int("limit", 20, "number of most recent sessions to list")
+	usageJSON = fs.Bool("json", false, "print usage as JSON")
+
+	register(&command{
+		Name:    "usage",
+		Summary: "Summarize LLM tokens and estimated cost per hypothesis generation run",
+		Flags:   fs,
+		Run:     runUsage,
+	})
+}
+
+// sessionUsage is one research session's accumulated LLM usage
+type sessionUsage struct {
+	SessionID string          `json:"session_id"`
+	State     string          `json:"state"`
+	StartedAt time.Time       `json:"started_at"`
+	Usage     models.RunUsage `json:"usage"`
+}
+
+func runUsage(ctx context.Context, fs *flag.FlagSet) error {
+	db, err := connectDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rows, err := loadSessionUsage(ctx, db, *usageSession, *usageLimit)
+	if err != nil {
+		return err
+	}
+	if *usageJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	if len(rows) == 0 {
+		fmt.Println("No recorded LLM usage yet.")
+		return nil
+	}
+	printUsageTable(os.Stdout, rows)
+	return nil
+}
+
+// connectDatabase connects to the database named by DATABASE_URL, read from .env if present
+func connectDatabase(ctx context.Context) (*sqlx.DB, error) {
+	godotenv.Load()
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		return nil, fmt.Errorf("DATABASE_URL is not set (run 'gohypo-cli doctor')")
+	}
+	if sqlite.IsURL(url) {
+		return sqlite.Open(ctx, url)
+	}
+	db, err := sqlx.ConnectContext(ctx, "postgres", url)
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	return db, nil
+}
+
+func loadSessionUsage(ctx context.Context, db *sqlx.DB, sessionID string, limit int) ([]sessionUsage, error) {
+	query := `
+		SELECT id::text, state, started_at, metadata->'llm_usage' AS usage
+		FROM research_sessions
+		WHERE metadata->'llm_usage' IS NOT NULL`
+	args := []interface{}{}
+	if sessionID != "" {
+		query += ` AND id::text = $1`
+		args = append(args, sessionID)
+	}
+	query += fmt.Sprintf(` ORDER BY started_at DESC LIMIT %d`, max(limit, 1))
+
+	var records []struct {
+		ID        string    `db:"id"`
+		State     string    `db:"state"`
+		StartedAt time.Time `db:"started_at"`
+		Usage     []byte    `db:"usage"`
+	}
+	if err := db.SelectContext(ctx, &records, query, args...); err != nil {
+		return nil, fmt.Errorf("query session usage: %w", err)
+	}
+
+	rows := make([]sessionUsage, 0, len(records))
+	for _, record := range records {
+		row := sessionUsage{SessionID: record.ID, State: record.State, StartedAt: record.StartedAt}
+		if err := json.Unmarshal(record.Usage, &row.Usage); err != nil {
+			return nil, fmt.Errorf("decode usage for session %s: %w", record.ID, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func printUsageTable(w io.Writer, rows []sessionUsage) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "SESSION\tSTARTED\tCALLS\tCACHED\tPROMPT\tCOMPLETION\tEST. COST\t")
+
+	var total models.RunUsage
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t$%.4f\t\n",
+			row.SessionID, row.StartedAt.Local().Format("2006-01-02 15:04"), row.Usage.Calls, row.Usage.CachedCalls,
+			row.Usage.PromptTokens, row.Usage.CompletionTokens, row.Usage.EstimatedCostUSD)
+		total.Add(&row.Usage)
+	}
+	fmt.Fprintf(tw, "TOTAL\t\t%d\t%d\t%d\t%d\t$%.4f\t\n",
+		total.Calls, total.CachedCalls, total.PromptTokens, total.CompletionTokens, total.EstimatedCostUSD)
+	tw.Flush()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"gohypo/internal/buildinfo"
+)
+
+var versionJSON *bool
+
+func init() {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	versionJSON = fs.Bool("json", false, "print build info as JSON")
+
+	register(&command{
+		Name:    "version",
+		Summary: "Print version, commit and statistical method versions",
+		Flags:   fs,
+		Run:     runVersion,
+	})
+}
+
+func runVersion(ctx context.Context, fs *flag.FlagSet) error {
+	info := buildinfo.Get()
+	if *versionJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+
+	commit := info.Commit
+	if commit == "" {
+		commit = "unknown"
+	} else if info.Modified {
+		commit += " (modified)"
+	}
+	fmt.Printf("gohypo-cli %s\n", info.Version)
+	fmt.Printf("  commit:  %s\n", commit)
+	if info.BuildDate != "" {
+		fmt.Printf("  built:   %s\n", info.BuildDate)
+	}
+	fmt.Printf("  go:      %s\n", info.GoVersion)
+	fmt.Printf("  methods:\n")
+
+	names := make([]string, 0, len(info.MethodVersions))
+	for name := range info.MethodVersions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("    %-22s %s\n", name, info.MethodVersions[name])
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"gohypo/app"
+	"gohypo/domain/core"
+	"gohypo/domain/stats"
+)
+
+var (
+	watchInDir    *string
+	watchSpec     *string
+	watchInterval *time.Duration
+	watchVerbose  *bool
+	watchRunsDir  *string
+)
+
+func init() {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	watchInDir = fs.String("in", "./data", "directory of CSV/Excel files to watch")
+	watchSpec = fs.String("spec", "", "YAML run spec (variables, rigor, fdr_method, inference, workers, seed, top)")
+	watchInterval = fs.Duration("interval", 2*time.Second, "how often to poll for file changes")
+	watchVerbose = fs.Bool("verbose", false, "show service debug output")
+	watchRunsDir = fs.String("runs", "./runs", "directory for run reproduction descriptors (empty to skip)")
+
+	register(&command{
+		Name:    "watch",
+		Summary: "Re-run readiness and an incremental sweep whenever input files change",
+		Flags:   fs,
+		Run:     runWatch,
+	})
+}
+
+// effectChangeThreshold is the minimum |Δr| reported as a changed relationship
+const effectChangeThreshold = 0.01
+
+// fileStamp identifies one version of a file on disk
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// watchedFile holds the last sweep of a file, used as the incremental baseline and diff base
+type watchedFile struct {
+	stamp    fileStamp
+	pending  *fileStamp // last seen stamp of an in-progress change, processed once stable
+	baseline *app.SweepBaseline
+	rows     []relationshipRow
+}
+
+// runWatch polls the input directory and re-sweeps files after they change
+func runWatch(ctx context.Context, fs *flag.FlagSet) error {
+	spec, err := loadRunSpec(*watchSpec)
+	if err != nil {
+		return err
+	}
+	if err := spec.applyDefaultSeed(); err != nil {
+		return err
+	}
+	out, restore := quietLibraryOutput(*watchVerbose)
+	defer restore()
+
+	fmt.Fprintf(out, "Watching %s every %s (Ctrl+C to stop)\n", *watchInDir, *watchInterval)
+
+	files := make(map[string]*watchedFile)
+	ticker := time.NewTicker(*watchInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := pollWatchedFiles(ctx, out, spec, files); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(out, "\nStopped watching.")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollWatchedFiles rescans the directory, re-running files whose change has settled.
+// A change is processed only when two consecutive polls see the same size and mtime,
+// so files still being written are not read half-way.
+func pollWatchedFiles(ctx context.Context, out io.Writer, spec *runSpec, files map[string]*watchedFile) error {
+	paths, err := findDataFiles(*watchInDir)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", *watchInDir, err)
+	}
+
+	seen := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		seen[path] = true
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		stamp := fileStamp{modTime: info.ModTime(), size: info.Size()}
+
+		state, known := files[path]
+		if !known {
+			state = &watchedFile{}
+			files[path] = state
+		} else if state.stamp == stamp {
+			state.pending = nil
+			continue
+		}
+
+		// First sighting of this version: wait one interval for writes to settle
+		if known && (state.pending == nil || *state.pending != stamp) {
+			state.pending = &stamp
+			continue
+		}
+
+		state.stamp = stamp
+		state.pending = nil
+		if ctx.Err() != nil {
+			return nil
+		}
+		rerunWatchedFile(ctx, out, spec, path, state)
+	}
+
+	for path := range files {
+		if !seen[path] {
+			fmt.Fprintf(out, "\n[%s] %s removed\n", time.Now().Format("15:04:05"), filepath.Base(path))
+			delete(files, path)
+		}
+	}
+	return nil
+}
+
+// rerunWatchedFile runs readiness and an incremental sweep for one file and prints the diff.
+// Errors are reported and watching continues, since the next save may fix them.
+func rerunWatchedFile(ctx context.Context, out io.Writer, spec *runSpec, path string, state *watchedFile) {
+	name := filepath.Base(path)
+	fmt.Fprintf(out, "\n[%s] %s changed, re-running\n", time.Now().Format("15:04:05"), name)
+
+	headers, err := readHeaders(path)
+	if err != nil {
+		fmt.Fprintf(out, "  ✗ failed to read: %v\n", err)
+		return
+	}
+	requested := spec.variableKeys(headers)
+
+	bundle, err := loadBundle(ctx, path, requested, spec.Locale, spec.TimeZone)
+	if err != nil {
+		fmt.Fprintf(out, "  ✗ readiness failed: %v\n", err)
+		return
+	}
+	dropped := droppedVariables(requested, bundle.Matrix.VariableKeys)
+	fmt.Fprintf(out, "  readiness: %d of %d variables usable", len(bundle.Matrix.VariableKeys), len(requested))
+	if len(dropped) > 0 {
+		fmt.Fprintf(out, " (dropped: %v)", dropped)
+	}
+	fmt.Fprintln(out)
+
+	req := spec.sweepRequest(bundle)
+	req.Baseline = state.baseline
+	resp, err := newSweepService().RunStatsSweep(ctx, req)
+	if err != nil {
+		fmt.Fprintf(out, "  ✗ sweep failed: %v\n", err)
+		return
+	}
+	if manifest, ok := resp.Manifest.Payload.(map[string]interface{}); ok {
+		fmt.Fprintf(out, "  sweep: %v mode, %v pairs computed, %v reused\n",
+			manifest["mode"], manifest["pairs_computed"], manifest["pairs_reused"])
+	}
+
+	rows := relationshipRows(resp.Relationships)
+	if state.baseline == nil {
+		fmt.Fprintln(out)
+		printRelationshipTable(out, rows, spec.Top)
+	} else {
+		printRelationshipDiff(out, diffRelationships(state.rows, rows, spec.Policy))
+	}
+	if *watchRunsDir != "" {
+		if descriptorPath, err := writeRunDescriptor(*watchRunsDir, spec, path, requested, resp, rows); err != nil {
+			fmt.Fprintf(out, "  ✗ failed to write run descriptor: %v\n", err)
+		} else {
+			fmt.Fprintf(out, "  descriptor: %s\n", descriptorPath)
+		}
+	}
+
+	state.baseline = &app.SweepBaseline{Manifest: resp.Manifest, Relationships: resp.Relationships}
+	state.rows = rows
+}
+
+// droppedVariables lists requested variables that did not make it into the matrix
+func droppedVariables(requested, resolved []core.VariableKey) []string {
+	have := make(map[core.VariableKey]bool, len(resolved))
+	for _, key := range resolved {
+		have[key] = true
+	}
+	var dropped []string
+	for _, key := range requested {
+		if !have[key] {
+			dropped = append(dropped, string(key))
+		}
+	}
+	return dropped
+}
+
+// relationshipChange pairs the previous and current version of a relationship
+type relationshipChange struct {
+	Before relationshipRow
+	After  relationshipRow
+}
+
+// relationshipDiff is the difference between two sweeps of the same file
+type relationshipDiff struct {
+	Added   []relationshipRow
+	Changed []relationshipChange
+	Removed []relationshipRow
+}
+
+func (d relationshipDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// rowKey identifies a relationship regardless of column order, which can differ between runs
+func rowKey(row relationshipRow) string {
+	x, y := row.VariableX, row.VariableY
+	if y < x {
+		x, y = y, x
+	}
+	return x + "|" + y
+}
+
+// diffRelationships reports new, changed and removed relationships. A relationship is
+// changed when its effect moves by at least effectChangeThreshold or its q-value crosses the
+// policy's significance level.
+func diffRelationships(before, after []relationshipRow, policy stats.AnalysisPolicy) relationshipDiff {
+	policy = policy.WithDefaults()
+	previous := make(map[string]relationshipRow, len(before))
+	for _, row := range before {
+		previous[rowKey(row)] = row
+	}
+
+	var diff relationshipDiff
+	for _, row := range after {
+		old, ok := previous[rowKey(row)]
+		if !ok {
+			diff.Added = append(diff.Added, row)
+			continue
+		}
+		delete(previous, rowKey(row))
+
+		wasSignificant := policy.Significant(old.QValue)
+		isSignificant := policy.Significant(row.QValue)
+		if math.Abs(row.EffectSize-old.EffectSize) >= effectChangeThreshold || wasSignificant != isSignificant {
+			diff.Changed = append(diff.Changed, relationshipChange{Before: old, After: row})
+		}
+	}
+	for _, row := range previous {
+		diff.Removed = append(diff.Removed, row)
+	}
+	sort.Slice(diff.Removed, func(i, j int) bool { return rowKey(diff.Removed[i]) < rowKey(diff.Removed[j]) })
+	return diff
+}
+
+// printRelationshipDiff renders a diff as +/~/- lines
+func printRelationshipDiff(w io.Writer, diff relationshipDiff) {
+	if diff.empty() {
+		fmt.Fprintln(w, "  no relationship changes")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range diff.Added {
+		fmt.Fprintf(tw, "  +\t%s ~ %s\tr=%+.3f\tq=%.2g\n", row.VariableX, row.VariableY, row.EffectSize, row.QValue)
+	}
+	for _, change := range diff.Changed {
+		fmt.Fprintf(tw, "  ~\t%s ~ %s\tr=%+.3f → %+.3f\tq=%.2g → %.2g\n",
+			change.After.VariableX, change.After.VariableY,
+			change.Before.EffectSize, change.After.EffectSize, change.Before.QValue, change.After.QValue)
+	}
+	for _, row := range diff.Removed {
+		fmt.Fprintf(tw, "  -\t%s ~ %s\tr=%+.3f\tq=%.2g\n", row.VariableX, row.VariableY, row.EffectSize, row.QValue)
+	}
+	tw.Flush()
+}
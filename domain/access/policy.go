@@ -0,0 +1,104 @@
+package access
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"gohypo/domain/core"
+)
+
+// Principal identifies who is asking for data and which roles they hold
+type Principal struct {
+	UserID core.ID  `json:"user_id"`
+	Roles  []string `json:"roles"`
+}
+
+// HasRole reports whether the principal holds the given role
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// ColumnRule restricts every variable whose key matches Pattern
+// Pattern uses shell glob syntax and is matched case-insensitively (e.g. "*salary*", "ssn")
+type ColumnRule struct {
+	Name           string   `json:"name"`
+	Pattern        string   `json:"pattern"`
+	Classification string   `json:"classification,omitempty"` // e.g. "pii", "compensation"
+	AllowedRoles   []string `json:"allowed_roles"`            // empty = nobody may access
+}
+
+// Matches reports whether the rule applies to a variable key
+func (r ColumnRule) Matches(varKey core.VariableKey) bool {
+	matched, err := path.Match(strings.ToLower(r.Pattern), strings.ToLower(string(varKey)))
+	return err == nil && matched
+}
+
+// ColumnPolicy is an ordered list of column rules; the first matching rule wins
+// Variables matched by no rule are unrestricted
+type ColumnPolicy struct {
+	Rules []ColumnRule `json:"rules"`
+}
+
+// Decision records the outcome of evaluating a column policy for one variable
+type Decision struct {
+	UserID      core.ID          `json:"user_id"`
+	VariableKey core.VariableKey `json:"variable_key"`
+	Allowed     bool             `json:"allowed"`
+	Rule        string           `json:"rule,omitempty"`
+	Reason      string           `json:"reason"`
+	DecidedAt   core.Timestamp   `json:"decided_at"`
+}
+
+// Decide evaluates the policy for a principal and a variable
+func (p *ColumnPolicy) Decide(principal Principal, varKey core.VariableKey) Decision {
+	decision := Decision{
+		UserID:      principal.UserID,
+		VariableKey: varKey,
+		Allowed:     true,
+		Reason:      "no matching rule",
+		DecidedAt:   core.Now(),
+	}
+	if p == nil {
+		return decision
+	}
+
+	for _, rule := range p.Rules {
+		if !rule.Matches(varKey) {
+			continue
+		}
+		decision.Rule = rule.Name
+		for _, role := range rule.AllowedRoles {
+			if principal.HasRole(role) {
+				decision.Reason = "granted by role " + role
+				return decision
+			}
+		}
+		decision.Allowed = false
+		decision.Reason = "restricted"
+		if rule.Classification != "" {
+			decision.Reason = "restricted (" + rule.Classification + ")"
+		}
+		return decision
+	}
+
+	return decision
+}
+
+type principalKey struct{}
+
+// WithPrincipal attaches the requesting principal to a context
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal attached to a context, if any
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
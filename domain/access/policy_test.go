@@ -0,0 +1,65 @@
+package access
+
+import (
+	"context"
+	"testing"
+
+	"gohypo/domain/core"
+)
+
+// TestColumnPolicyDecide verifies the first matching rule wins, roles grant access
+// case-insensitively, a rule without roles restricts everyone and unmatched variables stay open
+func TestColumnPolicyDecide(t *testing.T) {
+	policy := &ColumnPolicy{Rules: []ColumnRule{
+		{Name: "compensation", Pattern: "*salary*", Classification: "compensation", AllowedRoles: []string{"hr"}},
+		{Name: "identifiers", Pattern: "ssn"},
+		{Name: "broad", Pattern: "*", AllowedRoles: []string{"analyst"}},
+	}}
+	hr := Principal{UserID: "u-hr", Roles: []string{"HR"}}
+	analyst := Principal{UserID: "u-analyst", Roles: []string{"analyst"}}
+
+	cases := []struct {
+		principal Principal
+		varKey    core.VariableKey
+		allowed   bool
+		rule      string
+		reason    string
+	}{
+		{hr, "Base_Salary", true, "compensation", "granted by role hr"},
+		{analyst, "base_salary", false, "compensation", "restricted (compensation)"},
+		{hr, "SSN", false, "identifiers", "restricted"},
+		{analyst, "region", true, "broad", "granted by role analyst"},
+		{hr, "region", false, "broad", "restricted"},
+	}
+	for _, tc := range cases {
+		decision := policy.Decide(tc.principal, tc.varKey)
+		if decision.Allowed != tc.allowed || decision.Rule != tc.rule || decision.Reason != tc.reason {
+			t.Errorf("%s on %s: expected allowed=%v rule=%q reason=%q, got allowed=%v rule=%q reason=%q",
+				tc.principal.UserID, tc.varKey, tc.allowed, tc.rule, tc.reason, decision.Allowed, decision.Rule, decision.Reason)
+		}
+		if decision.UserID != tc.principal.UserID || decision.VariableKey != tc.varKey {
+			t.Errorf("expected the decision to record %s and %s, got %+v", tc.principal.UserID, tc.varKey, decision)
+		}
+	}
+
+	narrow := &ColumnPolicy{Rules: []ColumnRule{{Name: "ids", Pattern: "ssn"}}}
+	if decision := narrow.Decide(Principal{}, "region"); !decision.Allowed || decision.Rule != "" {
+		t.Errorf("expected an unmatched variable to be allowed without a rule, got %+v", decision)
+	}
+	var unset *ColumnPolicy
+	if !unset.Decide(Principal{}, "ssn").Allowed {
+		t.Error("expected a nil policy to allow every variable")
+	}
+}
+
+// TestPrincipalContext verifies a principal round-trips through a context
+func TestPrincipalContext(t *testing.T) {
+	if _, ok := PrincipalFromContext(context.Background()); ok {
+		t.Fatal("expected no principal on a bare context")
+	}
+	ctx := WithPrincipal(context.Background(), Principal{UserID: "u-1", Roles: []string{"hr"}})
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID != "u-1" || !principal.HasRole("hr") {
+		t.Errorf("expected principal u-1 with role hr, got %+v", principal)
+	}
+}
@@ -0,0 +1,262 @@
+// Package causal represents hypotheses as fragments of a causal DAG and merges them into a
+// workspace-level graph, so claims can be checked against each other instead of read one
+// cause→effect string at a time.
+package causal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gohypo/domain/core"
+)
+
+// Edge is a claimed causal direction From → To
+type Edge struct {
+	From       core.VariableKey `json:"from"`
+	To         core.VariableKey `json:"to"`
+	Confidence float64          `json:"confidence"`           // 0..1
+	Validated  bool             `json:"validated"`            // at least one supporting hypothesis passed validation
+	Hypotheses []string         `json:"hypotheses"`           // IDs of the hypotheses claiming this edge
+	Confounder bool             `json:"confounder,omitempty"` // edge from a named confounder, not the main claim
+}
+
+// Fragment is the part of the causal graph one hypothesis claims
+type Fragment struct {
+	HypothesisID string             `json:"hypothesis_id"`
+	Nodes        []core.VariableKey `json:"nodes"`
+	Edges        []Edge             `json:"edges"`
+}
+
+// FromHypothesis builds the fragment for cause → effect. Each confounder becomes a common
+// parent of both, which is what naming it as a confounder claims.
+func FromHypothesis(hypothesisID string, cause, effect core.VariableKey, confounders []core.VariableKey, confidence float64, validated bool) (Fragment, error) {
+	if cause == "" || effect == "" {
+		return Fragment{}, fmt.Errorf("hypothesis %s: cause and effect are required", hypothesisID)
+	}
+	if cause == effect {
+		return Fragment{}, fmt.Errorf("hypothesis %s: %s cannot cause itself", hypothesisID, cause)
+	}
+	confidence = clampConfidence(confidence)
+
+	fragment := Fragment{HypothesisID: hypothesisID, Nodes: []core.VariableKey{cause, effect}}
+	fragment.Edges = append(fragment.Edges, Edge{From: cause, To: effect, Confidence: confidence, Validated: validated, Hypotheses: []string{hypothesisID}})
+	for _, confounder := range confounders {
+		if confounder == "" || confounder == cause || confounder == effect {
+			continue
+		}
+		fragment.Nodes = append(fragment.Nodes, confounder)
+		for _, target := range []core.VariableKey{cause, effect} {
+			fragment.Edges = append(fragment.Edges, Edge{From: confounder, To: target, Confidence: confidence, Validated: validated, Hypotheses: []string{hypothesisID}, Confounder: true})
+		}
+	}
+	return fragment, nil
+}
+
+// edgeKey identifies a directed edge
+type edgeKey struct {
+	from, to core.VariableKey
+}
+
+// Graph is a causal graph merged from hypothesis fragments. Each directed edge appears once;
+// repeated claims strengthen it rather than duplicating it.
+type Graph struct {
+	nodes map[core.VariableKey]bool
+	edges map[edgeKey]*Edge
+}
+
+// NewGraph creates an empty graph
+func NewGraph() *Graph {
+	return &Graph{nodes: make(map[core.VariableKey]bool), edges: make(map[edgeKey]*Edge)}
+}
+
+// Merge adds a fragment. Independent claims of the same edge combine by noisy-OR, so two
+// 0.5-confidence hypotheses give 0.75; a main claim outranks a confounder edge.
+func (g *Graph) Merge(fragment Fragment) {
+	for _, node := range fragment.Nodes {
+		g.nodes[node] = true
+	}
+	for _, edge := range fragment.Edges {
+		g.nodes[edge.From] = true
+		g.nodes[edge.To] = true
+
+		key := edgeKey{edge.From, edge.To}
+		existing, ok := g.edges[key]
+		if !ok {
+			merged := edge
+			merged.Hypotheses = append([]string(nil), edge.Hypotheses...)
+			g.edges[key] = &merged
+			continue
+		}
+		existing.Confidence = 1 - (1-existing.Confidence)*(1-edge.Confidence)
+		existing.Validated = existing.Validated || edge.Validated
+		existing.Confounder = existing.Confounder && edge.Confounder
+		for _, id := range edge.Hypotheses {
+			if !containsString(existing.Hypotheses, id) {
+				existing.Hypotheses = append(existing.Hypotheses, id)
+			}
+		}
+	}
+}
+
+// Nodes returns the graph's variables, sorted
+func (g *Graph) Nodes() []core.VariableKey {
+	nodes := make([]core.VariableKey, 0, len(g.nodes))
+	for node := range g.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+	return nodes
+}
+
+// Edges returns the graph's edges ordered by source, then target
+func (g *Graph) Edges() []Edge {
+	edges := make([]Edge, 0, len(g.edges))
+	for _, edge := range g.edges {
+		edges = append(edges, *edge)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
+
+// Conflict kinds
+const (
+	ConflictContradiction = "contradiction" // validated hypotheses claim A → B and B → A
+	ConflictCycle         = "cycle"         // validated edges form a longer feedback loop
+)
+
+// Conflict is a set of validated claims that cannot all hold in a DAG
+type Conflict struct {
+	Kind       string             `json:"kind"`
+	Variables  []core.VariableKey `json:"variables"`
+	Hypotheses []string           `json:"hypotheses"`
+	Detail     string             `json:"detail"`
+}
+
+// Conflicts checks the validated edges for directions that contradict each other and for
+// longer cycles. Unvalidated claims are candidates and may disagree freely.
+func (g *Graph) Conflicts() []Conflict {
+	adjacency := make(map[core.VariableKey][]core.VariableKey)
+	for _, edge := range g.Edges() {
+		if edge.Validated {
+			adjacency[edge.From] = append(adjacency[edge.From], edge.To)
+		}
+	}
+
+	var conflicts []Conflict
+	for _, component := range stronglyConnected(g.Nodes(), adjacency) {
+		if len(component) < 2 {
+			continue
+		}
+		members := make(map[core.VariableKey]bool, len(component))
+		for _, node := range component {
+			members[node] = true
+		}
+		var hypotheses []string
+		for _, edge := range g.Edges() {
+			if edge.Validated && members[edge.From] && members[edge.To] {
+				for _, id := range edge.Hypotheses {
+					if !containsString(hypotheses, id) {
+						hypotheses = append(hypotheses, id)
+					}
+				}
+			}
+		}
+		sort.Strings(hypotheses)
+
+		conflict := Conflict{Variables: component, Hypotheses: hypotheses}
+		if len(component) == 2 {
+			conflict.Kind = ConflictContradiction
+			conflict.Detail = fmt.Sprintf("validated hypotheses claim both %s → %s and %s → %s", component[0], component[1], component[1], component[0])
+		} else {
+			conflict.Kind = ConflictCycle
+			conflict.Detail = "validated edges form a cycle through " + joinKeys(component)
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+// stronglyConnected returns the strongly connected components (Tarjan), each sorted, in
+// order of their smallest node
+func stronglyConnected(nodes []core.VariableKey, adjacency map[core.VariableKey][]core.VariableKey) [][]core.VariableKey {
+	index := 0
+	indices := make(map[core.VariableKey]int)
+	lowlink := make(map[core.VariableKey]int)
+	onStack := make(map[core.VariableKey]bool)
+	var stack []core.VariableKey
+	var components [][]core.VariableKey
+
+	var visit func(node core.VariableKey)
+	visit = func(node core.VariableKey) {
+		indices[node] = index
+		lowlink[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range adjacency[node] {
+			if _, seen := indices[next]; !seen {
+				visit(next)
+				lowlink[node] = min(lowlink[node], lowlink[next])
+			} else if onStack[next] {
+				lowlink[node] = min(lowlink[node], indices[next])
+			}
+		}
+
+		if lowlink[node] == indices[node] {
+			var component []core.VariableKey
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == node {
+					break
+				}
+			}
+			sort.Slice(component, func(i, j int) bool { return component[i] < component[j] })
+			components = append(components, component)
+		}
+	}
+
+	for _, node := range nodes {
+		if _, seen := indices[node]; !seen {
+			visit(node)
+		}
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+	return components
+}
+
+func clampConfidence(c float64) float64 {
+	if c < 0 {
+		return 0
+	}
+	if c > 1 {
+		return 1
+	}
+	return c
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func joinKeys(keys []core.VariableKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = string(key)
+	}
+	return strings.Join(parts, ", ")
+}
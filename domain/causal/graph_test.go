@@ -0,0 +1,66 @@
+package causal
+
+import (
+	"math"
+	"testing"
+
+	"gohypo/domain/core"
+)
+
+func mustFragment(t *testing.T, id string, cause, effect core.VariableKey, confidence float64, validated bool) Fragment {
+	t.Helper()
+	fragment, err := FromHypothesis(id, cause, effect, nil, confidence, validated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fragment
+}
+
+// TestMergeCombinesRepeatedClaims verifies repeated edges merge by noisy-OR
+func TestMergeCombinesRepeatedClaims(t *testing.T) {
+	g := NewGraph()
+	g.Merge(mustFragment(t, "h1", "price", "churn", 0.5, false))
+	g.Merge(mustFragment(t, "h2", "price", "churn", 0.5, true))
+
+	edges := g.Edges()
+	if len(edges) != 1 {
+		t.Fatalf("expected one merged edge, got %d", len(edges))
+	}
+	if math.Abs(edges[0].Confidence-0.75) > 1e-9 || !edges[0].Validated || len(edges[0].Hypotheses) != 2 {
+		t.Fatalf("unexpected merged edge %+v", edges[0])
+	}
+}
+
+// TestConflictsFindContradictionsAndCycles verifies only validated claims are checked
+func TestConflictsFindContradictionsAndCycles(t *testing.T) {
+	g := NewGraph()
+	g.Merge(mustFragment(t, "h1", "a", "b", 0.9, true))
+	g.Merge(mustFragment(t, "h2", "b", "a", 0.8, true))
+	g.Merge(mustFragment(t, "h3", "x", "y", 0.9, true))
+	g.Merge(mustFragment(t, "h4", "y", "z", 0.9, true))
+	g.Merge(mustFragment(t, "h5", "z", "x", 0.9, true))
+	g.Merge(mustFragment(t, "h6", "p", "q", 0.9, true))
+	g.Merge(mustFragment(t, "h7", "q", "p", 0.9, false))
+
+	conflicts := g.Conflicts()
+	if len(conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts, got %+v", conflicts)
+	}
+	if conflicts[0].Kind != ConflictContradiction || len(conflicts[0].Hypotheses) != 2 {
+		t.Fatalf("expected the a/b contradiction first, got %+v", conflicts[0])
+	}
+	if conflicts[1].Kind != ConflictCycle || len(conflicts[1].Variables) != 3 {
+		t.Fatalf("expected the x/y/z cycle, got %+v", conflicts[1])
+	}
+}
+
+// TestFromHypothesisRejectsSelfLoops verifies a variable cannot cause itself
+func TestFromHypothesisRejectsSelfLoops(t *testing.T) {
+	if _, err := FromHypothesis("h1", "a", "a", nil, 0.5, true); err == nil {
+		t.Fatal("expected an error for a self loop")
+	}
+	fragment, err := FromHypothesis("h2", "a", "b", []core.VariableKey{"season"}, 0.5, true)
+	if err != nil || len(fragment.Edges) != 3 {
+		t.Fatalf("expected the claim plus two confounder edges, got %+v (%v)", fragment.Edges, err)
+	}
+}
@@ -26,7 +26,8 @@ func NewRegistryVersion(contracts map[string]*dataset.VariableContract) *Registr
 	}
 }
 
-// ComputeRegistryHash creates a deterministic hash of all contracts
+// ComputeRegistryHash creates a deterministic hash of all contracts. A contract counting
+// business days contributes its calendar's hash, so editing a holiday changes the registry.
 func ComputeRegistryHash(contracts map[string]*dataset.VariableContract) core.RegistryHash {
 	// Sort keys for deterministic hashing
 	keys := make([]string, 0, len(contracts))
@@ -38,19 +39,29 @@ func ComputeRegistryHash(contracts map[string]*dataset.VariableContract) core.Re
 	var data string
 	for _, key := range keys {
 		contract := contracts[key]
-		data += fmt.Sprintf("%s:%s:%s:%v:%s:%t;",
+		data += fmt.Sprintf("%s:%s:%s:%s:%s:%t:%s:%s;",
 			key,
 			contract.AsOfMode,
 			contract.StatisticalType,
-			contract.WindowDays,
+			days(contract.WindowDays),
 			contract.ImputationPolicy,
 			contract.ScalarGuarantee,
+			days(contract.LagDays),
+			contract.Days().Fingerprint(),
 		)
 	}
 
 	return core.NewRegistryHash([]byte(data))
 }
 
+// days formats an optional day count by value; formatting the pointer would hash its address
+func days(n *int) string {
+	if n == nil {
+		return "-"
+	}
+	return fmt.Sprint(*n)
+}
+
 // RegistryManager handles registry versioning and contract compilation
 type RegistryManager struct {
 	versions map[core.RegistryHash]*RegistryVersion
@@ -112,5 +123,9 @@ func (rm *RegistryManager) ValidateContract(varKey string) error {
 		return core.NewValidationError("contract", "imputation_policy is required")
 	}
 
+	if err := contract.Days().Validate(); err != nil {
+		return core.NewValidationError("contract", err.Error())
+	}
+
 	return nil
 }
@@ -0,0 +1,35 @@
+package contracts
+
+import (
+	"testing"
+	"time"
+
+	"gohypo/domain/dataset"
+)
+
+func TestRegistryHashTracksBusinessCalendar(t *testing.T) {
+	window := 10
+	contract := func(holidays ...time.Time) map[string]*dataset.VariableContract {
+		calendar := &dataset.BusinessCalendar{}
+		for _, day := range holidays {
+			calendar.Holidays = append(calendar.Holidays, dataset.Holiday{Date: day})
+		}
+		w := window // a distinct pointer each time; only the value may count
+		return map[string]*dataset.VariableContract{
+			"orders": {AsOfMode: dataset.AsOfCountWindow, WindowDays: &w, DayCount: dataset.DayCountBusiness, Calendar: calendar},
+		}
+	}
+	christmas := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	if ComputeRegistryHash(contract(christmas)) != ComputeRegistryHash(contract(christmas)) {
+		t.Error("equal contracts should hash equally")
+	}
+	if ComputeRegistryHash(contract(christmas)) == ComputeRegistryHash(contract()) {
+		t.Error("a holiday added to the calendar should change the registry hash")
+	}
+	calendarDays := contract(christmas)
+	calendarDays["orders"].DayCount = dataset.DayCountCalendar
+	if ComputeRegistryHash(calendarDays) == ComputeRegistryHash(contract(christmas)) {
+		t.Error("switching to calendar days should change the registry hash")
+	}
+}
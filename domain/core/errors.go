@@ -34,6 +34,7 @@ var (
 	ErrAsOfModeUnsupported = errors.New("unsupported as-of mode")
 	ErrLagTooLarge         = errors.New("lag buffer too large")
 	ErrFutureData          = errors.New("future data detected")
+	ErrQueryNotApproved    = errors.New("query not approved for execution")
 )
 
 // Error constructors with context
@@ -90,10 +90,11 @@ func ParseArtifactID(s string) (ArtifactID, error) {
 
 // Artifact represents any output of the system
 type Artifact struct {
-	ID        ID           `json:"id"`
-	Kind      ArtifactKind `json:"kind"`
-	Payload   interface{}  `json:"payload"`
-	CreatedAt Timestamp    `json:"created_at"`
+	ID            ID           `json:"id"`
+	Kind          ArtifactKind `json:"kind"`
+	SchemaVersion int          `json:"schema_version,omitempty"` // payload schema; stamped by the ledger
+	Payload       interface{}  `json:"payload"`
+	CreatedAt     Timestamp    `json:"created_at"`
 }
 
 // ArtifactKind defines types of artifacts
@@ -107,6 +108,8 @@ const (
 	ArtifactSkippedRelationship ArtifactKind = "skipped_relationship"
 	// ArtifactSweepManifest captures audit metadata for a sweep (counts, thresholds, fingerprint, etc.).
 	ArtifactSweepManifest ArtifactKind = "sweep_manifest"
+	// ArtifactSweepCheckpoint records a batch of completed pairs so an interrupted sweep can resume.
+	ArtifactSweepCheckpoint ArtifactKind = "sweep_checkpoint"
 	// ArtifactFDRFamily captures FDR family definitions produced by stats stages.
 	ArtifactFDRFamily      ArtifactKind = "fdr_family"
 	ArtifactVariableHealth ArtifactKind = "variable_health"
@@ -115,4 +118,20 @@ const (
 	// NEW: Greenfield Research Flow artifacts
 	ArtifactResearchDirective  ArtifactKind = "research_directive"
 	ArtifactEngineeringBacklog ArtifactKind = "engineering_backlog"
+	// ArtifactLLMUsage records tokens and estimated cost of one hypothesis generation run.
+	ArtifactLLMUsage ArtifactKind = "llm_usage"
+	// ArtifactPropensityMatch compares a binary cause's effect before and after propensity matching.
+	ArtifactPropensityMatch ArtifactKind = "propensity_match"
+	// ArtifactCompactedRun stands in for a run's artifacts moved to cold storage, keeping their fingerprints.
+	ArtifactCompactedRun ArtifactKind = "compacted_run"
+	// ArtifactRunInputs records what a run was produced from, so it can be replayed from its ledger.
+	ArtifactRunInputs ArtifactKind = "run_inputs"
+	// ArtifactPairExploration records an on-demand test of a variable pair picked in the explorer.
+	ArtifactPairExploration ArtifactKind = "pair_exploration"
+	// ArtifactSkepticReanalysis records a skeptic-mode re-analysis of a hypothesis.
+	ArtifactSkepticReanalysis ArtifactKind = "skeptic_reanalysis"
+	// ArtifactNoiseFloor records a calibration of a workspace's noise floor on permuted data.
+	ArtifactNoiseFloor ArtifactKind = "noise_floor"
+	// ArtifactSweepBaseline points at the sweep the next incremental sweep of a dataset reuses.
+	ArtifactSweepBaseline ArtifactKind = "sweep_baseline"
 )
@@ -0,0 +1,68 @@
+package core
+
+import "fmt"
+
+// ArtifactSchemaVersions is the payload schema version each kind is produced with now. A
+// producer that renames or removes a payload field bumps its kind's version, so readers
+// written against the old fields can tell they no longer understand the payload.
+// Kinds not listed are at version 1.
+var ArtifactSchemaVersions = map[ArtifactKind]int{}
+
+// CurrentSchemaVersion is the payload schema version kind is produced with
+func CurrentSchemaVersion(kind ArtifactKind) int {
+	if version, ok := ArtifactSchemaVersions[kind]; ok {
+		return version
+	}
+	return 1
+}
+
+// PayloadSchemaVersion is the schema version of the artifact's payload. Artifacts stored
+// before versions were recorded are version 1.
+func (a Artifact) PayloadSchemaVersion() int {
+	if a.SchemaVersion == 0 {
+		return 1
+	}
+	return a.SchemaVersion
+}
+
+// StampSchemaVersion records the current schema version of the artifact's kind, unless the
+// producer recorded one. Ledgers stamp every artifact they store.
+func StampSchemaVersion(a Artifact) Artifact {
+	if a.SchemaVersion == 0 {
+		a.SchemaVersion = CurrentSchemaVersion(a.Kind)
+	}
+	return a
+}
+
+// SchemaSupport is the range of payload schema versions a reader understands
+type SchemaSupport struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// SupportsVersions declares a reader of the given versions
+func SupportsVersions(min, max int) SchemaSupport {
+	return SchemaSupport{Min: min, Max: max}
+}
+
+// SchemaNegotiation is the outcome of offering an artifact to a reader
+type SchemaNegotiation struct {
+	Version   int    `json:"schema_version"`
+	Supported bool   `json:"supported"`
+	Newer     bool   `json:"newer,omitempty"` // produced by a newer engine than the reader knows
+	Notice    string `json:"notice,omitempty"`
+}
+
+// Negotiate reports whether the reader understands the artifact's payload, and why not
+func (s SchemaSupport) Negotiate(a Artifact) SchemaNegotiation {
+	version := a.PayloadSchemaVersion()
+	n := SchemaNegotiation{Version: version, Supported: version >= s.Min && version <= s.Max}
+	switch {
+	case version > s.Max:
+		n.Newer = true
+		n.Notice = fmt.Sprintf("This %s was produced by a newer engine (schema v%d; this view reads up to v%d).", a.Kind, version, s.Max)
+	case version < s.Min:
+		n.Notice = fmt.Sprintf("This %s was produced by an older engine (schema v%d; this view reads v%d and later).", a.Kind, version, s.Min)
+	}
+	return n
+}
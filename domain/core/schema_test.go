@@ -0,0 +1,57 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSchemaNegotiation(t *testing.T) {
+	reader := SupportsVersions(1, 2)
+
+	tests := []struct {
+		version   int
+		supported bool
+		newer     bool
+	}{
+		{0, true, false}, // stored before versions were recorded
+		{1, true, false},
+		{2, true, false},
+		{3, false, true},
+	}
+	for _, tt := range tests {
+		artifact := Artifact{Kind: ArtifactRelationship, SchemaVersion: tt.version}
+		n := reader.Negotiate(artifact)
+		if n.Supported != tt.supported || n.Newer != tt.newer {
+			t.Errorf("v%d: supported=%t newer=%t, want %t %t", tt.version, n.Supported, n.Newer, tt.supported, tt.newer)
+		}
+		if !n.Supported && !strings.Contains(n.Notice, "newer engine") {
+			t.Errorf("v%d: notice %q should say a newer engine produced it", tt.version, n.Notice)
+		}
+	}
+
+	if n := SupportsVersions(2, 2).Negotiate(Artifact{Kind: ArtifactRelationship}); n.Supported || n.Newer || n.Notice == "" {
+		t.Errorf("an older payload should be unsupported with a notice, got %+v", n)
+	}
+}
+
+func TestStampSchemaVersion(t *testing.T) {
+	ArtifactSchemaVersions[ArtifactRelationship] = 3
+	defer delete(ArtifactSchemaVersions, ArtifactRelationship)
+
+	stamped := StampSchemaVersion(Artifact{Kind: ArtifactRelationship})
+	if stamped.SchemaVersion != 3 {
+		t.Errorf("stamped v%d, want the kind's current v3", stamped.SchemaVersion)
+	}
+	if kept := StampSchemaVersion(Artifact{Kind: ArtifactRelationship, SchemaVersion: 2}); kept.SchemaVersion != 2 {
+		t.Errorf("a version set by the producer should be kept, got v%d", kept.SchemaVersion)
+	}
+	if other := StampSchemaVersion(Artifact{Kind: ArtifactHypothesis}); other.SchemaVersion != 1 {
+		t.Errorf("unlisted kinds are v1, got v%d", other.SchemaVersion)
+	}
+
+	data, _ := json.Marshal(stamped)
+	if !strings.Contains(string(data), `"schema_version":3`) {
+		t.Errorf("schema version not serialized: %s", data)
+	}
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// Sources of a dataset's time zone, recorded so a manifest shows why a zone applied
+const (
+	TimeZoneFromDataset = "dataset" // set on the dataset when it was uploaded
+	TimeZoneFromSpec    = "spec"    // set by the run spec or request
+	TimeZoneFromDefault = "default" // nothing set; wall-clock times are UTC
+)
+
+// TimeZone is the zone a dataset's wall-clock timestamps were written in. Timestamps
+// without an offset, SnapshotAt included, are read in it and converted to UTC instants,
+// so "as of midnight" is the dataset's midnight wherever the resolver runs.
+type TimeZone struct {
+	Name   string `json:"name"`   // IANA name, e.g. Europe/Berlin
+	Source string `json:"source"` // dataset, spec or default
+	loc    *time.Location
+}
+
+// UTCZone is the zone of datasets that set none
+func UTCZone() TimeZone {
+	return TimeZone{Name: "UTC", Source: TimeZoneFromDefault, loc: time.UTC}
+}
+
+// ResolveTimeZone loads the named IANA zone; an empty name is UTC by default. "Local" is
+// rejected, as it would make a run depend on the machine it ran on.
+func ResolveTimeZone(name, source string) (TimeZone, error) {
+	if name == "" {
+		return UTCZone(), nil
+	}
+	if name == "Local" {
+		return TimeZone{}, fmt.Errorf("time zone must be an IANA name such as Europe/Berlin, not Local")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return TimeZone{}, fmt.Errorf("unknown time zone %q", name)
+	}
+	return TimeZone{Name: loc.String(), Source: source, loc: loc}, nil
+}
+
+// Location returns the zone's location; a zone read back from JSON is loaded by name
+func (z TimeZone) Location() *time.Location {
+	if z.loc != nil {
+		return z.loc
+	}
+	if z.Name != "Local" {
+		if loc, err := time.LoadLocation(z.Name); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// Wall reads the wall clock of t, whatever location it carries, as a time in the zone and
+// returns that instant in UTC. It is how naive timestamps, such as a TIMESTAMP column that
+// the driver labels UTC, are placed on the timeline.
+func (z TimeZone) Wall(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	h, mi, s := t.Clock()
+	return time.Date(y, mo, d, h, mi, s, t.Nanosecond(), z.Location()).UTC()
+}
+
+// Local formats an instant as wall-clock time in the zone, without an offset, as naive
+// timestamp columns store it
+func (z TimeZone) Local(t time.Time) string {
+	return t.In(z.Location()).Format("2006-01-02 15:04:05")
+}
+
+// ApplyLag subtracts lag from a snapshot time. Whole days are stepped on the zone's
+// calendar, so a one-day lag from midnight is the previous midnight even across a
+// daylight-saving change; the rest of the lag is subtracted as elapsed time.
+func (z TimeZone) ApplyLag(at SnapshotAt, lag Lag) CutoffAt {
+	const day = 24 * time.Hour
+	days := int(lag.Duration() / day)
+	rest := lag.Duration() % day
+	local := at.Time().In(z.Location()).AddDate(0, 0, -days).Add(-rest)
+	return NewCutoffAt(local.UTC())
+}
+
+// TimeZoneDecision records how a resolution read time: the zone, why it applied and the
+// cutoff as the dataset's wall clock shows it
+type TimeZoneDecision struct {
+	Zone         string `json:"zone"`
+	Source       string `json:"source"`
+	CutoffLocal  string `json:"cutoff_local"`  // e.g. 2024-03-01T00:00:00+01:00
+	CutoffOffset string `json:"cutoff_offset"` // UTC offset in effect at the cutoff, e.g. +01:00
+}
+
+// Decision records the zone for a resolution cut off at cutoff
+func (z TimeZone) Decision(cutoff CutoffAt) TimeZoneDecision {
+	local := cutoff.Time().In(z.Location())
+	name := z.Name
+	if name == "" {
+		name = "UTC"
+	}
+	source := z.Source
+	if source == "" {
+		source = TimeZoneFromDefault
+	}
+	return TimeZoneDecision{
+		Zone:         name,
+		Source:       source,
+		CutoffLocal:  local.Format(time.RFC3339),
+		CutoffOffset: local.Format("-07:00"),
+	}
+}
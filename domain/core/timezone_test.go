@@ -0,0 +1,72 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestTimeZoneWallReadsNaiveTimesInTheZone tests that midnight means the dataset's midnight
+func TestTimeZoneWallReadsNaiveTimesInTheZone(t *testing.T) {
+	zone, err := ResolveTimeZone("Europe/Berlin", TimeZoneFromDataset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	naive := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC) // as a TIMESTAMP column scans
+	got := zone.Wall(naive)
+	want := time.Date(2024, 2, 29, 23, 0, 0, 0, time.UTC)
+	if !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("Wall = %v, want %v", got, want)
+	}
+	if local := zone.Local(got); local != "2024-03-01 00:00:00" {
+		t.Errorf("Local = %q, want the wall clock back", local)
+	}
+}
+
+// TestTimeZoneApplyLagAcrossDaylightSaving tests that a one-day lag keeps local midnight
+func TestTimeZoneApplyLagAcrossDaylightSaving(t *testing.T) {
+	zone, err := ResolveTimeZone("America/New_York", TimeZoneFromSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Clocks went forward on 2024-03-10, so that day was 23 hours long
+	at := NewSnapshotAt(zone.Wall(time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)))
+	cutoff := zone.ApplyLag(at, NewLag(24*time.Hour+30*time.Minute))
+
+	if local := zone.Local(cutoff.Time()); local != "2024-03-09 23:30:00" {
+		t.Errorf("cutoff = %s local, want 2024-03-09 23:30:00", local)
+	}
+	// The plain lag subtracts elapsed time and lands an hour off local midnight
+	if naive := zone.Local(at.ApplyLag(NewLag(24 * time.Hour)).Time()); naive != "2024-03-09 23:00:00" {
+		t.Errorf("elapsed lag = %s local", naive)
+	}
+
+	decision := zone.Decision(cutoff)
+	if decision.Zone != "America/New_York" || decision.Source != TimeZoneFromSpec || decision.CutoffOffset != "-05:00" {
+		t.Errorf("decision = %+v", decision)
+	}
+}
+
+func TestResolveTimeZone(t *testing.T) {
+	zone, err := ResolveTimeZone("", TimeZoneFromDataset)
+	if err != nil || zone.Name != "UTC" || zone.Source != TimeZoneFromDefault {
+		t.Errorf("empty zone = %+v, %v; want the UTC default", zone, err)
+	}
+	for _, name := range []string{"Local", "Mars/Olympus"} {
+		if _, err := ResolveTimeZone(name, TimeZoneFromSpec); err == nil {
+			t.Errorf("expected %q to be rejected", name)
+		}
+	}
+
+	// A zone read back from a manifest still converts
+	berlin, _ := ResolveTimeZone("Europe/Berlin", TimeZoneFromDataset)
+	data, _ := json.Marshal(berlin)
+	var decoded TimeZone
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	naive := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	if !decoded.Wall(naive).Equal(berlin.Wall(naive)) {
+		t.Errorf("decoded zone reads %v, want %v", decoded.Wall(naive), berlin.Wall(naive))
+	}
+}
@@ -0,0 +1,86 @@
+package profiling
+
+import "strings"
+
+// OrdinalScales are the ordered category sets recognized as ordinal, lowest level first.
+// Values are matched case-insensitively, ignoring surrounding whitespace, hyphens and
+// underscores.
+var OrdinalScales = [][]string{
+	{"very low", "low", "medium", "high", "very high"},
+	{"low", "moderate", "high"},
+	{"very small", "small", "medium", "large", "very large"},
+	{"xxs", "xs", "s", "m", "l", "xl", "xxl"},
+	{"very poor", "poor", "fair", "good", "very good", "excellent"},
+	{"bad", "average", "good", "excellent"},
+	{"never", "rarely", "sometimes", "often", "always"},
+	{"strongly disagree", "disagree", "neutral", "agree", "strongly agree"},
+	{"very dissatisfied", "dissatisfied", "neutral", "satisfied", "very satisfied"},
+	{"very unlikely", "unlikely", "neutral", "likely", "very likely"},
+	{"none", "mild", "moderate", "severe", "critical"},
+	{"bronze", "silver", "gold", "platinum"},
+	{"first", "second", "third", "fourth", "fifth"},
+}
+
+// minOrdinalLevels is the fewest distinct levels treated as ordinal; two levels are binary
+const minOrdinalLevels = 3
+
+// OrdinalLevels returns the distinct values in scale order when every one of them is a level
+// of the same known scale and there are at least three, otherwise nil. The returned levels
+// keep the values' original spelling.
+func OrdinalLevels(values []string) []string {
+	spelling := make(map[string]string)
+	for _, v := range values {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		key := normalizeLevel(v)
+		if _, seen := spelling[key]; !seen {
+			spelling[key] = v
+		}
+	}
+	if len(spelling) < minOrdinalLevels {
+		return nil
+	}
+
+	for _, scale := range OrdinalScales {
+		if len(scale) < len(spelling) {
+			continue
+		}
+		var levels []string
+		for _, level := range scale {
+			if original, ok := spelling[level]; ok {
+				levels = append(levels, original)
+			}
+		}
+		if len(levels) == len(spelling) {
+			return levels
+		}
+	}
+	return nil
+}
+
+// OrdinalEncoding maps each level to its rank, lowest 0
+func OrdinalEncoding(levels []string) map[string]float64 {
+	encoding := make(map[string]float64, len(levels))
+	for rank, level := range levels {
+		encoding[level] = float64(rank)
+	}
+	return encoding
+}
+
+// LevelRank returns the rank of value among levels, matched the way scales are recognized
+func LevelRank(levels []string, value string) (int, bool) {
+	key := normalizeLevel(value)
+	for rank, level := range levels {
+		if normalizeLevel(level) == key {
+			return rank, true
+		}
+	}
+	return 0, false
+}
+
+func normalizeLevel(v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	v = strings.NewReplacer("_", " ", "-", " ").Replace(v)
+	return strings.Join(strings.Fields(v), " ")
+}
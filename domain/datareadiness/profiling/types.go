@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"gohypo/domain/datareadiness/ingestion"
+	"gohypo/domain/stats"
 	"gohypo/domain/stats/brief"
 )
 
@@ -142,12 +143,26 @@ func (fp *FieldProfile) ToStatisticalBrief() *brief.StatisticalBrief {
 	return sb
 }
 
+// TestProfile returns the measures the stats sweep chooses the field's test parameters from
+func (fp *FieldProfile) TestProfile() stats.VariableProfile {
+	profile := stats.VariableProfile{
+		Rows:        fp.SampleSize,
+		MissingRate: fp.MissingStats.MissingRate,
+		Cardinality: fp.Cardinality.UniqueCount,
+	}
+	if fp.TypeSpecific.NumericStats != nil {
+		profile.Skewness = fp.TypeSpecific.NumericStats.Skewness
+	}
+	return profile
+}
+
 // InferredType represents the automatically detected data type
 type InferredType string
 
 const (
 	TypeNumeric     InferredType = "numeric"
 	TypeCategorical InferredType = "categorical"
+	TypeOrdinal     InferredType = "ordinal" // ordered categories, e.g. Low/Medium/High
 	TypeBoolean     InferredType = "boolean"
 	TypeTimestamp   InferredType = "timestamp"
 	TypeText        InferredType = "text"
@@ -208,6 +223,9 @@ type CategoricalStats struct {
 	ModeFrequency  int     `json:"mode_frequency"`
 	GiniIndex      float64 `json:"gini_index"`      // Measure of inequality
 	RareCategories int     `json:"rare_categories"` // Categories appearing < 1%
+
+	// Levels are the categories in order, lowest first; set for ordinal fields only
+	Levels []string `json:"levels,omitempty"`
 }
 
 // TextStats contains statistics for text fields
@@ -3,6 +3,7 @@ package resolution
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"gohypo/adapters/datareadiness/coercer"
@@ -20,6 +21,7 @@ type ReadinessOrchestratorDeps struct {
 	Coercer     *coercer.TypeCoercer
 	Synthesizer *synthesizer.ContractSynthesizer
 	Gate        *ReadinessGate
+	Seasonality SeasonalityConfig
 }
 
 // DataReadinessOrchestrator coordinates the entire data readiness pipeline
@@ -29,19 +31,21 @@ type DataReadinessOrchestrator struct {
 
 // OrchestratorConfig defines the configuration for the orchestrator
 type OrchestratorConfig struct {
-	CoercionConfig  coercer.CoercionConfig      `json:"coercion_config"`
-	SynthesisConfig synthesizer.SynthesisConfig `json:"synthesis_config"`
-	GateConfig      GateConfig                  `json:"gate_config"`
-	ProfilingConfig profiling.ProfilingConfig   `json:"profiling_config"`
+	CoercionConfig    coercer.CoercionConfig      `json:"coercion_config"`
+	SynthesisConfig   synthesizer.SynthesisConfig `json:"synthesis_config"`
+	GateConfig        GateConfig                  `json:"gate_config"`
+	ProfilingConfig   profiling.ProfilingConfig   `json:"profiling_config"`
+	SeasonalityConfig SeasonalityConfig           `json:"seasonality_config"`
 }
 
 // DefaultOrchestratorConfig returns sensible defaults
 func DefaultOrchestratorConfig() OrchestratorConfig {
 	return OrchestratorConfig{
-		CoercionConfig:  coercer.DefaultCoercionConfig(),
-		SynthesisConfig: synthesizer.DefaultSynthesisConfig(),
-		GateConfig:      DefaultGateConfig(),
-		ProfilingConfig: profiling.DefaultProfilingConfig(),
+		CoercionConfig:    coercer.DefaultCoercionConfig(),
+		SynthesisConfig:   synthesizer.DefaultSynthesisConfig(),
+		GateConfig:        DefaultGateConfig(),
+		ProfilingConfig:   profiling.DefaultProfilingConfig(),
+		SeasonalityConfig: DefaultSeasonalityConfig(),
 	}
 }
 
@@ -87,6 +91,18 @@ func (o *DataReadinessOrchestrator) ProcessSource(ctx context.Context, sourceNam
 		return ReadinessResult{}, fmt.Errorf("no events could be ingested from source %s", sourceName)
 	}
 
+	// Step 1b: Decompose seasonal series into trend/seasonal/residual variables, which then
+	// flow through profiling and contract synthesis like any ingested field
+	var decompositions []SeasonalComponents
+	if o.deps.Seasonality.Enabled {
+		var derived []ingestion.CanonicalEvent
+		derived, decompositions = DecomposeSeasonalFields(events, o.deps.Seasonality)
+		if len(derived) > 0 {
+			events = append(events, derived...)
+			fmt.Printf("Derived %d seasonal component events for source %s\n", len(derived), sourceName)
+		}
+	}
+
 	// Step 2: Profile all field_keys
 	profilingResult, err := o.deps.Profiler.ProfileSource(ctx, sourceName, events, profiling.DefaultProfilingConfig())
 	if err != nil {
@@ -113,6 +129,10 @@ func (o *DataReadinessOrchestrator) ProcessSource(ctx context.Context, sourceNam
 	for i, evaluation := range readinessResult.ReadyVariables {
 		readinessResult.ReadyVariables[i] = o.deps.Gate.ApplyRemediation(evaluation)
 	}
+	readinessResult.Decompositions = decompositions
+	for _, evaluation := range append(append([]VariableEvaluation(nil), readinessResult.ReadyVariables...), readinessResult.RejectedVariables...) {
+		readinessResult.Audit = append(readinessResult.Audit, auditEntry(evaluation, AuditModeFull, "", startTime))
+	}
 
 	// Log final results
 	fmt.Printf("Data readiness completed for %s: %d events ingested, %d variables profiled, %d ready, %d rejected (%.2fs)\n",
@@ -345,6 +365,12 @@ func (o *DataReadinessOrchestrator) generateDescription(profile profiling.FieldP
 	case profiling.TypeCategorical:
 		return fmt.Sprintf("Categorical variable (%d unique values)", profile.Cardinality.UniqueCount)
 
+	case profiling.TypeOrdinal:
+		if profile.TypeSpecific.CategoricalStats != nil {
+			return fmt.Sprintf("Ordinal variable (%s)", strings.Join(profile.TypeSpecific.CategoricalStats.Levels, " < "))
+		}
+		return "Ordinal variable"
+
 	case profiling.TypeBoolean:
 		return "Boolean/binary variable"
 
@@ -180,11 +180,13 @@ func (g *ReadinessGate) ApplyRemediation(evaluation VariableEvaluation) Variable
 
 // ReadinessResult contains the outcome of readiness evaluation
 type ReadinessResult struct {
-	TotalVariables    int                  `json:"total_variables"`
-	ReadyCount        int                  `json:"ready_count"`
-	RejectedCount     int                  `json:"rejected_count"`
-	ReadyVariables    []VariableEvaluation `json:"ready_variables"`
-	RejectedVariables []VariableEvaluation `json:"rejected_variables"`
+	TotalVariables    int                   `json:"total_variables"`
+	ReadyCount        int                   `json:"ready_count"`
+	RejectedCount     int                   `json:"rejected_count"`
+	ReadyVariables    []VariableEvaluation  `json:"ready_variables"`
+	RejectedVariables []VariableEvaluation  `json:"rejected_variables"`
+	Decompositions    []SeasonalComponents  `json:"decompositions,omitempty"`
+	Audit             []ReadinessAuditEntry `json:"audit,omitempty"` // every evaluation of every variable, oldest first
 }
 
 // VariableEvaluation contains the evaluation of a single variable
@@ -0,0 +1,219 @@
+package resolution
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gohypo/domain/datareadiness/ingestion"
+	"gohypo/domain/datareadiness/profiling"
+	"gohypo/domain/dataset"
+)
+
+// Audit modes of readiness evaluations
+const (
+	AuditModeFull    = "full"
+	AuditModePartial = "partial"
+)
+
+// ReadinessAuditEntry records one evaluation of a variable: when it ran, whether it was a
+// full run or a partial re-run, and how the verdict moved
+type ReadinessAuditEntry struct {
+	VariableKey string    `json:"variable_key"`
+	Mode        string    `json:"mode"`
+	Reason      string    `json:"reason,omitempty"`
+	EvaluatedAt time.Time `json:"evaluated_at"`
+	WasReady    *bool     `json:"was_ready,omitempty"` // nil when the variable was not evaluated before
+	Ready       bool      `json:"ready"`
+	Rules       []string  `json:"rules,omitempty"` // rejection rules raised, warnings included
+}
+
+// ReprocessRequest selects the variables a partial readiness re-run covers
+type ReprocessRequest struct {
+	Variables []string `json:"variables"`
+	Reason    string   `json:"reason,omitempty"` // recorded in the audit, e.g. "contract changed"
+}
+
+// ChangedContractVariables returns the variables whose contract differs between two
+// contract sets, including ones added or dropped, sorted
+func ChangedContractVariables(previous, current []*dataset.VariableContract) []string {
+	before := make(map[string]*dataset.VariableContract, len(previous))
+	for _, contract := range previous {
+		before[string(contract.VarKey)] = contract
+	}
+	changed := []string{}
+	seen := make(map[string]bool, len(current))
+	for _, contract := range current {
+		key := string(contract.VarKey)
+		seen[key] = true
+		if old, ok := before[key]; !ok || !reflect.DeepEqual(old, contract) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range before {
+		if !seen[key] {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// ReprocessVariables re-runs readiness for the requested variables only and merges the
+// outcome into previous, leaving every other variable's evaluation as it was. Only the
+// selected fields are profiled. Selecting a field also re-derives its seasonal components;
+// selecting a component re-runs the field it was derived from. previous is not modified.
+func (o *DataReadinessOrchestrator) ReprocessVariables(ctx context.Context, sourceName string, rawData interface{}, previous ReadinessResult, req ReprocessRequest) (ReadinessResult, error) {
+	startTime := time.Now()
+	if len(req.Variables) == 0 {
+		return ReadinessResult{}, fmt.Errorf("no variables selected for reprocessing")
+	}
+
+	ingestionResult, events, err := o.ingestSource(sourceName, rawData)
+	if err != nil {
+		return ReadinessResult{}, fmt.Errorf("ingestion failed: %w", err)
+	}
+	if ingestionResult.EventsIngested == 0 {
+		return ReadinessResult{}, fmt.Errorf("no events could be ingested from source %s", sourceName)
+	}
+
+	fields := make(map[string]bool, len(req.Variables))
+	for _, key := range req.Variables {
+		fields[seasonalBase(key)] = true
+	}
+	events = selectFields(events, fields)
+
+	var decompositions []SeasonalComponents
+	if o.deps.Seasonality.Enabled {
+		var derived []ingestion.CanonicalEvent
+		derived, decompositions = DecomposeSeasonalFields(events, o.deps.Seasonality)
+		events = append(events, derived...)
+	}
+
+	profilingResult, err := o.deps.Profiler.ProfileSource(ctx, sourceName, events, profiling.DefaultProfilingConfig())
+	if err != nil {
+		return ReadinessResult{}, fmt.Errorf("profiling failed: %w", err)
+	}
+	profiled := make(map[string]bool, len(profilingResult.Profiles))
+	for _, profile := range profilingResult.Profiles {
+		profiled[profile.FieldKey] = true
+	}
+	var missing []string
+	for field := range fields {
+		if !profiled[field] {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return ReadinessResult{}, fmt.Errorf("variables not found in source %s: %s", sourceName, strings.Join(missing, ", "))
+	}
+
+	partial := o.deps.Gate.EvaluateReadiness(profilingResult.Profiles)
+	for i, evaluation := range partial.ReadyVariables {
+		partial.ReadyVariables[i] = o.deps.Gate.ApplyRemediation(evaluation)
+	}
+	partial.Decompositions = decompositions
+
+	merged := previous.MergeReprocessed(partial, fields, req.Reason, startTime)
+	fmt.Printf("Readiness re-run for %s: %d variables reprocessed, %d ready, %d rejected overall (%.2fs)\n",
+		sourceName, len(profilingResult.Profiles), merged.ReadyCount, merged.RejectedCount, time.Since(startTime).Seconds())
+	return merged, nil
+}
+
+// MergeReprocessed returns the result with the evaluations of a partial re-run over the
+// given fields in place of earlier ones, and an audit entry for each re-run variable. The
+// earlier evaluations of the fields' seasonal components are replaced too.
+func (r ReadinessResult) MergeReprocessed(partial ReadinessResult, fields map[string]bool, reason string, at time.Time) ReadinessResult {
+	replaced := func(key string) bool {
+		return fields[seasonalBase(key)]
+	}
+	wasReady := map[string]bool{}
+	merged := ReadinessResult{Audit: append([]ReadinessAuditEntry(nil), r.Audit...)}
+	for _, evaluation := range r.ReadyVariables {
+		if replaced(evaluation.VariableKey) {
+			wasReady[evaluation.VariableKey] = true
+			continue
+		}
+		merged.ReadyVariables = append(merged.ReadyVariables, evaluation)
+	}
+	for _, evaluation := range r.RejectedVariables {
+		if replaced(evaluation.VariableKey) {
+			wasReady[evaluation.VariableKey] = false
+			continue
+		}
+		merged.RejectedVariables = append(merged.RejectedVariables, evaluation)
+	}
+	for _, components := range r.Decompositions {
+		if !fields[components.FieldKey] {
+			merged.Decompositions = append(merged.Decompositions, components)
+		}
+	}
+
+	merged.ReadyVariables = append(merged.ReadyVariables, partial.ReadyVariables...)
+	merged.RejectedVariables = append(merged.RejectedVariables, partial.RejectedVariables...)
+	merged.Decompositions = append(merged.Decompositions, partial.Decompositions...)
+	for _, evaluation := range append(append([]VariableEvaluation(nil), partial.ReadyVariables...), partial.RejectedVariables...) {
+		entry := auditEntry(evaluation, AuditModePartial, reason, at)
+		if ready, ok := wasReady[evaluation.VariableKey]; ok {
+			entry.WasReady = &ready
+		}
+		merged.Audit = append(merged.Audit, entry)
+	}
+
+	merged.ReadyCount = len(merged.ReadyVariables)
+	merged.RejectedCount = len(merged.RejectedVariables)
+	merged.TotalVariables = merged.ReadyCount + merged.RejectedCount
+	return merged
+}
+
+// auditEntry records an evaluation in the audit
+func auditEntry(evaluation VariableEvaluation, mode, reason string, at time.Time) ReadinessAuditEntry {
+	entry := ReadinessAuditEntry{
+		VariableKey: evaluation.VariableKey,
+		Mode:        mode,
+		Reason:      reason,
+		EvaluatedAt: at,
+		Ready:       evaluation.Ready,
+	}
+	for _, rejection := range evaluation.Rejections {
+		entry.Rules = append(entry.Rules, rejection.Rule)
+	}
+	return entry
+}
+
+// selectFields narrows events to the given fields: row events keep only those fields of
+// their payload, cell events are kept only for those fields
+func selectFields(events []ingestion.CanonicalEvent, fields map[string]bool) []ingestion.CanonicalEvent {
+	selected := make([]ingestion.CanonicalEvent, 0, len(events))
+	for _, event := range events {
+		if event.RawPayload == nil {
+			if fields[event.FieldKey] {
+				selected = append(selected, event)
+			}
+			continue
+		}
+		payload := make(map[string]interface{}, len(fields))
+		for field := range fields {
+			if value, ok := event.RawPayload[field]; ok {
+				payload[field] = value
+			}
+		}
+		event.RawPayload = payload
+		selected = append(selected, event)
+	}
+	return selected
+}
+
+// seasonalBase returns the field a seasonal component was derived from, or the key itself
+func seasonalBase(key string) string {
+	for _, suffix := range []string{TrendSuffix, SeasonalSuffix, ResidualSuffix} {
+		if base, ok := strings.CutSuffix(key, suffix); ok && base != "" {
+			return base
+		}
+	}
+	return key
+}
@@ -0,0 +1,102 @@
+package resolution
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"gohypo/adapters/datareadiness"
+	"gohypo/adapters/datareadiness/coercer"
+	"gohypo/adapters/datareadiness/synthesizer"
+	"gohypo/domain/dataset"
+)
+
+func newTestOrchestrator(t *testing.T) *DataReadinessOrchestrator {
+	t.Helper()
+	config := DefaultOrchestratorConfig()
+	config.GateConfig.RequireTimestamps = false
+	config.GateConfig.MinSampleSize = 5
+	typeCoercer := coercer.NewTypeCoercer(config.CoercionConfig)
+	orchestrator, err := NewDataReadinessOrchestrator(ReadinessOrchestratorDeps{
+		Profiler:    datareadiness.NewProfilerAdapter(typeCoercer),
+		Coercer:     typeCoercer,
+		Synthesizer: synthesizer.NewContractSynthesizer(config.SynthesisConfig),
+		Gate:        NewReadinessGate(config.GateConfig),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return orchestrator
+}
+
+// TestReprocessVariables verifies a partial re-run replaces only the selected variables'
+// evaluations and audits how their verdicts moved
+func TestReprocessVariables(t *testing.T) {
+	rows := func(emptyScore bool) []map[string]interface{} {
+		data := make([]map[string]interface{}, 20)
+		for i := range data {
+			var score interface{} = float64(i % 7)
+			if emptyScore {
+				score = nil
+			}
+			data[i] = map[string]interface{}{"spend": float64(i * 3), "score": score}
+		}
+		return data
+	}
+	ctx := context.Background()
+	orchestrator := newTestOrchestrator(t)
+
+	full, err := orchestrator.ProcessSource(ctx, "orders", rows(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if full.ReadyCount != 2 || len(full.Audit) != 2 {
+		t.Fatalf("full run: %d ready, audit %+v", full.ReadyCount, full.Audit)
+	}
+
+	// The score column empties out; only it is re-run
+	partial, err := orchestrator.ReprocessVariables(ctx, "orders", rows(true), full, ReprocessRequest{Variables: []string{"score"}, Reason: "contract changed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partial.ReadyCount != 1 || partial.RejectedCount != 1 || partial.TotalVariables != 2 {
+		t.Fatalf("merged %d ready, %d rejected of %d", partial.ReadyCount, partial.RejectedCount, partial.TotalVariables)
+	}
+	if partial.ReadyVariables[0].VariableKey != "spend" || partial.RejectedVariables[0].VariableKey != "score" {
+		t.Errorf("ready %s, rejected %s", partial.ReadyVariables[0].VariableKey, partial.RejectedVariables[0].VariableKey)
+	}
+	if len(partial.Audit) != 3 {
+		t.Fatalf("audit %+v", partial.Audit)
+	}
+	entry := partial.Audit[2]
+	if entry.VariableKey != "score" || entry.Mode != AuditModePartial || entry.Reason != "contract changed" ||
+		entry.WasReady == nil || !*entry.WasReady || entry.Ready || len(entry.Rules) == 0 {
+		t.Errorf("unexpected audit entry %+v", entry)
+	}
+	if len(full.Audit) != 2 || full.ReadyCount != 2 {
+		t.Error("the previous result was modified")
+	}
+
+	if _, err := orchestrator.ReprocessVariables(ctx, "orders", rows(false), full, ReprocessRequest{Variables: []string{"refunds"}}); err == nil {
+		t.Error("reprocessing a variable missing from the source should fail")
+	}
+}
+
+// TestChangedContractVariables verifies changed, added and dropped contracts are all selected
+func TestChangedContractVariables(t *testing.T) {
+	window := 30
+	previous := []*dataset.VariableContract{
+		{VarKey: "spend", StatisticalType: dataset.TypeNumeric},
+		{VarKey: "region", StatisticalType: dataset.TypeCategorical},
+		{VarKey: "churned", StatisticalType: dataset.TypeBinary},
+	}
+	current := []*dataset.VariableContract{
+		{VarKey: "spend", StatisticalType: dataset.TypeNumeric, WindowDays: &window},
+		{VarKey: "region", StatisticalType: dataset.TypeCategorical},
+		{VarKey: "tenure", StatisticalType: dataset.TypeNumeric},
+	}
+	got := fmt.Sprint(ChangedContractVariables(previous, current))
+	if want := "[churned spend tenure]"; got != want {
+		t.Errorf("changed = %s, want %s", got, want)
+	}
+}
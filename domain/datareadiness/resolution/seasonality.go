@@ -0,0 +1,234 @@
+package resolution
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"gohypo/domain/datareadiness/ingestion"
+	"gohypo/domain/stats"
+)
+
+// Suffixes of the variables derived from a seasonal field
+const (
+	TrendSuffix    = "_trend"
+	SeasonalSuffix = "_seasonal"
+	ResidualSuffix = "_residual"
+)
+
+// SeasonalityConfig controls the decomposition step of the readiness pipeline
+type SeasonalityConfig struct {
+	Enabled     bool    `json:"enabled"`
+	MinStrength float64 `json:"min_strength"` // components are derived only for series at least this seasonal
+	MaxGapRate  float64 `json:"max_gap_rate"` // share of empty periods filled by interpolation before a series is skipped
+}
+
+// DefaultSeasonalityConfig returns sensible defaults
+func DefaultSeasonalityConfig() SeasonalityConfig {
+	return SeasonalityConfig{
+		Enabled:     true,
+		MinStrength: 0.3,
+		MaxGapRate:  0.2,
+	}
+}
+
+// SeasonalComponents records the decomposition of one field, or why it was skipped
+type SeasonalComponents struct {
+	FieldKey string   `json:"field_key"`
+	Grain    string   `json:"grain,omitempty"`
+	Period   int      `json:"period,omitempty"`
+	Periods  int      `json:"periods,omitempty"` // length of the aggregated series
+	Strength float64  `json:"strength"`
+	Derived  []string `json:"derived,omitempty"`
+	Skipped  string   `json:"skipped,omitempty"`
+}
+
+// seasonalGrain is a sampling frequency and the cycle length assumed at that frequency
+type seasonalGrain struct {
+	name   string
+	step   time.Duration
+	period int
+	months bool
+}
+
+var seasonalGrains = []seasonalGrain{
+	{name: "hourly", step: time.Hour, period: 24},
+	{name: "daily", step: 24 * time.Hour, period: 7},
+	{name: "weekly", step: 7 * 24 * time.Hour, period: 52},
+	{name: "monthly", step: 30 * 24 * time.Hour, period: 12, months: true},
+}
+
+// index numbers the grain's periods from origin
+func (g seasonalGrain) index(t, origin time.Time) int {
+	if g.months {
+		return (t.Year()-origin.Year())*12 + int(t.Month()) - int(origin.Month())
+	}
+	return int(t.Truncate(g.step).Sub(origin.Truncate(g.step)) / g.step)
+}
+
+// DecomposeSeasonalFields decomposes every time-indexed numeric field into trend, seasonal
+// and residual components. Each field's events are averaged per period at the grain their
+// spacing suggests; the components are then mapped back onto the original events so the
+// derived variables keep entity-level detail: the residual is the observation with its
+// period's trend and seasonal effect removed. Fields whose seasonal strength is below the
+// configured minimum are reported but not derived.
+func DecomposeSeasonalFields(events []ingestion.CanonicalEvent, config SeasonalityConfig) ([]ingestion.CanonicalEvent, []SeasonalComponents) {
+	byField := make(map[string][]ingestion.CanonicalEvent)
+	var fields []string
+	for _, event := range events {
+		if event.Value.IsMissing || event.Value.NumericVal == nil || event.ObservedAt.IsZero() {
+			continue
+		}
+		if _, seen := byField[event.FieldKey]; !seen {
+			fields = append(fields, event.FieldKey)
+		}
+		byField[event.FieldKey] = append(byField[event.FieldKey], event)
+	}
+	existing := make(map[string]bool)
+	for _, event := range events {
+		existing[event.FieldKey] = true
+	}
+	sort.Strings(fields)
+
+	var derived []ingestion.CanonicalEvent
+	var decompositions []SeasonalComponents
+	for _, field := range fields {
+		components, fieldEvents := decomposeField(field, byField[field], config, existing)
+		decompositions = append(decompositions, components)
+		derived = append(derived, fieldEvents...)
+	}
+	return derived, decompositions
+}
+
+func decomposeField(field string, events []ingestion.CanonicalEvent, config SeasonalityConfig, existing map[string]bool) (SeasonalComponents, []ingestion.CanonicalEvent) {
+	components := SeasonalComponents{FieldKey: field}
+	grain, ok := detectGrain(events)
+	if !ok {
+		components.Skipped = "observations are not regularly spaced"
+		return components, nil
+	}
+	components.Grain, components.Period = grain.name, grain.period
+
+	origin := events[0].ObservedAt.Time()
+	for _, event := range events {
+		if t := event.ObservedAt.Time(); t.Before(origin) {
+			origin = t
+		}
+	}
+	sums := map[int]float64{}
+	counts := map[int]int{}
+	last := 0
+	for _, event := range events {
+		i := grain.index(event.ObservedAt.Time(), origin)
+		sums[i] += *event.Value.NumericVal
+		counts[i]++
+		if i > last {
+			last = i
+		}
+	}
+	series := make([]float64, last+1)
+	gaps := 0
+	for i := range series {
+		if counts[i] == 0 {
+			series[i] = math.NaN()
+			gaps++
+			continue
+		}
+		series[i] = sums[i] / float64(counts[i])
+	}
+	components.Periods = len(series)
+	if float64(gaps) > config.MaxGapRate*float64(len(series)) {
+		components.Skipped = fmt.Sprintf("%d of %d %s periods have no observations", gaps, len(series), grain.name)
+		return components, nil
+	}
+	interpolateGaps(series)
+
+	decomposition, err := stats.DecomposeSeasonal(series, grain.period)
+	if err != nil {
+		components.Skipped = err.Error()
+		return components, nil
+	}
+	components.Strength = decomposition.Strength
+	if decomposition.Strength < config.MinStrength {
+		components.Skipped = fmt.Sprintf("seasonal strength %.2f is below %.2f", decomposition.Strength, config.MinStrength)
+		return components, nil
+	}
+	for _, suffix := range []string{TrendSuffix, SeasonalSuffix, ResidualSuffix} {
+		if existing[field+suffix] {
+			components.Skipped = fmt.Sprintf("field %s already exists", field+suffix)
+			return components, nil
+		}
+	}
+
+	derived := make([]ingestion.CanonicalEvent, 0, 3*len(events))
+	for _, event := range events {
+		i := grain.index(event.ObservedAt.Time(), origin)
+		trend, seasonal := decomposition.Trend[i], decomposition.Seasonal[i]
+		residual := *event.Value.NumericVal - trend - seasonal
+		for _, c := range []struct {
+			suffix string
+			value  float64
+		}{{TrendSuffix, trend}, {SeasonalSuffix, seasonal}, {ResidualSuffix, residual}} {
+			value := c.value
+			derived = append(derived, ingestion.CanonicalEvent{
+				EntityID:   event.EntityID,
+				ObservedAt: event.ObservedAt,
+				Source:     event.Source,
+				FieldKey:   field + c.suffix,
+				Value:      ingestion.Value{Type: ingestion.ValueTypeNumeric, NumericVal: &value},
+			})
+		}
+	}
+	components.Derived = []string{field + TrendSuffix, field + SeasonalSuffix, field + ResidualSuffix}
+	return components, derived
+}
+
+// detectGrain picks the grain closest to the median spacing of the distinct timestamps
+func detectGrain(events []ingestion.CanonicalEvent) (seasonalGrain, bool) {
+	seen := make(map[int64]bool)
+	var times []time.Time
+	for _, event := range events {
+		t := event.ObservedAt.Time()
+		if !seen[t.UnixNano()] {
+			seen[t.UnixNano()] = true
+			times = append(times, t)
+		}
+	}
+	if len(times) < 3 {
+		return seasonalGrain{}, false
+	}
+	sort.Slice(times, func(a, b int) bool { return times[a].Before(times[b]) })
+	gaps := make([]time.Duration, len(times)-1)
+	for i := 1; i < len(times); i++ {
+		gaps[i-1] = times[i].Sub(times[i-1])
+	}
+	sort.Slice(gaps, func(a, b int) bool { return gaps[a] < gaps[b] })
+	median := gaps[len(gaps)/2]
+	for _, grain := range seasonalGrains {
+		ratio := float64(median) / float64(grain.step)
+		if ratio >= 0.8 && ratio <= 1.25 {
+			return grain, true
+		}
+	}
+	return seasonalGrain{}, false
+}
+
+// interpolateGaps fills NaN runs linearly between their neighbours; the series' first and
+// last values are observed because they bound the period index
+func interpolateGaps(series []float64) {
+	for i := 0; i < len(series); i++ {
+		if !math.IsNaN(series[i]) {
+			continue
+		}
+		j := i
+		for j < len(series) && math.IsNaN(series[j]) {
+			j++
+		}
+		left, right := series[i-1], series[j]
+		for k := i; k < j; k++ {
+			series[k] = left + (right-left)*float64(k-i+1)/float64(j-i+1)
+		}
+		i = j
+	}
+}
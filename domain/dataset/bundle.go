@@ -1,7 +1,10 @@
 package dataset
 
 import (
+	"encoding/binary"
 	"fmt"
+	"math"
+	"strings"
 
 	"gohypo/domain/core"
 )
@@ -24,6 +27,7 @@ type MatrixBundle struct {
 	// Metadata
 	CutoffAt  core.CutoffAt
 	Lag       core.Lag
+	TimeZone  core.TimeZoneDecision // zone the cutoff and the data's timestamps were read in
 	CreatedAt core.Timestamp
 
 	// Fingerprint for replayability
@@ -35,6 +39,8 @@ type Matrix struct {
 	Data         [][]float64        // rows=entities, cols=variables
 	EntityIDs    []core.ID          // entity identifiers
 	VariableKeys []core.VariableKey // column variable keys
+
+	columns *ColumnStore // column-major copy of Data, see MatrixBundle.Columnarize
 }
 
 // ColumnMeta contains metadata for each matrix column
@@ -43,6 +49,16 @@ type ColumnMeta struct {
 	StatisticalType StatisticalType
 	DerivedColumns  []DerivedColumn // missing indicators, etc.
 	ResolutionAudit ResolutionAudit
+	Origin          *ColumnOrigin // dataset a workspace sweep joined the column from; nil for single-dataset matrices
+}
+
+// ColumnOrigin is the provenance of a column of a matrix combined from several datasets
+type ColumnOrigin struct {
+	DatasetID   core.ID `json:"dataset_id"`
+	DatasetName string  `json:"dataset_name"`
+	Column      string  `json:"column"`               // the column's key in its dataset
+	JoinedOn    string  `json:"joined_on,omitempty"`  // how its dataset was joined; empty for the base dataset
+	Aggregated  bool    `json:"aggregated,omitempty"` // several rows of its dataset matched one row
 }
 
 // DerivedColumn represents computed columns (e.g., missing indicators)
@@ -61,6 +77,8 @@ type ResolutionAudit struct {
 	ScalarGuarantee   bool
 	AsOfMode          AsOfMode
 	WindowDays        *int
+	LagDays           *int
+	DayCount          DayCount
 	ResolutionErrors  []string
 }
 
@@ -80,9 +98,13 @@ type VariableContract struct {
 	AsOfMode            AsOfMode           `json:"as_of_mode"`
 	StatisticalType     StatisticalType    `json:"statistical_type"`
 	WindowDays          *int               `json:"window_days,omitempty"`
+	LagDays             *int               `json:"lag_days,omitempty"`  // days the data arrives late; resolved as of the cutoff less these
+	DayCount            DayCount           `json:"day_count,omitempty"` // how WindowDays and LagDays count: calendar (default) or business
+	Calendar            *BusinessCalendar  `json:"calendar,omitempty"`  // business days of a business day count
 	ImputationPolicy    ImputationPolicy   `json:"imputation_policy"`
 	ScalarGuarantee     bool               `json:"scalar_guarantee"`
 	CategoricalEncoding map[string]float64 `json:"categorical_encoding,omitempty"` // For categorical variables: value -> numeric encoding
+	OrdinalLevels       []string           `json:"ordinal_levels,omitempty"`       // For ordinal variables: levels lowest first, encoded by rank
 }
 
 // StatisticalType defines variable types for analysis
@@ -91,6 +113,7 @@ type StatisticalType string
 const (
 	TypeNumeric     StatisticalType = "numeric"
 	TypeCategorical StatisticalType = "categorical"
+	TypeOrdinal     StatisticalType = "ordinal"
 	TypeBinary      StatisticalType = "binary"
 	TypeTimestamp   StatisticalType = "timestamp"
 )
@@ -106,6 +129,7 @@ func NewMatrixBundle(snapshotID core.SnapshotID, viewID core.ID, cohortHash core
 		CohortHash: cohortHash,
 		CutoffAt:   cutoff,
 		Lag:        lag,
+		TimeZone:   core.UTCZone().Decision(cutoff),
 		CreatedAt:  core.Now(),
 	}
 }
@@ -126,12 +150,29 @@ func (b *MatrixBundle) AddColumn(varKey core.VariableKey, values []float64, meta
 		b.Matrix.Data[i] = append(b.Matrix.Data[i], value)
 	}
 
-	// Add metadata
+	// Add metadata; the column store no longer matches
+	b.Matrix.columns = nil
 	b.Matrix.VariableKeys = append(b.Matrix.VariableKeys, varKey)
 	b.ColumnMeta = append(b.ColumnMeta, meta)
 	b.Audits = append(b.Audits, audit)
 }
 
+// ColumnOrigins returns the provenance of each column of a matrix combined from several
+// datasets, or nil for a single-dataset matrix
+func (b *MatrixBundle) ColumnOrigins() map[core.VariableKey]ColumnOrigin {
+	var origins map[core.VariableKey]ColumnOrigin
+	for _, meta := range b.ColumnMeta {
+		if meta.Origin == nil {
+			continue
+		}
+		if origins == nil {
+			origins = make(map[core.VariableKey]ColumnOrigin)
+		}
+		origins[meta.VariableKey] = *meta.Origin
+	}
+	return origins
+}
+
 // Validate ensures the bundle is internally consistent
 func (b *MatrixBundle) Validate() error {
 	if len(b.Matrix.Data) == 0 {
@@ -169,12 +210,16 @@ func (b *MatrixBundle) GetColumn(varKey core.VariableKey) (int, bool) {
 	return -1, false
 }
 
-// GetColumnData returns the data for a specific column
+// GetColumnData returns a copy of the data for a specific column, which the caller may
+// modify; ColumnView avoids the copy for read-only use
 func (b *MatrixBundle) GetColumnData(varKey core.VariableKey) ([]float64, bool) {
 	colIdx, found := b.GetColumn(varKey)
 	if !found {
 		return nil, false
 	}
+	if b.Columnar() {
+		return append([]float64(nil), b.Matrix.columns.Column(colIdx)...), true
+	}
 
 	data := make([]float64, len(b.Matrix.Data))
 	for i, row := range b.Matrix.Data {
@@ -193,3 +238,143 @@ func (b *MatrixBundle) RowCount() int {
 func (b *MatrixBundle) ColumnCount() int {
 	return len(b.Matrix.VariableKeys)
 }
+
+// ColumnFingerprints hashes each column's values together with the entity order, so a
+// column's fingerprint changes when either its data or the row set changes
+func (b *MatrixBundle) ColumnFingerprints() map[core.VariableKey]core.Hash {
+	entityPart := make([]byte, 0, len(b.Matrix.EntityIDs)*16)
+	for _, id := range b.Matrix.EntityIDs {
+		entityPart = append(entityPart, id...)
+		entityPart = append(entityPart, 0)
+	}
+
+	fingerprints := make(map[core.VariableKey]core.Hash, len(b.Matrix.VariableKeys))
+	for col, key := range b.Matrix.VariableKeys {
+		buf := make([]byte, 0, len(entityPart)+len(b.Matrix.Data)*8)
+		buf = append(buf, entityPart...)
+		for _, row := range b.Matrix.Data {
+			value := math.NaN()
+			if col < len(row) {
+				value = row[col]
+			}
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(value))
+		}
+		fingerprints[key] = core.NewHash(buf)
+	}
+	return fingerprints
+}
+
+// timeIndexNames are column names taken to be a time index when no column is typed as a
+// timestamp
+var timeIndexNames = []string{"timestamp", "date", "datetime", "time", "period", "week", "month", "year", "ds"}
+
+// TimeIndex returns the column that orders the rows in time: the first column typed as a
+// timestamp, otherwise a column named like one whose values are distinct, so each row is one
+// point in time. Panel data with repeated timestamps has no single time index.
+func (b *MatrixBundle) TimeIndex() (core.VariableKey, bool) {
+	for _, meta := range b.ColumnMeta {
+		if meta.StatisticalType == TypeTimestamp {
+			if _, ok := b.GetColumn(meta.VariableKey); ok {
+				return meta.VariableKey, true
+			}
+		}
+	}
+	for _, key := range b.Matrix.VariableKeys {
+		if !namedLike(string(key), timeIndexNames) {
+			continue
+		}
+		values, _ := b.GetColumnData(key)
+		seen := make(map[float64]bool, len(values))
+		distinct := true
+		for _, v := range values {
+			if math.IsNaN(v) || seen[v] {
+				distinct = false
+				break
+			}
+			seen[v] = true
+		}
+		if distinct && len(values) > 0 {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// entityIndexNames are column names taken to identify the entity a panel row observes;
+// columns ending in _id qualify too
+var entityIndexNames = []string{"id", "entity", "subject", "unit", "panel"}
+
+// PanelIndex returns the entity and time columns of panel data, where each row is one entity
+// observed at one time: an entity column whose values repeat across rows, and a timestamp or
+// time-named column whose values repeat across entities, with no entity observed twice at the
+// same time.
+func (b *MatrixBundle) PanelIndex() (entity, time core.VariableKey, ok bool) {
+	var times []core.VariableKey
+	for _, meta := range b.ColumnMeta {
+		if meta.StatisticalType == TypeTimestamp {
+			times = append(times, meta.VariableKey)
+		}
+	}
+	for _, key := range b.Matrix.VariableKeys {
+		if namedLike(string(key), timeIndexNames) {
+			times = append(times, key)
+		}
+	}
+	for _, candidate := range b.Matrix.VariableKeys {
+		name := strings.ToLower(string(candidate))
+		if !strings.HasSuffix(name, "_id") && !namedLike(name, entityIndexNames) {
+			continue
+		}
+		entities, _ := b.GetColumnData(candidate)
+		distinct := make(map[float64]bool)
+		for _, v := range entities {
+			if !math.IsNaN(v) {
+				distinct[v] = true
+			}
+		}
+		if len(distinct) < 2 || 2*len(distinct) > len(entities) {
+			continue
+		}
+		for _, timeKey := range times {
+			if timeKey != candidate && b.observesOncePerTime(entities, timeKey) {
+				return candidate, timeKey, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// observesOncePerTime reports whether the time column repeats across rows while no entity
+// appears twice at the same time
+func (b *MatrixBundle) observesOncePerTime(entities []float64, timeKey core.VariableKey) bool {
+	times, ok := b.GetColumnData(timeKey)
+	if !ok {
+		return false
+	}
+	seen := make(map[[2]float64]bool, len(times))
+	distinctTimes := make(map[float64]bool)
+	for i, t := range times {
+		if math.IsNaN(t) || math.IsNaN(entities[i]) {
+			continue
+		}
+		key := [2]float64{entities[i], t}
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+		distinctTimes[t] = true
+	}
+	return len(distinctTimes) > 0 && len(distinctTimes) < len(seen)
+}
+
+// namedLike reports whether a column name is one of the candidates, alone or as a prefix or
+// suffix joined by an underscore
+func namedLike(name string, candidates []string) bool {
+	name = strings.ToLower(name)
+	for _, candidate := range candidates {
+		if name == candidate || strings.HasSuffix(name, "_"+candidate) || strings.HasPrefix(name, candidate+"_") {
+			return true
+		}
+	}
+	return false
+}
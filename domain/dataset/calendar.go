@@ -0,0 +1,175 @@
+package dataset
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gohypo/domain/core"
+)
+
+// BusinessCalendar defines business day and holiday rules
+type BusinessCalendar struct {
+	IncludeWeekends bool      `json:"include_weekends"`         // Whether to include weekend data
+	Holidays        []Holiday `json:"holidays,omitempty"`       // List of holidays
+	BusinessHours   TimeRange `json:"business_hours,omitempty"` // Business hours for filtering
+}
+
+// Holiday represents a holiday or special date
+type Holiday struct {
+	Date      time.Time `json:"date"`
+	Name      string    `json:"name,omitempty"`
+	IsHalfDay bool      `json:"is_half_day,omitempty"`
+}
+
+// TimeRange represents a time range
+type TimeRange struct {
+	Start time.Duration `json:"start"` // Duration from midnight
+	End   time.Duration `json:"end"`   // Duration from midnight
+}
+
+// IsBusinessDay reports whether t falls on a business day: not a weekend, unless the
+// calendar includes weekends, and not a holiday. The day is t's date in its own location.
+func (c *BusinessCalendar) IsBusinessDay(t time.Time) bool {
+	if !c.IncludeWeekends {
+		if weekday := t.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+			return false
+		}
+	}
+	for _, holiday := range c.Holidays {
+		if t.Year() == holiday.Date.Year() && t.Month() == holiday.Date.Month() && t.Day() == holiday.Date.Day() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsBusinessTime reports whether t falls on a business day and, when the calendar sets
+// business hours, within them
+func (c *BusinessCalendar) IsBusinessTime(t time.Time) bool {
+	if !c.IsBusinessDay(t) {
+		return false
+	}
+	if c.BusinessHours.Start != 0 || c.BusinessHours.End != 0 {
+		sinceMidnight := time.Duration(t.Hour())*time.Hour +
+			time.Duration(t.Minute())*time.Minute +
+			time.Duration(t.Second())*time.Second
+		if sinceMidnight < c.BusinessHours.Start || sinceMidnight > c.BusinessHours.End {
+			return false
+		}
+	}
+	return true
+}
+
+// AddBusinessDays moves t by n business days, backwards when n is negative, keeping its
+// time of day. Days are stepped on t's own calendar, so pass t in the dataset's zone.
+func (c *BusinessCalendar) AddBusinessDays(t time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	// A calendar without business days would never get there; a year of misses ends it
+	for misses := 0; n > 0 && misses < 366; {
+		t = t.AddDate(0, 0, step)
+		if c.IsBusinessDay(t) {
+			n--
+			misses = 0
+		} else {
+			misses++
+		}
+	}
+	return t
+}
+
+// Hash identifies the calendar's rules, so contracts counting business days change the
+// registry hash when their calendar does. Holiday names are left out; their dates are sorted.
+func (c *BusinessCalendar) Hash() core.Hash {
+	dates := make([]string, len(c.Holidays))
+	for i, holiday := range c.Holidays {
+		dates[i] = holiday.Date.Format("2006-01-02")
+		if holiday.IsHalfDay {
+			dates[i] += "/half"
+		}
+	}
+	sort.Strings(dates)
+	data := fmt.Sprintf("weekends=%t;hours=%s-%s;holidays=%s",
+		c.IncludeWeekends, c.BusinessHours.Start, c.BusinessHours.End, strings.Join(dates, ","))
+	return core.NewHash([]byte(data))
+}
+
+// DayCount is how a contract counts the days of its window and lag
+type DayCount string
+
+const (
+	DayCountCalendar DayCount = "calendar" // every day counts; the default
+	DayCountBusiness DayCount = "business" // only business days of the contract's calendar count
+)
+
+// ParseDayCount validates a day count; empty is the calendar default
+func ParseDayCount(s string) (DayCount, error) {
+	switch DayCount(s) {
+	case "", DayCountCalendar:
+		return DayCountCalendar, nil
+	case DayCountBusiness:
+		return DayCountBusiness, nil
+	}
+	return "", fmt.Errorf("unknown day count %q (use calendar or business)", s)
+}
+
+// DayCounter steps days the way a contract counts them
+type DayCounter struct {
+	Count    DayCount
+	Calendar *BusinessCalendar // required for business days
+}
+
+// Validate checks that a business day count has a calendar to count in
+func (d DayCounter) Validate() error {
+	if _, err := ParseDayCount(string(d.Count)); err != nil {
+		return err
+	}
+	if d.Count == DayCountBusiness && d.Calendar == nil {
+		return fmt.Errorf("a business day count needs a calendar")
+	}
+	return nil
+}
+
+// Back moves t back by days, keeping its time of day
+func (d DayCounter) Back(t time.Time, days int) time.Time {
+	if d.Count == DayCountBusiness && d.Calendar != nil {
+		return d.Calendar.AddBusinessDays(t, -days)
+	}
+	return t.AddDate(0, 0, -days)
+}
+
+// Fingerprint identifies the day count for the registry hash, with the calendar's hash
+// when business days are counted
+func (d DayCounter) Fingerprint() string {
+	if d.Count == DayCountBusiness && d.Calendar != nil {
+		return fmt.Sprintf("%s@%s", DayCountBusiness, d.Calendar.Hash())
+	}
+	return string(DayCountCalendar)
+}
+
+// Days returns how the contract counts days
+func (c *VariableContract) Days() DayCounter {
+	return DayCounter{Count: c.DayCount, Calendar: c.Calendar}
+}
+
+// AsOf is the time the variable is resolved as of: the cutoff less the contract's lag.
+// Pass the cutoff in the dataset's zone so days are stepped on its calendar.
+func (c *VariableContract) AsOf(cutoff time.Time) time.Time {
+	if c.LagDays == nil || *c.LagDays <= 0 {
+		return cutoff
+	}
+	return c.Days().Back(cutoff, *c.LagDays)
+}
+
+// WindowStart is the start of a windowed variable's window ending at asOf, or asOf when
+// the contract sets no window
+func (c *VariableContract) WindowStart(asOf time.Time) time.Time {
+	if c.WindowDays == nil {
+		return asOf
+	}
+	return c.Days().Back(asOf, *c.WindowDays)
+}
@@ -0,0 +1,83 @@
+package dataset
+
+import (
+	"testing"
+	"time"
+)
+
+func ukCalendar() *BusinessCalendar {
+	return &BusinessCalendar{Holidays: []Holiday{
+		{Date: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC), Name: "Christmas Day"},
+		{Date: time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC), Name: "Boxing Day"},
+	}}
+}
+
+func TestAddBusinessDaysSkipsWeekendsAndHolidays(t *testing.T) {
+	calendar := ukCalendar()
+	friday := time.Date(2024, 12, 27, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		days int
+		want time.Time
+	}{
+		{-1, time.Date(2024, 12, 24, 9, 30, 0, 0, time.UTC)}, // over Boxing Day and Christmas
+		{-3, time.Date(2024, 12, 20, 9, 30, 0, 0, time.UTC)}, // and the weekend before
+		{1, time.Date(2024, 12, 30, 9, 30, 0, 0, time.UTC)},  // Monday
+		{0, friday},
+	}
+	for _, tt := range tests {
+		if got := calendar.AddBusinessDays(friday, tt.days); !got.Equal(tt.want) {
+			t.Errorf("AddBusinessDays(%d) = %v, want %v", tt.days, got, tt.want)
+		}
+	}
+
+	// A calendar without business days gives up rather than loop forever
+	closed := &BusinessCalendar{}
+	for d := 1; d <= 400; d++ {
+		closed.Holidays = append(closed.Holidays, Holiday{Date: friday.AddDate(0, 0, -d)})
+	}
+	closed.AddBusinessDays(friday, -1)
+}
+
+func TestContractWindowAndLagCountBusinessDays(t *testing.T) {
+	window, lag := 5, 1
+	contract := &VariableContract{
+		AsOfMode:   AsOfSumWindow,
+		WindowDays: &window,
+		LagDays:    &lag,
+		DayCount:   DayCountBusiness,
+		Calendar:   ukCalendar(),
+	}
+	cutoff := time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC) // Monday
+
+	asOf := contract.AsOf(cutoff)
+	if want := time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC); !asOf.Equal(want) {
+		t.Errorf("AsOf = %v, want the Friday before", asOf)
+	}
+	if start, want := contract.WindowStart(asOf), time.Date(2024, 12, 18, 0, 0, 0, 0, time.UTC); !start.Equal(want) {
+		t.Errorf("WindowStart = %v, want %v", start, want)
+	}
+
+	contract.DayCount = DayCountCalendar
+	if start, want := contract.WindowStart(cutoff), time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC); !start.Equal(want) {
+		t.Errorf("calendar WindowStart = %v, want %v", start, want)
+	}
+
+	if err := (DayCounter{Count: DayCountBusiness}).Validate(); err == nil {
+		t.Error("expected a business day count without a calendar to be rejected")
+	}
+}
+
+func TestBusinessCalendarHash(t *testing.T) {
+	a := ukCalendar()
+	b := ukCalendar()
+	b.Holidays[0], b.Holidays[1] = b.Holidays[1], b.Holidays[0]
+	b.Holidays[0].Name = "renamed"
+	if a.Hash() != b.Hash() {
+		t.Error("holiday order and names should not change the hash")
+	}
+	b.Holidays = b.Holidays[:1]
+	if a.Hash() == b.Hash() {
+		t.Error("removing a holiday should change the hash")
+	}
+}
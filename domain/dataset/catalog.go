@@ -0,0 +1,210 @@
+package dataset
+
+import (
+	"sort"
+	"time"
+
+	"gohypo/domain/core"
+)
+
+// Catalog lists every dataset of a workspace with its fields, how they relate to the other
+// datasets and which hypotheses use them
+type Catalog struct {
+	WorkspaceID core.ID          `json:"workspace_id"`
+	Datasets    []CatalogDataset `json:"datasets"`
+	GeneratedAt time.Time        `json:"generated_at"`
+}
+
+// CatalogDataset is one dataset's catalog entry
+type CatalogDataset struct {
+	ID           core.ID           `json:"id"`
+	Name         string            `json:"name"`
+	Domain       string            `json:"domain,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	Status       DatasetStatus     `json:"status"`
+	RecordCount  int               `json:"record_count"`
+	QualityScore float64           `json:"quality_score"` // mean of its fields' scores, 0..1
+	Fields       []CatalogField    `json:"fields"`
+	Relations    []CatalogRelation `json:"relations"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// CatalogField is one field of a catalogued dataset
+type CatalogField struct {
+	Name         string                 `json:"name"`
+	DisplayName  string                 `json:"display_name"` // header the name was normalized from
+	Unit         string                 `json:"unit,omitempty"`
+	DataType     string                 `json:"data_type"`
+	MissingRate  float64                `json:"missing_rate"`
+	UniqueCount  int                    `json:"unique_count"`
+	QualityScore float64                `json:"quality_score"`
+	PII          PIIKind                `json:"pii,omitempty"`
+	Excluded     bool                   `json:"excluded,omitempty"` // kept out of matrices as PII
+	Hypotheses   []CatalogHypothesisRef `json:"hypotheses"`
+	RelatedVia   []core.ID              `json:"related_via,omitempty"` // datasets sharing this field
+}
+
+// CatalogRelation is a discovered relationship from a dataset to another
+type CatalogRelation struct {
+	DatasetID    core.ID   `json:"dataset_id"`
+	DatasetName  string    `json:"dataset_name"`
+	RelationType string    `json:"relation_type"`
+	Confidence   float64   `json:"confidence"`
+	CommonFields []string  `json:"common_fields,omitempty"`
+	DiscoveredAt time.Time `json:"discovered_at"`
+}
+
+// CatalogHypothesisRef is a hypothesis that uses a field as its cause or effect
+type CatalogHypothesisRef struct {
+	HypothesisID string `json:"hypothesis_id"`
+	Title        string `json:"title"`
+	Role         string `json:"role"` // "cause" or "effect"
+	Passed       bool   `json:"passed"`
+}
+
+// FieldReference is a hypothesis' use of a variable, as read from its execution metadata
+type FieldReference struct {
+	HypothesisID string
+	Title        string
+	Passed       bool
+	CauseKey     string
+	EffectKey    string
+}
+
+// FieldQualityScore rates a field from 0 to 1: the share of values present, halved for a
+// field holding a single distinct value, which no analysis can use
+func FieldQualityScore(field FieldInfo, recordCount int) float64 {
+	if recordCount <= 0 {
+		return 0
+	}
+	score := 1 - float64(field.MissingCount)/float64(recordCount)
+	if score < 0 {
+		score = 0
+	}
+	if field.UniqueCount == 1 {
+		score /= 2
+	}
+	return score
+}
+
+// BuildCatalog assembles a workspace catalog from its datasets, their relations and the
+// hypotheses referencing their fields. Datasets are listed by name.
+func BuildCatalog(workspaceID core.ID, datasets []*Dataset, relations []*DatasetRelation, references []FieldReference) *Catalog {
+	catalog := &Catalog{WorkspaceID: workspaceID, Datasets: make([]CatalogDataset, 0, len(datasets)), GeneratedAt: time.Now()}
+
+	names := make(map[core.ID]string, len(datasets))
+	for _, ds := range datasets {
+		names[ds.ID] = datasetName(ds)
+	}
+
+	uses := make(map[string][]CatalogHypothesisRef)
+	for _, ref := range references {
+		if ref.CauseKey != "" {
+			uses[ref.CauseKey] = append(uses[ref.CauseKey], CatalogHypothesisRef{ref.HypothesisID, ref.Title, "cause", ref.Passed})
+		}
+		if ref.EffectKey != "" && ref.EffectKey != ref.CauseKey {
+			uses[ref.EffectKey] = append(uses[ref.EffectKey], CatalogHypothesisRef{ref.HypothesisID, ref.Title, "effect", ref.Passed})
+		}
+	}
+
+	for _, ds := range datasets {
+		entry := CatalogDataset{
+			ID:          ds.ID,
+			Name:        names[ds.ID],
+			Domain:      ds.Domain,
+			Description: ds.Description,
+			Status:      ds.Status,
+			RecordCount: ds.RecordCount,
+			Fields:      make([]CatalogField, 0, len(ds.Metadata.Fields)),
+			Relations:   []CatalogRelation{},
+			UpdatedAt:   ds.UpdatedAt,
+		}
+
+		sharedWith := make(map[string][]core.ID)
+		for _, rel := range relations {
+			other := rel.TargetDatasetID
+			switch ds.ID {
+			case rel.SourceDatasetID:
+			case rel.TargetDatasetID:
+				other = rel.SourceDatasetID
+			default:
+				continue
+			}
+			common := relationCommonFields(rel)
+			entry.Relations = append(entry.Relations, CatalogRelation{
+				DatasetID:    other,
+				DatasetName:  names[other],
+				RelationType: rel.RelationType,
+				Confidence:   rel.Confidence,
+				CommonFields: common,
+				DiscoveredAt: rel.DiscoveredAt,
+			})
+			for _, field := range common {
+				sharedWith[field] = append(sharedWith[field], other)
+			}
+		}
+
+		total := 0.0
+		for _, field := range ds.Metadata.Fields {
+			catalogField := CatalogField{
+				Name:         field.Name,
+				DisplayName:  field.DisplayName(),
+				Unit:         field.Unit,
+				DataType:     field.DataType,
+				UniqueCount:  field.UniqueCount,
+				QualityScore: FieldQualityScore(field, ds.RecordCount),
+				Hypotheses:   uses[field.Name],
+				RelatedVia:   sharedWith[field.Name],
+			}
+			if ds.RecordCount > 0 {
+				catalogField.MissingRate = float64(field.MissingCount) / float64(ds.RecordCount)
+			}
+			if catalogField.Hypotheses == nil {
+				catalogField.Hypotheses = []CatalogHypothesisRef{}
+			}
+			if ds.Metadata.PII != nil {
+				if column := ds.Metadata.PII.Column(field.Name); column != nil {
+					catalogField.PII = column.Kind
+					catalogField.Excluded = column.Excluded
+				}
+			}
+			total += catalogField.QualityScore
+			entry.Fields = append(entry.Fields, catalogField)
+		}
+		if len(entry.Fields) > 0 {
+			entry.QualityScore = total / float64(len(entry.Fields))
+		}
+		catalog.Datasets = append(catalog.Datasets, entry)
+	}
+
+	sort.SliceStable(catalog.Datasets, func(i, j int) bool {
+		return catalog.Datasets[i].Name < catalog.Datasets[j].Name
+	})
+	return catalog
+}
+
+// datasetName is the name a dataset is listed under: its generated name, else its file name
+func datasetName(ds *Dataset) string {
+	if ds.DisplayName != "" {
+		return ds.DisplayName
+	}
+	return ds.OriginalFilename
+}
+
+// relationCommonFields reads the fields a relation was discovered through from its metadata,
+// which may have been decoded from JSON
+func relationCommonFields(rel *DatasetRelation) []string {
+	switch fields := rel.Metadata["common_fields"].(type) {
+	case []string:
+		return fields
+	case []interface{}:
+		var out []string
+		for _, field := range fields {
+			if name, ok := field.(string); ok {
+				out = append(out, name)
+			}
+		}
+		return out
+	}
+	return nil
+}
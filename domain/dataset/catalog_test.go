@@ -0,0 +1,44 @@
+package dataset
+
+import "testing"
+
+// TestBuildCatalog verifies fields carry their quality, PII flag, the hypotheses naming them
+// and the datasets they are shared with, from both ends of a relation
+func TestBuildCatalog(t *testing.T) {
+	orders := &Dataset{ID: "orders", DisplayName: "orders", RecordCount: 100, Metadata: DatasetMetadata{
+		Fields: []FieldInfo{
+			{Name: "customer_id", DataType: "categorical", UniqueCount: 80},
+			{Name: "basket_size", DataType: "numeric", UniqueCount: 40, MissingCount: 20},
+			{Name: "email", DataType: "text", UniqueCount: 80},
+		},
+		PII: &PIIReport{Columns: []PIIColumn{{Field: "email", Kind: PIIEmail, Excluded: true}}},
+	}}
+	customers := &Dataset{ID: "customers", OriginalFilename: "customers.csv", RecordCount: 50, Metadata: DatasetMetadata{
+		Fields: []FieldInfo{{Name: "customer_id", UniqueCount: 50}, {Name: "country", UniqueCount: 1}},
+	}}
+	relations := []*DatasetRelation{{
+		SourceDatasetID: "orders", TargetDatasetID: "customers", RelationType: "entity_link", Confidence: 0.9,
+		Metadata: map[string]interface{}{"common_fields": []interface{}{"customer_id"}},
+	}}
+	references := []FieldReference{{HypothesisID: "h1", Title: "Country drives basket size", Passed: true, CauseKey: "country", EffectKey: "basket_size"}}
+
+	catalog := BuildCatalog("ws", []*Dataset{orders, customers}, relations, references)
+	if len(catalog.Datasets) != 2 || catalog.Datasets[0].Name != "customers.csv" {
+		t.Fatalf("expected datasets sorted by name, got %+v", catalog.Datasets)
+	}
+	customersEntry, ordersEntry := catalog.Datasets[0], catalog.Datasets[1]
+
+	if basket := ordersEntry.Fields[1]; basket.QualityScore != 0.8 || len(basket.Hypotheses) != 1 || basket.Hypotheses[0].Role != "effect" {
+		t.Errorf("unexpected basket_size entry: %+v", basket)
+	}
+	if email := ordersEntry.Fields[2]; email.PII != PIIEmail || !email.Excluded {
+		t.Errorf("expected email to be flagged and excluded, got %+v", email)
+	}
+	if country := customersEntry.Fields[1]; country.QualityScore != 0.5 || len(country.Hypotheses) != 1 {
+		t.Errorf("expected a constant field at half quality used as a cause, got %+v", country)
+	}
+	if len(customersEntry.Relations) != 1 || customersEntry.Relations[0].DatasetName != "orders" ||
+		len(customersEntry.Fields[0].RelatedVia) != 1 || customersEntry.Fields[0].RelatedVia[0] != "orders" {
+		t.Errorf("expected customers to relate to orders via customer_id, got %+v", customersEntry)
+	}
+}
@@ -0,0 +1,165 @@
+package dataset
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ColumnName maps a normalized column key to the header it was read from. Keys are what
+// matrices, contracts and artifacts use; the original header is what people read.
+type ColumnName struct {
+	Key      string `json:"key"`
+	Original string `json:"original"`
+	Unit     string `json:"unit,omitempty"` // stripped from the header, e.g. "%" of "Conv. Rate (%)"
+}
+
+// Renamed reports whether normalization changed the header
+func (c ColumnName) Renamed() bool {
+	return c.Key != c.Original
+}
+
+// ColumnNames is the mapping table of a dataset's columns, in header order
+type ColumnNames []ColumnName
+
+// unitSymbols are trailing symbols read as a header's unit
+var unitSymbols = []string{"%", "$", "€", "£", "¥", "‰"}
+
+// maxUnitLength bounds the bracketed suffix read as a unit, so a header such as
+// "Score (after the second follow-up visit)" keeps its parenthetical in the key
+const maxUnitLength = 12
+
+// NormalizeColumnNames normalizes a table's headers into unique snake_case keys. Each header
+// is trimmed, its unit stripped (a short trailing "(...)" or "[...]", or a trailing symbol
+// such as %) and recorded, camelCase split and every run of other characters turned into
+// one underscore. A key already taken gets the first free _2, _3... suffix; a header left
+// empty becomes column_N, N being its position.
+func NormalizeColumnNames(headers []string) ColumnNames {
+	names := make(ColumnNames, len(headers))
+	taken := make(map[string]bool, len(headers))
+	for i, header := range headers {
+		key, unit := NormalizeColumnName(header)
+		if key == "" {
+			key = fmt.Sprintf("column_%d", i+1)
+		}
+		if taken[key] {
+			base := key
+			for n := 2; taken[key]; n++ {
+				key = fmt.Sprintf("%s_%d", base, n)
+			}
+		}
+		taken[key] = true
+		names[i] = ColumnName{Key: key, Original: strings.TrimSpace(header), Unit: unit}
+	}
+	return names
+}
+
+// NormalizeColumnName returns a header's snake_case key and the unit stripped from it. The
+// key is empty when the header holds nothing but a unit or punctuation.
+func NormalizeColumnName(header string) (key, unit string) {
+	name, unit := splitUnit(strings.TrimSpace(header))
+	return snakeCase(name), unit
+}
+
+// Keys returns the normalized keys in header order
+func (c ColumnNames) Keys() []string {
+	keys := make([]string, len(c))
+	for i, name := range c {
+		keys[i] = name.Key
+	}
+	return keys
+}
+
+// Lookup returns the mapping of a key
+func (c ColumnNames) Lookup(key string) (ColumnName, bool) {
+	for _, name := range c {
+		if name.Key == key {
+			return name, true
+		}
+	}
+	return ColumnName{}, false
+}
+
+// Display returns the header a key was read from, or the key when it is not in the table
+func (c ColumnNames) Display(key string) string {
+	if name, ok := c.Lookup(key); ok && name.Original != "" {
+		return name.Original
+	}
+	return key
+}
+
+// Resolve returns the key a name refers to: the name itself when it is a key, else the key
+// of the header it matches, so requests naming columns by their original header still work
+func (c ColumnNames) Resolve(name string) (string, bool) {
+	if _, ok := c.Lookup(name); ok {
+		return name, true
+	}
+	trimmed := strings.TrimSpace(name)
+	for _, column := range c {
+		if column.Original == trimmed {
+			return column.Key, true
+		}
+	}
+	return "", false
+}
+
+// Renamed returns the columns whose key differs from their header
+func (c ColumnNames) Renamed() ColumnNames {
+	var renamed ColumnNames
+	for _, name := range c {
+		if name.Renamed() {
+			renamed = append(renamed, name)
+		}
+	}
+	return renamed
+}
+
+// splitUnit strips a trailing unit from a trimmed header
+func splitUnit(header string) (string, string) {
+	for _, brackets := range []string{"()", "[]"} {
+		if !strings.HasSuffix(header, brackets[1:]) {
+			continue
+		}
+		open := strings.LastIndex(header, brackets[:1])
+		if open < 0 {
+			continue
+		}
+		unit := strings.TrimSpace(header[open+1 : len(header)-1])
+		if unit != "" && len([]rune(unit)) <= maxUnitLength {
+			return strings.TrimSpace(header[:open]), unit
+		}
+	}
+	for _, symbol := range unitSymbols {
+		if name, ok := strings.CutSuffix(header, symbol); ok {
+			return strings.TrimSpace(name), symbol
+		}
+	}
+	return header, ""
+}
+
+// snakeCase lowercases a name, splitting camelCase words and joining words with single
+// underscores
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	pendingSeparator := false
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pendingSeparator = b.Len() > 0
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 && b.Len() > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				pendingSeparator = true
+			}
+		}
+		if pendingSeparator {
+			b.WriteByte('_')
+			pendingSeparator = false
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package dataset
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestNormalizeColumnNames verifies messy headers become unique snake_case keys with their
+// units stripped into the mapping table
+func TestNormalizeColumnNames(t *testing.T) {
+	headers := []string{"Conv. Rate (%)  ", "Revenue [USD]", "orderValue", "Order Value", "HTTPStatusCode", "growth%", "", "(%)", "Score (after the second follow-up)", "customer_id"}
+	want := ColumnNames{
+		{Key: "conv_rate", Original: "Conv. Rate (%)", Unit: "%"},
+		{Key: "revenue", Original: "Revenue [USD]", Unit: "USD"},
+		{Key: "order_value", Original: "orderValue"},
+		{Key: "order_value_2", Original: "Order Value"},
+		{Key: "http_status_code", Original: "HTTPStatusCode"},
+		{Key: "growth", Original: "growth%", Unit: "%"},
+		{Key: "column_7", Original: ""},
+		{Key: "column_8", Original: "(%)", Unit: "%"},
+		{Key: "score_after_the_second_follow_up", Original: "Score (after the second follow-up)"},
+		{Key: "customer_id", Original: "customer_id"},
+	}
+	got := NormalizeColumnNames(headers)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("NormalizeColumnNames:\n got %+v\nwant %+v", got, want)
+	}
+	if renamed := got.Renamed(); len(renamed) != 9 {
+		t.Errorf("expected every header but customer_id renamed, got %d", len(renamed))
+	}
+}
+
+// TestColumnNamesLookups verifies keys display as their original headers and that a
+// variable requested by its header resolves to its key
+func TestColumnNamesLookups(t *testing.T) {
+	names := NormalizeColumnNames([]string{"Conv. Rate (%)", "sessions"})
+	if got := names.Display("conv_rate"); got != "Conv. Rate (%)" {
+		t.Errorf("Display(conv_rate) = %q", got)
+	}
+	if got := names.Display("unknown"); got != "unknown" {
+		t.Errorf("Display of an unmapped key = %q, want the key", got)
+	}
+	for _, name := range []string{"conv_rate", "Conv. Rate (%)", " Conv. Rate (%) "} {
+		if key, ok := names.Resolve(name); !ok || key != "conv_rate" {
+			t.Errorf("Resolve(%q) = %q, %v", name, key, ok)
+		}
+	}
+	if _, ok := names.Resolve("Bounce Rate"); ok {
+		t.Error("resolved a header the table does not have")
+	}
+
+	metadata := DatasetMetadata{Fields: []FieldInfo{{Name: "conv_rate", OriginalName: "Conv. Rate (%)", Unit: "%"}, {Name: "legacy name"}}}
+	if got := metadata.ColumnNames().Display("legacy name"); got != "legacy name" {
+		t.Errorf("field stored before normalization displays as %q", got)
+	}
+}
@@ -0,0 +1,141 @@
+package dataset
+
+import (
+	"fmt"
+	"math"
+
+	"gohypo/domain/core"
+)
+
+// ColumnStore holds a matrix column-major in one contiguous slice. Each column is a slice
+// of it, so reading a column copies nothing and a pass over a column walks memory in
+// order, where the row-major Data strides across every row's allocation.
+type ColumnStore struct {
+	values []float64 // column j occupies values[j*rows : (j+1)*rows]
+	rows   int
+	cols   int
+	origin *[]float64 // first row of the Data the store was built from
+}
+
+// NewColumnStore copies row-major data into column-major storage. Rows shorter than cols
+// are padded with NaN, the matrix's marker for missing values.
+func NewColumnStore(data [][]float64, cols int) *ColumnStore {
+	rows := len(data)
+	store := &ColumnStore{values: make([]float64, rows*cols), rows: rows, cols: cols}
+	if rows > 0 {
+		store.origin = &data[0]
+	}
+	for i, row := range data {
+		for j := 0; j < cols; j++ {
+			value := math.NaN()
+			if j < len(row) {
+				value = row[j]
+			}
+			store.values[j*rows+i] = value
+		}
+	}
+	return store
+}
+
+// Column returns column j without copying, or nil when out of range. The slice's capacity
+// ends with the column, so appending to it reallocates instead of overwriting the next
+// column. Callers must not modify its values.
+func (s *ColumnStore) Column(j int) []float64 {
+	if j < 0 || j >= s.cols {
+		return nil
+	}
+	start := j * s.rows
+	return s.values[start : start+s.rows : start+s.rows]
+}
+
+// Rows returns the number of entities
+func (s *ColumnStore) Rows() int {
+	return s.rows
+}
+
+// Cols returns the number of variables
+func (s *ColumnStore) Cols() int {
+	return s.cols
+}
+
+// matches reports whether the store was built from this Data and is still its shape
+func (s *ColumnStore) matches(m *Matrix) bool {
+	if s == nil || s.rows != len(m.Data) || s.cols != len(m.VariableKeys) {
+		return false
+	}
+	return s.rows == 0 || s.origin == &m.Data[0]
+}
+
+// Columnarize builds the column store that column reads are served from, unless the
+// current one still matches the matrix. Call it once the matrix is complete and before the
+// bundle is shared between goroutines; Data changed in place afterwards is not seen until
+// the store is rebuilt with Rebuild.
+func (b *MatrixBundle) Columnarize() *ColumnStore {
+	if !b.Matrix.columns.matches(&b.Matrix) {
+		b.Rebuild()
+	}
+	return b.Matrix.columns
+}
+
+// Rebuild rebuilds the column store from Data unconditionally
+func (b *MatrixBundle) Rebuild() {
+	b.Matrix.columns = NewColumnStore(b.Matrix.Data, len(b.Matrix.VariableKeys))
+}
+
+// Columnar reports whether column reads are served from a current column store
+func (b *MatrixBundle) Columnar() bool {
+	return b.Matrix.columns.matches(&b.Matrix)
+}
+
+// ColumnAt returns column j: a zero-copy slice of the column store once the bundle is
+// columnarized, otherwise a copy gathered from Data. Callers must not modify it.
+func (b *MatrixBundle) ColumnAt(j int) []float64 {
+	if j < 0 || j >= len(b.Matrix.VariableKeys) {
+		return nil
+	}
+	if store := b.Matrix.columns; store.matches(&b.Matrix) {
+		return store.Column(j)
+	}
+	column := make([]float64, len(b.Matrix.Data))
+	for i, row := range b.Matrix.Data {
+		if j < len(row) {
+			column[i] = row[j]
+		} else {
+			column[i] = math.NaN()
+		}
+	}
+	return column
+}
+
+// ColumnView returns a variable's column like ColumnAt: without copying once the bundle is
+// columnarized. Callers must not modify it; use GetColumnData for a private copy.
+func (b *MatrixBundle) ColumnView(varKey core.VariableKey) ([]float64, bool) {
+	colIdx, found := b.GetColumn(varKey)
+	if !found {
+		return nil, false
+	}
+	return b.ColumnAt(colIdx), true
+}
+
+// ColumnSource serves a matrix's columns one at a time, so pairwise stages can stream
+// column pairs from storage that need not hold the whole matrix in memory. The column is
+// valid until release is called, and callers must not modify it.
+type ColumnSource interface {
+	Variables() []core.VariableKey
+	AcquireColumn(j int) (column []float64, release func(), err error)
+}
+
+// Variables returns the bundle's variable keys in column order
+func (b *MatrixBundle) Variables() []core.VariableKey {
+	return b.Matrix.VariableKeys
+}
+
+// AcquireColumn returns column j like ColumnAt; the bundle holds every column in memory,
+// so releasing it is a no-op
+func (b *MatrixBundle) AcquireColumn(j int) ([]float64, func(), error) {
+	column := b.ColumnAt(j)
+	if column == nil {
+		return nil, nil, fmt.Errorf("column %d out of range (%d columns)", j, len(b.Matrix.VariableKeys))
+	}
+	return column, func() {}, nil
+}
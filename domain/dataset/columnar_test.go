@@ -0,0 +1,144 @@
+package dataset
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gohypo/domain/core"
+)
+
+func newTestBundle(rows, cols int) *MatrixBundle {
+	bundle := &MatrixBundle{}
+	bundle.Matrix.Data = make([][]float64, rows)
+	for i := range bundle.Matrix.Data {
+		bundle.Matrix.Data[i] = make([]float64, cols)
+		for j := range bundle.Matrix.Data[i] {
+			bundle.Matrix.Data[i][j] = float64(i*cols + j)
+		}
+	}
+	for j := 0; j < cols; j++ {
+		bundle.Matrix.VariableKeys = append(bundle.Matrix.VariableKeys, core.VariableKey(fmt.Sprintf("v%d", j)))
+	}
+	return bundle
+}
+
+// TestColumnViewsMatchRows verifies columnar reads return the same values as the rows and
+// share the store instead of copying
+func TestColumnViewsMatchRows(t *testing.T) {
+	bundle := newTestBundle(4, 3)
+	gathered := bundle.ColumnAt(1)
+	if bundle.Columnar() {
+		t.Fatal("bundle should not be columnar before Columnarize")
+	}
+
+	store := bundle.Columnarize()
+	if !bundle.Columnar() || store.Rows() != 4 || store.Cols() != 3 {
+		t.Fatalf("unexpected store %dx%d", store.Rows(), store.Cols())
+	}
+	view, ok := bundle.ColumnView("v1")
+	if !ok {
+		t.Fatal("expected v1")
+	}
+	for i := range gathered {
+		if view[i] != gathered[i] || view[i] != bundle.Matrix.Data[i][1] {
+			t.Fatalf("row %d: view %v, gathered %v", i, view[i], gathered[i])
+		}
+	}
+	if &bundle.ColumnAt(1)[0] != &view[0] {
+		t.Error("column views should share the store")
+	}
+	if bundle.Columnarize() != store {
+		t.Error("a current store should be reused")
+	}
+
+	copied, _ := bundle.GetColumnData("v1")
+	copied[0] = -1
+	if view[0] == -1 {
+		t.Error("GetColumnData should return a private copy")
+	}
+	if grown := append(bundle.ColumnAt(0), 99); bundle.ColumnAt(1)[0] == 99 || len(grown) != 5 {
+		t.Error("appending to a view must not overwrite the next column")
+	}
+}
+
+// TestColumnStoreInvalidation verifies changes to the matrix shape or rows drop the store
+func TestColumnStoreInvalidation(t *testing.T) {
+	bundle := newTestBundle(3, 2)
+	bundle.Columnarize()
+
+	bundle.AddColumn("v2", []float64{7, 8, 9}, ColumnMeta{VariableKey: "v2"}, ResolutionAudit{})
+	if bundle.Columnar() {
+		t.Fatal("AddColumn should invalidate the store")
+	}
+	if view, _ := bundle.ColumnView("v2"); view[2] != 9 {
+		t.Errorf("gathered column = %v", view)
+	}
+
+	bundle.Columnarize()
+	copied := *bundle
+	copied.Matrix.Data = [][]float64{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}}
+	if copied.Columnar() {
+		t.Error("a copy with replaced rows should not read the original store")
+	}
+	if !bundle.Columnar() {
+		t.Error("the original should keep its store")
+	}
+}
+
+// TestColumnStorePadsShortRows verifies ragged rows read as missing values
+func TestColumnStorePadsShortRows(t *testing.T) {
+	store := NewColumnStore([][]float64{{1, 2}, {3}}, 2)
+	if column := store.Column(1); column[0] != 2 || !math.IsNaN(column[1]) {
+		t.Errorf("column = %v", column)
+	}
+	if store.Column(2) != nil || store.Column(-1) != nil {
+		t.Error("out of range columns should be nil")
+	}
+}
+
+// benchmarkPairs reads every column pair of a wide matrix and sums the products, the
+// access pattern of a pairwise sweep
+func benchmarkPairs(b *testing.B, columnar bool) {
+	bundle := newTestBundle(2000, 200)
+	if columnar {
+		bundle.Columnarize()
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		sum := 0.0
+		cols := len(bundle.Matrix.VariableKeys)
+		for i := 0; i < cols-1; i++ {
+			for j := i + 1; j < cols; j++ {
+				x, y := bundle.ColumnAt(i), bundle.ColumnAt(j)
+				for k := range x {
+					sum += x[k] * y[k]
+				}
+			}
+		}
+		if math.IsNaN(sum) {
+			b.Fatal("unexpected NaN")
+		}
+	}
+}
+
+// BenchmarkPairsRowGather is the row-major representation: each pair gathers both columns
+func BenchmarkPairsRowGather(b *testing.B) {
+	benchmarkPairs(b, false)
+}
+
+// BenchmarkPairsColumnar reads both columns of each pair as views of the column store
+func BenchmarkPairsColumnar(b *testing.B) {
+	benchmarkPairs(b, true)
+}
+
+// BenchmarkColumnarize is the one-off cost of building the column store
+func BenchmarkColumnarize(b *testing.B) {
+	bundle := newTestBundle(2000, 200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		bundle.Rebuild()
+	}
+}
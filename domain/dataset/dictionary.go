@@ -0,0 +1,329 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gohypo/domain/core"
+)
+
+// Where a dictionary field's definition came from
+const (
+	DefinitionGlossary = "glossary" // the workspace glossary defines the term
+	DefinitionProfile  = "profile"  // generated from the field's profile; no one has defined it
+)
+
+// DataDictionary documents a dataset's variables for the people reading its findings: what
+// each one means, how it was read and how good it is, and where the dataset came from. It
+// is built from the current dataset on every request, so it follows the dataset's versions;
+// Fingerprint changes whenever the documented content does.
+type DataDictionary struct {
+	DatasetID   core.ID `json:"dataset_id"`
+	Name        string  `json:"name"`
+	Domain      string  `json:"domain,omitempty"`
+	Description string  `json:"description,omitempty"`
+	// NamingConfidence is the Forensic Scout's confidence in the name and domain, when it named them
+	NamingConfidence float64           `json:"naming_confidence,omitempty"`
+	RecordCount      int               `json:"record_count"`
+	QualityScore     float64           `json:"quality_score"`
+	Lineage          DictionaryLineage `json:"lineage"`
+	Fields           []DictionaryField `json:"fields"`
+	Version          time.Time         `json:"version"` // the dataset's last update
+	Fingerprint      string            `json:"fingerprint"`
+	GeneratedAt      time.Time         `json:"generated_at"`
+}
+
+// DictionaryLineage is where a dataset came from and what it connects to
+type DictionaryLineage struct {
+	Source       string            `json:"source"` // "upload", "excel", "api", "sql"
+	Filename     string            `json:"filename,omitempty"`
+	Provenance   *Provenance       `json:"provenance,omitempty"`
+	Locale       string            `json:"locale,omitempty"`
+	TimeZone     string            `json:"time_zone,omitempty"`
+	RelatedTo    []CatalogRelation `json:"related_to"`
+	RenamedCount int               `json:"renamed_count"` // headers normalized to a different variable key
+	DerivedFrom  []LineageEdge     `json:"derived_from,omitempty"`
+}
+
+// DictionaryField is one variable's dictionary entry
+type DictionaryField struct {
+	Name             string                 `json:"name"`
+	Header           string                 `json:"header"` // as written in the source
+	Unit             string                 `json:"unit,omitempty"`
+	DataType         string                 `json:"data_type"`
+	Definition       string                 `json:"definition"`
+	DefinitionSource string                 `json:"definition_source"`
+	Owner            string                 `json:"owner,omitempty"`
+	MissingRate      float64                `json:"missing_rate"`
+	UniqueCount      int                    `json:"unique_count"`
+	QualityScore     float64                `json:"quality_score"`
+	Min              *float64               `json:"min,omitempty"`
+	Max              *float64               `json:"max,omitempty"`
+	Examples         []string               `json:"examples,omitempty"` // left out for PII
+	PII              PIIKind                `json:"pii,omitempty"`
+	Excluded         bool                   `json:"excluded,omitempty"`
+	RelatedVia       []core.ID              `json:"related_via,omitempty"`
+	Hypotheses       []CatalogHypothesisRef `json:"hypotheses"`
+}
+
+// maxDictionaryExamples bounds the sample values listed per field
+const maxDictionaryExamples = 3
+
+// BuildDataDictionary documents a dataset from its metadata, its catalog entry and the
+// workspace glossary. entry carries the quality scores, relations and hypothesis uses the
+// catalog computed; without one they are computed from the dataset alone.
+func BuildDataDictionary(ds *Dataset, entry *CatalogDataset, glossary Glossary) *DataDictionary {
+	if entry == nil {
+		entry = &BuildCatalog(ds.WorkspaceID, []*Dataset{ds}, nil, nil).Datasets[0]
+	}
+	dict := &DataDictionary{
+		DatasetID:    ds.ID,
+		Name:         datasetName(ds),
+		Domain:       ds.Domain,
+		Description:  ds.Description,
+		RecordCount:  ds.RecordCount,
+		QualityScore: entry.QualityScore,
+		Lineage: DictionaryLineage{
+			Source:     ds.Source,
+			Filename:   ds.OriginalFilename,
+			Provenance: ds.Metadata.Provenance,
+			RelatedTo:  entry.Relations,
+		},
+		Fields:      make([]DictionaryField, 0, len(ds.Metadata.Fields)),
+		Version:     ds.UpdatedAt,
+		GeneratedAt: time.Now(),
+	}
+	if !ds.Metadata.AIAnalysis.AnalyzedAt.IsZero() {
+		dict.NamingConfidence = ds.Metadata.AIAnalysis.Confidence
+	}
+	if ds.Metadata.Locale != nil {
+		dict.Lineage.Locale = ds.Metadata.Locale.Name
+	}
+	if ds.Metadata.TimeZone != nil {
+		dict.Lineage.TimeZone = ds.Metadata.TimeZone.Name
+	}
+	if ds.Metadata.Lineage != nil {
+		dict.Lineage.DerivedFrom = ds.Metadata.Lineage.DerivedFrom
+	}
+	if dict.Lineage.RelatedTo == nil {
+		dict.Lineage.RelatedTo = []CatalogRelation{}
+	}
+
+	catalogFields := make(map[string]CatalogField, len(entry.Fields))
+	for _, field := range entry.Fields {
+		catalogFields[field.Name] = field
+	}
+	for _, field := range ds.Metadata.Fields {
+		catalogField := catalogFields[field.Name]
+		item := DictionaryField{
+			Name:         field.Name,
+			Header:       field.DisplayName(),
+			Unit:         field.Unit,
+			DataType:     field.DataType,
+			MissingRate:  catalogField.MissingRate,
+			UniqueCount:  field.UniqueCount,
+			QualityScore: catalogField.QualityScore,
+			Min:          statistic(field, "min"),
+			Max:          statistic(field, "max"),
+			PII:          catalogField.PII,
+			Excluded:     catalogField.Excluded,
+			RelatedVia:   catalogField.RelatedVia,
+			Hypotheses:   catalogField.Hypotheses,
+		}
+		if item.Hypotheses == nil {
+			item.Hypotheses = []CatalogHypothesisRef{}
+		}
+		if term, ok := glossary.Lookup(field); ok {
+			item.Definition, item.DefinitionSource, item.Owner = term.Definition, DefinitionGlossary, term.Owner
+		} else {
+			item.Definition, item.DefinitionSource = profileDefinition(item), DefinitionProfile
+		}
+		if item.PII == "" {
+			for _, value := range field.SampleValues {
+				if len(item.Examples) == maxDictionaryExamples {
+					break
+				}
+				if text := strings.TrimSpace(fmt.Sprint(value)); value != nil && text != "" {
+					item.Examples = append(item.Examples, text)
+				}
+			}
+		}
+		if item.Header != item.Name {
+			dict.Lineage.RenamedCount++
+		}
+		dict.Fields = append(dict.Fields, item)
+	}
+	dict.Fingerprint = dict.fingerprint()
+	return dict
+}
+
+// statistic reads a numeric summary statistic recorded for a field
+func statistic(field FieldInfo, name string) *float64 {
+	switch v := field.Statistics[name].(type) {
+	case float64:
+		return &v
+	case int:
+		f := float64(v)
+		return &f
+	}
+	return nil
+}
+
+// profileDefinition describes a field no glossary term defines from what its profile shows
+func profileDefinition(field DictionaryField) string {
+	kind := field.DataType
+	if kind == "" {
+		kind = "untyped"
+	}
+	parts := []string{fmt.Sprintf("%s variable read from %q", kind, field.Header)}
+	if field.Unit != "" {
+		parts[0] += " in " + field.Unit
+	}
+	if field.Min != nil && field.Max != nil {
+		parts = append(parts, fmt.Sprintf("ranging from %g to %g", *field.Min, *field.Max))
+	}
+	parts = append(parts, fmt.Sprintf("%d distinct values", field.UniqueCount))
+	if field.MissingRate > 0 {
+		parts = append(parts, fmt.Sprintf("%.0f%% missing", field.MissingRate*100))
+	}
+	if field.PII != "" {
+		parts = append(parts, fmt.Sprintf("flagged as %s", field.PII))
+	}
+	text := strings.Join(parts, ", ") + "."
+	return strings.ToUpper(text[:1]) + text[1:]
+}
+
+// fingerprint hashes what the dictionary documents, leaving out when the dataset was updated
+// and the dictionary generated, so a new version with the same content keeps it
+func (d *DataDictionary) fingerprint() string {
+	content := *d
+	content.Version, content.GeneratedAt, content.Fingerprint = time.Time{}, time.Time{}, ""
+	data, err := json.Marshal(content)
+	if err != nil {
+		return ""
+	}
+	return core.NewHash(data).String()[:16]
+}
+
+// Markdown renders the dictionary for reports and wikis
+func (d *DataDictionary) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Data dictionary: %s\n\n", d.Name)
+	if d.Description != "" {
+		b.WriteString(d.Description + "\n\n")
+	}
+	if d.Domain != "" {
+		fmt.Fprintf(&b, "- **Domain:** %s\n", d.Domain)
+	}
+	fmt.Fprintf(&b, "- **Records:** %d, quality %.0f%%\n", d.RecordCount, d.QualityScore*100)
+	fmt.Fprintf(&b, "- **Source:** %s\n", d.Lineage.describe())
+	for _, rel := range d.Lineage.RelatedTo {
+		fmt.Fprintf(&b, "- **Related to:** %s (%s", rel.DatasetName, strings.ReplaceAll(rel.RelationType, "_", " "))
+		if len(rel.CommonFields) > 0 {
+			fmt.Fprintf(&b, " via %s", strings.Join(rel.CommonFields, ", "))
+		}
+		b.WriteString(")\n")
+	}
+	fmt.Fprintf(&b, "- **Version:** %s (fingerprint `%s`)\n\n", d.Version.UTC().Format(time.RFC3339), d.Fingerprint)
+
+	b.WriteString("| Variable | Header | Type | Unit | Definition | Missing | Distinct | Quality | Used by |\n")
+	b.WriteString("|---|---|---|---|---|---|---|---|---|\n")
+	for _, f := range d.Fields {
+		definition := markdownCell(f.Definition)
+		if f.DefinitionSource == DefinitionProfile {
+			definition = "*" + definition + "*"
+		} else if f.Owner != "" {
+			definition += " (ask " + markdownCell(f.Owner) + ")"
+		}
+		if f.Excluded {
+			definition += " **Excluded from analysis as PII.**"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %.0f%% | %d | %.0f%% | %d hypotheses |\n",
+			f.Name, markdownCell(f.Header), f.DataType, markdownCell(f.Unit), definition,
+			f.MissingRate*100, f.UniqueCount, f.QualityScore*100, len(f.Hypotheses))
+	}
+	b.WriteString("\nDefinitions in italics were generated from the data; add them to the workspace glossary to replace them.\n")
+	return b.String()
+}
+
+// describe states where a dataset came from in one line
+func (l DictionaryLineage) describe() string {
+	text := l.Source
+	if l.Filename != "" {
+		text += " of " + l.Filename
+	}
+	if p := l.Provenance; p != nil {
+		text = fmt.Sprintf("%s pull", p.Kind)
+		if p.Location != "" {
+			text += " from " + p.Location
+		}
+		if !p.PulledAt.IsZero() {
+			text += " at " + p.PulledAt.UTC().Format(time.RFC3339)
+		}
+		if p.Truncated {
+			text += ", truncated by the row limit"
+		}
+	}
+	if l.Locale != "" {
+		text += "; numbers and dates read as " + l.Locale
+	}
+	if l.TimeZone != "" {
+		text += "; timestamps in " + l.TimeZone
+	}
+	if l.RenamedCount > 0 {
+		text += fmt.Sprintf("; %d headers renamed to variable keys", l.RenamedCount)
+	}
+	for _, edge := range l.DerivedFrom {
+		text += fmt.Sprintf("; derived from %s by %s", edge.DatasetID, edge.Transformation.Summary())
+	}
+	return text
+}
+
+// markdownCell escapes text for a Markdown table cell
+func markdownCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", `\|`), "\n", " ")
+}
+
+// dictionaryColumns are the CSV export's columns
+var dictionaryColumns = []string{
+	"variable", "header", "data_type", "unit", "definition", "definition_source", "owner",
+	"missing_rate", "unique_count", "quality_score", "min", "max", "examples", "pii", "excluded",
+	"related_datasets", "hypotheses",
+}
+
+// WriteCSV writes one row per variable, for spreadsheets and catalog imports
+func (d *DataDictionary) WriteCSV(out io.Writer) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(dictionaryColumns); err != nil {
+		return fmt.Errorf("failed to write dictionary header: %w", err)
+	}
+	number := func(v *float64) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'g', -1, 64)
+	}
+	for _, f := range d.Fields {
+		related := make([]string, len(f.RelatedVia))
+		for i, id := range f.RelatedVia {
+			related[i] = string(id)
+		}
+		record := []string{
+			f.Name, f.Header, f.DataType, f.Unit, f.Definition, f.DefinitionSource, f.Owner,
+			strconv.FormatFloat(f.MissingRate, 'f', 4, 64), strconv.Itoa(f.UniqueCount),
+			strconv.FormatFloat(f.QualityScore, 'f', 4, 64), number(f.Min), number(f.Max),
+			strings.Join(f.Examples, "; "), string(f.PII), strconv.FormatBool(f.Excluded),
+			strings.Join(related, " "), strconv.Itoa(len(f.Hypotheses)),
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("failed to write dictionary row %s: %w", f.Name, err)
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
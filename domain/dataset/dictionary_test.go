@@ -0,0 +1,115 @@
+package dataset
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dictionaryDataset is a dataset with a renamed header, a PII column and a pulled source
+func dictionaryDataset() *Dataset {
+	return &Dataset{
+		ID: "orders", WorkspaceID: "ws", DisplayName: "customer_orders", Domain: "Retail", Source: "sql",
+		RecordCount: 100, UpdatedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Metadata: DatasetMetadata{
+			Fields: []FieldInfo{
+				{Name: "basket_size", OriginalName: "Basket Size (EUR)", Unit: "EUR", DataType: "numeric", UniqueCount: 40, MissingCount: 20,
+					SampleValues: []interface{}{"12.5", nil, "30"}, Statistics: map[string]interface{}{"min": 1.0, "max": 250.0}},
+				{Name: "email", DataType: "text", UniqueCount: 80, SampleValues: []interface{}{"a@example.com"}},
+				{Name: "region", DataType: "categorical", UniqueCount: 4},
+			},
+			PII:        &PIIReport{Columns: []PIIColumn{{Field: "email", Kind: PIIEmail, Excluded: true}}},
+			Provenance: &Provenance{Kind: "sql", Location: "postgres://warehouse/sales", PulledAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+			AIAnalysis: ForensicScoutResult{Domain: "Retail", Confidence: 0.8, AnalyzedAt: time.Now()},
+		},
+	}
+}
+
+// TestBuildDataDictionary verifies glossary terms define fields by key or header, undefined
+// fields get a generated definition, and PII values stay out of the examples
+func TestBuildDataDictionary(t *testing.T) {
+	glossary := Glossary{{Term: "Basket size", Definition: "Order value before discounts.", Owner: "finance"}}
+	dict := BuildDataDictionary(dictionaryDataset(), nil, glossary)
+
+	if dict.Name != "customer_orders" || dict.NamingConfidence != 0.8 || dict.Lineage.RenamedCount != 1 {
+		t.Fatalf("unexpected dictionary header: %+v", dict)
+	}
+	basket, email, region := dict.Fields[0], dict.Fields[1], dict.Fields[2]
+	if basket.DefinitionSource != DefinitionGlossary || basket.Owner != "finance" || basket.Header != "Basket Size (EUR)" {
+		t.Errorf("expected the glossary to define basket_size, got %+v", basket)
+	}
+	if basket.MissingRate != 0.2 || basket.Min == nil || *basket.Max != 250 || len(basket.Examples) != 2 {
+		t.Errorf("unexpected basket_size profile: %+v", basket)
+	}
+	if email.PII != PIIEmail || !email.Excluded || len(email.Examples) != 0 {
+		t.Errorf("expected email flagged without examples, got %+v", email)
+	}
+	if region.DefinitionSource != DefinitionProfile || !strings.HasPrefix(region.Definition, "Categorical variable") {
+		t.Errorf("expected a generated definition for region, got %q", region.Definition)
+	}
+
+	markdown := dict.Markdown()
+	for _, want := range []string{"# Data dictionary: customer_orders", "sql pull from postgres://warehouse/sales", "(ask finance)", "Excluded from analysis as PII"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("markdown is missing %q:\n%s", want, markdown)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := dict.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 || records[1][0] != "basket_size" || records[1][4] != "Order value before discounts." {
+		t.Errorf("unexpected CSV export: %v", records)
+	}
+}
+
+// TestDataDictionaryFingerprintFollowsContent verifies the fingerprint ignores when the
+// dictionary was built but changes with the definitions
+func TestDataDictionaryFingerprintFollowsContent(t *testing.T) {
+	ds := dictionaryDataset()
+	first := BuildDataDictionary(ds, nil, nil)
+	ds.UpdatedAt = ds.UpdatedAt.Add(time.Hour)
+	if again := BuildDataDictionary(ds, nil, nil); again.Fingerprint != first.Fingerprint {
+		t.Error("an unchanged dataset should keep its fingerprint")
+	}
+	defined := BuildDataDictionary(ds, nil, Glossary{{Term: "region", Definition: "Sales region of the store."}})
+	if defined.Fingerprint == first.Fingerprint {
+		t.Error("a new definition should change the fingerprint")
+	}
+}
+
+// TestGlossaryFromMetadata reads a glossary back from workspace metadata that went through JSON
+// and rejects terms defined twice
+func TestGlossaryFromMetadata(t *testing.T) {
+	glossary := Glossary{{Term: "Basket size", Definition: "Order value.", Aliases: []string{"order_value"}}}
+	if err := glossary.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(map[string]interface{}{GlossaryKey: glossary})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		t.Fatal(err)
+	}
+	read := GlossaryFrom(metadata)
+	if _, ok := read.Lookup(FieldInfo{Name: "order_value"}); !ok {
+		t.Fatalf("expected the alias to match, got %+v", read)
+	}
+	if GlossaryFrom(map[string]interface{}{}) != nil {
+		t.Error("expected no glossary on an empty workspace")
+	}
+	duplicate := append(glossary, GlossaryEntry{Term: "basket_size", Definition: "Again."})
+	if err := duplicate.Validate(); err == nil {
+		t.Error("expected a term defined twice to be refused")
+	}
+}
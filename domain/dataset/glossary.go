@@ -0,0 +1,94 @@
+package dataset
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GlossaryKey is the workspace metadata entry holding the workspace's glossary
+const GlossaryKey = "glossary"
+
+// maxGlossaryEntries bounds a glossary stored in workspace metadata
+const maxGlossaryEntries = 2000
+
+// GlossaryEntry defines a business term. Term is matched against a field's variable key
+// and the header it was read from, so "Basket size (EUR)" defines basket_size; Aliases name
+// other columns meaning the same.
+type GlossaryEntry struct {
+	Term       string   `json:"term"`
+	Definition string   `json:"definition"`
+	Aliases    []string `json:"aliases,omitempty"`
+	Owner      string   `json:"owner,omitempty"` // who to ask about the term
+}
+
+// Glossary is a workspace's business terms, shared by all its datasets
+type Glossary []GlossaryEntry
+
+// Validate checks every entry has a term and a definition and no term is defined twice
+func (g Glossary) Validate() error {
+	if len(g) > maxGlossaryEntries {
+		return fmt.Errorf("a glossary holds at most %d entries", maxGlossaryEntries)
+	}
+	seen := make(map[string]string)
+	for _, entry := range g {
+		if strings.TrimSpace(entry.Term) == "" || strings.TrimSpace(entry.Definition) == "" {
+			return fmt.Errorf("every glossary entry needs a term and a definition")
+		}
+		for _, name := range append([]string{entry.Term}, entry.Aliases...) {
+			key, _ := NormalizeColumnName(name)
+			if key == "" {
+				return fmt.Errorf("glossary term %q names no column", name)
+			}
+			if other, ok := seen[key]; ok {
+				return fmt.Errorf("%q is defined by both %q and %q", name, other, entry.Term)
+			}
+			seen[key] = entry.Term
+		}
+	}
+	return nil
+}
+
+// Sorted returns the entries ordered by term
+func (g Glossary) Sorted() Glossary {
+	sorted := append(Glossary(nil), g...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Term) < strings.ToLower(sorted[j].Term)
+	})
+	return sorted
+}
+
+// Lookup finds the entry defining a field, by its variable key or original header
+func (g Glossary) Lookup(field FieldInfo) (GlossaryEntry, bool) {
+	keys := map[string]bool{field.Name: true}
+	if field.OriginalName != "" {
+		key, _ := NormalizeColumnName(field.OriginalName)
+		keys[key] = true
+	}
+	for _, entry := range g {
+		for _, name := range append([]string{entry.Term}, entry.Aliases...) {
+			if key, _ := NormalizeColumnName(name); keys[key] {
+				return entry, true
+			}
+		}
+	}
+	return GlossaryEntry{}, false
+}
+
+// GlossaryFrom reads a workspace's glossary from its metadata; nil when it has none
+func GlossaryFrom(metadata map[string]interface{}) Glossary {
+	switch raw := metadata[GlossaryKey].(type) {
+	case nil:
+		return nil
+	case Glossary:
+		return raw
+	default:
+		// Metadata read back from the database holds the glossary as generic values
+		var glossary Glossary
+		if data, err := json.Marshal(raw); err != nil || json.Unmarshal(data, &glossary) != nil {
+			return nil
+		}
+		return glossary
+	}
+}
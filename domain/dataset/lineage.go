@@ -0,0 +1,274 @@
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gohypo/domain/core"
+)
+
+// Transformations a dataset can be derived from its parents by
+const (
+	TransformMerge    = "merge"    // parents joined or appended into one dataset
+	TransformFilter   = "filter"   // rows of one parent kept by conditions
+	TransformResample = "resample" // a time series regridded to another frequency
+	TransformDerive   = "derive"   // any other transformation, described in prose
+)
+
+// Relations of the edges of a lineage graph, read from the node the edge leaves
+const (
+	RelationDerivedFrom = "derived_from" // dataset produced from another dataset
+	RelationSwept       = "swept"        // run computed its matrix from a dataset
+	RelationFoundIn     = "found_in"     // hypothesis generated from a run's findings
+)
+
+// Kinds of lineage graph node
+const (
+	LineageNodeDataset    = "dataset"
+	LineageNodeRun        = "run"
+	LineageNodeHypothesis = "hypothesis"
+)
+
+const (
+	// maxLineageParents bounds the parents one dataset declares
+	maxLineageParents = 32
+
+	// maxLineageNodes bounds the datasets a lineage graph walks, so a long chain of
+	// derivations cannot make a request unbounded
+	maxLineageNodes = 200
+)
+
+// Lineage records the datasets a dataset was derived from. Datasets without one are
+// originals: uploaded files or pulls from a data source.
+type Lineage struct {
+	DerivedFrom []LineageEdge `json:"derived_from"`
+}
+
+// LineageEdge is one parent of a derived dataset and how the dataset was produced from it
+type LineageEdge struct {
+	DatasetID      core.ID        `json:"dataset_id"`
+	Transformation Transformation `json:"transformation"`
+}
+
+// Transformation describes how a dataset was produced from a parent. Merge, Filters and
+// Resample hold the settings of the matching kind; a merge that also resampled to a time
+// grid carries both.
+type Transformation struct {
+	Kind        string             `json:"kind"`
+	Description string             `json:"description,omitempty"`
+	Merge       *MergeTransform    `json:"merge,omitempty"`
+	Filters     []string           `json:"filters,omitempty"` // row conditions, e.g. "region = EU"
+	Resample    *ResampleTransform `json:"resample,omitempty"`
+	RecordedAt  time.Time          `json:"recorded_at"`
+}
+
+// MergeTransform is the configuration a merge ran with
+type MergeTransform struct {
+	JoinType        string   `json:"join_type"` // "union", "inner", "left", "outer" or "intersect"
+	KeyColumns      []string `json:"key_columns,omitempty"`
+	TimeColumn      string   `json:"time_column,omitempty"`
+	DuplicatePolicy string   `json:"duplicate_policy,omitempty"`
+}
+
+// ResampleTransform is the time grid a series was resampled to
+type ResampleTransform struct {
+	Frequency     string `json:"frequency"`
+	Interpolation string `json:"interpolation,omitempty"`
+	GapFill       string `json:"gap_fill,omitempty"`
+	TimeColumn    string `json:"time_column,omitempty"`
+}
+
+// Validate checks the lineage of dataset id names each parent once, not id itself, and
+// describes every transformation by a known kind
+func (l *Lineage) Validate(id core.ID) error {
+	if len(l.DerivedFrom) > maxLineageParents {
+		return fmt.Errorf("a dataset is derived from at most %d datasets", maxLineageParents)
+	}
+	seen := make(map[core.ID]bool)
+	for _, edge := range l.DerivedFrom {
+		if edge.DatasetID == "" {
+			return fmt.Errorf("every lineage edge needs a dataset_id")
+		}
+		if edge.DatasetID == id {
+			return fmt.Errorf("a dataset cannot be derived from itself")
+		}
+		if seen[edge.DatasetID] {
+			return fmt.Errorf("dataset %s is listed as a parent twice", edge.DatasetID)
+		}
+		seen[edge.DatasetID] = true
+		if err := edge.Transformation.Validate(); err != nil {
+			return fmt.Errorf("transformation from %s: %w", edge.DatasetID, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the transformation's kind is known and carries the settings it needs
+func (t Transformation) Validate() error {
+	switch t.Kind {
+	case TransformMerge:
+		if t.Merge == nil || t.Merge.JoinType == "" {
+			return fmt.Errorf("a merge transformation needs its join type")
+		}
+	case TransformFilter:
+		if len(t.Filters) == 0 {
+			return fmt.Errorf("a filter transformation needs at least one filter")
+		}
+	case TransformResample:
+		if t.Resample == nil || t.Resample.Frequency == "" {
+			return fmt.Errorf("a resample transformation needs its frequency")
+		}
+	case TransformDerive:
+		if strings.TrimSpace(t.Description) == "" {
+			return fmt.Errorf("a derive transformation needs a description")
+		}
+	default:
+		return fmt.Errorf("unknown transformation kind %q (use %s, %s, %s or %s)",
+			t.Kind, TransformMerge, TransformFilter, TransformResample, TransformDerive)
+	}
+	return nil
+}
+
+// Summary states the transformation in a few words, for graph labels and dictionaries
+func (t Transformation) Summary() string {
+	if t.Description != "" {
+		return t.Description
+	}
+	switch {
+	case t.Merge != nil:
+		text := t.Merge.JoinType + " merge"
+		if len(t.Merge.KeyColumns) > 0 {
+			text += " on " + strings.Join(t.Merge.KeyColumns, ", ")
+		}
+		if t.Resample != nil {
+			text += ", resampled " + t.Resample.Frequency
+		}
+		return text
+	case t.Resample != nil:
+		return "resampled " + t.Resample.Frequency
+	case len(t.Filters) > 0:
+		return "filtered by " + strings.Join(t.Filters, " and ")
+	}
+	return t.Kind
+}
+
+// LineageGraph is the ancestry of a dataset, run or hypothesis down to the original
+// datasets it came from. Edges point from a node to what it was produced from.
+type LineageGraph struct {
+	Root      string        `json:"root"`
+	Nodes     []LineageNode `json:"nodes"`
+	Edges     []GraphEdge   `json:"edges"`
+	Originals []core.ID     `json:"originals"` // datasets with no parents, in walk order
+	Truncated bool          `json:"truncated,omitempty"`
+}
+
+// LineageNode is one dataset, run or hypothesis of a lineage graph. Depth counts the edges
+// from the root, which places the node in the graph view.
+type LineageNode struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"`
+	Label    string `json:"label"`
+	Filename string `json:"filename,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Original bool   `json:"original,omitempty"`
+	Missing  bool   `json:"missing,omitempty"` // a parent no longer stored
+	Depth    int    `json:"depth"`
+}
+
+// GraphEdge connects two lineage graph nodes
+type GraphEdge struct {
+	From           string          `json:"from"`
+	To             string          `json:"to"`
+	Relation       string          `json:"relation"`
+	Transformation *Transformation `json:"transformation,omitempty"`
+}
+
+// DatasetLookup loads a dataset by ID; it returns a nil dataset for one that is not stored
+type DatasetLookup func(ctx context.Context, id core.ID) (*Dataset, error)
+
+// BuildLineageGraph walks the lineage of root breadth first. A parent that is no longer
+// stored stays in the graph as a missing node, so the gap is visible, and a dataset reached
+// twice is added once, so a cycle cannot loop the walk.
+func BuildLineageGraph(ctx context.Context, root *Dataset, lookup DatasetLookup) (*LineageGraph, error) {
+	graph := &LineageGraph{Root: root.ID.String(), Nodes: []LineageNode{}, Edges: []GraphEdge{}, Originals: []core.ID{}}
+	graph.addDataset(root, 0)
+
+	queue := []*Dataset{root}
+	depth := map[core.ID]int{root.ID: 0}
+	for len(queue) > 0 {
+		ds := queue[0]
+		queue = queue[1:]
+		if ds.Metadata.Lineage == nil {
+			continue
+		}
+		for i := range ds.Metadata.Lineage.DerivedFrom {
+			edge := ds.Metadata.Lineage.DerivedFrom[i]
+			if _, seen := depth[edge.DatasetID]; !seen {
+				if len(depth) >= maxLineageNodes {
+					graph.Truncated = true
+					continue
+				}
+				depth[edge.DatasetID] = depth[ds.ID] + 1
+				parent, err := lookup(ctx, edge.DatasetID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load parent dataset %s: %w", edge.DatasetID, err)
+				}
+				if parent == nil {
+					graph.Nodes = append(graph.Nodes, LineageNode{
+						ID: edge.DatasetID.String(), Kind: LineageNodeDataset, Label: edge.DatasetID.String(),
+						Missing: true, Depth: depth[edge.DatasetID],
+					})
+				} else {
+					graph.addDataset(parent, depth[edge.DatasetID])
+					queue = append(queue, parent)
+				}
+			}
+			graph.Edges = append(graph.Edges, GraphEdge{
+				From:           ds.ID.String(),
+				To:             edge.DatasetID.String(),
+				Relation:       RelationDerivedFrom,
+				Transformation: &edge.Transformation,
+			})
+		}
+	}
+	return graph, nil
+}
+
+func (g *LineageGraph) addDataset(ds *Dataset, depth int) {
+	original := ds.Metadata.Lineage == nil || len(ds.Metadata.Lineage.DerivedFrom) == 0
+	g.Nodes = append(g.Nodes, LineageNode{
+		ID:       ds.ID.String(),
+		Kind:     LineageNodeDataset,
+		Label:    ds.GetDisplayName(),
+		Filename: ds.OriginalFilename,
+		Source:   ds.Source,
+		Original: original,
+		Depth:    depth,
+	})
+	if original {
+		g.Originals = append(g.Originals, ds.ID)
+	}
+}
+
+// Contains reports whether the graph has a node with id
+func (g *LineageGraph) Contains(id string) bool {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Extend puts a run or hypothesis in front of the graph: it becomes the root, linked to
+// the old root by relation, and every other node moves one level deeper
+func (g *LineageGraph) Extend(id, kind, label, relation string) {
+	for i := range g.Nodes {
+		g.Nodes[i].Depth++
+	}
+	g.Nodes = append([]LineageNode{{ID: id, Kind: kind, Label: label}}, g.Nodes...)
+	g.Edges = append([]GraphEdge{{From: id, To: g.Root, Relation: relation}}, g.Edges...)
+	g.Root = id
+}
@@ -0,0 +1,80 @@
+package dataset
+
+import (
+	"context"
+	"testing"
+
+	"gohypo/domain/core"
+)
+
+// TestBuildLineageGraph verifies a merge of a filtered dataset and an upload traces back to
+// both uploads, a deleted parent stays as a missing node and a shared ancestor is added once
+func TestBuildLineageGraph(t *testing.T) {
+	merge := Transformation{Kind: TransformMerge, Merge: &MergeTransform{JoinType: "left", KeyColumns: []string{"customer_id"}}}
+	stored := map[core.ID]*Dataset{
+		"orders":    {ID: "orders", OriginalFilename: "orders.csv", Source: "upload"},
+		"customers": {ID: "customers", OriginalFilename: "customers.csv", Source: "upload"},
+		"eu_orders": {ID: "eu_orders", DisplayName: "eu_orders", Metadata: DatasetMetadata{Lineage: &Lineage{DerivedFrom: []LineageEdge{
+			{DatasetID: "orders", Transformation: Transformation{Kind: TransformFilter, Filters: []string{"region = EU"}}},
+		}}}},
+	}
+	merged := &Dataset{ID: "merged", DisplayName: "merged", Metadata: DatasetMetadata{Lineage: &Lineage{DerivedFrom: []LineageEdge{
+		{DatasetID: "eu_orders", Transformation: merge},
+		{DatasetID: "customers", Transformation: merge},
+		{DatasetID: "orders", Transformation: merge},
+		{DatasetID: "deleted", Transformation: merge},
+	}}}}
+	lookup := func(ctx context.Context, id core.ID) (*Dataset, error) { return stored[id], nil }
+
+	graph, err := BuildLineageGraph(context.Background(), merged, lookup)
+	if err != nil {
+		t.Fatalf("BuildLineageGraph failed: %v", err)
+	}
+	if len(graph.Nodes) != 5 || len(graph.Edges) != 5 {
+		t.Fatalf("expected 5 nodes and 5 edges, got %d and %d: %+v", len(graph.Nodes), len(graph.Edges), graph)
+	}
+	if len(graph.Originals) != 2 || graph.Originals[0] != "customers" || graph.Originals[1] != "orders" {
+		t.Errorf("expected the two uploads as originals, got %v", graph.Originals)
+	}
+	for _, node := range graph.Nodes {
+		if node.ID == "deleted" && (!node.Missing || node.Depth != 1) {
+			t.Errorf("expected the deleted parent as a missing node at depth 1, got %+v", node)
+		}
+		if node.ID == "orders" && node.Depth != 1 {
+			t.Errorf("expected orders at its shortest depth 1, got %d", node.Depth)
+		}
+	}
+	if summary := graph.Edges[0].Transformation.Summary(); summary != "left merge on customer_id" {
+		t.Errorf("unexpected merge summary %q", summary)
+	}
+
+	graph.Extend("sweep-1", LineageNodeRun, "sweep-1", RelationSwept)
+	if graph.Root != "sweep-1" || graph.Nodes[1].Depth != 1 || graph.Edges[0].To != "merged" {
+		t.Errorf("expected the run in front of the merged dataset, got %+v", graph)
+	}
+	if !graph.Contains("customers") || graph.Contains("unrelated") {
+		t.Error("Contains does not match the graph's nodes")
+	}
+}
+
+// TestLineageValidate verifies self references, repeated parents and incomplete
+// transformations are rejected
+func TestLineageValidate(t *testing.T) {
+	filter := Transformation{Kind: TransformFilter, Filters: []string{"year >= 2020"}}
+	cases := []struct {
+		name    string
+		lineage Lineage
+		valid   bool
+	}{
+		{"filter", Lineage{DerivedFrom: []LineageEdge{{DatasetID: "a", Transformation: filter}}}, true},
+		{"self", Lineage{DerivedFrom: []LineageEdge{{DatasetID: "d", Transformation: filter}}}, false},
+		{"twice", Lineage{DerivedFrom: []LineageEdge{{DatasetID: "a", Transformation: filter}, {DatasetID: "a", Transformation: filter}}}, false},
+		{"resample without frequency", Lineage{DerivedFrom: []LineageEdge{{DatasetID: "a", Transformation: Transformation{Kind: TransformResample, Resample: &ResampleTransform{}}}}}, false},
+		{"unknown kind", Lineage{DerivedFrom: []LineageEdge{{DatasetID: "a", Transformation: Transformation{Kind: "copy"}}}}, false},
+	}
+	for _, tc := range cases {
+		if err := tc.lineage.Validate("d"); (err == nil) != tc.valid {
+			t.Errorf("%s: expected valid=%v, got %v", tc.name, tc.valid, err)
+		}
+	}
+}
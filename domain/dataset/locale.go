@@ -0,0 +1,365 @@
+package dataset
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DateOrder is the order of day, month and year in a numeric date such as 03/04/2024
+type DateOrder string
+
+const (
+	DateOrderMDY DateOrder = "MDY"
+	DateOrderDMY DateOrder = "DMY"
+	DateOrderYMD DateOrder = "YMD"
+)
+
+// LocaleAuto asks for a dataset's locale to be detected from its values
+const LocaleAuto = "auto"
+
+// Locale is how a dataset writes numbers and dates
+type Locale struct {
+	Name      string    `json:"name"`
+	Decimal   string    `json:"decimal"` // decimal separator
+	Groups    string    `json:"groups"`  // characters accepted as thousands separators
+	DateOrder DateOrder `json:"date_order"`
+}
+
+// spaceGroups are the spaces written between thousands, plain and non-breaking
+const spaceGroups = " \u00a0\u202f"
+
+// locales are the locales a dataset can be configured with, by name
+var locales = map[string]Locale{
+	"en-US": {Name: "en-US", Decimal: ".", Groups: ",", DateOrder: DateOrderMDY},
+	"en-GB": {Name: "en-GB", Decimal: ".", Groups: ",", DateOrder: DateOrderDMY},
+	"de-DE": {Name: "de-DE", Decimal: ",", Groups: "." + spaceGroups, DateOrder: DateOrderDMY},
+	"de-CH": {Name: "de-CH", Decimal: ".", Groups: "'’", DateOrder: DateOrderDMY},
+	"fr-FR": {Name: "fr-FR", Decimal: ",", Groups: spaceGroups + ".", DateOrder: DateOrderDMY},
+	"es-ES": {Name: "es-ES", Decimal: ",", Groups: "." + spaceGroups, DateOrder: DateOrderDMY},
+	"it-IT": {Name: "it-IT", Decimal: ",", Groups: "." + spaceGroups, DateOrder: DateOrderDMY},
+	"nl-NL": {Name: "nl-NL", Decimal: ",", Groups: "." + spaceGroups, DateOrder: DateOrderDMY},
+	"pt-BR": {Name: "pt-BR", Decimal: ",", Groups: "." + spaceGroups, DateOrder: DateOrderDMY},
+	"ja-JP": {Name: "ja-JP", Decimal: ".", Groups: ",", DateOrder: DateOrderYMD},
+}
+
+// DefaultLocale is used when detection finds no evidence either way
+var DefaultLocale = locales["en-US"]
+
+// LookupLocale returns a named locale
+func LookupLocale(name string) (Locale, bool) {
+	locale, ok := locales[name]
+	return locale, ok
+}
+
+// LocaleNames lists the named locales, sorted
+func LocaleNames() []string {
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveLocale returns the named locale, or the locale detected from values when name is
+// empty or auto
+func ResolveLocale(name string, values []string) (Locale, error) {
+	if name == "" || name == LocaleAuto {
+		return DetectLocale(values), nil
+	}
+	locale, ok := locales[name]
+	if !ok {
+		return Locale{}, fmt.Errorf("unknown locale %q (use %s or %s)", name, LocaleAuto, strings.Join(LocaleNames(), ", "))
+	}
+	return locale, nil
+}
+
+// String describes the locale, e.g. "de-DE (decimal ',', DMY dates)"
+func (l Locale) String() string {
+	return fmt.Sprintf("%s (decimal '%s', %s dates)", l.Name, l.Decimal, l.DateOrder)
+}
+
+// ParseNumber parses a number written in the locale, such as "1.234,56" in de-DE. Unit
+// symbols such as currency and percent signs are stripped and a parenthesized number is
+// negative. Thousands separators must group digits in threes, so "1,5" is not a number in
+// en-US.
+func (l Locale) ParseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s, negative = s[1:len(s)-1], true
+	}
+	for _, symbol := range unitSymbols {
+		s = strings.ReplaceAll(s, symbol, "")
+	}
+	s = strings.TrimSpace(s)
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], strings.TrimSpace(s[1:])
+	}
+	if s == "" {
+		return 0, false
+	}
+
+	mantissa, exponent := s, ""
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa, exponent = s[:i], s[i+1:]
+		if exponent == "" || !isDigits(strings.TrimLeft(exponent, "+-")) || strings.ContainsAny(mantissa, l.Groups) {
+			return 0, false
+		}
+		exponent = "e" + exponent
+	}
+	if strings.Count(mantissa, l.Decimal) > 1 {
+		return 0, false
+	}
+	integer, fraction, hasFraction := strings.Cut(mantissa, l.Decimal)
+	integer, ok := l.ungroup(integer)
+	if !ok || (hasFraction && !isDigits(fraction)) || (integer == "" && fraction == "") {
+		return 0, false
+	}
+	normalized := sign + integer
+	if hasFraction {
+		normalized += "." + fraction
+	}
+	value, err := strconv.ParseFloat(normalized+exponent, 64)
+	if err != nil {
+		return 0, false
+	}
+	if negative {
+		value = -value
+	}
+	return value, true
+}
+
+// ungroup removes thousands separators from the integer part of a number, checking that
+// they group its digits in threes
+func (l Locale) ungroup(integer string) (string, bool) {
+	if !strings.ContainsAny(integer, l.Groups) {
+		return integer, integer == "" || isDigits(integer)
+	}
+	var groups []string
+	start := 0
+	for i, r := range integer {
+		if strings.ContainsRune(l.Groups, r) {
+			groups = append(groups, integer[start:i])
+			start = i + len(string(r))
+		}
+	}
+	groups = append(groups, integer[start:])
+	if len(groups[0]) > 3 || !isDigits(groups[0]) {
+		return "", false
+	}
+	for _, group := range groups[1:] {
+		if len(group) != 3 || !isDigits(group) {
+			return "", false
+		}
+	}
+	return strings.Join(groups, ""), true
+}
+
+// isoLayouts are read the same way in every locale
+var isoLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// namedMonthLayouts are dates with an English month name, which are unambiguous
+var namedMonthLayouts = []string{
+	"02-Jan-2006",
+	"2-Jan-2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+	"2 January 2006",
+}
+
+// numericDate matches a numeric date with an optional time, e.g. 31/12/2024 23:59 or 31.12.24
+var numericDate = regexp.MustCompile(`^(\d{1,4})[./-](\d{1,2})[./-](\d{1,4})(?:[ T](\d{1,2}):(\d{2})(?::(\d{2}))?)?$`)
+
+// ParseDate parses a date or timestamp written in the locale, in UTC. ISO dates and dates
+// with month names are read the same in every locale; the locale's date order decides
+// numeric dates such as 03/04/2024. Two-digit years follow Go's pivot: 69-99 are 1900s.
+func (l Locale) ParseDate(s string) (time.Time, bool) {
+	return l.ParseDateIn(s, time.UTC)
+}
+
+// ParseDateIn is ParseDate for a dataset whose wall clock is in loc: dates without an
+// offset are read in loc, while an explicit offset such as +01:00 or Z is kept.
+func (l Locale) ParseDateIn(s string, loc *time.Location) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range isoLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	if m := numericDate.FindStringSubmatch(s); m != nil {
+		return l.numericDate(m, loc)
+	}
+	for _, layout := range namedMonthLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (l Locale) numericDate(m []string, loc *time.Location) (time.Time, bool) {
+	a, b, c := m[1], m[2], m[3]
+	var year, month, day string
+	switch {
+	case len(a) == 4 || l.DateOrder == DateOrderYMD:
+		year, month, day = a, b, c
+	case l.DateOrder == DateOrderDMY:
+		day, month, year = a, b, c
+	default:
+		month, day, year = a, b, c
+	}
+	if (len(year) != 2 && len(year) != 4) || len(day) > 2 {
+		return time.Time{}, false
+	}
+	y, _ := strconv.Atoi(year)
+	if len(year) == 2 {
+		y += 2000
+		if y >= 2069 {
+			y -= 100
+		}
+	}
+	mo, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	var hour, minute, second int
+	if m[4] != "" {
+		hour, _ = strconv.Atoi(m[4])
+		minute, _ = strconv.Atoi(m[5])
+		if m[6] != "" {
+			second, _ = strconv.Atoi(m[6])
+		}
+	}
+	if mo < 1 || mo > 12 || d < 1 || hour > 23 || minute > 59 || second > 59 {
+		return time.Time{}, false
+	}
+	t := time.Date(y, time.Month(mo), d, hour, minute, second, 0, loc)
+	if t.Day() != d { // 31/02 rolls over into March
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// DetectLocale infers the decimal separator and date order of a dataset from its values.
+// A value only counts as evidence when it is unambiguous: "1.234,56", "0,5" and "1,5" say
+// the decimal is a comma, while "1,234" could be either and is ignored. A date whose first
+// field is above 12 says day first, whose second is above 12 month first. Without evidence
+// the decimal is a point, and the date order follows the decimal: day first with a comma.
+func DetectLocale(values []string) Locale {
+	var commaDecimal, pointDecimal, dayFirst, monthFirst int
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if m := numericDate.FindStringSubmatch(value); m != nil {
+			if len(m[1]) <= 2 {
+				a, _ := strconv.Atoi(m[1])
+				b, _ := strconv.Atoi(m[2])
+				switch {
+				case a > 12 && b <= 12:
+					dayFirst++
+				case b > 12 && a <= 12:
+					monthFirst++
+				}
+			}
+			continue
+		}
+		switch decimalEvidence(value) {
+		case ",":
+			commaDecimal++
+		case ".":
+			pointDecimal++
+		}
+	}
+
+	locale := Locale{Name: LocaleAuto, Decimal: ".", Groups: ",'’" + spaceGroups, DateOrder: DateOrderMDY}
+	if commaDecimal > pointDecimal {
+		locale.Decimal, locale.Groups, locale.DateOrder = ",", "."+spaceGroups, DateOrderDMY
+	}
+	switch {
+	case dayFirst > monthFirst:
+		locale.DateOrder = DateOrderDMY
+	case monthFirst > dayFirst:
+		locale.DateOrder = DateOrderMDY
+	}
+	return locale
+}
+
+// LocaleSampleRows bounds the rows of a table its locale is detected from
+const LocaleSampleRows = 500
+
+// DetectTableLocale detects the locale of a table's cells from its first rows, headers
+// excluded
+func DetectTableLocale(rows [][]string) Locale {
+	if len(rows) > LocaleSampleRows {
+		rows = rows[:LocaleSampleRows]
+	}
+	var values []string
+	for _, row := range rows {
+		values = append(values, row...)
+	}
+	return DetectLocale(values)
+}
+
+// decimalEvidence returns the decimal separator a number must be using, or "" when the
+// value is not a number or could be read either way
+func decimalEvidence(value string) string {
+	value = strings.Trim(value, "()+-"+strings.Join(unitSymbols, "")+spaceGroups)
+	if value == "" || strings.IndexFunc(value, unicode.IsDigit) < 0 {
+		return ""
+	}
+	for _, r := range value {
+		if !unicode.IsDigit(r) && !strings.ContainsRune(".,'’"+spaceGroups, r) {
+			return ""
+		}
+	}
+	lastComma, lastPoint := strings.LastIndex(value, ","), strings.LastIndex(value, ".")
+	switch {
+	case lastComma >= 0 && lastPoint >= 0:
+		if lastComma > lastPoint {
+			return ","
+		}
+		return "."
+	case lastComma >= 0:
+		return soleSeparatorEvidence(value, ",", ".")
+	case lastPoint >= 0:
+		return soleSeparatorEvidence(value, ".", ",")
+	}
+	return ""
+}
+
+// soleSeparatorEvidence reads a number holding one kind of separator: repeated, it groups
+// thousands; once, it is the decimal unless exactly three digits follow a non-zero integer
+func soleSeparatorEvidence(value, separator, other string) string {
+	if strings.Count(value, separator) > 1 {
+		return other
+	}
+	integer, fraction, _ := strings.Cut(value, separator)
+	if len(fraction) != 3 || integer == "0" || integer == "" {
+		return separator
+	}
+	return ""
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package dataset
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseNumberByLocale(t *testing.T) {
+	tests := []struct {
+		locale string
+		input  string
+		want   float64
+		ok     bool
+	}{
+		{"en-US", "1,234.56", 1234.56, true},
+		{"en-US", "$1,234,567", 1234567, true},
+		{"en-US", "(12.5)", -12.5, true},
+		{"en-US", "1.5e3", 1500, true},
+		{"en-US", "1,5", 0, false},
+		{"en-US", "1,23,456", 0, false},
+		{"de-DE", "1.234,56", 1234.56, true},
+		{"de-DE", "-0,5", -0.5, true},
+		{"de-DE", "12,5 %", 12.5, true},
+		{"de-DE", "1.234", 1234, true},
+		{"de-DE", "12.5", 0, false},
+		{"de-DE", "31.12.2024", 0, false},
+		{"fr-FR", "1 234 567,8", 1234567.8, true},
+		{"fr-FR", "1 234,56 €", 1234.56, true},
+		{"de-CH", "1'234.50", 1234.5, true},
+		{"en-US", "", 0, false},
+		{"en-US", "NaN", 0, false},
+		{"en-US", "12 apples", 0, false},
+	}
+	for _, tt := range tests {
+		locale, _ := LookupLocale(tt.locale)
+		got, ok := locale.ParseNumber(tt.input)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("%s ParseNumber(%q) = %v, %v; want %v, %v", tt.locale, tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseDateByLocale(t *testing.T) {
+	date := func(y, m, d int) time.Time { return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		locale string
+		input  string
+		want   time.Time
+		ok     bool
+	}{
+		{"en-GB", "31/12/2024", date(2024, 12, 31), true},
+		{"en-GB", "03/04/2024", date(2024, 4, 3), true},
+		{"en-US", "03/04/2024", date(2024, 3, 4), true},
+		{"en-US", "31/12/2024", time.Time{}, false},
+		{"de-DE", "31.12.24", date(2024, 12, 31), true},
+		{"de-DE", "01.02.99", date(1999, 2, 1), true},
+		{"de-DE", "31.02.2024", time.Time{}, false},
+		{"en-US", "2024-12-31", date(2024, 12, 31), true},
+		{"de-DE", "2024/12/31", date(2024, 12, 31), true},
+		{"ja-JP", "24/12/31", date(2024, 12, 31), true},
+		{"en-GB", "2 Jan 2024", date(2024, 1, 2), true},
+		{"en-GB", "31/12/2024 23:59:30", time.Date(2024, 12, 31, 23, 59, 30, 0, time.UTC), true},
+		{"en-GB", "not a date", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		locale, _ := LookupLocale(tt.locale)
+		got, ok := locale.ParseDate(tt.input)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("%s ParseDate(%q) = %v, %v; want %v, %v", tt.locale, tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	de, _ := LookupLocale("de-DE")
+	if got, _ := de.ParseDateIn("01.03.2024", berlin); !got.Equal(time.Date(2024, 2, 29, 23, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseDateIn read a naive date as %v, want Berlin midnight", got.UTC())
+	}
+	if got, _ := de.ParseDateIn("2024-03-01T00:00:00Z", berlin); !got.Equal(date(2024, 3, 1)) {
+		t.Errorf("ParseDateIn moved an explicit offset to %v", got.UTC())
+	}
+}
+
+func TestDetectLocale(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		decimal string
+		order   DateOrder
+	}{
+		{"european", []string{"1.234,56", "0,5", "31/12/2024", "1.234"}, ",", DateOrderDMY},
+		{"us", []string{"1,234.56", "12/31/2024", "7"}, ".", DateOrderMDY},
+		{"point decimal with day-first dates", []string{"3.14", "25.12.2024"}, ".", DateOrderDMY},
+		{"comma decimal without dates", []string{"12,5", "3,75", "abc"}, ",", DateOrderDMY},
+		{"ambiguous values only", []string{"1,234", "1.234", "03/04/2024"}, ".", DateOrderMDY},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale := DetectLocale(tt.values)
+			if locale.Decimal != tt.decimal || locale.DateOrder != tt.order {
+				t.Fatalf("DetectLocale = %s, want decimal %q and %s dates", locale, tt.decimal, tt.order)
+			}
+			for _, value := range tt.values {
+				if _, isDate := locale.ParseDate(value); isDate {
+					continue
+				}
+				if decimalEvidence(value) != "" {
+					if _, ok := locale.ParseNumber(value); !ok {
+						t.Errorf("detected locale cannot parse %q", value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestResolveLocale(t *testing.T) {
+	if locale, err := ResolveLocale("fr-FR", []string{"1,234.56"}); err != nil || locale.Name != "fr-FR" {
+		t.Errorf("a named locale should win over the values, got %v, %v", locale, err)
+	}
+	if locale, err := ResolveLocale("", []string{"1.234,56"}); err != nil || locale.Decimal != "," {
+		t.Errorf("an empty name should detect the locale, got %v, %v", locale, err)
+	}
+	if _, err := ResolveLocale("xx-XX", nil); err == nil {
+		t.Error("expected an unknown locale to be rejected")
+	}
+}
@@ -10,27 +10,31 @@ import (
 
 // SnapshotManifest captures the complete specification of a data snapshot
 type SnapshotManifest struct {
-	SnapshotID    core.SnapshotID `json:"snapshot_id"`
-	SnapshotAt    core.SnapshotAt `json:"snapshot_at"`
-	Lag           core.Lag        `json:"lag"`
-	CutoffAt      core.CutoffAt   `json:"cutoff_at"`
-	CohortSize    int             `json:"cohort_size"`
-	EntityIDsHash core.Hash       `json:"entity_ids_hash"`
-	ViewID        core.ID         `json:"view_id"`
-	CohortHash    core.CohortHash `json:"cohort_hash"`
-	CreatedAt     core.Timestamp  `json:"created_at"`
+	SnapshotID    core.SnapshotID       `json:"snapshot_id"`
+	SnapshotAt    core.SnapshotAt       `json:"snapshot_at"`
+	Lag           core.Lag              `json:"lag"`
+	CutoffAt      core.CutoffAt         `json:"cutoff_at"`
+	TimeZone      core.TimeZoneDecision `json:"time_zone"` // how the dataset's wall clock was read
+	CohortSize    int                   `json:"cohort_size"`
+	EntityIDsHash core.Hash             `json:"entity_ids_hash"`
+	ViewID        core.ID               `json:"view_id"`
+	CohortHash    core.CohortHash       `json:"cohort_hash"`
+	CreatedAt     core.Timestamp        `json:"created_at"`
 }
 
-// NewSnapshotManifest creates a manifest for a snapshot resolution
-func NewSnapshotManifest(snapshotID core.SnapshotID, snapshotAt core.SnapshotAt, lag core.Lag, entityIDs []core.ID, viewID core.ID, cohortHash core.CohortHash) *SnapshotManifest {
+// NewSnapshotManifest creates a manifest for a snapshot resolution. The lag is applied on
+// the calendar of the dataset's zone, and the zone is part of the manifest's hash.
+func NewSnapshotManifest(snapshotID core.SnapshotID, snapshotAt core.SnapshotAt, lag core.Lag, zone core.TimeZone, entityIDs []core.ID, viewID core.ID, cohortHash core.CohortHash) *SnapshotManifest {
 	// Compute entity IDs hash for audit trail
 	entityHash := computeEntityIDsHash(entityIDs)
+	cutoff := zone.ApplyLag(snapshotAt, lag)
 
 	return &SnapshotManifest{
 		SnapshotID:    snapshotID,
 		SnapshotAt:    snapshotAt,
 		Lag:           lag,
-		CutoffAt:      snapshotAt.ApplyLag(lag),
+		CutoffAt:      cutoff,
+		TimeZone:      zone.Decision(cutoff),
 		CohortSize:    len(entityIDs),
 		EntityIDsHash: entityHash,
 		ViewID:        viewID,
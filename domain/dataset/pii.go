@@ -0,0 +1,71 @@
+package dataset
+
+import (
+	"time"
+
+	"gohypo/domain/core"
+)
+
+// PIIKind is the kind of personal data a column was flagged for
+type PIIKind string
+
+const (
+	PIIEmail PIIKind = "email"
+	PIIPhone PIIKind = "phone"
+	PIISSN   PIIKind = "ssn"
+	PIIName  PIIKind = "name"
+)
+
+// PIIReport records the PII scan of a dataset and the decision taken on every flagged column
+type PIIReport struct {
+	ScannedAt   time.Time   `json:"scanned_at"`
+	RowsScanned int         `json:"rows_scanned"`
+	Columns     []PIIColumn `json:"columns"`
+}
+
+// PIIColumn is a column the scanner flagged. Flagged columns are excluded from matrices
+// unless the LLM rejected the flag or a user overrode the decision.
+type PIIColumn struct {
+	Field        string       `json:"field"`
+	Kind         PIIKind      `json:"kind"`
+	MatchRate    float64      `json:"match_rate"`              // share of non-empty values matching the pattern
+	Source       string       `json:"source"`                  // "regex" or "regex+llm"
+	LLMConfirmed *bool        `json:"llm_confirmed,omitempty"` // nil when no LLM was asked
+	Excluded     bool         `json:"excluded"`
+	Override     *PIIOverride `json:"override,omitempty"`
+}
+
+// PIIOverride is a user's decision to include or exclude a flagged column regardless of the scan
+type PIIOverride struct {
+	Include   bool      `json:"include"`
+	Reason    string    `json:"reason,omitempty"`
+	UserID    core.ID   `json:"user_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Column returns the flagged column with the given name
+func (r *PIIReport) Column(field string) *PIIColumn {
+	if r == nil {
+		return nil
+	}
+	for i := range r.Columns {
+		if r.Columns[i].Field == field {
+			return &r.Columns[i]
+		}
+	}
+	return nil
+}
+
+// ExcludedFields returns the names of the columns to keep out of matrices
+func (m DatasetMetadata) ExcludedFields() map[string]bool {
+	excluded := make(map[string]bool)
+	if m.PII == nil {
+		return excluded
+	}
+	for _, col := range m.PII.Columns {
+		if col.Excluded {
+			excluded[col.Field] = true
+		}
+	}
+	return excluded
+}
@@ -63,7 +63,7 @@ type Dataset struct {
 	RecordCount int     `json:"record_count"`
 	FieldCount  int     `json:"field_count"`
 	MissingRate float64 `json:"missing_rate"`
-	Source      string  `json:"source"` // "upload", "excel", "api"
+	Source      string  `json:"source"` // "upload", "excel", "api", "sql"
 
 	// Processing state
 	Status       DatasetStatus `json:"status"`
@@ -86,6 +86,24 @@ type DatasetMetadata struct {
 	SampleRows []map[string]interface{} `json:"sample_rows"`
 	AIAnalysis ForensicScoutResult      `json:"ai_analysis"`
 	FileInfo   FileInfo                 `json:"file_info,omitempty"`
+	PII        *PIIReport               `json:"pii,omitempty"`
+	Provenance *Provenance              `json:"provenance,omitempty"` // set for datasets pulled from a source
+	Locale     *Locale                  `json:"locale,omitempty"`     // how the file wrote numbers and dates
+	TimeZone   *core.TimeZone           `json:"time_zone,omitempty"`  // zone of timestamps without an offset; UTC when nil
+	Lineage    *Lineage                 `json:"lineage,omitempty"`    // set for datasets derived from other datasets
+}
+
+// Provenance records where a dataset pulled from an external source came from
+type Provenance struct {
+	Kind      string    `json:"kind"` // "sql", "url" or "google_sheets"
+	SourceID  string    `json:"source_id,omitempty"`
+	Driver    string    `json:"driver,omitempty"`
+	Location  string    `json:"location,omitempty"` // connection string or URL, credentials redacted
+	Query     string    `json:"query,omitempty"`
+	Rows      int       `json:"rows,omitempty"`
+	Truncated bool      `json:"truncated,omitempty"` // the row limit cut the result short
+	Checksum  string    `json:"checksum,omitempty"`  // SHA-256 of the fetched file
+	PulledAt  time.Time `json:"pulled_at"`
 }
 
 // FieldInfo describes a single field/column in the dataset
@@ -96,7 +114,27 @@ type FieldInfo struct {
 	UniqueCount  int                    `json:"unique_count"`
 	MissingCount int                    `json:"missing_count"`
 	SampleValues []interface{}          `json:"sample_values,omitempty"`
-	Statistics   map[string]interface{} `json:"statistics,omitempty"` // min, max, mean, etc.
+	Statistics   map[string]interface{} `json:"statistics,omitempty"`    // min, max, mean, etc.
+	OriginalName string                 `json:"original_name,omitempty"` // header Name was normalized from
+	Unit         string                 `json:"unit,omitempty"`          // unit stripped from the header
+}
+
+// DisplayName returns the header the field was read from, or its name for fields stored
+// before headers were normalized
+func (f FieldInfo) DisplayName() string {
+	if f.OriginalName != "" {
+		return f.OriginalName
+	}
+	return f.Name
+}
+
+// ColumnNames returns the mapping table of the dataset's fields
+func (m DatasetMetadata) ColumnNames() ColumnNames {
+	names := make(ColumnNames, len(m.Fields))
+	for i, field := range m.Fields {
+		names[i] = ColumnName{Key: field.Name, Original: field.DisplayName(), Unit: field.Unit}
+	}
+	return names
 }
 
 // ForensicScoutResult contains the AI analysis results
@@ -122,6 +160,19 @@ type DatasetUpload struct {
 	Filename    string
 	File        multipart.File // Multipart file from HTTP upload
 	MimeType    string
+
+	// Set when the file was pulled from a data source rather than uploaded (optional)
+	Source     string
+	Provenance *Provenance
+
+	// Locale the file writes numbers and dates in; empty or "auto" detects it (optional)
+	Locale string
+
+	// IANA zone the file's timestamps were recorded in, e.g. Europe/Berlin; UTC when empty (optional)
+	TimeZone string
+
+	// Datasets the file was derived from, e.g. by a merge (optional)
+	Lineage *Lineage
 }
 
 // NewDataset creates a new dataset with default values
@@ -21,6 +21,7 @@ type ResearchDirective struct {
 	RefereeGates          RefereeGates          `json:"referee_gates"`
 	ExplanationMarkdown string              `json:"explanation_markdown"`
 	ExplanationStructure models.ExplanationStructure `json:"explanation_structure"` // Legacy
+	SelfConsistency       *models.SelfConsistency  `json:"self_consistency,omitempty"` // Set when generation sampled for consensus
 	CreatedAt             core.Timestamp           `json:"created_at"`
 }
 
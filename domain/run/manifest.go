@@ -8,18 +8,19 @@ import (
 // RunManifestArtifact represents the complete specification for a run
 // This is the "truth source" for replay - must exist before any stage artifacts
 type RunManifestArtifact struct {
-	RunID         core.RunID         `json:"run_id"`
-	SnapshotID    core.SnapshotID    `json:"snapshot_id"`
-	SnapshotAt    core.SnapshotAt    `json:"snapshot_at"`
-	Lag           core.Lag           `json:"lag"`
-	CutoffAt      core.CutoffAt      `json:"cutoff_at"`
-	RegistryHash  core.RegistryHash  `json:"registry_hash"`
-	CohortHash    core.CohortHash    `json:"cohort_hash"`
-	StagePlanHash core.StageListHash `json:"stage_plan_hash"`
-	Seed          int64              `json:"seed"`
-	CodeVersion   string             `json:"code_version"`
-	Fingerprint   RunFingerprint     `json:"fingerprint"` // Determinism fingerprint
-	CreatedAt     core.Timestamp     `json:"created_at"`
+	RunID         core.RunID            `json:"run_id"`
+	SnapshotID    core.SnapshotID       `json:"snapshot_id"`
+	SnapshotAt    core.SnapshotAt       `json:"snapshot_at"`
+	Lag           core.Lag              `json:"lag"`
+	CutoffAt      core.CutoffAt         `json:"cutoff_at"`
+	TimeZone      core.TimeZoneDecision `json:"time_zone"`
+	RegistryHash  core.RegistryHash     `json:"registry_hash"`
+	CohortHash    core.CohortHash       `json:"cohort_hash"`
+	StagePlanHash core.StageListHash    `json:"stage_plan_hash"`
+	Seed          int64                 `json:"seed"`
+	CodeVersion   string                `json:"code_version"`
+	Fingerprint   RunFingerprint        `json:"fingerprint"` // Determinism fingerprint
+	CreatedAt     core.Timestamp        `json:"created_at"`
 }
 
 // NewRunManifestArtifact creates a run manifest from a pipeline request
@@ -29,6 +30,7 @@ func NewRunManifestArtifact(
 	snapshotAt core.SnapshotAt,
 	lag core.Lag,
 	cutoffAt core.CutoffAt,
+	zone core.TimeZone,
 	registryHash core.RegistryHash,
 	cohortHash core.CohortHash,
 	stagePlan *stage.StagePlan,
@@ -44,6 +46,7 @@ func NewRunManifestArtifact(
 		SnapshotAt:    snapshotAt,
 		Lag:           lag,
 		CutoffAt:      cutoffAt,
+		TimeZone:      zone.Decision(cutoffAt),
 		RegistryHash:  registryHash,
 		CohortHash:    cohortHash,
 		StagePlanHash: stagePlanHash,
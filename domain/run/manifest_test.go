@@ -114,7 +114,7 @@ func TestRunManifestArtifact_Complete(t *testing.T) {
 	}
 
 	manifest := NewRunManifestArtifact(
-		runID, snapshotID, snapshotAt, lag, cutoffAt,
+		runID, snapshotID, snapshotAt, lag, cutoffAt, core.UTCZone(),
 		registryHash, cohortHash, stagePlan, seed, codeVersion,
 	)
 
@@ -137,6 +137,9 @@ func TestRunManifestArtifact_Complete(t *testing.T) {
 	if manifest.CodeVersion != codeVersion {
 		t.Errorf("CodeVersion not set correctly")
 	}
+	if manifest.TimeZone.Zone != "UTC" || manifest.TimeZone.Source != core.TimeZoneFromDefault {
+		t.Errorf("TimeZone not recorded: %+v", manifest.TimeZone)
+	}
 
 	// Verify fingerprint is computed
 	if manifest.Fingerprint.Fingerprint == "" {
@@ -7,6 +7,7 @@ import (
 
 	"gohypo/domain/core"
 	"gohypo/domain/dataset"
+	"gohypo/domain/stats"
 )
 
 // StageName represents a named stage in the pipeline
@@ -30,6 +31,25 @@ const (
 	RigorDecision RigorProfile = "decision" // full validation for decisions
 )
 
+// FDRMethod returns the default multiple-comparison correction for the profile: decision
+// runs tolerate arbitrary dependence, every other run keeps Benjamini-Hochberg. Storey's
+// q-value is only used when a sweep asks for it.
+func (r RigorProfile) FDRMethod() stats.FDRMethod {
+	if r == RigorDecision {
+		return stats.FDRBenjaminiYekutiel
+	}
+	return stats.FDRBenjaminiHochberg
+}
+
+// InferenceMode returns the default inference framework for the profile: decision runs
+// report Bayes factors and posteriors next to p-values
+func (r RigorProfile) InferenceMode() stats.InferenceMode {
+	if r == RigorDecision {
+		return stats.InferenceBoth
+	}
+	return stats.InferenceFrequentist
+}
+
 // Predefined stage names
 const (
 	// Stats stages
@@ -0,0 +1,124 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// GroupComparison tests whether a numeric variable differs across the levels of a categorical
+// one. ANOVA compares the group means; Kruskal-Wallis compares ranks and so holds up when
+// the groups are skewed or have outliers. Both report an eta-squared effect size: the share
+// of the numeric variable's variance (of its ranks, for Kruskal-Wallis) the grouping explains.
+type GroupComparison struct {
+	Groups     int `json:"groups"`
+	SampleSize int `json:"sample_size"`
+
+	FStatistic  float64 `json:"f_statistic"`
+	ANOVAPValue float64 `json:"anova_p_value"`
+	EtaSquared  float64 `json:"eta_squared"`
+
+	HStatistic    float64 `json:"h_statistic"` // tie-corrected
+	KruskalPValue float64 `json:"kruskal_p_value"`
+	EtaSquaredH   float64 `json:"eta_squared_h"` // (H - k + 1) / (n - k), floored at 0
+}
+
+// CompareGroups runs one-way ANOVA and Kruskal-Wallis on the groups' values. It needs at
+// least two groups and more observations than groups.
+func CompareGroups(groups [][]float64) (*GroupComparison, error) {
+	k := len(groups)
+	if k < 2 {
+		return nil, fmt.Errorf("group comparison needs at least 2 groups, got %d", k)
+	}
+	n := 0
+	grandSum := 0.0
+	for i, group := range groups {
+		if len(group) == 0 {
+			return nil, fmt.Errorf("group %d is empty", i)
+		}
+		n += len(group)
+		for _, v := range group {
+			grandSum += v
+		}
+	}
+	if n <= k {
+		return nil, fmt.Errorf("group comparison needs more observations (%d) than groups (%d)", n, k)
+	}
+	grandMean := grandSum / float64(n)
+
+	// One-way ANOVA
+	ssBetween, ssWithin := 0.0, 0.0
+	for _, group := range groups {
+		mean := 0.0
+		for _, v := range group {
+			mean += v
+		}
+		mean /= float64(len(group))
+		ssBetween += float64(len(group)) * (mean - grandMean) * (mean - grandMean)
+		for _, v := range group {
+			ssWithin += (v - mean) * (v - mean)
+		}
+	}
+	result := &GroupComparison{Groups: k, SampleSize: n}
+	dfBetween, dfWithin := float64(k-1), float64(n-k)
+	if total := ssBetween + ssWithin; total > 0 {
+		result.EtaSquared = ssBetween / total
+	}
+	switch {
+	case ssWithin > 0:
+		result.FStatistic = (ssBetween / dfBetween) / (ssWithin / dfWithin)
+		result.ANOVAPValue = distuv.F{D1: dfBetween, D2: dfWithin}.Survival(result.FStatistic)
+	case ssBetween > 0:
+		// Constant within groups but different between them: perfectly separated
+		result.FStatistic = math.Inf(1)
+		result.ANOVAPValue = 0
+	default:
+		result.ANOVAPValue = 1
+	}
+
+	// Kruskal-Wallis on mid-ranks, corrected for ties
+	type observation struct {
+		value float64
+		group int
+	}
+	all := make([]observation, 0, n)
+	for g, group := range groups {
+		for _, v := range group {
+			all = append(all, observation{v, g})
+		}
+	}
+	sort.Slice(all, func(a, b int) bool { return all[a].value < all[b].value })
+	rankSums := make([]float64, k)
+	tieTerm := 0.0
+	for i := 0; i < n; {
+		j := i
+		for j < n && all[j].value == all[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2 // mean of ranks i+1..j
+		for t := i; t < j; t++ {
+			rankSums[all[t].group] += rank
+		}
+		ties := float64(j - i)
+		tieTerm += ties*ties*ties - ties
+		i = j
+	}
+	nf := float64(n)
+	correction := 1 - tieTerm/(nf*nf*nf-nf)
+	if correction <= 0 {
+		// Every observation is tied: no evidence either way
+		result.KruskalPValue = 1
+		return result, nil
+	}
+	h := 0.0
+	for g, group := range groups {
+		h += rankSums[g] * rankSums[g] / float64(len(group))
+	}
+	h = (12/(nf*(nf+1))*h - 3*(nf+1)) / correction
+	result.HStatistic = h
+	result.KruskalPValue = distuv.ChiSquared{K: dfBetween}.Survival(h)
+	result.EtaSquaredH = math.Max(0, (h-float64(k)+1)/(nf-float64(k)))
+	return result, nil
+}
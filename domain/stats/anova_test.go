@@ -0,0 +1,37 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// TestCompareGroupsSeparatesLevels verifies shifted groups are detected by both tests and
+// identical ones are not
+func TestCompareGroupsSeparatesLevels(t *testing.T) {
+	shifted, err := CompareGroups([][]float64{
+		{1, 2, 3, 2, 1, 2},
+		{5, 6, 7, 6, 5, 6},
+		{9, 10, 11, 10, 9, 10},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shifted.ANOVAPValue > 0.001 || shifted.KruskalPValue > 0.01 {
+		t.Fatalf("expected both tests to reject, got %+v", shifted)
+	}
+	if shifted.EtaSquared < 0.9 || shifted.EtaSquaredH < 0.7 {
+		t.Fatalf("expected large effect sizes, got %+v", shifted)
+	}
+
+	same, _ := CompareGroups([][]float64{{1, 2, 3, 4}, {4, 3, 2, 1}, {2, 3, 1, 4}})
+	if same.ANOVAPValue < 0.9 || same.EtaSquared > 1e-9 {
+		t.Fatalf("expected no difference between identical groups, got %+v", same)
+	}
+	if math.Abs(same.HStatistic) > 1e-9 {
+		t.Fatalf("expected H of zero, got %.6f", same.HStatistic)
+	}
+
+	if _, err := CompareGroups([][]float64{{1, 2, 3}}); err == nil {
+		t.Fatal("expected an error for a single group")
+	}
+}
@@ -0,0 +1,171 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// InferenceMode selects which statistical framework reports on each pair
+type InferenceMode string
+
+const (
+	InferenceFrequentist InferenceMode = "frequentist" // p-values and FDR q-values
+	InferenceBayesian    InferenceMode = "bayesian"    // Bayes factors and posteriors lead; p-values are kept for compatibility
+	InferenceBoth        InferenceMode = "both"        // both, side by side
+)
+
+// ParseInferenceMode normalizes user input (e.g. "Bayes", "nhst") to an InferenceMode
+func ParseInferenceMode(s string) (InferenceMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "frequentist", "nhst":
+		return InferenceFrequentist, nil
+	case "bayesian", "bayes":
+		return InferenceBayesian, nil
+	case "both":
+		return InferenceBoth, nil
+	default:
+		return "", fmt.Errorf("unsupported inference mode: %q", s)
+	}
+}
+
+// Bayesian reports whether the mode computes Bayes factors
+func (m InferenceMode) Bayesian() bool {
+	return m == InferenceBayesian || m == InferenceBoth
+}
+
+// credibleLevel is the posterior mass of reported credible intervals
+const credibleLevel = 0.95
+
+// BayesianEstimate is the Bayesian counterpart of a p-value for one pair
+type BayesianEstimate struct {
+	Method         string  `json:"method"` // "jzs_correlation" or "beta_binomial"
+	Prior          string  `json:"prior"`
+	BayesFactor10  float64 `json:"bayes_factor_10"` // evidence for an effect over none
+	LogBF10        float64 `json:"log_bf_10"`
+	PosteriorMean  float64 `json:"posterior_mean"`
+	CredibleLower  float64 `json:"credible_lower"`
+	CredibleUpper  float64 `json:"credible_upper"`
+	CredibleLevel  float64 `json:"credible_level"`
+	Interpretation string  `json:"interpretation"`
+}
+
+// JZSCorrelation computes the Jeffreys-Zellner-Siow Bayes factor for a Pearson correlation r
+// over n observations (Wetzels & Wagenmakers 2012) and an approximate posterior for rho from
+// Fisher's z. The integral over the g prior is evaluated on a log-spaced grid.
+func JZSCorrelation(r float64, n int) (*BayesianEstimate, error) {
+	if n < 4 {
+		return nil, fmt.Errorf("JZS Bayes factor needs at least 4 observations, got %d", n)
+	}
+	r2 := math.Min(r*r, 1-1e-12)
+	nf := float64(n)
+
+	// BF10 = sqrt(n/2)/Γ(1/2) ∫ (1+g)^((n-2)/2) (1+(1-r²)g)^(-(n-1)/2) g^(-3/2) e^(-n/2g) dg,
+	// integrated over t = ln g so the Jacobian adds one power of g
+	const lo, hi, steps = -20.0, 25.0, 4500
+	dt := (hi - lo) / steps
+	logTerms := make([]float64, steps+1)
+	for i := range logTerms {
+		t := lo + float64(i)*dt
+		g := math.Exp(t)
+		logTerms[i] = (nf-2)/2*math.Log1p(g) - (nf-1)/2*math.Log1p((1-r2)*g) - 0.5*t - nf/(2*g)
+		if i == 0 || i == steps {
+			logTerms[i] += math.Log(0.5) // trapezoid end weights
+		}
+	}
+	logBF := 0.5*math.Log(nf/2) - 0.5*math.Log(math.Pi) + logSumExp(logTerms) + math.Log(dt)
+
+	z := math.Atanh(math.Max(-1+1e-12, math.Min(1-1e-12, r)))
+	half := normalQuantile((1+credibleLevel)/2) / math.Sqrt(nf-3)
+	return &BayesianEstimate{
+		Method:         "jzs_correlation",
+		Prior:          "JZS (Cauchy scale 1 on the standardized slope)",
+		BayesFactor10:  math.Exp(logBF),
+		LogBF10:        logBF,
+		PosteriorMean:  r,
+		CredibleLower:  math.Tanh(z - half),
+		CredibleUpper:  math.Tanh(z + half),
+		CredibleLevel:  credibleLevel,
+		Interpretation: InterpretBayesFactor(math.Exp(logBF)),
+	}, nil
+}
+
+// BetaBinomial compares two proportions, successes1/n1 against successes2/n2, under uniform
+// Beta(1,1) priors: H1 gives each group its own rate, H0 one shared rate. The posterior is
+// for the difference p1 - p2, approximated as normal from the two Beta posteriors.
+func BetaBinomial(successes1, n1, successes2, n2 int) (*BayesianEstimate, error) {
+	if n1 <= 0 || n2 <= 0 || successes1 < 0 || successes2 < 0 || successes1 > n1 || successes2 > n2 {
+		return nil, fmt.Errorf("invalid counts %d/%d and %d/%d", successes1, n1, successes2, n2)
+	}
+	const a, b = 1.0, 1.0
+	s1, f1 := float64(successes1), float64(n1-successes1)
+	s2, f2 := float64(successes2), float64(n2-successes2)
+	logBF := logBeta(a+s1, b+f1) + logBeta(a+s2, b+f2) - logBeta(a, b) - logBeta(a+s1+s2, b+f1+f2)
+
+	mean1, var1 := betaMoments(a+s1, b+f1)
+	mean2, var2 := betaMoments(a+s2, b+f2)
+	diff := mean1 - mean2
+	half := normalQuantile((1+credibleLevel)/2) * math.Sqrt(var1+var2)
+	return &BayesianEstimate{
+		Method:         "beta_binomial",
+		Prior:          "Beta(1,1) per group",
+		BayesFactor10:  math.Exp(logBF),
+		LogBF10:        logBF,
+		PosteriorMean:  diff,
+		CredibleLower:  math.Max(-1, diff-half),
+		CredibleUpper:  math.Min(1, diff+half),
+		CredibleLevel:  credibleLevel,
+		Interpretation: InterpretBayesFactor(math.Exp(logBF)),
+	}, nil
+}
+
+// InterpretBayesFactor labels BF10 on the Lee & Wagenmakers (2013) adaptation of Jeffreys' scale
+func InterpretBayesFactor(bf10 float64) string {
+	direction, strength := "effect", bf10
+	if bf10 < 1 {
+		direction, strength = "no effect", 1/bf10
+	}
+	switch {
+	case strength > 100:
+		return "extreme evidence for " + direction
+	case strength > 30:
+		return "very strong evidence for " + direction
+	case strength > 10:
+		return "strong evidence for " + direction
+	case strength > 3:
+		return "moderate evidence for " + direction
+	case strength > 1:
+		return "anecdotal evidence for " + direction
+	default:
+		return "no evidence either way"
+	}
+}
+
+func logBeta(a, b float64) float64 {
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	return la + lb - lab
+}
+
+func betaMoments(a, b float64) (float64, float64) {
+	mean := a / (a + b)
+	return mean, a * b / ((a + b) * (a + b) * (a + b + 1))
+}
+
+func logSumExp(values []float64) float64 {
+	peak := math.Inf(-1)
+	for _, v := range values {
+		peak = math.Max(peak, v)
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += math.Exp(v - peak)
+	}
+	return peak + math.Log(sum)
+}
+
+// normalQuantile is the standard normal inverse CDF
+func normalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
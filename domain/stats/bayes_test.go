@@ -0,0 +1,52 @@
+package stats
+
+import "testing"
+
+// TestJZSCorrelationWeighsEvidence verifies strong correlations favour H1 and null ones H0
+func TestJZSCorrelationWeighsEvidence(t *testing.T) {
+	strong, err := JZSCorrelation(0.5, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strong.BayesFactor10 < 30 || strong.CredibleLower <= 0 || strong.CredibleUpper >= 1 {
+		t.Fatalf("expected very strong evidence with an interval above zero, got %+v", strong)
+	}
+
+	null, _ := JZSCorrelation(0.01, 300)
+	if null.BayesFactor10 > 1.0/3 {
+		t.Fatalf("expected evidence for no effect, got %+v", null)
+	}
+
+	if _, err := JZSCorrelation(0.9, 3); err == nil {
+		t.Fatal("expected an error for tiny samples")
+	}
+}
+
+// TestBetaBinomialComparesProportions verifies differing rates yield BF10 > 1 and equal ones < 1
+func TestBetaBinomialComparesProportions(t *testing.T) {
+	differ, err := BetaBinomial(80, 100, 40, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if differ.BayesFactor10 < 100 || differ.PosteriorMean < 0.3 {
+		t.Fatalf("expected extreme evidence for a ~0.4 difference, got %+v", differ)
+	}
+
+	same, _ := BetaBinomial(50, 100, 51, 100)
+	if same.BayesFactor10 >= 1 {
+		t.Fatalf("expected evidence for equal rates, got %+v", same)
+	}
+}
+
+// TestParseInferenceMode verifies aliases and rejection of unknown modes
+func TestParseInferenceMode(t *testing.T) {
+	if mode, _ := ParseInferenceMode("Bayes"); mode != InferenceBayesian || !mode.Bayesian() {
+		t.Fatalf("unexpected mode %q", mode)
+	}
+	if mode, _ := ParseInferenceMode(""); mode.Bayesian() {
+		t.Fatal("frequentist must be the default")
+	}
+	if _, err := ParseInferenceMode("fiducial"); err == nil {
+		t.Fatal("expected an error for an unknown mode")
+	}
+}
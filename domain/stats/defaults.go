@@ -0,0 +1,137 @@
+package stats
+
+import "math"
+
+// Bounds of the per-variable test defaults
+const (
+	minDefaultBins  = 2
+	maxDefaultBins  = 50
+	maxMinGroupSize = 30
+	floorGroupSize  = 2
+	minGroupShare   = 0.1 // share of the mean level size a level needs to be compared
+	smallSampleSize = 30  // below this many present values the trim is not raised above the default
+)
+
+// VariableProfile is what the per-variable test defaults are chosen from: the shape,
+// cardinality and missingness measures of a readiness profile
+type VariableProfile struct {
+	Rows        int     `json:"rows"`         // rows including missing ones
+	MissingRate float64 `json:"missing_rate"` // share of rows missing, 0 to 1
+	Cardinality int     `json:"cardinality"`  // distinct present values
+	Skewness    float64 `json:"skewness"`     // sample skewness of the present values
+}
+
+// Present returns the number of rows with a value
+func (p VariableProfile) Present() int {
+	return int(math.Round(float64(p.Rows) * (1 - p.MissingRate)))
+}
+
+// ProfileValues profiles a column, NaN and ±Inf counting as missing
+func ProfileValues(values []float64) VariableProfile {
+	profile := VariableProfile{Rows: len(values)}
+	distinct := make(map[float64]bool)
+	var present []float64
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		present = append(present, v)
+		distinct[v] = true
+	}
+	profile.Cardinality = len(distinct)
+	if len(values) > 0 {
+		profile.MissingRate = float64(len(values)-len(present)) / float64(len(values))
+	}
+	profile.Skewness = sampleSkewness(present)
+	return profile
+}
+
+// TestDefaults are the test parameters chosen for one variable from its profile
+type TestDefaults struct {
+	Bins         int     `json:"bins"`           // histogram bins for binned estimates
+	WinsorTrim   float64 `json:"winsor_trim"`    // fraction of each tail the winsorized correlation clamps
+	MinGroupSize int     `json:"min_group_size"` // fewest rows a level needs in a group comparison
+}
+
+// DefaultsFor chooses a variable's test parameters from its profile. Missingness enters
+// through the present rows every choice is sized by.
+//
+//   - Bins follow Doane's rule, which adds bins for skewed data, capped by the cardinality.
+//   - The winsorizing trim grows with |skewness|: 0.05 for near-symmetric data up to 0.2
+//     for heavily skewed data, held at DefaultWinsorTrim for small samples.
+//   - The minimum group size is a tenth of the mean level size, between 2 and 30, so
+//     levels far rarer than the others are left out of the comparison.
+func DefaultsFor(p VariableProfile) TestDefaults {
+	n := p.Present()
+	return TestDefaults{
+		Bins:         doaneBins(n, p.Cardinality, p.Skewness),
+		WinsorTrim:   winsorTrimFor(n, p.Skewness),
+		MinGroupSize: minGroupSizeFor(n, p.Cardinality),
+	}
+}
+
+// PairWinsorTrim is the trim of a pair: one trim applies to both variables, so the more
+// skewed one sets it
+func PairWinsorTrim(x, y TestDefaults) float64 {
+	return math.Max(x.WinsorTrim, y.WinsorTrim)
+}
+
+func doaneBins(n, cardinality int, skewness float64) int {
+	if n < 3 || cardinality < minDefaultBins {
+		return minDefaultBins
+	}
+	nf := float64(n)
+	sigma := math.Sqrt(6 * (nf - 2) / ((nf + 1) * (nf + 3)))
+	bins := int(math.Ceil(1 + math.Log2(nf) + math.Log2(1+math.Abs(skewness)/sigma)))
+	bins = min(bins, cardinality, maxDefaultBins)
+	return max(bins, minDefaultBins)
+}
+
+func winsorTrimFor(n int, skewness float64) float64 {
+	skew := math.Abs(skewness)
+	trim := 0.2
+	switch {
+	case skew < 0.5:
+		trim = 0.05
+	case skew < 1:
+		trim = DefaultWinsorTrim
+	case skew < 2:
+		trim = 0.15
+	}
+	if n < smallSampleSize {
+		trim = math.Min(trim, DefaultWinsorTrim)
+	}
+	return trim
+}
+
+func minGroupSizeFor(n, cardinality int) int {
+	if cardinality < 1 {
+		return floorGroupSize
+	}
+	size := int(math.Round(minGroupShare * float64(n) / float64(cardinality)))
+	return min(max(size, floorGroupSize), maxMinGroupSize)
+}
+
+// sampleSkewness is the moment coefficient of skewness, 0 for fewer than 3 values or no spread
+func sampleSkewness(values []float64) float64 {
+	if len(values) < 3 {
+		return 0
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	var m2, m3 float64
+	for _, v := range values {
+		d := v - mean
+		m2 += d * d
+		m3 += d * d * d
+	}
+	m2 /= float64(len(values))
+	m3 /= float64(len(values))
+	if m2 == 0 {
+		return 0
+	}
+	return m3 / math.Pow(m2, 1.5)
+}
@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestDefaultsForFollowProfile verifies skewed variables get a wider trim and more bins than
+// symmetric ones, and that missing rows shrink what the sizes are based on
+func TestDefaultsForFollowProfile(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	symmetric := make([]float64, 500)
+	skewed := make([]float64, 500)
+	for i := range symmetric {
+		symmetric[i] = rng.NormFloat64()
+		skewed[i] = math.Exp(1.2 * rng.NormFloat64())
+	}
+	sym, skew := DefaultsFor(ProfileValues(symmetric)), DefaultsFor(ProfileValues(skewed))
+	if sym.WinsorTrim != 0.05 || skew.WinsorTrim != 0.2 {
+		t.Errorf("trims = %g (symmetric), %g (skewed), want 0.05 and 0.2", sym.WinsorTrim, skew.WinsorTrim)
+	}
+	if skew.Bins <= sym.Bins {
+		t.Errorf("skewed data got %d bins, symmetric %d; want more for skewed", skew.Bins, sym.Bins)
+	}
+	if got := PairWinsorTrim(sym, skew); got != 0.2 {
+		t.Errorf("pair trim = %g, want the skewed variable's 0.2", got)
+	}
+
+	full := VariableProfile{Rows: 1000, Cardinality: 4}
+	if got := DefaultsFor(full).MinGroupSize; got != 25 {
+		t.Errorf("min group size = %d, want a tenth of 250 rows per level", got)
+	}
+	sparse := full
+	sparse.MissingRate = 0.6
+	if got := DefaultsFor(sparse).MinGroupSize; got != 10 {
+		t.Errorf("min group size with 60%% missing = %d, want a tenth of 100 rows per level", got)
+	}
+}
+
+// TestDefaultsForBounds verifies the chosen parameters stay usable at the extremes
+func TestDefaultsForBounds(t *testing.T) {
+	tiny := DefaultsFor(VariableProfile{Rows: 12, Cardinality: 3, Skewness: 4})
+	if tiny.WinsorTrim != DefaultWinsorTrim {
+		t.Errorf("small-sample trim = %g, want it held at %g", tiny.WinsorTrim, DefaultWinsorTrim)
+	}
+	if tiny.Bins != 3 || tiny.MinGroupSize != 2 {
+		t.Errorf("small sample got %d bins and min group size %d, want 3 and 2", tiny.Bins, tiny.MinGroupSize)
+	}
+	huge := DefaultsFor(VariableProfile{Rows: 1000000, Cardinality: 1000000, Skewness: 10})
+	if huge.Bins > maxDefaultBins || huge.MinGroupSize != 2 {
+		t.Errorf("identifier-like variable got %d bins and min group size %d, want at most %d and 2", huge.Bins, huge.MinGroupSize, maxDefaultBins)
+	}
+	if coded := DefaultsFor(VariableProfile{Rows: 1000, Cardinality: 5}); coded.Bins != 5 {
+		t.Errorf("five-valued variable got %d bins, want one per value", coded.Bins)
+	}
+	if empty := DefaultsFor(ProfileValues(nil)); empty.Bins != 2 || empty.MinGroupSize != 2 {
+		t.Errorf("empty column got %+v", empty)
+	}
+}
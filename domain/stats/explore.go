@@ -0,0 +1,90 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// ExploreMaxPoints caps the rows a pair exploration keeps for its scatter plot
+const ExploreMaxPoints = 500
+
+const exploreMinRows = 3
+
+// PairTest is the on-demand test of one variable pair: its correlation judged under a policy,
+// with a 95% interval, the robust estimates, and an evenly spaced sample of the rows to plot
+type PairTest struct {
+	Rows         int               `json:"rows"`
+	CompleteRows int               `json:"complete_rows"`
+	MissingX     int               `json:"missing_x"`
+	MissingY     int               `json:"missing_y"`
+	Correlation  float64           `json:"correlation"`
+	PValue       float64           `json:"p_value"`
+	CILow        float64           `json:"ci_low"`
+	CIHigh       float64           `json:"ci_high"`
+	Significant  bool              `json:"significant"`
+	Meaningful   bool              `json:"meaningful"`
+	Strength     string            `json:"strength"`
+	Evidence     string            `json:"evidence"`
+	Robust       *RobustComparison `json:"robust,omitempty"` // nil when the robust estimators are undefined
+	Points       [][2]float64      `json:"points"`
+	Summary      string            `json:"summary"`
+}
+
+// TestPair tests the association of x and y over the rows where both are present, under policy
+func TestPair(x, y []float64, policy AnalysisPolicy) (*PairTest, error) {
+	policy = policy.WithDefaults()
+	test := &PairTest{Rows: max(len(x), len(y))}
+	for i := 0; i < test.Rows; i++ {
+		if i >= len(x) || math.IsNaN(x[i]) {
+			test.MissingX++
+		}
+		if i >= len(y) || math.IsNaN(y[i]) {
+			test.MissingY++
+		}
+	}
+	xs, ys := completePairs(x, y)
+	n := len(xs)
+	test.CompleteRows = n
+	if n < exploreMinRows {
+		return nil, fmt.Errorf("only %d rows have both values; at least %d are needed", n, exploreMinRows)
+	}
+	r := stat.Correlation(xs, ys, nil)
+	if math.IsNaN(r) {
+		return nil, fmt.Errorf("correlation is undefined: one of the variables is constant")
+	}
+	test.Correlation = r
+	test.PValue = correlationPValue(r, float64(n-2))
+	test.CILow, test.CIHigh = r, r
+	if n > 3 {
+		half := 1.959964 / math.Sqrt(float64(n-3))
+		test.CILow, test.CIHigh = math.Tanh(fisherZ(r)-half), math.Tanh(fisherZ(r)+half)
+	}
+	test.Significant = policy.Significant(test.PValue)
+	test.Meaningful = policy.Meaningful(r)
+	test.Strength = policy.EffectStrength(r)
+	test.Evidence = policy.EvidenceLevel(test.PValue)
+	if comparison, err := CompareRobust(xs, ys, r, RobustBoth); err == nil {
+		test.Robust = comparison
+	}
+
+	stride := max(1, (n+ExploreMaxPoints-1)/ExploreMaxPoints)
+	test.Points = make([][2]float64, 0, min(n, ExploreMaxPoints))
+	for i := 0; i < n; i += stride {
+		test.Points = append(test.Points, [2]float64{xs[i], ys[i]})
+	}
+
+	switch {
+	case test.Significant && test.Meaningful:
+		test.Summary = fmt.Sprintf("Significant %s association: r = %.3f (95%% CI %.3f to %.3f), p = %.3g over %d rows", test.Strength, r, test.CILow, test.CIHigh, test.PValue, n)
+	case test.Significant:
+		test.Summary = fmt.Sprintf("Significant but below the meaningful effect of |r| > %.2f: r = %.3f, p = %.3g over %d rows", policy.MinEffect, r, test.PValue, n)
+	default:
+		test.Summary = fmt.Sprintf("No significant association: r = %.3f, p = %.3g over %d rows", r, test.PValue, n)
+	}
+	if test.Robust != nil && test.Robust.OutlierDriven {
+		test.Summary += "; robust estimates say it is carried by a few extreme rows"
+	}
+	return test, nil
+}
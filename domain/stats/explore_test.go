@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestTestPairReportsAssociation verifies a pair's correlation, interval, missing rows and
+// plot sample
+func TestTestPairReportsAssociation(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	x := make([]float64, 1200)
+	y := make([]float64, 1200)
+	for i := range x {
+		x[i] = rng.NormFloat64()
+		y[i] = 0.6*x[i] + 0.8*rng.NormFloat64()
+	}
+	x[0], y[1], y[2] = math.NaN(), math.NaN(), math.NaN()
+
+	test, err := TestPair(x, y, DefaultAnalysisPolicy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if test.Rows != 1200 || test.CompleteRows != 1197 || test.MissingX != 1 || test.MissingY != 2 {
+		t.Fatalf("unexpected row counts %+v", test)
+	}
+	if !test.Significant || !test.Meaningful || test.Correlation < 0.5 || test.CILow >= test.Correlation || test.CIHigh <= test.Correlation {
+		t.Fatalf("expected a significant association inside its interval, got %+v", test)
+	}
+	if test.Robust == nil || test.Robust.OutlierDriven {
+		t.Errorf("expected robust estimates agreeing with r, got %+v", test.Robust)
+	}
+	if len(test.Points) > ExploreMaxPoints || len(test.Points) < ExploreMaxPoints/2 {
+		t.Errorf("expected at most %d evenly spaced points, got %d", ExploreMaxPoints, len(test.Points))
+	}
+	if !strings.HasPrefix(test.Summary, "Significant") {
+		t.Errorf("unexpected summary %q", test.Summary)
+	}
+}
+
+// TestTestPairRejectsConstantVariable refuses pairs whose correlation is undefined
+func TestTestPairRejectsConstantVariable(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5}
+	y := []float64{2, 2, 2, 2, 2}
+	if _, err := TestPair(x, y, DefaultAnalysisPolicy()); err == nil {
+		t.Fatal("expected an error for a constant variable")
+	}
+	if _, err := TestPair(x[:2], y[:2], DefaultAnalysisPolicy()); err == nil {
+		t.Fatal("expected an error for two rows")
+	}
+}
@@ -0,0 +1,118 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// FDRMethod identifies a multiple-comparison correction procedure
+type FDRMethod string
+
+const (
+	FDRNone              FDRMethod = "none"   // No correction (single test)
+	FDRBenjaminiHochberg FDRMethod = "BH"     // Benjamini-Hochberg step-up (independent/PRDS tests)
+	FDRBenjaminiYekutiel FDRMethod = "BY"     // Benjamini-Yekutieli (arbitrary dependence)
+	FDRHolmBonferroni    FDRMethod = "HOLM"   // Holm-Bonferroni step-down (controls FWER)
+	FDRStorey            FDRMethod = "STOREY" // Storey's q-value with estimated null proportion
+)
+
+// storeyLambda is the tuning parameter used to estimate the proportion of true nulls
+const storeyLambda = 0.5
+
+// ParseFDRMethod normalizes user input (e.g. "bh", "holm-bonferroni", "qvalue") to an FDRMethod
+func ParseFDRMethod(s string) (FDRMethod, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "bh", "benjamini-hochberg", "benjamini_hochberg":
+		return FDRBenjaminiHochberg, nil
+	case "by", "benjamini-yekutieli", "benjamini_yekutieli":
+		return FDRBenjaminiYekutiel, nil
+	case "holm", "holm-bonferroni", "holm_bonferroni":
+		return FDRHolmBonferroni, nil
+	case "storey", "qvalue", "q-value", "storey_qvalue":
+		return FDRStorey, nil
+	case "none":
+		return FDRNone, nil
+	default:
+		return "", fmt.Errorf("unsupported FDR method: %q", s)
+	}
+}
+
+// AdjustPValues returns corrected values (q-values, or FWER-adjusted p-values for Holm)
+// in the same order as the input p-values
+func AdjustPValues(method FDRMethod, pValues []float64) []float64 {
+	m := len(pValues)
+	adjusted := make([]float64, m)
+	if m == 0 {
+		return adjusted
+	}
+
+	// Rank p-values ascending while remembering original positions
+	order := make([]int, m)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return pValues[order[a]] < pValues[order[b]]
+	})
+
+	switch method {
+	case FDRNone:
+		copy(adjusted, pValues)
+
+	case FDRHolmBonferroni:
+		running := 0.0
+		for rank, idx := range order {
+			value := math.Min(1.0, float64(m-rank)*pValues[idx])
+			running = math.Max(running, value) // enforce monotonicity
+			adjusted[idx] = running
+		}
+
+	case FDRBenjaminiYekutiel:
+		harmonic := 0.0
+		for i := 1; i <= m; i++ {
+			harmonic += 1.0 / float64(i)
+		}
+		stepUp(order, pValues, adjusted, float64(m)*harmonic)
+
+	case FDRStorey:
+		stepUp(order, pValues, adjusted, estimatePi0(pValues)*float64(m))
+
+	default: // FDRBenjaminiHochberg
+		stepUp(order, pValues, adjusted, float64(m))
+	}
+
+	return adjusted
+}
+
+// stepUp applies q_(i) = min_{j>=i} scale * p_(j) / j, clamped to [0, 1]
+func stepUp(order []int, pValues, adjusted []float64, scale float64) {
+	running := 1.0
+	for rank := len(order) - 1; rank >= 0; rank-- {
+		idx := order[rank]
+		value := scale * pValues[idx] / float64(rank+1)
+		running = math.Min(running, value)
+		adjusted[idx] = math.Max(0, running)
+	}
+}
+
+// estimatePi0 estimates the proportion of true null hypotheses (Storey 2002)
+func estimatePi0(pValues []float64) float64 {
+	above := 0
+	for _, p := range pValues {
+		if p > storeyLambda {
+			above++
+		}
+	}
+	pi0 := float64(above) / (float64(len(pValues)) * (1 - storeyLambda))
+	if pi0 > 1.0 {
+		return 1.0
+	}
+	if pi0 <= 0 {
+		// No p-values above lambda leaves too little to estimate from; assuming every null
+		// true reduces to Benjamini-Hochberg rather than shrinking q-values
+		return 1.0
+	}
+	return pi0
+}
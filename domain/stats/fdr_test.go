@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// TestAdjustPValues checks each correction against hand-computed values
+func TestAdjustPValues(t *testing.T) {
+	pValues := []float64{0.01, 0.04, 0.03, 0.005}
+
+	tests := []struct {
+		method   FDRMethod
+		expected []float64
+	}{
+		{FDRNone, []float64{0.01, 0.04, 0.03, 0.005}},
+		{FDRBenjaminiHochberg, []float64{0.02, 0.04, 0.04, 0.02}},
+		{FDRBenjaminiYekutiel, []float64{0.02 * 25 / 12, 0.04 * 25 / 12, 0.04 * 25 / 12, 0.02 * 25 / 12}},
+		{FDRHolmBonferroni, []float64{0.03, 0.06, 0.06, 0.02}},
+		{FDRStorey, []float64{0.02, 0.04, 0.04, 0.02}}, // no p-value above lambda: pi0 = 1
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			got := AdjustPValues(tt.method, pValues)
+			for i := range tt.expected {
+				if math.Abs(got[i]-tt.expected[i]) > 1e-9 {
+					t.Errorf("index %d: expected %.6f, got %.6f", i, tt.expected[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+// TestAdjustPValuesClampsToOne ensures corrected values never exceed 1
+func TestAdjustPValuesClampsToOne(t *testing.T) {
+	for _, method := range []FDRMethod{FDRBenjaminiHochberg, FDRBenjaminiYekutiel, FDRHolmBonferroni, FDRStorey} {
+		for _, q := range AdjustPValues(method, []float64{0.9, 0.8, 0.95}) {
+			if q > 1.0 {
+				t.Errorf("%s produced q-value %.4f > 1", method, q)
+			}
+		}
+	}
+}
+
+// TestParseFDRMethod tests user-facing method aliases
+func TestParseFDRMethod(t *testing.T) {
+	cases := map[string]FDRMethod{
+		"bh":              FDRBenjaminiHochberg,
+		"BY":              FDRBenjaminiYekutiel,
+		"holm-bonferroni": FDRHolmBonferroni,
+		"qvalue":          FDRStorey,
+	}
+	for input, expected := range cases {
+		got, err := ParseFDRMethod(input)
+		if err != nil || got != expected {
+			t.Errorf("ParseFDRMethod(%q) = %q, %v; expected %q", input, got, err, expected)
+		}
+	}
+
+	if _, err := ParseFDRMethod("bonferroni-ish"); err == nil {
+		t.Error("expected error for unknown method")
+	}
+}
+
+// TestStoreyEstimatesPi0 verifies Storey's q-values shrink by the estimated share of true nulls
+// when p-values above lambda allow an estimate
+func TestStoreyEstimatesPi0(t *testing.T) {
+	pValues := []float64{0.001, 0.002, 0.003, 0.004, 0.6, 0.7, 0.8, 0.9}
+	if pi0 := estimatePi0(pValues); math.Abs(pi0-1.0) > 1e-9 {
+		t.Fatalf("expected pi0 = 4 / (8 * 0.5) = 1, got %.4f", pi0)
+	}
+	pValues = append(pValues, 0.0005, 0.0006, 0.0007, 0.0008, 0.0009, 0.001, 0.0011, 0.0012)
+	pi0 := estimatePi0(pValues)
+	if math.Abs(pi0-0.5) > 1e-9 {
+		t.Fatalf("expected pi0 = 4 / (16 * 0.5) = 0.5, got %.4f", pi0)
+	}
+	bh := AdjustPValues(FDRBenjaminiHochberg, pValues)
+	storey := AdjustPValues(FDRStorey, pValues)
+	for i := range pValues {
+		if math.Abs(storey[i]-math.Min(1, bh[i]*pi0)) > 1e-9 {
+			t.Errorf("index %d: expected Storey %.6f to be BH %.6f scaled by pi0", i, storey[i], bh[i])
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// mixedDivergenceRatio is how much of the larger of the pooled and mixed-model effects the
+// smaller must keep, in the same direction, for the two estimates to agree
+const mixedDivergenceRatio = 0.5
+
+// mixedNegligibleEffect is the standardized effect below which both estimates read as no effect
+// and their disagreement is not worth a warning
+const mixedNegligibleEffect = 0.1
+
+// MixedModelEstimate is the within-entity slope of y on x in a linear mixed model with a
+// random intercept per entity, fitted by REML. The entity's mean of x enters as a covariate
+// (the within-between or Mundlak form), so entities whose baselines track their x level do not
+// leak into the slope. Standardized puts the slope on the correlation scale (slope·sd(x)/sd(y))
+// so it reads next to the pooled coefficient.
+type MixedModelEstimate struct {
+	Slope            float64 `json:"slope"`
+	StdErr           float64 `json:"std_err"`
+	PValue           float64 `json:"p_value"` // two-sided t test on N - groups - 1 degrees of freedom
+	Standardized     float64 `json:"standardized"`
+	BetweenSlope     float64 `json:"between_slope"` // how y differs between entities per unit of their mean x
+	Groups           int     `json:"groups"`
+	Observations     int     `json:"observations"`
+	GroupVariance    float64 `json:"group_variance"`    // variance of the random intercepts
+	ResidualVariance float64 `json:"residual_variance"` // variance within entities
+	ICC              float64 `json:"icc"`               // share of the residual variance between entities
+}
+
+// MixedComparison sets a pooled effect against the mixed-model estimate of the same pair. The
+// two diverge when they disagree in sign, or one keeps less than half of the other: part of
+// the pooled association is differences between entities rather than changes within them.
+type MixedComparison struct {
+	Pooled   float64            `json:"pooled"`
+	Mixed    MixedModelEstimate `json:"mixed"`
+	Diverges bool               `json:"diverges"`
+}
+
+// CompareMixed fits the random-intercept model for a pair and compares its standardized slope
+// with the pooled correlation. groups holds each row's entity code; rows missing any of x, y
+// or the entity are left out.
+func CompareMixed(x, y, groups []float64, pooled float64) (*MixedComparison, error) {
+	estimate, err := RandomInterceptSlope(x, y, groups)
+	if err != nil {
+		return nil, err
+	}
+	comparison := &MixedComparison{Pooled: pooled, Mixed: *estimate}
+	larger, smaller := math.Abs(pooled), math.Abs(estimate.Standardized)
+	if smaller > larger {
+		larger, smaller = smaller, larger
+	}
+	if larger >= mixedNegligibleEffect && (pooled*estimate.Standardized < 0 || smaller < mixedDivergenceRatio*larger) {
+		comparison.Diverges = true
+	}
+	return comparison, nil
+}
+
+// RandomInterceptSlope fits y_ij = b0 + b1·x_ij + b2·x̄_j + u_j + e_ij, with u_j ~ N(0, τ²) the
+// entity's intercept and e_ij ~ N(0, σ²); b1 is the within-entity slope and b1 + b2 the
+// between-entity one. For a variance ratio λ = τ²/σ², GLS reduces to OLS on data quasi-demeaned
+// within each entity by θ_j = 1 - 1/√(1 + n_j·λ); λ is chosen to maximize the REML profile
+// likelihood.
+func RandomInterceptSlope(x, y, groups []float64) (*MixedModelEstimate, error) {
+	if len(x) != len(y) || len(x) != len(groups) {
+		return nil, fmt.Errorf("mixed model needs aligned samples, got %d, %d and %d", len(x), len(y), len(groups))
+	}
+	index := make(map[float64]int)
+	var xs, ys []float64
+	var members []int
+	for i := range x {
+		if math.IsNaN(x[i]) || math.IsNaN(y[i]) || math.IsNaN(groups[i]) {
+			continue
+		}
+		j, ok := index[groups[i]]
+		if !ok {
+			j = len(index)
+			index[groups[i]] = j
+		}
+		xs = append(xs, x[i])
+		ys = append(ys, y[i])
+		members = append(members, j)
+	}
+	n, g := len(xs), len(index)
+	if g < 3 || n < g+4 {
+		return nil, fmt.Errorf("mixed model needs at least three entities with repeated observations, got %d rows of %d entities", n, g)
+	}
+	sdX, sdY := stat.StdDev(xs, nil), stat.StdDev(ys, nil)
+	if sdX == 0 || sdY == 0 {
+		return nil, fmt.Errorf("mixed model is undefined: a variable is constant")
+	}
+
+	sizes := make([]float64, g)
+	meanX, meanY := make([]float64, g), make([]float64, g)
+	for i, j := range members {
+		sizes[j]++
+		meanX[j] += xs[i]
+		meanY[j] += ys[i]
+	}
+	for j := range sizes {
+		meanX[j] /= sizes[j]
+		meanY[j] /= sizes[j]
+	}
+
+	// fit runs the quasi-demeaned OLS for a variance ratio and returns its REML profile
+	// log-likelihood, coefficients, residual sum of squares and (X*'X*)⁻¹
+	const p = 3
+	type olsFit struct {
+		loglik, rss float64
+		beta        [p]float64
+		inverse     mat.SymDense
+	}
+	fit := func(lambda float64) (*olsFit, bool) {
+		xtx := mat.NewSymDense(p, nil)
+		xty := mat.NewVecDense(p, nil)
+		var yty, logDet float64
+		for j := range sizes {
+			logDet += math.Log1p(sizes[j] * lambda)
+		}
+		for i, j := range members {
+			theta := 1 - 1/math.Sqrt(1+sizes[j]*lambda)
+			row := [p]float64{1 - theta, xs[i] - theta*meanX[j], (1 - theta) * meanX[j]}
+			yt := ys[i] - theta*meanY[j]
+			for a := 0; a < p; a++ {
+				xty.SetVec(a, xty.AtVec(a)+row[a]*yt)
+				for b := a; b < p; b++ {
+					xtx.SetSym(a, b, xtx.At(a, b)+row[a]*row[b])
+				}
+			}
+			yty += yt * yt
+		}
+		var chol mat.Cholesky
+		if !chol.Factorize(xtx) {
+			return nil, false
+		}
+		var beta mat.VecDense
+		if err := chol.SolveVecTo(&beta, xty); err != nil {
+			return nil, false
+		}
+		f := &olsFit{rss: yty - mat.Dot(&beta, xty)}
+		if f.rss <= 0 {
+			return nil, false
+		}
+		for a := 0; a < p; a++ {
+			f.beta[a] = beta.AtVec(a)
+		}
+		if err := chol.InverseTo(&f.inverse); err != nil {
+			return nil, false
+		}
+		f.loglik = -0.5*float64(n-p)*math.Log(f.rss) - 0.5*logDet - 0.5*chol.LogDet()
+		return f, true
+	}
+	profile := func(t float64) float64 {
+		if f, ok := fit(math.Exp(t)); ok {
+			return f.loglik
+		}
+		return math.Inf(-1)
+	}
+
+	// Coarse grid on log λ, then golden-section refinement around the best point
+	best, bestLambda := math.Inf(-1), 0.0
+	if f, ok := fit(0); ok {
+		best = f.loglik
+	}
+	const lo, hi, steps = -10.0, 8.0, 37
+	bestStep := -1
+	for k := 0; k < steps; k++ {
+		if loglik := profile(lo + (hi-lo)*float64(k)/(steps-1)); loglik > best {
+			best, bestStep = loglik, k
+		}
+	}
+	if bestStep >= 0 {
+		a := lo + (hi-lo)*float64(max(bestStep-1, 0))/(steps-1)
+		b := lo + (hi-lo)*float64(min(bestStep+1, steps-1))/(steps-1)
+		bestLambda = math.Exp(lo + (hi-lo)*float64(bestStep)/(steps-1))
+		phi := (math.Sqrt(5) - 1) / 2
+		c, d := b-phi*(b-a), a+phi*(b-a)
+		fc, fd := profile(c), profile(d)
+		for b-a > 1e-6 {
+			if fc > fd {
+				b, d, fd = d, c, fc
+				c = b - phi*(b-a)
+				fc = profile(c)
+			} else {
+				a, c, fc = c, d, fd
+				d = a + phi*(b-a)
+				fd = profile(d)
+			}
+		}
+		if t := (a + b) / 2; profile(t) > best {
+			bestLambda = math.Exp(t)
+		}
+	}
+
+	f, ok := fit(bestLambda)
+	if !ok {
+		return nil, fmt.Errorf("mixed model is undefined: x does not vary within entities")
+	}
+	sigma2 := f.rss / float64(n-p)
+	estimate := &MixedModelEstimate{
+		Slope:            f.beta[1],
+		StdErr:           math.Sqrt(sigma2 * f.inverse.At(1, 1)),
+		Standardized:     f.beta[1] * sdX / sdY,
+		BetweenSlope:     f.beta[1] + f.beta[2],
+		Groups:           g,
+		Observations:     n,
+		GroupVariance:    bestLambda * sigma2,
+		ResidualVariance: sigma2,
+		ICC:              bestLambda / (1 + bestLambda),
+	}
+	if estimate.StdErr > 0 {
+		t := estimate.Slope / estimate.StdErr
+		estimate.PValue = 2 * distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(n - g - 1)}.Survival(math.Abs(t))
+	}
+	return estimate, nil
+}
@@ -0,0 +1,89 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// panelPair simulates entities observed repeatedly: within each entity y falls with x, while
+// entities with a higher x level have a much higher baseline when between is set
+func panelPair(rng *rand.Rand, entities, periods int, between float64) (x, y, groups []float64) {
+	for j := 0; j < entities; j++ {
+		level := rng.NormFloat64() * 3
+		intercept := between*level + rng.NormFloat64()
+		for t := 0; t < periods; t++ {
+			xi := level + rng.NormFloat64()
+			x = append(x, xi)
+			y = append(y, intercept-0.5*(xi-level)+0.3*rng.NormFloat64())
+			groups = append(groups, float64(j))
+		}
+	}
+	return x, y, groups
+}
+
+// TestMixedModelSeparatesWithinFromBetween verifies the random-intercept slope recovers the
+// within-entity effect the pooled correlation hides, and flags the divergence
+func TestMixedModelSeparatesWithinFromBetween(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	x, y, groups := panelPair(rng, 30, 8, 4)
+	pooled := correlation(x, y)
+	if pooled < 0.5 {
+		t.Fatalf("expected the between-entity baseline to dominate the pooled correlation, got %.3f", pooled)
+	}
+
+	comparison, err := CompareMixed(x, y, groups, pooled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mixed := comparison.Mixed
+	if math.Abs(mixed.Slope+0.5) > 0.1 || mixed.PValue > 0.001 {
+		t.Errorf("expected the within-entity slope near -0.5, got %.3f (p=%.3g)", mixed.Slope, mixed.PValue)
+	}
+	if math.Abs(mixed.BetweenSlope-4) > 0.5 {
+		t.Errorf("expected the between-entity slope near 4, got %.3f", mixed.BetweenSlope)
+	}
+	if mixed.Groups != 30 || mixed.Observations != 240 || mixed.ICC < 0.9 {
+		t.Errorf("unexpected fit summary: %+v", mixed)
+	}
+	if !comparison.Diverges {
+		t.Errorf("expected pooled %.3f and mixed %.3f to diverge", pooled, mixed.Standardized)
+	}
+}
+
+// TestMixedModelAgreesWithoutEntityEffects verifies the mixed estimate matches the pooled one
+// when entities share a baseline
+func TestMixedModelAgreesWithoutEntityEffects(t *testing.T) {
+	rng := rand.New(rand.NewSource(12))
+	var x, y, groups []float64
+	for i := 0; i < 200; i++ {
+		xi := rng.NormFloat64()
+		x = append(x, xi)
+		y = append(y, 0.6*xi+0.8*rng.NormFloat64())
+		groups = append(groups, float64(i%20))
+	}
+	x[3], groups[7] = math.NaN(), math.NaN()
+
+	comparison, err := CompareMixed(x, y, groups, correlation(x, y))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comparison.Diverges || math.Abs(comparison.Mixed.Standardized-comparison.Pooled) > 0.05 {
+		t.Errorf("expected agreement, got pooled %.3f and mixed %.3f", comparison.Pooled, comparison.Mixed.Standardized)
+	}
+	if comparison.Mixed.Observations != 198 || comparison.Mixed.ICC > 0.2 {
+		t.Errorf("unexpected fit summary: %+v", comparison.Mixed)
+	}
+
+	if _, err := RandomInterceptSlope(x[:10], y[:10], make([]float64, 10)); err == nil {
+		t.Error("expected an error with a single entity")
+	}
+}
+
+// correlation is Pearson's r over the complete rows
+func correlation(x, y []float64) float64 {
+	xs, ys := completePairs(x, y)
+	return stat.Correlation(xs, ys, nil)
+}
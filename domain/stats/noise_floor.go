@@ -0,0 +1,167 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// NoiseFloorKey is the workspace metadata entry holding the workspace's calibrated noise floor
+const NoiseFloorKey = "noise_floor"
+
+// Calibration defaults: permutations of the dataset, and pairs of the reduced sweep run on each
+const (
+	DefaultNoiseFloorPermutations = 20
+	DefaultNoiseFloorPairs        = 200
+	MaxNoiseFloorPermutations     = 200
+	noiseFloorMinRows             = 10
+	noiseFloorQuantiles           = 100
+)
+
+// NoiseFloor is a workspace's empirical null distribution of effect sizes: the |r| a reduced
+// sweep finds on copies of the dataset whose columns were permuted independently, so every
+// association in them is noise. Findings are placed against it by percentile.
+type NoiseFloor struct {
+	DatasetID    string    `json:"dataset_id,omitempty"`
+	CalibratedAt time.Time `json:"calibrated_at"`
+	Permutations int       `json:"permutations"`
+	Pairs        int       `json:"pairs"`   // pairs tested on each permutation
+	Samples      int       `json:"samples"` // null effects collected in all
+	Seed         int64     `json:"seed"`
+	// Quantiles holds the null |r| at each percentile from 0 to 100
+	Quantiles []float64 `json:"quantiles"`
+}
+
+// NoiseFloorOptions size a calibration; zero fields take the defaults
+type NoiseFloorOptions struct {
+	Permutations int   `json:"permutations,omitempty"`
+	Pairs        int   `json:"pairs,omitempty"`
+	Seed         int64 `json:"seed,omitempty"`
+}
+
+// CalibrateNoiseFloor permutes every column of the dataset independently, Permutations times,
+// and collects the |r| of up to Pairs variable pairs on each permuted copy. The pairs are the
+// same on every permutation, drawn evenly from all pairs with enough complete rows.
+func CalibrateNoiseFloor(columns [][]float64, opts NoiseFloorOptions) (*NoiseFloor, error) {
+	if opts.Permutations <= 0 {
+		opts.Permutations = DefaultNoiseFloorPermutations
+	}
+	if opts.Permutations > MaxNoiseFloorPermutations {
+		return nil, fmt.Errorf("at most %d permutations are allowed", MaxNoiseFloorPermutations)
+	}
+	if opts.Pairs <= 0 {
+		opts.Pairs = DefaultNoiseFloorPairs
+	}
+
+	var pairs [][2]int
+	for i := 0; i < len(columns); i++ {
+		for j := i + 1; j < len(columns); j++ {
+			if xs, _ := completePairs(columns[i], columns[j]); len(xs) >= noiseFloorMinRows {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("no variable pair has %d complete rows to calibrate on", noiseFloorMinRows)
+	}
+	if len(pairs) > opts.Pairs {
+		sampled := make([][2]int, opts.Pairs)
+		for k := range sampled {
+			sampled[k] = pairs[k*len(pairs)/opts.Pairs]
+		}
+		pairs = sampled
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	permuted := make([][]float64, len(columns))
+	for i, column := range columns {
+		permuted[i] = append([]float64(nil), column...)
+	}
+	null := make([]float64, 0, opts.Permutations*len(pairs))
+	for k := 0; k < opts.Permutations; k++ {
+		for _, column := range permuted {
+			rng.Shuffle(len(column), func(a, b int) { column[a], column[b] = column[b], column[a] })
+		}
+		for _, pair := range pairs {
+			xs, ys := completePairs(permuted[pair[0]], permuted[pair[1]])
+			if len(xs) < noiseFloorMinRows {
+				continue
+			}
+			if r := stat.Correlation(xs, ys, nil); !math.IsNaN(r) {
+				null = append(null, math.Abs(r))
+			}
+		}
+	}
+	if len(null) == 0 {
+		return nil, fmt.Errorf("no permuted pair had a defined correlation; are the columns constant?")
+	}
+	sort.Float64s(null)
+
+	floor := &NoiseFloor{
+		CalibratedAt: time.Now(),
+		Permutations: opts.Permutations,
+		Pairs:        len(pairs),
+		Samples:      len(null),
+		Seed:         opts.Seed,
+		Quantiles:    make([]float64, noiseFloorQuantiles+1),
+	}
+	for q := range floor.Quantiles {
+		floor.Quantiles[q] = stat.Quantile(float64(q)/noiseFloorQuantiles, stat.LinInterp, null, nil)
+	}
+	return floor, nil
+}
+
+// Percentile places an effect size against the noise floor: the share of null effects, in
+// percent, that are weaker than |effect|. 99 means stronger than 99% of what permuted data shows.
+func (f *NoiseFloor) Percentile(effect float64) float64 {
+	q := f.Quantiles
+	if len(q) < 2 || math.IsNaN(effect) {
+		return math.NaN()
+	}
+	effect = math.Abs(effect)
+	if effect <= q[0] {
+		return 0
+	}
+	if effect >= q[len(q)-1] {
+		return 100
+	}
+	i := sort.SearchFloat64s(q, effect) // q[i-1] < effect <= q[i]
+	step := 100 / float64(len(q)-1)
+	lower := float64(i-1) * step
+	if span := q[i] - q[i-1]; span > 0 {
+		return lower + step*(effect-q[i-1])/span
+	}
+	return lower
+}
+
+// Level is the null |r| at a percentile, such as the effect noise reaches one time in twenty at 95
+func (f *NoiseFloor) Level(percentile float64) float64 {
+	q := f.Quantiles
+	if len(q) < 2 {
+		return math.NaN()
+	}
+	return stat.Quantile(math.Max(0, math.Min(1, percentile/100)), stat.LinInterp, q, nil)
+}
+
+// NoiseFloorFrom reads a workspace's noise floor from its metadata; nil when it was never
+// calibrated
+func NoiseFloorFrom(metadata map[string]interface{}) *NoiseFloor {
+	raw, ok := metadata[NoiseFloorKey]
+	if !ok {
+		return nil
+	}
+	if floor, ok := raw.(*NoiseFloor); ok {
+		return floor
+	}
+	// Metadata read back from the database holds the floor as a generic map
+	var floor NoiseFloor
+	if data, err := json.Marshal(raw); err != nil || json.Unmarshal(data, &floor) != nil || len(floor.Quantiles) < 2 {
+		return nil
+	}
+	return &floor
+}
@@ -0,0 +1,94 @@
+package stats
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// noiseColumns builds independent noise columns with a few gaps
+func noiseColumns(rng *rand.Rand, n, k int) [][]float64 {
+	columns := make([][]float64, k)
+	for j := range columns {
+		columns[j] = make([]float64, n)
+		for i := range columns[j] {
+			columns[j][i] = rng.NormFloat64()
+			if i%17 == j {
+				columns[j][i] = math.NaN()
+			}
+		}
+	}
+	return columns
+}
+
+// TestNoiseFloorPlacesFindingsAgainstNull calibrates on noise and checks that a real effect
+// lands far above the floor while a typical null effect lands in the middle of it
+func TestNoiseFloorPlacesFindingsAgainstNull(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	columns := noiseColumns(rng, 200, 8)
+	floor, err := CalibrateNoiseFloor(columns, NoiseFloorOptions{Permutations: 10, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if floor.Pairs != 28 || floor.Samples != 280 || len(floor.Quantiles) != 101 {
+		t.Fatalf("unexpected calibration size: %+v", floor)
+	}
+	for q := 1; q < len(floor.Quantiles); q++ {
+		if floor.Quantiles[q] < floor.Quantiles[q-1] {
+			t.Fatalf("quantiles are not monotone at %d", q)
+		}
+	}
+	// With n≈190 the null |r| at the 95th percentile is near 1.96/sqrt(n) ≈ 0.14
+	if level := floor.Level(95); level < 0.08 || level > 0.22 {
+		t.Errorf("95th percentile of the null is %.3f, expected near 0.14", level)
+	}
+	if p := floor.Percentile(0.5); p != 100 {
+		t.Errorf("a strong effect should be above the whole floor, got %.1f", p)
+	}
+	if p := floor.Percentile(-floor.Quantiles[50]); math.Abs(p-50) > 1 {
+		t.Errorf("the null median should sit at the 50th percentile, got %.1f", p)
+	}
+	if p := floor.Percentile(0); p != 0 {
+		t.Errorf("a zero effect should sit at the bottom of the floor, got %.1f", p)
+	}
+
+	again, err := CalibrateNoiseFloor(columns, NoiseFloorOptions{Permutations: 10, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Quantiles[95] != floor.Quantiles[95] {
+		t.Error("a calibration with the same seed should reproduce the floor")
+	}
+
+	sampled, err := CalibrateNoiseFloor(columns, NoiseFloorOptions{Permutations: 2, Pairs: 5, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sampled.Pairs != 5 || sampled.Samples != 10 {
+		t.Errorf("expected the reduced sweep to test 5 pairs, got %+v", sampled)
+	}
+	if _, err := CalibrateNoiseFloor(columns[:1], NoiseFloorOptions{}); err == nil {
+		t.Error("expected an error without any pair to calibrate on")
+	}
+}
+
+// TestNoiseFloorFromMetadata reads a floor back from workspace metadata that went through JSON
+func TestNoiseFloorFromMetadata(t *testing.T) {
+	if NoiseFloorFrom(map[string]interface{}{}) != nil {
+		t.Fatal("expected no floor on an uncalibrated workspace")
+	}
+	floor := &NoiseFloor{Permutations: 3, Quantiles: []float64{0, 0.1, 0.2}}
+	data, err := json.Marshal(map[string]interface{}{NoiseFloorKey: floor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		t.Fatal(err)
+	}
+	read := NoiseFloorFrom(metadata)
+	if read == nil || read.Permutations != 3 || read.Percentile(0.15) != 75 {
+		t.Fatalf("unexpected floor read back: %+v", read)
+	}
+}
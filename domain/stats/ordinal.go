@@ -0,0 +1,200 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// OrdinalAssociation is the association of an ordinal variable with another variable. For
+// ordinal or numeric partners it is Kendall's tau-b, which uses only the order of the levels;
+// for a binary partner it is the Cochran-Armitage trend test, the score test of a logistic
+// model with a linear trend across the levels, with the trend correlation as coefficient.
+type OrdinalAssociation struct {
+	Test        TestType `json:"test"`
+	Coefficient float64  `json:"coefficient"` // tau-b, or the score-outcome correlation
+	ZStatistic  float64  `json:"z_statistic"`
+	PValue      float64  `json:"p_value"` // two-sided, normal approximation
+	SampleSize  int      `json:"sample_size"`
+}
+
+// KendallTauB computes Kendall's tau-b with the tie-corrected variance of its numerator. Ties
+// are the rule for ordinal data, so both the coefficient and the variance account for them.
+// It runs in O(n log n) (Knight's algorithm).
+func KendallTauB(x, y []float64) (*OrdinalAssociation, error) {
+	n := len(x)
+	if n != len(y) {
+		return nil, fmt.Errorf("kendall tau-b needs paired samples, got %d and %d", len(x), len(y))
+	}
+	if n < 3 {
+		return nil, fmt.Errorf("kendall tau-b needs at least 3 observations, got %d", n)
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		if x[i] != x[j] {
+			return x[i] < x[j]
+		}
+		return y[i] < y[j]
+	})
+
+	// Ties in x, and joint ties in (x, y), over the (x, y)-sorted order
+	xTies, jointTies := tieRuns(n, func(a, b int) bool { return x[order[a]] == x[order[b]] }),
+		tieRuns(n, func(a, b int) bool { return x[order[a]] == x[order[b]] && y[order[a]] == y[order[b]] })
+
+	// Sorting y in this order by merge sort counts the discordant pairs as swaps
+	ys := make([]float64, n)
+	for i, idx := range order {
+		ys[i] = y[idx]
+	}
+	swaps := mergeCountSwaps(ys, make([]float64, n))
+	yTies := tieRuns(n, func(a, b int) bool { return ys[a] == ys[b] })
+
+	pairs := float64(n) * float64(n-1) / 2
+	n1, n2, n3 := tiedPairs(xTies), tiedPairs(yTies), tiedPairs(jointTies)
+	if pairs == n1 || pairs == n2 {
+		return nil, fmt.Errorf("kendall tau-b is undefined for a constant variable")
+	}
+	s := pairs - n1 - n2 + n3 - 2*float64(swaps)
+	tau := s / math.Sqrt((pairs-n1)*(pairs-n2))
+
+	// Variance of S under independence with ties in both variables
+	nf := float64(n)
+	v0 := nf * (nf - 1) * (2*nf + 5)
+	var vt, vu, t1, u1, t2, u2 float64
+	for _, t := range xTies {
+		tf := float64(t)
+		vt += tf * (tf - 1) * (2*tf + 5)
+		t1 += tf * (tf - 1)
+		t2 += tf * (tf - 1) * (tf - 2)
+	}
+	for _, u := range yTies {
+		uf := float64(u)
+		vu += uf * (uf - 1) * (2*uf + 5)
+		u1 += uf * (uf - 1)
+		u2 += uf * (uf - 1) * (uf - 2)
+	}
+	variance := (v0-vt-vu)/18 + t1*u1/(2*nf*(nf-1)) + t2*u2/(9*nf*(nf-1)*(nf-2))
+
+	z := s / math.Sqrt(variance)
+	return &OrdinalAssociation{
+		Test:        TestKendall,
+		Coefficient: tau,
+		ZStatistic:  z,
+		PValue:      twoSidedNormalP(z),
+		SampleSize:  n,
+	}, nil
+}
+
+// TrendTest runs the Cochran-Armitage test for a trend in a binary outcome across ordinal
+// scores. The outcome's two values are taken as 0 (lower) and 1 (higher); the statistic is
+// z = r·√n, with r the correlation of scores and outcome.
+func TrendTest(scores, outcome []float64) (*OrdinalAssociation, error) {
+	n := len(scores)
+	if n != len(outcome) {
+		return nil, fmt.Errorf("trend test needs paired samples, got %d and %d", len(scores), len(outcome))
+	}
+	if n < 3 {
+		return nil, fmt.Errorf("trend test needs at least 3 observations, got %d", n)
+	}
+	high := math.Inf(-1)
+	for _, v := range outcome {
+		high = math.Max(high, v)
+	}
+
+	var meanS, p float64
+	for i := range scores {
+		meanS += scores[i]
+		if outcome[i] == high {
+			p++
+		}
+	}
+	meanS /= float64(n)
+	p /= float64(n)
+	if p == 0 || p == 1 {
+		return nil, fmt.Errorf("trend test needs both outcomes")
+	}
+
+	var t, ss float64
+	for i := range scores {
+		event := 0.0
+		if outcome[i] == high {
+			event = 1
+		}
+		t += (event - p) * scores[i]
+		ss += (scores[i] - meanS) * (scores[i] - meanS)
+	}
+	if ss == 0 {
+		return nil, fmt.Errorf("trend test is undefined for a constant score")
+	}
+	z := t / math.Sqrt(p*(1-p)*ss)
+	return &OrdinalAssociation{
+		Test:        TestTrend,
+		Coefficient: z / math.Sqrt(float64(n)),
+		ZStatistic:  z,
+		PValue:      twoSidedNormalP(z),
+		SampleSize:  n,
+	}, nil
+}
+
+// tieRuns returns the lengths of the runs of two or more consecutive equal positions
+func tieRuns(n int, equal func(a, b int) bool) []int {
+	var runs []int
+	run := 1
+	for i := 1; i <= n; i++ {
+		if i < n && equal(i-1, i) {
+			run++
+			continue
+		}
+		if run > 1 {
+			runs = append(runs, run)
+		}
+		run = 1
+	}
+	return runs
+}
+
+// tiedPairs counts the pairs within tie runs
+func tiedPairs(runs []int) float64 {
+	total := 0.0
+	for _, t := range runs {
+		total += float64(t) * float64(t-1) / 2
+	}
+	return total
+}
+
+// mergeCountSwaps sorts values ascending and returns the number of strictly inverted pairs
+func mergeCountSwaps(values, buf []float64) int64 {
+	if len(values) < 2 {
+		return 0
+	}
+	mid := len(values) / 2
+	swaps := mergeCountSwaps(values[:mid], buf[:mid]) + mergeCountSwaps(values[mid:], buf[mid:])
+	i, j, k := 0, mid, 0
+	for i < mid && j < len(values) {
+		if values[j] < values[i] {
+			buf[k] = values[j]
+			swaps += int64(mid - i)
+			j++
+		} else {
+			buf[k] = values[i]
+			i++
+		}
+		k++
+	}
+	k += copy(buf[k:], values[i:mid])
+	copy(buf[k:], values[j:])
+	copy(values, buf[:len(values)])
+	return swaps
+}
+
+// twoSidedNormalP is the two-sided p-value of a standard normal statistic
+func twoSidedNormalP(z float64) float64 {
+	return 2 * distuv.UnitNormal.Survival(math.Abs(z))
+}
@@ -0,0 +1,77 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// TestKendallTauBMatchesPairCounting verifies the O(n log n) coefficient against direct
+// pair counting on tied ordinal data
+func TestKendallTauBMatchesPairCounting(t *testing.T) {
+	x := []float64{1, 1, 2, 2, 3, 3, 3, 4, 4, 5, 5, 1}
+	y := []float64{1, 2, 1, 3, 2, 3, 3, 4, 3, 5, 4, 2}
+
+	var concordant, discordant, tiesX, tiesY float64
+	for i := range x {
+		for j := i + 1; j < len(x); j++ {
+			dx, dy := x[i]-x[j], y[i]-y[j]
+			switch {
+			case dx == 0 && dy == 0:
+			case dx == 0:
+				tiesX++
+			case dy == 0:
+				tiesY++
+			case dx*dy > 0:
+				concordant++
+			default:
+				discordant++
+			}
+		}
+	}
+	want := (concordant - discordant) / math.Sqrt((concordant+discordant+tiesX)*(concordant+discordant+tiesY))
+
+	got, err := KendallTauB(x, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got.Coefficient-want) > 1e-12 {
+		t.Fatalf("tau-b = %.6f, want %.6f", got.Coefficient, want)
+	}
+	if got.Coefficient < 0.7 || got.PValue > 0.01 {
+		t.Fatalf("expected a strong significant association, got %+v", got)
+	}
+	if _, err := KendallTauB([]float64{2, 2, 2}, []float64{1, 2, 3}); err == nil {
+		t.Fatal("expected a constant variable to be rejected")
+	}
+}
+
+// TestTrendTestDetectsMonotoneOutcome verifies a rising event rate across levels is detected
+// and a flat one is not
+func TestTrendTestDetectsMonotoneOutcome(t *testing.T) {
+	var scores, rising, flat []float64
+	for level := 0; level < 4; level++ {
+		for i := 0; i < 20; i++ {
+			scores = append(scores, float64(level))
+			rising = append(rising, boolFloat(i < 4+4*level))
+			flat = append(flat, boolFloat(i%2 == 0))
+		}
+	}
+	trend, err := TrendTest(scores, rising)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trend.Coefficient <= 0 || trend.PValue > 0.001 {
+		t.Fatalf("expected a significant rising trend, got %+v", trend)
+	}
+	none, _ := TrendTest(scores, flat)
+	if none.PValue < 0.5 {
+		t.Fatalf("expected no trend, got %+v", none)
+	}
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
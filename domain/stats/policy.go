@@ -0,0 +1,152 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// AnalysisPolicyKey is the workspace metadata entry holding the workspace's analysis policy
+const AnalysisPolicyKey = "analysis_policy"
+
+// AnalysisPolicy holds the cutoffs results are judged by: the significance level and the
+// effect sizes (|r|, or the equivalent for other tests) at which an association is kept and
+// called weak, moderate or strong. Each workspace may set its own; zero fields take the
+// defaults.
+type AnalysisPolicy struct {
+	Alpha          float64 `json:"alpha,omitempty" yaml:"alpha"`                     // p (or q) below it is significant
+	MinEffect      float64 `json:"min_effect,omitempty" yaml:"min_effect"`           // weakest effect the sweep keeps
+	ModerateEffect float64 `json:"moderate_effect,omitempty" yaml:"moderate_effect"` // from here an effect is moderate
+	StrongEffect   float64 `json:"strong_effect,omitempty" yaml:"strong_effect"`     // from here an effect is strong
+}
+
+// Effect strength labels, from EffectStrength
+const (
+	EffectNegligible = "negligible"
+	EffectWeak       = "weak"
+	EffectModerate   = "moderate"
+	EffectStrong     = "strong"
+)
+
+// DefaultAnalysisPolicy is the policy of workspaces that set none: α = 0.05 and Cohen-style
+// effect bands of 0.3, 0.5 and 0.7
+func DefaultAnalysisPolicy() AnalysisPolicy {
+	return AnalysisPolicy{Alpha: 0.05, MinEffect: 0.3, ModerateEffect: 0.5, StrongEffect: 0.7}
+}
+
+// AnalysisPolicyFrom reads the policy from workspace metadata; a workspace without one uses
+// the defaults
+func AnalysisPolicyFrom(metadata map[string]interface{}) AnalysisPolicy {
+	var policy AnalysisPolicy
+	if raw, ok := metadata[AnalysisPolicyKey]; ok {
+		// Metadata read back from the database holds the policy as a generic map
+		if data, err := json.Marshal(raw); err == nil {
+			json.Unmarshal(data, &policy)
+		}
+	}
+	return policy.WithDefaults()
+}
+
+// WithDefaults returns p with its zero fields taken from the default policy
+func (p AnalysisPolicy) WithDefaults() AnalysisPolicy {
+	defaults := DefaultAnalysisPolicy()
+	if p.Alpha == 0 {
+		p.Alpha = defaults.Alpha
+	}
+	if p.MinEffect == 0 {
+		p.MinEffect = defaults.MinEffect
+	}
+	if p.ModerateEffect == 0 {
+		p.ModerateEffect = defaults.ModerateEffect
+	}
+	if p.StrongEffect == 0 {
+		p.StrongEffect = defaults.StrongEffect
+	}
+	return p
+}
+
+// Validate checks the policy, with defaults applied, is usable: α in (0, 0.5] and effect
+// cutoffs rising within (0, 1)
+func (p AnalysisPolicy) Validate() error {
+	p = p.WithDefaults()
+	if p.Alpha <= 0 || p.Alpha > 0.5 {
+		return fmt.Errorf("alpha must be in (0, 0.5], got %g", p.Alpha)
+	}
+	if p.MinEffect <= 0 || p.StrongEffect >= 1 {
+		return fmt.Errorf("effect cutoffs must be between 0 and 1")
+	}
+	if p.MinEffect >= p.ModerateEffect || p.ModerateEffect >= p.StrongEffect {
+		return fmt.Errorf("effect cutoffs must rise: min_effect %g < moderate_effect %g < strong_effect %g",
+			p.MinEffect, p.ModerateEffect, p.StrongEffect)
+	}
+	return nil
+}
+
+// Significant reports whether a p- or q-value clears the significance level
+func (p AnalysisPolicy) Significant(pValue float64) bool {
+	return pValue < p.Alpha
+}
+
+// Meaningful reports whether an effect is large enough for the sweep to keep
+func (p AnalysisPolicy) Meaningful(effect float64) bool {
+	return math.Abs(effect) > p.MinEffect
+}
+
+// MinKendallTau is the Kendall τ about as strong as MinEffect is for Pearson: for bivariate
+// normal data τ = (2/π)·arcsin(r). It is rounded to two places since the mapping only holds
+// approximately off the normal.
+func (p AnalysisPolicy) MinKendallTau() float64 {
+	return math.Round(2/math.Pi*math.Asin(p.MinEffect)*100) / 100
+}
+
+// MinEtaSquared is the η² of a group difference as strong as MinEffect is for a correlation
+func (p AnalysisPolicy) MinEtaSquared() float64 {
+	return math.Round(p.MinEffect*p.MinEffect*1e6) / 1e6
+}
+
+// EffectStrength labels an effect size as negligible, weak, moderate or strong
+func (p AnalysisPolicy) EffectStrength(effect float64) string {
+	switch abs := math.Abs(effect); {
+	case abs >= p.StrongEffect:
+		return EffectStrong
+	case abs >= p.ModerateEffect:
+		return EffectModerate
+	case abs >= p.MinEffect:
+		return EffectWeak
+	default:
+		return EffectNegligible
+	}
+}
+
+// PracticalSignificance labels an effect size small, medium or large for relationship artifacts
+func (p AnalysisPolicy) PracticalSignificance(effect float64) string {
+	switch abs := math.Abs(effect); {
+	case abs >= p.ModerateEffect:
+		return "large"
+	case abs >= p.MinEffect:
+		return "medium"
+	default:
+		return "small"
+	}
+}
+
+// EvidenceLevel labels a p-value by how far it clears the significance level: very_strong
+// below α/50, strong below α/5, moderate below α and weak otherwise
+func (p AnalysisPolicy) EvidenceLevel(pValue float64) string {
+	switch {
+	case pValue < p.Alpha/50:
+		return "very_strong"
+	case pValue < p.Alpha/5:
+		return "strong"
+	case pValue < p.Alpha:
+		return "moderate"
+	default:
+		return "weak"
+	}
+}
+
+// Describe states the policy for reports, e.g. "α = 0.05; effects |r| ≥ 0.3 weak, ≥ 0.5
+// moderate, ≥ 0.7 strong"
+func (p AnalysisPolicy) Describe() string {
+	return fmt.Sprintf("α = %g; effects |r| ≥ %g weak, ≥ %g moderate, ≥ %g strong", p.Alpha, p.MinEffect, p.ModerateEffect, p.StrongEffect)
+}
@@ -0,0 +1,66 @@
+package stats
+
+import "testing"
+
+// TestDefaultAnalysisPolicyKeepsLegacyCutoffs pins the defaults to the cutoffs the sweep used
+// before policies existed, so workspaces without one see unchanged results
+func TestDefaultAnalysisPolicyKeepsLegacyCutoffs(t *testing.T) {
+	p := DefaultAnalysisPolicy()
+	if p.MinKendallTau() != 0.19 {
+		t.Errorf("expected a minimum Kendall tau of 0.19, got %g", p.MinKendallTau())
+	}
+	if p.MinEtaSquared() != 0.09 {
+		t.Errorf("expected a minimum eta squared of 0.09, got %g", p.MinEtaSquared())
+	}
+	if p.Meaningful(0.3) || !p.Meaningful(-0.31) {
+		t.Error("expected effects to count only above 0.3 in magnitude")
+	}
+	if got := p.EvidenceLevel(0.0005); got != "very_strong" {
+		t.Errorf("expected very_strong evidence at p=0.0005, got %s", got)
+	}
+	if got := p.EvidenceLevel(0.005); got != "strong" {
+		t.Errorf("expected strong evidence at p=0.005, got %s", got)
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("default policy is invalid: %v", err)
+	}
+}
+
+// TestAnalysisPolicyFromMetadata reads a stored policy and fills what it leaves out
+func TestAnalysisPolicyFromMetadata(t *testing.T) {
+	metadata := map[string]interface{}{
+		AnalysisPolicyKey: map[string]interface{}{"alpha": 0.01, "strong_effect": 0.8},
+	}
+	p := AnalysisPolicyFrom(metadata)
+	if p.Alpha != 0.01 || p.StrongEffect != 0.8 || p.MinEffect != 0.3 || p.ModerateEffect != 0.5 {
+		t.Fatalf("unexpected policy %+v", p)
+	}
+	if p.Significant(0.02) {
+		t.Error("expected p=0.02 not to be significant at alpha 0.01")
+	}
+	if got := p.EffectStrength(-0.75); got != EffectModerate {
+		t.Errorf("expected r=-0.75 to be moderate under a 0.8 strong cutoff, got %s", got)
+	}
+	if AnalysisPolicyFrom(nil) != DefaultAnalysisPolicy() {
+		t.Error("expected the default policy without metadata")
+	}
+}
+
+// TestAnalysisPolicyValidate rejects cutoffs that are out of range or out of order
+func TestAnalysisPolicyValidate(t *testing.T) {
+	invalid := []AnalysisPolicy{
+		{Alpha: 0.6},
+		{Alpha: -0.01},
+		{MinEffect: 0.6},
+		{ModerateEffect: 0.7, StrongEffect: 0.7},
+		{StrongEffect: 1},
+	}
+	for _, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", p)
+		}
+	}
+	if err := (AnalysisPolicy{Alpha: 0.01, MinEffect: 0.2}).Validate(); err != nil {
+		t.Errorf("expected a partial policy to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// PooledEstimate combines one estimate computed on each of m imputed datasets by Rubin's
+// rules. The between-imputation variance is the uncertainty due to the missing values; a
+// finding that only one imputation supports shows up as a large share of it.
+type PooledEstimate struct {
+	Imputations         int     `json:"imputations"`
+	Estimate            float64 `json:"estimate"`                     // mean of the per-imputation estimates
+	Correlation         float64 `json:"correlation,omitempty"`        // back-transformed estimate, for pooled correlations
+	WithinVariance      float64 `json:"within_variance"`              // mean sampling variance
+	BetweenVariance     float64 `json:"between_variance"`             // variance across imputations
+	TotalVariance       float64 `json:"total_variance"`               // within + (1 + 1/m)·between
+	DegreesOfFreedom    float64 `json:"degrees_of_freedom"`           // Barnard-Rubin
+	FractionMissingInfo float64 `json:"fraction_missing_information"` // (1 + 1/m)·between / total
+	PValue              float64 `json:"p_value"`                      // two-sided, for estimate = 0
+}
+
+// PoolRubin pools per-imputation estimates and their sampling variances. completeDF is the
+// degrees of freedom the test would have without missing data; it bounds the pooled degrees
+// of freedom (Barnard and Rubin, 1999).
+func PoolRubin(estimates, variances []float64, completeDF float64) (*PooledEstimate, error) {
+	m := len(estimates)
+	if m < 2 {
+		return nil, fmt.Errorf("pooling needs at least 2 imputations, got %d", m)
+	}
+	if len(variances) != m {
+		return nil, fmt.Errorf("pooling needs one variance per estimate, got %d and %d", m, len(variances))
+	}
+
+	var mean, within float64
+	for i := range estimates {
+		mean += estimates[i]
+		within += variances[i]
+	}
+	mean /= float64(m)
+	within /= float64(m)
+	var between float64
+	for _, q := range estimates {
+		between += (q - mean) * (q - mean)
+	}
+	between /= float64(m - 1)
+	total := within + (1+1/float64(m))*between
+	if total <= 0 {
+		return nil, fmt.Errorf("pooled variance is zero")
+	}
+
+	lambda := (1 + 1/float64(m)) * between / total
+	observedDF := (completeDF + 1) / (completeDF + 3) * completeDF * (1 - lambda)
+	df := observedDF
+	if lambda > 0 {
+		oldDF := float64(m-1) / (lambda * lambda)
+		df = oldDF * observedDF / (oldDF + observedDF)
+	}
+
+	pooled := &PooledEstimate{
+		Imputations:         m,
+		Estimate:            mean,
+		WithinVariance:      within,
+		BetweenVariance:     between,
+		TotalVariance:       total,
+		DegreesOfFreedom:    df,
+		FractionMissingInfo: lambda,
+		PValue:              1.0,
+	}
+	if df > 0 {
+		t := mean / math.Sqrt(total)
+		pooled.PValue = 2 * distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}.Survival(math.Abs(t))
+	}
+	return pooled, nil
+}
+
+// PoolCorrelations pools correlations computed on m imputed datasets of n rows each. They are
+// pooled on Fisher's z scale, where the sampling variance is 1/(n-3), and transformed back.
+func PoolCorrelations(correlations []float64, n int) (*PooledEstimate, error) {
+	if n < 4 {
+		return nil, fmt.Errorf("pooling correlations needs at least 4 rows, got %d", n)
+	}
+	z := make([]float64, len(correlations))
+	variances := make([]float64, len(correlations))
+	for i, r := range correlations {
+		if math.IsNaN(r) {
+			return nil, fmt.Errorf("correlation of imputation %d is undefined", i+1)
+		}
+		// Keep |r| = 1 finite on the z scale
+		z[i] = math.Atanh(math.Max(-0.999999, math.Min(0.999999, r)))
+		variances[i] = 1 / float64(n-3)
+	}
+	pooled, err := PoolRubin(z, variances, float64(n-3))
+	if err != nil {
+		return nil, err
+	}
+	pooled.Correlation = math.Tanh(pooled.Estimate)
+	return pooled, nil
+}
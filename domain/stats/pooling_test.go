@@ -0,0 +1,46 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// TestPoolRubinMatchesHandComputation checks the pooled variance, fraction of missing
+// information and degrees of freedom against Rubin's formulas worked by hand
+func TestPoolRubinMatchesHandComputation(t *testing.T) {
+	pooled, err := PoolRubin([]float64{1.0, 1.2, 0.8}, []float64{0.04, 0.05, 0.06}, 100)
+	if err != nil {
+		t.Fatalf("PoolRubin: %v", err)
+	}
+	// mean 1.0, W = 0.05, B = 0.04, T = 0.05 + (4/3)·0.04
+	wantTotal := 0.05 + 4.0/3.0*0.04
+	wantLambda := 4.0 / 3.0 * 0.04 / wantTotal
+	if math.Abs(pooled.Estimate-1.0) > 1e-12 || math.Abs(pooled.BetweenVariance-0.04) > 1e-12 ||
+		math.Abs(pooled.TotalVariance-wantTotal) > 1e-12 || math.Abs(pooled.FractionMissingInfo-wantLambda) > 1e-12 {
+		t.Fatalf("unexpected pooling %+v", pooled)
+	}
+	oldDF := 2 / (wantLambda * wantLambda)
+	observedDF := 101.0 / 103.0 * 100 * (1 - wantLambda)
+	if want := oldDF * observedDF / (oldDF + observedDF); math.Abs(pooled.DegreesOfFreedom-want) > 1e-9 {
+		t.Errorf("df = %v, want %v", pooled.DegreesOfFreedom, want)
+	}
+}
+
+// TestPoolCorrelationsWidensWithDisagreement verifies that imputations disagreeing about a
+// correlation yield a weaker pooled p-value than identical ones
+func TestPoolCorrelationsWidensWithDisagreement(t *testing.T) {
+	agree, err := PoolCorrelations([]float64{0.3, 0.3, 0.3, 0.3, 0.3}, 80)
+	if err != nil {
+		t.Fatalf("PoolCorrelations: %v", err)
+	}
+	disagree, err := PoolCorrelations([]float64{0.05, 0.55, 0.1, 0.5, 0.3}, 80)
+	if err != nil {
+		t.Fatalf("PoolCorrelations: %v", err)
+	}
+	if math.Abs(agree.Correlation-0.3) > 1e-9 || agree.BetweenVariance != 0 {
+		t.Errorf("identical imputations should pool to r=0.3 without between variance, got %+v", agree)
+	}
+	if disagree.PValue <= agree.PValue || disagree.FractionMissingInfo < 0.5 {
+		t.Errorf("disagreeing imputations should weaken the evidence: agree p=%v, disagree %+v", agree.PValue, disagree)
+	}
+}
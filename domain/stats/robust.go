@@ -0,0 +1,218 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// RobustMethod selects the outlier-resistant correlation estimators reported next to Pearson
+type RobustMethod string
+
+const (
+	RobustOff        RobustMethod = "off"        // Pearson only
+	RobustWinsorized RobustMethod = "winsorized" // correlation of the winsorized samples
+	RobustBiweight   RobustMethod = "biweight"   // biweight midcorrelation
+	RobustBoth       RobustMethod = "both"       // both estimators, side by side
+)
+
+// ParseRobustMethod normalizes user input (e.g. "bicor", "winsor") to a RobustMethod
+func ParseRobustMethod(s string) (RobustMethod, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "off", "none":
+		return RobustOff, nil
+	case "winsorized", "winsorised", "winsor":
+		return RobustWinsorized, nil
+	case "biweight", "bicor", "biweight_midcorrelation":
+		return RobustBiweight, nil
+	case "both", "all":
+		return RobustBoth, nil
+	default:
+		return "", fmt.Errorf("unsupported robust correlation method: %q", s)
+	}
+}
+
+// DefaultWinsorTrim is the fraction of each tail clamped by the winsorized correlation
+const DefaultWinsorTrim = 0.1
+
+// outlierDrivenRatio is how much of the raw effect a robust estimate must keep, in the same
+// direction, for the finding not to be attributed to a few extreme points
+const outlierDrivenRatio = 0.5
+
+// RobustEstimate is one outlier-resistant estimate of a correlation
+type RobustEstimate struct {
+	Method      RobustMethod `json:"method"`
+	Coefficient float64      `json:"coefficient"`
+	PValue      float64      `json:"p_value"` // two-sided, t approximation
+}
+
+// RobustComparison sets a raw Pearson effect against its robust estimates. A finding is
+// outlier-driven when any robust estimate loses more than half of the raw effect or flips
+// its sign: the bulk of the data does not carry the association the raw coefficient reports.
+type RobustComparison struct {
+	Raw           float64          `json:"raw"`
+	Estimates     []RobustEstimate `json:"estimates"`
+	OutlierDriven bool             `json:"outlier_driven"`
+}
+
+// Weakest returns the robust estimate closest to no effect, the conservative robust effect size
+func (c *RobustComparison) Weakest() RobustEstimate {
+	weakest := c.Estimates[0]
+	for _, e := range c.Estimates[1:] {
+		if math.Abs(e.Coefficient) < math.Abs(weakest.Coefficient) {
+			weakest = e
+		}
+	}
+	return weakest
+}
+
+// CompareRobust computes the robust estimates selected by method for a pair and compares
+// them with the raw Pearson coefficient
+func CompareRobust(x, y []float64, raw float64, method RobustMethod) (*RobustComparison, error) {
+	return CompareRobustTrimmed(x, y, raw, method, DefaultWinsorTrim)
+}
+
+// CompareRobustTrimmed is CompareRobust with the winsorizing trim chosen for the pair
+func CompareRobustTrimmed(x, y []float64, raw float64, method RobustMethod, trim float64) (*RobustComparison, error) {
+	var methods []RobustMethod
+	switch method {
+	case RobustWinsorized, RobustBiweight:
+		methods = []RobustMethod{method}
+	case RobustBoth:
+		methods = []RobustMethod{RobustWinsorized, RobustBiweight}
+	default:
+		return nil, fmt.Errorf("no robust estimator selected")
+	}
+
+	comparison := &RobustComparison{Raw: raw}
+	for _, m := range methods {
+		var estimate *RobustEstimate
+		var err error
+		if m == RobustWinsorized {
+			estimate, err = WinsorizedCorrelation(x, y, trim)
+		} else {
+			estimate, err = BiweightMidcorrelation(x, y)
+		}
+		if err != nil {
+			return nil, err
+		}
+		comparison.Estimates = append(comparison.Estimates, *estimate)
+		if estimate.Coefficient*raw < 0 || math.Abs(estimate.Coefficient) < outlierDrivenRatio*math.Abs(raw) {
+			comparison.OutlierDriven = true
+		}
+	}
+	return comparison, nil
+}
+
+// WinsorizedCorrelation is the Pearson correlation after clamping the trim fraction of each
+// tail of both variables to the nearest retained value. The test uses h-2 degrees of freedom,
+// h being the number of values left untouched (Wilcox).
+func WinsorizedCorrelation(x, y []float64, trim float64) (*RobustEstimate, error) {
+	n := len(x)
+	if n != len(y) {
+		return nil, fmt.Errorf("winsorized correlation needs paired samples, got %d and %d", len(x), len(y))
+	}
+	if trim < 0 || trim >= 0.5 {
+		return nil, fmt.Errorf("winsorizing trim must be in [0, 0.5), got %g", trim)
+	}
+	g := int(math.Floor(trim * float64(n)))
+	h := n - 2*g
+	if h < 4 {
+		return nil, fmt.Errorf("winsorized correlation needs at least 4 untrimmed observations, got %d", h)
+	}
+
+	wx, wy := winsorize(x, g), winsorize(y, g)
+	r := stat.Correlation(wx, wy, nil)
+	if math.IsNaN(r) {
+		return nil, fmt.Errorf("winsorized correlation is undefined: a winsorized variable is constant")
+	}
+	return &RobustEstimate{Method: RobustWinsorized, Coefficient: r, PValue: correlationPValue(r, float64(h-2))}, nil
+}
+
+// BiweightMidcorrelation is the correlation of Tukey-biweighted deviations from the medians:
+// points more than nine MADs from a median get zero weight, so a handful of extreme values
+// cannot dominate the estimate.
+func BiweightMidcorrelation(x, y []float64) (*RobustEstimate, error) {
+	n := len(x)
+	if n != len(y) {
+		return nil, fmt.Errorf("biweight midcorrelation needs paired samples, got %d and %d", len(x), len(y))
+	}
+	if n < 4 {
+		return nil, fmt.Errorf("biweight midcorrelation needs at least 4 observations, got %d", n)
+	}
+
+	wx, err := biweightDeviations(x)
+	if err != nil {
+		return nil, err
+	}
+	wy, err := biweightDeviations(y)
+	if err != nil {
+		return nil, err
+	}
+	var sxy, sxx, syy float64
+	for i := range wx {
+		sxy += wx[i] * wy[i]
+		sxx += wx[i] * wx[i]
+		syy += wy[i] * wy[i]
+	}
+	if sxx == 0 || syy == 0 {
+		return nil, fmt.Errorf("biweight midcorrelation is undefined: no weighted spread")
+	}
+	r := sxy / math.Sqrt(sxx*syy)
+	return &RobustEstimate{Method: RobustBiweight, Coefficient: r, PValue: correlationPValue(r, float64(n-2))}, nil
+}
+
+// winsorize clamps the g smallest and g largest values to the nearest retained ones
+func winsorize(values []float64, g int) []float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	low, high := sorted[g], sorted[len(sorted)-1-g]
+	out := make([]float64, len(values))
+	for i, v := range values {
+		out[i] = math.Min(math.Max(v, low), high)
+	}
+	return out
+}
+
+// biweightDeviations returns the weighted deviations (x - med)·(1 - u²)² with
+// u = (x - med) / (9·MAD), zero for |u| ≥ 1
+func biweightDeviations(values []float64) ([]float64, error) {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	med := stat.Quantile(0.5, stat.Empirical, sorted, nil)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - med)
+	}
+	sort.Float64s(deviations)
+	mad := stat.Quantile(0.5, stat.Empirical, deviations, nil)
+	if mad == 0 {
+		return nil, fmt.Errorf("biweight midcorrelation is undefined: median absolute deviation is zero")
+	}
+
+	weighted := make([]float64, len(values))
+	for i, v := range values {
+		u := (v - med) / (9 * mad)
+		if math.Abs(u) < 1 {
+			w := 1 - u*u
+			weighted[i] = (v - med) * w * w
+		}
+	}
+	return weighted, nil
+}
+
+// correlationPValue is the two-sided p-value of a correlation by its t statistic
+func correlationPValue(r, df float64) float64 {
+	if df <= 0 {
+		return 1.0
+	}
+	if math.Abs(r) >= 1 {
+		return 0
+	}
+	t := r * math.Sqrt(df/(1-r*r))
+	return 2 * distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}.Survival(math.Abs(t))
+}
@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// TestRobustCorrelationFlagsSingleOutlier verifies that one extreme point producing a strong
+// Pearson effect in otherwise unrelated data is flagged as outlier-driven
+func TestRobustCorrelationFlagsSingleOutlier(t *testing.T) {
+	x := make([]float64, 0, 41)
+	y := make([]float64, 0, 41)
+	for i := 0; i < 40; i++ {
+		x = append(x, float64(i%8))
+		y = append(y, float64((i*5)%7))
+	}
+	x = append(x, 100)
+	y = append(y, 100)
+
+	raw := stat.Correlation(x, y, nil)
+	if raw < 0.9 {
+		t.Fatalf("expected the outlier to dominate Pearson, got r=%.3f", raw)
+	}
+	comparison, err := CompareRobust(x, y, raw, RobustBoth)
+	if err != nil {
+		t.Fatalf("CompareRobust: %v", err)
+	}
+	if len(comparison.Estimates) != 2 || !comparison.OutlierDriven {
+		t.Fatalf("expected two estimates flagged outlier-driven, got %+v", comparison)
+	}
+	if w := comparison.Weakest(); math.Abs(w.Coefficient) > 0.3 || w.PValue < 0.05 {
+		t.Errorf("expected a weak robust estimate, got %+v", w)
+	}
+}
+
+// TestRobustCorrelationKeepsGenuineEffect verifies that a linear relationship keeps its
+// strength under both estimators and is not flagged
+func TestRobustCorrelationKeepsGenuineEffect(t *testing.T) {
+	x := make([]float64, 50)
+	y := make([]float64, 50)
+	for i := range x {
+		x[i] = float64(i)
+		y[i] = 2*float64(i) + float64((i*7)%5)
+	}
+
+	raw := stat.Correlation(x, y, nil)
+	comparison, err := CompareRobust(x, y, raw, RobustBoth)
+	if err != nil {
+		t.Fatalf("CompareRobust: %v", err)
+	}
+	if comparison.OutlierDriven {
+		t.Fatalf("genuine effect flagged outlier-driven: %+v", comparison)
+	}
+	for _, e := range comparison.Estimates {
+		if e.Coefficient < 0.95 || e.PValue > 1e-6 {
+			t.Errorf("%s: expected a strong robust estimate, got %+v", e.Method, e)
+		}
+	}
+}
+
+func TestParseRobustMethod(t *testing.T) {
+	cases := map[string]RobustMethod{"": RobustOff, "bicor": RobustBiweight, "Winsorized": RobustWinsorized, "both": RobustBoth}
+	for in, want := range cases {
+		if got, err := ParseRobustMethod(in); err != nil || got != want {
+			t.Errorf("ParseRobustMethod(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseRobustMethod("median"); err == nil {
+		t.Error("expected an error for an unknown method")
+	}
+}
@@ -0,0 +1,133 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+// stlIterations is how many times the seasonal and trend passes alternate; STL's inner loop
+// converges in one or two for series without outliers
+const stlIterations = 2
+
+// SeasonalDecomposition splits a regularly spaced series into trend + seasonal + residual
+type SeasonalDecomposition struct {
+	Period   int       `json:"period"`
+	Trend    []float64 `json:"-"`
+	Seasonal []float64 `json:"-"`
+	Residual []float64 `json:"-"`
+	// Strength is 1 - Var(residual)/Var(seasonal+residual) (Wang, Smith & Hyndman 2006),
+	// floored at 0: near 1 the seasonal pattern dominates, near 0 there is none
+	Strength float64 `json:"strength"`
+}
+
+// DecomposeSeasonal runs an STL-style decomposition: cycle-subseries smoothing for the
+// seasonal component and a centred moving average for the trend, alternated so each is
+// estimated on the series with the other removed. The seasonal component sums to zero over
+// a cycle. The series needs at least two full cycles and no missing values.
+func DecomposeSeasonal(series []float64, period int) (*SeasonalDecomposition, error) {
+	n := len(series)
+	if period < 2 {
+		return nil, fmt.Errorf("seasonal period must be at least 2, got %d", period)
+	}
+	if n < 2*period {
+		return nil, fmt.Errorf("seasonal decomposition needs two full cycles (%d observations), got %d", 2*period, n)
+	}
+	for i, v := range series {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("series has a missing value at %d", i)
+		}
+	}
+
+	trend := make([]float64, n)
+	seasonal := make([]float64, n)
+	detrended := make([]float64, n)
+	deseasonalized := make([]float64, n)
+	for iter := 0; iter < stlIterations; iter++ {
+		for i := range series {
+			detrended[i] = series[i] - trend[i]
+		}
+		seasonal = cycleSubseriesMeans(detrended, period)
+		for i := range series {
+			deseasonalized[i] = series[i] - seasonal[i]
+		}
+		trend = centredMovingAverage(deseasonalized, period)
+	}
+
+	residual := make([]float64, n)
+	seasonalPlusResidual := make([]float64, n)
+	for i := range series {
+		residual[i] = series[i] - trend[i] - seasonal[i]
+		seasonalPlusResidual[i] = series[i] - trend[i]
+	}
+	strength := 0.0
+	if total := variance(seasonalPlusResidual); total > 0 {
+		strength = math.Max(0, 1-variance(residual)/total)
+	}
+	return &SeasonalDecomposition{Period: period, Trend: trend, Seasonal: seasonal, Residual: residual, Strength: strength}, nil
+}
+
+// cycleSubseriesMeans averages each position of the cycle and centres the result on zero
+func cycleSubseriesMeans(detrended []float64, period int) []float64 {
+	sums := make([]float64, period)
+	counts := make([]int, period)
+	for i, v := range detrended {
+		sums[i%period] += v
+		counts[i%period]++
+	}
+	means := make([]float64, period)
+	centre := 0.0
+	for p := range means {
+		means[p] = sums[p] / float64(counts[p])
+		centre += means[p]
+	}
+	centre /= float64(period)
+	out := make([]float64, len(detrended))
+	for i := range out {
+		out[i] = means[i%period] - centre
+	}
+	return out
+}
+
+// centredMovingAverage smooths over one full cycle (a 2×period average when the period is
+// even) so the seasonal pattern cancels; the ends use the widest symmetric window available
+func centredMovingAverage(series []float64, period int) []float64 {
+	n := len(series)
+	half := period / 2
+	out := make([]float64, n)
+	for i := range series {
+		h := half
+		if i < h {
+			h = i
+		}
+		if n-1-i < h {
+			h = n - 1 - i
+		}
+		sum, weight := 0.0, 0.0
+		for j := i - h; j <= i+h; j++ {
+			w := 1.0
+			if period%2 == 0 && h == half && (j == i-h || j == i+h) {
+				w = 0.5 // 2×m moving average for even periods
+			}
+			sum += w * series[j]
+			weight += w
+		}
+		out[i] = sum / weight
+	}
+	return out
+}
+
+func variance(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	ss := 0.0
+	for _, v := range values {
+		ss += (v - mean) * (v - mean)
+	}
+	return ss / float64(len(values)-1)
+}
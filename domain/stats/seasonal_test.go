@@ -0,0 +1,37 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// TestDecomposeSeasonalRecoversCycle verifies a weekly cycle on a linear trend is separated out
+func TestDecomposeSeasonalRecoversCycle(t *testing.T) {
+	series := make([]float64, 70)
+	for i := range series {
+		series[i] = 0.5*float64(i) + 10*math.Sin(2*math.Pi*float64(i)/7)
+	}
+	d, err := DecomposeSeasonal(series, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Strength < 0.9 {
+		t.Fatalf("expected a strongly seasonal series, got strength %.3f", d.Strength)
+	}
+	cycle := 0.0
+	for _, v := range d.Seasonal[:7] {
+		cycle += v
+	}
+	if math.Abs(cycle) > 1e-9 {
+		t.Fatalf("expected the seasonal component to sum to zero over a cycle, got %.6f", cycle)
+	}
+	for i := 7; i < len(series)-7; i++ {
+		if math.Abs(d.Trend[i]-0.5*float64(i)) > 0.5 {
+			t.Fatalf("trend at %d is %.3f, expected about %.3f", i, d.Trend[i], 0.5*float64(i))
+		}
+	}
+
+	if _, err := DecomposeSeasonal(series[:10], 7); err == nil {
+		t.Fatal("expected an error for fewer than two cycles")
+	}
+}
@@ -0,0 +1,272 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Skeptic-mode settings: the number of block permutations, the share of rows held out for
+// confirmation and the prior standard deviation of Fisher z effects shrinkage assumes
+const (
+	SkepticPermutations    = 999
+	SkepticHoldoutFraction = 0.3
+	SkepticPriorSD         = 0.2
+)
+
+// Skeptic checks, in the order they run
+const (
+	SkepticRobust      = "robust_estimator"
+	SkepticPermutation = "block_permutation"
+	SkepticCluster     = "cluster_correction"
+	SkepticShrinkage   = "shrinkage"
+	SkepticHoldout     = "holdout_confirmation"
+)
+
+const (
+	skepticMinRows     = 20
+	skepticMinHoldout  = 10
+	skepticMinClusters = 5
+	skepticMaxClusters = 20
+)
+
+// SkepticEstimate is a pair's effect and significance under one set of analysis choices
+type SkepticEstimate struct {
+	Effect      float64 `json:"effect"`
+	PValue      float64 `json:"p_value"`
+	Significant bool    `json:"significant"`
+	Strength    string  `json:"strength"`
+}
+
+// SkepticCheck is the outcome of one conservative choice
+type SkepticCheck struct {
+	Name   string  `json:"name"`
+	Effect float64 `json:"effect"`
+	PValue float64 `json:"p_value"`
+	Passed bool    `json:"passed"`
+	Detail string  `json:"detail"`
+}
+
+// SkepticReport compares a pair's association as reported with the one left after the most
+// conservative choices: the weakest robust estimator, shrunk toward no effect, tested by block
+// permutation and a cluster-robust jackknife, and confirmed on rows held out at the end.
+// Survives when every check passes.
+type SkepticReport struct {
+	SampleSize int             `json:"sample_size"`
+	BlockSize  int             `json:"block_size"`
+	Before     SkepticEstimate `json:"before"`
+	After      SkepticEstimate `json:"after"`
+	Checks     []SkepticCheck  `json:"checks"`
+	Survives   bool            `json:"survives"`
+	Summary    string          `json:"summary"`
+}
+
+// SkepticReanalysis re-analyzes the association of x and y in row order with the most
+// conservative choices under policy. Rows missing either value are dropped; seed fixes the
+// permutations so a re-run reports the same result.
+func SkepticReanalysis(x, y []float64, policy AnalysisPolicy, seed int64) (*SkepticReport, error) {
+	policy = policy.WithDefaults()
+	xs, ys := completePairs(x, y)
+	n := len(xs)
+	if n < skepticMinRows {
+		return nil, fmt.Errorf("skeptic mode needs at least %d complete rows, got %d", skepticMinRows, n)
+	}
+	raw := stat.Correlation(xs, ys, nil)
+	if math.IsNaN(raw) {
+		return nil, fmt.Errorf("correlation is undefined: one of the variables is constant")
+	}
+	rawP := correlationPValue(raw, float64(n-2))
+	report := &SkepticReport{
+		SampleSize: n,
+		BlockSize:  skepticBlockSize(n),
+		Before:     skepticEstimate(policy, raw, rawP),
+	}
+
+	comparison, err := CompareRobust(xs, ys, raw, RobustBoth)
+	if err != nil {
+		return nil, err
+	}
+	weakest := comparison.Weakest()
+	report.Checks = append(report.Checks, SkepticCheck{
+		Name:   SkepticRobust,
+		Effect: weakest.Coefficient,
+		PValue: weakest.PValue,
+		Passed: !comparison.OutlierDriven,
+		Detail: fmt.Sprintf("weakest robust estimate (%s) r = %.3f against Pearson r = %.3f", weakest.Method, weakest.Coefficient, raw),
+	})
+
+	rng := rand.New(rand.NewSource(seed))
+	permP := blockPermutationPValue(xs, ys, raw, report.BlockSize, SkepticPermutations, rng)
+	report.Checks = append(report.Checks, SkepticCheck{
+		Name:   SkepticPermutation,
+		Effect: raw,
+		PValue: permP,
+		Passed: policy.Significant(permP),
+		Detail: fmt.Sprintf("%d permutations of blocks of %d rows, keeping serial dependence", SkepticPermutations, report.BlockSize),
+	})
+
+	clusterP, clusters := clusterJackknifePValue(xs, ys, raw, report.BlockSize)
+	report.Checks = append(report.Checks, SkepticCheck{
+		Name:   SkepticCluster,
+		Effect: raw,
+		PValue: clusterP,
+		Passed: policy.Significant(clusterP),
+		Detail: fmt.Sprintf("delete-a-cluster jackknife over %d contiguous clusters", clusters),
+	})
+
+	shrunk := shrinkCorrelation(weakest.Coefficient, n)
+	report.Checks = append(report.Checks, SkepticCheck{
+		Name:   SkepticShrinkage,
+		Effect: shrunk,
+		PValue: weakest.PValue,
+		Passed: policy.Meaningful(shrunk),
+		Detail: fmt.Sprintf("robust effect shrunk toward zero under a N(0, %.2f²) prior on Fisher z; needs |r| > %.2f", SkepticPriorSD, policy.MinEffect),
+	})
+
+	holdout := skepticHoldoutCheck(xs, ys, raw, policy)
+	report.Checks = append(report.Checks, holdout)
+
+	pValue := math.Max(permP, clusterP)
+	report.After = skepticEstimate(policy, shrunk, pValue)
+	report.After.Significant = report.After.Significant && holdout.Passed
+
+	var failed []string
+	for _, check := range report.Checks {
+		if !check.Passed {
+			failed = append(failed, strings.ReplaceAll(check.Name, "_", " "))
+		}
+	}
+	report.Survives = len(failed) == 0
+	if report.Survives {
+		report.Summary = fmt.Sprintf("Survives skeptic mode: r = %.3f (%s) → %.3f (%s), p ≤ %.3g under the most conservative choices",
+			raw, report.Before.Strength, shrunk, report.After.Strength, pValue)
+	} else {
+		report.Summary = fmt.Sprintf("Does not survive skeptic mode: fails %s; r = %.3f → %.3f", strings.Join(failed, ", "), raw, shrunk)
+	}
+	return report, nil
+}
+
+// skepticEstimate judges an effect and p-value by policy
+func skepticEstimate(policy AnalysisPolicy, effect, pValue float64) SkepticEstimate {
+	return SkepticEstimate{
+		Effect:      effect,
+		PValue:      pValue,
+		Significant: policy.Significant(pValue),
+		Strength:    policy.EffectStrength(effect),
+	}
+}
+
+// completePairs keeps the rows where both values are present, in order
+func completePairs(x, y []float64) ([]float64, []float64) {
+	xs := make([]float64, 0, len(x))
+	ys := make([]float64, 0, len(y))
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if math.IsNaN(x[i]) || math.IsNaN(y[i]) {
+			continue
+		}
+		xs = append(xs, x[i])
+		ys = append(ys, y[i])
+	}
+	return xs, ys
+}
+
+// skepticBlockSize is the block length for n rows, n^(1/3) rounded and at least 2, the usual
+// rate for block resampling of weakly dependent series
+func skepticBlockSize(n int) int {
+	return max(2, int(math.Round(math.Cbrt(float64(n)))))
+}
+
+// blockPermutationPValue is the two-sided permutation p-value of a correlation when y is
+// shuffled in contiguous blocks, so serial dependence within blocks survives the permutation
+func blockPermutationPValue(x, y []float64, observed float64, blockSize, permutations int, rng *rand.Rand) float64 {
+	var starts []int
+	for start := 0; start < len(y); start += blockSize {
+		starts = append(starts, start)
+	}
+	permuted := make([]float64, 0, len(y))
+	extreme := 0
+	for i := 0; i < permutations; i++ {
+		rng.Shuffle(len(starts), func(a, b int) { starts[a], starts[b] = starts[b], starts[a] })
+		permuted = permuted[:0]
+		for _, start := range starts {
+			permuted = append(permuted, y[start:min(start+blockSize, len(y))]...)
+		}
+		if r := stat.Correlation(x, permuted, nil); math.Abs(r) >= math.Abs(observed) {
+			extreme++
+		}
+	}
+	return float64(extreme+1) / float64(permutations+1)
+}
+
+// clusterJackknifePValue tests a correlation with a delete-a-group jackknife over contiguous
+// clusters of rows, on the Fisher z scale with clusters-1 degrees of freedom. Dependence
+// within clusters widens the jackknife spread instead of being counted as independent rows.
+func clusterJackknifePValue(x, y []float64, observed float64, blockSize int) (float64, int) {
+	n := len(x)
+	clusters := min(skepticMaxClusters, max(skepticMinClusters, n/blockSize))
+	z := make([]float64, 0, clusters)
+	xs := make([]float64, 0, n)
+	ys := make([]float64, 0, n)
+	for k := 0; k < clusters; k++ {
+		lo, hi := k*n/clusters, (k+1)*n/clusters
+		xs = append(append(xs[:0], x[:lo]...), x[hi:]...)
+		ys = append(append(ys[:0], y[:lo]...), y[hi:]...)
+		z = append(z, fisherZ(stat.Correlation(xs, ys, nil)))
+	}
+	mean := stat.Mean(z, nil)
+	var spread float64
+	for _, zk := range z {
+		spread += (zk - mean) * (zk - mean)
+	}
+	se := math.Sqrt(float64(clusters-1) / float64(clusters) * spread)
+	if se == 0 || math.IsNaN(se) {
+		return correlationPValue(observed, float64(n-2)), clusters
+	}
+	t := fisherZ(observed) / se
+	return 2 * distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(clusters - 1)}.Survival(math.Abs(t)), clusters
+}
+
+// shrinkCorrelation pulls a correlation toward zero by the posterior mean of its Fisher z
+// under a N(0, SkepticPriorSD²) prior, so small samples keep less of their effect
+func shrinkCorrelation(r float64, n int) float64 {
+	prior := SkepticPriorSD * SkepticPriorSD
+	sampling := 1 / float64(max(n-3, 1))
+	return math.Tanh(fisherZ(r) * prior / (prior + sampling))
+}
+
+// skepticHoldoutCheck confirms the association on the last rows, which played no part in
+// how the rest were analyzed: same direction and one-sided significance there
+func skepticHoldoutCheck(x, y []float64, observed float64, policy AnalysisPolicy) SkepticCheck {
+	n := len(x)
+	h := int(math.Round(float64(n) * SkepticHoldoutFraction))
+	check := SkepticCheck{Name: SkepticHoldout, PValue: 1}
+	if h < skepticMinHoldout {
+		check.Detail = fmt.Sprintf("too few rows to hold out %d (need %d)", h, skepticMinHoldout)
+		return check
+	}
+	r := stat.Correlation(x[n-h:], y[n-h:], nil)
+	if math.IsNaN(r) {
+		check.Detail = fmt.Sprintf("a variable is constant on the last %d rows", h)
+		return check
+	}
+	twoSided := correlationPValue(r, float64(h-2))
+	check.Effect = r
+	if (r > 0) == (observed > 0) {
+		check.PValue = twoSided / 2
+	} else {
+		check.PValue = 1 - twoSided/2
+	}
+	check.Passed = policy.Significant(check.PValue)
+	check.Detail = fmt.Sprintf("r = %.3f on the last %d rows, one-sided in the reported direction", r, h)
+	return check
+}
+
+// fisherZ is atanh(r), clamped so perfect correlations stay finite
+func fisherZ(r float64) float64 {
+	const limit = 0.999999
+	return math.Atanh(math.Max(-limit, math.Min(limit, r)))
+}
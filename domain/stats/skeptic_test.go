@@ -0,0 +1,89 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestSkepticReanalysisKeepsGenuineEffect verifies a strong linear relationship in
+// independent rows survives every conservative choice, with a smaller effect after shrinkage
+func TestSkepticReanalysisKeepsGenuineEffect(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	x := make([]float64, 200)
+	y := make([]float64, 200)
+	for i := range x {
+		x[i] = rng.NormFloat64()
+		y[i] = 0.8*x[i] + 0.5*rng.NormFloat64()
+	}
+
+	report, err := SkepticReanalysis(x, y, DefaultAnalysisPolicy(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Survives || !report.Before.Significant || !report.After.Significant {
+		t.Fatalf("expected the effect to survive, got %+v", report)
+	}
+	if len(report.Checks) != 5 {
+		t.Fatalf("expected five checks, got %d", len(report.Checks))
+	}
+	if after, before := math.Abs(report.After.Effect), math.Abs(report.Before.Effect); after >= before || after < 0.5 {
+		t.Errorf("expected a slightly shrunk strong effect, got %.3f from %.3f", after, before)
+	}
+
+	again, err := SkepticReanalysis(x, y, DefaultAnalysisPolicy(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.After != report.After {
+		t.Errorf("expected the same seed to reproduce the result, got %+v and %+v", again.After, report.After)
+	}
+}
+
+// TestSkepticReanalysisRejectsTrendArtifact verifies two unrelated random walks, which
+// correlate by their shared drift alone, pass naively but not in skeptic mode
+func TestSkepticReanalysisRejectsTrendArtifact(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	x := make([]float64, 120)
+	y := make([]float64, 120)
+	for i := 1; i < len(x); i++ {
+		x[i] = x[i-1] + 0.2 + rng.NormFloat64()
+		y[i] = y[i-1] + 0.2 + rng.NormFloat64()
+	}
+	for i := 80; i < len(y); i++ {
+		y[i] = y[79] + rng.NormFloat64()
+	}
+
+	report, err := SkepticReanalysis(x, y, DefaultAnalysisPolicy(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Before.Significant {
+		t.Fatalf("expected the drift to look significant as reported, got p=%.3g", report.Before.PValue)
+	}
+	if report.Survives || report.After.Significant {
+		t.Fatalf("expected skeptic mode to reject the trend artifact, got %+v", report)
+	}
+	failed := map[string]bool{}
+	for _, check := range report.Checks {
+		failed[check.Name] = !check.Passed
+	}
+	if !failed[SkepticHoldout] {
+		t.Errorf("expected the holdout rows to disconfirm the trend, got %+v", report.Checks)
+	}
+}
+
+// TestSkepticReanalysisNeedsRows rejects pairs with too few complete rows
+func TestSkepticReanalysisNeedsRows(t *testing.T) {
+	x := make([]float64, 30)
+	y := make([]float64, 30)
+	for i := range x {
+		x[i], y[i] = float64(i), float64(i%7)
+		if i%2 == 0 {
+			y[i] = math.NaN()
+		}
+	}
+	if _, err := SkepticReanalysis(x, y, DefaultAnalysisPolicy(), 1); err == nil {
+		t.Fatal("expected an error for 15 complete rows")
+	}
+}
@@ -0,0 +1,278 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+// stationarityAlpha is the significance level of both stationarity tests
+const stationarityAlpha = 0.05
+
+// maxDifferences bounds how many times a series is differenced looking for stationarity;
+// a series that needs more is reported as non-stationary rather than over-differenced
+const maxDifferences = 2
+
+// minStationarityObservations is the shortest series the tests are run on
+const minStationarityObservations = 20
+
+// StationarityCheck is one ADF/KPSS test pair on a series. ADF's null is a unit root, KPSS's
+// null is stationarity, so a series counts as stationary only when ADF rejects and KPSS does
+// not.
+type StationarityCheck struct {
+	Differences   int     `json:"differences"` // differencing applied before this check
+	Observations  int     `json:"observations"`
+	ADFStatistic  float64 `json:"adf_statistic"`
+	ADFPValue     float64 `json:"adf_p_value"`
+	ADFLags       int     `json:"adf_lags"`
+	KPSSStatistic float64 `json:"kpss_statistic"`
+	KPSSPValue    float64 `json:"kpss_p_value"` // interpolated from the table, so clamped to [0.01, 0.10]
+	Stationary    bool    `json:"stationary"`
+}
+
+// SeriesTransformation records what was done to a series to make it stationary
+type SeriesTransformation struct {
+	Variable    string              `json:"variable"`
+	Differences int                 `json:"differences"`
+	Stationary  bool                `json:"stationary"` // false when maxDifferences was not enough
+	Checks      []StationarityCheck `json:"checks"`
+}
+
+// CheckStationarity runs ADF (constant, Schwert lag rule) and level KPSS on a series
+func CheckStationarity(series []float64) (StationarityCheck, error) {
+	n := len(series)
+	if n < minStationarityObservations {
+		return StationarityCheck{}, fmt.Errorf("stationarity tests need at least %d observations, got %d", minStationarityObservations, n)
+	}
+	check := StationarityCheck{Observations: n}
+	check.ADFLags = int(12 * math.Pow(float64(n)/100, 0.25))
+	if check.ADFLags > n/4 {
+		check.ADFLags = n / 4
+	}
+	stat, ok := adfStatistic(series, check.ADFLags)
+	if !ok {
+		return StationarityCheck{}, fmt.Errorf("ADF regression is singular (constant series?)")
+	}
+	check.ADFStatistic = stat
+	check.ADFPValue = mackinnonPValue(stat)
+	check.KPSSStatistic = kpssStatistic(series, int(4*math.Pow(float64(n)/100, 0.25)))
+	check.KPSSPValue = kpssPValue(check.KPSSStatistic)
+	check.Stationary = check.ADFPValue < stationarityAlpha && check.KPSSPValue > stationarityAlpha
+	return check, nil
+}
+
+// MakeStationary differences a series until both tests agree it is stationary, at most
+// maxDifferences times. Each difference shortens the series by one observation.
+func MakeStationary(variable string, series []float64) ([]float64, SeriesTransformation, error) {
+	transform := SeriesTransformation{Variable: variable}
+	current := series
+	for {
+		check, err := CheckStationarity(current)
+		if err != nil {
+			return nil, transform, err
+		}
+		check.Differences = transform.Differences
+		transform.Checks = append(transform.Checks, check)
+		if check.Stationary {
+			transform.Stationary = true
+			return current, transform, nil
+		}
+		if transform.Differences == maxDifferences {
+			return current, transform, nil
+		}
+		current = Difference(current)
+		transform.Differences++
+	}
+}
+
+// Difference returns the first differences x[t] - x[t-1]
+func Difference(series []float64) []float64 {
+	if len(series) < 2 {
+		return nil
+	}
+	out := make([]float64, len(series)-1)
+	for i := 1; i < len(series); i++ {
+		out[i-1] = series[i] - series[i-1]
+	}
+	return out
+}
+
+// adfStatistic regresses Δy_t on a constant, y_{t-1} and p lagged differences and returns the
+// t statistic of the y_{t-1} coefficient
+func adfStatistic(y []float64, p int) (float64, bool) {
+	dy := Difference(y)
+	rows := len(dy) - p
+	k := p + 2
+	if rows <= k {
+		return 0, false
+	}
+	X := make([][]float64, rows)
+	target := make([]float64, rows)
+	for r := 0; r < rows; r++ {
+		t := r + p // index into dy
+		row := make([]float64, k)
+		row[0] = 1
+		row[1] = y[t]
+		for j := 1; j <= p; j++ {
+			row[1+j] = dy[t-j]
+		}
+		X[r] = row
+		target[r] = dy[t]
+	}
+
+	xtx := make([][]float64, k)
+	xty := make([]float64, k)
+	for a := 0; a < k; a++ {
+		xtx[a] = make([]float64, k)
+	}
+	for r, row := range X {
+		for a := 0; a < k; a++ {
+			xty[a] += row[a] * target[r]
+			for b := 0; b < k; b++ {
+				xtx[a][b] += row[a] * row[b]
+			}
+		}
+	}
+	inv, ok := invertSymmetric(xtx)
+	if !ok {
+		return 0, false
+	}
+	beta := make([]float64, k)
+	for a := 0; a < k; a++ {
+		for b := 0; b < k; b++ {
+			beta[a] += inv[a][b] * xty[b]
+		}
+	}
+	ssr := 0.0
+	for r, row := range X {
+		fit := 0.0
+		for a, v := range row {
+			fit += beta[a] * v
+		}
+		ssr += (target[r] - fit) * (target[r] - fit)
+	}
+	sigma2 := ssr / float64(rows-k)
+	se := math.Sqrt(sigma2 * inv[1][1])
+	if se == 0 || math.IsNaN(se) {
+		return 0, false
+	}
+	return beta[1] / se, true
+}
+
+// invertSymmetric inverts a small symmetric matrix by Gauss-Jordan elimination with pivoting
+func invertSymmetric(m [][]float64) ([][]float64, bool) {
+	n := len(m)
+	a := make([][]float64, n)
+	for i := range m {
+		a[i] = make([]float64, 2*n)
+		copy(a[i], m[i])
+		a[i][n+i] = 1
+	}
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		scale := a[col][col]
+		for j := range a[col] {
+			a[col][j] /= scale
+		}
+		for r := 0; r < n; r++ {
+			if r == col || a[r][col] == 0 {
+				continue
+			}
+			factor := a[r][col]
+			for j := range a[r] {
+				a[r][j] -= factor * a[col][j]
+			}
+		}
+	}
+	inv := make([][]float64, n)
+	for i := range a {
+		inv[i] = a[i][n:]
+	}
+	return inv, true
+}
+
+// mackinnonPValue approximates the ADF p-value for the constant-only case from MacKinnon's
+// (1994) response surface, as statsmodels does
+func mackinnonPValue(stat float64) float64 {
+	const tauMax, tauMin, tauStar = 2.74, -18.83, -1.61
+	switch {
+	case stat > tauMax:
+		return 1
+	case stat < tauMin:
+		return 0
+	}
+	var coefs []float64
+	if stat <= tauStar {
+		coefs = []float64{2.1659, 1.4412, 0.038269}
+	} else {
+		coefs = []float64{1.7339, 0.93202, -0.12745, -0.010368}
+	}
+	z, power := 0.0, 1.0
+	for _, c := range coefs {
+		z += c * power
+		power *= stat
+	}
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+// kpssStatistic is the level-stationarity KPSS statistic with a Bartlett-kernel long-run
+// variance over the given number of lags
+func kpssStatistic(y []float64, lags int) float64 {
+	n := len(y)
+	mean := 0.0
+	for _, v := range y {
+		mean += v
+	}
+	mean /= float64(n)
+	resid := make([]float64, n)
+	for i, v := range y {
+		resid[i] = v - mean
+	}
+
+	longRun := 0.0
+	for _, e := range resid {
+		longRun += e * e
+	}
+	for l := 1; l <= lags && l < n; l++ {
+		cov := 0.0
+		for t := l; t < n; t++ {
+			cov += resid[t] * resid[t-l]
+		}
+		longRun += 2 * (1 - float64(l)/float64(lags+1)) * cov
+	}
+	longRun /= float64(n)
+	if longRun <= 0 {
+		return 0
+	}
+
+	partial, sumSq := 0.0, 0.0
+	for _, e := range resid {
+		partial += e
+		sumSq += partial * partial
+	}
+	return sumSq / (float64(n) * float64(n) * longRun)
+}
+
+// kpssPValue interpolates the level KPSS critical values (Kwiatkowski et al. 1992, table 1)
+func kpssPValue(stat float64) float64 {
+	crit := []float64{0.347, 0.463, 0.574, 0.739}
+	pvals := []float64{0.10, 0.05, 0.025, 0.01}
+	if stat <= crit[0] {
+		return pvals[0]
+	}
+	for i := 1; i < len(crit); i++ {
+		if stat <= crit[i] {
+			frac := (stat - crit[i-1]) / (crit[i] - crit[i-1])
+			return pvals[i-1] + frac*(pvals[i]-pvals[i-1])
+		}
+	}
+	return pvals[len(pvals)-1]
+}
@@ -0,0 +1,35 @@
+package stats
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMakeStationaryDifferencesRandomWalk(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	noise := make([]float64, 300)
+	walk := make([]float64, 300)
+	for i := range noise {
+		noise[i] = rng.NormFloat64()
+		walk[i] = noise[i]
+		if i > 0 {
+			walk[i] += walk[i-1]
+		}
+	}
+
+	check, err := CheckStationarity(noise)
+	if err != nil || !check.Stationary {
+		t.Fatalf("white noise should be stationary: %+v (%v)", check, err)
+	}
+
+	series, transform, err := MakeStationary("walk", walk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transform.Differences != 1 || !transform.Stationary || len(series) != len(walk)-1 {
+		t.Fatalf("a random walk should need exactly one difference: %+v", transform)
+	}
+	if first := transform.Checks[0]; first.Stationary || first.ADFPValue < 0.05 {
+		t.Errorf("undifferenced walk should fail ADF: %+v", first)
+	}
+}
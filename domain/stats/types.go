@@ -80,6 +80,8 @@ const (
 	WarningLowN               WarningCode = "LOW_N"               // Sample size < 30
 	WarningHighMissing        WarningCode = "HIGH_MISSING"        // >30% missing in either variable
 	WarningSparseData         WarningCode = "SPARSE_DATA"         // Very few non-zero values
+	WarningOutlierDriven      WarningCode = "OUTLIER_DRIVEN"      // Robust estimates lose most of the raw effect
+	WarningMixedDivergence    WarningCode = "MIXED_DIVERGENCE"    // Pooled and mixed-model estimates disagree on panel data
 )
 
 // ============================================================================
@@ -256,6 +258,7 @@ const (
 	TestANOVA         TestType = "anova"          // Analysis of variance
 	TestMannWhitney   TestType = "mann_whitney"   // Mann-Whitney U test
 	TestKruskalWallis TestType = "kruskal_wallis" // Kruskal-Wallis test
+	TestTrend         TestType = "trend"          // Cochran-Armitage trend test
 )
 
 // StatisticalType defines variable types for analysis (moved from dataset for DRY)
@@ -264,6 +267,7 @@ type StatisticalType string
 const (
 	TypeNumeric     StatisticalType = "numeric"
 	TypeCategorical StatisticalType = "categorical"
+	TypeOrdinal     StatisticalType = "ordinal"
 	TypeBinary      StatisticalType = "binary"
 	TypeTimestamp   StatisticalType = "timestamp"
 	TypeText        StatisticalType = "text"
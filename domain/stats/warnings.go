@@ -0,0 +1,116 @@
+package stats
+
+import "sort"
+
+// WarningSeverity ranks how much a warning undermines the result it is attached to
+type WarningSeverity string
+
+const (
+	WarningSeverityInfo     WarningSeverity = "info"     // worth knowing; rarely changes how a result reads
+	WarningSeverityCaution  WarningSeverity = "caution"  // weakens the result; confirm before acting on it
+	WarningSeverityCritical WarningSeverity = "critical" // likely invalidates the result until resolved
+)
+
+// severityRank orders severities from most to least serious
+var severityRank = map[WarningSeverity]int{
+	WarningSeverityCritical: 0,
+	WarningSeverityCaution:  1,
+	WarningSeverityInfo:     2,
+}
+
+// WarningInfo describes a warning code for the people reading results: what it means, how
+// serious it is, and what to do about it
+type WarningInfo struct {
+	Code        WarningCode     `json:"code"`
+	Title       string          `json:"title"`
+	Severity    WarningSeverity `json:"severity"`
+	Description string          `json:"description"`
+	Remediation string          `json:"remediation"`
+}
+
+var warningCatalog = map[WarningCode]WarningInfo{
+	WarningPerfectCorrelation: {
+		Title:       "Perfect correlation",
+		Severity:    WarningSeverityCritical,
+		Description: "The two variables move in lockstep (|r| = 1). Real-world relationships are never this clean; one variable is almost always a copy, transform or component of the other.",
+		Remediation: "Check whether one column is derived from the other (unit conversions, totals, lagged copies, IDs) and exclude it from the sweep.",
+	},
+	WarningLikelyDerived: {
+		Title:       "Likely derived variable",
+		Severity:    WarningSeverityCritical,
+		Description: "The variables look mathematically related, for example a ratio and its numerator or a sum and one of its parts, so the association is built into the data rather than discovered.",
+		Remediation: "Keep the source column and drop the derived one, or mark the pair as known in the variable contracts.",
+	},
+	WarningOutlierDriven: {
+		Title:       "Driven by outliers",
+		Severity:    WarningSeverityCaution,
+		Description: "Robust estimates lose most of the raw effect: a handful of extreme rows carry the relationship.",
+		Remediation: "Inspect the extreme rows for entry errors or one-off events, then rerun with them removed or winsorized and compare.",
+	},
+	WarningMixedDivergence: {
+		Title:       "Pooled and within-entity effects differ",
+		Severity:    WarningSeverityCaution,
+		Description: "On this panel data the mixed model, which gives each entity its own baseline, finds a much weaker or opposite effect than the pooled estimate: much of the association is differences between entities, not changes within them.",
+		Remediation: "Read the mixed-model estimate for claims about what happens when a variable changes for one entity; keep the pooled estimate for comparisons across entities, and look for entity-level confounders.",
+	},
+	WarningHighMissing: {
+		Title:       "High missingness",
+		Severity:    WarningSeverityCaution,
+		Description: "More than 30% of rows are missing in at least one of the variables, so the result rests on the rows that happen to be complete, which may not be representative.",
+		Remediation: "Check why values are missing; backfill the source, join a more complete dataset, or restrict the claim to the population with complete records.",
+	},
+	WarningLowN: {
+		Title:       "Small sample",
+		Severity:    WarningSeverityCaution,
+		Description: "Fewer than 30 usable observations. Estimates are noisy and a few rows can flip the conclusion.",
+		Remediation: "Collect more rows or widen the time range before relying on the result; treat it as a lead rather than a finding.",
+	},
+	WarningLowVariance: {
+		Title:       "Low variance",
+		Severity:    WarningSeverityInfo,
+		Description: "One of the variables barely changes, which leaves little signal to relate to anything and makes the statistics unstable.",
+		Remediation: "Confirm the column is not a constant or a default value; if it is legitimately near-constant, exclude it from the sweep.",
+	},
+	WarningSparseData: {
+		Title:       "Sparse data",
+		Severity:    WarningSeverityInfo,
+		Description: "Very few values are non-zero, so the relationship is decided by a small subset of rows.",
+		Remediation: "Consider modelling the column as a yes/no indicator, or aggregating to a level where it is less sparse.",
+	},
+}
+
+// DescribeWarning returns the catalog entry of code, or a generic one for codes the catalog
+// does not know, so new codes are still shown rather than dropped
+func DescribeWarning(code WarningCode) WarningInfo {
+	if info, ok := warningCatalog[code]; ok {
+		info.Code = code
+		return info
+	}
+	return WarningInfo{
+		Code:        code,
+		Title:       string(code),
+		Severity:    WarningSeverityCaution,
+		Description: "The analysis flagged this result with a warning that has no description yet.",
+		Remediation: "Review the underlying data for the pair before relying on the result.",
+	}
+}
+
+// WarningCatalog returns every known warning, most serious first
+func WarningCatalog() []WarningInfo {
+	infos := make([]WarningInfo, 0, len(warningCatalog))
+	for code := range warningCatalog {
+		infos = append(infos, DescribeWarning(code))
+	}
+	SortWarnings(infos)
+	return infos
+}
+
+// SortWarnings orders warnings most serious first, then by code
+func SortWarnings(infos []WarningInfo) {
+	sort.SliceStable(infos, func(i, j int) bool {
+		if ri, rj := severityRank[infos[i].Severity], severityRank[infos[j].Severity]; ri != rj {
+			return ri < rj
+		}
+		return infos[i].Code < infos[j].Code
+	})
+}
@@ -0,0 +1,48 @@
+package stats
+
+import "testing"
+
+// TestWarningCatalogCoversEveryCode ensures each declared warning code has guidance
+func TestWarningCatalogCoversEveryCode(t *testing.T) {
+	codes := []WarningCode{
+		WarningPerfectCorrelation, WarningLowVariance, WarningLikelyDerived, WarningLowN,
+		WarningHighMissing, WarningSparseData, WarningOutlierDriven, WarningMixedDivergence,
+	}
+	for _, code := range codes {
+		info, ok := warningCatalog[code]
+		if !ok {
+			t.Errorf("%s has no catalog entry", code)
+			continue
+		}
+		if info.Title == "" || info.Description == "" || info.Remediation == "" {
+			t.Errorf("%s is missing its title, description or remediation", code)
+		}
+		if _, ok := severityRank[info.Severity]; !ok {
+			t.Errorf("%s has unknown severity %q", code, info.Severity)
+		}
+	}
+	if len(WarningCatalog()) != len(codes) {
+		t.Errorf("expected %d catalog entries, got %d", len(codes), len(WarningCatalog()))
+	}
+}
+
+// TestWarningCatalogOrder checks the catalog lists the most serious warnings first
+func TestWarningCatalogOrder(t *testing.T) {
+	catalog := WarningCatalog()
+	for i := 1; i < len(catalog); i++ {
+		if severityRank[catalog[i-1].Severity] > severityRank[catalog[i].Severity] {
+			t.Fatalf("%s (%s) listed before %s (%s)", catalog[i-1].Code, catalog[i-1].Severity, catalog[i].Code, catalog[i].Severity)
+		}
+	}
+}
+
+// TestDescribeUnknownWarning keeps codes the catalog does not know visible
+func TestDescribeUnknownWarning(t *testing.T) {
+	info := DescribeWarning("NEW_CHECK")
+	if info.Code != "NEW_CHECK" || info.Title != "NEW_CHECK" || info.Remediation == "" {
+		t.Fatalf("expected a generic entry for an unknown code, got %+v", info)
+	}
+	if known := DescribeWarning(WarningLowN); known.Code != WarningLowN || known.Severity != WarningSeverityCaution {
+		t.Fatalf("expected the catalog entry for LOW_N, got %+v", known)
+	}
+}
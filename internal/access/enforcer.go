@@ -0,0 +1,153 @@
+package access
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"gohypo/domain/access"
+	"gohypo/domain/core"
+)
+
+// maxRecordedDecisions bounds the in-memory decision log
+const maxRecordedDecisions = 1000
+
+// PolicyFile is the on-disk format for column policies and role bindings
+type PolicyFile struct {
+	Rules        []access.ColumnRule `json:"rules"`
+	RoleBindings map[string][]string `json:"role_bindings"` // user ID -> roles
+}
+
+// Enforcer applies column-level access policies and logs every decision
+type Enforcer struct {
+	policy       *access.ColumnPolicy
+	roleBindings map[core.ID][]string
+
+	mu        sync.RWMutex
+	decisions []access.Decision
+}
+
+// NewEnforcer creates an enforcer for a policy and user role bindings
+func NewEnforcer(policy *access.ColumnPolicy, roleBindings map[core.ID][]string) *Enforcer {
+	if policy == nil {
+		policy = &access.ColumnPolicy{}
+	}
+	if roleBindings == nil {
+		roleBindings = make(map[core.ID][]string)
+	}
+	return &Enforcer{
+		policy:       policy,
+		roleBindings: roleBindings,
+		decisions:    make([]access.Decision, 0),
+	}
+}
+
+// LoadEnforcer reads a JSON policy file and builds an enforcer from it
+func LoadEnforcer(path string) (*Enforcer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read column policy file: %w", err)
+	}
+
+	var file PolicyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse column policy file: %w", err)
+	}
+
+	for i, rule := range file.Rules {
+		if rule.Pattern == "" {
+			return nil, core.NewValidationError(fmt.Sprintf("rules[%d]", i), "pattern is required")
+		}
+	}
+
+	bindings := make(map[core.ID][]string, len(file.RoleBindings))
+	for userID, roles := range file.RoleBindings {
+		bindings[core.ID(userID)] = roles
+	}
+
+	log.Printf("[ColumnPolicy] Loaded %d column rules and %d role bindings from %s", len(file.Rules), len(bindings), path)
+	return NewEnforcer(&access.ColumnPolicy{Rules: file.Rules}, bindings), nil
+}
+
+// PrincipalFor builds a principal for a user from the configured role bindings
+func (e *Enforcer) PrincipalFor(userID core.ID) access.Principal {
+	return access.Principal{UserID: userID, Roles: e.roleBindings[userID]}
+}
+
+// ContextFor attaches the principal for a user to the context
+func (e *Enforcer) ContextFor(ctx context.Context, userID core.ID) context.Context {
+	return access.WithPrincipal(ctx, e.PrincipalFor(userID))
+}
+
+// FilterVariables returns only the variables the context's principal may access.
+// A context without a principal is treated as an anonymous user with no roles.
+func (e *Enforcer) FilterVariables(ctx context.Context, varKeys []core.VariableKey) []core.VariableKey {
+	principal, _ := access.PrincipalFromContext(ctx)
+
+	allowed := make([]core.VariableKey, 0, len(varKeys))
+	for _, varKey := range varKeys {
+		if e.decide(principal, varKey).Allowed {
+			allowed = append(allowed, varKey)
+		}
+	}
+	return allowed
+}
+
+// FilterNames is FilterVariables for plain field names
+func (e *Enforcer) FilterNames(ctx context.Context, names []string) []string {
+	principal, _ := access.PrincipalFromContext(ctx)
+
+	allowed := make([]string, 0, len(names))
+	for _, name := range names {
+		if e.decide(principal, core.VariableKey(name)).Allowed {
+			allowed = append(allowed, name)
+		}
+	}
+	return allowed
+}
+
+// IsAllowed reports whether the context's principal may access a single variable
+func (e *Enforcer) IsAllowed(ctx context.Context, varKey core.VariableKey) bool {
+	principal, _ := access.PrincipalFromContext(ctx)
+	return e.decide(principal, varKey).Allowed
+}
+
+// RecentDecisions returns up to limit of the most recent decisions, newest last
+func (e *Enforcer) RecentDecisions(limit int) []access.Decision {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	start := 0
+	if limit > 0 && len(e.decisions) > limit {
+		start = len(e.decisions) - limit
+	}
+	out := make([]access.Decision, len(e.decisions)-start)
+	copy(out, e.decisions[start:])
+	return out
+}
+
+// decide evaluates the policy and records restricted decisions
+func (e *Enforcer) decide(principal access.Principal, varKey core.VariableKey) access.Decision {
+	decision := e.policy.Decide(principal, varKey)
+
+	// Only decisions made by a rule are interesting for audit purposes
+	if decision.Rule == "" {
+		return decision
+	}
+
+	if !decision.Allowed {
+		log.Printf("[ColumnPolicy] DENY user=%s variable=%s rule=%s reason=%s", principal.UserID, varKey, decision.Rule, decision.Reason)
+	}
+
+	e.mu.Lock()
+	e.decisions = append(e.decisions, decision)
+	if len(e.decisions) > maxRecordedDecisions {
+		e.decisions = e.decisions[len(e.decisions)-maxRecordedDecisions:]
+	}
+	e.mu.Unlock()
+
+	return decision
+}
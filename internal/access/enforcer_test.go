@@ -0,0 +1,87 @@
+package access
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gohypo/domain/core"
+)
+
+// writePolicy writes a policy file into a temporary directory and returns its path
+func writePolicy(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write policy: %v", err)
+	}
+	return path
+}
+
+// TestEnforcerFiltersByRoleBinding verifies a loaded policy filters variables by the roles
+// bound to the context's user, treats a context without a principal as anonymous and
+// audits only decisions a rule made
+func TestEnforcerFiltersByRoleBinding(t *testing.T) {
+	enforcer, err := LoadEnforcer(writePolicy(t, `{
+		"rules": [{"name": "compensation", "pattern": "*salary*", "allowed_roles": ["hr"]}],
+		"role_bindings": {"u-hr": ["hr"]}
+	}`))
+	if err != nil {
+		t.Fatalf("LoadEnforcer: %v", err)
+	}
+	varKeys := []core.VariableKey{"region", "base_salary", "tenure"}
+
+	hr := enforcer.FilterVariables(enforcer.ContextFor(context.Background(), "u-hr"), varKeys)
+	if !reflect.DeepEqual(hr, varKeys) {
+		t.Errorf("expected hr to see every variable, got %v", hr)
+	}
+	other := enforcer.FilterVariables(enforcer.ContextFor(context.Background(), "u-other"), varKeys)
+	if want := []core.VariableKey{"region", "tenure"}; !reflect.DeepEqual(other, want) {
+		t.Errorf("expected an unbound user to see %v, got %v", want, other)
+	}
+	if names := enforcer.FilterNames(context.Background(), []string{"base_salary", "region"}); !reflect.DeepEqual(names, []string{"region"}) {
+		t.Errorf("expected an anonymous context to lose restricted names, got %v", names)
+	}
+
+	decisions := enforcer.RecentDecisions(0)
+	if len(decisions) != 3 {
+		t.Fatalf("expected only the 3 decisions made by a rule to be audited, got %d", len(decisions))
+	}
+	if decisions[0].UserID != "u-hr" || !decisions[0].Allowed || decisions[1].Allowed || decisions[2].UserID != "" {
+		t.Errorf("unexpected audit log: %+v", decisions)
+	}
+	if latest := enforcer.RecentDecisions(1); len(latest) != 1 || latest[0] != decisions[2] {
+		t.Errorf("expected the newest decision last, got %+v", latest)
+	}
+}
+
+// TestEnforcerBoundsDecisionLog verifies the in-memory audit log keeps only the newest decisions
+func TestEnforcerBoundsDecisionLog(t *testing.T) {
+	enforcer, err := LoadEnforcer(writePolicy(t, `{"rules": [{"name": "ids", "pattern": "ssn"}]}`))
+	if err != nil {
+		t.Fatalf("LoadEnforcer: %v", err)
+	}
+	for i := 0; i < maxRecordedDecisions+10; i++ {
+		enforcer.IsAllowed(context.Background(), "ssn")
+	}
+	if n := len(enforcer.RecentDecisions(0)); n != maxRecordedDecisions {
+		t.Errorf("expected the log capped at %d decisions, got %d", maxRecordedDecisions, n)
+	}
+}
+
+// TestLoadEnforcerRejectsInvalidPolicy verifies unreadable, malformed and patternless
+// policies fail to load
+func TestLoadEnforcerRejectsInvalidPolicy(t *testing.T) {
+	cases := map[string]string{
+		"missing":    filepath.Join(t.TempDir(), "absent.json"),
+		"malformed":  writePolicy(t, `{"rules": [`),
+		"no pattern": writePolicy(t, `{"rules": [{"name": "empty"}]}`),
+	}
+	for name, path := range cases {
+		if _, err := LoadEnforcer(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/ports"
+)
+
+// EnforcingResolver wraps a MatrixResolverPort so restricted variables are never resolved
+type EnforcingResolver struct {
+	inner    ports.MatrixResolverPort
+	enforcer *Enforcer
+}
+
+// NewEnforcingResolver decorates a resolver with column policy enforcement
+func NewEnforcingResolver(inner ports.MatrixResolverPort, enforcer *Enforcer) *EnforcingResolver {
+	return &EnforcingResolver{inner: inner, enforcer: enforcer}
+}
+
+// ResolveMatrix drops restricted variables from the request before delegating
+func (r *EnforcingResolver) ResolveMatrix(ctx context.Context, req ports.MatrixResolutionRequest) (*dataset.MatrixBundle, error) {
+	allowed := r.enforcer.FilterVariables(ctx, req.VarKeys)
+	if dropped := len(req.VarKeys) - len(allowed); dropped > 0 {
+		log.Printf("[EnforcingResolver] Removed %d restricted variables from resolution request", dropped)
+	}
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("no accessible variables in resolution request")
+	}
+
+	req.VarKeys = allowed
+	bundle, err := r.inner.ResolveMatrix(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Some resolvers return extra columns; never let a restricted one through
+	return r.dropRestrictedColumns(ctx, bundle), nil
+}
+
+// dropRestrictedColumns removes any column the principal may not access from a resolved bundle
+func (r *EnforcingResolver) dropRestrictedColumns(ctx context.Context, bundle *dataset.MatrixBundle) *dataset.MatrixBundle {
+	keep := make([]int, 0, len(bundle.Matrix.VariableKeys))
+	for i, varKey := range bundle.Matrix.VariableKeys {
+		if r.enforcer.IsAllowed(ctx, varKey) {
+			keep = append(keep, i)
+		}
+	}
+	if len(keep) == len(bundle.Matrix.VariableKeys) {
+		return bundle
+	}
+
+	filtered := *bundle
+	filtered.Matrix.VariableKeys = make([]core.VariableKey, len(keep))
+	filtered.Matrix.Data = make([][]float64, len(bundle.Matrix.Data))
+	for i, idx := range keep {
+		filtered.Matrix.VariableKeys[i] = bundle.Matrix.VariableKeys[idx]
+	}
+	for row, values := range bundle.Matrix.Data {
+		filtered.Matrix.Data[row] = make([]float64, len(keep))
+		for i, idx := range keep {
+			if idx < len(values) {
+				filtered.Matrix.Data[row][i] = values[idx]
+			}
+		}
+	}
+	if len(bundle.ColumnMeta) == len(bundle.Matrix.VariableKeys) {
+		filtered.ColumnMeta = make([]dataset.ColumnMeta, len(keep))
+		for i, idx := range keep {
+			filtered.ColumnMeta[i] = bundle.ColumnMeta[idx]
+		}
+	}
+	if len(bundle.Audits) == len(bundle.Matrix.VariableKeys) {
+		filtered.Audits = make([]dataset.ResolutionAudit, len(keep))
+		for i, idx := range keep {
+			filtered.Audits[i] = bundle.Audits[idx]
+		}
+	}
+	filtered.Columnarize()
+	return &filtered
+}
+
+// Ensure EnforcingResolver implements MatrixResolverPort
+var _ ports.MatrixResolverPort = (*EnforcingResolver)(nil)
@@ -0,0 +1,75 @@
+package access
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"gohypo/domain/access"
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/ports"
+)
+
+// stubResolver returns every column it knows about, whatever was requested, and records the request
+type stubResolver struct {
+	requested []core.VariableKey
+}
+
+func (r *stubResolver) ResolveMatrix(_ context.Context, req ports.MatrixResolutionRequest) (*dataset.MatrixBundle, error) {
+	r.requested = req.VarKeys
+	keys := []core.VariableKey{"region", "base_salary", "tenure"}
+	bundle := &dataset.MatrixBundle{
+		Matrix: dataset.Matrix{
+			Data:         [][]float64{{1, 50000, 3}, {2, 60000, 4}},
+			EntityIDs:    []core.ID{"e1", "e2"},
+			VariableKeys: keys,
+		},
+	}
+	for _, key := range keys {
+		bundle.ColumnMeta = append(bundle.ColumnMeta, dataset.ColumnMeta{VariableKey: key})
+		bundle.Audits = append(bundle.Audits, dataset.ResolutionAudit{VariableKey: key})
+	}
+	return bundle, nil
+}
+
+// TestEnforcingResolverDropsRestrictedColumns verifies restricted variables are removed from
+// the request and from whatever the inner resolver returns, keeping the remaining columns aligned
+func TestEnforcingResolverDropsRestrictedColumns(t *testing.T) {
+	enforcer := NewEnforcer(&access.ColumnPolicy{Rules: []access.ColumnRule{
+		{Name: "compensation", Pattern: "*salary*", AllowedRoles: []string{"hr"}},
+	}}, map[core.ID][]string{"u-hr": {"hr"}})
+	inner := &stubResolver{}
+	resolver := NewEnforcingResolver(inner, enforcer)
+
+	ctx := enforcer.ContextFor(context.Background(), "u-analyst")
+	bundle, err := resolver.ResolveMatrix(ctx, ports.MatrixResolutionRequest{
+		VarKeys: []core.VariableKey{"region", "base_salary"},
+	})
+	if err != nil {
+		t.Fatalf("ResolveMatrix: %v", err)
+	}
+	if want := []core.VariableKey{"region"}; !reflect.DeepEqual(inner.requested, want) {
+		t.Errorf("expected the inner resolver asked for %v, got %v", want, inner.requested)
+	}
+	if want := []core.VariableKey{"region", "tenure"}; !reflect.DeepEqual(bundle.Matrix.VariableKeys, want) {
+		t.Fatalf("expected columns %v, got %v", want, bundle.Matrix.VariableKeys)
+	}
+	if want := [][]float64{{1, 3}, {2, 4}}; !reflect.DeepEqual(bundle.Matrix.Data, want) {
+		t.Errorf("expected data %v, got %v", want, bundle.Matrix.Data)
+	}
+	if bundle.ColumnMeta[1].VariableKey != "tenure" || bundle.Audits[1].VariableKey != "tenure" {
+		t.Errorf("expected column metadata and audits to follow the kept columns, got %+v %+v", bundle.ColumnMeta, bundle.Audits)
+	}
+
+	full, err := resolver.ResolveMatrix(enforcer.ContextFor(context.Background(), "u-hr"), ports.MatrixResolutionRequest{
+		VarKeys: []core.VariableKey{"base_salary"},
+	})
+	if err != nil || len(full.Matrix.VariableKeys) != 3 {
+		t.Errorf("expected hr to receive every column, got %v (err %v)", full, err)
+	}
+
+	if _, err := resolver.ResolveMatrix(ctx, ports.MatrixResolutionRequest{VarKeys: []core.VariableKey{"base_salary"}}); err == nil {
+		t.Error("expected a request with only restricted variables to fail")
+	}
+}
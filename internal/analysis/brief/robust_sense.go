@@ -0,0 +1,119 @@
+package brief
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"gohypo/domain/core"
+	domainstats "gohypo/domain/stats"
+	"gohypo/domain/stats/brief"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// RobustCorrelationSense reports a correlation that a few extreme points cannot swing: the
+// effect size is the weaker of the winsorized and biweight estimates, and the raw Pearson
+// coefficient is kept next to it so outlier-driven associations stand out
+type RobustCorrelationSense struct {
+	method domainstats.RobustMethod
+}
+
+func NewRobustCorrelationSense(method domainstats.RobustMethod) *RobustCorrelationSense {
+	if method == "" || method == domainstats.RobustOff {
+		method = domainstats.RobustBoth
+	}
+	return &RobustCorrelationSense{method: method}
+}
+
+func (s *RobustCorrelationSense) Name() string {
+	return "robust_correlation"
+}
+
+func (s *RobustCorrelationSense) Description() string {
+	return "Detects linear relationships resistant to outliers and flags effects carried by a few extreme points"
+}
+
+func (s *RobustCorrelationSense) RequiresGroups() bool {
+	return false
+}
+
+func (s *RobustCorrelationSense) Analyze(ctx context.Context, x, y []float64, varX, varY core.VariableKey) brief.SenseResult {
+	var xs, ys []float64
+	for i := range x {
+		if i < len(y) && !math.IsNaN(x[i]) && !math.IsNaN(y[i]) && !math.IsInf(x[i], 0) && !math.IsInf(y[i], 0) {
+			xs = append(xs, x[i])
+			ys = append(ys, y[i])
+		}
+	}
+	if len(x) != len(y) || len(xs) < 10 {
+		return brief.SenseResult{
+			SenseName:   s.Name(),
+			EffectSize:  0,
+			PValue:      1.0,
+			Confidence:  0,
+			Signal:      "weak",
+			Description: "Insufficient data for robust correlation analysis",
+		}
+	}
+
+	raw := stat.Correlation(xs, ys, nil)
+	comparison, err := domainstats.CompareRobust(xs, ys, raw, s.method)
+	if err != nil || math.IsNaN(raw) {
+		return brief.SenseResult{
+			SenseName:   s.Name(),
+			EffectSize:  0,
+			PValue:      1.0,
+			Confidence:  0,
+			Signal:      "weak",
+			Description: "Unable to compute robust correlation",
+		}
+	}
+
+	weakest := comparison.Weakest()
+	metadata := map[string]interface{}{
+		"raw_correlation": raw,
+		"robust_method":   string(weakest.Method),
+		"outlier_driven":  comparison.OutlierDriven,
+	}
+	for _, e := range comparison.Estimates {
+		metadata[string(e.Method)+"_correlation"] = e.Coefficient
+		metadata[string(e.Method)+"_p_value"] = e.PValue
+	}
+
+	return brief.SenseResult{
+		SenseName:   s.Name(),
+		EffectSize:  weakest.Coefficient,
+		PValue:      weakest.PValue,
+		Confidence:  1.0 - weakest.PValue,
+		Signal:      s.classifyRobustSignal(math.Abs(weakest.Coefficient), weakest.PValue),
+		Description: s.generateRobustDescription(raw, weakest, comparison.OutlierDriven),
+		Metadata:    metadata,
+	}
+}
+
+func (s *RobustCorrelationSense) classifyRobustSignal(absCorr, pValue float64) string {
+	if pValue > 0.05 {
+		return "weak"
+	}
+	if absCorr > 0.8 {
+		return "very_strong"
+	}
+	if absCorr > 0.6 {
+		return "strong"
+	}
+	if absCorr > 0.3 {
+		return "moderate"
+	}
+	return "weak"
+}
+
+func (s *RobustCorrelationSense) generateRobustDescription(raw float64, robust domainstats.RobustEstimate, outlierDriven bool) string {
+	if outlierDriven {
+		return fmt.Sprintf("Outlier-driven: Pearson r=%.3f falls to %.3f under the %s estimate", raw, robust.Coefficient, robust.Method)
+	}
+	if robust.PValue > 0.05 {
+		return fmt.Sprintf("No significant robust relationship (%s r=%.3f, p=%.3f)", robust.Method, robust.Coefficient, robust.PValue)
+	}
+	return fmt.Sprintf("Relationship holds without outliers (%s r=%.3f vs Pearson r=%.3f, p=%.3f)", robust.Method, robust.Coefficient, raw, robust.PValue)
+}
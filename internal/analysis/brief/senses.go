@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"gohypo/domain/core"
+	domainstats "gohypo/domain/stats"
 	"gohypo/domain/stats/brief"
 
 	"github.com/montanaflynn/stats"
@@ -47,6 +48,7 @@ func NewSenseEngine(computer *StatisticalBriefComputer) *SenseEngine {
 			NewWelchTTestSense(),
 			NewChiSquareSense(),
 			NewSpearmanSense(),
+			NewRobustCorrelationSense(domainstats.RobustBoth),
 			NewCrossCorrelationSense(),
 			NewTemporalSense("day"),
 		},
@@ -580,11 +582,14 @@ func (s *SpearmanSense) generateSpearmanDescription(corr, pValue float64) string
 	return fmt.Sprintf("%s monotonic relationship detected (ρ=%.3f, p=%.3f)", direction, corr, pValue)
 }
 
-// CrossCorrelationSense detects lagged relationships
-type CrossCorrelationSense struct{}
+// CrossCorrelationSense detects lagged relationships, grading them by the effect bands of
+// its analysis policy
+type CrossCorrelationSense struct {
+	policy domainstats.AnalysisPolicy
+}
 
 func NewCrossCorrelationSense() *CrossCorrelationSense {
-	return &CrossCorrelationSense{}
+	return &CrossCorrelationSense{policy: domainstats.DefaultAnalysisPolicy()}
 }
 
 func (s *CrossCorrelationSense) Name() string {
@@ -711,16 +716,16 @@ func (s *CrossCorrelationSense) computeCrossCorrelation(x, y []float64, lag int)
 }
 
 func (s *CrossCorrelationSense) classifyCrossCorrelationSignal(corr, pValue float64) string {
-	if pValue > 0.05 {
+	if pValue > s.policy.Alpha {
 		return "weak"
 	}
-	if math.Abs(corr) > 0.7 {
+	if math.Abs(corr) > s.policy.StrongEffect {
 		return "very_strong"
 	}
-	if math.Abs(corr) > 0.5 {
+	if math.Abs(corr) > s.policy.ModerateEffect {
 		return "strong"
 	}
-	if math.Abs(corr) > 0.3 {
+	if math.Abs(corr) > s.policy.MinEffect {
 		return "moderate"
 	}
 	return "weak"
@@ -732,7 +737,7 @@ func (s *CrossCorrelationSense) generateCrossCorrelationDescription(corr float64
 		direction = "lags behind"
 	}
 
-	if pValue > 0.05 {
+	if pValue > s.policy.Alpha {
 		return fmt.Sprintf("No significant cross-correlation (r=%.3f at lag %d, p=%.3f)", corr, lag, pValue)
 	}
 	return fmt.Sprintf("Cross-correlation shows %s relationship (r=%.3f at lag %d, p=%.3f)", direction, corr, lag, pValue)
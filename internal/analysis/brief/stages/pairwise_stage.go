@@ -3,11 +3,11 @@ package stages
 import (
 	"context"
 	"fmt"
-	"math"
 	"time"
 
 	"gohypo/domain/core"
 	"gohypo/domain/dataset"
+	"gohypo/domain/stage"
 	"gohypo/domain/stats"
 	brief "gohypo/internal/analysis/brief"
 )
@@ -29,7 +29,9 @@ func (p *PairwiseStage) Execute(bundle *dataset.MatrixBundle, stageConfig map[st
 	artifacts := make([]interface{}, 0)
 
 	variables := bundle.Matrix.VariableKeys
-	data := bundle.Matrix.Data
+	// Serve every pair's columns as slices of one column store instead of gathering
+	// each column from the rows once per pair
+	bundle.Columnarize()
 
 	// A5: Performance guardrails - explicit caps
 	const (
@@ -72,9 +74,9 @@ func (p *PairwiseStage) Execute(bundle *dataset.MatrixBundle, stageConfig map[st
 			var1 := variables[i]
 			var2 := variables[j]
 
-			// Extract columns
-			col1 := p.extractColumn(data, i)
-			col2 := p.extractColumn(data, j)
+			// Column views, shared read-only with the tests below
+			col1 := bundle.ColumnAt(i)
+			col2 := bundle.ColumnAt(j)
 
 			// Perform appropriate statistical test
 			relationship := p.analyzeRelationship(var1, var2, col1, col2, familyID)
@@ -85,8 +87,12 @@ func (p *PairwiseStage) Execute(bundle *dataset.MatrixBundle, stageConfig map[st
 		}
 	}
 
-	// AC2: Apply BH FDR correction to all relationship artifacts
-	p.applyFDRCorrection(artifacts)
+	// AC2: Apply FDR correction to all relationship artifacts
+	fdrMethod, err := fdrMethodFromConfig(stageConfig)
+	if err != nil {
+		return nil, err
+	}
+	p.applyFDRCorrection(artifacts, fdrMethod)
 
 	// Create FDR family artifact with correction method
 	fdrFamily := stats.NewFDRFamilyArtifact(
@@ -99,36 +105,27 @@ func (p *PairwiseStage) Execute(bundle *dataset.MatrixBundle, stageConfig map[st
 			StagePlanHash: core.Hash("test-stage-plan"),
 		},
 		len(artifacts),
-		"BH", // Benjamini-Hochberg FDR correction applied
+		string(fdrMethod),
 	)
 	artifacts = append(artifacts, fdrFamily)
 
 	return artifacts, nil
 }
 
-// extractColumn extracts a column from the data matrix
-func (p *PairwiseStage) extractColumn(data [][]float64, colIndex int) []float64 {
-	if len(data) == 0 {
-		return nil
-	}
-	if colIndex >= len(data[0]) {
-		return nil
+// fdrMethodFromConfig reads the correction method from the stage config.
+// An explicit "fdr_method" wins over "rigor"; the default is Benjamini-Hochberg.
+func fdrMethodFromConfig(stageConfig map[string]interface{}) (stats.FDRMethod, error) {
+	if method, ok := stageConfig["fdr_method"].(string); ok && method != "" {
+		return stats.ParseFDRMethod(method)
 	}
-
-	column := make([]float64, len(data))
-	for i, row := range data {
-		if colIndex < len(row) {
-			column[i] = row[colIndex]
-		} else {
-			// Pad with NaN if row is too short
-			column[i] = math.NaN()
-		}
+	if rigor, ok := stageConfig["rigor"].(string); ok && rigor != "" {
+		return stage.RigorProfile(rigor).FDRMethod(), nil
 	}
-	return column
+	return stats.FDRBenjaminiHochberg, nil
 }
 
-// applyFDRCorrection applies Benjamini-Hochberg FDR correction to relationship artifacts
-func (p *PairwiseStage) applyFDRCorrection(artifacts []interface{}) {
+// applyFDRCorrection applies the selected multiple-comparison correction to relationship artifacts
+func (p *PairwiseStage) applyFDRCorrection(artifacts []interface{}, method stats.FDRMethod) {
 	// Collect relationship artifacts for FDR correction
 	var relationshipArtifacts []*RelationshipResult
 	for _, artifact := range artifacts {
@@ -141,31 +138,16 @@ func (p *PairwiseStage) applyFDRCorrection(artifacts []interface{}) {
 		return
 	}
 
-	m := len(relationshipArtifacts) // total number of tests
-
-	// Sort by p-value ascending
-	for i := 0; i < len(relationshipArtifacts)-1; i++ {
-		for j := i + 1; j < len(relationshipArtifacts); j++ {
-			if relationshipArtifacts[i].Metrics.PValue > relationshipArtifacts[j].Metrics.PValue {
-				relationshipArtifacts[i], relationshipArtifacts[j] = relationshipArtifacts[j], relationshipArtifacts[i]
-			}
-		}
+	pValues := make([]float64, len(relationshipArtifacts))
+	for i, rel := range relationshipArtifacts {
+		pValues[i] = rel.Metrics.PValue
 	}
 
-	// Apply BH correction: q_i = p_i * (m / i)
-	// where i is the rank (1-based)
+	qValues := stats.AdjustPValues(method, pValues)
 	for i, rel := range relationshipArtifacts {
-		rank := i + 1 // 1-based rank
-		qValue := rel.Metrics.PValue * float64(m) / float64(rank)
-
-		// Clamp q-value to [0, 1]
-		if qValue > 1.0 {
-			qValue = 1.0
-		}
-
-		rel.Metrics.QValue = qValue
-		rel.Metrics.TotalComparisons = m
-		rel.Metrics.FDRMethod = "BH"
+		rel.Metrics.QValue = qValues[i]
+		rel.Metrics.TotalComparisons = len(relationshipArtifacts)
+		rel.Metrics.FDRMethod = string(method)
 	}
 }
 
@@ -1,6 +1,7 @@
 package analysis
 
 import (
+	"sort"
 	"strings"
 )
 
@@ -94,9 +95,15 @@ func (m *BusinessNameMapper) MapColumnToBusinessName(columnName, outcomeCol stri
 		return "shipping costs"
 	}
 
-	// Generic business term mappings
-	for businessTerm, technicalTerms := range m.mappings {
-		for _, term := range technicalTerms {
+	// Generic business term mappings, in a fixed order so a column matching several terms
+	// (discount holds both "discount" and "count") always gets the same name
+	businessTerms := make([]string, 0, len(m.mappings))
+	for businessTerm := range m.mappings {
+		businessTerms = append(businessTerms, businessTerm)
+	}
+	sort.Strings(businessTerms)
+	for _, businessTerm := range businessTerms {
+		for _, term := range m.mappings[businessTerm] {
 			if strings.Contains(colLower, term) {
 				return m.businessTermToName(businessTerm)
 			}
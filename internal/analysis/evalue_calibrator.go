@@ -4,11 +4,17 @@ import (
 	"context"
 	"math"
 	"sort"
+	"sync"
 	"time"
 
 	"gohypo/domain/stats"
+	"gohypo/models"
 )
 
+// minOutcomeDecisions is how many conclusive decision outcomes a test needs before its hit
+// rate adjusts the E-values it produces
+const minOutcomeDecisions = 5
+
 // EValueCalibrator provides calibrated E-value conversion and evidence aggregation
 type EValueCalibrator struct {
 	// Configuration parameters
@@ -22,6 +28,10 @@ type EValueCalibrator struct {
 
 	// Dynamic thresholds
 	riskMultipliers map[string]float64 // Domain-specific risk adjustments
+
+	// Decision outcomes per test, fed back from the decision log
+	outcomeMu      sync.RWMutex
+	outcomeTallies map[string]models.OutcomeTally
 }
 
 // CalibrationDatum stores historical performance data
@@ -76,6 +86,9 @@ func (ec *EValueCalibrator) ConvertQValueToEValue(qValue, pValue float64, testTy
 	// Apply historical calibration
 	calibratedE := ec.applyHistoricalCalibration(eValue, testType, dataDomain)
 
+	// Apply the hit rate of decisions informed by this test
+	calibratedE *= ec.outcomeFactor(testType)
+
 	// Calculate confidence bounds
 	lower, upper := ec.calculateConfidenceBounds(calibratedE, testType, sampleSize)
 
@@ -487,6 +500,27 @@ func (ec *EValueCalibrator) determineVerdict(normalizedE float64, testCount int,
 	}
 }
 
+// SetOutcomeTallies replaces the decision outcomes credited to each test
+func (ec *EValueCalibrator) SetOutcomeTallies(tallies map[string]models.OutcomeTally) {
+	ec.outcomeMu.Lock()
+	defer ec.outcomeMu.Unlock()
+	ec.outcomeTallies = tallies
+}
+
+// outcomeFactor scales a test's E-values by how often decisions it supported were confirmed:
+// twice the smoothed hit rate, so 50% is neutral, clamped to [0.5, 1.5] so a short run of
+// outcomes cannot swamp the statistics
+func (ec *EValueCalibrator) outcomeFactor(testType string) float64 {
+	ec.outcomeMu.RLock()
+	tally, ok := ec.outcomeTallies[testType]
+	ec.outcomeMu.RUnlock()
+	if !ok || tally.Confirmed+tally.Refuted < minOutcomeDecisions {
+		return 1.0
+	}
+	hitRate := float64(tally.Confirmed+1) / float64(tally.Confirmed+tally.Refuted+2)
+	return math.Max(0.5, math.Min(1.5, 2*hitRate))
+}
+
 // Data management methods
 func (ec *EValueCalibrator) getHistoricalDataForTestTypeAndDomain(testType, dataDomain string) []CalibrationDatum {
 	var filtered []CalibrationDatum
@@ -210,7 +210,7 @@ type HysteresisResult struct {
 type EvidenceBrief struct {
 	// Core metadata
 	Version            string    `json:"version"`
-	Timestamp          time.Time `json:"timestamp"`
+	Timestamp          time.Time `json:"-"` // left out of prompts so the same evidence renders the same prompt
 	DatasetName        string    `json:"dataset_name"`
 	RowCount           int       `json:"row_count"`
 	ColumnCount        int       `json:"column_count"`
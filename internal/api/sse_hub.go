@@ -165,6 +165,12 @@ func (h *SSEHub) HandleSSE(c *gin.Context) {
 		log.Printf("[SSE] Reconnection attempt for session: %s", sessionID)
 	}
 
+	h.StreamTopic(c, sessionID)
+}
+
+// StreamTopic streams the events broadcast under a topic (a session ID, or any other key the
+// caller has authorized) until the client disconnects; an empty topic receives every event
+func (h *SSEHub) StreamTopic(c *gin.Context, sessionID string) {
 	// Set SSE headers
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
@@ -267,6 +273,18 @@ func (h *SSEHub) GetClientCount(sessionID string) int {
 	return 0
 }
 
+// ClientCount returns the number of connected clients across all sessions
+func (h *SSEHub) ClientCount() int {
+	h.clientsMu.RLock()
+	defer h.clientsMu.RUnlock()
+
+	count := 0
+	for _, clients := range h.clients {
+		count += len(clients)
+	}
+	return count
+}
+
 // UploadProgressEvent represents an upload progress event
 type UploadProgressEvent struct {
 	SessionID string                 `json:"session_id"`
@@ -0,0 +1,189 @@
+package buildinfo
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	apperrors "gohypo/internal/errors"
+)
+
+// Set at build time, e.g. go build -ldflags "-X gohypo/internal/buildinfo.Version=v1.2.0"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+// MethodVersions are the semantic versions of every method whose output is persisted and
+// later reused. Bump the major version when a change makes earlier results incomparable
+// (different statistic, different pair layout); bump the minor version otherwise.
+var MethodVersions = map[string]string{
+	"pairwise_correlation": "2.0.0", // Pearson r, or Kendall's tau-b / trend test for ordinal pairs, and its p-value per pair
+	"fdr_correction":       "1.0.0", // multiple-comparison adjustment of sweep p-values
+	"column_fingerprint":   "1.0.0", // per-column hashing used by incremental sweeps
+	"sweep_checkpoint":     "1.0.0", // checkpoint batch format and pair indexing
+	"group_comparison":     "1.1.0", // ANOVA and Kruskal-Wallis of numeric variables across categorical levels
+}
+
+// RefereeVersions are the versions of the referee gates, keyed by lowercased gate name. Bump a
+// version whenever a change could alter verdicts reached before: hypotheses validated with the
+// earlier version become stale and are offered for re-validation.
+var RefereeVersions = map[string]string{
+	"algorithmic_complexity":         "1.0.0",
+	"alpha_decay_test":               "1.0.0",
+	"chow_stability_test":            "1.0.0",
+	"conditional_mutual_information": "1.0.0",
+	"convergent_cross_mapping":       "1.0.0",
+	"cusum_drift_detection":          "1.0.0",
+	"functional_form_test":           "1.0.0",
+	"isotonic_mechanism_check":       "1.0.0",
+	"leave_one_out_cv":               "1.0.0",
+	"lempel_ziv_complexity":          "1.0.0",
+	"monotonicity_stress_test":       "1.0.0",
+	"partial_correlation":            "1.0.0",
+	"permutation_shredder":           "1.0.0",
+	"persistent_homology":            "1.0.0",
+	"spectral_analysis":              "1.0.0",
+	"synthetic_intervention":         "1.0.0",
+	"transfer_entropy":               "1.0.0",
+	"wavelet_coherence":              "1.0.0",
+}
+
+// SenseVersions are the versions of the statistical senses whose evidence referees audit,
+// keyed by sense name with temporal senses sharing "temporal". Bump them like RefereeVersions.
+var SenseVersions = map[string]string{
+	"mutual_information": "1.0.0",
+	"welch_ttest":        "1.0.0",
+	"chi_square":         "1.0.0",
+	"spearman":           "1.0.0",
+	"robust_correlation": "1.0.0",
+	"cross_correlation":  "1.0.0",
+	"temporal":           "1.0.0",
+}
+
+// ErrIncompatible is returned when persisted results came from incompatible method versions
+var ErrIncompatible = apperrors.DeterminismViolation("produced by incompatible method versions")
+
+// Info describes the running binary
+type Info struct {
+	Version        string            `json:"version"`
+	Commit         string            `json:"commit,omitempty"`
+	Modified       bool              `json:"modified,omitempty"`
+	BuildDate      string            `json:"build_date,omitempty"`
+	GoVersion      string            `json:"go_version"`
+	MethodVersions map[string]string `json:"method_versions"`
+	EngineVersions map[string]string `json:"engine_versions"`
+}
+
+// Get returns the build info; the commit falls back to the VCS stamp Go embeds
+func Get() Info {
+	info := Info{
+		Version:        Version,
+		Commit:         Commit,
+		BuildDate:      BuildDate,
+		GoVersion:      runtime.Version(),
+		MethodVersions: Methods(),
+		EngineVersions: Engines(nil),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	return info
+}
+
+// Methods returns a copy of MethodVersions for embedding in manifests
+func Methods() map[string]string {
+	methods := make(map[string]string, len(MethodVersions))
+	for name, version := range MethodVersions {
+		methods[name] = version
+	}
+	return methods
+}
+
+// CheckCompatible compares method versions recorded with persisted results against this
+// build. Methods differ incompatibly when their major versions differ. Methods this build
+// does not know, and results recorded before versioning existed, are accepted.
+func CheckCompatible(recorded map[string]string) error {
+	var conflicts []string
+	for name, version := range recorded {
+		current, ok := MethodVersions[name]
+		if !ok {
+			continue
+		}
+		if majorVersion(version) != majorVersion(current) {
+			conflicts = append(conflicts, fmt.Sprintf("%s %s (this build has %s)", name, version, current))
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	sort.Strings(conflicts)
+	return fmt.Errorf("%w: %s", ErrIncompatible, strings.Join(conflicts, ", "))
+}
+
+func majorVersion(version string) string {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.Index(version, "."); i >= 0 {
+		return version[:i]
+	}
+	return version
+}
+
+// Engines returns the versions a validation ran with, for recording with its verdict: the
+// given referee gates, or every referee when gates is nil, and every sense. Keys are
+// "referee/<gate>" and "sense/<name>".
+func Engines(gates []string) map[string]string {
+	engines := make(map[string]string, len(gates)+len(SenseVersions))
+	if gates == nil {
+		for gate, version := range RefereeVersions {
+			engines["referee/"+gate] = version
+		}
+	}
+	for _, gate := range gates {
+		gate = strings.ToLower(gate)
+		if version, ok := RefereeVersions[gate]; ok {
+			engines["referee/"+gate] = version
+		}
+	}
+	for sense, version := range SenseVersions {
+		engines["sense/"+sense] = version
+	}
+	return engines
+}
+
+// ChangedEngines lists, sorted, the referees and senses recorded with a verdict whose version
+// differs in this build, as "referee/<gate> 1.0.0 → 1.1.0". Engines this build no longer has
+// are not listed.
+func ChangedEngines(recorded map[string]string) []string {
+	var changed []string
+	for name, version := range recorded {
+		var current string
+		var ok bool
+		if gate, found := strings.CutPrefix(name, "referee/"); found {
+			current, ok = RefereeVersions[gate]
+		} else if sense, found := strings.CutPrefix(name, "sense/"); found {
+			current, ok = SenseVersions[sense]
+		}
+		if ok && current != version {
+			changed = append(changed, fmt.Sprintf("%s %s → %s", name, version, current))
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,47 @@
+package buildinfo
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestCheckCompatible verifies only major version differences are refused
+func TestCheckCompatible(t *testing.T) {
+	current := MethodVersions["pairwise_correlation"]
+
+	if err := CheckCompatible(nil); err != nil {
+		t.Fatalf("unversioned results must be accepted: %v", err)
+	}
+	if err := CheckCompatible(map[string]string{"pairwise_correlation": majorVersion(current) + ".9.0", "retired_method": "7.0.0"}); err != nil {
+		t.Fatalf("minor bumps and unknown methods must be accepted: %v", err)
+	}
+
+	err := CheckCompatible(map[string]string{"pairwise_correlation": "99.0.0"})
+	if !errors.Is(err, ErrIncompatible) {
+		t.Fatalf("expected ErrIncompatible for a major bump, got %v", err)
+	}
+}
+
+// TestChangedEngines verifies any version change of a recorded referee or sense is reported
+// and engines this build no longer has are not
+func TestChangedEngines(t *testing.T) {
+	recorded := Engines([]string{"Permutation_Shredder", "Unknown_Gate"})
+	if _, ok := recorded["referee/unknown_gate"]; ok {
+		t.Fatal("unknown gates must not be recorded")
+	}
+	if changed := ChangedEngines(recorded); len(changed) != 0 {
+		t.Fatalf("expected nothing changed in this build, got %v", changed)
+	}
+
+	recorded["referee/permutation_shredder"] = "0.9.0"
+	recorded["sense/spearman"] = "0.1.0"
+	recorded["sense/retired_sense"] = "1.0.0"
+	changed := ChangedEngines(recorded)
+	want := []string{
+		"referee/permutation_shredder 0.9.0 → " + RefereeVersions["permutation_shredder"],
+		"sense/spearman 0.1.0 → " + SenseVersions["spearman"],
+	}
+	if len(changed) != len(want) || changed[0] != want[0] || changed[1] != want[1] {
+		t.Fatalf("expected %v, got %v", want, changed)
+	}
+}
@@ -1,11 +1,15 @@
 package config
 
 import (
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"gohypo/internal/errors"
+	"gohypo/internal/logging"
 )
 
 // Config represents the complete application configuration
@@ -16,6 +20,13 @@ type Config struct {
 	Paths     PathConfig     `validate:"required"`
 	Data      DataConfig     `validate:"required"`
 	Profiling ProfilingConfig
+	Access    AccessConfig
+	Tenancy   TenancyConfig
+	Branding  BrandingConfig
+	Autoscale AutoscaleConfig
+	Retention RetentionConfig
+	Logging   LoggingConfig
+	Jobs      JobsConfig
 }
 
 // DatabaseConfig holds database connection settings
@@ -27,22 +38,65 @@ type DatabaseConfig struct {
 	Host     string
 	Port     int
 	SSLMode  string
+	// AutoMigrate applies pending schema migrations at startup; when off the server
+	// refuses to start until they are applied with 'gohypo-cli migrate up'
+	AutoMigrate bool
+	// ResetOnStart drops and recreates the schema at every startup. It destroys all
+	// data, so it is only allowed with APP_ENV=development.
+	ResetOnStart bool
 }
 
 // AIConfig holds AI/LLM related settings
 type AIConfig struct {
-	OpenAIKey     string `validate:"required"`
-	OpenAIModel   string `validate:"required"`
+	GeneratorMode string // openai (default), anthropic or ollama
+	OpenAIKey     string
+	OpenAIModel   string
 	SystemContext string
 	MaxTokens     int
 	Temperature   float64
 	PromptsDir    string `validate:"required"`
+
+	AnthropicKey     string
+	AnthropicModel   string
+	AnthropicBaseURL string
+
+	OllamaBaseURL string
+	OllamaModel   string
+	OllamaAPI     string // ollama (native) or openai (llama.cpp compatible)
+
+	// Column embeddings for relationship discovery: local, provider or off
+	ColumnEmbeddings string
+	EmbeddingModel   string
+
+	// Response cache: memory, disk, postgres or off
+	CacheBackend string
+	CacheDir     string
+
+	// Record-and-replay fixtures for every LLM call: off, record or replay
+	FixtureMode string
+	FixtureDir  string
+}
+
+// Configured reports whether the selected generator provider has its required settings
+func (c *AIConfig) Configured() bool {
+	switch c.GeneratorMode {
+	case "anthropic":
+		return c.AnthropicKey != ""
+	case "ollama":
+		return c.OllamaBaseURL != ""
+	default:
+		return c.OpenAIKey != ""
+	}
 }
 
 // ServerConfig holds web server settings
 type ServerConfig struct {
 	Port    string `validate:"required"`
 	GinMode string
+	// Environment is APP_ENV: "production" (default) or "development"
+	Environment string
+	// PublicURL is where users reach the server, for links sent out of the app
+	PublicURL string
 }
 
 // PathConfig holds file system paths
@@ -54,6 +108,7 @@ type PathConfig struct {
 type DataConfig struct {
 	ExcelFile    string
 	AutoLoadCSVs bool
+	SeedDemo     bool // seed the labeled demo workspace and run research over it once
 }
 
 // ProfilingConfig holds performance profiling settings
@@ -62,6 +117,80 @@ type ProfilingConfig struct {
 	Enabled bool
 }
 
+// AccessConfig holds data access policy settings
+type AccessConfig struct {
+	ColumnPolicyFile string   // JSON column policy; empty disables column-level enforcement
+	AdminAPIKeys     []string // keys accepted on /api/admin; empty leaves the admin API open
+	SecretEnvNames   []string // environment variables source credentials may reference as env:NAME
+}
+
+// TenancyConfig holds workspace isolation settings
+type TenancyConfig struct {
+	Mode string // "shared" (default) or "schema" for a Postgres schema per workspace
+}
+
+// BrandingConfig holds the deployment's look of the UI and reports; empty fields keep the defaults
+type BrandingConfig struct {
+	Name         string
+	LogoURL      string // absolute http(s) URL or a path on this server
+	PrimaryColor string // hex, e.g. #0f766e
+	AccentColor  string
+	FooterText   string
+}
+
+// AutoscaleConfig holds validation worker pool sizing settings
+type AutoscaleConfig struct {
+	MinWorkers      int
+	MaxWorkers      int
+	TargetPerWorker int // waiting validations one extra worker is added for
+	ScaleDownDelay  time.Duration
+	Interval        time.Duration
+	HookURL         string // receives every scale decision as a JSON POST; empty disables
+}
+
+// RetentionConfig holds artifact archiving settings
+type RetentionConfig struct {
+	ArchiveAfter time.Duration // inactivity after which a session's artifacts are archived; 0 disables
+	Interval     time.Duration // how often inactive sessions are looked for
+	ArchiveDir   string        // blob store root the archives are written under
+
+	LedgerPolicy          string        // per-kind ledger retention rules (see retention.ParseLedgerPolicy); empty disables compaction
+	CompactionInterval    time.Duration // how often expired ledger artifacts are compacted
+	KeepValidatedEvidence bool          // exempt validated hypotheses' evidence chains from the ledger policy
+}
+
+// LoggingConfig holds the process logger's settings
+type LoggingConfig struct {
+	Level  string // error, warn, info, debug or trace
+	Format string // text or json
+}
+
+// JobsConfig holds the research job queue's worker settings
+type JobsConfig struct {
+	Workers int           // research sessions this instance runs at once; 0 makes it enqueue-only
+	Lease   time.Duration // how long a job stays claimed without a heartbeat before it is recovered
+}
+
+// Enabled reports whether artifacts are archived
+func (r RetentionConfig) Enabled() bool {
+	return r.ArchiveAfter > 0
+}
+
+// CompactionEnabled reports whether the ledger is compacted under a retention policy
+func (r RetentionConfig) CompactionEnabled() bool {
+	return r.LedgerPolicy != ""
+}
+
+// SQLite reports whether DATABASE_URL names a single-file SQLite database instead of PostgreSQL
+func (d DatabaseConfig) SQLite() bool {
+	return strings.HasPrefix(d.URL, "sqlite:")
+}
+
+// SchemaPerWorkspace reports whether each workspace is isolated in its own schema
+func (t TenancyConfig) SchemaPerWorkspace() bool {
+	return t.Mode == "schema"
+}
+
 // Load reads configuration from environment variables and validates it
 func Load() (*Config, error) {
 	config := &Config{}
@@ -96,6 +225,34 @@ func Load() (*Config, error) {
 	profilingConfig := loadProfilingConfig()
 	config.Profiling = *profilingConfig
 
+	// Load access policy configuration
+	accessConfig := loadAccessConfig()
+	config.Access = *accessConfig
+
+	// Load tenancy configuration
+	tenancyConfig := loadTenancyConfig()
+	config.Tenancy = *tenancyConfig
+
+	// Load branding configuration
+	brandingConfig := loadBrandingConfig()
+	config.Branding = *brandingConfig
+
+	// Load worker pool autoscaling configuration
+	autoscaleConfig := loadAutoscaleConfig()
+	config.Autoscale = *autoscaleConfig
+
+	// Load artifact retention configuration
+	retentionConfig := loadRetentionConfig()
+	config.Retention = *retentionConfig
+
+	// Load logging configuration
+	loggingConfig := loadLoggingConfig()
+	config.Logging = *loggingConfig
+
+	// Load research job queue configuration
+	jobsConfig := loadJobsConfig()
+	config.Jobs = *jobsConfig
+
 	// Validate required fields
 	if err := validateConfig(config); err != nil {
 		return nil, errors.Wrap(err, "configuration validation failed")
@@ -118,13 +275,28 @@ func loadDatabaseConfig() (*DatabaseConfig, error) {
 		Host:     getEnvOrDefault("DB_HOST", ""),
 		Port:     getEnvIntOrDefault("DB_PORT", 5432),
 		SSLMode:  getEnvOrDefault("SSL_MODE", "disable"),
+
+		AutoMigrate:  getEnvBoolOrDefault("DB_AUTO_MIGRATE", true),
+		ResetOnStart: getEnvBoolOrDefault("DB_RESET_ON_START", false),
 	}, nil
 }
 
 func loadAIConfig() (*AIConfig, error) {
+	mode := getEnvOrDefault("GENERATOR_MODE", "openai")
 	openaiKey := os.Getenv("OPENAI_API_KEY")
-	if openaiKey == "" {
-		return nil, errors.ConfigInvalid("OPENAI_API_KEY is required")
+	anthropicKey := os.Getenv("ANTHROPIC_API_KEY")
+	switch mode {
+	case "openai":
+		if openaiKey == "" {
+			return nil, errors.ConfigInvalid("OPENAI_API_KEY is required")
+		}
+	case "anthropic":
+		if anthropicKey == "" {
+			return nil, errors.ConfigInvalid("ANTHROPIC_API_KEY is required when GENERATOR_MODE=anthropic")
+		}
+	case "ollama":
+	default:
+		return nil, errors.ConfigInvalid("GENERATOR_MODE must be \"openai\", \"anthropic\" or \"ollama\"")
 	}
 
 	promptsDir := os.Getenv("PROMPTS_DIR")
@@ -138,19 +310,40 @@ func loadAIConfig() (*AIConfig, error) {
 	}
 
 	return &AIConfig{
+		GeneratorMode: mode,
 		OpenAIKey:     openaiKey,
 		OpenAIModel:   model,
 		SystemContext: "You are a statistical research assistant",
 		MaxTokens:     getEnvIntOrDefault("MAX_TOKENS", 4000), // Reasonable default for gpt-5.2 (8192 context limit)
 		Temperature:   getEnvFloatOrDefault("TEMPERATURE", 1.0),
 		PromptsDir:    promptsDir,
+
+		AnthropicKey:     anthropicKey,
+		AnthropicModel:   os.Getenv("ANTHROPIC_MODEL"),
+		AnthropicBaseURL: os.Getenv("ANTHROPIC_BASE_URL"),
+
+		OllamaBaseURL: getEnvOrDefault("OLLAMA_BASE_URL", "http://localhost:11434"),
+		OllamaModel:   os.Getenv("OLLAMA_MODEL"),
+		OllamaAPI:     getEnvOrDefault("OLLAMA_API", "ollama"),
+
+		ColumnEmbeddings: getEnvOrDefault("COLUMN_EMBEDDINGS", "local"),
+		EmbeddingModel:   os.Getenv("EMBEDDING_MODEL"),
+
+		CacheBackend: getEnvOrDefault("LLM_CACHE", "memory"),
+		CacheDir:     getEnvOrDefault("LLM_CACHE_DIR", "./.cache/llm"),
+
+		FixtureMode: getEnvOrDefault("LLM_FIXTURES", "off"),
+		FixtureDir:  getEnvOrDefault("LLM_FIXTURES_DIR", "./testdata/llm_fixtures"),
 	}, nil
 }
 
 func loadServerConfig() *ServerConfig {
+	port := getEnvOrDefault("PORT", "8080")
 	return &ServerConfig{
-		Port:    getEnvOrDefault("PORT", "8080"),
-		GinMode: getEnvOrDefault("GIN_MODE", "debug"),
+		Port:        port,
+		GinMode:     getEnvOrDefault("GIN_MODE", "debug"),
+		Environment: getEnvOrDefault("APP_ENV", "production"),
+		PublicURL:   getEnvOrDefault("PUBLIC_URL", "http://localhost:"+port),
 	}
 }
 
@@ -164,6 +357,7 @@ func loadDataConfig() *DataConfig {
 	return &DataConfig{
 		ExcelFile:    getEnvOrDefault("EXCEL_FILE", ""),
 		AutoLoadCSVs: getEnvBoolOrDefault("AUTO_LOAD_CSVS", true),
+		SeedDemo:     getEnvBoolOrDefault("DEMO_WORKSPACE_ENABLED", false),
 	}
 }
 
@@ -174,17 +368,174 @@ func loadProfilingConfig() *ProfilingConfig {
 	}
 }
 
+func loadAccessConfig() *AccessConfig {
+	return &AccessConfig{
+		ColumnPolicyFile: getEnvOrDefault("COLUMN_POLICY_FILE", ""),
+		AdminAPIKeys:     splitList(getEnvOrDefault("ADMIN_API_KEYS", "")),
+		SecretEnvNames:   splitList(getEnvOrDefault("SECRET_ENV_NAMES", "")),
+	}
+}
+
+func loadTenancyConfig() *TenancyConfig {
+	return &TenancyConfig{
+		Mode: getEnvOrDefault("TENANCY_MODE", "shared"),
+	}
+}
+
+func loadBrandingConfig() *BrandingConfig {
+	return &BrandingConfig{
+		Name:         getEnvOrDefault("BRAND_NAME", ""),
+		LogoURL:      getEnvOrDefault("BRAND_LOGO_URL", ""),
+		PrimaryColor: getEnvOrDefault("BRAND_PRIMARY_COLOR", ""),
+		AccentColor:  getEnvOrDefault("BRAND_ACCENT_COLOR", ""),
+		FooterText:   getEnvOrDefault("BRAND_FOOTER_TEXT", ""),
+	}
+}
+
+func loadAutoscaleConfig() *AutoscaleConfig {
+	return &AutoscaleConfig{
+		MinWorkers:      getEnvIntOrDefault("VALIDATION_POOL_MIN", 2),
+		MaxWorkers:      getEnvIntOrDefault("VALIDATION_POOL_MAX", 8),
+		TargetPerWorker: getEnvIntOrDefault("VALIDATION_POOL_TARGET_PER_WORKER", 4),
+		ScaleDownDelay:  getEnvDurationOrDefault("VALIDATION_POOL_SCALE_DOWN_DELAY", 10*time.Minute),
+		Interval:        getEnvDurationOrDefault("VALIDATION_POOL_INTERVAL", 15*time.Second),
+		HookURL:         getEnvOrDefault("VALIDATION_POOL_HOOK_URL", ""),
+	}
+}
+
+func loadRetentionConfig() *RetentionConfig {
+	return &RetentionConfig{
+		ArchiveAfter: time.Duration(getEnvIntOrDefault("ARTIFACT_ARCHIVE_AFTER_DAYS", 0)) * 24 * time.Hour,
+		Interval:     getEnvDurationOrDefault("ARTIFACT_ARCHIVE_INTERVAL", 6*time.Hour),
+		ArchiveDir:   getEnvOrDefault("ARTIFACT_ARCHIVE_DIR", "./data/archives"),
+
+		LedgerPolicy:          getEnvOrDefault("LEDGER_RETENTION_POLICY", ""),
+		CompactionInterval:    getEnvDurationOrDefault("LEDGER_COMPACTION_INTERVAL", time.Hour),
+		KeepValidatedEvidence: getEnvBoolOrDefault("LEDGER_RETENTION_KEEP_VALIDATED", true),
+	}
+}
+
+func loadLoggingConfig() *LoggingConfig {
+	return &LoggingConfig{
+		Level:  getEnvOrDefault("LOG_LEVEL", "info"),
+		Format: getEnvOrDefault("LOG_FORMAT", "text"),
+	}
+}
+
+func loadJobsConfig() *JobsConfig {
+	return &JobsConfig{
+		Workers: getEnvIntOrDefault("RESEARCH_JOB_WORKERS", 2),
+		Lease:   getEnvDurationOrDefault("RESEARCH_JOB_LEASE", 2*time.Minute),
+	}
+}
+
+// validateAutoscale rejects pool bounds that cannot be met and hooks that are not http(s)
+func validateAutoscale(a AutoscaleConfig) error {
+	if a.MinWorkers < 1 || a.MaxWorkers < a.MinWorkers {
+		return errors.ConfigInvalid("VALIDATION_POOL_MIN must be at least 1 and at most VALIDATION_POOL_MAX")
+	}
+	if a.TargetPerWorker < 1 {
+		return errors.ConfigInvalid("VALIDATION_POOL_TARGET_PER_WORKER must be at least 1")
+	}
+	if a.Interval <= 0 {
+		return errors.ConfigInvalid("VALIDATION_POOL_INTERVAL must be positive")
+	}
+	if a.HookURL != "" {
+		parsed, err := url.Parse(a.HookURL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return errors.ConfigInvalid("VALIDATION_POOL_HOOK_URL must be an http(s) URL")
+		}
+	}
+	return nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validateBranding keeps branding values to what can be placed in pages as-is
+func validateBranding(b BrandingConfig) error {
+	for key, color := range map[string]string{"BRAND_PRIMARY_COLOR": b.PrimaryColor, "BRAND_ACCENT_COLOR": b.AccentColor} {
+		if color != "" && !hexColor.MatchString(color) {
+			return errors.ConfigInvalid(key + " must be a hex color such as #0f766e")
+		}
+	}
+	if b.LogoURL != "" {
+		parsed, err := url.Parse(b.LogoURL)
+		local := err == nil && parsed.Scheme == "" && parsed.Host == "" && strings.HasPrefix(b.LogoURL, "/") && !strings.HasPrefix(b.LogoURL, "//")
+		remote := err == nil && (parsed.Scheme == "https" || parsed.Scheme == "http") && parsed.Host != ""
+		if !local && !remote {
+			return errors.ConfigInvalid("BRAND_LOGO_URL must be an http(s) URL or a path starting with /")
+		}
+	}
+	return nil
+}
+
 func validateConfig(config *Config) error {
 	if config.Database.URL == "" {
 		return errors.ConfigInvalid("database URL is required")
 	}
-	if config.AI.OpenAIKey == "" {
-		return errors.ConfigInvalid("OpenAI API key is required")
+	if config.Server.Environment != "production" && config.Server.Environment != "development" {
+		return errors.ConfigInvalid("APP_ENV must be \"production\" or \"development\"")
+	}
+	if config.Database.ResetOnStart && config.Server.Environment != "development" {
+		return errors.ConfigInvalid("DB_RESET_ON_START drops all data and requires APP_ENV=development")
+	}
+	if config.Tenancy.Mode != "shared" && config.Tenancy.Mode != "schema" {
+		return errors.ConfigInvalid("TENANCY_MODE must be \"shared\" or \"schema\"")
+	}
+	if err := validateSQLite(config); err != nil {
+		return err
+	}
+	// Replay answers from fixtures, so it needs no provider credentials
+	if !config.AI.Configured() && config.AI.FixtureMode != "replay" {
+		return errors.ConfigInvalid("API key for generator mode " + config.AI.GeneratorMode + " is required")
+	}
+	if config.AI.OllamaAPI != "ollama" && config.AI.OllamaAPI != "openai" {
+		return errors.ConfigInvalid("OLLAMA_API must be \"ollama\" or \"openai\"")
+	}
+	switch config.AI.ColumnEmbeddings {
+	case "local", "provider", "off":
+	default:
+		return errors.ConfigInvalid("COLUMN_EMBEDDINGS must be \"local\", \"provider\" or \"off\"")
+	}
+	switch config.AI.CacheBackend {
+	case "memory", "disk", "postgres", "off":
+	default:
+		return errors.ConfigInvalid("LLM_CACHE must be \"memory\", \"disk\", \"postgres\" or \"off\"")
+	}
+	switch config.AI.FixtureMode {
+	case "off", "record", "replay":
+	default:
+		return errors.ConfigInvalid("LLM_FIXTURES must be \"off\", \"record\" or \"replay\"")
 	}
 	if config.AI.PromptsDir == "" {
 		return errors.ConfigInvalid("prompts directory is required")
 	}
-	return nil
+	if err := validateAutoscale(config.Autoscale); err != nil {
+		return err
+	}
+	if config.Retention.ArchiveAfter < 0 {
+		return errors.ConfigInvalid("ARTIFACT_ARCHIVE_AFTER_DAYS must not be negative")
+	}
+	if config.Retention.Enabled() && config.Retention.Interval <= 0 {
+		return errors.ConfigInvalid("ARTIFACT_ARCHIVE_INTERVAL must be positive")
+	}
+	if config.Retention.CompactionEnabled() && config.Retention.CompactionInterval <= 0 {
+		return errors.ConfigInvalid("LEDGER_COMPACTION_INTERVAL must be positive")
+	}
+	if _, err := logging.ParseLevel(config.Logging.Level); err != nil {
+		return errors.ConfigInvalid("LOG_LEVEL: " + err.Error())
+	}
+	if config.Logging.Format != logging.FormatText && config.Logging.Format != logging.FormatJSON {
+		return errors.ConfigInvalid("LOG_FORMAT must be \"text\" or \"json\"")
+	}
+	if config.Jobs.Workers < 0 {
+		return errors.ConfigInvalid("RESEARCH_JOB_WORKERS must not be negative")
+	}
+	// Workers heartbeat every third of the lease, which must leave room for a slow round trip
+	if config.Jobs.Lease < 10*time.Second {
+		return errors.ConfigInvalid("RESEARCH_JOB_LEASE must be at least 10s")
+	}
+	return validateBranding(config.Branding)
 }
 
 // Helper functions for environment variable parsing
@@ -222,7 +573,7 @@ func getEnvBoolOrDefault(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
-// Duration parsing helper (for future use)
+// Duration parsing helper
 func getEnvDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -232,4 +583,31 @@ func getEnvDurationOrDefault(key string, defaultValue time.Duration) time.Durati
 	return defaultValue
 }
 
+// splitList parses a comma-separated list, dropping blank entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
 
+// validateSQLite refuses the features a SQLite database cannot back: they keep their
+// data in PostgreSQL-only tables
+func validateSQLite(config *Config) error {
+	if !config.Database.SQLite() {
+		return nil
+	}
+	if config.Tenancy.SchemaPerWorkspace() {
+		return errors.ConfigInvalid("TENANCY_MODE=schema needs PostgreSQL; use the shared mode with SQLite")
+	}
+	if config.AI.CacheBackend == "postgres" {
+		return errors.ConfigInvalid("LLM_CACHE=postgres needs PostgreSQL; use memory or disk with SQLite")
+	}
+	if config.Retention.Enabled() {
+		return errors.ConfigInvalid("ARTIFACT_ARCHIVE_AFTER_DAYS needs PostgreSQL; unset it with SQLite")
+	}
+	return nil
+}
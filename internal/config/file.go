@@ -0,0 +1,195 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"gohypo/internal/errors"
+)
+
+// FileEnv names a config file to read instead of the default one
+const FileEnv = "GOHYPO_CONFIG"
+
+// Settings of the command-line tools that have no server equivalent
+const (
+	SeedEnv    = "GOHYPO_SEED"     // default seed of runs whose spec sets none
+	LedgerEnv  = "GOHYPO_LEDGER"   // ledger backend of runs: file, sqlite or memory
+	RunsDirEnv = "GOHYPO_RUNS_DIR" // where runs are written and replayed from
+	DataDirEnv = "GOHYPO_DATA_DIR" // where datasets are picked from
+	ServerEnv  = "GOHYPO_SERVER"   // base URL of the server the admin commands call
+	APIKeyEnv  = "GOHYPO_API_KEY"  // key the admin commands authenticate with
+)
+
+// File is the YAML config file of the command-line tools. Each setting stands for an
+// environment variable, and a variable that is set overrides the file, so the file holds
+// the defaults and the environment the exceptions.
+type File struct {
+	Database struct {
+		URL string `yaml:"url"`
+	} `yaml:"database"`
+	LLM    LLMFile `yaml:"llm"`
+	Seed   int64   `yaml:"seed"`
+	Ledger string  `yaml:"ledger"` // file, sqlite or memory
+	Server struct {
+		URL    string `yaml:"url"`
+		APIKey string `yaml:"api_key"` // one of the server's ADMIN_API_KEYS
+	} `yaml:"server"`
+	Paths struct {
+		Prompts  string `yaml:"prompts"`
+		Runs     string `yaml:"runs"`
+		Data     string `yaml:"data"`
+		Storage  string `yaml:"storage"`  // uploaded datasets
+		Archives string `yaml:"archives"` // archived artifacts
+	} `yaml:"paths"`
+}
+
+// LLMFile holds the provider used for generation and the settings of each provider
+type LLMFile struct {
+	Provider    string          `yaml:"provider"` // openai, anthropic or ollama
+	MaxTokens   int             `yaml:"max_tokens"`
+	Temperature *float64        `yaml:"temperature"`
+	Cache       string          `yaml:"cache"` // memory, disk, postgres or off
+	CacheDir    string          `yaml:"cache_dir"`
+	Fixtures    string          `yaml:"fixtures"` // off, record or replay
+	FixturesDir string          `yaml:"fixtures_dir"`
+	Embeddings  string          `yaml:"embeddings"` // local, provider or off
+	EmbedModel  string          `yaml:"embedding_model"`
+	OpenAI      LLMProviderFile `yaml:"openai"`
+	Anthropic   LLMProviderFile `yaml:"anthropic"`
+	Ollama      LLMProviderFile `yaml:"ollama"`
+}
+
+// LLMProviderFile is one provider's settings; API only applies to ollama
+type LLMProviderFile struct {
+	APIKey  string `yaml:"api_key"`
+	Model   string `yaml:"model"`
+	BaseURL string `yaml:"base_url"`
+	API     string `yaml:"api"`
+}
+
+// DefaultFilePath is ~/.gohypo/config.yaml, or "" when there is no home directory
+func DefaultFilePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".gohypo", "config.yaml")
+}
+
+// LoadFile reads the config file at path. With an empty path it reads the file named by
+// GOHYPO_CONFIG, else the default one, which may be absent: the returned path is then ""
+// and the file empty.
+func LoadFile(path string) (*File, string, error) {
+	explicit := path != ""
+	if !explicit {
+		path = os.Getenv(FileEnv)
+		explicit = path != ""
+	}
+	if !explicit {
+		path = DefaultFilePath()
+	}
+
+	file := &File{}
+	if path == "" {
+		return file, "", nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return file, "", nil
+	}
+	if err != nil {
+		return nil, "", errors.ConfigInvalid(fmt.Sprintf("failed to read config file: %v", err))
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true) // a misspelt setting would otherwise be ignored silently
+	if err := decoder.Decode(file); err != nil && err != io.EOF {
+		return nil, "", errors.ConfigInvalid(fmt.Sprintf("config file %s: %v", path, err))
+	}
+	if file.Ledger != "" && file.Ledger != "file" && file.Ledger != "memory" {
+		return nil, "", errors.ConfigInvalid(fmt.Sprintf("config file %s: ledger must be \"file\" or \"memory\"", path))
+	}
+	return file, path, nil
+}
+
+// Env lists the environment variables the file sets, in a stable order. Settings that are
+// not set in the file are left out.
+func (f *File) Env() [][2]string {
+	var env [][2]string
+	add := func(value string, keys ...string) {
+		if value == "" {
+			return
+		}
+		for _, key := range keys {
+			env = append(env, [2]string{key, value})
+		}
+	}
+	add(f.Database.URL, "DATABASE_URL")
+
+	add(f.LLM.Provider, "GENERATOR_MODE")
+	if f.LLM.MaxTokens > 0 {
+		add(strconv.Itoa(f.LLM.MaxTokens), "MAX_TOKENS", "LLM_MAX_TOKENS")
+	}
+	if f.LLM.Temperature != nil {
+		add(strconv.FormatFloat(*f.LLM.Temperature, 'f', -1, 64), "TEMPERATURE", "LLM_TEMPERATURE")
+	}
+	add(f.LLM.Cache, "LLM_CACHE")
+	add(f.LLM.CacheDir, "LLM_CACHE_DIR")
+	add(f.LLM.Fixtures, "LLM_FIXTURES")
+	add(f.LLM.FixturesDir, "LLM_FIXTURES_DIR")
+	add(f.LLM.Embeddings, "COLUMN_EMBEDDINGS")
+	add(f.LLM.EmbedModel, "EMBEDDING_MODEL")
+	add(f.LLM.OpenAI.APIKey, "OPENAI_API_KEY")
+	add(f.LLM.OpenAI.Model, "LLM_MODEL")
+	add(f.LLM.Anthropic.APIKey, "ANTHROPIC_API_KEY")
+	add(f.LLM.Anthropic.Model, "ANTHROPIC_MODEL")
+	add(f.LLM.Anthropic.BaseURL, "ANTHROPIC_BASE_URL")
+	add(f.LLM.Ollama.BaseURL, "OLLAMA_BASE_URL")
+	add(f.LLM.Ollama.Model, "OLLAMA_MODEL")
+	add(f.LLM.Ollama.API, "OLLAMA_API")
+
+	if f.Seed != 0 {
+		add(strconv.FormatInt(f.Seed, 10), SeedEnv)
+	}
+	add(f.Ledger, LedgerEnv)
+	add(f.Server.URL, ServerEnv)
+	add(f.Server.APIKey, APIKeyEnv)
+	add(f.Paths.Prompts, "PROMPTS_DIR")
+	add(f.Paths.Runs, RunsDirEnv)
+	add(f.Paths.Data, DataDirEnv)
+	add(f.Paths.Storage, "DATASET_STORAGE_PATH")
+	add(f.Paths.Archives, "ARTIFACT_ARCHIVE_DIR")
+	return env
+}
+
+// Apply sets the file's environment variables that are not set already and returns their
+// names, so that Load and every other reader of the environment see the file
+func (f *File) Apply() []string {
+	var applied []string
+	for _, kv := range f.Env() {
+		if _, set := os.LookupEnv(kv[0]); set {
+			continue
+		}
+		os.Setenv(kv[0], kv[1])
+		applied = append(applied, kv[0])
+	}
+	return applied
+}
+
+// Seed returns GOHYPO_SEED, or 0 when it is not set
+func Seed() (int64, error) {
+	value := os.Getenv(SeedEnv)
+	if value == "" {
+		return 0, nil
+	}
+	seed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.ConfigInvalid(SeedEnv + " must be an integer")
+	}
+	return seed, nil
+}
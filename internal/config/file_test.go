@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileAppliesUnderTheEnvironment(t *testing.T) {
+	path := writeConfigFile(t, `
+llm:
+  provider: anthropic
+  temperature: 0.2
+  anthropic:
+    api_key: from-file
+    model: file-model
+seed: 42
+server:
+  url: https://gohypo.example.com
+  api_key: admin-key
+paths:
+  runs: /tmp/runs
+`)
+	t.Setenv("ANTHROPIC_MODEL", "env-model")
+	for _, key := range []string{"GENERATOR_MODE", "ANTHROPIC_API_KEY", "TEMPERATURE", "LLM_TEMPERATURE", SeedEnv, RunsDirEnv, ServerEnv, APIKeyEnv} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+
+	file, loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded != path {
+		t.Errorf("loaded %q, want %q", loaded, path)
+	}
+	file.Apply()
+
+	want := map[string]string{
+		"GENERATOR_MODE":    "anthropic",
+		"ANTHROPIC_API_KEY": "from-file",
+		"ANTHROPIC_MODEL":   "env-model", // the environment overrides the file
+		"LLM_TEMPERATURE":   "0.2",
+		RunsDirEnv:          "/tmp/runs",
+		ServerEnv:           "https://gohypo.example.com",
+		APIKeyEnv:           "admin-key",
+	}
+	for key, value := range want {
+		if got := os.Getenv(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	if seed, err := Seed(); err != nil || seed != 42 {
+		t.Errorf("Seed() = %d, %v; want 42", seed, err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(FileEnv, "")
+
+	if _, loaded, err := LoadFile(""); err != nil || loaded != "" {
+		t.Errorf("a missing default file should be skipped, got %q, %v", loaded, err)
+	}
+	if _, _, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected a missing named file to be an error")
+	}
+	if _, _, err := LoadFile(writeConfigFile(t, "seeed: 1\n")); err == nil {
+		t.Error("expected a misspelt setting to be rejected")
+	}
+	if _, _, err := LoadFile(writeConfigFile(t, "ledger: postgres\n")); err == nil {
+		t.Error("expected an unknown ledger to be rejected")
+	}
+
+	path := writeConfigFile(t, "")
+	t.Setenv(FileEnv, path)
+	if _, loaded, err := LoadFile(""); err != nil || loaded != path {
+		t.Errorf("GOHYPO_CONFIG should name the file, got %q, %v", loaded, err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"gohypo/adapters/postgres"
+	"gohypo/adapters/sqlite"
 	"gohypo/ai"
 	"gohypo/domain/core"
 	"gohypo/domain/dataset"
@@ -15,6 +16,8 @@ import (
 	"gohypo/internal/config"
 	"gohypo/internal/referee"
 	"gohypo/internal/research"
+	"gohypo/internal/retention"
+	"gohypo/internal/session"
 	"gohypo/internal/testkit"
 	"gohypo/ports"
 
@@ -34,9 +37,19 @@ type Container struct {
 	HypothesisRepo ports.HypothesisRepository
 	PromptRepo     ports.PromptRepository
 	WorkspaceRepo  ports.WorkspaceRepository
+	DatasetRepo    ports.DatasetRepository
 	EvidenceRepo   *postgres.EvidenceRepository
 	UIStateRepo    *postgres.UIStateRepository
 
+	// Artifact archiving (nil unless ARTIFACT_ARCHIVE_AFTER_DAYS is set)
+	Archiver *retention.Archiver
+
+	// Ledger compaction (nil unless LEDGER_RETENTION_POLICY is set)
+	Compactor *retention.Compactor
+
+	// Schema-per-workspace provisioning (nil in shared tenancy mode)
+	SchemaProvisioner *postgres.WorkspaceSchemaProvisioner
+
 	// Research components
 	SessionManager  *research.SessionManager
 	ResearchWorker  *research.ResearchWorker
@@ -92,6 +105,11 @@ func (c *Container) InitWithDatabase(db *sqlx.DB) error {
 		return fmt.Errorf("failed to initialize test infrastructure: %w", err)
 	}
 
+	// Initialize ledger compaction
+	if err := c.initLedgerCompaction(); err != nil {
+		return fmt.Errorf("failed to initialize ledger compaction: %w", err)
+	}
+
 	// Initialize research components
 	if err := c.initResearch(); err != nil {
 		return fmt.Errorf("failed to initialize research components: %w", err)
@@ -106,8 +124,35 @@ func (c *Container) initRepositories() error {
 	c.UserRepo = postgres.NewUserRepository(c.DB)
 	c.SessionRepo = postgres.NewSessionRepository(c.DB)
 	c.HypothesisRepo = postgres.NewHypothesisRepository(c.DB)
+	if c.Config.Retention.Enabled() {
+		blobs, err := session.NewLocalBlobStore(c.Config.Retention.ArchiveDir)
+		if err != nil {
+			return fmt.Errorf("failed to open artifact archive: %w", err)
+		}
+		c.Archiver = retention.NewArchiver(postgres.NewArtifactArchiveRepository(c.DB), blobs, c.Config.Retention.ArchiveAfter)
+		// Opening an archived session rehydrates it, wherever the hypotheses are read from
+		c.HypothesisRepo = retention.NewRehydratingHypothesisRepository(c.HypothesisRepo, c.Archiver)
+	}
 	c.PromptRepo = postgres.NewPromptRepository(c.DB)
-	c.WorkspaceRepo = postgres.NewWorkspaceRepository(c.DB)
+	if sqlite.IsDB(c.DB) {
+		// Config validation keeps schema tenancy and archiving, which need PostgreSQL, off
+		c.HypothesisRepo = sqlite.NewHypothesisRepository(c.DB)
+		c.WorkspaceRepo = sqlite.NewWorkspaceRepository(c.DB)
+		c.DatasetRepo = sqlite.NewDatasetRepository(c.DB)
+	} else if c.Config.Tenancy.SchemaPerWorkspace() {
+		c.SchemaProvisioner = postgres.NewWorkspaceSchemaProvisioner(c.DB)
+		if err := c.SchemaProvisioner.EnsureIndex(context.Background()); err != nil {
+			return err
+		}
+		if _, err := c.SchemaProvisioner.ProvisionAll(context.Background()); err != nil {
+			return err
+		}
+		c.WorkspaceRepo = postgres.NewSchemaIsolatedWorkspaceRepository(c.DB)
+		c.DatasetRepo = postgres.NewSchemaRoutedDatasetRepository(c.DB)
+	} else {
+		c.WorkspaceRepo = postgres.NewWorkspaceRepository(c.DB)
+		c.DatasetRepo = postgres.NewDatasetRepository(c.DB)
+	}
 	c.EvidenceRepo = postgres.NewEvidenceRepository(c.DB)
 	c.UIStateRepo = postgres.NewUIStateRepository(c.DB)
 	return nil
@@ -117,9 +162,34 @@ func (c *Container) initRepositories() error {
 func (c *Container) initTestInfrastructure() error {
 	var err error
 	c.TestKit, err = testkit.NewTestKit()
+	if err == nil && sqlite.IsDB(c.DB) {
+		c.TestKit.UseLedger(sqlite.NewLedger(c.DB))
+	}
 	return err
 }
 
+// initLedgerCompaction sets up the compactor enforcing the ledger retention policy
+func (c *Container) initLedgerCompaction() error {
+	if !c.Config.Retention.CompactionEnabled() {
+		return nil
+	}
+	policy, err := retention.ParseLedgerPolicy(c.Config.Retention.LedgerPolicy)
+	if err != nil {
+		return fmt.Errorf("invalid LEDGER_RETENTION_POLICY: %w", err)
+	}
+	policy.KeepValidatedEvidence = c.Config.Retention.KeepValidatedEvidence
+	blobs, err := session.NewLocalBlobStore(c.Config.Retention.ArchiveDir)
+	if err != nil {
+		return fmt.Errorf("failed to open ledger archive: %w", err)
+	}
+	validated := postgres.NewValidatedEvidenceRepository(c.DB)
+	if sqlite.IsDB(c.DB) {
+		validated = sqlite.NewValidatedEvidenceRepository(c.DB)
+	}
+	c.Compactor = retention.NewCompactor(c.TestKit.LedgerCompactionAdapter(), blobs, policy, validated)
+	return nil
+}
+
 // initResearch initializes research-related components
 func (c *Container) initResearch() error {
 	// Initialize core research components
@@ -144,7 +214,7 @@ func (c *Container) initResearch() error {
 	log.Printf("Core research components initialized: SessionManager, ResearchStorage, SSEHub")
 
 	// Initialize AI components (require config to be available)
-	if c.Config != nil && c.Config.AI.OpenAIKey != "" {
+	if c.Config != nil && c.Config.AI.Configured() {
 		if err := c.initAIComponents(); err != nil {
 			log.Printf("Warning: Failed to initialize AI components: %v", err)
 			// Continue without AI components for backward compatibility
@@ -190,8 +260,8 @@ func (c *Container) initResearch() error {
 // initAIComponents initializes AI and machine learning components
 func (c *Container) initAIComponents() error {
 	// Check if AI configuration is available
-	if c.Config.AI.OpenAIKey == "" {
-		return fmt.Errorf("AI configuration not available - OpenAI key required")
+	if !c.Config.AI.Configured() {
+		return fmt.Errorf("AI configuration not available - API key for generator mode %s required", c.Config.AI.GeneratorMode)
 	}
 
 	// TODO: Initialize proper LLM client
@@ -217,7 +287,7 @@ func (c *Container) EnsureDefaultWorkspace(ctx context.Context) error {
 	workspace, err := c.WorkspaceRepo.GetByID(ctx, defaultWorkspaceID)
 	if err == nil && workspace != nil {
 		// Workspace exists
-		return nil
+		return c.provisionWorkspaceSchema(ctx, defaultWorkspaceID)
 	}
 
 	// Create default workspace
@@ -241,6 +311,17 @@ func (c *Container) EnsureDefaultWorkspace(ctx context.Context) error {
 	}
 
 	log.Printf("Created default workspace: %s", defaultWorkspaceID)
+	return c.provisionWorkspaceSchema(ctx, defaultWorkspaceID)
+}
+
+// provisionWorkspaceSchema creates the workspace schema when running schema-per-workspace
+func (c *Container) provisionWorkspaceSchema(ctx context.Context, workspaceID core.ID) error {
+	if c.SchemaProvisioner == nil {
+		return nil
+	}
+	if err := c.SchemaProvisioner.Provision(ctx, workspaceID); err != nil {
+		return fmt.Errorf("failed to provision workspace schema: %w", err)
+	}
 	return nil
 }
 
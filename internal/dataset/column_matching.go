@@ -0,0 +1,261 @@
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+	"gohypo/ports"
+)
+
+const (
+	// defaultMinColumnSimilarity is the similarity below which two columns are not matched
+	defaultMinColumnSimilarity = 0.6
+
+	// valueSimilarityWeight is the weight of sample values against names when both columns
+	// have some; below 1, so columns of one format (dates, prices) do not match on values alone
+	valueSimilarityWeight = 0.6
+
+	// columnSampleValues bounds the sample values embedded per column
+	columnSampleValues = 8
+
+	// maxCachedDatasets bounds the datasets whose column vectors are kept; the least
+	// recently used are evicted first
+	maxCachedDatasets = 256
+)
+
+// ColumnMatch pairs a column of the source dataset with its most similar column in the target
+type ColumnMatch struct {
+	SourceColumn    string  `json:"source_column"`
+	TargetColumn    string  `json:"target_column"`
+	Similarity      float64 `json:"similarity"`
+	NameSimilarity  float64 `json:"name_similarity"`
+	ValueSimilarity float64 `json:"value_similarity,omitempty"`
+	JoinCandidate   bool    `json:"join_candidate"` // both columns look like keys
+}
+
+// ColumnMatcher scores cross-dataset column similarity from embeddings of each column's name
+// and of its sample values, so columns match even when their datasets name them differently.
+// Vectors are cached per dataset and recomputed when the dataset is updated.
+type ColumnMatcher struct {
+	embedder      ports.TextEmbedder
+	minSimilarity float64
+
+	mu    sync.Mutex
+	cache map[core.ID]*datasetVectors
+}
+
+// datasetVectors holds the vectors of one dataset's column texts
+type datasetVectors struct {
+	model     string
+	updatedAt time.Time
+	lastUsed  time.Time
+	vectors   map[string][]float32
+}
+
+// columnVectors are the embeddings of one column; values is nil without sample values
+type columnVectors struct {
+	field  domainDataset.FieldInfo
+	name   []float32
+	values []float32
+}
+
+// NewColumnMatcher creates a matcher over embedder
+func NewColumnMatcher(embedder ports.TextEmbedder) *ColumnMatcher {
+	return &ColumnMatcher{
+		embedder:      embedder,
+		minSimilarity: defaultMinColumnSimilarity,
+		cache:         make(map[core.ID]*datasetVectors),
+	}
+}
+
+// Model returns the embedding model of the matcher
+func (m *ColumnMatcher) Model() string {
+	return m.embedder.Model()
+}
+
+// Match pairs the columns of ds1 and ds2 one to one, most similar first, keeping pairs at or
+// above the minimum similarity. A column's similarity is that of its names, raised by the
+// weighted similarity of its sample values when both columns have some.
+func (m *ColumnMatcher) Match(ctx context.Context, ds1, ds2 *domainDataset.Dataset) ([]ColumnMatch, error) {
+	columns1, err := m.columnVectors(ctx, ds1)
+	if err != nil {
+		return nil, err
+	}
+	columns2, err := m.columnVectors(ctx, ds2)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []ColumnMatch
+	for _, c1 := range columns1 {
+		for _, c2 := range columns2 {
+			match := ColumnMatch{
+				SourceColumn:   c1.field.Name,
+				TargetColumn:   c2.field.Name,
+				NameSimilarity: cosineSimilarity(c1.name, c2.name),
+			}
+			match.Similarity = match.NameSimilarity
+			if c1.values != nil && c2.values != nil {
+				match.ValueSimilarity = cosineSimilarity(c1.values, c2.values)
+				match.Similarity = math.Max(match.NameSimilarity,
+					(1-valueSimilarityWeight)*match.NameSimilarity+valueSimilarityWeight*match.ValueSimilarity)
+			}
+			if match.Similarity >= m.minSimilarity {
+				match.JoinCandidate = looksLikeKey(c1.field, ds1.RecordCount) && looksLikeKey(c2.field, ds2.RecordCount)
+				candidates = append(candidates, match)
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Similarity > candidates[j].Similarity
+	})
+
+	used1 := make(map[string]bool)
+	used2 := make(map[string]bool)
+	var matches []ColumnMatch
+	for _, candidate := range candidates {
+		if used1[candidate.SourceColumn] || used2[candidate.TargetColumn] {
+			continue
+		}
+		used1[candidate.SourceColumn] = true
+		used2[candidate.TargetColumn] = true
+		matches = append(matches, candidate)
+	}
+	return matches, nil
+}
+
+// columnVectors embeds the columns of ds, reusing the dataset's cached vectors and embedding
+// only the texts not seen since the dataset was last updated
+func (m *ColumnMatcher) columnVectors(ctx context.Context, ds *domainDataset.Dataset) ([]columnVectors, error) {
+	type columnTexts struct{ name, values string }
+	texts := make([]columnTexts, len(ds.Metadata.Fields))
+	for i, field := range ds.Metadata.Fields {
+		texts[i] = columnTexts{name: columnNameText(field), values: columnValuesText(field)}
+	}
+
+	m.mu.Lock()
+	cached := m.cache[ds.ID]
+	if cached == nil || cached.model != m.embedder.Model() || !cached.updatedAt.Equal(ds.UpdatedAt) {
+		cached = &datasetVectors{model: m.embedder.Model(), updatedAt: ds.UpdatedAt, vectors: make(map[string][]float32)}
+	}
+	var missing []string
+	queued := make(map[string]bool)
+	for _, t := range texts {
+		for _, text := range []string{t.name, t.values} {
+			if text != "" && cached.vectors[text] == nil && !queued[text] {
+				queued[text] = true
+				missing = append(missing, text)
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	var embedded [][]float32
+	if len(missing) > 0 {
+		var err error
+		embedded, err = m.embedder.Embed(ctx, missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed columns of dataset %s: %w", ds.ID, err)
+		}
+		if len(embedded) != len(missing) {
+			return nil, fmt.Errorf("embedder returned %d vectors for %d columns of dataset %s", len(embedded), len(missing), ds.ID)
+		}
+	}
+
+	m.mu.Lock()
+	for i, text := range missing {
+		cached.vectors[text] = embedded[i]
+	}
+	cached.lastUsed = time.Now()
+	m.cache[ds.ID] = cached
+	m.evictLocked()
+	columns := make([]columnVectors, len(texts))
+	for i, t := range texts {
+		columns[i] = columnVectors{field: ds.Metadata.Fields[i], name: cached.vectors[t.name]}
+		if t.values != "" {
+			columns[i].values = cached.vectors[t.values]
+		}
+	}
+	m.mu.Unlock()
+	return columns, nil
+}
+
+// evictLocked drops the least recently used datasets beyond maxCachedDatasets
+func (m *ColumnMatcher) evictLocked() {
+	for len(m.cache) > maxCachedDatasets {
+		var oldestID core.ID
+		var oldest time.Time
+		for id, vectors := range m.cache {
+			if oldestID == "" || vectors.lastUsed.Before(oldest) {
+				oldestID, oldest = id, vectors.lastUsed
+			}
+		}
+		delete(m.cache, oldestID)
+	}
+}
+
+// columnNameText is the text a column's name is embedded from: its original header, the
+// normalized name when that differs, and its unit
+func columnNameText(field domainDataset.FieldInfo) string {
+	text := field.DisplayName()
+	if field.Name != text {
+		text += " " + field.Name
+	}
+	if field.Unit != "" {
+		text += " (" + field.Unit + ")"
+	}
+	return text
+}
+
+// columnValuesText is the text a column's sample values are embedded from, or empty when
+// it has none
+func columnValuesText(field domainDataset.FieldInfo) string {
+	var values []string
+	for _, value := range field.SampleValues {
+		if value == nil {
+			continue
+		}
+		if s := strings.TrimSpace(fmt.Sprint(value)); s != "" {
+			values = append(values, s)
+		}
+		if len(values) == columnSampleValues {
+			break
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// looksLikeKey reports whether a field is named like an identifier or is nearly unique
+// across the dataset's records
+func looksLikeKey(field domainDataset.FieldInfo, recordCount int) bool {
+	name := strings.ToLower(field.Name)
+	for _, suffix := range []string{"id", "key", "code", "ref", "number", "no", "uuid", "sku"} {
+		if name == suffix || strings.HasSuffix(name, "_"+suffix) {
+			return true
+		}
+	}
+	return recordCount > 0 && field.UniqueCount >= recordCount*9/10
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
@@ -0,0 +1,103 @@
+package dataset
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gohypo/ai"
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+)
+
+// countingEmbedder counts the texts it is asked to embed
+type countingEmbedder struct {
+	*ai.HashingEmbedder
+	embedded int
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.embedded += len(texts)
+	return e.HashingEmbedder.Embed(ctx, texts)
+}
+
+func customersAndOrders() (*domainDataset.Dataset, *domainDataset.Dataset) {
+	customers := &domainDataset.Dataset{
+		ID: "customers", WorkspaceID: "w1", RecordCount: 100,
+		Metadata: domainDataset.DatasetMetadata{Fields: []domainDataset.FieldInfo{
+			{Name: "customer_id", UniqueCount: 100, SampleValues: []interface{}{"C-1001", "C-1002", "C-1003", "C-1004"}},
+			{Name: "full_name", SampleValues: []interface{}{"Ada Lovelace", "Alan Turing"}},
+			{Name: "signup_date", SampleValues: []interface{}{"2024-01-03", "2024-02-11"}},
+		}},
+	}
+	orders := &domainDataset.Dataset{
+		ID: "orders", WorkspaceID: "w1", RecordCount: 400,
+		Metadata: domainDataset.DatasetMetadata{Fields: []domainDataset.FieldInfo{
+			{Name: "order_total", SampleValues: []interface{}{19.99, 5.49, 120}},
+			{Name: "cust_ref", UniqueCount: 90, SampleValues: []interface{}{"C-1003", "C-1001", "C-1004", "C-1002"}},
+			{Name: "OrderedOn", SampleValues: []interface{}{"2024-03-01", "2024-03-02"}},
+		}},
+	}
+	return customers, orders
+}
+
+// TestColumnMatcherDivergentNames verifies differently named key columns match on their
+// shared values and suggest a join, while unrelated columns stay unmatched
+func TestColumnMatcherDivergentNames(t *testing.T) {
+	customers, orders := customersAndOrders()
+	matches, err := NewColumnMatcher(ai.NewHashingEmbedder()).Match(context.Background(), customers, orders)
+	if err != nil {
+		t.Fatalf("Match: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected only customer_id to match, got %+v", matches)
+	}
+	match := matches[0]
+	if match.SourceColumn != "customer_id" || match.TargetColumn != "cust_ref" || !match.JoinCandidate || match.ValueSimilarity < match.NameSimilarity {
+		t.Errorf("unexpected match %+v", match)
+	}
+
+	engine := NewRelationshipDiscoveryEngine(nil, nil, nil, nil, nil)
+	engine.SetColumnEmbedder(ai.NewHashingEmbedder())
+	relation := engine.analyzeColumnEmbeddings(customers, orders)
+	if relation == nil || relation.RelationType != "potential_join" || relation.Confidence < 0.7 {
+		t.Fatalf("expected a join relation, got %+v", relation)
+	}
+	if engine.determineMergeType([]*domainDataset.Dataset{customers, orders}, []*domainDataset.DatasetRelation{relation}) != AutoJoin {
+		t.Error("expected a join match to suggest AutoJoin")
+	}
+}
+
+// TestColumnMatcherCachesPerDataset verifies vectors are reused across pairs and recomputed
+// once a dataset is updated
+func TestColumnMatcherCachesPerDataset(t *testing.T) {
+	customers, orders := customersAndOrders()
+	embedder := &countingEmbedder{HashingEmbedder: ai.NewHashingEmbedder()}
+	matcher := NewColumnMatcher(embedder)
+	ctx := context.Background()
+
+	if _, err := matcher.Match(ctx, customers, orders); err != nil {
+		t.Fatal(err)
+	}
+	if embedder.embedded != 12 {
+		t.Fatalf("expected a name and a values text per column, embedded %d", embedder.embedded)
+	}
+	if _, err := matcher.Match(ctx, orders, customers); err != nil {
+		t.Fatal(err)
+	}
+	if embedder.embedded != 12 {
+		t.Errorf("expected cached vectors to be reused, embedded %d", embedder.embedded)
+	}
+
+	orders.UpdatedAt = time.Now()
+	orders.Metadata.Fields = append(orders.Metadata.Fields, domainDataset.FieldInfo{Name: "channel"})
+	if _, err := matcher.Match(ctx, customers, orders); err != nil {
+		t.Fatal(err)
+	}
+	if embedder.embedded != 12+7 {
+		t.Errorf("expected only the updated dataset to be embedded again, embedded %d", embedder.embedded)
+	}
+	if _, ok := matcher.cache[core.ID("customers")]; !ok {
+		t.Error("expected customers to stay cached")
+	}
+}
@@ -0,0 +1,604 @@
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gohypo/adapters/excel"
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+	"gohypo/ports"
+)
+
+// Periods a temporal join buckets timestamps to before matching them
+const (
+	AlignHour  = "hour"
+	AlignDay   = "day"
+	AlignWeek  = "week"
+	AlignMonth = "month"
+	AlignYear  = "year"
+)
+
+const (
+	// MaxCombinedDatasets bounds the datasets one workspace sweep joins
+	MaxCombinedDatasets = 8
+
+	// combinedColumnSeparator joins a dataset's name to a column name the combined table
+	// already uses
+	combinedColumnSeparator = "__"
+
+	// temporalJoinScore ranks a join on time alone below every join on key values, whose
+	// containment is at least minKeyContainment
+	temporalJoinScore = 0.3
+)
+
+// ParseAlignFrequency checks a temporal join period; empty is a day
+func ParseAlignFrequency(s string) (string, error) {
+	switch s {
+	case "":
+		return AlignDay, nil
+	case AlignHour, AlignDay, AlignWeek, AlignMonth, AlignYear:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown alignment frequency %q (use %s, %s, %s, %s or %s)", s, AlignHour, AlignDay, AlignWeek, AlignMonth, AlignYear)
+}
+
+// CrossDatasetJoin attaches a dataset to one already in the combined table: rows match on a
+// key the two share values of, on timestamps in the same period, or on both
+type CrossDatasetJoin struct {
+	DatasetID        core.ID `json:"dataset_id"`
+	DatasetName      string  `json:"dataset_name"`
+	ParentID         core.ID `json:"parent_id"`
+	Column           string  `json:"column,omitempty"`        // key column of the dataset
+	ParentColumn     string  `json:"parent_column,omitempty"` // key column it matches in the parent
+	Containment      float64 `json:"containment,omitempty"`
+	TimeColumn       string  `json:"time_column,omitempty"`
+	ParentTimeColumn string  `json:"parent_time_column,omitempty"`
+	Frequency        string  `json:"frequency,omitempty"` // period timestamps are matched in
+	MatchedRows      int     `json:"matched_rows"`        // combined rows the dataset gave values to
+	AggregatedRows   int     `json:"aggregated_rows"`     // combined rows several of its rows matched
+	// Set when the key columns hold names resolved to shared entities rather than equal values
+	EntityResolution *EntityMatchReport `json:"entity_resolution,omitempty"`
+	EntityColumn     string             `json:"entity_column,omitempty"` // combined column of the resolved entity IDs
+
+	resolution *EntityResolution
+}
+
+// Describe states what the join matched rows on
+func (j CrossDatasetJoin) Describe() string {
+	var parts []string
+	switch {
+	case j.EntityResolution != nil:
+		parts = append(parts, j.Column+" ~ "+j.ParentColumn+" by entity resolution")
+	case j.Column != "":
+		parts = append(parts, j.Column+" = "+j.ParentColumn)
+	}
+	if j.TimeColumn != "" {
+		parts = append(parts, fmt.Sprintf("%s ~ %s by %s", j.TimeColumn, j.ParentTimeColumn, j.Frequency))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SkippedDataset is a workspace dataset left out of the combined table
+type SkippedDataset struct {
+	DatasetID   core.ID `json:"dataset_id"`
+	DatasetName string  `json:"dataset_name"`
+	Reason      string  `json:"reason"`
+}
+
+// CrossDatasetPlan is how a workspace sweep combines datasets: the rows of the base dataset,
+// with each join adding the columns of a dataset to the base or to a dataset joined before it
+type CrossDatasetPlan struct {
+	BaseID   core.ID            `json:"base_id"`
+	BaseName string             `json:"base_name"`
+	Joins    []CrossDatasetJoin `json:"joins"`
+	Skipped  []SkippedDataset   `json:"skipped,omitempty"`
+}
+
+// CombinedTable is the data of several datasets joined into one table, with the origin of
+// each of its columns
+type CombinedTable struct {
+	Data    *excel.ExcelData
+	Origins map[string]domainDataset.ColumnOrigin
+	Plan    *CrossDatasetPlan
+
+	identifiers map[string]bool // resolved entity ID columns, which are not variables
+}
+
+// VariableKeys returns the columns of the table as variables, without resolved entity IDs
+func (t *CombinedTable) VariableKeys() []core.VariableKey {
+	keys := make([]core.VariableKey, 0, len(t.Data.Headers))
+	for _, header := range t.Data.Headers {
+		if !t.identifiers[header] {
+			keys = append(keys, core.VariableKey(header))
+		}
+	}
+	return keys
+}
+
+// CrossDatasetCombiner joins the datasets of a workspace into one table, matching rows on the
+// join keys their values reveal and aligning time series on a common period
+type CrossDatasetCombiner struct {
+	reader    ColumnValueReader
+	detector  *KeyOverlapDetector
+	frequency string
+	entities  EntityResolutionConfig
+}
+
+// NewCrossDatasetCombiner creates a combiner reading datasets through reader; frequency is
+// the period temporal joins match timestamps in
+func NewCrossDatasetCombiner(reader ColumnValueReader, frequency string) *CrossDatasetCombiner {
+	return &CrossDatasetCombiner{
+		reader:    reader,
+		detector:  NewKeyOverlapDetector(reader),
+		frequency: frequency,
+		entities:  DefaultEntityResolutionConfig(),
+	}
+}
+
+// SetEntityResolution sets how names are resolved when two datasets share no key values
+func (c *CrossDatasetCombiner) SetEntityResolution(config EntityResolutionConfig) {
+	c.entities = config
+}
+
+// Plan chooses the base dataset, the one with the most records, and attaches the others one
+// at a time by their strongest link to a dataset already attached: a shared key first, then
+// names resolved to shared entities, time alone when neither is found. Datasets linked to none, or written in another locale than the
+// base, are skipped with the reason.
+func (c *CrossDatasetCombiner) Plan(ctx context.Context, datasets []*domainDataset.Dataset) (*CrossDatasetPlan, error) {
+	if len(datasets) == 0 {
+		return nil, fmt.Errorf("no datasets to combine")
+	}
+	ordered := append([]*domainDataset.Dataset(nil), datasets...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].RecordCount != ordered[j].RecordCount {
+			return ordered[i].RecordCount > ordered[j].RecordCount
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+	base := ordered[0]
+	plan := &CrossDatasetPlan{BaseID: base.ID, BaseName: base.GetDisplayName(), Joins: []CrossDatasetJoin{}}
+
+	attached := []*domainDataset.Dataset{base}
+	var pending []*domainDataset.Dataset
+	for i, ds := range ordered[1:] {
+		switch {
+		case i+1 >= MaxCombinedDatasets:
+			plan.skip(ds, fmt.Sprintf("a workspace sweep combines at most %d datasets", MaxCombinedDatasets))
+		case tableLocale(ds).Name != tableLocale(base).Name:
+			plan.skip(ds, fmt.Sprintf("writes numbers and dates as %s, the base dataset as %s", tableLocale(ds).Name, tableLocale(base).Name))
+		default:
+			pending = append(pending, ds)
+		}
+	}
+
+	// A pair's link does not change as datasets are attached, so each is scored once
+	type scoredLink struct {
+		join  CrossDatasetJoin
+		score float64
+	}
+	links := make(map[[2]core.ID]scoredLink)
+	for len(pending) > 0 {
+		bestIndex, bestScore := -1, 0.0
+		var best CrossDatasetJoin
+		for i, candidate := range pending {
+			for _, parent := range attached {
+				pair := [2]core.ID{parent.ID, candidate.ID}
+				if _, ok := links[pair]; !ok {
+					join, score, err := c.link(ctx, parent, candidate)
+					if err != nil {
+						return nil, err
+					}
+					links[pair] = scoredLink{join, score}
+				}
+				join, score := links[pair].join, links[pair].score
+				if score > bestScore {
+					bestIndex, bestScore, best = i, score, join
+				}
+			}
+		}
+		if bestIndex < 0 {
+			for _, ds := range pending {
+				plan.skip(ds, "shares no key values, entity names or time column with the combined datasets")
+			}
+			break
+		}
+		plan.Joins = append(plan.Joins, best)
+		attached = append(attached, pending[bestIndex])
+		pending = append(pending[:bestIndex], pending[bestIndex+1:]...)
+	}
+	return plan, nil
+}
+
+// link scores the best way to join candidate onto parent, or returns a zero score when the
+// two share neither key values nor a time column. A key the candidate is unique in wins over
+// one it repeats; rows of a repeated key are also aligned on time when both have a time column.
+func (c *CrossDatasetCombiner) link(ctx context.Context, parent, candidate *domainDataset.Dataset) (CrossDatasetJoin, float64, error) {
+	join := CrossDatasetJoin{DatasetID: candidate.ID, DatasetName: candidate.GetDisplayName(), ParentID: parent.ID}
+	parentTime, candidateTime := timeColumn(parent), timeColumn(candidate)
+
+	overlaps, err := c.detector.Detect(ctx, parent, candidate)
+	if err != nil {
+		return join, 0, fmt.Errorf("failed to find join keys of %s and %s: %w", parent.ID, candidate.ID, err)
+	}
+	// A PII column is not in the combined table, so it cannot be matched on
+	parentExcluded, candidateExcluded := parent.Metadata.ExcludedFields(), candidate.Metadata.ExcludedFields()
+	usable := overlaps[:0]
+	for _, overlap := range overlaps {
+		if !parentExcluded[overlap.SourceColumn] && !candidateExcluded[overlap.TargetColumn] {
+			usable = append(usable, overlap)
+		}
+	}
+	if parentExcluded[parentTime] || candidateExcluded[candidateTime] {
+		parentTime, candidateTime = "", ""
+	}
+	if len(usable) > 0 {
+		best := usable[0]
+		for _, overlap := range usable {
+			if overlap.Direction == "source_references_target" && overlap.Containment() >= best.Containment()-0.05 {
+				best = overlap
+				break
+			}
+		}
+		join.Column, join.ParentColumn, join.Containment = best.TargetColumn, best.SourceColumn, best.Containment()
+		score := best.Containment()
+		if best.Direction == "source_references_target" {
+			score += 0.1
+		} else if parentTime != "" && candidateTime != "" {
+			join.TimeColumn, join.ParentTimeColumn, join.Frequency = candidateTime, parentTime, c.frequency
+		}
+		return join, score, nil
+	}
+
+	// Names spelled differently in the two datasets may still be the same entities; a match
+	// ranks below a shared key of the same containment
+	match, err := ResolveEntityColumns(ctx, c.reader, parent, candidate, c.entities)
+	if err != nil {
+		return join, 0, fmt.Errorf("failed to resolve entities of %s and %s: %w", parent.ID, candidate.ID, err)
+	}
+	if match != nil {
+		join.Column, join.ParentColumn = match.TargetColumn, match.SourceColumn
+		join.Containment = match.Resolution.Report.MatchRate
+		join.EntityResolution, join.resolution = &match.Resolution.Report, match.Resolution
+		return join, 0.9 * join.Containment, nil
+	}
+	if parentTime != "" && candidateTime != "" {
+		join.TimeColumn, join.ParentTimeColumn, join.Frequency = candidateTime, parentTime, c.frequency
+		return join, temporalJoinScore, nil
+	}
+	return join, 0, nil
+}
+
+func (p *CrossDatasetPlan) skip(ds *domainDataset.Dataset, reason string) {
+	p.Skipped = append(p.Skipped, SkippedDataset{DatasetID: ds.ID, DatasetName: ds.GetDisplayName(), Reason: reason})
+}
+
+// combinedSide is a dataset read for combining, with the names its columns take in the table
+type combinedSide struct {
+	ds      *domainDataset.Dataset
+	values  map[string][]string
+	rows    int
+	renamed map[string]string // dataset column -> combined column
+}
+
+// Combine reads the datasets of plan and joins them. Every row of the base dataset is kept;
+// a joined dataset adds its columns, except the key and time columns it was matched on, which
+// repeat its parent's. Where several of its rows match one row, numeric values are averaged
+// and the first non-empty value of other columns is taken. PII columns excluded from matrices
+// are left out, and column names another dataset already uses are prefixed with the name of
+// their dataset.
+func (c *CrossDatasetCombiner) Combine(ctx context.Context, datasets []*domainDataset.Dataset, plan *CrossDatasetPlan) (*CombinedTable, error) {
+	byID := make(map[core.ID]*domainDataset.Dataset, len(datasets))
+	for _, ds := range datasets {
+		byID[ds.ID] = ds
+	}
+	base := byID[plan.BaseID]
+	if base == nil {
+		return nil, fmt.Errorf("base dataset %s is not among the datasets", plan.BaseID)
+	}
+	locale := tableLocale(base)
+
+	table := &CombinedTable{
+		Data:    &excel.ExcelData{Locale: locale},
+		Origins: make(map[string]domainDataset.ColumnOrigin),
+		Plan:    plan,
+	}
+	taken := make(map[string]bool)
+	sides := make(map[core.ID]*combinedSide, len(plan.Joins)+1)
+
+	baseSide, err := c.read(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	sides[base.ID] = baseSide
+	for _, field := range base.Metadata.Fields {
+		if _, ok := baseSide.values[field.Name]; !ok || base.Metadata.ExcludedFields()[field.Name] {
+			continue
+		}
+		table.addColumn(baseSide, field, field.Name, "", false, taken)
+	}
+	table.Data.Rows = make([]excel.RawRowData, baseSide.rows)
+	for i := range table.Data.Rows {
+		row := make(excel.RawRowData, len(baseSide.renamed))
+		for column, combined := range baseSide.renamed {
+			row[combined] = baseSide.values[column][i]
+		}
+		table.Data.Rows[i] = row
+	}
+
+	for i := range plan.Joins {
+		join := &plan.Joins[i]
+		ds, parent := byID[join.DatasetID], sides[join.ParentID]
+		if ds == nil || parent == nil {
+			return nil, fmt.Errorf("join of dataset %s onto %s has no data", join.DatasetID, join.ParentID)
+		}
+		side, err := c.read(ctx, ds)
+		if err != nil {
+			return nil, err
+		}
+		sides[ds.ID] = side
+		// The matched columns repeat the parent's, so they take its names
+		if join.Column != "" {
+			side.renamed[join.Column] = parent.renamed[join.ParentColumn]
+		}
+		if join.TimeColumn != "" {
+			side.renamed[join.TimeColumn] = parent.renamed[join.ParentTimeColumn]
+		}
+		excluded := ds.Metadata.ExcludedFields()
+		var added []string
+		for _, field := range ds.Metadata.Fields {
+			if _, ok := side.values[field.Name]; !ok || excluded[field.Name] || field.Name == join.Column || field.Name == join.TimeColumn {
+				continue
+			}
+			added = append(added, field.Name)
+		}
+		// Resolved names match on the entity they resolved to
+		parentKey, sideKey := func(value string) string { return value }, func(value string) string { return value }
+		if join.resolution != nil {
+			parentKey, sideKey = join.resolution.SourceID, join.resolution.TargetID
+		}
+		combinedKey := func(row excel.RawRowData) string {
+			return matchKey(parentKey(row[side.renamed[join.Column]]), row[side.renamed[join.TimeColumn]], join, locale, tableZone(base))
+		}
+		index := make(map[string][]int)
+		for r := 0; r < side.rows; r++ {
+			key := matchKey(sideKey(side.value(join.Column, r)), side.value(join.TimeColumn, r), join, locale, tableZone(ds))
+			if key != "" {
+				index[key] = append(index[key], r)
+			}
+		}
+
+		// Counted first, so the origins of its columns can say whether values were averaged
+		for _, row := range table.Data.Rows {
+			if matches := index[combinedKey(row)]; len(matches) > 1 {
+				join.AggregatedRows++
+			}
+		}
+		for _, name := range added {
+			field := fieldByName(ds, name)
+			table.addColumn(side, field, name, join.Describe(), join.AggregatedRows > 0, taken)
+		}
+		if join.resolution != nil {
+			table.addEntityColumn(parent, join, taken)
+		}
+		for _, row := range table.Data.Rows {
+			matches := index[combinedKey(row)]
+			if len(matches) == 0 {
+				continue
+			}
+			join.MatchedRows++
+			for _, name := range added {
+				row[side.renamed[name]] = combineValues(side.values[name], matches, locale)
+			}
+		}
+		if join.MatchedRows == 0 {
+			log.Printf("[CrossDataset] No rows of %s matched on %s", join.DatasetName, join.Describe())
+		}
+	}
+	return table, nil
+}
+
+// addEntityColumn adds the entity each row's name in the parent resolved to, so rows of names
+// spelled differently can be told to be one entity
+func (t *CombinedTable) addEntityColumn(parent *combinedSide, join *CrossDatasetJoin, taken map[string]bool) {
+	column := join.ParentColumn + "_entity_id"
+	t.addColumn(parent, domainDataset.FieldInfo{Name: column}, column, join.Describe(), false, taken)
+	name := parent.renamed[column]
+	origin := t.Origins[name]
+	origin.Column = join.ParentColumn
+	t.Origins[name] = origin
+	if t.identifiers == nil {
+		t.identifiers = make(map[string]bool)
+	}
+	t.identifiers[name] = true
+	join.EntityColumn = name
+
+	names := parent.renamed[join.ParentColumn]
+	for _, row := range t.Data.Rows {
+		row[name] = join.resolution.SourceID(row[names])
+	}
+}
+
+func (c *CrossDatasetCombiner) read(ctx context.Context, ds *domainDataset.Dataset) (*combinedSide, error) {
+	names := make([]string, len(ds.Metadata.Fields))
+	for i, field := range ds.Metadata.Fields {
+		names[i] = field.Name
+	}
+	values, err := c.reader.ReadColumns(ctx, ds, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset %s: %w", ds.ID, err)
+	}
+	side := &combinedSide{ds: ds, values: values, renamed: make(map[string]string)}
+	for _, column := range values {
+		side.rows = max(side.rows, len(column))
+	}
+	return side, nil
+}
+
+func (s *combinedSide) value(column string, row int) string {
+	if column == "" || row >= len(s.values[column]) {
+		return ""
+	}
+	return s.values[column][row]
+}
+
+// addColumn names a column of side in the table and records its origin
+func (t *CombinedTable) addColumn(side *combinedSide, field domainDataset.FieldInfo, column, joinedOn string, aggregated bool, taken map[string]bool) {
+	name := column
+	if taken[name] {
+		prefix, _ := domainDataset.NormalizeColumnName(side.ds.GetDisplayName())
+		name = prefix + combinedColumnSeparator + column
+		for n := 2; taken[name]; n++ {
+			name = fmt.Sprintf("%s%s%s_%d", prefix, combinedColumnSeparator, column, n)
+		}
+	}
+	taken[name] = true
+	side.renamed[column] = name
+	t.Data.Headers = append(t.Data.Headers, name)
+	t.Data.Columns = append(t.Data.Columns, domainDataset.ColumnName{Key: name, Original: field.DisplayName(), Unit: field.Unit})
+	t.Origins[name] = domainDataset.ColumnOrigin{
+		DatasetID:   side.ds.ID,
+		DatasetName: side.ds.GetDisplayName(),
+		Column:      column,
+		JoinedOn:    joinedOn,
+		Aggregated:  aggregated,
+	}
+}
+
+// matchKey is the value rows of a join match on: the normalized key, the period of the
+// timestamp, or both; empty when a value it needs is missing or unreadable
+func matchKey(key, timestamp string, join *CrossDatasetJoin, locale domainDataset.Locale, zone core.TimeZone) string {
+	var parts []string
+	if join.Column != "" {
+		key = normalizeKeyValue(key)
+		if key == "" {
+			return ""
+		}
+		parts = append(parts, key)
+	}
+	if join.TimeColumn != "" {
+		t, ok := locale.ParseDateIn(timestamp, zone.Location())
+		if !ok {
+			return ""
+		}
+		parts = append(parts, timePeriod(t.UTC(), join.Frequency))
+	}
+	return strings.Join(parts, "|")
+}
+
+// timePeriod names the period of frequency t falls in
+func timePeriod(t time.Time, frequency string) string {
+	switch frequency {
+	case AlignHour:
+		return t.Format("2006-01-02T15")
+	case AlignWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case AlignMonth:
+		return t.Format("2006-01")
+	case AlignYear:
+		return t.Format("2006")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// combineValues is the value of a column over the matched rows: the mean when every
+// non-empty value is a number, the first non-empty value otherwise
+func combineValues(values []string, rows []int, locale domainDataset.Locale) string {
+	if len(rows) == 1 {
+		return values[rows[0]]
+	}
+	var first string
+	var sum float64
+	count, numeric := 0, true
+	for _, r := range rows {
+		value := strings.TrimSpace(values[r])
+		if value == "" {
+			continue
+		}
+		if first == "" {
+			first = value
+		}
+		if number, ok := locale.ParseNumber(value); ok && numeric {
+			sum += number
+			count++
+		} else {
+			numeric = false
+		}
+	}
+	if !numeric || count == 0 {
+		return first
+	}
+	mean := strconv.FormatFloat(sum/float64(count), 'f', -1, 64)
+	return strings.Replace(mean, ".", locale.Decimal, 1)
+}
+
+// timeColumn returns the first date column of a dataset, or empty
+func timeColumn(ds *domainDataset.Dataset) string {
+	for _, field := range ds.Metadata.Fields {
+		if field.DataType == "date" {
+			return field.Name
+		}
+	}
+	return ""
+}
+
+func fieldByName(ds *domainDataset.Dataset, name string) domainDataset.FieldInfo {
+	for _, field := range ds.Metadata.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return domainDataset.FieldInfo{Name: name}
+}
+
+func tableLocale(ds *domainDataset.Dataset) domainDataset.Locale {
+	if ds.Metadata.Locale != nil {
+		return *ds.Metadata.Locale
+	}
+	return domainDataset.DefaultLocale
+}
+
+func tableZone(ds *domainDataset.Dataset) core.TimeZone {
+	if ds.Metadata.TimeZone != nil {
+		return *ds.Metadata.TimeZone
+	}
+	return core.UTCZone()
+}
+
+// CombinedMatrixResolver resolves matrices from a combined table and records on each column
+// the dataset it came from
+type CombinedMatrixResolver struct {
+	table    *CombinedTable
+	resolver ports.MatrixResolverPort
+}
+
+// NewCombinedMatrixResolver creates a resolver over table
+func NewCombinedMatrixResolver(table *CombinedTable) *CombinedMatrixResolver {
+	config := excel.DefaultExcelConfig()
+	config.Enabled = true
+	return &CombinedMatrixResolver{
+		table:    table,
+		resolver: excel.NewExcelMatrixResolverAdapterWithData(config, table.Data),
+	}
+}
+
+// ResolveMatrix resolves the requested variables and sets the origin of every column
+func (r *CombinedMatrixResolver) ResolveMatrix(ctx context.Context, req ports.MatrixResolutionRequest) (*domainDataset.MatrixBundle, error) {
+	bundle, err := r.resolver.ResolveMatrix(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	for i, meta := range bundle.ColumnMeta {
+		if origin, ok := r.table.Origins[string(meta.VariableKey)]; ok {
+			bundle.ColumnMeta[i].Origin = &origin
+		}
+	}
+	return bundle, nil
+}
@@ -0,0 +1,418 @@
+package dataset
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Encrypted dataset file layout (envelope encryption):
+//
+//	magic | uint32 header length | JSON header | frames...
+//
+// The header carries the key-encryption key ID and the per-file data key
+// wrapped with that KEK. Each frame is a uint32 length followed by an AES-GCM
+// sealed chunk; the chunk index and a final-frame flag are bound as additional
+// data so frames cannot be reordered or the file silently truncated.
+const (
+	encryptedFileMagic = "GHENC1\n"
+	dataKeySize        = 32
+	maxFrameSize       = 16 * 1024 * 1024
+)
+
+// ErrEncryptionKeyNotFound is returned when a file references a key the provider cannot supply
+var ErrEncryptionKeyNotFound = errors.New("encryption key not found")
+
+// KeyProvider supplies key-encryption keys (KEKs) for envelope encryption.
+// Implementations back onto a secrets manager (Vault, KMS, env) and must keep
+// retired keys readable until every file has been rotated off them.
+type KeyProvider interface {
+	// ActiveKey returns the key new files are wrapped with
+	ActiveKey(ctx context.Context) (keyID string, key []byte, err error)
+	// Key returns a specific key by ID, including retired keys
+	Key(ctx context.Context, keyID string) ([]byte, error)
+}
+
+// StaticKeyProvider serves KEKs from an in-memory keyring
+type StaticKeyProvider struct {
+	activeKeyID string
+	keys        map[string][]byte
+}
+
+// NewStaticKeyProvider creates a key provider from a keyring and the ID of the active key
+func NewStaticKeyProvider(activeKeyID string, keys map[string][]byte) (*StaticKeyProvider, error) {
+	if _, ok := keys[activeKeyID]; !ok {
+		return nil, fmt.Errorf("active key %q not present in keyring", activeKeyID)
+	}
+	for id, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key %q must be 32 bytes for AES-256, got %d", id, len(key))
+		}
+	}
+	return &StaticKeyProvider{activeKeyID: activeKeyID, keys: keys}, nil
+}
+
+// KeyProviderFromEnv builds a provider from DATASET_ENCRYPTION_KEYS ("id:base64,id:base64")
+// or DATASET_ENCRYPTION_KEYS_DIR, and DATASET_ENCRYPTION_ACTIVE_KEY. Returns nil when
+// encryption is not configured.
+func KeyProviderFromEnv() (KeyProvider, error) {
+	raw := strings.TrimSpace(os.Getenv("DATASET_ENCRYPTION_KEYS"))
+	dir := strings.TrimSpace(os.Getenv("DATASET_ENCRYPTION_KEYS_DIR"))
+	activeID := os.Getenv("DATASET_ENCRYPTION_ACTIVE_KEY")
+	switch {
+	case raw != "" && dir != "":
+		return nil, fmt.Errorf("set DATASET_ENCRYPTION_KEYS or DATASET_ENCRYPTION_KEYS_DIR, not both")
+	case dir != "":
+		if activeID == "" {
+			return nil, fmt.Errorf("DATASET_ENCRYPTION_KEYS_DIR needs DATASET_ENCRYPTION_ACTIVE_KEY")
+		}
+		keys, err := keysFromDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		return NewStaticKeyProvider(activeID, keys)
+	case raw == "":
+		return nil, nil
+	}
+
+	keys := make(map[string][]byte)
+	var firstID string
+	for _, entry := range strings.Split(raw, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("invalid DATASET_ENCRYPTION_KEYS entry %q (expected id:base64)", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 for key %q: %w", id, err)
+		}
+		keys[id] = key
+		if firstID == "" {
+			firstID = id
+		}
+	}
+
+	if activeID == "" {
+		activeID = firstID
+	}
+	return NewStaticKeyProvider(activeID, keys)
+}
+
+// keysFromDir reads a keyring a secrets manager mounts as files, as Docker and Kubernetes
+// secrets or a Vault agent template do: each file is named by its key ID and holds the
+// base64 key. Hidden entries, such as the timestamped directories of a Kubernetes mount,
+// are skipped.
+func keysFromDir(dir string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DATASET_ENCRYPTION_KEYS_DIR: %w", err)
+	}
+	keys := make(map[string][]byte)
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		encoded, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %q: %w", entry.Name(), err)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 for key %q: %w", entry.Name(), err)
+		}
+		keys[entry.Name()] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("DATASET_ENCRYPTION_KEYS_DIR %s holds no keys", dir)
+	}
+	return keys, nil
+}
+
+// ActiveKey returns the active KEK
+func (p *StaticKeyProvider) ActiveKey(ctx context.Context) (string, []byte, error) {
+	return p.activeKeyID, p.keys[p.activeKeyID], nil
+}
+
+// Key returns the KEK with the given ID
+func (p *StaticKeyProvider) Key(ctx context.Context, keyID string) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrEncryptionKeyNotFound, keyID)
+	}
+	return key, nil
+}
+
+// encryptionHeader is the plaintext JSON header of an encrypted file
+type encryptionHeader struct {
+	KeyID       string `json:"key_id"`
+	WrappedKey  string `json:"wrapped_key"` // data key sealed with the KEK (nonce-prefixed, base64)
+	NoncePrefix string `json:"nonce_prefix"`
+}
+
+// encryptStream writes src to dst as an encrypted file under the provider's active key
+func encryptStream(ctx context.Context, provider KeyProvider, dst io.Writer, src io.Reader, chunkSize int) error {
+	keyID, kek, err := provider.ActiveKey(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active encryption key: %w", err)
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return fmt.Errorf("failed to generate data key: %w", err)
+	}
+	wrapped, err := sealWithKey(kek, dataKey, []byte(keyID))
+	if err != nil {
+		return fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	noncePrefix := make([]byte, 4)
+	if _, err := rand.Read(noncePrefix); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	header, err := json.Marshal(encryptionHeader{
+		KeyID:       keyID,
+		WrappedKey:  base64.StdEncoding.EncodeToString(wrapped),
+		NoncePrefix: base64.StdEncoding.EncodeToString(noncePrefix),
+	})
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(dst)
+	w.WriteString(encryptedFileMagic)
+	binary.Write(w, binary.BigEndian, uint32(len(header)))
+	w.Write(header)
+
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return err
+	}
+
+	if chunkSize <= 0 || chunkSize > maxFrameSize {
+		chunkSize = 1024 * 1024
+	}
+	// Read one chunk ahead so the last frame can be flagged as final
+	current := make([]byte, chunkSize)
+	next := make([]byte, chunkSize)
+	n, err := io.ReadFull(src, current)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return fmt.Errorf("failed to read source: %w", err)
+	}
+
+	for index := uint64(0); ; index++ {
+		m, readErr := io.ReadFull(src, next)
+		if readErr != nil && readErr != io.ErrUnexpectedEOF && readErr != io.EOF {
+			return fmt.Errorf("failed to read source: %w", readErr)
+		}
+		final := m == 0
+
+		sealed := aead.Seal(nil, frameNonce(noncePrefix, index), current[:n], frameAAD(index, final))
+		binary.Write(w, binary.BigEndian, uint32(len(sealed)))
+		if _, err := w.Write(sealed); err != nil {
+			return fmt.Errorf("failed to write encrypted frame: %w", err)
+		}
+
+		if final {
+			break
+		}
+		current, next = next, current
+		n = m
+	}
+
+	return w.Flush()
+}
+
+// decryptingReader streams plaintext out of an encrypted file
+type decryptingReader struct {
+	src         *bufio.Reader
+	closer      io.Closer
+	aead        cipher.AEAD
+	noncePrefix []byte
+	index       uint64
+	buf         bytes.Buffer
+	done        bool
+}
+
+// newDecryptingReader parses the header of an encrypted file and returns a plaintext reader
+func newDecryptingReader(ctx context.Context, provider KeyProvider, src io.ReadCloser) (io.ReadCloser, error) {
+	r := bufio.NewReader(src)
+	magic := make([]byte, len(encryptedFileMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != encryptedFileMagic {
+		return nil, fmt.Errorf("not an encrypted dataset file")
+	}
+
+	header, err := readEncryptionHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	kek, err := provider.Key(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(header.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wrapped key: %w", err)
+	}
+	dataKey, err := openWithKey(kek, wrapped, []byte(header.KeyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	noncePrefix, err := base64.StdEncoding.DecodeString(header.NoncePrefix)
+	if err != nil || len(noncePrefix) != 4 {
+		return nil, fmt.Errorf("invalid nonce prefix")
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptingReader{src: r, closer: src, aead: aead, noncePrefix: noncePrefix}, nil
+}
+
+// Read implements io.Reader
+func (d *decryptingReader) Read(p []byte) (int, error) {
+	for d.buf.Len() == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.readFrame(); err != nil {
+			return 0, err
+		}
+	}
+	return d.buf.Read(p)
+}
+
+func (d *decryptingReader) readFrame() error {
+	var size uint32
+	if err := binary.Read(d.src, binary.BigEndian, &size); err != nil {
+		return fmt.Errorf("encrypted file truncated: %w", io.ErrUnexpectedEOF)
+	}
+	if size > maxFrameSize+uint32(d.aead.Overhead()) {
+		return fmt.Errorf("encrypted frame too large: %d bytes", size)
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(d.src, sealed); err != nil {
+		return fmt.Errorf("encrypted file truncated: %w", io.ErrUnexpectedEOF)
+	}
+
+	nonce := frameNonce(d.noncePrefix, d.index)
+	plain, err := d.aead.Open(nil, nonce, sealed, frameAAD(d.index, false))
+	if err != nil {
+		plain, err = d.aead.Open(nil, nonce, sealed, frameAAD(d.index, true))
+		if err != nil {
+			return fmt.Errorf("encrypted frame %d failed authentication", d.index)
+		}
+		d.done = true
+	}
+	d.index++
+	d.buf.Write(plain)
+	return nil
+}
+
+// Close closes the underlying file
+func (d *decryptingReader) Close() error {
+	return d.closer.Close()
+}
+
+// readEncryptionHeader reads the length-prefixed JSON header following the magic
+func readEncryptionHeader(r io.Reader) (*encryptionHeader, error) {
+	var headerLen uint32
+	if err := binary.Read(r, binary.BigEndian, &headerLen); err != nil {
+		return nil, fmt.Errorf("failed to read encryption header: %w", err)
+	}
+	if headerLen > 64*1024 {
+		return nil, fmt.Errorf("encryption header too large: %d bytes", headerLen)
+	}
+	raw := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, raw); err != nil {
+		return nil, fmt.Errorf("failed to read encryption header: %w", err)
+	}
+	var header encryptionHeader
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("invalid encryption header: %w", err)
+	}
+	return &header, nil
+}
+
+// inspectEncryptedFile reports whether a file is encrypted and, if so, which key wraps it
+func inspectEncryptedFile(filePath string) (encrypted bool, keyID string, err error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return false, "", err
+	}
+	defer f.Close()
+
+	magic := make([]byte, len(encryptedFileMagic))
+	if _, err := io.ReadFull(f, magic); err != nil || string(magic) != encryptedFileMagic {
+		return false, "", nil
+	}
+	header, err := readEncryptionHeader(f)
+	if err != nil {
+		return true, "", err
+	}
+	return true, header.KeyID, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealWithKey encrypts plaintext with a random nonce, returning nonce||ciphertext
+func sealWithKey(key, plaintext, aad []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// openWithKey reverses sealWithKey
+func openWithKey(key, sealed, aad []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], aad)
+}
+
+// frameNonce derives a unique 12-byte nonce from the file's random prefix and the frame index
+func frameNonce(prefix []byte, index uint64) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint64(nonce[4:], index)
+	return nonce
+}
+
+func frameAAD(index uint64, final bool) []byte {
+	aad := make([]byte, 9)
+	binary.BigEndian.PutUint64(aad, index)
+	if final {
+		aad[8] = 1
+	}
+	return aad
+}
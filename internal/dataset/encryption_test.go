@@ -0,0 +1,144 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testKeyProvider(t *testing.T, activeKeyID string) *StaticKeyProvider {
+	t.Helper()
+	provider, err := NewStaticKeyProvider(activeKeyID, map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": bytes.Repeat([]byte{2}, 32),
+	})
+	if err != nil {
+		t.Fatalf("failed to create key provider: %v", err)
+	}
+	return provider
+}
+
+// TestEncryptedRoundTrip verifies multi-frame encryption decrypts to the original bytes
+func TestEncryptedRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	provider := testKeyProvider(t, "k1")
+	plaintext := bytes.Repeat([]byte("entity_id,revenue\n42,1000\n"), 1000)
+
+	var sealed bytes.Buffer
+	if err := encryptStream(ctx, provider, &sealed, bytes.NewReader(plaintext), 4096); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if bytes.Contains(sealed.Bytes(), []byte("revenue")) {
+		t.Fatal("ciphertext contains plaintext")
+	}
+
+	reader, err := newDecryptingReader(ctx, provider, io.NopCloser(bytes.NewReader(sealed.Bytes())))
+	if err != nil {
+		t.Fatalf("failed to open encrypted stream: %v", err)
+	}
+	decrypted, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Fatal("decrypted bytes do not match original")
+	}
+
+	// Dropping the final frame must be detected as truncation
+	truncated := sealed.Bytes()[:sealed.Len()-100]
+	reader, err = newDecryptingReader(ctx, provider, io.NopCloser(bytes.NewReader(truncated)))
+	if err != nil {
+		t.Fatalf("failed to open truncated stream: %v", err)
+	}
+	if _, err := io.ReadAll(reader); err == nil {
+		t.Fatal("expected error reading truncated file")
+	}
+}
+
+// TestRotateKeys verifies plaintext and retired-key files move to the active key
+func TestRotateKeys(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	content := []byte("a,b\n1,2\n")
+
+	plainPath := filepath.Join(dir, "plain.csv")
+	if err := os.WriteFile(plainPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	oldPath := filepath.Join(dir, "old.csv")
+	var sealed bytes.Buffer
+	if err := encryptStream(ctx, testKeyProvider(t, "k1"), &sealed, bytes.NewReader(content), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(oldPath, sealed.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultStorageConfig()
+	config.BasePath = dir
+	config.KeyProvider = testKeyProvider(t, "k2")
+	storage := NewLocalFileStorage(config)
+
+	report, err := storage.RotateKeys(ctx)
+	if err != nil {
+		t.Fatalf("rotation failed: %v", err)
+	}
+	if report.Rotated != 1 || report.Encrypted != 1 || len(report.Failed) != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	for _, path := range []string{plainPath, oldPath} {
+		encrypted, keyID, err := inspectEncryptedFile(path)
+		if err != nil || !encrypted || keyID != "k2" {
+			t.Fatalf("%s: encrypted=%v key=%q err=%v", path, encrypted, keyID, err)
+		}
+		reader, err := storage.GetReader(ctx, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := io.ReadAll(reader)
+		reader.Close()
+		if !bytes.Equal(got, content) {
+			t.Fatalf("%s: content mismatch after rotation", path)
+		}
+	}
+}
+
+// TestKeyProviderFromDir verifies a keyring mounted as one file per key, skipping the hidden
+// entries of a Kubernetes secret mount
+func TestKeyProviderFromDir(t *testing.T) {
+	dir := t.TempDir()
+	for id, fill := range map[string]byte{"k1": 1, "k2": 2} {
+		encoded := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{fill}, 32))
+		if err := os.WriteFile(filepath.Join(dir, id), []byte(encoded+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "..data"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DATASET_ENCRYPTION_KEYS", "")
+	t.Setenv("DATASET_ENCRYPTION_KEYS_DIR", dir)
+
+	t.Setenv("DATASET_ENCRYPTION_ACTIVE_KEY", "")
+	if _, err := KeyProviderFromEnv(); err == nil {
+		t.Fatal("a keys directory without an active key should be refused")
+	}
+
+	t.Setenv("DATASET_ENCRYPTION_ACTIVE_KEY", "k2")
+	provider, err := KeyProviderFromEnv()
+	if err != nil {
+		t.Fatalf("KeyProviderFromEnv: %v", err)
+	}
+	ctx := context.Background()
+	if id, key, _ := provider.ActiveKey(ctx); id != "k2" || key[0] != 2 {
+		t.Errorf("active key is %s", id)
+	}
+	if key, err := provider.Key(ctx, "k1"); err != nil || key[0] != 1 {
+		t.Errorf("retired key k1 not readable: %v", err)
+	}
+}
@@ -0,0 +1,531 @@
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	domainDataset "gohypo/domain/dataset"
+)
+
+// Blocking keys limiting the values an entity name is compared with
+const (
+	BlockPrefix   = "prefix"   // names sharing their first letters
+	BlockPhonetic = "phonetic" // names that sound alike
+	BlockToken    = "token"    // names sharing a word
+)
+
+// Methods an entity match was made by
+const (
+	MatchExact    = "exact"    // the normalized names are equal
+	MatchPhonetic = "phonetic" // the names sound alike and are close in spelling
+	MatchFuzzy    = "fuzzy"    // the names are close in spelling
+)
+
+const (
+	// phoneticAllowance is how far below the threshold names that sound alike may score
+	phoneticAllowance = 0.1
+
+	// ambiguityMargin is the score gap under which two candidate entities cannot be told apart
+	ambiguityMargin = 0.02
+
+	// maxBlockSize bounds the names one blocking key compares with; a larger block, such as
+	// every name with a common word, tells too little to be worth comparing
+	maxBlockSize = 1000
+
+	// maxReviewMatches bounds the weakest matches a report lists for review
+	maxReviewMatches = 10
+
+	// maxEntityColumns bounds the name columns of each dataset tried against the other's
+	maxEntityColumns = 4
+)
+
+// legalSuffixes are words dropped from names, so "Acme Corp." and "ACME Corporation" match
+var legalSuffixes = map[string]bool{
+	"the": true, "and": true, "inc": true, "incorporated": true, "llc": true, "ltd": true,
+	"limited": true, "co": true, "corp": true, "corporation": true, "company": true,
+	"gmbh": true, "ag": true, "sa": true, "plc": true, "bv": true, "nv": true, "pty": true,
+}
+
+// entityNameWords mark columns holding the names of entities, such as customer_name or client
+var entityNameWords = []string{
+	"name", "client", "customer", "company", "account", "vendor", "supplier", "partner",
+	"organization", "organisation", "business", "merchant", "brand", "member", "patient",
+	"employee", "person", "contact", "store",
+}
+
+// EntityResolutionConfig tunes how names are matched
+type EntityResolutionConfig struct {
+	// Threshold is the Jaro-Winkler similarity from which two names are the same entity
+	Threshold float64 `json:"threshold"`
+	// Blocking lists the keys candidates must share one of to be compared
+	Blocking []string `json:"blocking"`
+	// PrefixLength is the letters a prefix block compares
+	PrefixLength int `json:"prefix_length"`
+}
+
+// DefaultEntityResolutionConfig returns the settings workspace sweeps resolve entities with
+func DefaultEntityResolutionConfig() EntityResolutionConfig {
+	return EntityResolutionConfig{Threshold: 0.9, Blocking: []string{BlockPhonetic, BlockPrefix}, PrefixLength: 3}
+}
+
+// Validate checks the threshold is a similarity and every blocking key is known
+func (c EntityResolutionConfig) Validate() error {
+	if c.Threshold <= 0 || c.Threshold > 1 {
+		return fmt.Errorf("threshold must be above 0 and at most 1, got %g", c.Threshold)
+	}
+	if len(c.Blocking) == 0 {
+		return fmt.Errorf("at least one blocking key is needed")
+	}
+	for _, key := range c.Blocking {
+		switch key {
+		case BlockPrefix, BlockPhonetic, BlockToken:
+		default:
+			return fmt.Errorf("unknown blocking key %q (use %s, %s or %s)", key, BlockPrefix, BlockPhonetic, BlockToken)
+		}
+	}
+	if c.PrefixLength < 1 {
+		return fmt.Errorf("prefix_length must be at least 1")
+	}
+	return nil
+}
+
+// EntityMatch is a name of the target resolved to a name of the source
+type EntityMatch struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Score  float64 `json:"score"`
+	Method string  `json:"method"`
+}
+
+// EntityMatchReport measures how well the names of two columns resolved to shared entities
+type EntityMatchReport struct {
+	SourceDistinct int     `json:"source_distinct"`
+	TargetDistinct int     `json:"target_distinct"`
+	Exact          int     `json:"exact"`
+	Phonetic       int     `json:"phonetic"`
+	Fuzzy          int     `json:"fuzzy"`
+	Ambiguous      int     `json:"ambiguous"` // names close to several entities, left unmatched
+	Unmatched      int     `json:"unmatched"`
+	MatchRate      float64 `json:"match_rate"` // share of the target's names resolved
+	// Comparisons made, against the pairs comparing every name with every other would take
+	Comparisons    int                    `json:"comparisons"`
+	AllPairs       int                    `json:"all_pairs"`
+	Config         EntityResolutionConfig `json:"config"`
+	WeakestMatches []EntityMatch          `json:"weakest_matches,omitempty"` // lowest scored non-exact matches, for review
+}
+
+// EntityResolution assigns entity IDs to the names of a source and a target column. Target
+// names resolved to a source entity share its ID; the others get an ID of their own.
+type EntityResolution struct {
+	SourceIDs map[string]string `json:"-"`
+	TargetIDs map[string]string `json:"-"`
+	Report    EntityMatchReport `json:"report"`
+}
+
+// SourceID returns the entity ID of a name of the source column, or empty
+func (r *EntityResolution) SourceID(name string) string { return r.SourceIDs[name] }
+
+// TargetID returns the entity ID of a name of the target column, or empty
+func (r *EntityResolution) TargetID(name string) string { return r.TargetIDs[name] }
+
+// EntityResolver matches names that refer to the same entity despite differences in case,
+// punctuation, legal suffixes, word order and spelling
+type EntityResolver struct {
+	config EntityResolutionConfig
+}
+
+// NewEntityResolver creates a resolver with config
+func NewEntityResolver(config EntityResolutionConfig) *EntityResolver {
+	return &EntityResolver{config: config}
+}
+
+// entityName is a distinct name with its comparison forms
+type entityName struct {
+	raw        string
+	normalized string
+	phonetic   string
+}
+
+// Resolve resolves the names of target to those of source. Names equal once normalized match
+// exactly; the others are compared with the source names sharing a blocking key and take the
+// best scoring entity at or above the threshold, unless another entity scores as well.
+func (r *EntityResolver) Resolve(source, target []string) *EntityResolution {
+	resolution := &EntityResolution{
+		SourceIDs: make(map[string]string),
+		TargetIDs: make(map[string]string),
+		Report:    EntityMatchReport{Config: r.config},
+	}
+	sourceNames := distinctNames(source)
+	targetNames := distinctNames(target)
+	report := &resolution.Report
+	report.SourceDistinct, report.TargetDistinct = len(sourceNames), len(targetNames)
+	report.AllPairs = len(sourceNames) * len(targetNames)
+
+	// Source names equal once normalized are one entity
+	entities := make(map[string]string) // normalized -> entity ID
+	var entityNames []entityName
+	for _, name := range sourceNames {
+		id, ok := entities[name.normalized]
+		if !ok {
+			id = entityID(name.normalized)
+			entities[name.normalized] = id
+			entityNames = append(entityNames, name)
+		}
+		resolution.SourceIDs[name.raw] = id
+	}
+	blocks := make(map[string][]int)
+	for i, name := range entityNames {
+		for _, key := range r.blockingKeys(name) {
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+
+	var review []EntityMatch
+	for _, name := range targetNames {
+		if id, ok := entities[name.normalized]; ok {
+			resolution.TargetIDs[name.raw] = id
+			report.Exact++
+			continue
+		}
+
+		compared := make(map[int]bool)
+		best, second := EntityMatch{}, 0.0
+		bestIndex := -1
+		for _, key := range r.blockingKeys(name) {
+			block := blocks[key]
+			if len(block) > maxBlockSize {
+				continue
+			}
+			for _, i := range block {
+				if compared[i] {
+					continue
+				}
+				compared[i] = true
+				report.Comparisons++
+				candidate := entityNames[i]
+				score := jaroWinkler(name.normalized, candidate.normalized)
+				method := MatchFuzzy
+				if name.phonetic == candidate.phonetic {
+					method = MatchPhonetic
+				}
+				if score < r.acceptance(method) {
+					continue
+				}
+				if score > best.Score {
+					second = best.Score
+					best = EntityMatch{Source: candidate.raw, Target: name.raw, Score: score, Method: method}
+					bestIndex = i
+				} else if score > second {
+					second = score
+				}
+			}
+		}
+
+		switch {
+		case bestIndex < 0:
+			report.Unmatched++
+			resolution.TargetIDs[name.raw] = entityID(name.normalized)
+		case best.Score-second < ambiguityMargin:
+			report.Ambiguous++
+			resolution.TargetIDs[name.raw] = entityID(name.normalized)
+		default:
+			resolution.TargetIDs[name.raw] = entities[entityNames[bestIndex].normalized]
+			if best.Method == MatchPhonetic {
+				report.Phonetic++
+			} else {
+				report.Fuzzy++
+			}
+			review = append(review, best)
+		}
+	}
+
+	if report.TargetDistinct > 0 {
+		report.MatchRate = float64(report.Exact+report.Phonetic+report.Fuzzy) / float64(report.TargetDistinct)
+	}
+	sort.SliceStable(review, func(i, j int) bool { return review[i].Score < review[j].Score })
+	if len(review) > maxReviewMatches {
+		review = review[:maxReviewMatches]
+	}
+	report.WeakestMatches = review
+	return resolution
+}
+
+// acceptance is the lowest score a match by method is accepted at
+func (r *EntityResolver) acceptance(method string) float64 {
+	if method == MatchPhonetic {
+		return r.config.Threshold - phoneticAllowance
+	}
+	return r.config.Threshold
+}
+
+// blockingKeys returns the blocks a name is compared within
+func (r *EntityResolver) blockingKeys(name entityName) []string {
+	var keys []string
+	for _, blocking := range r.config.Blocking {
+		switch blocking {
+		case BlockPrefix:
+			prefix := []rune(name.normalized)
+			if len(prefix) > r.config.PrefixLength {
+				prefix = prefix[:r.config.PrefixLength]
+			}
+			keys = append(keys, "p:"+string(prefix))
+		case BlockPhonetic:
+			keys = append(keys, "s:"+name.phonetic)
+		case BlockToken:
+			for _, token := range strings.Fields(name.normalized) {
+				if len(token) >= 3 {
+					keys = append(keys, "t:"+token)
+				}
+			}
+		}
+	}
+	return keys
+}
+
+func distinctNames(values []string) []entityName {
+	seen := make(map[string]bool)
+	var names []entityName
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		normalized := NormalizeEntityName(value)
+		if normalized == "" {
+			continue
+		}
+		names = append(names, entityName{raw: value, normalized: normalized, phonetic: PhoneticKey(normalized)})
+	}
+	return names
+}
+
+// entityID is the ID of the entity a normalized name stands for
+func entityID(normalized string) string {
+	return fmt.Sprintf("ent_%016x", hashValue(normalized))
+}
+
+// NormalizeEntityName reduces a name to lower-case words of letters and digits, without
+// legal suffixes, in sorted order, so "Smith, John" and "john smith" are equal
+func NormalizeEntityName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := words[:0]
+	for _, word := range words {
+		if !legalSuffixes[word] {
+			kept = append(kept, word)
+		}
+	}
+	if len(kept) == 0 {
+		// A name of suffixes only, such as "The Company", is kept as it is
+		kept = words
+	}
+	sort.Strings(kept)
+	return strings.Join(kept, " ")
+}
+
+// PhoneticKey returns the Soundex codes of the words of a normalized name
+func PhoneticKey(normalized string) string {
+	words := strings.Fields(normalized)
+	codes := make([]string, len(words))
+	for i, word := range words {
+		codes[i] = soundex(word)
+	}
+	return strings.Join(codes, " ")
+}
+
+// soundexCodes maps consonants to their Soundex digit; vowels, h, w and y have none
+var soundexCodes = map[rune]byte{
+	'b': '1', 'f': '1', 'p': '1', 'v': '1',
+	'c': '2', 'g': '2', 'j': '2', 'k': '2', 'q': '2', 's': '2', 'x': '2', 'z': '2',
+	'd': '3', 't': '3',
+	'l': '4',
+	'm': '5', 'n': '5',
+	'r': '6',
+}
+
+// soundex codes a word as its first letter and up to three digits for the consonants after
+// it; words not starting with a letter a-z are kept as they are
+func soundex(word string) string {
+	runes := []rune(word)
+	if len(runes) == 0 || runes[0] < 'a' || runes[0] > 'z' {
+		return word
+	}
+	code := []byte{byte(unicode.ToUpper(runes[0]))}
+	last := soundexCodes[runes[0]]
+	for _, r := range runes[1:] {
+		digit, ok := soundexCodes[r]
+		switch {
+		case ok && digit != last:
+			code = append(code, digit)
+			last = digit
+		case !ok && r != 'h' && r != 'w':
+			// A vowel separates consonants of the same code; h and w do not
+			last = 0
+		}
+		if len(code) == 4 {
+			break
+		}
+	}
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+	return string(code)
+}
+
+// jaroWinkler is the Jaro similarity of a and b raised for a common prefix of up to four runes
+func jaroWinkler(a, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+	window := max(len(s1), len(s2))/2 - 1
+	window = max(window, 0)
+	matched1 := make([]bool, len(s1))
+	matched2 := make([]bool, len(s2))
+	matches := 0
+	for i := range s1 {
+		for j := max(0, i-window); j < min(len(s2), i+window+1); j++ {
+			if !matched2[j] && s1[i] == s2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+	transpositions, j := 0, 0
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if s1[i] != s2[j] {
+			transpositions++
+		}
+		j++
+	}
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(s1), len(s2)) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// EntityColumnMatch is the pair of name columns of two datasets whose names resolve to the
+// most shared entities
+type EntityColumnMatch struct {
+	SourceColumn string            `json:"source_column"`
+	TargetColumn string            `json:"target_column"`
+	Resolution   *EntityResolution `json:"resolution"`
+}
+
+// ResolveEntityColumns resolves the names of each name column of target against each of
+// source and returns the pair resolving the largest share of the target's names, or nil when
+// no pair resolves at least minKeyContainment of them. PII columns excluded from matrices
+// are not tried.
+func ResolveEntityColumns(ctx context.Context, reader ColumnValueReader, source, target *domainDataset.Dataset, config EntityResolutionConfig) (*EntityColumnMatch, error) {
+	sourceColumns, targetColumns := entityNameFields(source), entityNameFields(target)
+	if len(sourceColumns) == 0 || len(targetColumns) == 0 {
+		return nil, nil
+	}
+	sourceValues, err := reader.ReadColumns(ctx, source, sourceColumns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset %s: %w", source.ID, err)
+	}
+	targetValues, err := reader.ReadColumns(ctx, target, targetColumns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset %s: %w", target.ID, err)
+	}
+
+	resolver := NewEntityResolver(config)
+	var best *EntityColumnMatch
+	for _, sourceColumn := range sourceColumns {
+		for _, targetColumn := range targetColumns {
+			if sourceValues[sourceColumn] == nil || targetValues[targetColumn] == nil {
+				continue
+			}
+			resolution := resolver.Resolve(sourceValues[sourceColumn], targetValues[targetColumn])
+			if resolution.Report.TargetDistinct < 2 || resolution.Report.MatchRate < minKeyContainment {
+				continue
+			}
+			if best == nil || resolution.Report.MatchRate > best.Resolution.Report.MatchRate {
+				best = &EntityColumnMatch{SourceColumn: sourceColumn, TargetColumn: targetColumn, Resolution: resolution}
+			}
+		}
+	}
+	return best, nil
+}
+
+// entityNameFields returns the text columns of a dataset named like the names of entities
+func entityNameFields(ds *domainDataset.Dataset) []string {
+	excluded := ds.Metadata.ExcludedFields()
+	var columns []string
+	for _, field := range ds.Metadata.Fields {
+		if excluded[field.Name] || (field.DataType != "text" && field.DataType != "string" && field.DataType != "categorical") {
+			continue
+		}
+		name := strings.ToLower(field.Name)
+		for _, word := range entityNameWords {
+			if strings.Contains(name, word) {
+				columns = append(columns, field.Name)
+				break
+			}
+		}
+		if len(columns) == maxEntityColumns {
+			break
+		}
+	}
+	return columns
+}
+
+// ResolveEntityPair resolves the names of targetColumn against those of sourceColumn
+func ResolveEntityPair(ctx context.Context, reader ColumnValueReader, source, target *domainDataset.Dataset, sourceColumn, targetColumn string, config EntityResolutionConfig) (*EntityColumnMatch, error) {
+	sourceValues, err := reader.ReadColumns(ctx, source, []string{sourceColumn})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset %s: %w", source.ID, err)
+	}
+	if sourceValues[sourceColumn] == nil {
+		return nil, fmt.Errorf("dataset %s has no column %s", source.ID, sourceColumn)
+	}
+	targetValues, err := reader.ReadColumns(ctx, target, []string{targetColumn})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset %s: %w", target.ID, err)
+	}
+	if targetValues[targetColumn] == nil {
+		return nil, fmt.Errorf("dataset %s has no column %s", target.ID, targetColumn)
+	}
+	resolution := NewEntityResolver(config).Resolve(sourceValues[sourceColumn], targetValues[targetColumn])
+	return &EntityColumnMatch{SourceColumn: sourceColumn, TargetColumn: targetColumn, Resolution: resolution}, nil
+}
+
+// ResolveEntities resolves the names of target against those of source, reading the datasets'
+// files. With no columns named, the name columns resolving best are chosen; nil means none
+// resolved enough names.
+func (rde *RelationshipDiscoveryEngine) ResolveEntities(ctx context.Context, source, target *domainDataset.Dataset, sourceColumn, targetColumn string, config EntityResolutionConfig) (*EntityColumnMatch, error) {
+	if rde.keyDetector == nil {
+		return nil, fmt.Errorf("dataset values cannot be read")
+	}
+	if sourceColumn == "" && targetColumn == "" {
+		// Only columns the caller may see are candidates for the automatic pairing
+		allowed := rde.applyColumnPolicy(ctx, []*domainDataset.Dataset{source, target})
+		return ResolveEntityColumns(ctx, rde.keyDetector.reader, allowed[0], allowed[1], config)
+	}
+	if sourceColumn == "" || targetColumn == "" {
+		return nil, fmt.Errorf("name both columns or neither")
+	}
+	return ResolveEntityPair(ctx, rde.keyDetector.reader, source, target, sourceColumn, targetColumn, config)
+}
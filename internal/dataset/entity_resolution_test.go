@@ -0,0 +1,119 @@
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	domainDataset "gohypo/domain/dataset"
+)
+
+// TestEntityResolver verifies names differing in case, suffixes and word order match exactly,
+// misspellings match phonetically or fuzzily, a name close to two entities is left unmatched
+// and blocking keeps comparisons below every pair
+func TestEntityResolver(t *testing.T) {
+	source := []string{"Acme Corp.", "Jonathan Smith", "Globex Corporation", "Initech", "Umbrella Ltd", "Jane Doe", "Jane Dow", "Acme Corp."}
+	target := []string{"ACME Corporation", "Smith, Jonathan", "Globx", "Jonathon Smith", "Intech", "Jane Doh", "Stark Industries", ""}
+
+	resolution := NewEntityResolver(DefaultEntityResolutionConfig()).Resolve(source, target)
+	report := resolution.Report
+	if report.SourceDistinct != 7 || report.TargetDistinct != 7 {
+		t.Fatalf("expected 7 distinct names a side, got %+v", report)
+	}
+	if report.Exact != 2 || report.Phonetic+report.Fuzzy != 3 || report.Ambiguous != 1 || report.Unmatched != 1 {
+		t.Errorf("unexpected match counts %+v", report)
+	}
+	if report.Comparisons >= report.AllPairs {
+		t.Errorf("expected blocking to compare fewer than %d pairs, compared %d", report.AllPairs, report.Comparisons)
+	}
+	if id := resolution.TargetID("ACME Corporation"); id == "" || id != resolution.SourceID("Acme Corp.") {
+		t.Errorf("expected ACME Corporation to resolve to Acme Corp., got %q", id)
+	}
+	if resolution.TargetID("Jonathon Smith") != resolution.SourceID("Jonathan Smith") {
+		t.Error("expected the misspelled Jonathon Smith to resolve to Jonathan Smith")
+	}
+	if id := resolution.TargetID("Stark Industries"); id == "" || id == resolution.SourceID("Initech") {
+		t.Errorf("expected an unmatched name to keep an ID of its own, got %q", id)
+	}
+	if len(report.WeakestMatches) != 3 || report.WeakestMatches[0].Score > report.WeakestMatches[2].Score {
+		t.Errorf("expected the non-exact matches weakest first, got %+v", report.WeakestMatches)
+	}
+
+	for word, code := range map[string]string{"robert": "R163", "rupert": "R163", "ashcraft": "A261", "tymczak": "T522", "pfister": "P236", "7eleven": "7eleven"} {
+		if got := soundex(word); got != code {
+			t.Errorf("soundex(%s) = %s, want %s", word, got, code)
+		}
+	}
+	if err := (EntityResolutionConfig{Threshold: 0.9, Blocking: []string{"everything"}, PrefixLength: 3}).Validate(); err == nil {
+		t.Error("expected an unknown blocking key to be rejected")
+	}
+}
+
+// TestCrossDatasetEntityJoin verifies datasets keyed by customer_name and client, which share
+// no exact values, are joined by entity resolution with a resolved entity ID column
+func TestCrossDatasetEntityJoin(t *testing.T) {
+	firstNames := []string{"Alice", "Bruno", "Chidi", "Dagny", "Elena", "Farid", "Greta", "Hiroshi", "Ingrid", "Jamal"}
+	lastNames := []string{"Anderson", "Bergstrom", "Castellano", "Dubois", "Eriksen"}
+	var names, clients, tiers, orderNames, amounts []string
+	for i, last := range lastNames {
+		for j, first := range firstNames {
+			names = append(names, first+" "+last)
+			// The CRM writes clients last name first, upper case, with a missing letter for some
+			client := strings.ToUpper(last + ", " + first)
+			if (i+j)%4 == 0 {
+				client = client[:len(client)-1]
+			}
+			clients = append(clients, client)
+			tiers = append(tiers, []string{"gold", "silver"}[j%2])
+		}
+	}
+	for i := 0; i < 150; i++ {
+		orderNames = append(orderNames, names[i%len(names)])
+		amounts = append(amounts, fmt.Sprint(10+i%7))
+	}
+	orders := &domainDataset.Dataset{ID: "orders", RecordCount: 150, Metadata: domainDataset.DatasetMetadata{Fields: []domainDataset.FieldInfo{
+		{Name: "customer_name", DataType: "text"}, {Name: "amount", DataType: "numeric"},
+	}}}
+	crm := &domainDataset.Dataset{ID: "crm", RecordCount: 50, Metadata: domainDataset.DatasetMetadata{Fields: []domainDataset.FieldInfo{
+		{Name: "client", DataType: "text"}, {Name: "tier", DataType: "categorical"},
+	}}}
+	reader := &fakeColumnReader{columns: map[string]map[string][]string{
+		"orders": {"customer_name": orderNames, "amount": amounts},
+		"crm":    {"client": clients, "tier": tiers},
+	}}
+
+	combiner := NewCrossDatasetCombiner(reader, AlignDay)
+	datasets := []*domainDataset.Dataset{crm, orders}
+	plan, err := combiner.Plan(context.Background(), datasets)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if len(plan.Joins) != 1 || plan.Joins[0].EntityResolution == nil || plan.Joins[0].Column != "client" || plan.Joins[0].ParentColumn != "customer_name" {
+		t.Fatalf("expected crm joined on client by entity resolution, got %+v", plan)
+	}
+	// Reordered names are equal once normalized; the truncated ones need spelling similarity
+	if report := plan.Joins[0].EntityResolution; report.MatchRate < 0.99 || report.Exact != 37 || report.Phonetic+report.Fuzzy != 13 {
+		t.Errorf("expected every client resolved, 13 by similarity, got %+v", report)
+	}
+
+	table, err := combiner.Combine(context.Background(), datasets, plan)
+	if err != nil {
+		t.Fatalf("Combine: %v", err)
+	}
+	if plan.Joins[0].MatchedRows != 150 || plan.Joins[0].EntityColumn != "customer_name_entity_id" {
+		t.Errorf("expected every order matched and an entity column, got %+v", plan.Joins[0])
+	}
+	// Order 1 is of Bruno Anderson, a silver client
+	if row := table.Data.Rows[1]; row["tier"] != "silver" || row["customer_name_entity_id"] == "" {
+		t.Errorf("unexpected combined row %v", row)
+	}
+	for _, key := range table.VariableKeys() {
+		if key == "customer_name_entity_id" {
+			t.Error("resolved entity IDs should not be swept as a variable")
+		}
+	}
+	if origin := table.Origins["customer_name_entity_id"]; origin.DatasetID != "orders" || origin.Column != "customer_name" {
+		t.Errorf("unexpected entity column origin %+v", origin)
+	}
+}
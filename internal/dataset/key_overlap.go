@@ -0,0 +1,409 @@
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gohypo/adapters/excel"
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+)
+
+const (
+	// keySampleSize bounds the distinct values of a column probed against the other side
+	keySampleSize = 2000
+
+	// bloomFalsePositiveRate sizes the filter holding every distinct value of a column
+	bloomFalsePositiveRate = 0.01
+
+	// minOverlapDistinct is the cardinality below which two columns not named like keys are
+	// not compared; status or segment columns overlap fully without being join keys
+	minOverlapDistinct = 10
+
+	// minKeyContainment is the share of one column's values found in the other below which
+	// two columns are not reported as a join key
+	minKeyContainment = 0.5
+
+	// foreignKeyContainment is the share of a column's values found in a unique column from
+	// which it is taken to reference that column
+	foreignKeyContainment = 0.9
+
+	// maxProfiledDatasets bounds the datasets whose value profiles are kept
+	maxProfiledDatasets = 32
+)
+
+// ColumnValueReader reads the values of the named columns of a dataset, row by row
+type ColumnValueReader interface {
+	ReadColumns(ctx context.Context, ds *domainDataset.Dataset, columns []string) (map[string][]string, error)
+}
+
+// FileLocator gives a local, decrypted copy of a stored dataset file; cleanup removes it
+type FileLocator interface {
+	LocalPath(ctx context.Context, filePath string) (string, func(), error)
+}
+
+// FileColumnReader reads column values from the dataset's stored file, decrypting it first
+// when the storage encrypts files at rest
+type FileColumnReader struct {
+	locator FileLocator
+}
+
+// NewFileColumnReader creates a reader over storage; a nil storage reads file paths directly
+func NewFileColumnReader(storage FileStorage) *FileColumnReader {
+	locator, _ := storage.(FileLocator)
+	return &FileColumnReader{locator: locator}
+}
+
+// NewLocatedColumnReader creates a reader that opens files through locator; a nil locator
+// reads file paths directly
+func NewLocatedColumnReader(locator FileLocator) *FileColumnReader {
+	return &FileColumnReader{locator: locator}
+}
+
+// ReadColumns reads the dataset file and returns the values of columns, missing columns omitted
+func (r *FileColumnReader) ReadColumns(ctx context.Context, ds *domainDataset.Dataset, columns []string) (map[string][]string, error) {
+	if ds.FilePath == "" {
+		return nil, fmt.Errorf("dataset %s has no file to read", ds.ID)
+	}
+	path := ds.FilePath
+	if r.locator != nil {
+		localPath, cleanup, err := r.locator.LocalPath(ctx, ds.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open dataset file: %w", err)
+		}
+		defer cleanup()
+		path = localPath
+	}
+
+	data, err := excel.NewDataReader(path).ReadData()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset %s: %w", ds.ID, err)
+	}
+	present := make(map[string]bool, len(data.Headers))
+	for _, header := range data.Headers {
+		present[header] = true
+	}
+	values := make(map[string][]string, len(columns))
+	for _, column := range columns {
+		if !present[column] {
+			continue
+		}
+		columnValues := make([]string, len(data.Rows))
+		for i, row := range data.Rows {
+			columnValues[i] = row[column]
+		}
+		values[column] = columnValues
+	}
+	return values, nil
+}
+
+// KeyOverlap is the measured value overlap of a column of the source dataset and a column
+// of the target dataset
+type KeyOverlap struct {
+	SourceColumn   string `json:"source_column"`
+	TargetColumn   string `json:"target_column"`
+	SourceDistinct int    `json:"source_distinct"`
+	TargetDistinct int    `json:"target_distinct"`
+	// Share of the source column's distinct values found in the target column, and back
+	SourceContainment float64 `json:"source_containment"`
+	TargetContainment float64 `json:"target_containment"`
+	Jaccard           float64 `json:"jaccard"`
+	// "source_references_target", "target_references_source", or empty for a shared key
+	// neither side is unique in
+	Direction string `json:"direction,omitempty"`
+}
+
+// Containment returns the larger of the two containments
+func (o KeyOverlap) Containment() float64 {
+	return math.Max(o.SourceContainment, o.TargetContainment)
+}
+
+// KeyOverlapDetector finds join keys between datasets from the values their columns share.
+// Each column is profiled once per dataset version into a bloom filter of every distinct value
+// and a bounded sample of them; the sample of one column is probed against the filter of the
+// other, so memory stays bounded however many values the columns hold.
+type KeyOverlapDetector struct {
+	reader ColumnValueReader
+
+	mu       sync.Mutex
+	profiles map[core.ID]*datasetProfiles
+}
+
+// datasetProfiles holds the value profiles of one dataset version
+type datasetProfiles struct {
+	updatedAt time.Time
+	lastUsed  time.Time
+	columns   map[string]*valueProfile // nil for a column absent from the file
+}
+
+// valueProfile summarizes the values of one column
+type valueProfile struct {
+	rows     int // non-empty values
+	distinct int
+	sample   []string // the distinct values of smallest hash, at most keySampleSize
+	filter   *bloomFilter
+}
+
+// unique reports whether nearly every non-empty value of the column is distinct
+func (p *valueProfile) unique() bool {
+	return p.rows > 0 && float64(p.distinct) >= 0.95*float64(p.rows)
+}
+
+// NewKeyOverlapDetector creates a detector reading values with reader
+func NewKeyOverlapDetector(reader ColumnValueReader) *KeyOverlapDetector {
+	return &KeyOverlapDetector{reader: reader, profiles: make(map[core.ID]*datasetProfiles)}
+}
+
+// Detect measures the overlap of every candidate key column pair of ds1 and ds2 and returns
+// the pairs sharing at least minKeyContainment of their values, largest containment first
+func (d *KeyOverlapDetector) Detect(ctx context.Context, ds1, ds2 *domainDataset.Dataset) ([]KeyOverlap, error) {
+	candidates1 := keyCandidateFields(ds1)
+	candidates2 := keyCandidateFields(ds2)
+	if len(candidates1) == 0 || len(candidates2) == 0 {
+		return nil, nil
+	}
+	profiles1, err := d.columnProfiles(ctx, ds1, candidates1)
+	if err != nil {
+		return nil, err
+	}
+	profiles2, err := d.columnProfiles(ctx, ds2, candidates2)
+	if err != nil {
+		return nil, err
+	}
+
+	var overlaps []KeyOverlap
+	for _, f1 := range candidates1 {
+		p1 := profiles1[f1.Name]
+		if p1 == nil || p1.distinct < 2 {
+			continue
+		}
+		for _, f2 := range candidates2 {
+			p2 := profiles2[f2.Name]
+			if p2 == nil || p2.distinct < 2 {
+				continue
+			}
+			namedKeys := looksLikeKey(f1, ds1.RecordCount) && looksLikeKey(f2, ds2.RecordCount)
+			if !namedKeys && min(p1.distinct, p2.distinct) < minOverlapDistinct {
+				continue
+			}
+			overlap := measureOverlap(f1.Name, p1, f2.Name, p2)
+			if overlap.Containment() >= minKeyContainment {
+				overlaps = append(overlaps, overlap)
+			}
+		}
+	}
+	sort.SliceStable(overlaps, func(i, j int) bool {
+		return overlaps[i].Containment() > overlaps[j].Containment()
+	})
+	return overlaps, nil
+}
+
+// measureOverlap estimates containment both ways from each sample probed against the other
+// column's filter, corrected for the filter's false positives
+func measureOverlap(name1 string, p1 *valueProfile, name2 string, p2 *valueProfile) KeyOverlap {
+	overlap := KeyOverlap{
+		SourceColumn:      name1,
+		TargetColumn:      name2,
+		SourceDistinct:    p1.distinct,
+		TargetDistinct:    p2.distinct,
+		SourceContainment: probeContainment(p1.sample, p2.filter),
+		TargetContainment: probeContainment(p2.sample, p1.filter),
+	}
+	intersection := overlap.SourceContainment * float64(p1.distinct)
+	if union := float64(p1.distinct+p2.distinct) - intersection; union > 0 {
+		overlap.Jaccard = intersection / union
+	}
+	// A column references another when nearly all its values are found in that unique column;
+	// when both qualify (one-to-one) the larger containment decides
+	sourceReferences := overlap.SourceContainment >= foreignKeyContainment && p2.unique()
+	targetReferences := overlap.TargetContainment >= foreignKeyContainment && p1.unique()
+	switch {
+	case sourceReferences && (!targetReferences || overlap.SourceContainment >= overlap.TargetContainment):
+		overlap.Direction = "source_references_target"
+	case targetReferences:
+		overlap.Direction = "target_references_source"
+	}
+	return overlap
+}
+
+func probeContainment(sample []string, filter *bloomFilter) float64 {
+	if len(sample) == 0 {
+		return 0
+	}
+	found := 0
+	for _, value := range sample {
+		if filter.mayContain(value) {
+			found++
+		}
+	}
+	observed := float64(found) / float64(len(sample))
+	corrected := (observed - bloomFalsePositiveRate) / (1 - bloomFalsePositiveRate)
+	return math.Min(1, math.Max(0, corrected))
+}
+
+// columnProfiles returns the profiles of fields, reading only the columns not yet profiled
+// for this version of the dataset
+func (d *KeyOverlapDetector) columnProfiles(ctx context.Context, ds *domainDataset.Dataset, fields []domainDataset.FieldInfo) (map[string]*valueProfile, error) {
+	d.mu.Lock()
+	cached := d.profiles[ds.ID]
+	if cached == nil || !cached.updatedAt.Equal(ds.UpdatedAt) {
+		cached = &datasetProfiles{updatedAt: ds.UpdatedAt, columns: make(map[string]*valueProfile)}
+	}
+	var missing []string
+	for _, field := range fields {
+		if _, ok := cached.columns[field.Name]; !ok {
+			missing = append(missing, field.Name)
+		}
+	}
+	d.mu.Unlock()
+
+	built := make(map[string]*valueProfile, len(missing))
+	if len(missing) > 0 {
+		values, err := d.reader.ReadColumns(ctx, ds, missing)
+		if err != nil {
+			return nil, err
+		}
+		for _, column := range missing {
+			if columnValues, ok := values[column]; ok {
+				built[column] = newValueProfile(columnValues)
+			} else {
+				built[column] = nil
+			}
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for column, profile := range built {
+		cached.columns[column] = profile
+	}
+	cached.lastUsed = time.Now()
+	d.profiles[ds.ID] = cached
+	for len(d.profiles) > maxProfiledDatasets {
+		var oldestID core.ID
+		var oldest time.Time
+		for id, profiles := range d.profiles {
+			if oldestID == "" || profiles.lastUsed.Before(oldest) {
+				oldestID, oldest = id, profiles.lastUsed
+			}
+		}
+		delete(d.profiles, oldestID)
+	}
+	profiles := make(map[string]*valueProfile, len(fields))
+	for _, field := range fields {
+		profiles[field.Name] = cached.columns[field.Name]
+	}
+	return profiles, nil
+}
+
+func newValueProfile(values []string) *valueProfile {
+	distinct := make(map[string]uint64)
+	rows := 0
+	for _, raw := range values {
+		value := normalizeKeyValue(raw)
+		if value == "" {
+			continue
+		}
+		rows++
+		if _, ok := distinct[value]; !ok {
+			distinct[value] = hashValue(value)
+		}
+	}
+
+	profile := &valueProfile{rows: rows, distinct: len(distinct), filter: newBloomFilter(len(distinct), bloomFalsePositiveRate)}
+	sample := make([]string, 0, len(distinct))
+	for value := range distinct {
+		profile.filter.add(value)
+		sample = append(sample, value)
+	}
+	// Keeping the smallest hashes samples the same values of two columns holding the same keys
+	sort.Slice(sample, func(i, j int) bool { return distinct[sample[i]] < distinct[sample[j]] })
+	if len(sample) > keySampleSize {
+		sample = sample[:keySampleSize]
+	}
+	profile.sample = sample
+	return profile
+}
+
+// normalizeKeyValue folds case and surrounding space, and writes whole numbers without
+// decimals or leading zeros, so 1001, "1001.0" and "01001" compare equal
+func normalizeKeyValue(raw string) string {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	if number, err := strconv.ParseFloat(value, 64); err == nil && number == math.Trunc(number) && math.Abs(number) < 1e15 {
+		return strconv.FormatInt(int64(number), 10)
+	}
+	return value
+}
+
+// keyCandidateFields returns the fields that may hold join keys: those named or counted like
+// keys, and the categorical and text columns; measures and dates are left out
+func keyCandidateFields(ds *domainDataset.Dataset) []domainDataset.FieldInfo {
+	var candidates []domainDataset.FieldInfo
+	for _, field := range ds.Metadata.Fields {
+		switch {
+		case looksLikeKey(field, ds.RecordCount):
+			candidates = append(candidates, field)
+		case field.DataType == "categorical" || field.DataType == "text" || field.DataType == "string":
+			candidates = append(candidates, field)
+		}
+	}
+	return candidates
+}
+
+func hashValue(value string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(value))
+	return h.Sum64()
+}
+
+// bloomFilter answers whether a value may be in a set, with no false negatives
+type bloomFilter struct {
+	bits   []uint64
+	size   uint64
+	hashes int
+}
+
+// newBloomFilter sizes a filter for n values at the given false positive rate
+func newBloomFilter(n int, falsePositiveRate float64) *bloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	size := uint64(math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	hashes := int(math.Round(float64(size) / float64(n) * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+	return &bloomFilter{bits: make([]uint64, (size+63)/64), size: size, hashes: hashes}
+}
+
+func (f *bloomFilter) add(value string) {
+	h1, h2 := f.hashPair(value)
+	for i := 0; i < f.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % f.size
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+func (f *bloomFilter) mayContain(value string) bool {
+	h1, h2 := f.hashPair(value)
+	for i := 0; i < f.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % f.size
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hashPair derives the two hashes every probe position is combined from
+func (f *bloomFilter) hashPair(value string) (uint64, uint64) {
+	sum := hashValue(value)
+	return sum, (sum >> 32) | 1
+}
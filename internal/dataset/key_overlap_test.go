@@ -0,0 +1,201 @@
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+	"gohypo/ports"
+)
+
+// fakeColumnReader serves column values from memory and counts the reads
+type fakeColumnReader struct {
+	columns map[string]map[string][]string // dataset ID -> column -> values
+	reads   int
+}
+
+func (r *fakeColumnReader) ReadColumns(ctx context.Context, ds *domainDataset.Dataset, columns []string) (map[string][]string, error) {
+	r.reads++
+	values := make(map[string][]string)
+	for _, column := range columns {
+		if columnValues, ok := r.columns[string(ds.ID)][column]; ok {
+			values[column] = columnValues
+		}
+	}
+	return values, nil
+}
+
+// customerOrderValues holds 200 unique customers and 600 orders of 150 of them, each side with
+// a low-cardinality column whose values overlap fully
+func customerOrderValues() (*domainDataset.Dataset, *domainDataset.Dataset, *fakeColumnReader) {
+	var customerIDs, segments, orderCustomers, statuses []string
+	for i := 0; i < 200; i++ {
+		customerIDs = append(customerIDs, fmt.Sprintf("C-%04d", i))
+		segments = append(segments, []string{"retail", "wholesale"}[i%2])
+	}
+	for i := 0; i < 600; i++ {
+		// Formatting differs across the files; normalization folds case and space
+		orderCustomers = append(orderCustomers, fmt.Sprintf(" c-%04d", i%150))
+		statuses = append(statuses, []string{"retail", "wholesale", ""}[i%3])
+	}
+	customers := &domainDataset.Dataset{ID: "customers", WorkspaceID: "w1", FilePath: "customers.csv", RecordCount: 200,
+		Metadata: domainDataset.DatasetMetadata{Fields: []domainDataset.FieldInfo{
+			{Name: "customer_id", DataType: "text"}, {Name: "segment", DataType: "categorical"}, {Name: "lifetime_value", DataType: "numeric"},
+		}}}
+	orders := &domainDataset.Dataset{ID: "orders", WorkspaceID: "w1", FilePath: "orders.csv", RecordCount: 600,
+		Metadata: domainDataset.DatasetMetadata{Fields: []domainDataset.FieldInfo{
+			{Name: "buyer", DataType: "text"}, {Name: "channel", DataType: "categorical"},
+		}}}
+	reader := &fakeColumnReader{columns: map[string]map[string][]string{
+		"customers": {"customer_id": customerIDs, "segment": segments},
+		"orders":    {"buyer": orderCustomers, "channel": statuses},
+	}}
+	return customers, orders, reader
+}
+
+// TestKeyRelationshipsFromValueOverlap verifies a column referencing another dataset's unique
+// column is reported as a foreign key, ignoring low-cardinality overlaps, and that merge
+// suggestions join on it
+func TestKeyRelationshipsFromValueOverlap(t *testing.T) {
+	customers, orders, reader := customerOrderValues()
+	engine := NewRelationshipDiscoveryEngine(nil, nil, nil, nil, nil)
+	engine.SetColumnValueReader(reader)
+
+	relation := engine.analyzeKeyRelationships(customers, orders)
+	if relation == nil || relation.RelationType != "foreign_key" {
+		t.Fatalf("expected a foreign key, got %+v", relation)
+	}
+	overlaps := relation.Metadata["key_overlaps"].([]KeyOverlap)
+	if len(overlaps) != 1 {
+		t.Fatalf("expected only customer_id and buyer to overlap, got %+v", overlaps)
+	}
+	key := relation.Metadata["join_key"].(KeyOverlap)
+	if key.SourceColumn != "customer_id" || key.TargetColumn != "buyer" || key.Direction != "target_references_source" ||
+		key.TargetContainment < 0.99 || key.SourceContainment < 0.7 || key.SourceContainment > 0.8 || key.TargetDistinct != 150 {
+		t.Errorf("unexpected join key %+v", key)
+	}
+
+	suggestion := engine.createMergeSuggestion(customers, []*domainDataset.Dataset{{ID: orders.ID}}, []*domainDataset.DatasetRelation{relation})
+	if suggestion.MergeType != AutoJoin || len(suggestion.JoinKeys) != 1 || suggestion.Reasoning != "buyer references customer_id: 100% of its values are found there" {
+		t.Errorf("unexpected suggestion %+v", suggestion)
+	}
+
+	// Profiles are kept per dataset version, so analyzing the pair again reads nothing
+	engine.analyzeKeyRelationships(orders, customers)
+	if reader.reads != 2 {
+		t.Errorf("expected one read per dataset, got %d", reader.reads)
+	}
+}
+
+// TestBloomFilterFalsePositives verifies the filter never misses an added value and keeps
+// false positives near the rate it was sized for
+func TestBloomFilterFalsePositives(t *testing.T) {
+	filter := newBloomFilter(10000, bloomFalsePositiveRate)
+	for i := 0; i < 10000; i++ {
+		filter.add(fmt.Sprintf("in-%d", i))
+	}
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if !filter.mayContain(fmt.Sprintf("in-%d", i)) {
+			t.Fatalf("added value in-%d not found", i)
+		}
+		if filter.mayContain(fmt.Sprintf("out-%d", i)) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / 10000; rate > 3*bloomFalsePositiveRate {
+		t.Errorf("false positive rate %.4f", rate)
+	}
+}
+
+// TestFileColumnReaderNormalizedHeaders verifies columns are read by the normalized names
+// their fields are stored under
+func TestFileColumnReaderNormalizedHeaders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.csv")
+	if err := os.WriteFile(path, []byte("Customer ID,Order Total ($)\nC-1,10\nC-2,12\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	columns := domainDataset.NormalizeColumnNames([]string{"Customer ID", "Order Total ($)"}).Keys()
+	values, err := NewFileColumnReader(nil).ReadColumns(context.Background(), &domainDataset.Dataset{ID: "orders", FilePath: path}, append(columns, "missing"))
+	if err != nil {
+		t.Fatalf("ReadColumns: %v", err)
+	}
+	if len(values) != 2 || len(values[columns[0]]) != 2 || values[columns[0]][1] != "C-2" {
+		t.Errorf("unexpected values %v for columns %v", values, columns)
+	}
+}
+
+// TestCrossDatasetCombine verifies orders take the segment of their customer through the key
+// the values reveal and the mean temperature of their day through time alignment, with each
+// column's origin recorded, and that an unrelated dataset is skipped
+func TestCrossDatasetCombine(t *testing.T) {
+	customers, orders, reader := customerOrderValues()
+	var orderDates, weatherDates, temperatures []string
+	for i := 0; i < 600; i++ {
+		orderDates = append(orderDates, fmt.Sprintf("2024-01-%02d", 1+i%28))
+	}
+	for day := 1; day <= 28; day++ {
+		// Two readings a day, averaged into the orders of that day
+		weatherDates = append(weatherDates, fmt.Sprintf("2024-01-%02d 06:00", day), fmt.Sprintf("2024-01-%02d 18:00", day))
+		temperatures = append(temperatures, fmt.Sprint(day), fmt.Sprint(day+2))
+	}
+	orders.Metadata.Fields = append(orders.Metadata.Fields, domainDataset.FieldInfo{Name: "order_date", DataType: "date"})
+	reader.columns["orders"]["order_date"] = orderDates
+	weather := &domainDataset.Dataset{ID: "weather", WorkspaceID: "w1", RecordCount: 56,
+		Metadata: domainDataset.DatasetMetadata{Fields: []domainDataset.FieldInfo{
+			{Name: "observed_at", DataType: "date"}, {Name: "temperature", DataType: "numeric"},
+		}}}
+	reader.columns["weather"] = map[string][]string{"observed_at": weatherDates, "temperature": temperatures}
+	unrelated := &domainDataset.Dataset{ID: "unrelated", WorkspaceID: "w1", RecordCount: 5,
+		Metadata: domainDataset.DatasetMetadata{Fields: []domainDataset.FieldInfo{{Name: "score", DataType: "numeric"}}}}
+	datasets := []*domainDataset.Dataset{customers, weather, orders, unrelated}
+
+	combiner := NewCrossDatasetCombiner(reader, AlignDay)
+	plan, err := combiner.Plan(context.Background(), datasets)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if plan.BaseID != "orders" || len(plan.Joins) != 2 || len(plan.Skipped) != 1 || plan.Skipped[0].DatasetID != "unrelated" {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+	if join := plan.Joins[0]; join.DatasetID != "customers" || join.Column != "customer_id" || join.ParentColumn != "buyer" || join.TimeColumn != "" {
+		t.Errorf("expected customers joined on their key alone, got %+v", join)
+	}
+
+	table, err := combiner.Combine(context.Background(), datasets, plan)
+	if err != nil {
+		t.Fatalf("Combine: %v", err)
+	}
+	if len(table.Data.Rows) != 600 || len(table.Data.Headers) != 5 {
+		t.Fatalf("expected 600 rows of buyer, channel, order_date, segment and temperature, got %d rows of %v", len(table.Data.Rows), table.Data.Headers)
+	}
+	// Order 3 is of customer c-0003, odd so wholesale, on January 4th (4 and 6 degrees)
+	if row := table.Data.Rows[3]; row["segment"] != "wholesale" || row["temperature"] != "5" {
+		t.Errorf("unexpected combined row %v", row)
+	}
+	if join := plan.Joins[1]; join.MatchedRows != 600 || join.AggregatedRows != 600 {
+		t.Errorf("expected every order to match two readings, got %+v", join)
+	}
+	origin := table.Origins["temperature"]
+	if origin.DatasetID != "weather" || origin.Column != "temperature" || !origin.Aggregated || origin.JoinedOn != "observed_at ~ order_date by day" {
+		t.Errorf("unexpected origin %+v", origin)
+	}
+	if origin := table.Origins["segment"]; origin.DatasetID != "customers" || origin.Aggregated || origin.JoinedOn != "customer_id = buyer" {
+		t.Errorf("unexpected origin %+v", origin)
+	}
+
+	bundle, err := NewCombinedMatrixResolver(table).ResolveMatrix(context.Background(), ports.MatrixResolutionRequest{
+		VarKeys: []core.VariableKey{"channel", "temperature"},
+	})
+	if err != nil {
+		t.Fatalf("ResolveMatrix: %v", err)
+	}
+	origins := bundle.ColumnOrigins()
+	if len(origins) != 2 || origins["channel"].DatasetID != "orders" || origins["temperature"].DatasetID != "weather" {
+		t.Errorf("unexpected column origins %+v", origins)
+	}
+}
@@ -0,0 +1,154 @@
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// KeyRotationReport summarizes a key rotation pass over stored datasets
+type KeyRotationReport struct {
+	ActiveKeyID string   `json:"active_key_id"`
+	Scanned     int      `json:"scanned"`
+	Rotated     int      `json:"rotated"`   // re-encrypted from a retired key
+	Encrypted   int      `json:"encrypted"` // previously plaintext files now encrypted
+	Skipped     int      `json:"skipped"`   // already under the active key
+	Failed      []string `json:"failed,omitempty"`
+}
+
+// RotateKeys re-encrypts every stored dataset that is plaintext or wrapped with a
+// retired key. Each file is rewritten to a temp file and atomically renamed over the
+// original, so readers holding the old file keep working and no downtime is needed.
+func (s *LocalFileStorage) RotateKeys(ctx context.Context) (*KeyRotationReport, error) {
+	if s.config.KeyProvider == nil {
+		return nil, fmt.Errorf("key rotation requires a configured key provider")
+	}
+	activeKeyID, _, err := s.config.KeyProvider.ActiveKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active encryption key: %w", err)
+	}
+
+	report := &KeyRotationReport{ActiveKeyID: activeKeyID}
+	err = filepath.WalkDir(s.config.BasePath, func(path string, d os.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if os.IsNotExist(walkErr) && path == s.config.BasePath {
+				return filepath.SkipDir
+			}
+			return walkErr
+		}
+		if d.IsDir() || strings.HasSuffix(path, ".rotating") {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		report.Scanned++
+
+		encrypted, keyID, err := inspectEncryptedFile(path)
+		if err != nil {
+			report.Failed = append(report.Failed, path)
+			log.Printf("[LocalFileStorage] Failed to inspect %s: %v", path, err)
+			return nil
+		}
+		if encrypted && keyID == activeKeyID {
+			report.Skipped++
+			return nil
+		}
+
+		if err := s.reencryptFile(ctx, path); err != nil {
+			report.Failed = append(report.Failed, path)
+			log.Printf("[LocalFileStorage] Failed to rotate %s: %v", path, err)
+			return nil
+		}
+		if encrypted {
+			report.Rotated++
+		} else {
+			report.Encrypted++
+		}
+		return nil
+	})
+	if err != nil {
+		return report, fmt.Errorf("key rotation aborted: %w", err)
+	}
+
+	log.Printf("[LocalFileStorage] 🔑 Key rotation to %s: scanned=%d rotated=%d encrypted=%d skipped=%d failed=%d",
+		activeKeyID, report.Scanned, report.Rotated, report.Encrypted, report.Skipped, len(report.Failed))
+	return report, nil
+}
+
+// reencryptFile rewrites a single file under the active key via temp file + rename
+func (s *LocalFileStorage) reencryptFile(ctx context.Context, path string) error {
+	src, err := s.GetReader(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmpPath := path + ".rotating"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	if err := encryptStream(ctx, s.config.KeyProvider, tmp, src, s.config.ChunkSize); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	return nil
+}
+
+// LocalPath returns a plaintext path for consumers that need a real file (e.g. the
+// Excel reader). Encrypted files are decrypted into TempDir; call cleanup when done.
+func (s *LocalFileStorage) LocalPath(ctx context.Context, filePath string) (string, func(), error) {
+	noop := func() {}
+
+	encrypted, _, err := inspectEncryptedFile(filePath)
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to open file: %w", err)
+	}
+	if !encrypted {
+		return filePath, noop, nil
+	}
+
+	src, err := s.GetReader(ctx, filePath)
+	if err != nil {
+		return "", noop, err
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(s.config.TempDir, "gohypo-*"+filepath.Ext(filePath))
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	cleanup := func() { os.Remove(tmp.Name()) }
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		cleanup()
+		return "", noop, fmt.Errorf("failed to decrypt file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	return tmp.Name(), cleanup, nil
+}
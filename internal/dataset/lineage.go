@@ -0,0 +1,42 @@
+package dataset
+
+import (
+	"time"
+
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+)
+
+// MergeLineage records each merged dataset as a parent of the merge output, with the join
+// and, for time series, the time grid the merge ran with
+func MergeLineage(datasetIDs []core.ID, config *MergeConfig) *domainDataset.Lineage {
+	transform := domainDataset.Transformation{
+		Kind: domainDataset.TransformMerge,
+		Merge: &domainDataset.MergeTransform{
+			JoinType:        string(config.JoinType),
+			KeyColumns:      config.KeyColumns,
+			DuplicatePolicy: string(config.DuplicatePolicy),
+		},
+		RecordedAt: time.Now(),
+	}
+	if transform.Merge.JoinType == "" {
+		transform.Merge.JoinType = string(UnionJoin)
+	}
+	if temporal := config.TemporalConfig; temporal != nil && temporal.TimeColumn != "" {
+		transform.Merge.TimeColumn = temporal.TimeColumn
+		if temporal.Frequency != "" && temporal.Frequency != FrequencyUnknown {
+			transform.Resample = &domainDataset.ResampleTransform{
+				Frequency:     string(temporal.Frequency),
+				Interpolation: string(temporal.Interpolation),
+				GapFill:       string(temporal.GapFillStrategy),
+				TimeColumn:    temporal.TimeColumn,
+			}
+		}
+	}
+
+	lineage := &domainDataset.Lineage{DerivedFrom: make([]domainDataset.LineageEdge, 0, len(datasetIDs))}
+	for _, id := range datasetIDs {
+		lineage.DerivedFrom = append(lineage.DerivedFrom, domainDataset.LineageEdge{DatasetID: id, Transformation: transform})
+	}
+	return lineage
+}
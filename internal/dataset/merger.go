@@ -22,11 +22,11 @@ import (
 	"math"
 	"runtime"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
 	"gohypo/domain/core"
+	"gohypo/domain/dataset"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -66,6 +66,7 @@ type MergeConfig struct {
 type TemporalMergeConfig struct {
 	TimeColumn         string             // Name of the timestamp column
 	TimeFormat         string             // Expected time format (e.g., "2006-01-02 15:04:05")
+	Locale             string             // Locale of dates and numbers (e.g., "de-DE"); empty or "auto" detects it per dataset
 	SourceTimeZone     string             // Source timezone (e.g., "America/New_York")
 	TargetTimeZone     string             // Target timezone for normalization (e.g., "UTC")
 	Frequency          TemporalFrequency  // Expected data frequency
@@ -124,25 +125,13 @@ const (
 	DedupeTimeAggregate  DeduplicateByTime = "aggregate" // Aggregate duplicate values
 )
 
-// BusinessCalendar defines business day and holiday rules
-type BusinessCalendar struct {
-	IncludeWeekends bool      // Whether to include weekend data
-	Holidays        []Holiday // List of holidays
-	BusinessHours   TimeRange // Business hours for filtering
-}
-
-// Holiday represents a holiday or special date
-type Holiday struct {
-	Date     time.Time
-	Name     string
-	IsHalfDay bool
-}
-
-// TimeRange represents a time range
-type TimeRange struct {
-	Start time.Duration // Duration from midnight
-	End   time.Duration // Duration from midnight
-}
+// The business calendar is shared with variable contracts, which count windows and lags
+// in business days
+type (
+	BusinessCalendar = dataset.BusinessCalendar
+	Holiday          = dataset.Holiday
+	TimeRange        = dataset.TimeRange
+)
 
 // DuplicatePolicy defines how to handle duplicate rows during merge
 type DuplicatePolicy string
@@ -177,6 +166,10 @@ type MergeResult struct {
 	MemoryUsedMB    int           `json:"memory_used_mb"`
 	Error           string        `json:"error,omitempty"`
 	Warnings        []string      `json:"warnings,omitempty"`
+
+	// Lineage records the merged datasets as parents of the output, for the dataset
+	// registered from OutputPath
+	Lineage *dataset.Lineage `json:"lineage,omitempty"`
 }
 
 // Merger handles dataset merging operations
@@ -335,6 +328,7 @@ func (m *Merger) mergeStreaming(ctx context.Context, datasetIDs []core.ID, outpu
 		OutputPath:      outputPath,
 		StrategyUsed:    StreamingMerge,
 		MemoryUsedMB:    m.getCurrentMemoryUsage(),
+		Lineage:         MergeLineage(datasetIDs, config),
 	}, nil
 }
 
@@ -513,6 +507,7 @@ type TimeseriesRow struct {
 	Timestamp time.Time
 	Data      []string
 	DatasetID string
+	Locale    dataset.Locale // how the row's dataset writes numbers
 }
 
 // processTimeseriesDataset processes a single timeseries dataset
@@ -538,11 +533,36 @@ func (m *Merger) processTimeseriesDataset(reader io.Reader, headers []string, ti
 		return 0, 0, fmt.Errorf("time column '%s' not found in headers", timeCol)
 	}
 
+	// Buffer the first rows to detect the dataset's locale, then process them with the rest
+	var buffered [][]string
+	for len(buffered) < dataset.LocaleSampleRows {
+		row, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to read row: %w", err)
+		}
+		buffered = append(buffered, row)
+	}
+	locale, err := datasetLocale(config.Locale, dataset.DetectTableLocale(buffered))
+	if err != nil {
+		return 0, 0, err
+	}
+	nextRow := func() ([]string, error) {
+		if len(buffered) > 0 {
+			row := buffered[0]
+			buffered = buffered[1:]
+			return row, nil
+		}
+		return csvReader.Read()
+	}
+
 	rowsProcessed := 0
 	duplicates := 0
 
 	for {
-		row, err := csvReader.Read()
+		row, err := nextRow()
 		if err == io.EOF {
 			break
 		}
@@ -560,7 +580,7 @@ func (m *Merger) processTimeseriesDataset(reader io.Reader, headers []string, ti
 			continue // Skip rows without timestamps
 		}
 
-		timestamp, err := m.parseTimestamp(timestampStr, config.TimeFormat, config.SourceTimeZone, config.TargetTimeZone)
+		timestamp, err := m.parseTimestamp(timestampStr, config.TimeFormat, config.SourceTimeZone, config.TargetTimeZone, locale)
 		if err != nil {
 			// Log warning but continue processing
 			continue
@@ -575,6 +595,7 @@ func (m *Merger) processTimeseriesDataset(reader io.Reader, headers []string, ti
 			Timestamp: timestamp,
 			Data:      make([]string, len(row)),
 			DatasetID: "", // Will be set when merging multiple datasets
+			Locale:    locale,
 		}
 		copy(tsRow.Data, row)
 
@@ -612,45 +633,42 @@ func (m *Merger) processTimeseriesDataset(reader io.Reader, headers []string, ti
 	return rowsProcessed, duplicates, nil
 }
 
-// parseTimestamp parses timestamp with flexible format detection and timezone conversion
-func (m *Merger) parseTimestamp(timestampStr, format, sourceTimezone, targetTimezone string) (time.Time, error) {
+// parseTimestamp parses timestamp with flexible format detection and timezone conversion.
+// Without a format, numeric dates such as 03/04/2024 are read in the dataset's locale.
+// Timestamps without an offset are read in the source timezone, and every timestamp is
+// converted to the target timezone, UTC by default, so rows of datasets written in
+// different zones line up on the same keys.
+func (m *Merger) parseTimestamp(timestampStr, format, sourceTimezone, targetTimezone string, locale dataset.Locale) (time.Time, error) {
 	var parsedTime time.Time
 	var err error
 
+	source, err := core.ResolveTimeZone(sourceTimezone, core.TimeZoneFromSpec)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid source timezone: %w", err)
+	}
+
 	// Parse the timestamp
 	if format != "" {
 		// Use specified format
-		if sourceTimezone != "" {
-			loc, err := time.LoadLocation(sourceTimezone)
-			if err != nil {
-				return time.Time{}, fmt.Errorf("invalid source timezone %s: %w", sourceTimezone, err)
-			}
-			parsedTime, err = time.ParseInLocation(format, timestampStr, loc)
-		} else {
-			parsedTime, err = time.Parse(format, timestampStr)
-		}
+		parsedTime, err = time.ParseInLocation(format, timestampStr, source.Location())
 		if err != nil {
 			return time.Time{}, fmt.Errorf("failed to parse timestamp with format %s: %w", format, err)
 		}
 	} else {
-		// Auto-detect format
-		formats := []string{
-			time.RFC3339,
-			"2006-01-02T15:04:05Z07:00",
-			"2006-01-02 15:04:05",
-			"2006-01-02 15:04:05.000",
-			"2006-01-02",
-			"01/02/2006",
-			"2006/01/02",
-			"02-Jan-2006",
-			"2006-01-02 15:04:05 -0700",
-			"Mon Jan 2 15:04:05 2006",
-		}
+		// Auto-detect format: the locale reads ISO, numeric and named-month dates
+		if t, ok := locale.ParseDateIn(timestampStr, source.Location()); ok {
+			parsedTime = t
+		} else {
+			formats := []string{
+				"2006-01-02 15:04:05 -0700",
+				"Mon Jan 2 15:04:05 2006",
+			}
 
-		for _, fmt := range formats {
-			if t, err := time.Parse(fmt, timestampStr); err == nil {
-				parsedTime = t
-				break
+			for _, fmt := range formats {
+				if t, err := time.ParseInLocation(fmt, timestampStr, source.Location()); err == nil {
+					parsedTime = t
+					break
+				}
 			}
 		}
 
@@ -659,16 +677,12 @@ func (m *Merger) parseTimestamp(timestampStr, format, sourceTimezone, targetTime
 		}
 	}
 
-	// Convert to target timezone if specified
-	if targetTimezone != "" && targetTimezone != sourceTimezone {
-		targetLoc, err := time.LoadLocation(targetTimezone)
-		if err != nil {
-			return time.Time{}, fmt.Errorf("invalid target timezone %s: %w", targetTimezone, err)
-		}
-		parsedTime = parsedTime.In(targetLoc)
+	// Convert to the target timezone
+	target, err := core.ResolveTimeZone(targetTimezone, core.TimeZoneFromSpec)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid target timezone: %w", err)
 	}
-
-	return parsedTime, nil
+	return parsedTime.In(target.Location()), nil
 }
 
 // normalizeTimezone converts timestamp to target timezone
@@ -956,36 +970,7 @@ func reportProgress(config *MergeConfig, progress float64, message string) {
 
 // isBusinessTime checks if a timestamp falls within business calendar rules
 func (m *Merger) isBusinessTime(timestamp time.Time, calendar *BusinessCalendar) bool {
-	// Check weekends
-	if !calendar.IncludeWeekends {
-		weekday := timestamp.Weekday()
-		if weekday == time.Saturday || weekday == time.Sunday {
-			return false
-		}
-	}
-
-	// Check holidays
-	for _, holiday := range calendar.Holidays {
-		if timestamp.Year() == holiday.Date.Year() &&
-		   timestamp.Month() == holiday.Date.Month() &&
-		   timestamp.Day() == holiday.Date.Day() {
-			return false
-		}
-	}
-
-	// Check business hours (if specified)
-	if calendar.BusinessHours.Start != 0 || calendar.BusinessHours.End != 0 {
-		sinceMidnight := time.Duration(timestamp.Hour())*time.Hour +
-						time.Duration(timestamp.Minute())*time.Minute +
-						time.Duration(timestamp.Second())*time.Second
-
-		if sinceMidnight < calendar.BusinessHours.Start ||
-		   sinceMidnight > calendar.BusinessHours.End {
-			return false
-		}
-	}
-
-	return true
+	return calendar.IsBusinessTime(timestamp)
 }
 
 // detectOutliers performs statistical outlier detection on numeric columns
@@ -1007,7 +992,7 @@ func (m *Merger) detectOutliers(rows []TimeseriesRow, config *TemporalMergeConfi
 		var values []float64
 		for _, row := range rows {
 			if i < len(row.Data) {
-				if val, err := strconv.ParseFloat(strings.TrimSpace(row.Data[i]), 64); err == nil {
+				if val, ok := parseNumber(row.Locale, row.Data[i]); ok {
 					values = append(values, val)
 				}
 			}
@@ -1151,10 +1136,10 @@ func (m *Merger) resampleTimeseries(data map[string][]TimeseriesRow, targetFreq
 
 					for i := range newRow.Data {
 						if i < len(beforeRow.Data) && i < len(afterRow.Data) {
-							beforeVal, beforeErr := strconv.ParseFloat(beforeRow.Data[i], 64)
-							afterVal, afterErr := strconv.ParseFloat(afterRow.Data[i], 64)
+							beforeVal, beforeOK := parseNumber(beforeRow.Locale, beforeRow.Data[i])
+							afterVal, afterOK := parseNumber(afterRow.Locale, afterRow.Data[i])
 
-							if beforeErr == nil && afterErr == nil {
+							if beforeOK && afterOK {
 								interpolated := beforeVal + (afterVal-beforeVal)*ratio
 								newRow.Data[i] = fmt.Sprintf("%.6f", interpolated)
 							}
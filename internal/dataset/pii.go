@@ -0,0 +1,158 @@
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"gohypo/domain/dataset"
+)
+
+const (
+	// piiMatchThreshold is the share of non-empty values that must match for a column to be flagged
+	piiMatchThreshold = 0.8
+	// piiMaxScanRows bounds how many rows are scanned per column
+	piiMaxScanRows = 1000
+	// piiMinValues is the fewest non-empty values a column needs to be judged at all
+	piiMinValues = 3
+	// piiMask replaces flagged values in stored samples
+	piiMask = "[redacted]"
+)
+
+var (
+	piiEmailPattern = regexp.MustCompile(`^[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}$`)
+	piiSSNPattern   = regexp.MustCompile(`^\d{3}-\d{2}-\d{4}$`)
+	piiPhonePattern = regexp.MustCompile(`^\+?[\d\s().\-]{7,20}$`)
+	piiNamePattern  = regexp.MustCompile(`^[A-Z][a-zA-Z'\-]+(\s+[A-Z][a-zA-Z'\-.]*){0,3}$`)
+
+	// Person-name columns are only recognised by header, since capitalised words alone also match
+	// cities, products and categories
+	piiNameHeader    = regexp.MustCompile(`(?i)(^|[_\s])(first|last|full|given|family|middle|sur|maiden)?[_\s]?name$`)
+	piiNonPersonName = regexp.MustCompile(`(?i)(file|user|product|company|business|brand|dataset|domain|host|column|field|display|city|country|street|item|category|team|org|organization|vendor|store|sheet)[_\s]?name$`)
+)
+
+// piiMatcher recognises the values of one kind of PII
+type piiMatcher struct {
+	kind  dataset.PIIKind
+	match func(string) bool
+}
+
+// PIIConfirmer asks a language model whether a flagged column really holds personal data
+type PIIConfirmer interface {
+	ConfirmPII(ctx context.Context, field string, kind dataset.PIIKind, samples []string) (bool, error)
+}
+
+// ScanPII flags columns whose values look like emails, phone numbers, SSNs or person names.
+// Flagged columns are excluded by default; when a confirmer is given it can clear a flag,
+// in which case the column is kept and the decision is still recorded.
+func ScanPII(ctx context.Context, fields []dataset.FieldInfo, rows []map[string]interface{}, confirmer PIIConfirmer) *dataset.PIIReport {
+	if len(rows) > piiMaxScanRows {
+		rows = rows[:piiMaxScanRows]
+	}
+	report := &dataset.PIIReport{ScannedAt: time.Now(), RowsScanned: len(rows), Columns: []dataset.PIIColumn{}}
+
+	for _, field := range fields {
+		kind, rate, samples := classifyPIIColumn(field.Name, rows)
+		if kind == "" {
+			continue
+		}
+		column := dataset.PIIColumn{Field: field.Name, Kind: kind, MatchRate: rate, Source: "regex", Excluded: true}
+		if confirmer != nil {
+			confirmed, err := confirmer.ConfirmPII(ctx, field.Name, kind, samples)
+			if err != nil {
+				log.Printf("[PIIScanner] LLM confirmation failed for %s, keeping regex verdict: %v", field.Name, err)
+			} else {
+				column.Source = "regex+llm"
+				column.LLMConfirmed = &confirmed
+				column.Excluded = confirmed
+			}
+		}
+		log.Printf("[PIIScanner] Flagged column %s as %s (%.0f%% of values, excluded=%v)", field.Name, kind, rate*100, column.Excluded)
+		report.Columns = append(report.Columns, column)
+	}
+	return report
+}
+
+// classifyPIIColumn returns the PII kind a column's values match, the share that matched and a
+// few matching values for confirmation; kind is empty when the column looks clean
+func classifyPIIColumn(name string, rows []map[string]interface{}) (dataset.PIIKind, float64, []string) {
+	var values []string
+	for _, row := range rows {
+		if v, ok := row[name]; ok && v != nil {
+			if s := strings.TrimSpace(fmt.Sprintf("%v", v)); s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+	if len(values) < piiMinValues {
+		return "", 0, nil
+	}
+
+	candidates := []piiMatcher{
+		{dataset.PIIEmail, piiEmailPattern.MatchString},
+		{dataset.PIISSN, piiSSNPattern.MatchString},
+		{dataset.PIIPhone, isPhoneNumber},
+	}
+	if piiNameHeader.MatchString(name) && !piiNonPersonName.MatchString(name) {
+		candidates = append(candidates, piiMatcher{dataset.PIIName, piiNamePattern.MatchString})
+	}
+
+	for _, candidate := range candidates {
+		var samples []string
+		matched := 0
+		for _, v := range values {
+			if candidate.match(v) {
+				matched++
+				if len(samples) < 5 {
+					samples = append(samples, v)
+				}
+			}
+		}
+		if rate := float64(matched) / float64(len(values)); rate >= piiMatchThreshold {
+			return candidate.kind, rate, samples
+		}
+	}
+	return "", 0, nil
+}
+
+// isPhoneNumber accepts 10 to 15 digits written with phone formatting (a leading +, spaces,
+// dashes, dots or parentheses); bare digit runs are left alone as they are usually IDs
+func isPhoneNumber(v string) bool {
+	if !piiPhonePattern.MatchString(v) || !strings.ContainsAny(v, "+ -.()") {
+		return false
+	}
+	digits := 0
+	for _, r := range v {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 10 && digits <= 15
+}
+
+// maskPII replaces the values of excluded columns in the stored samples, so previews and prompts
+// never see them. Overriding the decision later does not bring the samples back.
+func maskPII(fields []dataset.FieldInfo, sampleRows []map[string]interface{}, report *dataset.PIIReport) {
+	excluded := dataset.DatasetMetadata{PII: report}.ExcludedFields()
+	if len(excluded) == 0 {
+		return
+	}
+	for i := range fields {
+		if !excluded[fields[i].Name] {
+			continue
+		}
+		for j := range fields[i].SampleValues {
+			fields[i].SampleValues[j] = piiMask
+		}
+	}
+	for _, row := range sampleRows {
+		for name := range excluded {
+			if v, ok := row[name]; ok && v != nil {
+				row[name] = piiMask
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package dataset
+
+import (
+	"context"
+	"testing"
+
+	domainDataset "gohypo/domain/dataset"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rejectingConfirmer struct{ field string }
+
+func (r rejectingConfirmer) ConfirmPII(ctx context.Context, field string, kind domainDataset.PIIKind, samples []string) (bool, error) {
+	return field != r.field, nil
+}
+
+func TestScanPIIFlagsAndMasksPersonalColumns(t *testing.T) {
+	fields := []domainDataset.FieldInfo{
+		{Name: "email", SampleValues: []interface{}{"ann@example.com"}},
+		{Name: "phone"},
+		{Name: "ssn"},
+		{Name: "customer_name"},
+		{Name: "city_name"},
+		{Name: "order_id"},
+		{Name: "revenue"},
+	}
+	rows := []map[string]interface{}{
+		{"email": "ann@example.com", "phone": "(555) 123-4567", "ssn": "123-45-6789", "customer_name": "Ann Lee", "city_name": "Boston", "order_id": "5551234567", "revenue": "12.5"},
+		{"email": "bob@example.org", "phone": "+1 555 987 6543", "ssn": "987-65-4321", "customer_name": "Bob Stone", "city_name": "Denver", "order_id": "5559876543", "revenue": "7"},
+		{"email": "cy@example.net", "phone": "555.222.3333", "ssn": "111-22-3333", "customer_name": "Cy Young", "city_name": "Austin", "order_id": "5552223333", "revenue": "3.25"},
+	}
+
+	report := ScanPII(context.Background(), fields, rows, nil)
+	kinds := map[string]domainDataset.PIIKind{}
+	for _, col := range report.Columns {
+		kinds[col.Field] = col.Kind
+		assert.True(t, col.Excluded, col.Field)
+	}
+	assert.Equal(t, map[string]domainDataset.PIIKind{
+		"email":         domainDataset.PIIEmail,
+		"phone":         domainDataset.PIIPhone,
+		"ssn":           domainDataset.PIISSN,
+		"customer_name": domainDataset.PIIName,
+	}, kinds)
+
+	sample := []map[string]interface{}{{"email": "ann@example.com", "revenue": "12.5"}}
+	maskPII(fields, sample, report)
+	assert.Equal(t, piiMask, sample[0]["email"])
+	assert.Equal(t, piiMask, fields[0].SampleValues[0])
+	assert.Equal(t, "12.5", sample[0]["revenue"])
+
+	confirmed := ScanPII(context.Background(), fields, rows, rejectingConfirmer{field: "customer_name"})
+	excluded := domainDataset.DatasetMetadata{PII: confirmed}.ExcludedFields()
+	assert.False(t, excluded["customer_name"])
+	assert.True(t, excluded["email"])
+	assert.Equal(t, "regex+llm", confirmed.Column("customer_name").Source)
+}
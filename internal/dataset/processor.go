@@ -35,6 +35,7 @@ import (
 	"strings"
 	"time"
 
+	"gohypo/adapters/datareadiness/jsonevents"
 	"gohypo/adapters/excel"
 	"gohypo/ai"
 	"gohypo/domain/core"
@@ -55,6 +56,12 @@ type Processor struct {
 	config             *StorageConfig
 	Merger             *Merger
 	RelationshipEngine *RelationshipDiscoveryEngine
+
+	// OnReady is called with each dataset version that finishes processing (optional)
+	OnReady func(datasetID core.ID)
+
+	// PIIConfirmer asks an LLM to confirm the columns the PII scan flags (optional, nil = regex only)
+	PIIConfirmer PIIConfirmer
 }
 
 // FileStorage defines the interface for file storage operations
@@ -81,6 +88,9 @@ type StorageConfig struct {
 	ChunkSize     int           // Chunk size for streaming (default 1MB)
 	EnableCleanup bool          // Auto-cleanup temporary files
 	CleanupAfter  time.Duration // How long to keep temp files
+
+	// Encryption at rest
+	KeyProvider KeyProvider // Envelope KEKs for local AES-GCM encryption (nil = plaintext)
 }
 
 // DefaultStorageConfig returns sensible defaults
@@ -90,7 +100,7 @@ func DefaultStorageConfig() *StorageConfig {
 		MaxFileSize:   50 * 1024 * 1024, // 50MB
 		MaxMemoryMB:   512,              // 512MB
 		TempDir:       os.TempDir(),
-		AllowedTypes:  []string{"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "application/vnd.ms-excel", "text/csv"},
+		AllowedTypes:  []string{"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "application/vnd.ms-excel", "text/csv", "application/json", "application/x-ndjson"},
 		ChunkSize:     1024 * 1024, // 1MB
 		EnableCleanup: true,
 		CleanupAfter:  time.Hour,
@@ -116,6 +126,9 @@ func NewProcessorWithConfig(forensicScout *ai.ForensicScout, repository ports.Da
 		ValidateSchema: true,
 	}
 
+	relationshipEngine := NewRelationshipDiscoveryEngine(forensicScout, repository, workspaceRepo, NewMerger(db, fileStorage, mergeConfig), db)
+	relationshipEngine.SetColumnValueReader(NewFileColumnReader(fileStorage))
+
 	return &Processor{
 		forensicScout:      forensicScout,
 		repository:         repository,
@@ -124,7 +137,7 @@ func NewProcessorWithConfig(forensicScout *ai.ForensicScout, repository ports.Da
 		sseHub:             sseHub,
 		config:             config,
 		Merger:             NewMerger(db, fileStorage, mergeConfig),
-		RelationshipEngine: NewRelationshipDiscoveryEngine(forensicScout, repository, workspaceRepo, NewMerger(db, fileStorage, mergeConfig), db),
+		RelationshipEngine: relationshipEngine,
 	}
 }
 
@@ -159,6 +172,7 @@ func (p *Processor) ProcessUpload(ctx context.Context, upload *dataset.DatasetUp
 	// Create initial dataset record with row count
 	ds := dataset.NewDataset(upload.UserID, upload.Filename)
 	ds.WorkspaceID = upload.WorkspaceID
+	ds.Source = uploadSource(upload)
 	ds.MimeType = upload.MimeType
 	if ds.MimeType == "" {
 		// Fallback mime type based on file extension
@@ -168,6 +182,10 @@ func (p *Processor) ProcessUpload(ctx context.Context, upload *dataset.DatasetUp
 			ds.MimeType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
 		} else if strings.HasSuffix(strings.ToLower(upload.Filename), ".xls") {
 			ds.MimeType = "application/vnd.ms-excel"
+		} else if strings.HasSuffix(strings.ToLower(upload.Filename), ".json") {
+			ds.MimeType = "application/json"
+		} else if strings.HasSuffix(strings.ToLower(upload.Filename), ".jsonl") || strings.HasSuffix(strings.ToLower(upload.Filename), ".ndjson") {
+			ds.MimeType = "application/x-ndjson"
 		} else {
 			ds.MimeType = "application/octet-stream"
 		}
@@ -251,7 +269,7 @@ func (p *Processor) processInBackground(ctx context.Context, datasetID core.ID,
 
 	// Step 2: Parse the file to extract metadata
 	p.broadcastProgress(datasetID, "upload_progress", 30, "Parsing file and extracting metadata...")
-	parsedData, err := p.parseFile(upload.File.(multipart.File), upload.MimeType)
+	parsedData, err := p.parseFile(upload.File.(multipart.File), upload.MimeType, upload.Locale)
 	if err != nil {
 		p.broadcastProgress(datasetID, "upload_failed", 0, fmt.Sprintf("Failed to parse file: %v", err))
 		return fmt.Errorf("failed to parse file: %w", err)
@@ -262,6 +280,10 @@ func (p *Processor) processInBackground(ctx context.Context, datasetID core.ID,
 		seeker.Seek(0, io.SeekStart)
 	}
 
+	// Step 2b: Flag columns holding personal data so they stay out of matrices
+	p.broadcastProgress(datasetID, "upload_progress", 45, "Scanning columns for personal data...")
+	piiReport := ScanPII(ctx, parsedData.Fields, parsedData.Rows, p.PIIConfirmer)
+
 	// Step 3: Run Forensic Scout analysis
 	p.broadcastProgress(datasetID, "upload_progress", 60, "Analyzing data structure with AI...")
 	scoutResult, err := p.runForensicScout(ctx, parsedData.Fields)
@@ -282,6 +304,9 @@ func (p *Processor) processInBackground(ctx context.Context, datasetID core.ID,
 	// Step 5: Generate description
 	description := p.generateDescription(scoutResult, stats, parsedData)
 
+	// Mask flagged values before the samples are stored
+	maskPII(parsedData.Fields, parsedData.SampleRows, piiReport)
+
 	// Step 6: Update dataset record
 	updateDataset := &dataset.Dataset{
 		ID:               datasetID,
@@ -295,6 +320,7 @@ func (p *Processor) processInBackground(ctx context.Context, datasetID core.ID,
 		RecordCount:      len(parsedData.Rows),
 		FieldCount:       len(parsedData.Fields),
 		MissingRate:      stats.OverallMissingRate,
+		Source:           uploadSource(upload),
 		Status:           dataset.StatusReady,
 		Metadata: dataset.DatasetMetadata{
 			Fields:     parsedData.Fields,
@@ -304,6 +330,11 @@ func (p *Processor) processInBackground(ctx context.Context, datasetID core.ID,
 				DatasetName: scoutResult.DatasetName,
 				AnalyzedAt:  time.Now(),
 			},
+			PII:        piiReport,
+			Provenance: upload.Provenance,
+			Locale:     parsedData.Locale,
+			TimeZone:   uploadTimeZone(upload),
+			Lineage:    upload.Lineage,
 		},
 		UpdatedAt: time.Now(),
 	}
@@ -316,6 +347,10 @@ func (p *Processor) processInBackground(ctx context.Context, datasetID core.ID,
 	// Relationship discovery is now triggered manually via UI buttons
 	// Removed automatic relationship discovery after upload
 
+	if p.OnReady != nil {
+		p.OnReady(datasetID)
+	}
+
 	p.broadcastProgress(datasetID, "upload_completed", 100, fmt.Sprintf("Dataset '%s' ready for analysis!", scoutResult.DatasetName))
 
 	log.Printf("[DatasetProcessor] ✅ Successfully processed dataset: %s (%s) with %d fields and %d records",
@@ -323,28 +358,53 @@ func (p *Processor) processInBackground(ctx context.Context, datasetID core.ID,
 	return nil
 }
 
+// uploadSource is the source a dataset is recorded under: the upload's, else "upload"
+func uploadSource(upload *dataset.DatasetUpload) string {
+	if upload.Source != "" {
+		return upload.Source
+	}
+	return "upload"
+}
+
+// uploadTimeZone is the zone the upload names, or nil when it names none; it was validated
+// with the upload
+func uploadTimeZone(upload *dataset.DatasetUpload) *core.TimeZone {
+	if upload.TimeZone == "" {
+		return nil
+	}
+	zone, err := core.ResolveTimeZone(upload.TimeZone, core.TimeZoneFromDataset)
+	if err != nil {
+		return nil
+	}
+	return &zone
+}
+
 // ParsedFileData represents the extracted data from a file
 type ParsedFileData struct {
 	Fields     []dataset.FieldInfo
 	Rows       []map[string]interface{}
 	SampleRows []map[string]interface{}
+	Locale     *dataset.Locale // nil for JSON without a named locale
 }
 
-// parseFile extracts data from various file formats
-func (p *Processor) parseFile(file multipart.File, mimeType string) (*ParsedFileData, error) {
+// parseFile extracts data from various file formats, reading numbers and dates in the named
+// locale or, when it is empty or auto, the one detected from the file
+func (p *Processor) parseFile(file multipart.File, mimeType, locale string) (*ParsedFileData, error) {
 	// Determine file type and parse accordingly
 	switch {
 	case strings.Contains(mimeType, "spreadsheet") || strings.HasSuffix(strings.ToLower(mimeType), "xlsx") || strings.HasSuffix(strings.ToLower(mimeType), "xls"):
-		return p.parseExcelFile(file)
+		return p.parseExcelFile(file, locale)
 	case strings.Contains(mimeType, "csv") || strings.HasSuffix(strings.ToLower(mimeType), "csv"):
-		return p.parseCSVFile(file)
+		return p.parseCSVFile(file, locale)
+	case isJSONMimeType(mimeType):
+		return p.parseJSONFile(file, locale)
 	default:
 		return nil, fmt.Errorf("unsupported file type: %s", mimeType)
 	}
 }
 
 // parseExcelFile parses Excel files with cloud-friendly temporary storage
-func (p *Processor) parseExcelFile(file multipart.File) (*ParsedFileData, error) {
+func (p *Processor) parseExcelFile(file multipart.File, locale string) (*ParsedFileData, error) {
 	// Create temporary file with proper cleanup
 	tempFile, err := p.createTempFile(file, "dataset_excel_*.xlsx")
 	if err != nil {
@@ -362,6 +422,30 @@ func (p *Processor) parseExcelFile(file multipart.File) (*ParsedFileData, error)
 		return nil, fmt.Errorf("failed to read Excel data: %w", err)
 	}
 
+	return p.parseTableData(data, locale)
+}
+
+// parseJSONFile parses JSON and JSONL event files, flattening nested objects into dot-path
+// fields and arrays into their count, latest and exists fields
+func (p *Processor) parseJSONFile(file multipart.File, locale string) (*ParsedFileData, error) {
+	if seeker, ok := file.(io.Seeker); ok {
+		seeker.Seek(0, io.SeekStart)
+	}
+
+	doc, err := jsonevents.Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON data: %w", err)
+	}
+	return p.parseTableData(excel.JSONData(doc), locale)
+}
+
+// parseTableData converts rows read by the excel adapter into parsed file data
+func (p *Processor) parseTableData(data *excel.ExcelData, localeName string) (*ParsedFileData, error) {
+	locale, err := datasetLocale(localeName, data.Locale)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert to our format
 	fields := make([]dataset.FieldInfo, len(data.Headers))
 	rows := make([]map[string]interface{}, len(data.Rows))
@@ -376,10 +460,11 @@ func (p *Processor) parseExcelFile(file multipart.File) (*ParsedFileData, error)
 
 		fields[i] = dataset.FieldInfo{
 			Name:         header,
-			DataType:     p.inferDataType(data.Rows, header),
+			DataType:     p.inferDataType(data.Rows, header, locale),
 			SampleValues: sampleInterfaces,
 		}
 	}
+	applyColumnNames(fields, data.Columns)
 
 	// Process rows - convert RawRowData (map[string]string) to map[string]interface{}
 	for i, row := range data.Rows {
@@ -405,15 +490,46 @@ func (p *Processor) parseExcelFile(file multipart.File) (*ParsedFileData, error)
 		fields[i].Nullable = fields[i].MissingCount > 0
 	}
 
-	return &ParsedFileData{
+	parsed := &ParsedFileData{
 		Fields:     fields,
 		Rows:       rows,
 		SampleRows: sampleRows,
-	}, nil
+	}
+	if locale.Name != "" {
+		parsed.Locale = &locale
+	}
+	return parsed, nil
+}
+
+// datasetLocale is the named locale, or the detected one when the name is empty or auto
+func datasetLocale(name string, detected dataset.Locale) (dataset.Locale, error) {
+	if name == "" || name == dataset.LocaleAuto {
+		return detected, nil
+	}
+	return dataset.ResolveLocale(name, nil)
+}
+
+// parseNumber parses a value in the locale, or as a plain Go float when there is none
+func parseNumber(locale dataset.Locale, value string) (float64, bool) {
+	if locale.Name == "" {
+		val, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return val, err == nil
+	}
+	return locale.ParseNumber(value)
+}
+
+// applyColumnNames records the header and unit each field's name was normalized from
+func applyColumnNames(fields []dataset.FieldInfo, columns dataset.ColumnNames) {
+	for i := range fields {
+		if column, ok := columns.Lookup(fields[i].Name); ok && column.Renamed() {
+			fields[i].OriginalName = column.Original
+			fields[i].Unit = column.Unit
+		}
+	}
 }
 
 // parseCSVFile parses CSV files with proper field analysis
-func (p *Processor) parseCSVFile(file multipart.File) (*ParsedFileData, error) {
+func (p *Processor) parseCSVFile(file multipart.File, localeName string) (*ParsedFileData, error) {
 	// Reset file position to beginning
 	if seeker, ok := file.(io.Seeker); ok {
 		seeker.Seek(0, io.SeekStart)
@@ -432,9 +548,14 @@ func (p *Processor) parseCSVFile(file multipart.File) (*ParsedFileData, error) {
 		return nil, fmt.Errorf("CSV file is empty")
 	}
 
-	// First row is headers
-	headers := records[0]
+	// First row is headers, normalized the way the matrix resolver reads them
+	columns := dataset.NormalizeColumnNames(records[0])
+	headers := columns.Keys()
 	dataRows := records[1:]
+	locale, err := datasetLocale(localeName, dataset.DetectTableLocale(dataRows))
+	if err != nil {
+		return nil, err
+	}
 
 	// Convert data rows to map format for consistency with Excel parsing
 	rows := make([]map[string]interface{}, len(dataRows))
@@ -465,10 +586,11 @@ func (p *Processor) parseCSVFile(file multipart.File) (*ParsedFileData, error) {
 
 		fields[i] = dataset.FieldInfo{
 			Name:         header,
-			DataType:     p.inferDataTypeFromCSV(dataRows, i),
+			DataType:     p.inferDataTypeFromCSV(dataRows, i, locale),
 			SampleValues: sampleInterfaces,
 		}
 	}
+	applyColumnNames(fields, columns)
 
 	// Extract sample rows efficiently
 	const maxSampleRows = 100
@@ -485,6 +607,7 @@ func (p *Processor) parseCSVFile(file multipart.File) (*ParsedFileData, error) {
 		Fields:     fields,
 		Rows:       rows,
 		SampleRows: sampleRows,
+		Locale:     &locale,
 	}, nil
 }
 
@@ -515,8 +638,8 @@ func (p *Processor) getSampleValuesFromCSV(records [][]string, colIndex int, max
 	return samples
 }
 
-// inferDataTypeFromCSV infers data type from CSV column values
-func (p *Processor) inferDataTypeFromCSV(records [][]string, colIndex int) string {
+// inferDataTypeFromCSV infers data type from CSV column values written in the locale
+func (p *Processor) inferDataTypeFromCSV(records [][]string, colIndex int, locale dataset.Locale) string {
 	if colIndex >= len(records[0]) {
 		return "text"
 	}
@@ -557,12 +680,12 @@ func (p *Processor) inferDataTypeFromCSV(records [][]string, colIndex int) strin
 		}
 
 		// Check for numbers (integers and floats)
-		if _, err := strconv.ParseFloat(value, 64); err == nil {
+		if _, ok := parseNumber(locale, value); ok {
 			hasNumbers = true
 		}
 
-		// Check for dates (basic patterns)
-		if p.isLikelyDate(value) {
+		// Check for dates (basic patterns, and numeric dates the locale can read)
+		if _, ok := locale.ParseDate(value); ok || p.isLikelyDate(value) {
 			hasDates = true
 		}
 	}
@@ -593,6 +716,12 @@ func (p *Processor) quickCountRowsAndFields(file multipart.File, mimeType string
 		return p.quickCountExcel(file)
 	case strings.Contains(mimeType, "csv") || strings.HasSuffix(strings.ToLower(mimeType), "csv"):
 		return p.quickCountCSV(file)
+	case isJSONMimeType(mimeType):
+		doc, err := jsonevents.Parse(file)
+		if err != nil {
+			return 0, 0, err
+		}
+		return len(doc.Records), len(doc.Keys), nil
 	default:
 		// For unknown types, return 0,0
 		return 0, 0, nil
@@ -723,7 +852,7 @@ func (p *Processor) generateDescription(scout *ai.ScoutResponse, stats *DatasetS
 }
 
 // Helper methods for data analysis
-func (p *Processor) inferDataType(rows []excel.RawRowData, fieldName string) string {
+func (p *Processor) inferDataType(rows []excel.RawRowData, fieldName string, locale dataset.Locale) string {
 	// Simple type inference - check first few non-null values
 	sampleValues := p.getSampleValues(rows, fieldName, 10)
 
@@ -736,7 +865,7 @@ func (p *Processor) inferDataType(rows []excel.RawRowData, fieldName string) str
 		}
 
 		// Check if it's numeric
-		if _, err := strconv.ParseFloat(val, 64); err == nil {
+		if _, ok := parseNumber(locale, val); ok {
 			hasNumeric = true
 		} else {
 			hasText = true
@@ -841,6 +970,13 @@ func (p *Processor) validateUpload(upload *dataset.DatasetUpload) error {
 		return err
 	}
 
+	if _, err := datasetLocale(upload.Locale, dataset.Locale{}); err != nil {
+		return err
+	}
+	if _, err := core.ResolveTimeZone(upload.TimeZone, core.TimeZoneFromDataset); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -854,6 +990,12 @@ func (p *Processor) isAllowedMimeType(mimeType string) bool {
 	return false
 }
 
+// isJSONMimeType reports whether a MIME type names JSON or newline-delimited JSON
+func isJSONMimeType(mimeType string) bool {
+	mimeType = strings.ToLower(mimeType)
+	return strings.Contains(mimeType, "json")
+}
+
 // validateFileExtension ensures file extension matches the MIME type
 func (p *Processor) validateFileExtension(filename, mimeType string) error {
 	ext := strings.ToLower(filepath.Ext(filename))
@@ -871,9 +1013,13 @@ func (p *Processor) validateFileExtension(filename, mimeType string) error {
 		if ext != ".csv" {
 			return fmt.Errorf("file extension %s does not match MIME type %s", ext, mimeType)
 		}
+	case "application/json", "application/x-ndjson":
+		if ext != ".json" && ext != ".jsonl" && ext != ".ndjson" {
+			return fmt.Errorf("file extension %s does not match MIME type %s", ext, mimeType)
+		}
 	default:
 		// For other types, just check basic extension
-		validExts := []string{".xlsx", ".xls", ".csv"}
+		validExts := []string{".xlsx", ".xls", ".csv", ".json", ".jsonl", ".ndjson"}
 		for _, validExt := range validExts {
 			if ext == validExt {
 				return nil
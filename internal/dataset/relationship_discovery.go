@@ -13,6 +13,7 @@ import (
 	"gohypo/ai"
 	"gohypo/domain/core"
 	domainDataset "gohypo/domain/dataset"
+	"gohypo/internal/access"
 	"gohypo/ports"
 
 	"github.com/jmoiron/sqlx"
@@ -25,6 +26,15 @@ type RelationshipDiscoveryEngine struct {
 	workspaceRepo ports.WorkspaceRepository
 	merger        *Merger
 	db            *sqlx.DB
+
+	// Column-level access policy (optional); restricted fields never reach the scout
+	columnEnforcer *access.Enforcer
+
+	// Embedding column matcher (optional); matches columns whose names diverge
+	columnMatcher *ColumnMatcher
+
+	// Value overlap detector (optional); finds join keys from the values columns share
+	keyDetector *KeyOverlapDetector
 }
 
 // DiscoveryResult represents the result of relationship discovery
@@ -44,6 +54,16 @@ type MergeSuggestion struct {
 	Reasoning        string    `json:"reasoning"`
 	ExpectedRowCount int       `json:"expected_row_count"`
 	ExpectedColumns  int       `json:"expected_columns"`
+
+	// Join keys measured between the datasets, largest containment first
+	JoinKeys []SuggestedJoinKey `json:"join_keys,omitempty"`
+}
+
+// SuggestedJoinKey is a key column pair of two datasets of a merge suggestion
+type SuggestedJoinKey struct {
+	SourceDatasetID core.ID `json:"source_dataset_id"`
+	TargetDatasetID core.ID `json:"target_dataset_id"`
+	KeyOverlap
 }
 
 // MergeType defines different types of automatic merges
@@ -73,6 +93,31 @@ func NewRelationshipDiscoveryEngine(
 	}
 }
 
+// SetColumnEnforcer enables column-level access policies during discovery
+func (rde *RelationshipDiscoveryEngine) SetColumnEnforcer(enforcer *access.Enforcer) {
+	rde.columnEnforcer = enforcer
+}
+
+// SetColumnEmbedder matches columns across datasets by embeddings of their names and sample
+// values; nil disables embedding matches
+func (rde *RelationshipDiscoveryEngine) SetColumnEmbedder(embedder ports.TextEmbedder) {
+	if embedder == nil {
+		rde.columnMatcher = nil
+		return
+	}
+	rde.columnMatcher = NewColumnMatcher(embedder)
+}
+
+// SetColumnValueReader detects join keys from the values columns of two datasets share;
+// nil disables value overlap detection
+func (rde *RelationshipDiscoveryEngine) SetColumnValueReader(reader ColumnValueReader) {
+	if reader == nil {
+		rde.keyDetector = nil
+		return
+	}
+	rde.keyDetector = NewKeyOverlapDetector(reader)
+}
+
 // DiscoverRelationships analyzes all datasets in a workspace and discovers relationships
 func (rde *RelationshipDiscoveryEngine) DiscoverRelationships(ctx context.Context, workspaceID core.ID) (*DiscoveryResult, error) {
 	return rde.DiscoverRelationshipsWithOptions(ctx, workspaceID, &DiscoveryOptions{
@@ -117,6 +162,8 @@ func (rde *RelationshipDiscoveryEngine) DiscoverRelationshipsWithOptions(ctx con
 		}
 	}
 
+	validDatasets = rde.applyColumnPolicy(ctx, validDatasets)
+
 	if len(validDatasets) < 2 {
 		return &DiscoveryResult{
 			WorkspaceID:      workspaceID,
@@ -145,6 +192,33 @@ func (rde *RelationshipDiscoveryEngine) DiscoverRelationshipsWithOptions(ctx con
 	}, nil
 }
 
+// applyColumnPolicy returns copies of the datasets with restricted fields and the
+// columns excluded as PII removed
+func (rde *RelationshipDiscoveryEngine) applyColumnPolicy(ctx context.Context, datasets []*domainDataset.Dataset) []*domainDataset.Dataset {
+	filtered := make([]*domainDataset.Dataset, len(datasets))
+	for i, ds := range datasets {
+		dsCopy := *ds
+		excluded := ds.Metadata.ExcludedFields()
+		if rde.columnEnforcer == nil && len(excluded) == 0 {
+			filtered[i] = ds
+			continue
+		}
+		fields := make([]domainDataset.FieldInfo, 0, len(ds.Metadata.Fields))
+		for _, field := range ds.Metadata.Fields {
+			if excluded[field.Name] {
+				continue
+			}
+			if rde.columnEnforcer == nil || rde.columnEnforcer.IsAllowed(ctx, core.VariableKey(field.Name)) {
+				fields = append(fields, field)
+			}
+		}
+		dsCopy.Metadata.Fields = fields
+		dsCopy.Metadata.SampleRows = nil // sample rows may contain restricted values
+		filtered[i] = &dsCopy
+	}
+	return filtered
+}
+
 // clearExistingRelationships removes all stored relationships for a workspace
 func (rde *RelationshipDiscoveryEngine) clearExistingRelationships(ctx context.Context, workspaceID core.ID) error {
 	// Get all existing relationships for this workspace
@@ -201,6 +275,7 @@ func (rde *RelationshipDiscoveryEngine) analyzeDatasetPair(ds1, ds2 *domainDatas
 	strategies := []func(*domainDataset.Dataset, *domainDataset.Dataset) *domainDataset.DatasetRelation{
 		rde.analyzeSchemaCompatibility,
 		rde.analyzeSemanticSimilarity,
+		rde.analyzeColumnEmbeddings,
 		rde.analyzeKeyRelationships,
 		rde.analyzeTemporalPatterns,
 		rde.analyzeTimeseriesCompatibility, // New timeseries-specific analysis
@@ -383,11 +458,144 @@ func (rde *RelationshipDiscoveryEngine) analyzeSemanticSimilarity(ds1, ds2 *doma
 	return nil
 }
 
-// analyzeKeyRelationships looks for foreign key relationships
+// analyzeColumnEmbeddings matches columns by embedding similarity. A matched pair of key-like
+// columns suggests a join; otherwise the share of matched columns scores schema overlap.
+func (rde *RelationshipDiscoveryEngine) analyzeColumnEmbeddings(ds1, ds2 *domainDataset.Dataset) *domainDataset.DatasetRelation {
+	if rde.columnMatcher == nil || len(ds1.Metadata.Fields) == 0 || len(ds2.Metadata.Fields) == 0 {
+		return nil
+	}
+
+	matches, err := rde.columnMatcher.Match(context.Background(), ds1, ds2)
+	if err != nil {
+		log.Printf("[RelationshipDiscoveryEngine] Column embedding match failed: %v", err)
+		return nil
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	totalSimilarity := 0.0
+	var joinKeys []ColumnMatch
+	for _, match := range matches {
+		totalSimilarity += match.Similarity
+		if match.JoinCandidate {
+			joinKeys = append(joinKeys, match)
+		}
+	}
+	meanSimilarity := totalSimilarity / float64(len(matches))
+	coverage := float64(len(matches)*2) / float64(len(ds1.Metadata.Fields)+len(ds2.Metadata.Fields))
+	confidence := coverage * meanSimilarity
+
+	var relationType string
+	switch {
+	case len(joinKeys) > 0:
+		relationType = "potential_join"
+		// Matches are sorted by similarity, so the first join key is the strongest
+		if joinConfidence := 0.5 + 0.4*joinKeys[0].Similarity; joinConfidence > confidence {
+			confidence = joinConfidence
+		}
+	case coverage > 0.8:
+		relationType = "schema_match"
+	case coverage > 0.5:
+		relationType = "partial_schema_match"
+	default:
+		relationType = "weak_schema_match"
+	}
+
+	if confidence < 0.3 {
+		return nil
+	}
+
+	return &domainDataset.DatasetRelation{
+		WorkspaceID:     ds1.WorkspaceID,
+		SourceDatasetID: ds1.ID,
+		TargetDatasetID: ds2.ID,
+		RelationType:    relationType,
+		Confidence:      confidence,
+		Metadata: map[string]interface{}{
+			"column_matches":  matches,
+			"join_keys":       joinKeys,
+			"coverage":        coverage,
+			"mean_similarity": meanSimilarity,
+			"embedding_model": rde.columnMatcher.Model(),
+			"analysis_type":   "column_embeddings",
+		},
+		DiscoveredAt: time.Now(),
+	}
+}
+
+// analyzeKeyRelationships looks for foreign key relationships in the values the datasets'
+// columns share. A column whose values are nearly all found in a unique column of the other
+// dataset references it; other large overlaps are shared join keys.
 func (rde *RelationshipDiscoveryEngine) analyzeKeyRelationships(ds1, ds2 *domainDataset.Dataset) *domainDataset.DatasetRelation {
-	// This would analyze actual data to find key relationships
-	// For now, return a placeholder
-	return nil
+	if rde.keyDetector == nil || ds1.FilePath == "" || ds2.FilePath == "" {
+		return nil
+	}
+
+	overlaps, err := rde.keyDetector.Detect(context.Background(), ds1, ds2)
+	if err != nil {
+		log.Printf("[RelationshipDiscoveryEngine] Key overlap detection failed: %v", err)
+		return nil
+	}
+	if len(overlaps) == 0 {
+		return rde.analyzeEntityNames(ds1, ds2)
+	}
+
+	// Prefer a foreign key over a larger but undirected overlap
+	best := overlaps[0]
+	for _, overlap := range overlaps {
+		if overlap.Direction != "" {
+			best = overlap
+			break
+		}
+	}
+
+	relationType := "potential_join"
+	confidence := 0.4 + 0.4*best.Containment()
+	if best.Direction != "" {
+		relationType = "foreign_key"
+		confidence = 0.7 + 0.25*best.Containment()
+	}
+
+	return &domainDataset.DatasetRelation{
+		WorkspaceID:     ds1.WorkspaceID,
+		SourceDatasetID: ds1.ID,
+		TargetDatasetID: ds2.ID,
+		RelationType:    relationType,
+		Confidence:      confidence,
+		Metadata: map[string]interface{}{
+			"join_key":      best,
+			"key_overlaps":  overlaps,
+			"analysis_type": "value_overlap",
+		},
+		DiscoveredAt: time.Now(),
+	}
+}
+
+// analyzeEntityNames looks for name columns whose values differ in spelling but resolve to the
+// same entities, such as a customer_name and a client column
+func (rde *RelationshipDiscoveryEngine) analyzeEntityNames(ds1, ds2 *domainDataset.Dataset) *domainDataset.DatasetRelation {
+	match, err := ResolveEntityColumns(context.Background(), rde.keyDetector.reader, ds1, ds2, DefaultEntityResolutionConfig())
+	if err != nil {
+		log.Printf("[RelationshipDiscoveryEngine] Entity resolution failed: %v", err)
+		return nil
+	}
+	if match == nil {
+		return nil
+	}
+
+	return &domainDataset.DatasetRelation{
+		WorkspaceID:     ds1.WorkspaceID,
+		SourceDatasetID: ds1.ID,
+		TargetDatasetID: ds2.ID,
+		RelationType:    "entity_match",
+		Confidence:      0.35 + 0.4*match.Resolution.Report.MatchRate,
+		Metadata: map[string]interface{}{
+			"entity_match":  match,
+			"analysis_type": "entity_resolution",
+		},
+		DiscoveredAt: time.Now(),
+	}
 }
 
 // analyzeTemporalPatterns looks for time-based relationships
@@ -715,9 +923,31 @@ func (rde *RelationshipDiscoveryEngine) createMergeSuggestion(primary *domainDat
 		Reasoning:        rde.generateMergeReasoning(allDatasets, relationships),
 		ExpectedRowCount: expectedRows,
 		ExpectedColumns:  expectedCols,
+		JoinKeys:         rde.suggestedJoinKeys(sourceIDs, relationships),
 	}
 }
 
+// suggestedJoinKeys collects the join keys value overlap measured between the datasets
+func (rde *RelationshipDiscoveryEngine) suggestedJoinKeys(datasetIDs []core.ID, relationships []*domainDataset.DatasetRelation) []SuggestedJoinKey {
+	members := make(map[core.ID]bool, len(datasetIDs))
+	for _, id := range datasetIDs {
+		members[id] = true
+	}
+	var keys []SuggestedJoinKey
+	for _, rel := range relationships {
+		if !members[rel.SourceDatasetID] || !members[rel.TargetDatasetID] {
+			continue
+		}
+		if key, ok := rel.Metadata["join_key"].(KeyOverlap); ok {
+			keys = append(keys, SuggestedJoinKey{SourceDatasetID: rel.SourceDatasetID, TargetDatasetID: rel.TargetDatasetID, KeyOverlap: key})
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].Containment() > keys[j].Containment()
+	})
+	return keys
+}
+
 // Helper methods
 
 func (rde *RelationshipDiscoveryEngine) calculateStringSimilarity(s1, s2 string) float64 {
@@ -832,6 +1062,7 @@ func (rde *RelationshipDiscoveryEngine) determineMergeType(datasets []*domainDat
 	hasSemanticMatch := false
 	hasTemporalMatch := false
 	hasTimeseriesMatch := false
+	hasJoinMatch := false
 
 	for _, rel := range relationships {
 		switch rel.RelationType {
@@ -843,6 +1074,8 @@ func (rde *RelationshipDiscoveryEngine) determineMergeType(datasets []*domainDat
 			hasTemporalMatch = true
 		case "timeseries_merge_candidate":
 			hasTimeseriesMatch = true
+		case "potential_join", "foreign_key", "entity_match":
+			hasJoinMatch = true
 		}
 	}
 
@@ -852,6 +1085,11 @@ func (rde *RelationshipDiscoveryEngine) determineMergeType(datasets []*domainDat
 		return AutoJoin
 	}
 
+	// Matched key columns join directly
+	if hasJoinMatch {
+		return AutoJoin
+	}
+
 	// Determine merge type based on relationship patterns
 	if hasSchemaMatch && hasSemanticMatch {
 		// Strong schema and semantic matches suggest append/consolidate
@@ -922,8 +1160,8 @@ func (rde *RelationshipDiscoveryEngine) calculateMergeConfidence(datasets []*dom
 	for _, rel := range relationships {
 		weight := 1.0
 		switch rel.RelationType {
-		case "schema_match":
-			weight = 3.0 // Schema matches are strongest indicators
+		case "schema_match", "foreign_key":
+			weight = 3.0 // Schema matches and measured foreign keys are strongest indicators
 		case "semantic_similarity":
 			weight = 2.5 // Semantic matches are very strong
 		case "partial_schema_match":
@@ -959,7 +1197,27 @@ func (rde *RelationshipDiscoveryEngine) calculateMergeConfidence(datasets []*dom
 }
 
 func (rde *RelationshipDiscoveryEngine) generateMergeReasoning(datasets []*domainDataset.Dataset, relationships []*domainDataset.DatasetRelation) string {
-	// Generate human-readable reasoning for the merge suggestion
+	ids := make([]core.ID, len(datasets))
+	for i, ds := range datasets {
+		ids[i] = ds.ID
+	}
+	// Measured join keys are the strongest reason to merge, so they lead the reasoning
+	if keys := rde.suggestedJoinKeys(ids, relationships); len(keys) > 0 {
+		key := keys[0]
+		switch key.Direction {
+		case "source_references_target":
+			return fmt.Sprintf("%s references %s: %.0f%% of its values are found there", key.SourceColumn, key.TargetColumn, key.SourceContainment*100)
+		case "target_references_source":
+			return fmt.Sprintf("%s references %s: %.0f%% of its values are found there", key.TargetColumn, key.SourceColumn, key.TargetContainment*100)
+		default:
+			return fmt.Sprintf("%s and %s share %.0f%% of their values", key.SourceColumn, key.TargetColumn, key.Containment()*100)
+		}
+	}
+	for _, rel := range relationships {
+		if match, ok := rel.Metadata["entity_match"].(*EntityColumnMatch); ok {
+			return fmt.Sprintf("%s and %s name the same entities: %.0f%% of the names resolved", match.SourceColumn, match.TargetColumn, match.Resolution.Report.MatchRate*100)
+		}
+	}
 	return "Datasets appear to be related based on schema and semantic analysis"
 }
 
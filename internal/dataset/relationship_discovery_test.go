@@ -110,6 +110,11 @@ func (m *MockWorkspaceRepository) GetWithDatasets(ctx context.Context, id core.I
 	return args.Get(0).(*ports.WorkspaceWithDatasets), args.Error(1)
 }
 
+func (m *MockWorkspaceRepository) Reassign(ctx context.Context, id, userID core.ID) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
 func (m *MockWorkspaceRepository) CreateRelation(ctx context.Context, relation *domainDataset.DatasetRelation) error {
 	args := m.Called(ctx, relation)
 	m.relations = append(m.relations, relation)
@@ -0,0 +1,27 @@
+package dataset
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+// TestFetchRemoteRefusesInternalAddresses verifies a URL cannot reach services inside the
+// deployment
+func TestFetchRemoteRefusesInternalAddresses(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.0.0.8", "169.254.169.254", "::1"} {
+		if publicIP(net.ParseIP(ip)) {
+			t.Errorf("expected %s not to count as public", ip)
+		}
+	}
+	if !publicIP(net.ParseIP("8.8.8.8")) {
+		t.Error("expected 8.8.8.8 to count as public")
+	}
+
+	p := &Processor{config: DefaultStorageConfig()}
+	_, err := p.FetchRemote(context.Background(), "https://127.0.0.1:1/data.csv", "")
+	if err == nil || !strings.Contains(err.Error(), "non-public address") {
+		t.Errorf("expected the loopback fetch to be refused, got %v", err)
+	}
+}
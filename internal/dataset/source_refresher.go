@@ -0,0 +1,192 @@
+package dataset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gohypo/adapters/sqlsource"
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// sourceRefreshTimeout bounds one pull from a source database or one remote download
+const sourceRefreshTimeout = 10 * time.Minute
+
+// SourceRefresher pulls SQL and URL data sources into the readiness pipeline, on request and
+// on each source's schedule. Every pull registers a new dataset carrying its provenance.
+type SourceRefresher struct {
+	sqlSources ports.SQLDataSourceStore
+	urlSources ports.URLDataSourceStore
+	processor  *Processor
+}
+
+// NewSourceRefresher creates a refresher registering pulled datasets through processor
+func NewSourceRefresher(sqlSources ports.SQLDataSourceStore, urlSources ports.URLDataSourceStore, processor *Processor) *SourceRefresher {
+	return &SourceRefresher{sqlSources: sqlSources, urlSources: urlSources, processor: processor}
+}
+
+// RefreshSQL pulls a SQL source and hands the rows to the dataset processor, recording the
+// outcome on the source. The returned dataset is still processing.
+func (r *SourceRefresher) RefreshSQL(ctx context.Context, source *models.SQLDataSource) (core.ID, error) {
+	datasetID, err := r.refreshSQL(ctx, source)
+	if recordErr := r.sqlSources.RecordRefresh(ctx, source.ID, time.Now(), string(datasetID), errorMessage(err)); recordErr != nil {
+		log.Printf("[SourceRefresher] Failed to record refresh of %s: %v", source.ID, recordErr)
+	}
+	return datasetID, err
+}
+
+func (r *SourceRefresher) refreshSQL(ctx context.Context, source *models.SQLDataSource) (core.ID, error) {
+	pullCtx, cancel := context.WithTimeout(ctx, sourceRefreshTimeout)
+	defer cancel()
+	extract, err := sqlsource.Pull(pullCtx, source)
+	if err != nil {
+		return "", refreshFailed(fmt.Sprintf("failed to pull %s", source.Name), err)
+	}
+	if extract.Rows == 0 {
+		return "", refreshFailed(fmt.Sprintf("source %s returned no rows", source.Name), nil)
+	}
+
+	upload := &dataset.DatasetUpload{
+		UserID:      core.ID(source.UserID),
+		WorkspaceID: core.ID(source.WorkspaceID),
+		Filename:    sourceFilename(source.Name),
+		File:        sqlsource.NewFile(extract),
+		MimeType:    "text/csv",
+		Source:      "sql",
+		Provenance: &dataset.Provenance{
+			Kind:      "sql",
+			SourceID:  source.ID,
+			Driver:    source.Driver,
+			Location:  models.RedactDSN(source.DSN),
+			Query:     extract.Statement,
+			Rows:      extract.Rows,
+			Truncated: extract.Truncated,
+			PulledAt:  extract.PulledAt,
+		},
+	}
+	datasetID, err := r.processor.ProcessUpload(ctx, upload)
+	if err != nil {
+		return "", refreshFailed(fmt.Sprintf("failed to register dataset from %s", source.Name), err)
+	}
+	log.Printf("[SourceRefresher] Pulled %d rows from %s into dataset %s", extract.Rows, source.Name, datasetID)
+	return datasetID, nil
+}
+
+// RefreshURL fetches a URL source and registers the file as a dataset unless it is
+// unchanged since the last dataset registered from it, in which case the returned ID is
+// empty. The outcome is recorded on the source.
+func (r *SourceRefresher) RefreshURL(ctx context.Context, source *models.URLDataSource) (core.ID, error) {
+	datasetID, checksum, err := r.refreshURL(ctx, source)
+	if recordErr := r.urlSources.RecordRefresh(ctx, source.ID, time.Now(), string(datasetID), checksum, errorMessage(err)); recordErr != nil {
+		log.Printf("[SourceRefresher] Failed to record refresh of %s: %v", source.ID, recordErr)
+	}
+	return datasetID, err
+}
+
+func (r *SourceRefresher) refreshURL(ctx context.Context, source *models.URLDataSource) (core.ID, string, error) {
+	token, err := models.ResolveSecret(source.Token)
+	if err != nil {
+		return "", "", refreshFailed(fmt.Sprintf("failed to resolve the token of %s", source.Name), err)
+	}
+	fetchCtx, cancel := context.WithTimeout(ctx, sourceRefreshTimeout)
+	defer cancel()
+	remote, err := r.processor.FetchRemote(fetchCtx, source.URL, token)
+	if err != nil {
+		return "", "", refreshFailed(fmt.Sprintf("failed to fetch %s", source.Name), err)
+	}
+	if remote.Checksum == source.LastChecksum && source.LastDatasetID != "" {
+		return "", "", nil
+	}
+	datasetID, err := r.processor.ProcessRemote(ctx, core.ID(source.UserID), core.ID(source.WorkspaceID), source.Name, source.ID, remote)
+	if err != nil {
+		return "", "", refreshFailed(fmt.Sprintf("failed to register dataset from %s", source.Name), err)
+	}
+	log.Printf("[SourceRefresher] Fetched %s into dataset %s", source.Name, datasetID)
+	return datasetID, remote.Checksum, nil
+}
+
+// Run refreshes due sources every interval until ctx is done
+func (r *SourceRefresher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.RefreshDue(ctx, now)
+		}
+	}
+}
+
+// RefreshDue refreshes every scheduled source due at now, one after the other, and returns
+// how many were pulled
+func (r *SourceRefresher) RefreshDue(ctx context.Context, now time.Time) int {
+	refreshed := 0
+	if sources, err := r.sqlSources.ListScheduled(ctx); err != nil {
+		log.Printf("[SourceRefresher] Failed to list scheduled SQL sources: %v", err)
+	} else {
+		for _, source := range sources {
+			if !source.Due(now) {
+				continue
+			}
+			if _, err := r.RefreshSQL(ctx, source); err != nil {
+				log.Printf("[SourceRefresher] Scheduled refresh of %s failed: %v", source.Name, err)
+				continue
+			}
+			refreshed++
+		}
+	}
+	if sources, err := r.urlSources.ListScheduled(ctx); err != nil {
+		log.Printf("[SourceRefresher] Failed to list scheduled URL sources: %v", err)
+	} else {
+		for _, source := range sources {
+			if !source.Due(now) {
+				continue
+			}
+			if _, err := r.RefreshURL(ctx, source); err != nil {
+				log.Printf("[SourceRefresher] Scheduled refresh of %s failed: %v", source.Name, err)
+				continue
+			}
+			refreshed++
+		}
+	}
+	return refreshed
+}
+
+// refreshFailure is a failed refresh. Its summary is recorded on the source, where users see
+// it; the cause may carry hosts, addresses or driver output and only reaches the logs.
+type refreshFailure struct {
+	summary string
+	cause   error
+}
+
+func refreshFailed(summary string, cause error) error {
+	return &refreshFailure{summary: summary, cause: cause}
+}
+
+func (f *refreshFailure) Error() string {
+	if f.cause == nil {
+		return f.summary
+	}
+	return f.summary + ": " + f.cause.Error()
+}
+
+func (f *refreshFailure) Unwrap() error { return f.cause }
+
+// errorMessage is the text recorded for a refresh outcome, empty on success
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	var failure *refreshFailure
+	if errors.As(err, &failure) {
+		return failure.summary
+	}
+	return "refresh failed"
+}
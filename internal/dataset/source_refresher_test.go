@@ -0,0 +1,28 @@
+package dataset
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestErrorMessageKeepsCauseOutOfLastError verifies a source records only the summary of a
+// failed refresh, while the error returned for the logs keeps its cause
+func TestErrorMessageKeepsCauseOutOfLastError(t *testing.T) {
+	cause := errors.New("dial tcp 10.0.3.7:5432: connection refused")
+	err := fmt.Errorf("scheduled: %w", refreshFailed("failed to pull orders", cause))
+
+	if got := errorMessage(err); got != "failed to pull orders" {
+		t.Errorf("recorded %q, want only the summary", got)
+	}
+	if !strings.Contains(err.Error(), "10.0.3.7") || !errors.Is(err, cause) {
+		t.Errorf("the logged error should keep its cause, got %v", err)
+	}
+	if got := errorMessage(errors.New("pq: password authentication failed")); got != "refresh failed" {
+		t.Errorf("an unclassified error recorded %q", got)
+	}
+	if got := errorMessage(nil); got != "" {
+		t.Errorf("a successful refresh recorded %q", got)
+	}
+}
@@ -54,9 +54,13 @@ func (s *LocalFileStorage) Store(ctx context.Context, file multipart.File, filen
 	}
 	defer destFile.Close()
 
-	// Copy file contents with chunking for large files
-	buf := make([]byte, s.config.ChunkSize)
-	_, err = io.CopyBuffer(destFile, file, buf)
+	// Copy file contents with chunking for large files, encrypting when a key provider is configured
+	if s.config.KeyProvider != nil {
+		err = encryptStream(ctx, s.config.KeyProvider, destFile, file, s.config.ChunkSize)
+	} else {
+		buf := make([]byte, s.config.ChunkSize)
+		_, err = io.CopyBuffer(destFile, file, buf)
+	}
 	if err != nil {
 		os.Remove(filePath) // Clean up on failure
 		return "", fmt.Errorf("failed to copy file contents: %w", err)
@@ -65,13 +69,31 @@ func (s *LocalFileStorage) Store(ctx context.Context, file multipart.File, filen
 	return filePath, nil
 }
 
-// GetReader returns a reader for the stored file
+// GetReader returns a reader for the stored file, transparently decrypting encrypted files
 func (s *LocalFileStorage) GetReader(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	encrypted, _, err := inspectEncryptedFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
-	return file, nil
+	if !encrypted {
+		return file, nil
+	}
+	if s.config.KeyProvider == nil {
+		file.Close()
+		return nil, fmt.Errorf("file %s is encrypted but no key provider is configured", filePath)
+	}
+
+	reader, err := newDecryptingReader(ctx, s.config.KeyProvider, file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to decrypt file: %w", err)
+	}
+	return reader, nil
 }
 
 // Delete removes a file from storage
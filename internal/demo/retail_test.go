@@ -0,0 +1,86 @@
+package demo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"strconv"
+	"testing"
+)
+
+// TestRetailOrdersDeterministic verifies the same seed always yields the same sample
+func TestRetailOrdersDeterministic(t *testing.T) {
+	if !bytes.Equal(RetailOrders(50, 7), RetailOrders(50, 7)) {
+		t.Error("same seed produced different samples")
+	}
+	if bytes.Equal(RetailOrders(50, 7), RetailOrders(50, 8)) {
+		t.Error("different seeds produced the same sample")
+	}
+}
+
+// TestRetailOrdersPlantedRelationships verifies the sample carries the relationships it
+// documents, strong enough for a sweep to find, and its missing satisfaction scores
+func TestRetailOrdersPlantedRelationships(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(RetailOrders(sampleRows, sampleSeed))).ReadAll()
+	if err != nil {
+		t.Fatalf("sample is not valid CSV: %v", err)
+	}
+	if len(records) != sampleRows+1 || len(records[0]) != len(RetailColumns) {
+		t.Fatalf("got %d records of %d columns", len(records), len(records[0]))
+	}
+
+	column := func(name string) []float64 {
+		index := -1
+		for i, header := range records[0] {
+			if header == name {
+				index = i
+			}
+		}
+		values := make([]float64, 0, len(records)-1)
+		for _, record := range records[1:] {
+			value, err := strconv.ParseFloat(record[index], 64)
+			if err != nil {
+				value = math.NaN()
+			}
+			values = append(values, value)
+		}
+		return values
+	}
+
+	if r := pearson(column("basket_items"), column("order_value")); r < 0.7 {
+		t.Errorf("basket_items ~ order_value r = %.2f, want strong positive", r)
+	}
+	if r := pearson(column("delivery_days"), column("satisfaction_score")); r > -0.3 {
+		t.Errorf("delivery_days ~ satisfaction_score r = %.2f, want negative", r)
+	}
+	if r := pearson(column("customer_tenure_months"), column("sessions_last_30d")); r < 0.5 {
+		t.Errorf("customer_tenure_months ~ sessions_last_30d r = %.2f, want positive", r)
+	}
+
+	missing := 0
+	for _, value := range column("satisfaction_score") {
+		if math.IsNaN(value) {
+			missing++
+		}
+	}
+	if rate := float64(missing) / sampleRows; rate < 0.01 || rate > 0.08 {
+		t.Errorf("satisfaction missing rate = %.3f, want about 0.04", rate)
+	}
+}
+
+// pearson correlates the pairs where both values are present
+func pearson(x, y []float64) float64 {
+	var n, sx, sy, sxx, syy, sxy float64
+	for i := range x {
+		if math.IsNaN(x[i]) || math.IsNaN(y[i]) {
+			continue
+		}
+		n++
+		sx += x[i]
+		sy += y[i]
+		sxx += x[i] * x[i]
+		syy += y[i] * y[i]
+		sxy += x[i] * y[i]
+	}
+	return (n*sxy - sx*sy) / math.Sqrt((n*sxx-sx*sx)*(n*syy-sy*sy))
+}
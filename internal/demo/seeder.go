@@ -0,0 +1,233 @@
+package demo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/domain/greenfield"
+	internalDataset "gohypo/internal/dataset"
+	"gohypo/internal/research"
+	"gohypo/ports"
+)
+
+// Labels every seeded record carries, so demo output is never mistaken for real findings
+const (
+	// MetadataKey marks demo workspaces and research sessions in their metadata
+	MetadataKey = "demo"
+	// SourceKind is the source and provenance kind of the demo dataset
+	SourceKind = "demo"
+
+	WorkspaceName        = "Demo: Online retail orders"
+	WorkspaceDescription = "Sample workspace with synthetic retail orders, seeded so you can explore GoHypo before uploading data. Its relationships were planted by a generator; nothing here describes real customers."
+	DatasetName          = "Retail orders (demo sample)"
+
+	workspaceColor = "#F59E0B"
+	sampleRows     = 600
+	sampleSeed     = 42
+	sampleFile     = "demo_retail_orders.csv"
+)
+
+// Seeder creates the demo workspace, loads the sample dataset into it through the upload
+// pipeline and runs research over it once
+type Seeder struct {
+	workspaces ports.WorkspaceRepository
+	datasets   ports.DatasetRepository
+	processor  *internalDataset.Processor
+	sessions   *research.SessionManager
+	worker     *research.ResearchWorker // nil skips the research run
+	userID     core.ID
+
+	// ReadyTimeout bounds the wait for the sample dataset to finish processing
+	ReadyTimeout time.Duration
+}
+
+// NewSeeder creates a seeder for the user's demo workspace. Without a worker only the
+// workspace and dataset are seeded.
+func NewSeeder(workspaces ports.WorkspaceRepository, datasets ports.DatasetRepository, processor *internalDataset.Processor, sessions *research.SessionManager, worker *research.ResearchWorker, userID core.ID) *Seeder {
+	return &Seeder{
+		workspaces:   workspaces,
+		datasets:     datasets,
+		processor:    processor,
+		sessions:     sessions,
+		worker:       worker,
+		userID:       userID,
+		ReadyTimeout: 2 * time.Minute,
+	}
+}
+
+// Seed seeds the demo workspace unless it already holds a ready sample dataset, and runs
+// research over a newly seeded dataset. It returns the demo workspace.
+func (s *Seeder) Seed(ctx context.Context) (*dataset.Workspace, error) {
+	workspace, err := s.findWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if workspace != nil {
+		ready, err := s.readyDataset(ctx, workspace.ID)
+		if err != nil {
+			return nil, err
+		}
+		if ready != nil {
+			log.Printf("[Demo] Demo workspace %s already seeded", workspace.ID)
+			return workspace, nil
+		}
+	} else {
+		workspace = dataset.NewWorkspace(s.userID, WorkspaceName)
+		workspace.Description = WorkspaceDescription
+		workspace.Color = workspaceColor
+		workspace.Metadata[MetadataKey] = true
+		if err := s.workspaces.Create(ctx, workspace); err != nil {
+			return nil, fmt.Errorf("failed to create demo workspace: %w", err)
+		}
+		log.Printf("[Demo] Created demo workspace %s", workspace.ID)
+	}
+
+	ds, err := s.loadSample(ctx, workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[Demo] Loaded %s (%d rows, %d fields) into demo workspace %s", DatasetName, ds.RecordCount, ds.FieldCount, workspace.ID)
+
+	if s.worker == nil {
+		log.Printf("[Demo] No research worker (LLM not configured); demo workspace seeded without a research run")
+		return workspace, nil
+	}
+	return workspace, s.runResearch(ctx, workspace.ID, ds)
+}
+
+// findWorkspace returns the user's demo workspace, or nil when there is none
+func (s *Seeder) findWorkspace(ctx context.Context) (*dataset.Workspace, error) {
+	workspaces, err := s.workspaces.GetByUserID(ctx, s.userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	for _, workspace := range workspaces {
+		if IsDemo(workspace.Metadata) {
+			return workspace, nil
+		}
+	}
+	return nil, nil
+}
+
+// readyDataset returns the workspace's ready demo dataset, or nil
+func (s *Seeder) readyDataset(ctx context.Context, workspaceID core.ID) (*dataset.Dataset, error) {
+	datasets, err := s.datasets.GetByWorkspace(ctx, workspaceID, 100, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list demo datasets: %w", err)
+	}
+	for _, ds := range datasets {
+		if ds.Source == SourceKind && ds.Status == dataset.StatusReady {
+			return ds, nil
+		}
+	}
+	return nil, nil
+}
+
+// loadSample runs the sample through the upload pipeline, waits for it to be ready and
+// labels it as demo data
+func (s *Seeder) loadSample(ctx context.Context, workspaceID core.ID) (*dataset.Dataset, error) {
+	data := RetailOrders(sampleRows, sampleSeed)
+	sum := sha256.Sum256(data)
+	datasetID, err := s.processor.ProcessUpload(ctx, &dataset.DatasetUpload{
+		UserID:      s.userID,
+		WorkspaceID: workspaceID,
+		Filename:    sampleFile,
+		File:        &sampleReader{bytes.NewReader(data)},
+		MimeType:    "text/csv",
+		Source:      SourceKind,
+		Provenance: &dataset.Provenance{
+			Kind:     SourceKind,
+			Location: fmt.Sprintf("synthetic retail orders generator (seed %d)", sampleSeed),
+			Rows:     sampleRows,
+			Checksum: hex.EncodeToString(sum[:]),
+			PulledAt: time.Now(),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load demo dataset: %w", err)
+	}
+
+	ds, err := s.waitReady(ctx, datasetID)
+	if err != nil {
+		return nil, err
+	}
+	// The scout names datasets after their content; the demo must say what it is
+	ds.DisplayName = DatasetName
+	ds.Description = "Demo data: synthetic sample, not real customers. " + ds.Description
+	ds.UpdatedAt = time.Now()
+	if err := s.datasets.Update(ctx, ds); err != nil {
+		return nil, fmt.Errorf("failed to label demo dataset: %w", err)
+	}
+	return ds, nil
+}
+
+// waitReady polls the dataset until background processing finishes
+func (s *Seeder) waitReady(ctx context.Context, datasetID core.ID) (*dataset.Dataset, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ReadyTimeout)
+	defer cancel()
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		ds, err := s.datasets.GetByID(ctx, datasetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check demo dataset: %w", err)
+		}
+		switch ds.Status {
+		case dataset.StatusReady:
+			return ds, nil
+		case dataset.StatusFailed:
+			return nil, fmt.Errorf("demo dataset failed to process: %s", ds.ErrorMessage)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("demo dataset not ready after %s", s.ReadyTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// runResearch runs the full research pipeline over the sample in a demo-labeled session:
+// the stats sweep, hypothesis generation and validation
+func (s *Seeder) runResearch(ctx context.Context, workspaceID core.ID, ds *dataset.Dataset) error {
+	fields := make([]greenfield.FieldMetadata, 0, len(ds.Metadata.Fields))
+	for _, field := range ds.Metadata.Fields {
+		if field.Name != "" {
+			fields = append(fields, greenfield.FieldMetadata{Name: field.Name, DataType: field.DataType})
+		}
+	}
+	session, err := s.sessions.CreateSessionInWorkspace(ctx, string(workspaceID), map[string]interface{}{
+		MetadataKey:   true,
+		"field_count": len(fields),
+		"dataset_id":  string(ds.ID),
+		"timestamp":   time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create demo research session: %w", err)
+	}
+	log.Printf("[Demo] Running research over the demo dataset in session %s", session.ID)
+	s.worker.ProcessResearch(ctx, session.ID.String(), fields, nil, nil)
+	return nil
+}
+
+// IsDemo reports whether a workspace's or session's metadata marks it as demo content
+func IsDemo(metadata map[string]interface{}) bool {
+	demo, _ := metadata[MetadataKey].(bool)
+	return demo
+}
+
+// sampleReader serves the generated sample as the multipart file the processor reads
+type sampleReader struct {
+	*bytes.Reader
+}
+
+// Close is a no-op for in-memory content
+func (r *sampleReader) Close() error {
+	return nil
+}
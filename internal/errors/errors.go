@@ -100,6 +100,13 @@ const (
 	CodeInternalError    = "INTERNAL_ERROR"
 	CodeExternalService  = "EXTERNAL_SERVICE_ERROR"
 	CodeInvalidInput     = "INVALID_INPUT"
+
+	// Taxonomy codes surfaced to users; see taxonomy.go for HTTP and exit code mapping
+	CodeConflict             = "CONFLICT"
+	CodeCapacity             = "CAPACITY_EXCEEDED"
+	CodeUpstreamLLM          = "UPSTREAM_LLM_ERROR"
+	CodeDeterminismViolation = "DETERMINISM_VIOLATION"
+	CodeForbidden            = "FORBIDDEN"
 )
 
 // Common error constructors
@@ -139,4 +146,26 @@ func InvalidInput(message string) *AppError {
 	return New(CodeInvalidInput, message)
 }
 
+func Conflict(message string) *AppError {
+	return New(CodeConflict, message)
+}
+
+func Capacity(message string) *AppError {
+	return New(CodeCapacity, message)
+}
+
+func UpstreamLLM(provider string, cause error) *AppError {
+	return &AppError{
+		Code:    CodeUpstreamLLM,
+		Message: fmt.Sprintf("%s request failed", provider),
+		Cause:   cause,
+	}
+}
+
+func DeterminismViolation(message string) *AppError {
+	return New(CodeDeterminismViolation, message)
+}
 
+func Forbidden(message string) *AppError {
+	return New(CodeForbidden, message)
+}
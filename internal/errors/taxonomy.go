@@ -0,0 +1,107 @@
+package errors
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strings"
+)
+
+// classification maps an error code to its HTTP status, CLI exit code and problem title
+type classification struct {
+	status int
+	exit   int
+	title  string
+}
+
+// taxonomy is the user-facing error contract. Exit codes are stable so scripts can branch on
+// them: 1 internal, 2 validation, 3 not found, 4 conflict, 5 capacity, 6 upstream LLM,
+// 7 determinism violation, 8 unauthorized or forbidden.
+var taxonomy = map[string]classification{
+	CodeValidationError:      {http.StatusBadRequest, 2, "Validation failed"},
+	CodeInvalidInput:         {http.StatusBadRequest, 2, "Invalid input"},
+	CodeConfigInvalid:        {http.StatusInternalServerError, 2, "Invalid configuration"},
+	CodeNotFound:             {http.StatusNotFound, 3, "Not found"},
+	CodeConflict:             {http.StatusConflict, 4, "Conflict"},
+	CodeCapacity:             {http.StatusTooManyRequests, 5, "Capacity exceeded"},
+	CodeUpstreamLLM:          {http.StatusBadGateway, 6, "LLM provider error"},
+	CodeExternalService:      {http.StatusBadGateway, 6, "Upstream service error"},
+	CodeDeterminismViolation: {http.StatusConflict, 7, "Determinism violation"},
+	CodeUnauthorized:         {http.StatusUnauthorized, 8, "Unauthorized"},
+	CodeForbidden:            {http.StatusForbidden, 8, "Forbidden"},
+	CodeDatabaseError:        {http.StatusInternalServerError, 1, "Storage error"},
+	CodeInternalError:        {http.StatusInternalServerError, 1, "Internal error"},
+}
+
+// Classify returns the most specific AppError in err's chain. Wrap marks plain errors as
+// INTERNAL_ERROR, so a more specific code deeper in the chain wins over it. Errors with no
+// AppError in the chain classify as nil.
+func Classify(err error) *AppError {
+	var fallback *AppError
+	for e := err; e != nil; e = stderrors.Unwrap(e) {
+		appErr, ok := e.(*AppError)
+		if !ok {
+			continue
+		}
+		if appErr.Code != CodeInternalError {
+			return appErr
+		}
+		if fallback == nil {
+			fallback = appErr
+		}
+	}
+	return fallback
+}
+
+// CodeOf returns the taxonomy code for err, INTERNAL_ERROR when it is unclassified
+func CodeOf(err error) string {
+	if appErr := Classify(err); appErr != nil {
+		if _, ok := taxonomy[appErr.Code]; ok {
+			return appErr.Code
+		}
+	}
+	return CodeInternalError
+}
+
+// HTTPStatus maps err to the response status for the API
+func HTTPStatus(err error) int {
+	return taxonomy[CodeOf(err)].status
+}
+
+// ExitCode maps err to the CLI process exit code; nil exits 0
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return taxonomy[CodeOf(err)].exit
+}
+
+// Problem is an RFC 7807 problem+json body
+type Problem struct {
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Status        int    `json:"status"`
+	Detail        string `json:"detail"`
+	Code          string `json:"code"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+	Instance      string `json:"instance,omitempty"`
+}
+
+// NewProblem builds the response body for err. Detail is the classified error's own message,
+// never its cause, so internal failures do not leak driver or provider text to clients.
+func NewProblem(err error, correlationID, instance string) Problem {
+	code := CodeOf(err)
+	detail := "An unexpected error occurred; quote the correlation ID when reporting it"
+	if appErr := Classify(err); appErr != nil && appErr.Message != "" {
+		detail = appErr.Message
+	}
+	class := taxonomy[code]
+	return Problem{
+		Type:          "urn:gohypo:error:" + strings.ToLower(strings.ReplaceAll(code, "_", "-")),
+		Title:         class.title,
+		Status:        class.status,
+		Detail:        detail,
+		Code:          code,
+		CorrelationID: correlationID,
+		Instance:      instance,
+	}
+}
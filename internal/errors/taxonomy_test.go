@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// TestClassifyPrefersSpecificCodes verifies Wrap and fmt wrapping keep the taxonomy code
+func TestClassifyPrefersSpecificCodes(t *testing.T) {
+	err := Wrap(fmt.Errorf("load: %w", NotFound("dataset")), "failed to open dataset")
+
+	if got := CodeOf(err); got != CodeNotFound {
+		t.Fatalf("expected %s, got %s", CodeNotFound, got)
+	}
+	if HTTPStatus(err) != http.StatusNotFound || ExitCode(err) != 3 {
+		t.Fatalf("unexpected mapping: http %d exit %d", HTTPStatus(err), ExitCode(err))
+	}
+	if ExitCode(nil) != 0 || ExitCode(fmt.Errorf("plain")) != 1 {
+		t.Fatal("nil must exit 0 and unclassified errors must exit 1")
+	}
+}
+
+// TestNewProblemHidesInternalCauses verifies unclassified causes never reach the response
+func TestNewProblemHidesInternalCauses(t *testing.T) {
+	problem := NewProblem(Wrap(fmt.Errorf("pq: password authentication failed"), "failed to list workspaces"), "req-1", "/api/workspaces")
+
+	if problem.Status != http.StatusInternalServerError || problem.Code != CodeInternalError {
+		t.Fatalf("unexpected problem %+v", problem)
+	}
+	if problem.Detail != "failed to list workspaces" || problem.CorrelationID != "req-1" {
+		t.Fatalf("unexpected detail or correlation ID: %+v", problem)
+	}
+	if got := NewProblem(fmt.Errorf("boom"), "", "").Detail; got == "boom" {
+		t.Fatal("plain error text must not be exposed")
+	}
+}
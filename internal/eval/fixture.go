@@ -0,0 +1,128 @@
+// Package eval scores generated hypotheses offline so prompt and model changes can be
+// compared on recorded relationship fixtures before they are rolled out.
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gohypo/domain/core"
+	"gohypo/domain/greenfield"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// Fixture is one recorded generation input: the fields and statistical artifacts a run handed
+// to the generator, and optionally the output the generator gave back at the time
+type Fixture struct {
+	Name                 string                           `json:"name"`
+	Description          string                           `json:"description,omitempty"`
+	DomainPack           string                           `json:"domain_pack,omitempty"`
+	Directives           int                              `json:"directives,omitempty"`
+	FieldMetadata        []greenfield.FieldMetadata       `json:"field_metadata"`
+	StatisticalArtifacts []map[string]interface{}         `json:"statistical_artifacts"`
+	RecordedOutput       *models.GreenfieldResearchOutput `json:"recorded_output,omitempty"`
+}
+
+// LoadFixtures reads every *.json fixture in dir, sorted by file name so reports line up
+// between runs
+func LoadFixtures(dir string) ([]*Fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.json fixtures in %s", dir)
+	}
+	sort.Strings(paths)
+
+	fixtures := make([]*Fixture, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture %s: %w", path, err)
+		}
+		var fixture Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+		}
+		if fixture.Name == "" {
+			fixture.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+		if len(fixture.FieldMetadata) == 0 {
+			return nil, fmt.Errorf("fixture %s has no field_metadata", path)
+		}
+		fixtures = append(fixtures, &fixture)
+	}
+	return fixtures, nil
+}
+
+// Request builds the generation request the fixture was recorded from
+func (f *Fixture) Request() ports.GreenfieldResearchRequest {
+	directives := f.Directives
+	if directives <= 0 {
+		directives = 3
+	}
+	return ports.GreenfieldResearchRequest{
+		RunID:                core.RunID("eval-" + f.Name),
+		SnapshotID:           core.SnapshotID(f.Name),
+		FieldMetadata:        f.FieldMetadata,
+		StatisticalArtifacts: f.StatisticalArtifacts,
+		DomainPack:           f.DomainPack,
+		Directives:           directives,
+	}
+}
+
+// fieldSet returns the fixture's variable names, lowercased
+func (f *Fixture) fieldSet() map[string]bool {
+	fields := make(map[string]bool, len(f.FieldMetadata))
+	for _, field := range f.FieldMetadata {
+		fields[strings.ToLower(field.Name)] = true
+	}
+	return fields
+}
+
+// relationships indexes the measured pairs by "cause->effect" in both orders, since a
+// correlation does not say which side is the cause. Sweep payloads name the pair
+// cause_key/effect_key with a correlation; relationship payloads use variable_x/variable_y
+// with an effect_size.
+func (f *Fixture) relationships() map[string]float64 {
+	pairs := make(map[string]float64)
+	for _, artifact := range f.StatisticalArtifacts {
+		payload, ok := artifact["payload"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		x, y, effect, ok := pairFromPayload(payload)
+		if !ok {
+			continue
+		}
+		x, y = strings.ToLower(x), strings.ToLower(y)
+		pairs[x+"->"+y] = effect
+		pairs[y+"->"+x] = effect
+	}
+	return pairs
+}
+
+func pairFromPayload(payload map[string]interface{}) (string, string, float64, bool) {
+	x, _ := payload["cause_key"].(string)
+	y, _ := payload["effect_key"].(string)
+	if x == "" || y == "" {
+		x, _ = payload["variable_x"].(string)
+		y, _ = payload["variable_y"].(string)
+	}
+	if x == "" || y == "" {
+		return "", "", 0, false
+	}
+	for _, key := range []string{"correlation", "effect_size"} {
+		if v, ok := payload[key].(float64); ok && !math.IsNaN(v) {
+			return x, y, v, true
+		}
+	}
+	return x, y, 0, true
+}
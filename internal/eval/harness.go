@@ -0,0 +1,218 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gohypo/ai"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// DirectiveScore is one generated hypothesis with its heuristic scores and, when a judge
+// ran, the judge's normalized scores
+type DirectiveScore struct {
+	ID          string        `json:"id"`
+	CauseKey    string        `json:"cause_key"`
+	EffectKey   string        `json:"effect_key"`
+	Grounding   float64       `json:"grounding"`
+	Specificity float64       `json:"specificity"`
+	Testability float64       `json:"testability"`
+	Judge       *JudgeVerdict `json:"judge,omitempty"`
+	JudgeError  string        `json:"judge_error,omitempty"`
+	Issues      []string      `json:"issues,omitempty"`
+}
+
+// Scores averages the three dimensions; Judge* fields are zero when no judge ran
+type Scores struct {
+	Directives       int     `json:"directives"`
+	Grounding        float64 `json:"grounding"`
+	Specificity      float64 `json:"specificity"`
+	Testability      float64 `json:"testability"`
+	Overall          float64 `json:"overall"`
+	Judged           int     `json:"judged"`
+	JudgeGrounding   float64 `json:"judge_grounding,omitempty"`
+	JudgeSpecificity float64 `json:"judge_specificity,omitempty"`
+	JudgeTestability float64 `json:"judge_testability,omitempty"`
+	JudgeOverall     float64 `json:"judge_overall,omitempty"`
+}
+
+// FixtureReport is the result for one fixture
+type FixtureReport struct {
+	Fixture    string           `json:"fixture"`
+	Error      string           `json:"error,omitempty"`
+	Directives []DirectiveScore `json:"directives"`
+	Scores     Scores           `json:"scores"`
+}
+
+// Report is a full eval run. Reports are written as JSON and compared with Compare.
+type Report struct {
+	Label     string           `json:"label,omitempty"` // e.g. the prompt or model under test
+	Recorded  bool             `json:"recorded"`        // scored the fixtures' recorded outputs instead of generating
+	CreatedAt time.Time        `json:"created_at"`
+	Fixtures  []FixtureReport  `json:"fixtures"`
+	Summary   Scores           `json:"summary"`
+	Usage     *models.RunUsage `json:"usage,omitempty"`
+}
+
+// Harness generates hypotheses for each fixture and scores them. With no Generator it scores
+// the fixtures' recorded outputs, so heuristics and the judge can be tuned without spending
+// generation calls.
+type Harness struct {
+	Generator ports.GreenfieldResearchPort
+	Judge     Judge // optional
+	Label     string
+}
+
+// Run scores every fixture. A fixture whose generation fails is reported with its error and
+// counts as zero, so a prompt that breaks on some inputs does not look better for it.
+func (h *Harness) Run(ctx context.Context, fixtures []*Fixture) (*Report, error) {
+	report := &Report{Label: h.Label, Recorded: h.Generator == nil, CreatedAt: time.Now(), Usage: &models.RunUsage{RunID: "eval"}}
+	// Generation runs meter themselves; this meter collects the judge's calls
+	ctx, judgeMeter := ai.WithUsageMeter(ctx, "eval")
+	var all []DirectiveScore
+	for _, fixture := range fixtures {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		fr := FixtureReport{Fixture: fixture.Name}
+		output, usage, err := h.directives(ctx, fixture)
+		report.Usage.Add(usage)
+		if err != nil {
+			log.Printf("[Eval] ⚠️ %s: %v", fixture.Name, err)
+			fr.Error = err.Error()
+			report.Fixtures = append(report.Fixtures, fr)
+			continue
+		}
+		for _, directive := range output.ResearchDirectives {
+			fr.Directives = append(fr.Directives, h.score(ctx, fixture, directive))
+		}
+		fr.Scores = summarize(fr.Directives)
+		all = append(all, fr.Directives...)
+		report.Fixtures = append(report.Fixtures, fr)
+		log.Printf("[Eval] 📏 %s: %d directives, overall %.2f", fixture.Name, len(fr.Directives), fr.Scores.Overall)
+	}
+	report.Usage.Add(judgeMeter.Snapshot())
+	report.Summary = summarize(all)
+	failed := 0
+	for _, fr := range report.Fixtures {
+		if fr.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		// Failed fixtures contribute zero-scored slots so the summary penalizes them
+		scale := float64(len(fixtures)-failed) / float64(len(fixtures))
+		report.Summary.Grounding *= scale
+		report.Summary.Specificity *= scale
+		report.Summary.Testability *= scale
+		report.Summary.Overall *= scale
+	}
+	return report, nil
+}
+
+// directives returns the fixture's hypotheses: generated, or the recorded output
+func (h *Harness) directives(ctx context.Context, fixture *Fixture) (*models.GreenfieldResearchOutput, *models.RunUsage, error) {
+	if h.Generator == nil {
+		if fixture.RecordedOutput == nil {
+			return nil, nil, fmt.Errorf("fixture has no recorded_output to score")
+		}
+		return fixture.RecordedOutput, nil, nil
+	}
+	resp, err := h.Generator.GenerateResearchDirectives(ctx, fixture.Request())
+	if err != nil {
+		return nil, nil, err
+	}
+	output, ok := resp.RawLLMResponse.(*models.GreenfieldResearchOutput)
+	if !ok || output == nil {
+		return nil, resp.Usage, fmt.Errorf("generator returned no structured output")
+	}
+	return output, resp.Usage, nil
+}
+
+func (h *Harness) score(ctx context.Context, fixture *Fixture, directive models.ResearchDirectiveResponse) DirectiveScore {
+	ds := DirectiveScore{ID: directive.ID, CauseKey: directive.CauseKey, EffectKey: directive.EffectKey}
+	var issues []string
+	ds.Grounding, issues = ScoreGrounding(fixture, directive)
+	ds.Issues = append(ds.Issues, issues...)
+	ds.Specificity, issues = ScoreSpecificity(directive)
+	ds.Issues = append(ds.Issues, issues...)
+	ds.Testability, issues = ScoreTestability(directive)
+	ds.Issues = append(ds.Issues, issues...)
+
+	if h.Judge != nil {
+		verdict, err := h.Judge.Judge(ctx, fixture, directive)
+		if err != nil {
+			ds.JudgeError = err.Error()
+		} else {
+			ds.Judge = verdict
+		}
+	}
+	return ds
+}
+
+// summarize averages directive scores
+func summarize(scores []DirectiveScore) Scores {
+	s := Scores{Directives: len(scores)}
+	if len(scores) == 0 {
+		return s
+	}
+	for _, ds := range scores {
+		s.Grounding += ds.Grounding
+		s.Specificity += ds.Specificity
+		s.Testability += ds.Testability
+		if ds.Judge != nil {
+			s.Judged++
+			s.JudgeGrounding += normalized(ds.Judge.Grounding)
+			s.JudgeSpecificity += normalized(ds.Judge.Specificity)
+			s.JudgeTestability += normalized(ds.Judge.Testability)
+		}
+	}
+	n := float64(len(scores))
+	s.Grounding /= n
+	s.Specificity /= n
+	s.Testability /= n
+	s.Overall = (s.Grounding + s.Specificity + s.Testability) / 3
+	if s.Judged > 0 {
+		j := float64(s.Judged)
+		s.JudgeGrounding /= j
+		s.JudgeSpecificity /= j
+		s.JudgeTestability /= j
+		s.JudgeOverall = (s.JudgeGrounding + s.JudgeSpecificity + s.JudgeTestability) / 3
+	}
+	return s
+}
+
+// Delta is the change in one summary metric between a baseline and a candidate report
+type Delta struct {
+	Metric    string  `json:"metric"`
+	Baseline  float64 `json:"baseline"`
+	Candidate float64 `json:"candidate"`
+	Change    float64 `json:"change"`
+}
+
+// Compare lists the summary metrics of two reports side by side, overall first. Judge
+// metrics are included only when both runs were judged.
+func Compare(baseline, candidate *Report) []Delta {
+	b, c := baseline.Summary, candidate.Summary
+	deltas := []Delta{
+		{Metric: "overall", Baseline: b.Overall, Candidate: c.Overall},
+		{Metric: "grounding", Baseline: b.Grounding, Candidate: c.Grounding},
+		{Metric: "specificity", Baseline: b.Specificity, Candidate: c.Specificity},
+		{Metric: "testability", Baseline: b.Testability, Candidate: c.Testability},
+	}
+	if b.Judged > 0 && c.Judged > 0 {
+		deltas = append(deltas,
+			Delta{Metric: "judge_overall", Baseline: b.JudgeOverall, Candidate: c.JudgeOverall},
+			Delta{Metric: "judge_grounding", Baseline: b.JudgeGrounding, Candidate: c.JudgeGrounding},
+			Delta{Metric: "judge_specificity", Baseline: b.JudgeSpecificity, Candidate: c.JudgeSpecificity},
+			Delta{Metric: "judge_testability", Baseline: b.JudgeTestability, Candidate: c.JudgeTestability},
+		)
+	}
+	for i := range deltas {
+		deltas[i].Change = deltas[i].Candidate - deltas[i].Baseline
+	}
+	return deltas
+}
@@ -0,0 +1,66 @@
+package eval
+
+import (
+	"context"
+	"testing"
+
+	"gohypo/models"
+)
+
+func TestClaimedDirection(t *testing.T) {
+	cases := map[string]int{
+		"Higher discount leads to higher quantity":   1,
+		"More shipping days reduce satisfaction":     -1,
+		"Price is inversely related to demand":       -1,
+		"Tenure is positively associated with spend": 1,
+		"Region is associated with churn":            0,
+	}
+	for text, want := range cases {
+		if got := claimedDirection(text); got != want {
+			t.Errorf("claimedDirection(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+type fixedJudge struct{ verdict JudgeVerdict }
+
+func (j fixedJudge) Judge(ctx context.Context, f *Fixture, d models.ResearchDirectiveResponse) (*JudgeVerdict, error) {
+	v := j.verdict
+	return &v, nil
+}
+
+func TestHarnessScoresRecordedOutput(t *testing.T) {
+	fixtures, err := LoadFixtures("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := (&Harness{Judge: fixedJudge{JudgeVerdict{Grounding: 5, Specificity: 3, Testability: 1}}}).Run(context.Background(), fixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Recorded || len(report.Fixtures) != 1 {
+		t.Fatalf("unexpected report shape: %+v", report)
+	}
+	scores := report.Fixtures[0].Directives
+	if len(scores) != 3 {
+		t.Fatalf("expected 3 scored directives, got %d", len(scores))
+	}
+	grounded, contradicted, hallucinated := scores[0], scores[1], scores[2]
+	if grounded.Grounding != 1 || grounded.Specificity != 1 || grounded.Testability != 1 {
+		t.Errorf("well-grounded directive should score 1 on every heuristic: %+v", grounded)
+	}
+	if contradicted.Grounding >= grounded.Grounding {
+		t.Errorf("a claim against the measured sign should lose grounding: %+v", contradicted)
+	}
+	if hallucinated.Grounding != 0 || hallucinated.Testability >= contradicted.Testability {
+		t.Errorf("unknown variable and missing null case should score lowest: %+v", hallucinated)
+	}
+	if report.Summary.Judged != 3 || report.Summary.JudgeGrounding != 1 || report.Summary.JudgeTestability != 0 {
+		t.Errorf("judge ratings should be normalized to [0,1]: %+v", report.Summary)
+	}
+
+	deltas := Compare(report, report)
+	if len(deltas) != 8 || deltas[0].Metric != "overall" || deltas[0].Change != 0 {
+		t.Errorf("unexpected comparison: %+v", deltas)
+	}
+}
@@ -0,0 +1,150 @@
+package eval
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gohypo/models"
+)
+
+// Heuristic scores are in [0, 1]. They are deliberately simple and deterministic: they
+// catch regressions cheaply, and the LLM judge covers what they cannot read.
+
+// Direction words: an odd number of decreasing words in a claim means the claim predicts a
+// negative association ("higher X lowers Y"), an even number a positive one
+var (
+	increasingWords = []string{"increase", "increases", "increased", "increasing", "higher", "more", "raise", "raises", "boost", "boosts", "grow", "grows", "rise", "rises", "greater", "larger"}
+	decreasingWords = []string{"decrease", "decreases", "decreased", "decreasing", "lower", "lowers", "less", "fewer", "reduce", "reduces", "reduced", "decline", "declines", "drop", "drops", "smaller"}
+	vaguePhrases    = []string{"may affect", "might affect", "is related to", "are related", "some relationship", "has an impact", "has an effect", "plays a role", "influences", "is associated with"}
+	quantityPattern = regexp.MustCompile(`\d`)
+)
+
+// claimedDirection reads the sign a hypothesis predicts: +1, -1, or 0 when it does not say.
+// "negative"/"inverse" and "positive" are taken at their word before counting direction words.
+func claimedDirection(text string) int {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z')
+	})
+	set := make(map[string]int, len(words))
+	for _, w := range words {
+		set[w]++
+	}
+	switch {
+	case set["negative"]+set["negatively"]+set["inverse"]+set["inversely"] > 0:
+		return -1
+	case set["positive"]+set["positively"] > 0:
+		return 1
+	}
+	ups, downs := 0, 0
+	for _, w := range increasingWords {
+		ups += set[w]
+	}
+	for _, w := range decreasingWords {
+		downs += set[w]
+	}
+	if ups+downs == 0 {
+		return 0
+	}
+	if downs%2 == 1 {
+		return -1
+	}
+	return 1
+}
+
+// ScoreGrounding checks the directive against the fixture: both variables must exist, the
+// pair should be one the evidence measured, and a stated direction must match the sign of
+// the measured effect. Each check is worth a third.
+func ScoreGrounding(f *Fixture, d models.ResearchDirectiveResponse) (float64, []string) {
+	var issues []string
+	fields := f.fieldSet()
+	cause, effect := strings.ToLower(d.CauseKey), strings.ToLower(d.EffectKey)
+
+	score := 0.0
+	if fields[cause] && fields[effect] {
+		score += 1.0 / 3
+	} else {
+		issues = append(issues, fmt.Sprintf("cites variables not in the dataset (%s, %s)", d.CauseKey, d.EffectKey))
+		return 0, issues
+	}
+
+	measured, ok := f.relationships()[cause+"->"+effect]
+	if !ok {
+		issues = append(issues, "pair has no statistical evidence in the fixture")
+		return score, issues
+	}
+	score += 1.0 / 3
+
+	claimed := claimedDirection(d.ScienceHypothesis)
+	if claimed == 0 {
+		claimed = claimedDirection(d.BusinessHypothesis)
+	}
+	switch {
+	case measured == 0:
+		score += 1.0 / 3 // nothing to contradict
+	case claimed == 0:
+		issues = append(issues, "hypothesis does not state a direction")
+	case (claimed > 0) == (measured > 0):
+		score += 1.0 / 3
+	default:
+		issues = append(issues, fmt.Sprintf("claimed direction contradicts the measured effect (%.3f)", measured))
+	}
+	return score, issues
+}
+
+// ScoreSpecificity rewards hypotheses that name both variables, commit to a quantity, and
+// avoid hedged wording
+func ScoreSpecificity(d models.ResearchDirectiveResponse) (float64, []string) {
+	var issues []string
+	text := strings.ToLower(d.BusinessHypothesis + " " + d.ScienceHypothesis)
+
+	score := 0.0
+	if strings.Contains(text, strings.ToLower(d.CauseKey)) && strings.Contains(text, strings.ToLower(d.EffectKey)) {
+		score += 0.25
+	} else {
+		issues = append(issues, "hypothesis text does not name both variables")
+	}
+	if quantityPattern.MatchString(d.ScienceHypothesis) || quantityPattern.MatchString(d.NullCase) {
+		score += 0.25
+	} else {
+		issues = append(issues, "no quantity, threshold or effect size")
+	}
+	if claimedDirection(text) != 0 {
+		score += 0.25
+	}
+	vague := false
+	for _, phrase := range vaguePhrases {
+		if strings.Contains(text, phrase) && claimedDirection(text) == 0 {
+			vague = true
+			issues = append(issues, fmt.Sprintf("vague wording: %q", phrase))
+			break
+		}
+	}
+	if !vague {
+		score += 0.25
+	}
+	return score, issues
+}
+
+// ScoreTestability rewards hypotheses that can be refuted as written: a distinct cause and
+// effect, a null case, and referees to run
+func ScoreTestability(d models.ResearchDirectiveResponse) (float64, []string) {
+	var issues []string
+	score := 0.0
+	if d.CauseKey != "" && d.EffectKey != "" && !strings.EqualFold(d.CauseKey, d.EffectKey) {
+		score += 1.0 / 3
+	} else {
+		issues = append(issues, "cause and effect must be two different variables")
+	}
+	if strings.TrimSpace(d.NullCase) != "" {
+		score += 1.0 / 3
+	} else {
+		issues = append(issues, "no null case")
+	}
+	if len(d.RefereeGates.SelectedReferees) > 0 {
+		score += 1.0 / 3
+	} else {
+		issues = append(issues, "no referees selected")
+	}
+	return score, issues
+}
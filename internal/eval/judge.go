@@ -0,0 +1,106 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gohypo/ai"
+	"gohypo/models"
+)
+
+// JudgeVerdict is an LLM judge's rating of one hypothesis, each dimension on a 1-5 scale
+type JudgeVerdict struct {
+	Grounding   float64 `json:"grounding"`
+	Specificity float64 `json:"specificity"`
+	Testability float64 `json:"testability"`
+	Rationale   string  `json:"rationale"`
+}
+
+// normalized maps a 1-5 rating onto [0, 1] so judge and heuristic scores share a scale
+func normalized(rating float64) float64 {
+	return clamp01((rating - 1) / 4)
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// Judge rates a hypothesis against the evidence it was generated from
+type Judge interface {
+	Judge(ctx context.Context, f *Fixture, d models.ResearchDirectiveResponse) (*JudgeVerdict, error)
+}
+
+// LLMJudge asks a model to grade hypotheses. It runs at the provider's configured
+// temperature; pin it low in the AI config so repeated evals are comparable.
+type LLMJudge struct {
+	client *ai.StructuredClient[JudgeVerdict]
+}
+
+// NewLLMJudge builds a judge on the given AI configuration
+func NewLLMJudge(config *models.AIConfig) *LLMJudge {
+	client := ai.NewStructuredClientLegacy[JudgeVerdict](config, config.PromptsDir)
+	client.LLMClient = ai.NewMeteredLLMClient(client.LLMClient)
+	return &LLMJudge{client: client}
+}
+
+const judgeSystemMessage = `You are a strict reviewer of data-science hypotheses. Grade only against the evidence provided; never reward fluent writing that the evidence does not support.
+Output valid JSON only.`
+
+// Judge grades one directive
+func (j *LLMJudge) Judge(ctx context.Context, f *Fixture, d models.ResearchDirectiveResponse) (*JudgeVerdict, error) {
+	verdict, err := j.client.GetJsonResponseWithContext(ctx, "openai", judgePrompt(f, d), judgeSystemMessage)
+	if err != nil {
+		return nil, err
+	}
+	for _, rating := range []float64{verdict.Grounding, verdict.Specificity, verdict.Testability} {
+		if rating < 1 || rating > 5 {
+			return nil, fmt.Errorf("judge returned a rating outside 1-5: %+v", *verdict)
+		}
+	}
+	return verdict, nil
+}
+
+// judgePrompt lists the dataset's variables and measured relationships, then the hypothesis
+func judgePrompt(f *Fixture, d models.ResearchDirectiveResponse) string {
+	var b strings.Builder
+	b.WriteString("DATASET VARIABLES:\n")
+	for _, field := range f.FieldMetadata {
+		fmt.Fprintf(&b, "- %s (%s)\n", field.Name, field.DataType)
+	}
+	b.WriteString("\nMEASURED RELATIONSHIPS:\n")
+	for _, artifact := range f.StatisticalArtifacts {
+		payload, ok := artifact["payload"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if x, y, effect, ok := pairFromPayload(payload); ok {
+			fmt.Fprintf(&b, "- %s ~ %s: effect %.3f, p=%v\n", x, y, effect, payload["p_value"])
+		}
+	}
+
+	hypothesis, _ := json.MarshalIndent(map[string]string{
+		"cause_key":           d.CauseKey,
+		"effect_key":          d.EffectKey,
+		"business_hypothesis": d.BusinessHypothesis,
+		"science_hypothesis":  d.ScienceHypothesis,
+		"null_case":           d.NullCase,
+	}, "", "  ")
+	fmt.Fprintf(&b, "\nHYPOTHESIS:\n%s\n", hypothesis)
+
+	b.WriteString(`
+Rate the hypothesis from 1 (worst) to 5 (best) on:
+- grounding: it cites variables that exist and a relationship the evidence measured, in the direction the evidence shows
+- specificity: it commits to a concrete, quantified pattern rather than a vague association
+- testability: it states a refutable claim with a null case a statistical test could decide
+
+Respond with {"grounding": n, "specificity": n, "testability": n, "rationale": "one or two sentences"}.`)
+	return b.String()
+}
@@ -0,0 +1,352 @@
+// Package golden keeps golden runs: small datasets with the relationship results a sweep
+// produced from them, so a statistical refactor that changes results is caught and traced
+// to the engine that drifted.
+package golden
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gohypo/domain/core"
+	apperrors "gohypo/internal/errors"
+)
+
+// ErrDrift is returned when a sweep no longer reproduces a golden case
+var ErrDrift = apperrors.DeterminismViolation("results drifted from the golden runs")
+
+// Files of a case directory besides its data.* dataset
+const (
+	ExpectedFile = "expected.json"
+	SpecFile     = "spec.yaml" // optional run spec; the sweep defaults apply without one
+)
+
+// Case is one golden dataset with the results expected from it
+type Case struct {
+	Name     string
+	Dir      string
+	DataPath string
+	SpecPath string    // empty when the case has no spec
+	Expected *Expected // nil until the case is recorded
+}
+
+// Expected is what a sweep produced from a case when it was recorded
+type Expected struct {
+	Dataset       string            `json:"dataset"`
+	SHA256        string            `json:"sha256"`
+	Methods       map[string]string `json:"method_versions"`
+	RecordedAt    time.Time         `json:"recorded_at"`
+	Relationships []Relationship    `json:"relationships"`
+}
+
+// Relationship is the numeric outcome of one relationship artifact
+type Relationship struct {
+	ID        string             `json:"id"`
+	TestType  string             `json:"test_type"`
+	CauseKey  string             `json:"cause_key"`
+	EffectKey string             `json:"effect_key"`
+	Values    map[string]float64 `json:"values"`
+}
+
+// engineFields are the payload fields a golden run compares, with the engine computing
+// each. An empty engine is the relationship's own test.
+var engineFields = map[string]string{
+	"correlation":        "",
+	"p_value":            "",
+	"sample_size":        "",
+	"effect_size":        "",
+	"f_statistic":        "",
+	"kruskal_h":          "kruskal_wallis",
+	"kruskal_p_value":    "kruskal_wallis",
+	"q_value":            "fdr_correction",
+	"robust_correlation": "robust",
+	"robust_p_value":     "robust",
+	"mixed_correlation":  "mixed_model",
+	"mixed_p_value":      "mixed_model",
+	"bayes_factor_10":    "bayesian",
+}
+
+// engine is the engine computing a field of the relationship
+func (r Relationship) engine(field string) string {
+	if engine := engineFields[field]; engine != "" {
+		return engine
+	}
+	return r.TestType
+}
+
+// Relationships extracts the compared values of relationship artifacts, sorted by ID.
+// Values that are not finite are left out, as JSON cannot hold them.
+func Relationships(artifacts []core.Artifact) []Relationship {
+	relationships := make([]Relationship, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		payload, ok := artifact.Payload.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		relationship := Relationship{ID: string(artifact.ID), Values: map[string]float64{}}
+		relationship.TestType, _ = payload["test_type"].(string)
+		relationship.CauseKey, _ = payload["cause_key"].(string)
+		relationship.EffectKey, _ = payload["effect_key"].(string)
+		for field := range engineFields {
+			if value, ok := number(payload[field]); ok && !math.IsNaN(value) && !math.IsInf(value, 0) {
+				relationship.Values[field] = value
+			}
+		}
+		relationships = append(relationships, relationship)
+	}
+	sort.Slice(relationships, func(i, j int) bool { return relationships[i].ID < relationships[j].ID })
+	return relationships
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// LoadCases reads every case under dir, sorted by name. A case is a directory holding one
+// data.* dataset; its expected.json is read when it has been recorded.
+func LoadCases(dir string) ([]*Case, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden directory: %w", err)
+	}
+	var cases []*Case
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		caseDir := filepath.Join(dir, entry.Name())
+		data, err := filepath.Glob(filepath.Join(caseDir, "data.*"))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) != 1 {
+			continue
+		}
+		c := &Case{Name: entry.Name(), Dir: caseDir, DataPath: data[0]}
+		if _, err := os.Stat(filepath.Join(caseDir, SpecFile)); err == nil {
+			c.SpecPath = filepath.Join(caseDir, SpecFile)
+		}
+		if c.Expected, err = readExpected(filepath.Join(caseDir, ExpectedFile)); err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no golden cases (directories with a data.* file) in %s", dir)
+	}
+	return cases, nil
+}
+
+func readExpected(path string) (*Expected, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var expected Expected
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &expected, nil
+}
+
+// Record writes relationships as the case's expected results
+func (c *Case) Record(relationships []Relationship, methods map[string]string) error {
+	sum, err := hashFile(c.DataPath)
+	if err != nil {
+		return err
+	}
+	c.Expected = &Expected{
+		Dataset:       filepath.Base(c.DataPath),
+		SHA256:        sum,
+		Methods:       methods,
+		RecordedAt:    time.Now().UTC(),
+		Relationships: relationships,
+	}
+	data, err := json.MarshalIndent(c.Expected, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.Dir, ExpectedFile), append(data, '\n'), 0o644)
+}
+
+// Tolerance is how far a value may move before it counts as drift: the larger of the
+// absolute bound and the relative bound scaled by the expected value
+type Tolerance struct {
+	Absolute float64 `json:"absolute"`
+	Relative float64 `json:"relative"`
+}
+
+// DefaultTolerance absorbs floating-point reassociation, not a change of method
+var DefaultTolerance = Tolerance{Absolute: 1e-9, Relative: 1e-6}
+
+// Within reports whether actual is within tolerance of expected
+func (t Tolerance) Within(expected, actual float64) bool {
+	return math.Abs(expected-actual) <= math.Max(t.Absolute, t.Relative*math.Abs(expected))
+}
+
+// Drift is one value that moved beyond tolerance. A nil value was not produced on that side.
+type Drift struct {
+	Engine       string   `json:"engine"`
+	Relationship string   `json:"relationship"`
+	Field        string   `json:"field"`
+	Expected     *float64 `json:"expected"`
+	Actual       *float64 `json:"actual"`
+}
+
+// Report is the outcome of one case
+type Report struct {
+	Case           string   `json:"case"`
+	Compared       int      `json:"compared"`
+	Drifts         []Drift  `json:"drifts,omitempty"`
+	Missing        []string `json:"missing,omitempty"` // expected relationships no longer produced
+	Extra          []string `json:"extra,omitempty"`   // relationships produced but not expected
+	Engines        []string `json:"engines,omitempty"` // engines that drifted, sorted
+	DatasetChanged bool     `json:"dataset_changed,omitempty"`
+	MethodChanges  []string `json:"method_changes,omitempty"` // method versions bumped since recording
+}
+
+// Passed reports whether the case reproduced its expected results
+func (r *Report) Passed() bool {
+	return len(r.Drifts) == 0 && len(r.Missing) == 0 && len(r.Extra) == 0 && !r.DatasetChanged
+}
+
+// Compare checks a case's current results against its expected ones. Relationships that
+// appear or disappear are attributed to their test's engine.
+func (c *Case) Compare(actual []Relationship, methods map[string]string, tol Tolerance) (*Report, error) {
+	if c.Expected == nil {
+		return nil, fmt.Errorf("golden case %s has no %s; record it first", c.Name, ExpectedFile)
+	}
+	sum, err := hashFile(c.DataPath)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Case: c.Name, DatasetChanged: sum != c.Expected.SHA256}
+	for name, version := range c.Expected.Methods {
+		if now, ok := methods[name]; ok && now != version {
+			report.MethodChanges = append(report.MethodChanges, fmt.Sprintf("%s %s → %s", name, version, now))
+		}
+	}
+	sort.Strings(report.MethodChanges)
+
+	engines := map[string]bool{}
+	current := make(map[string]Relationship, len(actual))
+	for _, relationship := range actual {
+		current[relationship.ID] = relationship
+	}
+	expected := make(map[string]bool, len(c.Expected.Relationships))
+	for _, want := range c.Expected.Relationships {
+		expected[want.ID] = true
+		got, ok := current[want.ID]
+		if !ok {
+			report.Missing = append(report.Missing, want.ID)
+			engines[want.TestType] = true
+			continue
+		}
+		report.Compared++
+		for _, drift := range diffValues(want, got, tol) {
+			report.Drifts = append(report.Drifts, drift)
+			engines[drift.Engine] = true
+		}
+	}
+	for _, got := range actual {
+		if !expected[got.ID] {
+			report.Extra = append(report.Extra, got.ID)
+			engines[got.TestType] = true
+		}
+	}
+	for engine := range engines {
+		report.Engines = append(report.Engines, engine)
+	}
+	sort.Strings(report.Engines)
+	return report, nil
+}
+
+// diffValues lists the fields of a relationship that drifted, in field order
+func diffValues(want, got Relationship, tol Tolerance) []Drift {
+	fields := make([]string, 0, len(want.Values)+len(got.Values))
+	for field := range want.Values {
+		fields = append(fields, field)
+	}
+	for field := range got.Values {
+		if _, ok := want.Values[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var drifts []Drift
+	for _, field := range fields {
+		before, hadBefore := want.Values[field]
+		after, hasAfter := got.Values[field]
+		if hadBefore && hasAfter && tol.Within(before, after) {
+			continue
+		}
+		drift := Drift{Engine: want.engine(field), Relationship: want.ID, Field: field}
+		if hadBefore {
+			drift.Expected = &before
+		}
+		if hasAfter {
+			drift.Actual = &after
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts
+}
+
+// Summary is a one-line account of a report
+func (r *Report) Summary() string {
+	if r.Passed() {
+		return fmt.Sprintf("%d relationships match", r.Compared)
+	}
+	var parts []string
+	if r.DatasetChanged {
+		parts = append(parts, "dataset changed since recording")
+	}
+	if len(r.Drifts) > 0 {
+		parts = append(parts, fmt.Sprintf("%d values drifted", len(r.Drifts)))
+	}
+	if len(r.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf("%d missing", len(r.Missing)))
+	}
+	if len(r.Extra) > 0 {
+		parts = append(parts, fmt.Sprintf("%d extra", len(r.Extra)))
+	}
+	if len(r.Engines) > 0 {
+		parts = append(parts, "engines: "+strings.Join(r.Engines, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
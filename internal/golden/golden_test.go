@@ -0,0 +1,131 @@
+package golden
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gohypo/domain/core"
+)
+
+func recordedCase(t *testing.T, relationships []Relationship) *Case {
+	t.Helper()
+	dir := t.TempDir()
+	caseDir := filepath.Join(dir, "orders")
+	if err := os.MkdirAll(caseDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(caseDir, "data.csv"), []byte("a,b\n1,2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cases, err := LoadCases(dir)
+	if err != nil {
+		t.Fatalf("LoadCases: %v", err)
+	}
+	if err := cases[0].Record(relationships, map[string]string{"pairwise_correlation": "2.0.0"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	cases, err = LoadCases(dir)
+	if err != nil {
+		t.Fatalf("LoadCases: %v", err)
+	}
+	return cases[0]
+}
+
+func TestRelationshipsExtractsComparedValues(t *testing.T) {
+	relationships := Relationships([]core.Artifact{
+		{ID: "corr_b_c", Payload: map[string]interface{}{"test_type": "pearson_correlation", "correlation": 0.5, "sample_size": 40, "evidence_id": "assoc_002"}},
+		{ID: "corr_a_b", Payload: map[string]interface{}{"test_type": "pearson_correlation", "p_value": 0.01, "bayes_factor_10": math.NaN()}},
+	})
+	if len(relationships) != 2 || relationships[0].ID != "corr_a_b" {
+		t.Fatalf("expected relationships sorted by ID, got %+v", relationships)
+	}
+	if want := map[string]float64{"p_value": 0.01}; !reflect.DeepEqual(relationships[0].Values, want) {
+		t.Errorf("non-finite values should be left out: got %v", relationships[0].Values)
+	}
+	if want := map[string]float64{"correlation": 0.5, "sample_size": 40}; !reflect.DeepEqual(relationships[1].Values, want) {
+		t.Errorf("got %v, want %v", relationships[1].Values, want)
+	}
+}
+
+func TestCompareAttributesDriftToEngines(t *testing.T) {
+	expected := []Relationship{
+		{ID: "anova_region_price", TestType: "anova", Values: map[string]float64{"p_value": 0.001, "kruskal_p_value": 0.002, "q_value": 0.004}},
+		{ID: "corr_a_b", TestType: "pearson_correlation", Values: map[string]float64{"correlation": 0.5}},
+		{ID: "corr_a_c", TestType: "kendall_tau_b", Values: map[string]float64{"correlation": 0.3}},
+	}
+	c := recordedCase(t, expected)
+
+	actual := []Relationship{
+		{ID: "anova_region_price", TestType: "anova", Values: map[string]float64{"p_value": 0.001 + 1e-12, "kruskal_p_value": 0.003, "q_value": 0.004}},
+		{ID: "corr_a_b", TestType: "pearson_correlation", Values: map[string]float64{"correlation": 0.5, "robust_correlation": 0.45}},
+		{ID: "corr_b_c", TestType: "pearson_correlation", Values: map[string]float64{"correlation": 0.6}},
+	}
+	report, err := c.Compare(actual, map[string]string{"pairwise_correlation": "2.1.0"}, DefaultTolerance)
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	if report.Passed() {
+		t.Fatal("expected the case to drift")
+	}
+	if report.DatasetChanged {
+		t.Error("dataset is unchanged")
+	}
+	if report.Compared != 2 {
+		t.Errorf("compared = %d, want 2", report.Compared)
+	}
+	if len(report.Drifts) != 2 {
+		t.Fatalf("expected the Kruskal-Wallis p-value and the new robust estimate to drift, got %+v", report.Drifts)
+	}
+	if d := report.Drifts[0]; d.Field != "kruskal_p_value" || *d.Expected != 0.002 || *d.Actual != 0.003 {
+		t.Errorf("unexpected drift %+v", d)
+	}
+	if d := report.Drifts[1]; d.Field != "robust_correlation" || d.Expected != nil || *d.Actual != 0.45 {
+		t.Errorf("unexpected drift %+v", d)
+	}
+	if !reflect.DeepEqual(report.Missing, []string{"corr_a_c"}) || !reflect.DeepEqual(report.Extra, []string{"corr_b_c"}) {
+		t.Errorf("missing %v, extra %v", report.Missing, report.Extra)
+	}
+	want := []string{"kendall_tau_b", "kruskal_wallis", "pearson_correlation", "robust"}
+	if !reflect.DeepEqual(report.Engines, want) {
+		t.Errorf("engines = %v, want %v", report.Engines, want)
+	}
+	if !reflect.DeepEqual(report.MethodChanges, []string{"pairwise_correlation 2.0.0 → 2.1.0"}) {
+		t.Errorf("method changes = %v", report.MethodChanges)
+	}
+}
+
+func TestCompareDetectsChangedDataset(t *testing.T) {
+	relationships := []Relationship{{ID: "corr_a_b", TestType: "pearson_correlation", Values: map[string]float64{"correlation": 0.5}}}
+	c := recordedCase(t, relationships)
+	if err := os.WriteFile(c.DataPath, []byte("a,b\n1,3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	report, err := c.Compare(relationships, nil, DefaultTolerance)
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	if report.Passed() || !report.DatasetChanged {
+		t.Errorf("expected a changed dataset to fail the case, got %+v", report)
+	}
+}
+
+func TestToleranceWithin(t *testing.T) {
+	tol := Tolerance{Absolute: 1e-9, Relative: 1e-6}
+	tests := []struct {
+		expected, actual float64
+		want             bool
+	}{
+		{0, 5e-10, true},
+		{0, 2e-9, false},
+		{1000, 1000.0005, true},
+		{1000, 1000.002, false},
+	}
+	for _, tt := range tests {
+		if got := tol.Within(tt.expected, tt.actual); got != tt.want {
+			t.Errorf("Within(%v, %v) = %v, want %v", tt.expected, tt.actual, got, tt.want)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package internal
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
 	"os"
+
+	"gohypo/internal/logging"
 )
 
 // LogLevel represents different logging verbosity levels
@@ -16,7 +20,7 @@ const (
 	LogLevelTrace
 )
 
-// Logger provides leveled logging
+// Logger provides leveled logging through the default slog logger and its LOG_FORMAT
 type Logger struct {
 	level LogLevel
 }
@@ -49,35 +53,35 @@ func NewDefaultLogger() *Logger {
 // Error logs error messages
 func (l *Logger) Error(format string, args ...interface{}) {
 	if l.level >= LogLevelError {
-		log.Printf("[ERROR] "+format, args...)
+		slog.Log(context.Background(), slog.LevelError, fmt.Sprintf(format, args...))
 	}
 }
 
 // Warn logs warning messages
 func (l *Logger) Warn(format string, args ...interface{}) {
 	if l.level >= LogLevelWarn {
-		log.Printf("[WARN] "+format, args...)
+		slog.Log(context.Background(), slog.LevelWarn, fmt.Sprintf(format, args...))
 	}
 }
 
 // Info logs info messages
 func (l *Logger) Info(format string, args ...interface{}) {
 	if l.level >= LogLevelInfo {
-		log.Printf("[INFO] "+format, args...)
+		slog.Log(context.Background(), slog.LevelInfo, fmt.Sprintf(format, args...))
 	}
 }
 
 // Debug logs debug messages
 func (l *Logger) Debug(format string, args ...interface{}) {
 	if l.level >= LogLevelDebug {
-		log.Printf("[DEBUG] "+format, args...)
+		slog.Log(context.Background(), slog.LevelDebug, fmt.Sprintf(format, args...))
 	}
 }
 
 // Trace logs trace messages
 func (l *Logger) Trace(format string, args ...interface{}) {
 	if l.level >= LogLevelTrace {
-		log.Printf("[TRACE] "+format, args...)
+		slog.Log(context.Background(), logging.LevelTrace, fmt.Sprintf(format, args...))
 	}
 }
 
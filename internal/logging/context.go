@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"context"
+	"sync"
+)
+
+// Attribute keys added to every line logged with a run's context
+const (
+	RunIDKey       = "run_id"
+	DatasetIDKey   = "dataset_id"
+	StageKey       = "stage"
+	FingerprintKey = "fingerprint"
+)
+
+type runKey struct{}
+type stageKey struct{}
+
+// run is the identity of a run. The dataset and fingerprint are only known part way
+// through, so they are set on the run every context derived from WithRun shares.
+type run struct {
+	mu          sync.RWMutex
+	id          string
+	datasetID   string
+	fingerprint string
+}
+
+// WithRun returns a context whose log lines carry runID
+func WithRun(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runKey{}, &run{id: runID})
+}
+
+// WithStage returns a context whose log lines carry the pipeline stage
+func WithStage(ctx context.Context, stage string) context.Context {
+	return context.WithValue(ctx, stageKey{}, stage)
+}
+
+// SetDataset records the dataset the run in ctx reads; a context without a run is ignored
+func SetDataset(ctx context.Context, datasetID string) {
+	if r, ok := ctx.Value(runKey{}).(*run); ok {
+		r.mu.Lock()
+		r.datasetID = datasetID
+		r.mu.Unlock()
+	}
+}
+
+// SetFingerprint records the fingerprint of the data the run in ctx sweeps
+func SetFingerprint(ctx context.Context, fingerprint string) {
+	if r, ok := ctx.Value(runKey{}).(*run); ok {
+		r.mu.Lock()
+		r.fingerprint = fingerprint
+		r.mu.Unlock()
+	}
+}
+
+// RunID returns the run ctx belongs to, "" outside a run
+func RunID(ctx context.Context) string {
+	if r, ok := ctx.Value(runKey{}).(*run); ok {
+		return r.id
+	}
+	return ""
+}
+
+// fields returns the run attributes of ctx that are known, in a stable order
+func fields(ctx context.Context) []field {
+	if ctx == nil {
+		return nil
+	}
+	var out []field
+	if r, ok := ctx.Value(runKey{}).(*run); ok {
+		r.mu.RLock()
+		out = append(out, field{RunIDKey, r.id})
+		if r.datasetID != "" {
+			out = append(out, field{DatasetIDKey, r.datasetID})
+		}
+		if r.fingerprint != "" {
+			out = append(out, field{FingerprintKey, r.fingerprint})
+		}
+		r.mu.RUnlock()
+	}
+	if stage, ok := ctx.Value(stageKey{}).(string); ok && stage != "" {
+		out = append(out, field{StageKey, stage})
+	}
+	return out
+}
+
+type field struct {
+	key, value string
+}
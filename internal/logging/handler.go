@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// contextHandler adds the run attributes of the context to each record before the inner
+// handler writes it, and records the lines of runs in the run log
+type contextHandler struct {
+	inner  slog.Handler
+	runs   *RunLog
+	attrs  []slog.Attr // attributes from With, kept for the run log
+	prefix string      // group prefix of later attribute keys, from WithGroup
+}
+
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	runFields := fields(ctx)
+	for _, f := range runFields {
+		r.AddAttrs(slog.String(f.key, f.value))
+	}
+	if h.runs != nil && len(runFields) > 0 && runFields[0].key == RunIDKey {
+		h.runs.add(runFields[0].value, h.entry(r))
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := *h
+	next.inner = h.inner.WithAttrs(attrs)
+	next.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, a := range attrs {
+		next.attrs = append(next.attrs, slog.Attr{Key: h.prefix + a.Key, Value: a.Value})
+	}
+	return &next
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	next := *h
+	next.inner = h.inner.WithGroup(name)
+	next.prefix = h.prefix + name + "."
+	return &next
+}
+
+// entry is the run log copy of a record, without the run ID every entry of a run shares
+func (h *contextHandler) entry(r slog.Record) Entry {
+	e := Entry{Time: r.Time, Level: LevelName(r.Level), Message: r.Message, level: r.Level}
+	add := func(key string, value slog.Value) {
+		if key == RunIDKey {
+			return
+		}
+		if e.Attrs == nil {
+			e.Attrs = make(map[string]any)
+		}
+		// Errors are kept as their message, which is what they mean once encoded as JSON
+		if err, ok := value.Resolve().Any().(error); ok {
+			e.Attrs[key] = err.Error()
+			return
+		}
+		e.Attrs[key] = value.Resolve().Any()
+	}
+	for _, a := range h.attrs {
+		add(a.Key, a.Value)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		add(h.prefix+a.Key, a.Value)
+		return true
+	})
+	return e
+}
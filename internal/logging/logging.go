@@ -0,0 +1,93 @@
+// Package logging configures the process logger: a slog handler writing text or JSON that
+// tags each line with the run it belongs to (run_id, dataset_id, stage, fingerprint) from
+// the context, and keeps every run's recent lines so they can be read back per run.
+//
+// Setup installs the handler as slog's default, which also routes the log package through
+// it, so log.Printf lines keep working and share the level and format.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// LevelTrace is below debug, for the most verbose LOG_LEVEL
+const LevelTrace = slog.LevelDebug - 4
+
+// Runs keeps the recent lines of the runs logged through the default logger
+var Runs = NewRunLog(defaultMaxRuns, defaultMaxEntries)
+
+// ParseLevel reads a LOG_LEVEL value: error, warn, info, debug or trace, in any case
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "error":
+		return slog.LevelError, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "trace":
+		return LevelTrace, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (want error, warn, info, debug or trace)", name)
+}
+
+// LevelName is the LOG_LEVEL name of a level
+func LevelName(level slog.Level) string {
+	if level <= LevelTrace {
+		return "TRACE"
+	}
+	return level.String()
+}
+
+// New builds a logger writing to w in format, recording run lines in runs when it is set
+func New(w io.Writer, level slog.Level, format string, runs *RunLog) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey && len(groups) == 0 {
+				if l, ok := a.Value.Any().(slog.Level); ok {
+					a.Value = slog.StringValue(LevelName(l))
+				}
+			}
+			return a
+		},
+	}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q (want text or json)", format)
+	}
+	return slog.New(&contextHandler{inner: handler, runs: runs}), nil
+}
+
+// Setup makes a logger on stderr the default for slog and the log package
+func Setup(levelName, format string) error {
+	level, err := ParseLevel(levelName)
+	if err != nil {
+		return err
+	}
+	logger, err := New(os.Stderr, level, format, Runs)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	log.SetFlags(0) // the handler stamps the time
+	return nil
+}
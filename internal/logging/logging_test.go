@@ -0,0 +1,179 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// TestRunAttributesOnEveryLine verifies lines logged with a run's context carry its run,
+// dataset, stage and fingerprint, including ones set after the context was derived
+func TestRunAttributesOnEveryLine(t *testing.T) {
+	var buf bytes.Buffer
+	runs := NewRunLog(10, 10)
+	logger, err := New(&buf, slog.LevelInfo, FormatJSON, runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithRun(context.Background(), "session-1")
+	sweepCtx := WithStage(ctx, "sweep")
+	SetDataset(sweepCtx, "dataset-1")
+	SetFingerprint(sweepCtx, "abc123")
+	logger.InfoContext(sweepCtx, "Stats sweep completed", "relationships", 4)
+	logger.InfoContext(WithStage(ctx, "generation"), "Generating hypotheses")
+	logger.Info("Not part of a run")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %s", len(lines), buf.String())
+	}
+	var first, second map[string]any
+	json.Unmarshal(lines[0], &first)
+	json.Unmarshal(lines[1], &second)
+	if first[RunIDKey] != "session-1" || first[DatasetIDKey] != "dataset-1" || first[StageKey] != "sweep" || first[FingerprintKey] != "abc123" {
+		t.Fatalf("expected the run attributes on the sweep line, got %v", first)
+	}
+	if second[StageKey] != "generation" || second[DatasetIDKey] != "dataset-1" {
+		t.Fatalf("expected the dataset set during the sweep on later stages, got %v", second)
+	}
+
+	entries, _, ok := runs.Entries("session-1", slog.LevelInfo)
+	if !ok || len(entries) != 2 {
+		t.Fatalf("expected the run's two lines in the run log, got %d", len(entries))
+	}
+	if entries[0].Attrs["relationships"] != int64(4) || entries[0].Attrs[StageKey] != "sweep" {
+		t.Fatalf("expected the line's attributes in the run log, got %v", entries[0].Attrs)
+	}
+}
+
+// TestRunLogBounds verifies the run log drops the oldest lines and runs beyond its limits
+func TestRunLogBounds(t *testing.T) {
+	runs := NewRunLog(2, 3)
+	for _, id := range []string{"a", "b"} {
+		runs.add(id, Entry{Message: "start", level: slog.LevelInfo})
+	}
+	for i := 0; i < 4; i++ {
+		runs.add("b", Entry{Message: "step", level: slog.LevelDebug})
+	}
+	runs.add("c", Entry{Message: "start", level: slog.LevelInfo})
+
+	if _, _, ok := runs.Entries("a", slog.LevelInfo); ok {
+		t.Fatal("expected the oldest run to be dropped for a third")
+	}
+	entries, dropped, ok := runs.Entries("b", LevelTrace)
+	if !ok || len(entries) != 3 || dropped != 2 {
+		t.Fatalf("expected 3 kept and 2 dropped lines, got %d kept and %d dropped", len(entries), dropped)
+	}
+	if entries, _, _ := runs.Entries("b", slog.LevelInfo); len(entries) != 0 {
+		t.Fatalf("expected debug lines filtered at info, got %d", len(entries))
+	}
+}
+
+// TestFollowRun verifies a follower gets the run's kept lines and then its later ones at
+// its level, numbered in order, and that cancelling closes the channel
+func TestFollowRun(t *testing.T) {
+	runs := NewRunLog(10, 10)
+	runs.add("a", Entry{Message: "scout", level: slog.LevelInfo})
+	runs.add("a", Entry{Message: "probe", level: slog.LevelDebug})
+
+	backlog, lines, cancel := runs.Follow("a", slog.LevelInfo)
+	if len(backlog) != 1 || backlog[0].Message != "scout" || backlog[0].Seq != 1 {
+		t.Fatalf("expected the info line as backlog, got %+v", backlog)
+	}
+	runs.add("a", Entry{Message: "probe", level: slog.LevelDebug})
+	runs.add("b", Entry{Message: "other run", level: slog.LevelInfo})
+	runs.add("a", Entry{Message: "Column skipped", level: slog.LevelWarn})
+
+	select {
+	case e := <-lines:
+		if e.Message != "Column skipped" || e.Seq != 4 {
+			t.Fatalf("expected the run's next line at info or above, got %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a live line")
+	}
+	if missed := cancel(); missed != 0 {
+		t.Fatalf("expected no missed lines, got %d", missed)
+	}
+	if _, open := <-lines; open {
+		t.Fatal("expected cancel to close the channel")
+	}
+	runs.add("a", Entry{Message: "after cancel", level: slog.LevelInfo}) // must not send on the closed channel
+}
+
+// memoryRunLogs stores run log lines in memory
+type memoryRunLogs struct {
+	ports.RunLogStore
+	mu      sync.Mutex
+	entries []models.RunLogEntry
+}
+
+func (m *memoryRunLogs) AppendRunLogs(ctx context.Context, entries []models.RunLogEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, entries...)
+	return nil
+}
+
+// TestPersistRunLines verifies persisted lines keep their run, order, level and attributes,
+// and read back as the entries they were logged as
+func TestPersistRunLines(t *testing.T) {
+	var buf bytes.Buffer
+	runs := NewRunLog(10, 10)
+	logger, _ := New(&buf, slog.LevelInfo, FormatJSON, runs)
+	store := &memoryRunLogs{}
+	ctx, cancel := context.WithCancel(context.Background())
+	runs.Persist(ctx, store)
+
+	runCtx := WithStage(WithRun(context.Background(), "session-1"), "sweep")
+	logger.InfoContext(runCtx, "Stats sweep started")
+	logger.WarnContext(runCtx, "Skipped column", "column", "email", "error", errors.New("free text"))
+	logger.Info("Not part of a run")
+	cancel()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		store.mu.Lock()
+		n := len(store.entries)
+		store.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the run's 2 lines stored, got %d", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	warn := store.entries[1]
+	if warn.RunID != "session-1" || warn.Seq != 2 || warn.Level != "WARN" || warn.Attrs["error"] != "free text" {
+		t.Fatalf("expected the warning stored with its attributes, got %+v", warn)
+	}
+	entries := StoredEntries(store.entries)
+	if entries[1].level != slog.LevelWarn || entries[0].Attrs[StageKey] != "sweep" {
+		t.Fatalf("expected stored lines to read back with their level, got %+v", entries)
+	}
+}
+
+// TestParseLevel verifies the LOG_LEVEL names, and that unknown ones are rejected
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]slog.Level{"": slog.LevelInfo, "WARN": slog.LevelWarn, "debug": slog.LevelDebug, "trace": LevelTrace} {
+		if got, err := ParseLevel(name); err != nil || got != want {
+			t.Fatalf("ParseLevel(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Fatal("expected an unknown level to be rejected")
+	}
+	if _, err := New(&bytes.Buffer{}, slog.LevelInfo, "xml", nil); err == nil {
+		t.Fatal("expected an unknown format to be rejected")
+	}
+}
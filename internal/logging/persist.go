@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// Persistence batching: lines are written every persistInterval, or sooner once
+// persistBatch are waiting. Beyond persistQueue waiting lines, new ones are not stored.
+const (
+	persistInterval = time.Second
+	persistBatch    = 200
+	persistQueue    = 10000
+)
+
+type persistedEntry struct {
+	runID string
+	entry Entry
+}
+
+// Persist stores every later run line in store, in batches written in the background
+// until ctx is done. Logging never waits on the store: when it falls behind, lines are
+// dropped from storage and the count is logged.
+func (l *RunLog) Persist(ctx context.Context, store ports.RunLogStore) {
+	queue := make(chan persistedEntry, persistQueue)
+	l.mu.Lock()
+	l.persist = queue
+	l.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(persistInterval)
+		defer ticker.Stop()
+		batch := make([]models.RunLogEntry, 0, persistBatch)
+		flush := func() {
+			if dropped := l.persistDropped.Swap(0); dropped > 0 {
+				slog.Warn("Run log storage fell behind, lines were not stored", "lines", dropped)
+			}
+			if len(batch) == 0 {
+				return
+			}
+			writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+			defer cancel()
+			if err := store.AppendRunLogs(writeCtx, batch); err != nil {
+				slog.Error("Failed to store run log lines", "lines", len(batch), "error", err)
+			}
+			batch = batch[:0]
+		}
+		for {
+			select {
+			case p := <-queue:
+				batch = append(batch, stored(p.runID, p.entry))
+				if len(batch) >= persistBatch {
+					flush()
+				}
+			case <-ticker.C:
+				flush()
+			case <-ctx.Done():
+				l.mu.Lock()
+				l.persist = nil
+				l.mu.Unlock()
+				for {
+					select {
+					case p := <-queue:
+						batch = append(batch, stored(p.runID, p.entry))
+						if len(batch) >= persistBatch {
+							flush()
+						}
+					default:
+						flush()
+						return
+					}
+				}
+			}
+		}
+	}()
+}
+
+// stored is the form a run's line is persisted in
+func stored(runID string, e Entry) models.RunLogEntry {
+	return models.RunLogEntry{
+		RunID:    runID,
+		Seq:      e.Seq,
+		Time:     e.Time,
+		Level:    e.Level,
+		Severity: int(e.level),
+		Message:  e.Message,
+		Attrs:    e.Attrs,
+	}
+}
+
+// StoredEntries turns lines read back from a RunLogStore into entries
+func StoredEntries(rows []models.RunLogEntry) []Entry {
+	entries := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		e := Entry{
+			Seq:     row.Seq,
+			Time:    row.Time,
+			Level:   row.Level,
+			Message: row.Message,
+			level:   slog.Level(row.Severity),
+		}
+		if len(row.Attrs) > 0 {
+			e.Attrs = row.Attrs
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
@@ -0,0 +1,162 @@
+package logging
+
+import (
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Bounds of the default run log: a busy server keeps the last lines of its recent runs
+const (
+	defaultMaxRuns    = 200
+	defaultMaxEntries = 5000
+)
+
+// Entry is one log line of a run
+type Entry struct {
+	Seq     int64          `json:"seq"` // order of the line within its run, from 1
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+
+	level slog.Level
+}
+
+// RunLog keeps the most recent lines of the most recent runs in memory. Lines beyond a
+// run's limit push out its oldest ones, and a new run beyond the run limit pushes out the
+// run that started logging first. Followers receive a run's lines as they are logged, and
+// a persister started with Persist stores every line.
+type RunLog struct {
+	mu         sync.Mutex
+	maxRuns    int
+	maxEntries int
+	runs       map[string]*runEntries
+	order      []string // run IDs, oldest first
+	followers  map[string]map[*follower]struct{}
+	persist    chan<- persistedEntry // nil until Persist
+
+	persistDropped atomic.Int64 // lines not stored because the persister fell behind
+}
+
+type runEntries struct {
+	entries []Entry
+	dropped int
+	seq     int64 // of the latest line
+}
+
+// follower is one live tail of a run, which misses lines while its buffer is full
+type follower struct {
+	ch       chan Entry
+	minLevel slog.Level
+	missed   int
+}
+
+// followBuffer is how many lines a follower may fall behind before it misses some
+const followBuffer = 256
+
+// NewRunLog keeps up to maxEntries lines for each of up to maxRuns runs
+func NewRunLog(maxRuns, maxEntries int) *RunLog {
+	return &RunLog{
+		maxRuns:    max(maxRuns, 1),
+		maxEntries: max(maxEntries, 1),
+		runs:       make(map[string]*runEntries),
+		followers:  make(map[string]map[*follower]struct{}),
+	}
+}
+
+func (l *RunLog) add(runID string, e Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	run, ok := l.runs[runID]
+	if !ok {
+		if len(l.order) >= l.maxRuns {
+			delete(l.runs, l.order[0])
+			l.order = l.order[1:]
+		}
+		run = &runEntries{}
+		l.runs[runID] = run
+		l.order = append(l.order, runID)
+	}
+	if len(run.entries) >= l.maxEntries {
+		run.entries = run.entries[1:]
+		run.dropped++
+	}
+	run.seq++
+	e.Seq = run.seq
+	run.entries = append(run.entries, e)
+
+	for f := range l.followers[runID] {
+		if e.level < f.minLevel {
+			continue
+		}
+		select {
+		case f.ch <- e:
+		default:
+			f.missed++
+		}
+	}
+	if l.persist != nil {
+		select {
+		case l.persist <- persistedEntry{runID: runID, entry: e}:
+		default:
+			l.persistDropped.Add(1)
+		}
+	}
+}
+
+// Entries returns a run's kept lines at minLevel or above, oldest first, with the number
+// of lines dropped to stay within the limit. ok is false for a run with no kept lines.
+func (l *RunLog) Entries(runID string, minLevel slog.Level) (entries []Entry, dropped int, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	run, ok := l.runs[runID]
+	if !ok {
+		return nil, 0, false
+	}
+	entries = make([]Entry, 0, len(run.entries))
+	for _, e := range run.entries {
+		if e.level >= minLevel {
+			entries = append(entries, e)
+		}
+	}
+	return entries, run.dropped, true
+}
+
+// Follow returns a run's kept lines at minLevel or above with a channel receiving its later
+// ones at that level, with nothing lost in between. cancel closes the channel and returns
+// how many lines the follower missed by falling behind.
+func (l *RunLog) Follow(runID string, minLevel slog.Level) (backlog []Entry, lines <-chan Entry, cancel func() int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if run, ok := l.runs[runID]; ok {
+		for _, e := range run.entries {
+			if e.level >= minLevel {
+				backlog = append(backlog, e)
+			}
+		}
+	}
+	f := &follower{ch: make(chan Entry, followBuffer), minLevel: minLevel}
+	if l.followers[runID] == nil {
+		l.followers[runID] = make(map[*follower]struct{})
+	}
+	l.followers[runID][f] = struct{}{}
+
+	cancel = func() int {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if _, ok := l.followers[runID][f]; ok {
+			delete(l.followers[runID], f)
+			if len(l.followers[runID]) == 0 {
+				delete(l.followers, runID)
+			}
+			close(f.ch)
+		}
+		return f.missed
+	}
+	return backlog, f.ch, cancel
+}
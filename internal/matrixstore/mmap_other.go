@@ -0,0 +1,15 @@
+//go:build !unix
+
+package matrixstore
+
+import "os"
+
+// mapColumn reads a column file into memory where memory mapping is not available; the
+// column is still dropped once released and evicted
+func mapColumn(path string, rows int) ([]float64, func() error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return decodeColumn(data, rows), func() error { return nil }, nil
+}
@@ -0,0 +1,33 @@
+//go:build unix
+
+package matrixstore
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// mapColumn maps a column file read-only. The kernel pages values in as they are read and
+// may drop them again under memory pressure, since they are backed by the file.
+func mapColumn(path string, rows int) ([]float64, func() error, error) {
+	if rows == 0 {
+		return []float64{}, func() error { return nil }, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, rows*valueSize, syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !nativeLittleEndian {
+		values := decodeColumn(data, rows)
+		return values, func() error { return nil }, syscall.Munmap(data)
+	}
+	values := unsafe.Slice((*float64)(unsafe.Pointer(&data[0])), rows)
+	return values, func() error { return syscall.Munmap(data) }, nil
+}
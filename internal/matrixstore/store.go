@@ -0,0 +1,370 @@
+// Package matrixstore keeps matrices too large for memory on disk, one file per column,
+// and serves their columns through memory mappings.
+//
+// A store is a directory holding manifest.json and one file per column with the column's
+// values as little-endian float64s, one per row; NaN marks a missing value as it does in
+// a MatrixBundle. Columns are mapped when acquired and unmapped once released and evicted,
+// so resident memory is bounded by the number of mapped columns, whatever the matrix size.
+package matrixstore
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+)
+
+const (
+	manifestFile  = "manifest.json"
+	formatVersion = 1
+	valueSize     = 8 // bytes per float64
+
+	// DefaultMappedColumns is how many released columns a store keeps mapped for reuse
+	DefaultMappedColumns = 64
+
+	// writeBufferBytes bounds the rows a Writer buffers before appending them to the column
+	// files, whatever the matrix width
+	writeBufferBytes = 64 << 20
+)
+
+// nativeLittleEndian reports whether column files can be mapped as float64s directly;
+// elsewhere they are decoded into memory when acquired
+var nativeLittleEndian = binary.NativeEndian.Uint16([]byte{1, 0}) == 1
+
+// Manifest describes a store's matrix. It is written last, so a store whose writer did not
+// finish has none and cannot be opened.
+type Manifest struct {
+	Version    int             `json:"version"`
+	SnapshotID core.SnapshotID `json:"snapshot_id,omitempty"`
+	CohortHash core.CohortHash `json:"cohort_hash,omitempty"`
+	Rows       int             `json:"rows"`
+	Columns    []Column        `json:"columns"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// Column is one variable of the matrix and the file holding its values
+type Column struct {
+	Key  core.VariableKey        `json:"key"`
+	Type dataset.StatisticalType `json:"type,omitempty"`
+	File string                  `json:"file"`
+}
+
+func columnFile(j int) string {
+	return fmt.Sprintf("col_%06d.f64", j)
+}
+
+// Writer builds a store row by row, buffering a bounded number of rows and appending them
+// to the column files, so a matrix never has to be held in memory to be stored
+type Writer struct {
+	dir      string
+	manifest Manifest
+	buffer   [][]float64 // buffered values by column
+	capacity int         // rows buffered before a flush
+	encoded  []byte
+	closed   bool
+}
+
+// Create starts a store in dir for the given columns, replacing any store already there
+func Create(dir string, columns []Column) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create matrix store %s: %w", dir, err)
+	}
+	if err := os.Remove(filepath.Join(dir, manifestFile)); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to replace matrix store %s: %w", dir, err)
+	}
+
+	manifest := Manifest{Version: formatVersion, Columns: make([]Column, len(columns))}
+	for j, column := range columns {
+		column.File = columnFile(j)
+		manifest.Columns[j] = column
+		file, err := os.Create(filepath.Join(dir, column.File))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create column file for %s: %w", column.Key, err)
+		}
+		file.Close()
+	}
+
+	capacity := 1
+	if len(columns) > 0 && writeBufferBytes/(len(columns)*valueSize) > 1 {
+		capacity = writeBufferBytes / (len(columns) * valueSize)
+	}
+	w := &Writer{dir: dir, manifest: manifest, buffer: make([][]float64, len(columns)), capacity: capacity}
+	for j := range w.buffer {
+		w.buffer[j] = make([]float64, 0, min(capacity, 1024))
+	}
+	return w, nil
+}
+
+// SetSnapshot records the snapshot and cohort the matrix was resolved for
+func (w *Writer) SetSnapshot(snapshotID core.SnapshotID, cohortHash core.CohortHash) {
+	w.manifest.SnapshotID = snapshotID
+	w.manifest.CohortHash = cohortHash
+}
+
+// AppendRow adds one entity's values; a row shorter than the matrix is padded with NaN
+func (w *Writer) AppendRow(row []float64) error {
+	if w.closed {
+		return fmt.Errorf("matrix store %s is closed", w.dir)
+	}
+	if len(row) > len(w.buffer) {
+		return fmt.Errorf("row has %d values, matrix has %d columns", len(row), len(w.buffer))
+	}
+	for j := range w.buffer {
+		value := math.NaN()
+		if j < len(row) {
+			value = row[j]
+		}
+		w.buffer[j] = append(w.buffer[j], value)
+	}
+	w.manifest.Rows++
+	if w.manifest.Rows%w.capacity == 0 {
+		return w.flush()
+	}
+	return nil
+}
+
+// flush appends the buffered rows to the column files
+func (w *Writer) flush() error {
+	for j, values := range w.buffer {
+		if len(values) == 0 {
+			continue
+		}
+		w.encoded = w.encoded[:0]
+		for _, value := range values {
+			w.encoded = binary.LittleEndian.AppendUint64(w.encoded, math.Float64bits(value))
+		}
+		path := filepath.Join(w.dir, w.manifest.Columns[j].File)
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			return fmt.Errorf("failed to open column file %s: %w", path, err)
+		}
+		_, err = file.Write(w.encoded)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write column file %s: %w", path, err)
+		}
+		w.buffer[j] = values[:0]
+	}
+	return nil
+}
+
+// Close flushes the buffered rows and writes the manifest, completing the store
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if err := w.flush(); err != nil {
+		return err
+	}
+	w.manifest.CreatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode matrix store manifest: %w", err)
+	}
+	tmp := filepath.Join(w.dir, manifestFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write matrix store manifest: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(w.dir, manifestFile)); err != nil {
+		return fmt.Errorf("failed to write matrix store manifest: %w", err)
+	}
+	return nil
+}
+
+// WriteBundle stores an in-memory bundle in dir
+func WriteBundle(dir string, bundle *dataset.MatrixBundle) error {
+	types := make(map[core.VariableKey]dataset.StatisticalType, len(bundle.ColumnMeta))
+	for _, meta := range bundle.ColumnMeta {
+		types[meta.VariableKey] = meta.StatisticalType
+	}
+	columns := make([]Column, len(bundle.Matrix.VariableKeys))
+	for j, key := range bundle.Matrix.VariableKeys {
+		columns[j] = Column{Key: key, Type: types[key]}
+	}
+
+	w, err := Create(dir, columns)
+	if err != nil {
+		return err
+	}
+	w.SetSnapshot(bundle.SnapshotID, bundle.CohortHash)
+	for _, row := range bundle.Matrix.Data {
+		if err := w.AppendRow(row); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+// Matrix is an opened store. It serves columns to any number of goroutines; each acquired
+// column stays mapped until released, and up to maxMapped released columns stay mapped
+// for the next caller.
+type Matrix struct {
+	dir       string
+	manifest  Manifest
+	variables []core.VariableKey
+	maxMapped int
+
+	mu     sync.Mutex
+	mapped map[int]*mapping
+	clock  uint64
+}
+
+type mapping struct {
+	values   []float64
+	unmap    func() error
+	refs     int
+	lastUsed uint64
+}
+
+// Open opens the store in dir, keeping up to maxMapped released columns mapped
+// (0 = DefaultMappedColumns)
+func Open(dir string, maxMapped int) (*Matrix, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read matrix store manifest: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse matrix store manifest: %w", err)
+	}
+	if manifest.Version != formatVersion {
+		return nil, fmt.Errorf("unsupported matrix store version %d (want %d)", manifest.Version, formatVersion)
+	}
+
+	variables := make([]core.VariableKey, len(manifest.Columns))
+	for j, column := range manifest.Columns {
+		info, err := os.Stat(filepath.Join(dir, column.File))
+		if err != nil {
+			return nil, fmt.Errorf("missing column file for %s: %w", column.Key, err)
+		}
+		if info.Size() != int64(manifest.Rows)*valueSize {
+			return nil, fmt.Errorf("column file for %s holds %d bytes, expected %d", column.Key, info.Size(), int64(manifest.Rows)*valueSize)
+		}
+		variables[j] = column.Key
+	}
+
+	if maxMapped <= 0 {
+		maxMapped = DefaultMappedColumns
+	}
+	return &Matrix{
+		dir:       dir,
+		manifest:  manifest,
+		variables: variables,
+		maxMapped: maxMapped,
+		mapped:    make(map[int]*mapping),
+	}, nil
+}
+
+// Manifest returns the store's manifest
+func (m *Matrix) Manifest() Manifest {
+	return m.manifest
+}
+
+// Rows returns the number of entities
+func (m *Matrix) Rows() int {
+	return m.manifest.Rows
+}
+
+// Variables returns the variable keys in column order
+func (m *Matrix) Variables() []core.VariableKey {
+	return m.variables
+}
+
+// ColumnType returns column j's statistical type, empty when unknown
+func (m *Matrix) ColumnType(j int) dataset.StatisticalType {
+	if j < 0 || j >= len(m.manifest.Columns) {
+		return ""
+	}
+	return m.manifest.Columns[j].Type
+}
+
+// AcquireColumn maps column j, or reuses its mapping, and returns its values. They are
+// valid until release is called and must not be modified.
+func (m *Matrix) AcquireColumn(j int) ([]float64, func(), error) {
+	if j < 0 || j >= len(m.manifest.Columns) {
+		return nil, nil, fmt.Errorf("column %d out of range (%d columns)", j, len(m.manifest.Columns))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.mapped[j]
+	if !ok {
+		values, unmap, err := mapColumn(filepath.Join(m.dir, m.manifest.Columns[j].File), m.manifest.Rows)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to map column %s: %w", m.manifest.Columns[j].Key, err)
+		}
+		entry = &mapping{values: values, unmap: unmap}
+		m.mapped[j] = entry
+	}
+	entry.refs++
+	m.clock++
+	entry.lastUsed = m.clock
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			entry.refs--
+			m.evictLocked()
+		})
+	}
+	return entry.values, release, nil
+}
+
+// evictLocked unmaps the least recently used released columns beyond maxMapped
+func (m *Matrix) evictLocked() {
+	for len(m.mapped) > m.maxMapped {
+		victim, oldest := -1, uint64(0)
+		for j, entry := range m.mapped {
+			if entry.refs == 0 && (victim < 0 || entry.lastUsed < oldest) {
+				victim, oldest = j, entry.lastUsed
+			}
+		}
+		if victim < 0 { // every mapped column is in use
+			return
+		}
+		m.mapped[victim].unmap()
+		delete(m.mapped, victim)
+	}
+}
+
+// Resident returns how many columns are mapped
+func (m *Matrix) Resident() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.mapped)
+}
+
+// Close unmaps every column. Columns still acquired must not be read afterwards.
+func (m *Matrix) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var firstErr error
+	for j, entry := range m.mapped {
+		if err := entry.unmap(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(m.mapped, j)
+	}
+	return firstErr
+}
+
+// decodeColumn reads little-endian float64s into memory
+func decodeColumn(data []byte, rows int) []float64 {
+	values := make([]float64, rows)
+	for i := range values {
+		values[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[i*valueSize:]))
+	}
+	return values
+}
@@ -0,0 +1,150 @@
+package matrixstore
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+)
+
+func testColumns(n int) []Column {
+	columns := make([]Column, n)
+	for j := range columns {
+		columns[j] = Column{Key: core.VariableKey(fmt.Sprintf("v%d", j)), Type: dataset.TypeNumeric}
+	}
+	return columns
+}
+
+// TestStoreRoundTrip verifies a bundle written to a store reads back column by column, with
+// short rows padded as missing
+func TestStoreRoundTrip(t *testing.T) {
+	bundle := &dataset.MatrixBundle{SnapshotID: "snap"}
+	bundle.Matrix.VariableKeys = []core.VariableKey{"a", "b", "c"}
+	bundle.Matrix.Data = [][]float64{{1, 2, 3}, {4, 5}, {7, 8, 9}}
+	bundle.ColumnMeta = []dataset.ColumnMeta{{VariableKey: "c", StatisticalType: dataset.TypeCategorical}}
+
+	dir := t.TempDir()
+	if err := WriteBundle(dir, bundle); err != nil {
+		t.Fatal(err)
+	}
+	store, err := Open(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if store.Rows() != 3 || len(store.Variables()) != 3 || store.Manifest().SnapshotID != "snap" {
+		t.Fatalf("unexpected manifest %+v", store.Manifest())
+	}
+	if store.ColumnType(2) != dataset.TypeCategorical || store.ColumnType(0) != "" {
+		t.Errorf("unexpected column types %q %q", store.ColumnType(0), store.ColumnType(2))
+	}
+	for j := range bundle.Matrix.VariableKeys {
+		column, release, err := store.AcquireColumn(j)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := bundle.ColumnAt(j)
+		for i := range want {
+			if column[i] != want[i] && !(math.IsNaN(column[i]) && math.IsNaN(want[i])) {
+				t.Errorf("column %d row %d = %v, want %v", j, i, column[i], want[i])
+			}
+		}
+		release()
+	}
+	if _, _, err := store.AcquireColumn(3); err == nil {
+		t.Error("expected an out of range error")
+	}
+}
+
+// TestStoreBoundsMappedColumns verifies released columns beyond the limit are unmapped
+// while acquired ones stay readable
+func TestStoreBoundsMappedColumns(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Create(dir, testColumns(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		row := make([]float64, 10)
+		for j := range row {
+			row[j] = float64(i*10 + j)
+		}
+		if err := w.AppendRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := Open(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	held, releaseHeld, err := store.AcquireColumn(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 1; j < 10; j++ {
+				column, release, err := store.AcquireColumn(j)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if column[99] != float64(990+j) {
+					t.Errorf("column %d ends with %v", j, column[99])
+				}
+				release()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if resident := store.Resident(); resident > 2 {
+		t.Errorf("%d columns mapped, limit is 2", resident)
+	}
+	if held[99] != 990 {
+		t.Errorf("held column ends with %v", held[99])
+	}
+	releaseHeld()
+}
+
+// TestStoreRequiresManifest verifies an unfinished or damaged store cannot be opened
+func TestStoreRequiresManifest(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Create(dir, testColumns(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AppendRow([]float64{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(dir, 0); err == nil {
+		t.Fatal("expected an error opening a store without a manifest")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AppendRow([]float64{1, 2}); err == nil {
+		t.Error("expected an error appending to a closed store")
+	}
+	if err := os.Truncate(filepath.Join(dir, columnFile(1)), 4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(dir, 0); err == nil {
+		t.Error("expected an error opening a store with a truncated column")
+	}
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"runtime"
+	"time"
+)
+
+// Sweep service
+var (
+	SweepDuration = NewHistogramVec("gohypo_sweep_duration_seconds",
+		"Duration of statistical sweeps by mode (full, incremental, time_series) and outcome.",
+		DurationBuckets, "mode", "outcome")
+	SweepPairs = NewCounterVec("gohypo_sweep_pairs_total",
+		"Variable pairs handled by sweeps, by how the result was obtained (computed, reused, resumed).",
+		"source")
+	SweepPairsPerSecond = NewGaugeVec("gohypo_sweep_pairs_per_second",
+		"Pairs computed per second by the most recent sweep of each mode.",
+		"mode")
+)
+
+// LLM providers
+var (
+	LLMRequestDuration = NewHistogramVec("gohypo_llm_request_duration_seconds",
+		"Latency of LLM provider calls, failed calls included.",
+		DurationBuckets, "provider", "model")
+	LLMErrors = NewCounterVec("gohypo_llm_errors_total",
+		"LLM provider calls that failed.",
+		"provider", "model")
+)
+
+// Research worker pool
+var (
+	ResearchSessionsActive = NewGaugeVec("gohypo_research_sessions_active",
+		"Research sessions the worker is processing.")
+	ValidationsCompleted = NewCounterVec("gohypo_validation_completed_total",
+		"Queued hypothesis validations that finished, by verdict (passed, failed).",
+		"verdict")
+)
+
+// UI server
+var (
+	HTTPRequests = NewCounterVec("gohypo_http_requests_total",
+		"HTTP requests served, by method, route pattern and status code.",
+		"method", "route", "status")
+	HTTPRequestDuration = NewHistogramVec("gohypo_http_request_duration_seconds",
+		"Latency of HTTP requests by method and route pattern.",
+		DurationBuckets, "method", "route")
+)
+
+func init() {
+	GaugeFunc("go_goroutines", "Number of goroutines that currently exist.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+}
+
+// Since is the seconds elapsed since start, for observing a duration
+func Since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
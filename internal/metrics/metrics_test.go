@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"bufio"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	Default.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("content type %q", ct)
+	}
+	return rec.Body.String()
+}
+
+func TestExposition(t *testing.T) {
+	requests := NewCounterVec("test_requests_total", "Requests.", "route", "status")
+	requests.Inc("/api/datasets/:id", "200")
+	requests.Add(2, "/api/datasets/:id", "200")
+	requests.Inc(`odd"route`, "500")
+	queue := NewGaugeVec("test_queue_depth", "Queue depth.")
+	queue.Set(4)
+	queue.Add(-1)
+
+	body := scrape(t)
+	for _, want := range []string{
+		"# HELP test_requests_total Requests.\n# TYPE test_requests_total counter\n",
+		`test_requests_total{route="/api/datasets/:id",status="200"} 3` + "\n",
+		`test_requests_total{route="odd\"route",status="500"} 1` + "\n",
+		"# TYPE test_queue_depth gauge\ntest_queue_depth 3\n",
+		"# TYPE go_goroutines gauge\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("scrape is missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Index(body, "test_queue_depth") > strings.Index(body, "test_requests_total") {
+		t.Error("families should be sorted by name")
+	}
+}
+
+func TestHistogramBucketsAreCumulative(t *testing.T) {
+	latency := NewHistogramVec("test_latency_seconds", "Latency.", []float64{1, 0.1}, "provider")
+	for _, v := range []float64{0.05, 0.1, 0.5, 30} {
+		latency.Observe(v, "openai")
+	}
+	if n := latency.Count("openai"); n != 4 {
+		t.Fatalf("count = %d, want 4", n)
+	}
+
+	var sb strings.Builder
+	w := bufio.NewWriter(&sb)
+	latency.write(w)
+	w.Flush()
+	want := strings.Join([]string{
+		"# HELP test_latency_seconds Latency.",
+		"# TYPE test_latency_seconds histogram",
+		`test_latency_seconds_bucket{provider="openai",le="0.1"} 2`, // bounds are inclusive
+		`test_latency_seconds_bucket{provider="openai",le="1"} 3`,
+		`test_latency_seconds_bucket{provider="openai",le="+Inf"} 4`,
+		`test_latency_seconds_sum{provider="openai"} 30.65`,
+		`test_latency_seconds_count{provider="openai"} 4`,
+	}, "\n") + "\n"
+	if sb.String() != want {
+		t.Errorf("histogram =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
+
+func TestWrongLabelCountPanics(t *testing.T) {
+	counter := NewCounterVec("test_labelled_total", "Labelled.", "verdict")
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a missing label value")
+		}
+	}()
+	counter.Inc()
+}
@@ -18,6 +18,7 @@ type Migrator interface {
 // MigrationRunner handles database schema migrations
 type MigrationRunner struct {
 	version string
+	sqlite  bool // migrates a single-file SQLite database instead of PostgreSQL
 }
 
 // NewRunner creates a new migration runner
@@ -27,13 +28,36 @@ func NewRunner() *MigrationRunner {
 	}
 }
 
-// Version returns the migration version
+// NewSQLiteRunner creates a migration runner for a SQLite database
+func NewSQLiteRunner() *MigrationRunner {
+	return &MigrationRunner{
+		version: "1.0.0",
+		sqlite:  true,
+	}
+}
+
+// ForDriver returns the migration runner for a database opened with driverName
+func ForDriver(driverName string) *MigrationRunner {
+	if driverName == "sqlite3" {
+		return NewSQLiteRunner()
+	}
+	return NewRunner()
+}
+
+// Version returns the schema version the runner migrates to
 func (r *MigrationRunner) Version() string {
-	return r.version
+	migrations := r.Migrations()
+	return fmt.Sprintf("%s (schema %d)", r.version, migrations[len(migrations)-1].Version)
 }
 
-// Run executes all database migrations in the correct order
+// Run brings the schema up to the latest version; see Up
 func (r *MigrationRunner) Run(ctx context.Context, db *sqlx.DB) error {
+	return r.Up(ctx, db)
+}
+
+// applyBaseline creates the schema as it stood when versioned migrations were introduced.
+// Its steps are not all re-runnable, so databases created before then adopt it instead.
+func (r *MigrationRunner) applyBaseline(ctx context.Context, db *sqlx.DB) error {
 	if err := r.createUsersTable(ctx, db); err != nil {
 		return errors.Wrap(err, "failed to create users table")
 	}
@@ -74,6 +98,74 @@ func (r *MigrationRunner) Run(ctx context.Context, db *sqlx.DB) error {
 		return errors.Wrap(err, "failed to add workspace_id to hypothesis_results")
 	}
 
+	if err := r.createLLMResponseCacheTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create llm_response_cache table")
+	}
+
+	if err := r.createFewShotExemplarsTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create few_shot_exemplars table")
+	}
+
+	if err := r.createWorkspaceLLMSettingsTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create workspace_llm_settings table")
+	}
+
+	if err := r.createRelationshipChecksTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create relationship_checks table")
+	}
+
+	if err := r.createDecisionsTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create decisions table")
+	}
+
+	if err := r.createShareLinksTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create share_links table")
+	}
+
+	if err := r.createTriageTables(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create triage tables")
+	}
+
+	if err := r.createSQLDataSourcesTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create sql_data_sources table")
+	}
+
+	if err := r.createURLDataSourcesTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create url_data_sources table")
+	}
+
+	if err := r.createRefereeRunsTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create referee_runs table")
+	}
+
+	if err := r.createArtifactArchivesTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create artifact_archives table")
+	}
+
+	if err := r.createRunSummaryTables(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create run summary tables")
+	}
+
+	if err := r.createJobsTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create jobs table")
+	}
+
+	if err := r.createRunLogsTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create run_logs table")
+	}
+
+	if err := r.createLeasesTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create leases table")
+	}
+
+	if err := r.createWebhookTables(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create webhook tables")
+	}
+
+	if err := r.createNotificationChannelsTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create notification channels table")
+	}
+
 	return nil
 }
 
@@ -351,6 +443,276 @@ func (r *MigrationRunner) addHypothesisWorkspaceColumn(ctx context.Context, db *
 	return nil
 }
 
+func (r *MigrationRunner) createLLMResponseCacheTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS llm_response_cache (
+			cache_key TEXT PRIMARY KEY,
+			content TEXT NOT NULL,
+			usage JSONB,
+			hit_count INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			last_hit_at TIMESTAMP WITH TIME ZONE
+		);
+	`)
+	return err
+}
+
+func (r *MigrationRunner) createFewShotExemplarsTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS few_shot_exemplars (
+			id TEXT PRIMARY KEY,
+			domain_pack TEXT NOT NULL DEFAULT 'general',
+			directive JSONB NOT NULL,
+			evidence JSONB,
+			source_hypothesis_id TEXT,
+			status TEXT NOT NULL DEFAULT 'proposed' CHECK (status IN ('proposed', 'approved', 'retired')),
+			proposed_by TEXT,
+			approved_by TEXT,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			approved_at TIMESTAMP WITH TIME ZONE
+		);
+		CREATE INDEX IF NOT EXISTS idx_few_shot_exemplars_pack ON few_shot_exemplars(domain_pack, status);
+	`)
+	return err
+}
+
+func (r *MigrationRunner) createWorkspaceLLMSettingsTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS workspace_llm_settings (
+			workspace_id TEXT PRIMARY KEY,
+			provider TEXT,
+			model TEXT,
+			temperature DOUBLE PRECISION,
+			max_tokens INTEGER NOT NULL DEFAULT 0,
+			locked BOOLEAN NOT NULL DEFAULT false,
+			locked_by TEXT,
+			locked_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+	`)
+	return err
+}
+
+func (r *MigrationRunner) createRelationshipChecksTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS relationship_checks (
+			id TEXT PRIMARY KEY,
+			hypothesis_id TEXT NOT NULL,
+			workspace_id TEXT NOT NULL,
+			dataset_id TEXT NOT NULL,
+			dataset_version TIMESTAMP WITH TIME ZONE NOT NULL,
+			cause TEXT NOT NULL,
+			effect TEXT NOT NULL,
+			validated_correlation DOUBLE PRECISION NOT NULL,
+			correlation DOUBLE PRECISION NOT NULL,
+			directional_effect DOUBLE PRECISION NOT NULL,
+			p_value DOUBLE PRECISION NOT NULL,
+			sample_size INTEGER NOT NULL,
+			floor DOUBLE PRECISION NOT NULL,
+			degraded BOOLEAN NOT NULL,
+			alerted BOOLEAN NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			checked_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_relationship_checks_workspace ON relationship_checks(workspace_id, dataset_version);
+		CREATE INDEX IF NOT EXISTS idx_relationship_checks_hypothesis ON relationship_checks(hypothesis_id, checked_at DESC);
+	`)
+	return err
+}
+
+func (r *MigrationRunner) createDecisionsTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS decisions (
+			id TEXT PRIMARY KEY,
+			workspace_id TEXT NOT NULL,
+			hypothesis_id TEXT NOT NULL,
+			action TEXT NOT NULL,
+			expected TEXT NOT NULL DEFAULT '',
+			decided_by TEXT NOT NULL DEFAULT '',
+			decided_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			outcome TEXT NOT NULL DEFAULT 'pending',
+			outcome_notes TEXT NOT NULL DEFAULT '',
+			outcome_at TIMESTAMP WITH TIME ZONE,
+			gates JSONB NOT NULL DEFAULT '[]'
+		);
+		CREATE INDEX IF NOT EXISTS idx_decisions_workspace ON decisions(workspace_id, decided_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_decisions_hypothesis ON decisions(hypothesis_id);
+	`)
+	return err
+}
+
+func (r *MigrationRunner) createShareLinksTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS share_links (
+			id TEXT PRIMARY KEY,
+			token_hash TEXT NOT NULL UNIQUE,
+			workspace_id TEXT NOT NULL,
+			hypothesis_id TEXT NOT NULL,
+			created_by TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			revoked_at TIMESTAMP WITH TIME ZONE
+		);
+		CREATE INDEX IF NOT EXISTS idx_share_links_workspace ON share_links(workspace_id, created_at DESC);
+	`)
+	return err
+}
+
+func (r *MigrationRunner) createTriageTables(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS triage_marks (
+			workspace_id TEXT NOT NULL,
+			list TEXT NOT NULL,
+			item_id TEXT NOT NULL,
+			status TEXT NOT NULL,
+			marked_by TEXT NOT NULL DEFAULT '',
+			marked_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			PRIMARY KEY (workspace_id, list, item_id)
+		);
+		CREATE TABLE IF NOT EXISTS saved_views (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			list TEXT NOT NULL,
+			name TEXT NOT NULL,
+			filters JSONB NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_saved_views_user ON saved_views(user_id, list, created_at);
+	`)
+	return err
+}
+
+func (r *MigrationRunner) createSQLDataSourcesTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sql_data_sources (
+			id TEXT PRIMARY KEY,
+			workspace_id TEXT NOT NULL,
+			user_id TEXT NOT NULL DEFAULT '',
+			name TEXT NOT NULL,
+			driver TEXT NOT NULL DEFAULT 'postgres',
+			dsn TEXT NOT NULL,
+			query TEXT NOT NULL DEFAULT '',
+			table_name TEXT NOT NULL DEFAULT '',
+			max_rows INTEGER NOT NULL DEFAULT 100000,
+			refresh_minutes INTEGER NOT NULL DEFAULT 0,
+			last_refreshed_at TIMESTAMP WITH TIME ZONE,
+			last_dataset_id TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sql_data_sources_workspace ON sql_data_sources(workspace_id, created_at);
+	`)
+	return err
+}
+
+func (r *MigrationRunner) createURLDataSourcesTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS url_data_sources (
+			id TEXT PRIMARY KEY,
+			workspace_id TEXT NOT NULL,
+			user_id TEXT NOT NULL DEFAULT '',
+			name TEXT NOT NULL,
+			url TEXT NOT NULL,
+			kind TEXT NOT NULL DEFAULT 'url',
+			token TEXT NOT NULL DEFAULT '',
+			refresh_minutes INTEGER NOT NULL DEFAULT 0,
+			last_refreshed_at TIMESTAMP WITH TIME ZONE,
+			last_dataset_id TEXT NOT NULL DEFAULT '',
+			last_checksum TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_url_data_sources_workspace ON url_data_sources(workspace_id, created_at);
+	`)
+	return err
+}
+
+func (r *MigrationRunner) createRefereeRunsTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS referee_runs (
+			id TEXT PRIMARY KEY,
+			session_id TEXT NOT NULL,
+			hypothesis_id TEXT NOT NULL,
+			referee TEXT NOT NULL,
+			sample_size INTEGER NOT NULL DEFAULT 0,
+			status TEXT NOT NULL DEFAULT 'running',
+			passed BOOLEAN,
+			started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			finished_at TIMESTAMP WITH TIME ZONE,
+			duration_ms BIGINT NOT NULL DEFAULT 0
+		);
+		CREATE INDEX IF NOT EXISTS idx_referee_runs_session ON referee_runs(session_id, started_at);
+		CREATE INDEX IF NOT EXISTS idx_referee_runs_referee ON referee_runs(referee, finished_at DESC);
+	`)
+	return err
+}
+
+// createArtifactArchivesTable indexes sessions whose artifacts were moved to cold storage;
+// hypothesis rows point at the archive holding their payloads while it is cold
+func (r *MigrationRunner) createArtifactArchivesTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS artifact_archives (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			session_id UUID NOT NULL UNIQUE REFERENCES research_sessions(id) ON DELETE CASCADE,
+			workspace_id UUID,
+			blob_key TEXT NOT NULL,
+			artifact_count INTEGER NOT NULL DEFAULT 0,
+			raw_bytes BIGINT NOT NULL DEFAULT 0,
+			compressed_bytes BIGINT NOT NULL DEFAULT 0,
+			archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			rehydrated_at TIMESTAMP WITH TIME ZONE
+		);
+		CREATE INDEX IF NOT EXISTS idx_artifact_archives_workspace ON artifact_archives(workspace_id);
+		ALTER TABLE hypothesis_results ADD COLUMN IF NOT EXISTS archive_id UUID;
+	`)
+	return err
+}
+
+// createRunSummaryTables holds the aggregates materialized after each sweep run: the run's
+// totals, its relationships counted by test type, and its strongest relationships
+func (r *MigrationRunner) createRunSummaryTables(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS run_summaries (
+			run_id TEXT PRIMARY KEY,
+			session_id TEXT,
+			workspace_id TEXT,
+			snapshot_id TEXT NOT NULL DEFAULT '',
+			fdr_method TEXT NOT NULL DEFAULT '',
+			variables_analyzed INTEGER NOT NULL DEFAULT 0,
+			relationships INTEGER NOT NULL DEFAULT 0,
+			significant_pairs INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_run_summaries_session ON run_summaries(session_id, created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_run_summaries_workspace ON run_summaries(workspace_id, created_at DESC);
+		ALTER TABLE run_summaries ADD COLUMN IF NOT EXISTS dataset_id TEXT;
+		CREATE INDEX IF NOT EXISTS idx_run_summaries_dataset ON run_summaries(dataset_id, created_at DESC);
+
+		CREATE TABLE IF NOT EXISTS run_summary_test_types (
+			run_id TEXT NOT NULL REFERENCES run_summaries(run_id) ON DELETE CASCADE,
+			test_type TEXT NOT NULL,
+			relationships INTEGER NOT NULL DEFAULT 0,
+			significant INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (run_id, test_type)
+		);
+
+		CREATE TABLE IF NOT EXISTS run_summary_top_effects (
+			run_id TEXT NOT NULL REFERENCES run_summaries(run_id) ON DELETE CASCADE,
+			rank INTEGER NOT NULL,
+			variable_x TEXT NOT NULL,
+			variable_y TEXT NOT NULL,
+			test_type TEXT NOT NULL,
+			effect_size DOUBLE PRECISION NOT NULL,
+			p_value DOUBLE PRECISION NOT NULL,
+			q_value DOUBLE PRECISION NOT NULL,
+			sample_size INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (run_id, rank)
+		);
+		ALTER TABLE run_summary_top_effects ADD COLUMN IF NOT EXISTS noise_percentile DOUBLE PRECISION;
+	`)
+	return err
+}
+
 // runDatasetMigrations runs the newer dataset and workspace migrations
 func (r *MigrationRunner) runDatasetMigrations(ctx context.Context, db *sqlx.DB) error {
 	migrations := []string{
@@ -610,4 +972,119 @@ WHERE explanation_markdown IS NULL;
 	return nil
 }
 
+// createJobsTable holds the durable background job queue. Workers claim due queued jobs
+// with FOR UPDATE SKIP LOCKED, so the partial index only covers the rows they scan.
+func (r *MigrationRunner) createJobsTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS jobs (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			kind TEXT NOT NULL,
+			payload JSONB NOT NULL DEFAULT '{}',
+			status TEXT NOT NULL DEFAULT 'queued',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			max_attempts INTEGER NOT NULL DEFAULT 3,
+			run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			last_error TEXT,
+			locked_by TEXT,
+			locked_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_jobs_due ON jobs(kind, run_at) WHERE status = 'queued';
+		CREATE INDEX IF NOT EXISTS idx_jobs_kind_created ON jobs(kind, created_at DESC);
+		ALTER TABLE jobs ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMP WITH TIME ZONE;
+		CREATE INDEX IF NOT EXISTS idx_jobs_lease ON jobs(lease_expires_at) WHERE status = 'running';
+	`)
+	return err
+}
+
+// createRunLogsTable holds the structured log lines of research runs, written in batches as
+// they are logged
+func (r *MigrationRunner) createRunLogsTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS run_logs (
+			id BIGSERIAL PRIMARY KEY,
+			run_id TEXT NOT NULL,
+			seq BIGINT NOT NULL,
+			logged_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			level TEXT NOT NULL,
+			severity INTEGER NOT NULL,
+			message TEXT NOT NULL,
+			attrs JSONB
+		);
+		CREATE INDEX IF NOT EXISTS idx_run_logs_run ON run_logs(run_id, logged_at, id);
+	`)
+	return err
+}
+
+// createLeasesTable holds the expiring named locks server instances share, such as the one
+// letting a single worker process a research session at a time
+func (r *MigrationRunner) createLeasesTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS leases (
+			key TEXT PRIMARY KEY,
+			holder TEXT NOT NULL,
+			acquired_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+		)
+	`)
+	return err
+}
 
+// createWebhookTables holds workspaces' webhook endpoints and the log of deliveries to them.
+// Dispatchers claim due pending deliveries with FOR UPDATE SKIP LOCKED, so the partial index
+// only covers the rows they scan.
+func (r *MigrationRunner) createWebhookTables(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS webhooks (
+			id TEXT PRIMARY KEY,
+			workspace_id TEXT NOT NULL,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL,
+			events TEXT[] NOT NULL,
+			active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_by TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_webhooks_workspace ON webhooks(workspace_id, created_at DESC);
+
+		CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id TEXT PRIMARY KEY,
+			webhook_id TEXT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+			workspace_id TEXT NOT NULL,
+			event TEXT NOT NULL,
+			payload JSONB NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			response_status INTEGER,
+			last_error TEXT,
+			next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			delivered_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+		CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_workspace ON webhook_deliveries(workspace_id, created_at DESC);
+	`)
+	return err
+}
+
+// createNotificationChannelsTable holds the Slack and Teams channels workspaces announce
+// validated hypotheses in
+func (r *MigrationRunner) createNotificationChannelsTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS notification_channels (
+			id TEXT PRIMARY KEY,
+			workspace_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			name TEXT NOT NULL,
+			webhook_url TEXT NOT NULL,
+			min_rigor TEXT NOT NULL DEFAULT 'decision',
+			active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_by TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_notification_channels_workspace ON notification_channels(workspace_id, created_at);
+	`)
+	return err
+}
@@ -0,0 +1,153 @@
+package migration
+
+import (
+	"context"
+
+	"gohypo/internal/errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// sqliteBaseline is the schema a SQLite database starts from: the tables behind the
+// dataset, workspace, hypothesis and ledger repositories. JSON is stored as TEXT, and times
+// are TIMESTAMP columns so the driver reads them back as times.
+var sqliteBaseline = []string{
+	`CREATE TABLE IF NOT EXISTS users (
+		id TEXT PRIMARY KEY,
+		email TEXT UNIQUE NOT NULL,
+		username TEXT UNIQUE,
+		is_active BOOLEAN DEFAULT TRUE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`INSERT INTO users (id, email, username, is_active)
+	VALUES ('550e8400-e29b-41d4-a716-446655440000', 'default@grohypo.local', 'default', TRUE)
+	ON CONFLICT (email) DO NOTHING`,
+
+	`CREATE TABLE IF NOT EXISTS workspaces (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		name TEXT NOT NULL,
+		description TEXT,
+		color TEXT DEFAULT '#3B82F6',
+		is_default BOOLEAN DEFAULT FALSE,
+		metadata TEXT DEFAULT '{}',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_workspaces_user_default ON workspaces(user_id, is_default)`,
+
+	`CREATE TABLE IF NOT EXISTS datasets (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		workspace_id TEXT REFERENCES workspaces(id) ON DELETE CASCADE,
+		original_filename TEXT NOT NULL,
+		file_path TEXT,
+		file_size INTEGER,
+		mime_type TEXT,
+		display_name TEXT,
+		domain TEXT,
+		description TEXT,
+		record_count INTEGER,
+		field_count INTEGER,
+		missing_rate REAL DEFAULT 0.0,
+		source TEXT DEFAULT 'upload',
+		status TEXT DEFAULT 'pending',
+		error_message TEXT,
+		metadata TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_datasets_user_created ON datasets(user_id, created_at DESC)`,
+	`CREATE INDEX IF NOT EXISTS idx_datasets_workspace_created ON datasets(workspace_id, created_at DESC)`,
+	`CREATE INDEX IF NOT EXISTS idx_datasets_status ON datasets(status)`,
+	`CREATE INDEX IF NOT EXISTS idx_datasets_domain ON datasets(domain)`,
+
+	`CREATE TABLE IF NOT EXISTS workspace_dataset_relations (
+		id TEXT PRIMARY KEY,
+		workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+		source_dataset_id TEXT NOT NULL REFERENCES datasets(id) ON DELETE CASCADE,
+		target_dataset_id TEXT NOT NULL REFERENCES datasets(id) ON DELETE CASCADE,
+		relation_type TEXT NOT NULL,
+		confidence REAL DEFAULT 1.0,
+		metadata TEXT,
+		discovered_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		CONSTRAINT no_self_reference CHECK (source_dataset_id != target_dataset_id),
+		UNIQUE(workspace_id, source_dataset_id, target_dataset_id, relation_type)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_relations_workspace_id ON workspace_dataset_relations(workspace_id)`,
+
+	// Research sessions are not stored in SQLite, so session_id references nothing
+	`CREATE TABLE IF NOT EXISTS hypothesis_results (
+		id TEXT PRIMARY KEY,
+		session_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		workspace_id TEXT,
+		business_hypothesis TEXT NOT NULL,
+		science_hypothesis TEXT NOT NULL,
+		null_case TEXT,
+		explanation_markdown TEXT,
+		referee_results TEXT,
+		passed BOOLEAN NOT NULL,
+		validation_timestamp TIMESTAMP,
+		standards_version TEXT,
+		execution_metadata TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		phase_e_values TEXT DEFAULT '[]',
+		feasibility_score REAL,
+		risk_level TEXT,
+		data_topology TEXT DEFAULT '{}',
+		current_e_value REAL DEFAULT 0.0,
+		normalized_e_value REAL,
+		confidence REAL,
+		status TEXT DEFAULT 'pending',
+		evidence_sid INTEGER,
+		hypothesis_sid INTEGER
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_hypotheses_user_created ON hypothesis_results(user_id, created_at DESC)`,
+	`CREATE INDEX IF NOT EXISTS idx_hypotheses_user_session ON hypothesis_results(user_id, session_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_hypotheses_workspace_id ON hypothesis_results(workspace_id)`,
+
+	// The artifact ledger; PostgreSQL deployments keep it in memory. An artifact stored
+	// under several runs has one row and one link per run, in the order they were stored.
+	`CREATE TABLE IF NOT EXISTS ledger_artifacts (
+		id TEXT PRIMARY KEY,
+		kind TEXT NOT NULL,
+		body TEXT NOT NULL,
+		stored_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_ledger_artifacts_kind ON ledger_artifacts(kind)`,
+	`CREATE TABLE IF NOT EXISTS ledger_run_artifacts (
+		seq INTEGER PRIMARY KEY AUTOINCREMENT,
+		run_id TEXT NOT NULL,
+		artifact_id TEXT NOT NULL REFERENCES ledger_artifacts(id) ON DELETE CASCADE
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_ledger_run_artifacts_run ON ledger_run_artifacts(run_id, seq)`,
+	`CREATE INDEX IF NOT EXISTS idx_ledger_run_artifacts_artifact ON ledger_run_artifacts(artifact_id)`,
+}
+
+// sqliteBaselineTables are the SQLite baseline's tables, dependents first
+var sqliteBaselineTables = []string{
+	"ledger_run_artifacts", "ledger_artifacts", "hypothesis_results",
+	"workspace_dataset_relations", "datasets", "workspaces", "users",
+}
+
+// applySQLiteBaseline creates the SQLite schema
+func (r *MigrationRunner) applySQLiteBaseline(ctx context.Context, db *sqlx.DB) error {
+	for _, statement := range sqliteBaseline {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return errors.Wrap(err, "failed to create SQLite schema")
+		}
+	}
+	return nil
+}
+
+// dropSQLiteBaseline drops every table the SQLite baseline creates, and with them all data
+func (r *MigrationRunner) dropSQLiteBaseline(ctx context.Context, db *sqlx.DB) error {
+	for _, table := range sqliteBaselineTables {
+		if _, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS `+table); err != nil {
+			return errors.Wrapf(err, "failed to drop %s", table)
+		}
+	}
+	return nil
+}
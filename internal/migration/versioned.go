@@ -0,0 +1,400 @@
+package migration
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"gohypo/internal/errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// historyTable records which migrations ran. The legacy file-based migrator owns
+// schema_migrations, so versioned migrations keep their own table.
+const historyTable = "schema_history"
+
+// advisoryLockID serializes migrations between server replicas and the CLI
+const advisoryLockID int64 = 0x676f6879706f // "gohypo"
+
+// baselineVersion is the schema created by the original startup migrations
+const baselineVersion int64 = 1
+
+// ErrDirty is returned when a migration failed part-way and the schema needs an operator
+var ErrDirty = stderrors.New("database schema is dirty")
+
+// Migration is one versioned schema change. Down undoes Up; a nil Down makes the
+// migration irreversible.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(ctx context.Context, db *sqlx.DB) error
+	Down    func(ctx context.Context, db *sqlx.DB) error
+}
+
+// MigrationStatus is a migration and whether the database has it
+type MigrationStatus struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	Dirty     bool       `json:"dirty"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Migrations lists every versioned migration in ascending order. New schema changes are
+// appended here with the next version; existing entries never change once released.
+func (r *MigrationRunner) Migrations() []Migration {
+	baselineUp, baselineDown := r.applyBaseline, r.dropBaseline
+	if r.sqlite {
+		baselineUp, baselineDown = r.applySQLiteBaseline, r.dropSQLiteBaseline
+	}
+	return []Migration{
+		{Version: baselineVersion, Name: "baseline", Up: baselineUp, Down: baselineDown},
+		{Version: 2, Name: "resolution_query_approvals", Up: createQueryApprovalsTable, Down: dropQueryApprovalsTable},
+	}
+}
+
+// createQueryApprovalsTable holds reviewers' approvals of compiled push-down queries, which
+// the warehouse resolver checks before running a query when approval is required
+func createQueryApprovalsTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS resolution_query_approvals (
+			query_hash TEXT PRIMARY KEY,
+			approved_by TEXT NOT NULL,
+			approved_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+func dropQueryApprovalsTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS resolution_query_approvals`)
+	return err
+}
+
+// Up applies every pending migration in order, recording each in the history table. A
+// migration is marked dirty before it runs and clean after, so one that fails part-way
+// stops later runs until an operator repairs the schema and forces the version.
+func (r *MigrationRunner) Up(ctx context.Context, db *sqlx.DB) error {
+	return r.locked(ctx, db, func() error {
+		if err := r.adoptLegacySchema(ctx, db); err != nil {
+			return err
+		}
+		pending, err := r.pending(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, m := range pending {
+			if err := r.apply(ctx, db, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Down reverts the latest steps applied migrations, newest first
+func (r *MigrationRunner) Down(ctx context.Context, db *sqlx.DB, steps int) error {
+	if steps <= 0 {
+		return errors.InvalidInput("steps must be positive")
+	}
+	return r.locked(ctx, db, func() error {
+		if err := r.adoptLegacySchema(ctx, db); err != nil {
+			return err
+		}
+		applied, err := r.applied(ctx, db)
+		if err != nil {
+			return err
+		}
+		if err := checkClean(applied); err != nil {
+			return err
+		}
+		migrations := r.Migrations()
+		for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+			m := migrations[i]
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+			if m.Down == nil {
+				return errors.InvalidInput(fmt.Sprintf("migration %d (%s) cannot be reverted", m.Version, m.Name))
+			}
+			if err := r.revert(ctx, db, m); err != nil {
+				return err
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// Reset reverts every migration and applies them again, leaving an empty schema. It
+// destroys all data and is meant for development databases only.
+func (r *MigrationRunner) Reset(ctx context.Context, db *sqlx.DB) error {
+	if err := r.Down(ctx, db, len(r.Migrations())); err != nil {
+		return err
+	}
+	return r.Up(ctx, db)
+}
+
+// Status reports every known migration against the history table
+func (r *MigrationRunner) Status(ctx context.Context, db *sqlx.DB) ([]MigrationStatus, error) {
+	if err := r.ensureHistory(ctx, db); err != nil {
+		return nil, err
+	}
+	applied, err := r.applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []MigrationStatus
+	for _, m := range r.Migrations() {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if row, ok := applied[m.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.Applied, status.Dirty, status.AppliedAt = true, row.Dirty, &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending lists the migrations Up would apply; it fails when the schema is dirty
+func (r *MigrationRunner) Pending(ctx context.Context, db *sqlx.DB) ([]Migration, error) {
+	if err := r.ensureHistory(ctx, db); err != nil {
+		return nil, err
+	}
+	if legacy, err := r.hasLegacySchema(ctx, db); err != nil {
+		return nil, err
+	} else if legacy {
+		// Up adopts the baseline without running it
+		return r.pendingAfter(ctx, db, baselineVersion)
+	}
+	return r.pending(ctx, db)
+}
+
+// Force records the schema as being exactly at version, clearing any dirty flag, without
+// running migrations. Operators use it after repairing a failed migration by hand; 0
+// records an empty schema.
+func (r *MigrationRunner) Force(ctx context.Context, db *sqlx.DB, version int64) error {
+	known := version == 0
+	for _, m := range r.Migrations() {
+		known = known || m.Version == version
+	}
+	if !known {
+		return errors.InvalidInput(fmt.Sprintf("unknown migration version %d", version))
+	}
+	return r.locked(ctx, db, func() error {
+		if _, err := db.ExecContext(ctx, db.Rebind(`DELETE FROM `+historyTable+` WHERE version > ? OR dirty`), version); err != nil {
+			return errors.Wrap(err, "failed to clear migration history")
+		}
+		for _, m := range r.Migrations() {
+			if m.Version > version {
+				break
+			}
+			if err := r.record(ctx, db, m, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// apply runs one migration between marking it dirty and clean
+func (r *MigrationRunner) apply(ctx context.Context, db *sqlx.DB, m Migration) error {
+	if err := r.record(ctx, db, m, true); err != nil {
+		return err
+	}
+	if err := m.Up(ctx, db); err != nil {
+		return errors.Wrapf(err, "migration %d (%s) failed and left the schema dirty", m.Version, m.Name)
+	}
+	return r.record(ctx, db, m, false)
+}
+
+// revert runs one migration's Down, leaving it dirty if Down fails
+func (r *MigrationRunner) revert(ctx context.Context, db *sqlx.DB, m Migration) error {
+	if err := r.record(ctx, db, m, true); err != nil {
+		return err
+	}
+	if err := m.Down(ctx, db); err != nil {
+		return errors.Wrapf(err, "reverting migration %d (%s) failed and left the schema dirty", m.Version, m.Name)
+	}
+	if _, err := db.ExecContext(ctx, db.Rebind(`DELETE FROM `+historyTable+` WHERE version = ?`), m.Version); err != nil {
+		return errors.Wrap(err, "failed to update migration history")
+	}
+	return nil
+}
+
+// record upserts a migration's history row
+func (r *MigrationRunner) record(ctx context.Context, db *sqlx.DB, m Migration, dirty bool) error {
+	_, err := db.ExecContext(ctx, db.Rebind(`
+INSERT INTO `+historyTable+` (version, name, dirty, applied_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (version) DO UPDATE SET name = EXCLUDED.name, dirty = EXCLUDED.dirty, applied_at = EXCLUDED.applied_at`),
+		m.Version, m.Name, dirty)
+	if err != nil {
+		return errors.Wrapf(err, "failed to record migration %d", m.Version)
+	}
+	return nil
+}
+
+// historyRow is one row of the history table
+type historyRow struct {
+	Version   int64     `db:"version"`
+	Dirty     bool      `db:"dirty"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// applied reads the history table by version
+func (r *MigrationRunner) applied(ctx context.Context, db *sqlx.DB) (map[int64]historyRow, error) {
+	var rows []historyRow
+	if err := db.SelectContext(ctx, &rows, `SELECT version, dirty, applied_at FROM `+historyTable); err != nil {
+		return nil, errors.Wrap(err, "failed to read migration history")
+	}
+	applied := make(map[int64]historyRow, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// pending lists the migrations not yet applied, refusing a dirty schema
+func (r *MigrationRunner) pending(ctx context.Context, db *sqlx.DB) ([]Migration, error) {
+	return r.pendingAfter(ctx, db, 0)
+}
+
+// pendingAfter lists unapplied migrations above version, refusing a dirty schema
+func (r *MigrationRunner) pendingAfter(ctx context.Context, db *sqlx.DB, version int64) ([]Migration, error) {
+	applied, err := r.applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkClean(applied); err != nil {
+		return nil, err
+	}
+	return pendingMigrations(r.Migrations(), applied, version)
+}
+
+// checkClean refuses a history with a migration that did not finish
+func checkClean(applied map[int64]historyRow) error {
+	for v, row := range applied {
+		if row.Dirty {
+			return fmt.Errorf("%w: migration %d did not finish; repair the schema, then run 'gohypo-cli migrate force <version>'", ErrDirty, v)
+		}
+	}
+	return nil
+}
+
+// pendingMigrations picks the unapplied migrations above version. A gap, an unapplied
+// migration older than one already applied, means the history does not match this build.
+func pendingMigrations(migrations []Migration, applied map[int64]historyRow, version int64) ([]Migration, error) {
+	var pending []Migration
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+		if _, ok := applied[m.Version]; ok {
+			if len(pending) > 0 {
+				return nil, errors.Conflict(fmt.Sprintf("migration %d is applied but %d before it is not", m.Version, pending[0].Version))
+			}
+			continue
+		}
+		pending = append(pending, m)
+	}
+	return pending, nil
+}
+
+// ensureHistory creates the history table
+func (r *MigrationRunner) ensureHistory(ctx context.Context, db *sqlx.DB) error {
+	// SQLite only reads back TIMESTAMP columns as times
+	appliedAt := "TIMESTAMPTZ"
+	if r.sqlite {
+		appliedAt = "TIMESTAMP"
+	}
+	_, err := db.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS `+historyTable+` (
+    version BIGINT PRIMARY KEY,
+    name TEXT NOT NULL,
+    dirty BOOLEAN NOT NULL DEFAULT FALSE,
+    applied_at `+appliedAt+` NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`)
+	if err != nil {
+		return errors.Wrap(err, "failed to create migration history table")
+	}
+	return nil
+}
+
+// hasLegacySchema reports a database built by the unversioned startup migrations: it has
+// the baseline's tables but no history. SQLite databases never had those.
+func (r *MigrationRunner) hasLegacySchema(ctx context.Context, db *sqlx.DB) (bool, error) {
+	if r.sqlite {
+		return false, nil
+	}
+	var state struct {
+		Users   bool `db:"users"`
+		History int  `db:"history"`
+	}
+	err := db.GetContext(ctx, &state, `SELECT to_regclass('public.users') IS NOT NULL AS users, (SELECT COUNT(*) FROM `+historyTable+`) AS history`)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to inspect schema")
+	}
+	return state.Users && state.History == 0, nil
+}
+
+// adoptLegacySchema records the baseline as applied on a database that already has it
+func (r *MigrationRunner) adoptLegacySchema(ctx context.Context, db *sqlx.DB) error {
+	legacy, err := r.hasLegacySchema(ctx, db)
+	if err != nil || !legacy {
+		return err
+	}
+	return r.record(ctx, db, r.Migrations()[0], false)
+}
+
+// locked runs fn holding the migration advisory lock, after creating the history table
+func (r *MigrationRunner) locked(ctx context.Context, db *sqlx.DB, fn func() error) error {
+	if r.sqlite {
+		// SQLite serializes writers itself, and its pool's one connection cannot be
+		// reserved for a lock without starving fn
+		if err := r.ensureHistory(ctx, db); err != nil {
+			return err
+		}
+		return fn()
+	}
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to reserve a connection for the migration lock")
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockID); err != nil {
+		return errors.Wrap(err, "failed to acquire the migration lock")
+	}
+	defer func() {
+		// Use a fresh context so a cancelled run still releases the lock
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockID)
+	}()
+
+	if err := r.ensureHistory(ctx, db); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// baselineTables are the baseline's tables, dependents first
+var baselineTables = []string{
+	"notification_channels", "webhook_deliveries", "webhooks", "leases", "run_logs", "jobs",
+	"run_summary_top_effects", "run_summary_test_types", "run_summaries", "artifact_archives",
+	"referee_runs", "url_data_sources", "sql_data_sources", "saved_views", "triage_marks",
+	"share_links", "decisions", "relationship_checks", "workspace_llm_settings",
+	"few_shot_exemplars", "llm_response_cache", "workspace_dataset_relations", "datasets",
+	"workspaces", "hypothesis_results", "research_prompts", "research_sessions", "users",
+}
+
+// dropBaseline drops every table the baseline creates, and with them all data. Workspace
+// schemas (ws_<id>) are left for the operator.
+func (r *MigrationRunner) dropBaseline(ctx context.Context, db *sqlx.DB) error {
+	for _, table := range baselineTables {
+		if _, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS `+table+` CASCADE`); err != nil {
+			return errors.Wrapf(err, "failed to drop %s", table)
+		}
+	}
+	return nil
+}
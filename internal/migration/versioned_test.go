@@ -0,0 +1,56 @@
+package migration
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestPendingMigrationsFollowHistory checks pending selection against recorded history
+func TestPendingMigrationsFollowHistory(t *testing.T) {
+	migrations := []Migration{{Version: 1, Name: "baseline"}, {Version: 2, Name: "second"}, {Version: 3, Name: "third"}}
+
+	pending, err := pendingMigrations(migrations, map[int64]historyRow{1: {Version: 1}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 || pending[0].Version != 2 || pending[1].Version != 3 {
+		t.Fatalf("expected migrations 2 and 3 to be pending, got %+v", pending)
+	}
+
+	// An adopted legacy schema skips the baseline even before it is recorded
+	pending, err = pendingMigrations(migrations, map[int64]historyRow{}, 1)
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("expected the baseline to be skipped, got %+v (%v)", pending, err)
+	}
+
+	if _, err := pendingMigrations(migrations, map[int64]historyRow{1: {Version: 1}, 3: {Version: 3}}, 0); err == nil {
+		t.Error("expected a gap in the history to be refused")
+	}
+}
+
+// TestCheckCleanRefusesDirtyHistory checks that an unfinished migration blocks further runs
+func TestCheckCleanRefusesDirtyHistory(t *testing.T) {
+	if err := checkClean(map[int64]historyRow{1: {Version: 1}}); err != nil {
+		t.Fatalf("expected a clean history to pass, got %v", err)
+	}
+	err := checkClean(map[int64]historyRow{1: {Version: 1}, 2: {Version: 2, Dirty: true}})
+	if !errors.Is(err, ErrDirty) {
+		t.Fatalf("expected ErrDirty, got %v", err)
+	}
+}
+
+// TestMigrationsAreOrdered guards the registry against reordered or reused versions
+func TestMigrationsAreOrdered(t *testing.T) {
+	migrations := NewRunner().Migrations()
+	if migrations[0].Version != baselineVersion {
+		t.Fatalf("expected the baseline first, got %d", migrations[0].Version)
+	}
+	for i, m := range migrations {
+		if m.Up == nil || m.Name == "" {
+			t.Errorf("migration %d is incomplete", m.Version)
+		}
+		if i > 0 && m.Version <= migrations[i-1].Version {
+			t.Errorf("migration %d does not follow %d", m.Version, migrations[i-1].Version)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+// Package monitoring re-estimates validated relationships on each new dataset version and
+// alerts when one degrades below the configured effect floor.
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// DefaultEffectFloor is the correlation, in the validated direction, below which a tracked
+// relationship counts as degraded
+const DefaultEffectFloor = 0.1
+
+// maxTrackedHypotheses bounds how many of a workspace's hypotheses are considered
+const maxTrackedHypotheses = 1000
+
+// MatrixSource opens a resolver over a dataset version's data; cleanup releases any
+// temporary file behind it
+type MatrixSource func(ctx context.Context, ds *dataset.Dataset) (resolver ports.MatrixResolverPort, cleanup func(), err error)
+
+// Monitor tracks validated relationships across dataset versions
+type Monitor struct {
+	hypotheses ports.HypothesisRepository
+	datasets   ports.DatasetRepository
+	checks     ports.RelationshipCheckStore
+	openMatrix MatrixSource
+
+	// Floor is the minimum directional effect of a healthy relationship
+	Floor float64
+	// OnAlert is called for each relationship that has just degraded
+	OnAlert func(check *models.RelationshipCheck)
+}
+
+// NewMonitor creates a monitor with the default effect floor
+func NewMonitor(hypotheses ports.HypothesisRepository, datasets ports.DatasetRepository, checks ports.RelationshipCheckStore, openMatrix MatrixSource) *Monitor {
+	return &Monitor{
+		hypotheses: hypotheses,
+		datasets:   datasets,
+		checks:     checks,
+		openMatrix: openMatrix,
+		Floor:      DefaultEffectFloor,
+	}
+}
+
+// trackedHypothesis is a validated hypothesis with the variables and correlation it was
+// validated on
+type trackedHypothesis struct {
+	result    *models.HypothesisResult
+	cause     string
+	effect    string
+	validated float64
+}
+
+// tracked returns the workspace's validated hypotheses that recorded their variables and
+// observed correlation; older validations did not and cannot be re-estimated
+func (m *Monitor) tracked(ctx context.Context, userID uuid.UUID, workspaceID string) ([]trackedHypothesis, error) {
+	results, err := m.hypotheses.ListByWorkspace(ctx, userID, workspaceID, maxTrackedHypotheses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hypotheses for workspace %s: %w", workspaceID, err)
+	}
+	var tracked []trackedHypothesis
+	for _, result := range results {
+		if !result.Passed {
+			continue
+		}
+		cause, _ := result.ExecutionMetadata["cause_key"].(string)
+		effect, _ := result.ExecutionMetadata["effect_key"].(string)
+		validated, ok := result.ExecutionMetadata["observed_correlation"].(float64)
+		if cause == "" || effect == "" || !ok || math.IsNaN(validated) {
+			continue
+		}
+		tracked = append(tracked, trackedHypothesis{result: result, cause: cause, effect: effect, validated: validated})
+	}
+	return tracked, nil
+}
+
+// CheckDataset re-estimates every validated relationship of the dataset's workspace on this
+// version of the dataset. Only validated relationships are re-estimated; nothing is swept or
+// regenerated. Relationships already checked against this version are skipped, so calling it
+// twice for the same version is harmless.
+func (m *Monitor) CheckDataset(ctx context.Context, datasetID core.ID) ([]*models.RelationshipCheck, error) {
+	ds, err := m.datasets.GetByID(ctx, datasetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dataset %s: %w", datasetID, err)
+	}
+	if ds.Status != dataset.StatusReady {
+		return nil, fmt.Errorf("dataset %s is not ready (status %s)", datasetID, ds.Status)
+	}
+	userID, err := uuid.Parse(string(ds.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("dataset %s has an invalid user id: %w", datasetID, err)
+	}
+	workspaceID := string(ds.WorkspaceID)
+
+	tracked, err := m.tracked(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tracked) == 0 {
+		return nil, nil
+	}
+
+	var pending []trackedHypothesis
+	previous := make(map[string]*models.RelationshipCheck)
+	for _, h := range tracked {
+		latest, err := m.checks.LatestCheck(ctx, h.result.ID)
+		if err != nil {
+			return nil, err
+		}
+		if latest != nil && latest.DatasetID == string(ds.ID) && latest.DatasetVersion.Equal(ds.UpdatedAt) {
+			continue
+		}
+		previous[h.result.ID] = latest
+		pending = append(pending, h)
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	log.Printf("[Monitor] 🔭 Re-estimating %d validated relationships on dataset %s", len(pending), ds.ID)
+
+	keys := make(map[core.VariableKey]bool)
+	for _, h := range pending {
+		keys[core.VariableKey(h.cause)] = true
+		keys[core.VariableKey(h.effect)] = true
+	}
+	varKeys := make([]core.VariableKey, 0, len(keys))
+	for key := range keys {
+		varKeys = append(varKeys, key)
+	}
+	sort.Slice(varKeys, func(a, b int) bool { return varKeys[a] < varKeys[b] })
+
+	resolver, cleanup, err := m.openMatrix(ctx, ds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset %s: %w", datasetID, err)
+	}
+	defer cleanup()
+	bundle, err := resolver.ResolveMatrix(ctx, ports.MatrixResolutionRequest{
+		ViewID:  core.ID("relationship_monitoring"),
+		VarKeys: varKeys,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve dataset %s: %w", datasetID, err)
+	}
+
+	checks := make([]*models.RelationshipCheck, 0, len(pending))
+	for _, h := range pending {
+		check := &models.RelationshipCheck{
+			HypothesisID:         h.result.ID,
+			WorkspaceID:          workspaceID,
+			DatasetID:            string(ds.ID),
+			DatasetVersion:       ds.UpdatedAt,
+			Cause:                h.cause,
+			Effect:               h.effect,
+			ValidatedCorrelation: h.validated,
+			Floor:                m.Floor,
+			CheckedAt:            time.Now(),
+		}
+		estimate(bundle, check)
+		check.Evaluate()
+		if prior := previous[h.result.ID]; check.Degraded && (prior == nil || !prior.Degraded) {
+			check.Alerted = true
+		}
+		if err := m.checks.SaveCheck(ctx, check); err != nil {
+			return checks, err
+		}
+		checks = append(checks, check)
+
+		if check.Alerted {
+			log.Printf("[Monitor] 🚨 Relationship %s → %s (hypothesis %s) degraded on dataset %s: effect %.3f below floor %.3f %s",
+				h.cause, h.effect, h.result.ID, ds.ID, check.DirectionalEffect, check.Floor, check.Error)
+			if m.OnAlert != nil {
+				m.OnAlert(check)
+			}
+		}
+	}
+	return checks, nil
+}
+
+// estimate fills in the correlation of the check's variables, or the reason it has none
+func estimate(bundle *dataset.MatrixBundle, check *models.RelationshipCheck) {
+	check.Correlation, check.PValue = math.NaN(), math.NaN()
+	xs, okX := bundle.GetColumnData(core.VariableKey(check.Cause))
+	ys, okY := bundle.GetColumnData(core.VariableKey(check.Effect))
+	if !okX || !okY {
+		check.Error = fmt.Sprintf("variables %s and %s are not both in this dataset version", check.Cause, check.Effect)
+		return
+	}
+	var x, y []float64
+	for i := range xs {
+		if i < len(ys) && !math.IsNaN(xs[i]) && !math.IsNaN(ys[i]) {
+			x = append(x, xs[i])
+			y = append(y, ys[i])
+		}
+	}
+	check.SampleSize = len(x)
+	if len(x) < 3 {
+		check.Error = fmt.Sprintf("only %d complete rows", len(x))
+		return
+	}
+	r := stat.Correlation(x, y, nil)
+	if math.IsNaN(r) {
+		check.Error = "a variable is constant in this dataset version"
+		return
+	}
+	check.Correlation = r
+	check.PValue = 0
+	if math.Abs(r) < 1 {
+		df := float64(len(x) - 2)
+		t := r * math.Sqrt(df/(1-r*r))
+		check.PValue = 2 * distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}.Survival(math.Abs(t))
+	}
+}
+
+// Tracked returns the workspace's validated relationships with their trajectories
+func (m *Monitor) Tracked(ctx context.Context, userID uuid.UUID, workspaceID string) ([]models.TrackedRelationship, error) {
+	tracked, err := m.tracked(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	checks, err := m.checks.ListChecks(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	byHypothesis := make(map[string][]models.RelationshipCheck)
+	for _, check := range checks {
+		byHypothesis[check.HypothesisID] = append(byHypothesis[check.HypothesisID], *check)
+	}
+
+	relationships := make([]models.TrackedRelationship, 0, len(tracked))
+	for _, h := range tracked {
+		relationship := models.TrackedRelationship{
+			HypothesisID:         h.result.ID,
+			Description:          h.result.BusinessHypothesis,
+			Cause:                h.cause,
+			Effect:               h.effect,
+			ValidatedCorrelation: h.validated,
+			Status:               models.TrackingUnchecked,
+			Trajectory:           byHypothesis[h.result.ID],
+		}
+		if latest := relationship.Latest(); latest != nil {
+			relationship.Status = models.TrackingHealthy
+			if latest.Degraded {
+				relationship.Status = models.TrackingDegraded
+			}
+		}
+		relationships = append(relationships, relationship)
+	}
+	return relationships, nil
+}
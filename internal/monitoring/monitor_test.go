@@ -0,0 +1,129 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+)
+
+type fakeHypotheses struct {
+	ports.HypothesisRepository
+	results []*models.HypothesisResult
+}
+
+func (f *fakeHypotheses) ListByWorkspace(ctx context.Context, userID uuid.UUID, workspaceID string, limit int) ([]*models.HypothesisResult, error) {
+	return f.results, nil
+}
+
+type fakeDatasets struct {
+	ports.DatasetRepository
+	ds *dataset.Dataset
+}
+
+func (f *fakeDatasets) GetByID(ctx context.Context, id core.ID) (*dataset.Dataset, error) {
+	return f.ds, nil
+}
+
+type fakeChecks struct {
+	checks []*models.RelationshipCheck
+}
+
+func (f *fakeChecks) SaveCheck(ctx context.Context, check *models.RelationshipCheck) error {
+	f.checks = append(f.checks, check)
+	return nil
+}
+
+func (f *fakeChecks) ListChecks(ctx context.Context, workspaceID string) ([]*models.RelationshipCheck, error) {
+	return f.checks, nil
+}
+
+func (f *fakeChecks) LatestCheck(ctx context.Context, hypothesisID string) (*models.RelationshipCheck, error) {
+	var latest *models.RelationshipCheck
+	for _, check := range f.checks {
+		if check.HypothesisID == hypothesisID {
+			latest = check
+		}
+	}
+	return latest, nil
+}
+
+type fakeResolver struct {
+	ports.MatrixResolverPort
+	bundle *dataset.MatrixBundle
+}
+
+func (f *fakeResolver) ResolveMatrix(ctx context.Context, req ports.MatrixResolutionRequest) (*dataset.MatrixBundle, error) {
+	return f.bundle, nil
+}
+
+func versionBundle(slope float64) *dataset.MatrixBundle {
+	bundle := &dataset.MatrixBundle{}
+	bundle.Matrix.VariableKeys = []core.VariableKey{"spend", "sales"}
+	for i := 0; i < 40; i++ {
+		x := float64(i)
+		bundle.Matrix.EntityIDs = append(bundle.Matrix.EntityIDs, core.ID(fmt.Sprintf("e%d", i)))
+		bundle.Matrix.Data = append(bundle.Matrix.Data, []float64{x, slope*x + float64((i*7)%5)*4})
+	}
+	return bundle
+}
+
+// TestCheckDatasetAlertsOnceWhenRelationshipDegrades verifies a relationship is re-estimated
+// per dataset version, alerts on the version where it falls below the floor, does not alert
+// again while it stays degraded, and skips unvalidated hypotheses
+func TestCheckDatasetAlertsOnceWhenRelationshipDegrades(t *testing.T) {
+	ds := &dataset.Dataset{ID: "ds1", UserID: core.ID(uuid.New().String()), WorkspaceID: "ws1", Status: dataset.StatusReady, UpdatedAt: time.Now()}
+	hypotheses := &fakeHypotheses{results: []*models.HypothesisResult{
+		{ID: "h1", Passed: true, ExecutionMetadata: map[string]interface{}{"cause_key": "spend", "effect_key": "sales", "observed_correlation": 0.8}},
+		{ID: "h2", Passed: false, ExecutionMetadata: map[string]interface{}{"cause_key": "spend", "effect_key": "sales", "observed_correlation": 0.8}},
+	}}
+	checks := &fakeChecks{}
+	resolver := &fakeResolver{bundle: versionBundle(1)}
+	monitor := NewMonitor(hypotheses, &fakeDatasets{ds: ds}, checks, func(ctx context.Context, ds *dataset.Dataset) (ports.MatrixResolverPort, func(), error) {
+		return resolver, func() {}, nil
+	})
+	alerts := 0
+	monitor.OnAlert = func(*models.RelationshipCheck) { alerts++ }
+	ctx := context.Background()
+
+	first, err := monitor.CheckDataset(ctx, ds.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 1 || first[0].Degraded || first[0].DirectionalEffect < 0.5 {
+		t.Fatalf("expected one healthy check, got %+v", first)
+	}
+	if again, _ := monitor.CheckDataset(ctx, ds.ID); len(again) != 0 {
+		t.Fatalf("expected the same version to be skipped, got %d checks", len(again))
+	}
+
+	for _, slope := range []float64{-1, -1} {
+		ds.UpdatedAt = ds.UpdatedAt.Add(time.Hour)
+		resolver.bundle = versionBundle(slope)
+		checked, err := monitor.CheckDataset(ctx, ds.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(checked) != 1 || !checked[0].Degraded {
+			t.Fatalf("expected the flipped relationship to be degraded, got %+v", checked)
+		}
+	}
+	if alerts != 1 {
+		t.Fatalf("expected exactly one alert, got %d", alerts)
+	}
+
+	tracked, err := monitor.Tracked(ctx, uuid.New(), "ws1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracked) != 1 || tracked[0].Status != models.TrackingDegraded || len(tracked[0].Trajectory) != 3 {
+		t.Fatalf("expected one degraded relationship with three checks, got %+v", tracked)
+	}
+}
@@ -0,0 +1,67 @@
+// Package notify announces validated hypotheses in workspaces' Slack and Teams channels.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// Service posts validated hypotheses to the chat channels of their workspace whose rigor bar
+// they clear, as cards linking back to the hypothesis' validation page
+type Service struct {
+	channels  ports.NotificationChannelStore
+	notifiers map[models.NotificationKind]ports.Notifier
+	baseURL   string
+}
+
+// NewService creates a notification service. baseURL is where users reach the server, used for
+// the links on cards.
+func NewService(channels ports.NotificationChannelStore, notifiers map[models.NotificationKind]ports.Notifier, baseURL string) *Service {
+	return &Service{channels: channels, notifiers: notifiers, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// ValidationURL is the absolute link to a hypothesis' validation page
+func (s *Service) ValidationURL(hypothesisID string) string {
+	return s.baseURL + "/hypotheses/" + url.PathEscape(hypothesisID)
+}
+
+// NotifyValidated posts a hypothesis to each of the workspace's channels that announce it. Every
+// channel is tried; the errors of those that failed are returned together.
+func (s *Service) NotifyValidated(ctx context.Context, workspaceID string, hypothesis *models.HypothesisResult) error {
+	if workspaceID == "" || !hypothesis.Passed {
+		return nil
+	}
+	channels, err := s.channels.ListChannels(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	card := models.ValidatedHypothesisNotification(hypothesis, s.ValidationURL(hypothesis.ID))
+	var errs []error
+	for _, channel := range channels {
+		if !channel.Announces(hypothesis) {
+			continue
+		}
+		if err := s.Send(ctx, channel, card); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Send posts a card to one channel with the notifier of its chat service
+func (s *Service) Send(ctx context.Context, channel *models.NotificationChannel, notification models.Notification) error {
+	notifier, ok := s.notifiers[channel.Kind]
+	if !ok {
+		return fmt.Errorf("no notifier for %s channel %q", channel.Kind, channel.Name)
+	}
+	if err := notifier.Notify(ctx, channel.WebhookURL, notification); err != nil {
+		return fmt.Errorf("%s channel %q: %w", channel.Kind, channel.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gohypo/domain/stage"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+type memoryChannels struct {
+	ports.NotificationChannelStore
+	channels []*models.NotificationChannel
+}
+
+func (m *memoryChannels) ListChannels(_ context.Context, workspaceID string) ([]*models.NotificationChannel, error) {
+	var out []*models.NotificationChannel
+	for _, channel := range m.channels {
+		if channel.WorkspaceID == workspaceID {
+			out = append(out, channel)
+		}
+	}
+	return out, nil
+}
+
+// recordingNotifier keeps the webhook URLs it posted to, failing those in fail
+type recordingNotifier struct {
+	posted []string
+	cards  []models.Notification
+	fail   map[string]bool
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, webhookURL string, notification models.Notification) error {
+	if r.fail[webhookURL] {
+		return fmt.Errorf("channel_not_found")
+	}
+	r.posted = append(r.posted, webhookURL)
+	r.cards = append(r.cards, notification)
+	return nil
+}
+
+func newTestChannel(t *testing.T, kind models.NotificationKind, url string, rigor stage.RigorProfile) *models.NotificationChannel {
+	t.Helper()
+	channel, err := models.NewNotificationChannel("ws1", kind, "#insights", url, rigor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return channel
+}
+
+func hypothesisWithReferees(passed ...bool) *models.HypothesisResult {
+	h := &models.HypothesisResult{ID: "h1", BusinessHypothesis: "Discounts grow baskets", Passed: true, ExecutionMetadata: map[string]interface{}{}}
+	for i, p := range passed {
+		h.RefereeResults = append(h.RefereeResults, models.RefereeResult{GateName: fmt.Sprintf("gate_%d", i), Passed: p})
+	}
+	return h
+}
+
+// TestNotifyValidatedHonorsRigor verifies each channel only hears of hypotheses meeting its
+// rigor, through the notifier of its chat service
+func TestNotifyValidatedHonorsRigor(t *testing.T) {
+	slack, teams := &recordingNotifier{}, &recordingNotifier{}
+	store := &memoryChannels{channels: []*models.NotificationChannel{
+		newTestChannel(t, models.NotifySlack, "https://hooks.slack.com/decision", stage.RigorDecision),
+		newTestChannel(t, models.NotifyTeams, "https://example.webhook.office.com/standard", stage.RigorStandard),
+	}}
+	service := NewService(store, map[models.NotificationKind]ports.Notifier{models.NotifySlack: slack, models.NotifyTeams: teams}, "https://gohypo.example.com/")
+
+	if err := service.NotifyValidated(context.Background(), "ws1", hypothesisWithReferees(true, true, false)); err != nil {
+		t.Fatal(err)
+	}
+	if len(slack.posted) != 0 || len(teams.posted) != 1 {
+		t.Fatalf("expected only the standard-rigor channel notified, got slack=%v teams=%v", slack.posted, teams.posted)
+	}
+
+	if err := service.NotifyValidated(context.Background(), "ws1", hypothesisWithReferees(true, true, true)); err != nil {
+		t.Fatal(err)
+	}
+	if len(slack.posted) != 1 || len(teams.posted) != 2 {
+		t.Fatalf("expected both channels notified of a decision-rigor hypothesis, got slack=%v teams=%v", slack.posted, teams.posted)
+	}
+	if link := slack.cards[0].LinkURL; link != "https://gohypo.example.com/hypotheses/h1" {
+		t.Errorf("unexpected validation link %q", link)
+	}
+
+	if err := service.NotifyValidated(context.Background(), "ws2", hypothesisWithReferees(true, true, true)); err != nil || len(slack.posted) != 1 {
+		t.Fatalf("expected another workspace's hypothesis to reach no channel, got %v (%v)", slack.posted, err)
+	}
+}
+
+// TestNotifyValidatedReportsFailedChannels verifies a failing channel does not stop the others
+func TestNotifyValidatedReportsFailedChannels(t *testing.T) {
+	slack := &recordingNotifier{fail: map[string]bool{"https://hooks.slack.com/gone": true}}
+	store := &memoryChannels{channels: []*models.NotificationChannel{
+		newTestChannel(t, models.NotifySlack, "https://hooks.slack.com/gone", stage.RigorBasic),
+		newTestChannel(t, models.NotifySlack, "https://hooks.slack.com/ok", stage.RigorBasic),
+	}}
+	service := NewService(store, map[models.NotificationKind]ports.Notifier{models.NotifySlack: slack}, "https://gohypo.example.com")
+
+	err := service.NotifyValidated(context.Background(), "ws1", hypothesisWithReferees(true))
+	if err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Fatalf("expected the failed channel's error, got %v", err)
+	}
+	if len(slack.posted) != 1 || slack.posted[0] != "https://hooks.slack.com/ok" {
+		t.Fatalf("expected the healthy channel still notified, got %v", slack.posted)
+	}
+}
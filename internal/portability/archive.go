@@ -0,0 +1,308 @@
+// Package portability moves a workspace between deployments, for promoting work from dev to
+// prod: export writes its datasets' metadata, variable contracts, run summaries and
+// hypotheses to a portable archive, and import recreates them under new IDs. It also deletes
+// datasets in bulk, refusing while runs still reference them.
+package portability
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/internal/buildinfo"
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// Format names workspace archives, so other JSON files are refused on import
+const Format = "gohypo.workspace-archive"
+
+// FormatVersion is the archive layout version; archives from a newer layout are refused
+const FormatVersion = 1
+
+// exportLimit caps the runs and hypotheses an archive carries
+const exportLimit = 10000
+
+// Archive is a workspace as exported. Datasets carry their metadata only; their files stay
+// behind and are uploaded again on the target deployment.
+type Archive struct {
+	Format        string                      `json:"format"`
+	FormatVersion int                         `json:"format_version"`
+	ExportedAt    time.Time                   `json:"exported_at"`
+	Engine        buildinfo.Info              `json:"engine"`
+	Workspace     *dataset.Workspace          `json:"workspace"`
+	Datasets      []*dataset.Dataset          `json:"datasets"`
+	Relations     []*dataset.DatasetRelation  `json:"relations"`
+	Contracts     []*dataset.VariableContract `json:"contracts"`
+	Runs          []*models.RunSummary        `json:"runs"`
+	Hypotheses    []*models.HypothesisResult  `json:"hypotheses"`
+}
+
+// ImportResult reports what an import created
+type ImportResult struct {
+	WorkspaceID core.ID            `json:"workspace_id"`
+	Datasets    map[string]core.ID `json:"datasets"` // archived dataset ID to the new one
+	Relations   int                `json:"relations"`
+	Contracts   int                `json:"contracts"`
+	Runs        int                `json:"runs"`
+	Sessions    int                `json:"sessions"`
+	Hypotheses  int                `json:"hypotheses"`
+	// Contracts this deployment already had; they are kept, not replaced by the archived ones
+	ContractConflicts []string `json:"contract_conflicts,omitempty"`
+}
+
+// ErrUnsupportedArchive is returned for files that are not workspace archives this build reads
+var ErrUnsupportedArchive = apperrors.InvalidInput("not a workspace archive this version can import")
+
+// Service exports, imports and bulk-deletes over the workspace's stores. Contracts and Runs
+// may be nil, in which case archives carry none and deletes check no runs.
+type Service struct {
+	Workspaces ports.WorkspaceRepository
+	Datasets   ports.DatasetRepository
+	Sessions   ports.SessionRepository
+	Hypotheses ports.HypothesisRepository
+	Contracts  ports.ContractStore
+	Runs       ports.RunSummaryStore
+	Files      FileDeleter // removes deleted datasets' files (optional)
+}
+
+// FileDeleter removes a stored dataset file
+type FileDeleter interface {
+	Delete(ctx context.Context, filePath string) error
+}
+
+// Export writes a workspace to an archive
+func (s *Service) Export(ctx context.Context, workspaceID, userID core.ID) (*Archive, error) {
+	workspace, err := s.Workspaces.GetWithDatasets(ctx, workspaceID)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to load workspace")
+	}
+	archive := &Archive{
+		Format:        Format,
+		FormatVersion: FormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		Engine:        buildinfo.Get(),
+		Workspace:     workspace.Workspace,
+		Datasets:      workspace.Datasets,
+		Relations:     workspace.Relations,
+	}
+
+	if s.Contracts != nil {
+		seen := map[string]bool{}
+		for _, ds := range workspace.Datasets {
+			for _, field := range ds.Metadata.Fields {
+				if seen[field.Name] {
+					continue
+				}
+				seen[field.Name] = true
+				// Fields without a contract use the defaults on both sides
+				if contract, err := s.Contracts.GetContract(ctx, field.Name); err == nil && contract != nil {
+					archive.Contracts = append(archive.Contracts, contract)
+				}
+			}
+		}
+	}
+
+	if s.Runs != nil {
+		runs, err := s.Runs.ListWorkspaceSummaries(ctx, string(workspaceID), exportLimit)
+		if err != nil {
+			return nil, apperrors.Wrap(err, "failed to list runs")
+		}
+		for _, run := range runs {
+			full, err := s.Runs.GetRunSummary(ctx, run.RunID)
+			if err != nil {
+				return nil, apperrors.Wrap(err, "failed to load run "+run.RunID)
+			}
+			if full != nil {
+				archive.Runs = append(archive.Runs, full)
+			}
+		}
+	}
+
+	if s.Hypotheses != nil {
+		user, err := uuid.Parse(string(userID))
+		if err != nil {
+			return nil, apperrors.InvalidInput("invalid user ID")
+		}
+		if archive.Hypotheses, err = s.Hypotheses.ListByWorkspace(ctx, user, string(workspaceID), exportLimit); err != nil {
+			return nil, apperrors.Wrap(err, "failed to list hypotheses")
+		}
+	}
+	return archive, nil
+}
+
+// Import recreates an archived workspace for a user. The workspace, its datasets, relations
+// and research sessions get new IDs; runs and hypotheses keep theirs unless this deployment
+// already has them. Imported datasets have no file until one is uploaded again. Contracts are
+// shared across workspaces, so existing ones are kept and reported as conflicts. The stores
+// share no transaction, so a failed import deletes what it had created before returning.
+func (s *Service) Import(ctx context.Context, archive *Archive, userID core.ID) (result *ImportResult, err error) {
+	if archive == nil || archive.Format != Format || archive.FormatVersion < 1 || archive.FormatVersion > FormatVersion || archive.Workspace == nil {
+		return nil, ErrUnsupportedArchive
+	}
+	user, err := uuid.Parse(string(userID))
+	if err != nil {
+		return nil, apperrors.InvalidInput("invalid user ID")
+	}
+	now := time.Now()
+
+	workspace := *archive.Workspace
+	workspace.ID = core.NewID()
+	workspace.UserID = userID
+	workspace.IsDefault = false
+	workspace.CreatedAt, workspace.UpdatedAt = now, now
+	workspace.Metadata = withImportedFrom(workspace.Metadata, string(archive.Workspace.ID))
+	if err := s.Workspaces.Create(ctx, &workspace); err != nil {
+		return nil, apperrors.Wrap(err, "failed to create workspace")
+	}
+
+	// undo holds a delete for every row created so far, run newest first if the import fails
+	undo := []func(context.Context) error{func(ctx context.Context) error {
+		return s.Workspaces.Delete(ctx, workspace.ID)
+	}}
+	defer func() {
+		if err == nil {
+			return
+		}
+		cleanupCtx := context.WithoutCancel(ctx)
+		for i := len(undo) - 1; i >= 0; i-- {
+			if undoErr := undo[i](cleanupCtx); undoErr != nil {
+				log.Printf("[Portability] Failed to undo partial import of workspace %s: %v", workspace.ID, undoErr)
+			}
+		}
+	}()
+	result = &ImportResult{WorkspaceID: workspace.ID, Datasets: map[string]core.ID{}}
+
+	for _, archived := range archive.Datasets {
+		ds := *archived
+		ds.ID = core.NewID()
+		ds.UserID = userID
+		ds.WorkspaceID = workspace.ID
+		ds.FilePath = ""
+		ds.Workspace, ds.Relations = nil, nil
+		ds.CreatedAt, ds.UpdatedAt = now, now
+		if err := s.Datasets.Create(ctx, &ds); err != nil {
+			return nil, apperrors.Wrap(err, "failed to create dataset "+archived.GetDisplayName())
+		}
+		undo = append(undo, func(ctx context.Context) error { return s.Datasets.Delete(ctx, ds.ID) })
+		result.Datasets[string(archived.ID)] = ds.ID
+	}
+
+	for _, archived := range archive.Relations {
+		source, okSource := result.Datasets[string(archived.SourceDatasetID)]
+		target, okTarget := result.Datasets[string(archived.TargetDatasetID)]
+		if !okSource || !okTarget {
+			continue
+		}
+		relation := *archived
+		relation.ID = core.NewID()
+		relation.WorkspaceID = workspace.ID
+		relation.SourceDatasetID, relation.TargetDatasetID = source, target
+		if err := s.Workspaces.CreateRelation(ctx, &relation); err != nil {
+			return nil, apperrors.Wrap(err, "failed to create dataset relation")
+		}
+		undo = append(undo, func(ctx context.Context) error { return s.Workspaces.DeleteRelation(ctx, relation.ID) })
+		result.Relations++
+	}
+
+	if s.Contracts != nil {
+		for _, contract := range archive.Contracts {
+			if existing, err := s.Contracts.GetContract(ctx, string(contract.VarKey)); err == nil && existing != nil {
+				result.ContractConflicts = append(result.ContractConflicts, string(contract.VarKey))
+				continue
+			}
+			if err := s.Contracts.SaveContract(ctx, contract); err != nil {
+				return nil, apperrors.Wrap(err, "failed to save contract")
+			}
+			varKey := string(contract.VarKey)
+			undo = append(undo, func(ctx context.Context) error { return s.Contracts.DeleteContract(ctx, varKey) })
+			result.Contracts++
+		}
+	}
+
+	workspaceID := string(workspace.ID)
+	if s.Runs != nil {
+		for _, archived := range archive.Runs {
+			run := *archived
+			if existing, err := s.Runs.GetRunSummary(ctx, run.RunID); err != nil {
+				return nil, apperrors.Wrap(err, "failed to check run "+run.RunID)
+			} else if existing != nil {
+				run.RunID = string(core.NewID())
+			}
+			run.WorkspaceID = &workspaceID
+			run.SessionID = nil
+			if archived.DatasetID != nil {
+				if id, ok := result.Datasets[*archived.DatasetID]; ok {
+					datasetID := string(id)
+					run.DatasetID = &datasetID
+				} else {
+					run.DatasetID = nil
+				}
+			}
+			if err := s.Runs.SaveRunSummary(ctx, &run); err != nil {
+				return nil, apperrors.Wrap(err, "failed to save run "+run.RunID)
+			}
+			runID := run.RunID
+			undo = append(undo, func(ctx context.Context) error { return s.Runs.DeleteRunSummary(ctx, runID) })
+			result.Runs++
+		}
+	}
+
+	if s.Hypotheses != nil && s.Sessions != nil {
+		sessions := map[string]uuid.UUID{}
+		for _, archived := range archive.Hypotheses {
+			sessionID, ok := sessions[archived.SessionID]
+			if !ok {
+				session, err := s.Sessions.CreateSession(ctx, user, map[string]interface{}{
+					"workspace_id":  workspaceID,
+					"imported_from": archived.SessionID,
+				})
+				if err != nil {
+					return nil, apperrors.Wrap(err, "failed to create research session")
+				}
+				sessionID = session.ID
+				// Deleting the session also deletes its hypotheses
+				undo = append(undo, func(ctx context.Context) error { return s.Sessions.DeleteSession(ctx, user, sessionID) })
+				sessions[archived.SessionID] = sessionID
+				if err := s.Sessions.UpdateSessionState(ctx, user, sessionID, models.SessionStateComplete); err != nil {
+					return nil, apperrors.Wrap(err, "failed to close research session")
+				}
+				result.Sessions++
+			}
+
+			hypothesis := *archived
+			if existing, err := s.Hypotheses.GetHypothesis(ctx, user, hypothesis.ID); err == nil && existing != nil {
+				hypothesis.ID = string(core.NewID())
+			}
+			hypothesis.SessionID = sessionID.String()
+			hypothesis.WorkspaceID = workspaceID
+			if err := s.Hypotheses.SaveHypothesis(ctx, user, sessionID, &hypothesis); err != nil {
+				return nil, apperrors.Wrap(err, "failed to save hypothesis "+archived.ID)
+			}
+			result.Hypotheses++
+		}
+	}
+	return result, nil
+}
+
+// withImportedFrom copies metadata and records the archived ID it was imported from
+func withImportedFrom(metadata map[string]interface{}, id string) map[string]interface{} {
+	copied := make(map[string]interface{}, len(metadata)+1)
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	copied["imported_from"] = id
+	return copied
+}
+
+// String describes what an import created, for logs
+func (r *ImportResult) String() string {
+	return fmt.Sprintf("workspace %s: %d datasets, %d relations, %d contracts (%d kept), %d runs, %d hypotheses in %d sessions",
+		r.WorkspaceID, len(r.Datasets), r.Relations, r.Contracts, len(r.ContractConflicts), r.Runs, r.Hypotheses, r.Sessions)
+}
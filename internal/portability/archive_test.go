@@ -0,0 +1,460 @@
+package portability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+const testUser = core.ID("550e8400-e29b-41d4-a716-446655440000")
+
+// memoryWorkspaces keeps workspaces and relations in memory
+type memoryWorkspaces struct {
+	ports.WorkspaceRepository
+	workspaces map[core.ID]*dataset.Workspace
+	relations  []*dataset.DatasetRelation
+	datasets   *memoryDatasets
+}
+
+func (m *memoryWorkspaces) Create(ctx context.Context, workspace *dataset.Workspace) error {
+	m.workspaces[workspace.ID] = workspace
+	return nil
+}
+
+func (m *memoryWorkspaces) Delete(ctx context.Context, id core.ID) error {
+	delete(m.workspaces, id)
+	return nil
+}
+
+func (m *memoryWorkspaces) CreateRelation(ctx context.Context, relation *dataset.DatasetRelation) error {
+	m.relations = append(m.relations, relation)
+	return nil
+}
+
+func (m *memoryWorkspaces) DeleteRelation(ctx context.Context, id core.ID) error {
+	for i, relation := range m.relations {
+		if relation.ID == id {
+			m.relations = append(m.relations[:i], m.relations[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("relation not found")
+}
+
+func (m *memoryWorkspaces) GetWithDatasets(ctx context.Context, id core.ID) (*ports.WorkspaceWithDatasets, error) {
+	workspace, ok := m.workspaces[id]
+	if !ok {
+		return nil, errors.New("workspace not found")
+	}
+	result := &ports.WorkspaceWithDatasets{Workspace: workspace}
+	for _, ds := range m.datasets.rows {
+		if ds.WorkspaceID == id {
+			result.Datasets = append(result.Datasets, ds)
+		}
+	}
+	for _, relation := range m.relations {
+		if relation.WorkspaceID == id {
+			result.Relations = append(result.Relations, relation)
+		}
+	}
+	return result, nil
+}
+
+// memoryDatasets keeps datasets in memory, in creation order
+type memoryDatasets struct {
+	ports.DatasetRepository
+	rows []*dataset.Dataset
+}
+
+func (m *memoryDatasets) Create(ctx context.Context, ds *dataset.Dataset) error {
+	m.rows = append(m.rows, ds)
+	return nil
+}
+
+func (m *memoryDatasets) GetByID(ctx context.Context, id core.ID) (*dataset.Dataset, error) {
+	for _, ds := range m.rows {
+		if ds.ID == id {
+			return ds, nil
+		}
+	}
+	return nil, errors.New("dataset not found")
+}
+
+func (m *memoryDatasets) Delete(ctx context.Context, id core.ID) error {
+	for i, ds := range m.rows {
+		if ds.ID == id {
+			m.rows = append(m.rows[:i], m.rows[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("dataset not found")
+}
+
+// memorySessions creates sessions and records their states
+type memorySessions struct {
+	ports.SessionRepository
+	created []*models.ResearchSession
+	states  map[uuid.UUID]models.SessionState
+}
+
+func (m *memorySessions) CreateSession(ctx context.Context, userID uuid.UUID, metadata map[string]interface{}) (*models.ResearchSession, error) {
+	session := models.NewResearchSession(uuid.New(), userID, metadata)
+	m.created = append(m.created, session)
+	return session, nil
+}
+
+func (m *memorySessions) DeleteSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	for i, session := range m.created {
+		if session.ID == sessionID {
+			m.created = append(m.created[:i], m.created[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("session not found")
+}
+
+func (m *memorySessions) UpdateSessionState(ctx context.Context, userID, sessionID uuid.UUID, state models.SessionState) error {
+	m.states[sessionID] = state
+	return nil
+}
+
+// memoryHypotheses keeps hypotheses by ID; saves fail with failSave when it is set
+type memoryHypotheses struct {
+	ports.HypothesisRepository
+	rows     map[string]*models.HypothesisResult
+	failSave error
+}
+
+func (m *memoryHypotheses) SaveHypothesis(ctx context.Context, userID, sessionID uuid.UUID, result *models.HypothesisResult) error {
+	if m.failSave != nil {
+		return m.failSave
+	}
+	m.rows[result.ID] = result
+	return nil
+}
+
+func (m *memoryHypotheses) GetHypothesis(ctx context.Context, userID uuid.UUID, hypothesisID string) (*models.HypothesisResult, error) {
+	if row, ok := m.rows[hypothesisID]; ok {
+		return row, nil
+	}
+	return nil, errors.New("hypothesis not found")
+}
+
+func (m *memoryHypotheses) ListByWorkspace(ctx context.Context, userID uuid.UUID, workspaceID string, limit int) ([]*models.HypothesisResult, error) {
+	var rows []*models.HypothesisResult
+	for _, row := range m.rows {
+		if row.WorkspaceID == workspaceID {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// memoryContracts keeps contracts by variable key
+type memoryContracts struct {
+	rows map[string]*dataset.VariableContract
+}
+
+func (m *memoryContracts) GetContract(ctx context.Context, varKey string) (*dataset.VariableContract, error) {
+	if row, ok := m.rows[varKey]; ok {
+		return row, nil
+	}
+	return nil, errors.New("contract not found")
+}
+
+func (m *memoryContracts) SaveContract(ctx context.Context, contract *dataset.VariableContract) error {
+	m.rows[string(contract.VarKey)] = contract
+	return nil
+}
+
+func (m *memoryContracts) DeleteContract(ctx context.Context, varKey string) error {
+	delete(m.rows, varKey)
+	return nil
+}
+
+// memoryRuns keeps run summaries by run ID
+type memoryRuns struct {
+	ports.RunSummaryStore
+	rows map[string]*models.RunSummary
+}
+
+func (m *memoryRuns) SaveRunSummary(ctx context.Context, summary *models.RunSummary) error {
+	m.rows[summary.RunID] = summary
+	return nil
+}
+
+func (m *memoryRuns) DeleteRunSummary(ctx context.Context, runID string) error {
+	delete(m.rows, runID)
+	return nil
+}
+
+func (m *memoryRuns) GetRunSummary(ctx context.Context, runID string) (*models.RunSummary, error) {
+	return m.rows[runID], nil
+}
+
+func (m *memoryRuns) ListWorkspaceSummaries(ctx context.Context, workspaceID string, limit int) ([]*models.RunSummary, error) {
+	var rows []*models.RunSummary
+	for _, row := range m.rows {
+		if row.WorkspaceID != nil && *row.WorkspaceID == workspaceID {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (m *memoryRuns) ListDatasetRuns(ctx context.Context, datasetID string, limit int) ([]*models.RunSummary, error) {
+	var rows []*models.RunSummary
+	for _, row := range m.rows {
+		if row.DatasetID != nil && *row.DatasetID == datasetID {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func newMemoryService() *Service {
+	datasets := &memoryDatasets{}
+	return &Service{
+		Workspaces: &memoryWorkspaces{workspaces: map[core.ID]*dataset.Workspace{}, datasets: datasets},
+		Datasets:   datasets,
+		Sessions:   &memorySessions{states: map[uuid.UUID]models.SessionState{}},
+		Hypotheses: &memoryHypotheses{rows: map[string]*models.HypothesisResult{}},
+		Contracts:  &memoryContracts{rows: map[string]*dataset.VariableContract{}},
+		Runs:       &memoryRuns{rows: map[string]*models.RunSummary{}},
+	}
+}
+
+// seedWorkspace adds a workspace with two related datasets, a contract, a run over the
+// first dataset and a hypothesis
+func seedWorkspace(t *testing.T, s *Service) (core.ID, []core.ID) {
+	t.Helper()
+	ctx := context.Background()
+	workspace := &dataset.Workspace{ID: core.NewID(), UserID: testUser, Name: "Retail", IsDefault: true}
+	s.Workspaces.Create(ctx, workspace)
+
+	var ids []core.ID
+	for _, name := range []string{"orders.csv", "customers.csv"} {
+		ds := dataset.NewDataset(testUser, name)
+		ds.WorkspaceID = workspace.ID
+		ds.FilePath = "uploads/" + name
+		ds.Metadata.Fields = []dataset.FieldInfo{{Name: "revenue"}, {Name: "region"}}
+		s.Datasets.Create(ctx, ds)
+		ids = append(ids, ds.ID)
+	}
+	s.Workspaces.CreateRelation(ctx, &dataset.DatasetRelation{ID: core.NewID(), WorkspaceID: workspace.ID,
+		SourceDatasetID: ids[0], TargetDatasetID: ids[1], RelationType: "entity_link"})
+
+	window := 30
+	s.Contracts.SaveContract(ctx, &dataset.VariableContract{VarKey: "revenue", AsOfMode: dataset.AsOfLatestValue, WindowDays: &window})
+
+	workspaceID, datasetID := string(workspace.ID), string(ids[0])
+	s.Runs.SaveRunSummary(ctx, &models.RunSummary{RunID: "run-1", WorkspaceID: &workspaceID, DatasetID: &datasetID,
+		TopEffects: []models.RunEffect{{Rank: 1, VariableX: "revenue", VariableY: "region"}}})
+
+	user := uuid.MustParse(string(testUser))
+	s.Hypotheses.SaveHypothesis(ctx, user, uuid.New(), &models.HypothesisResult{ID: "hyp-1", SessionID: "session-1",
+		WorkspaceID: workspaceID, BusinessHypothesis: "Revenue varies by region", Passed: true})
+	return workspace.ID, ids
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := newMemoryService()
+	workspaceID, datasetIDs := seedWorkspace(t, source)
+
+	archive, err := source.Export(ctx, workspaceID, testUser)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if len(archive.Datasets) != 2 || len(archive.Relations) != 1 || len(archive.Contracts) != 1 || len(archive.Runs) != 1 || len(archive.Hypotheses) != 1 {
+		t.Fatalf("archive has %d datasets, %d relations, %d contracts, %d runs, %d hypotheses",
+			len(archive.Datasets), len(archive.Relations), len(archive.Contracts), len(archive.Runs), len(archive.Hypotheses))
+	}
+
+	// The archive travels as JSON
+	data, err := json.Marshal(archive)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded Archive
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	target := newMemoryService()
+	result, err := target.Import(ctx, &decoded, testUser)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.WorkspaceID == workspaceID || len(result.Datasets) != 2 || result.Relations != 1 || result.Contracts != 1 ||
+		result.Runs != 1 || result.Hypotheses != 1 || result.Sessions != 1 {
+		t.Fatalf("unexpected import result %s", result)
+	}
+
+	imported, _ := target.Workspaces.GetWithDatasets(ctx, result.WorkspaceID)
+	if imported.IsDefault || imported.Metadata["imported_from"] != string(workspaceID) {
+		t.Errorf("imported workspace should not be default and should name its origin, got %+v", imported.Workspace)
+	}
+	for _, ds := range imported.Datasets {
+		if ds.FilePath != "" {
+			t.Errorf("imported dataset %s kept file path %q", ds.OriginalFilename, ds.FilePath)
+		}
+	}
+	relation := imported.Relations[0]
+	if relation.SourceDatasetID != result.Datasets[string(datasetIDs[0])] || relation.TargetDatasetID != result.Datasets[string(datasetIDs[1])] {
+		t.Errorf("relation not remapped to the imported datasets: %+v", relation)
+	}
+
+	run, _ := target.Runs.GetRunSummary(ctx, "run-1")
+	if run == nil || *run.WorkspaceID != string(result.WorkspaceID) || *run.DatasetID != string(result.Datasets[string(datasetIDs[0])]) || len(run.TopEffects) != 1 {
+		t.Errorf("run not imported into the new workspace and dataset: %+v", run)
+	}
+
+	hypothesis, err := target.Hypotheses.GetHypothesis(ctx, uuid.MustParse(string(testUser)), "hyp-1")
+	if err != nil || hypothesis.WorkspaceID != string(result.WorkspaceID) || !hypothesis.Passed {
+		t.Fatalf("hypothesis not imported: %+v, %v", hypothesis, err)
+	}
+	sessions := target.Sessions.(*memorySessions)
+	if hypothesis.SessionID != sessions.created[0].ID.String() || sessions.states[sessions.created[0].ID] != models.SessionStateComplete {
+		t.Errorf("hypothesis should belong to a completed imported session, got session %s", hypothesis.SessionID)
+	}
+}
+
+func TestImportIntoSameDeploymentKeepsOriginals(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryService()
+	workspaceID, _ := seedWorkspace(t, s)
+
+	archive, err := s.Export(ctx, workspaceID, testUser)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	result, err := s.Import(ctx, archive, testUser)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	if result.Contracts != 0 || len(result.ContractConflicts) != 1 || result.ContractConflicts[0] != "revenue" {
+		t.Errorf("the existing contract should be kept and reported, got %s with conflicts %v", result, result.ContractConflicts)
+	}
+	if run, _ := s.Runs.GetRunSummary(ctx, "run-1"); *run.WorkspaceID != string(workspaceID) {
+		t.Errorf("the original run moved to workspace %s", *run.WorkspaceID)
+	}
+	original, _ := s.Hypotheses.GetHypothesis(ctx, uuid.MustParse(string(testUser)), "hyp-1")
+	if original.WorkspaceID != string(workspaceID) {
+		t.Errorf("the original hypothesis moved to workspace %s", original.WorkspaceID)
+	}
+	copies, _ := s.Hypotheses.ListByWorkspace(ctx, uuid.MustParse(string(testUser)), string(result.WorkspaceID), 10)
+	if len(copies) != 1 || copies[0].ID == "hyp-1" {
+		t.Errorf("the imported hypothesis should have a new ID, got %+v", copies)
+	}
+}
+
+func TestImportUndoesFailedImport(t *testing.T) {
+	ctx := context.Background()
+	source := newMemoryService()
+	workspaceID, _ := seedWorkspace(t, source)
+	archive, err := source.Export(ctx, workspaceID, testUser)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	// Hypotheses are saved last, so everything else has been created when the import fails
+	target := newMemoryService()
+	target.Hypotheses.(*memoryHypotheses).failSave = errors.New("connection reset")
+	if _, err := target.Import(ctx, archive, testUser); err == nil {
+		t.Fatal("import should fail when a hypothesis cannot be saved")
+	}
+
+	workspaces := target.Workspaces.(*memoryWorkspaces)
+	if len(workspaces.workspaces) != 0 || len(workspaces.relations) != 0 || len(target.Datasets.(*memoryDatasets).rows) != 0 {
+		t.Errorf("the partial workspace was left behind: %d workspaces, %d relations, %d datasets",
+			len(workspaces.workspaces), len(workspaces.relations), len(target.Datasets.(*memoryDatasets).rows))
+	}
+	if runs := target.Runs.(*memoryRuns).rows; len(runs) != 0 {
+		t.Errorf("imported runs were left behind: %v", runs)
+	}
+	if contracts := target.Contracts.(*memoryContracts).rows; len(contracts) != 0 {
+		t.Errorf("imported contracts were left behind: %v", contracts)
+	}
+	if sessions := target.Sessions.(*memorySessions).created; len(sessions) != 0 {
+		t.Errorf("imported sessions were left behind: %d", len(sessions))
+	}
+}
+
+func TestImportRefusesOtherFiles(t *testing.T) {
+	s := newMemoryService()
+	for name, archive := range map[string]*Archive{
+		"nil":          nil,
+		"other format": {Format: "something-else", FormatVersion: 1, Workspace: &dataset.Workspace{}},
+		"newer layout": {Format: Format, FormatVersion: FormatVersion + 1, Workspace: &dataset.Workspace{}},
+		"no workspace": {Format: Format, FormatVersion: FormatVersion},
+	} {
+		if _, err := s.Import(context.Background(), archive, testUser); !errors.Is(err, ErrUnsupportedArchive) {
+			t.Errorf("%s: got %v, want ErrUnsupportedArchive", name, err)
+		}
+	}
+}
+
+func TestDeleteDatasetsChecksRuns(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryService()
+	workspaceID, ids := seedWorkspace(t, s)
+	files := &recordingFiles{}
+	s.Files = files
+
+	result, err := s.DeleteDatasets(ctx, workspaceID, ids, false)
+	if !errors.Is(err, ErrDatasetsInUse) || apperrors.HTTPStatus(err) != 409 {
+		t.Fatalf("got %v, want ErrDatasetsInUse as a conflict", err)
+	}
+	if len(result.Blocked) != 1 || result.Blocked[0].DatasetID != ids[0] || result.Blocked[0].Runs[0] != "run-1" {
+		t.Errorf("blocked should list the run over the first dataset, got %+v", result.Blocked)
+	}
+	if len(result.Deleted) != 0 || len(s.Datasets.(*memoryDatasets).rows) != 2 {
+		t.Errorf("a blocked delete should delete nothing")
+	}
+
+	if _, err := s.DeleteDatasets(ctx, workspaceID, []core.ID{ids[1], ids[1]}, false); err != nil {
+		t.Fatalf("deleting an unreferenced dataset: %v", err)
+	}
+	if _, err := s.DeleteDatasets(ctx, workspaceID, []core.ID{ids[1]}, false); apperrors.HTTPStatus(err) != 404 {
+		t.Errorf("deleting a deleted dataset: got %v, want not found", err)
+	}
+
+	result, err = s.DeleteDatasets(ctx, workspaceID, []core.ID{ids[0]}, true)
+	if err != nil || len(result.Deleted) != 1 {
+		t.Fatalf("forced delete: %+v, %v", result, err)
+	}
+	if len(files.deleted) != 2 {
+		t.Errorf("both datasets' files should be removed, got %v", files.deleted)
+	}
+}
+
+func TestDeleteDatasetsStaysInWorkspace(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryService()
+	_, ids := seedWorkspace(t, s)
+	otherWorkspace, _ := seedWorkspace(t, s)
+
+	if _, err := s.DeleteDatasets(ctx, otherWorkspace, ids[1:], false); apperrors.HTTPStatus(err) != 404 {
+		t.Errorf("deleting another workspace's dataset: got %v, want not found", err)
+	}
+}
+
+type recordingFiles struct {
+	deleted []string
+}
+
+func (f *recordingFiles) Delete(ctx context.Context, filePath string) error {
+	f.deleted = append(f.deleted, filePath)
+	return nil
+}
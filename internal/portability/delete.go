@@ -0,0 +1,93 @@
+package portability
+
+import (
+	"context"
+	"log"
+
+	"gohypo/domain/core"
+	apperrors "gohypo/internal/errors"
+)
+
+// dependentLimit caps the runs a blocked delete lists per dataset
+const dependentLimit = 20
+
+// BlockedDataset is a dataset that runs still reference
+type BlockedDataset struct {
+	DatasetID core.ID  `json:"dataset_id"`
+	Name      string   `json:"name"`
+	Runs      []string `json:"runs"` // newest first, at most dependentLimit
+}
+
+// DeleteResult reports a bulk delete. When any dataset is blocked nothing is deleted.
+type DeleteResult struct {
+	Deleted []core.ID        `json:"deleted"`
+	Blocked []BlockedDataset `json:"blocked,omitempty"`
+}
+
+// ErrDatasetsInUse is returned when runs reference datasets a delete was not forced over
+var ErrDatasetsInUse = apperrors.Conflict("datasets are referenced by runs")
+
+// DeleteDatasets deletes datasets of a workspace. Each must belong to the workspace. Unless
+// force is set, no dataset is deleted while runs reference any of them; the result then
+// lists the runs and the error is ErrDatasetsInUse. Forced deletes keep the runs, which go
+// on naming the deleted dataset.
+func (s *Service) DeleteDatasets(ctx context.Context, workspaceID core.ID, ids []core.ID, force bool) (*DeleteResult, error) {
+	if len(ids) == 0 {
+		return nil, apperrors.InvalidInput("no datasets to delete")
+	}
+
+	ids = orderedIDs(ids)
+	result := &DeleteResult{}
+	var filePaths []string
+	for _, id := range ids {
+		ds, err := s.Datasets.GetByID(ctx, id)
+		if err != nil || ds == nil || ds.WorkspaceID != workspaceID {
+			return nil, apperrors.NotFound("Dataset " + string(id))
+		}
+		filePaths = append(filePaths, ds.FilePath)
+
+		if s.Runs == nil {
+			continue
+		}
+		runs, err := s.Runs.ListDatasetRuns(ctx, string(id), dependentLimit)
+		if err != nil {
+			return nil, apperrors.Wrap(err, "failed to check runs of dataset "+string(id))
+		}
+		if len(runs) > 0 {
+			blocked := BlockedDataset{DatasetID: id, Name: ds.GetDisplayName()}
+			for _, run := range runs {
+				blocked.Runs = append(blocked.Runs, run.RunID)
+			}
+			result.Blocked = append(result.Blocked, blocked)
+		}
+	}
+	if len(result.Blocked) > 0 && !force {
+		return result, ErrDatasetsInUse
+	}
+
+	for i, id := range ids {
+		if err := s.Datasets.Delete(ctx, id); err != nil {
+			return result, apperrors.Wrap(err, "failed to delete dataset "+string(id))
+		}
+		result.Deleted = append(result.Deleted, id)
+		if s.Files != nil && filePaths[i] != "" {
+			if err := s.Files.Delete(ctx, filePaths[i]); err != nil {
+				log.Printf("[Portability] Failed to remove file of deleted dataset %s: %v", id, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// orderedIDs drops repeated IDs, keeping the first of each
+func orderedIDs(ids []core.ID) []core.ID {
+	seen := map[core.ID]bool{}
+	var ordered []core.ID
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			ordered = append(ordered, id)
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,287 @@
+package referee
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/models"
+)
+
+const (
+	// counterexampleMinRows is the smallest segment or window whose correlation is trusted
+	counterexampleMinRows = 10
+
+	// counterexampleMinEffect is the smallest |r| counted as an effect; a baseline below it
+	// leaves nothing to reverse or absorb
+	counterexampleMinEffect = 0.1
+
+	// counterexampleMaxSegments skips columns with more distinct values than this as segments
+	counterexampleMaxSegments = 12
+
+	// counterexampleWindows is how many consecutive stretches of time are compared
+	counterexampleWindows = 4
+
+	// confounderAbsorption is the share of the effect a variable must absorb to be reported
+	confounderAbsorption = 0.5
+)
+
+// FindCounterexamples searches the matrix of a failed hypothesis for the evidence that killed
+// it: the segment of a low-cardinality column where the effect reverses, the stretch of the
+// time index where it disappears, and the variable that absorbs it when controlled for. At
+// most one counterexample of each kind is returned, strongest first.
+func FindCounterexamples(bundle *dataset.MatrixBundle, cause, effect core.VariableKey) []models.Counterexample {
+	if bundle == nil {
+		return nil
+	}
+	x, okX := bundle.GetColumnData(cause)
+	y, okY := bundle.GetColumnData(effect)
+	if !okX || !okY {
+		return nil
+	}
+	baseline, rows := completeCorrelation(x, y, nil)
+	if rows < counterexampleMinRows || math.IsNaN(baseline) {
+		return nil
+	}
+
+	timeKey, hasTime := bundle.TimeIndex()
+	var found []models.Counterexample
+	if hasTime {
+		if c, ok := timeWindowCounterexample(bundle, timeKey, x, y, baseline); ok {
+			found = append(found, c)
+		}
+	}
+
+	var segment, confounder *models.Counterexample
+	for _, key := range bundle.Matrix.VariableKeys {
+		if key == cause || key == effect || (hasTime && key == timeKey) {
+			continue
+		}
+		column, ok := bundle.GetColumnData(key)
+		if !ok || !hasVariance(column) {
+			continue
+		}
+		if c, ok := segmentCounterexample(key, column, x, y, baseline); ok && (segment == nil || c.Strength() > segment.Strength()) {
+			segment = &c
+		}
+		if c, ok := confounderCounterexample(key, column, x, y, baseline); ok && (confounder == nil || c.Strength() > confounder.Strength()) {
+			confounder = &c
+		}
+	}
+	if segment != nil {
+		found = append(found, *segment)
+	}
+	if confounder != nil {
+		found = append(found, *confounder)
+	}
+	return models.RankCounterexamples(found)
+}
+
+// segmentCounterexample finds the segment of a low-cardinality column where the effect runs
+// against its overall direction
+func segmentCounterexample(key core.VariableKey, column, x, y []float64, baseline float64) (models.Counterexample, bool) {
+	if math.Abs(baseline) < counterexampleMinEffect {
+		return models.Counterexample{}, false
+	}
+	members := make(map[float64][]int)
+	for i, v := range column {
+		if math.IsNaN(v) {
+			continue
+		}
+		members[v] = append(members[v], i)
+		if len(members) > counterexampleMaxSegments {
+			return models.Counterexample{}, false
+		}
+	}
+	var best models.Counterexample
+	found := false
+	for value, rows := range members {
+		r, n := completeCorrelation(x, y, rows)
+		if n < counterexampleMinRows || math.IsNaN(r) || r*baseline >= 0 || math.Abs(r) < counterexampleMinEffect {
+			continue
+		}
+		c := models.Counterexample{
+			Kind:     models.CounterexampleSegmentReversal,
+			Source:   "segment_scan",
+			Variable: string(key),
+			Segment:  strconv.FormatFloat(value, 'g', -1, 64),
+			Effect:   r,
+			Baseline: baseline,
+			Rows:     n,
+		}
+		if !found || c.Strength() > best.Strength() || (c.Strength() == best.Strength() && c.Segment < best.Segment) {
+			best, found = c, true
+		}
+	}
+	if found {
+		best.Summary = fmt.Sprintf("Where %s = %s (%d rows) the effect reverses: r = %.2f against %.2f overall.", best.Variable, best.Segment, best.Rows, best.Effect, best.Baseline)
+	}
+	return best, found
+}
+
+// timeWindowCounterexample splits the rows, ordered by the time index, into consecutive
+// windows and reports the one where the effect is weakest, when it fades or reverses there
+func timeWindowCounterexample(bundle *dataset.MatrixBundle, timeKey core.VariableKey, x, y []float64, baseline float64) (models.Counterexample, bool) {
+	if math.Abs(baseline) < counterexampleMinEffect {
+		return models.Counterexample{}, false
+	}
+	times, _ := bundle.GetColumnData(timeKey)
+	var order []int
+	for i, t := range times {
+		if !math.IsNaN(t) && i < len(x) && i < len(y) {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return times[order[a]] < times[order[b]] })
+	size := len(order) / counterexampleWindows
+	if size < counterexampleMinRows {
+		return models.Counterexample{}, false
+	}
+
+	var best models.Counterexample
+	found := false
+	for w := 0; w < counterexampleWindows; w++ {
+		window := order[w*size : (w+1)*size]
+		if w == counterexampleWindows-1 {
+			window = order[w*size:]
+		}
+		r, n := completeCorrelation(x, y, window)
+		if n < counterexampleMinRows || math.IsNaN(r) {
+			continue
+		}
+		// Same sign: the effect counts as gone once it falls below a third of the baseline
+		if r*baseline > 0 && math.Abs(r) >= math.Max(counterexampleMinEffect, math.Abs(baseline)/3) {
+			continue
+		}
+		c := models.Counterexample{
+			Kind:     models.CounterexampleTimeWindow,
+			Source:   "time_window_scan",
+			Variable: string(timeKey),
+			From:     times[window[0]],
+			To:       times[window[len(window)-1]],
+			Effect:   r,
+			Baseline: baseline,
+			Rows:     n,
+		}
+		if !found || c.Strength() > best.Strength() {
+			best, found = c, true
+		}
+	}
+	if found {
+		best.Summary = fmt.Sprintf("Between %s %g and %g (%d rows) the effect disappears: r = %.2f against %.2f overall.", best.Variable, best.From, best.To, best.Rows, best.Effect, best.Baseline)
+	}
+	return best, found
+}
+
+// confounderCounterexample reports a variable whose control absorbs most of the effect
+func confounderCounterexample(key core.VariableKey, z, x, y []float64, baseline float64) (models.Counterexample, bool) {
+	if math.Abs(baseline) < counterexampleMinEffect {
+		return models.Counterexample{}, false
+	}
+	var rows []int
+	for i := range x {
+		if i < len(y) && i < len(z) && !math.IsNaN(x[i]) && !math.IsNaN(y[i]) && !math.IsNaN(z[i]) {
+			rows = append(rows, i)
+		}
+	}
+	if len(rows) < counterexampleMinRows {
+		return models.Counterexample{}, false
+	}
+	rxy, _ := completeCorrelation(x, y, rows)
+	rxz, _ := completeCorrelation(x, z, rows)
+	ryz, _ := completeCorrelation(y, z, rows)
+	denominator := math.Sqrt((1 - rxz*rxz) * (1 - ryz*ryz))
+	if math.IsNaN(rxy) || math.IsNaN(rxz) || math.IsNaN(ryz) || denominator < 1e-9 {
+		return models.Counterexample{}, false
+	}
+	partial := (rxy - rxz*ryz) / denominator
+	absorbed := 1 - math.Abs(partial)/math.Abs(rxy)
+	if math.Abs(rxy) < counterexampleMinEffect || absorbed < confounderAbsorption {
+		return models.Counterexample{}, false
+	}
+	return models.Counterexample{
+		Kind:     models.CounterexampleConfounder,
+		Source:   "confounder_scan",
+		Variable: string(key),
+		Summary:  fmt.Sprintf("Controlling for %s absorbs %.0f%% of the effect: partial r = %.2f against %.2f.", key, absorbed*100, partial, rxy),
+		Effect:   partial,
+		Baseline: rxy,
+		Rows:     len(rows),
+	}, true
+}
+
+// Counterexample turns a confounded match into the "why it failed" evidence, or nil when
+// matching did not change the picture
+func (r *PropensityMatchReport) Counterexample() *models.Counterexample {
+	if r == nil || !r.Confounded {
+		return nil
+	}
+	summary := fmt.Sprintf("Matching on %d covariates moves the effect from %.3g to %.3g", len(r.Adjusted), r.NaiveEffect, r.MatchedEffect)
+	if r.SignFlipped {
+		summary += ", reversing it"
+	}
+	return &models.Counterexample{
+		Kind:     models.CounterexampleConfounder,
+		Source:   "propensity_matching",
+		Variable: joinNames(r.Adjusted),
+		Summary:  summary + ".",
+		Effect:   r.MatchedEffect,
+		Baseline: r.NaiveEffect,
+		Rows:     2 * r.MatchedPairs,
+	}
+}
+
+// completeCorrelation is the Pearson correlation over the given rows (all rows when nil)
+// where both values are present, with the number of rows used
+func completeCorrelation(x, y []float64, rows []int) (float64, int) {
+	var sx, sy, sxx, syy, sxy float64
+	n := 0
+	visit := func(i int) {
+		if i >= len(x) || i >= len(y) || math.IsNaN(x[i]) || math.IsNaN(y[i]) {
+			return
+		}
+		sx += x[i]
+		sy += y[i]
+		sxx += x[i] * x[i]
+		syy += y[i] * y[i]
+		sxy += x[i] * y[i]
+		n++
+	}
+	if rows == nil {
+		for i := range x {
+			visit(i)
+		}
+	} else {
+		for _, i := range rows {
+			visit(i)
+		}
+	}
+	if n < 3 {
+		return math.NaN(), n
+	}
+	fn := float64(n)
+	varX := sxx - sx*sx/fn
+	varY := syy - sy*sy/fn
+	if varX <= 0 || varY <= 0 {
+		return math.NaN(), n
+	}
+	return (sxy - sx*sy/fn) / math.Sqrt(varX*varY), n
+}
+
+// joinNames lists names for a summary, shortening long lists
+func joinNames(names []string) string {
+	if len(names) > 3 {
+		return fmt.Sprintf("%s, %s, %s and %d more", names[0], names[1], names[2], len(names)-3)
+	}
+	out := ""
+	for i, name := range names {
+		if i > 0 {
+			out += ", "
+		}
+		out += name
+	}
+	return out
+}
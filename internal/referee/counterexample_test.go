@@ -0,0 +1,66 @@
+package referee
+
+import (
+	"math/rand"
+	"testing"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/models"
+)
+
+func counterexampleBundle(columns map[core.VariableKey][]float64, order ...core.VariableKey) *dataset.MatrixBundle {
+	bundle := dataset.NewMatrixBundle("snap", "view", "cohort", core.CutoffAt(core.Now()), 0)
+	for _, key := range order {
+		bundle.AddColumn(key, columns[key], dataset.ColumnMeta{VariableKey: key, StatisticalType: dataset.TypeNumeric}, dataset.ResolutionAudit{VariableKey: key})
+	}
+	return bundle
+}
+
+// TestFindCounterexamples verifies the scan names the segment where an effect reverses, the
+// stretch of time where it vanishes and the variable that absorbs it
+func TestFindCounterexamples(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	n := 400
+	x, y, region, date := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	for i := 0; i < n; i++ {
+		x[i] = rng.NormFloat64()
+		region[i] = float64(i % 4)
+		date[i] = float64(20000 + i)
+		switch {
+		case region[i] == 3:
+			y[i] = -x[i] + 0.3*rng.NormFloat64()
+		case i >= 3*n/4:
+			y[i] = rng.NormFloat64()
+		default:
+			y[i] = x[i] + 0.3*rng.NormFloat64()
+		}
+	}
+	found := FindCounterexamples(counterexampleBundle(map[core.VariableKey][]float64{
+		"spend": x, "revenue": y, "region": region, "date": date,
+	}, "spend", "revenue", "region", "date"), "spend", "revenue")
+
+	kinds := make(map[string]models.Counterexample)
+	for _, c := range found {
+		kinds[c.Kind] = c
+	}
+	if c, ok := kinds[models.CounterexampleSegmentReversal]; !ok || c.Variable != "region" || c.Segment != "3" || c.Effect > -0.5 {
+		t.Errorf("expected region 3 to reverse the effect, got %+v", found)
+	}
+	if c, ok := kinds[models.CounterexampleTimeWindow]; !ok || c.Variable != "date" || c.From != 20300 || c.To != 20399 {
+		t.Errorf("expected the last quarter of dates to lose the effect, got %+v", found)
+	}
+
+	z := make([]float64, n)
+	for i := range z {
+		z[i] = rng.NormFloat64()
+		x[i] = z[i] + 0.5*rng.NormFloat64()
+		y[i] = z[i] + 0.5*rng.NormFloat64()
+	}
+	found = FindCounterexamples(counterexampleBundle(map[core.VariableKey][]float64{
+		"spend": x, "revenue": y, "season": z,
+	}, "spend", "revenue", "season"), "spend", "revenue")
+	if len(found) != 1 || found[0].Kind != models.CounterexampleConfounder || found[0].Variable != "season" {
+		t.Errorf("expected season to absorb the effect, got %+v", found)
+	}
+}
@@ -3,87 +3,291 @@ package referee
 import (
 	"fmt"
 	"math"
+	"math/rand"
 	"sort"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+)
+
+const (
+	// interventionCILevel is the bootstrap confidence level the effect must clear
+	interventionCILevel = 0.999
+
+	// maxInterventionCovariates caps how many extra bundle columns adjust the outcome model
+	maxInterventionCovariates = 10
+
+	// minInterventionRows is the smallest complete-case sample worth modelling
+	minInterventionRows = 10
 )
 
-// SyntheticIntervention implements synthetic intervention testing via G-Computation
+// CounterfactualGateName is the gate name of the counterfactual check every hypothesis faces
+const CounterfactualGateName = "Synthetic_Intervention"
+
+// Verdict is the outcome of a hypothesis's gates
+type Verdict struct {
+	Passed               bool
+	PassedReferees       int  // selected referees that passed, the counterfactual gate aside
+	TotalReferees        int  // selected referees that ran, the counterfactual gate aside
+	CounterfactualRan    bool // whether the counterfactual gate is among the results
+	CounterfactualPassed bool
+}
+
+// Decide accepts a hypothesis when the counterfactual gate passed and at least one other
+// referee passed, or no other referee ran. The gate runs on every hypothesis whether it was
+// selected or not. Counted as one more referee, it would accept on its own any hypothesis
+// whose selected referees all failed, since one passing referee is enough; so it can reject
+// a hypothesis but never accept one the referees did not.
+func Decide(results []RefereeResult) Verdict {
+	verdict := Verdict{CounterfactualPassed: true}
+	for _, result := range results {
+		if result.GateName == CounterfactualGateName {
+			verdict.CounterfactualRan = true
+			verdict.CounterfactualPassed = verdict.CounterfactualPassed && result.Passed
+			continue
+		}
+		verdict.TotalReferees++
+		if result.Passed {
+			verdict.PassedReferees++
+		}
+	}
+	verdict.Passed = verdict.CounterfactualPassed && (verdict.PassedReferees > 0 || verdict.TotalReferees == 0)
+	return verdict
+}
+
+// SyntheticIntervention implements synthetic intervention testing via G-Computation: fit an
+// outcome model Y ~ X + C, set X to a low and a high value for every row, and compare the
+// average predicted outcomes. The difference estimates E[Y|do(X=high)] - E[Y|do(X=low)].
 type SyntheticIntervention struct {
-	InterventionStrength  float64 // Strength of synthetic intervention (0.1 to 1.0)
+	InterventionStrength  float64 // Fraction of the SYNTHETIC_INTERVENTION_SIGMA span to intervene over (0.1 to 1.0)
 	NumBootstrap          int     // Number of bootstrap samples for confidence
 	ConfoundingAdjustment bool    // Whether to adjust for confounding variables
+	Seed                  int64   // Bootstrap seed; the same data always yields the same interval
+}
+
+// InterventionEstimate is the simulated effect of moving X from Low to High
+type InterventionEstimate struct {
+	Low        float64  `json:"do_x_low"`
+	High       float64  `json:"do_x_high"`
+	Effect     float64  `json:"effect"` // E[Y|do(X=high)] - E[Y|do(X=low)]
+	CILower    float64  `json:"ci_lower"`
+	CIUpper    float64  `json:"ci_upper"`
+	CILevel    float64  `json:"ci_level"`
+	PValue     float64  `json:"p_value"` // two-sided bootstrap p-value for a zero effect
+	Bootstrap  int      `json:"bootstrap"`
+	SampleSize int      `json:"sample_size"`
+	Adjusted   []string `json:"adjusted_for,omitempty"`
 }
 
-// Execute tests causal claims through synthetic intervention simulation
+// Execute tests causal claims through synthetic intervention simulation. Confounders are
+// read from metadata["confounding_variables"] when ConfoundingAdjustment is set.
 func (si *SyntheticIntervention) Execute(x, y []float64, metadata map[string]interface{}) RefereeResult {
 	if err := ValidateData(x, y); err != nil {
 		return RefereeResult{
-			GateName:      "Synthetic_Intervention",
+			GateName:      CounterfactualGateName,
 			Passed:        false,
 			FailureReason: err.Error(),
 		}
 	}
 
-	if si.InterventionStrength == 0 {
-		si.InterventionStrength = 0.5 // Moderate intervention strength
-	}
-	if si.NumBootstrap == 0 {
-		si.NumBootstrap = 1000
-	}
-
-	// Extract confounding variables if available
 	confounders := [][]float64{}
 	if si.ConfoundingAdjustment {
 		if confounderData, ok := metadata["confounding_variables"].([][]float64); ok {
 			confounders = confounderData
 		}
 	}
+	return si.run(x, y, confounders, nil)
+}
 
-	// Fit observational model
-	model := si.fitObservationalModel(x, y, confounders)
+// ExecuteOnBundle runs the intervention on a resolved matrix. Every other numeric column in
+// the bundle (up to maxInterventionCovariates) adjusts the outcome model, and rows with a
+// missing value in any used column are dropped.
+func (si *SyntheticIntervention) ExecuteOnBundle(bundle *dataset.MatrixBundle, cause, effect core.VariableKey) RefereeResult {
+	if bundle == nil {
+		return RefereeResult{GateName: CounterfactualGateName, FailureReason: "no matrix bundle"}
+	}
+	x, okX := bundle.GetColumnData(cause)
+	y, okY := bundle.GetColumnData(effect)
+	if !okX || !okY {
+		return RefereeResult{
+			GateName:      CounterfactualGateName,
+			FailureReason: fmt.Sprintf("matrix is missing %s or %s", cause, effect),
+		}
+	}
 
-	// Simulate intervention: set X to different values and predict Y
-	interventionEffects := si.simulateInterventions(x, model, si.InterventionStrength)
+	var names []string
+	var covariates [][]float64
+	for _, key := range bundle.Matrix.VariableKeys {
+		if key == cause || key == effect || len(covariates) >= maxInterventionCovariates {
+			continue
+		}
+		if column, ok := bundle.GetColumnData(key); ok && hasVariance(column) {
+			names = append(names, string(key))
+			covariates = append(covariates, column)
+		}
+	}
 
-	// Compute average treatment effect (ATE)
-	ate := si.computeATE(interventionEffects)
+	// Complete cases only
+	var cx, cy []float64
+	cc := make([][]float64, len(covariates))
+	for i := range x {
+		if i >= len(y) || math.IsNaN(x[i]) || math.IsNaN(y[i]) {
+			continue
+		}
+		complete := true
+		for _, column := range covariates {
+			if i >= len(column) || math.IsNaN(column[i]) {
+				complete = false
+				break
+			}
+		}
+		if !complete {
+			continue
+		}
+		cx = append(cx, x[i])
+		cy = append(cy, y[i])
+		for j, column := range covariates {
+			cc[j] = append(cc[j], column[i])
+		}
+	}
+	if err := ValidateData(cx, cy); err != nil {
+		return RefereeResult{GateName: CounterfactualGateName, FailureReason: err.Error()}
+	}
+	return si.run(cx, cy, cc, names)
+}
 
-	// Bootstrap for confidence interval
-	bootstrapATEs := si.bootstrapATE(x, y, confounders, si.InterventionStrength, si.NumBootstrap)
-	ciLower, ciUpper := si.computeConfidenceInterval(bootstrapATEs, 0.999) // 99.9% CI
+// run estimates the intervention effect and turns it into a gate result
+func (si *SyntheticIntervention) run(x, y []float64, confounders [][]float64, names []string) RefereeResult {
+	estimate, err := si.Estimate(x, y, confounders)
+	if err != nil {
+		return RefereeResult{
+			GateName:      CounterfactualGateName,
+			Passed:        false,
+			FailureReason: err.Error(),
+		}
+	}
+	estimate.Adjusted = names
 
 	// Check if CI excludes zero (statistical significance at 99.9% level)
-	passed := ciLower > 0 || ciUpper < 0 // CI excludes zero
+	passed := estimate.CILower > 0 || estimate.CIUpper < 0
 
 	failureReason := ""
 	if !passed {
-		if ciLower <= 0 && ciUpper >= 0 {
-			failureReason = fmt.Sprintf("NO CAUSAL EFFECT: Intervention simulation shows no significant treatment effect (ATE=%.4f, 99.9%% CI=[%.4f, %.4f]). Relationship may be correlational, not causal. Observed association could be due to confounding or reverse causation.", ate, ciLower, ciUpper)
-		} else if math.Abs(ate) < 0.1 {
-			failureReason = fmt.Sprintf("WEAK CAUSAL EFFECT: Intervention shows statistically significant but practically negligible effect (ATE=%.4f). Relationship exists but causal impact is too small to be meaningful.", ate)
-		} else {
-			failureReason = fmt.Sprintf("CAUSAL EFFECT DETECTED BUT UNCERTAIN: Intervention suggests causal relationship (ATE=%.4f) but confidence interval too wide ([%.4f, %.4f]). Effect may be real but requires more data for precision.", ate, ciLower, ciUpper)
-		}
+		failureReason = fmt.Sprintf("NO CAUSAL EFFECT: Intervention simulation shows no significant treatment effect (effect=%.4f, %.1f%% CI=[%.4f, %.4f]). Relationship may be correlational, not causal. Observed association could be due to confounding or reverse causation.",
+			estimate.Effect, estimate.CILevel*100, estimate.CILower, estimate.CIUpper)
 	}
 
-	// Use width of CI as pseudo p-value (narrower CI = more significant)
-	ciWidth := ciUpper - ciLower
-	pValue := math.Min(1.0, ciWidth*2) // Simplified p-value based on CI width
-
 	return RefereeResult{
-		GateName:      "Synthetic_Intervention",
-		Passed:        passed,
-		Statistic:     ate,
-		PValue:        pValue,
-		StandardUsed:  "99.9% CI excludes zero (G-computation ATE significant)",
-		FailureReason: failureReason,
+		GateName:       CounterfactualGateName,
+		Passed:         passed,
+		Statistic:      estimate.Effect,
+		PValue:         estimate.PValue,
+		StandardUsed:   fmt.Sprintf("%.1f%% bootstrap CI of do(X=%.3g) vs do(X=%.3g) effect excludes zero (G-computation)", estimate.CILevel*100, estimate.High, estimate.Low),
+		FailureReason:  failureReason,
+		EvidenceBlocks: []interface{}{estimate},
 	}
 }
 
+// Estimate fits the outcome model and simulates do(X=low) and do(X=high), where the two
+// values sit SYNTHETIC_INTERVENTION_SIGMA·strength standard deviations apart around the mean
+// of X, clamped to the observed range so the model is never extrapolated. The interval is a
+// percentile bootstrap over rows.
+func (si *SyntheticIntervention) Estimate(x, y []float64, confounders [][]float64) (*InterventionEstimate, error) {
+	if len(x) < minInterventionRows {
+		return nil, fmt.Errorf("INSUFFICIENT DATA: %d complete rows, intervention simulation needs at least %d", len(x), minInterventionRows)
+	}
+	strength := si.InterventionStrength
+	if strength <= 0 {
+		strength = 1
+	}
+	nBootstrap := si.NumBootstrap
+	if nBootstrap <= 0 {
+		nBootstrap = BOOTSTRAP_SAMPLES
+	}
+
+	mean, sd := meanStd(x)
+	if sd == 0 {
+		return nil, fmt.Errorf("NO VARIATION: cause is constant, so no intervention can be simulated")
+	}
+	xMin, xMax := si.dataRange(x)
+	halfSpan := SYNTHETIC_INTERVENTION_SIGMA * strength * sd / 2
+	low := math.Max(xMin, mean-halfSpan)
+	high := math.Min(xMax, mean+halfSpan)
+
+	effect, ok := si.interventionEffect(x, y, confounders, low, high)
+	if !ok {
+		return nil, fmt.Errorf("MODEL FAILURE: outcome model could not be fit (collinear covariates)")
+	}
+
+	rng := rand.New(rand.NewSource(si.Seed))
+	n := len(x)
+	boot := make([]float64, 0, nBootstrap)
+	xBoot, yBoot := make([]float64, n), make([]float64, n)
+	cBoot := make([][]float64, len(confounders))
+	for j := range cBoot {
+		cBoot[j] = make([]float64, n)
+	}
+	for b := 0; b < nBootstrap; b++ {
+		for i := 0; i < n; i++ {
+			idx := rng.Intn(n)
+			xBoot[i], yBoot[i] = x[idx], y[idx]
+			for j, confounder := range confounders {
+				cBoot[j][i] = confounder[idx]
+			}
+		}
+		if e, ok := si.interventionEffect(xBoot, yBoot, cBoot, low, high); ok {
+			boot = append(boot, e)
+		}
+	}
+	if len(boot) == 0 {
+		return nil, fmt.Errorf("MODEL FAILURE: no bootstrap resample could be fit")
+	}
+
+	ciLower, ciUpper := si.computeConfidenceInterval(boot, interventionCILevel)
+	below, above := 0, 0
+	for _, e := range boot {
+		if e <= 0 {
+			below++
+		}
+		if e >= 0 {
+			above++
+		}
+	}
+	pValue := math.Min(1, 2*float64(min(below, above)+1)/float64(len(boot)+1))
+
+	return &InterventionEstimate{
+		Low:        low,
+		High:       high,
+		Effect:     effect,
+		CILower:    ciLower,
+		CIUpper:    ciUpper,
+		CILevel:    interventionCILevel,
+		PValue:     pValue,
+		Bootstrap:  len(boot),
+		SampleSize: n,
+	}, nil
+}
+
 // AuditEvidence performs evidence auditing for synthetic interventions using discovery q-values
 func (si *SyntheticIntervention) AuditEvidence(discoveryEvidence interface{}, validationData []float64, metadata map[string]interface{}) RefereeResult {
 	// Synthetic interventions are about counterfactual analysis - use default audit logic
 	// since intervention analysis requires causal modeling that's hard to audit from q-values alone
-	return DefaultAuditEvidence("Synthetic_Intervention", discoveryEvidence, validationData, metadata)
+	return DefaultAuditEvidence(CounterfactualGateName, discoveryEvidence, validationData, metadata)
+}
+
+// interventionEffect fits Y ~ X + C and returns the g-formula contrast: the mean prediction
+// over all rows with X set to high, minus the same with X set to low
+func (si *SyntheticIntervention) interventionEffect(x, y []float64, confounders [][]float64, low, high float64) (float64, bool) {
+	model := si.fitObservationalModel(x, y, confounders)
+	if math.IsNaN(model.Beta1) || math.IsInf(model.Beta1, 0) {
+		return 0, false
+	}
+	var sumHigh, sumLow float64
+	for i := range x {
+		sumHigh += si.predictCounterfactual(high, i, confounders, model)
+		sumLow += si.predictCounterfactual(low, i, confounders, model)
+	}
+	return (sumHigh - sumLow) / float64(len(x)), true
 }
 
 // fitObservationalModel fits a model for the observational data
@@ -130,7 +334,7 @@ func (si *SyntheticIntervention) linearRegression(x, y []float64) (float64, floa
 // multipleRegression performs multiple regression with confounders
 func (si *SyntheticIntervention) multipleRegression(x, y []float64, confounders [][]float64) *ObservationalModel {
 	n := len(x)
-	nVars := 1 + len(confounders) // X + confounders
+	nVars := 2 + len(confounders) // intercept, X and confounders
 
 	// Create design matrix: [1, X, C1, C2, ..., Ck]
 	X := make([][]float64, n)
@@ -161,114 +365,19 @@ func (si *SyntheticIntervention) multipleRegression(x, y []float64, confounders
 	return model
 }
 
-// simulateInterventions simulates different intervention values
-func (si *SyntheticIntervention) simulateInterventions(x []float64, model *ObservationalModel, strength float64) []InterventionEffect {
-	// Create intervention scenarios: vary X while holding confounders constant
-	xMin, xMax := si.dataRange(x)
-	interventionPoints := []float64{
-		xMin,                    // Minimum intervention
-		xMin + (xMax-xMin)*0.25, // Low intervention
-		xMin + (xMax-xMin)*0.5,  // Medium intervention
-		xMin + (xMax-xMin)*0.75, // High intervention
-		xMax,                    // Maximum intervention
-	}
-
-	effects := make([]InterventionEffect, len(interventionPoints))
-
-	for i, interventionValue := range interventionPoints {
-		// Predict counterfactual outcomes under intervention
-		counterfactualY := si.predictCounterfactual(interventionValue, model)
-
-		// Compute effect relative to baseline (median observed X)
-		sort.Float64s(x)
-		baselineX := x[len(x)/2] // Median as baseline
-		baselineY := si.predictCounterfactual(baselineX, model)
-
-		effect := counterfactualY - baselineY
-
-		effects[i] = InterventionEffect{
-			InterventionValue: interventionValue,
-			CounterfactualY:   counterfactualY,
-			BaselineY:         baselineY,
-			Effect:            effect,
-		}
-	}
-
-	return effects
-}
-
-type InterventionEffect struct {
-	InterventionValue float64
-	CounterfactualY   float64
-	BaselineY         float64
-	Effect            float64
-}
-
-// predictCounterfactual predicts outcome under intervention
-func (si *SyntheticIntervention) predictCounterfactual(interventionX float64, model *ObservationalModel) float64 {
-	if !model.HasConfounders {
-		return model.Beta0 + model.Beta1*interventionX
-	}
-
-	// For confounders, use their mean values (simplified approach)
+// predictCounterfactual predicts row i's outcome under do(X=interventionX), keeping the row's
+// own confounder values
+func (si *SyntheticIntervention) predictCounterfactual(interventionX float64, row int, confounders [][]float64, model *ObservationalModel) float64 {
 	prediction := model.Beta0 + model.Beta1*interventionX
-	for _, coef := range model.ConfounderCoefs {
-		// Assume confounders are centered, so their mean effect is 0
-		// In practice, you'd use actual confounder values or their means
-		prediction += coef * 0.0 // Simplified: assume confounders are mean-centered
-	}
-
-	return prediction
-}
-
-// computeATE computes average treatment effect from intervention effects
-func (si *SyntheticIntervention) computeATE(effects []InterventionEffect) float64 {
-	if len(effects) == 0 {
-		return 0
-	}
-
-	sumEffects := 0.0
-	for _, effect := range effects {
-		sumEffects += effect.Effect
+	if !model.HasConfounders {
+		return prediction
 	}
-
-	return sumEffects / float64(len(effects))
-}
-
-// bootstrapATE performs bootstrap sampling for ATE confidence intervals
-func (si *SyntheticIntervention) bootstrapATE(x, y []float64, confounders [][]float64, strength float64, nBootstrap int) []float64 {
-	ates := make([]float64, nBootstrap)
-	n := len(x)
-
-	for i := 0; i < nBootstrap; i++ {
-		// Bootstrap sample with replacement
-		xBoot, yBoot := make([]float64, n), make([]float64, n)
-		confoundersBoot := make([][]float64, len(confounders))
-		for j := range confoundersBoot {
-			confoundersBoot[j] = make([]float64, n)
+	for j, coef := range model.ConfounderCoefs {
+		if j < len(confounders) && row < len(confounders[j]) {
+			prediction += coef * confounders[j][row]
 		}
-
-		for j := 0; j < n; j++ {
-			idx := int(math.Floor(float64(n) * math.Sqrt(float64(i*j%n))))
-			if idx >= n {
-				idx = n - 1
-			}
-			xBoot[j] = x[idx]
-			yBoot[j] = y[idx]
-			for k, confounder := range confounders {
-				if idx < len(confounder) {
-					confoundersBoot[k][j] = confounder[idx]
-				}
-			}
-		}
-
-		// Compute ATE for bootstrap sample
-		model := si.fitObservationalModel(xBoot, yBoot, confoundersBoot)
-		effects := si.simulateInterventions(xBoot, model, strength)
-		ates[i] = si.computeATE(effects)
 	}
-
-	return ates
+	return prediction
 }
 
 // computeConfidenceInterval computes confidence interval from bootstrap distribution
@@ -309,6 +418,39 @@ func (si *SyntheticIntervention) dataRange(data []float64) (float64, float64) {
 	return minVal, maxVal
 }
 
+// meanStd returns the mean and sample standard deviation
+func meanStd(data []float64) (float64, float64) {
+	if len(data) < 2 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, v := range data {
+		sum += v
+	}
+	mean := sum / float64(len(data))
+	ss := 0.0
+	for _, v := range data {
+		ss += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(ss / float64(len(data)-1))
+}
+
+// hasVariance reports whether a column has at least two distinct non-missing values
+func hasVariance(data []float64) bool {
+	first, seen := 0.0, false
+	for _, v := range data {
+		if math.IsNaN(v) {
+			continue
+		}
+		if !seen {
+			first, seen = v, true
+		} else if v != first {
+			return true
+		}
+	}
+	return false
+}
+
 // Matrix operations for SyntheticIntervention
 func (si *SyntheticIntervention) matrixTranspose(A [][]float64) [][]float64 {
 	m, n := len(A), len(A[0])
@@ -0,0 +1,89 @@
+package referee
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestSyntheticInterventionAdjustsForConfounder verifies the g-computation effect recovers the
+// direct slope when a confounder drives both variables, and that the result is reproducible
+func TestSyntheticInterventionAdjustsForConfounder(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	n := 300
+	x, y, c := make([]float64, n), make([]float64, n), make([]float64, n)
+	for i := range x {
+		c[i] = rng.NormFloat64()
+		x[i] = c[i] + rng.NormFloat64()
+		y[i] = 2*x[i] + 3*c[i] + 0.5*rng.NormFloat64()
+	}
+
+	si := &SyntheticIntervention{NumBootstrap: 200, ConfoundingAdjustment: true}
+	estimate, err := si.Estimate(x, y, [][]float64{c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slope := estimate.Effect / (estimate.High - estimate.Low)
+	if math.Abs(slope-2) > 0.2 {
+		t.Fatalf("expected an adjusted slope near 2, got %.3f", slope)
+	}
+	if estimate.CILower <= 0 {
+		t.Fatalf("expected the interval to exclude zero, got [%.3f, %.3f]", estimate.CILower, estimate.CIUpper)
+	}
+
+	again, _ := si.Estimate(x, y, [][]float64{c})
+	if again.CILower != estimate.CILower || again.CIUpper != estimate.CIUpper {
+		t.Fatal("bootstrap interval is not reproducible")
+	}
+
+	result := si.Execute(x, y, map[string]interface{}{"confounding_variables": [][]float64{c}})
+	if !result.Passed || result.GateName != CounterfactualGateName {
+		t.Fatalf("expected the gate to pass, got %+v", result)
+	}
+}
+
+// TestSyntheticInterventionRejectsNoEffect verifies independent data fails the gate
+func TestSyntheticInterventionRejectsNoEffect(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	x, y := make([]float64, 200), make([]float64, 200)
+	for i := range x {
+		x[i] = rng.NormFloat64()
+		y[i] = rng.NormFloat64()
+	}
+	result := (&SyntheticIntervention{NumBootstrap: 200}).Execute(x, y, nil)
+	if result.Passed {
+		t.Fatalf("expected no effect, got %+v", result)
+	}
+}
+
+// TestDecide verifies the counterfactual gate can veto a hypothesis but never accept one on
+// its own, and that without other referees the gate decides
+func TestDecide(t *testing.T) {
+	gate := func(passed bool) RefereeResult {
+		return RefereeResult{GateName: CounterfactualGateName, Passed: passed}
+	}
+	referee := func(passed bool) RefereeResult {
+		return RefereeResult{GateName: "Permutation_Shredder", Passed: passed}
+	}
+
+	cases := []struct {
+		name    string
+		results []RefereeResult
+		want    bool
+	}{
+		{"gate alone passes referees that failed", []RefereeResult{referee(false), referee(false), gate(true)}, false},
+		{"referee and gate pass", []RefereeResult{referee(false), referee(true), gate(true)}, true},
+		{"gate vetoes passing referees", []RefereeResult{referee(true), referee(true), gate(false)}, false},
+		{"no referees, gate passes", []RefereeResult{gate(true)}, true},
+		{"no referees, gate fails", []RefereeResult{gate(false)}, false},
+		{"nothing ran", nil, true},
+	}
+	for _, tc := range cases {
+		if got := Decide(tc.results).Passed; got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+	if verdict := Decide([]RefereeResult{referee(true), referee(false), gate(true)}); verdict.PassedReferees != 1 || verdict.TotalReferees != 2 || !verdict.CounterfactualRan {
+		t.Errorf("expected the gate kept out of the referee counts, got %+v", verdict)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math"
 	"sort"
+
+	"gohypo/models"
 )
 
 // ChowTest implements structural stability testing via Chow breakpoint test
@@ -39,12 +41,14 @@ func (c *ChowTest) Execute(x, y []float64, metadata map[string]interface{}) Refe
 
 	// Supremum Wald: Find maximum F-statistic across all possible breakpoints
 	maxFStat := 0.0
+	breakpoint := 0
 	trim := int(float64(len(x)) * c.TrimFraction)
 
 	for k := trim; k < len(x)-trim; k++ {
 		fStat, _ := c.computeChowStatistic(x, y, float64(k)/float64(len(x)), timeVar)
 		if fStat > maxFStat {
 			maxFStat = fStat
+			breakpoint = k
 		}
 	}
 
@@ -63,6 +67,13 @@ func (c *ChowTest) Execute(x, y []float64, metadata map[string]interface{}) Refe
 		}
 	}
 
+	var evidence []interface{}
+	if !passed && len(timeVar) == 0 && breakpoint > 0 {
+		if counterexample, ok := breakpointCounterexample(x, y, breakpoint); ok {
+			evidence = append(evidence, counterexample)
+		}
+	}
+
 	return RefereeResult{
 		GateName:  "Chow_Stability_Test",
 		Passed:    passed,
@@ -70,8 +81,47 @@ func (c *ChowTest) Execute(x, y []float64, metadata map[string]interface{}) Refe
 		PValue:    pValue,
 		StandardUsed: fmt.Sprintf("Supremum Wald F < %.2f (α=%.3f, Trim=%.0f%%)",
 			c.FCritical, c.AlphaCritical, c.TrimFraction*100),
-		FailureReason: failureReason,
+		FailureReason:  failureReason,
+		EvidenceBlocks: evidence,
+	}
+}
+
+// breakpointCounterexample compares the effect before and after the strongest break, in row
+// order, and reports the side where it is weaker as the window where it disappears
+func breakpointCounterexample(x, y []float64, breakpoint int) (models.Counterexample, bool) {
+	baseline, _ := completeCorrelation(x, y, nil)
+	before, after := make([]int, 0, breakpoint), make([]int, 0, len(x)-breakpoint)
+	for i := range x {
+		if i < breakpoint {
+			before = append(before, i)
+		} else {
+			after = append(after, i)
+		}
+	}
+	rBefore, nBefore := completeCorrelation(x, y, before)
+	rAfter, nAfter := completeCorrelation(x, y, after)
+	if math.IsNaN(baseline) || math.IsNaN(rBefore) || math.IsNaN(rAfter) {
+		return models.Counterexample{}, false
+	}
+	direction := 1.0
+	if baseline < 0 {
+		direction = -1
+	}
+	c := models.Counterexample{
+		Kind:     models.CounterexampleTimeWindow,
+		Source:   "Chow_Stability_Test",
+		Variable: "row order",
+		From:     float64(breakpoint),
+		To:       float64(len(x) - 1),
+		Effect:   rAfter,
+		Baseline: baseline,
+		Rows:     nAfter,
+	}
+	if rBefore*direction < rAfter*direction {
+		c.From, c.To, c.Effect, c.Rows = 0, float64(breakpoint-1), rBefore, nBefore
 	}
+	c.Summary = fmt.Sprintf("The relationship breaks at row %d: r = %.2f before and %.2f after, so it fades over rows %.0f to %.0f.", breakpoint, rBefore, rAfter, c.From, c.To)
+	return c, true
 }
 
 // AuditEvidence performs evidence auditing for Chow stability test using discovery q-values
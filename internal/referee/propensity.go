@@ -0,0 +1,367 @@
+package referee
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+)
+
+const (
+	// propensityCaliperSD is the matching caliper in standard deviations of the logit
+	// propensity score (Austin 2011)
+	propensityCaliperSD = 0.2
+
+	// propensityRidge keeps the logistic fit finite under separation
+	propensityRidge = 1e-3
+
+	// propensityMaxIterations bounds the Newton-Raphson fit
+	propensityMaxIterations = 25
+
+	// confoundingShiftThreshold flags confounding when matching moves the effect by more than
+	// this fraction of the naive estimate
+	confoundingShiftThreshold = 0.3
+
+	// minMatchedPairs is the smallest matched sample whose effect is reported as usable
+	minMatchedPairs = 5
+)
+
+// CovariateBalance is the standardized mean difference of one covariate between treated and
+// control rows, before and after matching. |SMD| < 0.1 is conventionally balanced.
+type CovariateBalance struct {
+	Name      string  `json:"name"`
+	SMDBefore float64 `json:"smd_before"`
+	SMDAfter  float64 `json:"smd_after"`
+}
+
+// PropensityMatchReport compares the effect of a binary cause on the full sample with the
+// effect on a propensity-matched sample. A large shift means the raw contrast was confounded
+// by the covariates.
+type PropensityMatchReport struct {
+	CauseKey      string             `json:"cause_key"`
+	EffectKey     string             `json:"effect_key"`
+	Treated       int                `json:"treated"`
+	Control       int                `json:"control"`
+	MatchedPairs  int                `json:"matched_pairs"`
+	Caliper       float64            `json:"caliper"`        // on the logit propensity scale
+	NaiveEffect   float64            `json:"naive_effect"`   // mean(Y|treated) - mean(Y|control), all rows
+	MatchedEffect float64            `json:"matched_effect"` // mean over matched pairs of Y_treated - Y_control
+	EffectShift   float64            `json:"effect_shift"`   // (matched - naive) / |naive|
+	SignFlipped   bool               `json:"sign_flipped"`
+	Confounded    bool               `json:"confounded"`
+	Balance       []CovariateBalance `json:"balance"`
+	Adjusted      []string           `json:"adjusted_for"`
+}
+
+// PropensityMatching estimates propensity scores for a binary cause from covariates by
+// logistic regression and re-estimates the effect on 1:1 nearest-neighbour matches
+type PropensityMatching struct{}
+
+// IsBinary reports whether a column takes exactly two distinct non-missing values
+func IsBinary(data []float64) bool {
+	var values []float64
+	for _, v := range data {
+		if math.IsNaN(v) {
+			continue
+		}
+		if len(values) == 0 || (v != values[0] && (len(values) == 1 || v != values[1])) {
+			values = append(values, v)
+			if len(values) > 2 {
+				return false
+			}
+		}
+	}
+	return len(values) == 2
+}
+
+// MatchOnBundle runs the matching stage on a resolved matrix. The cause must be binary; the
+// larger of its two values marks treated rows. Every other varying column (up to
+// maxInterventionCovariates) enters the propensity model, and only complete cases are used.
+func (pm *PropensityMatching) MatchOnBundle(bundle *dataset.MatrixBundle, cause, effect core.VariableKey) (*PropensityMatchReport, error) {
+	if bundle == nil {
+		return nil, fmt.Errorf("no matrix bundle")
+	}
+	x, okX := bundle.GetColumnData(cause)
+	y, okY := bundle.GetColumnData(effect)
+	if !okX || !okY {
+		return nil, fmt.Errorf("matrix is missing %s or %s", cause, effect)
+	}
+	if !IsBinary(x) {
+		return nil, fmt.Errorf("cause %s is not binary", cause)
+	}
+
+	var names []string
+	var covariates [][]float64
+	for _, key := range bundle.Matrix.VariableKeys {
+		if key == cause || key == effect || len(covariates) >= maxInterventionCovariates {
+			continue
+		}
+		if column, ok := bundle.GetColumnData(key); ok && hasVariance(column) {
+			names = append(names, string(key))
+			covariates = append(covariates, column)
+		}
+	}
+	if len(covariates) == 0 {
+		return nil, fmt.Errorf("no covariates to match on")
+	}
+
+	treatedValue := math.Inf(-1)
+	for _, v := range x {
+		if !math.IsNaN(v) && v > treatedValue {
+			treatedValue = v
+		}
+	}
+
+	var treatment []bool
+	var outcome []float64
+	rows := make([][]float64, 0, len(x))
+	for i := range x {
+		if i >= len(y) || math.IsNaN(x[i]) || math.IsNaN(y[i]) {
+			continue
+		}
+		row := make([]float64, len(covariates))
+		complete := true
+		for j, column := range covariates {
+			if i >= len(column) || math.IsNaN(column[i]) {
+				complete = false
+				break
+			}
+			row[j] = column[i]
+		}
+		if !complete {
+			continue
+		}
+		treatment = append(treatment, x[i] == treatedValue)
+		outcome = append(outcome, y[i])
+		rows = append(rows, row)
+	}
+
+	report, err := pm.Match(treatment, outcome, rows, names)
+	if err != nil {
+		return nil, err
+	}
+	report.CauseKey = string(cause)
+	report.EffectKey = string(effect)
+	return report, nil
+}
+
+// Match fits the propensity model on rows (one covariate vector per unit), matches each
+// treated unit to its nearest control on the logit score without replacement, and compares
+// the naive and matched effects. Treated units are matched in descending score order, the
+// hardest to match first, so the result is deterministic.
+func (pm *PropensityMatching) Match(treatment []bool, outcome []float64, rows [][]float64, names []string) (*PropensityMatchReport, error) {
+	report := &PropensityMatchReport{Adjusted: names}
+	var treatedIdx, controlIdx []int
+	for i, t := range treatment {
+		if t {
+			treatedIdx = append(treatedIdx, i)
+		} else {
+			controlIdx = append(controlIdx, i)
+		}
+	}
+	report.Treated, report.Control = len(treatedIdx), len(controlIdx)
+	if len(treatedIdx) < minMatchedPairs || len(controlIdx) < minMatchedPairs {
+		return nil, fmt.Errorf("INSUFFICIENT DATA: %d treated and %d control rows, matching needs at least %d of each",
+			len(treatedIdx), len(controlIdx), minMatchedPairs)
+	}
+
+	logits := fitPropensityLogits(treatment, rows)
+	_, logitSD := meanStd(logits)
+	report.Caliper = propensityCaliperSD * logitSD
+	if report.Caliper == 0 {
+		report.Caliper = math.Inf(1)
+	}
+
+	sort.SliceStable(treatedIdx, func(a, b int) bool { return logits[treatedIdx[a]] > logits[treatedIdx[b]] })
+	used := make([]bool, len(treatment))
+	var matchedTreated, matchedControl []int
+	for _, t := range treatedIdx {
+		best, bestDist := -1, math.Inf(1)
+		for _, c := range controlIdx {
+			if used[c] {
+				continue
+			}
+			if dist := math.Abs(logits[t] - logits[c]); dist < bestDist {
+				best, bestDist = c, dist
+			}
+		}
+		if best < 0 || bestDist > report.Caliper {
+			continue
+		}
+		used[best] = true
+		matchedTreated = append(matchedTreated, t)
+		matchedControl = append(matchedControl, best)
+	}
+	report.MatchedPairs = len(matchedTreated)
+	if report.MatchedPairs < minMatchedPairs {
+		return nil, fmt.Errorf("POOR OVERLAP: only %d treated rows found a control within the caliper", report.MatchedPairs)
+	}
+
+	report.NaiveEffect = meanAt(outcome, treatedIdx) - meanAt(outcome, controlIdx)
+	report.MatchedEffect = meanAt(outcome, matchedTreated) - meanAt(outcome, matchedControl)
+	if report.NaiveEffect != 0 {
+		report.EffectShift = (report.MatchedEffect - report.NaiveEffect) / math.Abs(report.NaiveEffect)
+	}
+	report.SignFlipped = report.NaiveEffect*report.MatchedEffect < 0
+	report.Confounded = report.SignFlipped || math.Abs(report.EffectShift) > confoundingShiftThreshold
+
+	for j, name := range names {
+		column := make([]float64, len(rows))
+		for i, row := range rows {
+			column[i] = row[j]
+		}
+		report.Balance = append(report.Balance, CovariateBalance{
+			Name:      name,
+			SMDBefore: standardizedMeanDifference(column, treatedIdx, controlIdx),
+			SMDAfter:  standardizedMeanDifference(column, matchedTreated, matchedControl),
+		})
+	}
+	return report, nil
+}
+
+// fitPropensityLogits fits P(treated | covariates) by ridge-penalised Newton-Raphson on
+// standardized covariates and returns each row's linear predictor
+func fitPropensityLogits(treatment []bool, rows [][]float64) []float64 {
+	n, k := len(rows), len(rows[0])
+	design := make([][]float64, n)
+	for i := range design {
+		design[i] = make([]float64, k+1)
+		design[i][0] = 1
+	}
+	for j := 0; j < k; j++ {
+		column := make([]float64, n)
+		for i := range rows {
+			column[i] = rows[i][j]
+		}
+		mean, sd := meanStd(column)
+		if sd == 0 {
+			sd = 1
+		}
+		for i := range rows {
+			design[i][j+1] = (rows[i][j] - mean) / sd
+		}
+	}
+
+	beta := make([]float64, k+1)
+	logits := make([]float64, n)
+	for iter := 0; iter < propensityMaxIterations; iter++ {
+		gradient := make([]float64, k+1)
+		hessian := make([][]float64, k+1)
+		for a := range hessian {
+			hessian[a] = make([]float64, k+1)
+			hessian[a][a] = propensityRidge
+			gradient[a] = -propensityRidge * beta[a]
+		}
+		for i, row := range design {
+			eta := 0.0
+			for a, v := range row {
+				eta += beta[a] * v
+			}
+			p := 1 / (1 + math.Exp(-eta))
+			target := 0.0
+			if treatment[i] {
+				target = 1
+			}
+			w := p * (1 - p)
+			for a, va := range row {
+				gradient[a] += (target - p) * va
+				for b, vb := range row {
+					hessian[a][b] += w * va * vb
+				}
+			}
+		}
+		step, ok := choleskySolve(hessian, gradient)
+		if !ok {
+			break
+		}
+		change := 0.0
+		for a := range beta {
+			beta[a] += step[a]
+			change = math.Max(change, math.Abs(step[a]))
+		}
+		if change < 1e-8 {
+			break
+		}
+	}
+
+	for i, row := range design {
+		for a, v := range row {
+			logits[i] += beta[a] * v
+		}
+	}
+	return logits
+}
+
+// choleskySolve solves A·x = b for a symmetric positive-definite A
+func choleskySolve(A [][]float64, b []float64) ([]float64, bool) {
+	n := len(A)
+	L := make([][]float64, n)
+	for i := range L {
+		L[i] = make([]float64, n)
+		for j := 0; j <= i; j++ {
+			sum := A[i][j]
+			for k := 0; k < j; k++ {
+				sum -= L[i][k] * L[j][k]
+			}
+			if i == j {
+				if sum <= 0 {
+					return nil, false
+				}
+				L[i][i] = math.Sqrt(sum)
+			} else {
+				L[i][j] = sum / L[j][j]
+			}
+		}
+	}
+	z := make([]float64, n)
+	for i := 0; i < n; i++ {
+		z[i] = b[i]
+		for k := 0; k < i; k++ {
+			z[i] -= L[i][k] * z[k]
+		}
+		z[i] /= L[i][i]
+	}
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		x[i] = z[i]
+		for k := i + 1; k < n; k++ {
+			x[i] -= L[k][i] * x[k]
+		}
+		x[i] /= L[i][i]
+	}
+	return x, true
+}
+
+// meanAt averages data over the given row indices
+func meanAt(data []float64, idx []int) float64 {
+	if len(idx) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, i := range idx {
+		sum += data[i]
+	}
+	return sum / float64(len(idx))
+}
+
+// standardizedMeanDifference is (mean_treated - mean_control) / pooled SD
+func standardizedMeanDifference(data []float64, treated, control []int) float64 {
+	variance := func(idx []int, mean float64) float64 {
+		if len(idx) < 2 {
+			return 0
+		}
+		ss := 0.0
+		for _, i := range idx {
+			ss += (data[i] - mean) * (data[i] - mean)
+		}
+		return ss / float64(len(idx)-1)
+	}
+	mt, mc := meanAt(data, treated), meanAt(data, control)
+	pooled := math.Sqrt((variance(treated, mt) + variance(control, mc)) / 2)
+	if pooled == 0 {
+		return 0
+	}
+	return (mt - mc) / pooled
+}
@@ -0,0 +1,58 @@
+package referee
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestPropensityMatchingDetectsConfounding verifies matching removes an effect that is
+// entirely driven by a covariate, and keeps a causal one
+func TestPropensityMatchingDetectsConfounding(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	n := 400
+	treatment := make([]bool, n)
+	spurious, causal := make([]float64, n), make([]float64, n)
+	rows := make([][]float64, n)
+	for i := range rows {
+		z := rng.NormFloat64()
+		rows[i] = []float64{z}
+		treatment[i] = z+rng.NormFloat64() > 0
+		spurious[i] = 2*z + 0.5*rng.NormFloat64()
+		causal[i] = spurious[i]
+		if treatment[i] {
+			causal[i] += 3
+		}
+	}
+
+	pm := &PropensityMatching{}
+	report, err := pm.Match(treatment, spurious, rows, []string{"z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.NaiveEffect < 1 || math.Abs(report.MatchedEffect) > 0.5 || !report.Confounded {
+		t.Fatalf("expected a confounded naive effect that vanishes after matching, got %+v", report)
+	}
+	if math.Abs(report.Balance[0].SMDAfter) >= math.Abs(report.Balance[0].SMDBefore) {
+		t.Fatalf("expected matching to improve balance, got %+v", report.Balance[0])
+	}
+
+	report, err = pm.Match(treatment, causal, rows, []string{"z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(report.MatchedEffect-3) > 0.5 {
+		t.Fatalf("expected a matched effect near 3, got %.3f", report.MatchedEffect)
+	}
+}
+
+// TestIsBinary verifies only two-valued columns qualify for matching
+func TestIsBinary(t *testing.T) {
+	nan := math.NaN()
+	if !IsBinary([]float64{0, 1, nan, 1, 0}) {
+		t.Fatal("expected 0/1 with missing values to be binary")
+	}
+	if IsBinary([]float64{0, 1, 2}) || IsBinary([]float64{1, 1, nan}) {
+		t.Fatal("expected three-valued and constant columns not to be binary")
+	}
+}
@@ -82,7 +82,10 @@ func GetRefereeFactory(refereeName string) (Referee, error) {
 
 	// COUNTERFACTUAL Category
 	case "synthetic_intervention", "g_computation":
-		return &SyntheticIntervention{}, nil
+		return &SyntheticIntervention{
+			NumBootstrap:          BOOTSTRAP_SAMPLES,
+			ConfoundingAdjustment: true,
+		}, nil
 
 	// SPECTRAL Category
 	case "wavelet_coherence", "spectral_analysis":
@@ -0,0 +1,427 @@
+package reproduce
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gohypo/domain/core"
+	"gohypo/internal/buildinfo"
+	apperrors "gohypo/internal/errors"
+)
+
+// BundleFormatVersion is the bundle layout version; bundles from a newer layout are refused
+const BundleFormatVersion = 1
+
+// Files of a bundle. The run's own files are kept under bundleRunDir and restored as they
+// were; the other files split its ledger into sections a reviewer can read directly.
+const (
+	BundleIndexName = "bundle.json"
+	bundleRunDir    = "run"
+	runLedgerName   = "ledger.jsonl"
+	runManifestName = "manifest.json"
+)
+
+// Bundle sections, in the order they are written
+const (
+	SectionDataset     = "dataset"     // the dataset snapshot reference; the data itself is not included
+	SectionContracts   = "contracts"   // the pinned config, the seed and each variable's contract
+	SectionResolution  = "resolution"  // how the matrix was resolved: variables kept, dropped and renamed
+	SectionSweep       = "sweep"       // relationships, profiles and the sweep manifest
+	SectionHypotheses  = "hypotheses"  // the research directives the run generated
+	SectionValidation  = "validation"  // the verdict on each hypothesis
+	SectionRun         = "run"         // the run directory as written, restored on import
+	SectionUnsectioned = "unsectioned" // ledger artifacts of a kind no section claims
+)
+
+// resolutionFields are the run manifest fields the resolution section keeps
+var resolutionFields = []string{"dataset", "variables", "dropped", "columns", "time_zone", "bundle_fingerprint", "stages"}
+
+// BundleIndex is the table of contents of an exported run, written first in the tarball
+type BundleIndex struct {
+	FormatVersion int                `json:"format_version"`
+	RunID         string             `json:"run_id"`
+	ExportedAt    time.Time          `json:"exported_at"`
+	Binary        buildinfo.Info     `json:"binary"`
+	Dataset       *DatasetVersion    `json:"dataset,omitempty"`
+	Fingerprints  BundleFingerprints `json:"fingerprints"`
+	Artifacts     map[string]int     `json:"artifacts"` // artifact count by section
+	Files         []BundleFile       `json:"files"`
+}
+
+// BundleFingerprints are the hashes a reviewer compares against another run
+type BundleFingerprints struct {
+	Matrix     string `json:"matrix,omitempty"`     // the resolved matrix, as hashed by the sweep
+	Dataset    string `json:"dataset,omitempty"`    // SHA-256 of the input file
+	Config     string `json:"config,omitempty"`     // SHA-256 of the pinned config, compacted
+	Results    string `json:"results,omitempty"`    // the descriptor's result hash
+	Descriptor string `json:"descriptor,omitempty"` // the descriptor file name under run/
+}
+
+// BundleFile is one file of a bundle with its checksum
+type BundleFile struct {
+	Name    string `json:"name"`
+	Section string `json:"section"`
+	SHA256  string `json:"sha256"`
+	Size    int64  `json:"size"`
+}
+
+// ErrBundleExists is returned when an imported run is already present and overwriting was
+// not asked for
+var ErrBundleExists = apperrors.Conflict("run already exists")
+
+// bundleFile is a file waiting to be written into the tarball
+type bundleFile struct {
+	BundleFile
+	data []byte
+}
+
+// ledgerLine is one line of a file ledger, with the artifact kept as written
+type ledgerLine struct {
+	RunID    string          `json:"run_id"`
+	Artifact json.RawMessage `json:"artifact"`
+}
+
+// ExportBundle writes the run recorded in runDir to w as a gzipped tarball: the run's
+// files as written, its ledger split by section and an index with every file's checksum.
+// The dataset is referenced by content, not included.
+func ExportBundle(w io.Writer, runDir string) (*BundleIndex, error) {
+	runID := filepath.Base(filepath.Clean(runDir))
+	index := &BundleIndex{
+		FormatVersion: BundleFormatVersion,
+		RunID:         runID,
+		ExportedAt:    time.Now().UTC(),
+		Binary:        buildinfo.Get(),
+		Artifacts:     map[string]int{},
+	}
+
+	var files []bundleFile
+	add := func(section, name string, data []byte) {
+		files = append(files, bundleFile{
+			BundleFile: BundleFile{Name: name, Section: section, SHA256: hashBytes(data), Size: int64(len(data))},
+			data:       data,
+		})
+	}
+
+	// The run directory, as written
+	entries, err := os.ReadDir(runDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run directory: %w", err)
+	}
+	runFiles := map[string][]byte{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(runDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+		runFiles[entry.Name()] = data
+	}
+	ledger, ok := runFiles[runLedgerName]
+	if !ok {
+		return nil, apperrors.NotFound(fmt.Sprintf("file ledger %s", filepath.Join(runDir, runLedgerName)))
+	}
+
+	sections, inputs, err := splitLedger(ledger)
+	if err != nil {
+		return nil, err
+	}
+	if inputs != nil {
+		index.Dataset = &inputs.Dataset
+		index.Fingerprints.Dataset = inputs.Dataset.SHA256
+		index.Fingerprints.Config = hashJSON(inputs.Config)
+		dataset, err := encodeSection(inputs.Dataset)
+		if err != nil {
+			return nil, err
+		}
+		add(SectionDataset, SectionDataset+".json", dataset)
+		contracts, err := encodeSection(inputs)
+		if err != nil {
+			return nil, err
+		}
+		add(SectionContracts, SectionContracts+".json", contracts)
+	}
+	if manifest, ok := runFiles[runManifestName]; ok {
+		resolution, fingerprint, err := resolutionSection(manifest)
+		if err != nil {
+			return nil, err
+		}
+		index.Fingerprints.Matrix = fingerprint
+		add(SectionResolution, SectionResolution+".json", resolution)
+	}
+	for _, section := range []string{SectionSweep, SectionHypotheses, SectionValidation, SectionUnsectioned} {
+		lines := sections[section]
+		if len(lines) == 0 {
+			continue
+		}
+		index.Artifacts[section] = len(lines)
+		add(section, section+".jsonl", append(bytes.Join(lines, []byte("\n")), '\n'))
+	}
+
+	names := make([]string, 0, len(runFiles))
+	for name := range runFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add(SectionRun, path.Join(bundleRunDir, name), runFiles[name])
+		if name == runID+".json" {
+			if descriptor, err := parseDescriptor(runFiles[name]); err == nil {
+				index.Fingerprints.Results = descriptor.ResultHash
+				index.Fingerprints.Descriptor = name
+			}
+		}
+	}
+
+	for _, file := range files {
+		index.Files = append(index.Files, file.BundleFile)
+	}
+	indexJSON, err := encodeSection(index)
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	write := func(name string, data []byte) error {
+		header := &tar.Header{
+			Name:    path.Join(runID, name),
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: index.ExportedAt,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		return nil
+	}
+	if err := write(BundleIndexName, indexJSON); err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if err := write(file.Name, file.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return index, nil
+}
+
+// ImportBundle reads a bundle written by ExportBundle, checks every file against the
+// index and restores the run directory under runsDir. An existing run is only replaced
+// when overwrite is set.
+func ImportBundle(r io.Reader, runsDir string, overwrite bool) (*BundleIndex, string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, "", apperrors.InvalidInput(fmt.Sprintf("not a run bundle: %v", err))
+	}
+	defer gz.Close()
+
+	var index *BundleIndex
+	contents := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name, err := bundleEntryName(header.Name, index)
+		if err != nil {
+			return nil, "", err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		if index == nil {
+			// The index comes first, so every later entry is checked against its run
+			if name != BundleIndexName {
+				return nil, "", apperrors.InvalidInput("not a run bundle: " + BundleIndexName + " must come first")
+			}
+			index = &BundleIndex{}
+			if err := json.Unmarshal(data, index); err != nil {
+				return nil, "", apperrors.InvalidInput(fmt.Sprintf("failed to parse %s: %v", BundleIndexName, err))
+			}
+			if index.FormatVersion > BundleFormatVersion {
+				return nil, "", apperrors.InvalidInput(fmt.Sprintf("bundle format %d is newer than this build understands (%d)", index.FormatVersion, BundleFormatVersion))
+			}
+			if index.RunID == "" || index.RunID != path.Base(index.RunID) || index.RunID == ".." || header.Name != path.Join(index.RunID, BundleIndexName) {
+				return nil, "", apperrors.InvalidInput(fmt.Sprintf("bundle names an invalid run %q", index.RunID))
+			}
+			continue
+		}
+		contents[name] = data
+	}
+	if index == nil {
+		return nil, "", apperrors.InvalidInput("not a run bundle: it is empty")
+	}
+
+	for _, file := range index.Files {
+		data, ok := contents[file.Name]
+		if !ok {
+			return nil, "", fmt.Errorf("%w: bundle is missing %s", ErrNotReproducible, file.Name)
+		}
+		if sum := hashBytes(data); sum != file.SHA256 {
+			return nil, "", fmt.Errorf("%w: %s does not match its checksum (sha256 %s, recorded %s)",
+				ErrNotReproducible, file.Name, short(sum), short(file.SHA256))
+		}
+	}
+
+	runDir := filepath.Join(runsDir, index.RunID)
+	if _, err := os.Stat(runDir); err == nil {
+		if !overwrite {
+			return nil, "", fmt.Errorf("%w: %s", ErrBundleExists, runDir)
+		}
+		if err := os.RemoveAll(runDir); err != nil {
+			return nil, "", fmt.Errorf("failed to replace %s: %w", runDir, err)
+		}
+	}
+	if err := os.MkdirAll(runDir, 0o755); err != nil {
+		return nil, "", fmt.Errorf("failed to create run directory: %w", err)
+	}
+	for _, file := range index.Files {
+		if file.Section != SectionRun {
+			continue
+		}
+		target := filepath.Join(runDir, path.Base(file.Name))
+		if err := os.WriteFile(target, contents[file.Name], 0o644); err != nil {
+			return nil, "", fmt.Errorf("failed to write %s: %w", target, err)
+		}
+	}
+	return index, runDir, nil
+}
+
+// bundleEntryName strips the run directory from a tarball entry name, refusing names that
+// would leave it
+func bundleEntryName(name string, index *BundleIndex) (string, error) {
+	clean := path.Clean(name)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", apperrors.InvalidInput(fmt.Sprintf("bundle entry %q leaves the bundle", name))
+	}
+	dir, rest, ok := strings.Cut(clean, "/")
+	if !ok || (index != nil && dir != index.RunID) {
+		return "", apperrors.InvalidInput(fmt.Sprintf("bundle entry %q is outside the run", name))
+	}
+	return rest, nil
+}
+
+// splitLedger sorts a file ledger's artifacts into bundle sections, keeping each as
+// written, and decodes the run inputs when the run recorded them
+func splitLedger(ledger []byte) (map[string][][]byte, *RunInputs, error) {
+	sections := map[string][][]byte{}
+	var inputs *RunInputs
+	scanner := bufio.NewScanner(bytes.NewReader(ledger))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var line ledgerLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode ledger: %w", err)
+		}
+		var artifact struct {
+			Kind    core.ArtifactKind `json:"kind"`
+			Payload json.RawMessage   `json:"payload"`
+		}
+		if err := json.Unmarshal(line.Artifact, &artifact); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode ledger artifact: %w", err)
+		}
+		if artifact.Kind == core.ArtifactRunInputs {
+			inputs = &RunInputs{}
+			if err := json.Unmarshal(artifact.Payload, inputs); err != nil {
+				return nil, nil, fmt.Errorf("failed to decode run inputs: %w", err)
+			}
+			continue
+		}
+		section := sectionOf(artifact.Kind)
+		sections[section] = append(sections[section], append([]byte(nil), line.Artifact...))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read ledger: %w", err)
+	}
+	return sections, inputs, nil
+}
+
+// sectionOf is the bundle section an artifact kind belongs to
+func sectionOf(kind core.ArtifactKind) string {
+	switch kind {
+	// The stats sweep stores its relationships as "association" artifacts
+	case "association", core.ArtifactRelationship, core.ArtifactSkippedRelationship, core.ArtifactSweepManifest,
+		core.ArtifactVariableProfile, core.ArtifactVariableHealth, core.ArtifactFDRFamily, core.ArtifactNoiseFloor:
+		return SectionSweep
+	case core.ArtifactResearchDirective:
+		return SectionHypotheses
+	case core.ArtifactHypothesis:
+		return SectionValidation
+	}
+	return SectionUnsectioned
+}
+
+// resolutionSection keeps the resolution fields of a run manifest and returns the matrix
+// fingerprint it recorded
+func resolutionSection(manifest []byte) ([]byte, string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(manifest, &fields); err != nil {
+		return nil, "", fmt.Errorf("failed to decode run manifest: %w", err)
+	}
+	resolution := make(map[string]json.RawMessage, len(resolutionFields))
+	for _, field := range resolutionFields {
+		if value, ok := fields[field]; ok {
+			resolution[field] = value
+		}
+	}
+	var fingerprint string
+	if value, ok := fields["bundle_fingerprint"]; ok {
+		json.Unmarshal(value, &fingerprint)
+	}
+	data, err := encodeSection(resolution)
+	return data, fingerprint, err
+}
+
+// parseDescriptor decodes a descriptor held in memory
+func parseDescriptor(data []byte) (*Descriptor, error) {
+	var d Descriptor
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	if d.RunID == "" {
+		return nil, fmt.Errorf("not a descriptor")
+	}
+	return &d, nil
+}
+
+// encodeSection encodes a section as indented JSON
+func encodeSection(v interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundle section: %w", err)
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,142 @@
+package reproduce
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gohypo/domain/core"
+)
+
+// writeRun writes a run directory as `gohypo-cli run` leaves it: a file ledger and a manifest
+func writeRun(t *testing.T, runsDir, runID string) string {
+	t.Helper()
+	runDir := filepath.Join(runsDir, runID)
+	if err := os.MkdirAll(runDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Unix(100, 0)
+	artifacts := []core.Artifact{
+		{ID: core.ID(core.ArtifactRunInputs), Kind: core.ArtifactRunInputs, CreatedAt: core.NewTimestamp(at), Payload: RunInputs{
+			Dataset:   DatasetVersion{Name: "orders.csv", Path: "/data/orders.csv", SHA256: "abc123", Size: 42},
+			Config:    json.RawMessage(`{"seed": 7}`),
+			Seed:      7,
+			Contracts: []VariableContract{{VariableKey: "basket_size", StatisticalType: "numeric"}},
+		}},
+		relationship("corr_a_b", 0.01, at),
+		{ID: "h1", Kind: core.ArtifactResearchDirective, Payload: map[string]interface{}{"claim": "a drives b"}, CreatedAt: core.NewTimestamp(at)},
+		{ID: "verdict_h1", Kind: core.ArtifactHypothesis, Payload: map[string]interface{}{"passed": true}, CreatedAt: core.NewTimestamp(at)},
+	}
+	var ledger bytes.Buffer
+	for _, artifact := range artifacts {
+		line, err := json.Marshal(map[string]interface{}{"run_id": runID, "artifact": artifact})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ledger.Write(append(line, '\n'))
+	}
+	files := map[string]string{
+		"ledger.jsonl":  ledger.String(),
+		"manifest.json": `{"run_id": "` + runID + `", "variables": ["a", "b"], "dropped": ["c"], "bundle_fingerprint": "fp-1", "relationships": 1}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(runDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return runDir
+}
+
+// TestBundleRoundTrip verifies an exported run is restored byte for byte, with its ledger
+// split by section and its fingerprints in the index
+func TestBundleRoundTrip(t *testing.T) {
+	runDir := writeRun(t, t.TempDir(), "20260101T000000Z-orders")
+	var bundle bytes.Buffer
+	exported, err := ExportBundle(&bundle, runDir)
+	if err != nil {
+		t.Fatalf("ExportBundle: %v", err)
+	}
+	if exported.Fingerprints.Matrix != "fp-1" || exported.Fingerprints.Dataset != "abc123" || exported.Fingerprints.Config == "" {
+		t.Errorf("unexpected fingerprints %+v", exported.Fingerprints)
+	}
+	for section, want := range map[string]int{SectionSweep: 1, SectionHypotheses: 1, SectionValidation: 1} {
+		if exported.Artifacts[section] != want {
+			t.Errorf("%s section has %d artifacts, want %d", section, exported.Artifacts[section], want)
+		}
+	}
+
+	runsDir := t.TempDir()
+	imported, restored, err := ImportBundle(bytes.NewReader(bundle.Bytes()), runsDir, false)
+	if err != nil {
+		t.Fatalf("ImportBundle: %v", err)
+	}
+	if imported.RunID != exported.RunID || restored != filepath.Join(runsDir, exported.RunID) {
+		t.Fatalf("restored %s as %s", imported.RunID, restored)
+	}
+	for _, name := range []string{"ledger.jsonl", "manifest.json"} {
+		original, _ := os.ReadFile(filepath.Join(runDir, name))
+		copied, err := os.ReadFile(filepath.Join(restored, name))
+		if err != nil || !bytes.Equal(original, copied) {
+			t.Errorf("%s was not restored as written (%v)", name, err)
+		}
+	}
+
+	if _, _, err := ImportBundle(bytes.NewReader(bundle.Bytes()), runsDir, false); !errors.Is(err, ErrBundleExists) {
+		t.Errorf("expected importing over an existing run to fail, got %v", err)
+	}
+	if _, _, err := ImportBundle(bytes.NewReader(bundle.Bytes()), runsDir, true); err != nil {
+		t.Errorf("expected --force to replace the run: %v", err)
+	}
+}
+
+// TestImportBundleRejectsTampering verifies a file that no longer matches its checksum is
+// refused before anything is written
+func TestImportBundleRejectsTampering(t *testing.T) {
+	runDir := writeRun(t, t.TempDir(), "20260101T000000Z-orders")
+	var bundle bytes.Buffer
+	if _, err := ExportBundle(&bundle, runDir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rewrite the bundle with one byte of the ledger changed
+	gz, err := gzip.NewReader(&bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tampered bytes.Buffer
+	gzw := gzip.NewWriter(&tampered)
+	tw := tar.NewWriter(gzw)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		if filepath.Base(header.Name) == "ledger.jsonl" {
+			data = bytes.Replace(data, []byte("0.01"), []byte("0.02"), 1)
+		}
+		tw.WriteHeader(header)
+		tw.Write(data)
+	}
+	tw.Close()
+	gzw.Close()
+
+	runsDir := t.TempDir()
+	if _, _, err := ImportBundle(&tampered, runsDir, false); !errors.Is(err, ErrNotReproducible) {
+		t.Fatalf("expected a checksum failure, got %v", err)
+	}
+	if entries, _ := os.ReadDir(runsDir); len(entries) != 0 {
+		t.Errorf("a rejected bundle should write nothing, found %d entries", len(entries))
+	}
+}
@@ -0,0 +1,300 @@
+// Package reproduce records the environment a run was produced in and checks whether the
+// current environment can reproduce it before the run is replayed.
+package reproduce
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gohypo/internal/buildinfo"
+	apperrors "gohypo/internal/errors"
+)
+
+// FormatVersion is the descriptor layout version; descriptors from a newer layout are refused
+const FormatVersion = 1
+
+// Descriptor pins everything a run depended on: the binary and its method versions, the
+// run configuration, the exact dataset versions and the seeds
+type Descriptor struct {
+	FormatVersion int              `json:"format_version"`
+	RunID         string           `json:"run_id"`
+	CreatedAt     time.Time        `json:"created_at"`
+	Binary        buildinfo.Info   `json:"binary"`
+	ConfigHash    string           `json:"config_hash"` // SHA-256 of Config, compacted
+	Config        json.RawMessage  `json:"config"`
+	Datasets      []DatasetVersion `json:"datasets"`
+	Seeds         map[string]int64 `json:"seeds"`
+	Results       []Result         `json:"results"`
+	ResultHash    string           `json:"result_hash"` // SHA-256 of Results
+}
+
+// DatasetVersion identifies one input file by content
+type DatasetVersion struct {
+	Name              string `json:"name"`
+	Path              string `json:"path"`
+	SHA256            string `json:"sha256"`
+	Size              int64  `json:"size"`
+	BundleFingerprint string `json:"bundle_fingerprint,omitempty"` // the resolved matrix, as hashed by the sweep
+}
+
+// Result is one relationship a run reported
+type Result struct {
+	VariableX  string  `json:"variable_x"`
+	VariableY  string  `json:"variable_y"`
+	EffectSize float64 `json:"effect_size"`
+	PValue     float64 `json:"p_value"`
+	QValue     float64 `json:"q_value"`
+	SampleSize int     `json:"sample_size"`
+}
+
+// Severity of a check finding
+type Severity string
+
+const (
+	SeverityOK       Severity = "ok"
+	SeverityWarning  Severity = "warning"  // the replay may differ in ways that do not change results
+	SeverityBlocking Severity = "blocking" // the replay cannot match the run
+)
+
+// Finding is the outcome of one environment check
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Detail   string   `json:"detail"`
+}
+
+// ErrNotReproducible is returned when a blocking check fails or a replay differs
+var ErrNotReproducible = apperrors.DeterminismViolation("run cannot be reproduced")
+
+// New creates a descriptor for a run of the current binary. config is stored as given and
+// hashed; results are hashed in the order given.
+func New(runID string, config interface{}, datasets []DatasetVersion, seeds map[string]int64, results []Result) (*Descriptor, error) {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode run config: %w", err)
+	}
+	return &Descriptor{
+		FormatVersion: FormatVersion,
+		RunID:         runID,
+		CreatedAt:     time.Now().UTC(),
+		Binary:        buildinfo.Get(),
+		ConfigHash:    hashJSON(configJSON),
+		Config:        configJSON,
+		Datasets:      datasets,
+		Seeds:         seeds,
+		Results:       results,
+		ResultHash:    HashResults(results),
+	}, nil
+}
+
+// DescribeDataset hashes a dataset file, recording its absolute path
+func DescribeDataset(path, bundleFingerprint string) (DatasetVersion, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	sum, size, err := hashFile(abs)
+	if err != nil {
+		return DatasetVersion{}, err
+	}
+	return DatasetVersion{
+		Name:              filepath.Base(abs),
+		Path:              abs,
+		SHA256:            sum,
+		Size:              size,
+		BundleFingerprint: bundleFingerprint,
+	}, nil
+}
+
+// HashResults hashes results at full float precision, so any numeric drift shows
+func HashResults(results []Result) string {
+	var b strings.Builder
+	for _, r := range results {
+		fmt.Fprintf(&b, "%s|%s|%.17g|%.17g|%.17g|%d\n", r.VariableX, r.VariableY, r.EffectSize, r.PValue, r.QValue, r.SampleSize)
+	}
+	return hashBytes([]byte(b.String()))
+}
+
+// Write stores the descriptor as indented JSON
+func (d *Descriptor) Write(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create descriptor directory: %w", err)
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode descriptor: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write descriptor: %w", err)
+	}
+	return nil
+}
+
+// Read loads a descriptor
+func Read(path string) (*Descriptor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read descriptor: %w", err)
+	}
+	var d Descriptor
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, &apperrors.AppError{Code: apperrors.CodeInvalidInput, Message: fmt.Sprintf("failed to parse descriptor %s", path), Cause: err}
+	}
+	return &d, nil
+}
+
+// Check compares the descriptor with the current binary and the dataset files on disk.
+// Datasets missing from their recorded path are looked for next to the descriptor, in
+// searchDir, so a descriptor can travel with its data. Method versions that differ in
+// major version, changed or missing datasets and a config that does not match its hash
+// block the replay; other differences are warnings.
+func (d *Descriptor) Check(current buildinfo.Info, searchDir string) []Finding {
+	var findings []Finding
+	add := func(check string, severity Severity, detail string, args ...interface{}) {
+		findings = append(findings, Finding{Check: check, Severity: severity, Detail: fmt.Sprintf(detail, args...)})
+	}
+
+	if d.FormatVersion > FormatVersion {
+		add("descriptor", SeverityBlocking, "format %d is newer than this build understands (%d)", d.FormatVersion, FormatVersion)
+		return findings
+	}
+	if hashJSON(d.Config) != d.ConfigHash {
+		add("config", SeverityBlocking, "config does not match its recorded hash")
+	} else {
+		add("config", SeverityOK, "hash %s", short(d.ConfigHash))
+	}
+	if HashResults(d.Results) != d.ResultHash {
+		add("results", SeverityBlocking, "recorded results do not match their hash")
+	}
+
+	switch {
+	case d.Binary.Version == current.Version && d.Binary.Commit == current.Commit && !current.Modified:
+		add("binary", SeverityOK, "%s", describeBinary(current))
+	case d.Binary.Commit != "" && d.Binary.Commit == current.Commit:
+		add("binary", SeverityWarning, "same commit, but this build has local modifications")
+	default:
+		add("binary", SeverityWarning, "recorded %s, running %s", describeBinary(d.Binary), describeBinary(current))
+	}
+	if d.Binary.GoVersion != current.GoVersion {
+		add("go", SeverityWarning, "recorded %s, running %s", d.Binary.GoVersion, current.GoVersion)
+	}
+
+	if err := buildinfo.CheckCompatible(d.Binary.MethodVersions); err != nil {
+		add("methods", SeverityBlocking, "%v", err)
+	} else if changed := changedMethods(d.Binary.MethodVersions, current.MethodVersions); len(changed) > 0 {
+		add("methods", SeverityWarning, "compatible version changes: %s", strings.Join(changed, ", "))
+	} else {
+		add("methods", SeverityOK, "%d method versions match", len(d.Binary.MethodVersions))
+	}
+
+	for _, ds := range d.Datasets {
+		path, ok := d.Locate(ds, searchDir)
+		if !ok {
+			add("dataset "+ds.Name, SeverityBlocking, "not found at %s or next to the descriptor", ds.Path)
+			continue
+		}
+		sum, _, err := hashFile(path)
+		switch {
+		case err != nil:
+			add("dataset "+ds.Name, SeverityBlocking, "%v", err)
+		case sum != ds.SHA256:
+			add("dataset "+ds.Name, SeverityBlocking, "content changed since the run (sha256 %s, recorded %s)", short(sum), short(ds.SHA256))
+		default:
+			add("dataset "+ds.Name, SeverityOK, "sha256 %s at %s", short(sum), path)
+		}
+	}
+	return findings
+}
+
+// Locate is where a recorded dataset is found now: its recorded path, or its name inside
+// searchDir
+func (d *Descriptor) Locate(ds DatasetVersion, searchDir string) (string, bool) {
+	return locate(ds, searchDir)
+}
+
+func locate(ds DatasetVersion, searchDir string) (string, bool) {
+	if _, err := os.Stat(ds.Path); err == nil {
+		return ds.Path, true
+	}
+	if searchDir != "" {
+		candidate := filepath.Join(searchDir, ds.Name)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// Blocking reports whether any finding blocks the replay
+func Blocking(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityBlocking {
+			return true
+		}
+	}
+	return false
+}
+
+// changedMethods lists methods whose version differs without breaking compatibility
+func changedMethods(recorded, current map[string]string) []string {
+	var changed []string
+	for name, version := range recorded {
+		if now, ok := current[name]; ok && now != version {
+			changed = append(changed, fmt.Sprintf("%s %s → %s", name, version, now))
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func describeBinary(info buildinfo.Info) string {
+	s := info.Version
+	if info.Commit != "" {
+		s += " (" + short(info.Commit) + ")"
+	}
+	return s
+}
+
+func hashFile(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to hash dataset: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// hashJSON hashes JSON in compact form, so indenting a written descriptor keeps its hash
+func hashJSON(data []byte) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err == nil {
+		data = compact.Bytes()
+	}
+	return hashBytes(data)
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func short(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
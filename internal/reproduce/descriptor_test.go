@@ -0,0 +1,143 @@
+package reproduce
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gohypo/internal/buildinfo"
+)
+
+func writeDataset(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newTestDescriptor(t *testing.T, dataPath string) *Descriptor {
+	t.Helper()
+	dataset, err := DescribeDataset(dataPath, "bundle-fp")
+	if err != nil {
+		t.Fatalf("DescribeDataset: %v", err)
+	}
+	config := map[string]interface{}{"rigor": "standard", "seed": 42}
+	results := []Result{{VariableX: "a", VariableY: "b", EffectSize: 0.5, PValue: 0.01, QValue: 0.02, SampleSize: 100}}
+	d, err := New("run-1", config, []DatasetVersion{dataset}, map[string]int64{"sweep": 42}, results)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return d
+}
+
+func findingFor(findings []Finding, check string) Finding {
+	for _, f := range findings {
+		if f.Check == check {
+			return f
+		}
+	}
+	return Finding{}
+}
+
+// TestDescriptorRoundTrip verifies a written descriptor reads back and passes its checks
+func TestDescriptorRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	d := newTestDescriptor(t, writeDataset(t, dir, "a,b\n1,2\n"))
+
+	path := filepath.Join(dir, "runs", "run-1.json")
+	if err := d.Write(path); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	read, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if read.RunID != "run-1" || read.Seeds["sweep"] != 42 || len(read.Datasets) != 1 {
+		t.Fatalf("unexpected descriptor: %+v", read)
+	}
+
+	findings := read.Check(buildinfo.Get(), filepath.Dir(path))
+	if Blocking(findings) {
+		t.Fatalf("expected no blocking findings, got %+v", findings)
+	}
+	if f := findingFor(findings, "config"); f.Severity != SeverityOK {
+		t.Errorf("indenting the descriptor should keep the config hash: %+v", f)
+	}
+}
+
+// TestCheckBlocksChangedDataset verifies a dataset edited after the run blocks the replay
+func TestCheckBlocksChangedDataset(t *testing.T) {
+	dir := t.TempDir()
+	dataPath := writeDataset(t, dir, "a,b\n1,2\n")
+	d := newTestDescriptor(t, dataPath)
+
+	writeDataset(t, dir, "a,b\n1,3\n")
+	findings := d.Check(buildinfo.Get(), dir)
+	if f := findingFor(findings, "dataset data.csv"); f.Severity != SeverityBlocking || !strings.Contains(f.Detail, "changed") {
+		t.Errorf("expected a blocking dataset finding, got %+v", f)
+	}
+}
+
+// TestCheckFindsDatasetNextToDescriptor verifies moved data is found beside the descriptor
+func TestCheckFindsDatasetNextToDescriptor(t *testing.T) {
+	original := t.TempDir()
+	d := newTestDescriptor(t, writeDataset(t, original, "a,b\n1,2\n"))
+
+	moved := t.TempDir()
+	writeDataset(t, moved, "a,b\n1,2\n")
+	os.Remove(filepath.Join(original, "data.csv"))
+
+	if findings := d.Check(buildinfo.Get(), moved); Blocking(findings) {
+		t.Fatalf("expected the moved dataset to be found, got %+v", findings)
+	}
+	if path, ok := d.Locate(d.Datasets[0], moved); !ok || filepath.Dir(path) != moved {
+		t.Errorf("Locate = %s, %v", path, ok)
+	}
+	if findings := d.Check(buildinfo.Get(), ""); !Blocking(findings) {
+		t.Error("expected a missing dataset to block")
+	}
+}
+
+// TestCheckMethodVersions verifies only incompatible method versions block
+func TestCheckMethodVersions(t *testing.T) {
+	dir := t.TempDir()
+	d := newTestDescriptor(t, writeDataset(t, dir, "a\n1\n"))
+	current := buildinfo.Get()
+
+	d.Binary.MethodVersions["pairwise_correlation"] = "0.9.0"
+	if f := findingFor(d.Check(current, dir), "methods"); f.Severity != SeverityBlocking {
+		t.Errorf("expected a major version change to block, got %+v", f)
+	}
+
+	major := strings.SplitN(current.MethodVersions["pairwise_correlation"], ".", 2)[0]
+	d.Binary.MethodVersions["pairwise_correlation"] = major + ".99.0"
+	if f := findingFor(d.Check(current, dir), "methods"); f.Severity != SeverityWarning {
+		t.Errorf("expected a minor version change to warn, got %+v", f)
+	}
+}
+
+// TestCheckDetectsTampering verifies edited configs and results fail their hashes
+func TestCheckDetectsTampering(t *testing.T) {
+	dir := t.TempDir()
+	d := newTestDescriptor(t, writeDataset(t, dir, "a\n1\n"))
+
+	d.Config = []byte(`{"rigor":"basic","seed":42}`)
+	d.Results[0].EffectSize = 0.6
+	findings := d.Check(buildinfo.Get(), dir)
+	if findingFor(findings, "config").Severity != SeverityBlocking || findingFor(findings, "results").Severity != SeverityBlocking {
+		t.Errorf("expected config and results to block, got %+v", findings)
+	}
+}
+
+// TestCheckRefusesNewerFormat verifies descriptors from a newer layout are refused
+func TestCheckRefusesNewerFormat(t *testing.T) {
+	dir := t.TempDir()
+	d := newTestDescriptor(t, writeDataset(t, dir, "a\n1\n"))
+	d.FormatVersion = FormatVersion + 1
+	if !Blocking(d.Check(buildinfo.Get(), dir)) {
+		t.Error("expected a newer format to block")
+	}
+}
@@ -0,0 +1,191 @@
+package reproduce
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gohypo/domain/core"
+)
+
+// RunInputs is what a run was produced from. It is stored in the run's ledger as a
+// run_inputs artifact, so the run can be replayed from the ledger alone.
+type RunInputs struct {
+	Dataset   DatasetVersion     `json:"dataset"`
+	Config    json.RawMessage    `json:"config"` // the run spec, with its seed, rigor and pinned variables
+	Seed      int64              `json:"seed"`
+	Contracts []VariableContract `json:"contracts"`
+}
+
+// VariableContract is how a run resolved one variable of its matrix
+type VariableContract struct {
+	VariableKey     string `json:"variable_key"`
+	StatisticalType string `json:"statistical_type"`
+	Imputation      string `json:"imputation,omitempty"`
+}
+
+// LocateDataset finds the recorded dataset, at its recorded path or by name inside
+// searchDir, and checks it is the same version
+func (in RunInputs) LocateDataset(searchDir string) (string, error) {
+	path, ok := locate(in.Dataset, searchDir)
+	if !ok {
+		return "", fmt.Errorf("%w: dataset %s not found", ErrNotReproducible, in.Dataset.Name)
+	}
+	sum, _, err := hashFile(path)
+	if err != nil {
+		return "", err
+	}
+	if sum != in.Dataset.SHA256 {
+		return "", fmt.Errorf("%w: %s changed since the run (sha256 %s, recorded %s)",
+			ErrNotReproducible, path, short(sum), short(in.Dataset.SHA256))
+	}
+	return path, nil
+}
+
+// VolatileFields are the artifact fields that record when or where an artifact was written
+// rather than what it holds; DiffArtifacts leaves them out
+var VolatileFields = []string{"created_at", "payload.analysis_timestamp", "payload.dataset.path"}
+
+// ArtifactRef names an artifact in a divergence report
+type ArtifactRef struct {
+	Kind core.ArtifactKind `json:"kind"`
+	ID   core.ID           `json:"id"`
+}
+
+// FieldDiff is one value that differs between a recorded and a replayed artifact, at a
+// JSON path such as payload.p_value or payload.referees[1].p_value
+type FieldDiff struct {
+	Path     string      `json:"path"`
+	Recorded interface{} `json:"recorded"`
+	Replayed interface{} `json:"replayed"`
+}
+
+// ArtifactDiff lists the differing fields of one artifact
+type ArtifactDiff struct {
+	ArtifactRef
+	Fields []FieldDiff `json:"fields"`
+}
+
+// Divergence is how a replay's artifacts differ from the recorded ones
+type Divergence struct {
+	Compared int            `json:"compared"`
+	Missing  []ArtifactRef  `json:"missing,omitempty"` // recorded but not replayed
+	Extra    []ArtifactRef  `json:"extra,omitempty"`   // replayed but not recorded
+	Changed  []ArtifactDiff `json:"changed,omitempty"`
+}
+
+// Empty reports whether the replay matched the recorded artifacts exactly
+func (d *Divergence) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.Changed) == 0
+}
+
+// DiffArtifacts compares every recorded artifact with the replayed artifact of the same
+// kind and ID, field by field as JSON, so numbers are compared at full precision. Fields
+// at the ignored paths are left out.
+func DiffArtifacts(recorded, replayed []core.Artifact, ignore ...string) (*Divergence, error) {
+	skip := make(map[string]bool, len(ignore))
+	for _, path := range ignore {
+		skip[path] = true
+	}
+	before, order, err := indexArtifacts(recorded)
+	if err != nil {
+		return nil, err
+	}
+	after, _, err := indexArtifacts(replayed)
+	if err != nil {
+		return nil, err
+	}
+
+	divergence := &Divergence{}
+	for _, ref := range order {
+		replay, ok := after[ref]
+		if !ok {
+			divergence.Missing = append(divergence.Missing, ref)
+			continue
+		}
+		divergence.Compared++
+		var fields []FieldDiff
+		diffValues("", before[ref], replay, skip, &fields)
+		if len(fields) > 0 {
+			divergence.Changed = append(divergence.Changed, ArtifactDiff{ArtifactRef: ref, Fields: fields})
+		}
+	}
+	for _, artifact := range replayed {
+		ref := ArtifactRef{Kind: artifact.Kind, ID: artifact.ID}
+		if _, ok := before[ref]; !ok {
+			divergence.Extra = append(divergence.Extra, ref)
+		}
+	}
+	return divergence, nil
+}
+
+// indexArtifacts decodes artifacts to generic JSON values by reference, keeping the order
+// they came in
+func indexArtifacts(artifacts []core.Artifact) (map[ArtifactRef]interface{}, []ArtifactRef, error) {
+	index := make(map[ArtifactRef]interface{}, len(artifacts))
+	order := make([]ArtifactRef, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		data, err := json.Marshal(artifact)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode artifact %s: %w", artifact.ID, err)
+		}
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode artifact %s: %w", artifact.ID, err)
+		}
+		ref := ArtifactRef{Kind: artifact.Kind, ID: artifact.ID}
+		if _, seen := index[ref]; !seen {
+			order = append(order, ref)
+		}
+		index[ref] = value
+	}
+	return index, order, nil
+}
+
+// diffValues appends the differences between two decoded JSON values below path
+func diffValues(path string, recorded, replayed interface{}, skip map[string]bool, diffs *[]FieldDiff) {
+	if skip[path] {
+		return
+	}
+	switch before := recorded.(type) {
+	case map[string]interface{}:
+		after, ok := replayed.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(before)+len(after))
+		for key := range before {
+			keys = append(keys, key)
+		}
+		for key := range after {
+			if _, ok := before[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			diffValues(joinPath(path, key), before[key], after[key], skip, diffs)
+		}
+		return
+	case []interface{}:
+		after, ok := replayed.([]interface{})
+		if !ok || len(after) != len(before) {
+			break
+		}
+		for i := range before {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), before[i], after[i], skip, diffs)
+		}
+		return
+	}
+	if !reflect.DeepEqual(recorded, replayed) {
+		*diffs = append(*diffs, FieldDiff{Path: path, Recorded: recorded, Replayed: replayed})
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
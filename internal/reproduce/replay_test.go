@@ -0,0 +1,66 @@
+package reproduce
+
+import (
+	"testing"
+	"time"
+
+	"gohypo/domain/core"
+)
+
+func relationship(id string, p float64, at time.Time) core.Artifact {
+	return core.Artifact{
+		ID:   core.ID(id),
+		Kind: core.ArtifactRelationship,
+		Payload: map[string]interface{}{
+			"p_value":            p,
+			"analysis_timestamp": at,
+			"estimates":          []interface{}{map[string]interface{}{"coefficient": 0.5}},
+		},
+		CreatedAt: core.NewTimestamp(at),
+	}
+}
+
+// TestDiffArtifactsIgnoresWhenArtifactsWereWritten verifies a replay written later but
+// holding the same values does not diverge
+func TestDiffArtifactsIgnoresWhenArtifactsWereWritten(t *testing.T) {
+	recorded := []core.Artifact{relationship("corr_a_b", 0.01, time.Unix(100, 0))}
+	replayed := []core.Artifact{relationship("corr_a_b", 0.01, time.Unix(900, 0))}
+	divergence, err := DiffArtifacts(recorded, replayed, VolatileFields...)
+	if err != nil {
+		t.Fatalf("DiffArtifacts: %v", err)
+	}
+	if !divergence.Empty() || divergence.Compared != 1 {
+		t.Fatalf("expected one matching artifact, got %+v", divergence)
+	}
+}
+
+// TestDiffArtifactsReportsDivergence verifies changed values are reported at their path at
+// full precision, with artifacts only one side has
+func TestDiffArtifactsReportsDivergence(t *testing.T) {
+	at := time.Unix(100, 0)
+	recorded := []core.Artifact{relationship("corr_a_b", 0.01, at), relationship("corr_a_c", 0.2, at)}
+	changed := relationship("corr_a_b", 0.010000000000000002, at)
+	changed.Payload.(map[string]interface{})["estimates"] = []interface{}{map[string]interface{}{"coefficient": 0.4}}
+	replayed := []core.Artifact{changed, relationship("corr_b_c", 0.3, at)}
+
+	divergence, err := DiffArtifacts(recorded, replayed, VolatileFields...)
+	if err != nil {
+		t.Fatalf("DiffArtifacts: %v", err)
+	}
+	if len(divergence.Missing) != 1 || divergence.Missing[0].ID != "corr_a_c" {
+		t.Errorf("missing = %+v, want corr_a_c", divergence.Missing)
+	}
+	if len(divergence.Extra) != 1 || divergence.Extra[0].ID != "corr_b_c" {
+		t.Errorf("extra = %+v, want corr_b_c", divergence.Extra)
+	}
+	if len(divergence.Changed) != 1 {
+		t.Fatalf("changed = %+v, want corr_a_b", divergence.Changed)
+	}
+	fields := divergence.Changed[0].Fields
+	if len(fields) != 2 || fields[0].Path != "payload.estimates[0].coefficient" || fields[1].Path != "payload.p_value" {
+		t.Fatalf("unexpected field diffs: %+v", fields)
+	}
+	if fields[1].Recorded != 0.01 || fields[1].Replayed != 0.010000000000000002 {
+		t.Errorf("p_value diff lost precision: %+v", fields[1])
+	}
+}
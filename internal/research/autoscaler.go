@@ -0,0 +1,213 @@
+package research
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Reasons for a scale decision
+const (
+	ScaleUp     = "scale_up"
+	ScaleDown   = "scale_down"
+	ScaleSteady = "steady"
+)
+
+// AutoscalePolicy bounds the validation pool and how closely it follows demand. Demand is
+// the validations running plus one worker per TargetPerWorker waiting. The pool grows to
+// meet demand at once, but shrinks only after demand stayed below it for ScaleDownDelay, so
+// a burst of short batches does not make it flap.
+type AutoscalePolicy struct {
+	MinWorkers      int
+	MaxWorkers      int
+	TargetPerWorker int           // waiting validations one extra worker is added for
+	ScaleDownDelay  time.Duration // how long demand must stay below the pool before it shrinks
+	Interval        time.Duration // how often demand is evaluated
+}
+
+// DefaultAutoscalePolicy keeps the pool between 2 and 8 workers
+func DefaultAutoscalePolicy() AutoscalePolicy {
+	return AutoscalePolicy{
+		MinWorkers:      2,
+		MaxWorkers:      8,
+		TargetPerWorker: 4,
+		ScaleDownDelay:  10 * time.Minute,
+		Interval:        15 * time.Second,
+	}
+}
+
+// DesiredWorkers is the pool size demand calls for, within the policy's bounds
+func (p AutoscalePolicy) DesiredWorkers(queued, running int) int {
+	target := p.TargetPerWorker
+	if target < 1 {
+		target = 1
+	}
+	desired := running + (queued+target-1)/target
+	if desired < p.MinWorkers {
+		desired = p.MinWorkers
+	}
+	if desired > p.MaxWorkers {
+		desired = p.MaxWorkers
+	}
+	return desired
+}
+
+// ScaleDecision is the outcome of one evaluation of the pool
+type ScaleDecision struct {
+	Workers int       `json:"workers"` // pool size after the decision
+	Desired int       `json:"desired"` // what demand called for
+	Queued  int       `json:"queued"`
+	Running int       `json:"running"`
+	Reason  string    `json:"reason"`
+	At      time.Time `json:"at"`
+}
+
+// ScaleHook is told about every change of the pool size, so operators can add or remove
+// capacity outside the process
+type ScaleHook interface {
+	OnScale(ctx context.Context, decision ScaleDecision) error
+}
+
+type scalablePool interface {
+	Depth() QueueDepth
+	SetPoolSize(size int)
+}
+
+// Autoscaler sizes the validation pool from its queue depth
+type Autoscaler struct {
+	pool   scalablePool
+	policy AutoscalePolicy
+	hooks  []ScaleHook
+	now    func() time.Time
+
+	mu         sync.Mutex
+	workers    int
+	belowSince time.Time // when demand first fell below the pool; zero while it is not below
+	last       ScaleDecision
+}
+
+// NewAutoscaler sizes the pool to the policy's minimum and returns its autoscaler
+func NewAutoscaler(pool scalablePool, policy AutoscalePolicy, hooks ...ScaleHook) *Autoscaler {
+	if policy.MinWorkers < 1 {
+		policy.MinWorkers = 1
+	}
+	if policy.MaxWorkers < policy.MinWorkers {
+		policy.MaxWorkers = policy.MinWorkers
+	}
+	a := &Autoscaler{pool: pool, policy: policy, hooks: hooks, now: time.Now, workers: policy.MinWorkers}
+	pool.SetPoolSize(a.workers)
+	a.last = ScaleDecision{Workers: a.workers, Desired: a.workers, Reason: ScaleSteady, At: a.now()}
+	return a
+}
+
+// Policy returns the policy the pool is sized by
+func (a *Autoscaler) Policy() AutoscalePolicy {
+	return a.policy
+}
+
+// Run evaluates the pool every policy interval until ctx is done
+func (a *Autoscaler) Run(ctx context.Context) {
+	interval := a.policy.Interval
+	if interval <= 0 {
+		interval = DefaultAutoscalePolicy().Interval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.Evaluate(ctx)
+		}
+	}
+}
+
+// Evaluate compares demand with the pool, resizes it when needed and tells the hooks
+func (a *Autoscaler) Evaluate(ctx context.Context) ScaleDecision {
+	depth := a.pool.Depth()
+	now := a.now()
+
+	a.mu.Lock()
+	desired := a.policy.DesiredWorkers(depth.Queued, depth.Running)
+	decision := ScaleDecision{Workers: a.workers, Desired: desired, Queued: depth.Queued, Running: depth.Running, Reason: ScaleSteady, At: now}
+	switch {
+	case desired > a.workers:
+		decision.Reason = ScaleUp
+		a.belowSince = time.Time{}
+	case desired < a.workers:
+		if a.belowSince.IsZero() {
+			a.belowSince = now
+		}
+		if now.Sub(a.belowSince) >= a.policy.ScaleDownDelay {
+			decision.Reason = ScaleDown
+			a.belowSince = time.Time{}
+		}
+	default:
+		a.belowSince = time.Time{}
+	}
+	if decision.Reason != ScaleSteady {
+		a.workers = desired
+		decision.Workers = desired
+	}
+	a.last = decision
+	a.mu.Unlock()
+
+	if decision.Reason == ScaleSteady {
+		return decision
+	}
+	log.Printf("[Autoscaler] %s to %d workers (%d queued, %d running)", decision.Reason, decision.Workers, decision.Queued, decision.Running)
+	a.pool.SetPoolSize(decision.Workers)
+	for _, hook := range a.hooks {
+		if err := hook.OnScale(ctx, decision); err != nil {
+			log.Printf("[Autoscaler] ⚠️ Scale hook failed: %v", err)
+		}
+	}
+	return decision
+}
+
+// Status returns the latest decision
+func (a *Autoscaler) Status() ScaleDecision {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.last
+}
+
+// HTTPScaleHook POSTs every scale decision as JSON to a URL, e.g. a job that resizes the
+// deployment or a chat webhook
+type HTTPScaleHook struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewHTTPScaleHook creates a hook posting to url with a 10 second timeout
+func NewHTTPScaleHook(url string) *HTTPScaleHook {
+	return &HTTPScaleHook{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// OnScale posts the decision; any non-2xx response is an error
+func (h *HTTPScaleHook) OnScale(ctx context.Context, decision ScaleDecision) error {
+	body, err := json.Marshal(decision)
+	if err != nil {
+		return fmt.Errorf("failed to encode scale decision: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build scale callback: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("scale callback to %s failed: %w", h.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("scale callback to %s returned %s", h.URL, resp.Status)
+	}
+	return nil
+}
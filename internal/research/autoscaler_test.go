@@ -0,0 +1,134 @@
+package research
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type stubPool struct {
+	depth QueueDepth
+	sizes []int
+}
+
+func (p *stubPool) Depth() QueueDepth { return p.depth }
+
+func (p *stubPool) SetPoolSize(size int) { p.sizes = append(p.sizes, size) }
+
+type recordedScales struct {
+	decisions []ScaleDecision
+}
+
+func (r *recordedScales) OnScale(ctx context.Context, decision ScaleDecision) error {
+	r.decisions = append(r.decisions, decision)
+	return nil
+}
+
+// TestDesiredWorkers verifies demand counts running validations plus a worker per
+// TargetPerWorker waiting, within the policy bounds
+func TestDesiredWorkers(t *testing.T) {
+	policy := AutoscalePolicy{MinWorkers: 2, MaxWorkers: 8, TargetPerWorker: 4}
+	tests := []struct{ queued, running, want int }{
+		{0, 0, 2},
+		{1, 2, 3},
+		{8, 2, 4},
+		{9, 2, 5},
+		{100, 6, 8},
+	}
+	for _, tt := range tests {
+		if got := policy.DesiredWorkers(tt.queued, tt.running); got != tt.want {
+			t.Errorf("DesiredWorkers(%d, %d) = %d, want %d", tt.queued, tt.running, got, tt.want)
+		}
+	}
+}
+
+// TestAutoscalerScalesUpAtOnceAndDownAfterDelay verifies growth is immediate, shrinking
+// waits for demand to stay low, and hooks hear about each change
+func TestAutoscalerScalesUpAtOnceAndDownAfterDelay(t *testing.T) {
+	ctx := context.Background()
+	pool := &stubPool{}
+	hook := &recordedScales{}
+	now := time.Date(2024, 1, 1, 22, 0, 0, 0, time.UTC)
+	scaler := NewAutoscaler(pool, AutoscalePolicy{MinWorkers: 2, MaxWorkers: 8, TargetPerWorker: 4, ScaleDownDelay: 10 * time.Minute}, hook)
+	scaler.now = func() time.Time { return now }
+	if len(pool.sizes) != 1 || pool.sizes[0] != 2 {
+		t.Fatalf("expected the pool to start at the minimum, got %v", pool.sizes)
+	}
+
+	pool.depth = QueueDepth{Queued: 20, Running: 2}
+	if decision := scaler.Evaluate(ctx); decision.Reason != ScaleUp || decision.Workers != 7 {
+		t.Fatalf("expected to scale up to 7, got %+v", decision)
+	}
+
+	// The run drains: demand falls, but the pool holds until the delay passes
+	pool.depth = QueueDepth{}
+	if decision := scaler.Evaluate(ctx); decision.Reason != ScaleSteady || decision.Workers != 7 {
+		t.Fatalf("expected to hold at 7, got %+v", decision)
+	}
+	now = now.Add(5 * time.Minute)
+	scaler.Evaluate(ctx)
+	now = now.Add(5 * time.Minute)
+	if decision := scaler.Evaluate(ctx); decision.Reason != ScaleDown || decision.Workers != 2 {
+		t.Fatalf("expected to scale down to 2, got %+v", decision)
+	}
+
+	if len(hook.decisions) != 2 || pool.sizes[len(pool.sizes)-1] != 2 {
+		t.Errorf("expected two hook calls and a pool of 2, got %+v / %v", hook.decisions, pool.sizes)
+	}
+	if scaler.Status().Workers != 2 {
+		t.Errorf("status = %+v", scaler.Status())
+	}
+}
+
+// TestAutoscalerDemandSpikeResetsScaleDown verifies a burst during the delay restarts it
+func TestAutoscalerDemandSpikeResetsScaleDown(t *testing.T) {
+	ctx := context.Background()
+	pool := &stubPool{depth: QueueDepth{Queued: 8, Running: 2}}
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	scaler := NewAutoscaler(pool, AutoscalePolicy{MinWorkers: 1, MaxWorkers: 4, TargetPerWorker: 4, ScaleDownDelay: time.Minute})
+	scaler.now = func() time.Time { return now }
+	scaler.Evaluate(ctx) // 4 workers
+
+	pool.depth = QueueDepth{}
+	scaler.Evaluate(ctx)
+	now = now.Add(45 * time.Second)
+	pool.depth = QueueDepth{Running: 4}
+	scaler.Evaluate(ctx)
+	pool.depth = QueueDepth{}
+	now = now.Add(45 * time.Second)
+	if decision := scaler.Evaluate(ctx); decision.Workers != 4 {
+		t.Errorf("expected the burst to restart the delay, got %+v", decision)
+	}
+}
+
+// TestHTTPScaleHook verifies decisions are posted as JSON and failures reported
+func TestHTTPScaleHook(t *testing.T) {
+	var received ScaleDecision
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	hook := NewHTTPScaleHook(server.URL)
+	if err := hook.OnScale(context.Background(), ScaleDecision{Workers: 5, Reason: ScaleUp}); err != nil {
+		t.Fatalf("OnScale: %v", err)
+	}
+	if received.Workers != 5 || received.Reason != ScaleUp {
+		t.Errorf("received %+v", received)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	if err := NewHTTPScaleHook(failing.URL).OnScale(context.Background(), ScaleDecision{}); err == nil {
+		t.Error("expected a failing callback to be reported")
+	}
+}
@@ -0,0 +1,316 @@
+package research
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"gohypo/domain/greenfield"
+	"gohypo/internal/logging"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+)
+
+// DefaultJobPollInterval is how often an idle job runner looks for due jobs; an enqueue on
+// the same process wakes it sooner
+const DefaultJobPollInterval = 5 * time.Second
+
+// ResearchJob is the payload of a research job. It holds what a request decides about the
+// run; the workspace's fields and artifacts are loaded when the job runs, so a retry sees
+// the data as it is then.
+type ResearchJob struct {
+	SessionID   string             `json:"session_id"`
+	WorkspaceID string             `json:"workspace_id"`
+	Domain      string             `json:"domain_pack,omitempty"`
+	Samples     int                `json:"self_consistency_samples,omitempty"`
+	LLM         models.LLMSettings `json:"llm,omitempty"`
+}
+
+// WorkspaceDataLoader loads the fields and statistical artifacts research runs over
+type WorkspaceDataLoader interface {
+	GetFieldMetadataByWorkspace(workspaceID uuid.UUID) ([]greenfield.FieldMetadata, error)
+	GetStatisticalArtifactsByWorkspace(workspaceID uuid.UUID) ([]map[string]interface{}, error)
+}
+
+type researchJobProcessor interface {
+	RunResearchJob(ctx context.Context, job ResearchJob, fieldMetadata []greenfield.FieldMetadata, statsArtifacts []map[string]interface{}) error
+}
+
+// JobRunnerOptions sizes a job runner
+type JobRunnerOptions struct {
+	Workers int           // jobs this instance runs at once; 0 only enqueues and recovers orphans
+	Lease   time.Duration // how long a claimed job and its session stay locked without a heartbeat
+}
+
+// DefaultJobLease is the lease of a claimed research job; workers heartbeat every third of it
+const DefaultJobLease = 2 * time.Minute
+
+// JobRunner runs research sessions from the durable job queue, so sessions requested before
+// a restart still run after it and failed ones are retried with backoff. Any number of
+// server instances may run one against the same queue: a session lease lets a single worker
+// process each session, workers heartbeat their job's and session's leases, and jobs whose
+// worker stopped heartbeating are recovered and queued again.
+type JobRunner struct {
+	queue        ports.JobQueue
+	leases       ports.LeaseStore
+	processor    researchJobProcessor
+	data         WorkspaceDataLoader
+	options      JobRunnerOptions
+	workerID     string
+	pollInterval time.Duration
+	wake         chan struct{}
+	now          func() time.Time
+}
+
+// NewJobRunner creates a runner claiming research jobs from queue for worker
+func NewJobRunner(queue ports.JobQueue, leases ports.LeaseStore, worker *ResearchWorker, data WorkspaceDataLoader, options JobRunnerOptions) *JobRunner {
+	return newJobRunner(queue, leases, worker, data, options)
+}
+
+func newJobRunner(queue ports.JobQueue, leases ports.LeaseStore, processor researchJobProcessor, data WorkspaceDataLoader, options JobRunnerOptions) *JobRunner {
+	if options.Lease <= 0 {
+		options.Lease = DefaultJobLease
+	}
+	host, _ := os.Hostname()
+	return &JobRunner{
+		queue:        queue,
+		leases:       leases,
+		processor:    processor,
+		data:         data,
+		options:      options,
+		workerID:     fmt.Sprintf("%s-%d", host, os.Getpid()),
+		pollInterval: DefaultJobPollInterval,
+		wake:         make(chan struct{}, 1),
+		now:          time.Now,
+	}
+}
+
+// EnqueueResearch queues a research session to run
+func (r *JobRunner) EnqueueResearch(ctx context.Context, job ResearchJob) (*models.Job, error) {
+	queued, err := models.NewJob(models.JobKindResearch, job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode research job: %w", err)
+	}
+	if err := r.queue.Enqueue(ctx, queued); err != nil {
+		return nil, err
+	}
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+	return queued, nil
+}
+
+// Start recovers orphaned jobs in the background and runs the configured number of workers
+// claiming jobs, until ctx is done
+func (r *JobRunner) Start(ctx context.Context) {
+	go r.recoverOrphans(ctx)
+	for i := 0; i < r.options.Workers; i++ {
+		go r.Run(ctx, fmt.Sprintf("%s/%d", r.workerID, i))
+	}
+	slog.Info("Research job runner started", "workers", r.options.Workers, "lease", r.options.Lease.String())
+}
+
+// recoverOrphans queues again the jobs of workers that stopped heartbeating, on this instance
+// or any other, every half lease
+func (r *JobRunner) recoverOrphans(ctx context.Context) {
+	ticker := time.NewTicker(r.options.Lease / 2)
+	defer ticker.Stop()
+	for {
+		if recovered, err := r.queue.RecoverOrphaned(ctx); err != nil {
+			slog.Error("Failed to recover orphaned jobs", "error", err)
+		} else if recovered > 0 {
+			slog.Warn("Recovered jobs whose worker stopped heartbeating", "jobs", recovered)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run claims and runs due jobs as workerID until ctx is done, polling while the queue is idle
+func (r *JobRunner) Run(ctx context.Context, workerID string) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			ran, err := r.RunOnce(ctx, workerID)
+			if err != nil {
+				slog.Error("Failed to claim a research job", "worker", workerID, "error", err)
+			}
+			if !ran || ctx.Err() != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// RunOnce claims one due research job as workerID and runs it, reporting whether there was
+// one. A failed run is queued to retry after the job's backoff until its attempts run out; a
+// job whose session another worker is processing is put back without counting the attempt.
+func (r *JobRunner) RunOnce(ctx context.Context, workerID string) (bool, error) {
+	job, err := r.queue.Claim(ctx, models.JobKindResearch, workerID, r.options.Lease)
+	if err != nil || job == nil {
+		return false, err
+	}
+	// Record the outcome even when ctx was cancelled part way, so the job is not left running
+	recordCtx := context.WithoutCancel(ctx)
+
+	var payload ResearchJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		r.fail(recordCtx, job, workerID, fmt.Errorf("invalid research job payload: %w", err))
+		return true, nil
+	}
+
+	sessionKey := "research_session:" + payload.SessionID
+	acquired, err := r.leases.AcquireLease(ctx, sessionKey, workerID, r.options.Lease)
+	if err != nil || !acquired {
+		if err != nil {
+			slog.Error("Failed to lock research session", "session_id", payload.SessionID, "error", err)
+		} else {
+			slog.Info("Research session is being processed by another worker, deferring job",
+				"session_id", payload.SessionID, "job_id", job.ID)
+		}
+		if err := r.queue.Release(recordCtx, job.ID, workerID, r.now().Add(r.pollInterval)); err != nil {
+			slog.Error("Failed to release research job", "job_id", job.ID, "error", err)
+		}
+		return true, nil
+	}
+	defer func() {
+		if err := r.leases.ReleaseLease(recordCtx, sessionKey, workerID); err != nil {
+			slog.Error("Failed to unlock research session", "session_id", payload.SessionID, "error", err)
+		}
+	}()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stopHeartbeat := r.heartbeat(runCtx, cancel, job.ID, sessionKey, workerID)
+	runErr := r.run(runCtx, job, payload)
+	if lost := stopHeartbeat(); lost {
+		// The job was recovered and may be running elsewhere; its outcome is no longer ours
+		return true, nil
+	}
+
+	if runErr == nil {
+		if err := r.queue.Complete(recordCtx, job.ID, workerID); err != nil {
+			slog.Error("Failed to complete research job", "job_id", job.ID, "error", err)
+		}
+		return true, nil
+	}
+	r.fail(recordCtx, job, workerID, runErr)
+	return true, nil
+}
+
+// fail records a failed attempt of job, to retry after its backoff while it has attempts left
+func (r *JobRunner) fail(ctx context.Context, job *models.Job, workerID string, runErr error) {
+	retryAt := r.now().Add(job.RetryDelay())
+	if job.CanRetry() {
+		slog.Warn("Research job failed, will retry", "job_id", job.ID, "attempt", job.Attempts,
+			"retry_at", retryAt, "error", runErr)
+	} else {
+		slog.Error("Research job failed, giving up", "job_id", job.ID, "attempts", job.Attempts, "error", runErr)
+	}
+	if err := r.queue.Fail(ctx, job.ID, workerID, runErr.Error(), retryAt); err != nil {
+		slog.Error("Failed to record research job failure", "job_id", job.ID, "error", err)
+	}
+}
+
+// heartbeat renews the job's and its session's leases every third of the lease until stop is
+// called, which reports whether a lease was lost. Losing one cancels the run, since the job
+// may already have been recovered and handed to another worker.
+func (r *JobRunner) heartbeat(ctx context.Context, cancel context.CancelFunc, jobID, sessionKey, workerID string) (stop func() bool) {
+	done := make(chan struct{})
+	lost := make(chan bool, 1)
+	go func() {
+		ticker := time.NewTicker(r.options.Lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				lost <- false
+				return
+			case <-ticker.C:
+			}
+			held, err := r.queue.Heartbeat(ctx, jobID, workerID, r.options.Lease)
+			if err == nil && held {
+				held, err = r.leases.RenewLease(ctx, sessionKey, workerID, r.options.Lease)
+			}
+			if err != nil {
+				// A missed heartbeat is fine as long as a later one lands within the lease
+				slog.Warn("Failed to renew research job lease", "job_id", jobID, "error", err)
+				continue
+			}
+			if !held {
+				slog.Error("Lost research job lease, stopping the run", "job_id", jobID)
+				cancel()
+				<-done
+				lost <- true
+				return
+			}
+		}
+	}()
+	return func() bool {
+		close(done)
+		return <-lost
+	}
+}
+
+// run loads a claimed job's workspace data and processes it, turning a panic into a failed
+// attempt
+func (r *JobRunner) run(ctx context.Context, job *models.Job, payload ResearchJob) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("research job panicked: %v", p)
+		}
+	}()
+
+	workspaceID, err := uuid.Parse(payload.WorkspaceID)
+	if err != nil {
+		return fmt.Errorf("invalid workspace ID %q: %w", payload.WorkspaceID, err)
+	}
+	fieldMetadata, err := r.data.GetFieldMetadataByWorkspace(workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to load field metadata: %w", err)
+	}
+	statsArtifacts, err := r.data.GetStatisticalArtifactsByWorkspace(workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to load statistical artifacts: %w", err)
+	}
+	ctx = logging.WithRun(ctx, payload.SessionID)
+	slog.InfoContext(ctx, "Running research job", "job_id", job.ID, "attempt", job.Attempts)
+	return r.processor.RunResearchJob(ctx, payload, fieldMetadata, statsArtifacts)
+}
+
+// RunResearchJob processes a queued research session with the job's domain pack, sampling
+// and LLM settings, returning the session's error when it ends in the error state
+func (rw *ResearchWorker) RunResearchJob(ctx context.Context, job ResearchJob, fieldMetadata []greenfield.FieldMetadata, statsArtifacts []map[string]interface{}) error {
+	ctx = WithSelfConsistency(WithDomainPack(ctx, job.Domain), job.Samples)
+	ctx = WithLLMSettings(ctx, job.LLM)
+	rw.ProcessResearch(ctx, job.SessionID, fieldMetadata, statsArtifacts, rw.sseHub)
+
+	session, err := rw.sessionMgr.GetSession(ctx, job.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load session %s after research: %w", job.SessionID, err)
+	}
+	if session.State == models.SessionStateError {
+		if session.Error.Valid && session.Error.String != "" {
+			return errors.New(session.Error.String)
+		}
+		return fmt.Errorf("session %s ended in error", job.SessionID)
+	}
+	return nil
+}
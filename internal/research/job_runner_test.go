@@ -0,0 +1,286 @@
+package research
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"gohypo/domain/greenfield"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+)
+
+// memoryJobs is a JobQueue holding one kind of job in memory
+type memoryJobs struct {
+	ports.JobQueue
+	mu   sync.Mutex
+	jobs []*models.Job
+}
+
+func (q *memoryJobs) Enqueue(ctx context.Context, job *models.Job) error {
+	job.ID = uuid.NewString()
+	q.jobs = append(q.jobs, job)
+	return nil
+}
+
+func (q *memoryJobs) Claim(ctx context.Context, kind, workerID string, lease time.Duration) (*models.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, job := range q.jobs {
+		if job.Status == models.JobStatusQueued && !job.RunAt.After(time.Now()) {
+			job.Status = models.JobStatusRunning
+			job.Attempts++
+			job.LockedBy = &workerID
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+// held returns the job when workerID holds it
+func (q *memoryJobs) held(jobID, workerID string) *models.Job {
+	for _, job := range q.jobs {
+		if job.ID == jobID && job.LockedBy != nil && *job.LockedBy == workerID {
+			return job
+		}
+	}
+	return nil
+}
+
+func (q *memoryJobs) Heartbeat(ctx context.Context, jobID, workerID string, lease time.Duration) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.held(jobID, workerID) != nil, nil
+}
+
+func (q *memoryJobs) Release(ctx context.Context, jobID, workerID string, runAt time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if job := q.held(jobID, workerID); job != nil {
+		job.Status, job.RunAt, job.LockedBy = models.JobStatusQueued, runAt, nil
+		job.Attempts--
+	}
+	return nil
+}
+
+func (q *memoryJobs) Complete(ctx context.Context, jobID, workerID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if job := q.held(jobID, workerID); job != nil {
+		job.Status, job.LockedBy = models.JobStatusSucceeded, nil
+	}
+	return nil
+}
+
+func (q *memoryJobs) Fail(ctx context.Context, jobID, workerID string, errMsg string, retryAt time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job := q.held(jobID, workerID)
+	if job == nil {
+		return nil
+	}
+	job.LastError, job.LockedBy = &errMsg, nil
+	if job.CanRetry() {
+		job.Status, job.RunAt = models.JobStatusQueued, retryAt
+	} else {
+		job.Status = models.JobStatusFailed
+	}
+	return nil
+}
+
+// steal hands a running job over to workerID, as when it is recovered and claimed elsewhere
+func (q *memoryJobs) steal(workerID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job := q.jobs[0]
+	if job.Status != models.JobStatusRunning {
+		return false
+	}
+	job.LockedBy = &workerID
+	return true
+}
+
+// memoryLeases is a LeaseStore that never expires leases
+type memoryLeases struct {
+	mu      sync.Mutex
+	holders map[string]string
+}
+
+func (l *memoryLeases) AcquireLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holders == nil {
+		l.holders = map[string]string{}
+	}
+	if current, ok := l.holders[key]; ok && current != holder {
+		return false, nil
+	}
+	l.holders[key] = holder
+	return true, nil
+}
+
+func (l *memoryLeases) RenewLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.holders[key] == holder, nil
+}
+
+func (l *memoryLeases) ReleaseLease(ctx context.Context, key, holder string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holders[key] == holder {
+		delete(l.holders, key)
+	}
+	return nil
+}
+
+// scriptedResearch fails the first failures runs, panicking on the first when panics is set,
+// or with block set runs until its context is cancelled
+type scriptedResearch struct {
+	failures int
+	panics   bool
+	block    bool
+	runs     []ResearchJob
+}
+
+func (s *scriptedResearch) RunResearchJob(ctx context.Context, job ResearchJob, fieldMetadata []greenfield.FieldMetadata, statsArtifacts []map[string]interface{}) error {
+	s.runs = append(s.runs, job)
+	if s.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if len(s.runs) == 1 && s.panics {
+		panic("index out of range")
+	}
+	if len(s.runs) <= s.failures {
+		return errors.New("LLM provider unavailable")
+	}
+	return nil
+}
+
+type staticWorkspaceData struct{}
+
+func (staticWorkspaceData) GetFieldMetadataByWorkspace(workspaceID uuid.UUID) ([]greenfield.FieldMetadata, error) {
+	return []greenfield.FieldMetadata{{Name: "revenue"}}, nil
+}
+
+func (staticWorkspaceData) GetStatisticalArtifactsByWorkspace(workspaceID uuid.UUID) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+// TestJobRunnerRetriesWithBackoff verifies a failed research job is queued again after its
+// backoff, runs with the payload it was enqueued with, and succeeds on a later attempt
+func TestJobRunnerRetriesWithBackoff(t *testing.T) {
+	queue := &memoryJobs{}
+	research := &scriptedResearch{failures: 1, panics: true}
+	runner := newJobRunner(queue, &memoryLeases{}, research, staticWorkspaceData{}, JobRunnerOptions{Workers: 1})
+	now := time.Now()
+	runner.now = func() time.Time { return now }
+
+	job := ResearchJob{SessionID: uuid.NewString(), WorkspaceID: uuid.NewString(), Domain: "retail", Samples: 3}
+	if _, err := runner.EnqueueResearch(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+	if ran, err := runner.RunOnce(context.Background(), "w1"); !ran || err != nil {
+		t.Fatalf("expected the queued job to run, got %v, %v", ran, err)
+	}
+	queued := queue.jobs[0]
+	if queued.Status != models.JobStatusQueued || queued.LastError == nil || !queued.RunAt.Equal(now.Add(models.JobRetryBaseDelay)) {
+		t.Fatalf("expected the panicked attempt to be retried after the base delay, got %+v", queued)
+	}
+	if ran, _ := runner.RunOnce(context.Background(), "w1"); ran {
+		t.Fatal("expected no job to run before its retry is due")
+	}
+
+	queued.RunAt = now
+	if ran, _ := runner.RunOnce(context.Background(), "w1"); !ran {
+		t.Fatal("expected the retry to run once due")
+	}
+	if queued.Status != models.JobStatusSucceeded || queued.Attempts != 2 {
+		t.Fatalf("expected the second attempt to succeed, got %s after %d attempts", queued.Status, queued.Attempts)
+	}
+	if research.runs[1] != job {
+		t.Fatalf("expected the retry to run the enqueued payload, got %+v", research.runs[1])
+	}
+}
+
+// TestJobRunnerGivesUp verifies a job that keeps failing is marked failed after its attempts
+func TestJobRunnerGivesUp(t *testing.T) {
+	queue := &memoryJobs{}
+	runner := newJobRunner(queue, &memoryLeases{}, &scriptedResearch{failures: 10}, staticWorkspaceData{}, JobRunnerOptions{Workers: 1})
+	runner.EnqueueResearch(context.Background(), ResearchJob{SessionID: "s1", WorkspaceID: uuid.NewString()})
+
+	for i := 0; i < models.DefaultJobMaxAttempts; i++ {
+		queue.jobs[0].RunAt = time.Now()
+		runner.RunOnce(context.Background(), "w1")
+	}
+	if job := queue.jobs[0]; job.Status != models.JobStatusFailed || *job.LastError != "LLM provider unavailable" {
+		t.Fatalf("expected the job to fail with the last error, got %s (%v)", job.Status, job.LastError)
+	}
+}
+
+// TestJobRunnerDefersLockedSession verifies a job whose session another worker holds is put
+// back without running it or counting the attempt, and runs once the session is free
+func TestJobRunnerDefersLockedSession(t *testing.T) {
+	queue := &memoryJobs{}
+	leases := &memoryLeases{}
+	research := &scriptedResearch{}
+	runner := newJobRunner(queue, leases, research, staticWorkspaceData{}, JobRunnerOptions{Workers: 1})
+	now := time.Now()
+	runner.now = func() time.Time { return now }
+
+	runner.EnqueueResearch(context.Background(), ResearchJob{SessionID: "s1", WorkspaceID: uuid.NewString()})
+	leases.AcquireLease(context.Background(), "research_session:s1", "other-instance/0", time.Minute)
+
+	if ran, err := runner.RunOnce(context.Background(), "w1"); !ran || err != nil {
+		t.Fatalf("expected the job to be claimed, got %v, %v", ran, err)
+	}
+	job := queue.jobs[0]
+	if len(research.runs) != 0 || job.Status != models.JobStatusQueued || job.Attempts != 0 || !job.RunAt.Equal(now.Add(runner.pollInterval)) {
+		t.Fatalf("expected the job to be deferred uncounted, got %d runs and %+v", len(research.runs), job)
+	}
+
+	leases.ReleaseLease(context.Background(), "research_session:s1", "other-instance/0")
+	job.RunAt = now
+	runner.RunOnce(context.Background(), "w1")
+	if len(research.runs) != 1 || job.Status != models.JobStatusSucceeded || job.Attempts != 1 {
+		t.Fatalf("expected the job to run once the session was free, got %d runs and %+v", len(research.runs), job)
+	}
+	if _, held := leases.holders["research_session:s1"]; held {
+		t.Fatal("expected the session lease to be released after the run")
+	}
+}
+
+// TestJobRunnerStopsOnLostLease verifies a run is cancelled when its job is recovered from
+// under it, and that its outcome is left to whoever holds the job now
+func TestJobRunnerStopsOnLostLease(t *testing.T) {
+	queue := &memoryJobs{}
+	runner := newJobRunner(queue, &memoryLeases{}, &scriptedResearch{block: true}, staticWorkspaceData{},
+		JobRunnerOptions{Workers: 1, Lease: 30 * time.Millisecond})
+	runner.EnqueueResearch(context.Background(), ResearchJob{SessionID: "s1", WorkspaceID: uuid.NewString()})
+
+	done := make(chan struct{})
+	go func() {
+		runner.RunOnce(context.Background(), "w1")
+		close(done)
+	}()
+	for !queue.steal("w2") {
+		time.Sleep(time.Millisecond)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the run to stop once its lease was lost")
+	}
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	if job := queue.jobs[0]; job.Status != models.JobStatusRunning || job.LastError != nil {
+		t.Fatalf("expected the new holder's job to be left alone, got %+v", job)
+	}
+}
@@ -0,0 +1,68 @@
+package research
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+)
+
+// metadataSessions keeps one session's metadata, serializing merges as the row lock does
+type metadataSessions struct {
+	ports.SessionRepository
+	mu       sync.Mutex
+	metadata map[string]json.RawMessage
+}
+
+func (m *metadataSessions) MergeSessionMetadata(ctx context.Context, userID, sessionID uuid.UUID, key string, merge func(current json.RawMessage) (interface{}, error)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, err := merge(m.metadata[key])
+	if err != nil {
+		return err
+	}
+	m.metadata[key], err = json.Marshal(value)
+	return err
+}
+
+// defaultUser always resolves to one user
+type defaultUser struct {
+	ports.UserRepository
+	id uuid.UUID
+}
+
+func (d defaultUser) GetOrCreateDefaultUser(ctx context.Context) (*models.User, error) {
+	return &models.User{ID: d.id}, nil
+}
+
+// TestGenerationUsageTotalsConcurrentRuns verifies generation runs of one session that finish
+// together each add to the session's usage total rather than overwriting one another
+func TestGenerationUsageTotalsConcurrentRuns(t *testing.T) {
+	sessions := &metadataSessions{metadata: map[string]json.RawMessage{}}
+	rw := &ResearchWorker{sessionMgr: NewSessionManager(sessions, defaultUser{id: uuid.New()})}
+	sessionID := uuid.NewString()
+
+	const runs = 20
+	var wg sync.WaitGroup
+	for i := 0; i < runs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rw.recordGenerationUsage(context.Background(), sessionID, &models.RunUsage{Calls: 1, TotalTokens: 100, EstimatedCostUSD: 0.01})
+		}()
+	}
+	wg.Wait()
+
+	var total models.RunUsage
+	if err := json.Unmarshal(sessions.metadata["llm_usage"], &total); err != nil {
+		t.Fatalf("expected usage recorded on the session: %v", err)
+	}
+	if total.RunID != sessionID || total.Calls != runs || total.TotalTokens != runs*100 {
+		t.Errorf("expected %d calls and %d tokens for %s, got %+v", runs, runs*100, sessionID, total)
+	}
+}
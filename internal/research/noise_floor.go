@@ -0,0 +1,119 @@
+package research
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/domain/stats"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+)
+
+// CalibrateNoiseFloor runs the burn-in calibration of a workspace: every analyzable column of
+// the dataset is permuted independently, a reduced sweep runs on each permuted copy, and the
+// null distribution of |r| it finds becomes the workspace's noise floor. Later sweeps,
+// validations and explorations place their findings against it by percentile.
+func (rw *ResearchWorker) CalibrateNoiseFloor(ctx context.Context, workspaceID uuid.UUID, ds *dataset.Dataset, userID core.ID, opts stats.NoiseFloorOptions) (*stats.NoiseFloor, error) {
+	if rw.workspaces == nil {
+		return nil, fmt.Errorf("workspace storage not available")
+	}
+	workspace, err := rw.workspaces.GetByID(ctx, core.ID(workspaceID.String()))
+	if err != nil || workspace == nil {
+		return nil, fmt.Errorf("workspace %s not found", workspaceID)
+	}
+
+	runID := "calibrate-" + uuid.NewString()
+	excluded := ds.Metadata.ExcludedFields()
+	varKeys := make([]core.VariableKey, 0, len(ds.Metadata.Fields))
+	for _, field := range ds.Metadata.Fields {
+		if field.Name != "" && !excluded[field.Name] {
+			varKeys = append(varKeys, core.VariableKey(field.Name))
+		}
+	}
+	if len(varKeys) < 2 {
+		return nil, fmt.Errorf("dataset %s has fewer than two variables to calibrate on", ds.ID)
+	}
+	bundle, err := rw.resolveDatasetMatrix(ctx, ds, userID, ports.MatrixResolutionRequest{
+		ViewID:     core.ID("ui-calibration"),
+		SnapshotID: core.SnapshotID(runID),
+		VarKeys:    varKeys,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve dataset %s: %w", ds.ID, err)
+	}
+	columns := make([][]float64, 0, len(bundle.Matrix.VariableKeys))
+	for _, key := range bundle.Matrix.VariableKeys {
+		if column, ok := bundle.GetColumnData(key); ok {
+			columns = append(columns, column)
+		}
+	}
+
+	floor, err := stats.CalibrateNoiseFloor(columns, opts)
+	if err != nil {
+		return nil, err
+	}
+	floor.DatasetID = string(ds.ID)
+
+	if rw.testkit != nil {
+		stored := *floor
+		artifact := core.Artifact{
+			ID:        core.ID("noise_floor_" + runID),
+			Kind:      core.ArtifactNoiseFloor,
+			Payload:   &stored,
+			CreatedAt: core.Now(),
+		}
+		if err := rw.testkit.LedgerAdapter().StoreArtifact(ctx, runID, artifact); err != nil {
+			slog.WarnContext(ctx, "Failed to store noise floor artifact", "error", err)
+		}
+	}
+	if workspace.Metadata == nil {
+		workspace.Metadata = make(map[string]interface{})
+	}
+	workspace.Metadata[stats.NoiseFloorKey] = floor
+	if err := rw.workspaces.Update(ctx, workspace); err != nil {
+		return nil, fmt.Errorf("failed to save the noise floor: %w", err)
+	}
+	slog.InfoContext(ctx, "Noise floor calibrated", "workspace_id", workspaceID, "dataset_id", floor.DatasetID,
+		"permutations", floor.Permutations, "pairs", floor.Pairs, "p95", floor.Level(95))
+	return floor, nil
+}
+
+// noiseFloor returns the workspace's calibrated noise floor, or nil when it has none or it was
+// calibrated on another dataset than datasetID; an empty datasetID accepts any
+func (rw *ResearchWorker) noiseFloor(ctx context.Context, workspaceID uuid.UUID, datasetID string) *stats.NoiseFloor {
+	if rw.workspaces == nil || workspaceID == uuid.Nil {
+		return nil
+	}
+	workspace, err := rw.workspaces.GetByID(ctx, core.ID(workspaceID.String()))
+	if err != nil || workspace == nil {
+		return nil
+	}
+	floor := stats.NoiseFloorFrom(workspace.Metadata)
+	if floor == nil || (datasetID != "" && floor.DatasetID != "" && floor.DatasetID != datasetID) {
+		return nil
+	}
+	return floor
+}
+
+// placeAgainstNoiseFloor records where a hypothesis' observed correlation falls against its
+// workspace's noise floor
+func (rw *ResearchWorker) placeAgainstNoiseFloor(ctx context.Context, sessionID string, h *models.HypothesisResult, correlation float64) {
+	if rw.sessionMgr == nil || math.IsNaN(correlation) {
+		return
+	}
+	session, err := rw.sessionMgr.GetSession(ctx, sessionID)
+	if err != nil || session == nil {
+		return
+	}
+	if floor := rw.noiseFloor(ctx, session.WorkspaceID, ""); floor != nil {
+		if percentile := floor.Percentile(correlation); !math.IsNaN(percentile) {
+			h.ExecutionMetadata[models.NoisePercentileKey] = percentile
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package research
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"gohypo/adapters/excel"
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/domain/stats"
+	"gohypo/internal/access"
+	analysisbrief "gohypo/internal/analysis/brief"
+	"gohypo/internal/buildinfo"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+)
+
+// ExplorePair resolves two variables of a workspace's dataset and tests their relationship on
+// demand, outside any research session, with the same PII exclusions and column access as a
+// sweep. The exploration is stored as a ledger artifact so the result can be audited like a sweep's.
+func (rw *ResearchWorker) ExplorePair(ctx context.Context, workspaceID uuid.UUID, ds *dataset.Dataset, userID core.ID, cause, effect string) (*models.PairExploration, error) {
+	if cause == "" || effect == "" {
+		return nil, fmt.Errorf("both a cause and an effect variable are required")
+	}
+	if cause == effect {
+		return nil, fmt.Errorf("pick two different variables")
+	}
+
+	exploration := &models.PairExploration{
+		RunID:       "explore-" + uuid.NewString(),
+		WorkspaceID: workspaceID.String(),
+		DatasetID:   string(ds.ID),
+		DatasetName: ds.GetDisplayName(),
+		Cause:       cause,
+		Effect:      effect,
+		At:          time.Now(),
+	}
+
+	excluded := ds.Metadata.ExcludedFields()
+	if excluded[cause] || excluded[effect] {
+		return nil, fmt.Errorf("PII columns are kept out of analysis")
+	}
+	bundle, err := rw.resolveDatasetMatrix(ctx, ds, userID, ports.MatrixResolutionRequest{
+		ViewID:     core.ID("ui-explorer"),
+		SnapshotID: core.SnapshotID(exploration.RunID),
+		VarKeys:    []core.VariableKey{core.VariableKey(cause), core.VariableKey(effect)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s and %s: %w", cause, effect, err)
+	}
+	x, ok := bundle.GetColumnData(core.VariableKey(cause))
+	y, ok2 := bundle.GetColumnData(core.VariableKey(effect))
+	if !ok || !ok2 {
+		return nil, fmt.Errorf("variable data not found: cause=%s, effect=%s", cause, effect)
+	}
+
+	exploration.Policy = rw.analysisPolicy(ctx, workspaceID).WithDefaults()
+	if exploration.Test, err = stats.TestPair(x, y, exploration.Policy); err != nil {
+		return nil, err
+	}
+	if floor := rw.noiseFloor(ctx, workspaceID, exploration.DatasetID); floor != nil {
+		if percentile := floor.Percentile(exploration.Test.Correlation); !math.IsNaN(percentile) {
+			exploration.NoisePercentile = &percentile
+		}
+	}
+	xs, ys := completeRows(x, y)
+	exploration.Senses = analysisbrief.NewSenseEngine(analysisbrief.NewComputer()).AnalyzeAll(ctx, xs, ys, core.VariableKey(cause), core.VariableKey(effect))
+	exploration.Audits = bundle.Audits
+	exploration.EngineVersions = buildinfo.Engines([]string{})
+
+	if rw.testkit != nil {
+		stored := *exploration // the ledger keeps the exploration as it was fingerprinted
+		artifact := core.Artifact{
+			ID:        core.ID("pair_exploration_" + exploration.RunID),
+			Kind:      core.ArtifactPairExploration,
+			Payload:   &stored,
+			CreatedAt: core.Now(),
+		}
+		if err := rw.testkit.LedgerAdapter().StoreArtifact(ctx, exploration.RunID, artifact); err != nil {
+			slog.WarnContext(ctx, "Failed to store pair exploration artifact", "error", err)
+		} else {
+			provenance := models.ArtifactProvenance(artifact)
+			exploration.Provenance = &provenance
+		}
+	}
+	slog.InfoContext(ctx, "Pair explored", "cause", cause, "effect", effect, "dataset_id", exploration.DatasetID,
+		"rows", exploration.Test.CompleteRows, "correlation", exploration.Test.Correlation, "p_value", exploration.Test.PValue)
+	return exploration, nil
+}
+
+// resolveDatasetMatrix resolves variables of an uploaded dataset the way a sweep does: from a
+// decrypted copy of its file, through the user's column access
+func (rw *ResearchWorker) resolveDatasetMatrix(ctx context.Context, ds *dataset.Dataset, userID core.ID, req ports.MatrixResolutionRequest) (*dataset.MatrixBundle, error) {
+	if ds.FilePath == "" {
+		return nil, fmt.Errorf("dataset %s has no file to analyze", ds.ID)
+	}
+	filePath := ds.FilePath
+	if rw.datasetFiles != nil {
+		localPath, cleanup, err := rw.datasetFiles.LocalPath(ctx, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open dataset file: %w", err)
+		}
+		defer cleanup()
+		filePath = localPath
+	}
+	var resolver ports.MatrixResolverPort = excel.NewExcelMatrixResolverAdapter(excel.ExcelConfig{FilePath: filePath})
+	if rw.columnEnforcer != nil {
+		ctx = rw.columnEnforcer.ContextFor(ctx, userID)
+		resolver = access.NewEnforcingResolver(resolver, rw.columnEnforcer)
+	}
+	return resolver.ResolveMatrix(ctx, req)
+}
+
+// completeRows keeps the rows where both x and y have a value; the senses do not skip missing values
+func completeRows(x, y []float64) ([]float64, []float64) {
+	n := min(len(x), len(y))
+	xs, ys := make([]float64, 0, n), make([]float64, 0, n)
+	for i := 0; i < n; i++ {
+		if math.IsNaN(x[i]) || math.IsNaN(y[i]) {
+			continue
+		}
+		xs = append(xs, x[i])
+		ys = append(ys, y[i])
+	}
+	return xs, ys
+}
@@ -0,0 +1,125 @@
+package research
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gohypo/models"
+)
+
+// ErrRetestInProgress is returned when a hypothesis is already being re-tested
+var ErrRetestInProgress = errors.New("a re-test of this hypothesis is already running")
+
+type hypothesisStore interface {
+	hypothesisLoader
+	SaveHypothesis(ctx context.Context, result *models.HypothesisResult) error
+}
+
+// Retester re-runs one hypothesis' resolution and referees against the data as it is now,
+// without a research session, and keeps the verdicts it reaches in the hypothesis' history
+type Retester struct {
+	store     hypothesisStore
+	validator hypothesisRevalidator
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+// RetestResult is the verdict a re-test reached next to the one it replaced
+type RetestResult struct {
+	Hypothesis *models.HypothesisResult `json:"hypothesis"`
+	Previous   models.VerdictRecord     `json:"previous"`
+	Current    models.VerdictRecord     `json:"current"`
+	Changed    bool                     `json:"changed"` // the verdict flipped
+	History    []models.VerdictRecord   `json:"history"`
+}
+
+// NewRetester creates a retester validating through validator
+func NewRetester(store hypothesisStore, validator hypothesisRevalidator) *Retester {
+	return &Retester{store: store, validator: validator, running: make(map[string]bool)}
+}
+
+// Retest re-validates a hypothesis against the given dataset version and appends the verdict
+// to its history. When validation cannot run, the previous verdict and hypothesis are kept,
+// the failed attempt is recorded in the history and an error is returned.
+func (r *Retester) Retest(ctx context.Context, hypothesisID, datasetID string, datasetVersion *time.Time) (*RetestResult, error) {
+	r.mu.Lock()
+	if r.running[hypothesisID] {
+		r.mu.Unlock()
+		return nil, ErrRetestInProgress
+	}
+	r.running[hypothesisID] = true
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.running, hypothesisID)
+		r.mu.Unlock()
+	}()
+
+	before, err := r.store.GetByID(ctx, hypothesisID)
+	if err != nil || before == nil {
+		return nil, fmt.Errorf("failed to load hypothesis %s: %w", hypothesisID, err)
+	}
+	history := models.VerdictHistoryOf(before)
+	previous := history[len(history)-1]
+
+	r.validator.RevalidateHypothesis(ctx, before)
+	after, err := r.store.GetByID(ctx, hypothesisID)
+	if err != nil || after == nil {
+		return nil, fmt.Errorf("failed to reload hypothesis %s after re-test: %w", hypothesisID, err)
+	}
+
+	// A validation that could not run overwrites the hypothesis with a system error record;
+	// put the hypothesis back and keep the attempt in its history instead
+	if category, _ := after.ExecutionMetadata["error_category"].(string); category == "system_error" {
+		reason, _ := after.ExecutionMetadata["failure_reason"].(string)
+		attempt := previous
+		attempt.At, attempt.Trigger, attempt.Error = time.Now(), models.VerdictTriggerRetest, reason
+		attempt.DatasetID, attempt.DatasetVersion = datasetID, datasetVersion
+		if err := r.saveHistory(ctx, before, append(history, attempt)); err != nil {
+			log.Printf("[Retester] Failed to restore hypothesis %s: %v", hypothesisID, err)
+		}
+		return nil, fmt.Errorf("re-test of hypothesis %s could not run: %s", hypothesisID, reason)
+	}
+
+	current := models.VerdictRecordOf(after, models.VerdictTriggerRetest)
+	current.DatasetID, current.DatasetVersion = datasetID, datasetVersion
+	history = append(history, current)
+	if err := r.saveHistory(ctx, after, history); err != nil {
+		return nil, err
+	}
+	return &RetestResult{
+		Hypothesis: after,
+		Previous:   previous,
+		Current:    current,
+		Changed:    previous.Passed != current.Passed,
+		History:    history,
+	}, nil
+}
+
+// RevalidateHypothesis re-tests a hypothesis for the validation queue, so queued
+// re-validations land in its verdict history too
+func (r *Retester) RevalidateHypothesis(ctx context.Context, hypothesis *models.HypothesisResult) bool {
+	result, err := r.Retest(ctx, hypothesis.ID, "", nil)
+	if err != nil {
+		log.Printf("[Retester] Re-test of hypothesis %s failed: %v", hypothesis.ID, err)
+		return false
+	}
+	return result.Current.Passed
+}
+
+// saveHistory stores a hypothesis with its verdict history
+func (r *Retester) saveHistory(ctx context.Context, h *models.HypothesisResult, history []models.VerdictRecord) error {
+	if h.ExecutionMetadata == nil {
+		h.ExecutionMetadata = make(map[string]interface{})
+	}
+	h.ExecutionMetadata[models.VerdictHistoryKey] = history
+	if err := r.store.SaveHypothesis(ctx, h); err != nil {
+		return fmt.Errorf("failed to save verdict history of hypothesis %s: %w", h.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package research
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gohypo/models"
+)
+
+// memoryHypotheses stores hypotheses in memory; validate replaces the stored hypothesis the
+// way the worker's save does
+type memoryHypotheses struct {
+	stored   map[string]*models.HypothesisResult
+	validate func(h *models.HypothesisResult) *models.HypothesisResult
+}
+
+func (m *memoryHypotheses) GetByID(ctx context.Context, id string) (*models.HypothesisResult, error) {
+	copied := *m.stored[id]
+	return &copied, nil
+}
+
+func (m *memoryHypotheses) SaveHypothesis(ctx context.Context, h *models.HypothesisResult) error {
+	m.stored[h.ID] = h
+	return nil
+}
+
+func (m *memoryHypotheses) RevalidateHypothesis(ctx context.Context, h *models.HypothesisResult) bool {
+	after := m.validate(h)
+	m.stored[h.ID] = after
+	return after.Passed
+}
+
+// TestRetestAppendsVerdict verifies a re-test appends its verdict after the original one and
+// that a re-test which cannot run leaves the hypothesis as it was
+func TestRetestAppendsVerdict(t *testing.T) {
+	original := &models.HypothesisResult{
+		ID:                  "h1",
+		BusinessHypothesis:  "Discounts drive repeat purchases",
+		Passed:              true,
+		Confidence:          0.8,
+		ValidationTimestamp: time.Now().Add(-48 * time.Hour),
+		RefereeResults:      []models.RefereeResult{{GateName: "Permutation_Shredder", Passed: true}},
+		ExecutionMetadata:   map[string]interface{}{"cause_key": "discount", "effect_key": "repeat_rate"},
+	}
+	store := &memoryHypotheses{stored: map[string]*models.HypothesisResult{"h1": original}}
+	store.validate = func(h *models.HypothesisResult) *models.HypothesisResult {
+		return &models.HypothesisResult{
+			ID:                  h.ID,
+			BusinessHypothesis:  h.BusinessHypothesis,
+			Passed:              false,
+			Confidence:          0.3,
+			ValidationTimestamp: time.Now(),
+			RefereeResults:      []models.RefereeResult{{GateName: "Permutation_Shredder", Passed: false}},
+			ExecutionMetadata:   map[string]interface{}{"cause_key": "discount", "effect_key": "repeat_rate"},
+		}
+	}
+	retester := NewRetester(store, store)
+
+	version := time.Now()
+	result, err := retester.Retest(context.Background(), "h1", "ds-2", &version)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Changed || result.Previous.Trigger != models.VerdictTriggerValidation || result.Current.DatasetID != "ds-2" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	history := models.VerdictHistoryOf(store.stored["h1"])
+	if len(history) != 2 || !history[0].Passed || history[1].Passed || history[1].GatesPassed != 0 {
+		t.Errorf("expected the original and re-tested verdicts in the history, got %+v", history)
+	}
+
+	store.validate = func(h *models.HypothesisResult) *models.HypothesisResult {
+		return &models.HypothesisResult{
+			ID:                 h.ID,
+			BusinessHypothesis: "Failed to validate - Matrix loading failed",
+			ExecutionMetadata:  map[string]interface{}{"error_category": "system_error", "failure_reason": "Matrix loading failed"},
+		}
+	}
+	if _, err := retester.Retest(context.Background(), "h1", "ds-3", nil); err == nil {
+		t.Fatal("expected a re-test that could not run to fail")
+	}
+	kept := store.stored["h1"]
+	history = models.VerdictHistoryOf(kept)
+	if kept.BusinessHypothesis != "Discounts drive repeat purchases" || len(history) != 3 || history[2].Error == "" || history[2].Passed != history[1].Passed {
+		t.Errorf("expected the hypothesis to be kept and the attempt recorded, got %q with %+v", kept.BusinessHypothesis, history)
+	}
+}
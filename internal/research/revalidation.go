@@ -0,0 +1,85 @@
+package research
+
+import (
+	"context"
+	"strings"
+
+	"gohypo/internal/buildinfo"
+	"gohypo/models"
+)
+
+// PlanRevalidation finds the hypotheses whose verdicts were reached by referees or senses that
+// changed since, estimates the cost of re-validating each from its referees' past runtimes,
+// and orders them for re-validation. Hypotheses validated before engine versions were recorded
+// are included only with includeUnversioned. pending names the hypotheses already queued or
+// running, which are listed but not counted in the cost.
+func (t *ValidationTracker) PlanRevalidation(ctx context.Context, workspaceID string, hypotheses []*models.HypothesisResult, capacity int, includeUnversioned bool, pending map[string]bool) *models.RevalidationPlan {
+	plan := &models.RevalidationPlan{WorkspaceID: workspaceID, Scanned: len(hypotheses), Capacity: capacity, Hypotheses: []models.StaleHypothesis{}}
+	for _, h := range hypotheses {
+		recorded := models.EngineVersionsOf(h)
+		changed := buildinfo.ChangedEngines(recorded)
+		unversioned := recorded == nil
+		if len(changed) == 0 && !(unversioned && includeUnversioned) {
+			continue
+		}
+		stale := models.StaleHypothesis{
+			HypothesisID: h.ID,
+			Title:        h.BusinessHypothesis,
+			Passed:       h.Passed,
+			Confidence:   h.Confidence,
+			ValidatedAt:  h.ValidationTimestamp,
+			Changed:      changed,
+			Unversioned:  unversioned,
+			Pending:      pending[h.ID],
+		}
+		if stale.Changed == nil {
+			stale.Changed = []string{}
+		}
+		if !stale.Pending {
+			stale.EstimatedSeconds, stale.Unestimated = t.estimateBattery(ctx, h)
+		}
+		plan.Add(stale)
+	}
+	plan.Prioritize()
+	return plan
+}
+
+// estimateBattery sums the expected runtimes of a hypothesis' referees at its sample size and
+// counts the referees with no runtime history to estimate from
+func (t *ValidationTracker) estimateBattery(ctx context.Context, h *models.HypothesisResult) (float64, int) {
+	referees := make([]string, 0, len(h.RefereeResults))
+	for _, result := range h.RefereeResults {
+		referees = append(referees, strings.ToLower(result.GateName))
+	}
+	t.loadHistory(ctx, referees)
+	sampleSize, _ := toFloat64(h.ExecutionMetadata["sample_size"])
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var seconds float64
+	unestimated := 0
+	for _, referee := range referees {
+		if estimate, ok := models.EstimateRefereeRuntime(t.history[referee], int(sampleSize)); ok {
+			seconds += estimate.Seconds()
+		} else {
+			unestimated++
+		}
+	}
+	return seconds, unestimated
+}
+
+// PlanRevalidation plans the re-validation of a workspace's hypotheses made stale by referee or
+// sense upgrades; see ValidationTracker.PlanRevalidation
+func (rw *ResearchWorker) PlanRevalidation(ctx context.Context, workspaceID string, hypotheses []*models.HypothesisResult, capacity int, includeUnversioned bool, pending map[string]bool) *models.RevalidationPlan {
+	return rw.validationProgress.PlanRevalidation(ctx, workspaceID, hypotheses, capacity, includeUnversioned, pending)
+}
+
+// engineVersions are the versions of the referees that reached a verdict and of the senses, for
+// recording with it
+func engineVersions(results []models.RefereeResult) map[string]string {
+	gates := make([]string, 0, len(results))
+	for _, result := range results {
+		gates = append(gates, result.GateName)
+	}
+	return buildinfo.Engines(gates)
+}
@@ -0,0 +1,75 @@
+package research
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gohypo/internal/buildinfo"
+	"gohypo/models"
+)
+
+func validatedWith(id string, passed bool, confidence float64, versions map[string]string) *models.HypothesisResult {
+	h := &models.HypothesisResult{
+		ID:                  id,
+		Passed:              passed,
+		Confidence:          confidence,
+		ValidationTimestamp: time.Unix(1_700_000_000, 0),
+		RefereeResults:      []models.RefereeResult{{GateName: "Permutation_Shredder"}, {GateName: "Transfer_Entropy"}},
+		ExecutionMetadata:   map[string]interface{}{"sample_size": 1000},
+	}
+	if versions != nil {
+		h.ExecutionMetadata[models.EngineVersionsKey] = versions
+	}
+	return h
+}
+
+// TestPlanRevalidationFindsStaleHypotheses verifies only hypotheses validated with since
+// changed engines are planned, validated findings first, with a cost from past runtimes
+func TestPlanRevalidationFindsStaleHypotheses(t *testing.T) {
+	store := &memoryRefereeRuns{}
+	for i := 0; i < 3; i++ {
+		store.runs = append(store.runs, &models.RefereeRun{
+			ID: fmt.Sprintf("run%d", i), Referee: "permutation_shredder", SampleSize: 1000,
+			Status: models.RefereeRunCompleted, DurationMs: 2000,
+		})
+	}
+	tracker := NewValidationTracker(store, nil)
+
+	current := buildinfo.Engines([]string{"Permutation_Shredder", "Transfer_Entropy"})
+	outdated := buildinfo.Engines([]string{"Permutation_Shredder", "Transfer_Entropy"})
+	outdated["referee/transfer_entropy"] = "0.1.0"
+
+	hypotheses := []*models.HypothesisResult{
+		validatedWith("current", true, 0.9, current),
+		validatedWith("rejected", false, 0.2, outdated),
+		validatedWith("finding", true, 0.6, outdated),
+		validatedWith("legacy", true, 0.8, nil),
+		validatedWith("queued", true, 0.7, outdated),
+	}
+	plan := tracker.PlanRevalidation(context.Background(), "ws1", hypotheses, 2, false, map[string]bool{"queued": true})
+
+	var order []string
+	for _, h := range plan.Hypotheses {
+		order = append(order, h.HypothesisID)
+	}
+	if len(order) != 3 || order[0] != "queued" || order[1] != "finding" || order[2] != "rejected" {
+		t.Fatalf("expected queued, finding, rejected in priority order, got %v", order)
+	}
+	if changed := plan.Hypotheses[1].Changed; len(changed) != 1 || changed[0] != "referee/transfer_entropy 0.1.0 → "+buildinfo.RefereeVersions["transfer_entropy"] {
+		t.Errorf("unexpected changes %v", changed)
+	}
+	// Two unqueued hypotheses of 2s of shredder each; transfer entropy has no history
+	if plan.EstimatedSeconds != 4 || plan.EstimatedWallSeconds != 2 || plan.Unestimated != 2 {
+		t.Errorf("unexpected cost %+v", plan)
+	}
+	if ids := plan.IDs(); len(ids) != 2 || ids[0] != "finding" {
+		t.Errorf("expected the pending hypothesis left out of the queue, got %v", ids)
+	}
+
+	withLegacy := tracker.PlanRevalidation(context.Background(), "ws1", hypotheses, 2, true, nil)
+	if len(withLegacy.Hypotheses) != 4 || withLegacy.Hypotheses[0].HypothesisID != "legacy" || !withLegacy.Hypotheses[0].Unversioned {
+		t.Fatalf("expected the unversioned finding planned first, got %+v", withLegacy.Hypotheses)
+	}
+}
@@ -2,6 +2,7 @@ package research
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -135,6 +136,22 @@ func (sm *SessionManager) SetSessionError(ctx context.Context, sessionID string,
 	return sm.sessionRepo.SetSessionError(ctx, user.ID, sessionUUID, errMsg)
 }
 
+// MergeSessionMetadata replaces one metadata key with merge's result over its stored value,
+// atomically with respect to other merges of the session
+func (sm *SessionManager) MergeSessionMetadata(ctx context.Context, sessionID string, key string, merge func(current json.RawMessage) (interface{}, error)) error {
+	user, err := sm.userRepo.GetOrCreateDefaultUser(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get default user: %w", err)
+	}
+
+	sessionUUID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	return sm.sessionRepo.MergeSessionMetadata(ctx, user.ID, sessionUUID, key, merge)
+}
+
 // GetActiveSessions returns all sessions that are not complete or errored
 func (sm *SessionManager) GetActiveSessions(ctx context.Context) ([]*models.ResearchSession, error) {
 	user, err := sm.userRepo.GetOrCreateDefaultUser(ctx)
@@ -0,0 +1,83 @@
+package research
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"time"
+
+	"gohypo/domain/core"
+	"gohypo/domain/stats"
+	"gohypo/models"
+
+	"github.com/google/uuid"
+)
+
+// SkepticReanalysis re-analyzes a hypothesis' cause and effect on the current data with the
+// most conservative choices under its workspace's policy, and keeps the before/after
+// comparison on the hypothesis. The verdict itself is left alone: skeptic mode is a
+// robustness check for readers, not a re-test.
+func (rw *ResearchWorker) SkepticReanalysis(ctx context.Context, hypothesis *models.HypothesisResult) (*models.SkepticRun, error) {
+	directive := models.DirectiveFromHypothesis(hypothesis)
+	if directive.CauseKey == "" || directive.EffectKey == "" {
+		return nil, fmt.Errorf("hypothesis %s does not name a cause and effect variable", hypothesis.ID)
+	}
+	bundle, err := rw.loadMatrixBundleForHypothesisWithContext(ctx, directive)
+	if err != nil {
+		return nil, err
+	}
+	x, ok := bundle.GetColumnData(core.VariableKey(directive.CauseKey))
+	y, ok2 := bundle.GetColumnData(core.VariableKey(directive.EffectKey))
+	if !ok || !ok2 {
+		return nil, fmt.Errorf("variable data not found: cause=%s, effect=%s", directive.CauseKey, directive.EffectKey)
+	}
+
+	workspaceID, _ := uuid.Parse(hypothesis.WorkspaceID)
+	policy := rw.analysisPolicy(ctx, workspaceID)
+	report, err := stats.SkepticReanalysis(x, y, policy, skepticSeed(hypothesis.ID))
+	if err != nil {
+		return nil, err
+	}
+	run := &models.SkepticRun{
+		At:        time.Now(),
+		CauseKey:  directive.CauseKey,
+		EffectKey: directive.EffectKey,
+		Policy:    policy,
+		Report:    report,
+	}
+	if rw.testkit != nil {
+		stored := *run // the ledger keeps the run as it was fingerprinted
+		artifact := core.Artifact{
+			ID:        core.ID(fmt.Sprintf("skeptic_%s_%d", hypothesis.ID, run.At.UnixNano())),
+			Kind:      core.ArtifactSkepticReanalysis,
+			Payload:   &stored,
+			CreatedAt: core.Now(),
+		}
+		if err := rw.testkit.LedgerAdapter().StoreArtifact(ctx, hypothesis.SessionID, artifact); err != nil {
+			slog.WarnContext(ctx, "Failed to store skeptic-mode artifact", "hypothesis_id", hypothesis.ID, "error", err)
+		} else {
+			provenance := models.ArtifactProvenance(artifact)
+			run.Provenance = &provenance
+		}
+	}
+
+	if hypothesis.ExecutionMetadata == nil {
+		hypothesis.ExecutionMetadata = make(map[string]interface{})
+	}
+	hypothesis.ExecutionMetadata[models.SkepticModeKey] = run
+	if err := rw.storage.SaveHypothesis(ctx, hypothesis); err != nil {
+		return nil, fmt.Errorf("failed to save skeptic-mode result of hypothesis %s: %w", hypothesis.ID, err)
+	}
+	slog.InfoContext(ctx, "Skeptic mode completed", "hypothesis_id", hypothesis.ID, "survives", report.Survives,
+		"effect_before", report.Before.Effect, "effect_after", report.After.Effect)
+	return run, nil
+}
+
+// skepticSeed derives the permutation seed from the hypothesis, so re-running skeptic mode on
+// unchanged data reports the same result
+func skepticSeed(hypothesisID string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(hypothesisID))
+	return int64(h.Sum64())
+}
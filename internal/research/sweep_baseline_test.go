@@ -0,0 +1,59 @@
+package research
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gohypo/app"
+	"gohypo/domain/core"
+	"gohypo/internal/testkit"
+)
+
+// TestSweepBaselineRoundTripsThroughLedger verifies the baseline for a dataset and user is
+// read back from the sweep recorded in the ledger, that the newest sweep wins and that other
+// keys see no baseline
+func TestSweepBaselineRoundTripsThroughLedger(t *testing.T) {
+	kit, err := testkit.NewTestKit()
+	if err != nil {
+		t.Fatalf("NewTestKit: %v", err)
+	}
+	rw := &ResearchWorker{testkit: kit}
+	ctx := context.Background()
+
+	if baseline := rw.sweepBaseline(ctx, "ds-1:user-1"); baseline != nil {
+		t.Fatalf("expected no baseline before any sweep, got %+v", baseline)
+	}
+
+	sweep := func(runID string, pairs int) {
+		resp := &app.StatsSweepResponse{
+			Manifest: core.Artifact{ID: "manifest", Kind: core.ArtifactSweepManifest, Payload: map[string]interface{}{"run_id": runID}},
+		}
+		for i := 0; i < pairs; i++ {
+			resp.Relationships = append(resp.Relationships, core.Artifact{
+				ID: core.ID("rel-" + string(rune('a'+i))), Kind: core.ArtifactRelationship, Payload: map[string]interface{}{},
+			})
+		}
+		if !rw.recordSweepResults(ctx, runID, resp) {
+			t.Fatalf("failed to record %s", runID)
+		}
+		rw.storeSweepBaseline(ctx, "ds-1:user-1", runID)
+		time.Sleep(time.Millisecond)
+	}
+	sweep("sweep-first", 1)
+	sweep("sweep-second", 2)
+
+	baseline := rw.sweepBaseline(ctx, "ds-1:user-1")
+	if baseline == nil {
+		t.Fatal("expected a baseline after a recorded sweep")
+	}
+	if runID := baseline.Manifest.Payload.(map[string]interface{})["run_id"]; runID != "sweep-second" {
+		t.Errorf("expected the newest sweep as baseline, got %v", runID)
+	}
+	if len(baseline.Relationships) != 2 {
+		t.Errorf("expected the newest sweep's 2 relationships, got %d", len(baseline.Relationships))
+	}
+	if other := rw.sweepBaseline(ctx, "ds-1:user-2"); other != nil {
+		t.Errorf("expected another user to get no baseline, got %+v", other)
+	}
+}
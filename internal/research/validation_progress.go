@@ -0,0 +1,321 @@
+package research
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"gohypo/internal/api"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// refereeHistoryLimit is how many recent runs of a referee its runtime estimates draw from
+const refereeHistoryLimit = 200
+
+// ValidationTracker follows running referee batteries. It records when each referee starts
+// and finishes, estimates how long the rest of a battery will take from the referees' past
+// runtimes on similar sample sizes, and broadcasts each start with the battery's ETA.
+// Without a store, runs and history are kept in memory only.
+type ValidationTracker struct {
+	store  ports.RefereeRunStore
+	events eventBroadcaster
+	now    func() time.Time
+
+	mu        sync.Mutex
+	batteries map[string]*models.ValidationProgress // by session, then hypothesis
+	history   map[string][]*models.RefereeRun       // finished runs by referee, oldest first
+	loaded    map[string]bool                       // referees whose stored history was read
+	sessions  map[string][]*models.RefereeRun       // runs by session, when there is no store
+}
+
+// NewValidationTracker creates a tracker; store and events may be nil
+func NewValidationTracker(store ports.RefereeRunStore, events eventBroadcaster) *ValidationTracker {
+	return &ValidationTracker{
+		store:     store,
+		events:    events,
+		now:       time.Now,
+		batteries: make(map[string]*models.ValidationProgress),
+		history:   make(map[string][]*models.RefereeRun),
+		loaded:    make(map[string]bool),
+		sessions:  make(map[string][]*models.RefereeRun),
+	}
+}
+
+// Battery is one hypothesis' referees as they run
+type Battery struct {
+	tracker  *ValidationTracker
+	key      string
+	progress *models.ValidationProgress
+	runs     map[string]*models.RefereeRun
+}
+
+func batteryKey(sessionID, hypothesisID string) string {
+	return sessionID + "/" + hypothesisID
+}
+
+// Begin starts tracking a hypothesis' battery with its referees pending
+func (t *ValidationTracker) Begin(ctx context.Context, sessionID, hypothesisID string, referees []string, sampleSize int) *Battery {
+	t.loadHistory(ctx, referees)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	progress := &models.ValidationProgress{
+		SessionID:    sessionID,
+		HypothesisID: hypothesisID,
+		SampleSize:   sampleSize,
+		StartedAt:    t.now(),
+	}
+	b := &Battery{tracker: t, key: batteryKey(sessionID, hypothesisID), progress: progress, runs: make(map[string]*models.RefereeRun)}
+	for _, referee := range referees {
+		b.addLocked(referee)
+	}
+	progress.UpdateETA(t.now())
+	t.batteries[b.key] = progress
+	return b
+}
+
+// Add appends a referee the battery decided to run after it began
+func (b *Battery) Add(ctx context.Context, referee string) {
+	b.tracker.loadHistory(ctx, []string{referee})
+	b.tracker.mu.Lock()
+	defer b.tracker.mu.Unlock()
+	b.addLocked(referee)
+}
+
+func (b *Battery) addLocked(referee string) {
+	entry := models.RefereeProgress{Referee: referee, Status: models.RefereeRunPending}
+	if estimate, ok := models.EstimateRefereeRuntime(b.tracker.history[referee], b.progress.SampleSize); ok {
+		seconds := estimate.Seconds()
+		entry.EstimatedSeconds = &seconds
+	}
+	b.progress.Referees = append(b.progress.Referees, entry)
+	b.progress.Total++
+}
+
+// Start records that a referee began and broadcasts the battery's progress
+func (b *Battery) Start(ctx context.Context, referee string) {
+	t := b.tracker
+	t.mu.Lock()
+	run := &models.RefereeRun{
+		SessionID:    b.progress.SessionID,
+		HypothesisID: b.progress.HypothesisID,
+		Referee:      referee,
+		SampleSize:   b.progress.SampleSize,
+		Status:       models.RefereeRunRunning,
+		StartedAt:    t.now(),
+	}
+	b.runs[referee] = run
+	var estimate *float64
+	if entry := b.entryLocked(referee); entry != nil {
+		entry.Status = models.RefereeRunRunning
+		entry.StartedAt = &run.StartedAt
+		estimate = entry.EstimatedSeconds
+	}
+	b.progress.UpdateETA(t.now())
+	snapshot := snapshotProgress(b.progress)
+	if t.store == nil {
+		t.sessions[run.SessionID] = append(t.sessions[run.SessionID], run)
+	}
+	t.mu.Unlock()
+
+	if t.store != nil {
+		if err := t.store.StartRun(ctx, run); err != nil {
+			log.Printf("[ResearchWorker] ⚠️ %v", err)
+		}
+	}
+
+	data := progressEventData(snapshot)
+	data["referee_name"] = referee
+	if estimate != nil {
+		data["estimated_seconds"] = *estimate
+	}
+	t.broadcast(snapshot, "referee_started", data)
+}
+
+// Finish records a referee's outcome and returns the battery's progress after it. A
+// referee that could not run is recorded as failed, whatever its result says.
+func (b *Battery) Finish(ctx context.Context, referee string, result models.RefereeResult, duration time.Duration, runErr error) models.ValidationProgress {
+	t := b.tracker
+	t.mu.Lock()
+	run, ok := b.runs[referee]
+	if !ok { // finished without a recorded start
+		run = &models.RefereeRun{
+			SessionID:    b.progress.SessionID,
+			HypothesisID: b.progress.HypothesisID,
+			Referee:      referee,
+			SampleSize:   b.progress.SampleSize,
+			StartedAt:    t.now().Add(-duration),
+		}
+		b.runs[referee] = run
+		if t.store == nil {
+			t.sessions[run.SessionID] = append(t.sessions[run.SessionID], run)
+		}
+	}
+	finished := run.StartedAt.Add(duration)
+	passed := result.Passed
+	run.Status = models.RefereeRunCompleted
+	if runErr != nil {
+		run.Status = models.RefereeRunFailed
+	}
+	run.Passed = &passed
+	run.FinishedAt = &finished
+	run.DurationMs = duration.Milliseconds()
+
+	if entry := b.entryLocked(referee); entry != nil {
+		entry.Status = run.Status
+		entry.Passed = &passed
+		entry.DurationSeconds = duration.Seconds()
+	}
+	b.progress.Completed++
+	b.progress.UpdateETA(t.now())
+	t.history[referee] = appendHistory(t.history[referee], run)
+	snapshot := snapshotProgress(b.progress)
+	t.mu.Unlock()
+
+	if t.store != nil && ok {
+		if err := t.store.FinishRun(ctx, run); err != nil {
+			log.Printf("[ResearchWorker] ⚠️ %v", err)
+		}
+	}
+	return snapshot
+}
+
+// Done stops tracking the battery
+func (b *Battery) Done() {
+	b.tracker.mu.Lock()
+	defer b.tracker.mu.Unlock()
+	delete(b.tracker.batteries, b.key)
+}
+
+func (b *Battery) entryLocked(referee string) *models.RefereeProgress {
+	for i := range b.progress.Referees {
+		if b.progress.Referees[i].Referee == referee {
+			return &b.progress.Referees[i]
+		}
+	}
+	return nil
+}
+
+// SessionProgress returns a session's running batteries and its recorded referee runs.
+// Recorded runs still marked running that no battery is executing were cut short.
+func (t *ValidationTracker) SessionProgress(ctx context.Context, sessionID string) (*models.SessionValidationProgress, error) {
+	var runs []*models.RefereeRun
+	if t.store != nil {
+		stored, err := t.store.ListSessionRuns(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		runs = stored
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.store == nil {
+		for _, run := range t.sessions[sessionID] {
+			copied := *run
+			runs = append(runs, &copied)
+		}
+	}
+	result := &models.SessionValidationProgress{SessionID: sessionID, Active: []models.ValidationProgress{}, Runs: runs}
+	active := make(map[string]bool)
+	for key, progress := range t.batteries {
+		if progress.SessionID != sessionID {
+			continue
+		}
+		progress.UpdateETA(t.now())
+		result.Active = append(result.Active, snapshotProgress(progress))
+		active[key] = true
+	}
+	sort.Slice(result.Active, func(i, j int) bool { return result.Active[i].StartedAt.Before(result.Active[j].StartedAt) })
+	for _, run := range runs {
+		if run.Status == models.RefereeRunRunning && !active[batteryKey(run.SessionID, run.HypothesisID)] {
+			run.Status = models.RefereeRunInterrupted
+		}
+	}
+	if result.Runs == nil {
+		result.Runs = []*models.RefereeRun{}
+	}
+	return result, nil
+}
+
+// loadHistory reads the stored runtimes of referees not seen yet
+func (t *ValidationTracker) loadHistory(ctx context.Context, referees []string) {
+	if t.store == nil {
+		return
+	}
+	for _, referee := range referees {
+		t.mu.Lock()
+		loaded := t.loaded[referee]
+		t.loaded[referee] = true
+		t.mu.Unlock()
+		if loaded {
+			continue
+		}
+		runs, err := t.store.RecentRuns(ctx, referee, refereeHistoryLimit)
+		if err != nil {
+			log.Printf("[ResearchWorker] ⚠️ No runtime history for referee %s: %v", referee, err)
+			continue
+		}
+		t.mu.Lock()
+		for i := len(runs) - 1; i >= 0; i-- { // stored newest first
+			t.history[referee] = appendHistory(t.history[referee], runs[i])
+		}
+		t.mu.Unlock()
+	}
+}
+
+func (t *ValidationTracker) broadcast(progress models.ValidationProgress, eventType string, data map[string]interface{}) {
+	if t.events == nil {
+		return
+	}
+	t.events.Broadcast(api.ResearchEvent{
+		SessionID:    progress.SessionID,
+		EventType:    eventType,
+		HypothesisID: progress.HypothesisID,
+		Progress:     refereeEventProgress(progress),
+		Data:         data,
+		Timestamp:    time.Now(),
+	})
+}
+
+// refereeEventProgress places a battery within the 50-90% band validation reports in
+func refereeEventProgress(progress models.ValidationProgress) float64 {
+	return 50.0 + progress.Percent()*0.4
+}
+
+// progressEventData is the battery state every referee event carries
+func progressEventData(progress models.ValidationProgress) map[string]interface{} {
+	data := map[string]interface{}{
+		"hypothesis_id":   progress.HypothesisID,
+		"completed":       progress.Completed,
+		"total":           progress.Total,
+		"elapsed_seconds": progress.ElapsedSeconds,
+	}
+	if progress.ETASeconds != nil {
+		data["eta_seconds"] = *progress.ETASeconds
+	}
+	return data
+}
+
+// snapshotProgress copies progress so it can leave the lock
+func snapshotProgress(progress *models.ValidationProgress) models.ValidationProgress {
+	snapshot := *progress
+	snapshot.Referees = append([]models.RefereeProgress(nil), progress.Referees...)
+	if progress.ETASeconds != nil {
+		eta := *progress.ETASeconds
+		snapshot.ETASeconds = &eta
+	}
+	return snapshot
+}
+
+// appendHistory keeps the most recent refereeHistoryLimit finished runs
+func appendHistory(history []*models.RefereeRun, run *models.RefereeRun) []*models.RefereeRun {
+	history = append(history, run)
+	if len(history) > refereeHistoryLimit {
+		history = history[len(history)-refereeHistoryLimit:]
+	}
+	return history
+}
@@ -0,0 +1,141 @@
+package research
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"gohypo/internal/api"
+	"gohypo/models"
+)
+
+type memoryRefereeRuns struct {
+	mu   sync.Mutex
+	runs []*models.RefereeRun
+}
+
+func (m *memoryRefereeRuns) StartRun(ctx context.Context, run *models.RefereeRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if run.ID == "" {
+		run.ID = run.HypothesisID + "/" + run.Referee
+	}
+	copied := *run
+	m.runs = append(m.runs, &copied)
+	return nil
+}
+
+func (m *memoryRefereeRuns) FinishRun(ctx context.Context, run *models.RefereeRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, stored := range m.runs {
+		if stored.ID == run.ID {
+			copied := *run
+			m.runs[i] = &copied
+		}
+	}
+	return nil
+}
+
+func (m *memoryRefereeRuns) ListSessionRuns(ctx context.Context, sessionID string) ([]*models.RefereeRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var runs []*models.RefereeRun
+	for _, run := range m.runs {
+		if run.SessionID == sessionID {
+			copied := *run
+			runs = append(runs, &copied)
+		}
+	}
+	return runs, nil
+}
+
+func (m *memoryRefereeRuns) RecentRuns(ctx context.Context, referee string, limit int) ([]*models.RefereeRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var runs []*models.RefereeRun
+	for i := len(m.runs) - 1; i >= 0 && len(runs) < limit; i-- {
+		if m.runs[i].Referee == referee && m.runs[i].Finished() {
+			runs = append(runs, m.runs[i])
+		}
+	}
+	return runs, nil
+}
+
+type recordedEvents struct {
+	mu     sync.Mutex
+	events []api.ResearchEvent
+}
+
+func (r *recordedEvents) Broadcast(event api.ResearchEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// TestValidationTrackerRecordsAndEstimates verifies referee starts and finishes are stored,
+// starts are broadcast, and a later battery estimates from the stored runtimes
+func TestValidationTrackerRecordsAndEstimates(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryRefereeRuns{}
+	events := &recordedEvents{}
+	tracker := NewValidationTracker(store, events)
+
+	battery := tracker.Begin(ctx, "s1", "h1", []string{"permutation", "bootstrap"}, 1000)
+	if progress, _ := tracker.SessionProgress(ctx, "s1"); len(progress.Active) != 1 || progress.Active[0].ETASeconds != nil {
+		t.Fatalf("expected one battery without an ETA, got %+v", progress.Active)
+	}
+	battery.Start(ctx, "permutation")
+	battery.Start(ctx, "bootstrap")
+	battery.Finish(ctx, "permutation", models.RefereeResult{Passed: true}, 2*time.Second, nil)
+	progress := battery.Finish(ctx, "bootstrap", models.RefereeResult{}, 4*time.Second, errors.New("no such referee"))
+	battery.Done()
+
+	if progress.Completed != 2 || progress.Percent() != 100 {
+		t.Errorf("unexpected final progress %+v", progress)
+	}
+	if len(events.events) != 2 || events.events[0].EventType != "referee_started" || events.events[0].Data["total"] != 2 {
+		t.Errorf("expected two start events, got %+v", events.events)
+	}
+	session, err := tracker.SessionProgress(ctx, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(session.Active) != 0 || len(session.Runs) != 2 {
+		t.Fatalf("unexpected session progress %+v", session)
+	}
+	if session.Runs[0].Status != models.RefereeRunCompleted || session.Runs[0].DurationMs != 2000 || session.Runs[1].Status != models.RefereeRunFailed {
+		t.Errorf("unexpected stored runs %+v %+v", session.Runs[0], session.Runs[1])
+	}
+
+	// A fresh tracker reads the stored runtimes: 2s on 1000 rows predicts 4s on 2000
+	restarted := NewValidationTracker(store, nil)
+	next := restarted.Begin(ctx, "s2", "h2", []string{"permutation"}, 2000)
+	next.Start(ctx, "permutation")
+	active, _ := restarted.SessionProgress(ctx, "s2")
+	estimate := active.Active[0].Referees[0].EstimatedSeconds
+	if estimate == nil || *estimate != 4 {
+		t.Fatalf("estimate = %v", estimate)
+	}
+	if eta := active.Active[0].ETASeconds; eta == nil || *eta > 4 || *eta < 3 {
+		t.Errorf("eta = %v", eta)
+	}
+}
+
+// TestValidationTrackerReportsInterruptedRuns verifies runs left running by a previous
+// process are reported as interrupted
+func TestValidationTrackerReportsInterruptedRuns(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryRefereeRuns{}
+	NewValidationTracker(store, nil).Begin(ctx, "s1", "h1", []string{"permutation"}, 100).Start(ctx, "permutation")
+
+	session, err := NewValidationTracker(store, nil).SessionProgress(ctx, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(session.Runs) != 1 || session.Runs[0].Status != models.RefereeRunInterrupted {
+		t.Errorf("expected an interrupted run, got %+v", session.Runs)
+	}
+}
@@ -0,0 +1,453 @@
+package research
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"gohypo/internal/api"
+	"gohypo/internal/metrics"
+	"gohypo/models"
+
+	"github.com/google/uuid"
+)
+
+// Priorities of a batch of queued validations; within a priority, batches run in the order
+// they were queued and hypotheses in the order they were listed
+const (
+	QueuePriorityHigh   = "high"
+	QueuePriorityNormal = "normal"
+	QueuePriorityLow    = "low"
+)
+
+// States of a queued validation
+const (
+	QueueStatusQueued    = "queued"
+	QueueStatusRunning   = "running"
+	QueueStatusPassed    = "passed"
+	QueueStatusFailed    = "failed"
+	QueueStatusCancelled = "cancelled"
+)
+
+// Workspace capacity: how many queued validations of one workspace run at once. Workspaces
+// set it in their metadata under ValidationCapacityKey.
+const (
+	ValidationCapacityKey     = "validation_capacity"
+	DefaultValidationCapacity = 2
+	MaxValidationCapacity     = 8
+)
+
+// queueHistory is how many finished validations a workspace queue keeps for status requests
+const queueHistory = 200
+
+var queuePriorityRank = map[string]int{QueuePriorityHigh: 0, QueuePriorityNormal: 1, QueuePriorityLow: 2}
+
+// QueuedValidation is one hypothesis waiting for, undergoing or done with re-validation
+type QueuedValidation struct {
+	HypothesisID string     `json:"hypothesis_id"`
+	BatchID      string     `json:"batch_id"`
+	Priority     string     `json:"priority"`
+	Position     int        `json:"position"` // place within its batch, from 1
+	Status       string     `json:"status"`
+	Passed       *bool      `json:"passed,omitempty"`
+	Confidence   float64    `json:"confidence,omitempty"`
+	EnqueuedAt   time.Time  `json:"enqueued_at"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+
+	workspaceID string
+	batchSeq    int64
+}
+
+type hypothesisLoader interface {
+	GetByID(ctx context.Context, id string) (*models.HypothesisResult, error)
+}
+
+type hypothesisRevalidator interface {
+	RevalidateHypothesis(ctx context.Context, hypothesis *models.HypothesisResult) bool
+}
+
+type eventBroadcaster interface {
+	Broadcast(event api.ResearchEvent)
+}
+
+// workspaceValidations is the queue of one workspace
+type workspaceValidations struct {
+	capacity int
+	running  int
+	items    []*QueuedValidation
+}
+
+// ValidationQueue re-validates hypotheses users pick from the list, in the order they chose,
+// never running more of a workspace's validations at once than its capacity, nor more
+// validations overall than the pool size an Autoscaler sets. Every start and verdict is
+// broadcast under the workspace's ValidationQueueTopic.
+type ValidationQueue struct {
+	loader    hypothesisLoader
+	validator hypothesisRevalidator
+	events    eventBroadcaster
+
+	mu         sync.Mutex
+	batchSeq   int64
+	workspaces map[string]*workspaceValidations
+	poolSize   int // validations running at once across workspaces; 0 = only workspace capacities limit
+	running    int
+}
+
+// QueueDepth is how much validation work is waiting and running across workspaces
+type QueueDepth struct {
+	Queued   int `json:"queued"`
+	Running  int `json:"running"`
+	PoolSize int `json:"pool_size"` // 0 when unlimited
+}
+
+// NewValidationQueue creates an empty queue; events may be nil
+func NewValidationQueue(loader hypothesisLoader, validator hypothesisRevalidator, events eventBroadcaster) *ValidationQueue {
+	return &ValidationQueue{
+		loader:     loader,
+		validator:  validator,
+		events:     events,
+		workspaces: make(map[string]*workspaceValidations),
+	}
+}
+
+// ValidationQueueTopic is the SSE topic a workspace's queue events are broadcast under
+func ValidationQueueTopic(workspaceID string) string {
+	return "validation-queue:" + workspaceID
+}
+
+// ValidationCapacity reads a workspace's capacity from its metadata, clamped to
+// 1..MaxValidationCapacity
+func ValidationCapacity(metadata map[string]interface{}) int {
+	capacity := DefaultValidationCapacity
+	switch v := metadata[ValidationCapacityKey].(type) {
+	case float64:
+		capacity = int(v)
+	case int:
+		capacity = v
+	case string:
+		if parsed, err := strconv.Atoi(v); err == nil {
+			capacity = parsed
+		}
+	}
+	if capacity < 1 {
+		return 1
+	}
+	if capacity > MaxValidationCapacity {
+		return MaxValidationCapacity
+	}
+	return capacity
+}
+
+// Enqueue queues hypotheses of a workspace as one batch, in the order given. Hypotheses
+// already queued or running are skipped. It returns the batch ID and the queued items.
+func (q *ValidationQueue) Enqueue(workspaceID string, hypothesisIDs []string, priority string, capacity int) (string, []QueuedValidation, error) {
+	if priority == "" {
+		priority = QueuePriorityNormal
+	}
+	if _, ok := queuePriorityRank[priority]; !ok {
+		return "", nil, fmt.Errorf("priority must be %s, %s or %s", QueuePriorityHigh, QueuePriorityNormal, QueuePriorityLow)
+	}
+	if len(hypothesisIDs) == 0 {
+		return "", nil, fmt.Errorf("no hypotheses to validate")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ws := q.workspaces[workspaceID]
+	if ws == nil {
+		ws = &workspaceValidations{}
+		q.workspaces[workspaceID] = ws
+	}
+	ws.capacity = capacity
+
+	active := make(map[string]bool)
+	for _, item := range ws.items {
+		if item.Status == QueueStatusQueued || item.Status == QueueStatusRunning {
+			active[item.HypothesisID] = true
+		}
+	}
+
+	q.batchSeq++
+	batchID := uuid.New().String()
+	now := time.Now()
+	var queued []QueuedValidation
+	for _, id := range hypothesisIDs {
+		if active[id] {
+			continue
+		}
+		active[id] = true
+		item := &QueuedValidation{
+			HypothesisID: id,
+			BatchID:      batchID,
+			Priority:     priority,
+			Position:     len(queued) + 1,
+			Status:       QueueStatusQueued,
+			EnqueuedAt:   now,
+			workspaceID:  workspaceID,
+			batchSeq:     q.batchSeq,
+		}
+		ws.items = append(ws.items, item)
+		queued = append(queued, *item)
+	}
+	log.Printf("[ValidationQueue] Queued %d of %d hypotheses for workspace %s (batch %s, %s priority, capacity %d)",
+		len(queued), len(hypothesisIDs), workspaceID, batchID, priority, capacity)
+	q.dispatch()
+	return batchID, queued, nil
+}
+
+// Cancel withdraws the items of a batch that have not started and returns how many it withdrew
+func (q *ValidationQueue) Cancel(workspaceID, batchID string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ws := q.workspaces[workspaceID]
+	if ws == nil {
+		return 0
+	}
+	cancelled := 0
+	now := time.Now()
+	for _, item := range ws.items {
+		if item.BatchID == batchID && item.Status == QueueStatusQueued {
+			item.Status = QueueStatusCancelled
+			item.FinishedAt = &now
+			cancelled++
+		}
+	}
+	q.trim(ws)
+	return cancelled
+}
+
+// SetPoolSize limits how many validations run at once across all workspaces; 0 removes
+// the limit. Raising it starts waiting validations straight away; lowering it lets running
+// ones finish.
+func (q *ValidationQueue) SetPoolSize(size int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if size < 0 {
+		size = 0
+	}
+	q.poolSize = size
+	q.dispatch()
+}
+
+// ExportMetrics publishes the queue's depth, running validations and pool size as gauges
+// read when /metrics is scraped
+func (q *ValidationQueue) ExportMetrics() {
+	metrics.GaugeFunc("gohypo_validation_queue_depth", "Validations waiting for a worker.", func() float64 {
+		return float64(q.Depth().Queued)
+	})
+	metrics.GaugeFunc("gohypo_validation_running", "Validations running.", func() float64 {
+		return float64(q.Depth().Running)
+	})
+	metrics.GaugeFunc("gohypo_validation_workers", "Validation pool size; 0 when unlimited.", func() float64 {
+		return float64(q.Depth().PoolSize)
+	})
+}
+
+// Depth counts the validations waiting and running across workspaces
+func (q *ValidationQueue) Depth() QueueDepth {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	depth := QueueDepth{Running: q.running, PoolSize: q.poolSize}
+	for _, ws := range q.workspaces {
+		for _, item := range ws.items {
+			if item.Status == QueueStatusQueued {
+				depth.Queued++
+			}
+		}
+	}
+	return depth
+}
+
+// Snapshot returns a workspace's validations: running first, then queued in the order they
+// will run, then the most recently finished
+func (q *ValidationQueue) Snapshot(workspaceID string) []QueuedValidation {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ws := q.workspaces[workspaceID]
+	if ws == nil {
+		return []QueuedValidation{}
+	}
+	items := make([]*QueuedValidation, len(ws.items))
+	copy(items, ws.items)
+	stage := map[string]int{QueueStatusRunning: 0, QueueStatusQueued: 1}
+	sort.SliceStable(items, func(i, j int) bool {
+		si, ok := stage[items[i].Status]
+		if !ok {
+			si = 2
+		}
+		sj, ok := stage[items[j].Status]
+		if !ok {
+			sj = 2
+		}
+		if si != sj {
+			return si < sj
+		}
+		if si == 2 {
+			return items[i].FinishedAt.After(*items[j].FinishedAt)
+		}
+		return queuedBefore(items[i], items[j])
+	})
+	snapshot := make([]QueuedValidation, len(items))
+	for i, item := range items {
+		snapshot[i] = *item
+	}
+	return snapshot
+}
+
+// Pending names a workspace's hypotheses queued or running for re-validation
+func (q *ValidationQueue) Pending(workspaceID string) map[string]bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := make(map[string]bool)
+	if ws := q.workspaces[workspaceID]; ws != nil {
+		for _, item := range ws.items {
+			if item.Status == QueueStatusQueued || item.Status == QueueStatusRunning {
+				pending[item.HypothesisID] = true
+			}
+		}
+	}
+	return pending
+}
+
+// queuedBefore orders queued items: by priority, then batch, then place within the batch
+func queuedBefore(a, b *QueuedValidation) bool {
+	if ra, rb := queuePriorityRank[a.Priority], queuePriorityRank[b.Priority]; ra != rb {
+		return ra < rb
+	}
+	if a.batchSeq != b.batchSeq {
+		return a.batchSeq < b.batchSeq
+	}
+	return a.Position < b.Position
+}
+
+// dispatch starts queued items of workspaces with spare capacity while the pool has a
+// free slot, in queue order across workspaces; q.mu must be held
+func (q *ValidationQueue) dispatch() {
+	for q.poolSize == 0 || q.running < q.poolSize {
+		var next *QueuedValidation
+		var nextWS *workspaceValidations
+		for _, ws := range q.workspaces {
+			if ws.running >= ws.capacity {
+				continue
+			}
+			for _, item := range ws.items {
+				if item.Status == QueueStatusQueued && (next == nil || queuedBefore(item, next)) {
+					next, nextWS = item, ws
+				}
+			}
+		}
+		if next == nil {
+			return
+		}
+		now := time.Now()
+		next.Status = QueueStatusRunning
+		next.StartedAt = &now
+		nextWS.running++
+		q.running++
+		q.broadcast(next, "validation_started")
+		go q.run(nextWS, next)
+	}
+}
+
+// run validates one item and hands its slot to the next
+func (q *ValidationQueue) run(ws *workspaceValidations, item *QueuedValidation) {
+	passed := false
+	var confidence float64
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[ValidationQueue] ERROR: Panic validating hypothesis %s: %v", item.HypothesisID, r)
+				passed = false
+			}
+		}()
+		ctx := context.Background()
+		hypothesis, err := q.loader.GetByID(ctx, item.HypothesisID)
+		if err != nil || hypothesis == nil {
+			log.Printf("[ValidationQueue] Hypothesis %s could not be loaded: %v", item.HypothesisID, err)
+			return
+		}
+		passed = q.validator.RevalidateHypothesis(ctx, hypothesis)
+		if updated, err := q.loader.GetByID(ctx, item.HypothesisID); err == nil && updated != nil {
+			confidence = updated.Confidence
+		}
+	}()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	item.Status = QueueStatusFailed
+	if passed {
+		item.Status = QueueStatusPassed
+	}
+	item.Passed = &passed
+	item.Confidence = confidence
+	item.FinishedAt = &now
+	metrics.ValidationsCompleted.Inc(string(item.Status))
+	ws.running--
+	q.running--
+	q.broadcast(item, "validation_verdict")
+	q.trim(ws)
+	q.dispatch()
+}
+
+// broadcast streams an item's state to the workspace topic; q.mu must be held
+func (q *ValidationQueue) broadcast(item *QueuedValidation, eventType string) {
+	if q.events == nil {
+		return
+	}
+	queued := 0
+	for _, other := range q.workspaces[item.workspaceID].items {
+		if other.Status == QueueStatusQueued {
+			queued++
+		}
+	}
+	data := map[string]interface{}{
+		"hypothesis_id": item.HypothesisID,
+		"batch_id":      item.BatchID,
+		"position":      item.Position,
+		"status":        item.Status,
+		"queued":        queued,
+	}
+	if item.Passed != nil {
+		data["passed"] = *item.Passed
+		data["confidence"] = item.Confidence
+	}
+	q.events.Broadcast(api.ResearchEvent{
+		SessionID:    ValidationQueueTopic(item.workspaceID),
+		EventType:    eventType,
+		HypothesisID: item.HypothesisID,
+		Data:         data,
+		Timestamp:    time.Now(),
+	})
+}
+
+// trim drops the oldest finished items beyond queueHistory; q.mu must be held
+func (q *ValidationQueue) trim(ws *workspaceValidations) {
+	finished := 0
+	for _, item := range ws.items {
+		if item.FinishedAt != nil {
+			finished++
+		}
+	}
+	if finished <= queueHistory {
+		return
+	}
+	kept := ws.items[:0]
+	for _, item := range ws.items {
+		if item.FinishedAt != nil && finished > queueHistory {
+			finished--
+			continue
+		}
+		kept = append(kept, item)
+	}
+	ws.items = kept
+}
@@ -0,0 +1,131 @@
+package research
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gohypo/internal/api"
+	"gohypo/models"
+)
+
+type stubHypotheses struct {
+	mu       sync.Mutex
+	order    []string
+	running  int
+	peak     int
+	release  chan struct{}
+	verdicts []api.ResearchEvent
+}
+
+func (s *stubHypotheses) GetByID(ctx context.Context, id string) (*models.HypothesisResult, error) {
+	return &models.HypothesisResult{ID: id, Confidence: 0.75}, nil
+}
+
+func (s *stubHypotheses) RevalidateHypothesis(ctx context.Context, h *models.HypothesisResult) bool {
+	s.mu.Lock()
+	s.order = append(s.order, h.ID)
+	s.running++
+	if s.running > s.peak {
+		s.peak = s.running
+	}
+	s.mu.Unlock()
+	<-s.release
+	s.mu.Lock()
+	s.running--
+	s.mu.Unlock()
+	return !strings.HasSuffix(h.ID, "-fail")
+}
+
+func (s *stubHypotheses) Broadcast(event api.ResearchEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if event.EventType == "validation_verdict" {
+		s.verdicts = append(s.verdicts, event)
+	}
+}
+
+// TestValidationQueueOrderAndCapacity verifies queued hypotheses run by priority, then batch,
+// then chosen order, never more at once than the workspace capacity, and that every verdict
+// is broadcast on the workspace topic
+func TestValidationQueueOrderAndCapacity(t *testing.T) {
+	stub := &stubHypotheses{release: make(chan struct{})}
+	queue := NewValidationQueue(stub, stub, stub)
+
+	// Capacity 1: the first hypothesis starts at once and holds the slot
+	if _, _, err := queue.Enqueue("ws", []string{"a", "b-fail"}, QueuePriorityLow, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, queued, _ := queue.Enqueue("ws", []string{"c", "a", "d"}, QueuePriorityHigh, 1); len(queued) != 2 {
+		t.Fatalf("expected the running hypothesis to be skipped, got %+v", queued)
+	}
+	if _, _, err := queue.Enqueue("ws", []string{"e"}, "urgent", 1); err == nil {
+		t.Fatal("expected an unknown priority to be rejected")
+	}
+
+	for i := 0; i < 4; i++ {
+		stub.release <- struct{}{}
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		stub.mu.Lock()
+		done := len(stub.verdicts) == 4
+		stub.mu.Unlock()
+		if done || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	if got := strings.Join(stub.order, " "); got != "a c d b-fail" {
+		t.Errorf("expected a, then the high-priority batch in order, then b; got %q", got)
+	}
+	if stub.peak != 1 {
+		t.Errorf("expected at most 1 validation at a time, saw %d", stub.peak)
+	}
+	if len(stub.verdicts) != 4 || stub.verdicts[3].SessionID != ValidationQueueTopic("ws") || stub.verdicts[3].Data["passed"] != false {
+		t.Errorf("expected four verdicts on the workspace topic ending with b's failure, got %+v", stub.verdicts)
+	}
+	snapshot := queue.Snapshot("ws")
+	if len(snapshot) != 4 || snapshot[0].Status == QueueStatusQueued {
+		t.Errorf("expected four finished validations, got %+v", snapshot)
+	}
+
+	if ValidationCapacity(map[string]interface{}{ValidationCapacityKey: float64(50)}) != MaxValidationCapacity ||
+		ValidationCapacity(nil) != DefaultValidationCapacity {
+		t.Error("expected capacity to default and clamp")
+	}
+}
+
+// TestValidationQueuePoolSize verifies the pool size caps validations across workspaces and
+// that raising it starts waiting ones
+func TestValidationQueuePoolSize(t *testing.T) {
+	stub := &stubHypotheses{release: make(chan struct{})}
+	queue := NewValidationQueue(stub, stub, nil)
+	queue.SetPoolSize(1)
+
+	queue.Enqueue("ws1", []string{"a", "b"}, QueuePriorityNormal, 2)
+	queue.Enqueue("ws2", []string{"c"}, QueuePriorityNormal, 2)
+	if depth := queue.Depth(); depth.Running != 1 || depth.Queued != 2 || depth.PoolSize != 1 {
+		t.Fatalf("expected one running and two waiting, got %+v", depth)
+	}
+
+	queue.SetPoolSize(3)
+	if depth := queue.Depth(); depth.Running != 3 || depth.Queued != 0 {
+		t.Fatalf("expected every validation to start, got %+v", depth)
+	}
+	for i := 0; i < 3; i++ {
+		stub.release <- struct{}{}
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for queue.Depth().Running > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if depth := queue.Depth(); depth.Running != 0 {
+		t.Errorf("expected the pool to drain, got %+v", depth)
+	}
+}
@@ -3,15 +3,19 @@ package research
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"slices"
 	"time"
 
 	"gohypo/ai"
 	"gohypo/app"
 	"gohypo/domain/greenfield"
 	"gohypo/internal"
+	"gohypo/internal/access"
 	"gohypo/internal/analysis"
 	"gohypo/internal/api"
+	"gohypo/internal/logging"
+	"gohypo/internal/metrics"
 	refereePkg "gohypo/internal/referee"
 	"gohypo/internal/testkit"
 	"gohypo/internal/validation"
@@ -50,6 +54,32 @@ type ResearchWorker struct {
 
 	// Dataset repository for accessing uploaded datasets
 	datasetRepo ports.DatasetRepository // Dataset repository for uploaded files
+
+	// Column-level access policy (optional)
+	columnEnforcer *access.Enforcer
+
+	// Resolves stored (possibly encrypted) dataset files to readable paths
+	datasetFiles DatasetFileLocator
+
+	// Referee batteries in flight, their recorded runs and runtime estimates
+	validationProgress *ValidationTracker
+
+	// Aggregate tables materialized after each sweep (optional)
+	runSummaries ports.RunSummaryStore
+
+	// Workspaces, read for their analysis policy; without it sweeps use the default cutoffs
+	workspaces ports.WorkspaceRepository
+
+	// Run lifecycle events for workspace webhooks (optional)
+	events ports.EventPublisher
+
+	// Chat channels announcing validated hypotheses (optional)
+	notifier ports.HypothesisNotifier
+}
+
+// DatasetFileLocator yields a plaintext local path for a stored dataset file
+type DatasetFileLocator interface {
+	LocalPath(ctx context.Context, filePath string) (string, func(), error)
 }
 
 // NewResearchWorker creates a new research worker
@@ -77,6 +107,12 @@ func NewResearchWorker(sessionMgr *SessionManager, storage *ResearchStorage, pro
 	// Initialize hypothesis summarizer for feedback learning
 	hypothesisSummarizer := app.NewValidatedHypothesisSummarizer(hypothesisRepo)
 
+	// A nil hub must not reach the tracker as a non-nil broadcaster
+	var progressEvents eventBroadcaster
+	if hub, ok := sseHub.(*api.SSEHub); ok && hub != nil {
+		progressEvents = hub
+	}
+
 	return &ResearchWorker{
 		sessionMgr:            sessionMgr,
 		storage:               storage,
@@ -95,9 +131,59 @@ func NewResearchWorker(sessionMgr *SessionManager, storage *ResearchStorage, pro
 		hypothesisSummarizer:  hypothesisSummarizer,
 		validationOrchestrator: validationOrchestrator,
 		datasetRepo:           datasetRepo,
+		validationProgress:    NewValidationTracker(nil, progressEvents),
 	}
 }
 
+// EValueCalibrator returns the calibrator validation converts referee evidence with
+func (rw *ResearchWorker) EValueCalibrator() *analysis.EValueCalibrator {
+	return rw.evalueValidator.GetCalibrator()
+}
+
+// SetColumnEnforcer enables column-level access policies for resolution and prompting
+func (rw *ResearchWorker) SetColumnEnforcer(enforcer *access.Enforcer) {
+	rw.columnEnforcer = enforcer
+}
+
+// SetDatasetFiles configures how uploaded dataset files are opened (required for encrypted storage)
+func (rw *ResearchWorker) SetDatasetFiles(locator DatasetFileLocator) {
+	rw.datasetFiles = locator
+}
+
+// SetRefereeRunStore persists referee runs, so progress and runtime estimates survive restarts
+func (rw *ResearchWorker) SetRefereeRunStore(store ports.RefereeRunStore) {
+	rw.validationProgress.store = store
+}
+
+// SetRunSummaryStore materializes each sweep's summary, so pages read it instead of
+// scanning the sweep's artifacts
+func (rw *ResearchWorker) SetRunSummaryStore(store ports.RunSummaryStore) {
+	rw.runSummaries = store
+}
+
+// SetWorkspaceRepository lets sweeps apply each workspace's analysis policy
+func (rw *ResearchWorker) SetWorkspaceRepository(repo ports.WorkspaceRepository) {
+	rw.workspaces = repo
+}
+
+// SetEventPublisher announces completed sweeps, validated hypotheses and failed runs to
+// workspace webhooks
+func (rw *ResearchWorker) SetEventPublisher(events ports.EventPublisher) {
+	rw.events = events
+}
+
+// SetHypothesisNotifier announces validated hypotheses in the Slack and Teams channels of
+// their workspace
+func (rw *ResearchWorker) SetHypothesisNotifier(notifier ports.HypothesisNotifier) {
+	rw.notifier = notifier
+}
+
+// ValidationProgress returns a session's running referee batteries with their ETAs and the
+// referee runs recorded for it
+func (rw *ResearchWorker) ValidationProgress(ctx context.Context, sessionID string) (*models.SessionValidationProgress, error) {
+	return rw.validationProgress.SessionProgress(ctx, sessionID)
+}
+
 // RunStatsSweep executes statistical analysis and returns artifacts
 func (rw *ResearchWorker) RunStatsSweep(ctx context.Context, sessionID string, fieldMetadata []greenfield.FieldMetadata) ([]map[string]interface{}, error) {
 	return rw.runStatsSweep(ctx, sessionID, fieldMetadata)
@@ -106,7 +192,10 @@ func (rw *ResearchWorker) RunStatsSweep(ctx context.Context, sessionID string, f
 // ProcessResearch initiates and manages the research generation workflow
 func (rw *ResearchWorker) ProcessResearch(ctx context.Context, sessionID string, fieldMetadata []greenfield.FieldMetadata, statsArtifacts []map[string]interface{}, sseHub interface{}) {
 	sessionStart := time.Now()
-	rw.logger.Info("Starting research process for session %s (%d fields, %d artifacts)", sessionID, len(fieldMetadata), len(statsArtifacts))
+	ctx = logging.WithRun(ctx, sessionID)
+	slog.InfoContext(ctx, "Starting research", "fields", len(fieldMetadata), "artifacts", len(statsArtifacts))
+	metrics.ResearchSessionsActive.Add(1)
+	defer metrics.ResearchSessionsActive.Add(-1)
 
 	// Initialize session-level variables
 	var totalHypotheses int
@@ -114,10 +203,8 @@ func (rw *ResearchWorker) ProcessResearch(ctx context.Context, sessionID string,
 
 	defer func() {
 		sessionDuration := time.Since(sessionStart)
-		rw.logger.Info("Session %s completed: %d hypotheses in %.2fs", sessionID, totalHypotheses, sessionDuration.Seconds())
-		if rw.logger.GetLevel() >= internal.LogLevelDebug && (successCount > 0 || failureCount > 0) {
-			rw.logger.Debug("Validation results: %d passed, %d failed", successCount, failureCount)
-		}
+		slog.InfoContext(ctx, "Research finished", "hypotheses", totalHypotheses, "passed", successCount,
+			"failed", failureCount, "duration_sec", sessionDuration.Seconds())
 	}()
 
 	// Emit Layer 0 start event
@@ -136,70 +223,70 @@ func (rw *ResearchWorker) ProcessResearch(ctx context.Context, sessionID string,
 
 	// Update session state to analyzing
 	phaseStart := time.Now()
+	scoutCtx := logging.WithStage(ctx, "scout")
 	if err := rw.sessionMgr.SetSessionState(ctx, sessionID, models.SessionStateAnalyzing); err != nil {
-		log.Printf("[ResearchWorker] ERROR: Failed to start analysis for session %s: %v", sessionID, err)
+		slog.ErrorContext(scoutCtx, "Failed to start analysis", "error", err)
 		return
 	}
 
+	// Apply column policies before anything reaches the sweep or a prompt
+	ctx, fieldMetadata, statsArtifacts = rw.applyColumnPolicy(ctx, sessionID, fieldMetadata, statsArtifacts)
+
 	// Handle statistical artifacts - attempt stats sweep when no pre-computed artifacts available
+	sweepCtx := logging.WithStage(ctx, "sweep")
 	if len(statsArtifacts) == 0 {
-		log.Printf("[ResearchWorker] 📊 Phase 2/4: Statistical Analysis - No pre-computed artifacts available for session %s", sessionID)
-		log.Printf("[ResearchWorker] 🔄 Attempting stats sweep to generate statistical artifacts...")
+		slog.InfoContext(sweepCtx, "No pre-computed artifacts, running a stats sweep")
 
 		// Attempt to run stats sweep to generate artifacts
-		newArtifacts, err := rw.RunStatsSweep(ctx, sessionID, fieldMetadata)
+		newArtifacts, err := rw.RunStatsSweep(sweepCtx, sessionID, fieldMetadata)
 		if err != nil {
-			log.Printf("[ResearchWorker] ⚠️ Stats sweep failed, proceeding with field metadata only: %v", err)
+			slog.WarnContext(sweepCtx, "Stats sweep failed, proceeding with field metadata only", "error", err)
 			statsArtifacts = []map[string]interface{}{} // Empty artifacts - LLM will work with field metadata only
 		} else {
 			statsArtifacts = newArtifacts
-			log.Printf("[ResearchWorker] ✅ Stats sweep completed, generated %d artifacts", len(statsArtifacts))
+			slog.InfoContext(sweepCtx, "Stats sweep generated artifacts", "artifacts", len(statsArtifacts))
 		}
 	} else {
-		log.Printf("[ResearchWorker] 📊 Phase 2/4: Statistical Analysis - Using %d existing artifacts for session %s", len(statsArtifacts), sessionID)
-		log.Printf("[ResearchWorker] 🔄 Running additional stats sweep to augment existing artifacts...")
+		slog.InfoContext(sweepCtx, "Running a stats sweep to augment existing artifacts", "artifacts", len(statsArtifacts))
 		// Run stats sweep to get additional artifacts
-		newArtifacts, err := rw.RunStatsSweep(ctx, sessionID, fieldMetadata)
+		newArtifacts, err := rw.RunStatsSweep(sweepCtx, sessionID, fieldMetadata)
 		if err != nil {
-			log.Printf("[ResearchWorker] ⚠️ Additional stats sweep failed, continuing with existing artifacts: %v", err)
+			slog.WarnContext(sweepCtx, "Additional stats sweep failed, continuing with existing artifacts", "error", err)
 		} else {
 			statsArtifacts = append(statsArtifacts, newArtifacts...)
-			log.Printf("[ResearchWorker] ✅ Additional stats sweep completed, total artifacts: %d", len(statsArtifacts))
+			slog.InfoContext(sweepCtx, "Additional stats sweep completed", "artifacts", len(statsArtifacts))
 		}
 	}
 
 	// Convert metadata and stats artifacts to JSON for LLM processing
-	log.Printf("[ResearchWorker] 📝 Preparing field metadata JSON for session %s", sessionID)
+	generationCtx := logging.WithStage(ctx, "generation")
 	fieldJSON, err := rw.prepareFieldMetadata(fieldMetadata, statsArtifacts, nil)
 	if err != nil {
-		log.Printf("[ResearchWorker] ❌ CRITICAL: Failed to prepare field metadata for session %s: %v", sessionID, err)
-		rw.sessionMgr.SetSessionError(ctx, sessionID, fmt.Sprintf("Failed to prepare metadata: %v", err))
+		slog.ErrorContext(generationCtx, "Failed to prepare field metadata", "error", err)
+		rw.failSession(ctx, sessionID, fmt.Sprintf("Failed to prepare metadata: %v", err))
 		return
 	}
-	log.Printf("[ResearchWorker] ✅ Field metadata prepared for session %s (%d chars)", sessionID, len(fieldJSON))
 
 	// Generate hypotheses using LLM
 	phaseStart = time.Now()
-	log.Printf("[ResearchWorker] 🧠 Phase 3/4: Hypothesis Generation - Calling LLM for session %s", sessionID)
-	log.Printf("[ResearchWorker] 📝 Context size: %d characters, %d fields available", len(fieldJSON), len(fieldMetadata))
+	slog.InfoContext(generationCtx, "Generating hypotheses", "context_chars", len(fieldJSON), "fields", len(fieldMetadata))
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	// Bound generation under the caller's context: it carries the domain pack and LLM settings,
+	// and the job runner cancels it when the session's lease is lost
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
 
-	hypotheses, err := rw.generateHypothesesWithContext(ctx, sessionID, fieldJSON)
+	hypotheses, err := rw.generateHypothesesWithContext(logging.WithStage(ctx, "generation"), sessionID, fieldJSON)
 	phaseDuration := time.Since(phaseStart)
 
 	if err != nil {
-		log.Printf("[ResearchWorker] ❌ CRITICAL ERROR: LLM hypothesis generation failed after %.2fs", phaseDuration.Seconds())
-		log.Printf("[ResearchWorker] 💥 Error details: %v", err)
-		log.Printf("[ResearchWorker] 📊 Context attempted: %d fields, %d chars of metadata", len(fieldMetadata), len(fieldJSON))
-		log.Printf("[ResearchWorker] 🛑 Session %s cannot continue - hypothesis generation failed", sessionID)
-		log.Printf("[ResearchWorker] 🔧 Suggested actions: Check LLM service connectivity, verify field metadata quality")
-		rw.sessionMgr.SetSessionError(ctx, sessionID, fmt.Sprintf("Failed to generate hypotheses: %v", err))
+		slog.ErrorContext(generationCtx, "Hypothesis generation failed; check LLM connectivity and field metadata quality",
+			"error", err, "duration_sec", phaseDuration.Seconds(), "fields", len(fieldMetadata), "context_chars", len(fieldJSON))
+		rw.failSession(ctx, sessionID, fmt.Sprintf("Failed to generate hypotheses: %v", err))
 		return
 	} else {
-		log.Printf("[ResearchWorker] ✅ LLM hypothesis generation completed in %.2fs", phaseDuration.Seconds())
-		log.Printf("[ResearchWorker] Generated %d hypotheses for validation", len(hypotheses.ResearchDirectives))
+		slog.InfoContext(generationCtx, "Hypotheses generated", "hypotheses", len(hypotheses.ResearchDirectives),
+			"duration_sec", phaseDuration.Seconds())
 
 		// Emit hypothesis generation events for chat interface
 		if sseHub, ok := rw.sseHub.(*api.SSEHub); ok {
@@ -249,23 +336,26 @@ func (rw *ResearchWorker) ProcessResearch(ctx context.Context, sessionID string,
 	}
 
 	// Update session state to validating
-	log.Printf("[ResearchWorker] Updating session %s to validating state", sessionID)
+	validationCtx := logging.WithStage(ctx, "validation")
 	if err := rw.sessionMgr.SetSessionState(ctx, sessionID, models.SessionStateValidating); err != nil {
-		log.Printf("[ResearchWorker] ERROR: Failed to update session state to validating: %v", err)
+		slog.ErrorContext(validationCtx, "Failed to update session state to validating", "error", err)
 		return
 	}
 
 	// Validate each hypothesis using e-value dynamic validation
 	phaseStart = time.Now()
 	totalHypotheses = len(hypotheses.ResearchDirectives)
-	log.Printf("[ResearchWorker] Starting validation phase for %d hypotheses in session %s", totalHypotheses, sessionID)
+	slog.InfoContext(validationCtx, "Validating hypotheses", "hypotheses", totalHypotheses)
+	warningsByPair := pairWarnings(statsArtifacts)
 
 	for i, directive := range hypotheses.ResearchDirectives {
+		directive.DataWarnings = warningsByPair[[2]string{directive.CauseKey, directive.EffectKey}]
 		hypothesisStart := time.Now()
 		hypothesisNum := i + 1
 		progressPercent := float64(hypothesisNum-1) / float64(totalHypotheses) * 100
 
-		log.Printf("[ResearchWorker] Processing hypothesis %d/%d (%.1f%%) - ID: %s", hypothesisNum, totalHypotheses, progressPercent, directive.ID)
+		slog.DebugContext(validationCtx, "Validating hypothesis", "hypothesis_id", directive.ID, "sequence", hypothesisNum,
+			"total", totalHypotheses, "progress_pct", progressPercent)
 
 		// Update progress
 		progress := float64(i) / float64(totalHypotheses) * 100
@@ -277,19 +367,20 @@ func (rw *ResearchWorker) ProcessResearch(ctx context.Context, sessionID string,
 		func() {
 			defer func() {
 				if r := recover(); r != nil {
-					log.Printf("[ResearchWorker] ERROR: Panic in hypothesis %s validation: %v", directive.ID, r)
+					slog.ErrorContext(validationCtx, "Panic in hypothesis validation", "hypothesis_id", directive.ID, "panic", r)
 					rw.recordFailedHypothesis(ctx, sessionID, directive.ID, fmt.Sprintf("Panic during validation: %v", r))
 					validationPassed = false
 				}
 			}()
 
-			validationPassed = rw.executeEValueValidation(ctx, sessionID, directive)
+			validationPassed = rw.executeEValueValidation(validationCtx, sessionID, directive)
 		}()
 
 		hypothesisDuration := time.Since(hypothesisStart)
 		phaseDuration = time.Since(phaseStart)
 
-		log.Printf("[ResearchWorker] Hypothesis %s validation completed in %.2fs", directive.ID, hypothesisDuration.Seconds())
+		slog.InfoContext(validationCtx, "Hypothesis validated", "hypothesis_id", directive.ID, "passed", validationPassed,
+			"duration_sec", hypothesisDuration.Seconds())
 
 		// Count successes vs failures
 		if validationPassed {
@@ -299,7 +390,7 @@ func (rw *ResearchWorker) ProcessResearch(ctx context.Context, sessionID string,
 		}
 	}
 
-	log.Printf("[ResearchWorker] Validation completed for session %s: %d hypotheses processed", sessionID, totalHypotheses)
+	slog.InfoContext(validationCtx, "Validation completed", "hypotheses", totalHypotheses, "duration_sec", phaseDuration.Seconds())
 
 	// Emit Layer 3 start event
 	if sseHub, ok := rw.sseHub.(*api.SSEHub); ok {
@@ -319,9 +410,8 @@ func (rw *ResearchWorker) ProcessResearch(ctx context.Context, sessionID string,
 	}
 
 	// Complete the session
-	log.Printf("[ResearchWorker] 🎯 Completing session %s", sessionID)
 	if err := rw.sessionMgr.SetSessionState(ctx, sessionID, models.SessionStateComplete); err != nil {
-		log.Printf("[ResearchWorker] ❌ CRITICAL: Failed to complete session %s: %v", sessionID, err)
+		slog.ErrorContext(logging.WithStage(ctx, "gateway"), "Failed to complete session", "error", err)
 	}
 
 	// Emit final completion event
@@ -391,6 +481,51 @@ func (rw *ResearchWorker) buildDiscoveryEvidenceFromStats(
 	return evidence
 }
 
+// pairWarnings collects the warning codes statistical artifacts attach to each variable pair,
+// under both orderings of the pair since a hypothesis may name either variable as the cause
+func pairWarnings(statsArtifacts []map[string]interface{}) map[[2]string][]string {
+	warnings := make(map[[2]string][]string)
+	for _, artifact := range statsArtifacts {
+		payload, ok := artifact["payload"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		x, y := getString(payload, "cause_key"), getString(payload, "effect_key")
+		if x == "" || y == "" {
+			x, y = getString(payload, "variable_x"), getString(payload, "variable_y")
+		}
+		codes := metadataCodes(payload["warnings"])
+		if x == "" || y == "" || len(codes) == 0 {
+			continue
+		}
+		for _, pair := range [][2]string{{x, y}, {y, x}} {
+			for _, code := range codes {
+				if !slices.Contains(warnings[pair], code) {
+					warnings[pair] = append(warnings[pair], code)
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// metadataCodes reads a list of codes that may have been decoded from JSON
+func metadataCodes(v interface{}) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		var codes []string
+		for _, item := range list {
+			if code, ok := item.(string); ok && code != "" {
+				codes = append(codes, code)
+			}
+		}
+		return codes
+	}
+	return nil
+}
+
 // performSamplePartitioningForValidation creates discovery and validation partitions
 func (rw *ResearchWorker) performSamplePartitioningForValidation(
 	ctx context.Context,
@@ -0,0 +1,62 @@
+package research
+
+import (
+	"context"
+	"log/slog"
+
+	"gohypo/domain/core"
+	"gohypo/domain/greenfield"
+)
+
+// applyColumnPolicy removes restricted fields and any stats artifact that mentions them,
+// and returns a context carrying the session owner's principal
+func (rw *ResearchWorker) applyColumnPolicy(ctx context.Context, sessionID string, fieldMetadata []greenfield.FieldMetadata, statsArtifacts []map[string]interface{}) (context.Context, []greenfield.FieldMetadata, []map[string]interface{}) {
+	if rw.columnEnforcer == nil {
+		return ctx, fieldMetadata, statsArtifacts
+	}
+
+	session, err := rw.sessionMgr.GetSession(ctx, sessionID)
+	if err != nil {
+		// Fail closed: an unknown user gets an anonymous principal with no roles
+		slog.WarnContext(ctx, "Could not load session for column policy, applying anonymous principal", "error", err)
+	} else {
+		ctx = rw.columnEnforcer.ContextFor(ctx, core.ID(session.UserID.String()))
+	}
+
+	allowedFields := make([]greenfield.FieldMetadata, 0, len(fieldMetadata))
+	for _, fm := range fieldMetadata {
+		if rw.columnEnforcer.IsAllowed(ctx, core.VariableKey(fm.Name)) {
+			allowedFields = append(allowedFields, fm)
+		}
+	}
+
+	allowedArtifacts := make([]map[string]interface{}, 0, len(statsArtifacts))
+	for _, artifact := range statsArtifacts {
+		if rw.artifactIsAccessible(ctx, artifact) {
+			allowedArtifacts = append(allowedArtifacts, artifact)
+		}
+	}
+
+	if removed := len(fieldMetadata) - len(allowedFields); removed > 0 {
+		slog.InfoContext(ctx, "Column policy removed fields", "fields", removed, "artifacts", len(statsArtifacts)-len(allowedArtifacts))
+	}
+
+	return ctx, allowedFields, allowedArtifacts
+}
+
+// artifactIsAccessible checks every variable reference in a stats artifact payload
+func (rw *ResearchWorker) artifactIsAccessible(ctx context.Context, artifact map[string]interface{}) bool {
+	payload, ok := artifact["payload"].(map[string]interface{})
+	if !ok {
+		return true
+	}
+
+	for _, field := range []string{"cause_key", "effect_key", "variable_x", "variable_y"} {
+		if key, ok := payload[field].(string); ok && key != "" {
+			if !rw.columnEnforcer.IsAllowed(ctx, core.VariableKey(key)) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package research
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gohypo/models"
+
+	"github.com/google/uuid"
+)
+
+// notifyTimeout bounds posting a validated hypothesis to a workspace's chat channels
+const notifyTimeout = 30 * time.Second
+
+// publish notifies the workspace's webhooks of a run lifecycle event. Webhooks are a side
+// channel, so a failure to queue the event is logged rather than failing the run.
+func (rw *ResearchWorker) publish(ctx context.Context, workspaceID uuid.UUID, event models.WebhookEvent, data map[string]interface{}) {
+	if rw.events == nil || workspaceID == uuid.Nil {
+		return
+	}
+	if err := rw.events.Publish(ctx, workspaceID.String(), event, data); err != nil {
+		slog.WarnContext(ctx, "Failed to publish webhook event", "event", event, "workspace_id", workspaceID, "error", err)
+	}
+}
+
+// publishForSession publishes an event for the workspace a research session belongs to
+func (rw *ResearchWorker) publishForSession(ctx context.Context, sessionID string, event models.WebhookEvent, data map[string]interface{}) {
+	if rw.events == nil {
+		return
+	}
+	session, err := rw.sessionMgr.GetSession(ctx, sessionID)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to publish webhook event", "event", event, "session_id", sessionID, "error", err)
+		return
+	}
+	data["session_id"] = sessionID
+	rw.publish(ctx, session.WorkspaceID, event, data)
+}
+
+// failSession marks a research session as failed and announces it to the workspace's webhooks
+func (rw *ResearchWorker) failSession(ctx context.Context, sessionID, errMsg string) {
+	rw.sessionMgr.SetSessionError(ctx, sessionID, errMsg)
+	rw.publishForSession(context.WithoutCancel(ctx), sessionID, models.WebhookRunFailed, map[string]interface{}{
+		"error": errMsg,
+	})
+}
+
+// publishValidated announces a hypothesis that passed validation to the workspace's webhooks
+// and chat channels. Chat posts go out in the background, so a slow webhook does not hold up
+// validation.
+func (rw *ResearchWorker) publishValidated(ctx context.Context, result *models.HypothesisResult) {
+	if rw.events == nil && rw.notifier == nil {
+		return
+	}
+	session, err := rw.sessionMgr.GetSession(ctx, result.SessionID)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to announce validated hypothesis", "hypothesis_id", result.ID, "session_id", result.SessionID, "error", err)
+		return
+	}
+	rw.publish(ctx, session.WorkspaceID, models.WebhookHypothesisValidated, map[string]interface{}{
+		"session_id":          result.SessionID,
+		"hypothesis_id":       result.ID,
+		"business_hypothesis": result.BusinessHypothesis,
+		"science_hypothesis":  result.ScienceHypothesis,
+		"confidence":          result.Confidence,
+		"cause_key":           result.ExecutionMetadata["cause_key"],
+		"effect_key":          result.ExecutionMetadata["effect_key"],
+	})
+	if rw.notifier == nil || session.WorkspaceID == uuid.Nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+		defer cancel()
+		if err := rw.notifier.NotifyValidated(ctx, session.WorkspaceID.String(), result); err != nil {
+			slog.WarnContext(ctx, "Failed to notify chat channels", "hypothesis_id", result.ID, "workspace_id", session.WorkspaceID, "error", err)
+		}
+	}()
+}
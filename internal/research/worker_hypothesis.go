@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -42,96 +42,24 @@ func (rw *ResearchWorker) generateHypothesesWithContext(ctx context.Context, ses
 		return nil, fmt.Errorf("fieldJSON cannot be empty")
 	}
 
-	log.Printf("[ResearchWorker] 🤖 Starting hypothesis generation for session %s", sessionID)
+	slog.InfoContext(ctx, "Starting hypothesis generation")
 
 	if rw.greenfieldPort == nil {
-		log.Printf("[ResearchWorker] ❌ Greenfield port not available for session %s", sessionID)
 		return nil, fmt.Errorf("greenfield port not available")
 	}
 
 	if rw.storage == nil {
-		log.Printf("[ResearchWorker] ❌ Research storage not available for session %s", sessionID)
 		return nil, fmt.Errorf("research storage not available")
 	}
 
-	// Parse field metadata from JSON
-	log.Printf("[ResearchWorker] 📝 Parsing field metadata JSON for session %s", sessionID)
-	var contextData map[string]interface{}
-	if err := json.Unmarshal([]byte(fieldJSON), &contextData); err != nil {
-		log.Printf("[ResearchWorker] ❌ Failed to parse field JSON for session %s: %v", sessionID, err)
-		return nil, fmt.Errorf("failed to parse field JSON: %w", err)
-	}
-
-	// Extract field metadata
-	fieldMetadataRaw, ok := contextData["field_metadata"].([]interface{})
-	if !ok {
-		log.Printf("[ResearchWorker] ❌ field_metadata not found or invalid for session %s", sessionID)
-		return nil, fmt.Errorf("field_metadata not found or invalid")
-	}
-
-	// Convert to greenfield.FieldMetadata
-	fieldMetadata := make([]greenfield.FieldMetadata, 0, len(fieldMetadataRaw))
-	for _, fm := range fieldMetadataRaw {
-		fmMap, ok := fm.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		fieldMetadata = append(fieldMetadata, greenfield.FieldMetadata{
-			Name:         getString(fmMap, "name"),
-			SemanticType: getString(fmMap, "semantic_type"),
-			DataType:     getString(fmMap, "data_type"),
-			Description:  getString(fmMap, "description"),
-		})
-	}
-	log.Printf("[ResearchWorker] Processing %d fields for hypothesis generation", len(fieldMetadata))
-
-	// Extract stats artifacts from context data
-	var statsArtifacts []map[string]interface{}
-	if statsArtifactsRaw, ok := contextData["statistical_artifacts"].([]interface{}); ok {
-		statsArtifacts = make([]map[string]interface{}, 0, len(statsArtifactsRaw))
-		for _, sa := range statsArtifactsRaw {
-			if saMap, ok := sa.(map[string]interface{}); ok {
-				statsArtifacts = append(statsArtifacts, saMap)
-			}
-		}
-	}
-	log.Printf("[ResearchWorker] Prepared %d statistical artifacts for hypothesis generation", len(statsArtifacts))
-
-	// Generate validated hypothesis summary for feedback learning
-	log.Printf("[ResearchWorker] 📊 Generating validated hypothesis summary for feedback learning (session %s)", sessionID)
-	var validatedHypothesisSummary interface{} = nil
-	if rw.hypothesisSummarizer != nil {
-		// Get user ID from session
-		session, err := rw.sessionMgr.GetSession(ctx, sessionID)
-		if err != nil {
-			log.Printf("[ResearchWorker] ⚠️ Failed to get session for user ID: %v", err)
-		} else {
-			userID := session.UserID
-			summary, err := rw.hypothesisSummarizer.GenerateSummary(ctx, userID, 1000) // Last 1000 validated hypotheses
-			if err != nil {
-				log.Printf("[ResearchWorker] ⚠️ Failed to generate hypothesis summary: %v", err)
-			} else if summary.TotalValidatedHypotheses > 0 {
-				validatedHypothesisSummary = summary
-				log.Printf("[ResearchWorker] ✅ Generated summary with %d validated hypotheses for feedback learning", summary.TotalValidatedHypotheses)
-			} else {
-				log.Printf("[ResearchWorker] ℹ️ No validated hypotheses found for feedback learning")
-			}
-		}
-	} else {
-		log.Printf("[ResearchWorker] ⚠️ Hypothesis summarizer not available for feedback learning")
+	req, err := rw.buildGreenfieldRequest(ctx, sessionID, fieldJSON)
+	if err != nil {
+		return nil, err
 	}
+	fieldMetadata, statsArtifacts := req.FieldMetadata, req.StatisticalArtifacts
 
 	// Call the port (which uses GreenfieldAdapter with Forensic Scout)
-	log.Printf("[ResearchWorker] 🚀 Calling Greenfield port for research directives (session %s)", sessionID)
-	req := ports.GreenfieldResearchRequest{
-		RunID:                   core.RunID(sessionID),
-		SnapshotID:              core.SnapshotID(""), // Not used in UI flow
-		FieldMetadata:           fieldMetadata,
-		StatisticalArtifacts:    statsArtifacts,
-		DiscoveryBriefs:         nil,
-		ValidatedHypothesisSummary: validatedHypothesisSummary,
-		Directives:              3,
-	}
+	slog.DebugContext(ctx, "Calling the Greenfield port for research directives")
 
 	// Emit Layer 1 start event
 	if broadcaster := rw.getBroadcaster(); broadcaster != nil {
@@ -154,7 +82,7 @@ func (rw *ResearchWorker) generateHypothesesWithContext(ctx context.Context, ses
 	llmDuration := time.Since(llmStart)
 
 	if err != nil {
-		log.Printf("[ResearchWorker] ❌ LLM call failed after %.2fs for session %s: %v", llmDuration.Seconds(), sessionID, err)
+		slog.ErrorContext(ctx, "LLM call failed", "error", err, "duration_sec", llmDuration.Seconds())
 
 		// Check if this is a JSON parsing error (critical logic failure)
 		isJSONError := isJSONParsingError(err)
@@ -162,7 +90,7 @@ func (rw *ResearchWorker) generateHypothesesWithContext(ctx context.Context, ses
 		eventType := "api_error"
 		if isJSONError {
 			eventType = "state_error"
-			log.Printf("[ResearchWorker] 🚨 CRITICAL: JSON parsing failure detected - sending STATE_ERROR event")
+			slog.ErrorContext(ctx, "LLM response is not valid JSON, sending STATE_ERROR event")
 		}
 
 		// Emit error event for UI (STATE_ERROR for JSON issues, API_ERROR for other issues)
@@ -182,22 +110,25 @@ func (rw *ResearchWorker) generateHypothesesWithContext(ctx context.Context, ses
 
 		return nil, fmt.Errorf("failed to generate research directives: %w", err)
 	}
-	log.Printf("[ResearchWorker] LLM call completed in %.2fs", llmDuration.Seconds())
+	slog.InfoContext(ctx, "LLM call completed", "duration_sec", llmDuration.Seconds())
+
+	rw.recordGenerationUsage(ctx, sessionID, portResponse.Usage)
 
 	// Save the rendered prompt (with industry context injection) for debugging
 	if portResponse.RenderedPrompt != "" {
 		if err := rw.savePromptToFile(ctx, sessionID, portResponse.RenderedPrompt); err != nil {
-			log.Printf("[ResearchWorker] ERROR: Failed to save prompt for session %s: %v", sessionID, err)
+			slog.ErrorContext(ctx, "Failed to save prompt", "error", err)
 		}
 	}
 
 	// Use raw LLM response if available (contains BusinessHypothesis, ScienceHypothesis, etc.)
 	if portResponse.RawLLMResponse != nil {
 		if llmResp, ok := portResponse.RawLLMResponse.(*models.GreenfieldResearchOutput); ok {
+			attachPromptBudget(llmResp, portResponse.PromptBudget)
 
 			// 🔥 IMMEDIATE HYPOTHESIS RENDERING: Create pending hypotheses for UI display
 			if err := rw.createPendingHypothesesForUI(ctx, sessionID, llmResp); err != nil {
-				log.Printf("[ResearchWorker] ⚠️ Failed to create pending hypotheses for UI: %v", err)
+				slog.WarnContext(ctx, "Failed to create pending hypotheses for the UI", "error", err)
 				// Continue anyway - validation will still work
 			}
 
@@ -206,17 +137,71 @@ func (rw *ResearchWorker) generateHypothesesWithContext(ctx context.Context, ses
 	}
 
 	// Fallback: convert domain objects to model format (shouldn't happen if adapter is working correctly)
-	log.Printf("[ResearchWorker] ⚠️ Raw LLM response not available for session %s, using fallback conversion", sessionID)
+	slog.WarnContext(ctx, "Raw LLM response not available, using fallback conversion")
 	modelResponse := rw.convertPortResponseToModel(portResponse)
+	attachPromptBudget(modelResponse, portResponse.PromptBudget)
 
 	// Create pending hypotheses for fallback case too
 	if err := rw.createPendingHypothesesForUI(ctx, sessionID, modelResponse); err != nil {
-		log.Printf("[ResearchWorker] ⚠️ Failed to create pending hypotheses for UI (fallback): %v", err)
+		slog.WarnContext(ctx, "Failed to create pending hypotheses for the UI from the fallback conversion", "error", err)
 	}
 
 	return modelResponse, nil
 }
 
+// attachPromptBudget stamps each directive with the budget report of the prompt that
+// generated it, so the hypothesis records which evidence the LLM actually saw. Directives
+// from a batched run already carry their own batch's report and keep it.
+func attachPromptBudget(output *models.GreenfieldResearchOutput, budget *models.PromptBudgetReport) {
+	if output == nil || budget == nil {
+		return
+	}
+	for i := range output.ResearchDirectives {
+		if output.ResearchDirectives[i].PromptBudget == nil {
+			output.ResearchDirectives[i].PromptBudget = budget
+		}
+	}
+}
+
+// recordGenerationUsage stores a generation run's token usage as a ledger artifact and adds it
+// to the session's running total, which the status endpoint, the run log page and gohypo-cli
+// usage report
+func (rw *ResearchWorker) recordGenerationUsage(ctx context.Context, sessionID string, usage *models.RunUsage) {
+	if usage == nil {
+		return
+	}
+	slog.InfoContext(ctx, "LLM usage of generation", "tokens", usage.TotalTokens, "calls", usage.Calls,
+		"cached_calls", usage.CachedCalls, "estimated_cost_usd", usage.EstimatedCostUSD)
+
+	if rw.testkit != nil {
+		artifact := core.Artifact{
+			ID:        core.ID(fmt.Sprintf("llm_usage_%s_%d", sessionID, time.Now().UnixNano())),
+			Kind:      core.ArtifactLLMUsage,
+			Payload:   usage,
+			CreatedAt: core.Now(),
+		}
+		if err := rw.testkit.LedgerAdapter().StoreArtifact(ctx, sessionID, artifact); err != nil {
+			slog.WarnContext(ctx, "Failed to store LLM usage artifact", "error", err)
+		}
+	}
+
+	if rw.sessionMgr == nil {
+		return
+	}
+	// Generations of one session may finish together, so the total is added to in place
+	err := rw.sessionMgr.MergeSessionMetadata(ctx, sessionID, "llm_usage", func(current json.RawMessage) (interface{}, error) {
+		total := &models.RunUsage{RunID: sessionID}
+		if len(current) > 0 {
+			json.Unmarshal(current, total)
+		}
+		total.Add(usage)
+		return total, nil
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to record LLM usage on session", "error", err)
+	}
+}
+
 // isJSONParsingError checks if an error is related to JSON parsing
 func isJSONParsingError(err error) bool {
 	errStr := err.Error()
@@ -308,21 +293,21 @@ func (rw *ResearchWorker) createPendingHypothesesForUI(ctx context.Context, sess
 	}
 
 	if len(llmResponse.ResearchDirectives) == 0 {
-		log.Printf("[ResearchWorker] ⚠️ No research directives in LLM response for session %s", sessionID)
+		slog.WarnContext(ctx, "No research directives in LLM response")
 		return nil // Not an error, just no hypotheses to create
 	}
 
-	log.Printf("[ResearchWorker] 🎨 Creating pending hypotheses for immediate UI display (%d hypotheses)", len(llmResponse.ResearchDirectives))
+	slog.DebugContext(ctx, "Creating pending hypotheses for immediate UI display", "hypotheses", len(llmResponse.ResearchDirectives))
 
 	for i, directive := range llmResponse.ResearchDirectives {
 		// Validate directive data
 		if directive.ID == "" {
-			log.Printf("[ResearchWorker] ⚠️ Skipping directive with empty ID at index %d", i)
+			slog.WarnContext(ctx, "Skipping directive with empty ID", "index", i)
 			continue
 		}
 
 		if directive.BusinessHypothesis == "" {
-			log.Printf("[ResearchWorker] ⚠️ Directive %s has empty business hypothesis", directive.ID)
+			slog.WarnContext(ctx, "Directive has empty business hypothesis", "hypothesis_id", directive.ID)
 		}
 
 		// Create pending referee results (gray checkmarks)
@@ -332,7 +317,7 @@ func (rw *ResearchWorker) createPendingHypothesesForUI(ctx context.Context, sess
 		for j, refereeSelection := range directive.RefereeGates.SelectedReferees {
 			refereeName := refereeSelection.Name
 			if refereeName == "" {
-				log.Printf("[ResearchWorker] ⚠️ Directive %s has empty referee name at index %d", directive.ID, j)
+				slog.WarnContext(ctx, "Directive has empty referee name", "hypothesis_id", directive.ID, "index", j)
 				continue
 			}
 
@@ -376,23 +361,110 @@ func (rw *ResearchWorker) createPendingHypothesesForUI(ctx context.Context, sess
 			Status:           "pending",
 		}
 
+		if directive.PromptBudget != nil {
+			pendingHypothesis.ExecutionMetadata["prompt_budget"] = directive.PromptBudget
+		}
+		if directive.SelfConsistency != nil {
+			pendingHypothesis.ExecutionMetadata["self_consistency"] = directive.SelfConsistency
+		}
+
 		// Save pending hypothesis to database for immediate UI display
 		if err := rw.storage.SaveHypothesis(ctx, pendingHypothesis); err != nil {
-			log.Printf("[ResearchWorker] ERROR: Failed to save pending hypothesis %s: %v", directive.ID, err)
+			slog.ErrorContext(ctx, "Failed to save pending hypothesis", "hypothesis_id", directive.ID, "error", err)
 		} else {
-			log.Printf("[ResearchWorker] Hypothesis %s generated and saved to database", directive.ID)
+			slog.DebugContext(ctx, "Pending hypothesis saved", "hypothesis_id", directive.ID)
 		}
 
-		log.Printf("[ResearchWorker] Hypothesis %s card created for UI display", directive.ID)
-
 		// Emit HTML fragment for immediate UI update via UI broadcaster
 		if rw.uiBroadcaster != nil {
 			if err := rw.uiBroadcaster.BroadcastHypothesisPending(sessionID, &directive); err != nil {
-				log.Printf("[ResearchWorker] Failed to broadcast hypothesis pending: %v", err)
+				slog.WarnContext(ctx, "Failed to broadcast pending hypothesis", "hypothesis_id", directive.ID, "error", err)
 			}
 		}
 	}
 
-	log.Printf("[ResearchWorker] 🎉 All pending hypotheses created - UI can now display them immediately")
+	slog.DebugContext(ctx, "Pending hypotheses created for the UI")
 	return nil
 }
+
+// buildGreenfieldRequest turns the prepared field JSON into the generation request, adding the
+// validated hypothesis summary used for feedback learning
+func (rw *ResearchWorker) buildGreenfieldRequest(ctx context.Context, sessionID string, fieldJSON string) (ports.GreenfieldResearchRequest, error) {
+	// Parse field metadata from JSON
+	var contextData map[string]interface{}
+	if err := json.Unmarshal([]byte(fieldJSON), &contextData); err != nil {
+		slog.ErrorContext(ctx, "Failed to parse field JSON", "error", err)
+		return ports.GreenfieldResearchRequest{}, fmt.Errorf("failed to parse field JSON: %w", err)
+	}
+
+	// Extract field metadata
+	fieldMetadataRaw, ok := contextData["field_metadata"].([]interface{})
+	if !ok {
+		slog.ErrorContext(ctx, "field_metadata not found or invalid")
+		return ports.GreenfieldResearchRequest{}, fmt.Errorf("field_metadata not found or invalid")
+	}
+
+	// Convert to greenfield.FieldMetadata
+	fieldMetadata := make([]greenfield.FieldMetadata, 0, len(fieldMetadataRaw))
+	for _, fm := range fieldMetadataRaw {
+		fmMap, ok := fm.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fieldMetadata = append(fieldMetadata, greenfield.FieldMetadata{
+			Name:         getString(fmMap, "name"),
+			SemanticType: getString(fmMap, "semantic_type"),
+			DataType:     getString(fmMap, "data_type"),
+			Description:  getString(fmMap, "description"),
+		})
+	}
+	slog.DebugContext(ctx, "Fields for hypothesis generation", "fields", len(fieldMetadata))
+
+	// Extract stats artifacts from context data
+	var statsArtifacts []map[string]interface{}
+	if statsArtifactsRaw, ok := contextData["statistical_artifacts"].([]interface{}); ok {
+		statsArtifacts = make([]map[string]interface{}, 0, len(statsArtifactsRaw))
+		for _, sa := range statsArtifactsRaw {
+			if saMap, ok := sa.(map[string]interface{}); ok {
+				statsArtifacts = append(statsArtifacts, saMap)
+			}
+		}
+	}
+	slog.DebugContext(ctx, "Statistical artifacts for hypothesis generation", "artifacts", len(statsArtifacts))
+
+	// Generate validated hypothesis summary for feedback learning
+	var validatedHypothesisSummary interface{} = nil
+	if rw.hypothesisSummarizer != nil {
+		// Get user ID from session
+		session, err := rw.sessionMgr.GetSession(ctx, sessionID)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to get session for user ID", "error", err)
+		} else {
+			userID := session.UserID
+			summary, err := rw.hypothesisSummarizer.GenerateSummary(ctx, userID, 1000) // Last 1000 validated hypotheses
+			if err != nil {
+				slog.WarnContext(ctx, "Failed to generate validated hypothesis summary", "error", err)
+			} else if summary.TotalValidatedHypotheses > 0 {
+				validatedHypothesisSummary = summary
+				slog.InfoContext(ctx, "Validated hypotheses summarized for feedback learning", "validated", summary.TotalValidatedHypotheses)
+			} else {
+				slog.DebugContext(ctx, "No validated hypotheses for feedback learning")
+			}
+		}
+	} else {
+		slog.DebugContext(ctx, "Hypothesis summarizer not available for feedback learning")
+	}
+
+	return ports.GreenfieldResearchRequest{
+		RunID:                   core.RunID(sessionID),
+		SnapshotID:              core.SnapshotID(""), // Not used in UI flow
+		FieldMetadata:           fieldMetadata,
+		StatisticalArtifacts:    statsArtifacts,
+		DiscoveryBriefs:         nil,
+		ValidatedHypothesisSummary: validatedHypothesisSummary,
+		DomainPack:              domainPackFrom(ctx),
+		SelfConsistencySamples:  selfConsistencyFrom(ctx),
+		LLM:                     llmSettingsFrom(ctx),
+		Directives:              3,
+	}, nil
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/rand"
 	"time"
 
@@ -21,7 +21,7 @@ func (rw *ResearchWorker) loadMatrixBundleForHypothesisWithContext(ctx context.C
 	causeKey := core.VariableKey(directive.CauseKey)
 	effectKey := core.VariableKey(directive.EffectKey)
 
-	log.Printf("[ResearchWorker] 🔍 Resolving matrix for variables: cause=%s, effect=%s", causeKey, effectKey)
+	slog.DebugContext(ctx, "Resolving matrix", "cause", causeKey, "effect", effectKey)
 
 	// Retry logic for matrix resolution
 	maxRetries := 3
@@ -29,7 +29,7 @@ func (rw *ResearchWorker) loadMatrixBundleForHypothesisWithContext(ctx context.C
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
-			log.Printf("[ResearchWorker] 🔄 Matrix resolution retry %d/%d for cause=%s, effect=%s", attempt, maxRetries, causeKey, effectKey)
+			slog.InfoContext(ctx, "Retrying matrix resolution", "attempt", attempt, "max_attempts", maxRetries, "cause", causeKey, "effect", effectKey)
 			// Wait before retry with exponential backoff
 			time.Sleep(time.Duration(attempt-1) * 2 * time.Second)
 		}
@@ -44,26 +44,26 @@ func (rw *ResearchWorker) loadMatrixBundleForHypothesisWithContext(ctx context.C
 
 		if err == nil {
 			if bundle == nil {
-				log.Printf("[ResearchWorker] ❌ Matrix resolution returned nil bundle for cause=%s, effect=%s", causeKey, effectKey)
+				slog.WarnContext(ctx, "Matrix resolution returned nil bundle", "cause", causeKey, "effect", effectKey)
 				lastErr = fmt.Errorf("matrix resolution returned nil bundle")
 				continue // Retry even for nil bundle
 			}
 
-			log.Printf("[ResearchWorker] ✅ Matrix resolved successfully on attempt %d: %d entities, %d variables", attempt, len(bundle.Matrix.EntityIDs), len(bundle.Matrix.VariableKeys))
+			slog.DebugContext(ctx, "Matrix resolved", "attempt", attempt, "entities", len(bundle.Matrix.EntityIDs), "variables", len(bundle.Matrix.VariableKeys))
 			return bundle, nil
 		}
 
 		lastErr = err
-		log.Printf("[ResearchWorker] ❌ Matrix resolution attempt %d failed for cause=%s, effect=%s: %v", attempt, causeKey, effectKey, err)
+		slog.WarnContext(ctx, "Matrix resolution attempt failed", "attempt", attempt, "cause", causeKey, "effect", effectKey, "error", err)
 
 		// Check if context was cancelled
 		if ctx.Err() != nil {
-			log.Printf("[ResearchWorker] ❌ Matrix resolution cancelled due to context: %v", ctx.Err())
+			slog.WarnContext(ctx, "Matrix resolution cancelled", "error", ctx.Err())
 			return nil, ctx.Err()
 		}
 	}
 
-	log.Printf("[ResearchWorker] ❌ Matrix resolution failed after %d attempts for cause=%s, effect=%s: %v", maxRetries, causeKey, effectKey, lastErr)
+	slog.ErrorContext(ctx, "Matrix resolution failed", "attempts", maxRetries, "cause", causeKey, "effect", effectKey, "error", lastErr)
 	return nil, fmt.Errorf("matrix resolution failed after %d attempts: %w", maxRetries, lastErr)
 }
 
@@ -3,10 +3,12 @@ package research
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"time"
 
+	"gohypo/domain/greenfield"
+	"gohypo/models"
 	"gohypo/ports"
 
 	"github.com/google/uuid"
@@ -33,7 +35,7 @@ func (rw *ResearchWorker) savePromptToFile(ctx context.Context, sessionID, promp
 			"file_backup": true,
 		}
 		if err := promptRepo.SavePrompt(ctx, user.ID, sessionUUID, prompt, "research_directive", metadata); err != nil {
-			log.Printf("[ResearchWorker] ⚠️ Failed to save prompt to database: %v", err)
+			slog.WarnContext(ctx, "Failed to save prompt to database", "error", err)
 			// Continue with file backup even if DB save fails
 		}
 	}
@@ -54,4 +56,77 @@ func (rw *ResearchWorker) savePromptToFile(ctx context.Context, sessionID, promp
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// PreviewHypothesisPrompt assembles the prompt hypothesis generation would send for the given
+// fields and artifacts without calling the LLM. Column policies and the validated hypothesis
+// summary apply exactly as in a real run; the stats sweep that augments the artifacts is
+// skipped, so pass the artifacts to preview against.
+func (rw *ResearchWorker) PreviewHypothesisPrompt(ctx context.Context, sessionID string, fieldMetadata []greenfield.FieldMetadata, statsArtifacts []map[string]interface{}) (*ports.GreenfieldPromptPreview, error) {
+	previewer, ok := rw.greenfieldPort.(ports.GreenfieldPromptPreviewer)
+	if !ok {
+		return nil, fmt.Errorf("greenfield port does not support prompt previews")
+	}
+
+	ctx, fieldMetadata, statsArtifacts = rw.applyColumnPolicy(ctx, sessionID, fieldMetadata, statsArtifacts)
+	fieldJSON, err := rw.prepareFieldMetadata(fieldMetadata, statsArtifacts, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare field metadata: %w", err)
+	}
+	req, err := rw.buildGreenfieldRequest(ctx, sessionID, fieldJSON)
+	if err != nil {
+		return nil, err
+	}
+	return previewer.PreviewResearchPrompt(ctx, req)
+}
+
+type domainPackKey struct{}
+
+// WithDomainPack selects the few-shot exemplar pack for generation runs and previews started
+// with ctx. Without one the adapter uses the scout's detected domain.
+func WithDomainPack(ctx context.Context, pack string) context.Context {
+	if pack == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, domainPackKey{}, pack)
+}
+
+func domainPackFrom(ctx context.Context) string {
+	pack, _ := ctx.Value(domainPackKey{}).(string)
+	return pack
+}
+
+type selfConsistencyKey struct{}
+
+// WithSelfConsistency samples each generation call n times and keeps the consensus
+// hypotheses, trading tokens for reliability. n <= 1 leaves generation single-shot.
+func WithSelfConsistency(ctx context.Context, n int) context.Context {
+	if n <= 1 {
+		return ctx
+	}
+	return context.WithValue(ctx, selfConsistencyKey{}, n)
+}
+
+func selfConsistencyFrom(ctx context.Context) int {
+	n, _ := ctx.Value(selfConsistencyKey{}).(int)
+	return n
+}
+
+type llmSettingsKey struct{}
+
+// WithLLMSettings runs generation started with ctx on the given workspace and run LLM
+// settings instead of the global AI config
+func WithLLMSettings(ctx context.Context, settings models.LLMSettings) context.Context {
+	if settings.IsZero() {
+		return ctx
+	}
+	return context.WithValue(ctx, llmSettingsKey{}, settings)
+}
+
+func llmSettingsFrom(ctx context.Context) *models.LLMSettings {
+	settings, ok := ctx.Value(llmSettingsKey{}).(models.LLMSettings)
+	if !ok {
+		return nil
+	}
+	return &settings
+}
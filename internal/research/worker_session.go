@@ -54,7 +54,7 @@ func (rw *ResearchWorker) workerLoop(workerID int) {
 			// Check if session has been running too long
 			if sessionAge > sessionTimeout {
 				log.Printf("[ResearchWorker] ⏰ Session %s timed out after %.1f minutes", session.ID, sessionAge.Minutes())
-				rw.sessionMgr.SetSessionError(context.Background(), session.ID.String(), fmt.Sprintf("Session timed out after %.1f minutes", sessionAge.Minutes()))
+				rw.failSession(context.Background(), session.ID.String(), fmt.Sprintf("Session timed out after %.1f minutes", sessionAge.Minutes()))
 				timeoutCount++
 			}
 		}
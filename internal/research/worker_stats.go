@@ -3,7 +3,7 @@ package research
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,44 +13,76 @@ import (
 	"gohypo/domain/dataset"
 	"gohypo/domain/greenfield"
 	"gohypo/domain/stats"
+	"gohypo/internal/access"
+	crossdataset "gohypo/internal/dataset"
+	"gohypo/internal/logging"
+	"gohypo/models"
 	"gohypo/ports"
 )
 
+// analysisPolicy returns the analysis policy of a workspace, or the defaults when the
+// workspace cannot be read
+func (rw *ResearchWorker) analysisPolicy(ctx context.Context, workspaceID uuid.UUID) stats.AnalysisPolicy {
+	if rw.workspaces == nil || workspaceID == uuid.Nil {
+		return stats.DefaultAnalysisPolicy()
+	}
+	workspace, err := rw.workspaces.GetByID(ctx, core.ID(workspaceID.String()))
+	if err != nil || workspace == nil {
+		slog.WarnContext(ctx, "Using the default analysis policy", "workspace", workspaceID, "error", err)
+		return stats.DefaultAnalysisPolicy()
+	}
+	return stats.AnalysisPolicyFrom(workspace.Metadata)
+}
+
 // runStatsSweep executes statistical analysis on the current dataset and returns
 // a prompt-friendly artifact slice. This MUST be sourced from the active dataset
 // (e.g. Excel file behind the UI), never from hardcoded examples.
 func (rw *ResearchWorker) runStatsSweep(ctx context.Context, sessionID string, fieldMetadata []greenfield.FieldMetadata) ([]map[string]interface{}, error) {
-	log.Printf("[ResearchWorker] 🔬 Starting stats sweep for session %s", sessionID)
+	if logging.RunID(ctx) == "" {
+		ctx = logging.WithStage(logging.WithRun(ctx, sessionID), "sweep")
+	}
+	slog.InfoContext(ctx, "Starting stats sweep")
 
 	if rw.statsSweepSvc == nil {
-		log.Printf("[ResearchWorker] ❌ Stats sweep service not available for session %s", sessionID)
 		return nil, fmt.Errorf("stats sweep service not available")
 	}
 
 	// Get the session to check for workspace-based datasets
 	session, err := rw.sessionMgr.GetSession(ctx, sessionID)
 	if err != nil {
-		log.Printf("[ResearchWorker] ❌ Could not get session %s: %v", sessionID, err)
 		return nil, fmt.Errorf("could not get session: %w", err)
 	}
 
 	var resolver ports.MatrixResolverPort
 	var useUploadedDataset bool
+	excludedFields := map[string]bool{}
+	baselineKey := "testkit"
+	datasetID := ""
 
-	// Check if there's an uploaded dataset for this workspace
-	if session.WorkspaceID != uuid.Nil && rw.datasetRepo != nil {
-		log.Printf("[ResearchWorker] 🔍 Checking for uploaded datasets in workspace %s", session.WorkspaceID)
-
+	// A workspace sweep joins the workspace's datasets into one matrix; otherwise the newest
+	// uploaded dataset is swept
+	var combined *crossdataset.CombinedTable
+	if workspaceSweep(session) {
+		combined, err = rw.combineWorkspaceDatasets(ctx, session)
+		if err != nil {
+			return nil, fmt.Errorf("workspace sweep failed: %w", err)
+		}
+		logging.SetDataset(ctx, string(combined.Plan.BaseID))
+		slog.InfoContext(ctx, "Sweeping the workspace's datasets combined", "base", combined.Plan.BaseName,
+			"datasets", len(combined.Plan.Joins)+1, "columns", len(combined.Data.Headers))
+		resolver = crossdataset.NewCombinedMatrixResolver(combined)
+		useUploadedDataset = true
+		baselineKey = "workspace:" + session.WorkspaceID.String()
+		datasetID = string(combined.Plan.BaseID)
+	} else if session.WorkspaceID != uuid.Nil && rw.datasetRepo != nil {
 		// Get datasets for this workspace
 		datasets, err := rw.datasetRepo.GetByWorkspace(ctx, core.ID(session.WorkspaceID.String()), 10, 0)
-		log.Printf("[ResearchWorker] 📊 GetByWorkspace result: err=%v, found %d datasets", err, len(datasets))
+		slog.DebugContext(ctx, "Looked up workspace datasets", "workspace_id", session.WorkspaceID, "datasets", len(datasets), "error", err)
 
 		if err == nil && len(datasets) > 0 {
 			// Find the most recently updated dataset that has a file path
 			var selectedDataset *dataset.Dataset
 			for _, ds := range datasets {
-				log.Printf("[ResearchWorker] 📁 Found dataset: name=%s, status=%s, filepath=%s, updated=%v",
-					ds.DisplayName, ds.Status, ds.FilePath, ds.UpdatedAt)
 				if ds.Status == dataset.StatusReady && ds.FilePath != "" {
 					if selectedDataset == nil || ds.UpdatedAt.After(selectedDataset.UpdatedAt) {
 						selectedDataset = ds
@@ -59,33 +91,51 @@ func (rw *ResearchWorker) runStatsSweep(ctx context.Context, sessionID string, f
 			}
 
 			if selectedDataset != nil {
-				log.Printf("[ResearchWorker] ✅ Selected dataset: %s (file: %s)", selectedDataset.DisplayName, selectedDataset.FilePath)
+				logging.SetDataset(ctx, string(selectedDataset.ID))
+				slog.InfoContext(ctx, "Sweeping uploaded dataset", "name", selectedDataset.DisplayName, "file", selectedDataset.FilePath)
+
+				// Decrypt to a temp file when the dataset is encrypted at rest
+				filePath := selectedDataset.FilePath
+				if rw.datasetFiles != nil {
+					localPath, cleanup, err := rw.datasetFiles.LocalPath(ctx, filePath)
+					if err != nil {
+						return nil, fmt.Errorf("failed to open dataset file: %w", err)
+					}
+					defer cleanup()
+					filePath = localPath
+				}
 
 				// Create a matrix resolver for the uploaded dataset
 				excelConfig := excel.ExcelConfig{
-					FilePath: selectedDataset.FilePath,
+					FilePath: filePath,
 				}
 				resolver = excel.NewExcelMatrixResolverAdapter(excelConfig)
 				useUploadedDataset = true
-				log.Printf("[ResearchWorker] 📊 Using uploaded dataset matrix resolver for session %s", sessionID)
+				baselineKey = string(selectedDataset.ID)
+				datasetID = string(selectedDataset.ID)
+				excludedFields = selectedDataset.Metadata.ExcludedFields()
 			} else {
-				log.Printf("[ResearchWorker] ❌ No ready datasets with file paths found in workspace")
+				slog.WarnContext(ctx, "No ready datasets with file paths in workspace", "workspace_id", session.WorkspaceID)
 			}
 		} else {
-			log.Printf("[ResearchWorker] ❌ No datasets found in workspace: err=%v", err)
+			slog.WarnContext(ctx, "No datasets found in workspace", "workspace_id", session.WorkspaceID, "error", err)
 		}
 	} else {
-		log.Printf("[ResearchWorker] ❌ Cannot check uploaded datasets: session.WorkspaceID=%s, datasetRepo=%v", session.WorkspaceID, rw.datasetRepo != nil)
+		slog.WarnContext(ctx, "Cannot check uploaded datasets", "workspace_id", session.WorkspaceID, "dataset_repo", rw.datasetRepo != nil)
 	}
 
 	// Fall back to testkit if no uploaded dataset found
 	if !useUploadedDataset {
 		if rw.testkit == nil {
-			log.Printf("[ResearchWorker] ❌ Testkit not available for session %s", sessionID)
 			return nil, fmt.Errorf("testkit not available")
 		}
 		resolver = rw.testkit.MatrixResolverAdapter()
-		log.Printf("[ResearchWorker] 🧪 Using testkit matrix resolver for session %s", sessionID)
+		slog.InfoContext(ctx, "Sweeping the testkit dataset")
+	}
+
+	if rw.columnEnforcer != nil {
+		ctx = rw.columnEnforcer.ContextFor(ctx, core.ID(session.UserID.String()))
+		resolver = access.NewEnforcingResolver(resolver, rw.columnEnforcer)
 	}
 
 	// Resolve a matrix bundle for the variables we know about.
@@ -95,13 +145,20 @@ func (rw *ResearchWorker) runStatsSweep(ctx context.Context, sessionID string, f
 		if fm.Name == "" {
 			continue
 		}
+		if excludedFields[fm.Name] {
+			slog.InfoContext(ctx, "Leaving PII column out of the matrix", "column", fm.Name)
+			continue
+		}
 		varKeys = append(varKeys, core.VariableKey(fm.Name))
 	}
+	if combined != nil {
+		// The combined table holds the columns of every joined dataset, PII columns already
+		// left out
+		varKeys = combined.VariableKeys()
+	}
 	if len(varKeys) == 0 {
-		log.Printf("[ResearchWorker] ❌ No variable keys available for stats sweep in session %s", sessionID)
 		return nil, fmt.Errorf("no variable keys available for stats sweep")
 	}
-	log.Printf("[ResearchWorker] 📊 Resolving matrix bundle for %d variables in session %s", len(varKeys), sessionID)
 
 	matrixStart := time.Now()
 	bundle, err := resolver.ResolveMatrix(ctx, ports.MatrixResolutionRequest{
@@ -113,22 +170,50 @@ func (rw *ResearchWorker) runStatsSweep(ctx context.Context, sessionID string, f
 	matrixDuration := time.Since(matrixStart)
 
 	if err != nil {
-		log.Printf("[ResearchWorker] ❌ Matrix resolution failed after %.2fs for session %s: %v", matrixDuration.Seconds(), sessionID, err)
-		return nil, fmt.Errorf("failed to resolve matrix bundle: %w", err)
+		return nil, fmt.Errorf("failed to resolve matrix bundle after %.2fs: %w", matrixDuration.Seconds(), err)
 	}
-	log.Printf("[ResearchWorker] ✅ Matrix resolved in %.2fs for session %s (%d entities, %d variables)", matrixDuration.Seconds(), sessionID, len(bundle.Matrix.EntityIDs), len(bundle.Matrix.VariableKeys))
+	slog.InfoContext(ctx, "Matrix resolved", "entities", len(bundle.Matrix.EntityIDs), "variables", len(bundle.Matrix.VariableKeys),
+		"duration_sec", matrixDuration.Seconds())
 
 	// Run the sweep and return the resulting artifacts (relationships + manifest).
-	log.Printf("[ResearchWorker] 🧮 Running statistical sweep for session %s", sessionID)
 	sweepStart := time.Now()
-	sweepResp, err := rw.statsSweepSvc.RunStatsSweep(ctx, app.StatsSweepRequest{MatrixBundle: bundle})
+	baselineKey += ":" + session.UserID.String()
+	noiseFloor := rw.noiseFloor(ctx, session.WorkspaceID, datasetID)
+	if combined != nil {
+		// A floor calibrated on one dataset does not hold for columns joined from others
+		noiseFloor = nil
+	}
+	sweepResp, err := rw.statsSweepSvc.RunStatsSweep(ctx, app.StatsSweepRequest{
+		MatrixBundle: bundle,
+		RunID:        core.RunID("sweep-" + sessionID),
+		Baseline:     rw.sweepBaseline(ctx, baselineKey),
+		Policy:       rw.analysisPolicy(ctx, session.WorkspaceID),
+		NoiseFloor:   noiseFloor,
+	})
 	sweepDuration := time.Since(sweepStart)
 
 	if err != nil {
-		log.Printf("[ResearchWorker] ❌ Stats sweep failed after %.2fs for session %s: %v", sweepDuration.Seconds(), sessionID, err)
-		return nil, fmt.Errorf("stats sweep failed: %w", err)
+		return nil, fmt.Errorf("stats sweep failed after %.2fs: %w", sweepDuration.Seconds(), err)
+	}
+	fingerprint := ""
+	if payload, ok := sweepResp.Manifest.Payload.(map[string]interface{}); ok {
+		if fingerprint, ok = payload["bundle_fingerprint"].(string); ok {
+			logging.SetFingerprint(ctx, fingerprint)
+		}
 	}
-	log.Printf("[ResearchWorker] ✅ Stats sweep completed in %.2fs for session %s (%d relationships)", sweepDuration.Seconds(), sessionID, len(sweepResp.Relationships))
+	slog.InfoContext(ctx, "Stats sweep completed", "relationships", len(sweepResp.Relationships), "duration_sec", sweepDuration.Seconds())
+	if rw.recordSweepResults(ctx, "sweep-"+sessionID, sweepResp) {
+		rw.storeSweepBaseline(ctx, baselineKey, "sweep-"+sessionID)
+	}
+	rw.materializeRunSummary(ctx, sessionID, session.WorkspaceID, datasetID, sweepResp)
+	rw.publish(ctx, session.WorkspaceID, models.WebhookSweepCompleted, map[string]interface{}{
+		"session_id":         sessionID,
+		"dataset_id":         datasetID,
+		"run_id":             "sweep-" + sessionID,
+		"relationships":      len(sweepResp.Relationships),
+		"bundle_fingerprint": fingerprint,
+		"duration_sec":       sweepDuration.Seconds(),
+	})
 
 	artifacts := make([]map[string]interface{}, 0, len(sweepResp.Relationships)+1)
 	for _, a := range sweepResp.Relationships {
@@ -146,10 +231,119 @@ func (rw *ResearchWorker) runStatsSweep(ctx context.Context, sessionID string, f
 		"created_at": sweepResp.Manifest.CreatedAt,
 	})
 
-	log.Printf("[ResearchWorker] 📦 Stats sweep complete for session %s: %d total artifacts", sessionID, len(artifacts))
 	return artifacts, nil
 }
 
+// recordSweepResults keeps the sweep's relationships and manifest in the ledger under its
+// run, so the run's results can be exported. Artifact IDs are unique across runs, so each
+// is scoped to the run as checkpoints are. It reports whether every artifact was stored.
+func (rw *ResearchWorker) recordSweepResults(ctx context.Context, runID string, resp *app.StatsSweepResponse) bool {
+	if rw.testkit == nil {
+		return false
+	}
+	ledger := rw.testkit.LedgerAdapter()
+	for _, artifact := range append(append([]core.Artifact{}, resp.Relationships...), resp.Manifest) {
+		artifact.ID = core.ID(fmt.Sprintf("%s_%s", runID, artifact.ID))
+		if err := ledger.StoreArtifact(ctx, runID, artifact); err != nil {
+			slog.WarnContext(ctx, "Failed to store sweep artifact", "sweep_run_id", runID, "artifact_id", artifact.ID, "error", err)
+			return false
+		}
+	}
+	return true
+}
+
+// materializeRunSummary writes the sweep's aggregate tables in the background; a failure
+// only costs the summary, which pages then report as missing
+func (rw *ResearchWorker) materializeRunSummary(ctx context.Context, sessionID string, workspaceID uuid.UUID, datasetID string, resp *app.StatsSweepResponse) {
+	if rw.runSummaries == nil {
+		return
+	}
+	summary := app.SummarizeSweep("sweep-"+sessionID, resp, models.DefaultTopEffects)
+	summary.SessionID = &sessionID
+	if workspaceID != uuid.Nil {
+		workspace := workspaceID.String()
+		summary.WorkspaceID = &workspace
+	}
+	if datasetID != "" {
+		summary.DatasetID = &datasetID
+	}
+	go func() {
+		saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if err := rw.runSummaries.SaveRunSummary(saveCtx, summary); err != nil {
+			slog.WarnContext(ctx, "Failed to materialize run summary", "sweep_run_id", summary.RunID, "error", err)
+			return
+		}
+		slog.InfoContext(ctx, "Materialized run summary", "sweep_run_id", summary.RunID,
+			"relationships", summary.Relationships, "significant", summary.SignificantPairs)
+	}()
+}
+
+// sweepBaselineRunID is the ledger run holding a dataset and user's baseline pointers
+func sweepBaselineRunID(key string) core.RunID {
+	return core.RunID("sweep-baseline-" + string(core.NewHash([]byte(key))))
+}
+
+// sweepBaseline loads the last sweep recorded for a dataset and user from the ledger so
+// unchanged pairs can be reused. Without a ledger, or once retention has archived the
+// sweep, it returns nil and the next sweep runs in full.
+func (rw *ResearchWorker) sweepBaseline(ctx context.Context, key string) *app.SweepBaseline {
+	if rw.testkit == nil {
+		return nil
+	}
+	ledger := rw.testkit.LedgerAdapter()
+	pointers, err := ledger.GetArtifactsByRun(ctx, sweepBaselineRunID(key))
+	if err != nil || len(pointers) == 0 {
+		return nil
+	}
+	latest := pointers[0]
+	for _, pointer := range pointers[1:] {
+		if !pointer.CreatedAt.Before(latest.CreatedAt) {
+			latest = pointer
+		}
+	}
+	payload, _ := latest.Payload.(map[string]interface{})
+	sweepRunID, _ := payload["sweep_run_id"].(string)
+	if sweepRunID == "" {
+		return nil
+	}
+
+	artifacts, err := ledger.GetArtifactsByRun(ctx, core.RunID(sweepRunID))
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load sweep baseline", "sweep_run_id", sweepRunID, "error", err)
+		return nil
+	}
+	baseline := &app.SweepBaseline{}
+	for _, artifact := range artifacts {
+		switch artifact.Kind {
+		case core.ArtifactSweepManifest:
+			baseline.Manifest = artifact
+		case core.ArtifactRelationship:
+			baseline.Relationships = append(baseline.Relationships, artifact)
+		}
+	}
+	if baseline.Manifest.Kind == "" {
+		return nil
+	}
+	return baseline
+}
+
+// storeSweepBaseline records a sweep already in the ledger as the baseline for the next
+// sweep of the same dataset and user
+func (rw *ResearchWorker) storeSweepBaseline(ctx context.Context, key, sweepRunID string) {
+	runID := sweepBaselineRunID(key)
+	pointer := core.Artifact{
+		ID:   core.ID(fmt.Sprintf("%s_%s", runID, sweepRunID)),
+		Kind: core.ArtifactSweepBaseline,
+		Payload: map[string]interface{}{
+			"sweep_run_id": sweepRunID,
+		},
+		CreatedAt: core.Now(),
+	}
+	if err := rw.testkit.LedgerAdapter().StoreArtifact(ctx, string(runID), pointer); err != nil {
+		slog.WarnContext(ctx, "Failed to store sweep baseline", "sweep_run_id", sweepRunID, "error", err)
+	}
+}
 
 func coerceRelationshipPayloadMap(m map[string]interface{}) (stats.RelationshipPayload, bool) {
 	varX, _ := m["variable_x"].(string)
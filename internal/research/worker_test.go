@@ -0,0 +1,32 @@
+package research
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestPairWarnings verifies sweep warnings reach hypotheses that name the pair in either
+// order, from both in-process and JSON-decoded artifacts
+func TestPairWarnings(t *testing.T) {
+	artifacts := []map[string]interface{}{
+		{"kind": "association", "payload": map[string]interface{}{
+			"cause_key": "discount", "effect_key": "revenue", "warnings": []string{"OUTLIER_DRIVEN"},
+		}},
+		{"kind": "relationship", "payload": map[string]interface{}{
+			"variable_x": "revenue", "variable_y": "discount", "warnings": []interface{}{"LOW_N", "OUTLIER_DRIVEN"},
+		}},
+		{"kind": "association", "payload": map[string]interface{}{
+			"cause_key": "region", "effect_key": "revenue",
+		}},
+	}
+	warnings := pairWarnings(artifacts)
+
+	for _, pair := range [][2]string{{"discount", "revenue"}, {"revenue", "discount"}} {
+		if got := warnings[pair]; !slices.Equal(got, []string{"OUTLIER_DRIVEN", "LOW_N"}) {
+			t.Errorf("%v: expected OUTLIER_DRIVEN and LOW_N once each, got %v", pair, got)
+		}
+	}
+	if got, ok := warnings[[2]string{"region", "revenue"}]; ok {
+		t.Errorf("expected no entry for a pair without warnings, got %v", got)
+	}
+}
@@ -3,14 +3,18 @@ package research
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
 	"time"
 
 	"gohypo/domain/core"
+	"gohypo/domain/dataset"
 	"gohypo/internal/api"
 	refereePkg "gohypo/internal/referee"
 	"gohypo/internal/validation"
 	"gohypo/models"
+
+	"gonum.org/v1/gonum/stat"
 )
 
 // executeEValueValidation performs e-value dynamic validation for a single hypothesis
@@ -26,7 +30,7 @@ func (rw *ResearchWorker) executeAdvancedValidation(ctx context.Context, session
 
 	matrixBundle, err := rw.loadMatrixBundleForHypothesisWithContext(ctx, directive)
 	if err != nil {
-		log.Printf("[ResearchWorker] ERROR: Matrix loading failed for hypothesis %s: %v", directive.ID, err)
+		slog.ErrorContext(ctx, "Matrix loading failed", "hypothesis_id", directive.ID, "error", err)
 		rw.recordFailedHypothesis(ctx, sessionID, directive.ID, fmt.Sprintf("Matrix loading failed: %v", err))
 		return false
 	}
@@ -36,7 +40,7 @@ func (rw *ResearchWorker) executeAdvancedValidation(ctx context.Context, session
 	yData, ok2 := matrixBundle.GetColumnData(core.VariableKey(directive.EffectKey))
 
 	if !ok || !ok2 {
-		log.Printf("[ResearchWorker] ERROR: Variables not found for hypothesis %s", directive.ID)
+		slog.ErrorContext(ctx, "Variables not found", "hypothesis_id", directive.ID)
 		rw.recordFailedHypothesis(ctx, sessionID, directive.ID, "Variable data not found")
 		return false
 	}
@@ -61,12 +65,12 @@ func (rw *ResearchWorker) executeAdvancedValidation(ctx context.Context, session
 	if rw.validationOrchestrator != nil {
 		result, err := rw.validationOrchestrator.ValidateHypothesis(ctx, &directive, xData, yData, statEvidence)
 		if err != nil {
-			log.Printf("[ResearchWorker] Advanced validation failed for hypothesis %s: %v", directive.ID, err)
+			slog.WarnContext(ctx, "Advanced validation failed, falling back to e-value validation", "hypothesis_id", directive.ID, "error", err)
 			return rw.executeEValueValidation(ctx, sessionID, directive) // Fallback to basic validation
 		}
 
 		// Convert result to hypothesis result and save
-		return rw.saveAdvancedValidationResult(ctx, sessionID, directive, result)
+		return rw.saveAdvancedValidationResult(ctx, sessionID, directive, result, observedCorrelation(xData, yData))
 	}
 
 	// Fallback to basic validation
@@ -76,11 +80,11 @@ func (rw *ResearchWorker) executeAdvancedValidation(ctx context.Context, session
 // executeEValueValidationWithEvidence performs e-value dynamic validation with optional discovery evidence
 func (rw *ResearchWorker) executeEValueValidationWithEvidence(ctx context.Context, sessionID string, directive models.ResearchDirectiveResponse, discoveryEvidence []refereePkg.DiscoveryEvidence) bool {
 	hypothesisID := directive.ID
-	log.Printf("[ResearchWorker] Starting validation for hypothesis %s", hypothesisID)
+	slog.DebugContext(ctx, "Starting validation", "hypothesis_id", hypothesisID)
 
 	// Validate referee selection (require at least 1 referee)
 	if err := directive.RefereeGates.Validate(); err != nil {
-		log.Printf("[ResearchWorker] ERROR: Invalid referee selection for hypothesis %s: %v", hypothesisID, err)
+		slog.ErrorContext(ctx, "Invalid referee selection", "hypothesis_id", hypothesisID, "error", err)
 		rw.recordFailedHypothesis(ctx, sessionID, hypothesisID, fmt.Sprintf("Invalid referee selection: %v", err))
 		return false
 	}
@@ -94,7 +98,7 @@ func (rw *ResearchWorker) executeEValueValidationWithEvidence(ctx context.Contex
 
 	matrixBundle, err := rw.loadMatrixBundleForHypothesisWithContext(ctx, directive)
 	if err != nil {
-		log.Printf("[ResearchWorker] ERROR: Matrix loading failed for hypothesis %s: %v", hypothesisID, err)
+		slog.ErrorContext(ctx, "Matrix loading failed", "hypothesis_id", hypothesisID, "error", err)
 		rw.recordFailedHypothesis(ctx, sessionID, hypothesisID, fmt.Sprintf("Matrix loading failed: %v", err))
 		return false
 	}
@@ -109,22 +113,30 @@ func (rw *ResearchWorker) executeEValueValidationWithEvidence(ctx context.Contex
 	if ok && ok2 && len(xData) > 0 {
 		sampleSize = len(xData)
 	}
-	log.Printf("[ResearchWorker] 📏 Sample size for hypothesis %s: %d data points", hypothesisID, sampleSize)
+	slog.DebugContext(ctx, "Sample size", "hypothesis_id", hypothesisID, "sample_size", sampleSize)
 
 	if !ok || !ok2 {
-		log.Printf("[ResearchWorker] ERROR: Variables not found for hypothesis %s - cause: %s, effect: %s", hypothesisID, directive.CauseKey, directive.EffectKey)
+		slog.ErrorContext(ctx, "Variables not found", "hypothesis_id", hypothesisID, "cause", directive.CauseKey, "effect", directive.EffectKey)
 		rw.recordFailedHypothesis(ctx, sessionID, hypothesisID, fmt.Sprintf("Variable data not found: cause=%s, effect=%s", directive.CauseKey, directive.EffectKey))
 		return false
 	}
 
 	// Execute referees concurrently for dynamic validation
-	log.Printf("[ResearchWorker] Executing %d referees for hypothesis %s", refereeCount, hypothesisID)
+	slog.DebugContext(ctx, "Executing referees", "hypothesis_id", hypothesisID, "referees", refereeCount)
+
+	refereeNames := make([]string, 0, refereeCount)
+	for _, refereeSelection := range directive.RefereeGates.SelectedReferees {
+		refereeNames = append(refereeNames, refereeSelection.Name)
+	}
+	battery := rw.validationProgress.Begin(ctx, sessionID, hypothesisID, refereeNames, sampleSize)
+	defer battery.Done()
 
 	type refereeJob struct {
 		index    int
 		name     string
 		result   models.RefereeResult
 		duration time.Duration
+		err      error
 	}
 
 	jobs := make(chan refereeJob, refereeCount)
@@ -133,10 +145,11 @@ func (rw *ResearchWorker) executeEValueValidationWithEvidence(ctx context.Contex
 	for i, refereeSelection := range directive.RefereeGates.SelectedReferees {
 		refereeName := refereeSelection.Name
 		go func(index int, name string) {
+			battery.Start(ctx, name)
 			jobStart := time.Now()
 			refereeInstance, err := refereePkg.GetRefereeFactory(name)
 			if err != nil {
-				log.Printf("[ResearchWorker] ERROR: Cannot create referee %s for hypothesis %s: %v", name, hypothesisID, err)
+				slog.ErrorContext(ctx, "Cannot create referee", "hypothesis_id", hypothesisID, "referee", name, "error", err)
 				jobs <- refereeJob{
 					index: index,
 					name:  name,
@@ -149,6 +162,18 @@ func (rw *ResearchWorker) executeEValueValidationWithEvidence(ctx context.Contex
 						FailureReason: fmt.Sprintf("Referee creation failed: %v", err),
 					},
 					duration: time.Since(jobStart),
+					err:      err,
+				}
+				return
+			}
+
+			// The counterfactual referee simulates interventions on the resolved matrix itself
+			if intervention, ok := refereeInstance.(*refereePkg.SyntheticIntervention); ok {
+				jobs <- refereeJob{
+					index:    index,
+					name:     name,
+					result:   intervention.ExecuteOnBundle(matrixBundle, core.VariableKey(directive.CauseKey), core.VariableKey(directive.EffectKey)),
+					duration: time.Since(jobStart),
 				}
 				return
 			}
@@ -187,8 +212,9 @@ func (rw *ResearchWorker) executeEValueValidationWithEvidence(ctx context.Contex
 		job := <-jobs
 		refereeResults[job.index] = job.result
 		if !job.result.Passed {
-			log.Printf("[ResearchWorker] Referee %s failed: %s", job.name, job.result.FailureReason)
+			slog.InfoContext(ctx, "Referee failed", "hypothesis_id", hypothesisID, "referee", job.name, "reason", job.result.FailureReason)
 		}
+		progress := battery.Finish(ctx, job.name, job.result, job.duration, job.err)
 
 		// Send SSE update for each referee completion
 		if sseHub, ok := rw.sseHub.(*api.SSEHub); ok {
@@ -205,34 +231,145 @@ func (rw *ResearchWorker) executeEValueValidationWithEvidence(ctx context.Contex
 			if !job.result.Passed {
 				eventData["failure_reason"] = job.result.FailureReason
 			}
+			for key, value := range progressEventData(progress) {
+				eventData[key] = value
+			}
 			sseHub.Broadcast(api.ResearchEvent{
 				SessionID:    sessionID,
 				EventType:    "referee_completed",
 				HypothesisID: hypothesisID,
-				Progress:     50.0 + (float64(i+1)/float64(refereeCount))*40.0,
+				Progress:     refereeEventProgress(progress),
 				Data:         eventData,
 				Timestamp:    time.Now(),
 			})
 		}
 	}
 
+	// Every hypothesis also faces the counterfactual gate, selected or not
+	if !hasRefereeResult(refereeResults, refereePkg.CounterfactualGateName) {
+		battery.Add(ctx, "synthetic_intervention")
+		battery.Start(ctx, "synthetic_intervention")
+		gateStart := time.Now()
+		intervention, _ := refereePkg.GetRefereeFactory("synthetic_intervention")
+		result := intervention.(*refereePkg.SyntheticIntervention).ExecuteOnBundle(matrixBundle, core.VariableKey(directive.CauseKey), core.VariableKey(directive.EffectKey))
+		battery.Finish(ctx, "synthetic_intervention", result, time.Since(gateStart), nil)
+		slog.InfoContext(ctx, "Counterfactual gate", "hypothesis_id", hypothesisID, "passed", result.Passed, "effect", result.Statistic)
+		refereeResults = append(refereeResults, result)
+	}
+
+	// Binary causes also get a propensity-matched re-estimate to expose confounding
+	matching := rw.runPropensityMatching(ctx, sessionID, directive, matrixBundle)
+
+	// A failing verdict comes with the evidence that killed it
+	counterexamples := failureCounterexamples(refereeResults, matrixBundle, directive, matching)
+
 	// Simple e-value dynamic validation - calculate overall result
-	return rw.acceptHypothesisWithEValue(ctx, sessionID, directive, refereeResults, sampleSize)
+	return rw.acceptHypothesisWithEValue(ctx, sessionID, directive, refereeResults, sampleSize, observedCorrelation(xData, yData), matching, counterexamples)
 }
 
-// acceptHypothesisWithEValue performs simple e-value dynamic validation
-func (rw *ResearchWorker) acceptHypothesisWithEValue(ctx context.Context, sessionID string, directive models.ResearchDirectiveResponse, refereeResults []models.RefereeResult, sampleSize int) bool {
-	id := directive.ID
+// observedCorrelation is the Pearson correlation over rows where both variables are present,
+// or NaN when there are too few. Exported monitoring metrics use its sign as the direction
+// the relationship was validated in.
+func observedCorrelation(x, y []float64) float64 {
+	var xs, ys []float64
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if math.IsNaN(x[i]) || math.IsNaN(y[i]) {
+			continue
+		}
+		xs = append(xs, x[i])
+		ys = append(ys, y[i])
+	}
+	if len(xs) < 3 {
+		return math.NaN()
+	}
+	return stat.Correlation(xs, ys, nil)
+}
 
-	passedReferees := 0
-	totalReferees := len(refereeResults)
-	for _, result := range refereeResults {
+// runPropensityMatching re-estimates the effect of a binary cause on a propensity-matched
+// sample and records the before/after comparison. Non-binary causes are skipped.
+func (rw *ResearchWorker) runPropensityMatching(ctx context.Context, sessionID string, directive models.ResearchDirectiveResponse, bundle *dataset.MatrixBundle) *refereePkg.PropensityMatchReport {
+	cause := core.VariableKey(directive.CauseKey)
+	if column, ok := bundle.GetColumnData(cause); !ok || !refereePkg.IsBinary(column) {
+		return nil
+	}
+	matcher := &refereePkg.PropensityMatching{}
+	report, err := matcher.MatchOnBundle(bundle, cause, core.VariableKey(directive.EffectKey))
+	if err != nil {
+		slog.WarnContext(ctx, "Propensity matching skipped", "hypothesis_id", directive.ID, "error", err)
+		return nil
+	}
+	slog.InfoContext(ctx, "Propensity matching", "hypothesis_id", directive.ID, "naive_effect", report.NaiveEffect,
+		"matched_effect", report.MatchedEffect, "matched_pairs", report.MatchedPairs, "confounded", report.Confounded)
+
+	if rw.testkit != nil {
+		artifact := core.Artifact{
+			ID:        core.ID(fmt.Sprintf("propensity_match_%s", directive.ID)),
+			Kind:      core.ArtifactPropensityMatch,
+			Payload:   report,
+			CreatedAt: core.Now(),
+		}
+		if err := rw.testkit.LedgerAdapter().StoreArtifact(ctx, sessionID, artifact); err != nil {
+			slog.WarnContext(ctx, "Failed to store propensity matching artifact", "hypothesis_id", directive.ID, "error", err)
+		}
+	}
+	return report
+}
+
+// recordVerdict stores a hypothesis' verdict in the ledger and notes on the hypothesis where
+// it was stored, so every number its pages show links back to the artifact it came from
+func (rw *ResearchWorker) recordVerdict(ctx context.Context, sessionID string, h *models.HypothesisResult) {
+	if rw.testkit == nil {
+		return
+	}
+	artifact := core.Artifact{
+		ID:        core.ID(models.VerdictArtifactID(h.ID)),
+		Kind:      core.ArtifactHypothesis,
+		Payload:   h,
+		CreatedAt: core.Now(),
+	}
+	provenance := models.ArtifactProvenance(artifact)
+	h.ExecutionMetadata[models.ProvenanceKey] = provenance
+	if err := rw.testkit.LedgerAdapter().StoreArtifact(ctx, sessionID, artifact); err != nil {
+		delete(h.ExecutionMetadata, models.ProvenanceKey)
+		slog.WarnContext(ctx, "Failed to store verdict artifact", "hypothesis_id", h.ID, "error", err)
+	}
+}
+
+// failureCounterexamples gathers, when no gate passed, the evidence against the hypothesis:
+// what the gates reported, a confounded propensity match, and a scan of the matrix for
+// reversing segments, fading time windows and absorbing confounders
+func failureCounterexamples(results []models.RefereeResult, bundle *dataset.MatrixBundle, directive models.ResearchDirectiveResponse, matching *refereePkg.PropensityMatchReport) []models.Counterexample {
+	for _, result := range results {
 		if result.Passed {
-			passedReferees++
+			return nil
 		}
 	}
+	found := models.CounterexamplesFromReferees(results)
+	if c := matching.Counterexample(); c != nil {
+		found = append(found, *c)
+	}
+	found = append(found, refereePkg.FindCounterexamples(bundle, core.VariableKey(directive.CauseKey), core.VariableKey(directive.EffectKey))...)
+	return models.RankCounterexamples(found)
+}
 
-	overallPassed := passedReferees > 0 || totalReferees == 0
+// hasRefereeResult reports whether a gate already ran for the hypothesis
+func hasRefereeResult(results []models.RefereeResult, gateName string) bool {
+	for _, result := range results {
+		if result.GateName == gateName {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptHypothesisWithEValue performs simple e-value dynamic validation
+func (rw *ResearchWorker) acceptHypothesisWithEValue(ctx context.Context, sessionID string, directive models.ResearchDirectiveResponse, refereeResults []models.RefereeResult, sampleSize int, correlation float64, matching *refereePkg.PropensityMatchReport, counterexamples []models.Counterexample) bool {
+	id := directive.ID
+
+	// The counterfactual gate must pass, but it does not count as a referee
+	verdict := refereePkg.Decide(refereeResults)
+	passedReferees, totalReferees := verdict.PassedReferees, verdict.TotalReferees
+	overallPassed := verdict.Passed
 
 	confidence := 0.5
 	if totalReferees > 0 {
@@ -253,7 +390,10 @@ func (rw *ResearchWorker) acceptHypothesisWithEValue(ctx context.Context, sessio
 			"validation_method": "e_value_dynamic",
 			"passed_referees":   passedReferees,
 			"total_referees":    totalReferees,
+			"counterfactual":    verdict.CounterfactualPassed,
 			"sample_size":       sampleSize,
+			"cause_key":         directive.CauseKey,
+			"effect_key":        directive.EffectKey,
 		},
 		PhaseEValues:     []float64{0.0, 0.0, 0.0},
 		FeasibilityScore: 0.0,
@@ -265,17 +405,43 @@ func (rw *ResearchWorker) acceptHypothesisWithEValue(ctx context.Context, sessio
 		Status:           "completed",
 	}
 
+	if directive.PromptBudget != nil {
+		hypothesisResult.ExecutionMetadata["prompt_budget"] = directive.PromptBudget
+	}
+	if directive.SelfConsistency != nil {
+		hypothesisResult.ExecutionMetadata["self_consistency"] = directive.SelfConsistency
+	}
+	if len(directive.DataWarnings) > 0 {
+		hypothesisResult.ExecutionMetadata[models.DataWarningsKey] = directive.DataWarnings
+	}
+	if matching != nil {
+		hypothesisResult.ExecutionMetadata["propensity_match"] = matching
+	}
+	if !math.IsNaN(correlation) {
+		hypothesisResult.ExecutionMetadata["observed_correlation"] = correlation
+		rw.placeAgainstNoiseFloor(ctx, sessionID, &hypothesisResult, correlation)
+	}
+	if !overallPassed && len(counterexamples) > 0 {
+		hypothesisResult.ExecutionMetadata[models.CounterexamplesKey] = counterexamples
+	}
+	hypothesisResult.ExecutionMetadata[models.EngineVersionsKey] = engineVersions(hypothesisResult.RefereeResults)
+	hypothesisResult.ExecutionMetadata[models.VerdictExplanationKey] = models.ExplainVerdict(&hypothesisResult)
+	rw.recordVerdict(ctx, sessionID, &hypothesisResult)
+
 	if err := rw.storage.SaveHypothesis(ctx, &hypothesisResult); err != nil {
-		log.Printf("[ResearchWorker] ERROR: Failed to save hypothesis %s: %v", id, err)
+		slog.ErrorContext(ctx, "Failed to save hypothesis", "hypothesis_id", id, "error", err)
 		return false
 	}
 
-	log.Printf("[ResearchWorker] Hypothesis %s validation completed", id)
+	slog.DebugContext(ctx, "Hypothesis saved", "hypothesis_id", id, "passed", overallPassed)
+	if overallPassed {
+		rw.publishValidated(ctx, &hypothesisResult)
+	}
 	return overallPassed
 }
 
 // saveAdvancedValidationResult converts advanced validation result to hypothesis result and saves it
-func (rw *ResearchWorker) saveAdvancedValidationResult(ctx context.Context, sessionID string, directive models.ResearchDirectiveResponse, result *validation.ValidationResult) bool {
+func (rw *ResearchWorker) saveAdvancedValidationResult(ctx context.Context, sessionID string, directive models.ResearchDirectiveResponse, result *validation.ValidationResult, correlation float64) bool {
 	// Create hypothesis result from advanced validation
 	hypothesisResult := models.HypothesisResult{
 		ID:                  result.HypothesisID,
@@ -292,6 +458,8 @@ func (rw *ResearchWorker) saveAdvancedValidationResult(ctx context.Context, sess
 			"execution_time_ms": result.ExecutionTime.Milliseconds(),
 			"confidence_score":  result.Confidence,
 			"e_value":          result.EValue,
+			"cause_key":         directive.CauseKey,
+			"effect_key":        directive.EffectKey,
 		},
 		PhaseEValues:     []float64{result.EValue, result.EValue, result.EValue},
 		FeasibilityScore: 0.8, // Would be calculated based on validation metrics
@@ -302,6 +470,10 @@ func (rw *ResearchWorker) saveAdvancedValidationResult(ctx context.Context, sess
 		Confidence:       result.Confidence,
 		Status:           "completed",
 	}
+	if !math.IsNaN(correlation) {
+		hypothesisResult.ExecutionMetadata["observed_correlation"] = correlation
+		rw.placeAgainstNoiseFloor(ctx, sessionID, &hypothesisResult, correlation)
+	}
 
 	// Add stability information if available
 	if result.StabilityResult != nil {
@@ -319,21 +491,42 @@ func (rw *ResearchWorker) saveAdvancedValidationResult(ctx context.Context, sess
 		hypothesisResult.ExecutionMetadata["auditor_reasoning"] = result.AuditorResult.Reasoning
 	}
 
+	if directive.PromptBudget != nil {
+		hypothesisResult.ExecutionMetadata["prompt_budget"] = directive.PromptBudget
+	}
+	if directive.SelfConsistency != nil {
+		hypothesisResult.ExecutionMetadata["self_consistency"] = directive.SelfConsistency
+	}
+	if len(directive.DataWarnings) > 0 {
+		hypothesisResult.ExecutionMetadata[models.DataWarningsKey] = directive.DataWarnings
+	}
+	if !result.Passed {
+		if counterexamples := models.RankCounterexamples(models.CounterexamplesFromReferees(result.RefereeResults)); len(counterexamples) > 0 {
+			hypothesisResult.ExecutionMetadata[models.CounterexamplesKey] = counterexamples
+		}
+	}
+	hypothesisResult.ExecutionMetadata[models.EngineVersionsKey] = engineVersions(hypothesisResult.RefereeResults)
+	hypothesisResult.ExecutionMetadata[models.VerdictExplanationKey] = models.ExplainVerdict(&hypothesisResult)
+	rw.recordVerdict(ctx, sessionID, &hypothesisResult)
+
 	// Save to storage
 	if err := rw.storage.SaveHypothesis(ctx, &hypothesisResult); err != nil {
-		log.Printf("[ResearchWorker] ERROR: Failed to save advanced validation result for hypothesis %s: %v", result.HypothesisID, err)
+		slog.ErrorContext(ctx, "Failed to save advanced validation result", "hypothesis_id", result.HypothesisID, "error", err)
 		return false
 	}
 
-	log.Printf("[ResearchWorker] ✅ Advanced validation completed for hypothesis %s: passed=%v, confidence=%.3f, e-value=%.2f",
-		result.HypothesisID, result.Passed, result.Confidence, result.EValue)
+	slog.InfoContext(ctx, "Advanced validation completed", "hypothesis_id", result.HypothesisID, "passed", result.Passed,
+		"confidence", result.Confidence, "e_value", result.EValue)
+	if result.Passed {
+		rw.publishValidated(ctx, &hypothesisResult)
+	}
 
 	return result.Passed
 }
 
 // recordFailedHypothesis creates a failed hypothesis result for error cases
 func (rw *ResearchWorker) recordFailedHypothesis(ctx context.Context, sessionID, hypothesisID, failureReason string) {
-	log.Printf("[ResearchWorker] Recording failed hypothesis %s: %s", hypothesisID, failureReason)
+	slog.InfoContext(ctx, "Recording failed hypothesis", "hypothesis_id", hypothesisID, "reason", failureReason)
 
 	failedResult := models.HypothesisResult{
 		ID:                  hypothesisID,
@@ -363,8 +556,12 @@ func (rw *ResearchWorker) recordFailedHypothesis(ctx context.Context, sessionID,
 	}
 
 	if err := rw.storage.SaveHypothesis(ctx, &failedResult); err != nil {
-		log.Printf("[ResearchWorker] ERROR: Failed to save failed hypothesis %s: %v", hypothesisID, err)
+		slog.ErrorContext(ctx, "Failed to save failed hypothesis", "hypothesis_id", hypothesisID, "error", err)
 	}
+}
 
-	log.Printf("[ResearchWorker] Error handling complete for hypothesis %s", hypothesisID)
+// RevalidateHypothesis runs a stored hypothesis through validation again, in its original
+// session, and reports whether it passed. The stored result is replaced with the new one.
+func (rw *ResearchWorker) RevalidateHypothesis(ctx context.Context, hypothesis *models.HypothesisResult) bool {
+	return rw.executeEValueValidation(ctx, hypothesis.SessionID, models.DirectiveFromHypothesis(hypothesis))
 }
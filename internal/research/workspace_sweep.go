@@ -0,0 +1,83 @@
+package research
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	crossdataset "gohypo/internal/dataset"
+	"gohypo/models"
+)
+
+// Session metadata choosing how a sweep resolves its variables
+const (
+	// SweepModeKey selects the datasets a sweep reads; absent sweeps the newest dataset
+	SweepModeKey = "sweep_mode"
+
+	// SweepModeWorkspace sweeps the workspace's datasets joined into one matrix
+	SweepModeWorkspace = "workspace"
+
+	// AlignFrequencyKey is the period a workspace sweep matches timestamps of time series in
+	AlignFrequencyKey = "align_frequency"
+)
+
+// workspaceSweep reports whether the session asked to sweep all of its workspace's datasets
+func workspaceSweep(session *models.ResearchSession) bool {
+	mode, _ := session.Metadata[SweepModeKey].(string)
+	return mode == SweepModeWorkspace
+}
+
+// combineWorkspaceDatasets joins the ready datasets of the session's workspace into one table,
+// matching rows on the join keys their values share and aligning time series on the session's
+// alignment frequency
+func (rw *ResearchWorker) combineWorkspaceDatasets(ctx context.Context, session *models.ResearchSession) (*crossdataset.CombinedTable, error) {
+	if rw.datasetRepo == nil {
+		return nil, fmt.Errorf("dataset repository not available")
+	}
+	all, err := rw.datasetRepo.GetByWorkspace(ctx, core.ID(session.WorkspaceID.String()), 100, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspace datasets: %w", err)
+	}
+	var datasets []*dataset.Dataset
+	for _, ds := range all {
+		if ds.Status == dataset.StatusReady && ds.FilePath != "" {
+			datasets = append(datasets, ds)
+		}
+	}
+	if len(datasets) < 2 {
+		return nil, fmt.Errorf("a workspace sweep needs at least two ready datasets, the workspace has %d", len(datasets))
+	}
+
+	frequency, _ := session.Metadata[AlignFrequencyKey].(string)
+	frequency, err = crossdataset.ParseAlignFrequency(frequency)
+	if err != nil {
+		return nil, err
+	}
+	combiner := crossdataset.NewCrossDatasetCombiner(crossdataset.NewLocatedColumnReader(rw.datasetFiles), frequency)
+	plan, err := combiner.Plan(ctx, datasets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan the workspace sweep: %w", err)
+	}
+	if len(plan.Joins) == 0 {
+		return nil, fmt.Errorf("no dataset of the workspace shares a join key or a time column with %s", plan.BaseName)
+	}
+	table, err := combiner.Combine(ctx, datasets, plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine workspace datasets: %w", err)
+	}
+
+	for _, join := range plan.Joins {
+		slog.InfoContext(ctx, "Joined dataset into the workspace sweep", "dataset", join.DatasetName, "on", join.Describe(),
+			"matched_rows", join.MatchedRows, "aggregated_rows", join.AggregatedRows)
+		if report := join.EntityResolution; report != nil {
+			slog.InfoContext(ctx, "Resolved entity names for the workspace sweep", "dataset", join.DatasetName, "match_rate", report.MatchRate,
+				"exact", report.Exact, "phonetic", report.Phonetic, "fuzzy", report.Fuzzy, "ambiguous", report.Ambiguous, "unmatched", report.Unmatched)
+		}
+	}
+	for _, skipped := range plan.Skipped {
+		slog.WarnContext(ctx, "Left dataset out of the workspace sweep", "dataset", skipped.DatasetName, "reason", skipped.Reason)
+	}
+	return table, nil
+}
@@ -0,0 +1,440 @@
+// Package resilience retries transient upstream failures with exponential backoff and jitter,
+// stops calling an upstream that keeps failing with a circuit breaker, bounds the extra load
+// retries add with a retry budget, and paces calls to stay under a provider's rate limit.
+// Every upstream (an LLM provider, a blob store) gets its own breaker, budget, pacing and
+// counters, looked up by name with For.
+package resilience
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "gohypo/internal/errors"
+)
+
+// ErrCircuitOpen is returned without calling the upstream while its breaker is open
+var ErrCircuitOpen = apperrors.New(apperrors.CodeExternalService, "circuit open: upstream is failing, try again later")
+
+// retryBudgetBurst is how many retries an idle upstream may spend before the budget refills
+const retryBudgetBurst = 10
+
+// Policy configures retries and the circuit breaker for one upstream
+type Policy struct {
+	MaxAttempts int           // total attempts per call, including the first
+	BaseDelay   time.Duration // delay before the first retry; doubles per retry
+	MaxDelay    time.Duration // cap on a single backoff delay
+
+	BreakerThreshold int           // consecutive transient failures that open the breaker; 0 disables it
+	BreakerCooldown  time.Duration // how long the breaker stays open before a probe call
+
+	// RetryBudget is the fraction of calls that may be retried at steady state: every call
+	// deposits this many tokens and every retry spends one. 0 leaves retries unbudgeted.
+	RetryBudget float64
+
+	// RequestsPerMinute spaces attempts evenly so concurrent callers stay under a provider's
+	// rate limit. 0 leaves calls unpaced; a Retry-After hint still pauses every caller.
+	RequestsPerMinute float64
+
+	// Retryable decides whether an error is worth retrying; nil uses Transient
+	Retryable func(error) bool
+}
+
+// DefaultPolicy suits remote APIs: 4 attempts over roughly 7 seconds, a breaker that opens
+// after 5 straight failures for 30 seconds, and retries capped at 20% of traffic.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:      4,
+		BaseDelay:        500 * time.Millisecond,
+		MaxDelay:         8 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+		RetryBudget:      0.2,
+	}
+}
+
+// PolicyFromEnv overrides base with RESILIENCE_<UPSTREAM>_* variables, e.g.
+// RESILIENCE_LLM_OPENAI_MAX_ATTEMPTS=6 or RESILIENCE_BLOB_S3_BREAKER_COOLDOWN=1m. Invalid values
+// are logged and ignored.
+func PolicyFromEnv(upstream string, base Policy) Policy {
+	prefix := "RESILIENCE_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", ":", "_").Replace(upstream)) + "_"
+	intVar := func(name string, dst *int) {
+		if raw := os.Getenv(prefix + name); raw != "" {
+			if v, err := strconv.Atoi(raw); err == nil && v >= 0 {
+				*dst = v
+			} else {
+				log.Printf("[Resilience] ⚠️ Ignoring invalid %s%s=%q", prefix, name, raw)
+			}
+		}
+	}
+	durationVar := func(name string, dst *time.Duration) {
+		if raw := os.Getenv(prefix + name); raw != "" {
+			if v, err := time.ParseDuration(raw); err == nil && v >= 0 {
+				*dst = v
+			} else {
+				log.Printf("[Resilience] ⚠️ Ignoring invalid %s%s=%q", prefix, name, raw)
+			}
+		}
+	}
+	floatVar := func(name string, dst *float64) {
+		if raw := os.Getenv(prefix + name); raw != "" {
+			if v, err := strconv.ParseFloat(raw, 64); err == nil && v >= 0 {
+				*dst = v
+			} else {
+				log.Printf("[Resilience] ⚠️ Ignoring invalid %s%s=%q", prefix, name, raw)
+			}
+		}
+	}
+
+	policy := base
+	intVar("MAX_ATTEMPTS", &policy.MaxAttempts)
+	durationVar("BASE_DELAY", &policy.BaseDelay)
+	durationVar("MAX_DELAY", &policy.MaxDelay)
+	intVar("BREAKER_THRESHOLD", &policy.BreakerThreshold)
+	durationVar("BREAKER_COOLDOWN", &policy.BreakerCooldown)
+	floatVar("RETRY_BUDGET", &policy.RetryBudget)
+	floatVar("REQUESTS_PER_MINUTE", &policy.RequestsPerMinute)
+	return policy
+}
+
+// Transient reports whether err is likely to succeed on retry: throttling, 5xx responses,
+// timeouts and connection failures. Caller cancellation, client errors, missing files or
+// rows, and classified application errors (validation, not found, ...) are permanent.
+func Transient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) || errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+	var status interface{ HTTPStatus() int }
+	if errors.As(err, &status) {
+		switch status.HTTPStatus() {
+		case 408, 425, 429, 500, 502, 503, 504:
+			return true
+		}
+		return false
+	}
+	if appErr := apperrors.Classify(err); appErr != nil {
+		switch appErr.Code {
+		case apperrors.CodeValidationError, apperrors.CodeInvalidInput, apperrors.CodeNotFound,
+			apperrors.CodeConflict, apperrors.CodeDeterminismViolation, apperrors.CodeUnauthorized,
+			apperrors.CodeForbidden, apperrors.CodeConfigInvalid:
+			return false
+		}
+	}
+	return true
+}
+
+// Breaker states
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+// Stats are an upstream's counters since process start
+type Stats struct {
+	Upstream      string     `json:"upstream"`
+	State         string     `json:"state"`
+	Calls         int64      `json:"calls"`
+	Failures      int64      `json:"failures"`
+	Retries       int64      `json:"retries"`
+	RetriesDenied int64      `json:"retries_denied"` // retries skipped because the budget was spent
+	Trips         int64      `json:"trips"`          // times the breaker opened
+	Rejected      int64      `json:"rejected"`       // calls refused while the breaker was open
+	Throttled     int64      `json:"throttled"`      // attempts delayed by pacing or a Retry-After pause
+	LastTripAt    *time.Time `json:"last_trip_at,omitempty"`
+}
+
+// Upstream guards calls to one external dependency
+type Upstream struct {
+	name   string
+	policy Policy
+
+	mu                  sync.Mutex
+	state               string
+	consecutiveFailures int
+	openedAt            time.Time
+	probing             bool
+	tokens              float64
+	nextSlot            time.Time // earliest start of the next paced attempt
+	pausedUntil         time.Time // Retry-After hold shared by every caller
+	stats               Stats
+	rng                 *rand.Rand
+	now                 func() time.Time
+	sleep               func(ctx context.Context, d time.Duration) error
+}
+
+// NewUpstream creates a standalone guard; most callers want the shared one from For
+func NewUpstream(name string, policy Policy) *Upstream {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	if policy.Retryable == nil {
+		policy.Retryable = Transient
+	}
+	return &Upstream{
+		name:   name,
+		policy: policy,
+		state:  StateClosed,
+		tokens: retryBudgetBurst,
+		stats:  Stats{Upstream: name},
+		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*Upstream{}
+)
+
+// For returns the shared guard for upstream, creating it from DefaultPolicy and the
+// RESILIENCE_<UPSTREAM>_* environment on first use
+func For(upstream string) *Upstream {
+	return ForPolicy(upstream, DefaultPolicy())
+}
+
+// ForPolicy is For with a different base policy; the first caller for a name decides it
+func ForPolicy(upstream string, base Policy) *Upstream {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if u, ok := registry[upstream]; ok {
+		return u
+	}
+	u := NewUpstream(upstream, PolicyFromEnv(upstream, base))
+	registry[upstream] = u
+	return u
+}
+
+// Snapshot returns the counters of every shared upstream, sorted by name
+func Snapshot() []Stats {
+	registryMu.Lock()
+	upstreams := make([]*Upstream, 0, len(registry))
+	for _, u := range registry {
+		upstreams = append(upstreams, u)
+	}
+	registryMu.Unlock()
+
+	stats := make([]Stats, 0, len(upstreams))
+	for _, u := range upstreams {
+		stats = append(stats, u.Stats())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Upstream < stats[j].Upstream })
+	return stats
+}
+
+// Name returns the upstream's name
+func (u *Upstream) Name() string {
+	return u.name
+}
+
+// Stats returns a copy of the upstream's counters
+func (u *Upstream) Stats() Stats {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.refreshStateLocked()
+	stats := u.stats
+	stats.State = u.state
+	return stats
+}
+
+// Do calls fn until it succeeds, fails permanently, runs out of attempts or budget, or the
+// breaker opens. The last error is returned; an open breaker returns ErrCircuitOpen.
+func (u *Upstream) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	u.mu.Lock()
+	u.stats.Calls++
+	if u.policy.RetryBudget > 0 {
+		u.tokens = math.Min(u.tokens+u.policy.RetryBudget, retryBudgetBurst)
+	}
+	u.mu.Unlock()
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if !u.allow() {
+			if err != nil {
+				return fmt.Errorf("%s: %w (last error: %v)", u.name, ErrCircuitOpen, err)
+			}
+			return fmt.Errorf("%s: %w", u.name, ErrCircuitOpen)
+		}
+
+		if waitErr := u.throttle(ctx); waitErr != nil {
+			if err != nil {
+				return err
+			}
+			return waitErr
+		}
+
+		err = fn(ctx)
+		transient := err != nil && ctx.Err() == nil && u.policy.Retryable(err)
+		u.record(transient)
+		if !transient {
+			return err
+		}
+		if attempt >= u.policy.MaxAttempts || !u.spendRetry() {
+			return err
+		}
+
+		delay := u.backoff(attempt, err)
+		log.Printf("[Resilience] 🔁 %s attempt %d/%d failed, retrying in %s: %v", u.name, attempt, u.policy.MaxAttempts, delay.Round(time.Millisecond), err)
+		if sleepErr := u.sleep(ctx, delay); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// allow reports whether a call may go through, letting a single probe through a half-open breaker
+func (u *Upstream) allow() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.refreshStateLocked()
+	switch u.state {
+	case StateOpen:
+		u.stats.Rejected++
+		return false
+	case StateHalfOpen:
+		if u.probing {
+			u.stats.Rejected++
+			return false
+		}
+		u.probing = true
+	}
+	return true
+}
+
+// refreshStateLocked moves an open breaker to half-open once its cooldown has passed
+func (u *Upstream) refreshStateLocked() {
+	if u.state == StateOpen && u.now().Sub(u.openedAt) >= u.policy.BreakerCooldown {
+		u.state = StateHalfOpen
+		u.probing = false
+	}
+}
+
+// record updates the breaker with one attempt's outcome. Permanent errors mean the upstream
+// answered, so they count as healthy.
+func (u *Upstream) record(transientFailure bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	wasProbe := u.state == StateHalfOpen
+	u.probing = false
+	if !transientFailure {
+		u.consecutiveFailures = 0
+		if wasProbe {
+			u.state = StateClosed
+			log.Printf("[Resilience] ✅ %s recovered, circuit closed", u.name)
+		}
+		return
+	}
+
+	u.stats.Failures++
+	u.consecutiveFailures++
+	if u.policy.BreakerThreshold <= 0 {
+		return
+	}
+	if wasProbe || (u.state == StateClosed && u.consecutiveFailures >= u.policy.BreakerThreshold) {
+		now := u.now()
+		u.state = StateOpen
+		u.openedAt = now
+		u.stats.Trips++
+		u.stats.LastTripAt = &now
+		log.Printf("[Resilience] ⚡ %s circuit opened after %d consecutive failures; cooling down for %s", u.name, u.consecutiveFailures, u.policy.BreakerCooldown)
+	}
+}
+
+// throttle waits for the upstream's next request slot. Attempts are spaced 1/RequestsPerMinute
+// apart across all callers, and none starts before a provider's Retry-After has passed.
+func (u *Upstream) throttle(ctx context.Context) error {
+	u.mu.Lock()
+	now := u.now()
+	start := now
+	if u.pausedUntil.After(start) {
+		start = u.pausedUntil
+	}
+	if u.policy.RequestsPerMinute > 0 {
+		if u.nextSlot.After(start) {
+			start = u.nextSlot
+		}
+		u.nextSlot = start.Add(time.Duration(float64(time.Minute) / u.policy.RequestsPerMinute))
+	}
+	wait := start.Sub(now)
+	if wait > 0 {
+		u.stats.Throttled++
+	}
+	u.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	return u.sleep(ctx, wait)
+}
+
+// spendRetry takes a token from the retry budget
+func (u *Upstream) spendRetry() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.policy.RetryBudget > 0 {
+		if u.tokens < 1 {
+			u.stats.RetriesDenied++
+			return false
+		}
+		u.tokens--
+	}
+	u.stats.Retries++
+	return true
+}
+
+// backoff is full-jitter exponential backoff, stretched to honor a server's Retry-After
+func (u *Upstream) backoff(attempt int, err error) time.Duration {
+	ceiling := u.policy.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || (u.policy.MaxDelay > 0 && ceiling > u.policy.MaxDelay) {
+		ceiling = u.policy.MaxDelay
+	}
+	u.mu.Lock()
+	delay := time.Duration(u.rng.Int63n(int64(ceiling) + 1))
+	u.mu.Unlock()
+
+	var hinted interface{ RetryAfter() time.Duration }
+	if errors.As(err, &hinted) {
+		if after := hinted.RetryAfter(); after > 0 {
+			// The provider is throttling the account, not just this call
+			u.mu.Lock()
+			if until := u.now().Add(after); until.After(u.pausedUntil) {
+				u.pausedUntil = until
+			}
+			u.mu.Unlock()
+		}
+		if after := hinted.RetryAfter(); after > delay {
+			delay = after
+			if u.policy.MaxDelay > 0 && delay > u.policy.MaxDelay {
+				delay = u.policy.MaxDelay
+			}
+		}
+	}
+	return delay
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
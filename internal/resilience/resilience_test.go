@@ -0,0 +1,127 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type statusError int
+
+func (e statusError) Error() string   { return "http error" }
+func (e statusError) HTTPStatus() int { return int(e) }
+
+func newTestUpstream(policy Policy) (*Upstream, *time.Time) {
+	u := NewUpstream("test", policy)
+	now := time.Unix(0, 0)
+	u.now = func() time.Time { return now }
+	u.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	return u, &now
+}
+
+// TestDoRetriesTransientFailures verifies 429s are retried and 400s are not
+func TestDoRetriesTransientFailures(t *testing.T) {
+	u, _ := newTestUpstream(Policy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+
+	calls := 0
+	err := u.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return statusError(429)
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success on the third attempt, got err=%v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = u.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return statusError(400)
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("client errors must not be retried, got %d calls", calls)
+	}
+	if stats := u.Stats(); stats.Retries != 2 || stats.Calls != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+// TestBreakerOpensAndRecovers verifies the breaker trips, rejects, and closes after a good probe
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	u, now := newTestUpstream(Policy{MaxAttempts: 1, BreakerThreshold: 2, BreakerCooldown: time.Minute})
+	failing := func(ctx context.Context) error { return statusError(503) }
+
+	u.Do(context.Background(), failing)
+	u.Do(context.Background(), failing)
+
+	called := false
+	err := u.Do(context.Background(), func(ctx context.Context) error { called = true; return nil })
+	if !errors.Is(err, ErrCircuitOpen) || called {
+		t.Fatalf("expected the open breaker to reject without calling, got %v", err)
+	}
+
+	*now = now.Add(time.Minute)
+	if err := u.Do(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("expected the half-open probe to pass, got %v", err)
+	}
+	if stats := u.Stats(); stats.State != StateClosed || stats.Trips != 1 || stats.Rejected != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+// TestRetryBudgetLimitsRetries verifies an exhausted budget stops retrying
+func TestRetryBudgetLimitsRetries(t *testing.T) {
+	u, _ := newTestUpstream(Policy{MaxAttempts: 100, RetryBudget: 0.1})
+
+	calls := 0
+	u.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return statusError(500)
+	})
+	// the original attempt plus one retry per budget token
+	if calls != retryBudgetBurst+1 {
+		t.Fatalf("expected %d attempts, got %d", retryBudgetBurst+1, calls)
+	}
+	if stats := u.Stats(); stats.RetriesDenied != 1 {
+		t.Fatalf("expected one denied retry, got %+v", stats)
+	}
+}
+
+// TestRequestsArePacedAndPausedByRetryAfter verifies attempts are spaced to the configured rate
+// and that a Retry-After hint holds back the next caller too
+func TestRequestsArePacedAndPausedByRetryAfter(t *testing.T) {
+	u, _ := newTestUpstream(Policy{MaxAttempts: 1, RequestsPerMinute: 60})
+	var waits []time.Duration
+	u.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	for i := 0; i < 3; i++ {
+		u.Do(context.Background(), func(ctx context.Context) error { return nil })
+	}
+	if len(waits) != 2 || waits[0] != time.Second || waits[1] != 2*time.Second {
+		t.Fatalf("expected the second and third calls to wait 1s and 2s, got %v", waits)
+	}
+
+	u, _ = newTestUpstream(Policy{MaxAttempts: 2, MaxDelay: time.Minute})
+	waits = nil
+	u.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	u.Do(context.Background(), func(ctx context.Context) error { return retryAfterError(5 * time.Second) })
+	u.Do(context.Background(), func(ctx context.Context) error { return nil })
+	if stats := u.Stats(); stats.Throttled < 2 {
+		t.Fatalf("expected the retry and the next call to be held by Retry-After, got %+v (waits %v)", stats, waits)
+	}
+}
+
+type retryAfterError time.Duration
+
+func (e retryAfterError) Error() string             { return "throttled" }
+func (e retryAfterError) HTTPStatus() int           { return 429 }
+func (e retryAfterError) RetryAfter() time.Duration { return time.Duration(e) }
@@ -0,0 +1,239 @@
+// Package retention moves the artifacts of inactive research sessions from the hot ledger
+// in Postgres to compressed archives in object storage, and back when they are needed.
+package retention
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"gohypo/internal/session"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// archiveBatch is how many sessions one pass archives at most
+const archiveBatch = 100
+
+// Archiver archives the artifacts of sessions inactive for longer than a threshold. Each
+// session becomes one gzip-compressed JSONL file in the blob store, one hypothesis result's
+// payload per line, indexed in Postgres; the hypothesis rows stay hot without their
+// payloads, so listings keep working and only opening a session needs the archive.
+type Archiver struct {
+	store ports.ArtifactArchiveStore
+	blobs session.BlobStore
+	after time.Duration
+	now   func() time.Time
+
+	mu sync.Mutex // serializes archiving and rehydration of the same session
+}
+
+// NewArchiver creates an archiver for sessions inactive for longer than after
+func NewArchiver(store ports.ArtifactArchiveStore, blobs session.BlobStore, after time.Duration) *Archiver {
+	return &Archiver{store: store, blobs: session.NewResilientBlobStore(blobs), after: after, now: time.Now}
+}
+
+// ArchiveReport summarizes one archiving pass
+type ArchiveReport struct {
+	Cutoff          time.Time `json:"cutoff"`
+	Sessions        int       `json:"sessions"`
+	Artifacts       int       `json:"artifacts"`
+	RawBytes        int64     `json:"raw_bytes"`
+	CompressedBytes int64     `json:"compressed_bytes"`
+	Failed          []string  `json:"failed,omitempty"` // sessions left hot by an error
+}
+
+// Run archives inactive sessions every interval until ctx is done
+func (a *Archiver) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := a.ArchiveInactive(ctx)
+		if err != nil {
+			log.Printf("[Retention] ⚠️ Archiving pass failed: %v", err)
+		} else if report.Sessions > 0 || len(report.Failed) > 0 {
+			log.Printf("[Retention] Archived %d artifacts of %d sessions (%d → %d bytes), %d failed",
+				report.Artifacts, report.Sessions, report.RawBytes, report.CompressedBytes, len(report.Failed))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ArchiveInactive archives the sessions with no activity within the threshold. A session
+// that fails is reported and left hot; the others are still archived.
+func (a *Archiver) ArchiveInactive(ctx context.Context) (*ArchiveReport, error) {
+	report := &ArchiveReport{Cutoff: a.now().Add(-a.after)}
+	candidates, err := a.store.ListArchivable(ctx, report.Cutoff, archiveBatch)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		archive, err := a.archiveSession(ctx, candidate)
+		if err != nil {
+			log.Printf("[Retention] ⚠️ Session %s left hot: %v", candidate.SessionID, err)
+			report.Failed = append(report.Failed, candidate.SessionID)
+			continue
+		}
+		if archive == nil {
+			continue
+		}
+		report.Sessions++
+		report.Artifacts += archive.ArtifactCount
+		report.RawBytes += archive.RawBytes
+		report.CompressedBytes += archive.CompressedBytes
+	}
+	return report, nil
+}
+
+// archiveSession writes a session's hot artifacts to one archive. Artifacts archived
+// earlier are rehydrated first, so the session's archive always holds all of them.
+func (a *Archiver) archiveSession(ctx context.Context, candidate models.ArchiveCandidate) (*models.ArtifactArchive, error) {
+	if _, err := a.EnsureHot(ctx, candidate.SessionID); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	previous, err := a.store.GetSessionArchive(ctx, candidate.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	artifacts, err := a.store.SessionArtifacts(ctx, candidate.SessionID)
+	if err != nil || len(artifacts) == 0 {
+		return nil, err
+	}
+	data, rawBytes, err := EncodeArchive(artifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &models.ArtifactArchive{
+		SessionID:       candidate.SessionID,
+		WorkspaceID:     candidate.WorkspaceID,
+		BlobKey:         archiveKey(candidate, a.now()),
+		ArtifactCount:   len(artifacts),
+		RawBytes:        rawBytes,
+		CompressedBytes: int64(len(data)),
+		ArchivedAt:      a.now(),
+	}
+	if err := a.blobs.StoreBlob(ctx, archive.BlobKey, data); err != nil {
+		return nil, fmt.Errorf("failed to upload archive: %w", err)
+	}
+	ids := make([]string, len(artifacts))
+	for i, artifact := range artifacts {
+		ids[i] = artifact.ID
+	}
+	if err := a.store.MarkArchived(ctx, archive, ids); err != nil {
+		a.deleteBlob(ctx, archive.BlobKey)
+		return nil, err
+	}
+	if previous != nil && previous.BlobKey != archive.BlobKey {
+		a.deleteBlob(ctx, previous.BlobKey)
+	}
+	return archive, nil
+}
+
+// EnsureHot rehydrates a session whose artifacts are archived and reports whether it did.
+// Sessions never archived, or already rehydrated, cost one index lookup.
+func (a *Archiver) EnsureHot(ctx context.Context, sessionID string) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	archive, err := a.store.GetSessionArchive(ctx, sessionID)
+	if err != nil || !archive.Cold() {
+		return false, err
+	}
+
+	reader, err := a.blobs.GetBlob(ctx, archive.BlobKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch archive of session %s: %w", sessionID, err)
+	}
+	defer reader.Close()
+	artifacts, err := DecodeArchive(reader)
+	if err != nil {
+		return false, fmt.Errorf("failed to read archive of session %s: %w", sessionID, err)
+	}
+	if err := a.store.Restore(ctx, archive, artifacts); err != nil {
+		return false, err
+	}
+	log.Printf("[Retention] Rehydrated %d artifacts of session %s", len(artifacts), sessionID)
+	return true, nil
+}
+
+// Usage reports a workspace's hot and archived artifact storage
+func (a *Archiver) Usage(ctx context.Context, workspaceID string) (*models.WorkspaceStorageUsage, error) {
+	return a.store.WorkspaceUsage(ctx, workspaceID)
+}
+
+func (a *Archiver) deleteBlob(ctx context.Context, key string) {
+	if err := a.blobs.DeleteBlob(ctx, key); err != nil {
+		log.Printf("[Retention] ⚠️ Failed to delete archive %s: %v", key, err)
+	}
+}
+
+// archiveKey places archives by workspace and session; the timestamp keeps a re-archived
+// session from overwriting the archive its index still points at
+func archiveKey(candidate models.ArchiveCandidate, at time.Time) string {
+	workspace := "unassigned"
+	if candidate.WorkspaceID != nil && *candidate.WorkspaceID != "" {
+		workspace = *candidate.WorkspaceID
+	}
+	return fmt.Sprintf("archives/%s/%s/%d.jsonl.gz", workspace, candidate.SessionID, at.Unix())
+}
+
+// EncodeArchive writes artifacts as gzip-compressed JSONL and returns it with the size of
+// the uncompressed JSONL
+func EncodeArchive(artifacts []models.ArchivedArtifact) ([]byte, int64, error) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	var rawBytes int64
+	for _, artifact := range artifacts {
+		line, err := json.Marshal(artifact)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to encode artifact %s: %w", artifact.ID, err)
+		}
+		line = append(line, '\n')
+		if _, err := gz.Write(line); err != nil {
+			return nil, 0, fmt.Errorf("failed to compress archive: %w", err)
+		}
+		rawBytes += int64(len(line))
+	}
+	if err := gz.Close(); err != nil {
+		return nil, 0, fmt.Errorf("failed to compress archive: %w", err)
+	}
+	return compressed.Bytes(), rawBytes, nil
+}
+
+// DecodeArchive reads an archive written by EncodeArchive
+func DecodeArchive(r io.Reader) ([]models.ArchivedArtifact, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var artifacts []models.ArchivedArtifact
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var artifact models.ArchivedArtifact
+		if err := json.Unmarshal(scanner.Bytes(), &artifact); err != nil {
+			return nil, fmt.Errorf("line %d: %w", len(artifacts)+1, err)
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, scanner.Err()
+}
@@ -0,0 +1,185 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"gohypo/internal/session"
+	"gohypo/models"
+)
+
+type memoryRow struct {
+	sessionID string
+	artifact  models.ArchivedArtifact
+	archiveID string
+}
+
+// memoryArchiveStore keeps hypothesis payloads and archives in memory; every session is
+// inactive since lastActivity
+type memoryArchiveStore struct {
+	mu           sync.Mutex
+	rows         map[string]*memoryRow
+	archives     map[string]*models.ArtifactArchive
+	lastActivity time.Time
+}
+
+func newMemoryArchiveStore(lastActivity time.Time) *memoryArchiveStore {
+	return &memoryArchiveStore{rows: map[string]*memoryRow{}, archives: map[string]*models.ArtifactArchive{}, lastActivity: lastActivity}
+}
+
+func (m *memoryArchiveStore) add(sessionID, id, refereeResults string) {
+	m.rows[id] = &memoryRow{sessionID: sessionID, artifact: models.ArchivedArtifact{ID: id, RefereeResults: json.RawMessage(refereeResults)}}
+}
+
+func (m *memoryArchiveStore) ListArchivable(ctx context.Context, before time.Time, limit int) ([]models.ArchiveCandidate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts := map[string]int{}
+	for _, row := range m.rows {
+		if row.archiveID == "" {
+			counts[row.sessionID]++
+		}
+	}
+	var candidates []models.ArchiveCandidate
+	for sessionID, count := range counts {
+		last := m.lastActivity
+		if archive := m.archives[sessionID]; archive != nil && archive.RehydratedAt != nil && archive.RehydratedAt.After(last) {
+			last = *archive.RehydratedAt
+		}
+		if last.Before(before) {
+			candidates = append(candidates, models.ArchiveCandidate{SessionID: sessionID, Artifacts: count, LastActivity: last})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].SessionID < candidates[j].SessionID })
+	return candidates, nil
+}
+
+func (m *memoryArchiveStore) SessionArtifacts(ctx context.Context, sessionID string) ([]models.ArchivedArtifact, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var artifacts []models.ArchivedArtifact
+	for _, row := range m.rows {
+		if row.sessionID == sessionID && row.archiveID == "" {
+			artifacts = append(artifacts, row.artifact)
+		}
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].ID < artifacts[j].ID })
+	return artifacts, nil
+}
+
+func (m *memoryArchiveStore) MarkArchived(ctx context.Context, archive *models.ArtifactArchive, artifactIDs []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	archive.ID = "archive-" + archive.SessionID
+	copied := *archive
+	m.archives[archive.SessionID] = &copied
+	for _, id := range artifactIDs {
+		m.rows[id].artifact = models.ArchivedArtifact{ID: id}
+		m.rows[id].archiveID = archive.ID
+	}
+	return nil
+}
+
+func (m *memoryArchiveStore) GetSessionArchive(ctx context.Context, sessionID string) (*models.ArtifactArchive, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if archive, ok := m.archives[sessionID]; ok {
+		copied := *archive
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *memoryArchiveStore) Restore(ctx context.Context, archive *models.ArtifactArchive, artifacts []models.ArchivedArtifact) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, artifact := range artifacts {
+		if row := m.rows[artifact.ID]; row != nil && row.archiveID == archive.ID {
+			row.artifact = artifact
+			row.archiveID = ""
+		}
+	}
+	now := m.lastActivity.Add(365 * 24 * time.Hour)
+	m.archives[archive.SessionID].RehydratedAt = &now
+	return nil
+}
+
+func (m *memoryArchiveStore) WorkspaceUsage(ctx context.Context, workspaceID string) (*models.WorkspaceStorageUsage, error) {
+	return &models.WorkspaceStorageUsage{WorkspaceID: workspaceID}, nil
+}
+
+// TestArchiveAndRehydrate verifies inactive sessions are archived with their payloads
+// cleared, and opening one restores the payloads from the archive
+func TestArchiveAndRehydrate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	store := newMemoryArchiveStore(now.Add(-100 * 24 * time.Hour))
+	store.add("s1", "h1", `{"permutation":{"passed":true}}`)
+	store.add("s1", "h2", `{"bootstrap":{"passed":false}}`)
+	store.add("s2", "h3", `{"permutation":{"passed":true}}`)
+
+	blobs, err := session.NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	archiver := NewArchiver(store, blobs, 90*24*time.Hour)
+	archiver.now = func() time.Time { return now }
+
+	report, err := archiver.ArchiveInactive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Sessions != 2 || report.Artifacts != 3 || len(report.Failed) != 0 || report.CompressedBytes == 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if store.rows["h1"].archiveID == "" || len(store.rows["h1"].artifact.RefereeResults) != 0 {
+		t.Fatalf("h1 was not archived: %+v", store.rows["h1"])
+	}
+
+	rehydrated, err := archiver.EnsureHot(ctx, "s1")
+	if err != nil || !rehydrated {
+		t.Fatalf("rehydrate = %v, %v", rehydrated, err)
+	}
+	if got := string(store.rows["h2"].artifact.RefereeResults); got != `{"bootstrap":{"passed":false}}` {
+		t.Errorf("h2 restored as %s", got)
+	}
+	if store.rows["h3"].archiveID == "" {
+		t.Error("s2 should stay archived")
+	}
+	if again, _ := archiver.EnsureHot(ctx, "s1"); again {
+		t.Error("a rehydrated session should not be rehydrated twice")
+	}
+
+	// A session opened again counts as active, so the next pass leaves it hot
+	report, err = archiver.ArchiveInactive(ctx)
+	if err != nil || report.Sessions != 0 {
+		t.Errorf("second pass archived %+v, %v", report, err)
+	}
+}
+
+// TestArchiveRoundTrip verifies archives decode to the artifacts they were encoded from
+func TestArchiveRoundTrip(t *testing.T) {
+	artifacts := []models.ArchivedArtifact{
+		{ID: "h1", RefereeResults: json.RawMessage(`{"a":1}`), DataTopology: json.RawMessage(`[1,2]`)},
+		{ID: "h2"},
+	}
+	data, raw, err := EncodeArchive(artifacts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw <= 0 {
+		t.Errorf("raw bytes = %d", raw)
+	}
+	decoded, err := DecodeArchive(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || string(decoded[0].RefereeResults) != `{"a":1}` || string(decoded[0].DataTopology) != `[1,2]` || decoded[1].ID != "h2" {
+		t.Errorf("decoded %+v", decoded)
+	}
+}
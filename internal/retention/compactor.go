@@ -0,0 +1,292 @@
+package retention
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gohypo/domain/core"
+	"gohypo/internal/session"
+	"gohypo/ports"
+)
+
+// Compactor enforces the ledger retention policy. Each pass moves the artifacts the policy
+// expires out of the ledger into one gzip-compressed JSONL archive per run, and leaves a
+// compacted_run tombstone in the run recording where they went with each artifact's
+// content hash and fingerprints, so lineage and replay checks still resolve after the
+// payloads are gone.
+type Compactor struct {
+	ledger    ports.LedgerCompactionPort
+	blobs     session.BlobStore
+	policy    LedgerPolicy
+	validated ports.ValidatedEvidenceSource // nil keeps no evidence beyond the policy
+	now       func() time.Time
+
+	mu   sync.Mutex // serializes passes
+	last *CompactionReport
+}
+
+// NewCompactor creates a compactor archiving expired ledger artifacts to blobs
+func NewCompactor(ledger ports.LedgerCompactionPort, blobs session.BlobStore, policy LedgerPolicy, validated ports.ValidatedEvidenceSource) *Compactor {
+	return &Compactor{ledger: ledger, blobs: session.NewResilientBlobStore(blobs), policy: policy, validated: validated, now: time.Now}
+}
+
+// CompactionReport summarizes one compaction pass
+type CompactionReport struct {
+	StartedAt       time.Time `json:"started_at"`
+	Runs            int       `json:"runs"`
+	Artifacts       int       `json:"artifacts"`
+	ProtectedRuns   int       `json:"protected_runs"`
+	RawBytes        int64     `json:"raw_bytes"`
+	CompressedBytes int64     `json:"compressed_bytes"`
+	Failed          []string  `json:"failed,omitempty"` // runs left uncompacted by an error
+}
+
+// CompactedArtifact is what a tombstone keeps of an archived artifact
+type CompactedArtifact struct {
+	ID           core.ID           `json:"id"`
+	Kind         core.ArtifactKind `json:"kind"`
+	CreatedAt    core.Timestamp    `json:"created_at"`
+	ContentHash  string            `json:"content_hash"`           // sha256 of the archived JSON line
+	Fingerprints map[string]string `json:"fingerprints,omitempty"` // the payload's *fingerprint fields
+}
+
+// CompactedRun is the payload of a compacted_run tombstone
+type CompactedRun struct {
+	RunID       core.RunID          `json:"run_id"`
+	BlobKey     string              `json:"blob_key"`
+	CompactedAt time.Time           `json:"compacted_at"`
+	Artifacts   []CompactedArtifact `json:"artifacts"`
+}
+
+// Policy returns the policy the compactor enforces
+func (c *Compactor) Policy() LedgerPolicy {
+	return c.policy
+}
+
+// LastReport returns the report of the latest pass, or nil before the first
+func (c *Compactor) LastReport() *CompactionReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+// Run compacts the ledger every interval until ctx is done
+func (c *Compactor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := c.Compact(ctx)
+		if err != nil {
+			log.Printf("[Retention] ⚠️ Ledger compaction failed: %v", err)
+		} else if report.Runs > 0 || len(report.Failed) > 0 {
+			log.Printf("[Retention] Compacted %d ledger artifacts of %d runs (%d → %d bytes), %d failed",
+				report.Artifacts, report.Runs, report.RawBytes, report.CompressedBytes, len(report.Failed))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Compact archives and drops the artifacts the policy expires. A run that fails is
+// reported and left as it was; the others are still compacted.
+func (c *Compactor) Compact(ctx context.Context) (*CompactionReport, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &CompactionReport{StartedAt: c.now()}
+	runIDs, err := c.ledger.ListRunIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledger runs: %w", err)
+	}
+	protected, err := c.protectedRuns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]LedgerRun, 0, len(runIDs))
+	for _, runID := range runIDs {
+		artifacts, err := c.ledger.GetArtifactsByRun(ctx, runID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ledger run %s: %w", runID, err)
+		}
+		runs = append(runs, LedgerRun{ID: runID, Artifacts: artifacts})
+		if protected[runID] {
+			report.ProtectedRuns++
+		}
+	}
+
+	expired := c.policy.Expired(runs, protected, report.StartedAt)
+	runIDs = runIDs[:0]
+	for runID := range expired {
+		runIDs = append(runIDs, runID)
+	}
+	sort.Slice(runIDs, func(i, j int) bool { return runIDs[i] < runIDs[j] })
+	for _, runID := range runIDs {
+		rawBytes, compressedBytes, err := c.compactRun(ctx, runID, expired[runID], report.StartedAt)
+		if err != nil {
+			log.Printf("[Retention] ⚠️ Ledger run %s left uncompacted: %v", runID, err)
+			report.Failed = append(report.Failed, string(runID))
+			continue
+		}
+		report.Runs++
+		report.Artifacts += len(expired[runID])
+		report.RawBytes += rawBytes
+		report.CompressedBytes += compressedBytes
+	}
+	c.last = report
+	return report, nil
+}
+
+// protectedRuns returns the runs holding validated hypotheses' evidence chains: each
+// validated session's run and its stats sweep's run
+func (c *Compactor) protectedRuns(ctx context.Context) (map[core.RunID]bool, error) {
+	protected := map[core.RunID]bool{}
+	if !c.policy.KeepValidatedEvidence || c.validated == nil {
+		return protected, nil
+	}
+	sessionIDs, err := c.validated.ValidatedSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list validated sessions: %w", err)
+	}
+	for _, sessionID := range sessionIDs {
+		protected[core.RunID(sessionID)] = true
+		protected[core.RunID("sweep-"+sessionID)] = true
+	}
+	return protected, nil
+}
+
+// compactRun archives a run's expired artifacts, records the tombstone and drops them
+func (c *Compactor) compactRun(ctx context.Context, runID core.RunID, artifacts []core.Artifact, at time.Time) (int64, int64, error) {
+	data, rawBytes, compacted, err := EncodeLedgerArchive(artifacts)
+	if err != nil {
+		return 0, 0, err
+	}
+	key := fmt.Sprintf("ledger/%s/%d.jsonl.gz", runID, at.Unix())
+	if err := c.blobs.StoreBlob(ctx, key, data); err != nil {
+		return 0, 0, fmt.Errorf("failed to upload ledger archive: %w", err)
+	}
+
+	tombstone := core.Artifact{
+		ID:   core.NewID(),
+		Kind: core.ArtifactCompactedRun,
+		Payload: CompactedRun{
+			RunID:       runID,
+			BlobKey:     key,
+			CompactedAt: at,
+			Artifacts:   compacted,
+		},
+		CreatedAt: core.NewTimestamp(at),
+	}
+	if err := c.ledger.StoreArtifact(ctx, string(runID), tombstone); err != nil {
+		c.deleteBlob(ctx, key)
+		return 0, 0, fmt.Errorf("failed to record compaction: %w", err)
+	}
+	ids := make([]core.ArtifactID, len(artifacts))
+	for i, artifact := range artifacts {
+		ids[i] = core.ArtifactID(artifact.ID)
+	}
+	if err := c.ledger.RemoveArtifacts(ctx, runID, ids); err != nil {
+		return 0, 0, fmt.Errorf("failed to drop compacted artifacts: %w", err)
+	}
+	return rawBytes, int64(len(data)), nil
+}
+
+func (c *Compactor) deleteBlob(ctx context.Context, key string) {
+	if err := c.blobs.DeleteBlob(ctx, key); err != nil {
+		log.Printf("[Retention] ⚠️ Failed to delete ledger archive %s: %v", key, err)
+	}
+}
+
+// EncodeLedgerArchive writes ledger artifacts as gzip-compressed JSONL and returns it with
+// the size of the uncompressed JSONL and what a tombstone keeps of each artifact
+func EncodeLedgerArchive(artifacts []core.Artifact) ([]byte, int64, []CompactedArtifact, error) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	var rawBytes int64
+	compacted := make([]CompactedArtifact, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		line, err := json.Marshal(artifact)
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to encode artifact %s: %w", artifact.ID, err)
+		}
+		sum := sha256.Sum256(line)
+		compacted = append(compacted, CompactedArtifact{
+			ID:           artifact.ID,
+			Kind:         artifact.Kind,
+			CreatedAt:    artifact.CreatedAt,
+			ContentHash:  hex.EncodeToString(sum[:]),
+			Fingerprints: payloadFingerprints(line),
+		})
+		line = append(line, '\n')
+		if _, err := gz.Write(line); err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to compress ledger archive: %w", err)
+		}
+		rawBytes += int64(len(line))
+	}
+	if err := gz.Close(); err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to compress ledger archive: %w", err)
+	}
+	return compressed.Bytes(), rawBytes, compacted, nil
+}
+
+// DecodeLedgerArchive reads an archive written by EncodeLedgerArchive. Payloads come back
+// as decoded JSON, not the types they were stored as.
+func DecodeLedgerArchive(r io.Reader) ([]core.Artifact, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var artifacts []core.Artifact
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var artifact core.Artifact
+		if err := json.Unmarshal(scanner.Bytes(), &artifact); err != nil {
+			return nil, fmt.Errorf("line %d: %w", len(artifacts)+1, err)
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, scanner.Err()
+}
+
+// payloadFingerprints collects the top-level payload fields named *fingerprint with string
+// values, such as bundle_fingerprint
+func payloadFingerprints(line []byte) map[string]string {
+	var artifact struct {
+		Payload map[string]json.RawMessage `json:"payload"`
+	}
+	if json.Unmarshal(line, &artifact) != nil {
+		return nil
+	}
+	var fingerprints map[string]string
+	for key, raw := range artifact.Payload {
+		var value string
+		if !strings.HasSuffix(strings.ToLower(key), "fingerprint") || json.Unmarshal(raw, &value) != nil || value == "" {
+			continue
+		}
+		if fingerprints == nil {
+			fingerprints = map[string]string{}
+		}
+		fingerprints[key] = value
+	}
+	return fingerprints
+}
@@ -0,0 +1,88 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gohypo/domain/core"
+	"gohypo/internal/session"
+	"gohypo/internal/testkit"
+)
+
+type staticValidated []string
+
+func (s staticValidated) ValidatedSessions(ctx context.Context) ([]string, error) {
+	return s, nil
+}
+
+// TestCompactLedger verifies expired artifacts move to an archive readable back, a
+// tombstone keeps their fingerprints, and validated sessions' evidence stays in the ledger
+func TestCompactLedger(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	ledger := testkit.NewInMemoryLedgerAdapter()
+	store := func(runID, id string, age time.Duration) {
+		err := ledger.StoreArtifact(ctx, runID, core.Artifact{
+			ID:        core.ID(id),
+			Kind:      core.ArtifactRelationship,
+			Payload:   map[string]interface{}{"bundle_fingerprint": "fp-" + id, "p_value": 0.01},
+			CreatedAt: core.NewTimestamp(now.Add(-age)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	store("sweep-s1", "old", 48*time.Hour)
+	store("sweep-s2", "validated", 48*time.Hour)
+	store("sweep-s3", "fresh", time.Hour)
+
+	blobs, err := session.NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := LedgerPolicy{Default: KindPolicy{TTL: 24 * time.Hour}, KeepValidatedEvidence: true}
+	compactor := NewCompactor(ledger, blobs, policy, staticValidated{"s2"})
+	compactor.now = func() time.Time { return now }
+
+	report, err := compactor.Compact(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Runs != 1 || report.Artifacts != 1 || report.ProtectedRuns != 1 || len(report.Failed) != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	for id, kept := range map[string]bool{"old": false, "validated": true, "fresh": true} {
+		if got, _ := ledger.GetArtifact(ctx, core.ArtifactID(id)); (got != nil) != kept {
+			t.Errorf("%s in ledger = %v, want %v", id, got != nil, kept)
+		}
+	}
+
+	remaining, err := ledger.GetArtifactsByRun(ctx, "sweep-s1")
+	if err != nil || len(remaining) != 1 || remaining[0].Kind != core.ArtifactCompactedRun {
+		t.Fatalf("sweep-s1 holds %+v, %v; want only the tombstone", remaining, err)
+	}
+	tombstone := remaining[0].Payload.(CompactedRun)
+	if len(tombstone.Artifacts) != 1 || tombstone.Artifacts[0].Fingerprints["bundle_fingerprint"] != "fp-old" || tombstone.Artifacts[0].ContentHash == "" {
+		t.Fatalf("tombstone %+v", tombstone)
+	}
+
+	reader, err := blobs.GetBlob(ctx, tombstone.BlobKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	archived, err := DecodeLedgerArchive(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archived) != 1 || archived[0].ID != "old" || archived[0].Payload.(map[string]interface{})["bundle_fingerprint"] != "fp-old" {
+		t.Errorf("archived %+v", archived)
+	}
+
+	// Tombstones never expire, so a second pass finds nothing to do
+	report, err = compactor.Compact(ctx)
+	if err != nil || report.Runs != 0 {
+		t.Errorf("second pass compacted %+v, %v", report, err)
+	}
+}
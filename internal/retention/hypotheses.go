@@ -0,0 +1,52 @@
+package retention
+
+import (
+	"context"
+	"log"
+
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+)
+
+// RehydratingHypothesisRepository rehydrates an archived session before returning its
+// hypotheses in full, so opening an old run reads like any other. Listings across sessions
+// return archived hypotheses without their payloads instead of rehydrating every session.
+type RehydratingHypothesisRepository struct {
+	ports.HypothesisRepository
+	archiver *Archiver
+}
+
+// NewRehydratingHypothesisRepository wraps repo so reads of archived sessions rehydrate them
+func NewRehydratingHypothesisRepository(repo ports.HypothesisRepository, archiver *Archiver) ports.HypothesisRepository {
+	return &RehydratingHypothesisRepository{HypothesisRepository: repo, archiver: archiver}
+}
+
+// GetHypothesis returns the hypothesis with its payload, rehydrating its session if needed
+func (r *RehydratingHypothesisRepository) GetHypothesis(ctx context.Context, userID uuid.UUID, hypothesisID string) (*models.HypothesisResult, error) {
+	result, err := r.HypothesisRepository.GetHypothesis(ctx, userID, hypothesisID)
+	if err != nil || result.SessionID == "" {
+		return result, err
+	}
+	if !r.rehydrate(ctx, result.SessionID) {
+		return result, nil
+	}
+	return r.HypothesisRepository.GetHypothesis(ctx, userID, hypothesisID)
+}
+
+// ListSessionHypotheses rehydrates the session if needed and returns its hypotheses
+func (r *RehydratingHypothesisRepository) ListSessionHypotheses(ctx context.Context, userID, sessionID uuid.UUID) ([]*models.HypothesisResult, error) {
+	r.rehydrate(ctx, sessionID.String())
+	return r.HypothesisRepository.ListSessionHypotheses(ctx, userID, sessionID)
+}
+
+// rehydrate reports whether the session was rehydrated; a failure is logged and the
+// hypotheses are served without their payloads rather than not at all
+func (r *RehydratingHypothesisRepository) rehydrate(ctx context.Context, sessionID string) bool {
+	rehydrated, err := r.archiver.EnsureHot(ctx, sessionID)
+	if err != nil {
+		log.Printf("[Retention] ⚠️ Serving session %s without archived payloads: %v", sessionID, err)
+	}
+	return rehydrated
+}
@@ -0,0 +1,208 @@
+package retention
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gohypo/domain/core"
+)
+
+// KindPolicy bounds how long one kind of ledger artifact stays in the ledger. Zero values
+// leave that bound off.
+type KindPolicy struct {
+	KeepRuns int           `json:"keep_runs,omitempty"` // runs whose artifacts of the kind are kept, newest first
+	TTL      time.Duration `json:"ttl,omitempty"`       // age after which an artifact of the kind expires
+}
+
+// Unbounded reports whether the policy never expires anything
+func (p KindPolicy) Unbounded() bool {
+	return p.KeepRuns <= 0 && p.TTL <= 0
+}
+
+// LedgerPolicy is the retention policy of the artifact ledger: a default for every kind,
+// overridden per kind, and whether the evidence chains of validated hypotheses are exempt
+type LedgerPolicy struct {
+	Default               KindPolicy                       `json:"default"`
+	Kinds                 map[core.ArtifactKind]KindPolicy `json:"kinds,omitempty"`
+	KeepValidatedEvidence bool                             `json:"keep_validated_evidence"`
+}
+
+// For returns the policy of an artifact kind
+func (p LedgerPolicy) For(kind core.ArtifactKind) KindPolicy {
+	if policy, ok := p.Kinds[kind]; ok {
+		return policy
+	}
+	return p.Default
+}
+
+// LedgerRun is one run's artifacts as the ledger holds them
+type LedgerRun struct {
+	ID        core.RunID
+	Artifacts []core.Artifact
+}
+
+// Expired returns the artifacts the policy expires at now, by run. For each kind, runs are
+// ranked by their newest artifact of that kind, so keeping N runs keeps that kind's N most
+// recent runs however many other kinds they hold. Protected runs and compaction tombstones
+// never expire.
+func (p LedgerPolicy) Expired(runs []LedgerRun, protected map[core.RunID]bool, now time.Time) map[core.RunID][]core.Artifact {
+	newest := map[core.ArtifactKind]map[core.RunID]time.Time{}
+	for _, r := range runs {
+		for _, artifact := range r.Artifacts {
+			if newest[artifact.Kind] == nil {
+				newest[artifact.Kind] = map[core.RunID]time.Time{}
+			}
+			if at := artifact.CreatedAt.Time(); at.After(newest[artifact.Kind][r.ID]) {
+				newest[artifact.Kind][r.ID] = at
+			}
+		}
+	}
+
+	// rank[kind][run] is the run's recency among the runs holding the kind, 0 for the newest
+	rank := map[core.ArtifactKind]map[core.RunID]int{}
+	for kind, byRun := range newest {
+		ids := make([]core.RunID, 0, len(byRun))
+		for id := range byRun {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			if !byRun[ids[i]].Equal(byRun[ids[j]]) {
+				return byRun[ids[i]].After(byRun[ids[j]])
+			}
+			return ids[i] > ids[j]
+		})
+		rank[kind] = make(map[core.RunID]int, len(ids))
+		for i, id := range ids {
+			rank[kind][id] = i
+		}
+	}
+
+	expired := map[core.RunID][]core.Artifact{}
+	for _, r := range runs {
+		if protected[r.ID] {
+			continue
+		}
+		for _, artifact := range r.Artifacts {
+			if artifact.Kind == core.ArtifactCompactedRun {
+				continue
+			}
+			policy := p.For(artifact.Kind)
+			if (policy.KeepRuns > 0 && rank[artifact.Kind][r.ID] >= policy.KeepRuns) ||
+				(policy.TTL > 0 && now.Sub(artifact.CreatedAt.Time()) > policy.TTL) {
+				expired[r.ID] = append(expired[r.ID], artifact)
+			}
+		}
+	}
+	return expired
+}
+
+// String renders the policy in the form ParseLedgerPolicy reads
+func (p LedgerPolicy) String() string {
+	parts := []string{}
+	if !p.Default.Unbounded() {
+		parts = append(parts, "*="+p.Default.String())
+	}
+	kinds := make([]string, 0, len(p.Kinds))
+	for kind := range p.Kinds {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		parts = append(parts, kind+"="+p.Kinds[core.ArtifactKind(kind)].String())
+	}
+	return strings.Join(parts, ";")
+}
+
+// String renders the kind policy in the form ParseLedgerPolicy reads
+func (p KindPolicy) String() string {
+	if p.Unbounded() {
+		return "keep:all"
+	}
+	parts := []string{}
+	if p.KeepRuns > 0 {
+		parts = append(parts, fmt.Sprintf("keep:%d", p.KeepRuns))
+	}
+	if p.TTL > 0 {
+		parts = append(parts, "ttl:"+p.TTL.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseLedgerPolicy reads a policy of ';'-separated kind=rules entries, where kind is an
+// artifact kind or '*' for the default and rules are ','-separated keep:N (the N most
+// recent runs), ttl:D (a Go duration, or whole days as 30d) or keep:all. For example:
+//
+//	*=keep:50;relationship=keep:20,ttl:720h;sweep_checkpoint=ttl:1d
+//
+// Evidence of validated hypotheses is kept; set KeepValidatedEvidence to change that.
+func ParseLedgerPolicy(spec string) (LedgerPolicy, error) {
+	policy := LedgerPolicy{Kinds: map[core.ArtifactKind]KindPolicy{}, KeepValidatedEvidence: true}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kind, rules, ok := strings.Cut(entry, "=")
+		kind = strings.TrimSpace(kind)
+		if !ok || kind == "" {
+			return LedgerPolicy{}, fmt.Errorf("retention entry %q: want kind=rules", entry)
+		}
+		kindPolicy, err := parseKindPolicy(rules)
+		if err != nil {
+			return LedgerPolicy{}, fmt.Errorf("retention entry %q: %w", entry, err)
+		}
+		if kind == "*" {
+			policy.Default = kindPolicy
+		} else {
+			policy.Kinds[core.ArtifactKind(kind)] = kindPolicy
+		}
+	}
+	return policy, nil
+}
+
+func parseKindPolicy(rules string) (KindPolicy, error) {
+	var policy KindPolicy
+	for _, rule := range strings.Split(rules, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(rule), ":")
+		if !ok {
+			return KindPolicy{}, fmt.Errorf("rule %q: want name:value", rule)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(name) {
+		case "keep":
+			if value == "all" {
+				policy.KeepRuns = 0
+				continue
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return KindPolicy{}, fmt.Errorf("keep %q: want a positive number of runs or all", value)
+			}
+			policy.KeepRuns = n
+		case "ttl":
+			ttl, err := parseTTL(value)
+			if err != nil || ttl <= 0 {
+				return KindPolicy{}, fmt.Errorf("ttl %q: want a positive duration such as 720h or 30d", value)
+			}
+			policy.TTL = ttl
+		default:
+			return KindPolicy{}, fmt.Errorf("unknown rule %q", name)
+		}
+	}
+	return policy, nil
+}
+
+// parseTTL reads a Go duration, or a whole number of days with a d suffix
+func parseTTL(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
@@ -0,0 +1,81 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"gohypo/domain/core"
+)
+
+// TestParseLedgerPolicy verifies policies parse per kind with a default and round-trip
+// through String, and malformed rules are rejected
+func TestParseLedgerPolicy(t *testing.T) {
+	policy, err := ParseLedgerPolicy("*=keep:50; relationship=keep:20,ttl:30d ;sweep_checkpoint=ttl:24h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.Default != (KindPolicy{KeepRuns: 50}) {
+		t.Errorf("default = %+v", policy.Default)
+	}
+	if got := policy.For("relationship"); got != (KindPolicy{KeepRuns: 20, TTL: 720 * time.Hour}) {
+		t.Errorf("relationship = %+v", got)
+	}
+	if got := policy.For("sweep_checkpoint"); got != (KindPolicy{TTL: 24 * time.Hour}) {
+		t.Errorf("sweep_checkpoint = %+v", got)
+	}
+	if got := policy.For("variable_profile"); got != policy.Default {
+		t.Errorf("unlisted kind = %+v, want the default", got)
+	}
+	if !policy.KeepValidatedEvidence {
+		t.Error("validated evidence should be kept by default")
+	}
+
+	again, err := ParseLedgerPolicy(policy.String())
+	if err != nil || again.String() != policy.String() {
+		t.Errorf("round trip %q -> %q, %v", policy.String(), again.String(), err)
+	}
+
+	for _, spec := range []string{"relationship", "relationship=keep:0", "relationship=ttl:soon", "relationship=forever:1"} {
+		if _, err := ParseLedgerPolicy(spec); err == nil {
+			t.Errorf("%q should not parse", spec)
+		}
+	}
+}
+
+// TestLedgerPolicyExpired verifies runs are ranked per kind, TTLs apply per artifact, and
+// protected runs and tombstones never expire
+func TestLedgerPolicyExpired(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	artifact := func(id string, kind core.ArtifactKind, age time.Duration) core.Artifact {
+		return core.Artifact{ID: core.ID(id), Kind: kind, CreatedAt: core.NewTimestamp(now.Add(-age))}
+	}
+	runs := []LedgerRun{
+		{ID: "r1", Artifacts: []core.Artifact{artifact("a1", core.ArtifactRelationship, 72*time.Hour), artifact("t1", core.ArtifactCompactedRun, 96*time.Hour)}},
+		{ID: "r2", Artifacts: []core.Artifact{artifact("a2", core.ArtifactRelationship, 48*time.Hour), artifact("c2", core.ArtifactSweepCheckpoint, 48*time.Hour)}},
+		{ID: "r3", Artifacts: []core.Artifact{artifact("a3", core.ArtifactRelationship, time.Hour), artifact("c3", core.ArtifactSweepCheckpoint, time.Hour)}},
+		{ID: "old", Artifacts: []core.Artifact{artifact("a0", core.ArtifactRelationship, 96*time.Hour)}},
+	}
+	policy := LedgerPolicy{
+		Kinds: map[core.ArtifactKind]KindPolicy{
+			core.ArtifactRelationship:    {KeepRuns: 2},
+			core.ArtifactSweepCheckpoint: {TTL: 24 * time.Hour},
+		},
+	}
+
+	expired := policy.Expired(runs, map[core.RunID]bool{"old": true}, now)
+	got := map[string]bool{}
+	for _, artifacts := range expired {
+		for _, a := range artifacts {
+			got[string(a.ID)] = true
+		}
+	}
+	want := map[string]bool{"a1": true, "c2": true}
+	if len(got) != len(want) {
+		t.Fatalf("expired %v, want %v", got, want)
+	}
+	for id := range want {
+		if !got[id] {
+			t.Errorf("%s should expire; expired %v", id, got)
+		}
+	}
+}
@@ -30,7 +30,7 @@ func NewTieredArtifactManager(
 
 	return &TieredArtifactManager{
 		hotStorage:  hotStorage,
-		coldStorage: coldStorage,
+		coldStorage: NewResilientBlobStore(coldStorage),
 		maxHotSize:  maxHotSize,
 	}
 }
@@ -41,6 +41,7 @@ func (tam *TieredArtifactManager) StoreArtifact(
 	artifact core.Artifact,
 	sessionID string,
 ) error {
+	artifact = core.StampSchemaVersion(artifact)
 
 	// Serialize the full artifact to check size
 	fullData, err := json.Marshal(artifact)
@@ -77,12 +78,13 @@ func (tam *TieredArtifactManager) splitArtifact(
 ) (*ports.ArtifactMetadata, *ports.ArtifactBlob) {
 
 	metadata := &ports.ArtifactMetadata{
-		ID:          artifact.ID,
-		Kind:        artifact.Kind,
-		Fingerprint: core.Hash(fmt.Sprintf("artifact_%s", artifact.ID.String())),
-		CreatedAt:   artifact.CreatedAt,
-		SessionID:   sessionID,
-		SizeBytes:   totalSize,
+		ID:            artifact.ID,
+		Kind:          artifact.Kind,
+		SchemaVersion: artifact.SchemaVersion,
+		Fingerprint:   core.Hash(fmt.Sprintf("artifact_%s", artifact.ID.String())),
+		CreatedAt:     artifact.CreatedAt,
+		SessionID:     sessionID,
+		SizeBytes:     totalSize,
 	}
 
 	blob := &ports.ArtifactBlob{
@@ -145,9 +147,10 @@ func (tam *TieredArtifactManager) RetrieveArtifact(
 	}
 
 	artifact := &core.Artifact{
-		ID:        metadata.ID,
-		Kind:      metadata.Kind,
-		CreatedAt: metadata.CreatedAt,
+		ID:            metadata.ID,
+		Kind:          metadata.Kind,
+		SchemaVersion: metadata.SchemaVersion,
+		CreatedAt:     metadata.CreatedAt,
 	}
 
 	if metadata.BlobKey == "" {
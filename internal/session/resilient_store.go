@@ -0,0 +1,109 @@
+package session
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"gohypo/internal/resilience"
+)
+
+// ResilientBlobStore retries transient blob store failures and trips a breaker shared by
+// every store of the same provider, so an S3 outage fails fast instead of stalling each
+// artifact write for the full backoff.
+type ResilientBlobStore struct {
+	inner    BlobStore
+	upstream *resilience.Upstream
+}
+
+// NewResilientBlobStore wraps store with the guard for its provider (upstream "blob-local"
+// or "blob-s3", configurable with RESILIENCE_BLOB_S3_* and friends). Local disk gets a
+// short backoff since its failures are rarely transient.
+func NewResilientBlobStore(store BlobStore) BlobStore {
+	if store == nil {
+		return nil
+	}
+	if _, ok := store.(*ResilientBlobStore); ok {
+		return store
+	}
+	policy := resilience.DefaultPolicy()
+	if store.Provider() == StorageLocal {
+		policy.MaxAttempts = 2
+		policy.BaseDelay = 50 * time.Millisecond
+	}
+	return &ResilientBlobStore{
+		inner:    store,
+		upstream: resilience.ForPolicy("blob-"+string(store.Provider()), policy),
+	}
+}
+
+// StoreBlob retries unless data is a reader, which the first attempt may have consumed
+func (r *ResilientBlobStore) StoreBlob(ctx context.Context, key string, data interface{}) error {
+	if _, ok := data.(io.Reader); ok {
+		return r.inner.StoreBlob(ctx, key, data)
+	}
+	return r.upstream.Do(ctx, func(ctx context.Context) error {
+		return r.inner.StoreBlob(ctx, key, data)
+	})
+}
+
+func (r *ResilientBlobStore) GetBlob(ctx context.Context, key string) (io.ReadCloser, error) {
+	var blob io.ReadCloser
+	err := r.upstream.Do(ctx, func(ctx context.Context) error {
+		var err error
+		blob, err = r.inner.GetBlob(ctx, key)
+		return err
+	})
+	return blob, err
+}
+
+func (r *ResilientBlobStore) DeleteBlob(ctx context.Context, key string) error {
+	return r.upstream.Do(ctx, func(ctx context.Context) error {
+		return r.inner.DeleteBlob(ctx, key)
+	})
+}
+
+func (r *ResilientBlobStore) BlobExists(ctx context.Context, key string) (bool, error) {
+	var exists bool
+	err := r.upstream.Do(ctx, func(ctx context.Context) error {
+		var err error
+		exists, err = r.inner.BlobExists(ctx, key)
+		return err
+	})
+	return exists, err
+}
+
+func (r *ResilientBlobStore) ListBlobs(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := r.upstream.Do(ctx, func(ctx context.Context) error {
+		var err error
+		keys, err = r.inner.ListBlobs(ctx, prefix)
+		return err
+	})
+	return keys, err
+}
+
+func (r *ResilientBlobStore) DeleteBlobs(ctx context.Context, keys []string) error {
+	return r.upstream.Do(ctx, func(ctx context.Context) error {
+		return r.inner.DeleteBlobs(ctx, keys)
+	})
+}
+
+func (r *ResilientBlobStore) GetBlobMetadata(ctx context.Context, key string) (*BlobMetadata, error) {
+	var metadata *BlobMetadata
+	err := r.upstream.Do(ctx, func(ctx context.Context) error {
+		var err error
+		metadata, err = r.inner.GetBlobMetadata(ctx, key)
+		return err
+	})
+	return metadata, err
+}
+
+// CleanupExpired is a long sweep that tolerates partial failure, so it is not retried
+func (r *ResilientBlobStore) CleanupExpired(ctx context.Context, olderThan time.Duration) error {
+	return r.inner.CleanupExpired(ctx, olderThan)
+}
+
+func (r *ResilientBlobStore) Provider() StorageProvider {
+	return r.inner.Provider()
+}
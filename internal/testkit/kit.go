@@ -24,10 +24,10 @@ import (
 
 // TestKit provides testing utilities and fixtures
 type TestKit struct {
-	ledger      *InMemoryLedgerAdapter // Shared ledger instance
-	excelConfig *excel.ExcelConfig     // Excel data source configuration
-	excelData   *excel.ExcelData       // Pre-loaded Excel data for fast access
-	excelLoaded bool                   // Whether Excel data has been loaded
+	ledger      ports.LedgerCompactionPort // Shared ledger instance
+	excelConfig *excel.ExcelConfig         // Excel data source configuration
+	excelData   *excel.ExcelData           // Pre-loaded Excel data for fast access
+	excelLoaded bool                       // Whether Excel data has been loaded
 }
 
 // NewTestKit creates a new test kit instance with synthetic data
@@ -184,6 +184,17 @@ func (t *TestKit) LedgerAdapter() ports.LedgerPort {
 	return t.ledger
 }
 
+// LedgerCompactionAdapter returns the shared ledger for retention to compact
+func (t *TestKit) LedgerCompactionAdapter() ports.LedgerCompactionPort {
+	return t.ledger
+}
+
+// UseLedger replaces the in-memory ledger with a persistent one. Call it before handing
+// out ledger adapters, which keep the ledger they were given.
+func (t *TestKit) UseLedger(ledger ports.LedgerCompactionPort) {
+	t.ledger = ledger
+}
+
 // ProfilerAdapter returns a profiler adapter
 func (t *TestKit) ProfilerAdapter() ports.ProfilerPort {
 	// Create coercer with default config
@@ -202,6 +213,7 @@ func (t *TestKit) ReadinessOrchestrator() (*resolution.DataReadinessOrchestrator
 		Coercer:     coercer.NewTypeCoercer(config.CoercionConfig),
 		Synthesizer: synthesizer.NewContractSynthesizer(config.SynthesisConfig),
 		Gate:        resolution.NewReadinessGate(config.GateConfig),
+		Seasonality: config.SeasonalityConfig,
 	}
 
 	return resolution.NewDataReadinessOrchestrator(deps)
@@ -479,7 +491,7 @@ func (s *InMemoryLedgerAdapter) StoreArtifact(ctx context.Context, runID string,
 	defer s.mu.Unlock()
 
 	artifactID := core.ArtifactID(artifact.ID)
-	s.artifacts[artifactID] = artifact
+	s.artifacts[artifactID] = core.StampSchemaVersion(artifact)
 
 	// Track artifacts by run
 	runIDTyped := core.RunID(runID)
@@ -566,6 +578,59 @@ func (s *InMemoryLedgerAdapter) GetArtifactsByKind(ctx context.Context, kind cor
 	return s.ListArtifacts(ctx, ports.ArtifactFilters{Kind: &kind, Limit: limit})
 }
 
+// ListRunIDs returns every run with artifacts in the ledger
+func (s *InMemoryLedgerAdapter) ListRunIDs(ctx context.Context) ([]core.RunID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	runIDs := make([]core.RunID, 0, len(s.runArtifacts))
+	for runID := range s.runArtifacts {
+		runIDs = append(runIDs, runID)
+	}
+	return runIDs, nil
+}
+
+// RemoveArtifacts drops artifacts from a run, and from the ledger once no run holds them
+func (s *InMemoryLedgerAdapter) RemoveArtifacts(ctx context.Context, runID core.RunID, artifactIDs []core.ArtifactID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	remove := make(map[core.ArtifactID]bool, len(artifactIDs))
+	for _, aid := range artifactIDs {
+		remove[aid] = true
+	}
+	kept := s.runArtifacts[runID][:0]
+	for _, aid := range s.runArtifacts[runID] {
+		if !remove[aid] {
+			kept = append(kept, aid)
+		}
+	}
+	if len(kept) == 0 {
+		delete(s.runArtifacts, runID)
+	} else {
+		s.runArtifacts[runID] = kept
+	}
+
+	for aid := range remove {
+		held := false
+		for _, ids := range s.runArtifacts {
+			for _, other := range ids {
+				if other == aid {
+					held = true
+					break
+				}
+			}
+			if held {
+				break
+			}
+		}
+		if !held {
+			delete(s.artifacts, aid)
+		}
+	}
+	return nil
+}
+
 func (s *InMemoryLedgerAdapter) GetRunManifest(ctx context.Context, runID core.RunID) (*run.RunManifestArtifact, error) {
 	// Return a fake run manifest
 	snapshotID := core.SnapshotID("test-snapshot")
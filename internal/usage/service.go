@@ -5,6 +5,7 @@ import (
 	"log"
 	"time"
 
+	"gohypo/internal/resilience"
 	"gohypo/models"
 	"gohypo/ports"
 
@@ -58,24 +59,21 @@ func (s *Service) RecordUsage(ctx context.Context, userID uuid.UUID, sessionID *
 	return nil
 }
 
-// persistWithRetry attempts to persist usage with exponential backoff
+// persistWithRetry persists usage through the usage-store guard, which backs off on
+// transient database errors and stops trying while the database is down
 func (s *Service) persistWithRetry(usage *models.LLMUsage) error {
-	const maxRetries = 3
-	const baseDelay = 100 * time.Millisecond
-
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		if err := s.repo.RecordUsage(context.Background(), usage); err == nil {
-			return nil // Success
-		}
-
-		if attempt < maxRetries-1 {
-			delay := time.Duration(attempt+1) * baseDelay
-			time.Sleep(delay)
-		}
-	}
+	return resilience.ForPolicy("usage-store", usageStorePolicy).Do(context.Background(), func(ctx context.Context) error {
+		return s.repo.RecordUsage(ctx, usage)
+	})
+}
 
-	// Final attempt
-	return s.repo.RecordUsage(context.Background(), usage)
+// usageStorePolicy keeps the previous budget of a few quick attempts
+var usageStorePolicy = resilience.Policy{
+	MaxAttempts:      4,
+	BaseDelay:        100 * time.Millisecond,
+	MaxDelay:         time.Second,
+	BreakerThreshold: 10,
+	BreakerCooldown:  30 * time.Second,
 }
 
 // GetUserUsageSummary returns aggregated usage for a user in a time period
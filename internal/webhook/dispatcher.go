@@ -0,0 +1,226 @@
+// Package webhook notifies workspaces' HTTP endpoints of run lifecycle events. Events are
+// stored as deliveries before anything is sent, then posted with an HMAC signature and
+// retried with exponential backoff until the endpoint accepts them or the attempts run out.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/google/uuid"
+)
+
+// Dispatcher defaults: pending deliveries are polled every DefaultPollInterval in batches
+// of DefaultBatchSize, and an endpoint has DefaultTimeout to answer
+const (
+	DefaultPollInterval = 5 * time.Second
+	DefaultBatchSize    = 20
+	DefaultTimeout      = 10 * time.Second
+)
+
+// maxErrorBody bounds how much of a failed response is kept in the delivery log
+const maxErrorBody = 512
+
+// Dispatcher queues webhook deliveries for events and sends them. Deliveries are claimed
+// from the store, so any number of server instances may run a dispatcher side by side.
+type Dispatcher struct {
+	store  ports.WebhookStore
+	client *http.Client
+	now    func() time.Time
+
+	// PollInterval, BatchSize and Timeout override the defaults when set
+	PollInterval time.Duration
+	BatchSize    int
+	Timeout      time.Duration
+}
+
+// NewDispatcher creates a dispatcher over a webhook store with the default settings
+func NewDispatcher(store ports.WebhookStore) *Dispatcher {
+	return &Dispatcher{
+		store:        store,
+		client:       &http.Client{},
+		now:          time.Now,
+		PollInterval: DefaultPollInterval,
+		BatchSize:    DefaultBatchSize,
+		Timeout:      DefaultTimeout,
+	}
+}
+
+// Publish queues a delivery of event with data to each of the workspace's active webhooks
+// subscribed to it. The deliveries are sent by the dispatch loop, so Publish never waits on
+// an endpoint.
+func (d *Dispatcher) Publish(ctx context.Context, workspaceID string, event models.WebhookEvent, data interface{}) error {
+	if workspaceID == "" {
+		return nil
+	}
+	hooks, err := d.store.ListWebhooks(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if !hook.Subscribes(event) {
+			continue
+		}
+		delivery := &models.WebhookDelivery{
+			WebhookID:     hook.ID,
+			WorkspaceID:   workspaceID,
+			Event:         event,
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: d.now(),
+		}
+		// The envelope carries the delivery ID, so it is assigned before the payload is built
+		delivery.ID = uuid.New().String()
+		payload, err := json.Marshal(models.WebhookEnvelope{
+			ID:          delivery.ID,
+			Event:       event,
+			WorkspaceID: workspaceID,
+			CreatedAt:   d.now().UTC(),
+			Data:        data,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to encode %s event: %w", event, err)
+		}
+		delivery.Payload = payload
+		if err := d.store.CreateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start sends due deliveries every poll interval until ctx is done
+func (d *Dispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil {
+			slog.ErrorContext(ctx, "Webhook dispatch failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue claims one batch of due deliveries and attempts each, returning how many were
+// attempted
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	// A claim outlasts the attempt, so a dispatcher that dies mid-attempt only delays the
+	// delivery until another one claims it again
+	deliveries, err := d.store.ClaimDueDeliveries(ctx, d.BatchSize, 2*d.Timeout)
+	if err != nil {
+		return 0, err
+	}
+	for _, delivery := range deliveries {
+		d.Attempt(ctx, delivery)
+	}
+	return len(deliveries), nil
+}
+
+// Attempt sends a delivery once and records the outcome: succeeded on a 2xx answer, pending
+// with a backed-off retry on any other, and failed once its attempts are used up or its
+// webhook is gone or paused
+func (d *Dispatcher) Attempt(ctx context.Context, delivery *models.WebhookDelivery) {
+	delivery.Attempts++
+	hook, err := d.store.GetWebhook(ctx, delivery.WorkspaceID, delivery.WebhookID)
+	switch {
+	case err != nil:
+		// A deleted webhook takes its deliveries with it, so this is usually the store failing
+		d.record(ctx, delivery, nil, fmt.Errorf("webhook unavailable: %w", err), apperrors.CodeOf(err) != apperrors.CodeNotFound)
+		return
+	case !hook.Active:
+		d.record(ctx, delivery, nil, fmt.Errorf("webhook is paused"), false)
+		return
+	}
+
+	status, err := d.send(ctx, hook, delivery)
+	d.record(ctx, delivery, status, err, true)
+	if err != nil {
+		slog.WarnContext(ctx, "Webhook delivery failed", "delivery", delivery.ID, "webhook", hook.ID,
+			"event", delivery.Event, "attempt", delivery.Attempts, "error", err)
+	}
+}
+
+// send posts a delivery's payload to the webhook, returning the response status when there
+// was a response and an error unless it was 2xx
+func (d *Dispatcher) send(ctx context.Context, hook *models.Webhook, delivery *models.WebhookDelivery) (*int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, err
+	}
+	sentAt := d.now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GoHypo-Webhooks/1.0")
+	req.Header.Set(models.WebhookEventHeader, string(delivery.Event))
+	req.Header.Set(models.WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(models.WebhookTimestampHeader, fmt.Sprintf("%d", sentAt.Unix()))
+	req.Header.Set(models.WebhookSignatureHeader, models.SignWebhook(hook.Secret, sentAt, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	status := resp.StatusCode
+	if status < 200 || status > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &status, fmt.Errorf("endpoint answered %d: %s", status, bytes.TrimSpace(body))
+	}
+	return &status, nil
+}
+
+// record stores the outcome of an attempt; retryable failures are queued again after the
+// delivery's backoff while attempts remain
+func (d *Dispatcher) record(ctx context.Context, delivery *models.WebhookDelivery, status *int, err error, retryable bool) {
+	delivery.ResponseStatus = status
+	now := d.now()
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.LastError = nil
+		delivery.DeliveredAt = &now
+	case retryable && delivery.CanRetry():
+		msg := err.Error()
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.LastError = &msg
+		delivery.NextAttemptAt = now.Add(delivery.RetryDelay())
+	default:
+		msg := err.Error()
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = &msg
+	}
+	// The outcome is recorded even when the dispatcher is stopping, so the attempt counts
+	if err := d.store.UpdateDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		slog.ErrorContext(ctx, "Failed to record webhook delivery", "delivery", delivery.ID, "error", err)
+	}
+}
+
+// Redeliver queues one of a workspace's deliveries to be sent again right away with a fresh
+// set of attempts, whatever its status
+func (d *Dispatcher) Redeliver(ctx context.Context, workspaceID, id string) (*models.WebhookDelivery, error) {
+	delivery, err := d.store.GetDelivery(ctx, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = d.now()
+	if err := d.store.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
@@ -0,0 +1,195 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+// memoryStore is an in-memory WebhookStore
+type memoryStore struct {
+	ports.WebhookStore
+	mu         sync.Mutex
+	hooks      []*models.Webhook
+	deliveries []*models.WebhookDelivery
+}
+
+func (m *memoryStore) ListWebhooks(_ context.Context, workspaceID string) ([]*models.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var hooks []*models.Webhook
+	for _, hook := range m.hooks {
+		if hook.WorkspaceID == workspaceID {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks, nil
+}
+
+func (m *memoryStore) GetWebhook(_ context.Context, workspaceID, id string) (*models.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, hook := range m.hooks {
+		if hook.ID == id && hook.WorkspaceID == workspaceID {
+			return hook, nil
+		}
+	}
+	return nil, apperrors.NotFound("Webhook")
+}
+
+func (m *memoryStore) CreateDelivery(_ context.Context, delivery *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+
+func (m *memoryStore) ClaimDueDeliveries(_ context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []*models.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if len(due) < limit && delivery.Status == models.WebhookDeliveryPending && !delivery.NextAttemptAt.After(time.Now()) {
+			delivery.NextAttemptAt = time.Now().Add(lease)
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (m *memoryStore) UpdateDelivery(context.Context, *models.WebhookDelivery) error {
+	return nil
+}
+
+func newTestHook(t *testing.T, workspaceID, url string, events ...models.WebhookEvent) *models.Webhook {
+	t.Helper()
+	hook, err := models.NewWebhook(workspaceID, url, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook.ID = workspaceID + "-" + string(events[0])
+	return hook
+}
+
+// TestDispatcherDeliversSignedEvents verifies an event reaches only the subscribed webhooks,
+// signed with their secret
+func TestDispatcherDeliversSignedEvents(t *testing.T) {
+	var mu sync.Mutex
+	var received []*http.Request
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, r)
+		bodies = append(bodies, body)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	subscribed := newTestHook(t, "ws1", server.URL, models.WebhookSweepCompleted)
+	other := newTestHook(t, "ws1", server.URL, models.WebhookRunFailed)
+	foreign := newTestHook(t, "ws2", server.URL, models.WebhookSweepCompleted)
+	store := &memoryStore{hooks: []*models.Webhook{subscribed, other, foreign}}
+	dispatcher := NewDispatcher(store)
+	ctx := context.Background()
+
+	if err := dispatcher.Publish(ctx, "ws1", models.WebhookSweepCompleted, map[string]int{"relationships": 6}); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.deliveries) != 1 {
+		t.Fatalf("expected one delivery, got %d", len(store.deliveries))
+	}
+	if n, err := dispatcher.DeliverDue(ctx); err != nil || n != 1 {
+		t.Fatalf("expected one attempt, got %d (%v)", n, err)
+	}
+
+	delivery := store.deliveries[0]
+	if delivery.Status != models.WebhookDeliverySucceeded || delivery.Attempts != 1 || delivery.DeliveredAt == nil {
+		t.Fatalf("expected a succeeded delivery, got %+v", delivery)
+	}
+	if len(received) != 1 {
+		t.Fatalf("expected one request, got %d", len(received))
+	}
+	req := received[0]
+	if req.Header.Get(models.WebhookEventHeader) != "sweep_completed" || req.Header.Get(models.WebhookDeliveryHeader) != delivery.ID {
+		t.Fatalf("unexpected headers %v", req.Header)
+	}
+	if err := models.VerifyWebhook(subscribed.Secret, req.Header.Get(models.WebhookSignatureHeader),
+		req.Header.Get(models.WebhookTimestampHeader), bodies[0], time.Minute, time.Now()); err != nil {
+		t.Fatalf("signature did not verify: %v", err)
+	}
+	var envelope struct {
+		ID   string         `json:"id"`
+		Data map[string]int `json:"data"`
+	}
+	if err := json.Unmarshal(bodies[0], &envelope); err != nil || envelope.ID != delivery.ID || envelope.Data["relationships"] != 6 {
+		t.Fatalf("unexpected body %s", bodies[0])
+	}
+}
+
+// TestDispatcherRetriesWithBackoff verifies failed attempts are retried later and the
+// delivery fails once its attempts are used up
+func TestDispatcherRetriesWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "maintenance", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	store := &memoryStore{hooks: []*models.Webhook{newTestHook(t, "ws1", server.URL, models.WebhookRunFailed)}}
+	dispatcher := NewDispatcher(store)
+	ctx := context.Background()
+	if err := dispatcher.Publish(ctx, "ws1", models.WebhookRunFailed, nil); err != nil {
+		t.Fatal(err)
+	}
+	delivery := store.deliveries[0]
+
+	dispatcher.Attempt(ctx, delivery)
+	if delivery.Status != models.WebhookDeliveryPending || delivery.ResponseStatus == nil || *delivery.ResponseStatus != http.StatusServiceUnavailable {
+		t.Fatalf("expected a pending retry after a 503, got %+v", delivery)
+	}
+	if wait := time.Until(delivery.NextAttemptAt); wait < 25*time.Second || wait > models.WebhookRetryBaseDelay {
+		t.Fatalf("expected the first retry in about %v, got %v", models.WebhookRetryBaseDelay, wait)
+	}
+	if delivery.LastError == nil || *delivery.LastError != "endpoint answered 503: maintenance" {
+		t.Fatalf("unexpected error %v", delivery.LastError)
+	}
+
+	for delivery.Attempts < models.WebhookMaxAttempts {
+		dispatcher.Attempt(ctx, delivery)
+	}
+	if delivery.Status != models.WebhookDeliveryFailed {
+		t.Fatalf("expected the delivery to fail after %d attempts, got %s", models.WebhookMaxAttempts, delivery.Status)
+	}
+}
+
+// TestDispatcherSkipsPausedWebhook verifies a paused webhook's pending delivery fails
+// without a request
+func TestDispatcherSkipsPausedWebhook(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { requests++ }))
+	defer server.Close()
+
+	hook := newTestHook(t, "ws1", server.URL, models.WebhookDatasetReady)
+	store := &memoryStore{hooks: []*models.Webhook{hook}}
+	dispatcher := NewDispatcher(store)
+	ctx := context.Background()
+	if err := dispatcher.Publish(ctx, "ws1", models.WebhookDatasetReady, nil); err != nil {
+		t.Fatal(err)
+	}
+	hook.Active = false
+	if _, err := dispatcher.DeliverDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if store.deliveries[0].Status != models.WebhookDeliveryFailed || requests != 0 {
+		t.Fatalf("expected a failed delivery and no request, got %s after %d requests", store.deliveries[0].Status, requests)
+	}
+}
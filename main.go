@@ -4,31 +4,40 @@ import (
 	"bytes"
 	"context"
 	"embed"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"path/filepath"
 	"time"
 
+	"gohypo/adapters/chat"
 	"gohypo/adapters/excel"
 	"gohypo/adapters/llm"
 	"gohypo/adapters/postgres"
+	"gohypo/adapters/sqlite"
 	"gohypo/ai"
 	"gohypo/app"
 	"gohypo/domain/core"
 	domainDataset "gohypo/domain/dataset"
+	"gohypo/internal/access"
 	"gohypo/internal/analysis/brief"
 	"gohypo/internal/config"
 	"gohypo/internal/container"
 	"gohypo/internal/dataset"
+	"gohypo/internal/demo"
 	"gohypo/internal/errors"
+	"gohypo/internal/logging"
 	"gohypo/internal/migration"
+	"gohypo/internal/notify"
 	"gohypo/internal/research"
 	"gohypo/internal/testkit"
 	"gohypo/internal/validation"
+	"gohypo/internal/webhook"
 	"gohypo/models"
 	"gohypo/ports"
 	"gohypo/ui"
@@ -51,39 +60,21 @@ func (m *memoryFileReader) Close() error {
 //go:embed ui/templates/** ui/static/*
 var embeddedFiles embed.FS
 
-// resetDatabase drops all tables and recreates the database schema
-func resetDatabase(db *sqlx.DB) error {
-	log.Println("🔄 Resetting database - dropping all tables...")
-
-	// Drop tables in reverse dependency order
-	dropTables := []string{
-		"workspace_dataset_relations",
-		"datasets",
-		"workspaces",
-		"hypothesis_results",
-		"research_prompts",
-		"research_sessions",
-		"users",
-	}
-
-	for _, table := range dropTables {
-		_, err := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE", table))
-		if err != nil {
-			log.Printf("Warning: failed to drop table %s: %v", table, err)
-		}
-	}
-
-	log.Println("✅ Database reset complete")
-	return nil
-}
-
-// initDatabase initializes the PostgreSQL database connection
+// initDatabase initializes the database connection: PostgreSQL, or a SQLite file when
+// DATABASE_URL is a sqlite:// URL
 func initDatabase(appConfig *config.Config) (*sqlx.DB, error) {
 	if appConfig.Database.URL == "" {
 		return nil, errors.ConfigInvalid("DATABASE_URL is required")
 	}
 
-	db, err := sqlx.Connect("postgres", appConfig.Database.URL)
+	ctx := context.Background()
+	var db *sqlx.DB
+	var err error
+	if appConfig.Database.SQLite() {
+		db, err = sqlite.Open(ctx, appConfig.Database.URL)
+	} else {
+		db, err = sqlx.Connect("postgres", appConfig.Database.URL)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to connect to database")
 	}
@@ -93,24 +84,34 @@ func initDatabase(appConfig *config.Config) (*sqlx.DB, error) {
 		return nil, errors.Wrap(err, "failed to ping database")
 	}
 
-	// Reset database on each bootup (development mode)
-	if err := resetDatabase(db); err != nil {
-		return nil, errors.Wrap(err, "database reset failed")
-	}
-
-	// Run migrations
-	migrator := migration.NewRunner()
-	if err := migrator.Run(context.Background(), db); err != nil {
-		return nil, errors.Wrap(err, "database migration failed")
+	migrator := migration.ForDriver(db.DriverName())
+	switch {
+	case appConfig.Database.ResetOnStart:
+		// Config validation only allows this with APP_ENV=development
+		log.Println("🔄 DB_RESET_ON_START is set - dropping and recreating the schema...")
+		if err := migrator.Reset(ctx, db); err != nil {
+			return nil, errors.Wrap(err, "database reset failed")
+		}
+	case appConfig.Database.AutoMigrate:
+		if err := migrator.Up(ctx, db); err != nil {
+			return nil, errors.Wrap(err, "database migration failed (run 'gohypo-cli migrate status' to inspect)")
+		}
+	default:
+		pending, err := migrator.Pending(ctx, db)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to check database migrations")
+		}
+		if len(pending) > 0 {
+			return nil, errors.ConfigInvalid(fmt.Sprintf("%d database migration(s) pending and DB_AUTO_MIGRATE is off; run 'gohypo-cli migrate up'", len(pending)))
+		}
 	}
 
 	return db, nil
 }
 
 func main() {
-	// #region agent log
-	log.Printf(`{"sessionId":"debug-session","runId":"initial","hypothesisId":"H2","location":"main.go:57","message":"Application starting","data":{},"timestamp":%d}`, time.Now().UnixMilli())
-	// #endregion
+	noLLMCache := flag.Bool("no-llm-cache", false, "bypass the LLM response cache for this run")
+	flag.Parse()
 
 	// Load environment variables from .env file
 	if err := godotenv.Load(); err != nil {
@@ -120,23 +121,29 @@ func main() {
 	// Load application configuration
 	appConfig, err := config.Load()
 	if err != nil {
-		// #region agent log
-		log.Printf(`{"sessionId":"debug-session","runId":"initial","hypothesisId":"H2","location":"main.go:66","message":"Configuration loading failed","data":{"error":"%s"},"timestamp":%d}`, err.Error(), time.Now().UnixMilli())
-		// #endregion
-		log.Fatalf("Failed to load configuration: %v", err)
+		log.Fatalf("Failed to load configuration: %v (run 'gohypo-cli doctor' to diagnose)", err)
 	}
 
-	// #region agent log
-	log.Printf(`{"sessionId":"debug-session","runId":"initial","hypothesisId":"H2","location":"main.go:70","message":"Configuration loaded successfully","data":{},"timestamp":%d}`, time.Now().UnixMilli())
-	// #endregion
+	// Structured logging from here on; log package lines go through the same handler
+	if err := logging.Setup(appConfig.Logging.Level, appConfig.Logging.Format); err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+	slog.Info("Application starting", "log_level", appConfig.Logging.Level, "log_format", appConfig.Logging.Format)
+
+	// Source credentials may only reference the environment variables the operator named
+	models.AllowSecretEnv(appConfig.Access.SecretEnvNames)
 
 	// Initialize database
 	db, err := initDatabase(appConfig)
 	if err != nil {
-		log.Fatal("Failed to initialize database:", err)
+		log.Fatalf("Failed to initialize database: %v (run 'gohypo-cli doctor' to diagnose)", err)
 	}
 	defer db.Close()
 
+	// Run summaries, run logs, webhooks, chat notifications, exemplars and the job queue keep
+	// their data in PostgreSQL; a SQLite deployment runs without them
+	postgresStores := !sqlite.IsDB(db)
+
 	// Create dependency injection container
 	appContainer, err := container.New(appConfig)
 	if err != nil {
@@ -149,8 +156,8 @@ func main() {
 		log.Fatalf("Failed to initialize container: %v", err)
 	}
 
-	// Create dataset repository (needed for research worker)
-	datasetRepo := postgres.NewDatasetRepository(db)
+	// Dataset repository (needed for research worker); schema-routed in schema-per-workspace mode
+	datasetRepo := appContainer.DatasetRepo
 
 	// Ensure default workspace exists
 	if err := appContainer.EnsureDefaultWorkspace(context.Background()); err != nil {
@@ -179,6 +186,10 @@ func main() {
 			log.Fatalf("Failed to initialize test kit with synthetic data: %v", err)
 		}
 	}
+	if sqlite.IsDB(db) {
+		// Keep the ledger in the database file, so artifacts survive restarts
+		kit.UseLedger(sqlite.NewLedger(db))
+	}
 
 	// Setup AI services (keeping existing pattern for now)
 	aiConfig := &models.AIConfig{
@@ -188,11 +199,34 @@ func main() {
 		MaxTokens:     appConfig.AI.MaxTokens,
 		Temperature:   appConfig.AI.Temperature,
 		PromptsDir:    appConfig.AI.PromptsDir,
+
+		GeneratorMode:    appConfig.AI.GeneratorMode,
+		AnthropicKey:     appConfig.AI.AnthropicKey,
+		AnthropicModel:   appConfig.AI.AnthropicModel,
+		AnthropicBaseURL: appConfig.AI.AnthropicBaseURL,
+		OllamaBaseURL:    appConfig.AI.OllamaBaseURL,
+		OllamaModel:      appConfig.AI.OllamaModel,
+		OllamaAPI:        appConfig.AI.OllamaAPI,
+
+		ColumnEmbeddings: appConfig.AI.ColumnEmbeddings,
+		EmbeddingModel:   appConfig.AI.EmbeddingModel,
+
+		FixtureMode: appConfig.AI.FixtureMode,
+		FixtureDir:  appConfig.AI.FixtureDir,
+	}
+
+	// Dataset encryption at rest (optional, keys from DATASET_ENCRYPTION_KEYS)
+	datasetKeys, err := dataset.KeyProviderFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load dataset encryption keys: %v", err)
+	}
+	if datasetKeys != nil {
+		log.Println("Dataset encryption at rest enabled")
 	}
 
 	// Auto-load CSV files from data directory if enabled
 	if appConfig.Data.AutoLoadCSVs {
-		if err := autoLoadCSVs(context.Background(), db, aiConfig, appContainer); err != nil {
+		if err := autoLoadCSVs(context.Background(), db, aiConfig, appContainer, datasetKeys); err != nil {
 			log.Printf("Warning: Failed to auto-load CSV files: %v", err)
 			// Don't exit - continue with application startup
 		}
@@ -202,23 +236,58 @@ func main() {
 
 	// Create hypothesis analyzer if AI is available
 	var hypothesisAnalyzer *ai.HypothesisAnalysisAgent
-	if aiConfig.OpenAIKey != "" && aiConfig.PromptsDir != "" {
+	if aiConfig.LLMConfigured() && aiConfig.PromptsDir != "" {
 		// TODO: Create proper LLM client here
 		// For now, we'll create a placeholder
 		hypothesisAnalyzer = nil // Will be set when LLM client is available
 	}
 
 	var greenfieldService *app.GreenfieldService
-	if aiConfig.OpenAIKey != "" && aiConfig.PromptsDir != "" {
-		greenfieldService = setupGreenfieldServices(aiConfig, kit.LedgerAdapter(), hypothesisAnalyzer)
+	if aiConfig.LLMConfigured() && aiConfig.PromptsDir != "" {
+		llmCache, err := newLLMResponseCache(&appConfig.AI, db, *noLLMCache)
+		if err != nil {
+			log.Fatalf("Failed to initialize LLM response cache: %v", err)
+		}
+		var exemplars ports.ExemplarStore
+		if postgresStores {
+			exemplars = postgres.NewExemplarRepository(db)
+		}
+		greenfieldService = setupGreenfieldServices(aiConfig, kit.LedgerAdapter(), hypothesisAnalyzer, llmCache, exemplars)
 		log.Println("Greenfield research service initialized")
 	}
 
+	// Load column-level access policies (optional)
+	var columnEnforcer *access.Enforcer
+	if appConfig.Access.ColumnPolicyFile != "" {
+		columnEnforcer, err = access.LoadEnforcer(appConfig.Access.ColumnPolicyFile)
+		if err != nil {
+			log.Fatalf("Failed to load column access policy: %v", err)
+		}
+	}
+
 	// Initialize research worker using container repositories
 	var worker *research.ResearchWorker
 	rngPort := kit.RNGAdapter()
 	stageRunner := app.NewStageRunner(kit.LedgerAdapter(), rngPort)
 	statsSweepService := app.NewStatsSweepService(stageRunner, kit.LedgerAdapter(), rngPort)
+	var runSummaries ports.RunSummaryStore
+	var runLogs ports.RunLogStore
+	var webhooks *webhook.Dispatcher
+	var notifications *notify.Service
+	if postgresStores {
+		runSummaries = postgres.NewRunSummaryRepository(db)
+
+		// Store run log lines as they are logged, so they outlive the in-memory window
+		runLogs = postgres.NewRunLogRepository(db)
+		logging.Runs.Persist(context.Background(), runLogs)
+
+		// Queue run lifecycle events for workspace webhooks and send them in the background
+		webhooks = webhook.NewDispatcher(postgres.NewWebhookRepository(db))
+		go webhooks.Start(context.Background())
+
+		// Announce validated hypotheses in workspaces' Slack and Teams channels
+		notifications = notify.NewService(postgres.NewNotificationChannelRepository(db), chat.Notifiers(nil), appConfig.Server.PublicURL)
+	}
 
 	if greenfieldService != nil {
 		// Create advanced validation orchestrator
@@ -266,10 +335,28 @@ func main() {
 			validationOrchestrator,
 			datasetRepo, // Dataset repository for accessing uploaded files
 		)
+		if columnEnforcer != nil {
+			worker.SetColumnEnforcer(columnEnforcer)
+		}
+		workerStorageConfig := dataset.DefaultStorageConfig()
+		workerStorageConfig.KeyProvider = datasetKeys
+		worker.SetDatasetFiles(dataset.NewLocalFileStorage(workerStorageConfig))
+		worker.SetWorkspaceRepository(appContainer.WorkspaceRepo)
+		if postgresStores {
+			worker.SetRefereeRunStore(postgres.NewRefereeRunRepository(db))
+			worker.SetRunSummaryStore(runSummaries)
+			worker.SetEventPublisher(webhooks)
+			worker.SetHypothesisNotifier(notifications)
+		}
 		worker.StartWorkerPool(2)
 		log.Println("Research worker pool initialized")
 	}
 
+	// Seed the labeled demo workspace and run research over it once, in the background
+	if appConfig.Data.SeedDemo {
+		go seedDemoWorkspace(context.Background(), db, aiConfig, appContainer, datasetKeys, worker)
+	}
+
 	// Initialize statistical engine
 	statisticalEngine := brief.NewStatisticalEngine()
 
@@ -280,6 +367,61 @@ func main() {
 		log.Fatalf("Failed to initialize server: %v", err)
 	}
 
+	if columnEnforcer != nil {
+		server.SetColumnEnforcer(columnEnforcer)
+		log.Println("Column-level access policies enabled")
+	}
+	if postgresStores {
+		server.SetRunSummaryStore(runSummaries)
+		server.SetRunLogStore(runLogs)
+		server.SetWebhookDispatcher(webhooks)
+		server.SetNotifier(notifications)
+		server.SetJobQueue(postgres.NewJobQueueRepository(db), postgres.NewLeaseRepository(db), research.JobRunnerOptions{
+			Workers: appConfig.Jobs.Workers,
+			Lease:   appConfig.Jobs.Lease,
+		})
+	}
+	if len(appConfig.Access.AdminAPIKeys) > 0 {
+		server.SetAdminAPIKeys(appConfig.Access.AdminAPIKeys)
+		log.Printf("Admin API requires an API key (%d configured)", len(appConfig.Access.AdminAPIKeys))
+	} else {
+		log.Println("⚠️  ADMIN_API_KEYS is not set - the admin API is open to anyone who can reach the server")
+	}
+	server.SetBranding(models.Branding{
+		Name:         appConfig.Branding.Name,
+		LogoURL:      appConfig.Branding.LogoURL,
+		PrimaryColor: appConfig.Branding.PrimaryColor,
+		AccentColor:  appConfig.Branding.AccentColor,
+		FooterText:   appConfig.Branding.FooterText,
+	})
+
+	// Validation pool sizing; the hook lets operators add capacity outside the process
+	var scaleHooks []research.ScaleHook
+	if appConfig.Autoscale.HookURL != "" {
+		scaleHooks = append(scaleHooks, research.NewHTTPScaleHook(appConfig.Autoscale.HookURL))
+	}
+	server.SetAutoscaling(research.AutoscalePolicy{
+		MinWorkers:      appConfig.Autoscale.MinWorkers,
+		MaxWorkers:      appConfig.Autoscale.MaxWorkers,
+		TargetPerWorker: appConfig.Autoscale.TargetPerWorker,
+		ScaleDownDelay:  appConfig.Autoscale.ScaleDownDelay,
+		Interval:        appConfig.Autoscale.Interval,
+	}, scaleHooks...)
+
+	// Move inactive sessions' artifacts to the cold archive
+	if appContainer.Archiver != nil {
+		server.SetArchiver(appContainer.Archiver)
+		go appContainer.Archiver.Run(context.Background(), appConfig.Retention.Interval)
+		log.Printf("Artifact archiving enabled: sessions inactive for %s move to %s", appConfig.Retention.ArchiveAfter, appConfig.Retention.ArchiveDir)
+	}
+
+	// Compact ledger artifacts expired by the retention policy into the cold archive
+	if appContainer.Compactor != nil {
+		server.SetCompactor(appContainer.Compactor)
+		go appContainer.Compactor.Run(context.Background(), appConfig.Retention.CompactionInterval)
+		log.Printf("Ledger compaction enabled: policy %s, archived to %s", appContainer.Compactor.Policy(), appConfig.Retention.ArchiveDir)
+	}
+
 	// Add research routes using container components
 	if worker != nil {
 		server.AddResearchRoutes(appContainer.SessionManager, appContainer.ResearchStorage, worker, appContainer.SSEHub, appContainer, appContainer.HypothesisRepo)
@@ -303,17 +445,40 @@ func main() {
 }
 
 // setupGreenfieldServices creates and configures the greenfield research service
-func setupGreenfieldServices(config *models.AIConfig, ledgerPort ports.LedgerPort, hypothesisAnalyzer *ai.HypothesisAnalysisAgent) *app.GreenfieldService {
-	greenfieldAdapter := llm.NewGreenfieldAdapter(config)
+func setupGreenfieldServices(config *models.AIConfig, ledgerPort ports.LedgerPort, hypothesisAnalyzer *ai.HypothesisAnalysisAgent, llmCache ports.LLMResponseCache, exemplars ports.ExemplarStore) *app.GreenfieldService {
+	greenfieldAdapter := llm.NewCachedGreenfieldAdapter(config, llmCache)
+	greenfieldAdapter.SetExemplarStore(exemplars)
 	return app.NewGreenfieldService(greenfieldAdapter, ledgerPort, hypothesisAnalyzer)
 }
 
+// newLLMResponseCache selects the LLM_CACHE backend; nil means every prompt goes to the provider
+func newLLMResponseCache(aiConfig *config.AIConfig, db *sqlx.DB, disabled bool) (ports.LLMResponseCache, error) {
+	if disabled {
+		log.Println("ℹ️  LLM response cache bypassed (--no-llm-cache)")
+		return nil, nil
+	}
+
+	switch aiConfig.CacheBackend {
+	case "off":
+		return nil, nil
+	case "disk":
+		log.Printf("LLM response cache: disk (%s)", aiConfig.CacheDir)
+		return ai.NewDiskLLMCache(aiConfig.CacheDir)
+	case "postgres":
+		log.Println("LLM response cache: postgres")
+		return postgres.NewLLMResponseCache(db), nil
+	default:
+		log.Println("LLM response cache: memory")
+		return ai.NewMemoryLLMCache(), nil
+	}
+}
+
 // createLLMClient creates an LLM client for validation purposes
 // This is a simplified implementation - in production, this would use the full LLM adapter
 func createLLMClient(config *models.AIConfig) ports.LLMClient {
 	// Placeholder implementation - returns nil if no API key
 	// In production, this would create a proper LLM client
-	if config.OpenAIKey == "" {
+	if !config.LLMConfigured() {
 		return nil
 	}
 
@@ -323,7 +488,7 @@ func createLLMClient(config *models.AIConfig) ports.LLMClient {
 }
 
 // autoLoadCSVs automatically loads CSV files from the data directory into datasets
-func autoLoadCSVs(ctx context.Context, db *sqlx.DB, aiConfig *models.AIConfig, appContainer *container.Container) error {
+func autoLoadCSVs(ctx context.Context, db *sqlx.DB, aiConfig *models.AIConfig, appContainer *container.Container, keyProvider dataset.KeyProvider) error {
 	log.Println("🔄 Starting automatic CSV loading from data/ directory...")
 
 	// Check if data directory exists
@@ -368,28 +533,7 @@ func autoLoadCSVs(ctx context.Context, db *sqlx.DB, aiConfig *models.AIConfig, a
 		log.Printf("✅ Created default workspace: %s", defaultWorkspace.ID)
 	}
 
-	// Create dataset processor (similar to how it's done in UI server)
-	storageConfig := dataset.DefaultStorageConfig()
-
-	// Create file storage
-	fileStorage := dataset.NewLocalFileStorage(storageConfig)
-
-	// Create forensic scout for data analysis
-	forensicScout := ai.NewForensicScout(aiConfig)
-
-	// Create dataset repository
-	datasetRepo := postgres.NewDatasetRepository(db)
-
-	// Create dataset processor
-	datasetProcessor := dataset.NewProcessorWithConfig(
-		forensicScout,
-		datasetRepo,
-		workspaceRepo,
-		fileStorage,
-		nil, // No SSE hub needed for auto-loading
-		db,
-		storageConfig,
-	)
+	datasetProcessor := newBackgroundProcessor(db, aiConfig, appContainer, keyProvider)
 
 	// Process each CSV file
 	for _, filePath := range files {
@@ -458,3 +602,36 @@ func autoLoadCSVs(ctx context.Context, db *sqlx.DB, aiConfig *models.AIConfig, a
 	log.Printf("🎉 Auto-loading complete! Processed %d CSV file(s)", len(files))
 	return nil
 }
+
+// newBackgroundProcessor creates a dataset processor for datasets loaded at startup, the way
+// the UI server creates its own, without progress events
+func newBackgroundProcessor(db *sqlx.DB, aiConfig *models.AIConfig, appContainer *container.Container, keyProvider dataset.KeyProvider) *dataset.Processor {
+	storageConfig := dataset.DefaultStorageConfig()
+	storageConfig.KeyProvider = keyProvider
+
+	return dataset.NewProcessorWithConfig(
+		ai.NewForensicScout(aiConfig),
+		appContainer.DatasetRepo, // schema-routed in schema-per-workspace mode
+		appContainer.WorkspaceRepo,
+		dataset.NewLocalFileStorage(storageConfig),
+		nil, // No SSE hub needed for startup loading
+		db,
+		storageConfig,
+	)
+}
+
+// seedDemoWorkspace seeds the labeled demo workspace with the sample dataset and, when a
+// research worker is available, runs research over it once
+func seedDemoWorkspace(ctx context.Context, db *sqlx.DB, aiConfig *models.AIConfig, appContainer *container.Container, keyProvider dataset.KeyProvider, worker *research.ResearchWorker) {
+	seeder := demo.NewSeeder(
+		appContainer.WorkspaceRepo,
+		appContainer.DatasetRepo,
+		newBackgroundProcessor(db, aiConfig, appContainer, keyProvider),
+		appContainer.SessionManager,
+		worker,
+		core.ID("550e8400-e29b-41d4-a716-446655440000"), // Default user for single-user mode
+	)
+	if _, err := seeder.Seed(ctx); err != nil {
+		log.Printf("Warning: Failed to seed demo workspace: %v", err)
+	}
+}
@@ -5,6 +5,21 @@ import (
 	"strconv"
 )
 
+// Generator modes select the LLM provider used for hypothesis generation (GENERATOR_MODE)
+const (
+	GeneratorModeOpenAI    = "openai"
+	GeneratorModeAnthropic = "anthropic"
+	GeneratorModeOllama    = "ollama"
+)
+
+// Column embedding modes select how dataset columns are embedded for relationship
+// discovery (COLUMN_EMBEDDINGS)
+const (
+	ColumnEmbeddingsLocal    = "local"
+	ColumnEmbeddingsProvider = "provider"
+	ColumnEmbeddingsOff      = "off"
+)
+
 // AIConfig holds AI service configuration for integration with dunlap/ai package
 type AIConfig struct {
 	OpenAIKey     string
@@ -13,6 +28,48 @@ type AIConfig struct {
 	MaxTokens     int
 	Temperature   float64
 	PromptsDir    string // Directory for external prompt files
+
+	// Provider selection; empty means openai
+	GeneratorMode string
+
+	// Anthropic messages API
+	AnthropicKey     string
+	AnthropicModel   string
+	AnthropicBaseURL string
+
+	// Local Ollama or llama.cpp server
+	OllamaBaseURL string
+	OllamaModel   string
+	OllamaAPI     string // "ollama" for the native /api/chat, "openai" for /v1/chat/completions (llama.cpp)
+
+	// Column embeddings for relationship discovery: "local" (default), "provider" or "off"
+	ColumnEmbeddings string
+	EmbeddingModel   string
+
+	// Record-and-replay of every LLM call: "record", "replay", or empty/"off"
+	FixtureMode string
+	FixtureDir  string
+}
+
+// Mode returns the generator mode, defaulting to openai
+func (c *AIConfig) Mode() string {
+	if c.GeneratorMode == "" {
+		return GeneratorModeOpenAI
+	}
+	return c.GeneratorMode
+}
+
+// LLMConfigured reports whether the selected provider has what it needs to make real calls.
+// When false, callers fall back to the heuristic mock client.
+func (c *AIConfig) LLMConfigured() bool {
+	switch c.Mode() {
+	case GeneratorModeAnthropic:
+		return c.AnthropicKey != ""
+	case GeneratorModeOllama:
+		return c.OllamaBaseURL != ""
+	default:
+		return c.OpenAIKey != ""
+	}
 }
 
 // DefaultAIConfig returns sensible defaults for AI configuration
@@ -24,6 +81,23 @@ func DefaultAIConfig() *AIConfig {
 		MaxTokens:     2000, // default
 		Temperature:   0.1,  // default
 		PromptsDir:    "./prompts",
+
+		GeneratorMode:    os.Getenv("GENERATOR_MODE"),
+		AnthropicKey:     os.Getenv("ANTHROPIC_API_KEY"),
+		AnthropicModel:   os.Getenv("ANTHROPIC_MODEL"),
+		AnthropicBaseURL: os.Getenv("ANTHROPIC_BASE_URL"),
+		OllamaBaseURL:    os.Getenv("OLLAMA_BASE_URL"),
+		OllamaModel:      os.Getenv("OLLAMA_MODEL"),
+		OllamaAPI:        os.Getenv("OLLAMA_API"),
+
+		ColumnEmbeddings: os.Getenv("COLUMN_EMBEDDINGS"),
+		EmbeddingModel:   os.Getenv("EMBEDDING_MODEL"),
+
+		FixtureMode: os.Getenv("LLM_FIXTURES"),
+		FixtureDir:  os.Getenv("LLM_FIXTURES_DIR"),
+	}
+	if config.FixtureDir == "" {
+		config.FixtureDir = "./testdata/llm_fixtures"
 	}
 
 	// Parse MaxTokens from environment
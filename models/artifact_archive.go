@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ArtifactArchive indexes one research session's artifacts moved from the hot ledger to a
+// compressed JSONL file in object storage. The session's hypothesis rows stay in Postgres
+// with their heavy payload columns cleared; opening the session restores them.
+type ArtifactArchive struct {
+	ID              string     `json:"id" db:"id"`
+	SessionID       string     `json:"session_id" db:"session_id"`
+	WorkspaceID     *string    `json:"workspace_id,omitempty" db:"workspace_id"`
+	BlobKey         string     `json:"blob_key" db:"blob_key"`
+	ArtifactCount   int        `json:"artifact_count" db:"artifact_count"`
+	RawBytes        int64      `json:"raw_bytes" db:"raw_bytes"`
+	CompressedBytes int64      `json:"compressed_bytes" db:"compressed_bytes"`
+	ArchivedAt      time.Time  `json:"archived_at" db:"archived_at"`
+	RehydratedAt    *time.Time `json:"rehydrated_at,omitempty" db:"rehydrated_at"`
+}
+
+// Cold reports whether the session's artifacts are only in the archive
+func (a *ArtifactArchive) Cold() bool {
+	return a != nil && a.RehydratedAt == nil
+}
+
+// ArchivedArtifact is one line of an archive: a hypothesis result's payload columns,
+// kept as the JSON they were stored as so rehydration restores them byte for byte
+type ArchivedArtifact struct {
+	ID                string          `json:"id"`
+	RefereeResults    json.RawMessage `json:"referee_results,omitempty"`
+	TriGateResult     json.RawMessage `json:"tri_gate_result,omitempty"`
+	ExecutionMetadata json.RawMessage `json:"execution_metadata,omitempty"`
+	DataTopology      json.RawMessage `json:"data_topology,omitempty"`
+}
+
+// Size returns the bytes of payload the artifact holds
+func (a ArchivedArtifact) Size() int64 {
+	return int64(len(a.RefereeResults) + len(a.TriGateResult) + len(a.ExecutionMetadata) + len(a.DataTopology))
+}
+
+// ArchiveCandidate is a session with hot artifacts and no activity since the archive cutoff
+type ArchiveCandidate struct {
+	SessionID    string    `json:"session_id" db:"session_id"`
+	WorkspaceID  *string   `json:"workspace_id,omitempty" db:"workspace_id"`
+	Artifacts    int       `json:"artifacts" db:"artifacts"`
+	LastActivity time.Time `json:"last_activity" db:"last_activity"`
+}
+
+// WorkspaceStorageUsage reports how much of a workspace's artifact storage is in the hot
+// ledger and how much is archived
+type WorkspaceStorageUsage struct {
+	WorkspaceID       string `json:"workspace_id"`
+	HotArtifacts      int    `json:"hot_artifacts" db:"hot_artifacts"`
+	HotBytes          int64  `json:"hot_bytes" db:"hot_bytes"`
+	ArchivedSessions  int    `json:"archived_sessions" db:"archived_sessions"`
+	ArchivedArtifacts int    `json:"archived_artifacts" db:"archived_artifacts"`
+	ArchivedRawBytes  int64  `json:"archived_raw_bytes" db:"archived_raw_bytes"`
+	ArchivedBytes     int64  `json:"archived_bytes" db:"archived_bytes"` // compressed, as stored
+}
+
+// TotalBytes returns the bytes stored across both tiers
+func (u WorkspaceStorageUsage) TotalBytes() int64 {
+	return u.HotBytes + u.ArchivedBytes
+}
+
+// CompressionRatio returns how many times smaller the archives are than their payloads,
+// 0 when nothing is archived
+func (u WorkspaceStorageUsage) CompressionRatio() float64 {
+	if u.ArchivedBytes == 0 {
+		return 0
+	}
+	return float64(u.ArchivedRawBytes) / float64(u.ArchivedBytes)
+}
@@ -0,0 +1,43 @@
+package models
+
+// Branding is the per-deployment look of the UI and of generated reports, so a deployment can
+// carry a client's or consultancy's name, logo, colors and footer instead of the product's
+type Branding struct {
+	Name         string `json:"name"`
+	LogoURL      string `json:"logo_url,omitempty"`
+	PrimaryColor string `json:"primary_color"`
+	AccentColor  string `json:"accent_color"`
+	FooterText   string `json:"footer_text,omitempty"`
+}
+
+// DefaultBranding is the stock look
+func DefaultBranding() Branding {
+	return Branding{
+		Name:         "GoHypo",
+		PrimaryColor: "#2563eb",
+		AccentColor:  "#1f2937",
+	}
+}
+
+// WithDefaults fills the unset fields from the stock look
+func (b Branding) WithDefaults() Branding {
+	defaults := DefaultBranding()
+	if b.Name == "" {
+		b.Name = defaults.Name
+	}
+	if b.PrimaryColor == "" {
+		b.PrimaryColor = defaults.PrimaryColor
+	}
+	if b.AccentColor == "" {
+		b.AccentColor = defaults.AccentColor
+	}
+	return b
+}
+
+// Title is a page title under the deployment's name
+func (b Branding) Title(page string) string {
+	if page == "" {
+		return b.Name
+	}
+	return b.Name + " - " + page
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// Kinds of evidence that can kill a hypothesis
+const (
+	CounterexampleSegmentReversal = "segment_reversal" // the effect reverses within a segment
+	CounterexampleTimeWindow      = "time_window"      // the effect disappears over a stretch of time
+	CounterexampleConfounder      = "confounder"       // another variable absorbs the effect
+)
+
+// CounterexamplesKey is the execution metadata key a failed hypothesis' counterexamples are
+// stored under
+const CounterexamplesKey = "counterexamples"
+
+// Counterexample is a specific piece of evidence against a hypothesis, for the "why it failed"
+// panel. Effect and Baseline are correlations of cause and effect: within the segment or
+// window, or controlling for the confounder, against all rows.
+type Counterexample struct {
+	Kind     string  `json:"kind"`
+	Source   string  `json:"source"` // the gate or check that produced it
+	Summary  string  `json:"summary"`
+	Variable string  `json:"variable,omitempty"` // segmenting column, time index or confounder
+	Segment  string  `json:"segment,omitempty"`
+	From     float64 `json:"from,omitempty"` // window bounds, in time index values or row positions
+	To       float64 `json:"to,omitempty"`
+	Effect   float64 `json:"effect"`
+	Baseline float64 `json:"baseline"`
+	Rows     int     `json:"rows,omitempty"`
+}
+
+// Strength is how far the counterexample moves the effect from its baseline
+func (c Counterexample) Strength() float64 {
+	return math.Abs(c.Baseline - c.Effect)
+}
+
+// CounterexamplesFromReferees extracts the counterexamples failed gates attached to their
+// evidence blocks
+func CounterexamplesFromReferees(results []RefereeResult) []Counterexample {
+	var found []Counterexample
+	for _, result := range results {
+		if result.Passed {
+			continue
+		}
+		for _, block := range result.EvidenceBlocks {
+			if c, ok := decodeCounterexample(block); ok {
+				if c.Source == "" {
+					c.Source = result.GateName
+				}
+				found = append(found, c)
+			}
+		}
+	}
+	return found
+}
+
+// RankCounterexamples orders counterexamples strongest first, keeping the strongest of each
+// kind and variable
+func RankCounterexamples(found []Counterexample) []Counterexample {
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Strength() > found[j].Strength() })
+	seen := make(map[string]bool)
+	ranked := make([]Counterexample, 0, len(found))
+	for _, c := range found {
+		key := c.Kind + "\x00" + c.Variable
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		ranked = append(ranked, c)
+	}
+	return ranked
+}
+
+// CounterexamplesOf returns the counterexamples stored with a failed hypothesis, or those its
+// gates reported for hypotheses validated before counterexamples were stored
+func CounterexamplesOf(h *HypothesisResult) []Counterexample {
+	if h.Passed {
+		return []Counterexample{}
+	}
+	if stored, ok := h.ExecutionMetadata[CounterexamplesKey]; ok {
+		if list, ok := stored.([]Counterexample); ok {
+			return list
+		}
+		var list []Counterexample
+		if data, err := json.Marshal(stored); err == nil && json.Unmarshal(data, &list) == nil {
+			return list
+		}
+	}
+	return RankCounterexamples(CounterexamplesFromReferees(h.RefereeResults))
+}
+
+// decodeCounterexample reads a counterexample from an evidence block, which is a
+// Counterexample when fresh and a generic map when decoded from the database
+func decodeCounterexample(block interface{}) (Counterexample, bool) {
+	switch c := block.(type) {
+	case Counterexample:
+		return c, true
+	case *Counterexample:
+		return *c, c != nil
+	case map[string]interface{}:
+		kind, _ := c["kind"].(string)
+		if kind != CounterexampleSegmentReversal && kind != CounterexampleTimeWindow && kind != CounterexampleConfounder {
+			return Counterexample{}, false
+		}
+		var decoded Counterexample
+		data, err := json.Marshal(c)
+		if err != nil || json.Unmarshal(data, &decoded) != nil {
+			return Counterexample{}, false
+		}
+		return decoded, true
+	}
+	return Counterexample{}, false
+}
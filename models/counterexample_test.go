@@ -0,0 +1,32 @@
+package models
+
+import "testing"
+
+// TestCounterexamplesOf verifies counterexamples are read from failed gates' evidence, also
+// after a round trip through the database, strongest first and only for failed hypotheses
+func TestCounterexamplesOf(t *testing.T) {
+	h := &HypothesisResult{
+		RefereeResults: []RefereeResult{
+			{GateName: "Chow_Stability_Test", EvidenceBlocks: []interface{}{
+				map[string]interface{}{"kind": CounterexampleTimeWindow, "variable": "row order", "effect": 0.05, "baseline": 0.4},
+				map[string]interface{}{"effect": 0.1},
+			}},
+			{GateName: "Synthetic_Intervention", EvidenceBlocks: []interface{}{
+				Counterexample{Kind: CounterexampleConfounder, Source: "propensity_matching", Variable: "season", Effect: -0.2, Baseline: 0.4},
+			}},
+			{GateName: "Permutation_Shredder", Passed: true, EvidenceBlocks: []interface{}{
+				Counterexample{Kind: CounterexampleSegmentReversal, Variable: "region", Effect: -0.9, Baseline: 0.4},
+			}},
+		},
+	}
+
+	found := CounterexamplesOf(h)
+	if len(found) != 2 || found[0].Variable != "season" || found[1].Source != "Chow_Stability_Test" {
+		t.Errorf("expected the confounder then the time window, got %+v", found)
+	}
+
+	h.Passed = true
+	if len(CounterexamplesOf(h)) != 0 {
+		t.Error("expected no counterexamples for a passing hypothesis")
+	}
+}
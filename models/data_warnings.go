@@ -0,0 +1,23 @@
+package models
+
+// DataWarningsKey is the execution metadata key holding the warning codes (LOW_N,
+// HIGH_MISSING, ...) the stats sweep attached to a hypothesis' cause/effect pair
+const DataWarningsKey = "data_warnings"
+
+// DataWarningsOf returns the warning codes stored with a hypothesis, which may have been
+// decoded from JSON
+func DataWarningsOf(h *HypothesisResult) []string {
+	switch list := h.ExecutionMetadata[DataWarningsKey].(type) {
+	case []string:
+		return list
+	case []interface{}:
+		codes := make([]string, 0, len(list))
+		for _, item := range list {
+			if code, ok := item.(string); ok && code != "" {
+				codes = append(codes, code)
+			}
+		}
+		return codes
+	}
+	return nil
+}
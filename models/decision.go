@@ -0,0 +1,154 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// StringList is a []string stored in a PostgreSQL JSONB column
+type StringList []string
+
+// Value implements driver.Valuer interface
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(l))
+}
+
+// Scan implements sql.Scanner interface
+func (l *StringList) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		*l = nil
+		return nil
+	}
+	if len(bytes) == 0 {
+		*l = nil
+		return nil
+	}
+	return json.Unmarshal(bytes, (*[]string)(l))
+}
+
+// Outcomes of a decision informed by a hypothesis
+const (
+	OutcomePending      = "pending"
+	OutcomeConfirmed    = "confirmed"    // the action worked the way the hypothesis predicted
+	OutcomeRefuted      = "refuted"      // it did not
+	OutcomeInconclusive = "inconclusive" // the result cannot be attributed either way
+)
+
+// Decision is a business action taken on the strength of a hypothesis and, once known, how
+// it turned out
+type Decision struct {
+	ID           string     `json:"id" db:"id"`
+	WorkspaceID  string     `json:"workspace_id" db:"workspace_id"`
+	HypothesisID string     `json:"hypothesis_id" db:"hypothesis_id"`
+	Action       string     `json:"action" db:"action"`
+	Expected     string     `json:"expected,omitempty" db:"expected"` // the outcome the hypothesis predicts
+	DecidedBy    string     `json:"decided_by,omitempty" db:"decided_by"`
+	DecidedAt    time.Time  `json:"decided_at" db:"decided_at"`
+	Outcome      string     `json:"outcome" db:"outcome"`
+	OutcomeNotes string     `json:"outcome_notes,omitempty" db:"outcome_notes"`
+	OutcomeAt    *time.Time `json:"outcome_at,omitempty" db:"outcome_at"`
+
+	// Gates are the referee tests the hypothesis passed when the decision was made; outcomes
+	// are credited to them when calibrating E-values
+	Gates StringList `json:"gates" db:"gates"`
+}
+
+// Validate checks a decision before it is recorded
+func (d *Decision) Validate() error {
+	if d.HypothesisID == "" {
+		return fmt.Errorf("hypothesis_id is required")
+	}
+	if d.Action == "" {
+		return fmt.Errorf("action is required")
+	}
+	return nil
+}
+
+// ValidOutcome reports whether outcome can be recorded against a decision
+func ValidOutcome(outcome string) bool {
+	switch outcome {
+	case OutcomeConfirmed, OutcomeRefuted, OutcomeInconclusive:
+		return true
+	}
+	return false
+}
+
+// DecisionSummary aggregates a decision log, e.g. "12 decisions informed, 8 confirmed"
+type DecisionSummary struct {
+	Informed     int `json:"informed"`
+	Confirmed    int `json:"confirmed"`
+	Refuted      int `json:"refuted"`
+	Inconclusive int `json:"inconclusive"`
+	Pending      int `json:"pending"`
+	// HitRate is confirmed / (confirmed + refuted); nil until an outcome is conclusive
+	HitRate *float64 `json:"hit_rate,omitempty"`
+}
+
+// Headline renders the summary for the workspace dashboard
+func (s DecisionSummary) Headline() string {
+	noun := "decisions"
+	if s.Informed == 1 {
+		noun = "decision"
+	}
+	return fmt.Sprintf("%d %s informed, %d confirmed", s.Informed, noun, s.Confirmed)
+}
+
+// SummarizeDecisions counts decisions by outcome
+func SummarizeDecisions(decisions []*Decision) DecisionSummary {
+	var summary DecisionSummary
+	for _, d := range decisions {
+		summary.Informed++
+		switch d.Outcome {
+		case OutcomeConfirmed:
+			summary.Confirmed++
+		case OutcomeRefuted:
+			summary.Refuted++
+		case OutcomeInconclusive:
+			summary.Inconclusive++
+		default:
+			summary.Pending++
+		}
+	}
+	if conclusive := summary.Confirmed + summary.Refuted; conclusive > 0 {
+		rate := float64(summary.Confirmed) / float64(conclusive)
+		summary.HitRate = &rate
+	}
+	return summary
+}
+
+// OutcomeTally counts conclusive decision outcomes for hypotheses that passed one test
+type OutcomeTally struct {
+	Confirmed int `json:"confirmed"`
+	Refuted   int `json:"refuted"`
+}
+
+// TallyOutcomes credits each conclusive outcome to every gate its hypothesis passed
+func TallyOutcomes(decisions []*Decision) map[string]OutcomeTally {
+	tallies := make(map[string]OutcomeTally)
+	for _, d := range decisions {
+		if d.Outcome != OutcomeConfirmed && d.Outcome != OutcomeRefuted {
+			continue
+		}
+		for _, gate := range d.Gates {
+			tally := tallies[gate]
+			if d.Outcome == OutcomeConfirmed {
+				tally.Confirmed++
+			} else {
+				tally.Refuted++
+			}
+			tallies[gate] = tally
+		}
+	}
+	return tallies
+}
@@ -0,0 +1,37 @@
+package models
+
+import "testing"
+
+// TestSummarizeDecisions verifies the dashboard headline and that only conclusive outcomes
+// count towards the hit rate and gate tallies
+func TestSummarizeDecisions(t *testing.T) {
+	decisions := []*Decision{
+		{Outcome: OutcomeConfirmed, Gates: StringList{"Permutation_Shuffling", "Conditional_MI"}},
+		{Outcome: OutcomeConfirmed, Gates: StringList{"Permutation_Shuffling"}},
+		{Outcome: OutcomeRefuted, Gates: StringList{"Conditional_MI"}},
+		{Outcome: OutcomeInconclusive, Gates: StringList{"Permutation_Shuffling"}},
+		{Outcome: OutcomePending, Gates: StringList{"Permutation_Shuffling"}},
+	}
+
+	summary := SummarizeDecisions(decisions)
+	if got := summary.Headline(); got != "5 decisions informed, 2 confirmed" {
+		t.Fatalf("unexpected headline %q", got)
+	}
+	if summary.Pending != 1 || summary.Inconclusive != 1 || summary.Refuted != 1 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if summary.HitRate == nil || *summary.HitRate < 0.66 || *summary.HitRate > 0.67 {
+		t.Fatalf("expected a 2/3 hit rate, got %v", summary.HitRate)
+	}
+	if SummarizeDecisions(nil).HitRate != nil {
+		t.Fatal("expected no hit rate without conclusive outcomes")
+	}
+
+	tallies := TallyOutcomes(decisions)
+	if tallies["Permutation_Shuffling"] != (OutcomeTally{Confirmed: 2}) {
+		t.Fatalf("unexpected permutation tally %+v", tallies["Permutation_Shuffling"])
+	}
+	if tallies["Conditional_MI"] != (OutcomeTally{Confirmed: 1, Refuted: 1}) {
+		t.Fatalf("unexpected conditional MI tally %+v", tallies["Conditional_MI"])
+	}
+}
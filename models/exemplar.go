@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Exemplar lifecycle: contributors propose, an admin approves, and only approved exemplars
+// reach generation prompts. Retired exemplars are kept for the audit trail.
+const (
+	ExemplarProposed = "proposed"
+	ExemplarApproved = "approved"
+	ExemplarRetired  = "retired"
+)
+
+// GeneralDomainPack holds exemplars offered to every domain
+const GeneralDomainPack = "general"
+
+// Exemplar is a curated hypothesis shown to the LLM as a few-shot example, together with the
+// evidence it was grounded in
+type Exemplar struct {
+	ID                 string                    `json:"id" db:"id"`
+	DomainPack         string                    `json:"domain_pack" db:"domain_pack"`
+	Directive          ResearchDirectiveResponse `json:"directive"`
+	Evidence           map[string]interface{}    `json:"evidence"`
+	SourceHypothesisID string                    `json:"source_hypothesis_id,omitempty" db:"source_hypothesis_id"`
+	Status             string                    `json:"status" db:"status"`
+	ProposedBy         string                    `json:"proposed_by,omitempty" db:"proposed_by"`
+	ApprovedBy         string                    `json:"approved_by,omitempty" db:"approved_by"`
+	CreatedAt          time.Time                 `json:"created_at" db:"created_at"`
+	ApprovedAt         *time.Time                `json:"approved_at,omitempty" db:"approved_at"`
+}
+
+// NormalizeDomainPack folds a domain name ("Freight Logistics") to its pack key
+// ("freight-logistics"); empty means the general pack
+func NormalizeDomainPack(domain string) string {
+	pack := strings.Join(strings.Fields(strings.ToLower(domain)), "-")
+	if pack == "" {
+		return GeneralDomainPack
+	}
+	return pack
+}
+
+// DirectiveFromHypothesis rebuilds the directive a stored hypothesis was validated from: its
+// statements, variables and the referees that judged it
+func DirectiveFromHypothesis(h *HypothesisResult) ResearchDirectiveResponse {
+	cause, _ := h.ExecutionMetadata["cause_key"].(string)
+	effect, _ := h.ExecutionMetadata["effect_key"].(string)
+	directive := ResearchDirectiveResponse{
+		ID:                  h.ID,
+		BusinessHypothesis:  h.BusinessHypothesis,
+		ScienceHypothesis:   h.ScienceHypothesis,
+		NullCase:            h.NullCase,
+		CauseKey:            cause,
+		EffectKey:           effect,
+		ExplanationMarkdown: h.ExplanationMarkdown,
+	}
+	for _, result := range h.RefereeResults {
+		directive.RefereeGates.SelectedReferees = append(directive.RefereeGates.SelectedReferees, RefereeSelection{Name: result.GateName})
+	}
+	return directive
+}
+
+// ExemplarFromHypothesis drafts an exemplar from a validated hypothesis: the directive in
+// the generation output shape, and the referee outcomes it passed as its evidence
+func ExemplarFromHypothesis(h *HypothesisResult, domainPack string) (*Exemplar, error) {
+	if h == nil {
+		return nil, fmt.Errorf("hypothesis is required")
+	}
+	if !h.Passed {
+		return nil, fmt.Errorf("hypothesis %s did not pass validation; only validated hypotheses can become exemplars", h.ID)
+	}
+
+	directive := DirectiveFromHypothesis(h)
+	referees := make([]map[string]interface{}, 0, len(h.RefereeResults))
+	for _, result := range h.RefereeResults {
+		referees = append(referees, map[string]interface{}{
+			"gate":      result.GateName,
+			"passed":    result.Passed,
+			"statistic": result.Statistic,
+			"p_value":   result.PValue,
+		})
+	}
+
+	return &Exemplar{
+		DomainPack:         NormalizeDomainPack(domainPack),
+		Directive:          directive,
+		SourceHypothesisID: h.ID,
+		Evidence: map[string]interface{}{
+			"referee_results": referees,
+			"confidence":      h.Confidence,
+			"sample_size":     h.ExecutionMetadata["sample_size"],
+		},
+	}, nil
+}
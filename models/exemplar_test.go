@@ -0,0 +1,32 @@
+package models
+
+import "testing"
+
+// TestExemplarFromHypothesisRequiresValidation verifies only passed hypotheses become
+// exemplars and that the directive keeps the output schema shape
+func TestExemplarFromHypothesisRequiresValidation(t *testing.T) {
+	h := &HypothesisResult{
+		ID:                 "HYP-001",
+		BusinessHypothesis: "Late pickups drive churn",
+		RefereeResults:     []RefereeResult{{GateName: "Permutation_Shuffling", Passed: true, PValue: 0.001}},
+		ExecutionMetadata:  map[string]interface{}{"cause_key": "pickup_delay", "effect_key": "churned"},
+	}
+	if _, err := ExemplarFromHypothesis(h, "logistics"); err == nil {
+		t.Fatal("expected an unvalidated hypothesis to be rejected")
+	}
+
+	h.Passed = true
+	exemplar, err := ExemplarFromHypothesis(h, "  Freight Logistics ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exemplar.DomainPack != "freight-logistics" || exemplar.SourceHypothesisID != "HYP-001" {
+		t.Fatalf("unexpected exemplar %+v", exemplar)
+	}
+	if exemplar.Directive.CauseKey != "pickup_delay" || len(exemplar.Directive.RefereeGates.SelectedReferees) != 1 {
+		t.Fatalf("directive not carried over: %+v", exemplar.Directive)
+	}
+	if NormalizeDomainPack("") != GeneralDomainPack {
+		t.Fatal("expected an empty domain to map to the general pack")
+	}
+}
@@ -42,6 +42,13 @@ type ResearchDirectiveResponse struct {
 	Claim              string             `json:"claim,omitempty" description:"Legacy field"`
 	LogicType          string             `json:"logic_type,omitempty" description:"Legacy field"`
 	ValidationStrategy ValidationStrategy `json:"validation_strategy,omitempty" description:"Legacy field"`
+	// PromptBudget is set by the worker, not the LLM: what the generating prompt included
+	PromptBudget *PromptBudgetReport `json:"-"`
+	// SelfConsistency is set by the generator when several samples were drawn
+	SelfConsistency *SelfConsistency `json:"-"`
+	// DataWarnings is set by the worker: the warning codes the sweep attached to the
+	// cause/effect pair, carried onto the hypothesis' caveats
+	DataWarnings []string `json:"-"`
 }
 
 type OpportunityAnalysis struct {
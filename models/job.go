@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JobStatus is where a queued job is in its lifecycle
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"    // waiting for a worker, possibly until RunAt for a retry
+	JobStatusRunning   JobStatus = "running"   // claimed by a worker
+	JobStatusSucceeded JobStatus = "succeeded" // finished
+	JobStatusFailed    JobStatus = "failed"    // gave up after MaxAttempts
+)
+
+// JobKindResearch runs a research session: the sweep, hypothesis generation and validation
+const JobKindResearch = "research"
+
+// Retry defaults: a job is tried DefaultJobMaxAttempts times, waiting JobRetryBaseDelay
+// before the first retry and twice as long before each later one, up to JobRetryMaxDelay
+const (
+	DefaultJobMaxAttempts = 3
+	JobRetryBaseDelay     = 30 * time.Second
+	JobRetryMaxDelay      = 30 * time.Minute
+)
+
+// Job is one unit of background work in the durable job queue. Jobs outlive the process
+// that enqueued them, so a restart resumes the queue instead of dropping it.
+type Job struct {
+	ID             string          `json:"id" db:"id"`
+	Kind           string          `json:"kind" db:"kind"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         JobStatus       `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"` // claims so far, including a running one
+	MaxAttempts    int             `json:"max_attempts" db:"max_attempts"`
+	RunAt          time.Time       `json:"run_at" db:"run_at"` // not claimed before this time
+	LastError      *string         `json:"last_error,omitempty" db:"last_error"`
+	LockedBy       *string         `json:"locked_by,omitempty" db:"locked_by"` // worker holding a running job
+	LockedAt       *time.Time      `json:"locked_at,omitempty" db:"locked_at"`
+	LeaseExpiresAt *time.Time      `json:"lease_expires_at,omitempty" db:"lease_expires_at"` // recovered unless heartbeated first
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// NewJob creates a job of kind runnable right away, with payload marshaled to JSON
+func NewJob(kind string, payload interface{}) (*Job, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &Job{
+		Kind:        kind,
+		Payload:     raw,
+		Status:      JobStatusQueued,
+		MaxAttempts: DefaultJobMaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// CanRetry reports whether a failed attempt leaves the job another one
+func (j *Job) CanRetry() bool {
+	return j.Attempts < j.MaxAttempts
+}
+
+// RetryDelay is how long to wait after the job's latest failed attempt before the next
+func (j *Job) RetryDelay() time.Duration {
+	delay := JobRetryBaseDelay
+	for i := 1; i < j.Attempts && delay < JobRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, JobRetryMaxDelay)
+}
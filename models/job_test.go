@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// TestJobRetryDelay verifies the backoff doubles after each failed attempt up to the cap
+func TestJobRetryDelay(t *testing.T) {
+	job, err := NewJob(JobKindResearch, map[string]string{"session_id": "s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute}
+	for i, delay := range want {
+		job.Attempts = i + 1
+		if got := job.RetryDelay(); got != delay {
+			t.Fatalf("attempt %d: expected %v, got %v", job.Attempts, delay, got)
+		}
+	}
+	job.Attempts = 20
+	if got := job.RetryDelay(); got != JobRetryMaxDelay {
+		t.Fatalf("expected the delay capped at %v, got %v", JobRetryMaxDelay, got)
+	}
+
+	job.Attempts = DefaultJobMaxAttempts - 1
+	if !job.CanRetry() {
+		t.Fatal("expected a retry while attempts remain")
+	}
+	job.Attempts = DefaultJobMaxAttempts
+	if job.CanRetry() {
+		t.Fatal("expected no retry once the attempts are used up")
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// LLMPrice is the list price in USD per million tokens
+type LLMPrice struct {
+	Prompt     float64 `json:"prompt"`
+	Completion float64 `json:"completion"`
+}
+
+// defaultLLMPrices are matched by longest model-name prefix. They are estimates for
+// reporting only; override or extend them with LLM_PRICES, e.g.
+// LLM_PRICES='{"gpt-5.2":{"prompt":1.75,"completion":14}}'.
+var defaultLLMPrices = map[string]LLMPrice{
+	"gpt-4o":            {Prompt: 2.50, Completion: 10.00},
+	"gpt-4o-mini":       {Prompt: 0.15, Completion: 0.60},
+	"gpt-4.1":           {Prompt: 2.00, Completion: 8.00},
+	"gpt-4.1-mini":      {Prompt: 0.40, Completion: 1.60},
+	"gpt-5":             {Prompt: 1.25, Completion: 10.00},
+	"gpt-5-mini":        {Prompt: 0.25, Completion: 2.00},
+	"claude-3-5-haiku":  {Prompt: 0.80, Completion: 4.00},
+	"claude-3-5-sonnet": {Prompt: 3.00, Completion: 15.00},
+	"claude-sonnet-4":   {Prompt: 3.00, Completion: 15.00},
+	"claude-opus-4":     {Prompt: 15.00, Completion: 75.00},
+}
+
+var (
+	llmPricesOnce sync.Once
+	llmPrices     map[string]LLMPrice
+)
+
+// LLMPrices returns the default price table merged with LLM_PRICES
+func LLMPrices() map[string]LLMPrice {
+	llmPricesOnce.Do(func() {
+		llmPrices = make(map[string]LLMPrice, len(defaultLLMPrices))
+		for model, price := range defaultLLMPrices {
+			llmPrices[model] = price
+		}
+		if raw := os.Getenv("LLM_PRICES"); raw != "" {
+			var overrides map[string]LLMPrice
+			if err := json.Unmarshal([]byte(raw), &overrides); err != nil {
+				log.Printf("[LLM] ⚠️ Ignoring invalid LLM_PRICES: %v", err)
+				return
+			}
+			for model, price := range overrides {
+				llmPrices[model] = price
+			}
+		}
+	})
+	return llmPrices
+}
+
+// EstimateLLMCost returns the estimated USD cost of a call. Local providers and the
+// heuristic mock are free, and unknown models cost 0 so reports never invent a price.
+func EstimateLLMCost(provider, model string, promptTokens, completionTokens int) float64 {
+	if provider == "ollama" || provider == "mock" {
+		return 0
+	}
+	var best string
+	for prefix := range LLMPrices() {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return 0
+	}
+	price := llmPrices[best]
+	return (float64(promptTokens)*price.Prompt + float64(completionTokens)*price.Completion) / 1e6
+}
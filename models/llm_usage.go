@@ -74,3 +74,53 @@ const (
 	OpDatasetProfiling      = "dataset_profiling"
 	OpMergeReasoning        = "merge_reasoning"
 )
+
+// RunUsage is the token and cost accounting for one hypothesis generation run
+type RunUsage struct {
+	RunID            string                    `json:"run_id"`
+	Calls            int                       `json:"calls"`
+	CachedCalls      int                       `json:"cached_calls"`
+	PromptTokens     int                       `json:"prompt_tokens"`
+	CompletionTokens int                       `json:"completion_tokens"`
+	TotalTokens      int                       `json:"total_tokens"`
+	EstimatedCostUSD float64                   `json:"estimated_cost_usd"`
+	ByModel          map[string]*ModelRunUsage `json:"by_model,omitempty"`
+}
+
+// ModelRunUsage is the part of a run's usage billed to one provider model
+type ModelRunUsage struct {
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+}
+
+// Add folds other into u, e.g. to total several generation runs of one session
+func (u *RunUsage) Add(other *RunUsage) {
+	if other == nil {
+		return
+	}
+	u.Calls += other.Calls
+	u.CachedCalls += other.CachedCalls
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.EstimatedCostUSD += other.EstimatedCostUSD
+	for key, model := range other.ByModel {
+		if u.ByModel == nil {
+			u.ByModel = make(map[string]*ModelRunUsage)
+		}
+		existing, ok := u.ByModel[key]
+		if !ok {
+			copied := *model
+			u.ByModel[key] = &copied
+			continue
+		}
+		existing.Calls += model.Calls
+		existing.PromptTokens += model.PromptTokens
+		existing.CompletionTokens += model.CompletionTokens
+		existing.EstimatedCostUSD += model.EstimatedCostUSD
+	}
+}
@@ -0,0 +1,212 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Expected directions of a tracked relationship
+const (
+	DirectionPositive = "positive"
+	DirectionNegative = "negative"
+	DirectionUnknown  = "unknown"
+)
+
+// SQL dialects a metric definition renders for. Postgres syntax also runs on Snowflake and
+// Redshift.
+const (
+	DialectPostgres = "postgres"
+	DialectBigQuery = "bigquery"
+)
+
+// SegmentFilter restricts a metric to one segment, e.g. region = 'EU'
+type SegmentFilter struct {
+	Column   string `json:"column"`
+	Operator string `json:"operator"` // =, !=, <, <=, >, >=
+	Value    string `json:"value"`
+}
+
+// MetricOptions say where the hypothesis' variables live in the user's warehouse
+type MetricOptions struct {
+	Table      string          `json:"table"`
+	TimeColumn string          `json:"time_column,omitempty"` // groups the metric by period when set
+	Grain      string          `json:"grain,omitempty"`       // day, week, month; defaults to week
+	Dialect    string          `json:"dialect,omitempty"`
+	Segments   []SegmentFilter `json:"segments,omitempty"`
+}
+
+// MetricDefinition turns a validated hypothesis into a metric a BI tool can track after the
+// analysis ends: the cause-effect correlation per period, with the direction it was
+// validated in so a dashboard can alert when it flips
+type MetricDefinition struct {
+	HypothesisID         string          `json:"hypothesis_id"`
+	Name                 string          `json:"name"`
+	Description          string          `json:"description"`
+	Cause                string          `json:"cause"`
+	Effect               string          `json:"effect"`
+	ExpectedDirection    string          `json:"expected_direction"`
+	ValidatedCorrelation *float64        `json:"validated_correlation,omitempty"`
+	SampleSize           int             `json:"sample_size,omitempty"`
+	Table                string          `json:"table"`
+	TimeColumn           string          `json:"time_column,omitempty"`
+	Grain                string          `json:"grain,omitempty"`
+	Segments             []SegmentFilter `json:"segments,omitempty"`
+	AlertCondition       string          `json:"alert_condition"`
+	Dialect              string          `json:"dialect"`
+	SQL                  string          `json:"sql"`
+}
+
+var (
+	metricIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+	metricNameUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+	metricOperators  = map[string]bool{"=": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+	metricGrains     = map[string]bool{"day": true, "week": true, "month": true}
+)
+
+// MetricDefinitionFromHypothesis drafts the monitoring metric for a validated hypothesis.
+// Plain identifiers are left bare so the SQL stays readable in a BI tool; anything else is
+// quoted for the dialect.
+func MetricDefinitionFromHypothesis(h *HypothesisResult, opts MetricOptions) (*MetricDefinition, error) {
+	if h == nil {
+		return nil, fmt.Errorf("hypothesis is required")
+	}
+	if !h.Passed {
+		return nil, fmt.Errorf("hypothesis %s did not pass validation; only validated hypotheses can be tracked", h.ID)
+	}
+	cause, _ := h.ExecutionMetadata["cause_key"].(string)
+	effect, _ := h.ExecutionMetadata["effect_key"].(string)
+	if cause == "" || effect == "" {
+		return nil, fmt.Errorf("hypothesis %s has no recorded cause and effect variables", h.ID)
+	}
+
+	if opts.Table == "" {
+		return nil, fmt.Errorf("table is required")
+	}
+	if opts.Dialect == "" {
+		opts.Dialect = DialectPostgres
+	}
+	if opts.Dialect != DialectPostgres && opts.Dialect != DialectBigQuery {
+		return nil, fmt.Errorf("dialect must be %s or %s, got %q", DialectPostgres, DialectBigQuery, opts.Dialect)
+	}
+	if opts.TimeColumn != "" && opts.Grain == "" {
+		opts.Grain = "week"
+	}
+	if opts.Grain != "" && !metricGrains[opts.Grain] {
+		return nil, fmt.Errorf("grain must be day, week or month, got %q", opts.Grain)
+	}
+	for _, segment := range opts.Segments {
+		if segment.Column == "" {
+			return nil, fmt.Errorf("segment filter needs a column")
+		}
+		if !metricOperators[segment.Operator] {
+			return nil, fmt.Errorf("unsupported segment operator %q", segment.Operator)
+		}
+	}
+
+	metric := &MetricDefinition{
+		HypothesisID:      h.ID,
+		Name:              strings.Trim(metricNameUnsafe.ReplaceAllString(strings.ToLower(cause+"_"+effect), "_"), "_") + "_correlation",
+		Description:       h.BusinessHypothesis,
+		Cause:             cause,
+		Effect:            effect,
+		ExpectedDirection: DirectionUnknown,
+		Table:             opts.Table,
+		TimeColumn:        opts.TimeColumn,
+		Grain:             opts.Grain,
+		Segments:          opts.Segments,
+		Dialect:           opts.Dialect,
+	}
+	if r, ok := h.ExecutionMetadata["observed_correlation"].(float64); ok && !math.IsNaN(r) {
+		metric.ValidatedCorrelation = &r
+		switch {
+		case r > 0:
+			metric.ExpectedDirection = DirectionPositive
+		case r < 0:
+			metric.ExpectedDirection = DirectionNegative
+		}
+	}
+	switch n := h.ExecutionMetadata["sample_size"].(type) {
+	case int:
+		metric.SampleSize = n
+	case float64:
+		metric.SampleSize = int(n)
+	}
+
+	switch metric.ExpectedDirection {
+	case DirectionPositive:
+		metric.AlertCondition = "correlation <= 0"
+	case DirectionNegative:
+		metric.AlertCondition = "correlation >= 0"
+	default:
+		metric.AlertCondition = "abs(correlation) < 0.1"
+	}
+	metric.SQL = metric.render()
+	return metric, nil
+}
+
+// render writes the metric query: one row per period (or one row overall) with the
+// correlation and the means it moves with
+func (m *MetricDefinition) render() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- %s (hypothesis %s)\n", m.Name, m.HypothesisID)
+	if m.Description != "" {
+		fmt.Fprintf(&b, "-- %s\n", strings.ReplaceAll(m.Description, "\n", " "))
+	}
+	if m.ValidatedCorrelation != nil {
+		fmt.Fprintf(&b, "-- Validated correlation %.3f over %d rows; alert when %s\n", *m.ValidatedCorrelation, m.SampleSize, m.AlertCondition)
+	} else {
+		fmt.Fprintf(&b, "-- Alert when %s\n", m.AlertCondition)
+	}
+
+	cause, effect := m.quote(m.Cause), m.quote(m.Effect)
+	b.WriteString("SELECT\n")
+	if m.TimeColumn != "" {
+		fmt.Fprintf(&b, "  %s AS period,\n", m.periodExpression())
+	}
+	fmt.Fprintf(&b, "  COUNT(*) AS n,\n")
+	fmt.Fprintf(&b, "  CORR(%s, %s) AS correlation,\n", cause, effect)
+	fmt.Fprintf(&b, "  AVG(%s) AS avg_cause,\n", cause)
+	fmt.Fprintf(&b, "  AVG(%s) AS avg_effect\n", effect)
+	fmt.Fprintf(&b, "FROM %s\n", m.quote(m.Table))
+	fmt.Fprintf(&b, "WHERE %s IS NOT NULL\n  AND %s IS NOT NULL\n", cause, effect)
+	for _, segment := range m.Segments {
+		fmt.Fprintf(&b, "  AND %s %s %s\n", m.quote(segment.Column), segment.Operator, m.literal(segment.Value))
+	}
+	if m.TimeColumn != "" {
+		b.WriteString("GROUP BY 1\nORDER BY 1\n")
+	}
+	return b.String()
+}
+
+func (m *MetricDefinition) periodExpression() string {
+	if m.Dialect == DialectBigQuery {
+		return fmt.Sprintf("DATE_TRUNC(DATE(%s), %s)", m.quote(m.TimeColumn), strings.ToUpper(m.Grain))
+	}
+	return fmt.Sprintf("DATE_TRUNC('%s', %s)", m.Grain, m.quote(m.TimeColumn))
+}
+
+// quote leaves plain (optionally schema-qualified) identifiers bare and quotes the rest,
+// e.g. spreadsheet headers with spaces
+func (m *MetricDefinition) quote(ident string) string {
+	if metricIdentifier.MatchString(ident) {
+		return ident
+	}
+	if m.Dialect == DialectBigQuery {
+		return "`" + strings.ReplaceAll(ident, "`", "\\`") + "`"
+	}
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+// literal leaves numbers bare and single-quotes everything else
+func (m *MetricDefinition) literal(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	if m.Dialect == DialectBigQuery {
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMetricDefinitionFromHypothesis verifies the validated direction drives the alert and
+// that non-plain identifiers and segment values are quoted
+func TestMetricDefinitionFromHypothesis(t *testing.T) {
+	h := &HypothesisResult{
+		ID:                 "HYP-007",
+		BusinessHypothesis: "Longer delivery times lower repeat purchases",
+		ExecutionMetadata: map[string]interface{}{
+			"cause_key":            "delivery days",
+			"effect_key":           "repeat_rate",
+			"observed_correlation": -0.41,
+			"sample_size":          float64(1200),
+		},
+	}
+	opts := MetricOptions{Table: "analytics.orders", TimeColumn: "order_date", Segments: []SegmentFilter{{Column: "region", Operator: "=", Value: "O'Hare"}}}
+	if _, err := MetricDefinitionFromHypothesis(h, opts); err == nil {
+		t.Fatal("expected an unvalidated hypothesis to be rejected")
+	}
+
+	h.Passed = true
+	metric, err := MetricDefinitionFromHypothesis(h, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metric.ExpectedDirection != DirectionNegative || metric.AlertCondition != "correlation >= 0" || metric.SampleSize != 1200 {
+		t.Fatalf("unexpected metric %+v", metric)
+	}
+	for _, want := range []string{
+		`CORR("delivery days", repeat_rate) AS correlation`,
+		"FROM analytics.orders",
+		"DATE_TRUNC('week', order_date) AS period",
+		"AND region = 'O''Hare'",
+		"GROUP BY 1",
+	} {
+		if !strings.Contains(metric.SQL, want) {
+			t.Errorf("SQL missing %q:\n%s", want, metric.SQL)
+		}
+	}
+
+	opts.Dialect = DialectBigQuery
+	metric, err = MetricDefinitionFromHypothesis(h, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(metric.SQL, "CORR(`delivery days`, repeat_rate)") || !strings.Contains(metric.SQL, "DATE_TRUNC(DATE(order_date), WEEK)") {
+		t.Fatalf("unexpected BigQuery SQL:\n%s", metric.SQL)
+	}
+}
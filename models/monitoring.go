@@ -0,0 +1,70 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Statuses of a tracked relationship
+const (
+	TrackingHealthy   = "healthy"
+	TrackingDegraded  = "degraded"
+	TrackingUnchecked = "unchecked"
+)
+
+// RelationshipCheck is one re-estimate of a validated relationship on a dataset version.
+// The effect is the cause-effect correlation signed by the validated direction, so a
+// relationship that weakens or flips falls towards and below zero.
+type RelationshipCheck struct {
+	ID                   string    `json:"id" db:"id"`
+	HypothesisID         string    `json:"hypothesis_id" db:"hypothesis_id"`
+	WorkspaceID          string    `json:"workspace_id" db:"workspace_id"`
+	DatasetID            string    `json:"dataset_id" db:"dataset_id"`
+	DatasetVersion       time.Time `json:"dataset_version" db:"dataset_version"` // the dataset's updated_at when checked
+	Cause                string    `json:"cause" db:"cause"`
+	Effect               string    `json:"effect" db:"effect"`
+	ValidatedCorrelation float64   `json:"validated_correlation" db:"validated_correlation"`
+	Correlation          float64   `json:"correlation" db:"correlation"`
+	DirectionalEffect    float64   `json:"directional_effect" db:"directional_effect"`
+	PValue               float64   `json:"p_value" db:"p_value"`
+	SampleSize           int       `json:"sample_size" db:"sample_size"`
+	Floor                float64   `json:"floor" db:"floor"`
+	Degraded             bool      `json:"degraded" db:"degraded"`
+	Alerted              bool      `json:"alerted" db:"alerted"` // first degraded check after a healthy one
+	Error                string    `json:"error,omitempty" db:"error"`
+	CheckedAt            time.Time `json:"checked_at" db:"checked_at"`
+}
+
+// Evaluate sets the directional effect and whether it is below the floor. A check that could
+// not estimate the relationship (e.g. a column was dropped) counts as degraded.
+func (c *RelationshipCheck) Evaluate() {
+	if c.Error != "" || math.IsNaN(c.Correlation) {
+		c.Degraded = true
+		return
+	}
+	c.DirectionalEffect = c.Correlation
+	if c.ValidatedCorrelation < 0 {
+		c.DirectionalEffect = -c.Correlation
+	}
+	c.Degraded = c.DirectionalEffect < c.Floor
+}
+
+// TrackedRelationship is a validated relationship with its effect-size trajectory across
+// dataset versions, oldest first
+type TrackedRelationship struct {
+	HypothesisID         string              `json:"hypothesis_id"`
+	Description          string              `json:"description"`
+	Cause                string              `json:"cause"`
+	Effect               string              `json:"effect"`
+	ValidatedCorrelation float64             `json:"validated_correlation"`
+	Status               string              `json:"status"`
+	Trajectory           []RelationshipCheck `json:"trajectory"`
+}
+
+// Latest returns the most recent check, or nil when the relationship was never re-checked
+func (t *TrackedRelationship) Latest() *RelationshipCheck {
+	if len(t.Trajectory) == 0 {
+		return nil
+	}
+	return &t.Trajectory[len(t.Trajectory)-1]
+}
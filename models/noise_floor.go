@@ -0,0 +1,17 @@
+package models
+
+// NoisePercentileKey is the execution metadata key holding where a hypothesis' observed
+// correlation falls against its workspace's noise floor, in percent of permuted effects
+const NoisePercentileKey = "noise_percentile"
+
+// NoisePercentileOf returns a hypothesis' noise-floor percentile, if its workspace had a
+// calibrated noise floor when it was validated
+func NoisePercentileOf(h *HypothesisResult) (float64, bool) {
+	switch percentile := h.ExecutionMetadata[NoisePercentileKey].(type) {
+	case float64:
+		return percentile, true
+	case int:
+		return float64(percentile), true
+	}
+	return 0, false
+}
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"gohypo/domain/stage"
+)
+
+// NotificationKind is the chat service a notification channel posts to
+type NotificationKind string
+
+const (
+	NotifySlack NotificationKind = "slack"
+	NotifyTeams NotificationKind = "teams"
+)
+
+// NotificationChannel is a Slack or Microsoft Teams channel a workspace announces validated
+// hypotheses in, through the channel's incoming webhook. Only hypotheses meeting MinRigor are
+// announced. The webhook URL is a credential: it is shown masked after creation.
+type NotificationChannel struct {
+	ID          string             `json:"id" db:"id"`
+	WorkspaceID string             `json:"workspace_id" db:"workspace_id"`
+	Kind        NotificationKind   `json:"kind" db:"kind"`
+	Name        string             `json:"name" db:"name"`
+	WebhookURL  string             `json:"webhook_url" db:"webhook_url"`
+	MinRigor    stage.RigorProfile `json:"min_rigor" db:"min_rigor"`
+	Active      bool               `json:"active" db:"active"`
+	CreatedBy   string             `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time          `json:"created_at" db:"created_at"`
+}
+
+// NewNotificationChannel creates an active channel of a workspace announcing hypotheses that
+// meet minRigor, decision rigor when unset, through an https incoming webhook
+func NewNotificationChannel(workspaceID string, kind NotificationKind, name, webhookURL string, minRigor stage.RigorProfile) (*NotificationChannel, error) {
+	if kind != NotifySlack && kind != NotifyTeams {
+		return nil, fmt.Errorf("unsupported notification channel kind %q (want slack or teams)", kind)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("channel name is required")
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("webhook_url must be an https incoming webhook URL")
+	}
+	if minRigor == "" {
+		minRigor = stage.RigorDecision
+	}
+	if rigorRank(minRigor) == 0 {
+		return nil, fmt.Errorf("unsupported rigor %q (want basic, standard or decision)", minRigor)
+	}
+	return &NotificationChannel{
+		WorkspaceID: workspaceID,
+		Kind:        kind,
+		Name:        name,
+		WebhookURL:  webhookURL,
+		MinRigor:    minRigor,
+		Active:      true,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// Masked returns the channel with its webhook URL cut down to the host, for listings
+func (c NotificationChannel) Masked() NotificationChannel {
+	if u, err := url.Parse(c.WebhookURL); err == nil {
+		c.WebhookURL = u.Scheme + "://" + u.Host + "/…"
+	} else {
+		c.WebhookURL = ""
+	}
+	return c
+}
+
+// Announces reports whether the channel announces a hypothesis: it is active and the
+// hypothesis' validation meets the channel's rigor
+func (c *NotificationChannel) Announces(h *HypothesisResult) bool {
+	return c.Active && rigorRank(RigorMet(h)) >= rigorRank(c.MinRigor)
+}
+
+// RigorMet is the strictest rigor profile a hypothesis' validation meets, "" when it did not
+// pass. Basic rigor only asks that it passed; standard that at least two referees ran and most
+// passed; decision that at least three ran and all passed.
+func RigorMet(h *HypothesisResult) stage.RigorProfile {
+	if !h.Passed {
+		return ""
+	}
+	record := VerdictRecordOf(h, VerdictTriggerValidation)
+	switch {
+	case record.Gates >= 3 && record.GatesPassed == record.Gates:
+		return stage.RigorDecision
+	case record.Gates >= 2 && 2*record.GatesPassed > record.Gates:
+		return stage.RigorStandard
+	default:
+		return stage.RigorBasic
+	}
+}
+
+// rigorRank orders rigor profiles from basic (1) to decision (3); 0 for anything else
+func rigorRank(r stage.RigorProfile) int {
+	switch r {
+	case stage.RigorBasic:
+		return 1
+	case stage.RigorStandard:
+		return 2
+	case stage.RigorDecision:
+		return 3
+	}
+	return 0
+}
+
+// Notification is a summary card, laid out by each chat service's notifier
+type Notification struct {
+	Title    string             `json:"title"`
+	Text     string             `json:"text"`
+	Facts    []NotificationFact `json:"facts,omitempty"`
+	LinkURL  string             `json:"link_url,omitempty"`
+	LinkText string             `json:"link_text,omitempty"`
+}
+
+// NotificationFact is one labelled value on a card
+type NotificationFact struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// ValidatedHypothesisNotification is the card announcing a validated hypothesis, linking to
+// its validation page
+func ValidatedHypothesisNotification(h *HypothesisResult, link string) Notification {
+	record := VerdictRecordOf(h, VerdictTriggerValidation)
+	n := Notification{
+		Title:    "Hypothesis validated",
+		Text:     h.BusinessHypothesis,
+		LinkURL:  link,
+		LinkText: "Open validation",
+	}
+	if n.Text == "" {
+		n.Text = h.ScienceHypothesis
+	}
+	cause, _ := h.ExecutionMetadata["cause_key"].(string)
+	effect, _ := h.ExecutionMetadata["effect_key"].(string)
+	if cause != "" && effect != "" {
+		n.Facts = append(n.Facts, NotificationFact{Label: "Relationship", Value: cause + " → " + effect})
+	}
+	n.Facts = append(n.Facts,
+		NotificationFact{Label: "Rigor", Value: string(RigorMet(h))},
+		NotificationFact{Label: "Referees passed", Value: fmt.Sprintf("%d of %d", record.GatesPassed, record.Gates)},
+		NotificationFact{Label: "Confidence", Value: fmt.Sprintf("%.0f%%", h.Confidence*100)},
+	)
+	if r, ok := h.ExecutionMetadata["observed_correlation"].(float64); ok {
+		n.Facts = append(n.Facts, NotificationFact{Label: "Observed correlation", Value: fmt.Sprintf("%.3f", r)})
+	}
+	return n
+}
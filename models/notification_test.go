@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+
+	"gohypo/domain/stage"
+)
+
+// TestNewNotificationChannelValidates rejects unknown services, blank names, plain-http
+// webhooks and unknown rigor, and defaults to decision rigor
+func TestNewNotificationChannelValidates(t *testing.T) {
+	channel, err := NewNotificationChannel("ws1", NotifySlack, " #insights ", "https://hooks.slack.com/services/T0/B0/secret", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if channel.Name != "#insights" || channel.MinRigor != stage.RigorDecision || !channel.Active {
+		t.Fatalf("unexpected channel %+v", channel)
+	}
+	if masked := channel.Masked().WebhookURL; masked != "https://hooks.slack.com/…" {
+		t.Errorf("expected the webhook path masked, got %q", masked)
+	}
+
+	for name, args := range map[string][4]string{
+		"kind":  {"discord", "#insights", "https://example.com/hook", ""},
+		"name":  {"slack", " ", "https://hooks.slack.com/x", ""},
+		"http":  {"teams", "Insights", "http://example.webhook.office.com/x", ""},
+		"rigor": {"slack", "#insights", "https://hooks.slack.com/x", "strict"},
+	} {
+		if _, err := NewNotificationChannel("ws1", NotificationKind(args[0]), args[1], args[2], stage.RigorProfile(args[3])); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// TestChannelAnnouncesByRigor verifies the rigor a hypothesis meets is derived from its
+// referees and compared against each channel's bar
+func TestChannelAnnouncesByRigor(t *testing.T) {
+	referees := func(passed ...bool) *HypothesisResult {
+		h := &HypothesisResult{Passed: true}
+		for _, p := range passed {
+			h.RefereeResults = append(h.RefereeResults, RefereeResult{Passed: p})
+		}
+		return h
+	}
+	cases := []struct {
+		h    *HypothesisResult
+		want stage.RigorProfile
+	}{
+		{&HypothesisResult{Passed: false}, ""},
+		{referees(true), stage.RigorBasic},
+		{referees(true, false), stage.RigorBasic},
+		{referees(true, true, false), stage.RigorStandard},
+		{referees(true, true, true), stage.RigorDecision},
+	}
+	for i, tc := range cases {
+		if got := RigorMet(tc.h); got != tc.want {
+			t.Errorf("case %d: expected %q rigor, got %q", i, tc.want, got)
+		}
+	}
+
+	standard := &NotificationChannel{Active: true, MinRigor: stage.RigorStandard}
+	if standard.Announces(referees(true)) || !standard.Announces(referees(true, true, false)) || !standard.Announces(referees(true, true, true)) {
+		t.Error("expected a standard-rigor channel to announce standard and decision hypotheses only")
+	}
+	standard.Active = false
+	if standard.Announces(referees(true, true, true)) {
+		t.Error("expected an inactive channel to announce nothing")
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gohypo/domain/dataset"
+	"gohypo/domain/stats"
+	"gohypo/domain/stats/brief"
+)
+
+// PairExploration is an on-demand test of two variables a user picked in the explorer, with
+// everything needed to audit it later: the data it resolved, the policy it was judged under
+// and the engine versions that judged it
+type PairExploration struct {
+	RunID          string                    `json:"run_id"`
+	WorkspaceID    string                    `json:"workspace_id"`
+	DatasetID      string                    `json:"dataset_id"`
+	DatasetName    string                    `json:"dataset_name"`
+	Cause          string                    `json:"cause"`
+	Effect         string                    `json:"effect"`
+	At             time.Time                 `json:"at"`
+	Policy         stats.AnalysisPolicy      `json:"policy"`
+	Test           *stats.PairTest           `json:"test"`
+	Senses         []brief.SenseResult       `json:"senses"`
+	Audits         []dataset.ResolutionAudit `json:"audits"`
+	EngineVersions map[string]string         `json:"engine_versions"`
+	// NoisePercentile places the correlation against the workspace's noise floor, when calibrated
+	NoisePercentile *float64 `json:"noise_percentile,omitempty"`
+	// Where the exploration was stored in the ledger; nil when the ledger is unavailable
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
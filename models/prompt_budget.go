@@ -0,0 +1,58 @@
+package models
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultContextWindows are total context sizes in tokens, matched by longest model-name
+// prefix like defaultLLMPrices. LLM_CONTEXT_WINDOW overrides them for every model.
+var defaultContextWindows = map[string]int{
+	"gpt-4o":          128000,
+	"gpt-4.1":         1047576,
+	"gpt-5":           400000,
+	"claude-3-5":      200000,
+	"claude-sonnet-4": 200000,
+	"claude-opus-4":   200000,
+	"llama3":          8192,
+}
+
+// fallbackContextWindow is assumed for unknown models, so a local or misnamed model is
+// budgeted conservatively rather than overflowed
+const fallbackContextWindow = 8192
+
+// ContextWindow returns the context size of model in tokens
+func ContextWindow(model string) int {
+	if raw := os.Getenv("LLM_CONTEXT_WINDOW"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			return n
+		}
+	}
+	var best string
+	for prefix := range defaultContextWindows {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return fallbackContextWindow
+	}
+	return defaultContextWindows[best]
+}
+
+// PromptBudgetReport records how a generation prompt was fitted to the model's context:
+// which optional fragments made it in and which were dropped, by stable fragment ID
+type PromptBudgetReport struct {
+	Model       string   `json:"model"`
+	LimitTokens int      `json:"limit_tokens"` // context window minus the completion reserve
+	FixedTokens int      `json:"fixed_tokens"` // template, schema and field list, never dropped
+	UsedTokens  int      `json:"used_tokens"`
+	Included    []string `json:"included"`
+	Dropped     []string `json:"dropped"`
+}
+
+// Truncated reports whether any fragment was left out
+func (r *PromptBudgetReport) Truncated() bool {
+	return r != nil && len(r.Dropped) > 0
+}
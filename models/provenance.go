@@ -0,0 +1,123 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"gohypo/domain/core"
+)
+
+// ProvenanceKey is the execution metadata key of the ledger artifact a hypothesis' verdict
+// was stored as
+const ProvenanceKey = "provenance"
+
+// Stages a number shown in the UI can come from
+const (
+	StageProfile    = "profile"
+	StagePairwise   = "pairwise"
+	StageFDR        = "fdr"
+	StageSweep      = "sweep"
+	StageValidation = "validation"
+	StageSkeptic    = "skeptic"
+	StageExplore    = "explore"
+	StageNoiseFloor = "noise_floor"
+)
+
+// Provenance traces a number back to the stored artifact it was read from: the artifact's ID,
+// a fingerprint of its content and the pipeline stage that produced it
+type Provenance struct {
+	ArtifactID  string `json:"artifact_id"`
+	Fingerprint string `json:"fingerprint"`
+	Stage       string `json:"stage"`
+}
+
+// VerdictArtifactID is the ID of the ledger artifact a hypothesis' verdict is stored under
+func VerdictArtifactID(hypothesisID string) string {
+	return hypothesisID + "-verdict"
+}
+
+// ArtifactProvenance is the provenance of a stored artifact. Verdicts are fingerprinted like
+// their validation pages; other payloads by a hash of their canonical JSON, so the fingerprint
+// is the same whether the payload is read back typed or decoded into a map.
+func ArtifactProvenance(artifact core.Artifact) Provenance {
+	provenance := Provenance{ArtifactID: string(artifact.ID), Stage: artifactStage(artifact.Kind)}
+	if h, ok := verdictPayload(artifact); ok {
+		provenance.Fingerprint = ValidationFingerprint(h)
+	} else {
+		provenance.Fingerprint = payloadFingerprint(artifact.Payload)
+	}
+	return provenance
+}
+
+// verdictPayload reads the hypothesis a verdict artifact holds, typed or decoded from JSON
+func verdictPayload(artifact core.Artifact) (*HypothesisResult, bool) {
+	if artifact.Kind != core.ArtifactHypothesis {
+		return nil, false
+	}
+	switch payload := artifact.Payload.(type) {
+	case *HypothesisResult:
+		return payload, true
+	case HypothesisResult:
+		return &payload, true
+	}
+	var h HypothesisResult
+	if data, err := json.Marshal(artifact.Payload); err != nil || json.Unmarshal(data, &h) != nil || h.ID == "" {
+		return nil, false
+	}
+	return &h, true
+}
+
+// ProvenanceOf returns the provenance of a hypothesis' verdict, if it was stored in the ledger
+func ProvenanceOf(h *HypothesisResult) (Provenance, bool) {
+	stored, ok := h.ExecutionMetadata[ProvenanceKey]
+	if !ok {
+		return Provenance{}, false
+	}
+	if provenance, ok := stored.(Provenance); ok {
+		return provenance, true
+	}
+	var provenance Provenance
+	if data, err := json.Marshal(stored); err != nil || json.Unmarshal(data, &provenance) != nil || provenance.ArtifactID == "" {
+		return Provenance{}, false
+	}
+	return provenance, true
+}
+
+// artifactStage names the pipeline stage that produces artifacts of a kind
+func artifactStage(kind core.ArtifactKind) string {
+	switch kind {
+	case core.ArtifactVariableProfile, core.ArtifactVariableHealth:
+		return StageProfile
+	case core.ArtifactRelationship, core.ArtifactSkippedRelationship:
+		return StagePairwise
+	case core.ArtifactFDRFamily:
+		return StageFDR
+	case core.ArtifactSweepManifest, core.ArtifactSweepCheckpoint, core.ArtifactRun:
+		return StageSweep
+	case core.ArtifactHypothesis, core.ArtifactPropensityMatch:
+		return StageValidation
+	case core.ArtifactSkepticReanalysis:
+		return StageSkeptic
+	case core.ArtifactPairExploration:
+		return StageExplore
+	case core.ArtifactNoiseFloor:
+		return StageNoiseFloor
+	}
+	return string(kind)
+}
+
+// payloadFingerprint hashes a payload's JSON after a round trip, which sorts struct fields and
+// map keys alike
+func payloadFingerprint(payload interface{}) string {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	var canonical interface{}
+	if json.Unmarshal(data, &canonical) == nil {
+		data, _ = json.Marshal(canonical)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gohypo/domain/core"
+)
+
+// TestArtifactProvenanceSurvivesLedgerRoundTrip fingerprints artifacts the same way whether
+// the payload is the value that was stored or the map the ledger reads back
+func TestArtifactProvenanceSurvivesLedgerRoundTrip(t *testing.T) {
+	type relationship struct {
+		VariableY  string  `json:"variable_y"`
+		VariableX  string  `json:"variable_x"`
+		EffectSize float64 `json:"effect_size"`
+	}
+	verdict := &HypothesisResult{
+		ID:                  "hyp-1",
+		Passed:              true,
+		ValidationTimestamp: time.Unix(1_700_000_000, 0),
+		RefereeResults:      []RefereeResult{{GateName: "permutation", Passed: true, PValue: 0.01}},
+	}
+	for _, stored := range []core.Artifact{
+		{ID: "rel-1", Kind: core.ArtifactRelationship, Payload: relationship{"basket_size", "discount", 0.42}},
+		{ID: core.ID(VerdictArtifactID(verdict.ID)), Kind: core.ArtifactHypothesis, Payload: verdict},
+	} {
+		data, err := json.Marshal(stored)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var read core.Artifact
+		if err := json.Unmarshal(data, &read); err != nil {
+			t.Fatal(err)
+		}
+		before, after := ArtifactProvenance(stored), ArtifactProvenance(read)
+		if before.Fingerprint == "" || before != after {
+			t.Fatalf("%s: provenance changed through the ledger: %+v, then %+v", stored.Kind, before, after)
+		}
+	}
+
+	provenance := ArtifactProvenance(core.Artifact{ID: "hyp-1-verdict", Kind: core.ArtifactHypothesis, Payload: verdict})
+	if provenance.Stage != StageValidation || provenance.Fingerprint != ValidationFingerprint(verdict) {
+		t.Fatalf("verdict provenance %+v does not match its validation fingerprint", provenance)
+	}
+}
+
+// TestProvenanceOfDecodesStoredProvenance reads a verdict's provenance back from metadata
+// that went through JSON
+func TestProvenanceOfDecodesStoredProvenance(t *testing.T) {
+	h := &HypothesisResult{ExecutionMetadata: map[string]interface{}{}}
+	if _, ok := ProvenanceOf(h); ok {
+		t.Fatal("expected no provenance on a fresh hypothesis")
+	}
+	h.ExecutionMetadata[ProvenanceKey] = Provenance{ArtifactID: "hyp-1-verdict", Fingerprint: "abc", Stage: StageValidation}
+	data, err := json.Marshal(h.ExecutionMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := &HypothesisResult{}
+	if err := json.Unmarshal(data, &stored.ExecutionMetadata); err != nil {
+		t.Fatal(err)
+	}
+	provenance, ok := ProvenanceOf(stored)
+	if !ok || provenance.ArtifactID != "hyp-1-verdict" || provenance.Stage != StageValidation {
+		t.Fatalf("unexpected provenance %+v, %v", provenance, ok)
+	}
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// States of one referee's run within a validation battery. A run still recorded as running
+// when no worker is executing it was cut short by a restart; pending referees of a running
+// battery have not started.
+const (
+	RefereeRunPending     = "pending"
+	RefereeRunRunning     = "running"
+	RefereeRunCompleted   = "completed"
+	RefereeRunFailed      = "failed"
+	RefereeRunInterrupted = "interrupted"
+)
+
+// RefereeRun records when one referee started and finished on one hypothesis, and on how
+// many rows, so later batteries can estimate how long they will take
+type RefereeRun struct {
+	ID           string     `json:"id" db:"id"`
+	SessionID    string     `json:"session_id" db:"session_id"`
+	HypothesisID string     `json:"hypothesis_id" db:"hypothesis_id"`
+	Referee      string     `json:"referee" db:"referee"`
+	SampleSize   int        `json:"sample_size" db:"sample_size"`
+	Status       string     `json:"status" db:"status"`
+	Passed       *bool      `json:"passed,omitempty" db:"passed"`
+	StartedAt    time.Time  `json:"started_at" db:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+	DurationMs   int64      `json:"duration_ms" db:"duration_ms"`
+}
+
+// Finished reports whether the run has a duration usable for estimates
+func (r *RefereeRun) Finished() bool {
+	return r.Status == RefereeRunCompleted || r.Status == RefereeRunFailed
+}
+
+// similarSampleRatio bounds how far a past run's sample size may be from the current one
+// for its runtime to count as a like-for-like observation
+const similarSampleRatio = 2.0
+
+// EstimateRefereeRuntime predicts how long a referee takes on sampleSize rows from its past
+// runs. Runs on similar sample sizes (within a factor of two) are preferred; each observed
+// duration is scaled linearly to the requested size and the median is returned. Without
+// any finished run there is no estimate.
+func EstimateRefereeRuntime(history []*RefereeRun, sampleSize int) (time.Duration, bool) {
+	var similar, all []float64
+	for _, run := range history {
+		if !run.Finished() || run.SampleSize <= 0 {
+			continue
+		}
+		ratio := 1.0
+		if sampleSize > 0 {
+			ratio = float64(sampleSize) / float64(run.SampleSize)
+		}
+		scaled := float64(run.DurationMs) * ratio
+		all = append(all, scaled)
+		if ratio <= similarSampleRatio && ratio >= 1/similarSampleRatio {
+			similar = append(similar, scaled)
+		}
+	}
+	observed := similar
+	if len(observed) == 0 {
+		observed = all
+	}
+	if len(observed) == 0 {
+		return 0, false
+	}
+	sort.Float64s(observed)
+	median := observed[len(observed)/2]
+	if len(observed)%2 == 0 {
+		median = (observed[len(observed)/2-1] + median) / 2
+	}
+	return time.Duration(math.Round(median)) * time.Millisecond, true
+}
+
+// RefereeProgress is one referee's place in a running battery
+type RefereeProgress struct {
+	Referee          string     `json:"referee"`
+	Status           string     `json:"status"` // pending, running, completed or failed
+	Passed           *bool      `json:"passed,omitempty"`
+	StartedAt        *time.Time `json:"started_at,omitempty"`
+	DurationSeconds  float64    `json:"duration_seconds,omitempty"`
+	EstimatedSeconds *float64   `json:"estimated_seconds,omitempty"` // from past runs; absent without history
+}
+
+// ValidationProgress is the state of one hypothesis' referee battery. The ETA is the
+// longest remaining estimate, as referees run concurrently; it is absent when no referee
+// still running has history to estimate from.
+type ValidationProgress struct {
+	SessionID      string            `json:"session_id"`
+	HypothesisID   string            `json:"hypothesis_id"`
+	SampleSize     int               `json:"sample_size"`
+	Total          int               `json:"total"`
+	Completed      int               `json:"completed"`
+	StartedAt      time.Time         `json:"started_at"`
+	ElapsedSeconds float64           `json:"elapsed_seconds"`
+	ETASeconds     *float64          `json:"eta_seconds,omitempty"`
+	Referees       []RefereeProgress `json:"referees"`
+}
+
+// Percent is the share of the battery's referees that finished
+func (p *ValidationProgress) Percent() float64 {
+	if p.Total == 0 {
+		return 0
+	}
+	return 100 * float64(p.Completed) / float64(p.Total)
+}
+
+// UpdateETA recomputes the elapsed time and the ETA at now
+func (p *ValidationProgress) UpdateETA(now time.Time) {
+	p.ElapsedSeconds = now.Sub(p.StartedAt).Seconds()
+	p.ETASeconds = nil
+	for _, referee := range p.Referees {
+		if referee.EstimatedSeconds == nil || (referee.Status != RefereeRunRunning && referee.Status != RefereeRunPending) {
+			continue
+		}
+		remaining := *referee.EstimatedSeconds
+		if referee.StartedAt != nil {
+			remaining = math.Max(0, remaining-now.Sub(*referee.StartedAt).Seconds())
+		}
+		if p.ETASeconds == nil || remaining > *p.ETASeconds {
+			eta := remaining
+			p.ETASeconds = &eta
+		}
+	}
+}
+
+// SessionValidationProgress is a session's batteries still running and every referee run
+// recorded for it
+type SessionValidationProgress struct {
+	SessionID string               `json:"session_id"`
+	Active    []ValidationProgress `json:"active"`
+	Runs      []*RefereeRun        `json:"runs"`
+}
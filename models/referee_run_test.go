@@ -0,0 +1,70 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func finishedRun(sampleSize int, durationMs int64) *RefereeRun {
+	return &RefereeRun{Referee: "permutation", SampleSize: sampleSize, Status: RefereeRunCompleted, DurationMs: durationMs}
+}
+
+// TestEstimateRefereeRuntime verifies estimates prefer similar sample sizes, scale linearly
+// and ignore unfinished runs
+func TestEstimateRefereeRuntime(t *testing.T) {
+	if _, ok := EstimateRefereeRuntime(nil, 1000); ok {
+		t.Error("expected no estimate without history")
+	}
+
+	history := []*RefereeRun{
+		finishedRun(1000, 100),
+		finishedRun(1200, 240),
+		finishedRun(900, 90),
+		finishedRun(100000, 1000), // far from 1000 rows, scales to 10ms
+		{Referee: "permutation", SampleSize: 1000, Status: RefereeRunRunning},
+	}
+	// Similar runs scaled to 1000 rows: 100, 200 and 100ms
+	if got, ok := EstimateRefereeRuntime(history, 1000); !ok || got != 100*time.Millisecond {
+		t.Errorf("estimate = %v, %v", got, ok)
+	}
+	// Only the large run is similar to 80000 rows
+	if got, _ := EstimateRefereeRuntime(history, 80000); got != 800*time.Millisecond {
+		t.Errorf("estimate for 80000 rows = %v", got)
+	}
+	// Nothing is similar to 10 rows, so every run counts, scaled: 1, 2, 1 and 0.1ms
+	if got, _ := EstimateRefereeRuntime(history, 10); got != time.Millisecond {
+		t.Errorf("estimate for 10 rows = %v", got)
+	}
+}
+
+// TestValidationProgressETA verifies the ETA is the longest remaining estimate of the
+// referees still to finish
+func TestValidationProgressETA(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ten, thirty, five := 10.0, 30.0, 5.0
+	progress := ValidationProgress{
+		StartedAt: start,
+		Total:     4,
+		Completed: 1,
+		Referees: []RefereeProgress{
+			{Referee: "done", Status: RefereeRunCompleted, EstimatedSeconds: &thirty},
+			{Referee: "running", Status: RefereeRunRunning, StartedAt: &start, EstimatedSeconds: &ten},
+			{Referee: "pending", Status: RefereeRunPending, EstimatedSeconds: &five},
+			{Referee: "unknown", Status: RefereeRunPending},
+		},
+	}
+	progress.UpdateETA(start.Add(4 * time.Second))
+	if progress.ETASeconds == nil || *progress.ETASeconds != 6 || progress.ElapsedSeconds != 4 {
+		t.Fatalf("eta = %v, elapsed = %v", progress.ETASeconds, progress.ElapsedSeconds)
+	}
+	if progress.Percent() != 25 {
+		t.Errorf("percent = %v", progress.Percent())
+	}
+
+	// An overrunning referee counts as about to finish
+	progress.Referees[2].Status = RefereeRunCompleted
+	progress.UpdateETA(start.Add(20 * time.Second))
+	if progress.ETASeconds == nil || *progress.ETASeconds != 0 {
+		t.Errorf("eta after overrun = %v", progress.ETASeconds)
+	}
+}
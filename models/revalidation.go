@@ -0,0 +1,92 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// EngineVersionsKey is the execution metadata key under which a verdict keeps the versions of
+// the referees and senses that reached it, keyed "referee/<gate>" and "sense/<name>"
+const EngineVersionsKey = "engine_versions"
+
+// EngineVersionsOf returns the engine versions recorded with a hypothesis' verdict; nil for
+// hypotheses validated before versions were recorded
+func EngineVersionsOf(h *HypothesisResult) map[string]string {
+	switch versions := h.ExecutionMetadata[EngineVersionsKey].(type) {
+	case map[string]string:
+		return versions
+	case map[string]interface{}:
+		out := make(map[string]string, len(versions))
+		for name, v := range versions {
+			if version, ok := v.(string); ok {
+				out[name] = version
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// StaleHypothesis is a hypothesis whose verdict was reached by referees or senses that have
+// changed since, with what changed and what re-validating it is expected to cost
+type StaleHypothesis struct {
+	HypothesisID     string    `json:"hypothesis_id"`
+	Title            string    `json:"title"`
+	Passed           bool      `json:"passed"`
+	Confidence       float64   `json:"confidence"`
+	ValidatedAt      time.Time `json:"validated_at"`
+	Changed          []string  `json:"changed"` // "referee/<gate> 1.0.0 → 1.1.0"; empty when versions were never recorded
+	Unversioned      bool      `json:"unversioned,omitempty"`
+	Pending          bool      `json:"pending,omitempty"` // already queued or running
+	EstimatedSeconds float64   `json:"estimated_seconds"`
+	Unestimated      int       `json:"unestimated_referees,omitempty"` // referees without runtime history
+}
+
+// RevalidationPlan is a workspace's stale hypotheses in the order they should be re-validated,
+// with the estimated cost of re-validating them all
+type RevalidationPlan struct {
+	WorkspaceID string            `json:"workspace_id"`
+	Scanned     int               `json:"scanned"`
+	Hypotheses  []StaleHypothesis `json:"hypotheses"`
+	Capacity    int               `json:"capacity"`
+	// Referee time summed over the hypotheses, and the wall time at the workspace's capacity
+	EstimatedSeconds     float64 `json:"estimated_seconds"`
+	EstimatedWallSeconds float64 `json:"estimated_wall_seconds"`
+	Unestimated          int     `json:"unestimated_referees,omitempty"`
+}
+
+// Add appends a stale hypothesis and counts its cost
+func (p *RevalidationPlan) Add(h StaleHypothesis) {
+	p.Hypotheses = append(p.Hypotheses, h)
+	p.EstimatedSeconds += h.EstimatedSeconds
+	p.Unestimated += h.Unestimated
+	if p.Capacity > 0 {
+		p.EstimatedWallSeconds = p.EstimatedSeconds / float64(p.Capacity)
+	}
+}
+
+// Prioritize orders the plan: validated findings first, since decisions may rest on them,
+// then by confidence and most recent validation
+func (p *RevalidationPlan) Prioritize() {
+	sort.SliceStable(p.Hypotheses, func(i, j int) bool {
+		a, b := p.Hypotheses[i], p.Hypotheses[j]
+		if a.Passed != b.Passed {
+			return a.Passed
+		}
+		if a.Confidence != b.Confidence {
+			return a.Confidence > b.Confidence
+		}
+		return a.ValidatedAt.After(b.ValidatedAt)
+	})
+}
+
+// IDs lists the plan's hypotheses in priority order, leaving out those already pending
+func (p *RevalidationPlan) IDs() []string {
+	ids := make([]string, 0, len(p.Hypotheses))
+	for _, h := range p.Hypotheses {
+		if !h.Pending {
+			ids = append(ids, h.HypothesisID)
+		}
+	}
+	return ids
+}
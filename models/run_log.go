@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// RunLogEntry is one stored log line of a run: a stage message, a warning or the reason a
+// step was skipped, with the structured attributes it was logged with
+type RunLogEntry struct {
+	RunID    string    `json:"run_id" db:"run_id"`
+	Seq      int64     `json:"seq" db:"seq"` // order of the line within its run
+	Time     time.Time `json:"time" db:"logged_at"`
+	Level    string    `json:"level" db:"level"`
+	Severity int       `json:"-" db:"severity"` // numeric slog level, for filtering by level
+	Message  string    `json:"message" db:"message"`
+	Attrs    JSONBMap  `json:"attrs,omitempty" db:"attrs"`
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// SummarySignificanceLevel is the q-value below which a run summary counts a relationship
+// as significant
+const SummarySignificanceLevel = 0.05
+
+// DefaultTopEffects is how many of a run's strongest relationships its summary keeps
+const DefaultTopEffects = 20
+
+// RunSummary is the materialized aggregate of one sweep run, written once when the sweep
+// completes so pages and the CLI can show it without scanning the run's artifacts
+type RunSummary struct {
+	RunID             string    `json:"run_id" db:"run_id"`
+	SessionID         *string   `json:"session_id,omitempty" db:"session_id"`
+	WorkspaceID       *string   `json:"workspace_id,omitempty" db:"workspace_id"`
+	DatasetID         *string   `json:"dataset_id,omitempty" db:"dataset_id"` // uploaded dataset swept; nil for the built-in one
+	SnapshotID        string    `json:"snapshot_id" db:"snapshot_id"`
+	FDRMethod         string    `json:"fdr_method" db:"fdr_method"`
+	VariablesAnalyzed int       `json:"variables_analyzed" db:"variables_analyzed"`
+	Relationships     int       `json:"relationships" db:"relationships"`
+	SignificantPairs  int       `json:"significant_pairs" db:"significant_pairs"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+
+	TestTypes  []RunTestTypeCount `json:"test_types" db:"-"`
+	TopEffects []RunEffect        `json:"top_effects" db:"-"`
+}
+
+// RunTestTypeCount counts a run's relationships of one test type
+type RunTestTypeCount struct {
+	RunID         string `json:"-" db:"run_id"`
+	TestType      string `json:"test_type" db:"test_type"`
+	Relationships int    `json:"relationships" db:"relationships"`
+	Significant   int    `json:"significant" db:"significant"`
+}
+
+// RunEffect is one relationship of a run, ranked among its strongest
+type RunEffect struct {
+	RunID      string  `json:"-" db:"run_id"`
+	Rank       int     `json:"rank" db:"rank"`
+	VariableX  string  `json:"variable_x" db:"variable_x"`
+	VariableY  string  `json:"variable_y" db:"variable_y"`
+	TestType   string  `json:"test_type" db:"test_type"`
+	EffectSize float64 `json:"effect_size" db:"effect_size"`
+	PValue     float64 `json:"p_value" db:"p_value"`
+	QValue     float64 `json:"q_value" db:"q_value"`
+	SampleSize int     `json:"sample_size" db:"sample_size"`
+
+	// NoisePercentile places the effect against the workspace's noise floor, when the run
+	// had one calibrated
+	NoisePercentile *float64 `json:"noise_percentile,omitempty" db:"noise_percentile"`
+}
+
+// Significant reports whether the relationship survives FDR correction
+func (e RunEffect) Significant() bool {
+	return e.QValue < SummarySignificanceLevel
+}
+
+// Summarize fills the summary's counts from all of a run's relationships and keeps the
+// topN strongest: significant relationships first, then by absolute effect size. Test
+// types are listed by name.
+func (s *RunSummary) Summarize(effects []RunEffect, topN int) {
+	s.Relationships = len(effects)
+	s.SignificantPairs = 0
+	counts := map[string]*RunTestTypeCount{}
+	for _, effect := range effects {
+		count, ok := counts[effect.TestType]
+		if !ok {
+			count = &RunTestTypeCount{RunID: s.RunID, TestType: effect.TestType}
+			counts[effect.TestType] = count
+		}
+		count.Relationships++
+		if effect.Significant() {
+			count.Significant++
+			s.SignificantPairs++
+		}
+	}
+	s.TestTypes = make([]RunTestTypeCount, 0, len(counts))
+	for _, count := range counts {
+		s.TestTypes = append(s.TestTypes, *count)
+	}
+	sort.Slice(s.TestTypes, func(i, j int) bool { return s.TestTypes[i].TestType < s.TestTypes[j].TestType })
+
+	ranked := append([]RunEffect(nil), effects...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Significant() != ranked[j].Significant() {
+			return ranked[i].Significant()
+		}
+		return math.Abs(ranked[i].EffectSize) > math.Abs(ranked[j].EffectSize)
+	})
+	if topN >= 0 && len(ranked) > topN {
+		ranked = ranked[:topN]
+	}
+	for i := range ranked {
+		ranked[i].RunID = s.RunID
+		ranked[i].Rank = i + 1
+	}
+	s.TopEffects = ranked
+}
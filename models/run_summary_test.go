@@ -0,0 +1,38 @@
+package models
+
+import "testing"
+
+// TestRunSummarySummarize verifies counts by test type, the significant pair count and the
+// ranking of top effects
+func TestRunSummarySummarize(t *testing.T) {
+	effects := []RunEffect{
+		{VariableX: "a", VariableY: "b", TestType: "pearson", EffectSize: 0.9, QValue: 0.2},
+		{VariableX: "a", VariableY: "c", TestType: "pearson", EffectSize: -0.4, QValue: 0.01},
+		{VariableX: "b", VariableY: "c", TestType: "pearson", EffectSize: 0.6, QValue: 0.001},
+		{VariableX: "region", VariableY: "c", TestType: "anova", EffectSize: 0.3, QValue: 0.04},
+	}
+	summary := &RunSummary{RunID: "sweep-1"}
+	summary.Summarize(effects, 3)
+
+	if summary.Relationships != 4 || summary.SignificantPairs != 3 {
+		t.Fatalf("relationships = %d, significant = %d", summary.Relationships, summary.SignificantPairs)
+	}
+	if len(summary.TestTypes) != 2 || summary.TestTypes[0].TestType != "anova" || summary.TestTypes[1].Relationships != 3 || summary.TestTypes[1].Significant != 2 {
+		t.Fatalf("test types = %+v", summary.TestTypes)
+	}
+
+	// Significant relationships rank first, by absolute effect size; the strongest
+	// insignificant one is cut by topN
+	want := []string{"b~c", "a~c", "region~c"}
+	if len(summary.TopEffects) != len(want) {
+		t.Fatalf("top effects = %+v", summary.TopEffects)
+	}
+	for i, effect := range summary.TopEffects {
+		if effect.VariableX+"~"+effect.VariableY != want[i] || effect.Rank != i+1 || effect.RunID != "sweep-1" {
+			t.Errorf("top effect %d = %+v, want %s", i, effect, want[i])
+		}
+	}
+	if effects[0].Rank != 0 {
+		t.Error("summarizing must not modify the caller's effects")
+	}
+}
@@ -0,0 +1,14 @@
+package models
+
+// DecisionRigorSamples is how many generations decision-rigor runs sample for
+// self-consistency when the caller does not choose
+const DecisionRigorSamples = 5
+
+// SelfConsistency records how many independent generations proposed a hypothesis. A
+// cause/effect pair that most samples arrive at is less likely to be a one-off confabulation.
+type SelfConsistency struct {
+	Score    float64 `json:"score"`    // Agreeing / Samples
+	Agreeing int     `json:"agreeing"` // samples that proposed this cause/effect pair
+	Samples  int     `json:"samples"`  // samples that returned a usable answer
+	Variants int     `json:"variants"` // distinct pairs proposed across all samples
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Share link lifetimes: links expire after DefaultShareLinkTTL unless asked otherwise, and
+// never later than MaxShareLinkTTL after creation
+const (
+	DefaultShareLinkTTL = 7 * 24 * time.Hour
+	MaxShareLinkTTL     = 90 * 24 * time.Hour
+)
+
+// ShareLinksEnabledKey is the workspace metadata flag that allows public share links. Turning
+// it off disables every existing link of the workspace without revoking them.
+const ShareLinksEnabledKey = "share_links_enabled"
+
+// ShareLink grants read-only access to one hypothesis' validation page without logging in.
+// Only the token's hash is stored; the token itself is shown once, when the link is created.
+type ShareLink struct {
+	ID           string     `json:"id" db:"id"`
+	Token        string     `json:"token,omitempty" db:"-"`
+	TokenHash    string     `json:"-" db:"token_hash"`
+	WorkspaceID  string     `json:"workspace_id" db:"workspace_id"`
+	HypothesisID string     `json:"hypothesis_id" db:"hypothesis_id"`
+	CreatedBy    string     `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt    time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// NewShareLink creates a link to a hypothesis expiring after ttl, capped at MaxShareLinkTTL,
+// with a fresh random token
+func NewShareLink(workspaceID, hypothesisID string, ttl time.Duration) (*ShareLink, error) {
+	if hypothesisID == "" {
+		return nil, fmt.Errorf("hypothesis_id is required")
+	}
+	if ttl <= 0 {
+		ttl = DefaultShareLinkTTL
+	}
+	if ttl > MaxShareLinkTTL {
+		return nil, fmt.Errorf("share links expire after at most %d days", int(MaxShareLinkTTL.Hours()/24))
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	now := time.Now()
+	return &ShareLink{
+		Token:        token,
+		TokenHash:    HashShareToken(token),
+		WorkspaceID:  workspaceID,
+		HypothesisID: hypothesisID,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(ttl),
+	}, nil
+}
+
+// HashShareToken is the stored form of a share token
+func HashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Active reports whether the link still grants access at the given time
+func (l *ShareLink) Active(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt)
+}
+
+// ValidationFingerprint identifies the validation a page shows: the hypothesis, its verdict
+// and every referee outcome. It is watermarked on shared pages so a copy or screenshot can be
+// traced back to the exact result it was taken from.
+func ValidationFingerprint(h *HypothesisResult) string {
+	type refereeOutcome struct {
+		Gate      string  `json:"gate"`
+		Passed    bool    `json:"passed"`
+		Statistic float64 `json:"statistic"`
+		PValue    float64 `json:"p_value"`
+		EValue    float64 `json:"e_value"`
+	}
+	outcomes := make([]refereeOutcome, 0, len(h.RefereeResults))
+	for _, r := range h.RefereeResults {
+		outcomes = append(outcomes, refereeOutcome{r.GateName, r.Passed, r.Statistic, r.PValue, r.EValue})
+	}
+	data, _ := json.Marshal(struct {
+		ID        string           `json:"id"`
+		Passed    bool             `json:"passed"`
+		Validated time.Time        `json:"validated"`
+		Standards string           `json:"standards"`
+		Referees  []refereeOutcome `json:"referees"`
+	}{h.ID, h.Passed, h.ValidationTimestamp.UTC(), h.StandardsVersion, outcomes})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// TestShareLinkLifecycle verifies tokens are stored hashed, expiry is capped and revocation
+// ends access
+func TestShareLinkLifecycle(t *testing.T) {
+	link, err := NewShareLink("ws-1", "HYP-001", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link.Token == "" || link.TokenHash != HashShareToken(link.Token) || link.TokenHash == link.Token {
+		t.Fatalf("expected a hashed random token, got %+v", link)
+	}
+	if got := link.ExpiresAt.Sub(link.CreatedAt); got != DefaultShareLinkTTL {
+		t.Fatalf("expected the default lifetime, got %v", got)
+	}
+	if !link.Active(time.Now()) || link.Active(link.ExpiresAt) {
+		t.Fatal("expected the link to be active until it expires")
+	}
+	revoked := time.Now()
+	link.RevokedAt = &revoked
+	if link.Active(time.Now()) {
+		t.Fatal("expected a revoked link to be inactive")
+	}
+
+	if _, err := NewShareLink("ws-1", "HYP-001", MaxShareLinkTTL+time.Hour); err == nil {
+		t.Fatal("expected lifetimes beyond the maximum to be rejected")
+	}
+	other, _ := NewShareLink("ws-1", "HYP-001", time.Hour)
+	if other.Token == link.Token {
+		t.Fatal("expected distinct tokens")
+	}
+}
+
+// TestValidationFingerprintTracksVerdict verifies the watermark changes with the result shown
+func TestValidationFingerprintTracksVerdict(t *testing.T) {
+	h := &HypothesisResult{
+		ID:             "HYP-001",
+		Passed:         true,
+		RefereeResults: []RefereeResult{{GateName: "Permutation_Shuffling", Passed: true, PValue: 0.001}},
+	}
+	fingerprint := ValidationFingerprint(h)
+	if len(fingerprint) != 16 || ValidationFingerprint(h) != fingerprint {
+		t.Fatalf("expected a stable 16-character fingerprint, got %q", fingerprint)
+	}
+	h.RefereeResults[0].PValue = 0.2
+	if ValidationFingerprint(h) == fingerprint {
+		t.Fatal("expected a different referee outcome to change the fingerprint")
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gohypo/domain/stats"
+)
+
+// SkepticModeKey is the execution metadata key a hypothesis' latest skeptic-mode
+// re-analysis is stored under
+const SkepticModeKey = "skeptic_mode"
+
+// SkepticRun is a skeptic-mode re-analysis of a hypothesis' cause and effect, with the data it
+// ran on
+type SkepticRun struct {
+	At        time.Time            `json:"at"`
+	CauseKey  string               `json:"cause_key"`
+	EffectKey string               `json:"effect_key"`
+	Policy    stats.AnalysisPolicy `json:"policy"`
+	Report    *stats.SkepticReport `json:"report"`
+	// Where the run was stored in the ledger; nil when the ledger is unavailable
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// SkepticRunOf returns a hypothesis' latest skeptic-mode re-analysis, if it has one
+func SkepticRunOf(h *HypothesisResult) (*SkepticRun, bool) {
+	stored, ok := h.ExecutionMetadata[SkepticModeKey]
+	if !ok {
+		return nil, false
+	}
+	if run, ok := stored.(*SkepticRun); ok {
+		return run, true
+	}
+	var run SkepticRun
+	if data, err := json.Marshal(stored); err != nil || json.Unmarshal(data, &run) != nil || run.Report == nil {
+		return nil, false
+	}
+	return &run, true
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gohypo/domain/stats"
+)
+
+// TestSkepticRunOfDecodesStoredRun reads a skeptic-mode run back after the hypothesis'
+// metadata went through JSON, as it does in storage
+func TestSkepticRunOfDecodesStoredRun(t *testing.T) {
+	h := &HypothesisResult{ExecutionMetadata: map[string]interface{}{}}
+	if _, ok := SkepticRunOf(h); ok {
+		t.Fatal("expected no run on a fresh hypothesis")
+	}
+
+	h.ExecutionMetadata[SkepticModeKey] = &SkepticRun{
+		At:        time.Unix(1_700_000_000, 0).UTC(),
+		CauseKey:  "discount",
+		EffectKey: "basket_size",
+		Policy:    stats.DefaultAnalysisPolicy(),
+		Report:    &stats.SkepticReport{SampleSize: 120, Survives: true, After: stats.SkepticEstimate{Effect: 0.41}},
+	}
+	data, err := json.Marshal(h.ExecutionMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := &HypothesisResult{}
+	if err := json.Unmarshal(data, &stored.ExecutionMetadata); err != nil {
+		t.Fatal(err)
+	}
+
+	run, ok := SkepticRunOf(stored)
+	if !ok {
+		t.Fatal("expected the stored run to decode")
+	}
+	if run.CauseKey != "discount" || !run.Report.Survives || run.Report.After.Effect != 0.41 || run.Policy != stats.DefaultAnalysisPolicy() {
+		t.Fatalf("unexpected run %+v", run)
+	}
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SQL data source defaults and bounds
+const (
+	DefaultSQLDriver         = "postgres"
+	DefaultSQLSourceRows     = 100000
+	MaxSQLSourceRows         = 1000000
+	MinSourceRefreshInterval = 5 // minutes, for SQL and URL sources alike
+)
+
+// SQLDataSource is a warehouse table or query pulled straight into a workspace as a dataset,
+// optionally on a schedule. The DSN may be given as env:NAME to keep credentials out of the
+// database; it is never returned by the API, see Redacted.
+type SQLDataSource struct {
+	ID              string     `json:"id" db:"id"`
+	WorkspaceID     string     `json:"workspace_id" db:"workspace_id"`
+	UserID          string     `json:"user_id" db:"user_id"`
+	Name            string     `json:"name" db:"name"`
+	Driver          string     `json:"driver" db:"driver"`
+	DSN             string     `json:"dsn" db:"dsn"`
+	Query           string     `json:"query,omitempty" db:"query"`
+	Table           string     `json:"table,omitempty" db:"table_name"`
+	MaxRows         int        `json:"max_rows" db:"max_rows"`
+	RefreshMinutes  int        `json:"refresh_minutes" db:"refresh_minutes"` // 0 = refreshed on request only
+	LastRefreshedAt *time.Time `json:"last_refreshed_at,omitempty" db:"last_refreshed_at"`
+	LastDatasetID   string     `json:"last_dataset_id,omitempty" db:"last_dataset_id"`
+	LastError       string     `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// tableNamePattern accepts a table name, optionally schema-qualified
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)?$`)
+
+// Validate fills in the driver and row limit defaults and checks the source names exactly
+// one of a query or a table, and a DSN variable the operator allowed
+func (s *SQLDataSource) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(s.DSN) == "" {
+		return fmt.Errorf("dsn is required")
+	}
+	if s.Driver == "" {
+		s.Driver = DefaultSQLDriver
+	}
+	s.Query = strings.TrimRight(strings.TrimSpace(s.Query), "; \n\t")
+	s.Table = strings.TrimSpace(s.Table)
+	switch {
+	case s.Query == "" && s.Table == "":
+		return fmt.Errorf("either query or table is required")
+	case s.Query != "" && s.Table != "":
+		return fmt.Errorf("give either query or table, not both")
+	case s.Table != "" && !tableNamePattern.MatchString(s.Table):
+		return fmt.Errorf("table must be a table name, optionally schema-qualified, got %q", s.Table)
+	case s.Query != "" && strings.Contains(s.Query, ";"):
+		return fmt.Errorf("query must be a single statement")
+	}
+	if s.MaxRows == 0 {
+		s.MaxRows = DefaultSQLSourceRows
+	}
+	if s.MaxRows < 0 || s.MaxRows > MaxSQLSourceRows {
+		return fmt.Errorf("max_rows must be between 1 and %d, got %d", MaxSQLSourceRows, s.MaxRows)
+	}
+	if s.RefreshMinutes < 0 || (s.RefreshMinutes > 0 && s.RefreshMinutes < MinSourceRefreshInterval) {
+		return fmt.Errorf("refresh_minutes must be 0 or at least %d, got %d", MinSourceRefreshInterval, s.RefreshMinutes)
+	}
+	return CheckSecret(s.DSN)
+}
+
+// Due reports whether a scheduled source should be pulled again at now
+func (s *SQLDataSource) Due(now time.Time) bool {
+	if s.RefreshMinutes <= 0 {
+		return false
+	}
+	return s.LastRefreshedAt == nil || now.Sub(*s.LastRefreshedAt) >= time.Duration(s.RefreshMinutes)*time.Minute
+}
+
+// ResolveDSN returns the connection string, reading it from the environment for env:NAME
+func (s *SQLDataSource) ResolveDSN() (string, error) {
+	return ResolveSecret(s.DSN)
+}
+
+// secretEnvNames are the environment variables a credential may reference as env:NAME.
+// Sources are created by users, so a reference to any other variable is refused: env:NAME
+// would otherwise send the server's own secrets to a URL or database the user chose.
+var (
+	secretEnvMu    sync.RWMutex
+	secretEnvNames = map[string]bool{}
+)
+
+// AllowSecretEnv sets the environment variables credentials may reference as env:NAME,
+// replacing any set before. The operator names them in SECRET_ENV_NAMES.
+func AllowSecretEnv(names []string) {
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			allowed[name] = true
+		}
+	}
+	secretEnvMu.Lock()
+	secretEnvNames = allowed
+	secretEnvMu.Unlock()
+}
+
+// CheckSecret rejects a credential referencing an environment variable the operator has not
+// allowed; literal credentials are accepted
+func CheckSecret(value string) error {
+	name, fromEnv := strings.CutPrefix(value, "env:")
+	if !fromEnv {
+		return nil
+	}
+	secretEnvMu.RLock()
+	allowed := secretEnvNames[name]
+	secretEnvMu.RUnlock()
+	if !allowed {
+		return fmt.Errorf("environment variable %s is not allowed for credentials, see SECRET_ENV_NAMES", name)
+	}
+	return nil
+}
+
+// ResolveSecret returns a credential given either literally or as env:NAME, read from the
+// environment when the operator allowed NAME
+func ResolveSecret(value string) (string, error) {
+	if err := CheckSecret(value); err != nil {
+		return "", err
+	}
+	name, fromEnv := strings.CutPrefix(value, "env:")
+	if !fromEnv {
+		return value, nil
+	}
+	secret := os.Getenv(name)
+	if secret == "" {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return secret, nil
+}
+
+// Redacted returns a copy of the source safe to show, with the DSN's password masked
+func (s *SQLDataSource) Redacted() *SQLDataSource {
+	redacted := *s
+	redacted.DSN = RedactDSN(s.DSN)
+	return &redacted
+}
+
+// dsnPasswordPattern finds the password of a key=value connection string
+var dsnPasswordPattern = regexp.MustCompile(`(?i)(password\s*=\s*)('[^']*'|\S+)`)
+
+// RedactDSN masks the password of a URL or key=value connection string. Environment
+// references are returned as given.
+func RedactDSN(dsn string) string {
+	if strings.HasPrefix(dsn, "env:") {
+		return dsn
+	}
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" && u.Host != "" {
+		if query := u.Query(); query.Has("password") {
+			query.Set("password", "xxxxx")
+			u.RawQuery = query.Encode()
+		}
+		return u.Redacted()
+	}
+	return dsnPasswordPattern.ReplaceAllString(dsn, "${1}xxxxx")
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StatusWidgetTokenKey is the workspace metadata entry holding the hash of the status widget
+// token. Removing it, or issuing a new token, takes every embedded widget of the old token offline.
+const StatusWidgetTokenKey = "status_widget_token_hash"
+
+// NewStatusWidgetToken creates a token for a workspace's status widget. The token carries the
+// workspace ID so it can be checked against that workspace's stored hash without a lookup table.
+func NewStatusWidgetToken(workspaceID string) (token, hash string, err error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", fmt.Errorf("failed to generate widget token: %w", err)
+	}
+	token = workspaceID + "." + base64.RawURLEncoding.EncodeToString(raw)
+	return token, HashShareToken(token), nil
+}
+
+// StatusWidgetWorkspace returns the workspace a status widget token belongs to
+func StatusWidgetWorkspace(token string) (string, bool) {
+	workspaceID, secret, ok := strings.Cut(token, ".")
+	if !ok || workspaceID == "" || secret == "" {
+		return "", false
+	}
+	return workspaceID, true
+}
+
+// RunStatus is what the embeddable widget shows of a workspace's latest research run: its
+// stage, progress and estimated finish, but not the hypotheses being worked on
+type RunStatus struct {
+	SessionID      string       `json:"session_id,omitempty"`
+	Stage          SessionState `json:"stage"`
+	Progress       float64      `json:"progress"` // percent
+	CompletedCount int          `json:"completed_count"`
+	StartedAt      *time.Time   `json:"started_at,omitempty"`
+	CompletedAt    *time.Time   `json:"completed_at,omitempty"`
+	ETASeconds     *int         `json:"eta_seconds,omitempty"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// NewRunStatus summarizes a session for the widget; a nil session is an idle workspace. The
+// ETA extrapolates the elapsed time at the current progress and is only given while a run is
+// active and has made progress.
+func NewRunStatus(session *ResearchSession, now time.Time) RunStatus {
+	if session == nil {
+		return RunStatus{Stage: SessionStateIdle, UpdatedAt: now}
+	}
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+
+	run := RunStatus{
+		SessionID:      session.ID.String(),
+		Stage:          session.State,
+		Progress:       session.Progress,
+		CompletedCount: len(session.CompletedHypotheses),
+		CompletedAt:    session.CompletedAt,
+		UpdatedAt:      now,
+	}
+	startedAt, progress := session.StartedAt, session.Progress
+	if !startedAt.IsZero() {
+		run.StartedAt = &startedAt
+	}
+	active := session.State == SessionStateAnalyzing || session.State == SessionStateValidating
+	if active && progress > 0 && progress < 100 && !startedAt.IsZero() {
+		elapsed := now.Sub(startedAt)
+		eta := int((time.Duration(float64(elapsed) * (100 - progress) / progress)).Seconds())
+		run.ETASeconds = &eta
+	}
+	return run
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestStatusWidgetTokenCarriesWorkspace(t *testing.T) {
+	token, hash, err := NewStatusWidgetToken("ws-1")
+	if err != nil {
+		t.Fatalf("NewStatusWidgetToken: %v", err)
+	}
+	if workspaceID, ok := StatusWidgetWorkspace(token); !ok || workspaceID != "ws-1" {
+		t.Errorf("StatusWidgetWorkspace(%q) = %q, %v", token, workspaceID, ok)
+	}
+	if hash != HashShareToken(token) || strings.Contains(hash, "ws-1") {
+		t.Errorf("unexpected token hash %q", hash)
+	}
+	if _, ok := StatusWidgetWorkspace("no-secret"); ok {
+		t.Error("token without a secret accepted")
+	}
+}
+
+// TestRunStatusETA verifies the ETA extrapolates elapsed time while a run is active and is
+// withheld once it finishes
+func TestRunStatusETA(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	session := NewResearchSession(uuid.New(), uuid.New(), nil)
+	session.StartedAt = now.Add(-10 * time.Minute)
+	session.SetState(SessionStateValidating)
+	session.UpdateProgress(25, "E-value Validating: H1 - revenue drives churn")
+
+	status := NewRunStatus(session, now)
+	if status.ETASeconds == nil || *status.ETASeconds != 1800 {
+		t.Fatalf("expected a 30 minute ETA, got %+v", status.ETASeconds)
+	}
+	if status.Stage != SessionStateValidating || status.Progress != 25 {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	session.SetState(SessionStateComplete)
+	if status := NewRunStatus(session, now); status.ETASeconds != nil || status.CompletedAt == nil {
+		t.Errorf("finished run should have a completion time and no ETA, got %+v", status)
+	}
+	if idle := NewRunStatus(nil, now); idle.Stage != SessionStateIdle {
+		t.Errorf("nil session should be idle, got %q", idle.Stage)
+	}
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Lists an analyst can triage
+const (
+	TriageListHypotheses    = "hypotheses"
+	TriageListRelationships = "relationships"
+)
+
+// Triage states of a list item; items without a mark are pending
+const (
+	TriagePending   = "pending"
+	TriageApproved  = "approved"
+	TriageDismissed = "dismissed"
+)
+
+// MaxTriageBatch bounds how many items one bulk action may mark
+const MaxTriageBatch = 1000
+
+// ValidTriageList reports whether list names a triageable list
+func ValidTriageList(list string) bool {
+	return list == TriageListHypotheses || list == TriageListRelationships
+}
+
+// ValidTriageStatus reports whether status is a triage state
+func ValidTriageStatus(status string) bool {
+	return status == TriagePending || status == TriageApproved || status == TriageDismissed
+}
+
+// TriageMark is an analyst's verdict on one item of a workspace list
+type TriageMark struct {
+	WorkspaceID string    `json:"workspace_id" db:"workspace_id"`
+	List        string    `json:"list" db:"list"`
+	ItemID      string    `json:"item_id" db:"item_id"`
+	Status      string    `json:"status" db:"status"`
+	MarkedBy    string    `json:"marked_by" db:"marked_by"`
+	MarkedAt    time.Time `json:"marked_at" db:"marked_at"`
+}
+
+// TriageItem is one row of a triage list: a hypothesis or a dataset relationship reduced to
+// what views filter and sort on
+type TriageItem struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Kind       string    `json:"kind,omitempty"` // relation type for relationships
+	Confidence float64   `json:"confidence"`
+	Passed     *bool     `json:"passed,omitempty"` // hypotheses only
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	// Queued or running for re-validation; the verdict shown may change (hypotheses only)
+	PendingRevalidation bool `json:"pending_revalidation,omitempty"`
+}
+
+// Sort orders of a view
+const (
+	ViewSortNewest     = "newest"
+	ViewSortConfidence = "confidence"
+)
+
+// ViewFilters narrow and order a triage list
+type ViewFilters struct {
+	Status        []string `json:"status,omitempty"`
+	MinConfidence float64  `json:"min_confidence,omitempty"`
+	Passed        *bool    `json:"passed,omitempty"`
+	Kinds         []string `json:"kinds,omitempty"`
+	Search        string   `json:"search,omitempty"`
+	Sort          string   `json:"sort,omitempty"`
+}
+
+// Value implements driver.Valuer interface
+func (f ViewFilters) Value() (driver.Value, error) {
+	return json.Marshal(f)
+}
+
+// Scan implements sql.Scanner interface
+func (f *ViewFilters) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		*f = ViewFilters{}
+		return nil
+	}
+	return json.Unmarshal(bytes, f)
+}
+
+// Validate checks the filters name known states and sort orders
+func (f ViewFilters) Validate() error {
+	for _, status := range f.Status {
+		if !ValidTriageStatus(status) {
+			return fmt.Errorf("unknown triage status %q", status)
+		}
+	}
+	if f.MinConfidence < 0 || f.MinConfidence > 1 {
+		return fmt.Errorf("min_confidence must be between 0 and 1")
+	}
+	if f.Sort != "" && f.Sort != ViewSortNewest && f.Sort != ViewSortConfidence {
+		return fmt.Errorf("sort must be %q or %q", ViewSortNewest, ViewSortConfidence)
+	}
+	return nil
+}
+
+// Matches reports whether an item passes the filters
+func (f ViewFilters) Matches(item TriageItem) bool {
+	if len(f.Status) > 0 && !containsString(f.Status, item.Status) {
+		return false
+	}
+	if item.Confidence < f.MinConfidence {
+		return false
+	}
+	if f.Passed != nil && (item.Passed == nil || *item.Passed != *f.Passed) {
+		return false
+	}
+	if len(f.Kinds) > 0 && !containsString(f.Kinds, item.Kind) {
+		return false
+	}
+	if f.Search != "" && !strings.Contains(strings.ToLower(item.Title), strings.ToLower(f.Search)) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the matching items in the view's order; newest first unless sorted by confidence
+func (f ViewFilters) Apply(items []TriageItem) []TriageItem {
+	matched := make([]TriageItem, 0, len(items))
+	for _, item := range items {
+		if f.Matches(item) {
+			matched = append(matched, item)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if f.Sort == ViewSortConfidence && matched[i].Confidence != matched[j].Confidence {
+			return matched[i].Confidence > matched[j].Confidence
+		}
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+	return matched
+}
+
+// SavedView is a named set of filters one user keeps for a triage list, in any workspace
+type SavedView struct {
+	ID        string      `json:"id" db:"id"`
+	UserID    string      `json:"user_id" db:"user_id"`
+	List      string      `json:"list" db:"list"`
+	Name      string      `json:"name" db:"name"`
+	Filters   ViewFilters `json:"filters" db:"filters"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+}
+
+// Validate checks the view can be saved
+func (v *SavedView) Validate() error {
+	if strings.TrimSpace(v.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !ValidTriageList(v.List) {
+		return fmt.Errorf("list must be %q or %q", TriageListHypotheses, TriageListRelationships)
+	}
+	return v.Filters.Validate()
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// TestViewFiltersApply verifies views narrow a triage list by status, confidence, verdict and
+// search text, and order it newest first or by confidence
+func TestViewFiltersApply(t *testing.T) {
+	now := time.Now()
+	passed, failed := true, false
+	items := []TriageItem{
+		{ID: "h1", Title: "Discounts raise basket size", Confidence: 0.9, Passed: &passed, Status: TriagePending, CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "h2", Title: "Weather drives returns", Confidence: 0.4, Passed: &failed, Status: TriagePending, CreatedAt: now.Add(-time.Hour)},
+		{ID: "h3", Title: "Discounts lift churn", Confidence: 0.7, Passed: &passed, Status: TriageDismissed, CreatedAt: now},
+	}
+	ids := func(items []TriageItem) string {
+		s := ""
+		for _, item := range items {
+			s += item.ID + " "
+		}
+		return s
+	}
+
+	if got := ids(ViewFilters{}.Apply(items)); got != "h3 h2 h1 " {
+		t.Errorf("expected newest first, got %q", got)
+	}
+	if got := ids(ViewFilters{Sort: ViewSortConfidence}.Apply(items)); got != "h1 h3 h2 " {
+		t.Errorf("expected highest confidence first, got %q", got)
+	}
+	if got := ids(ViewFilters{Status: []string{TriagePending}, MinConfidence: 0.5}.Apply(items)); got != "h1 " {
+		t.Errorf("expected only the confident pending item, got %q", got)
+	}
+	if got := ids(ViewFilters{Search: "discounts", Passed: &passed}.Apply(items)); got != "h3 h1 " {
+		t.Errorf("expected the passing discount hypotheses, got %q", got)
+	}
+
+	view := SavedView{Name: "Pending", List: TriageListHypotheses, Filters: ViewFilters{Status: []string{"archived"}}}
+	if err := view.Validate(); err == nil {
+		t.Error("expected an unknown status to be rejected")
+	}
+	view.Filters.Status = []string{TriagePending}
+	view.List = "datasets"
+	if err := view.Validate(); err == nil {
+		t.Error("expected an unknown list to be rejected")
+	}
+}
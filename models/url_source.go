@@ -0,0 +1,160 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Kinds of remote file a URL data source fetches
+const (
+	URLSourceCSV          = "url"
+	URLSourceGoogleSheets = "google_sheets"
+)
+
+// URLDataSource is a CSV behind an HTTPS URL or a Google Sheet, fetched into a workspace as
+// a dataset and optionally re-fetched on a schedule. Sheets that are not public need an
+// OAuth access token, which may be given as env:NAME like a SQL source's DSN when NAME is
+// in SECRET_ENV_NAMES.
+type URLDataSource struct {
+	ID              string     `json:"id" db:"id"`
+	WorkspaceID     string     `json:"workspace_id" db:"workspace_id"`
+	UserID          string     `json:"user_id" db:"user_id"`
+	Name            string     `json:"name" db:"name"`
+	URL             string     `json:"url" db:"url"`
+	Kind            string     `json:"kind" db:"kind"`
+	Token           string     `json:"token,omitempty" db:"token"`
+	RefreshMinutes  int        `json:"refresh_minutes" db:"refresh_minutes"` // 0 = fetched on request only
+	LastRefreshedAt *time.Time `json:"last_refreshed_at,omitempty" db:"last_refreshed_at"`
+	LastDatasetID   string     `json:"last_dataset_id,omitempty" db:"last_dataset_id"`
+	LastChecksum    string     `json:"last_checksum,omitempty" db:"last_checksum"` // of the last file registered
+	LastError       string     `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Validate checks the URL is HTTPS, sets the kind from it and names the source after the
+// file when no name is given
+func (s *URLDataSource) Validate() error {
+	s.URL = strings.TrimSpace(s.URL)
+	_, kind, err := ResolveSourceURL(s.URL)
+	if err != nil {
+		return err
+	}
+	s.Kind = kind
+	if strings.TrimSpace(s.Name) == "" {
+		s.Name = DefaultURLSourceName(s.URL)
+	}
+	if s.RefreshMinutes < 0 || (s.RefreshMinutes > 0 && s.RefreshMinutes < MinSourceRefreshInterval) {
+		return fmt.Errorf("refresh_minutes must be 0 or at least %d, got %d", MinSourceRefreshInterval, s.RefreshMinutes)
+	}
+	return CheckSecret(s.Token)
+}
+
+// Due reports whether a scheduled source should be fetched again at now
+func (s *URLDataSource) Due(now time.Time) bool {
+	if s.RefreshMinutes <= 0 {
+		return false
+	}
+	return s.LastRefreshedAt == nil || now.Sub(*s.LastRefreshedAt) >= time.Duration(s.RefreshMinutes)*time.Minute
+}
+
+// Redacted returns a copy of the source safe to show, with a literal token masked
+func (s *URLDataSource) Redacted() *URLDataSource {
+	redacted := *s
+	if s.Token != "" && !strings.HasPrefix(s.Token, "env:") {
+		redacted.Token = "xxxxx"
+	}
+	redacted.URL = RedactURL(s.URL)
+	return &redacted
+}
+
+// sheetPathPattern matches the path of a Google Sheets document link
+var sheetPathPattern = regexp.MustCompile(`^/spreadsheets/d/([A-Za-z0-9_-]+)`)
+
+// sheetGIDPattern finds the tab of a sheet link, which may be in the query or the fragment
+var sheetGIDPattern = regexp.MustCompile(`gid=([0-9]+)`)
+
+// ResolveSourceURL returns the URL to fetch a source's CSV from, and the source's kind. A
+// Google Sheets link, as copied from the browser, becomes the CSV export of its tab;
+// published sheets are asked for CSV output. Only HTTPS URLs are accepted.
+func ResolveSourceURL(raw string) (string, string, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", "", fmt.Errorf("url must be an absolute HTTPS URL, got %q", raw)
+	}
+	if u.Scheme != "https" {
+		return "", "", fmt.Errorf("url must use HTTPS, got %s", u.Scheme)
+	}
+	if u.Host != "docs.google.com" || !strings.HasPrefix(u.Path, "/spreadsheets/") {
+		return u.String(), URLSourceCSV, nil
+	}
+
+	gid := ""
+	if match := sheetGIDPattern.FindStringSubmatch(u.RawQuery + "&" + u.Fragment); match != nil {
+		gid = match[1]
+	}
+	if strings.HasPrefix(u.Path, "/spreadsheets/d/e/") {
+		// Published to the web: the pub link serves CSV when asked
+		query := u.Query()
+		query.Set("output", "csv")
+		if gid != "" {
+			query.Set("gid", gid)
+		}
+		u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/pubhtml"), "/pub") + "/pub"
+		u.RawQuery, u.Fragment = query.Encode(), ""
+		return u.String(), URLSourceGoogleSheets, nil
+	}
+	match := sheetPathPattern.FindStringSubmatch(u.Path)
+	if match == nil {
+		return "", "", fmt.Errorf("unrecognised Google Sheets link %q", raw)
+	}
+	export := "https://docs.google.com/spreadsheets/d/" + match[1] + "/export?format=csv"
+	if gid != "" {
+		export += "&gid=" + gid
+	}
+	return export, URLSourceGoogleSheets, nil
+}
+
+// DefaultURLSourceName names a source after the file its URL points at, or the sheet
+func DefaultURLSourceName(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "remote_dataset"
+	}
+	if u.Host == "docs.google.com" && strings.HasPrefix(u.Path, "/spreadsheets/d/e/") {
+		return "published_google_sheet"
+	}
+	if match := sheetPathPattern.FindStringSubmatch(u.Path); match != nil && u.Host == "docs.google.com" {
+		return "google_sheet_" + match[1]
+	}
+	if base := path.Base(u.Path); base != "." && base != "/" {
+		return strings.TrimSuffix(base, path.Ext(base))
+	}
+	return u.Host
+}
+
+// urlSecretParams are query parameters whose values are masked in shown URLs
+var urlSecretParams = []string{"key", "token", "access_token", "signature", "sig", "password"}
+
+// RedactURL masks the user info and credential-like query parameters of a URL
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	query := u.Query()
+	masked := false
+	for _, param := range urlSecretParams {
+		if query.Has(param) {
+			query.Set(param, "xxxxx")
+			masked = true
+		}
+	}
+	if masked {
+		u.RawQuery = query.Encode()
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,46 @@
+package models
+
+import "testing"
+
+// TestResolveSourceURL verifies sheet links become CSV exports of their tab and only HTTPS
+// URLs are accepted
+func TestResolveSourceURL(t *testing.T) {
+	cases := []struct{ raw, want, kind string }{
+		{"https://docs.google.com/spreadsheets/d/1AbC-x_9/edit#gid=42", "https://docs.google.com/spreadsheets/d/1AbC-x_9/export?format=csv&gid=42", URLSourceGoogleSheets},
+		{"https://docs.google.com/spreadsheets/d/1AbC-x_9/edit?usp=sharing", "https://docs.google.com/spreadsheets/d/1AbC-x_9/export?format=csv", URLSourceGoogleSheets},
+		{"https://docs.google.com/spreadsheets/d/e/2PACX-1v/pubhtml?gid=7", "https://docs.google.com/spreadsheets/d/e/2PACX-1v/pub?gid=7&output=csv", URLSourceGoogleSheets},
+		{"https://data.example.com/exports/sales.csv", "https://data.example.com/exports/sales.csv", URLSourceCSV},
+	}
+	for _, tc := range cases {
+		got, kind, err := ResolveSourceURL(tc.raw)
+		if err != nil || got != tc.want || kind != tc.kind {
+			t.Errorf("ResolveSourceURL(%q) = %q, %q, %v; want %q, %q", tc.raw, got, kind, err, tc.want, tc.kind)
+		}
+	}
+	for _, raw := range []string{"http://data.example.com/sales.csv", "file:///etc/passwd", "sales.csv"} {
+		if _, _, err := ResolveSourceURL(raw); err == nil {
+			t.Errorf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+// TestURLDataSourceRedacted verifies literal tokens and credential parameters are masked
+// while environment references stay visible
+func TestURLDataSourceRedacted(t *testing.T) {
+	source := &URLDataSource{URL: "https://data.example.com/sales.csv?key=abc123&region=eu", Token: "ya29.secret"}
+	redacted := source.Redacted()
+	if redacted.Token != "xxxxx" || redacted.URL != "https://data.example.com/sales.csv?key=xxxxx&region=eu" {
+		t.Errorf("unexpected redaction: %+v", redacted)
+	}
+	if source.Token != "ya29.secret" {
+		t.Error("expected the source itself to keep its token")
+	}
+	if (&URLDataSource{Token: "env:SHEETS_TOKEN"}).Redacted().Token != "env:SHEETS_TOKEN" {
+		t.Error("expected an environment reference to be shown as given")
+	}
+
+	named := &URLDataSource{URL: "https://docs.google.com/spreadsheets/d/1AbC/edit"}
+	if err := named.Validate(); err != nil || named.Name != "google_sheet_1AbC" || named.Kind != URLSourceGoogleSheets {
+		t.Errorf("expected the sheet to be named and kinded, got %+v (%v)", named, err)
+	}
+}
@@ -0,0 +1,247 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VerdictExplanationKey is the execution metadata key a hypothesis' explanation is stored under
+const VerdictExplanationKey = "verdict_explanation"
+
+// DefaultGateAlpha is the significance level assumed for gates whose standard names none
+const DefaultGateAlpha = 0.05
+
+// gateAlphaPattern finds the level in standards such as "p < 0.05" or "q ≤ 0.010"
+var gateAlphaPattern = regexp.MustCompile(`(?:\b[pq]\s*(?:<=|<|≤)|α\s*=)\s*([0-9]*\.?[0-9]+)`)
+
+// GateExplanation is one gate's part in a verdict
+type GateExplanation struct {
+	Gate     string  `json:"gate"`
+	Passed   bool    `json:"passed"`
+	Decisive bool    `json:"decisive"` // flipping this gate alone would flip the verdict
+	PValue   float64 `json:"p_value"`
+	Alpha    float64 `json:"alpha"`
+	// Margin is how many orders of magnitude p sits on the passing (positive) or failing
+	// (negative) side of alpha. It is omitted when the gate decided on its own criterion
+	// rather than on p.
+	Margin   *float64 `json:"margin,omitempty"`
+	EValue   float64  `json:"e_value,omitempty"`
+	Standard string   `json:"standard,omitempty"`
+	Reason   string   `json:"reason"`
+}
+
+// VerdictExplanation says in plain language why a hypothesis passed or failed: which gates
+// mattered, by how much each passed or failed, and what evidence would change the verdict
+type VerdictExplanation struct {
+	Passed      bool              `json:"passed"`
+	Summary     string            `json:"summary"`
+	Gates       []GateExplanation `json:"gates"`
+	WouldChange []string          `json:"would_change"`
+}
+
+// ExplainVerdict composes the explanation of a hypothesis' verdict. Validation passes a
+// hypothesis when at least one gate passes (and, when stability selection ran, the result
+// is stable), so a passing verdict rests on its passing gates and a failing one on all.
+func ExplainVerdict(h *HypothesisResult) VerdictExplanation {
+	explanation := VerdictExplanation{Passed: h.Passed, Gates: make([]GateExplanation, 0, len(h.RefereeResults))}
+
+	var passing []int
+	for _, r := range h.RefereeResults {
+		gate := GateExplanation{
+			Gate:     r.GateName,
+			Passed:   r.Passed,
+			PValue:   r.PValue,
+			Alpha:    gateAlpha(r.StandardUsed),
+			EValue:   r.EValue,
+			Standard: r.StandardUsed,
+		}
+		if margin := math.Log10(gate.Alpha / math.Max(r.PValue, 1e-12)); (margin >= 0) == r.Passed && !math.IsNaN(r.PValue) {
+			margin = math.Round(margin*100) / 100
+			gate.Margin = &margin
+		}
+		gate.Reason = gateReason(gate, r.FailureReason)
+		if r.Passed {
+			passing = append(passing, len(explanation.Gates))
+		}
+		explanation.Gates = append(explanation.Gates, gate)
+	}
+
+	verdict := "Not validated"
+	if h.Passed {
+		verdict = "Validated"
+	}
+	total := len(explanation.Gates)
+
+	switch {
+	case total == 0:
+		explanation.Summary = fmt.Sprintf("%s without any gate running.", verdict)
+		explanation.WouldChange = append(explanation.WouldChange, "Running the selected gates would give the verdict evidence to rest on.")
+
+	case h.Passed && len(passing) == 1:
+		gate := &explanation.Gates[passing[0]]
+		gate.Decisive = true
+		explanation.Summary = fmt.Sprintf("%s: %d of %d gates passed, and the verdict rests on %s alone (%s).", verdict, 1, total, gateLabel(gate.Gate), gateEvidence(*gate))
+		explanation.WouldChange = append(explanation.WouldChange, fmt.Sprintf("A failing %s would overturn the verdict: %s.", gateLabel(gate.Gate), flipRequirement(*gate)))
+
+	case h.Passed:
+		narrowest := narrowestGate(explanation.Gates, passing)
+		explanation.Summary = fmt.Sprintf("%s: %d of %d gates passed; no single gate decides the verdict.", verdict, len(passing), total)
+		explanation.WouldChange = append(explanation.WouldChange, fmt.Sprintf("All %d passing gates would have to fail to overturn the verdict.", len(passing)))
+		if narrowest >= 0 {
+			gate := explanation.Gates[narrowest]
+			explanation.WouldChange = append(explanation.WouldChange, fmt.Sprintf("The narrowest pass is %s (%s).", gateLabel(gate.Gate), gateEvidence(gate)))
+		}
+
+	case len(passing) > 0:
+		// Gates passed but the result was not stable across subsamples
+		explanation.Summary = fmt.Sprintf("%s: %d of %d gates passed, but the result was not stable across subsamples.", verdict, len(passing), total)
+		if score, ok := h.ExecutionMetadata["stability_score"].(float64); ok {
+			explanation.Summary = fmt.Sprintf("%s: %d of %d gates passed, but the result was not stable across subsamples (stability %.2f).", verdict, len(passing), total, score)
+		}
+		explanation.WouldChange = append(explanation.WouldChange, "The passing gates holding up across subsamples would validate the hypothesis.")
+
+	default:
+		for i := range explanation.Gates {
+			explanation.Gates[i].Decisive = true
+		}
+		explanation.Summary = fmt.Sprintf("%s: none of the %d gates passed.", verdict, total)
+		all := make([]int, total)
+		for i := range all {
+			all[i] = i
+		}
+		if closest := narrowestGate(explanation.Gates, all); closest >= 0 {
+			gate := explanation.Gates[closest]
+			explanation.Summary = fmt.Sprintf("%s: none of the %d gates passed; the closest was %s (%s).", verdict, total, gateLabel(gate.Gate), gateEvidence(gate))
+			explanation.WouldChange = append(explanation.WouldChange, fmt.Sprintf("Any one gate passing would validate the hypothesis; the closest, %s, needs %s.", gateLabel(gate.Gate), flipRequirement(gate)))
+		} else {
+			explanation.WouldChange = append(explanation.WouldChange, "Any one gate passing would validate the hypothesis.")
+		}
+		explanation.WouldChange = append(explanation.WouldChange, "More observations shrink p-values of a real effect, so a larger sample is the usual way to move a near miss.")
+	}
+	return explanation
+}
+
+// VerdictExplanationOf returns the explanation stored with a hypothesis, or composes one for
+// hypotheses validated before explanations were stored
+func VerdictExplanationOf(h *HypothesisResult) VerdictExplanation {
+	if stored, ok := h.ExecutionMetadata[VerdictExplanationKey]; ok {
+		switch v := stored.(type) {
+		case VerdictExplanation:
+			return v
+		case *VerdictExplanation:
+			return *v
+		default:
+			// Decoded from the database as a generic map
+			var explanation VerdictExplanation
+			if data, err := json.Marshal(v); err == nil && json.Unmarshal(data, &explanation) == nil && explanation.Summary != "" {
+				return explanation
+			}
+		}
+	}
+	return ExplainVerdict(h)
+}
+
+// Markdown renders the explanation for reports
+func (e VerdictExplanation) Markdown() string {
+	var b strings.Builder
+	b.WriteString("## Why this verdict\n\n")
+	b.WriteString(e.Summary + "\n\n")
+	if len(e.Gates) > 0 {
+		b.WriteString("| Gate | Result | p-value | Level | Margin | Decisive |\n|---|---|---|---|---|---|\n")
+		for _, gate := range e.Gates {
+			result, decisive, margin := "failed", "", "own criterion"
+			if gate.Passed {
+				result = "passed"
+			}
+			if gate.Decisive {
+				decisive = "yes"
+			}
+			if gate.Margin != nil {
+				margin = fmt.Sprintf("%+.2f orders", *gate.Margin)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %.4g | %.4g | %s | %s |\n", gateLabel(gate.Gate), result, gate.PValue, gate.Alpha, margin, decisive)
+		}
+		b.WriteString("\n")
+	}
+	if len(e.WouldChange) > 0 {
+		b.WriteString("### What would change it\n\n")
+		for _, line := range e.WouldChange {
+			b.WriteString("- " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// gateAlpha reads the significance level from a gate's standard, defaulting to DefaultGateAlpha
+func gateAlpha(standard string) float64 {
+	if m := gateAlphaPattern.FindStringSubmatch(standard); m != nil {
+		if alpha, err := strconv.ParseFloat(m[1], 64); err == nil && alpha > 0 && alpha < 1 {
+			return alpha
+		}
+	}
+	return DefaultGateAlpha
+}
+
+func gateLabel(name string) string {
+	return strings.ReplaceAll(name, "_", " ")
+}
+
+// gateEvidence states a gate's p-value against its level, with the margin when p decided it
+func gateEvidence(gate GateExplanation) string {
+	if gate.Margin == nil {
+		return fmt.Sprintf("p = %.3g, decided on its own criterion", gate.PValue)
+	}
+	side := "inside"
+	if *gate.Margin < 0 {
+		side = "outside"
+	}
+	return fmt.Sprintf("p = %.3g, %.2f orders of magnitude %s α = %.3g", gate.PValue, math.Abs(*gate.Margin), side, gate.Alpha)
+}
+
+// flipRequirement says what the gate's evidence would have to become for its outcome to flip
+func flipRequirement(gate GateExplanation) string {
+	switch {
+	case gate.Margin == nil:
+		return fmt.Sprintf("its criterion (%s) to come out the other way", gate.Standard)
+	case gate.Passed:
+		return fmt.Sprintf("p rising above %.3g from %.3g", gate.Alpha, gate.PValue)
+	default:
+		return fmt.Sprintf("p falling below %.3g from %.3g", gate.Alpha, gate.PValue)
+	}
+}
+
+// gateReason is a one-line account of a single gate's outcome
+func gateReason(gate GateExplanation, failureReason string) string {
+	outcome := "failed"
+	if gate.Passed {
+		outcome = "passed"
+	}
+	reason := fmt.Sprintf("%s %s (%s)", gateLabel(gate.Gate), outcome, gateEvidence(gate))
+	if !gate.Passed && failureReason != "" {
+		reason += ": " + failureReason
+	}
+	return reason
+}
+
+// narrowestGate returns the index, among candidates, of the gate whose margin is closest to
+// zero, or -1 when none of them decided on p
+func narrowestGate(gates []GateExplanation, candidates []int) int {
+	withMargin := make([]int, 0, len(candidates))
+	for _, i := range candidates {
+		if gates[i].Margin != nil {
+			withMargin = append(withMargin, i)
+		}
+	}
+	if len(withMargin) == 0 {
+		return -1
+	}
+	sort.SliceStable(withMargin, func(a, b int) bool {
+		return math.Abs(*gates[withMargin[a]].Margin) < math.Abs(*gates[withMargin[b]].Margin)
+	})
+	return withMargin[0]
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestExplainVerdict verifies the explanation names the gates a verdict rests on, measures
+// their margins against each gate's own level, and says what would flip the verdict
+func TestExplainVerdict(t *testing.T) {
+	h := &HypothesisResult{
+		ID:     "h1",
+		Passed: true,
+		RefereeResults: []RefereeResult{
+			{GateName: "Permutation_Shredder", Passed: true, PValue: 0.0005, StandardUsed: "Two-tailed permutation (N=1000) with p ≤ 0.050 (95.0% confidence)"},
+			{GateName: "Transfer_Entropy", Passed: false, PValue: 0.02, StandardUsed: "TE(X→Y) > TE(Y→X) with p < 0.001 (99.9% confidence in causal direction)", FailureReason: "no directional advantage"},
+			{GateName: "Chow_Stability", Passed: false, PValue: 0.01, StandardUsed: "Linear model adequate (R² improvement < 0.05 or p > 0.05)"},
+		},
+	}
+
+	explanation := ExplainVerdict(h)
+	if !explanation.Gates[0].Decisive || explanation.Gates[1].Decisive {
+		t.Errorf("expected the lone passing gate to be decisive, got %+v", explanation.Gates)
+	}
+	if m := explanation.Gates[0].Margin; m == nil || *m != 2 {
+		t.Errorf("expected p = 0.0005 to pass α = 0.05 by 2 orders of magnitude, got %v", m)
+	}
+	if m := explanation.Gates[1].Margin; m == nil || *m != -1.3 || explanation.Gates[1].Alpha != 0.001 {
+		t.Errorf("expected transfer entropy to miss its own α = 0.001, got %+v", explanation.Gates[1])
+	}
+	if explanation.Gates[2].Margin != nil {
+		t.Error("expected a gate that failed with p under α to be reported as deciding on its own criterion")
+	}
+	if !strings.Contains(explanation.Summary, "rests on Permutation Shredder alone") || len(explanation.WouldChange) == 0 {
+		t.Errorf("unexpected explanation: %+v", explanation)
+	}
+
+	h.Passed = false
+	h.RefereeResults[0].Passed = false
+	h.RefereeResults[0].PValue = 0.08
+	failed := ExplainVerdict(h)
+	if !failed.Gates[0].Decisive || !failed.Gates[1].Decisive {
+		t.Error("expected every gate of a failing verdict to be decisive")
+	}
+	if !strings.Contains(failed.WouldChange[0], "the closest, Permutation Shredder") {
+		t.Errorf("expected the nearest miss to be named, got %v", failed.WouldChange)
+	}
+
+	// Explanations read back from the database arrive as generic maps
+	var stored map[string]interface{}
+	data, _ := json.Marshal(explanation)
+	_ = json.Unmarshal(data, &stored)
+	h.ExecutionMetadata = map[string]interface{}{VerdictExplanationKey: stored}
+	if got := VerdictExplanationOf(h); got.Summary != explanation.Summary || len(got.Gates) != 3 {
+		t.Errorf("expected the stored explanation to be returned, got %+v", got)
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// VerdictHistoryKey is the execution metadata key a hypothesis' past verdicts are stored under
+const VerdictHistoryKey = "verdict_history"
+
+// What produced a verdict
+const (
+	VerdictTriggerValidation = "validation" // the hypothesis' original validation
+	VerdictTriggerRetest     = "retest"     // a re-test against newer data
+)
+
+// VerdictRecord is one verdict in a hypothesis' history, with the data it was reached on
+type VerdictRecord struct {
+	At             time.Time  `json:"at"`
+	Trigger        string     `json:"trigger"`
+	Passed         bool       `json:"passed"`
+	Confidence     float64    `json:"confidence"`
+	EValue         float64    `json:"e_value,omitempty"`
+	GatesPassed    int        `json:"gates_passed"`
+	Gates          int        `json:"gates"`
+	DatasetID      string     `json:"dataset_id,omitempty"`
+	DatasetVersion *time.Time `json:"dataset_version,omitempty"` // the dataset's update time
+	Error          string     `json:"error,omitempty"`           // the re-test could not run; the verdict stands
+}
+
+// VerdictRecordOf records a hypothesis' current verdict
+func VerdictRecordOf(h *HypothesisResult, trigger string) VerdictRecord {
+	record := VerdictRecord{
+		At:         h.ValidationTimestamp,
+		Trigger:    trigger,
+		Passed:     h.Passed,
+		Confidence: h.Confidence,
+		EValue:     h.CurrentEValue,
+		Gates:      len(h.RefereeResults),
+	}
+	if record.At.IsZero() {
+		record.At = time.Now()
+	}
+	for _, result := range h.RefereeResults {
+		if result.Passed {
+			record.GatesPassed++
+		}
+	}
+	return record
+}
+
+// VerdictHistoryOf returns a hypothesis' verdicts, oldest first. Hypotheses never re-tested
+// have a history of their original validation alone.
+func VerdictHistoryOf(h *HypothesisResult) []VerdictRecord {
+	if stored, ok := h.ExecutionMetadata[VerdictHistoryKey]; ok {
+		if list, ok := stored.([]VerdictRecord); ok {
+			return list
+		}
+		var list []VerdictRecord
+		if data, err := json.Marshal(stored); err == nil && json.Unmarshal(data, &list) == nil && len(list) > 0 {
+			return list
+		}
+	}
+	return []VerdictRecord{VerdictRecordOf(h, VerdictTriggerValidation)}
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// WebhookEvent names a run lifecycle event webhooks subscribe to
+type WebhookEvent string
+
+const (
+	WebhookDatasetReady        WebhookEvent = "dataset_ready"        // a dataset version finished processing
+	WebhookSweepCompleted      WebhookEvent = "sweep_completed"      // a research session's stats sweep finished
+	WebhookHypothesisValidated WebhookEvent = "hypothesis_validated" // a hypothesis passed validation
+	WebhookRunFailed           WebhookEvent = "run_failed"           // a research session ended in error
+)
+
+// WebhookEvents lists every event a webhook can subscribe to
+var WebhookEvents = []WebhookEvent{WebhookDatasetReady, WebhookSweepCompleted, WebhookHypothesisValidated, WebhookRunFailed}
+
+// WebhookDeliveryStatus is where a delivery is in its lifecycle
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // waiting for its first attempt or a retry
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // the endpoint answered 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // gave up after WebhookMaxAttempts
+)
+
+// Delivery retries: a delivery is tried WebhookMaxAttempts times, waiting WebhookRetryBaseDelay
+// before the first retry and twice as long before each later one, up to WebhookRetryMaxDelay
+const (
+	WebhookMaxAttempts    = 8
+	WebhookRetryBaseDelay = 30 * time.Second
+	WebhookRetryMaxDelay  = 2 * time.Hour
+)
+
+// Headers of webhook requests. The signature is "sha256=" and the hex HMAC-SHA256, keyed
+// with the webhook's secret, of the timestamp header, a dot and the raw body.
+const (
+	WebhookEventHeader     = "X-GoHypo-Event"
+	WebhookDeliveryHeader  = "X-GoHypo-Delivery"
+	WebhookTimestampHeader = "X-GoHypo-Timestamp"
+	WebhookSignatureHeader = "X-GoHypo-Signature"
+)
+
+// Webhook is a workspace's HTTP endpoint notified of the events it subscribes to. The secret
+// is shown once, when the webhook is created, and signs every request.
+type Webhook struct {
+	ID          string         `json:"id" db:"id"`
+	WorkspaceID string         `json:"workspace_id" db:"workspace_id"`
+	URL         string         `json:"url" db:"url"`
+	Secret      string         `json:"secret,omitempty" db:"secret"`
+	Events      pq.StringArray `json:"events" db:"events"`
+	Active      bool           `json:"active" db:"active"`
+	CreatedBy   string         `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+}
+
+// NewWebhook creates an active webhook of a workspace posting the given events to endpoint,
+// with a fresh random secret
+func NewWebhook(workspaceID, endpoint string, events []WebhookEvent) (*Webhook, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("subscribe to at least one event")
+	}
+	names := make([]string, 0, len(events))
+	for _, event := range events {
+		if !slices.Contains(WebhookEvents, event) {
+			return nil, fmt.Errorf("unknown event %q", event)
+		}
+		if !slices.Contains(names, string(event)) {
+			names = append(names, string(event))
+		}
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return &Webhook{
+		WorkspaceID: workspaceID,
+		URL:         endpoint,
+		Secret:      "whsec_" + hex.EncodeToString(raw),
+		Events:      names,
+		Active:      true,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// Subscribes reports whether the webhook is active and wants event
+func (w *Webhook) Subscribes(event WebhookEvent) bool {
+	return w.Active && slices.Contains(w.Events, string(event))
+}
+
+// SignWebhook returns the signature header value of a request body sent at timestamp
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook checks a received signature against the body and timestamp header, rejecting
+// timestamps further than tolerance from now so captured requests cannot be replayed
+func VerifyWebhook(secret, signature, timestampHeader string, body []byte, tolerance time.Duration, now time.Time) error {
+	unix, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestampHeader)
+	}
+	timestamp := time.Unix(unix, 0)
+	if d := now.Sub(timestamp); d > tolerance || d < -tolerance {
+		return fmt.Errorf("timestamp is %s away from now", d.Round(time.Second))
+	}
+	if !hmac.Equal([]byte(signature), []byte(SignWebhook(secret, timestamp, body))) {
+		return fmt.Errorf("signature does not match")
+	}
+	return nil
+}
+
+// WebhookDelivery is one event sent, or to be sent, to one webhook, with the outcome of its
+// latest attempt. Deliveries are stored before they are attempted so none are lost to a restart.
+type WebhookDelivery struct {
+	ID             string                `json:"id" db:"id"`
+	WebhookID      string                `json:"webhook_id" db:"webhook_id"`
+	WorkspaceID    string                `json:"workspace_id" db:"workspace_id"`
+	Event          WebhookEvent          `json:"event" db:"event"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	ResponseStatus *int                  `json:"response_status,omitempty" db:"response_status"`
+	LastError      *string               `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" db:"next_attempt_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookEnvelope is the JSON body of every webhook request
+type WebhookEnvelope struct {
+	ID          string       `json:"id"` // the delivery ID, stable across retries for deduplication
+	Event       WebhookEvent `json:"event"`
+	WorkspaceID string       `json:"workspace_id"`
+	CreatedAt   time.Time    `json:"created_at"`
+	Data        interface{}  `json:"data"`
+}
+
+// CanRetry reports whether a failed attempt leaves the delivery another one
+func (d *WebhookDelivery) CanRetry() bool {
+	return d.Attempts < WebhookMaxAttempts
+}
+
+// RetryDelay is how long to wait after the delivery's latest failed attempt before the next
+func (d *WebhookDelivery) RetryDelay() time.Duration {
+	delay := WebhookRetryBaseDelay
+	for i := 1; i < d.Attempts && delay < WebhookRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, WebhookRetryMaxDelay)
+}
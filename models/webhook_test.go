@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNewWebhookValidates rejects endpoints and subscriptions a delivery could not use
+func TestNewWebhookValidates(t *testing.T) {
+	if _, err := NewWebhook("ws1", "ftp://example.com/hook", []WebhookEvent{WebhookRunFailed}); err == nil {
+		t.Error("expected a non-HTTP URL to be rejected")
+	}
+	if _, err := NewWebhook("ws1", "https://example.com/hook", nil); err == nil {
+		t.Error("expected a webhook without events to be rejected")
+	}
+	if _, err := NewWebhook("ws1", "https://example.com/hook", []WebhookEvent{"dataset_deleted"}); err == nil {
+		t.Error("expected an unknown event to be rejected")
+	}
+
+	hook, err := NewWebhook("ws1", "https://example.com/hook", []WebhookEvent{WebhookSweepCompleted, WebhookSweepCompleted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hook.Events) != 1 || !hook.Subscribes(WebhookSweepCompleted) || hook.Subscribes(WebhookRunFailed) {
+		t.Fatalf("unexpected subscriptions %v", hook.Events)
+	}
+	if hook.Secret == "" {
+		t.Fatal("expected a generated secret")
+	}
+	hook.Active = false
+	if hook.Subscribes(WebhookSweepCompleted) {
+		t.Fatal("expected an inactive webhook to subscribe to nothing")
+	}
+}
+
+// TestWebhookSignature round-trips a signature and rejects tampered or stale requests
+func TestWebhookSignature(t *testing.T) {
+	sent := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"event":"run_failed"}`)
+	signature := SignWebhook("whsec_test", sent, body)
+	timestamp := "1700000000"
+
+	if err := VerifyWebhook("whsec_test", signature, timestamp, body, 5*time.Minute, sent.Add(time.Minute)); err != nil {
+		t.Fatalf("expected the signature to verify: %v", err)
+	}
+	if err := VerifyWebhook("whsec_test", signature, timestamp, []byte(`{"event":"sweep_completed"}`), 5*time.Minute, sent); err == nil {
+		t.Error("expected a changed body to fail verification")
+	}
+	if err := VerifyWebhook("whsec_other", signature, timestamp, body, 5*time.Minute, sent); err == nil {
+		t.Error("expected another secret to fail verification")
+	}
+	if err := VerifyWebhook("whsec_test", signature, timestamp, body, 5*time.Minute, sent.Add(time.Hour)); err == nil {
+		t.Error("expected a stale timestamp to fail verification")
+	}
+}
+
+// TestWebhookDeliveryRetryDelay verifies the backoff doubles after each failed attempt up to the cap
+func TestWebhookDeliveryRetryDelay(t *testing.T) {
+	delivery := &WebhookDelivery{}
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute}
+	for i, delay := range want {
+		delivery.Attempts = i + 1
+		if got := delivery.RetryDelay(); got != delay {
+			t.Fatalf("attempt %d: expected %v, got %v", delivery.Attempts, delay, got)
+		}
+	}
+	delivery.Attempts = 30
+	if got := delivery.RetryDelay(); got != WebhookRetryMaxDelay {
+		t.Fatalf("expected the delay capped at %v, got %v", WebhookRetryMaxDelay, got)
+	}
+	if delivery.CanRetry() {
+		t.Fatal("expected no retry once the attempts are used up")
+	}
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// LLMSettings override the global AI config for generation calls. Zero fields keep the
+// global value.
+type LLMSettings struct {
+	Provider    string   `json:"provider,omitempty" db:"provider"` // GENERATOR_MODE value
+	Model       string   `json:"model,omitempty" db:"model"`
+	Temperature *float64 `json:"temperature,omitempty" db:"temperature"`
+	MaxTokens   int      `json:"max_tokens,omitempty" db:"max_tokens"`
+}
+
+// IsZero reports whether the settings override nothing
+func (s LLMSettings) IsZero() bool {
+	return s.Provider == "" && s.Model == "" && s.Temperature == nil && s.MaxTokens == 0
+}
+
+// Validate checks the provider is known and the sampling values are in range
+func (s LLMSettings) Validate() error {
+	switch s.Provider {
+	case "", GeneratorModeOpenAI, GeneratorModeAnthropic, GeneratorModeOllama:
+	default:
+		return fmt.Errorf("provider must be %s, %s or %s, got %q", GeneratorModeOpenAI, GeneratorModeAnthropic, GeneratorModeOllama, s.Provider)
+	}
+	if s.Temperature != nil && (*s.Temperature < 0 || *s.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2, got %g", *s.Temperature)
+	}
+	if s.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must not be negative, got %d", s.MaxTokens)
+	}
+	return nil
+}
+
+// Merge returns s with every non-zero field of over applied on top
+func (s LLMSettings) Merge(over LLMSettings) LLMSettings {
+	if over.Provider != "" {
+		s.Provider = over.Provider
+	}
+	if over.Model != "" {
+		s.Model = over.Model
+	}
+	if over.Temperature != nil {
+		s.Temperature = over.Temperature
+	}
+	if over.MaxTokens > 0 {
+		s.MaxTokens = over.MaxTokens
+	}
+	return s
+}
+
+// Apply returns a copy of config with the settings applied. The model goes to the selected
+// provider's model field.
+func (s LLMSettings) Apply(config *AIConfig) *AIConfig {
+	applied := *config
+	if s.Provider != "" {
+		applied.GeneratorMode = s.Provider
+	}
+	if s.Model != "" {
+		switch applied.Mode() {
+		case GeneratorModeAnthropic:
+			applied.AnthropicModel = s.Model
+		case GeneratorModeOllama:
+			applied.OllamaModel = s.Model
+		default:
+			applied.OpenAIModel = s.Model
+		}
+	}
+	if s.Temperature != nil {
+		applied.Temperature = *s.Temperature
+	}
+	if s.MaxTokens > 0 {
+		applied.MaxTokens = s.MaxTokens
+	}
+	return &applied
+}
+
+// WorkspaceLLMSettings are a workspace's LLM defaults. An admin can lock them so a regulated
+// workspace only ever generates with its approved model: while locked, the settings cannot
+// be edited outside the admin API and runs cannot override them.
+type WorkspaceLLMSettings struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	LLMSettings
+	Locked    bool       `json:"locked" db:"locked"`
+	LockedBy  string     `json:"locked_by,omitempty" db:"locked_by"`
+	LockedAt  *time.Time `json:"locked_at,omitempty" db:"locked_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// ForRun returns the settings a run uses: the workspace defaults with the run's overrides on
+// top. A locked workspace refuses overrides rather than silently ignoring them.
+func (w *WorkspaceLLMSettings) ForRun(run LLMSettings) (LLMSettings, error) {
+	if w.Locked && !run.IsZero() {
+		return LLMSettings{}, fmt.Errorf("workspace %s has locked LLM settings (locked by %s); runs cannot override them", w.WorkspaceID, w.LockedBy)
+	}
+	return w.LLMSettings.Merge(run), nil
+}
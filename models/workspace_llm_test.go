@@ -0,0 +1,38 @@
+package models
+
+import "testing"
+
+// TestWorkspaceLLMSettingsLockRefusesOverrides verifies run overrides layer on workspace
+// defaults, land on the selected provider's model, and are refused once locked
+func TestWorkspaceLLMSettingsLockRefusesOverrides(t *testing.T) {
+	low := 0.0
+	workspace := &WorkspaceLLMSettings{
+		WorkspaceID: "ws-1",
+		LLMSettings: LLMSettings{Provider: GeneratorModeAnthropic, Model: "approved-model", Temperature: &low},
+	}
+
+	settings, err := workspace.ForRun(LLMSettings{MaxTokens: 1500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := settings.Apply(&AIConfig{OpenAIModel: "global-model", Temperature: 0.7, MaxTokens: 2000})
+	if config.Mode() != GeneratorModeAnthropic || config.AnthropicModel != "approved-model" || config.OpenAIModel != "global-model" {
+		t.Fatalf("model not applied to the selected provider: %+v", config)
+	}
+	if config.Temperature != 0 || config.MaxTokens != 1500 {
+		t.Fatalf("sampling settings not applied: temperature=%g max_tokens=%d", config.Temperature, config.MaxTokens)
+	}
+
+	workspace.Locked, workspace.LockedBy = true, "compliance"
+	if _, err := workspace.ForRun(LLMSettings{Model: "other-model"}); err == nil {
+		t.Fatal("expected a locked workspace to refuse a run override")
+	}
+	if settings, err := workspace.ForRun(LLMSettings{}); err != nil || settings.Model != "approved-model" {
+		t.Fatalf("expected the pinned settings without overrides, got %+v, %v", settings, err)
+	}
+
+	high := 3.0
+	if err := (LLMSettings{Temperature: &high}).Validate(); err == nil {
+		t.Fatal("expected an out-of-range temperature to be rejected")
+	}
+}
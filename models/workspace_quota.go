@@ -0,0 +1,60 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WorkspaceQuotaKey is the workspace metadata entry holding its quota
+const WorkspaceQuotaKey = "quota"
+
+// WorkspaceQuota caps what a workspace may hold. Zero fields are unlimited.
+type WorkspaceQuota struct {
+	MaxDatasets     int   `json:"max_datasets,omitempty"`
+	MaxStorageBytes int64 `json:"max_storage_bytes,omitempty"` // total size of the workspace's dataset files
+}
+
+// WorkspaceQuotaFrom reads the quota from workspace metadata; a workspace without one is unlimited
+func WorkspaceQuotaFrom(metadata map[string]interface{}) WorkspaceQuota {
+	var quota WorkspaceQuota
+	raw, ok := metadata[WorkspaceQuotaKey]
+	if !ok {
+		return quota
+	}
+	// Metadata read back from the database holds the quota as a generic map
+	data, err := json.Marshal(raw)
+	if err == nil {
+		json.Unmarshal(data, &quota)
+	}
+	return quota
+}
+
+// Validate rejects negative limits
+func (q WorkspaceQuota) Validate() error {
+	if q.MaxDatasets < 0 || q.MaxStorageBytes < 0 {
+		return fmt.Errorf("quota limits must not be negative")
+	}
+	return nil
+}
+
+// Unlimited reports whether the quota sets no limit
+func (q WorkspaceQuota) Unlimited() bool {
+	return q.MaxDatasets == 0 && q.MaxStorageBytes == 0
+}
+
+// Admit checks whether a workspace holding datasets files of storedBytes in total can take
+// one more dataset of incomingBytes
+func (q WorkspaceQuota) Admit(datasets int, storedBytes, incomingBytes int64) error {
+	if q.MaxDatasets > 0 && datasets+1 > q.MaxDatasets {
+		return fmt.Errorf("workspace quota allows %d datasets and %d are stored", q.MaxDatasets, datasets)
+	}
+	if q.MaxStorageBytes > 0 && storedBytes+incomingBytes > q.MaxStorageBytes {
+		return fmt.Errorf("workspace quota allows %.1f MB of dataset files; %.1f MB are stored and the new file is %.1f MB",
+			megabytes(q.MaxStorageBytes), megabytes(storedBytes), megabytes(incomingBytes))
+	}
+	return nil
+}
+
+func megabytes(bytes int64) float64 {
+	return float64(bytes) / (1024 * 1024)
+}
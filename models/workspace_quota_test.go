@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestWorkspaceQuotaRoundTrip verifies a quota survives being stored in workspace metadata
+func TestWorkspaceQuotaRoundTrip(t *testing.T) {
+	if quota := WorkspaceQuotaFrom(nil); !quota.Unlimited() {
+		t.Fatalf("expected a workspace without a quota to be unlimited, got %+v", quota)
+	}
+
+	metadata := map[string]interface{}{WorkspaceQuotaKey: WorkspaceQuota{MaxDatasets: 5, MaxStorageBytes: 10 << 20}}
+	data, _ := json.Marshal(metadata)
+	var stored map[string]interface{}
+	json.Unmarshal(data, &stored)
+
+	if quota := WorkspaceQuotaFrom(stored); quota.MaxDatasets != 5 || quota.MaxStorageBytes != 10<<20 {
+		t.Fatalf("expected the stored quota back, got %+v", quota)
+	}
+	if err := (WorkspaceQuota{MaxDatasets: -1}).Validate(); err == nil {
+		t.Fatal("expected negative limits to be rejected")
+	}
+}
+
+// TestWorkspaceQuotaAdmit verifies each limit refuses the dataset that would exceed it
+func TestWorkspaceQuotaAdmit(t *testing.T) {
+	quota := WorkspaceQuota{MaxDatasets: 2, MaxStorageBytes: 10 << 20}
+	if err := quota.Admit(1, 4<<20, 4<<20); err != nil {
+		t.Fatalf("expected a dataset within both limits to be admitted: %v", err)
+	}
+	if err := quota.Admit(2, 0, 1); err == nil {
+		t.Fatal("expected the dataset limit to refuse a third dataset")
+	}
+	if err := quota.Admit(0, 8<<20, 4<<20); err == nil {
+		t.Fatal("expected the storage limit to refuse a file that does not fit")
+	}
+	if err := (WorkspaceQuota{}).Admit(1000, 1<<40, 1<<40); err != nil {
+		t.Fatalf("expected an unlimited quota to admit anything: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"gohypo/models"
+)
+
+// ArtifactArchiveStore keeps the index of archived sessions and moves hypothesis payloads
+// between the hot ledger and it
+type ArtifactArchiveStore interface {
+	// ListArchivable returns up to limit sessions with hot artifacts and no activity since
+	// before, least recently active first
+	ListArchivable(ctx context.Context, before time.Time, limit int) ([]models.ArchiveCandidate, error)
+
+	// SessionArtifacts returns the payloads of a session's hot artifacts
+	SessionArtifacts(ctx context.Context, sessionID string) ([]models.ArchivedArtifact, error)
+
+	// MarkArchived records the archive and clears the archived artifacts' payloads
+	MarkArchived(ctx context.Context, archive *models.ArtifactArchive, artifactIDs []string) error
+
+	// GetSessionArchive returns a session's archive, or nil when it was never archived
+	GetSessionArchive(ctx context.Context, sessionID string) (*models.ArtifactArchive, error)
+
+	// Restore writes archived payloads back and marks the archive rehydrated
+	Restore(ctx context.Context, archive *models.ArtifactArchive, artifacts []models.ArchivedArtifact) error
+
+	// WorkspaceUsage reports a workspace's hot and archived artifact storage
+	WorkspaceUsage(ctx context.Context, workspaceID string) (*models.WorkspaceStorageUsage, error)
+}
+
+// ValidatedEvidenceSource names the research sessions with hypotheses that passed
+// validation, whose evidence chains ledger retention keeps
+type ValidatedEvidenceSource interface {
+	ValidatedSessions(ctx context.Context) ([]string, error)
+}
@@ -34,6 +34,7 @@ type BlobStorage interface {
 type ArtifactMetadata struct {
 	ID           core.ID           `json:"id"`
 	Kind         core.ArtifactKind `json:"kind"`
+	SchemaVersion int              `json:"schema_version,omitempty"`
 	Fingerprint  core.Hash         `json:"fingerprint"`
 	CreatedAt    core.Timestamp    `json:"created_at"`
 	SessionID    string            `json:"session_id"`
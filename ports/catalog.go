@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+)
+
+// CatalogReader assembles the data catalog of a workspace
+type CatalogReader interface {
+	// GetCatalog lists a workspace's datasets with their fields, discovered relations and the
+	// hypotheses referencing each field
+	GetCatalog(ctx context.Context, workspaceID core.ID) (*dataset.Catalog, error)
+}
@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"gohypo/models"
+)
+
+// SQLDataSourceStore persists the SQL data sources workspaces pull datasets from
+type SQLDataSourceStore interface {
+	// Create stores a source, assigning its ID when empty
+	Create(ctx context.Context, source *models.SQLDataSource) error
+
+	// Get returns one of a workspace's sources; NotFound when the workspace has no such source
+	Get(ctx context.Context, workspaceID, id string) (*models.SQLDataSource, error)
+
+	// ListByWorkspace returns a workspace's sources, oldest first
+	ListByWorkspace(ctx context.Context, workspaceID string) ([]*models.SQLDataSource, error)
+
+	// ListScheduled returns every source refreshed on a schedule
+	ListScheduled(ctx context.Context) ([]*models.SQLDataSource, error)
+
+	// RecordRefresh stores the outcome of a pull: the dataset it registered, or its error
+	RecordRefresh(ctx context.Context, id string, at time.Time, datasetID, errMsg string) error
+
+	// Delete removes one of a workspace's sources; NotFound when the workspace has no such source
+	Delete(ctx context.Context, workspaceID, id string) error
+}
+
+// URLDataSourceStore persists the remote CSV files and Google Sheets workspaces fetch
+// datasets from
+type URLDataSourceStore interface {
+	// Create stores a source, assigning its ID when empty
+	Create(ctx context.Context, source *models.URLDataSource) error
+
+	// Get returns one of a workspace's sources; NotFound when the workspace has no such source
+	Get(ctx context.Context, workspaceID, id string) (*models.URLDataSource, error)
+
+	// ListByWorkspace returns a workspace's sources, oldest first
+	ListByWorkspace(ctx context.Context, workspaceID string) ([]*models.URLDataSource, error)
+
+	// ListScheduled returns every source re-fetched on a schedule
+	ListScheduled(ctx context.Context) ([]*models.URLDataSource, error)
+
+	// RecordRefresh stores the outcome of a fetch: the dataset it registered and the file's
+	// checksum, or its error. Empty IDs and checksums keep the previous ones.
+	RecordRefresh(ctx context.Context, id string, at time.Time, datasetID, checksum, errMsg string) error
+
+	// Delete removes one of a workspace's sources; NotFound when the workspace has no such source
+	Delete(ctx context.Context, workspaceID, id string) error
+}
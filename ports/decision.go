@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+
+	"gohypo/models"
+)
+
+// DecisionLog records business actions taken on hypotheses and how they turned out
+type DecisionLog interface {
+	// Record adds a pending decision, assigning its ID when empty
+	Record(ctx context.Context, decision *models.Decision) error
+
+	// RecordOutcome sets the outcome of one of a workspace's decisions; NotFound when the
+	// workspace has no such decision
+	RecordOutcome(ctx context.Context, workspaceID, id, outcome, notes string) (*models.Decision, error)
+
+	// ListByWorkspace returns a workspace's decisions, newest first
+	ListByWorkspace(ctx context.Context, workspaceID string) ([]*models.Decision, error)
+
+	// ListResolved returns every decision with a recorded outcome, across workspaces
+	ListResolved(ctx context.Context) ([]*models.Decision, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import "context"
+
+// TextEmbedder turns texts into vectors whose cosine similarity follows how alike the texts
+// are in meaning. Embed returns one vector per text, in order.
+type TextEmbedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+
+	// Model names the vector space; vectors of different models are never compared
+	Model() string
+}
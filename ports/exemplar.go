@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"gohypo/models"
+)
+
+// ExemplarStore holds curated few-shot exemplars by domain pack
+type ExemplarStore interface {
+	Propose(ctx context.Context, exemplar *models.Exemplar) error
+	Get(ctx context.Context, id string) (*models.Exemplar, error)
+	// List returns a pack's exemplars, newest approval first; an empty status lists every state
+	List(ctx context.Context, domainPack, status string) ([]*models.Exemplar, error)
+	Approve(ctx context.Context, id, approvedBy string) error
+	Retire(ctx context.Context, id string) error
+	// DomainPacks lists the packs with at least one approved exemplar
+	DomainPacks(ctx context.Context) ([]string, error)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"gohypo/domain/core"
 	"gohypo/domain/greenfield"
+	"gohypo/models"
 	"time"
 )
 
@@ -20,6 +21,9 @@ type GreenfieldResearchRequest struct {
 	StatisticalArtifacts    []map[string]interface{}   `json:"statistical_artifacts,omitempty"`    // Full statistical artifacts for context
 	DiscoveryBriefs         interface{}                `json:"discovery_briefs,omitempty"`         // Discovery briefs for grounding
 	ValidatedHypothesisSummary interface{}             `json:"validated_hypothesis_summary,omitempty"` // Summary of previously validated hypotheses
+	DomainPack              string                     `json:"domain_pack,omitempty"`              // Few-shot exemplar pack; defaults to the scout's detected domain
+	SelfConsistencySamples  int                        `json:"self_consistency_samples,omitempty"` // Generations sampled per batch for consensus; 0 or 1 samples once
+	LLM                     *models.LLMSettings        `json:"llm,omitempty"`                      // Workspace and run overrides of the global AI config
 	Directives              int                        `json:"directives"`
 }
 
@@ -27,11 +31,37 @@ type GreenfieldResearchRequest struct {
 type GreenfieldResearchResponse struct {
 	Directives         []greenfield.ResearchDirective      `json:"directives"`
 	EngineeringBacklog []greenfield.EngineeringBacklogItem `json:"engineering_backlog"`
-	RawLLMResponse     interface{}                         `json:"-"` // Raw LLM response (models.GreenfieldResearchOutput) for internal use
-	RenderedPrompt     string                              `json:"-"` // Rendered prompt with industry context injection for debugging
+	RawLLMResponse     interface{}                         `json:"-"`                       // Raw LLM response (models.GreenfieldResearchOutput) for internal use
+	RenderedPrompt     string                              `json:"-"`                       // Rendered prompt with industry context injection for debugging
+	Usage              *models.RunUsage                    `json:"usage,omitempty"`         // Tokens and estimated cost of this run's LLM calls
+	PromptBudget       *models.PromptBudgetReport          `json:"prompt_budget,omitempty"` // Evidence fragments fitted into the context window
 	Audit              GreenfieldAudit                     `json:"audit"`
 }
 
+// GreenfieldPromptPreviewer renders a generation request's prompt without calling the LLM
+type GreenfieldPromptPreviewer interface {
+	PreviewResearchPrompt(ctx context.Context, req GreenfieldResearchRequest) (*GreenfieldPromptPreview, error)
+}
+
+// GreenfieldPromptPreview - The exact prompt a generation run would send (dry run)
+type GreenfieldPromptPreview struct {
+	Provider      string                     `json:"provider"`
+	Model         string                     `json:"model"`
+	MaxTokens     int                        `json:"max_tokens"`
+	SystemMessage string                     `json:"system_message"`
+	Prompt        string                     `json:"prompt"`      // Rendered template with injected context
+	FullPrompt    string                     `json:"full_prompt"` // Exactly what the provider receives
+	Sections      []PromptSection            `json:"sections"`
+	TotalChars    int                        `json:"total_chars"`
+	Budget        *models.PromptBudgetReport `json:"budget"`
+}
+
+// PromptSection - Size of one injected context block
+type PromptSection struct {
+	Name  string `json:"name"`
+	Chars int    `json:"chars"`
+}
+
 // GreenfieldAudit - Generation tracking
 type GreenfieldAudit struct {
 	GeneratorType  string  `json:"generator_type"`
@@ -0,0 +1,45 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"gohypo/models"
+)
+
+// JobQueue is the durable queue of background jobs. Any number of workers, on any number of
+// server instances, may claim from it concurrently; each queued job is handed to one of them
+// and stays theirs while they heartbeat within its lease.
+type JobQueue interface {
+	// Enqueue adds a job, filling in its ID
+	Enqueue(ctx context.Context, job *models.Job) error
+
+	// Claim marks the oldest queued job of kind that is due as running under workerID for
+	// lease, counting the attempt, and returns it; nil when no job is due
+	Claim(ctx context.Context, kind, workerID string, lease time.Duration) (*models.Job, error)
+
+	// Heartbeat extends a running job's lease by lease from now. It reports false when the
+	// job is no longer held by workerID, because its lease expired and it was recovered.
+	Heartbeat(ctx context.Context, jobID, workerID string, lease time.Duration) (bool, error)
+
+	// Release queues a job held by workerID again to run at runAt, without counting the
+	// attempt, for a job the worker could not start
+	Release(ctx context.Context, jobID, workerID string, runAt time.Time) error
+
+	// Complete marks a job held by workerID as succeeded
+	Complete(ctx context.Context, jobID, workerID string) error
+
+	// Fail records a failed attempt of a job held by workerID. The job is queued again to run
+	// at retryAt when it has attempts left, and marked failed otherwise.
+	Fail(ctx context.Context, jobID, workerID string, errMsg string, retryAt time.Time) error
+
+	// RecoverOrphaned queues again the running jobs whose lease expired, whose worker is
+	// taken to be gone, and returns how many there were
+	RecoverOrphaned(ctx context.Context) (int, error)
+
+	// GetJob returns a job, or nil when there is none with the ID
+	GetJob(ctx context.Context, jobID string) (*models.Job, error)
+
+	// ListJobs returns up to limit jobs of kind, newest first, optionally only those in status
+	ListJobs(ctx context.Context, kind string, status *models.JobStatus, limit int) ([]*models.Job, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// LeaseStore hands out named, expiring locks shared by every server instance. A lease is
+// held until it is released or its holder stops renewing it before it expires.
+type LeaseStore interface {
+	// AcquireLease takes the lease on key for holder for ttl, reporting false when another
+	// holder has it. Acquiring a lease the holder already has renews it.
+	AcquireLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error)
+
+	// RenewLease extends holder's lease on key by ttl from now, reporting false when holder
+	// no longer has it
+	RenewLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error)
+
+	// ReleaseLease gives up holder's lease on key; releasing a lease held by another holder
+	// does nothing
+	ReleaseLease(ctx context.Context, key, holder string) error
+}
@@ -39,3 +39,16 @@ type LedgerPort interface {
 	LedgerWriterPort
 	LedgerReaderPort
 }
+
+// LedgerCompactionPort lets retention walk the ledger run by run and drop artifacts once
+// they are archived. Dropping is the only mutation; the ledger stays append-only otherwise.
+type LedgerCompactionPort interface {
+	LedgerPort
+
+	// ListRunIDs returns every run with artifacts in the ledger
+	ListRunIDs(ctx context.Context) ([]core.RunID, error)
+
+	// RemoveArtifacts drops artifacts from a run; an artifact also stored under another run
+	// stays readable through that run
+	RemoveArtifacts(ctx context.Context, runID core.RunID, artifactIDs []core.ArtifactID) error
+}
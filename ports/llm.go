@@ -9,6 +9,7 @@ type UsageData struct {
 	TotalTokens      int    `json:"total_tokens"`
 	Model            string `json:"model"`
 	Provider         string `json:"provider"`
+	Cached           bool   `json:"cached,omitempty"` // served from the response cache; nothing was billed
 }
 
 // LLMResponse represents an enhanced LLM response with usage data
@@ -33,3 +34,10 @@ type LLMClient interface {
 	// Enhanced method with usage tracking and response format
 	ChatCompletionWithUsageAndFormat(ctx context.Context, model string, prompt string, maxTokens int, responseFormat *ResponseFormat) (*LLMResponse, error)
 }
+
+// LLMResponseCache stores provider responses by content-addressed key so identical
+// prompts are not re-billed. A miss returns (nil, false, nil).
+type LLMResponseCache interface {
+	Get(ctx context.Context, key string) (*LLMResponse, bool, error)
+	Put(ctx context.Context, key string, response *LLMResponse) error
+}
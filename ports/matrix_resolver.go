@@ -20,3 +20,51 @@ type MatrixResolutionRequest struct {
 	EntityIDs  []core.ID          // entities to include (cohort)
 	VarKeys    []core.VariableKey // variables to resolve
 }
+
+// QueryPreviewPort exposes the push-down SQL a resolver would run so it can be
+// reviewed (and approved) before it hits the warehouse
+type QueryPreviewPort interface {
+	// PreviewResolution compiles the SQL for each variable and returns EXPLAIN-based cost estimates
+	PreviewResolution(ctx context.Context, req MatrixResolutionRequest) (*ResolutionPreview, error)
+
+	// ApproveQuery records approvedBy's review of a compiled query (by hash) as safe to execute
+	ApproveQuery(ctx context.Context, queryHash core.Hash, approvedBy string) error
+}
+
+// QueryApproval is a reviewer's approval of one compiled push-down query
+type QueryApproval struct {
+	QueryHash  core.Hash      `json:"query_hash"`
+	ApprovedBy string         `json:"approved_by"`
+	ApprovedAt core.Timestamp `json:"approved_at"`
+}
+
+// QueryApprovalStore persists query approvals so they survive restarts and hold for every
+// instance resolving against the same warehouse
+type QueryApprovalStore interface {
+	// Approve records an approval; approving a query again keeps the first approval
+	Approve(ctx context.Context, approval QueryApproval) error
+
+	// IsApproved reports whether the query with hash was approved
+	IsApproved(ctx context.Context, queryHash core.Hash) (bool, error)
+}
+
+// ResolutionPreview is the compiled query plan for a matrix resolution request
+type ResolutionPreview struct {
+	SnapshotID    core.SnapshotID        `json:"snapshot_id"`
+	Variables     []VariableQueryPreview `json:"variables"`
+	TotalCost     float64                `json:"total_cost"`
+	GeneratedAt   core.Timestamp         `json:"generated_at"`
+	ApprovalGated bool                   `json:"approval_gated"` // resolver refuses unapproved queries
+}
+
+// VariableQueryPreview describes the SQL compiled for a single variable
+type VariableQueryPreview struct {
+	VariableKey   core.VariableKey `json:"variable_key"`
+	AsOfMode      string           `json:"as_of_mode"`
+	SQL           string           `json:"sql"`
+	QueryHash     core.Hash        `json:"query_hash"`     // stable identifier used for approval
+	EstimatedCost float64          `json:"estimated_cost"` // planner total cost
+	EstimatedRows float64          `json:"estimated_rows"` // planner row estimate
+	Approved      bool             `json:"approved"`
+	Error         string           `json:"error,omitempty"` // compilation or EXPLAIN failure
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"gohypo/models"
+)
+
+// RelationshipCheckStore persists re-estimates of validated relationships
+type RelationshipCheckStore interface {
+	// SaveCheck records a check, assigning its ID when empty
+	SaveCheck(ctx context.Context, check *models.RelationshipCheck) error
+
+	// ListChecks returns a workspace's checks ordered by dataset version, then check time
+	ListChecks(ctx context.Context, workspaceID string) ([]*models.RelationshipCheck, error)
+
+	// LatestCheck returns a hypothesis' most recent check, or nil when it was never checked
+	LatestCheck(ctx context.Context, hypothesisID string) (*models.RelationshipCheck, error)
+}
@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+
+	"gohypo/models"
+)
+
+// Notifier posts summary cards to one chat service's incoming webhooks
+type Notifier interface {
+	// Notify posts a notification to the channel behind webhookURL
+	Notify(ctx context.Context, webhookURL string, notification models.Notification) error
+}
+
+// HypothesisNotifier announces validated hypotheses to a workspace's chat channels
+type HypothesisNotifier interface {
+	// NotifyValidated posts a hypothesis to each of the workspace's channels whose rigor it meets
+	NotifyValidated(ctx context.Context, workspaceID string, hypothesis *models.HypothesisResult) error
+}
+
+// NotificationChannelStore persists workspaces' chat notification channels
+type NotificationChannelStore interface {
+	// CreateChannel stores a new channel, assigning its ID when empty
+	CreateChannel(ctx context.Context, channel *models.NotificationChannel) error
+
+	// GetChannel returns one of a workspace's channels; NotFound when it has no such channel
+	GetChannel(ctx context.Context, workspaceID, id string) (*models.NotificationChannel, error)
+
+	// ListChannels returns a workspace's channels, oldest first
+	ListChannels(ctx context.Context, workspaceID string) ([]*models.NotificationChannel, error)
+
+	// DeleteChannel removes one of a workspace's channels; NotFound when it has no such channel
+	DeleteChannel(ctx context.Context, workspaceID, id string) error
+}
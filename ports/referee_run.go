@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	"gohypo/models"
+)
+
+// RefereeRunStore persists when each referee of a validation battery started and finished
+type RefereeRunStore interface {
+	// StartRun records a running referee, assigning its ID when empty
+	StartRun(ctx context.Context, run *models.RefereeRun) error
+
+	// FinishRun records a run's outcome, finish time and duration
+	FinishRun(ctx context.Context, run *models.RefereeRun) error
+
+	// ListSessionRuns returns a session's runs, oldest first
+	ListSessionRuns(ctx context.Context, sessionID string) ([]*models.RefereeRun, error)
+
+	// RecentRuns returns up to limit of a referee's most recent finished runs
+	RecentRuns(ctx context.Context, referee string, limit int) ([]*models.RefereeRun, error)
+}
@@ -10,3 +10,10 @@ import (
 type RegistryPort interface {
 	GetContract(ctx context.Context, varKey string) (*dataset.VariableContract, error)
 }
+
+// ContractStore also writes contracts, so a workspace's contracts can move between deployments
+type ContractStore interface {
+	RegistryPort
+	SaveContract(ctx context.Context, contract *dataset.VariableContract) error
+	DeleteContract(ctx context.Context, varKey string) error
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"gohypo/models"
+)
+
+// RunLogStore persists the log lines of runs, so they can be read after the server has
+// dropped them from memory or restarted
+type RunLogStore interface {
+	// AppendRunLogs stores log lines, of one or more runs
+	AppendRunLogs(ctx context.Context, entries []models.RunLogEntry) error
+
+	// ListRunLogs returns up to limit of a run's lines at minSeverity or above, oldest first
+	ListRunLogs(ctx context.Context, runID string, minSeverity int, limit int) ([]models.RunLogEntry, error)
+}
@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+
+	"gohypo/models"
+)
+
+// RunSummaryStore persists the aggregate tables materialized after each sweep run
+type RunSummaryStore interface {
+	// SaveRunSummary writes a run's summary with its test type counts and top effects,
+	// replacing any earlier summary of the run
+	SaveRunSummary(ctx context.Context, summary *models.RunSummary) error
+
+	// DeleteRunSummary removes a run's summary with its test type counts and top effects
+	DeleteRunSummary(ctx context.Context, runID string) error
+
+	// GetRunSummary returns a run's summary, or nil when the run has none
+	GetRunSummary(ctx context.Context, runID string) (*models.RunSummary, error)
+
+	// LatestSessionSummary returns the summary of a session's most recent run, or nil
+	LatestSessionSummary(ctx context.Context, sessionID string) (*models.RunSummary, error)
+
+	// ListWorkspaceSummaries returns up to limit of a workspace's run summaries, newest first,
+	// without their test type counts and top effects
+	ListWorkspaceSummaries(ctx context.Context, workspaceID string, limit int) ([]*models.RunSummary, error)
+
+	// ListDatasetRuns returns up to limit of the runs that swept a dataset, newest first,
+	// without their test type counts and top effects
+	ListDatasetRuns(ctx context.Context, datasetID string, limit int) ([]*models.RunSummary, error)
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"encoding/json"
 
 	"gohypo/models"
 
@@ -30,4 +31,11 @@ type SessionRepository interface {
 
 	// SetSessionError sets an error state for a session
 	SetSessionError(ctx context.Context, userID, sessionID uuid.UUID, errorMsg string) error
+
+	// DeleteSession removes a session with its prompts and hypotheses
+	DeleteSession(ctx context.Context, userID, sessionID uuid.UUID) error
+
+	// MergeSessionMetadata sets one metadata key to merge's result over its stored value (nil
+	// when unset). The session is locked meanwhile, so concurrent merges apply in turn.
+	MergeSessionMetadata(ctx context.Context, userID, sessionID uuid.UUID, key string, merge func(current json.RawMessage) (interface{}, error)) error
 }
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+
+	"gohypo/models"
+)
+
+// ShareLinkStore persists public read-only links to validation pages
+type ShareLinkStore interface {
+	// Create stores a new link, assigning its ID when empty
+	Create(ctx context.Context, link *models.ShareLink) error
+
+	// GetByToken returns the link for a token whether or not it is still active; NotFound
+	// when no link has that token
+	GetByToken(ctx context.Context, token string) (*models.ShareLink, error)
+
+	// ListByWorkspace returns a workspace's links, newest first
+	ListByWorkspace(ctx context.Context, workspaceID string) ([]*models.ShareLink, error)
+
+	// Revoke ends one of a workspace's links now; NotFound when the workspace has no such link
+	Revoke(ctx context.Context, workspaceID, id string) error
+}
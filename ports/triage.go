@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+
+	"gohypo/models"
+)
+
+// TriageStore persists analysts' verdicts on list items and the views they triage with
+type TriageStore interface {
+	// Mark records the marks, replacing any earlier mark of the same items
+	Mark(ctx context.Context, marks []*models.TriageMark) error
+
+	// ListMarks returns the marks on one of a workspace's lists
+	ListMarks(ctx context.Context, workspaceID, list string) ([]*models.TriageMark, error)
+
+	// CreateView stores a saved view, assigning its ID when empty
+	CreateView(ctx context.Context, view *models.SavedView) error
+
+	// ListViews returns a user's saved views of a list, oldest first; every list when list is empty
+	ListViews(ctx context.Context, userID, list string) ([]*models.SavedView, error)
+
+	// GetView returns one of a user's saved views; NotFound when the user has no such view
+	GetView(ctx context.Context, userID, id string) (*models.SavedView, error)
+
+	// DeleteView removes one of a user's saved views; NotFound when the user has no such view
+	DeleteView(ctx context.Context, userID, id string) error
+}
@@ -0,0 +1,53 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"gohypo/models"
+)
+
+// WebhookStore persists workspaces' webhooks and the log of deliveries made to them
+type WebhookStore interface {
+	// CreateWebhook stores a new webhook, assigning its ID when empty
+	CreateWebhook(ctx context.Context, hook *models.Webhook) error
+
+	// GetWebhook returns one of a workspace's webhooks; NotFound when it has no such webhook
+	GetWebhook(ctx context.Context, workspaceID, id string) (*models.Webhook, error)
+
+	// ListWebhooks returns a workspace's webhooks, newest first
+	ListWebhooks(ctx context.Context, workspaceID string) ([]*models.Webhook, error)
+
+	// SetWebhookActive pauses or resumes one of a workspace's webhooks; NotFound when it has
+	// no such webhook
+	SetWebhookActive(ctx context.Context, workspaceID, id string, active bool) error
+
+	// DeleteWebhook removes one of a workspace's webhooks with its delivery log; NotFound when
+	// it has no such webhook
+	DeleteWebhook(ctx context.Context, workspaceID, id string) error
+
+	// CreateDelivery stores a pending delivery, assigning its ID when empty
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+
+	// ClaimDueDeliveries returns up to limit pending deliveries that are due, pushing their
+	// next attempt lease into the future so concurrent dispatchers do not claim them too
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error)
+
+	// UpdateDelivery records the outcome of a delivery attempt: its status, attempts,
+	// response, error and next attempt time
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+
+	// GetDelivery returns one of a workspace's deliveries; NotFound when it has no such delivery
+	GetDelivery(ctx context.Context, workspaceID, id string) (*models.WebhookDelivery, error)
+
+	// ListDeliveries returns up to limit of a workspace's deliveries, newest first, optionally
+	// only those of one webhook
+	ListDeliveries(ctx context.Context, workspaceID, webhookID string, limit int) ([]*models.WebhookDelivery, error)
+}
+
+// EventPublisher notifies a workspace's downstream systems of a run lifecycle event
+type EventPublisher interface {
+	// Publish queues a delivery of event with data to each of the workspace's webhooks
+	// subscribed to it
+	Publish(ctx context.Context, workspaceID string, event models.WebhookEvent, data interface{}) error
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"gohypo/models"
+)
+
+// WorkspaceLLMSettingsStore holds per-workspace LLM defaults and their admin lock
+type WorkspaceLLMSettingsStore interface {
+	// Get returns the workspace's settings; a workspace with none stored gets empty, unlocked settings
+	Get(ctx context.Context, workspaceID string) (*models.WorkspaceLLMSettings, error)
+	// Put replaces the workspace's defaults; it fails with Forbidden while they are locked
+	Put(ctx context.Context, workspaceID string, settings models.LLMSettings) error
+	// Lock pins the workspace's settings, replacing them first when settings is non-nil
+	Lock(ctx context.Context, workspaceID string, settings *models.LLMSettings, lockedBy string) error
+	Unlock(ctx context.Context, workspaceID string) error
+}
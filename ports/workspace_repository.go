@@ -26,6 +26,9 @@ type WorkspaceRepository interface {
 	GetDefaultForUser(ctx context.Context, userID core.ID) (*dataset.Workspace, error)
 	GetWithDatasets(ctx context.Context, id core.ID) (*WorkspaceWithDatasets, error)
 
+	// Reassign hands a workspace and its datasets to another user; it stops being anyone's default
+	Reassign(ctx context.Context, id, userID core.ID) error
+
 	// Dataset relationship operations
 	CreateRelation(ctx context.Context, relation *dataset.DatasetRelation) error
 	GetRelations(ctx context.Context, workspaceID core.ID) ([]*dataset.DatasetRelation, error)
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"gohypo/domain/core"
+	apperrors "gohypo/internal/errors"
+	"gohypo/ui/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// withPrincipal attaches the user's column policy principal to ctx, so discovery and
+// resolution filter by the user's roles rather than treating the request as anonymous
+func (s *Server) withPrincipal(ctx context.Context, userID core.ID) context.Context {
+	if s.columnEnforcer == nil {
+		return ctx
+	}
+	return s.columnEnforcer.ContextFor(ctx, userID)
+}
+
+// handleAccessDecisions returns the most recent column policy decisions for auditing. The
+// log names users and the columns they asked for, so it is only served to an admin key.
+func (s *Server) handleAccessDecisions(c *gin.Context) {
+	if middleware.AdminCaller(c) == "" {
+		respondProblem(c, apperrors.Forbidden("The access decision log requires ADMIN_API_KEYS to be configured"))
+		return
+	}
+	if s.columnEnforcer == nil {
+		c.JSON(http.StatusOK, gin.H{"enabled": false, "decisions": []interface{}{}})
+		return
+	}
+
+	limit := 100
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":   true,
+		"decisions": s.columnEnforcer.RecentDecisions(limit),
+	})
+}
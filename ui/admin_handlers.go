@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// adminDatasetLimit caps the datasets listed and counted against a quota per workspace
+const adminDatasetLimit = 10000
+
+// handleAdminListUsers lists the users workspaces can be assigned to
+func (s *Server) handleAdminListUsers(c *gin.Context) {
+	if s.userRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "User service not available"})
+		return
+	}
+	users, err := s.userRepository.ListUsers(c.Request.Context())
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list users"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"users": users, "count": len(users)})
+}
+
+// handleAdminListWorkspaces lists a user's workspaces, the default user's without user_id
+func (s *Server) handleAdminListWorkspaces(c *gin.Context) {
+	if s.workspaceRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Workspace service not available"})
+		return
+	}
+	userID, ok := s.adminUser(c, c.Query("user_id"))
+	if !ok {
+		return
+	}
+	workspaces, err := s.workspaceRepository.GetByUserID(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list workspaces"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"user_id": userID, "workspaces": workspaces, "count": len(workspaces)})
+}
+
+// handleAdminCreateWorkspace creates a workspace for any user, the default user's without user_id
+func (s *Server) handleAdminCreateWorkspace(c *gin.Context) {
+	if s.workspaceRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Workspace service not available"})
+		return
+	}
+	var req struct {
+		Name        string                 `json:"name" binding:"required"`
+		Description string                 `json:"description"`
+		Color       string                 `json:"color"`
+		UserID      string                 `json:"user_id"`
+		Quota       *models.WorkspaceQuota `json:"quota,omitempty"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, apperrors.InvalidInput("name is required"))
+		return
+	}
+	userID, ok := s.adminUser(c, req.UserID)
+	if !ok {
+		return
+	}
+
+	workspace := dataset.NewWorkspace(userID, req.Name)
+	workspace.Description = req.Description
+	if req.Color != "" {
+		workspace.Color = req.Color
+	}
+	if req.Quota != nil {
+		if err := req.Quota.Validate(); err != nil {
+			respondProblem(c, apperrors.InvalidInput(err.Error()))
+			return
+		}
+		workspace.Metadata = map[string]interface{}{models.WorkspaceQuotaKey: *req.Quota}
+	}
+	if err := s.workspaceRepository.Create(c.Request.Context(), workspace); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to create workspace"))
+		return
+	}
+	if s.schemaProvisioner != nil {
+		if err := s.schemaProvisioner.Provision(c.Request.Context(), workspace.ID); err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to provision workspace schema"))
+			return
+		}
+	}
+	c.JSON(http.StatusCreated, workspace)
+}
+
+// handleAdminListDatasets lists any workspace's datasets
+func (s *Server) handleAdminListDatasets(c *gin.Context) {
+	workspace, ok := s.adminWorkspace(c)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > adminDatasetLimit {
+		respondProblem(c, apperrors.InvalidInput("limit must be between 1 and 10000"))
+		return
+	}
+	datasets, err := s.datasetRepository.GetByWorkspace(c.Request.Context(), workspace.ID, limit, 0)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list datasets"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"workspace_id": workspace.ID, "datasets": datasets, "count": len(datasets)})
+}
+
+// handleAdminAssignWorkspace hands a workspace and its datasets to another user
+func (s *Server) handleAdminAssignWorkspace(c *gin.Context) {
+	workspace, ok := s.adminWorkspace(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		UserID string `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, apperrors.InvalidInput("user_id is required"))
+		return
+	}
+	userID, ok := s.adminUser(c, req.UserID)
+	if !ok {
+		return
+	}
+	if err := s.workspaceRepository.Reassign(c.Request.Context(), workspace.ID, userID); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to assign workspace"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"workspace_id": workspace.ID, "user_id": userID, "previous_user_id": workspace.UserID})
+}
+
+// handleAdminGetQuota returns a workspace's quota with what it currently holds
+func (s *Server) handleAdminGetQuota(c *gin.Context) {
+	workspace, ok := s.adminWorkspace(c)
+	if !ok {
+		return
+	}
+	s.respondQuota(c, workspace)
+}
+
+// handleAdminPutQuota replaces a workspace's quota; zero limits are unlimited. Datasets
+// already stored are kept when a lower limit is set; only new ones are refused.
+func (s *Server) handleAdminPutQuota(c *gin.Context) {
+	workspace, ok := s.adminWorkspace(c)
+	if !ok {
+		return
+	}
+	var quota models.WorkspaceQuota
+	if err := c.ShouldBindJSON(&quota); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+	if err := quota.Validate(); err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	if workspace.Metadata == nil {
+		workspace.Metadata = make(map[string]interface{})
+	}
+	if quota.Unlimited() {
+		delete(workspace.Metadata, models.WorkspaceQuotaKey)
+	} else {
+		workspace.Metadata[models.WorkspaceQuotaKey] = quota
+	}
+	if err := s.workspaceRepository.Update(c.Request.Context(), workspace); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to update workspace"))
+		return
+	}
+	s.respondQuota(c, workspace)
+}
+
+// respondQuota writes a workspace's quota alongside its current dataset count and stored bytes
+func (s *Server) respondQuota(c *gin.Context, workspace *dataset.Workspace) {
+	datasets, storedBytes, err := s.workspaceUsage(c.Request.Context(), workspace.ID)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to measure workspace usage"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"workspace_id": workspace.ID,
+		"quota":        models.WorkspaceQuotaFrom(workspace.Metadata),
+		"datasets":     datasets,
+		"stored_bytes": storedBytes,
+	})
+}
+
+// admitDataset checks a workspace's quota before a dataset of incomingBytes is added to it
+func (s *Server) admitDataset(ctx context.Context, workspaceID core.ID, incomingBytes int64) error {
+	if s.workspaceRepository == nil || s.datasetRepository == nil {
+		return nil
+	}
+	workspace, err := s.workspaceRepository.GetByID(ctx, workspaceID)
+	if err != nil {
+		return nil // the upload reports a missing workspace itself
+	}
+	quota := models.WorkspaceQuotaFrom(workspace.Metadata)
+	if quota.Unlimited() {
+		return nil
+	}
+	datasets, storedBytes, err := s.workspaceUsage(ctx, workspaceID)
+	if err != nil {
+		return apperrors.Wrap(err, "Failed to measure workspace usage")
+	}
+	if err := quota.Admit(datasets, storedBytes, incomingBytes); err != nil {
+		return apperrors.Capacity(err.Error())
+	}
+	return nil
+}
+
+// workspaceUsage counts a workspace's datasets and the total size of their files
+func (s *Server) workspaceUsage(ctx context.Context, workspaceID core.ID) (int, int64, error) {
+	datasets, err := s.datasetRepository.GetByWorkspace(ctx, workspaceID, adminDatasetLimit, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	var storedBytes int64
+	for _, ds := range datasets {
+		storedBytes += ds.FileSize
+	}
+	return len(datasets), storedBytes, nil
+}
+
+// adminUser resolves a user ID from an admin request, the default user when empty
+func (s *Server) adminUser(c *gin.Context, id string) (core.ID, bool) {
+	if id == "" {
+		userID, err := s.getDefaultUserID(c.Request.Context())
+		if err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to get user"))
+			return "", false
+		}
+		return userID, true
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		respondProblem(c, apperrors.InvalidInput("user_id must be a UUID"))
+		return "", false
+	}
+	if s.userRepository != nil {
+		if _, err := s.userRepository.GetUserByID(c.Request.Context(), parsed); err != nil {
+			respondProblem(c, apperrors.NotFound("User"))
+			return "", false
+		}
+	}
+	return core.ID(parsed.String()), true
+}
+
+// adminWorkspace loads the workspace in the path for the admin API, whoever owns it
+func (s *Server) adminWorkspace(c *gin.Context) (*dataset.Workspace, bool) {
+	if s.workspaceRepository == nil || s.datasetRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Workspace service not available"})
+		return nil, false
+	}
+	workspace, err := s.workspaceRepository.GetByID(c.Request.Context(), core.ID(c.Param("id")))
+	if err != nil {
+		respondProblem(c, apperrors.NotFound("Workspace"))
+		return nil, false
+	}
+	return workspace, true
+}
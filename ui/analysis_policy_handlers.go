@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"net/http"
+
+	"gohypo/domain/dataset"
+	"gohypo/domain/stats"
+	apperrors "gohypo/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetAnalysisPolicy returns the significance and effect-size cutoffs a workspace's
+// sweeps, strength labels and reports use
+func (s *Server) handleGetAnalysisPolicy(c *gin.Context) {
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return
+	}
+	respondAnalysisPolicy(c, workspace)
+}
+
+// handlePutAnalysisPolicy replaces a workspace's analysis policy; unset cutoffs take the
+// defaults. It applies to sweeps run from now on; earlier runs keep the policy their manifest
+// records.
+func (s *Server) handlePutAnalysisPolicy(c *gin.Context) {
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return
+	}
+	var policy stats.AnalysisPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+	if err := policy.Validate(); err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	if workspace.Metadata == nil {
+		workspace.Metadata = make(map[string]interface{})
+	}
+	if policy = policy.WithDefaults(); policy == stats.DefaultAnalysisPolicy() {
+		delete(workspace.Metadata, stats.AnalysisPolicyKey)
+	} else {
+		workspace.Metadata[stats.AnalysisPolicyKey] = policy
+	}
+	if err := s.workspaceRepository.Update(c.Request.Context(), workspace); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to update workspace"))
+		return
+	}
+	respondAnalysisPolicy(c, workspace)
+}
+
+// respondAnalysisPolicy writes a workspace's policy and whether it is the default one
+func respondAnalysisPolicy(c *gin.Context, workspace *dataset.Workspace) {
+	policy := stats.AnalysisPolicyFrom(workspace.Metadata)
+	c.JSON(http.StatusOK, gin.H{
+		"workspace_id": workspace.ID,
+		"policy":       policy,
+		"default":      policy == stats.DefaultAnalysisPolicy(),
+		"description":  policy.Describe(),
+	})
+}
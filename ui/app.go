@@ -15,6 +15,7 @@ import (
 	"gohypo/domain/core"
 	"gohypo/internal/testkit"
 	"gohypo/ports"
+	"gohypo/ui/services"
 )
 
 //go:embed templates/** static/**
@@ -99,7 +100,11 @@ func NewApp(config Config) (*App, error) {
 			}
 			return b
 		},
-		"upper": strings.ToUpper,
+		"upper":          strings.ToUpper,
+		"provenanceChip": provenanceChip,
+		"provenanceChipStyle": func() template.CSS {
+			return template.CSS(provenanceChipStyle)
+		},
 		"until": func(n int) []int {
 			res := make([]int, n)
 			for i := range res {
@@ -157,8 +162,10 @@ func (a *App) handleFieldsList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	readable, _ := services.ReadableArtifacts(allArtifacts)
+
 	fieldSet := make(map[string]bool)
-	for _, artifact := range allArtifacts {
+	for _, artifact := range readable {
 		if artifact.Kind == core.ArtifactRelationship {
 			if payload, ok := artifact.Payload.(map[string]interface{}); ok {
 				if vx, ok := payload["variable_x"].(string); ok && vx != "" {
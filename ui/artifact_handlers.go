@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"net/http"
+
+	"gohypo/domain/core"
+	apperrors "gohypo/internal/errors"
+	"gohypo/ui/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetArtifact returns an artifact as the UI reads it. An artifact produced by a newer
+// engine than this UI reads comes back with a notice and its raw JSON instead of a payload;
+// htmx requests get that rendered as a panel.
+func (s *Server) handleGetArtifact(c *gin.Context) {
+	artifact, err := s.reader.GetArtifact(c.Request.Context(), core.ArtifactID(c.Param("id")))
+	if err != nil || artifact == nil {
+		respondProblem(c, apperrors.NotFound("Artifact"))
+		return
+	}
+
+	view := services.ArtifactView(*artifact)
+	if supported, _ := view["supported"].(bool); !supported && c.GetHeader("HX-Request") == "true" {
+		c.Header("Content-Type", "text/html")
+		c.String(http.StatusOK, s.renderService.RenderArtifactFallback(view))
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
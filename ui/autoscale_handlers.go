@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleAutoscalingStatus returns the validation pool's policy, its latest scale decision
+// and the current queue depth
+func (s *Server) handleAutoscalingStatus(c *gin.Context) {
+	if s.autoscaler == nil || s.validationQueue == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Autoscaling not available"})
+		return
+	}
+	policy := s.autoscaler.Policy()
+	c.JSON(http.StatusOK, gin.H{
+		"policy": gin.H{
+			"min_workers":              policy.MinWorkers,
+			"max_workers":              policy.MaxWorkers,
+			"target_per_worker":        policy.TargetPerWorker,
+			"scale_down_delay_seconds": policy.ScaleDownDelay.Seconds(),
+			"interval_seconds":         policy.Interval.Seconds(),
+		},
+		"last_decision": s.autoscaler.Status(),
+		"depth":         s.validationQueue.Depth(),
+	})
+}
+
+// handleAutoscalingMetrics exposes queue depth and pool size as Prometheus gauges, so a
+// HorizontalPodAutoscaler can scale replicas on them through a metrics adapter
+func (s *Server) handleAutoscalingMetrics(c *gin.Context) {
+	if s.autoscaler == nil || s.validationQueue == nil {
+		c.String(http.StatusServiceUnavailable, "autoscaling not available\n")
+		return
+	}
+	depth := s.validationQueue.Depth()
+	decision := s.autoscaler.Status()
+	policy := s.autoscaler.Policy()
+
+	var b strings.Builder
+	gauge := func(name, help string, value int) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", name, help, name, name, value)
+	}
+	gauge("gohypo_validation_queue_depth", "Validations waiting for a worker.", depth.Queued)
+	gauge("gohypo_validation_running", "Validations running.", depth.Running)
+	gauge("gohypo_validation_workers", "Validation pool size.", decision.Workers)
+	gauge("gohypo_validation_workers_desired", "Validation pool size demand calls for at the last evaluation.", decision.Desired)
+	gauge("gohypo_validation_workers_min", "Smallest pool size the policy allows.", policy.MinWorkers)
+	gauge("gohypo_validation_workers_max", "Largest pool size the policy allows.", policy.MaxWorkers)
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
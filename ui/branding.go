@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"fmt"
+	"html/template"
+	"regexp"
+
+	"gohypo/models"
+)
+
+var brandHexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// brandStyle declares the branding colors as CSS custom properties (--brand-primary,
+// --brand-accent) for templates and generated reports to style with. Colors that are not
+// plain hex fall back to the defaults, so the rule can be placed in pages unescaped.
+func brandStyle(b models.Branding) template.CSS {
+	defaults := models.DefaultBranding()
+	primary, accent := b.PrimaryColor, b.AccentColor
+	if !brandHexColor.MatchString(primary) {
+		primary = defaults.PrimaryColor
+	}
+	if !brandHexColor.MatchString(accent) {
+		accent = defaults.AccentColor
+	}
+	return template.CSS(fmt.Sprintf(":root { --brand-primary: %s; --brand-accent: %s; }", primary, accent))
+}
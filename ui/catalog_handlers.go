@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	domainDataset "gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetCatalog lists a workspace's datasets with their fields, inferred types, quality
+// scores, discovered relations and the hypotheses referencing each field
+func (s *Server) handleGetCatalog(c *gin.Context) {
+	catalog, ok := s.workspaceCatalog(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, catalog)
+}
+
+// handleCatalogPage renders the workspace catalog for browsing
+func (s *Server) handleCatalogPage(c *gin.Context) {
+	catalog, ok := s.workspaceCatalog(c)
+	if !ok {
+		return
+	}
+	var page strings.Builder
+	data := gin.H{
+		"Catalog": catalog,
+		"Title":   s.branding.Title("Data catalog"),
+		"Brand":   s.branding,
+		"Style":   brandStyle(s.branding),
+	}
+	if err := catalogTemplate.Execute(&page, data); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to render catalog page"))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page.String()))
+}
+
+// workspaceCatalog loads the catalog of a workspace owned by the default user
+func (s *Server) workspaceCatalog(c *gin.Context) (*domainDataset.Catalog, bool) {
+	if s.catalog == nil || s.workspaceRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Data catalog not available"})
+		return nil, false
+	}
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return nil, false
+	}
+	catalog, err := s.catalog.GetCatalog(c.Request.Context(), workspace.ID)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to build data catalog"))
+		return nil, false
+	}
+	return catalog, true
+}
+
+var catalogTemplate = template.Must(template.New("catalog").Funcs(template.FuncMap{
+	"percent": func(v float64) string { return fmt.Sprintf("%.0f%%", v*100) },
+	"join":    func(list []string) string { return strings.Join(list, ", ") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+{{.Style}}
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 0; color: var(--brand-accent); font-size: 14px; }
+header { padding: 12px 20px; border-bottom: 3px solid var(--brand-primary); }
+header h1 { font-size: 16px; margin: 0; }
+main { padding: 12px 20px 40px; max-width: 72rem; }
+section { margin-bottom: 28px; }
+h2 { font-size: 15px; margin: 0 0 4px; }
+.meta { color: #6b7280; margin: 0 0 8px; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #f3f4f6; vertical-align: top; }
+th { color: #6b7280; font-weight: 500; }
+.pii { color: #b45309; }
+.passed { color: #166534; }
+.failed { color: #991b1b; }
+</style>
+</head>
+<body>
+<header><h1>Data catalog · {{len .Catalog.Datasets}} datasets</h1></header>
+<main>
+{{range .Catalog.Datasets}}<section>
+<h2>{{.Name}}</h2>
+<p class="meta">{{if .Domain}}{{.Domain}} · {{end}}{{.RecordCount}} records · {{len .Fields}} fields · quality {{percent .QualityScore}} · {{.Status}} · dictionary: <a href="/api/datasets/{{.ID}}/dictionary?format=markdown">Markdown</a>, <a href="/api/datasets/{{.ID}}/dictionary?format=csv">CSV</a>, <a href="/api/datasets/{{.ID}}/dictionary">JSON</a></p>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+<table>
+<tr><th>Field</th><th>Type</th><th>Missing</th><th>Distinct</th><th>Quality</th><th>Hypotheses</th></tr>
+{{range .Fields}}<tr>
+<td>{{.Name}}{{if .PII}} <span class="pii">PII: {{.PII}}{{if .Excluded}}, excluded{{end}}</span>{{end}}</td>
+<td>{{.DataType}}</td><td>{{percent .MissingRate}}</td><td>{{.UniqueCount}}</td><td>{{percent .QualityScore}}</td>
+<td>{{range .Hypotheses}}<div class="{{if .Passed}}passed{{else}}failed{{end}}">{{.Role}}: {{.Title}}</div>{{end}}</td>
+</tr>
+{{end}}</table>
+{{if .Relations}}<p class="meta">Related to: {{range $i, $r := .Relations}}{{if $i}}; {{end}}{{$r.DatasetName}} ({{$r.RelationType}}, {{percent $r.Confidence}}{{if $r.CommonFields}} via {{join $r.CommonFields}}{{end}}){{end}}</p>{{end}}
+</section>
+{{else}}<p>This workspace has no datasets yet.</p>
+{{end}}</main>
+</body>
+</html>
+`))
@@ -0,0 +1,17 @@
+package ui
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetDatasetColumns returns the mapping table of a dataset's columns: each variable
+// key with the header it was normalized from and the unit stripped from it
+func (s *Server) handleGetDatasetColumns(c *gin.Context) {
+	ds, ok := s.piiDataset(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"dataset_id": ds.ID, "columns": ds.Metadata.ColumnNames()})
+}
@@ -6,8 +6,11 @@ import (
 	"gohypo/ai"
 	"gohypo/domain/core"
 	"gohypo/domain/stats"
+	"gohypo/internal/demo"
+	apperrors "gohypo/internal/errors"
 	"gohypo/models"
 	"gohypo/ports"
+	"gohypo/ui/services"
 	"log"
 	"net/http"
 	"strconv"
@@ -106,17 +109,6 @@ func (s *Server) handleIndex(c *gin.Context) {
 
 						fields = append(fields, fieldStats)
 					}
-				} else if ds.FieldCount > 0 {
-					// Fallback to placeholder fields if metadata not available
-					fields = make([]*FieldStats, 0, ds.FieldCount)
-					for i := 0; i < int(ds.FieldCount) && i < 10; i++ {
-						fields = append(fields, &FieldStats{
-							Name:        fmt.Sprintf("field_%d", i+1),
-							Type:        "numeric",
-							SampleSize:  int(ds.RecordCount),
-							MissingRate: 0.0,
-						})
-					}
 				}
 
 				dataset := map[string]interface{}{
@@ -125,6 +117,7 @@ func (s *Server) handleIndex(c *gin.Context) {
 					"RecordCount": ds.RecordCount,
 					"Fields":      fields,
 					"Status":      string(ds.Status),
+					"Demo":        ds.Source == demo.SourceKind, // synthetic sample, labeled as such
 				}
 				storedDatasets = append(storedDatasets, dataset)
 			}
@@ -159,7 +152,7 @@ func (s *Server) handleIndex(c *gin.Context) {
 		falsificationPrompt, _ := promptManager.LoadPrompt("falsification_research")
 
 		data := map[string]interface{}{
-			"Title":      "GoHypo - Loading Dataset",
+			"Title":      s.branding.Title("Loading Dataset"),
 			"Loading":    true,
 			"FieldStats": []*FieldStats{},
 			"FieldCount": 0,
@@ -176,6 +169,7 @@ func (s *Server) handleIndex(c *gin.Context) {
 				"Fields":      []*FieldStats{},
 			},
 			"Hypotheses":           hypotheses,
+			"DecisionSummary":      s.decisionSummary(c.Request.Context(), currentWorkspaceID),
 			"GreenfieldPrompt":     greenfieldPrompt,
 			"LogicalAuditorPrompt": logicalAuditorPrompt,
 			"FalsificationPrompt":  falsificationPrompt,
@@ -215,6 +209,7 @@ func (s *Server) handleIndex(c *gin.Context) {
 		}
 	}
 	cacheData["Hypotheses"] = hypotheses
+	cacheData["DecisionSummary"] = s.decisionSummary(c.Request.Context(), currentWorkspaceID)
 
 	// Load AI prompts for debugging
 	promptManager := ai.NewPromptManager("prompts")
@@ -225,7 +220,7 @@ func (s *Server) handleIndex(c *gin.Context) {
 	cacheData["GreenfieldPrompt"] = greenfieldPrompt
 	cacheData["LogicalAuditorPrompt"] = logicalAuditorPrompt
 	cacheData["FalsificationPrompt"] = falsificationPrompt
-	cacheData["Title"] = "GoHypo - Research Dashboard"
+	cacheData["Title"] = s.branding.Title("Research Dashboard")
 	s.renderTemplate(c, "main.html", cacheData)
 }
 
@@ -331,6 +326,7 @@ func (s *Server) handleLoadMoreFields(c *gin.Context) {
 	if err != nil {
 		relArtifacts = []core.Artifact{}
 	}
+	relArtifacts, _ = services.ReadableArtifacts(relArtifacts)
 
 	fieldStatsMap := make(map[string]*FieldStats)
 	for i := start; i < end; i++ {
@@ -425,7 +421,9 @@ func (s *Server) handleLoadMoreFields(c *gin.Context) {
 			}
 		}
 
+		provenance := storedProvenance(artifact)
 		if statsX, exists := fieldStatsMap[varX]; exists {
+			statsX.Provenance = provenance
 			if missingRateX > 0 || sampleSize > 0 {
 				statsX.MissingRate = missingRateX
 				statsX.MissingRatePct = fmt.Sprintf("%.1f", missingRateX*100)
@@ -447,6 +445,7 @@ func (s *Server) handleLoadMoreFields(c *gin.Context) {
 		}
 
 		if statsY, exists := fieldStatsMap[varY]; exists {
+			statsY.Provenance = provenance
 			if missingRateY > 0 || sampleSize > 0 {
 				statsY.MissingRate = missingRateY
 				statsY.MissingRatePct = fmt.Sprintf("%.1f", missingRateY*100)
@@ -499,7 +498,7 @@ func (s *Server) handleDatasetsList(c *gin.Context) {
 	storedDatasets, err := s.datasetRepository.GetByUserID(c.Request.Context(), userID, 100, 0) // Limit 100, offset 0
 	if err != nil {
 		log.Printf("[handleDatasetsList] Error retrieving datasets: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve datasets"})
+		respondProblem(c, apperrors.Wrap(err, "Failed to retrieve datasets"))
 		return
 	}
 
@@ -599,6 +598,8 @@ func (s *Server) handleDatasetFields(c *gin.Context) {
 	for i, field := range ds.Metadata.Fields {
 		fields[i] = map[string]interface{}{
 			"name":         field.Name,
+			"displayName":  field.DisplayName(),
+			"unit":         field.Unit,
 			"type":         string(field.DataType),
 			"sampleSize":   len(field.SampleValues),
 			"missingCount": field.MissingCount,
@@ -839,8 +840,10 @@ func (s *Server) handleStoredDatasetPreview(c *gin.Context, datasetID string) {
 	fields := make([]map[string]interface{}, len(ds.Metadata.Fields))
 	for i, field := range ds.Metadata.Fields {
 		fields[i] = map[string]interface{}{
-			"name": field.Name,
-			"type": field.DataType,
+			"name":        field.Name,
+			"displayName": field.DisplayName(),
+			"unit":        field.Unit,
+			"type":        field.DataType,
 		}
 	}
 
@@ -7,9 +7,12 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
+	apperrors "gohypo/internal/errors"
 	"gohypo/internal/research"
+	"gohypo/models"
 	"gohypo/ui/services"
 
 	"github.com/gin-gonic/gin"
@@ -213,3 +216,120 @@ func (h *DataHandler) HandleHypothesisEvidence(storage *research.ResearchStorage
 		c.JSON(http.StatusOK, hypothesis)
 	}
 }
+
+// HandleHypothesisExplanation explains a hypothesis' verdict: which gates mattered, the margin
+// each passed or failed by, and what would change it. format=markdown returns the report section.
+func (h *DataHandler) HandleHypothesisExplanation(storage *research.ResearchStorage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+
+		hypothesis, err := storage.GetByID(c.Request.Context(), idStr)
+		if err != nil {
+			log.Printf("[API] Failed to get hypothesis %s: %v", idStr, err)
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Hypothesis not found",
+			})
+			return
+		}
+
+		explanation := models.VerdictExplanationOf(hypothesis)
+		if c.Query("format") == "markdown" {
+			report := explanation.Markdown()
+			if caveats := warningsMarkdown(describeWarnings(models.DataWarningsOf(hypothesis))); caveats != "" {
+				report += "\n" + caveats
+			}
+			if source := provenanceMarkdown(hypothesisProvenance(hypothesis)); source != "" {
+				report += "\n" + source
+			}
+			c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(report))
+			return
+		}
+		c.JSON(http.StatusOK, explanation)
+	}
+}
+
+// HandleHypothesisCounterexamples returns the evidence that killed a failed hypothesis: the
+// segment where the effect reverses, the time window where it disappears, or the confounder
+// that absorbs it. Passing hypotheses have none.
+func (h *DataHandler) HandleHypothesisCounterexamples(storage *research.ResearchStorage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+
+		hypothesis, err := storage.GetByID(c.Request.Context(), idStr)
+		if err != nil {
+			log.Printf("[API] Failed to get hypothesis %s: %v", idStr, err)
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Hypothesis not found",
+			})
+			return
+		}
+
+		counterexamples := models.CounterexamplesOf(hypothesis)
+		c.JSON(http.StatusOK, gin.H{
+			"hypothesis_id":   hypothesis.ID,
+			"passed":          hypothesis.Passed,
+			"counterexamples": counterexamples,
+			"count":           len(counterexamples),
+		})
+	}
+}
+
+// HandleHypothesisMetric exports a validated hypothesis as a monitoring metric: a SQL query
+// (format=sql, the default) or its full definition (format=json) for the user's BI tool.
+// The warehouse side is described by table, time_column, grain, dialect and repeated
+// segment filters such as segment=region=EU.
+func (h *DataHandler) HandleHypothesisMetric(storage *research.ResearchStorage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+
+		hypothesis, err := storage.GetByID(c.Request.Context(), idStr)
+		if err != nil {
+			log.Printf("[API] Failed to get hypothesis %s: %v", idStr, err)
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Hypothesis not found",
+			})
+			return
+		}
+
+		opts := models.MetricOptions{
+			Table:      c.Query("table"),
+			TimeColumn: c.Query("time_column"),
+			Grain:      c.Query("grain"),
+			Dialect:    c.Query("dialect"),
+		}
+		for _, raw := range c.QueryArray("segment") {
+			segment, err := parseSegmentFilter(raw)
+			if err != nil {
+				respondProblem(c, apperrors.InvalidInput(err.Error()))
+				return
+			}
+			opts.Segments = append(opts.Segments, segment)
+		}
+
+		metric, err := models.MetricDefinitionFromHypothesis(hypothesis, opts)
+		if err != nil {
+			respondProblem(c, apperrors.InvalidInput(err.Error()))
+			return
+		}
+
+		if c.DefaultQuery("format", "sql") == "json" {
+			c.JSON(http.StatusOK, metric)
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.sql\"", metric.Name))
+		c.Data(http.StatusOK, "application/sql; charset=utf-8", []byte(metric.SQL))
+	}
+}
+
+// parseSegmentFilter splits "column<op>value" at the first operator in the string
+func parseSegmentFilter(raw string) (models.SegmentFilter, error) {
+	i := strings.IndexAny(raw, "=!<>")
+	if i <= 0 {
+		return models.SegmentFilter{}, fmt.Errorf("segment %q must look like column=value", raw)
+	}
+	op := raw[i : i+1]
+	if i+1 < len(raw) && raw[i+1] == '=' && op != "=" {
+		op += "="
+	}
+	return models.SegmentFilter{Column: raw[:i], Operator: op, Value: raw[i+len(op):]}, nil
+}
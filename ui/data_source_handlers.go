@@ -0,0 +1,258 @@
+package ui
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ui/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// firstRefreshFailed is reported when a source was saved but its refresh_now pull failed.
+// The cause is logged, and the source's last_error holds a summary.
+const firstRefreshFailed = "Source saved, but the first refresh failed; see the source's last_error"
+
+// handleCreateSQLSource registers a SQL table or query as a workspace data source, owned by
+// the workspace's owner. With refresh_now the first pull starts straight away. A source runs
+// any read query with credentials the server holds, so it takes an authenticated admin.
+func (s *Server) handleCreateSQLSource(c *gin.Context) {
+	if s.sqlSources == nil || s.workspaceRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Data sources not available"})
+		return
+	}
+	if middleware.AdminCaller(c) == "" {
+		respondProblem(c, apperrors.Forbidden("Creating SQL data sources requires ADMIN_API_KEYS to be configured"))
+		return
+	}
+	workspace, err := s.workspaceRepository.GetByID(c.Request.Context(), core.ID(c.Param("id")))
+	if err != nil || workspace == nil {
+		respondProblem(c, apperrors.NotFound("Workspace"))
+		return
+	}
+	var body struct {
+		models.SQLDataSource
+		RefreshNow bool `json:"refresh_now"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+	source := body.SQLDataSource
+	source.ID = ""
+	source.WorkspaceID = string(workspace.ID)
+	source.UserID = string(workspace.UserID)
+	source.LastRefreshedAt, source.LastDatasetID, source.LastError = nil, "", ""
+	if err := source.Validate(); err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	if err := s.sqlSources.Create(c.Request.Context(), &source); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to save SQL data source"))
+		return
+	}
+
+	response := gin.H{"source": source.Redacted()}
+	if body.RefreshNow && s.sourceRefresher != nil {
+		datasetID, err := s.sourceRefresher.RefreshSQL(c.Request.Context(), &source)
+		if err != nil {
+			log.Printf("[DataSources] First refresh of SQL source %s failed: %v", source.ID, err)
+			response["refresh_error"] = firstRefreshFailed
+		} else {
+			response["dataset_id"] = datasetID
+		}
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// handleListSQLSources lists a workspace's SQL data sources with their last refresh
+func (s *Server) handleListSQLSources(c *gin.Context) {
+	workspace, ok := s.dataSourceWorkspace(c)
+	if !ok {
+		return
+	}
+	sources, err := s.sqlSources.ListByWorkspace(c.Request.Context(), string(workspace.ID))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list SQL data sources"))
+		return
+	}
+	redacted := make([]*models.SQLDataSource, len(sources))
+	for i, source := range sources {
+		redacted[i] = source.Redacted()
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"workspace_id": workspace.ID,
+		"sources":      redacted,
+		"count":        len(redacted),
+	})
+}
+
+// handleRefreshSQLSource pulls a source now, registering a new dataset from its rows
+func (s *Server) handleRefreshSQLSource(c *gin.Context) {
+	workspace, ok := s.dataSourceWorkspace(c)
+	if !ok {
+		return
+	}
+	if s.sourceRefresher == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Dataset processor not available"})
+		return
+	}
+	source, err := s.sqlSources.Get(c.Request.Context(), string(workspace.ID), c.Param("sourceId"))
+	if err != nil {
+		respondProblem(c, err)
+		return
+	}
+	datasetID, err := s.sourceRefresher.RefreshSQL(c.Request.Context(), source)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to refresh SQL data source"))
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"source_id":  source.ID,
+		"dataset_id": datasetID,
+		"message":    "Rows pulled, dataset processing started",
+	})
+}
+
+// handleDeleteSQLSource removes a source; the datasets it registered are kept
+func (s *Server) handleDeleteSQLSource(c *gin.Context) {
+	workspace, ok := s.dataSourceWorkspace(c)
+	if !ok {
+		return
+	}
+	if err := s.sqlSources.Delete(c.Request.Context(), string(workspace.ID), c.Param("sourceId")); err != nil {
+		respondProblem(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// handleCreateURLSource registers an HTTPS CSV URL or Google Sheets link as a workspace data
+// source. With refresh_now the first fetch starts straight away.
+func (s *Server) handleCreateURLSource(c *gin.Context) {
+	workspace, ok := s.dataSourceWorkspace(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		models.URLDataSource
+		RefreshNow bool `json:"refresh_now"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+	source := body.URLDataSource
+	source.WorkspaceID = string(workspace.ID)
+	source.UserID = string(workspace.UserID)
+	response, err := s.createURLSource(c.Request.Context(), &source, body.RefreshNow)
+	if err != nil {
+		respondProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// createURLSource validates and stores a URL source, fetching it straight away when asked
+func (s *Server) createURLSource(ctx context.Context, source *models.URLDataSource, refreshNow bool) (gin.H, error) {
+	source.ID = ""
+	source.LastRefreshedAt, source.LastDatasetID, source.LastChecksum, source.LastError = nil, "", "", ""
+	if err := source.Validate(); err != nil {
+		return nil, apperrors.InvalidInput(err.Error())
+	}
+	if err := s.urlSources.Create(ctx, source); err != nil {
+		return nil, apperrors.Wrap(err, "Failed to save URL data source")
+	}
+
+	response := gin.H{"source": source.Redacted()}
+	if refreshNow && s.sourceRefresher != nil {
+		datasetID, err := s.sourceRefresher.RefreshURL(ctx, source)
+		if err != nil {
+			log.Printf("[DataSources] First refresh of URL source %s failed: %v", source.ID, err)
+			response["refresh_error"] = firstRefreshFailed
+		} else {
+			response["dataset_id"] = datasetID
+		}
+	}
+	return response, nil
+}
+
+// handleListURLSources lists a workspace's URL data sources with their last fetch
+func (s *Server) handleListURLSources(c *gin.Context) {
+	workspace, ok := s.dataSourceWorkspace(c)
+	if !ok {
+		return
+	}
+	sources, err := s.urlSources.ListByWorkspace(c.Request.Context(), string(workspace.ID))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list URL data sources"))
+		return
+	}
+	redacted := make([]*models.URLDataSource, len(sources))
+	for i, source := range sources {
+		redacted[i] = source.Redacted()
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"workspace_id": workspace.ID,
+		"sources":      redacted,
+		"count":        len(redacted),
+	})
+}
+
+// handleRefreshURLSource fetches a source now, registering a new dataset when the file changed
+func (s *Server) handleRefreshURLSource(c *gin.Context) {
+	workspace, ok := s.dataSourceWorkspace(c)
+	if !ok {
+		return
+	}
+	if s.sourceRefresher == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Dataset processor not available"})
+		return
+	}
+	source, err := s.urlSources.Get(c.Request.Context(), string(workspace.ID), c.Param("sourceId"))
+	if err != nil {
+		respondProblem(c, err)
+		return
+	}
+	datasetID, err := s.sourceRefresher.RefreshURL(c.Request.Context(), source)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to refresh URL data source"))
+		return
+	}
+	if datasetID == "" {
+		c.JSON(http.StatusOK, gin.H{"source_id": source.ID, "unchanged": true, "dataset_id": source.LastDatasetID})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"source_id":  source.ID,
+		"dataset_id": datasetID,
+		"message":    "File fetched, dataset processing started",
+	})
+}
+
+// handleDeleteURLSource removes a source; the datasets it registered are kept
+func (s *Server) handleDeleteURLSource(c *gin.Context) {
+	workspace, ok := s.dataSourceWorkspace(c)
+	if !ok {
+		return
+	}
+	if err := s.urlSources.Delete(c.Request.Context(), string(workspace.ID), c.Param("sourceId")); err != nil {
+		respondProblem(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// dataSourceWorkspace loads a workspace owned by the default user for data source requests
+func (s *Server) dataSourceWorkspace(c *gin.Context) (*domainDataset.Workspace, bool) {
+	if s.sqlSources == nil || s.urlSources == nil || s.workspaceRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Data sources not available"})
+		return nil, false
+	}
+	return s.ownedWorkspace(c)
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"gohypo/domain/core"
 	"gohypo/domain/dataset"
 	processor "gohypo/internal/dataset"
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -63,9 +66,11 @@ func (s *Server) loadDatasetInfo(ctx context.Context) error {
 	if currentDataset.Metadata.Fields != nil && len(currentDataset.Metadata.Fields) > 0 {
 		for _, field := range currentDataset.Metadata.Fields {
 			fieldStat := map[string]interface{}{
-				"name":       field.Name,
-				"type":       string(field.DataType),
-				"sampleSize": currentDataset.RecordCount,
+				"name":        field.Name,
+				"displayName": field.DisplayName(),
+				"unit":        field.Unit,
+				"type":        string(field.DataType),
+				"sampleSize":  currentDataset.RecordCount,
 			}
 			fieldStats = append(fieldStats, fieldStat)
 		}
@@ -162,7 +167,7 @@ func (s *Server) handleFileUpload(c *gin.Context) {
 	contentType := header.Header.Get("Content-Type")
 
 	// Check file extension
-	validExtensions := []string{".xlsx", ".xls", ".csv"}
+	validExtensions := []string{".xlsx", ".xls", ".csv", ".json", ".jsonl", ".ndjson"}
 	hasValidExtension := false
 	for _, ext := range validExtensions {
 		if strings.HasSuffix(strings.ToLower(filename), ext) {
@@ -173,7 +178,7 @@ func (s *Server) handleFileUpload(c *gin.Context) {
 
 	if !hasValidExtension {
 		log.Printf("[handleFileUpload] FAILED - Invalid file extension: %s", filename)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Only Excel (.xlsx, .xls) and CSV (.csv) files are allowed"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only Excel (.xlsx, .xls), CSV (.csv) and JSON event (.json, .jsonl) files are allowed"})
 		return
 	}
 
@@ -184,6 +189,8 @@ func (s *Server) handleFileUpload(c *gin.Context) {
 		"text/csv",
 		"application/csv",
 		"text/plain", // Some CSV files might be detected as plain text
+		"application/json",
+		"application/x-ndjson",
 	}
 
 	isValidMimeType := false
@@ -194,6 +201,16 @@ func (s *Server) handleFileUpload(c *gin.Context) {
 		}
 	}
 
+	// Browsers rarely know JSONL; name event files by their extension
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		contentType = "application/json"
+		isValidMimeType = true
+	case ".jsonl", ".ndjson":
+		contentType = "application/x-ndjson"
+		isValidMimeType = true
+	}
+
 	if !isValidMimeType && !strings.Contains(contentType, "excel") && !strings.Contains(contentType, "csv") {
 		log.Printf("[handleFileUpload] WARNING - Unexpected MIME type: %s for file: %s", contentType, filename)
 		// Don't reject yet, but log the warning - some systems might not detect MIME types correctly
@@ -212,7 +229,7 @@ func (s *Server) handleFileUpload(c *gin.Context) {
 			defaultWorkspace, err := s.ensureDefaultWorkspace(c.Request.Context(), userID)
 			if err != nil {
 				log.Printf("[handleFileUpload] Failed to ensure default workspace: %v", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to setup workspace"})
+				respondProblem(c, apperrors.Wrap(err, "Failed to setup workspace"))
 				return
 			}
 			workspaceID = defaultWorkspace.ID
@@ -222,13 +239,28 @@ func (s *Server) handleFileUpload(c *gin.Context) {
 		}
 	}
 
+	if err := s.admitDataset(c.Request.Context(), workspaceID, header.Size); err != nil {
+		respondProblem(c, err)
+		return
+	}
+
 	// Create upload object for processor
 	upload := &dataset.DatasetUpload{
 		UserID:      userID,
 		WorkspaceID: workspaceID,
 		Filename:    filename,
 		File:        file,
-		MimeType:    header.Header.Get("Content-Type"),
+		MimeType:    contentType,
+		Locale:      c.PostForm("locale"),    // e.g. de-DE; detected when empty
+		TimeZone:    c.PostForm("time_zone"), // e.g. Europe/Berlin; UTC when empty
+	}
+	if _, err := dataset.ResolveLocale(upload.Locale, nil); err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	if _, err := core.ResolveTimeZone(upload.TimeZone, core.TimeZoneFromDataset); err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
 	}
 
 	// Process the dataset using the new processor
@@ -236,7 +268,7 @@ func (s *Server) handleFileUpload(c *gin.Context) {
 	datasetID, err := s.datasetProcessor.ProcessUpload(ctx, upload)
 	if err != nil {
 		log.Printf("[handleFileUpload] FAILED - Dataset processing failed: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to process dataset: %v", err)})
+		respondProblem(c, apperrors.Wrap(err, "Failed to process dataset"))
 		return
 	}
 
@@ -249,6 +281,96 @@ func (s *Server) handleFileUpload(c *gin.Context) {
 	})
 }
 
+// handleImportURL ingests the CSV behind an HTTPS URL or a Google Sheets link through the
+// same pipeline as uploads. With refresh_minutes the URL is kept as a data source of the
+// workspace and re-fetched on that schedule.
+func (s *Server) handleImportURL(c *gin.Context) {
+	if s.datasetProcessor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Dataset processor not available"})
+		return
+	}
+	var req struct {
+		URL            string `json:"url" form:"url" binding:"required"`
+		WorkspaceID    string `json:"workspace_id" form:"workspace_id"`
+		Name           string `json:"name" form:"name"`
+		Token          string `json:"token" form:"token"` // access token for sheets that are not public
+		RefreshMinutes int    `json:"refresh_minutes" form:"refresh_minutes"`
+	}
+	if err := c.ShouldBind(&req); err != nil {
+		respondProblem(c, apperrors.InvalidInput("url is required"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	userID := core.ID("550e8400-e29b-41d4-a716-446655440000") // Default user for single-user mode
+	workspaceID := core.ID(req.WorkspaceID)
+	if workspaceID == "" && s.workspaceRepository != nil {
+		defaultWorkspace, err := s.ensureDefaultWorkspace(ctx, userID)
+		if err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to setup workspace"))
+			return
+		}
+		workspaceID = defaultWorkspace.ID
+	}
+
+	if req.RefreshMinutes > 0 {
+		if s.urlSources == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Scheduled re-fetch not available"})
+			return
+		}
+		source := &models.URLDataSource{
+			WorkspaceID:    string(workspaceID),
+			UserID:         string(userID),
+			Name:           req.Name,
+			URL:            req.URL,
+			Token:          req.Token,
+			RefreshMinutes: req.RefreshMinutes,
+		}
+		response, err := s.createURLSource(ctx, source, true)
+		if err != nil {
+			respondProblem(c, err)
+			return
+		}
+		response["workspace_id"] = workspaceID
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	if _, _, err := models.ResolveSourceURL(req.URL); err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	token, err := models.ResolveSecret(req.Token)
+	if err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	remote, err := s.datasetProcessor.FetchRemote(ctx, req.URL, token)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to fetch dataset"))
+		return
+	}
+	if err := s.admitDataset(ctx, workspaceID, int64(len(remote.Data))); err != nil {
+		respondProblem(c, err)
+		return
+	}
+	name := req.Name
+	if name == "" {
+		name = models.DefaultURLSourceName(req.URL)
+	}
+	datasetID, err := s.datasetProcessor.ProcessRemote(context.Background(), userID, workspaceID, name, "", remote)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to process dataset"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Dataset fetched and processing started",
+		"dataset_id":   datasetID,
+		"source":       remote.Kind,
+		"workspace_id": workspaceID,
+	})
+}
+
 // handleMergeDatasets handles dataset merging requests
 func (s *Server) handleMergeDatasets(c *gin.Context) {
 	if s.datasetProcessor == nil {
@@ -269,6 +391,7 @@ func (s *Server) handleMergeDatasets(c *gin.Context) {
 			TemporalConfig struct {
 				TimeColumn       string  `json:"time_column"`
 				TimeFormat       string  `json:"time_format"`
+				Locale           string  `json:"locale"`
 				SourceTimeZone   string  `json:"source_time_zone"`
 				TargetTimeZone   string  `json:"target_time_zone"`
 				Frequency        string  `json:"frequency"`
@@ -350,6 +473,7 @@ func (s *Server) handleMergeDatasets(c *gin.Context) {
 		temporalConfig := &processor.TemporalMergeConfig{
 			TimeColumn:       req.MergeConfig.TemporalConfig.TimeColumn,
 			TimeFormat:       req.MergeConfig.TemporalConfig.TimeFormat,
+			Locale:           req.MergeConfig.TemporalConfig.Locale,
 			SourceTimeZone:   req.MergeConfig.TemporalConfig.SourceTimeZone,
 			TargetTimeZone:   req.MergeConfig.TemporalConfig.TargetTimeZone,
 			DetectFrequency:  req.MergeConfig.TemporalConfig.DetectFrequency,
@@ -435,7 +559,7 @@ func (s *Server) handleMergeDatasets(c *gin.Context) {
 	mergeResult, err := s.datasetProcessor.Merger.MergeDatasets(ctx, sourceIDs, req.OutputName, config)
 	if err != nil {
 		log.Printf("[handleMergeDatasets] Merge failed: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Merge operation failed: %v", err)})
+		respondProblem(c, apperrors.Wrap(err, "Merge operation failed"))
 		return
 	}
 
@@ -446,6 +570,7 @@ func (s *Server) handleMergeDatasets(c *gin.Context) {
 		"row_count":    mergeResult.RowCount,
 		"column_count": mergeResult.ColumnCount,
 		"dataset_ids":  req.DatasetIDs,
+		"lineage":      mergeResult.Lineage,
 	})
 }
 
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// refreshOutcomeCalibration feeds the hit rates of resolved decisions, per referee gate,
+// into the calibrator validation uses
+func (s *Server) refreshOutcomeCalibration(ctx context.Context) {
+	if s.decisionLog == nil || s.outcomeCalibrator == nil {
+		return
+	}
+	resolved, err := s.decisionLog.ListResolved(ctx)
+	if err != nil {
+		log.Printf("[Decisions] ❌ Failed to load resolved decisions for calibration: %v", err)
+		return
+	}
+	tallies := models.TallyOutcomes(resolved)
+	s.outcomeCalibrator.SetOutcomeTallies(tallies)
+	log.Printf("[Decisions] 🎯 Calibrating E-values with %d resolved decisions across %d gates", len(resolved), len(tallies))
+}
+
+// decisionSummary summarizes a workspace's decision log for the dashboard; nil when the log
+// is unavailable
+func (s *Server) decisionSummary(ctx context.Context, workspaceID string) *models.DecisionSummary {
+	if s.decisionLog == nil || workspaceID == "" {
+		return nil
+	}
+	decisions, err := s.decisionLog.ListByWorkspace(ctx, workspaceID)
+	if err != nil {
+		log.Printf("[Decisions] ⚠️ Failed to summarize decisions for workspace %s: %v", workspaceID, err)
+		return nil
+	}
+	summary := models.SummarizeDecisions(decisions)
+	return &summary
+}
+
+// authorizeDecisionWorkspace verifies the decision log is available and the workspace belongs
+// to the default user
+func (s *Server) authorizeDecisionWorkspace(c *gin.Context) bool {
+	if s.decisionLog == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Decision log not available"})
+		return false
+	}
+	_, ok := s.ownedWorkspace(c)
+	return ok
+}
+
+// handleListDecisions lists a workspace's decisions with their aggregate hit rate
+func (s *Server) handleListDecisions(c *gin.Context) {
+	if !s.authorizeDecisionWorkspace(c) {
+		return
+	}
+	decisions, err := s.decisionLog.ListByWorkspace(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list decisions"))
+		return
+	}
+	summary := models.SummarizeDecisions(decisions)
+	c.JSON(http.StatusOK, gin.H{
+		"workspace_id": c.Param("id"),
+		"summary":      summary,
+		"headline":     summary.Headline(),
+		"decisions":    decisions,
+	})
+}
+
+// handleRecordDecision records an action taken on the strength of a hypothesis. The referee
+// gates the hypothesis passed are captured so its outcome can later calibrate them.
+func (s *Server) handleRecordDecision(c *gin.Context) {
+	if !s.authorizeDecisionWorkspace(c) {
+		return
+	}
+	var body struct {
+		HypothesisID string `json:"hypothesis_id"`
+		Action       string `json:"action"`
+		Expected     string `json:"expected"`
+		DecidedBy    string `json:"decided_by"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+	decision := &models.Decision{
+		WorkspaceID:  c.Param("id"),
+		HypothesisID: body.HypothesisID,
+		Action:       strings.TrimSpace(body.Action),
+		Expected:     strings.TrimSpace(body.Expected),
+		DecidedBy:    body.DecidedBy,
+	}
+	if err := decision.Validate(); err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+
+	if s.researchStorage != nil {
+		hypothesis, err := s.researchStorage.GetByID(c.Request.Context(), decision.HypothesisID)
+		if err != nil || hypothesis == nil {
+			respondProblem(c, apperrors.NotFound("Hypothesis"))
+			return
+		}
+		for _, result := range hypothesis.RefereeResults {
+			if result.Passed {
+				decision.Gates = append(decision.Gates, result.GateName)
+			}
+		}
+	}
+
+	if err := s.decisionLog.Record(c.Request.Context(), decision); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to record decision"))
+		return
+	}
+	c.JSON(http.StatusCreated, decision)
+}
+
+// handleRecordDecisionOutcome records how a decision turned out and recalibrates E-values
+func (s *Server) handleRecordDecisionOutcome(c *gin.Context) {
+	if !s.authorizeDecisionWorkspace(c) {
+		return
+	}
+	var body struct {
+		Outcome string `json:"outcome"`
+		Notes   string `json:"notes"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || !models.ValidOutcome(body.Outcome) {
+		respondProblem(c, apperrors.InvalidInput("outcome must be one of confirmed, refuted or inconclusive"))
+		return
+	}
+	decision, err := s.decisionLog.RecordOutcome(c.Request.Context(), c.Param("id"), c.Param("decisionId"), body.Outcome, body.Notes)
+	if err != nil {
+		respondProblem(c, err)
+		return
+	}
+	s.refreshOutcomeCalibration(c.Request.Context())
+	c.JSON(http.StatusOK, decision)
+}
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	domainDataset "gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetDataDictionary documents a dataset's variables from its profile, its Forensic
+// Scout naming, the workspace glossary and its lineage. format=markdown or csv exports it;
+// the ETag is the dictionary's fingerprint, so consumers can tell when their copy is stale.
+func (s *Server) handleGetDataDictionary(c *gin.Context) {
+	ds, ok := s.piiDataset(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	var glossary domainDataset.Glossary
+	if s.workspaceRepository != nil {
+		if workspace, err := s.workspaceRepository.GetByID(ctx, ds.WorkspaceID); err == nil {
+			glossary = domainDataset.GlossaryFrom(workspace.Metadata)
+		}
+	}
+	var entry *domainDataset.CatalogDataset
+	if s.catalog != nil {
+		catalog, err := s.catalog.GetCatalog(ctx, ds.WorkspaceID)
+		if err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to build data catalog"))
+			return
+		}
+		for i := range catalog.Datasets {
+			if catalog.Datasets[i].ID == ds.ID {
+				entry = &catalog.Datasets[i]
+				break
+			}
+		}
+	}
+	dict := domainDataset.BuildDataDictionary(ds, entry, glossary)
+
+	etag := `"` + dict.Fingerprint + `"`
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	filename := dict.Name + "-dictionary"
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.JSON(http.StatusOK, dict)
+	case "markdown":
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".md"))
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(dict.Markdown()))
+	case "csv":
+		var buf bytes.Buffer
+		if err := dict.WriteCSV(&buf); err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to export data dictionary"))
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+	default:
+		respondProblem(c, apperrors.InvalidInput("format must be json, markdown or csv"))
+	}
+}
+
+// handleGetGlossary returns the business terms defining a workspace's variables
+func (s *Server) handleGetGlossary(c *gin.Context) {
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return
+	}
+	respondGlossary(c, workspace)
+}
+
+// handlePutGlossary replaces a workspace's glossary; an empty list clears it. Data
+// dictionaries pick the new definitions up on their next request.
+func (s *Server) handlePutGlossary(c *gin.Context) {
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Entries domainDataset.Glossary `json:"entries"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+	if err := req.Entries.Validate(); err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	if workspace.Metadata == nil {
+		workspace.Metadata = make(map[string]interface{})
+	}
+	if len(req.Entries) == 0 {
+		delete(workspace.Metadata, domainDataset.GlossaryKey)
+	} else {
+		workspace.Metadata[domainDataset.GlossaryKey] = req.Entries.Sorted()
+	}
+	if err := s.workspaceRepository.Update(c.Request.Context(), workspace); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to update workspace"))
+		return
+	}
+	respondGlossary(c, workspace)
+}
+
+// respondGlossary writes a workspace's glossary entries
+func respondGlossary(c *gin.Context, workspace *domainDataset.Workspace) {
+	entries := domainDataset.GlossaryFrom(workspace.Metadata)
+	if entries == nil {
+		entries = domainDataset.Glossary{}
+	}
+	c.JSON(http.StatusOK, gin.H{"workspace_id": workspace.ID, "entries": entries, "count": len(entries)})
+}
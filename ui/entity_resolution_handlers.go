@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"net/http"
+
+	"gohypo/domain/core"
+	processor "gohypo/internal/dataset"
+	apperrors "gohypo/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// entityResolutionRequest names the dataset whose names are resolved against the one in the
+// path. Columns are chosen from the names of the datasets' columns unless both are given;
+// unset settings take the defaults workspace sweeps use.
+type entityResolutionRequest struct {
+	TargetDatasetID string   `json:"target_dataset_id" binding:"required"`
+	Column          string   `json:"column"`
+	TargetColumn    string   `json:"target_column"`
+	Threshold       float64  `json:"threshold"`
+	Blocking        []string `json:"blocking"`
+	PrefixLength    int      `json:"prefix_length"`
+}
+
+// handleResolveEntities resolves the names of a column of the target dataset to the entities
+// of a column of this dataset, such as a client column to customer_name, and returns the
+// match-quality report with the entity ID each target name resolved to
+func (s *Server) handleResolveEntities(c *gin.Context) {
+	if s.datasetProcessor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Dataset processor not available"})
+		return
+	}
+	var req entityResolutionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Request body must name a target_dataset_id"))
+		return
+	}
+	if (req.Column == "") != (req.TargetColumn == "") {
+		respondProblem(c, apperrors.InvalidInput("Name both column and target_column, or neither"))
+		return
+	}
+	config := processor.DefaultEntityResolutionConfig()
+	if req.Threshold != 0 {
+		config.Threshold = req.Threshold
+	}
+	if len(req.Blocking) > 0 {
+		config.Blocking = req.Blocking
+	}
+	if req.PrefixLength != 0 {
+		config.PrefixLength = req.PrefixLength
+	}
+	if err := config.Validate(); err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+
+	source, ok := s.piiDataset(c)
+	if !ok {
+		return
+	}
+	ctx := s.withPrincipal(c.Request.Context(), source.UserID)
+	target, err := s.ownedDataset(ctx, source.UserID, core.ID(req.TargetDatasetID))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to load target dataset"))
+		return
+	}
+	if target == nil {
+		respondProblem(c, apperrors.NotFound("Target dataset"))
+		return
+	}
+	if source.Metadata.ExcludedFields()[req.Column] || target.Metadata.ExcludedFields()[req.TargetColumn] {
+		respondProblem(c, apperrors.Forbidden("PII columns cannot be resolved"))
+		return
+	}
+	if s.columnEnforcer != nil && req.Column != "" &&
+		(!s.columnEnforcer.IsAllowed(ctx, core.VariableKey(req.Column)) || !s.columnEnforcer.IsAllowed(ctx, core.VariableKey(req.TargetColumn))) {
+		respondProblem(c, apperrors.Forbidden("Restricted columns cannot be resolved"))
+		return
+	}
+
+	match, err := s.datasetProcessor.GetRelationshipEngine().ResolveEntities(ctx, source, target, req.Column, req.TargetColumn, config)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to resolve entities"))
+		return
+	}
+	if match == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"source_dataset_id": source.ID,
+			"target_dataset_id": target.ID,
+			"resolved":          false,
+			"message":           "No name columns of the datasets resolve to shared entities",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"source_dataset_id": source.ID,
+		"target_dataset_id": target.ID,
+		"resolved":          true,
+		"source_column":     match.SourceColumn,
+		"target_column":     match.TargetColumn,
+		"report":            match.Resolution.Report,
+		"entity_ids":        match.Resolution.TargetIDs,
+	})
+}
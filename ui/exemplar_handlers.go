@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ui/middleware"
+)
+
+// handleListExemplars lists few-shot exemplars, optionally by domain_pack and status
+func (s *Server) handleListExemplars(c *gin.Context) {
+	if s.exemplarStore == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Exemplar store not available"})
+		return
+	}
+	pack := c.Query("domain_pack")
+	if pack != "" {
+		pack = models.NormalizeDomainPack(pack)
+	}
+	exemplars, err := s.exemplarStore.List(c.Request.Context(), pack, c.Query("status"))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list exemplars"))
+		return
+	}
+	if exemplars == nil {
+		exemplars = []*models.Exemplar{}
+	}
+	c.JSON(http.StatusOK, gin.H{"exemplars": exemplars, "count": len(exemplars)})
+}
+
+// handleListDomainPacks lists the packs that have approved exemplars
+func (s *Server) handleListDomainPacks(c *gin.Context) {
+	if s.exemplarStore == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Exemplar store not available"})
+		return
+	}
+	packs, err := s.exemplarStore.DomainPacks(c.Request.Context())
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list domain packs"))
+		return
+	}
+	if packs == nil {
+		packs = []string{}
+	}
+	c.JSON(http.StatusOK, gin.H{"domain_packs": packs})
+}
+
+// handleProposeExemplar proposes a validated hypothesis as a few-shot exemplar. It only
+// reaches prompts once an admin approves it.
+func (s *Server) handleProposeExemplar(c *gin.Context) {
+	if s.exemplarStore == nil || s.researchStorage == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Exemplar store not available"})
+		return
+	}
+	var body struct {
+		HypothesisID string `json:"hypothesis_id"`
+		DomainPack   string `json:"domain_pack"`
+		ProposedBy   string `json:"proposed_by"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.HypothesisID == "" {
+		respondProblem(c, apperrors.InvalidInput("hypothesis_id is required"))
+		return
+	}
+
+	hypothesis, err := s.researchStorage.GetByID(c.Request.Context(), body.HypothesisID)
+	if err != nil || hypothesis == nil {
+		respondProblem(c, apperrors.NotFound("Hypothesis"))
+		return
+	}
+	exemplar, err := models.ExemplarFromHypothesis(hypothesis, body.DomainPack)
+	if err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	exemplar.ProposedBy = body.ProposedBy
+	if err := s.exemplarStore.Propose(c.Request.Context(), exemplar); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to store exemplar"))
+		return
+	}
+	c.JSON(http.StatusCreated, exemplar)
+}
+
+// handleApproveExemplar admits a proposed exemplar to generation prompts, recording the admin
+// key the request authenticated with as its approver
+func (s *Server) handleApproveExemplar(c *gin.Context) {
+	if s.exemplarStore == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Exemplar store not available"})
+		return
+	}
+	// The approver is whoever authenticated, never a name the request claims; with the admin
+	// API open there is no one to attribute the approval to
+	approver := middleware.AdminCaller(c)
+	if approver == "" {
+		respondProblem(c, apperrors.Forbidden("Approving exemplars requires ADMIN_API_KEYS to be configured"))
+		return
+	}
+	if err := s.exemplarStore.Approve(c.Request.Context(), c.Param("id"), approver); err != nil {
+		respondProblem(c, err)
+		return
+	}
+	exemplar, err := s.exemplarStore.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, exemplar)
+}
+
+// handleRetireExemplar withdraws an exemplar from prompts
+func (s *Server) handleRetireExemplar(c *gin.Context) {
+	if s.exemplarStore == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Exemplar store not available"})
+		return
+	}
+	if err := s.exemplarStore.Retire(c.Request.Context(), c.Param("id")); err != nil {
+		respondProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": c.Param("id"), "status": models.ExemplarRetired})
+}
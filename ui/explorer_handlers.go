@@ -0,0 +1,288 @@
+package ui
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// pairExplorer tests a variable pair picked by hand, outside any research session
+type pairExplorer interface {
+	ExplorePair(ctx context.Context, workspaceID uuid.UUID, ds *domainDataset.Dataset, userID core.ID, cause, effect string) (*models.PairExploration, error)
+}
+
+// explorePairRequest names the pair to test; the dataset defaults to the workspace's latest
+type explorePairRequest struct {
+	Cause     string `json:"cause" binding:"required"`
+	Effect    string `json:"effect" binding:"required"`
+	DatasetID string `json:"dataset_id"`
+}
+
+// handleExplorePair resolves and tests two variables of a workspace's dataset on demand,
+// whether or not a sweep kept the pair, and stores the result as an audit artifact
+func (s *Server) handleExplorePair(c *gin.Context) {
+	if s.pairExplorer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Pair explorer not available"})
+		return
+	}
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return
+	}
+	var req explorePairRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	ds, ok := s.explorerDataset(c, workspace.ID, req.DatasetID)
+	if !ok {
+		return
+	}
+	workspaceID, _ := uuid.Parse(string(workspace.ID))
+	userID, _ := s.getDefaultUserID(c.Request.Context())
+	exploration, err := s.pairExplorer.ExplorePair(c.Request.Context(), workspaceID, ds, userID, req.Cause, req.Effect)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to explore variable pair"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"exploration": exploration})
+}
+
+// handleExplorerPage renders the pair explorer for a workspace's latest dataset
+func (s *Server) handleExplorerPage(c *gin.Context) {
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return
+	}
+	var variables []domainDataset.FieldInfo
+	ds := s.latestReadyDataset(c, workspace.ID)
+	if ds != nil {
+		excluded := ds.Metadata.ExcludedFields()
+		for _, field := range ds.Metadata.Fields {
+			if !excluded[field.Name] {
+				variables = append(variables, field)
+			}
+		}
+	}
+	var page strings.Builder
+	data := gin.H{
+		"Workspace": workspace,
+		"Dataset":   ds,
+		"Variables": variables,
+		"Available": s.pairExplorer != nil,
+		"RunURL":    "/api/workspaces/" + url.PathEscape(string(workspace.ID)) + "/explore",
+		"Title":     s.branding.Title("Explore a pair"),
+		"Style":     brandStyle(s.branding),
+		"ChipStyle": template.CSS(provenanceChipStyle),
+	}
+	if err := explorerTemplate.Execute(&page, data); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to render pair explorer"))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page.String()))
+}
+
+// explorerDataset is the named dataset of the workspace, or its latest ready one when no
+// dataset is named
+func (s *Server) explorerDataset(c *gin.Context, workspaceID core.ID, datasetID string) (*domainDataset.Dataset, bool) {
+	if datasetID == "" {
+		ds := s.latestReadyDataset(c, workspaceID)
+		if ds == nil {
+			respondProblem(c, apperrors.NotFound("Ready dataset"))
+			return nil, false
+		}
+		return ds, true
+	}
+	if s.datasetRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Dataset service not available"})
+		return nil, false
+	}
+	ds, err := s.datasetRepository.GetByID(c.Request.Context(), core.ID(datasetID))
+	if err != nil || ds == nil || !ds.IsInWorkspace(workspaceID) {
+		respondProblem(c, apperrors.NotFound("Dataset"))
+		return nil, false
+	}
+	if !ds.IsReady() {
+		respondProblem(c, apperrors.InvalidInput("Dataset is still processing"))
+		return nil, false
+	}
+	return ds, true
+}
+
+var explorerTemplate = template.Must(template.New("explorer").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+{{.Style}}
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 0; color: var(--brand-accent); font-size: 14px; }
+header { padding: 12px 20px; border-bottom: 3px solid var(--brand-primary); display: flex; gap: 16px; align-items: center; }
+header h1 { font-size: 16px; margin: 0; }
+main { padding: 8px 20px 40px; max-width: 60rem; }
+form { display: flex; gap: 12px; align-items: center; margin: 12px 0; }
+.meta { color: #6b7280; }
+table { border-collapse: collapse; width: 100%; margin: 8px 0 20px; }
+th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #f3f4f6; vertical-align: top; }
+th { color: #6b7280; font-weight: 600; }
+.verdict { padding: 10px 14px; border-radius: 6px; font-weight: 600; }
+.significant { background: #dcfce7; color: #166534; }
+.not-significant { background: #f3f4f6; color: #374151; }
+svg { border: 1px solid #e5e7eb; background: #fff; }
+svg circle { fill: var(--brand-primary); fill-opacity: 0.5; }
+{{.ChipStyle}}
+</style>
+</head>
+<body>
+<header>
+<h1>Explore a pair</h1>
+<span class="meta">{{.Workspace.Name}}{{with .Dataset}} · {{.GetDisplayName}}{{end}}</span>
+</header>
+<main>
+{{if not .Dataset}}
+<p class="meta">This workspace has no ready dataset to explore yet.</p>
+{{else if not .Available}}
+<p class="meta">The pair explorer is not available on this server.</p>
+{{else}}
+<form id="explore" data-url="{{.RunURL}}" data-dataset="{{.Dataset.ID}}">
+<label>Cause <select name="cause">{{range .Variables}}<option value="{{.Name}}">{{.DisplayName}}</option>{{end}}</select></label>
+<label>Effect <select name="effect">{{range $i, $v := .Variables}}<option value="{{$v.Name}}"{{if eq $i 1}} selected{{end}}>{{$v.DisplayName}}</option>{{end}}</select></label>
+<button type="submit">Test</button>
+<span class="meta" id="state"></span>
+</form>
+<div id="result" hidden>
+<p class="verdict" id="summary"></p>
+<svg id="scatter" width="480" height="320" viewBox="0 0 480 320"></svg>
+<table id="stats"></table>
+<table id="senses"></table>
+<p class="meta" id="audit"></p>
+</div>
+{{end}}
+</main>
+<script>
+(function () {
+  var form = document.getElementById("explore");
+  if (!form) { return; }
+  var state = document.getElementById("state");
+  var svgNS = "http://www.w3.org/2000/svg";
+
+  function num(v, digits) { return v === undefined || v === null ? "–" : Number(v).toPrecision(digits || 3); }
+  function row(table, cells, header) {
+    var tr = table.insertRow();
+    cells.forEach(function (text) {
+      var cell = document.createElement(header ? "th" : "td");
+      cell.textContent = text;
+      tr.appendChild(cell);
+    });
+    return tr;
+  }
+  // chip links a number to the artifact it is stored in, like the server-rendered chips
+  function chip(p) {
+    if (!p) { return document.createTextNode(""); }
+    var link = document.createElement("a");
+    link.className = "provenance";
+    link.href = "/api/artifacts/" + encodeURIComponent(p.artifact_id);
+    link.target = "_blank";
+    link.rel = "noopener";
+    link.title = "Artifact " + p.artifact_id + " · fingerprint " + p.fingerprint;
+    link.textContent = p.stage + " · " + p.fingerprint.slice(0, 8);
+    return link;
+  }
+
+  function plot(points, cause, effect) {
+    var svg = document.getElementById("scatter");
+    while (svg.firstChild) { svg.removeChild(svg.firstChild); }
+    if (!points.length) { return; }
+    var xs = points.map(function (p) { return p[0]; }), ys = points.map(function (p) { return p[1]; });
+    var minX = Math.min.apply(null, xs), maxX = Math.max.apply(null, xs);
+    var minY = Math.min.apply(null, ys), maxY = Math.max.apply(null, ys);
+    var spanX = maxX - minX || 1, spanY = maxY - minY || 1;
+    points.forEach(function (p) {
+      var dot = document.createElementNS(svgNS, "circle");
+      dot.setAttribute("cx", 30 + (p[0] - minX) / spanX * 440);
+      dot.setAttribute("cy", 290 - (p[1] - minY) / spanY * 280);
+      dot.setAttribute("r", 3);
+      svg.appendChild(dot);
+    });
+    [[cause, 250, 315, "middle"], [effect, 4, 12, "start"]].forEach(function (l) {
+      var text = document.createElementNS(svgNS, "text");
+      text.textContent = l[0];
+      text.setAttribute("x", l[1]);
+      text.setAttribute("y", l[2]);
+      text.setAttribute("text-anchor", l[3]);
+      text.setAttribute("font-size", "11");
+      svg.appendChild(text);
+    });
+  }
+
+  function render(e) {
+    var t = e.test;
+    var summary = document.getElementById("summary");
+    summary.textContent = t.summary;
+    if (e.noise_percentile !== undefined) {
+      summary.textContent += " Stronger than " + Math.round(e.noise_percentile) + "% of effects in permuted data.";
+    }
+    summary.className = "verdict " + (t.significant ? "significant" : "not-significant");
+    summary.appendChild(chip(e.provenance));
+    plot(t.points || [], e.cause, e.effect);
+
+    var stats = document.getElementById("stats");
+    stats.innerHTML = "";
+    row(stats, ["Correlation (r)", "95% interval", "p", "Strength", "Rows", "Missing cause", "Missing effect"], true);
+    row(stats, [num(t.correlation), "[" + num(t.ci_low) + ", " + num(t.ci_high) + "]", num(t.p_value), t.strength,
+      t.complete_rows + " of " + t.rows, t.missing_x, t.missing_y]).cells[0].appendChild(chip(e.provenance));
+    if (t.robust) {
+      row(stats, ["Robust estimate", "Coefficient", "p", t.robust.outlier_driven ? "Outlier-driven" : "Holds without outliers"], true);
+      t.robust.estimates.forEach(function (r) {
+        row(stats, [r.method.replace(/_/g, " "), num(r.coefficient), num(r.p_value), ""]).cells[0].appendChild(chip(e.provenance));
+      });
+    }
+
+    var senses = document.getElementById("senses");
+    senses.innerHTML = "";
+    row(senses, ["Sense", "Effect", "p", "Signal", "Reading"], true);
+    (e.senses || []).forEach(function (s) {
+      row(senses, [s.sense_name.replace(/_/g, " "), num(s.effect_size), num(s.p_value), s.signal, s.description]).cells[0].appendChild(chip(e.provenance));
+    });
+
+    var audit = document.getElementById("audit");
+    audit.textContent = "Run " + e.run_id + " · " + e.policy.alpha + " significance level";
+    if (!e.provenance) {
+      audit.textContent += " · no audit artifact stored";
+    }
+    document.getElementById("result").hidden = false;
+  }
+
+  form.addEventListener("submit", function (event) {
+    event.preventDefault();
+    var button = form.querySelector("button");
+    button.disabled = true;
+    state.textContent = "testing…";
+    fetch(form.dataset.url, {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ cause: form.cause.value, effect: form.effect.value, dataset_id: form.dataset.dataset })
+    }).then(function (resp) {
+      return resp.json().then(function (body) {
+        button.disabled = false;
+        if (!resp.ok) { state.textContent = body.detail || body.error || "exploration failed"; return; }
+        state.textContent = "";
+        render(body.exploration);
+      });
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,29 @@
+// Package graphapi serves workspaces, datasets, runs, relationships and hypotheses as one
+// GraphQL schema, so a dashboard can fetch a hypothesis with its evidence relationships and
+// their variable audits in a single query.
+package graphapi
+
+import (
+	"fmt"
+	"net/http"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+// maxDepth bounds how deeply a query may nest, so a query cannot walk from runs to
+// relationships and back indefinitely
+const maxDepth = 12
+
+// NewHandler parses the schema over sources and returns a handler for POSTed GraphQL
+// queries; nesting deeper than maxDepth is refused
+func NewHandler(sources Sources) (http.Handler, error) {
+	if sources.UserID == nil {
+		return nil, fmt.Errorf("graphql sources need a user ID func")
+	}
+	parsed, err := graphql.ParseSchema(schema, &resolver{src: sources}, graphql.MaxDepth(maxDepth))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL schema: %w", err)
+	}
+	return &relay.Handler{Schema: parsed}, nil
+}
@@ -0,0 +1,203 @@
+package graphapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	"gohypo/internal/testkit"
+	"gohypo/models"
+	"gohypo/ports"
+)
+
+type fakeWorkspaces struct {
+	ports.WorkspaceRepository
+	workspaces map[core.ID]*dataset.Workspace
+}
+
+func (f *fakeWorkspaces) GetByID(ctx context.Context, id core.ID) (*dataset.Workspace, error) {
+	if ws, ok := f.workspaces[id]; ok {
+		return ws, nil
+	}
+	return nil, fmt.Errorf("workspace not found: %s", id)
+}
+
+func (f *fakeWorkspaces) GetByUserID(ctx context.Context, userID core.ID) ([]*dataset.Workspace, error) {
+	var owned []*dataset.Workspace
+	for _, ws := range f.workspaces {
+		if ws.UserID == userID {
+			owned = append(owned, ws)
+		}
+	}
+	return owned, nil
+}
+
+type fakeHypotheses map[string]*models.HypothesisResult
+
+func (f fakeHypotheses) GetByID(ctx context.Context, id string) (*models.HypothesisResult, error) {
+	return f[id], nil
+}
+
+func (f fakeHypotheses) ListByWorkspace(ctx context.Context, workspaceID string, limit int) ([]*models.HypothesisResult, error) {
+	var listed []*models.HypothesisResult
+	for _, h := range f {
+		if h.WorkspaceID == workspaceID {
+			listed = append(listed, h)
+		}
+	}
+	return listed, nil
+}
+
+// newTestHandler serves one workspace of user u1 holding hypothesis h1, whose session swept
+// two relationships, one workspace of another user holding h2, and h3 in no workspace
+func newTestHandler(t *testing.T) http.Handler {
+	t.Helper()
+	ctx := context.Background()
+	ledger := testkit.NewInMemoryLedgerAdapter()
+	artifacts := []core.Artifact{
+		{ID: "sweep-s1_corr_price_demand", Kind: "association", Payload: map[string]interface{}{
+			"cause_key": "price", "effect_key": "demand", "test_type": "pearson_correlation",
+			"correlation": -0.6, "p_value": 0.001, "q_value": 0.004, "sample_size": 80, "warnings": []string{"LOW_N"},
+		}},
+		{ID: "sweep-s1_corr_price_region", Kind: "association", Payload: map[string]interface{}{
+			"cause_key": "price", "effect_key": "region", "test_type": "pearson_correlation",
+			"correlation": 0.1, "p_value": 0.4, "q_value": 0.5, "sample_size": 80,
+		}},
+		{ID: "sweep-s1_stats_sweep_manifest", Kind: core.ArtifactSweepManifest, Payload: map[string]interface{}{
+			"fdr_method": "BH", "bundle_fingerprint": "fp-1", "variables_analyzed": 3,
+			"column_fingerprints": map[string]string{"price": "c-price", "demand": "c-demand", "region": "c-region"},
+			"changed_variables":   []string{"demand"},
+		}},
+	}
+	for _, artifact := range artifacts {
+		if err := ledger.StoreArtifact(ctx, "sweep-s1", artifact); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler, err := NewHandler(Sources{
+		Workspaces: &fakeWorkspaces{workspaces: map[core.ID]*dataset.Workspace{
+			"w1": {ID: "w1", UserID: "u1", Name: "Pricing"},
+			"w2": {ID: "w2", UserID: "u2", Name: "Someone else's"},
+		}},
+		Hypotheses: fakeHypotheses{
+			"h1": {ID: "h1", SessionID: "s1", WorkspaceID: "w1", BusinessHypothesis: "Discounts lift demand", Passed: true,
+				ExecutionMetadata: map[string]interface{}{"cause_key": "demand", "effect_key": "price"},
+				RefereeResults:    []models.RefereeResult{{GateName: "permutation", Passed: true, PValue: 0.01}}},
+			"h2": {ID: "h2", SessionID: "s2", WorkspaceID: "w2"},
+			"h3": {ID: "h3", SessionID: "s1"},
+		},
+		Ledger: ledger,
+		UserID: func(ctx context.Context) (core.ID, error) { return "u1", nil },
+	})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	return handler
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func query(t *testing.T, handler http.Handler, q string) response {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"query": q})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/graphql", bytes.NewReader(body)))
+	var resp response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body.String(), err)
+	}
+	return resp
+}
+
+// TestHypothesisEvidence verifies the nested path the schema exists for: a hypothesis, the
+// relationships of its session's run between its variables, and their variable audits
+func TestHypothesisEvidence(t *testing.T) {
+	resp := query(t, newTestHandler(t), `{
+		hypothesis(id: "h1") {
+			businessHypothesis
+			workspace { name }
+			referees { gate passed }
+			run { id fdrMethod bundleFingerprint relationshipCount significantPairs }
+			evidence {
+				cause effect effectSize significant warnings
+				variables { key fingerprint changed }
+			}
+		}
+	}`)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors %+v", resp.Errors)
+	}
+	var data struct {
+		Hypothesis struct {
+			BusinessHypothesis string
+			Workspace          struct{ Name string }
+			Referees           []struct{ Gate string }
+			Run                struct {
+				ID                                  string
+				FdrMethod, BundleFingerprint        string
+				RelationshipCount, SignificantPairs int
+			}
+			Evidence []struct {
+				Cause, Effect string
+				EffectSize    float64
+				Significant   bool
+				Warnings      []string
+				Variables     []struct {
+					Key, Fingerprint string
+					Changed          bool
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	h := data.Hypothesis
+	if h.Workspace.Name != "Pricing" || len(h.Referees) != 1 || h.Run.ID != "sweep-s1" || h.Run.FdrMethod != "BH" ||
+		h.Run.BundleFingerprint != "fp-1" || h.Run.RelationshipCount != 2 || h.Run.SignificantPairs != 1 {
+		t.Errorf("unexpected hypothesis %+v", h)
+	}
+	if len(h.Evidence) != 1 {
+		t.Fatalf("expected the price-demand relationship as evidence, got %+v", h.Evidence)
+	}
+	evidence := h.Evidence[0]
+	if evidence.Cause != "price" || evidence.EffectSize != -0.6 || !evidence.Significant || len(evidence.Warnings) != 1 {
+		t.Errorf("unexpected evidence %+v", evidence)
+	}
+	if len(evidence.Variables) != 2 || evidence.Variables[0].Fingerprint != "c-price" || evidence.Variables[0].Changed ||
+		evidence.Variables[1].Key != "demand" || !evidence.Variables[1].Changed {
+		t.Errorf("unexpected variable audits %+v", evidence.Variables)
+	}
+}
+
+// TestOtherUsersWorkspaceRefused verifies a hypothesis in another user's workspace is not
+// served, while the user's own workspaces list their hypotheses
+func TestOtherUsersWorkspaceRefused(t *testing.T) {
+	handler := newTestHandler(t)
+	resp := query(t, handler, `{ hypothesis(id: "h2") { id } }`)
+	if len(resp.Errors) != 1 || string(resp.Data) != `{"hypothesis":null}` {
+		t.Errorf("expected h2 to be refused, got %s %+v", resp.Data, resp.Errors)
+	}
+
+	// Neither the unowned hypothesis nor the sweep run, which has no summary naming its
+	// workspace, is served on its own
+	resp = query(t, handler, `{ workspaces { id hypotheses { id } } run(id: "sweep-missing") { id }
+		unowned: hypothesis(id: "h3") { id } ledgerRun: run(id: "sweep-s1") { id } }`)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors %+v", resp.Errors)
+	}
+	if want := `{"workspaces":[{"id":"w1","hypotheses":[{"id":"h1"}]}],"run":null,"unowned":null,"ledgerRun":null}`; string(resp.Data) != want {
+		t.Errorf("got %s, want %s", resp.Data, want)
+	}
+}
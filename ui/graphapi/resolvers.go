@@ -0,0 +1,743 @@
+package graphapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"gohypo/domain/core"
+	"gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ports"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// maxLimit caps every list a query asks for
+const maxLimit = 500
+
+// HypothesisSource reads stored hypotheses; research.ResearchStorage is one
+type HypothesisSource interface {
+	GetByID(ctx context.Context, id string) (*models.HypothesisResult, error)
+	ListByWorkspace(ctx context.Context, workspaceID string, limit int) ([]*models.HypothesisResult, error)
+}
+
+// Sources are the stores the schema reads. Any may be nil: fields backed by a missing
+// store resolve to null or an empty list.
+type Sources struct {
+	Workspaces   ports.WorkspaceRepository
+	Datasets     ports.DatasetRepository
+	Hypotheses   HypothesisSource
+	RunSummaries ports.RunSummaryStore
+	Ledger       ports.LedgerReaderPort
+	// UserID returns the user whose workspaces a query may read
+	UserID func(ctx context.Context) (core.ID, error)
+}
+
+// resolver is the root of the schema
+type resolver struct {
+	src Sources
+}
+
+func (r *resolver) Workspaces(ctx context.Context) ([]*workspaceResolver, error) {
+	if r.src.Workspaces == nil {
+		return nil, nil
+	}
+	userID, err := r.src.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workspaces, err := r.src.Workspaces.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	resolvers := make([]*workspaceResolver, len(workspaces))
+	for i, ws := range workspaces {
+		resolvers[i] = &workspaceResolver{r: r, ws: ws}
+	}
+	return resolvers, nil
+}
+
+func (r *resolver) Workspace(ctx context.Context, args struct{ ID graphql.ID }) (*workspaceResolver, error) {
+	ws, err := r.ownedWorkspace(ctx, core.ID(args.ID))
+	if err != nil || ws == nil {
+		return nil, err
+	}
+	return &workspaceResolver{r: r, ws: ws}, nil
+}
+
+func (r *resolver) Dataset(ctx context.Context, args struct{ ID graphql.ID }) (*datasetResolver, error) {
+	if r.src.Datasets == nil {
+		return nil, nil
+	}
+	ds, err := r.src.Datasets.GetByID(ctx, core.ID(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	if ws, err := r.ownedWorkspace(ctx, ds.WorkspaceID); err != nil || ws == nil {
+		return nil, err
+	}
+	return &datasetResolver{r: r, ds: ds}, nil
+}
+
+func (r *resolver) Run(ctx context.Context, args struct{ ID graphql.ID }) (*runResolver, error) {
+	return r.run(ctx, string(args.ID), "")
+}
+
+func (r *resolver) Hypothesis(ctx context.Context, args struct{ ID graphql.ID }) (*hypothesisResolver, error) {
+	if r.src.Hypotheses == nil {
+		return nil, nil
+	}
+	h, err := r.src.Hypotheses.GetByID(ctx, string(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	if h == nil {
+		return nil, nil
+	}
+	if ws, err := r.ownedWorkspace(ctx, core.ID(h.WorkspaceID)); err != nil || ws == nil {
+		return nil, err
+	}
+	return &hypothesisResolver{r: r, h: h}, nil
+}
+
+// ownedWorkspace returns a workspace of the current user; another user's is refused. It is
+// nil when there is no workspace to check, and callers resolve whatever it owns to null.
+func (r *resolver) ownedWorkspace(ctx context.Context, id core.ID) (*dataset.Workspace, error) {
+	if r.src.Workspaces == nil || id == "" {
+		return nil, nil
+	}
+	ws, err := r.src.Workspaces.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := r.src.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ws.UserID != userID {
+		return nil, apperrors.Forbidden("Access denied")
+	}
+	return ws, nil
+}
+
+// run resolves a run from its summary, or from the ledger when it has none. The summary
+// names the run's workspace; a ledger-only run has none, so it is served only when reached
+// through something already checked, like its hypothesis, whose workspace is passed as
+// ownerWorkspace. A run neither knows, or whose owner is unknown, is null.
+func (r *resolver) run(ctx context.Context, id, ownerWorkspace string) (*runResolver, error) {
+	run := &runResolver{r: r, id: id}
+	if r.src.RunSummaries != nil {
+		summary, err := r.src.RunSummaries.GetRunSummary(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if summary != nil {
+			if summary.WorkspaceID == nil {
+				return nil, nil
+			}
+			if ws, err := r.ownedWorkspace(ctx, core.ID(*summary.WorkspaceID)); err != nil || ws == nil {
+				return nil, err
+			}
+		}
+		run.summary = summary
+	}
+	if run.summary == nil {
+		if ownerWorkspace == "" {
+			return nil, nil
+		}
+		artifacts, err := run.artifacts(ctx)
+		if err != nil || len(artifacts) == 0 {
+			return nil, err
+		}
+	}
+	return run, nil
+}
+
+func (r *resolver) runs(summaries []*models.RunSummary) []*runResolver {
+	runs := make([]*runResolver, len(summaries))
+	for i, summary := range summaries {
+		runs[i] = &runResolver{r: r, id: summary.RunID, summary: summary}
+	}
+	return runs
+}
+
+type workspaceResolver struct {
+	r  *resolver
+	ws *dataset.Workspace
+}
+
+func (w *workspaceResolver) ID() graphql.ID          { return graphql.ID(w.ws.ID) }
+func (w *workspaceResolver) Name() string            { return w.ws.Name }
+func (w *workspaceResolver) Description() string     { return w.ws.Description }
+func (w *workspaceResolver) IsDefault() bool         { return w.ws.IsDefault }
+func (w *workspaceResolver) CreatedAt() graphql.Time { return graphql.Time{Time: w.ws.CreatedAt} }
+
+func (w *workspaceResolver) Datasets(ctx context.Context, args struct{ Limit, Offset int32 }) ([]*datasetResolver, error) {
+	if w.r.src.Datasets == nil {
+		return nil, nil
+	}
+	offset := 0
+	if args.Offset > 0 {
+		offset = int(args.Offset)
+	}
+	datasets, err := w.r.src.Datasets.GetByWorkspace(ctx, w.ws.ID, limitOf(args.Limit, 50), offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list datasets: %w", err)
+	}
+	resolvers := make([]*datasetResolver, len(datasets))
+	for i, ds := range datasets {
+		resolvers[i] = &datasetResolver{r: w.r, ds: ds}
+	}
+	return resolvers, nil
+}
+
+func (w *workspaceResolver) Runs(ctx context.Context, args struct{ Limit int32 }) ([]*runResolver, error) {
+	if w.r.src.RunSummaries == nil {
+		return nil, nil
+	}
+	summaries, err := w.r.src.RunSummaries.ListWorkspaceSummaries(ctx, string(w.ws.ID), limitOf(args.Limit, 20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	return w.r.runs(summaries), nil
+}
+
+func (w *workspaceResolver) Hypotheses(ctx context.Context, args struct{ Limit int32 }) ([]*hypothesisResolver, error) {
+	if w.r.src.Hypotheses == nil {
+		return nil, nil
+	}
+	hypotheses, err := w.r.src.Hypotheses.ListByWorkspace(ctx, string(w.ws.ID), limitOf(args.Limit, 50))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hypotheses: %w", err)
+	}
+	resolvers := make([]*hypothesisResolver, len(hypotheses))
+	for i, h := range hypotheses {
+		resolvers[i] = &hypothesisResolver{r: w.r, h: h}
+	}
+	return resolvers, nil
+}
+
+type datasetResolver struct {
+	r  *resolver
+	ds *dataset.Dataset
+}
+
+func (d *datasetResolver) ID() graphql.ID { return graphql.ID(d.ds.ID) }
+
+func (d *datasetResolver) Name() string {
+	if d.ds.DisplayName != "" {
+		return d.ds.DisplayName
+	}
+	return d.ds.OriginalFilename
+}
+
+func (d *datasetResolver) Filename() string        { return d.ds.OriginalFilename }
+func (d *datasetResolver) Domain() string          { return d.ds.Domain }
+func (d *datasetResolver) Status() string          { return string(d.ds.Status) }
+func (d *datasetResolver) RecordCount() int32      { return int32(d.ds.RecordCount) }
+func (d *datasetResolver) FieldCount() int32       { return int32(d.ds.FieldCount) }
+func (d *datasetResolver) MissingRate() float64    { return d.ds.MissingRate }
+func (d *datasetResolver) CreatedAt() graphql.Time { return graphql.Time{Time: d.ds.CreatedAt} }
+
+func (d *datasetResolver) Workspace(ctx context.Context) (*workspaceResolver, error) {
+	ws, err := d.r.ownedWorkspace(ctx, d.ds.WorkspaceID)
+	if err != nil || ws == nil {
+		return nil, err
+	}
+	return &workspaceResolver{r: d.r, ws: ws}, nil
+}
+
+func (d *datasetResolver) Runs(ctx context.Context, args struct{ Limit int32 }) ([]*runResolver, error) {
+	if d.r.src.RunSummaries == nil {
+		return nil, nil
+	}
+	summaries, err := d.r.src.RunSummaries.ListDatasetRuns(ctx, string(d.ds.ID), limitOf(args.Limit, 20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	return d.r.runs(summaries), nil
+}
+
+func (d *datasetResolver) Variables() []*variableAuditResolver {
+	audits := make([]*variableAuditResolver, len(d.ds.Metadata.Fields))
+	for i, field := range d.ds.Metadata.Fields {
+		audits[i] = fieldAudit(d.ds, field)
+	}
+	return audits
+}
+
+// runResolver reads a run's summary when one was materialized and its ledger artifacts
+// when a field needs them, once per query
+type runResolver struct {
+	r       *resolver
+	id      string
+	summary *models.RunSummary
+
+	once          sync.Once
+	loaded        []core.Artifact
+	relationships []*relationshipResolver
+	manifest      map[string]interface{}
+	profiles      map[string]map[string]interface{}
+	err           error
+
+	datasetOnce sync.Once
+	dataset     *dataset.Dataset
+	datasetErr  error
+}
+
+// artifacts loads the run's artifacts and decodes its relationships, manifest and profiles
+func (run *runResolver) artifacts(ctx context.Context) ([]core.Artifact, error) {
+	run.once.Do(func() {
+		if run.r.src.Ledger == nil {
+			return
+		}
+		run.loaded, run.err = run.r.src.Ledger.GetArtifactsByRun(ctx, core.RunID(run.id))
+		if run.err != nil {
+			run.err = fmt.Errorf("failed to read run %s: %w", run.id, run.err)
+			return
+		}
+		run.profiles = map[string]map[string]interface{}{}
+		seen := map[core.ID]bool{}
+		for _, artifact := range run.loaded {
+			// A run lists an artifact once per store
+			if seen[artifact.ID] {
+				continue
+			}
+			seen[artifact.ID] = true
+			switch artifact.Kind {
+			case "association", core.ArtifactRelationship:
+				var payload relationshipPayload
+				if run.err = decodePayload(artifact, &payload); run.err != nil {
+					return
+				}
+				run.relationships = append(run.relationships, &relationshipResolver{run: run, id: string(artifact.ID), p: payload})
+			case core.ArtifactSweepManifest:
+				if run.err = decodePayload(artifact, &run.manifest); run.err != nil {
+					return
+				}
+			case core.ArtifactVariableProfile:
+				var profile map[string]interface{}
+				if run.err = decodePayload(artifact, &profile); run.err != nil {
+					return
+				}
+				key, _ := profile["variable_key"].(string)
+				if key == "" {
+					key = string(artifact.ID)
+				}
+				run.profiles[key] = profile
+			}
+		}
+		sort.SliceStable(run.relationships, func(i, j int) bool {
+			return math.Abs(run.relationships[i].effect()) > math.Abs(run.relationships[j].effect())
+		})
+	})
+	return run.loaded, run.err
+}
+
+func (run *runResolver) ID() graphql.ID { return graphql.ID(run.id) }
+
+func (run *runResolver) SessionID() *string {
+	if run.summary == nil {
+		return nil
+	}
+	return run.summary.SessionID
+}
+
+func (run *runResolver) CreatedAt() *graphql.Time {
+	if run.summary == nil {
+		return nil
+	}
+	return &graphql.Time{Time: run.summary.CreatedAt}
+}
+
+func (run *runResolver) FdrMethod(ctx context.Context) (string, error) {
+	if run.summary != nil {
+		return run.summary.FDRMethod, nil
+	}
+	if _, err := run.artifacts(ctx); err != nil {
+		return "", err
+	}
+	method, _ := run.manifest["fdr_method"].(string)
+	return method, nil
+}
+
+func (run *runResolver) BundleFingerprint(ctx context.Context) (string, error) {
+	if _, err := run.artifacts(ctx); err != nil {
+		return "", err
+	}
+	fingerprint, _ := run.manifest["bundle_fingerprint"].(string)
+	return fingerprint, nil
+}
+
+func (run *runResolver) VariablesAnalyzed(ctx context.Context) (int32, error) {
+	if run.summary != nil {
+		return int32(run.summary.VariablesAnalyzed), nil
+	}
+	if _, err := run.artifacts(ctx); err != nil {
+		return 0, err
+	}
+	analyzed, _ := run.manifest["variables_analyzed"].(float64)
+	return int32(analyzed), nil
+}
+
+func (run *runResolver) RelationshipCount(ctx context.Context) (int32, error) {
+	if run.summary != nil {
+		return int32(run.summary.Relationships), nil
+	}
+	if _, err := run.artifacts(ctx); err != nil {
+		return 0, err
+	}
+	return int32(len(run.relationships)), nil
+}
+
+func (run *runResolver) SignificantPairs(ctx context.Context) (int32, error) {
+	if run.summary != nil {
+		return int32(run.summary.SignificantPairs), nil
+	}
+	if _, err := run.artifacts(ctx); err != nil {
+		return 0, err
+	}
+	significant := int32(0)
+	for _, rel := range run.relationships {
+		if rel.Significant() {
+			significant++
+		}
+	}
+	return significant, nil
+}
+
+func (run *runResolver) Dataset(ctx context.Context) (*datasetResolver, error) {
+	ds, err := run.sweptDataset(ctx)
+	if err != nil || ds == nil {
+		return nil, err
+	}
+	return &datasetResolver{r: run.r, ds: ds}, nil
+}
+
+// sweptDataset is the uploaded dataset the run swept, or nil for the built-in one
+func (run *runResolver) sweptDataset(ctx context.Context) (*dataset.Dataset, error) {
+	run.datasetOnce.Do(func() {
+		if run.summary == nil || run.summary.DatasetID == nil || run.r.src.Datasets == nil {
+			return
+		}
+		run.dataset, run.datasetErr = run.r.src.Datasets.GetByID(ctx, core.ID(*run.summary.DatasetID))
+	})
+	return run.dataset, run.datasetErr
+}
+
+func (run *runResolver) Relationships(ctx context.Context, args struct {
+	SignificantOnly bool
+	Variable        *string
+	Limit           int32
+}) ([]*relationshipResolver, error) {
+	if _, err := run.artifacts(ctx); err != nil {
+		return nil, err
+	}
+	limit := limitOf(args.Limit, 100)
+	var resolvers []*relationshipResolver
+	for _, rel := range run.relationships {
+		if len(resolvers) == limit {
+			break
+		}
+		if args.SignificantOnly && !rel.Significant() {
+			continue
+		}
+		if args.Variable != nil && rel.Cause() != *args.Variable && rel.Effect() != *args.Variable {
+			continue
+		}
+		resolvers = append(resolvers, rel)
+	}
+	return resolvers, nil
+}
+
+func (run *runResolver) Variables(ctx context.Context) ([]*variableAuditResolver, error) {
+	if _, err := run.artifacts(ctx); err != nil {
+		return nil, err
+	}
+	keys := map[string]bool{}
+	if fingerprints, ok := run.manifest["column_fingerprints"].(map[string]interface{}); ok {
+		for key := range fingerprints {
+			keys[key] = true
+		}
+	}
+	for key := range run.profiles {
+		keys[key] = true
+	}
+	for _, rel := range run.relationships {
+		keys[rel.Cause()], keys[rel.Effect()] = true, true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	audits := make([]*variableAuditResolver, len(sorted))
+	for i, key := range sorted {
+		audit, err := run.audit(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		audits[i] = audit
+	}
+	return audits, nil
+}
+
+// audit joins what the run's manifest and profiles and its dataset's fields recorded
+// about a variable
+func (run *runResolver) audit(ctx context.Context, key string) (*variableAuditResolver, error) {
+	if _, err := run.artifacts(ctx); err != nil {
+		return nil, err
+	}
+	audit := &variableAuditResolver{key: key}
+	ds, err := run.sweptDataset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ds != nil {
+		for _, field := range ds.Metadata.Fields {
+			if field.Name == key {
+				audit = fieldAudit(ds, field)
+				break
+			}
+		}
+	}
+	if fingerprints, ok := run.manifest["column_fingerprints"].(map[string]interface{}); ok {
+		if fingerprint, ok := fingerprints[key].(string); ok {
+			audit.fingerprint = &fingerprint
+		}
+	}
+	if changed, ok := run.manifest["changed_variables"].([]interface{}); ok {
+		for _, variable := range changed {
+			if variable == key {
+				audit.changed = true
+			}
+		}
+	}
+	if rate, ok := run.profiles[key]["missing_rate"].(float64); ok {
+		audit.missingRate = &rate
+	}
+	return audit, nil
+}
+
+// relationshipPayload is the part of a relationship payload the schema exposes
+type relationshipPayload struct {
+	CauseKey              string   `json:"cause_key"`
+	EffectKey             string   `json:"effect_key"`
+	VariableX             string   `json:"variable_x"`
+	VariableY             string   `json:"variable_y"`
+	TestType              string   `json:"test_type"`
+	Correlation           *float64 `json:"correlation"`
+	EffectSize            *float64 `json:"effect_size"`
+	PValue                float64  `json:"p_value"`
+	QValue                float64  `json:"q_value"`
+	SampleSize            int      `json:"sample_size"`
+	PracticalSignificance string   `json:"practical_significance"`
+	Warnings              []string `json:"warnings"`
+}
+
+type relationshipResolver struct {
+	run *runResolver
+	id  string
+	p   relationshipPayload
+}
+
+// effect is the relationship's effect size: eta squared for group differences, the
+// correlation otherwise
+func (rel *relationshipResolver) effect() float64 {
+	switch {
+	case rel.p.EffectSize != nil:
+		return *rel.p.EffectSize
+	case rel.p.Correlation != nil:
+		return *rel.p.Correlation
+	}
+	return math.NaN()
+}
+
+func (rel *relationshipResolver) ID() graphql.ID    { return graphql.ID(rel.id) }
+func (rel *relationshipResolver) Run() *runResolver { return rel.run }
+
+func (rel *relationshipResolver) Cause() string {
+	if rel.p.CauseKey == "" && rel.p.EffectKey == "" {
+		return rel.p.VariableX
+	}
+	return rel.p.CauseKey
+}
+
+func (rel *relationshipResolver) Effect() string {
+	if rel.p.CauseKey == "" && rel.p.EffectKey == "" {
+		return rel.p.VariableY
+	}
+	return rel.p.EffectKey
+}
+
+func (rel *relationshipResolver) TestType() string { return rel.p.TestType }
+
+func (rel *relationshipResolver) EffectSize() *float64 {
+	if effect := rel.effect(); !math.IsNaN(effect) {
+		return &effect
+	}
+	return nil
+}
+
+func (rel *relationshipResolver) PValue() float64   { return rel.p.PValue }
+func (rel *relationshipResolver) QValue() float64   { return rel.p.QValue }
+func (rel *relationshipResolver) SampleSize() int32 { return int32(rel.p.SampleSize) }
+func (rel *relationshipResolver) Significant() bool {
+	return rel.p.QValue < models.SummarySignificanceLevel
+}
+func (rel *relationshipResolver) Warnings() []string            { return append([]string{}, rel.p.Warnings...) }
+func (rel *relationshipResolver) PracticalSignificance() string { return rel.p.PracticalSignificance }
+
+func (rel *relationshipResolver) Variables(ctx context.Context) ([]*variableAuditResolver, error) {
+	cause, err := rel.run.audit(ctx, rel.Cause())
+	if err != nil {
+		return nil, err
+	}
+	effect, err := rel.run.audit(ctx, rel.Effect())
+	if err != nil {
+		return nil, err
+	}
+	return []*variableAuditResolver{cause, effect}, nil
+}
+
+type variableAuditResolver struct {
+	key                       string
+	dataType                  *string
+	missingCount, uniqueCount *int32
+	missingRate               *float64
+	fingerprint               *string
+	changed                   bool
+}
+
+// fieldAudit is what a dataset's profile recorded about one of its fields
+func fieldAudit(ds *dataset.Dataset, field dataset.FieldInfo) *variableAuditResolver {
+	dataType := field.DataType
+	missing, unique := int32(field.MissingCount), int32(field.UniqueCount)
+	audit := &variableAuditResolver{key: field.Name, dataType: &dataType, missingCount: &missing, uniqueCount: &unique}
+	if ds.RecordCount > 0 {
+		rate := float64(field.MissingCount) / float64(ds.RecordCount)
+		audit.missingRate = &rate
+	}
+	return audit
+}
+
+func (a *variableAuditResolver) Key() string           { return a.key }
+func (a *variableAuditResolver) DataType() *string     { return a.dataType }
+func (a *variableAuditResolver) MissingCount() *int32  { return a.missingCount }
+func (a *variableAuditResolver) UniqueCount() *int32   { return a.uniqueCount }
+func (a *variableAuditResolver) MissingRate() *float64 { return a.missingRate }
+func (a *variableAuditResolver) Fingerprint() *string  { return a.fingerprint }
+func (a *variableAuditResolver) Changed() bool         { return a.changed }
+
+type hypothesisResolver struct {
+	r *resolver
+	h *models.HypothesisResult
+}
+
+func (h *hypothesisResolver) ID() graphql.ID             { return graphql.ID(h.h.ID) }
+func (h *hypothesisResolver) SessionID() string          { return h.h.SessionID }
+func (h *hypothesisResolver) BusinessHypothesis() string { return h.h.BusinessHypothesis }
+func (h *hypothesisResolver) ScienceHypothesis() string  { return h.h.ScienceHypothesis }
+func (h *hypothesisResolver) NullCase() string           { return h.h.NullCase }
+func (h *hypothesisResolver) Passed() bool               { return h.h.Passed }
+func (h *hypothesisResolver) Status() string             { return h.h.Status }
+func (h *hypothesisResolver) Confidence() float64        { return h.h.Confidence }
+func (h *hypothesisResolver) EValue() float64            { return h.h.CurrentEValue }
+func (h *hypothesisResolver) ValidatedAt() graphql.Time {
+	return graphql.Time{Time: h.h.ValidationTimestamp}
+}
+
+func (h *hypothesisResolver) Cause() *string  { return h.metadataString("cause_key") }
+func (h *hypothesisResolver) Effect() *string { return h.metadataString("effect_key") }
+
+func (h *hypothesisResolver) metadataString(key string) *string {
+	if value, ok := h.h.ExecutionMetadata[key].(string); ok && value != "" {
+		return &value
+	}
+	return nil
+}
+
+func (h *hypothesisResolver) Workspace(ctx context.Context) (*workspaceResolver, error) {
+	ws, err := h.r.ownedWorkspace(ctx, core.ID(h.h.WorkspaceID))
+	if err != nil || ws == nil {
+		return nil, err
+	}
+	return &workspaceResolver{r: h.r, ws: ws}, nil
+}
+
+func (h *hypothesisResolver) Referees() []*refereeResolver {
+	referees := make([]*refereeResolver, len(h.h.RefereeResults))
+	for i := range h.h.RefereeResults {
+		referees[i] = &refereeResolver{result: h.h.RefereeResults[i]}
+	}
+	return referees
+}
+
+func (h *hypothesisResolver) Run(ctx context.Context) (*runResolver, error) {
+	if h.h.SessionID == "" {
+		return nil, nil
+	}
+	return h.r.run(ctx, "sweep-"+h.h.SessionID, h.h.WorkspaceID)
+}
+
+func (h *hypothesisResolver) Evidence(ctx context.Context) ([]*relationshipResolver, error) {
+	cause, effect := h.Cause(), h.Effect()
+	if cause == nil || effect == nil {
+		return nil, nil
+	}
+	run, err := h.Run(ctx)
+	if err != nil || run == nil {
+		return nil, err
+	}
+	if _, err := run.artifacts(ctx); err != nil {
+		return nil, err
+	}
+	var evidence []*relationshipResolver
+	for _, rel := range run.relationships {
+		x, y := rel.Cause(), rel.Effect()
+		if (x == *cause && y == *effect) || (x == *effect && y == *cause) {
+			evidence = append(evidence, rel)
+		}
+	}
+	return evidence, nil
+}
+
+type refereeResolver struct {
+	result models.RefereeResult
+}
+
+func (r *refereeResolver) Gate() string          { return r.result.GateName }
+func (r *refereeResolver) Passed() bool          { return r.result.Passed }
+func (r *refereeResolver) Statistic() float64    { return r.result.Statistic }
+func (r *refereeResolver) PValue() float64       { return r.result.PValue }
+func (r *refereeResolver) EValue() float64       { return r.result.EValue }
+func (r *refereeResolver) FailureReason() string { return r.result.FailureReason }
+
+// limitOf reads a list limit, capped at maxLimit
+func limitOf(limit int32, fallback int) int {
+	if limit <= 0 {
+		return fallback
+	}
+	if limit > maxLimit {
+		return maxLimit
+	}
+	return int(limit)
+}
+
+// decodePayload decodes an artifact's payload into v, whether it is typed or was read
+// back from JSON
+func decodePayload(artifact core.Artifact, v interface{}) error {
+	data, err := json.Marshal(artifact.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s artifact %s: %w", artifact.Kind, artifact.ID, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s artifact %s: %w", artifact.Kind, artifact.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package graphapi
+
+// schema is the GraphQL schema served at /api/graphql. Lists take a limit so dashboards
+// fetch only what they show; limits above maxLimit are capped.
+const schema = `
+schema {
+	query: Query
+}
+
+scalar Time
+
+type Query {
+	# Workspaces of the current user
+	workspaces: [Workspace!]!
+	workspace(id: ID!): Workspace
+	dataset(id: ID!): Dataset
+	# A sweep run; research sessions sweep as sweep-<session id>. Runs without a stored
+	# summary naming their workspace are reached through their hypothesis instead.
+	run(id: ID!): Run
+	hypothesis(id: ID!): Hypothesis
+}
+
+type Workspace {
+	id: ID!
+	name: String!
+	description: String!
+	isDefault: Boolean!
+	createdAt: Time!
+	datasets(limit: Int = 50, offset: Int = 0): [Dataset!]!
+	# Most recent first; empty when run summaries are not stored
+	runs(limit: Int = 20): [Run!]!
+	hypotheses(limit: Int = 50): [Hypothesis!]!
+}
+
+type Dataset {
+	id: ID!
+	name: String!
+	filename: String!
+	domain: String!
+	status: String!
+	recordCount: Int!
+	fieldCount: Int!
+	missingRate: Float!
+	createdAt: Time!
+	workspace: Workspace
+	runs(limit: Int = 20): [Run!]!
+	variables: [VariableAudit!]!
+}
+
+type Run {
+	id: ID!
+	sessionId: String
+	createdAt: Time
+	fdrMethod: String!
+	bundleFingerprint: String!
+	variablesAnalyzed: Int!
+	relationshipCount: Int!
+	significantPairs: Int!
+	dataset: Dataset
+	# Strongest first; variable keeps the relationships a variable takes part in
+	relationships(significantOnly: Boolean = false, variable: String, limit: Int = 100): [Relationship!]!
+	variables: [VariableAudit!]!
+}
+
+type Relationship {
+	id: ID!
+	run: Run!
+	cause: String!
+	effect: String!
+	testType: String!
+	effectSize: Float
+	pValue: Float!
+	qValue: Float!
+	sampleSize: Int!
+	significant: Boolean!
+	practicalSignificance: String!
+	warnings: [String!]!
+	# Audits of the cause and the effect, in that order
+	variables: [VariableAudit!]!
+}
+
+# What the run and its dataset recorded about one variable
+type VariableAudit {
+	key: String!
+	dataType: String
+	missingCount: Int
+	uniqueCount: Int
+	missingRate: Float
+	# Fingerprint of the column the run swept
+	fingerprint: String
+	# Whether the column changed since the sweep the run was compared against
+	changed: Boolean!
+}
+
+type Hypothesis {
+	id: ID!
+	sessionId: String!
+	workspace: Workspace
+	businessHypothesis: String!
+	scienceHypothesis: String!
+	nullCase: String!
+	cause: String
+	effect: String
+	passed: Boolean!
+	status: String!
+	confidence: Float!
+	eValue: Float!
+	validatedAt: Time!
+	referees: [RefereeResult!]!
+	# The session's sweep run
+	run: Run
+	# The run's relationships between the hypothesis' cause and effect
+	evidence: [Relationship!]!
+}
+
+type RefereeResult {
+	gate: String!
+	passed: Boolean!
+	statistic: Float!
+	pValue: Float!
+	eValue: Float!
+	failureReason: String!
+}
+`
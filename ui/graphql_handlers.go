@@ -0,0 +1,32 @@
+package ui
+
+import (
+	apperrors "gohypo/internal/errors"
+	"gohypo/ui/graphapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGraphQL answers a GraphQL query over workspaces, datasets, runs, relationships and
+// hypotheses, scoped to the default user's workspaces
+func (s *Server) handleGraphQL(c *gin.Context) {
+	s.graphqlOnce.Do(func() {
+		sources := graphapi.Sources{
+			Workspaces:   s.workspaceRepository,
+			Datasets:     s.datasetRepository,
+			RunSummaries: s.runSummaries,
+			Ledger:       s.reader,
+			UserID:       s.getDefaultUserID,
+		}
+		// A nil storage must not reach the sources as a non-nil interface
+		if s.researchStorage != nil {
+			sources.Hypotheses = s.researchStorage
+		}
+		s.graphqlHandler, s.graphqlErr = graphapi.NewHandler(sources)
+	})
+	if s.graphqlErr != nil {
+		respondProblem(c, apperrors.Wrap(s.graphqlErr, "GraphQL API not available"))
+		return
+	}
+	s.graphqlHandler.ServeHTTP(c.Writer, c.Request)
+}
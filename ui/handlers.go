@@ -2,6 +2,7 @@ package ui
 
 import (
 	domainBrief "gohypo/domain/stats/brief"
+	"gohypo/models"
 )
 
 // FieldStats represents statistics for a single field/variable
@@ -39,4 +40,9 @@ type FieldStats struct {
 
 	// Store the original StatisticalBrief for full access
 	StatisticalBrief *domainBrief.StatisticalBrief
+
+	// Artifacts the numbers were read from: the profile (or relationship) behind the field's
+	// data quality, and the relationship behind StrongestCorr
+	Provenance          *models.Provenance
+	StrongestProvenance *models.Provenance
 }
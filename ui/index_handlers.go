@@ -8,7 +8,9 @@ import (
 
 	"gohypo/domain/core"
 	"gohypo/domain/stats"
+	"gohypo/models"
 	"gohypo/ports"
+	"gohypo/ui/services"
 )
 
 // FieldRelationship represents a relationship between two fields
@@ -25,6 +27,7 @@ type FieldRelationship struct {
 	Significant  bool
 	StrengthDesc string
 	IsShadow     bool
+	Provenance   *models.Provenance // the relationship artifact the numbers were read from
 }
 
 // handleIndex renders the main index page with halftone matrix visualization
@@ -35,6 +38,8 @@ func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to load artifacts", http.StatusInternalServerError)
 		return
 	}
+	// Payloads from a newer engine are listed with a notice rather than read as empty
+	allArtifacts, newerArtifacts := services.ReadableArtifacts(allArtifacts)
 
 	// Extract dataset information from artifacts
 	datasetInfo := a.extractDatasetInfo(allArtifacts)
@@ -162,6 +167,7 @@ func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
 		Limit: 1000,
 	}
 	relArtifacts, _ := a.reader.ListArtifacts(r.Context(), relFilters)
+	relArtifacts, _ = services.ReadableArtifacts(relArtifacts)
 
 	for _, artifact := range relArtifacts {
 		if artifact.Kind == core.ArtifactRelationship {
@@ -209,11 +215,12 @@ func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Determine significance rule
-	significanceRule := "p ≤ 0.05"
+	// Determine significance rule from the policy the sweep ran under
+	policy := sweepPolicy(allArtifacts)
+	significanceRule := fmt.Sprintf("p < %g", policy.Alpha)
 	hasQValue := fdrArtifactCount > 0
 	if hasQValue {
-		significanceRule = "q ≤ 0.05 (BH)"
+		significanceRule = fmt.Sprintf("q < %g (BH)", policy.Alpha)
 	}
 
 	// Calculate pairs attempted
@@ -241,7 +248,7 @@ func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
 			} else if relPayload, ok := artifact.Payload.(stats.RelationshipPayload); ok {
 				pValue = relPayload.PValue
 			}
-			if pValue > 0 && pValue < 0.05 {
+			if pValue > 0 && policy.Significant(pValue) {
 				significantCount++
 			}
 		}
@@ -355,6 +362,7 @@ func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
 				stats.Cardinality = cardinality
 				stats.UniqueCount = cardinality // Same value for display
 				stats.SampleSize = sampleSize
+				stats.Provenance = storedProvenance(artifact)
 				// Determine type from variance
 				if variance > 0 {
 					stats.Type = "numeric"
@@ -402,6 +410,8 @@ func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
 	// Compute relationship statistics for each field
 	effectSizesPerField := make(map[string][]float64)
 	significantRelsPerField := make(map[string]int)
+	strongestPerField := make(map[string]float64)
+	strongestProvenance := make(map[string]*models.Provenance)
 
 	for _, artifact := range relArtifacts {
 		if artifact.Kind != core.ArtifactRelationship {
@@ -437,16 +447,22 @@ func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
 			pValue = relPayload.PValue
 		}
 
-		// Track effect sizes and significance for each field
+		// Track effect sizes and significance for each field, and which artifact holds the strongest
+		for _, field := range []string{varX, varY} {
+			if field != "" && (strongestProvenance[field] == nil || math.Abs(effectSize) > math.Abs(strongestPerField[field])) {
+				strongestPerField[field] = effectSize
+				strongestProvenance[field] = storedProvenance(artifact)
+			}
+		}
 		if varX != "" {
 			effectSizesPerField[varX] = append(effectSizesPerField[varX], effectSize)
-			if pValue < 0.05 { // Significant at 5% level
+			if policy.Significant(pValue) {
 				significantRelsPerField[varX]++
 			}
 		}
 		if varY != "" {
 			effectSizesPerField[varY] = append(effectSizesPerField[varY], effectSize)
-			if pValue < 0.05 { // Significant at 5% level
+			if policy.Significant(pValue) {
 				significantRelsPerField[varY]++
 			}
 		}
@@ -467,6 +483,7 @@ func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
 				sum += absES // Use absolute for average
 			}
 			stats.StrongestCorr = strongest
+			stats.StrongestProvenance = strongestProvenance[field]
 			stats.AvgEffectSize = sum / float64(len(effectSizes))
 			stats.TotalRelsAnalyzed = len(effectSizes)
 		}
@@ -538,9 +555,9 @@ func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
 			}
 
 			if relX != "" && relY != "" {
-				significant := relPValue > 0 && relPValue < 0.05
+				significant := relPValue > 0 && policy.Significant(relPValue)
 				if relQValue > 0 {
-					significant = significant && relQValue < 0.05
+					significant = significant && policy.Significant(relQValue)
 				}
 
 				fieldRelationships = append(fieldRelationships, FieldRelationship{
@@ -556,6 +573,7 @@ func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
 					Significant:  significant,
 					StrengthDesc: "Unknown",
 					IsShadow:     false,
+					Provenance:   storedProvenance(artifact),
 				})
 			}
 		}
@@ -623,6 +641,7 @@ func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
 		"FieldStats":         fieldStats,
 		"FieldRelationships": fieldRelationships,
 		"ValidatedCount":     significantCount,
+		"NewerArtifacts":     newerArtifacts,
 		"SessionID":          "default", // Will be updated with actual session ID
 	}
 	a.renderTemplate(w, "main.html", data)
@@ -739,6 +758,25 @@ func (a *App) extractDatasetInfo(artifacts []core.Artifact) map[string]interface
 	return info
 }
 
+// sweepPolicy returns the analysis policy recorded by the latest sweep manifest among the
+// artifacts, or the defaults for sweeps from before policies were recorded
+func sweepPolicy(artifacts []core.Artifact) stats.AnalysisPolicy {
+	var latest *core.Artifact
+	for i, artifact := range artifacts {
+		payload, ok := artifact.Payload.(map[string]interface{})
+		if !ok || payload["analysis_policy"] == nil {
+			continue
+		}
+		if latest == nil || artifact.CreatedAt.After(latest.CreatedAt) {
+			latest = &artifacts[i]
+		}
+	}
+	if latest == nil {
+		return stats.DefaultAnalysisPolicy()
+	}
+	return stats.AnalysisPolicyFrom(latest.Payload.(map[string]interface{}))
+}
+
 // extractSweepInfo extracts sweep run information from artifacts
 func (a *App) extractSweepInfo(artifacts []core.Artifact) map[string]interface{} {
 	info := map[string]interface{}{
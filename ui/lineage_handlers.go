@@ -0,0 +1,381 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// builtInDatasetID names the dataset node of runs over the built-in dataset
+const builtInDatasetID = "built-in"
+
+// handleGetDatasetLineage returns the datasets a dataset was derived from, down to the
+// original uploads
+func (s *Server) handleGetDatasetLineage(c *gin.Context) {
+	if graph, ok := s.datasetLineage(c); ok {
+		c.JSON(http.StatusOK, graph)
+	}
+}
+
+// handleSetDatasetLineage declares the datasets a dataset was derived from and how, for
+// datasets produced outside the merger. An empty derived_from marks the dataset original.
+func (s *Server) handleSetDatasetLineage(c *gin.Context) {
+	var lineage domainDataset.Lineage
+	if err := c.ShouldBindJSON(&lineage); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Request body must be a lineage with derived_from"))
+		return
+	}
+	ds, ok := s.piiDataset(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	if err := lineage.Validate(ds.ID); err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	for i := range lineage.DerivedFrom {
+		edge := &lineage.DerivedFrom[i]
+		parent, err := s.ownedDataset(ctx, ds.UserID, edge.DatasetID)
+		if err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to load parent dataset"))
+			return
+		}
+		if parent == nil {
+			respondProblem(c, apperrors.InvalidInput(fmt.Sprintf("parent dataset %s not found", edge.DatasetID)))
+			return
+		}
+		ancestry, err := domainDataset.BuildLineageGraph(ctx, parent, s.lineageLookup(ds.UserID))
+		if err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to trace parent lineage"))
+			return
+		}
+		if ancestry.Contains(ds.ID.String()) {
+			respondProblem(c, apperrors.InvalidInput(fmt.Sprintf("dataset %s is already derived from %s", edge.DatasetID, ds.ID)))
+			return
+		}
+		if edge.Transformation.RecordedAt.IsZero() {
+			edge.Transformation.RecordedAt = time.Now()
+		}
+	}
+
+	ds.Metadata.Lineage = &lineage
+	if len(lineage.DerivedFrom) == 0 {
+		ds.Metadata.Lineage = nil
+	}
+	// UpdatedAt is left alone: research picks the most recently updated dataset of a workspace
+	if err := s.datasetRepository.Update(ctx, ds); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to update dataset"))
+		return
+	}
+	graph, err := domainDataset.BuildLineageGraph(ctx, ds, s.lineageLookup(ds.UserID))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to trace lineage"))
+		return
+	}
+	c.JSON(http.StatusOK, graph)
+}
+
+// handleGetRunLineage returns the dataset a sweep run computed its matrix from and that
+// dataset's lineage
+func (s *Server) handleGetRunLineage(c *gin.Context) {
+	if graph, ok := s.runLineage(c, c.Param("id")); ok {
+		c.JSON(http.StatusOK, graph)
+	}
+}
+
+// handleGetHypothesisLineage returns the run a hypothesis was generated from, down to the
+// original uploads of the run's dataset
+func (s *Server) handleGetHypothesisLineage(c *gin.Context) {
+	if graph, ok := s.hypothesisLineage(c); ok {
+		c.JSON(http.StatusOK, graph)
+	}
+}
+
+// handleDatasetLineagePage, handleRunLineagePage and handleHypothesisLineagePage draw the
+// lineage graphs as pages
+func (s *Server) handleDatasetLineagePage(c *gin.Context) {
+	if graph, ok := s.datasetLineage(c); ok {
+		s.renderLineagePage(c, graph, "/api/datasets/"+url.PathEscape(c.Param("id"))+"/lineage")
+	}
+}
+
+func (s *Server) handleRunLineagePage(c *gin.Context) {
+	if graph, ok := s.runLineage(c, c.Param("id")); ok {
+		s.renderLineagePage(c, graph, "/api/runs/"+url.PathEscape(c.Param("id"))+"/lineage")
+	}
+}
+
+func (s *Server) handleHypothesisLineagePage(c *gin.Context) {
+	if graph, ok := s.hypothesisLineage(c); ok {
+		s.renderLineagePage(c, graph, "/api/hypotheses/"+url.PathEscape(c.Param("hypothesisId"))+"/lineage")
+	}
+}
+
+func (s *Server) datasetLineage(c *gin.Context) (*domainDataset.LineageGraph, bool) {
+	ds, ok := s.piiDataset(c)
+	if !ok {
+		return nil, false
+	}
+	graph, err := domainDataset.BuildLineageGraph(c.Request.Context(), ds, s.lineageLookup(ds.UserID))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to trace lineage"))
+		return nil, false
+	}
+	return graph, true
+}
+
+// runLineage roots the lineage of the run's dataset at the run. A run without a summary
+// has no recorded dataset and is shown alone.
+func (s *Server) runLineage(c *gin.Context, runID string) (*domainDataset.LineageGraph, bool) {
+	if s.runSummaries == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Run summaries not available"})
+		return nil, false
+	}
+	ctx := c.Request.Context()
+	summary, err := s.runSummaries.GetRunSummary(ctx, runID)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to read run summary"))
+		return nil, false
+	}
+	if summary == nil {
+		return &domainDataset.LineageGraph{
+			Root:      runID,
+			Nodes:     []domainDataset.LineageNode{{ID: runID, Kind: domainDataset.LineageNodeRun, Label: runID, Missing: true}},
+			Edges:     []domainDataset.GraphEdge{},
+			Originals: []core.ID{},
+		}, true
+	}
+
+	userID, err := s.getDefaultUserID(ctx)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to get user"))
+		return nil, false
+	}
+	root := &domainDataset.Dataset{ID: builtInDatasetID, DisplayName: "Built-in dataset", Source: builtInDatasetID}
+	missing := false
+	if summary.DatasetID != nil {
+		if root, err = s.ownedDataset(ctx, userID, core.ID(*summary.DatasetID)); err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to load run dataset"))
+			return nil, false
+		}
+		if root == nil {
+			// The dataset was deleted after the run; keep its ID so the gap shows
+			root = &domainDataset.Dataset{ID: core.ID(*summary.DatasetID), DisplayName: *summary.DatasetID}
+			missing = true
+		}
+	}
+	graph, err := domainDataset.BuildLineageGraph(ctx, root, s.lineageLookup(userID))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to trace lineage"))
+		return nil, false
+	}
+	if missing {
+		graph.Nodes[0].Missing, graph.Nodes[0].Original = true, false
+		graph.Originals = []core.ID{}
+	}
+	graph.Extend(runID, domainDataset.LineageNodeRun, runID, domainDataset.RelationSwept)
+	return graph, true
+}
+
+func (s *Server) hypothesisLineage(c *gin.Context) (*domainDataset.LineageGraph, bool) {
+	hypothesis, ok := s.ownedHypothesis(c)
+	if !ok {
+		return nil, false
+	}
+	graph, ok := s.runLineage(c, "sweep-"+hypothesis.SessionID)
+	if !ok {
+		return nil, false
+	}
+	label := hypothesis.BusinessHypothesis
+	if label == "" {
+		label = hypothesis.ID
+	}
+	graph.Extend(hypothesis.ID, domainDataset.LineageNodeHypothesis, label, domainDataset.RelationFoundIn)
+	return graph, true
+}
+
+// ownedDataset loads a dataset in a workspace of userID, or nil when it is not stored or
+// its workspace belongs to someone else
+func (s *Server) ownedDataset(ctx context.Context, userID, id core.ID) (*domainDataset.Dataset, error) {
+	if s.datasetRepository == nil || s.workspaceRepository == nil {
+		return nil, fmt.Errorf("dataset repository not available")
+	}
+	ds, err := s.datasetRepository.GetByID(ctx, id)
+	if err != nil || ds == nil {
+		// The repositories report a missing dataset as an error, like any failed read
+		return nil, nil
+	}
+	if _, err := s.userWorkspace(ctx, userID, ds.WorkspaceID); err != nil {
+		return nil, nil
+	}
+	return ds, nil
+}
+
+// lineageLookup loads the parents of a lineage walk; another user's dataset shows as missing
+func (s *Server) lineageLookup(userID core.ID) domainDataset.DatasetLookup {
+	return func(ctx context.Context, id core.ID) (*domainDataset.Dataset, error) {
+		return s.ownedDataset(ctx, userID, id)
+	}
+}
+
+// Layout of the lineage graph view: one column per depth, the root on the left
+const (
+	lineageBoxWidth  = 210
+	lineageBoxHeight = 54
+	lineageColumnGap = 110
+	lineageRowGap    = 26
+	lineageMargin    = 20
+
+	// lineageLabelRunes bounds a node label, so long hypothesis statements fit their box
+	lineageLabelRunes = 28
+)
+
+type lineageBox struct {
+	domainDataset.LineageNode
+	X, Y int
+	Href string
+}
+
+type lineageArrow struct {
+	X1, Y1, X2, Y2 int
+	Label          string
+}
+
+type lineageRow struct {
+	From, To, Relation, Transformation string
+}
+
+func (s *Server) renderLineagePage(c *gin.Context, graph *domainDataset.LineageGraph, jsonURL string) {
+	rows := make(map[int]int)
+	boxes := make([]lineageBox, 0, len(graph.Nodes))
+	position := make(map[string]lineageBox, len(graph.Nodes))
+	width, height := 0, 0
+	for _, node := range graph.Nodes {
+		if label := []rune(node.Label); len(label) > lineageLabelRunes {
+			node.Label = string(label[:lineageLabelRunes-1]) + "…"
+		}
+		box := lineageBox{
+			LineageNode: node,
+			X:           lineageMargin + node.Depth*(lineageBoxWidth+lineageColumnGap),
+			Y:           lineageMargin + rows[node.Depth]*(lineageBoxHeight+lineageRowGap),
+		}
+		rows[node.Depth]++
+		if node.Kind == domainDataset.LineageNodeDataset && !node.Missing && node.ID != builtInDatasetID && node.ID != graph.Root {
+			box.Href = "/datasets/" + url.PathEscape(node.ID) + "/lineage"
+		}
+		boxes = append(boxes, box)
+		position[node.ID] = box
+		width = max(width, box.X+lineageBoxWidth+lineageMargin)
+		height = max(height, box.Y+lineageBoxHeight+lineageMargin)
+	}
+
+	labels := make(map[string]string, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		labels[node.ID] = node.Label
+	}
+	arrows := make([]lineageArrow, 0, len(graph.Edges))
+	table := make([]lineageRow, 0, len(graph.Edges))
+	for _, edge := range graph.Edges {
+		from, to := position[edge.From], position[edge.To]
+		arrow := lineageArrow{
+			X1: from.X + lineageBoxWidth, Y1: from.Y + lineageBoxHeight/2,
+			X2: to.X, Y2: to.Y + lineageBoxHeight/2,
+			Label: strings.ReplaceAll(edge.Relation, "_", " "),
+		}
+		row := lineageRow{From: labels[edge.From], To: labels[edge.To], Relation: arrow.Label}
+		if edge.Transformation != nil {
+			arrow.Label = edge.Transformation.Summary()
+			row.Transformation = arrow.Label
+			if len(edge.Transformation.Filters) > 0 && edge.Transformation.Kind != domainDataset.TransformFilter {
+				row.Transformation += "; filtered by " + strings.Join(edge.Transformation.Filters, " and ")
+			}
+		}
+		arrows = append(arrows, arrow)
+		table = append(table, row)
+	}
+
+	var page strings.Builder
+	data := gin.H{
+		"Graph":   graph,
+		"Boxes":   boxes,
+		"Arrows":  arrows,
+		"Rows":    table,
+		"Width":   width,
+		"Height":  height,
+		"BoxW":    lineageBoxWidth,
+		"BoxH":    lineageBoxHeight,
+		"JSONURL": jsonURL,
+		"Title":   s.branding.Title("Lineage"),
+		"Style":   brandStyle(s.branding),
+	}
+	if err := lineageTemplate.Execute(&page, data); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to render lineage page"))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page.String()))
+}
+
+var lineageTemplate = template.Must(template.New("lineage").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+{{.Style}}
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 0; color: var(--brand-accent); font-size: 14px; }
+header { padding: 12px 20px; border-bottom: 3px solid var(--brand-primary); display: flex; gap: 16px; align-items: center; }
+header h1 { font-size: 16px; margin: 0; }
+.meta { color: #6b7280; }
+main { padding: 8px 20px 40px; }
+svg { display: block; margin: 12px 0; }
+.node rect { fill: #fff; stroke: #9ca3af; stroke-width: 1.5; }
+.node.original rect { stroke: var(--brand-primary); stroke-width: 2.5; }
+.node.run rect, .node.hypothesis rect { fill: #f3f4f6; }
+.node.missing rect { stroke-dasharray: 5 4; fill: #fef2f2; stroke: #991b1b; }
+.node .label { font-size: 13px; font-weight: 600; }
+.node .sub { font-size: 11px; fill: #6b7280; }
+.edge { stroke: #9ca3af; stroke-width: 1.5; fill: none; marker-end: url(#arrow); }
+.edge-label { font-size: 11px; fill: #374151; }
+table { border-collapse: collapse; margin-top: 16px; }
+th, td { text-align: left; padding: 4px 12px 4px 0; border-bottom: 1px solid #f3f4f6; }
+th { color: #6b7280; font-weight: 500; }
+</style>
+</head>
+<body>
+<header>
+<h1>Lineage · <span class="meta">{{.Graph.Root}}</span></h1>
+<span class="meta">{{len .Graph.Originals}} original dataset{{if ne (len .Graph.Originals) 1}}s{{end}}{{if .Graph.Truncated}}, truncated{{end}}</span>
+<a href="{{.JSONURL}}">JSON</a>
+</header>
+<main>
+<svg width="{{.Width}}" height="{{.Height}}" xmlns="http://www.w3.org/2000/svg">
+<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#9ca3af"/></marker></defs>
+{{range .Arrows}}<g><path class="edge" d="M {{.X1}} {{.Y1}} L {{.X2}} {{.Y2}}"/><text class="edge-label" x="{{.X1}}" y="{{.Y1}}" dx="8" dy="-6">{{.Label}}</text></g>
+{{end}}
+{{range .Boxes}}<g class="node {{.Kind}}{{if .Original}} original{{end}}{{if .Missing}} missing{{end}}" transform="translate({{.X}},{{.Y}})">
+{{if .Href}}<a href="{{.Href}}">{{end}}<rect width="{{$.BoxW}}" height="{{$.BoxH}}" rx="6"/>
+<text class="label" x="10" y="22">{{.Label}}</text>
+<text class="sub" x="10" y="40">{{.Kind}}{{if .Missing}} · not stored{{else if .Filename}} · {{.Filename}}{{end}}{{if .Original}} · original{{end}}</text>
+<title>{{.ID}}</title>{{if .Href}}</a>{{end}}
+</g>
+{{end}}
+</svg>
+{{if .Rows}}<table>
+<tr><th>From</th><th>Relation</th><th>To</th><th>Transformation</th></tr>
+{{range .Rows}}<tr><td>{{.From}}</td><td>{{.Relation}}</td><td>{{.To}}</td><td>{{.Transformation}}</td></tr>
+{{end}}</table>{{else if eq (index .Graph.Nodes 0).Kind "dataset"}}<p class="meta">Original dataset: it was uploaded or pulled from a source, not derived from other datasets.</p>{{end}}
+</main>
+</body>
+</html>
+`))
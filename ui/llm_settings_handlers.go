@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+	"gohypo/ui/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetWorkspaceLLMSettings returns a workspace's LLM defaults and lock state
+func (s *Server) handleGetWorkspaceLLMSettings(c *gin.Context) {
+	if s.llmSettingsStore == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LLM settings store not available"})
+		return
+	}
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return
+	}
+	s.respondLLMSettings(c, string(workspace.ID))
+}
+
+// respondLLMSettings writes a workspace's current LLM settings
+func (s *Server) respondLLMSettings(c *gin.Context, workspaceID string) {
+	settings, err := s.llmSettingsStore.Get(c.Request.Context(), workspaceID)
+	if err != nil {
+		respondProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// handlePutWorkspaceLLMSettings replaces a workspace's LLM defaults unless they are locked
+func (s *Server) handlePutWorkspaceLLMSettings(c *gin.Context) {
+	if s.llmSettingsStore == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LLM settings store not available"})
+		return
+	}
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return
+	}
+	var body models.LLMSettings
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid LLM settings: "+err.Error()))
+		return
+	}
+	if err := body.Validate(); err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	if err := s.llmSettingsStore.Put(c.Request.Context(), string(workspace.ID), body); err != nil {
+		respondProblem(c, err)
+		return
+	}
+	s.respondLLMSettings(c, string(workspace.ID))
+}
+
+// handleLockWorkspaceLLMSettings pins a workspace to its LLM settings, optionally setting them
+// in the same call so the approved model is never unlocked in between. The lock records the
+// admin key that set it.
+func (s *Server) handleLockWorkspaceLLMSettings(c *gin.Context) {
+	if s.llmSettingsStore == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LLM settings store not available"})
+		return
+	}
+	lockedBy := middleware.AdminCaller(c)
+	if lockedBy == "" {
+		respondProblem(c, apperrors.Forbidden("Locking LLM settings requires ADMIN_API_KEYS to be configured"))
+		return
+	}
+	workspace, ok := s.adminWorkspace(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		Settings *models.LLMSettings `json:"settings,omitempty"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		respondProblem(c, apperrors.InvalidInput("Invalid lock request: "+err.Error()))
+		return
+	}
+	if body.Settings != nil {
+		if err := body.Settings.Validate(); err != nil {
+			respondProblem(c, apperrors.InvalidInput(err.Error()))
+			return
+		}
+	}
+	if err := s.llmSettingsStore.Lock(c.Request.Context(), string(workspace.ID), body.Settings, lockedBy); err != nil {
+		respondProblem(c, err)
+		return
+	}
+	s.respondLLMSettings(c, string(workspace.ID))
+}
+
+// handleUnlockWorkspaceLLMSettings lets the workspace's settings be edited and overridden again
+func (s *Server) handleUnlockWorkspaceLLMSettings(c *gin.Context) {
+	if s.llmSettingsStore == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LLM settings store not available"})
+		return
+	}
+	if middleware.AdminCaller(c) == "" {
+		respondProblem(c, apperrors.Forbidden("Unlocking LLM settings requires ADMIN_API_KEYS to be configured"))
+		return
+	}
+	workspace, ok := s.adminWorkspace(c)
+	if !ok {
+		return
+	}
+	if err := s.llmSettingsStore.Unlock(c.Request.Context(), string(workspace.ID)); err != nil {
+		respondProblem(c, err)
+		return
+	}
+	s.respondLLMSettings(c, string(workspace.ID))
+}
@@ -4,7 +4,11 @@ import (
 	"io/fs"
 	"log"
 	"net/http"
+	"strings"
 
+	"gohypo/adapters/postgres"
+	"gohypo/domain/core"
+	apperrors "gohypo/internal/errors"
 	"gohypo/ui/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -12,10 +16,18 @@ import (
 
 // setupMiddleware configures Gin middleware
 func (s *Server) setupMiddleware() {
+	// Correlation IDs first so every later log line and error response can carry one
+	s.router.Use(middleware.CorrelationID())
+	s.router.Use(middleware.Metrics())
+	s.router.Use(middleware.AdminAPIKey(func() []string { return s.adminAPIKeys }))
+
 	// Add workspace middleware to ensure default workspace exists
 	if s.workspaceRepository != nil {
 		s.router.Use(middleware.EnsureWorkspace(s.workspaceRepository))
 	}
+	if s.schemaProvisioner != nil {
+		s.router.Use(s.workspaceScope())
+	}
 
 	staticFS, err := fs.Sub(s.embeddedFiles, "ui/static")
 	if err != nil {
@@ -49,3 +61,38 @@ func (s *Server) setupMiddleware() {
 		s.router.StaticFS("/static", http.FS(staticFS))
 	}
 }
+
+// workspaceScope binds each request to its tenant in schema-per-workspace mode: the workspace
+// its route or workspace_id names and, outside the admin API, the user it acts for. The
+// schema-routed dataset repository then refuses datasets of any other tenant.
+func (s *Server) workspaceScope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if workspaceID := requestWorkspaceID(c); workspaceID != "" {
+			ctx = postgres.WithWorkspaceScope(ctx, workspaceID)
+		}
+		if !strings.HasPrefix(c.Request.URL.Path, middleware.AdminPathPrefix) {
+			userID, err := s.getDefaultUserID(ctx)
+			if err != nil {
+				respondProblem(c, apperrors.Wrap(err, "Failed to get user"))
+				c.Abort()
+				return
+			}
+			ctx = postgres.WithOwnerScope(ctx, userID)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// requestWorkspaceID returns the workspace a request's route names, or its workspace_id
+// query parameter
+func requestWorkspaceID(c *gin.Context) core.ID {
+	route := c.FullPath()
+	for _, prefix := range []string{"/api/workspaces/:id", "/workspaces/:id", "/api/admin/workspaces/:id"} {
+		if strings.HasPrefix(route, prefix) {
+			return core.ID(c.Param("id"))
+		}
+	}
+	return core.ID(c.Query("workspace_id"))
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CorrelationIDHeader carries the request correlation ID in both directions
+const CorrelationIDHeader = "X-Correlation-ID"
+
+const correlationIDKey = "correlationID"
+
+// CorrelationID tags every request with an ID, reusing the caller's X-Correlation-ID or
+// X-Request-ID when present, and echoes it on the response so logs and errors can be joined
+func CorrelationID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(CorrelationIDHeader)
+		if id == "" {
+			id = c.GetHeader("X-Request-ID")
+		}
+		if id == "" || len(id) > 128 {
+			id = uuid.NewString()
+		}
+		c.Set(correlationIDKey, id)
+		c.Header(CorrelationIDHeader, id)
+		c.Next()
+	}
+}
+
+// CorrelationIDFrom returns the request's correlation ID, or "" outside the middleware
+func CorrelationIDFrom(c *gin.Context) string {
+	return c.GetString(correlationIDKey)
+}
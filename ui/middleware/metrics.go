@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"gohypo/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics counts requests and observes their latency by route pattern, so /api/datasets/:id
+// is one series however many datasets there are. Unmatched paths share the "unmatched" route.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPRequests.Inc(c.Request.Method, route, strconv.Itoa(c.Writer.Status()))
+		metrics.HTTPRequestDuration.Observe(metrics.Since(start), c.Request.Method, route)
+	}
+}
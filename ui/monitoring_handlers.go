@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gohypo/adapters/excel"
+	"gohypo/adapters/postgres"
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+	"gohypo/internal/api"
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/monitoring"
+	"gohypo/models"
+	"gohypo/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// monitoringSessionID is the SSE session degraded-relationship alerts are broadcast on
+const monitoringSessionID = "monitoring"
+
+// newRelationshipMonitor wires the monitor to the dataset files and the SSE hub. The floor
+// comes from MONITORING_EFFECT_FLOOR.
+func (s *Server) newRelationshipMonitor(db *sqlx.DB) *monitoring.Monitor {
+	monitor := monitoring.NewMonitor(s.hypothesisRepo, s.datasetRepository, postgres.NewRelationshipCheckRepository(db),
+		func(ctx context.Context, ds *domainDataset.Dataset) (ports.MatrixResolverPort, func(), error) {
+			path, cleanup := ds.FilePath, func() {}
+			if s.fileStorage != nil {
+				var err error
+				if path, cleanup, err = s.fileStorage.LocalPath(ctx, ds.FilePath); err != nil {
+					return nil, nil, err
+				}
+			}
+			return excel.NewExcelMatrixResolverAdapter(excel.ExcelConfig{FilePath: path}), cleanup, nil
+		})
+	if floor, err := strconv.ParseFloat(os.Getenv("MONITORING_EFFECT_FLOOR"), 64); err == nil {
+		monitor.Floor = floor
+	}
+	monitor.OnAlert = func(check *models.RelationshipCheck) {
+		if s.sseHub == nil {
+			return
+		}
+		s.sseHub.Broadcast(api.ResearchEvent{
+			SessionID:    monitoringSessionID,
+			EventType:    "relationship_degraded",
+			HypothesisID: check.HypothesisID,
+			DatasetID:    check.DatasetID,
+			Data: map[string]interface{}{
+				"check": check,
+			},
+			Timestamp:     time.Now(),
+			EventCategory: "HYPOTHESIS",
+		})
+	}
+	log.Printf("[Initialize] Relationship monitoring enabled (effect floor %.2f)", monitor.Floor)
+	return monitor
+}
+
+// checkTrackedRelationships re-estimates validated relationships on a dataset version that
+// just finished processing
+func (s *Server) checkTrackedRelationships(datasetID core.ID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	checks, err := s.monitor.CheckDataset(ctx, datasetID)
+	if err != nil {
+		log.Printf("[Monitor] ❌ Relationship check failed for dataset %s: %v", datasetID, err)
+		return
+	}
+	log.Printf("[Monitor] ✅ Re-estimated %d tracked relationships on dataset %s", len(checks), datasetID)
+}
+
+// workspaceMonitoringUser verifies the workspace belongs to the default user and returns them
+func (s *Server) workspaceMonitoringUser(c *gin.Context) (uuid.UUID, bool) {
+	if s.monitor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Relationship monitoring not available"})
+		return uuid.Nil, false
+	}
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return uuid.Nil, false
+	}
+	parsed, err := uuid.Parse(string(workspace.UserID))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Invalid user ID"))
+		return uuid.Nil, false
+	}
+	return parsed, true
+}
+
+// handleGetWorkspaceMonitoring lists the workspace's tracked relationships with their
+// effect-size trajectories. With format=svg and a hypothesis_id it charts one trajectory.
+func (s *Server) handleGetWorkspaceMonitoring(c *gin.Context) {
+	userID, ok := s.workspaceMonitoringUser(c)
+	if !ok {
+		return
+	}
+	relationships, err := s.monitor.Tracked(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondProblem(c, err)
+		return
+	}
+
+	if c.Query("format") == "svg" {
+		hypothesisID := c.Query("hypothesis_id")
+		for _, relationship := range relationships {
+			if relationship.HypothesisID == hypothesisID {
+				c.Data(http.StatusOK, "image/svg+xml", []byte(trajectorySVG(relationship, s.monitor.Floor)))
+				return
+			}
+		}
+		respondProblem(c, apperrors.NotFound("Tracked relationship"))
+		return
+	}
+
+	degraded := 0
+	for _, relationship := range relationships {
+		if relationship.Status == models.TrackingDegraded {
+			degraded++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"workspace_id":  c.Param("id"),
+		"floor":         s.monitor.Floor,
+		"tracked":       len(relationships),
+		"degraded":      degraded,
+		"relationships": relationships,
+	})
+}
+
+// handleCheckWorkspaceMonitoring re-estimates the tracked relationships on a dataset version
+// now: the dataset_id given, otherwise the workspace's most recently updated ready dataset
+func (s *Server) handleCheckWorkspaceMonitoring(c *gin.Context) {
+	if _, ok := s.workspaceMonitoringUser(c); !ok {
+		return
+	}
+	datasetID := core.ID(c.Query("dataset_id"))
+	if datasetID == "" {
+		datasets, err := s.datasetRepository.GetByWorkspace(c.Request.Context(), core.ID(c.Param("id")), 100, 0)
+		if err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to list workspace datasets"))
+			return
+		}
+		var latest *domainDataset.Dataset
+		for _, ds := range datasets {
+			if ds.Status == domainDataset.StatusReady && ds.FilePath != "" && (latest == nil || ds.UpdatedAt.After(latest.UpdatedAt)) {
+				latest = ds
+			}
+		}
+		if latest == nil {
+			respondProblem(c, apperrors.NotFound("Ready dataset"))
+			return
+		}
+		datasetID = latest.ID
+	}
+
+	checks, err := s.monitor.CheckDataset(c.Request.Context(), datasetID)
+	if err != nil {
+		respondProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"dataset_id": datasetID,
+		"checked":    len(checks),
+		"checks":     checks,
+	})
+}
+
+// trajectorySVG draws a relationship's directional effect per check against the validated
+// effect and the floor
+func trajectorySVG(relationship models.TrackedRelationship, floor float64) string {
+	const width, height, pad = 480.0, 200.0, 30.0
+	validated := relationship.ValidatedCorrelation
+	if validated < 0 {
+		validated = -validated
+	}
+	lo, hi := floor, validated
+	for _, check := range relationship.Trajectory {
+		if check.Error == "" {
+			lo = min(lo, check.DirectionalEffect)
+			hi = max(hi, check.DirectionalEffect)
+		}
+	}
+	lo, hi = min(lo, 0)-0.05, max(hi, 0)+0.05
+	y := func(v float64) float64 { return pad + (hi-v)/(hi-lo)*(height-2*pad) }
+	x := func(i int) float64 {
+		if len(relationship.Trajectory) < 2 {
+			return width / 2
+		}
+		return pad + float64(i)/float64(len(relationship.Trajectory)-1)*(width-2*pad)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="sans-serif" font-size="10">`, width, height, width, height)
+	fmt.Fprintf(&b, `<text x="%.0f" y="14">%s → %s</text>`, pad, template.HTMLEscapeString(relationship.Cause), template.HTMLEscapeString(relationship.Effect))
+	fmt.Fprintf(&b, `<line x1="%.0f" x2="%.0f" y1="%.1f" y2="%.1f" stroke="#9ca3af" stroke-dasharray="4 3"/><text x="%.0f" y="%.1f" fill="#6b7280">validated %.2f</text>`,
+		pad, width-pad, y(validated), y(validated), width-pad-70, y(validated)-3, validated)
+	fmt.Fprintf(&b, `<line x1="%.0f" x2="%.0f" y1="%.1f" y2="%.1f" stroke="#dc2626" stroke-dasharray="4 3"/><text x="%.0f" y="%.1f" fill="#dc2626">floor %.2f</text>`,
+		pad, width-pad, y(floor), y(floor), width-pad-70, y(floor)+11, floor)
+
+	var points []string
+	for i, check := range relationship.Trajectory {
+		if check.Error != "" {
+			continue
+		}
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x(i), y(check.DirectionalEffect)))
+	}
+	if len(points) > 1 {
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="#2563eb" stroke-width="2" points="%s"/>`, strings.Join(points, " "))
+	}
+	for i, check := range relationship.Trajectory {
+		color := "#2563eb"
+		if check.Degraded {
+			color = "#dc2626"
+		}
+		value := check.DirectionalEffect
+		if check.Error != "" {
+			value = lo + 0.05
+		}
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"><title>%s: %.3f (n=%d) %s</title></circle>`,
+			x(i), y(value), color, check.DatasetVersion.Format("2006-01-02 15:04"), check.DirectionalEffect, check.SampleSize, template.HTMLEscapeString(check.Error))
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+	"gohypo/domain/stats"
+	apperrors "gohypo/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// noiseCalibrator runs the burn-in calibration of a workspace's noise floor
+type noiseCalibrator interface {
+	CalibrateNoiseFloor(ctx context.Context, workspaceID uuid.UUID, ds *domainDataset.Dataset, userID core.ID, opts stats.NoiseFloorOptions) (*stats.NoiseFloor, error)
+}
+
+// calibrateNoiseFloorRequest sizes a calibration; the dataset defaults to the workspace's latest
+type calibrateNoiseFloorRequest struct {
+	stats.NoiseFloorOptions
+	DatasetID string `json:"dataset_id"`
+}
+
+// handleGetNoiseFloor returns a workspace's calibrated noise floor, if any
+func (s *Server) handleGetNoiseFloor(c *gin.Context) {
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return
+	}
+	respondNoiseFloor(c, workspace.ID, stats.NoiseFloorFrom(workspace.Metadata))
+}
+
+// handleCalibrateNoiseFloor permutes the workspace's dataset, runs a reduced sweep on each
+// permutation and keeps the null distribution of effect sizes as the workspace's noise floor.
+// It replaces any earlier calibration and applies to runs from now on.
+func (s *Server) handleCalibrateNoiseFloor(c *gin.Context) {
+	if s.noiseCalibrator == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Noise floor calibration not available"})
+		return
+	}
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return
+	}
+	var req calibrateNoiseFloorRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+			return
+		}
+	}
+	if req.Permutations < 0 || req.Permutations > stats.MaxNoiseFloorPermutations || req.Pairs < 0 {
+		respondProblem(c, apperrors.InvalidInput("permutations must be between 1 and 200, and pairs positive"))
+		return
+	}
+	ds, ok := s.explorerDataset(c, workspace.ID, req.DatasetID)
+	if !ok {
+		return
+	}
+	workspaceID, _ := uuid.Parse(string(workspace.ID))
+	userID, _ := s.getDefaultUserID(c.Request.Context())
+	floor, err := s.noiseCalibrator.CalibrateNoiseFloor(c.Request.Context(), workspaceID, ds, userID, req.NoiseFloorOptions)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to calibrate the noise floor"))
+		return
+	}
+	respondNoiseFloor(c, workspace.ID, floor)
+}
+
+// handleClearNoiseFloor drops a workspace's noise floor; later runs show no percentiles
+func (s *Server) handleClearNoiseFloor(c *gin.Context) {
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return
+	}
+	if _, calibrated := workspace.Metadata[stats.NoiseFloorKey]; calibrated {
+		delete(workspace.Metadata, stats.NoiseFloorKey)
+		if err := s.workspaceRepository.Update(c.Request.Context(), workspace); err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to update workspace"))
+			return
+		}
+	}
+	respondNoiseFloor(c, workspace.ID, nil)
+}
+
+// respondNoiseFloor writes a workspace's noise floor with the effect sizes noise reaches at
+// common percentiles
+func respondNoiseFloor(c *gin.Context, workspaceID core.ID, floor *stats.NoiseFloor) {
+	if floor == nil {
+		c.JSON(http.StatusOK, gin.H{"workspace_id": workspaceID, "calibrated": false})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"workspace_id": workspaceID,
+		"calibrated":   true,
+		"noise_floor":  floor,
+		"levels": gin.H{
+			"p50": floor.Level(50),
+			"p95": floor.Level(95),
+			"p99": floor.Level(99),
+		},
+	})
+}
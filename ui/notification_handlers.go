@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+
+	"gohypo/domain/core"
+	"gohypo/domain/stage"
+	"gohypo/domain/stats"
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/notify"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetNotifier lets workspaces test their chat channels through notifier, the service the
+// research worker announces validated hypotheses with
+func (s *Server) SetNotifier(notifier *notify.Service) {
+	s.notifier = notifier
+}
+
+// handleListNotificationChannels returns a workspace's chat channels with their webhook URLs
+// masked
+func (s *Server) handleListNotificationChannels(c *gin.Context) {
+	workspaceID, ok := s.notificationWorkspace(c)
+	if !ok {
+		return
+	}
+	channels, err := s.notificationChannels.ListChannels(c.Request.Context(), workspaceID)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list notification channels"))
+		return
+	}
+	masked := make([]models.NotificationChannel, 0, len(channels))
+	for _, channel := range channels {
+		masked = append(masked, channel.Masked())
+	}
+	c.JSON(http.StatusOK, gin.H{"channels": masked})
+}
+
+// handleCreateNotificationChannel adds a Slack or Teams channel announcing the workspace's
+// hypotheses that pass min_rigor (decision by default)
+func (s *Server) handleCreateNotificationChannel(c *gin.Context) {
+	workspaceID, ok := s.notificationWorkspace(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Kind       models.NotificationKind `json:"kind" binding:"required"`
+		Name       string                  `json:"name" binding:"required"`
+		WebhookURL string                  `json:"webhook_url" binding:"required"`
+		MinRigor   stage.RigorProfile      `json:"min_rigor"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+	channel, err := models.NewNotificationChannel(workspaceID, req.Kind, req.Name, req.WebhookURL, req.MinRigor)
+	if err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	if userID, err := s.getDefaultUserID(c.Request.Context()); err == nil {
+		channel.CreatedBy = userID.String()
+	}
+	if err := s.notificationChannels.CreateChannel(c.Request.Context(), channel); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to create notification channel"))
+		return
+	}
+	c.JSON(http.StatusCreated, channel.Masked())
+}
+
+// handleDeleteNotificationChannel stops announcing hypotheses in a channel
+func (s *Server) handleDeleteNotificationChannel(c *gin.Context) {
+	workspaceID, ok := s.notificationWorkspace(c)
+	if !ok {
+		return
+	}
+	if err := s.notificationChannels.DeleteChannel(c.Request.Context(), workspaceID, c.Param("channelId")); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to delete notification channel"))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// handleTestNotificationChannel posts a sample card to a channel, so its webhook can be checked
+// before a real hypothesis is announced there
+func (s *Server) handleTestNotificationChannel(c *gin.Context) {
+	workspaceID, ok := s.notificationWorkspace(c)
+	if !ok {
+		return
+	}
+	if s.notifier == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Notifications not available"})
+		return
+	}
+	channel, err := s.notificationChannels.GetChannel(c.Request.Context(), workspaceID, c.Param("channelId"))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to load notification channel"))
+		return
+	}
+	card := models.Notification{
+		Title: "Test notification",
+		Text:  "This channel will be told about hypotheses that pass " + string(channel.MinRigor) + " rigor.",
+		Facts: []models.NotificationFact{
+			{Label: "Channel", Value: channel.Name},
+			{Label: "Minimum rigor", Value: string(channel.MinRigor)},
+		},
+	}
+	if err := s.notifier.Send(c.Request.Context(), channel, card); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to send test notification"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": channel.ID, "sent": true})
+}
+
+// handleValidationPage renders a hypothesis' validation page for its owner, the page chat
+// notifications link to
+func (s *Server) handleValidationPage(c *gin.Context) {
+	hypothesis, ok := s.ownedHypothesis(c)
+	if !ok {
+		return
+	}
+	policy := stats.DefaultAnalysisPolicy()
+	if s.workspaceRepository != nil {
+		if workspace, err := s.workspaceRepository.GetByID(c.Request.Context(), core.ID(hypothesis.WorkspaceID)); err == nil {
+			policy = stats.AnalysisPolicyFrom(workspace.Metadata)
+		}
+	}
+	data := newSharedValidationPage(hypothesis, s.branding, policy)
+	if s.validationQueue != nil {
+		data.PendingRevalidation = s.validationQueue.Pending(hypothesis.WorkspaceID)[hypothesis.ID]
+	}
+	data.Provenance, data.ChipStyle = hypothesisProvenance(hypothesis), template.CSS(provenanceChipStyle)
+	var page strings.Builder
+	if err := sharedValidationTemplate.Execute(&page, data); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to render validation page"))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page.String()))
+}
+
+// notificationWorkspace resolves the caller's workspace of a notification channel route
+func (s *Server) notificationWorkspace(c *gin.Context) (string, bool) {
+	if s.notificationChannels == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Notification channels not available"})
+		return "", false
+	}
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return "", false
+	}
+	return string(workspace.ID), true
+}
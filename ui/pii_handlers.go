@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"net/http"
+	"time"
+
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetDatasetPII returns the PII scan of a dataset with the decision on every flagged column
+func (s *Server) handleGetDatasetPII(c *gin.Context) {
+	ds, ok := s.piiDataset(c)
+	if !ok {
+		return
+	}
+	report := ds.Metadata.PII
+	if report == nil {
+		report = &domainDataset.PIIReport{Columns: []domainDataset.PIIColumn{}}
+	}
+	c.JSON(http.StatusOK, gin.H{"dataset_id": ds.ID, "pii": report})
+}
+
+// handleOverrideDatasetPII includes a flagged column in matrices, or excludes it again. Only
+// columns the scan flagged can be overridden; other columns are never excluded.
+func (s *Server) handleOverrideDatasetPII(c *gin.Context) {
+	var body struct {
+		Include *bool  `json:"include"`
+		Reason  string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.Include == nil {
+		respondProblem(c, apperrors.InvalidInput("Request body must set include to true or false"))
+		return
+	}
+	ds, ok := s.piiDataset(c)
+	if !ok {
+		return
+	}
+	column := ds.Metadata.PII.Column(c.Param("field"))
+	if column == nil {
+		respondProblem(c, apperrors.NotFound("PII column"))
+		return
+	}
+	userID, err := s.getDefaultUserID(c.Request.Context())
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to get user"))
+		return
+	}
+
+	column.Override = &domainDataset.PIIOverride{
+		Include:   *body.Include,
+		Reason:    body.Reason,
+		UserID:    userID,
+		UpdatedAt: time.Now(),
+	}
+	column.Excluded = !*body.Include
+	// UpdatedAt is left alone: research picks the most recently updated dataset of a workspace
+	if err := s.datasetRepository.Update(c.Request.Context(), ds); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to update dataset"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"dataset_id": ds.ID, "column": column})
+}
+
+// piiDataset loads a dataset owned by the default user for PII review
+func (s *Server) piiDataset(c *gin.Context) (*domainDataset.Dataset, bool) {
+	if s.datasetRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Dataset repository not available"})
+		return nil, false
+	}
+	ds, err := s.datasetRepository.GetByID(c.Request.Context(), core.ID(c.Param("id")))
+	if err != nil {
+		respondProblem(c, apperrors.NotFound("Dataset"))
+		return nil, false
+	}
+	if _, ok := s.ownedWorkspaceByID(c, ds.WorkspaceID); !ok {
+		return nil, false
+	}
+	return ds, true
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/portability"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxArchiveSize caps the workspace archives import reads
+const maxArchiveSize = 256 << 20
+
+// handleExportWorkspace downloads a workspace as a portable archive: its datasets' metadata,
+// variable contracts, run summaries and hypotheses, without the dataset files
+func (s *Server) handleExportWorkspace(c *gin.Context) {
+	workspace, ok := s.portabilityWorkspace(c)
+	if !ok {
+		return
+	}
+	archive, err := s.portability().Export(c.Request.Context(), workspace.ID, workspace.UserID)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to export workspace"))
+		return
+	}
+	filename := fmt.Sprintf("workspace-%s-%s.json", workspace.ID, archive.ExportedAt.Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.JSON(http.StatusOK, archive)
+}
+
+// handleImportWorkspace recreates an exported workspace for the current user, from the
+// request body or a multipart "archive" file
+func (s *Server) handleImportWorkspace(c *gin.Context) {
+	if !s.portabilityAvailable(c) {
+		return
+	}
+	userID, err := s.getDefaultUserID(c.Request.Context())
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to get user"))
+		return
+	}
+
+	// The limit wraps the request body itself, so a multipart form is capped while it is parsed
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxArchiveSize)
+	body := io.Reader(c.Request.Body)
+	if c.ContentType() == "multipart/form-data" {
+		file, _, err := c.Request.FormFile("archive")
+		if err != nil {
+			respondProblem(c, apperrors.InvalidInput("No archive file provided"))
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+	var archive portability.Archive
+	if err := json.NewDecoder(body).Decode(&archive); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid workspace archive"))
+		return
+	}
+
+	started := time.Now()
+	result, err := s.portability().Import(c.Request.Context(), &archive, userID)
+	if err != nil {
+		respondProblem(c, err)
+		return
+	}
+	log.Printf("[Portability] Imported %s in %v", result, time.Since(started))
+	c.JSON(http.StatusCreated, result)
+}
+
+// handleBulkDeleteDatasets deletes datasets of a workspace. Datasets that runs still
+// reference are reported with a 409 and nothing is deleted, unless force is set.
+func (s *Server) handleBulkDeleteDatasets(c *gin.Context) {
+	workspace, ok := s.portabilityWorkspace(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		DatasetIDs []core.ID `json:"dataset_ids"`
+		Force      bool      `json:"force"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+
+	result, err := s.portability().DeleteDatasets(c.Request.Context(), workspace.ID, body.DatasetIDs, body.Force)
+	if errors.Is(err, portability.ErrDatasetsInUse) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Runs reference some of these datasets; nothing was deleted. Retry with force to delete them anyway.",
+			"blocked": result.Blocked,
+		})
+		return
+	}
+	if err != nil {
+		respondProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// portability builds the export, import and bulk delete service over the server's stores
+func (s *Server) portability() *portability.Service {
+	service := &portability.Service{
+		Workspaces: s.workspaceRepository,
+		Datasets:   s.datasetRepository,
+		Sessions:   s.sessionRepository,
+		Hypotheses: s.hypothesisRepo,
+		Contracts:  s.contractStore,
+		Runs:       s.runSummaries,
+	}
+	if s.fileStorage != nil {
+		service.Files = s.fileStorage
+	}
+	return service
+}
+
+// portabilityAvailable reports whether the stores export and import need are set up
+func (s *Server) portabilityAvailable(c *gin.Context) bool {
+	if s.workspaceRepository == nil || s.datasetRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Workspace service not available"})
+		return false
+	}
+	return true
+}
+
+// portabilityWorkspace loads the workspace in the path, checking the current user owns it
+func (s *Server) portabilityWorkspace(c *gin.Context) (*domainDataset.Workspace, bool) {
+	if !s.portabilityAvailable(c) {
+		return nil, false
+	}
+	return s.ownedWorkspace(c)
+}
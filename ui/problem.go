@@ -0,0 +1,22 @@
+package ui
+
+import (
+	"log"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/ui/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondProblem writes err as application/problem+json with the status its taxonomy code
+// maps to. The full error chain is logged under the correlation ID; clients only see the
+// classified message.
+func respondProblem(c *gin.Context, err error) {
+	correlationID := middleware.CorrelationIDFrom(c)
+	problem := apperrors.NewProblem(err, correlationID, c.Request.URL.Path)
+	log.Printf("[API] %s %s failed (%s, correlation_id=%s): %v", c.Request.Method, c.Request.URL.Path, problem.Code, correlationID, err)
+
+	c.Header("Content-Type", "application/problem+json")
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"strings"
+
+	"gohypo/domain/core"
+	"gohypo/models"
+)
+
+// provenanceChipStyle styles provenance chips; pages showing them include it once
+const provenanceChipStyle = `a.provenance { display: inline-block; margin-left: .35rem; padding: .05rem .45rem; border-radius: 999px; background: #f3f4f6; color: #4b5563; font: .72rem monospace; text-decoration: none; vertical-align: middle; }
+a.provenance:hover { background: #e5e7eb; color: #111827; }`
+
+var provenanceChipTemplate = template.Must(template.New("provenance_chip").Parse(
+	`<a class="provenance" href="{{.URL}}" target="_blank" rel="noopener" title="Artifact {{.ArtifactID}} · fingerprint {{.Fingerprint}}">` +
+		`{{.Stage}} · {{.Short}}</a>`))
+
+// artifactURL is where the UI serves an artifact's raw JSON
+func artifactURL(artifactID string) string {
+	return "/api/artifacts/" + url.PathEscape(artifactID)
+}
+
+// provenanceChip renders where a number came from as a chip linking to the artifact's JSON;
+// nothing when the number has no stored artifact behind it
+func provenanceChip(p *models.Provenance) template.HTML {
+	if p == nil || p.ArtifactID == "" {
+		return ""
+	}
+	short := p.Fingerprint
+	if len(short) > 8 {
+		short = short[:8]
+	}
+	var b strings.Builder
+	err := provenanceChipTemplate.Execute(&b, struct {
+		models.Provenance
+		URL   string
+		Short string
+	}{*p, artifactURL(p.ArtifactID), short})
+	if err != nil {
+		return ""
+	}
+	return template.HTML(b.String())
+}
+
+// provenanceMarkdown renders a report's source line; empty without provenance
+func provenanceMarkdown(p *models.Provenance) string {
+	if p == nil || p.ArtifactID == "" {
+		return ""
+	}
+	return fmt.Sprintf("*Source: %s artifact [`%s`](%s), fingerprint `%s`.*\n", p.Stage, p.ArtifactID, artifactURL(p.ArtifactID), p.Fingerprint)
+}
+
+// storedProvenance is the provenance of an artifact read from the ledger
+func storedProvenance(artifact core.Artifact) *models.Provenance {
+	p := models.ArtifactProvenance(artifact)
+	return &p
+}
+
+// hypothesisProvenance is the provenance of a hypothesis' verdict, or nil
+func hypothesisProvenance(h *models.HypothesisResult) *models.Provenance {
+	if p, ok := models.ProvenanceOf(h); ok {
+		return &p
+	}
+	return nil
+}
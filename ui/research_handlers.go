@@ -9,8 +9,11 @@ import (
 	"time"
 
 	"gohypo/internal/api"
+	processor "gohypo/internal/dataset"
+	apperrors "gohypo/internal/errors"
 	"gohypo/internal/research"
 	"gohypo/models"
+	"gohypo/ports"
 	"gohypo/ui/services"
 
 	"github.com/gin-gonic/gin"
@@ -22,6 +25,8 @@ type ResearchHandler struct {
 	hypothesisRepo interface {
 		GetHypothesis(ctx context.Context, workspaceID uuid.UUID, hypothesisID string) (*models.HypothesisResult, error)
 	}
+	llmSettings ports.WorkspaceLLMSettingsStore // nil runs every workspace on the global AI config
+	jobs        *research.JobRunner             // nil runs sessions in request goroutines
 }
 
 func NewResearchHandler(dataService *services.DataService, hypothesisRepo interface {
@@ -38,12 +43,14 @@ func (h *ResearchHandler) HandleInitiateResearch(sessionMgr *research.SessionMan
 		log.Printf("[API] 🚀 INITIATING RESEARCH SESSION - REQUEST RECEIVED")
 
 		// Extract workspace ID from request (supports both JSON and form data)
-		var workspaceIDStr string
+		var workspaceIDStr, sweepMode, alignFrequency string
 		var err error
 
 		if c.GetHeader("Content-Type") == "application/json" {
 			var requestBody struct {
-				WorkspaceID string `json:"workspace_id"`
+				WorkspaceID    string `json:"workspace_id"`
+				SweepMode      string `json:"sweep_mode"`      // "workspace" joins every dataset of the workspace
+				AlignFrequency string `json:"align_frequency"` // period time series are joined in
 			}
 			if err = c.ShouldBindJSON(&requestBody); err != nil {
 				log.Printf("[API] ❌ Invalid JSON request body: %v", err)
@@ -53,6 +60,7 @@ func (h *ResearchHandler) HandleInitiateResearch(sessionMgr *research.SessionMan
 				return
 			}
 			workspaceIDStr = requestBody.WorkspaceID
+			sweepMode, alignFrequency = requestBody.SweepMode, requestBody.AlignFrequency
 		} else {
 			// Handle form data from HTMX
 			workspaceIDStr = c.PostForm("workspace_id")
@@ -60,6 +68,23 @@ func (h *ResearchHandler) HandleInitiateResearch(sessionMgr *research.SessionMan
 				// Try query parameter as fallback
 				workspaceIDStr = c.DefaultPostForm("workspace_id", "550e8400-e29b-41d4-a716-446655440001")
 			}
+			sweepMode, alignFrequency = c.PostForm("sweep_mode"), c.PostForm("align_frequency")
+		}
+
+		sessionOptions := map[string]interface{}{}
+		switch sweepMode {
+		case "":
+		case research.SweepModeWorkspace:
+			frequency, err := processor.ParseAlignFrequency(alignFrequency)
+			if err != nil {
+				respondProblem(c, apperrors.InvalidInput(err.Error()))
+				return
+			}
+			sessionOptions[research.SweepModeKey] = sweepMode
+			sessionOptions[research.AlignFrequencyKey] = frequency
+		default:
+			respondProblem(c, apperrors.InvalidInput(fmt.Sprintf("unknown sweep_mode %q (use %s)", sweepMode, research.SweepModeWorkspace)))
+			return
 		}
 
 		if workspaceIDStr == "" {
@@ -83,18 +108,14 @@ func (h *ResearchHandler) HandleInitiateResearch(sessionMgr *research.SessionMan
 		fieldMetadata, err := h.dataService.GetFieldMetadataByWorkspace(workspaceID)
 		if err != nil {
 			log.Printf("[API] ❌ Failed to get field metadata for workspace %s: %v", workspaceID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to retrieve field metadata for workspace",
-			})
+			respondProblem(c, apperrors.Wrap(err, "Failed to retrieve field metadata for workspace"))
 			return
 		}
 
 		statsArtifacts, err := h.dataService.GetStatisticalArtifactsByWorkspace(workspaceID)
 		if err != nil {
 			log.Printf("[API] ❌ Failed to get statistical artifacts for workspace %s: %v", workspaceID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to retrieve statistical artifacts for workspace",
-			})
+			respondProblem(c, apperrors.Wrap(err, "Failed to retrieve statistical artifacts for workspace"))
 			return
 		}
 
@@ -114,16 +135,13 @@ func (h *ResearchHandler) HandleInitiateResearch(sessionMgr *research.SessionMan
 			return
 		}
 
-		session, err := sessionMgr.CreateSessionInWorkspace(c.Request.Context(), workspaceID.String(), map[string]interface{}{
-			"field_count":           len(fieldMetadata),
-			"stats_artifacts_count": len(statsArtifacts),
-			"timestamp":             time.Now(),
-		})
+		sessionOptions["field_count"] = len(fieldMetadata)
+		sessionOptions["stats_artifacts_count"] = len(statsArtifacts)
+		sessionOptions["timestamp"] = time.Now()
+		session, err := sessionMgr.CreateSessionInWorkspace(c.Request.Context(), workspaceID.String(), sessionOptions)
 		if err != nil {
 			log.Printf("[API] ❌ Failed to create session: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to create research session",
-			})
+			respondProblem(c, apperrors.Wrap(err, "Failed to create research session"))
 			return
 		}
 
@@ -143,10 +161,22 @@ func (h *ResearchHandler) HandleInitiateResearch(sessionMgr *research.SessionMan
 			Timestamp: time.Now(),
 		})
 
-		go func() {
-			log.Printf("[WORKER] 🏁 Starting background research process for session %s", session.ID)
-			worker.ProcessResearch(context.Background(), session.ID.String(), fieldMetadata, statsArtifacts, sseHub)
-		}()
+		if h.jobs != nil {
+			job, err := h.jobs.EnqueueResearch(c.Request.Context(), research.ResearchJob{
+				SessionID:   session.ID.String(),
+				WorkspaceID: workspaceID.String(),
+			})
+			if err != nil {
+				respondProblem(c, apperrors.Wrap(err, "Failed to queue research session"))
+				return
+			}
+			log.Printf("[API] 📥 Queued research job %s for session %s", job.ID, session.ID)
+		} else {
+			go func() {
+				log.Printf("[WORKER] 🏁 Starting background research process for session %s", session.ID)
+				worker.ProcessResearch(context.Background(), session.ID.String(), fieldMetadata, statsArtifacts, sseHub)
+			}()
+		}
 
 		log.Printf("[API] ✅ Research session %s successfully scheduled", session.ID)
 
@@ -195,9 +225,7 @@ func (h *ResearchHandler) HandleResearchStatus(sessionMgr *research.SessionManag
 		activeSessions, err := sessionMgr.GetActiveSessions(c.Request.Context())
 		if err != nil {
 			log.Printf("[API] ❌ Failed to get active sessions: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to retrieve session status",
-			})
+			respondProblem(c, apperrors.Wrap(err, "Failed to retrieve session status"))
 			return
 		}
 
@@ -206,9 +234,7 @@ func (h *ResearchHandler) HandleResearchStatus(sessionMgr *research.SessionManag
 			allSessions, err := sessionMgr.ListSessions(c.Request.Context(), nil)
 			if err != nil {
 				log.Printf("[API] ❌ Failed to get all sessions: %v", err)
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error": "Failed to retrieve session status",
-				})
+				respondProblem(c, apperrors.Wrap(err, "Failed to retrieve session status"))
 				return
 			}
 			if len(allSessions) > 0 {
@@ -221,6 +247,7 @@ func (h *ResearchHandler) HandleResearchStatus(sessionMgr *research.SessionManag
 					"current_hypothesis": status["current_hypothesis"],
 					"completed_count":    status["completed_count"],
 					"error":              status["error"],
+					"llm_usage":          session.Metadata["llm_usage"],
 				}
 			} else {
 				response = gin.H{
@@ -237,6 +264,7 @@ func (h *ResearchHandler) HandleResearchStatus(sessionMgr *research.SessionManag
 				"progress":           status["progress"],
 				"current_hypothesis": status["current_hypothesis"],
 				"completed_count":    status["completed_count"],
+				"llm_usage":          session.Metadata["llm_usage"],
 			}
 		}
 
@@ -244,12 +272,44 @@ func (h *ResearchHandler) HandleResearchStatus(sessionMgr *research.SessionManag
 	}
 }
 
+// HandleValidationProgress returns a session's running referee batteries, with how long
+// each referee is expected to take and the battery's ETA, and every referee run recorded
+// for the session. Runs a restart cut short are reported as interrupted.
+func (h *ResearchHandler) HandleValidationProgress(sessionMgr *research.SessionManager, worker *research.ResearchWorker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if worker == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Research worker not available"})
+			return
+		}
+		sessionID := c.Param("id")
+		if _, err := uuid.Parse(sessionID); err != nil {
+			respondProblem(c, apperrors.InvalidInput("invalid session ID"))
+			return
+		}
+		if _, err := sessionMgr.GetSession(c.Request.Context(), sessionID); err != nil {
+			respondProblem(c, apperrors.NotFound("session "+sessionID))
+			return
+		}
+
+		progress, err := worker.ValidationProgress(c.Request.Context(), sessionID)
+		if err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to load validation progress"))
+			return
+		}
+		c.JSON(http.StatusOK, progress)
+	}
+}
+
 func (h *ResearchHandler) HandleGenerateHypotheses(sessionMgr *research.SessionManager, worker *research.ResearchWorker, sseHub *api.SSEHub) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		log.Printf("[API] 🤖 GENERATING HYPOTHESES - REQUEST RECEIVED")
 
 		var requestBody struct {
-			SessionID string `json:"session_id"`
+			SessionID string             `json:"session_id"`
+			DryRun    bool               `json:"dry_run"`                            // render the prompt without calling the LLM
+			Domain    string             `json:"domain_pack,omitempty"`              // few-shot exemplar pack; defaults to the detected domain
+			Samples   int                `json:"self_consistency_samples,omitempty"` // sample each generation call N times and keep the consensus
+			LLM       models.LLMSettings `json:"llm,omitempty"`                      // per-run provider, model and sampling overrides
 		}
 
 		if err := c.ShouldBindJSON(&requestBody); err != nil {
@@ -285,9 +345,7 @@ func (h *ResearchHandler) HandleGenerateHypotheses(sessionMgr *research.SessionM
 		fieldMetadata, err := h.dataService.GetFieldMetadataByWorkspace(workspaceID)
 		if err != nil {
 			log.Printf("[API] ❌ Failed to get field metadata for workspace %s: %v", workspaceID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to retrieve field metadata",
-			})
+			respondProblem(c, apperrors.Wrap(err, "Failed to retrieve field metadata"))
 			return
 		}
 
@@ -295,14 +353,18 @@ func (h *ResearchHandler) HandleGenerateHypotheses(sessionMgr *research.SessionM
 		statsArtifacts, err := h.dataService.GetStatisticalArtifactsByWorkspace(workspaceID)
 		if err != nil {
 			log.Printf("[API] ❌ Failed to get statistical artifacts for workspace %s: %v", workspaceID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to retrieve statistical artifacts",
-			})
+			respondProblem(c, apperrors.Wrap(err, "Failed to retrieve statistical artifacts"))
 			return
 		}
 
 		log.Printf("[API] 📊 Found %d fields and %d statistical artifacts for hypothesis generation", len(fieldMetadata), len(statsArtifacts))
 
+		llmSettings, err := h.resolveLLMSettings(c.Request.Context(), workspaceID, requestBody.LLM)
+		if err != nil {
+			respondProblem(c, err)
+			return
+		}
+
 		if len(fieldMetadata) == 0 {
 			log.Printf("[API] ⚠️ No fields available - cannot generate hypotheses")
 			c.JSON(http.StatusBadRequest, gin.H{
@@ -311,11 +373,46 @@ func (h *ResearchHandler) HandleGenerateHypotheses(sessionMgr *research.SessionM
 			return
 		}
 
+		if requestBody.DryRun || c.Query("dry_run") == "true" {
+			ctx := research.WithLLMSettings(research.WithDomainPack(c.Request.Context(), requestBody.Domain), llmSettings)
+			preview, err := worker.PreviewHypothesisPrompt(ctx, sessionID, fieldMetadata, statsArtifacts)
+			if err != nil {
+				respondProblem(c, apperrors.Wrap(err, "Failed to assemble hypothesis prompt"))
+				return
+			}
+			log.Printf("[API] 🔍 Dry run for session %s: %d prompt chars, LLM not called", sessionID, preview.TotalChars)
+			c.JSON(http.StatusOK, gin.H{
+				"session_id":      sessionID,
+				"dry_run":         true,
+				"field_count":     len(fieldMetadata),
+				"stats_artifacts": len(statsArtifacts),
+				"prompt":          preview,
+			})
+			return
+		}
+
 		// Start background hypothesis generation
-		go func() {
-			log.Printf("[WORKER] 🤖 Starting hypothesis generation for session %s", sessionID)
-			worker.ProcessResearch(context.Background(), sessionID, fieldMetadata, statsArtifacts, sseHub)
-		}()
+		if h.jobs != nil {
+			job, err := h.jobs.EnqueueResearch(c.Request.Context(), research.ResearchJob{
+				SessionID:   sessionID,
+				WorkspaceID: workspaceID.String(),
+				Domain:      requestBody.Domain,
+				Samples:     requestBody.Samples,
+				LLM:         llmSettings,
+			})
+			if err != nil {
+				respondProblem(c, apperrors.Wrap(err, "Failed to queue hypothesis generation"))
+				return
+			}
+			log.Printf("[API] 📥 Queued research job %s for session %s", job.ID, sessionID)
+		} else {
+			go func() {
+				log.Printf("[WORKER] 🤖 Starting hypothesis generation for session %s", sessionID)
+				ctx := research.WithSelfConsistency(research.WithDomainPack(context.Background(), requestBody.Domain), requestBody.Samples)
+				ctx = research.WithLLMSettings(ctx, llmSettings)
+				worker.ProcessResearch(ctx, sessionID, fieldMetadata, statsArtifacts, sseHub)
+			}()
+		}
 
 		log.Printf("[API] ✅ Hypothesis generation started for session %s", sessionID)
 
@@ -329,6 +426,26 @@ func (h *ResearchHandler) HandleGenerateHypotheses(sessionMgr *research.SessionM
 	}
 }
 
+// resolveLLMSettings layers a run's LLM overrides on the workspace defaults. Overrides on a
+// workspace an admin has locked are refused.
+func (h *ResearchHandler) resolveLLMSettings(ctx context.Context, workspaceID uuid.UUID, run models.LLMSettings) (models.LLMSettings, error) {
+	if err := run.Validate(); err != nil {
+		return models.LLMSettings{}, apperrors.InvalidInput(err.Error())
+	}
+	if h.llmSettings == nil || workspaceID == uuid.Nil {
+		return run, nil
+	}
+	stored, err := h.llmSettings.Get(ctx, workspaceID.String())
+	if err != nil {
+		return models.LLMSettings{}, apperrors.Wrap(err, "Failed to load workspace LLM settings")
+	}
+	settings, err := stored.ForRun(run)
+	if err != nil {
+		return models.LLMSettings{}, apperrors.Forbidden(err.Error())
+	}
+	return settings, nil
+}
+
 // GetStabilityAnalysis returns detailed stability analysis for a hypothesis subsample
 func (h *ResearchHandler) GetStabilityAnalysis(c *gin.Context) {
 	hypothesisID := c.Param("hypothesisId")
@@ -18,7 +18,27 @@ func (s *Server) AddResearchRoutes(sessionMgr *research.SessionManager, storage
 }) {
 	// Set research components on server
 	s.researchStorage = storage
+	s.sessionManager = sessionMgr
 	s.renderService = services.NewRenderService(s.templates)
+	if worker != nil {
+		s.outcomeCalibrator = worker.EValueCalibrator()
+		s.refreshOutcomeCalibration(context.Background())
+		s.retester = research.NewRetester(storage, worker)
+		s.skeptic = worker
+		s.revalidation = worker
+		s.pairExplorer = worker
+		s.noiseCalibrator = worker
+		if sseHub != nil { // a nil hub must not reach the queue as a non-nil broadcaster
+			s.validationQueue = research.NewValidationQueue(storage, s.retester, sseHub)
+		} else {
+			s.validationQueue = research.NewValidationQueue(storage, s.retester, nil)
+		}
+		s.validationQueue.ExportMetrics()
+		if s.autoscalePolicy != nil {
+			s.autoscaler = research.NewAutoscaler(s.validationQueue, *s.autoscalePolicy, s.scaleHooks...)
+			go s.autoscaler.Run(context.Background())
+		}
+	}
 
 	// Initialize services
 	dataService := services.NewDataService(s.reader, s.datasetRepository)
@@ -26,6 +46,12 @@ func (s *Server) AddResearchRoutes(sessionMgr *research.SessionManager, storage
 
 	// Initialize handlers
 	researchHandler := NewResearchHandler(dataService, hypothesisRepo)
+	researchHandler.llmSettings = s.llmSettingsStore
+	if worker != nil && s.jobQueue != nil {
+		s.jobRunner = research.NewJobRunner(s.jobQueue, s.jobLeases, worker, dataService, s.jobOptions)
+		s.jobRunner.Start(context.Background())
+		researchHandler.jobs = s.jobRunner
+	}
 	dataHandler := NewDataHandler(renderService)
 	industryHandler := NewIndustryHandler(s.greenfieldService)
 
@@ -47,6 +73,7 @@ func (s *Server) AddResearchRoutes(sessionMgr *research.SessionManager, storage
 			research.POST("/initiate", researchHandler.HandleInitiateResearch(sessionMgr, worker, sseHub))
 			research.POST("/generate-hypotheses", researchHandler.HandleGenerateHypotheses(sessionMgr, worker, sseHub))
 			research.GET("/status", researchHandler.HandleResearchStatus(sessionMgr))
+			research.GET("/sessions/:id/progress", researchHandler.HandleValidationProgress(sessionMgr, worker))
 			research.GET("/ledger", dataHandler.HandleResearchLedger(storage))
 			research.GET("/download/:id", dataHandler.HandleDownloadHypothesis(storage))
 			research.GET("/industry-context", industryHandler.HandleIndustryContext())
@@ -63,6 +90,9 @@ func (s *Server) AddResearchRoutes(sessionMgr *research.SessionManager, storage
 		api.GET("/hypothesis/:id", dataHandler.HandleHypothesisCard(storage))
 		api.GET("/hypothesis/:id/toggle", dataHandler.HandleHypothesisToggle(storage))
 		api.GET("/hypothesis/:id/evidence", dataHandler.HandleHypothesisEvidence(storage))
+		api.GET("/hypothesis/:id/metric", dataHandler.HandleHypothesisMetric(storage))
+		api.GET("/hypothesis/:id/explanation", dataHandler.HandleHypothesisExplanation(storage))
+		api.GET("/hypothesis/:id/counterexamples", dataHandler.HandleHypothesisCounterexamples(storage))
 		api.GET("/hypotheses/:hypothesisId/stability/:subsampleIndex/:refereeIndex", researchHandler.GetStabilityAnalysis)
 	}
 }
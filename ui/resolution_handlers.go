@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"gohypo/domain/core"
+	"gohypo/internal/access"
+	apperrors "gohypo/internal/errors"
+	"gohypo/ports"
+	"gohypo/ui/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// resolutionRequest is the body of the preview and resolve endpoints
+type resolutionRequest struct {
+	ViewID     string   `json:"view_id"`
+	SnapshotID string   `json:"snapshot_id" binding:"required"`
+	EntityIDs  []string `json:"entity_ids"`
+	VarKeys    []string `json:"var_keys" binding:"required"`
+}
+
+// bindResolutionRequest reads a resolution request, answering 400 when it is invalid
+func bindResolutionRequest(c *gin.Context) (ports.MatrixResolutionRequest, bool) {
+	var req resolutionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return ports.MatrixResolutionRequest{}, false
+	}
+
+	resolutionReq := ports.MatrixResolutionRequest{
+		ViewID:     core.ID(req.ViewID),
+		SnapshotID: core.SnapshotID(req.SnapshotID),
+		EntityIDs:  make([]core.ID, len(req.EntityIDs)),
+		VarKeys:    make([]core.VariableKey, len(req.VarKeys)),
+	}
+	for i, id := range req.EntityIDs {
+		resolutionReq.EntityIDs[i] = core.ID(id)
+	}
+	for i, key := range req.VarKeys {
+		resolutionReq.VarKeys[i] = core.VariableKey(key)
+	}
+	return resolutionReq, true
+}
+
+// handleResolutionPreview returns the compiled SQL and EXPLAIN cost for each requested variable
+func (s *Server) handleResolutionPreview(c *gin.Context) {
+	if s.queryPreviewer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Query preview not available"})
+		return
+	}
+	resolutionReq, ok := bindResolutionRequest(c)
+	if !ok {
+		return
+	}
+
+	preview, err := s.queryPreviewer.PreviewResolution(c.Request.Context(), resolutionReq)
+	if err != nil {
+		log.Printf("[handleResolutionPreview] ERROR: Failed to preview resolution for snapshot %s: %v", resolutionReq.SnapshotID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preview resolution"})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// handleApproveResolutionQuery records a reviewer's approval of compiled queries, with the
+// admin key the request authenticated with as the approver
+func (s *Server) handleApproveResolutionQuery(c *gin.Context) {
+	if s.queryPreviewer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Query preview not available"})
+		return
+	}
+
+	var req struct {
+		QueryHashes []string `json:"query_hashes" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	// Approval is what lets a query run against the warehouse, so it must be attributable
+	approver := middleware.AdminCaller(c)
+	if approver == "" {
+		respondProblem(c, apperrors.Forbidden("Approving queries requires ADMIN_API_KEYS to be configured"))
+		return
+	}
+	for _, hash := range req.QueryHashes {
+		if err := s.queryPreviewer.ApproveQuery(c.Request.Context(), core.Hash(hash), approver); err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to approve query"))
+			return
+		}
+	}
+
+	log.Printf("[handleApproveResolutionQuery] %s approved %d push-down queries", approver, len(req.QueryHashes))
+	c.JSON(http.StatusOK, gin.H{"approved": req.QueryHashes, "approved_by": approver})
+}
+
+// handleResolveMatrix resolves variables against the warehouse with the push-down resolver.
+// Column policies apply, and with approval required only approved queries run. The response
+// carries each variable's resolution audit rather than the values.
+func (s *Server) handleResolveMatrix(c *gin.Context) {
+	if s.warehouseResolver == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Warehouse resolution not available"})
+		return
+	}
+	resolutionReq, ok := bindResolutionRequest(c)
+	if !ok {
+		return
+	}
+
+	resolver := s.warehouseResolver
+	if s.columnEnforcer != nil {
+		resolver = access.NewEnforcingResolver(resolver, s.columnEnforcer)
+	}
+	bundle, err := resolver.ResolveMatrix(c.Request.Context(), resolutionReq)
+	if errors.Is(err, core.ErrQueryNotApproved) {
+		respondProblem(c, apperrors.Forbidden("The resolution includes queries that have not been approved"))
+		return
+	}
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to resolve matrix"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"snapshot_id": bundle.SnapshotID,
+		"rows":        bundle.RowCount(),
+		"variables":   bundle.Matrix.VariableKeys,
+		"audits":      bundle.Audits,
+	})
+}
+
+// handleResolutionPage serves the page data engineers review, approve and run push-down
+// queries from
+func (s *Server) handleResolutionPage(c *gin.Context) {
+	var page strings.Builder
+	data := gin.H{
+		"Title":     s.branding.Title("Warehouse queries"),
+		"Style":     brandStyle(s.branding),
+		"Available": s.queryPreviewer != nil,
+	}
+	if err := resolutionTemplate.Execute(&page, data); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to render resolution page"))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page.String()))
+}
+
+var resolutionTemplate = template.Must(template.New("resolution").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+{{.Style}}
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 0; color: var(--brand-accent); font-size: 14px; }
+header { padding: 12px 20px; border-bottom: 3px solid var(--brand-primary); }
+header h1 { font-size: 16px; margin: 0; }
+main { padding: 12px 20px 40px; max-width: 72rem; }
+label { display: block; margin: 8px 0 2px; color: #6b7280; }
+input, textarea { width: 100%; box-sizing: border-box; font: inherit; padding: 4px 6px; }
+button { margin: 12px 8px 12px 0; padding: 6px 12px; font: inherit; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #f3f4f6; vertical-align: top; }
+th { color: #6b7280; font-weight: 500; }
+pre { white-space: pre-wrap; margin: 0; font-size: 12px; }
+.meta { color: #6b7280; }
+.passed { color: #166534; }
+.failed { color: #991b1b; }
+</style>
+</head>
+<body>
+<header><h1>Warehouse queries</h1></header>
+<main>
+{{if .Available}}
+<p class="meta">Preview the SQL each variable compiles to with the planner's cost, approve the queries you reviewed, then resolve.</p>
+<label for="snapshot">Snapshot ID</label><input id="snapshot">
+<label for="vars">Variables, one per line</label><textarea id="vars" rows="4"></textarea>
+<label for="entities">Entity IDs, one per line</label><textarea id="entities" rows="4"></textarea>
+<label for="apikey">Admin API key, to approve</label><input id="apikey" type="password" autocomplete="off">
+<button id="preview">Preview</button><button id="approve" disabled>Approve selected</button><button id="resolve">Resolve</button>
+<p id="status" class="meta"></p>
+<table id="queries" hidden>
+<thead><tr><th></th><th>Variable</th><th>SQL</th><th>Cost</th><th>Rows</th><th>Approved</th></tr></thead>
+<tbody></tbody>
+</table>
+<script>
+const lines = id => document.getElementById(id).value.split("\n").map(v => v.trim()).filter(Boolean);
+const body = () => JSON.stringify({snapshot_id: document.getElementById("snapshot").value.trim(), var_keys: lines("vars"), entity_ids: lines("entities")});
+const status = text => { document.getElementById("status").textContent = text; };
+const post = async (url, payload, headers = {}) => {
+  const response = await fetch(url, {method: "POST", headers: {"Content-Type": "application/json", ...headers}, body: payload});
+  const data = await response.json();
+  if (!response.ok) throw new Error(data.detail || data.error || response.statusText);
+  return data;
+};
+async function preview() {
+  try {
+    const plan = await post("/api/resolution/preview", body());
+    const rows = document.querySelector("#queries tbody");
+    rows.replaceChildren();
+    for (const v of plan.variables) {
+      const row = rows.insertRow();
+      const pick = document.createElement("input");
+      pick.type = "checkbox"; pick.value = v.query_hash; pick.disabled = !v.query_hash || v.approved;
+      row.insertCell().append(pick);
+      row.insertCell().textContent = v.variable_key;
+      const sql = document.createElement("pre");
+      sql.textContent = v.error ? v.error : v.sql;
+      row.insertCell().append(sql);
+      row.insertCell().textContent = v.estimated_cost.toFixed(1);
+      row.insertCell().textContent = Math.round(v.estimated_rows);
+      const approved = row.insertCell();
+      approved.textContent = v.approved ? "yes" : "no";
+      approved.className = v.approved ? "passed" : "failed";
+    }
+    document.getElementById("queries").hidden = false;
+    document.getElementById("approve").disabled = false;
+    status("Total cost " + plan.total_cost.toFixed(1) + (plan.approval_gated ? " · only approved queries run" : ""));
+  } catch (err) { status(err.message); }
+}
+async function approve() {
+  const hashes = [...document.querySelectorAll("#queries input:checked")].map(box => box.value);
+  if (!hashes.length) { status("Select the queries you reviewed"); return; }
+  try {
+    await post("/api/admin/resolution/approve", JSON.stringify({query_hashes: hashes}), {"X-API-Key": document.getElementById("apikey").value});
+    await preview();
+  } catch (err) { status(err.message); }
+}
+async function resolve() {
+  try {
+    const result = await post("/api/resolution/resolve", body());
+    status("Resolved " + result.variables.length + " variables over " + result.rows + " entities");
+  } catch (err) { status(err.message); }
+}
+document.getElementById("preview").onclick = preview;
+document.getElementById("approve").onclick = approve;
+document.getElementById("resolve").onclick = resolve;
+</script>
+{{else}}<p>Warehouse resolution needs a PostgreSQL database.</p>
+{{end}}</main>
+</body>
+</html>
+`))
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetWorkspaceStorage reports how much of a workspace's artifact storage is in the
+// hot ledger and how much is archived
+func (s *Server) handleGetWorkspaceStorage(c *gin.Context) {
+	if s.archiver == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Artifact archiving not enabled"})
+		return
+	}
+	usage, err := s.archiver.Usage(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"usage":             usage,
+		"total_bytes":       usage.TotalBytes(),
+		"compression_ratio": usage.CompressionRatio(),
+	})
+}
+
+// handleArchiveArtifacts runs an archiving pass now instead of waiting for the next one
+func (s *Server) handleArchiveArtifacts(c *gin.Context) {
+	if s.archiver == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Artifact archiving not enabled"})
+		return
+	}
+	report, err := s.archiver.ArchiveInactive(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"report":  report,
+	})
+}
+
+// handleGetLedgerRetention reports the ledger retention policy and the latest compaction
+func (s *Server) handleGetLedgerRetention(c *gin.Context) {
+	if s.compactor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Ledger retention not enabled"})
+		return
+	}
+	policy := s.compactor.Policy()
+	c.JSON(http.StatusOK, gin.H{
+		"policy":      policy,
+		"spec":        policy.String(),
+		"last_report": s.compactor.LastReport(),
+	})
+}
+
+// handleCompactLedger runs a compaction pass now instead of waiting for the next one
+func (s *Server) handleCompactLedger(c *gin.Context) {
+	if s.compactor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Ledger retention not enabled"})
+		return
+	}
+	report, err := s.compactor.Compact(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"report":  report,
+	})
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"gohypo/domain/causal"
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/research"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleRetestHypothesis re-runs one hypothesis' resolution and referees against the
+// workspace's latest dataset and appends the verdict to its history. The workspace causal
+// graph is built from the stored verdicts, so it reflects the new one straight away; the
+// hypothesis' updated edge is returned alongside.
+func (s *Server) handleRetestHypothesis(c *gin.Context) {
+	if s.retester == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Re-testing not available"})
+		return
+	}
+	hypothesis, ok := s.ownedHypothesis(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	var datasetID string
+	var datasetVersion *time.Time
+	if latest := s.latestReadyDataset(c, core.ID(hypothesis.WorkspaceID)); latest != nil {
+		datasetID = string(latest.ID)
+		datasetVersion = &latest.UpdatedAt
+	}
+	result, err := s.retester.Retest(ctx, hypothesis.ID, datasetID, datasetVersion)
+	if errors.Is(err, research.ErrRetestInProgress) {
+		respondProblem(c, apperrors.Conflict(err.Error()))
+		return
+	}
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to re-test hypothesis"))
+		return
+	}
+
+	response := gin.H{
+		"hypothesis_id": result.Hypothesis.ID,
+		"previous":      result.Previous,
+		"current":       result.Current,
+		"changed":       result.Changed,
+		"history":       result.History,
+		"hypothesis":    result.Hypothesis,
+	}
+	cause, _ := result.Hypothesis.ExecutionMetadata["cause_key"].(string)
+	effect, _ := result.Hypothesis.ExecutionMetadata["effect_key"].(string)
+	if fragment, err := causal.FromHypothesis(result.Hypothesis.ID, core.VariableKey(cause), core.VariableKey(effect), nil, result.Hypothesis.Confidence, result.Hypothesis.Passed); err == nil {
+		response["graph_fragment"] = fragment
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// handleGetVerdictHistory lists a hypothesis' verdicts, oldest first
+func (s *Server) handleGetVerdictHistory(c *gin.Context) {
+	hypothesis, ok := s.ownedHypothesis(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"hypothesis_id": hypothesis.ID,
+		"history":       models.VerdictHistoryOf(hypothesis),
+	})
+}
+
+// ownedHypothesis loads the hypothesis named in the path when its workspace belongs to the
+// default user
+func (s *Server) ownedHypothesis(c *gin.Context) (*models.HypothesisResult, bool) {
+	if s.researchStorage == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Research storage not available"})
+		return nil, false
+	}
+	ctx := c.Request.Context()
+	hypothesis, err := s.researchStorage.GetByID(ctx, c.Param("hypothesisId"))
+	if err != nil || hypothesis == nil {
+		respondProblem(c, apperrors.NotFound("Hypothesis"))
+		return nil, false
+	}
+	if _, ok := s.ownedWorkspaceByID(c, core.ID(hypothesis.WorkspaceID)); !ok {
+		return nil, false
+	}
+	return hypothesis, true
+}
+
+// latestReadyDataset is the most recently updated ready dataset of a workspace, or nil
+func (s *Server) latestReadyDataset(c *gin.Context, workspaceID core.ID) *domainDataset.Dataset {
+	if s.datasetRepository == nil {
+		return nil
+	}
+	datasets, err := s.datasetRepository.GetByWorkspace(c.Request.Context(), workspaceID, 100, 0)
+	if err != nil {
+		return nil
+	}
+	var latest *domainDataset.Dataset
+	for _, ds := range datasets {
+		if ds.IsReady() && (latest == nil || ds.UpdatedAt.After(latest.UpdatedAt)) {
+			latest = ds
+		}
+	}
+	return latest
+}
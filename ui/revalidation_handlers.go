@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/research"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// revalidationScanLimit caps how many of a workspace's hypotheses a staleness scan reads
+const revalidationScanLimit = 10000
+
+// revalidationPlanner finds hypotheses made stale by referee or sense upgrades and estimates
+// re-validating them
+type revalidationPlanner interface {
+	PlanRevalidation(ctx context.Context, workspaceID string, hypotheses []*models.HypothesisResult, capacity int, includeUnversioned bool, pending map[string]bool) *models.RevalidationPlan
+}
+
+// handleGetRevalidationPlan lists a workspace's hypotheses whose referees or senses changed
+// version since they were validated, in the order they would be re-validated, with the
+// estimated cost. ?include_unversioned=true adds hypotheses validated before versions were
+// recorded.
+func (s *Server) handleGetRevalidationPlan(c *gin.Context) {
+	plan, ok := s.revalidationPlan(c, c.Query("include_unversioned") == "true")
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// handleQueueRevalidation queues a workspace's stale hypotheses for background re-validation
+// as one batch in priority order, low queue priority by default so users' own re-validations
+// go first. Until re-validated they show as pending re-validation.
+func (s *Server) handleQueueRevalidation(c *gin.Context) {
+	var req struct {
+		Priority           string `json:"priority"`
+		IncludeUnversioned bool   `json:"include_unversioned"`
+		Limit              int    `json:"limit"` // the first hypotheses of the plan only
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+			return
+		}
+	}
+	if req.Priority == "" {
+		req.Priority = research.QueuePriorityLow
+	}
+	plan, ok := s.revalidationPlan(c, req.IncludeUnversioned)
+	if !ok {
+		return
+	}
+	ids := plan.IDs()
+	if req.Limit > 0 && len(ids) > req.Limit {
+		ids = ids[:req.Limit]
+	}
+	if len(ids) == 0 {
+		c.JSON(http.StatusOK, gin.H{"queued": []research.QueuedValidation{}, "plan": plan})
+		return
+	}
+	batchID, queued, err := s.validationQueue.Enqueue(plan.WorkspaceID, ids, req.Priority, plan.Capacity)
+	if err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"batch_id":   batchID,
+		"queued":     queued,
+		"plan":       plan,
+		"events_url": "/api/workspaces/" + plan.WorkspaceID + "/validation-queue/events",
+	})
+}
+
+// revalidationPlan scans the workspace named by the route for stale hypotheses
+func (s *Server) revalidationPlan(c *gin.Context, includeUnversioned bool) (*models.RevalidationPlan, bool) {
+	if s.revalidation == nil || s.validationQueue == nil || s.researchStorage == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Re-validation not available"})
+		return nil, false
+	}
+	// The admin API re-validates any workspace, whoever owns it
+	workspace, ok := s.adminWorkspace(c)
+	if !ok {
+		return nil, false
+	}
+	ctx := c.Request.Context()
+	workspaceID := string(workspace.ID)
+	hypotheses, err := s.researchStorage.ListByWorkspace(ctx, workspaceID, revalidationScanLimit)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list hypotheses"))
+		return nil, false
+	}
+	capacity := research.ValidationCapacity(workspace.Metadata)
+	return s.revalidation.PlanRevalidation(ctx, workspaceID, hypotheses, capacity, includeUnversioned, s.validationQueue.Pending(workspaceID)), true
+}
@@ -0,0 +1,267 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/logging"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxStoredLogLines caps the stored lines returned for one run
+const maxStoredLogLines = 5000
+
+// handleGetSessionLogs returns the log lines of a research session's run, oldest first, at
+// ?level= (default info) or above. Lines still held in memory are served from there, older
+// runs from the run log store.
+func (s *Server) handleGetSessionLogs(c *gin.Context) {
+	level, err := logging.ParseLevel(c.Query("level"))
+	if err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	sessionID := c.Param("id")
+	entries, dropped, ok := logging.Runs.Entries(sessionID, level)
+	source := "memory"
+	if !ok {
+		entries, err = s.storedRunLogs(c.Request.Context(), sessionID, level)
+		if err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to load session logs"))
+			return
+		}
+		if len(entries) == 0 {
+			respondProblem(c, apperrors.NotFound("Logs of this session"))
+			return
+		}
+		source = "store"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": sessionID,
+		"level":      logging.LevelName(level),
+		"entries":    entries,
+		"count":      len(entries),
+		"dropped":    dropped, // oldest lines pushed out by the per-run limit
+		"source":     source,
+	})
+}
+
+// handleStreamSessionLogs streams a research session's log lines as server-sent `log`
+// events: the lines logged so far, then each new one as it is logged, until the client
+// disconnects
+func (s *Server) handleStreamSessionLogs(c *gin.Context) {
+	level, err := logging.ParseLevel(c.Query("level"))
+	if err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	sessionID := c.Param("id")
+	backlog, lines, cancel := logging.Runs.Follow(sessionID, level)
+	defer func() {
+		if missed := cancel(); missed > 0 {
+			slog.Warn("Log tail fell behind and missed lines", "session_id", sessionID, "lines", missed)
+		}
+	}()
+	if len(backlog) == 0 {
+		// A finished run may only be in the store; a run yet to start has no lines anywhere
+		// and is followed from its first one
+		if backlog, err = s.storedRunLogs(c.Request.Context(), sessionID, level); err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to load session logs"))
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	send := func(w io.Writer, e logging.Entry) {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return
+		}
+		c.SSEvent("log", string(data))
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+	ctx := c.Request.Context()
+	sentBacklog := false
+	c.Stream(func(w io.Writer) bool {
+		if !sentBacklog {
+			for _, e := range backlog {
+				send(w, e)
+			}
+			sentBacklog = true
+			c.SSEvent("ready", `{}`) // everything logged so far has been sent
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+			return true
+		}
+		select {
+		case e, open := <-lines:
+			if !open {
+				return false
+			}
+			send(w, e)
+			return true
+		case <-time.After(30 * time.Second):
+			c.SSEvent("ping", `{}`)
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
+
+// storedRunLogs reads a run's lines from the run log store, none when there is no store
+func (s *Server) storedRunLogs(ctx context.Context, runID string, level slog.Level) ([]logging.Entry, error) {
+	if s.runLogs == nil {
+		return nil, nil
+	}
+	rows, err := s.runLogs.ListRunLogs(ctx, runID, int(level), maxStoredLogLines)
+	if err != nil {
+		return nil, err
+	}
+	return logging.StoredEntries(rows), nil
+}
+
+// handleSessionLogsPage renders a live tail of a research session's log lines
+func (s *Server) handleSessionLogsPage(c *gin.Context) {
+	level, err := logging.ParseLevel(c.Query("level"))
+	if err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	sessionID := c.Param("id")
+	levelName := strings.ToLower(logging.LevelName(level))
+	var page strings.Builder
+	data := gin.H{
+		"SessionID":   sessionID,
+		"Level":       levelName,
+		"Levels":      []string{"error", "warn", "info", "debug", "trace"},
+		"StreamURL":   "/api/research/sessions/" + url.PathEscape(sessionID) + "/logs/stream?level=" + levelName,
+		"WorkbookURL": "/api/runs/" + url.PathEscape("sweep-"+sessionID) + "/workbook",
+		"Usage":       s.sessionUsage(c.Request.Context(), sessionID),
+		"Title":       s.branding.Title("Run log"),
+		"Style":       brandStyle(s.branding),
+	}
+	if err := runLogTemplate.Execute(&page, data); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to render run log page"))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page.String()))
+}
+
+// sessionUsage returns the LLM usage recorded on a session so far, nil when there is none
+func (s *Server) sessionUsage(ctx context.Context, sessionID string) *models.RunUsage {
+	if s.sessionManager == nil {
+		return nil
+	}
+	session, err := s.sessionManager.GetSession(ctx, sessionID)
+	if err != nil || session.Metadata["llm_usage"] == nil {
+		return nil
+	}
+	raw, err := json.Marshal(session.Metadata["llm_usage"])
+	if err != nil {
+		return nil
+	}
+	var usage models.RunUsage
+	if err := json.Unmarshal(raw, &usage); err != nil {
+		slog.WarnContext(ctx, "Unreadable LLM usage on session", "session_id", sessionID, "error", err)
+		return nil
+	}
+	return &usage
+}
+
+var runLogTemplate = template.Must(template.New("run_log").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+{{.Style}}
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 0; color: var(--brand-accent); font-size: 14px; }
+header { padding: 12px 20px; border-bottom: 3px solid var(--brand-primary); display: flex; gap: 16px; align-items: center; }
+header h1 { font-size: 16px; margin: 0; }
+.meta { color: #6b7280; }
+main { padding: 8px 20px 40px; }
+#lines { font-family: ui-monospace, Menlo, monospace; font-size: 12px; white-space: pre-wrap; }
+.line { padding: 1px 0; border-bottom: 1px solid #f9fafb; }
+.time, .attrs { color: #9ca3af; }
+.stage { color: var(--brand-primary); }
+.WARN { color: #b45309; }
+.ERROR { color: #991b1b; }
+.DEBUG, .TRACE { color: #6b7280; }
+#usage { padding: 8px 20px; border-bottom: 1px solid #f3f4f6; }
+#usage table { border-collapse: collapse; margin-top: 4px; }
+#usage th, #usage td { text-align: left; padding: 2px 12px 2px 0; }
+#usage th { color: #6b7280; font-weight: 500; }
+</style>
+</head>
+<body>
+<header>
+<h1>Run log · <span class="meta">{{.SessionID}}</span></h1>
+<form method="get"><select name="level" onchange="this.form.submit()">{{range .Levels}}<option value="{{.}}"{{if eq . $.Level}} selected{{end}}>{{.}}</option>{{end}}</select></form>
+<span class="meta" id="state">connecting…</span>
+<a href="{{.WorkbookURL}}">Download results (Excel)</a>
+</header>
+{{with .Usage}}<section id="usage">
+<strong>LLM usage</strong> <span class="meta">{{.Calls}} calls{{if .CachedCalls}} ({{.CachedCalls}} cached){{end}} · {{.PromptTokens}} prompt + {{.CompletionTokens}} completion = {{.TotalTokens}} tokens · est. ${{printf "%.4f" .EstimatedCostUSD}}</span>
+{{if .ByModel}}<table>
+<thead><tr><th>Model</th><th>Calls</th><th>Prompt</th><th>Completion</th><th>Est. cost</th></tr></thead>
+<tbody>{{range .ByModel}}<tr><td>{{.Provider}}/{{.Model}}</td><td>{{.Calls}}</td><td>{{.PromptTokens}}</td><td>{{.CompletionTokens}}</td><td>${{printf "%.4f" .EstimatedCostUSD}}</td></tr>{{end}}</tbody>
+</table>{{end}}
+</section>{{end}}
+<main><div id="lines"></div></main>
+<script>
+(function () {
+  var lines = document.getElementById("lines");
+  var state = document.getElementById("state");
+  var source = new EventSource({{.StreamURL}});
+  function text(cls, value) {
+    var span = document.createElement("span");
+    span.className = cls;
+    span.textContent = value;
+    return span;
+  }
+  source.addEventListener("log", function (ev) {
+    var e = JSON.parse(ev.data);
+    var attrs = Object.assign({}, e.attrs || {});
+    var stage = attrs.stage;
+    delete attrs.stage;
+    var follow = window.innerHeight + window.scrollY >= document.body.scrollHeight - 40;
+    var line = document.createElement("div");
+    line.className = "line " + e.level;
+    line.appendChild(text("time", new Date(e.time).toLocaleTimeString() + " "));
+    line.appendChild(text("level", e.level.padEnd(5) + " "));
+    if (stage) { line.appendChild(text("stage", "[" + stage + "] ")); }
+    line.appendChild(text("message", e.message));
+    var keys = Object.keys(attrs);
+    if (keys.length) {
+      line.appendChild(text("attrs", " " + keys.map(function (k) { return k + "=" + JSON.stringify(attrs[k]); }).join(" ")));
+    }
+    lines.appendChild(line);
+    if (follow) { window.scrollTo(0, document.body.scrollHeight); }
+  });
+  source.addEventListener("ready", function () { state.textContent = "live"; });
+  source.onerror = function () { state.textContent = "reconnecting…"; lines.textContent = ""; };
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gohypo/adapters/excel"
+	"gohypo/domain/core"
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetRunSummary returns the aggregates materialized when a sweep run completed
+func (s *Server) handleGetRunSummary(c *gin.Context) {
+	if s.runSummaries == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Run summaries not available"})
+		return
+	}
+	summary, err := s.runSummaries.GetRunSummary(c.Request.Context(), c.Param("id"))
+	s.respondRunSummary(c, summary, err)
+}
+
+// handleGetRunWorkbook downloads a sweep run's relationships, variable profiles, warnings
+// and manifest as an Excel workbook
+func (s *Server) handleGetRunWorkbook(c *gin.Context) {
+	if s.reader == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Ledger not available"})
+		return
+	}
+	runID := c.Param("id")
+	artifacts, err := s.reader.GetArtifactsByRun(c.Request.Context(), core.RunID(runID))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to read run artifacts"))
+		return
+	}
+	// A run lists an artifact once per store; keep the latest of each
+	latest := make(map[core.ID]int, len(artifacts))
+	unique := make([]core.Artifact, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		if i, ok := latest[artifact.ID]; ok {
+			unique[i] = artifact
+			continue
+		}
+		latest[artifact.ID] = len(unique)
+		unique = append(unique, artifact)
+	}
+	if len(unique) == 0 {
+		respondProblem(c, apperrors.NotFound("run "+runID))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := excel.WriteSweepWorkbook(&buf, unique); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to export run workbook"))
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", runID+".xlsx"))
+	c.Data(http.StatusOK, excel.WorkbookContentType, buf.Bytes())
+}
+
+// handleGetSessionSummary returns the summary of a research session's latest sweep
+func (s *Server) handleGetSessionSummary(c *gin.Context) {
+	if s.runSummaries == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Run summaries not available"})
+		return
+	}
+	summary, err := s.runSummaries.LatestSessionSummary(c.Request.Context(), c.Param("id"))
+	s.respondRunSummary(c, summary, err)
+}
+
+// handleListWorkspaceRunSummaries lists a workspace's most recent run summaries, without
+// their test type counts and top effects
+func (s *Server) handleListWorkspaceRunSummaries(c *gin.Context) {
+	if s.runSummaries == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Run summaries not available"})
+		return
+	}
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	summaries, err := s.runSummaries.ListWorkspaceSummaries(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if summaries == nil {
+		summaries = []*models.RunSummary{}
+	}
+	c.JSON(http.StatusOK, gin.H{"summaries": summaries})
+}
+
+func (s *Server) respondRunSummary(c *gin.Context, summary *models.RunSummary, err error) {
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if summary == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No summary recorded for this run"})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
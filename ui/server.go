@@ -7,22 +7,34 @@ import (
 	"fmt"
 	"html/template"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	pgresolver "gohypo/adapters/db/postgres"
 	"gohypo/adapters/postgres"
+	"gohypo/adapters/sqlite"
 	"gohypo/ai"
 	"gohypo/domain/core"
 	domainDataset "gohypo/domain/dataset"
+	"gohypo/internal/access"
 	"gohypo/internal/analysis"
 	"gohypo/internal/analysis/brief"
 	"gohypo/internal/api"
+	"gohypo/internal/buildinfo"
 	"gohypo/internal/dataset"
+	"gohypo/internal/metrics"
+	"gohypo/internal/monitoring"
+	"gohypo/internal/notify"
 	"gohypo/internal/research"
+	"gohypo/internal/resilience"
+	"gohypo/internal/retention"
 	"gohypo/internal/testkit"
+	"gohypo/internal/webhook"
 	"gohypo/models"
 	"gohypo/ports"
 	"gohypo/ui/services"
@@ -58,6 +70,114 @@ type Server struct {
 	// Evidence components
 	evidenceHandler *api.EvidenceHandler
 
+	// Push-down warehouse resolver and its query preview (SQL + EXPLAIN cost) for review
+	queryPreviewer    ports.QueryPreviewPort
+	warehouseResolver ports.MatrixResolverPort
+
+	// Column-level access policy (optional)
+	columnEnforcer *access.Enforcer
+
+	// Dataset file storage (exposes key rotation for encrypted-at-rest datasets)
+	fileStorage *dataset.LocalFileStorage
+
+	// Schema-per-workspace provisioning (nil in shared tenancy mode)
+	schemaProvisioner *postgres.WorkspaceSchemaProvisioner
+
+	// Curated few-shot exemplars for generation prompts
+	exemplarStore ports.ExemplarStore
+
+	// Per-workspace LLM defaults and admin locks
+	llmSettingsStore ports.WorkspaceLLMSettingsStore
+
+	// Re-estimates validated relationships on each new dataset version
+	monitor *monitoring.Monitor
+
+	// Actions taken on hypotheses and their outcomes; resolved outcomes calibrate E-values
+	decisionLog       ports.DecisionLog
+	outcomeCalibrator *analysis.EValueCalibrator
+
+	// Public read-only links to validation pages
+	shareLinks ports.ShareLinkStore
+
+	// Workspace webhooks and the dispatcher sending their deliveries
+	webhooks          ports.WebhookStore
+	webhookDispatcher *webhook.Dispatcher
+
+	// Workspaces' Slack and Teams channels, and the service posting to them
+	notificationChannels ports.NotificationChannelStore
+	notifier             *notify.Service
+
+	// Triage marks on hypothesis and relationship lists, and users' saved views of them
+	triage ports.TriageStore
+
+	// Workspace data catalog: datasets, fields, relations and the hypotheses using them
+	catalog ports.CatalogReader
+
+	// Warehouse tables, remote CSVs and Google Sheets pulled in as datasets, on request and
+	// on a schedule
+	sqlSources      ports.SQLDataSourceStore
+	urlSources      ports.URLDataSourceStore
+	sourceRefresher *dataset.SourceRefresher
+
+	// Research sessions, for the embeddable run status widget
+	sessionManager *research.SessionManager
+
+	// Re-validates hypotheses users queue from the list, within each workspace's capacity
+	validationQueue *research.ValidationQueue
+
+	// Sizes the validation pool from the queue depth; the policy is set before the queue exists
+	autoscalePolicy *research.AutoscalePolicy
+	scaleHooks      []research.ScaleHook
+	autoscaler      *research.Autoscaler
+
+	// Archives inactive sessions' artifacts and reports storage by tier (nil when disabled)
+	archiver *retention.Archiver
+
+	// Compacts ledger artifacts expired by the retention policy (nil when disabled)
+	compactor *retention.Compactor
+
+	// Aggregates materialized after each sweep run
+	runSummaries ports.RunSummaryStore
+
+	// Run log lines older than the in-memory window (nil keeps logs in memory only)
+	runLogs ports.RunLogStore
+
+	// GraphQL schema over the stores above, built on the first query once setters have run
+	graphqlOnce    sync.Once
+	graphqlHandler http.Handler
+	graphqlErr     error
+
+	// Durable queue research sessions run from; the runner exists once research routes are added
+	jobQueue   ports.JobQueue
+	jobLeases  ports.LeaseStore
+	jobOptions research.JobRunnerOptions
+	jobRunner  *research.JobRunner
+
+	// Keys the admin API requires; none leaves it open
+	adminAPIKeys []string
+
+	// Variable contracts and research sessions, carried by workspace export and import
+	contractStore     ports.ContractStore
+	sessionRepository ports.SessionRepository
+
+	// Re-runs single hypotheses against the latest data, keeping their verdict history
+	retester *research.Retester
+
+	// Re-analyzes single hypotheses with the most conservative choices
+	skeptic skepticAnalyzer
+
+	// Finds hypotheses made stale by referee or sense upgrades for bulk re-validation
+	revalidation revalidationPlanner
+
+	// Tests variable pairs picked by hand in the explorer
+	pairExplorer pairExplorer
+
+	// Calibrates workspace noise floors on permuted copies of their data
+	noiseCalibrator noiseCalibrator
+
+	// Deployment name, logo, colors and footer for pages and reports
+	branding models.Branding
+
 	datasetCache        map[string]interface{}
 	cacheMutex          sync.RWMutex
 	cacheLoaded         bool
@@ -75,6 +195,7 @@ func NewServer(embeddedFiles embed.FS) *Server {
 		datasetCache:     make(map[string]interface{}),
 		cacheLoaded:      false,
 		cacheLastUpdated: time.Now(),
+		branding:         models.DefaultBranding(),
 	}
 }
 
@@ -93,22 +214,13 @@ func (s *Server) getDefaultUserID(ctx context.Context) (core.ID, error) {
 	return core.ID(user.ID.String()), nil
 }
 
-// validateWorkspaceOwnership checks if the workspace exists and belongs to the user
-func (s *Server) validateWorkspaceOwnership(ctx context.Context, workspaceID core.ID, userID core.ID) error {
-	workspace, err := s.workspaceRepository.GetByID(ctx, workspaceID)
-	if err != nil {
-		return fmt.Errorf("workspace not found")
-	}
-
-	if workspace.UserID != userID {
-		return fmt.Errorf("access denied")
-	}
-
-	return nil
-}
-
 func (s *Server) Initialize(kit *testkit.TestKit, reader ports.LedgerReaderPort, embeddedFiles embed.FS, greenfieldService interface{}, analysisEngine *brief.StatisticalEngine, aiConfig *models.AIConfig, db *sqlx.DB, sseHub *api.SSEHub, userRepo ports.UserRepository, hypothesisRepo ports.HypothesisRepository) error {
 	s.sseHub = sseHub
+	if sseHub != nil {
+		metrics.GaugeFunc("gohypo_sse_clients", "Clients connected to the research event stream.", func() float64 {
+			return float64(sseHub.ClientCount())
+		})
+	}
 	s.testkit = kit
 	s.reader = reader
 	s.greenfieldService = greenfieldService
@@ -130,8 +242,24 @@ func (s *Server) Initialize(kit *testkit.TestKit, reader ports.LedgerReaderPort,
 
 	// Initialize dataset and workspace components
 	if db != nil {
-		s.datasetRepository = postgres.NewDatasetRepository(db)
-		s.workspaceRepository = postgres.NewWorkspaceRepository(db)
+		if sqlite.IsDB(db) {
+			s.datasetRepository = sqlite.NewDatasetRepository(db)
+			s.workspaceRepository = sqlite.NewWorkspaceRepository(db)
+		} else if os.Getenv("TENANCY_MODE") == "schema" {
+			// Schema-per-workspace isolation
+			s.schemaProvisioner = postgres.NewWorkspaceSchemaProvisioner(db)
+			if err := s.schemaProvisioner.EnsureIndex(context.Background()); err != nil {
+				return err
+			}
+			if _, err := s.schemaProvisioner.ProvisionAll(context.Background()); err != nil {
+				return err
+			}
+			s.datasetRepository = postgres.NewSchemaRoutedDatasetRepository(db)
+			s.workspaceRepository = postgres.NewSchemaIsolatedWorkspaceRepository(db)
+		} else {
+			s.datasetRepository = postgres.NewDatasetRepository(db)
+			s.workspaceRepository = postgres.NewWorkspaceRepository(db)
+		}
 
 		// Initialize file storage with cloud-ready configuration
 		storageConfig := dataset.DefaultStorageConfig()
@@ -144,12 +272,52 @@ func (s *Server) Initialize(kit *testkit.TestKit, reader ports.LedgerReaderPort,
 				storageConfig.MaxFileSize = size * 1024 * 1024
 			}
 		}
+		keyProvider, err := dataset.KeyProviderFromEnv()
+		if err != nil {
+			return fmt.Errorf("failed to load dataset encryption keys: %w", err)
+		}
+		storageConfig.KeyProvider = keyProvider
 		fileStorage := dataset.NewLocalFileStorage(storageConfig)
+		s.fileStorage = fileStorage
+
+		// Push-down resolver: previews compile the same SQL warehouse resolutions run, and
+		// approvals recorded from a preview gate the resolution
+		matrixResolver := pgresolver.NewMatrixResolverAdapter(db.DB)
+		if requireApproval, err := strconv.ParseBool(os.Getenv("RESOLUTION_REQUIRE_APPROVAL")); err == nil {
+			matrixResolver.RequireApproval(requireApproval)
+		}
+		s.queryPreviewer = matrixResolver
+		s.warehouseResolver = matrixResolver
+		s.contractStore = pgresolver.NewRegistryAdapter(db.DB)
+		s.sessionRepository = postgres.NewSessionRepository(db)
+
+		s.exemplarStore = postgres.NewExemplarRepository(db)
+		s.llmSettingsStore = postgres.NewWorkspaceLLMSettingsRepository(db)
+		s.decisionLog = postgres.NewDecisionRepository(db)
+		s.shareLinks = postgres.NewShareLinkRepository(db)
+		s.webhooks = postgres.NewWebhookRepository(db)
+		s.notificationChannels = postgres.NewNotificationChannelRepository(db)
+		s.triage = postgres.NewTriageRepository(db)
+		s.catalog = postgres.NewCatalogRepository(db)
+		s.sqlSources = postgres.NewSQLDataSourceRepository(db)
+		s.urlSources = postgres.NewURLDataSourceRepository(db)
 
 		// Initialize dataset processor with forensic scout and SSE hub
 		if s.forensicScout != nil && sseHub != nil && s.workspaceRepository != nil {
 			s.datasetProcessor = dataset.NewProcessorWithConfig(s.forensicScout, s.datasetRepository, s.workspaceRepository, fileStorage, sseHub, db, storageConfig)
+			s.datasetProcessor.GetRelationshipEngine().SetColumnEmbedder(ai.NewColumnEmbedder(aiConfig))
 			log.Printf("[Initialize] Dataset processor initialized with Forensic Scout, SSE, and merge capabilities (max file size: %d MB)", storageConfig.MaxFileSize/(1024*1024))
+			if s.hypothesisRepo != nil {
+				s.monitor = s.newRelationshipMonitor(db)
+			}
+			s.datasetProcessor.OnReady = func(datasetID core.ID) {
+				if s.monitor != nil {
+					go s.checkTrackedRelationships(datasetID)
+				}
+				go s.publishDatasetReady(datasetID)
+			}
+			s.sourceRefresher = dataset.NewSourceRefresher(s.sqlSources, s.urlSources, s.datasetProcessor)
+			go s.sourceRefresher.Run(context.Background(), time.Minute)
 		} else {
 			log.Printf("[Initialize] Required dependencies not available - dataset processing will be limited")
 		}
@@ -175,15 +343,6 @@ func (s *Server) Initialize(kit *testkit.TestKit, reader ports.LedgerReaderPort,
 }
 
 func (s *Server) setupTemplates() error {
-	// #region agent log
-	log.Printf(`{"sessionId":"debug-session","runId":"initial","hypothesisId":"H1","location":"ui/server.go:87","message":"Starting template setup","data":{},"timestamp":%d}`, time.Now().UnixMilli())
-	// #endregion
-
-	// Check what files are being parsed
-	// #region agent log
-	log.Printf(`{"sessionId":"debug-session","runId":"initial","hypothesisId":"H1","location":"ui/server.go:89","message":"Template patterns to parse","data":{"patterns":["ui/templates/*.html","ui/templates/fragments/**/*.html"]},"timestamp":%d}`, time.Now().UnixMilli())
-	// #endregion
-
 	log.Printf("[setupTemplates] Parsing embedded HTML templates...")
 
 	// Define custom template functions
@@ -393,38 +552,52 @@ func (s *Server) setupTemplates() error {
 		"safe": func(s string) template.HTML {
 			return template.HTML(s)
 		},
+
+		// Deployment branding: {{brand.Name}}, {{brand.LogoURL}}, {{brandStyle}} in <style>
+		"brand": func() models.Branding {
+			return s.branding
+		},
+		"brandStyle": func() template.CSS {
+			return brandStyle(s.branding)
+		},
+
+		// Warning codes as badges expanding to their description and remediation:
+		// {{warningTooltip "LOW_N"}}, with {{warningTooltipStyle}} once in <style>
+		"warningTooltip": warningTooltip,
+		"warningTooltipStyle": func() template.CSS {
+			return template.CSS(warningTooltipStyle)
+		},
+
+		// Where a number came from, linking to its artifact's JSON:
+		// {{provenanceChip .Provenance}}, with {{provenanceChipStyle}} once in <style>
+		"provenanceChip": provenanceChip,
+		"provenanceChipStyle": func() template.CSS {
+			return template.CSS(provenanceChipStyle)
+		},
 	}
 
 	// Create a new template with custom functions
 	tmpl := template.New("").Funcs(funcMap)
 
-	// #region agent log
-	log.Printf(`{"sessionId":"debug-session","runId":"initial","hypothesisId":"H1","location":"ui/server.go:198","message":"About to parse templates","data":{"templateCount":%d},"timestamp":%d}`, len(funcMap), time.Now().UnixMilli())
-	// #endregion
-
 	// Parse all templates from embedded files (recursive for fragments)
-	tmpl, err := tmpl.ParseFS(s.embeddedFiles,
-		"ui/templates/*.html",
-		"ui/templates/fragments/**/*.html")
+	patterns := []string{"ui/templates/*.html", "ui/templates/fragments/**/*.html"}
+	slog.Debug("Parsing templates", "patterns", patterns, "funcs", len(funcMap))
+	tmpl, err := tmpl.ParseFS(s.embeddedFiles, patterns...)
 	if err != nil {
-		// #region agent log
-		log.Printf(`{"sessionId":"debug-session","runId":"initial","hypothesisId":"H1","location":"ui/server.go:201","message":"Template parsing failed","data":{"error":"%s"},"timestamp":%d}`, err.Error(), time.Now().UnixMilli())
-		// #endregion
 		log.Printf("[setupTemplates] ❌ Failed to parse templates: %v", err)
 		return fmt.Errorf("failed to parse templates: %w", err)
 	}
 
 	s.templates = tmpl
 
-	// #region agent log
-	log.Printf(`{"sessionId":"debug-session","runId":"initial","hypothesisId":"H1","location":"ui/server.go:207","message":"Templates parsed successfully","data":{},"timestamp":%d}`, time.Now().UnixMilli())
-	// #endregion
-
 	log.Printf("[setupTemplates] ✅ Templates parsed successfully")
 	return nil
 }
 
 func (s *Server) setupRoutes() {
+	// Prometheus scrapes the server, worker pool and sweep metrics here
+	s.router.GET("/metrics", gin.WrapH(metrics.Default.Handler()))
+
 	s.router.GET("/", s.handleIndex)
 	s.router.GET("/mission-control", s.handleMissionControl)
 	s.router.GET("/api/fields/list", s.handleFieldsList)
@@ -434,6 +607,7 @@ func (s *Server) setupRoutes() {
 
 	// File upload endpoint
 	s.router.POST("/api/dataset/upload", s.handleFileUpload)
+	s.router.POST("/api/dataset/import-url", s.handleImportURL)
 
 	// Workspace API endpoints
 	s.router.GET("/api/workspaces", s.handleGetWorkspaces)
@@ -443,27 +617,229 @@ func (s *Server) setupRoutes() {
 	s.router.DELETE("/api/workspaces/:id", s.handleDeleteWorkspace)
 	s.router.GET("/api/workspaces/:id/datasets", s.handleGetWorkspaceDatasets)
 
+	// Workspace export and import for promotion between deployments, and bulk dataset deletion
+	s.router.GET("/api/workspaces/:id/export", s.handleExportWorkspace)
+	s.router.POST("/api/workspaces/import", s.handleImportWorkspace)
+	s.router.POST("/api/workspaces/:id/datasets/bulk-delete", s.handleBulkDeleteDatasets)
+
 	// Dataset API endpoints
 	s.router.GET("/api/datasets/list", s.handleDatasetsList)
 	s.router.GET("/api/datasets/:id", s.handleGetDataset)
 	s.router.GET("/api/datasets/:id/fields", s.handleDatasetFields)
+	s.router.GET("/api/datasets/:id/columns", s.handleGetDatasetColumns)
 	s.router.GET("/api/datasets/:id/preview", s.handleDatasetPreview)
+	s.router.GET("/api/datasets/:id/pii", s.handleGetDatasetPII)
+	s.router.PUT("/api/datasets/:id/pii/:field", s.handleOverrideDatasetPII)
+	s.router.GET("/api/datasets/:id/dictionary", s.handleGetDataDictionary)
+	s.router.GET("/api/datasets/:id/lineage", s.handleGetDatasetLineage)
+	s.router.PUT("/api/datasets/:id/lineage", s.handleSetDatasetLineage)
+	s.router.GET("/datasets/:id/lineage", s.handleDatasetLineagePage)
+	s.router.POST("/api/datasets/:id/entity-resolution", s.handleResolveEntities)
 	s.router.GET("/api/fields/:name/details", s.handleFieldDetails)
 
+	// Warning code catalog behind the warning tooltips and report caveats
+	s.router.GET("/api/warnings", s.handleGetWarningCatalog)
+
 	// Dataset relationships and discovery
 	s.router.GET("/api/workspaces/:id/relations", s.handleGetWorkspaceRelations)
 	s.router.GET("/api/workspaces/:id/relationships", s.handleGetWorkspaceRelationships)
 	s.router.GET("/api/workspaces/:id/hypotheses", s.handleGetWorkspaceHypotheses)
+	s.router.GET("/api/workspaces/:id/causal-graph", s.handleGetWorkspaceCausalGraph)
 	s.router.POST("/api/workspaces/:id/discover", s.handleDiscoverRelationships)
 	s.router.POST("/api/workspaces/:id/auto-merge", s.handleAutoMergeSuggestions)
 
 	// Manifold visualization endpoints
 	s.router.GET("/api/hypotheses/:hypothesisId/manifold", s.handleGetHypothesisManifold)
 	s.router.GET("/api/hypotheses/:hypothesisId/evidence", s.handleGetHypothesisEvidence)
+	s.router.POST("/api/hypotheses/:hypothesisId/retest", s.handleRetestHypothesis)
+	s.router.GET("/api/hypotheses/:hypothesisId/history", s.handleGetVerdictHistory)
+	s.router.GET("/api/hypotheses/:hypothesisId/skeptic", s.handleGetSkepticRun)
+	s.router.POST("/api/hypotheses/:hypothesisId/skeptic", s.handleRunSkepticMode)
+	s.router.GET("/hypotheses/:hypothesisId/skeptic", s.handleSkepticPage)
+	s.router.GET("/api/hypotheses/:hypothesisId/lineage", s.handleGetHypothesisLineage)
+	s.router.GET("/hypotheses/:hypothesisId/lineage", s.handleHypothesisLineagePage)
+	s.router.GET("/hypotheses/:hypothesisId", s.handleValidationPage)
 
 	// Dataset merging
 	s.router.POST("/api/datasets/merge", s.handleMergeDatasets)
 	s.router.GET("/api/datasets/merge/:id/status", s.handleMergeStatus)
+
+	// Column access policy audit
+	s.router.GET("/api/admin/access/decisions", s.handleAccessDecisions)
+
+	// Push-down SQL review
+	s.router.GET("/resolution", s.handleResolutionPage)
+	s.router.POST("/api/resolution/preview", s.handleResolutionPreview)
+	s.router.POST("/api/admin/resolution/approve", s.handleApproveResolutionQuery)
+	s.router.POST("/api/resolution/resolve", s.handleResolveMatrix)
+
+	// Encrypted-at-rest key rotation
+	s.router.POST("/api/admin/storage/rotate-keys", s.handleRotateStorageKeys)
+
+	// Artifact retention tiers
+	s.router.GET("/api/workspaces/:id/storage", s.handleGetWorkspaceStorage)
+	s.router.POST("/api/admin/storage/archive", s.handleArchiveArtifacts)
+	s.router.GET("/api/admin/ledger/retention", s.handleGetLedgerRetention)
+	s.router.POST("/api/admin/ledger/compact", s.handleCompactLedger)
+
+	// Artifacts as the UI reads them, with a raw fallback for newer schemas
+	s.router.GET("/api/artifacts/:id", s.handleGetArtifact)
+
+	// Materialized sweep run summaries
+	s.router.GET("/api/runs/:id/summary", s.handleGetRunSummary)
+	s.router.GET("/api/runs/:id/workbook", s.handleGetRunWorkbook)
+	s.router.GET("/api/runs/:id/lineage", s.handleGetRunLineage)
+	s.router.GET("/runs/:id/lineage", s.handleRunLineagePage)
+	s.router.GET("/api/research/sessions/:id/summary", s.handleGetSessionSummary)
+	s.router.GET("/api/workspaces/:id/run-summaries", s.handleListWorkspaceRunSummaries)
+
+	// Workspaces, datasets, runs, relationships and hypotheses as one GraphQL schema
+	s.router.POST("/api/graphql", s.handleGraphQL)
+
+	// Structured log lines of a research session's run, kept in memory per run and stored
+	// when a run log store is set; the stream and page tail them live
+	s.router.GET("/api/research/sessions/:id/logs", s.handleGetSessionLogs)
+	s.router.GET("/api/research/sessions/:id/logs/stream", s.handleStreamSessionLogs)
+	s.router.GET("/research/sessions/:id/logs", s.handleSessionLogsPage)
+
+	// Workspace administration for operators and scripts; requires an API key when keys are configured
+	s.router.GET("/api/admin/users", s.handleAdminListUsers)
+	s.router.GET("/api/admin/workspaces", s.handleAdminListWorkspaces)
+	s.router.POST("/api/admin/workspaces", s.handleAdminCreateWorkspace)
+	s.router.GET("/api/admin/workspaces/:id/datasets", s.handleAdminListDatasets)
+	s.router.PUT("/api/admin/workspaces/:id/owner", s.handleAdminAssignWorkspace)
+	s.router.GET("/api/admin/workspaces/:id/quota", s.handleAdminGetQuota)
+	s.router.PUT("/api/admin/workspaces/:id/quota", s.handleAdminPutQuota)
+
+	// Schema-per-workspace provisioning
+	s.router.POST("/api/admin/workspaces/:id/schema", s.handleProvisionWorkspaceSchema)
+	s.router.DELETE("/api/admin/workspaces/:id/schema", s.handleDeprovisionWorkspaceSchema)
+
+	// Few-shot exemplars: anyone proposes, admins approve
+	s.router.GET("/api/exemplars", s.handleListExemplars)
+	s.router.GET("/api/exemplars/packs", s.handleListDomainPacks)
+	s.router.POST("/api/exemplars", s.handleProposeExemplar)
+	s.router.POST("/api/admin/exemplars/:id/approve", s.handleApproveExemplar)
+	s.router.POST("/api/admin/exemplars/:id/retire", s.handleRetireExemplar)
+
+	// Per-workspace LLM settings; only admins lock them to an approved model
+	s.router.GET("/api/workspaces/:id/llm-settings", s.handleGetWorkspaceLLMSettings)
+	s.router.PUT("/api/workspaces/:id/llm-settings", s.handlePutWorkspaceLLMSettings)
+	s.router.POST("/api/admin/workspaces/:id/llm-settings/lock", s.handleLockWorkspaceLLMSettings)
+	s.router.DELETE("/api/admin/workspaces/:id/llm-settings/lock", s.handleUnlockWorkspaceLLMSettings)
+
+	// Bulk re-validation of hypotheses whose referees or senses were upgraded since
+	s.router.GET("/api/admin/workspaces/:id/revalidation", s.handleGetRevalidationPlan)
+	s.router.POST("/api/admin/workspaces/:id/revalidation", s.handleQueueRevalidation)
+
+	// On-demand tests of any two variables of a workspace's dataset, kept as audit artifacts
+	s.router.POST("/api/workspaces/:id/explore", s.handleExplorePair)
+	s.router.GET("/workspaces/:id/explore", s.handleExplorerPage)
+
+	// Per-workspace significance and effect-size cutoffs, applied to sweeps run afterwards
+	s.router.GET("/api/workspaces/:id/analysis-policy", s.handleGetAnalysisPolicy)
+	s.router.PUT("/api/workspaces/:id/analysis-policy", s.handlePutAnalysisPolicy)
+
+	// Burn-in calibration of the workspace's noise floor, against which findings are placed
+	s.router.GET("/api/workspaces/:id/noise-floor", s.handleGetNoiseFloor)
+	s.router.POST("/api/workspaces/:id/noise-floor", s.handleCalibrateNoiseFloor)
+	s.router.DELETE("/api/workspaces/:id/noise-floor", s.handleClearNoiseFloor)
+
+	// Workspace webhooks for run lifecycle events, with their delivery log
+	s.router.GET("/api/workspaces/:id/webhooks", s.handleListWebhooks)
+	s.router.POST("/api/workspaces/:id/webhooks", s.handleCreateWebhook)
+	s.router.PUT("/api/workspaces/:id/webhooks/:webhookId/active", s.handleSetWebhookActive)
+	s.router.DELETE("/api/workspaces/:id/webhooks/:webhookId", s.handleDeleteWebhook)
+	s.router.GET("/api/workspaces/:id/webhooks/deliveries", s.handleListWebhookDeliveries)
+	s.router.POST("/api/workspaces/:id/webhooks/deliveries/:deliveryId/redeliver", s.handleRedeliverWebhook)
+	s.router.GET("/workspaces/:id/webhooks", s.handleWebhookDeliveriesPage)
+
+	// Slack and Teams channels announcing hypotheses that pass a workspace's chosen rigor
+	s.router.GET("/api/workspaces/:id/notification-channels", s.handleListNotificationChannels)
+	s.router.POST("/api/workspaces/:id/notification-channels", s.handleCreateNotificationChannel)
+	s.router.DELETE("/api/workspaces/:id/notification-channels/:channelId", s.handleDeleteNotificationChannel)
+	s.router.POST("/api/workspaces/:id/notification-channels/:channelId/test", s.handleTestNotificationChannel)
+
+	// Validated relationships tracked across dataset versions
+	s.router.GET("/api/workspaces/:id/monitoring", s.handleGetWorkspaceMonitoring)
+	s.router.POST("/api/workspaces/:id/monitoring/check", s.handleCheckWorkspaceMonitoring)
+	s.router.GET("/api/workspaces/:id/decisions", s.handleListDecisions)
+	s.router.POST("/api/workspaces/:id/decisions", s.handleRecordDecision)
+	s.router.POST("/api/workspaces/:id/decisions/:decisionId/outcome", s.handleRecordDecisionOutcome)
+	s.router.PUT("/api/workspaces/:id/share-links/settings", s.handleUpdateShareSettings)
+	s.router.GET("/api/workspaces/:id/share-links", s.handleListShareLinks)
+	s.router.POST("/api/workspaces/:id/share-links", s.handleCreateShareLink)
+	s.router.DELETE("/api/workspaces/:id/share-links/:linkId", s.handleRevokeShareLink)
+
+	// Public read-only validation pages behind share links; no login
+	s.router.GET("/share/:token", s.handleSharedValidation)
+
+	// Embeddable run status widget; the token grants nothing beyond the status
+	s.router.POST("/api/workspaces/:id/status-widget", s.handleEnableStatusWidget)
+	s.router.DELETE("/api/workspaces/:id/status-widget", s.handleDisableStatusWidget)
+	s.router.GET("/widget/:token", s.handleStatusWidget)
+	s.router.GET("/widget/:token/status", s.handleWidgetStatus)
+
+	// Keyboard-driven triage of hypotheses and relationships, with saved views
+	s.router.GET("/workspaces/:id/triage", s.handleTriagePage)
+	s.router.GET("/api/workspaces/:id/triage/:list", s.handleGetTriageList)
+	s.router.POST("/api/workspaces/:id/triage/:list", s.handleMarkTriageItems)
+	s.router.GET("/api/views", s.handleListSavedViews)
+	s.router.POST("/api/views", s.handleCreateSavedView)
+	s.router.DELETE("/api/views/:viewId", s.handleDeleteSavedView)
+
+	// Workspace data catalog and the glossary behind its data dictionaries
+	s.router.GET("/workspaces/:id/catalog", s.handleCatalogPage)
+	s.router.GET("/api/workspaces/:id/catalog", s.handleGetCatalog)
+	s.router.GET("/api/workspaces/:id/glossary", s.handleGetGlossary)
+	s.router.PUT("/api/workspaces/:id/glossary", s.handlePutGlossary)
+
+	// SQL data sources pulled into the workspace as datasets. Sources run arbitrary queries
+	// with server-side credentials, so only admins define them.
+	s.router.GET("/api/workspaces/:id/data-sources/sql", s.handleListSQLSources)
+	s.router.POST("/api/admin/workspaces/:id/data-sources/sql", s.handleCreateSQLSource)
+	s.router.POST("/api/workspaces/:id/data-sources/sql/:sourceId/refresh", s.handleRefreshSQLSource)
+	s.router.DELETE("/api/workspaces/:id/data-sources/sql/:sourceId", s.handleDeleteSQLSource)
+
+	// Remote CSV files and Google Sheets fetched into the workspace, re-fetched on a schedule
+	s.router.GET("/api/workspaces/:id/data-sources/url", s.handleListURLSources)
+	s.router.POST("/api/workspaces/:id/data-sources/url", s.handleCreateURLSource)
+	s.router.POST("/api/workspaces/:id/data-sources/url/:sourceId/refresh", s.handleRefreshURLSource)
+	s.router.DELETE("/api/workspaces/:id/data-sources/url/:sourceId", s.handleDeleteURLSource)
+
+	// Bulk re-validation queue with per-item verdicts over SSE
+	s.router.POST("/api/workspaces/:id/validation-queue", s.handleEnqueueValidations)
+	s.router.GET("/api/workspaces/:id/validation-queue", s.handleGetValidationQueue)
+	s.router.GET("/api/workspaces/:id/validation-queue/events", s.handleValidationQueueEvents)
+	s.router.DELETE("/api/workspaces/:id/validation-queue/:batchId", s.handleCancelValidationBatch)
+
+	// Validation pool size and demand, for operators and Kubernetes autoscalers
+	s.router.GET("/api/autoscaling", s.handleAutoscalingStatus)
+	s.router.GET("/metrics/autoscaling", s.handleAutoscalingMetrics)
+
+	// Build and method versions
+	s.router.GET("/api/version", s.handleVersion)
+
+	// Deployment branding, for pages styled client-side
+	s.router.GET("/api/branding", s.handleBranding)
+
+	// Retry and circuit-breaker counters per upstream
+	s.router.GET("/api/resilience", s.handleResilienceStats)
+}
+
+// handleVersion reports the build and the method versions stamped into sweep manifests
+func (s *Server) handleVersion(c *gin.Context) {
+	c.JSON(200, buildinfo.Get())
+}
+
+// handleBranding returns the deployment's branding
+func (s *Server) handleBranding(c *gin.Context) {
+	c.JSON(200, s.branding)
+}
+
+// handleResilienceStats reports breaker state, retries and trips for every guarded upstream
+func (s *Server) handleResilienceStats(c *gin.Context) {
+	c.JSON(200, gin.H{"upstreams": resilience.Snapshot()})
 }
 
 // Manifold visualization handler
@@ -578,11 +954,70 @@ func (s *Server) ensureDefaultWorkspace(ctx context.Context, userID core.ID) (*d
 	if err := s.workspaceRepository.Create(ctx, defaultWorkspace); err != nil {
 		return nil, fmt.Errorf("failed to create default workspace: %w", err)
 	}
+	if s.schemaProvisioner != nil {
+		if err := s.schemaProvisioner.Provision(ctx, defaultWorkspace.ID); err != nil {
+			return nil, fmt.Errorf("failed to provision default workspace schema: %w", err)
+		}
+	}
 
 	log.Printf("[ensureDefaultWorkspace] Created default workspace %s for user: %s", defaultWorkspace.ID, userID)
 	return defaultWorkspace, nil
 }
 
+// SetBranding applies a deployment's branding; unset fields keep the defaults
+func (s *Server) SetBranding(branding models.Branding) {
+	s.branding = branding.WithDefaults()
+}
+
+// SetAutoscaling sizes the validation pool by policy once research routes are added,
+// telling hooks about every change
+func (s *Server) SetAutoscaling(policy research.AutoscalePolicy, hooks ...research.ScaleHook) {
+	s.autoscalePolicy = &policy
+	s.scaleHooks = hooks
+}
+
+// SetArchiver enables the storage usage report and on-demand archiving passes
+func (s *Server) SetArchiver(archiver *retention.Archiver) {
+	s.archiver = archiver
+}
+
+// SetCompactor enables the ledger retention report and on-demand compaction passes
+func (s *Server) SetCompactor(compactor *retention.Compactor) {
+	s.compactor = compactor
+}
+
+// SetAdminAPIKeys requires one of keys on /api/admin routes
+func (s *Server) SetAdminAPIKeys(keys []string) {
+	s.adminAPIKeys = keys
+}
+
+// SetRunSummaryStore serves the summaries materialized after each sweep run
+func (s *Server) SetRunSummaryStore(store ports.RunSummaryStore) {
+	s.runSummaries = store
+}
+
+// SetRunLogStore serves run log lines from store once they have left memory
+func (s *Server) SetRunLogStore(store ports.RunLogStore) {
+	s.runLogs = store
+}
+
+// SetJobQueue runs research sessions from queue instead of in request goroutines, so they
+// survive restarts and failed ones are retried. Leases keep a session to one worker across
+// every instance sharing the queue; options.Workers may be 0 for an enqueue-only instance.
+func (s *Server) SetJobQueue(queue ports.JobQueue, leases ports.LeaseStore, options research.JobRunnerOptions) {
+	s.jobQueue = queue
+	s.jobLeases = leases
+	s.jobOptions = options
+}
+
+// SetColumnEnforcer enables column-level access policies for discovery
+func (s *Server) SetColumnEnforcer(enforcer *access.Enforcer) {
+	s.columnEnforcer = enforcer
+	if s.datasetProcessor != nil {
+		s.datasetProcessor.GetRelationshipEngine().SetColumnEnforcer(enforcer)
+	}
+}
+
 func (s *Server) Start(addr string) error {
 	log.Printf("Starting GoHypo UI on http://%s", addr)
 	log.Printf("[Start] Dataset loader should be running in background - page will show loading state until dataset is ready")
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+
+	"gohypo/domain/core"
+)
+
+// ArtifactReaders declares the payload schema versions the UI's views read, per kind.
+// Bump a kind's Max once its views read the fields a newer engine writes.
+var ArtifactReaders = map[core.ArtifactKind]core.SchemaSupport{
+	core.ArtifactRelationship:    core.SupportsVersions(1, 1),
+	core.ArtifactSweepManifest:   core.SupportsVersions(1, 1),
+	core.ArtifactFDRFamily:       core.SupportsVersions(1, 1),
+	core.ArtifactVariableHealth:  core.SupportsVersions(1, 1),
+	core.ArtifactVariableProfile: core.SupportsVersions(1, 1),
+	core.ArtifactRun:             core.SupportsVersions(1, 1),
+}
+
+// NegotiateArtifact offers an artifact to the UI's reader of its kind. Kinds without a
+// declared reader are read at version 1.
+func NegotiateArtifact(artifact core.Artifact) core.SchemaNegotiation {
+	support, ok := ArtifactReaders[artifact.Kind]
+	if !ok {
+		support = core.SupportsVersions(1, 1)
+	}
+	return support.Negotiate(artifact)
+}
+
+// ReadableArtifacts splits artifacts into those the views can read and views of the rest,
+// which carry a notice and the raw JSON instead of a payload
+func ReadableArtifacts(artifacts []core.Artifact) ([]core.Artifact, []map[string]interface{}) {
+	readable := make([]core.Artifact, 0, len(artifacts))
+	var unreadable []map[string]interface{}
+	for _, artifact := range artifacts {
+		if NegotiateArtifact(artifact).Supported {
+			readable = append(readable, artifact)
+		} else {
+			unreadable = append(unreadable, ArtifactView(artifact))
+		}
+	}
+	return readable, unreadable
+}
+
+// ArtifactView is the map the UI renders an artifact from. An artifact whose payload schema
+// the UI does not read has no payload; it carries the notice and its raw JSON instead, so
+// the panel shows what was produced rather than rendering empty.
+func ArtifactView(artifact core.Artifact) map[string]interface{} {
+	negotiation := NegotiateArtifact(artifact)
+	view := map[string]interface{}{
+		"kind":           string(artifact.Kind),
+		"id":             artifact.ID,
+		"payload":        artifact.Payload,
+		"created_at":     artifact.CreatedAt,
+		"schema_version": negotiation.Version,
+		"supported":      negotiation.Supported,
+	}
+	if !negotiation.Supported {
+		view["payload"] = nil
+		view["notice"] = negotiation.Notice
+		view["raw_json"] = rawArtifactJSON(artifact)
+	}
+	return view
+}
+
+// rawArtifactJSON is the artifact as indented JSON, for display when it cannot be read
+func rawArtifactJSON(artifact core.Artifact) string {
+	data, err := json.MarshalIndent(artifact, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("{\"error\": %q}", err.Error())
+	}
+	return string(data)
+}
+
+// RenderArtifactFallback renders an unreadable artifact view as its notice over its raw JSON.
+// It needs no templates, so it is safe before the research routes set the service up.
+func (s *RenderService) RenderArtifactFallback(view map[string]interface{}) string {
+	notice, _ := view["notice"].(string)
+	rawJSON, _ := view["raw_json"].(string)
+	return `<div class="rounded-lg border border-amber-200 bg-amber-50 p-4">
+                  <p class="text-sm font-semibold text-amber-900 mb-2">` + html.EscapeString(notice) + `</p>
+                  <p class="text-xs text-amber-800 mb-2">Update GoHypo to view it here. The raw artifact follows.</p>
+                  <pre class="text-xs text-gray-800 bg-white rounded p-3 overflow-x-auto max-h-96">` + html.EscapeString(rawJSON) + `</pre>
+                </div>`
+}
@@ -111,7 +111,12 @@ func (s *DataService) GetFieldMetadata() ([]greenfield.FieldMetadata, error) {
 		log.Printf("[API] 📊 Added %d fields directly from Excel file with inferred types", excelFields)
 	}
 
-	for _, artifact := range allArtifacts {
+	readable, unreadable := ReadableArtifacts(allArtifacts)
+	if len(unreadable) > 0 {
+		log.Printf("[API] ⚠️ Skipped %d artifacts produced by a newer engine when collecting fields", len(unreadable))
+	}
+
+	for _, artifact := range readable {
 		if artifact.Kind == core.ArtifactRelationship {
 			var varX, varY string
 
@@ -193,44 +198,9 @@ func (s *DataService) GetStatisticalArtifacts() ([]map[string]interface{}, error
 
 	for _, artifact := range allArtifacts {
 		switch artifact.Kind {
-		case core.ArtifactRelationship:
-			artifactData := map[string]interface{}{
-				"kind":       string(artifact.Kind),
-				"id":         artifact.ID,
-				"payload":    artifact.Payload,
-				"created_at": artifact.CreatedAt,
-			}
-			statsArtifacts = append(statsArtifacts, artifactData)
-			statArtifactCount++
-
-		case core.ArtifactSweepManifest:
-			artifactData := map[string]interface{}{
-				"kind":       string(artifact.Kind),
-				"id":         artifact.ID,
-				"payload":    artifact.Payload,
-				"created_at": artifact.CreatedAt,
-			}
-			statsArtifacts = append(statsArtifacts, artifactData)
-			statArtifactCount++
-
-		case core.ArtifactFDRFamily:
-			artifactData := map[string]interface{}{
-				"kind":       string(artifact.Kind),
-				"id":         artifact.ID,
-				"payload":    artifact.Payload,
-				"created_at": artifact.CreatedAt,
-			}
-			statsArtifacts = append(statsArtifacts, artifactData)
-			statArtifactCount++
-
-		case core.ArtifactVariableHealth:
-			artifactData := map[string]interface{}{
-				"kind":       string(artifact.Kind),
-				"id":         artifact.ID,
-				"payload":    artifact.Payload,
-				"created_at": artifact.CreatedAt,
-			}
-			statsArtifacts = append(statsArtifacts, artifactData)
+		case core.ArtifactRelationship, core.ArtifactSweepManifest, core.ArtifactFDRFamily, core.ArtifactVariableHealth:
+			// A payload this UI cannot read keeps its place with a notice and its raw JSON
+			statsArtifacts = append(statsArtifacts, ArtifactView(artifact))
 			statArtifactCount++
 		}
 	}
@@ -0,0 +1,383 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+	"gohypo/domain/stats"
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// shareLinksEnabled reports whether the workspace allows public share links
+func shareLinksEnabled(workspace *domainDataset.Workspace) bool {
+	enabled, _ := workspace.Metadata[models.ShareLinksEnabledKey].(bool)
+	return enabled
+}
+
+// shareLinkWorkspace loads a workspace owned by the default user for share link management
+func (s *Server) shareLinkWorkspace(c *gin.Context) (*domainDataset.Workspace, bool) {
+	if s.shareLinks == nil || s.workspaceRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Share links not available"})
+		return nil, false
+	}
+	return s.ownedWorkspace(c)
+}
+
+// handleUpdateShareSettings enables or disables public share links for a workspace.
+// Disabling takes every existing link offline; enabling again restores the unexpired ones.
+func (s *Server) handleUpdateShareSettings(c *gin.Context) {
+	workspace, ok := s.shareLinkWorkspace(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.Enabled == nil {
+		respondProblem(c, apperrors.InvalidInput("enabled is required"))
+		return
+	}
+	if workspace.Metadata == nil {
+		workspace.Metadata = make(map[string]interface{})
+	}
+	workspace.Metadata[models.ShareLinksEnabledKey] = *body.Enabled
+	if err := s.workspaceRepository.Update(c.Request.Context(), workspace); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to update workspace"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"workspace_id": workspace.ID, "enabled": *body.Enabled})
+}
+
+// handleCreateShareLink creates an expiring public link to one of the workspace's validation
+// pages. The token is returned only in this response.
+func (s *Server) handleCreateShareLink(c *gin.Context) {
+	workspace, ok := s.shareLinkWorkspace(c)
+	if !ok {
+		return
+	}
+	if !shareLinksEnabled(workspace) {
+		respondProblem(c, apperrors.Forbidden("Share links are disabled for this workspace"))
+		return
+	}
+	var body struct {
+		HypothesisID   string `json:"hypothesis_id"`
+		ExpiresInHours int    `json:"expires_in_hours"`
+		CreatedBy      string `json:"created_by"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+	if s.researchStorage != nil && body.HypothesisID != "" {
+		hypothesis, err := s.researchStorage.GetByID(c.Request.Context(), body.HypothesisID)
+		if err != nil || hypothesis == nil || hypothesis.WorkspaceID != string(workspace.ID) {
+			respondProblem(c, apperrors.NotFound("Hypothesis"))
+			return
+		}
+	}
+
+	link, err := models.NewShareLink(string(workspace.ID), body.HypothesisID, time.Duration(body.ExpiresInHours)*time.Hour)
+	if err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	link.CreatedBy = body.CreatedBy
+	if err := s.shareLinks.Create(c.Request.Context(), link); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to create share link"))
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"link": link,
+		"url":  "/share/" + link.Token,
+	})
+}
+
+// handleListShareLinks lists a workspace's share links without their tokens
+func (s *Server) handleListShareLinks(c *gin.Context) {
+	workspace, ok := s.shareLinkWorkspace(c)
+	if !ok {
+		return
+	}
+	links, err := s.shareLinks.ListByWorkspace(c.Request.Context(), string(workspace.ID))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list share links"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"workspace_id": workspace.ID,
+		"enabled":      shareLinksEnabled(workspace),
+		"links":        links,
+	})
+}
+
+// handleRevokeShareLink takes a share link offline for good
+func (s *Server) handleRevokeShareLink(c *gin.Context) {
+	workspace, ok := s.shareLinkWorkspace(c)
+	if !ok {
+		return
+	}
+	if err := s.shareLinks.Revoke(c.Request.Context(), string(workspace.ID), c.Param("linkId")); err != nil {
+		respondProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": c.Param("linkId"), "revoked": true})
+}
+
+// handleSharedValidation renders the read-only validation page behind a share link. It needs
+// no login; unknown, expired and revoked links, and links of workspaces that turned sharing
+// off, all get the same not-found page so tokens cannot be probed.
+func (s *Server) handleSharedValidation(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+	c.Header("Referrer-Policy", "no-referrer")
+
+	hypothesis, workspace, err := s.sharedHypothesis(c)
+	if err != nil {
+		log.Printf("[Share] Refused share link: %v", err)
+		c.Data(http.StatusNotFound, "text/html; charset=utf-8", []byte(sharedNotFoundPage))
+		return
+	}
+
+	var page strings.Builder
+	if err := sharedValidationTemplate.Execute(&page, newSharedValidationPage(hypothesis, s.branding, stats.AnalysisPolicyFrom(workspace.Metadata))); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to render validation page"))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page.String()))
+}
+
+// sharedHypothesis resolves a share token to the hypothesis it exposes and its workspace
+func (s *Server) sharedHypothesis(c *gin.Context) (*models.HypothesisResult, *domainDataset.Workspace, error) {
+	if s.shareLinks == nil || s.workspaceRepository == nil || s.researchStorage == nil {
+		return nil, nil, fmt.Errorf("share links not available")
+	}
+	ctx := c.Request.Context()
+	link, err := s.shareLinks.GetByToken(ctx, c.Param("token"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !link.Active(time.Now()) {
+		return nil, nil, fmt.Errorf("share link %s is expired or revoked", link.ID)
+	}
+	workspace, err := s.workspaceRepository.GetByID(ctx, core.ID(link.WorkspaceID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("workspace of share link %s: %w", link.ID, err)
+	}
+	if !shareLinksEnabled(workspace) {
+		return nil, nil, fmt.Errorf("share links are disabled for workspace %s", workspace.ID)
+	}
+	hypothesis, err := s.researchStorage.GetByID(ctx, link.HypothesisID)
+	if err != nil || hypothesis == nil || hypothesis.WorkspaceID != link.WorkspaceID {
+		return nil, nil, fmt.Errorf("hypothesis %s of share link %s not found", link.HypothesisID, link.ID)
+	}
+	return hypothesis, workspace, nil
+}
+
+// sharedValidationPage is what a shared page shows of a hypothesis: nothing beyond the
+// relationship, the hypothesis, its verdict and caveats
+type sharedValidationPage struct {
+	Hypothesis      *models.HypothesisResult
+	Verdict         string
+	Cause           string
+	Effect          string
+	Correlation     string
+	NoiseFloor      string // the correlation against the workspace's noise floor, when calibrated
+	SampleSize      string
+	Caveats         []string
+	Warnings        []stats.WarningInfo // data caveats from the sweep, with remediation
+	Explanation     models.VerdictExplanation
+	Counterexamples []models.Counterexample
+	Fingerprint     string
+	Watermark       template.CSS
+	Brand           models.Branding
+	BrandStyle      template.CSS
+	WarningStyle    template.CSS
+	// Queued or running for re-validation; only shown to the hypothesis' owner
+	PendingRevalidation bool
+	// The verdict artifact every number links to; only shown to the hypothesis' owner, as
+	// artifacts are not public
+	Provenance *models.Provenance
+	ChipStyle  template.CSS
+}
+
+func newSharedValidationPage(h *models.HypothesisResult, brand models.Branding, policy stats.AnalysisPolicy) sharedValidationPage {
+	page := sharedValidationPage{
+		Hypothesis:      h,
+		Verdict:         "Not validated",
+		Explanation:     models.VerdictExplanationOf(h),
+		Counterexamples: models.CounterexamplesOf(h),
+		Fingerprint:     models.ValidationFingerprint(h),
+		Brand:           brand,
+		BrandStyle:      brandStyle(brand),
+		Warnings:        describeWarnings(models.DataWarningsOf(h)),
+		WarningStyle:    template.CSS(warningTooltipStyle),
+	}
+	if h.Passed {
+		page.Verdict = "Validated"
+	}
+	page.Cause, _ = h.ExecutionMetadata["cause_key"].(string)
+	page.Effect, _ = h.ExecutionMetadata["effect_key"].(string)
+	if r, ok := h.ExecutionMetadata["observed_correlation"].(float64); ok {
+		page.Correlation = fmt.Sprintf("%.3f (%s)", r, policy.EffectStrength(r))
+	}
+	noisePercentile, calibrated := models.NoisePercentileOf(h)
+	if calibrated {
+		page.NoiseFloor = fmt.Sprintf("stronger than %.0f%% of effects in permuted data", noisePercentile)
+	}
+	sampleSize := 0
+	switch n := h.ExecutionMetadata["sample_size"].(type) {
+	case int:
+		sampleSize = n
+	case float64:
+		sampleSize = int(n)
+	}
+	if sampleSize > 0 {
+		page.SampleSize = fmt.Sprintf("%d", sampleSize)
+	}
+
+	for _, r := range h.RefereeResults {
+		if !r.Passed {
+			caveat := fmt.Sprintf("%s did not pass", strings.ReplaceAll(r.GateName, "_", " "))
+			if r.FailureReason != "" {
+				caveat += ": " + r.FailureReason
+			}
+			page.Caveats = append(page.Caveats, caveat)
+		}
+	}
+	if unstable := metadataStrings(h.ExecutionMetadata["unstable_referees"]); len(unstable) > 0 {
+		page.Caveats = append(page.Caveats, "Not stable across subsamples: "+strings.Join(unstable, ", "))
+	}
+	if calibrated && noisePercentile < 95 {
+		page.Caveats = append(page.Caveats, fmt.Sprintf("Within the noise floor: %.0f%% of effects in permuted copies of this workspace's data are as strong", 100-noisePercentile))
+	}
+	if sampleSize > 0 && sampleSize < 100 {
+		page.Caveats = append(page.Caveats, fmt.Sprintf("Small sample: %d observations", sampleSize))
+	}
+	if reasoning, _ := h.ExecutionMetadata["auditor_reasoning"].(string); reasoning != "" {
+		page.Caveats = append(page.Caveats, "Auditor: "+reasoning)
+	}
+	if policy != stats.DefaultAnalysisPolicy() {
+		page.Caveats = append(page.Caveats, "Judged under this workspace's analysis policy: "+policy.Describe())
+	}
+	page.Caveats = append(page.Caveats, "Validation shows a robust association in observational data; it does not by itself establish that the cause drives the effect.")
+
+	// A tiled, rotated fingerprint behind the content marks every screenshot and print
+	svg := fmt.Sprintf(`<svg xmlns='http://www.w3.org/2000/svg' width='320' height='160'><text x='0' y='100' transform='rotate(-20 160 80)' font-family='monospace' font-size='14' fill='rgba(0,0,0,0.07)'>%s %s</text></svg>`, html.EscapeString(brand.Name), page.Fingerprint)
+	page.Watermark = template.CSS(fmt.Sprintf(`url("data:image/svg+xml;utf8,%s")`, strings.NewReplacer("%", "%25", "<", "%3C", ">", "%3E", "#", "%23", `"`, "'", "\\", "%5C", "\n", " ").Replace(svg)))
+	return page
+}
+
+// metadataStrings reads a string list from execution metadata, which may have been decoded
+// from JSON
+func metadataStrings(v interface{}) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		var out []string
+		for _, item := range list {
+			if str, ok := item.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+const sharedNotFoundPage = `<!DOCTYPE html><html><head><meta charset="utf-8"><meta name="robots" content="noindex"><title>Link unavailable</title></head>` +
+	`<body style="font-family:sans-serif;margin:4rem auto;max-width:32rem;color:#374151"><h1>This link is unavailable</h1>` +
+	`<p>The share link has expired, was revoked, or never existed. Ask the person who shared it for a new one.</p></body></html>`
+
+var sharedValidationTemplate = template.Must(template.New("shared_validation").Funcs(template.FuncMap{
+	"provenanceChip": provenanceChip,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex, nofollow">
+<title>{{.Verdict}}: {{.Hypothesis.BusinessHypothesis}} · {{.Brand.Name}}</title>
+<style>
+{{.BrandStyle}}
+body { font-family: -apple-system, "Segoe UI", sans-serif; color: #1f2937; margin: 0; background-image: {{.Watermark}}; }
+header { display: flex; align-items: center; gap: .75rem; padding: .75rem 1.5rem; border-bottom: 3px solid var(--brand-primary); color: var(--brand-accent); font-weight: 600; }
+header img { max-height: 2rem; }
+main { max-width: 52rem; margin: 0 auto; padding: 2rem 1.5rem 4rem; }
+h1, h2 { color: var(--brand-accent); }
+.verdict { display: inline-block; padding: .25rem .75rem; border-radius: 999px; font-weight: 600; }
+.passed { background: #dcfce7; color: #166534; }
+.failed { background: #fee2e2; color: #991b1b; }
+.pending { background: #fef3c7; color: #92400e; }
+table { border-collapse: collapse; width: 100%; font-size: .9rem; }
+th, td { text-align: left; padding: .4rem .5rem; border-bottom: 1px solid #e5e7eb; }
+dl { display: grid; grid-template-columns: max-content 1fr; gap: .25rem 1rem; }
+dt { color: #6b7280; }
+{{.WarningStyle}}
+{{.ChipStyle}}
+footer { margin-top: 3rem; font-size: .8rem; color: #6b7280; font-family: monospace; }
+</style>
+</head>
+<body>
+<header>{{if .Brand.LogoURL}}<img src="{{.Brand.LogoURL}}" alt="{{.Brand.Name}}">{{end}}<span>{{.Brand.Name}}</span></header>
+<main>
+<p class="verdict {{if .Hypothesis.Passed}}passed{{else}}failed{{end}}">{{.Verdict}}</p>
+{{if .PendingRevalidation}}<p class="verdict pending">Pending re-validation: this verdict is being re-checked and may change</p>{{end}}
+<h1>{{.Hypothesis.BusinessHypothesis}}</h1>
+
+<h2>Relationship</h2>
+<dl>
+{{if .Cause}}<dt>Cause</dt><dd>{{.Cause}}</dd>{{end}}
+{{if .Effect}}<dt>Effect</dt><dd>{{.Effect}}</dd>{{end}}
+{{if .Correlation}}<dt>Observed correlation</dt><dd>{{.Correlation}}{{provenanceChip .Provenance}}</dd>{{end}}
+{{if .NoiseFloor}}<dt>Noise floor</dt><dd>{{.NoiseFloor}}{{provenanceChip .Provenance}}</dd>{{end}}
+{{if .SampleSize}}<dt>Sample size</dt><dd>{{.SampleSize}}{{provenanceChip .Provenance}}</dd>{{end}}
+<dt>E-value</dt><dd>{{printf "%.2f" .Hypothesis.CurrentEValue}}{{provenanceChip .Provenance}}</dd>
+<dt>Validated</dt><dd>{{.Hypothesis.ValidationTimestamp.Format "2006-01-02 15:04 MST"}}{{provenanceChip .Provenance}}</dd>
+</dl>
+
+<h2>Hypothesis</h2>
+{{if .Hypothesis.ScienceHypothesis}}<p>{{.Hypothesis.ScienceHypothesis}}</p>{{end}}
+{{if .Hypothesis.NullCase}}<p><strong>Null case:</strong> {{.Hypothesis.NullCase}}</p>{{end}}
+
+<h2>Evidence</h2>
+<table>
+<tr><th>Referee</th><th>Result</th><th>Statistic</th><th>p-value</th><th>E-value</th></tr>
+{{range .Hypothesis.RefereeResults}}<tr><td>{{.GateName}}</td><td>{{if .Passed}}passed{{else}}failed{{end}}</td><td>{{printf "%.4g" .Statistic}}</td><td>{{printf "%.4g" .PValue}}</td><td>{{printf "%.3g" .EValue}}{{provenanceChip $.Provenance}}</td></tr>
+{{end}}</table>
+
+<h2>Why this verdict</h2>
+<p>{{.Explanation.Summary}}</p>
+{{if .Explanation.Gates}}<ul>
+{{range .Explanation.Gates}}<li>{{.Reason}}{{if .Decisive}} <strong>(decisive)</strong>{{end}}</li>
+{{end}}</ul>{{end}}
+{{if .Explanation.WouldChange}}<p><strong>What would change it:</strong></p>
+<ul>
+{{range .Explanation.WouldChange}}<li>{{.}}</li>
+{{end}}</ul>{{end}}
+
+{{if .Counterexamples}}<h2>Why it failed</h2>
+<ul>
+{{range .Counterexamples}}<li>{{.Summary}}</li>
+{{end}}</ul>{{end}}
+
+<h2>Caveats</h2>
+<ul>
+{{range .Caveats}}<li>{{.}}</li>
+{{end}}{{range .Warnings}}<li><details class="warning {{.Severity}}"><summary>{{.Title}} · {{.Severity}}</summary><p>{{.Description}}</p><p><strong>What to do:</strong> {{.Remediation}}</p></details></li>
+{{end}}</ul>
+
+<footer>{{if .Brand.FooterText}}<p>{{.Brand.FooterText}}</p>{{end}}Read-only shared validation · fingerprint {{.Fingerprint}}{{if .Hypothesis.StandardsVersion}} · standards {{.Hypothesis.StandardsVersion}}{{end}}</footer>
+</main>
+</body>
+</html>
+`))
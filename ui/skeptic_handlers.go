@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// skepticAnalyzer re-runs a hypothesis' association with the most conservative choices
+type skepticAnalyzer interface {
+	SkepticReanalysis(ctx context.Context, hypothesis *models.HypothesisResult) (*models.SkepticRun, error)
+}
+
+// handleRunSkepticMode re-analyzes a hypothesis in skeptic mode on the current data and
+// returns the before/after comparison, which is kept on the hypothesis
+func (s *Server) handleRunSkepticMode(c *gin.Context) {
+	if s.skeptic == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Skeptic mode not available"})
+		return
+	}
+	hypothesis, ok := s.ownedHypothesis(c)
+	if !ok {
+		return
+	}
+	run, err := s.skeptic.SkepticReanalysis(c.Request.Context(), hypothesis)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to run skeptic mode"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"hypothesis_id": hypothesis.ID, "skeptic": run})
+}
+
+// handleGetSkepticRun returns a hypothesis' latest skeptic-mode comparison
+func (s *Server) handleGetSkepticRun(c *gin.Context) {
+	hypothesis, ok := s.ownedHypothesis(c)
+	if !ok {
+		return
+	}
+	run, ok := models.SkepticRunOf(hypothesis)
+	if !ok {
+		respondProblem(c, apperrors.NotFound("Skeptic-mode result"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"hypothesis_id": hypothesis.ID, "skeptic": run})
+}
+
+// handleSkepticPage renders a hypothesis' association as reported next to its skeptic-mode
+// re-analysis, with a button to run or re-run it
+func (s *Server) handleSkepticPage(c *gin.Context) {
+	hypothesis, ok := s.ownedHypothesis(c)
+	if !ok {
+		return
+	}
+	run, _ := models.SkepticRunOf(hypothesis)
+	var page strings.Builder
+	data := gin.H{
+		"Hypothesis": hypothesis,
+		"Run":        run,
+		"Reported":   hypothesisProvenance(hypothesis),
+		"ChipStyle":  template.CSS(provenanceChipStyle),
+		"Available":  s.skeptic != nil,
+		"RunURL":     "/api/hypotheses/" + url.PathEscape(hypothesis.ID) + "/skeptic",
+		"Title":      s.branding.Title("Skeptic mode"),
+		"Style":      brandStyle(s.branding),
+	}
+	if err := skepticTemplate.Execute(&page, data); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to render skeptic mode page"))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page.String()))
+}
+
+var skepticTemplate = template.Must(template.New("skeptic").Funcs(template.FuncMap{
+	"label":          func(name string) string { return strings.ReplaceAll(name, "_", " ") },
+	"provenanceChip": provenanceChip,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+{{.Style}}
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 0; color: var(--brand-accent); font-size: 14px; }
+header { padding: 12px 20px; border-bottom: 3px solid var(--brand-primary); display: flex; gap: 16px; align-items: center; }
+header h1 { font-size: 16px; margin: 0; }
+main { padding: 8px 20px 40px; max-width: 60rem; }
+.meta { color: #6b7280; }
+table { border-collapse: collapse; width: 100%; margin: 8px 0 20px; }
+th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #f3f4f6; vertical-align: top; }
+th { color: #6b7280; font-weight: 600; }
+.verdict { padding: 10px 14px; border-radius: 6px; font-weight: 600; }
+.survives { background: #dcfce7; color: #166534; }
+.fails { background: #fee2e2; color: #991b1b; }
+.pass { color: #166534; }
+.fail { color: #991b1b; }
+{{.ChipStyle}}
+</style>
+</head>
+<body>
+<header>
+<h1>Skeptic mode</h1>
+{{if .Available}}<button id="run" data-url="{{.RunURL}}">{{if .Run}}Re-run{{else}}Run{{end}} skeptic mode</button>{{end}}
+<span class="meta" id="state"></span>
+</header>
+<main>
+<p>{{.Hypothesis.BusinessHypothesis}}</p>
+{{with .Run}}
+<p class="meta">{{.CauseKey}} → {{.EffectKey}} · {{.Report.SampleSize}} rows · {{.At.Format "2006-01-02 15:04"}} · {{.Policy.Describe}}</p>
+<p class="verdict {{if .Report.Survives}}survives{{else}}fails{{end}}">{{.Report.Summary}}</p>
+<table>
+<tr><th></th><th>Effect (r)</th><th>p</th><th>Strength</th><th>Significant</th></tr>
+<tr><td>As reported{{provenanceChip $.Reported}}</td><td>{{printf "%.3f" .Report.Before.Effect}}</td><td>{{printf "%.3g" .Report.Before.PValue}}</td><td>{{.Report.Before.Strength}}</td><td>{{if .Report.Before.Significant}}yes{{else}}no{{end}}</td></tr>
+<tr><td>Skeptic mode{{provenanceChip .Provenance}}</td><td>{{printf "%.3f" .Report.After.Effect}}</td><td>{{printf "%.3g" .Report.After.PValue}}</td><td>{{.Report.After.Strength}}</td><td>{{if .Report.After.Significant}}yes{{else}}no{{end}}</td></tr>
+</table>
+<table>
+<tr><th>Check</th><th>Effect</th><th>p</th><th>Result</th><th>How</th></tr>
+{{range .Report.Checks}}<tr>
+<td>{{label .Name}}{{provenanceChip $.Run.Provenance}}</td>
+<td>{{printf "%.3f" .Effect}}</td>
+<td>{{printf "%.3g" .PValue}}</td>
+<td>{{if .Passed}}<span class="pass">passes</span>{{else}}<span class="fail">fails</span>{{end}}</td>
+<td class="meta">{{.Detail}}</td>
+</tr>{{end}}
+</table>
+{{else}}
+<p class="meta">Skeptic mode re-runs this finding with the most conservative choices: robust estimators, block permutation, cluster correction, shrinkage and holdout confirmation.</p>
+{{end}}
+</main>
+<script>
+(function () {
+  var button = document.getElementById("run");
+  if (!button) { return; }
+  var state = document.getElementById("state");
+  button.addEventListener("click", function () {
+    button.disabled = true;
+    state.textContent = "re-analyzing…";
+    fetch(button.dataset.url, { method: "POST" }).then(function (resp) {
+      if (resp.ok) { location.reload(); return; }
+      return resp.json().then(function (problem) {
+        button.disabled = false;
+        state.textContent = problem.detail || problem.error || "skeptic mode failed";
+      });
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
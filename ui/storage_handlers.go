@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleRotateStorageKeys re-encrypts stored datasets under the active encryption key
+func (s *Server) handleRotateStorageKeys(c *gin.Context) {
+	if s.fileStorage == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "File storage not available"})
+		return
+	}
+
+	report, err := s.fileStorage.RotateKeys(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "report": report})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"report":  report,
+	})
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"net/http"
+
+	"gohypo/domain/core"
+	apperrors "gohypo/internal/errors"
+	"gohypo/ui/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleProvisionWorkspaceSchema creates (or repairs) the schema for an existing workspace
+func (s *Server) handleProvisionWorkspaceSchema(c *gin.Context) {
+	if s.schemaProvisioner == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Schema-per-workspace mode not enabled"})
+		return
+	}
+
+	workspaceID := core.ID(c.Param("id"))
+	if _, err := s.workspaceRepository.GetByID(c.Request.Context(), workspaceID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+		return
+	}
+
+	if err := s.schemaProvisioner.Provision(c.Request.Context(), workspaceID); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to provision workspace schema"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"workspace_id": workspaceID,
+	})
+}
+
+// handleDeprovisionWorkspaceSchema drops a workspace schema and every row in it. It cannot be
+// undone, so it takes an authenticated admin even while the rest of the admin API is open.
+func (s *Server) handleDeprovisionWorkspaceSchema(c *gin.Context) {
+	if s.schemaProvisioner == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Schema-per-workspace mode not enabled"})
+		return
+	}
+	if middleware.AdminCaller(c) == "" {
+		respondProblem(c, apperrors.Forbidden("Dropping workspace schemas requires ADMIN_API_KEYS to be configured"))
+		return
+	}
+
+	workspaceID := core.ID(c.Param("id"))
+	if err := s.schemaProvisioner.Deprovision(c.Request.Context(), workspaceID); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to drop workspace schema"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"workspace_id": workspaceID,
+	})
+}
@@ -0,0 +1,571 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gohypo/domain/core"
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// triageListLimit bounds how many hypotheses a triage list loads
+const triageListLimit = 1000
+
+// handleTriagePage renders the keyboard-driven triage page of a workspace
+func (s *Server) handleTriagePage(c *gin.Context) {
+	if !s.authorizeTriageWorkspace(c) {
+		return
+	}
+	var page strings.Builder
+	data := gin.H{
+		"WorkspaceID": c.Param("id"),
+		"Title":       s.branding.Title("Triage"),
+		"Brand":       s.branding,
+		"Style":       brandStyle(s.branding),
+	}
+	if err := triageTemplate.Execute(&page, data); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to render triage page"))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page.String()))
+}
+
+// handleGetTriageList returns a workspace's hypotheses or relationships with their triage
+// status, narrowed by a saved view (?view=) and by filters in the query, which take precedence
+func (s *Server) handleGetTriageList(c *gin.Context) {
+	list, ok := triageListParam(c)
+	if !ok || !s.authorizeTriageWorkspace(c) {
+		return
+	}
+	ctx := c.Request.Context()
+
+	var filters models.ViewFilters
+	if viewID := c.Query("view"); viewID != "" {
+		userID, err := s.getDefaultUserID(ctx)
+		if err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to get user"))
+			return
+		}
+		view, err := s.triage.GetView(ctx, string(userID), viewID)
+		if err != nil {
+			respondProblem(c, err)
+			return
+		}
+		if view.List != list {
+			respondProblem(c, apperrors.InvalidInput(fmt.Sprintf("View %q is for %s", view.Name, view.List)))
+			return
+		}
+		filters = view.Filters
+	}
+	filters, err := triageFiltersFromQuery(c, filters)
+	if err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+
+	items, err := s.triageItems(ctx, c.Param("id"), list)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to load "+list))
+		return
+	}
+	counts := map[string]int{models.TriagePending: 0, models.TriageApproved: 0, models.TriageDismissed: 0}
+	for _, item := range items {
+		counts[item.Status]++
+	}
+	matched := filters.Apply(items)
+	c.JSON(http.StatusOK, gin.H{
+		"list":    list,
+		"filters": filters,
+		"items":   matched,
+		"count":   len(matched),
+		"total":   len(items),
+		"counts":  counts,
+	})
+}
+
+// handleMarkTriageItems sets the triage status of one or many items of a list at once
+func (s *Server) handleMarkTriageItems(c *gin.Context) {
+	list, ok := triageListParam(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		ItemIDs []string `json:"item_ids"`
+		Status  string   `json:"status"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+	if !models.ValidTriageStatus(body.Status) {
+		respondProblem(c, apperrors.InvalidInput("status must be pending, approved or dismissed"))
+		return
+	}
+	if len(body.ItemIDs) == 0 || len(body.ItemIDs) > models.MaxTriageBatch {
+		respondProblem(c, apperrors.InvalidInput(fmt.Sprintf("item_ids must list 1 to %d items", models.MaxTriageBatch)))
+		return
+	}
+	if !s.authorizeTriageWorkspace(c) {
+		return
+	}
+	ctx := c.Request.Context()
+	workspaceID := c.Param("id")
+
+	items, err := s.triageItems(ctx, workspaceID, list)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to load "+list))
+		return
+	}
+	known := make(map[string]bool, len(items))
+	for _, item := range items {
+		known[item.ID] = true
+	}
+	userID, err := s.getDefaultUserID(ctx)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to get user"))
+		return
+	}
+	marks := make([]*models.TriageMark, 0, len(body.ItemIDs))
+	for _, id := range body.ItemIDs {
+		if !known[id] {
+			respondProblem(c, apperrors.InvalidInput(fmt.Sprintf("Item %s is not in this workspace's %s", id, list)))
+			return
+		}
+		marks = append(marks, &models.TriageMark{
+			WorkspaceID: workspaceID,
+			List:        list,
+			ItemID:      id,
+			Status:      body.Status,
+			MarkedBy:    string(userID),
+		})
+	}
+	if err := s.triage.Mark(ctx, marks); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to save triage marks"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"list": list, "status": body.Status, "marked": len(marks)})
+}
+
+// handleListSavedViews lists the default user's saved views, of one list when ?list= is set
+func (s *Server) handleListSavedViews(c *gin.Context) {
+	userID, ok := s.triageUser(c)
+	if !ok {
+		return
+	}
+	list := c.Query("list")
+	if list != "" && !models.ValidTriageList(list) {
+		respondProblem(c, apperrors.InvalidInput("list must be hypotheses or relationships"))
+		return
+	}
+	views, err := s.triage.ListViews(c.Request.Context(), userID, list)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list saved views"))
+		return
+	}
+	if views == nil {
+		views = []*models.SavedView{}
+	}
+	c.JSON(http.StatusOK, gin.H{"views": views, "count": len(views)})
+}
+
+// handleCreateSavedView saves a named set of filters for the default user
+func (s *Server) handleCreateSavedView(c *gin.Context) {
+	userID, ok := s.triageUser(c)
+	if !ok {
+		return
+	}
+	var view models.SavedView
+	if err := c.ShouldBindJSON(&view); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+	view.ID = ""
+	view.UserID = userID
+	view.Name = strings.TrimSpace(view.Name)
+	if err := view.Validate(); err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	if err := s.triage.CreateView(c.Request.Context(), &view); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to save view"))
+		return
+	}
+	c.JSON(http.StatusCreated, view)
+}
+
+// handleDeleteSavedView removes one of the default user's saved views
+func (s *Server) handleDeleteSavedView(c *gin.Context) {
+	userID, ok := s.triageUser(c)
+	if !ok {
+		return
+	}
+	if err := s.triage.DeleteView(c.Request.Context(), userID, c.Param("viewId")); err != nil {
+		respondProblem(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// triageItems loads a workspace list as triage items carrying their current status
+func (s *Server) triageItems(ctx context.Context, workspaceID, list string) ([]models.TriageItem, error) {
+	var items []models.TriageItem
+	switch list {
+	case models.TriageListHypotheses:
+		if s.researchStorage == nil {
+			return nil, fmt.Errorf("research storage not available")
+		}
+		hypotheses, err := s.researchStorage.ListByWorkspace(ctx, workspaceID, triageListLimit)
+		if err != nil {
+			return nil, err
+		}
+		pending := map[string]bool{}
+		if s.validationQueue != nil {
+			pending = s.validationQueue.Pending(workspaceID)
+		}
+		for _, h := range hypotheses {
+			passed := h.Passed
+			items = append(items, models.TriageItem{
+				ID:                  h.ID,
+				Title:               h.BusinessHypothesis,
+				Confidence:          h.Confidence,
+				Passed:              &passed,
+				CreatedAt:           h.ValidationTimestamp,
+				PendingRevalidation: pending[h.ID],
+			})
+		}
+	case models.TriageListRelationships:
+		relations, err := s.workspaceRepository.GetRelations(ctx, core.ID(workspaceID))
+		if err != nil {
+			return nil, err
+		}
+		names := make(map[core.ID]string)
+		if workspace, err := s.workspaceRepository.GetWithDatasets(ctx, core.ID(workspaceID)); err == nil {
+			for _, ds := range workspace.Datasets {
+				names[ds.ID] = ds.DisplayName
+			}
+		}
+		for _, relation := range relations {
+			title, _ := relation.Metadata["business_context"].(string)
+			if title == "" {
+				title = fmt.Sprintf("%s → %s", names[relation.SourceDatasetID], names[relation.TargetDatasetID])
+			}
+			items = append(items, models.TriageItem{
+				ID:         string(relation.ID),
+				Title:      title,
+				Kind:       relation.RelationType,
+				Confidence: relation.Confidence,
+				CreatedAt:  relation.DiscoveredAt,
+			})
+		}
+	}
+
+	marks, err := s.triage.ListMarks(ctx, workspaceID, list)
+	if err != nil {
+		return nil, err
+	}
+	status := make(map[string]string, len(marks))
+	for _, mark := range marks {
+		status[mark.ItemID] = mark.Status
+	}
+	for i := range items {
+		items[i].Status = models.TriagePending
+		if marked, ok := status[items[i].ID]; ok {
+			items[i].Status = marked
+		}
+	}
+	return items, nil
+}
+
+// triageFiltersFromQuery overlays the filters given in the query string on a view's filters
+func triageFiltersFromQuery(c *gin.Context, filters models.ViewFilters) (models.ViewFilters, error) {
+	if raw, ok := c.GetQuery("status"); ok {
+		filters.Status = splitList(raw)
+	}
+	if raw := c.Query("min_confidence"); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return filters, fmt.Errorf("min_confidence must be a number")
+		}
+		filters.MinConfidence = value
+	}
+	if raw := c.Query("passed"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filters, fmt.Errorf("passed must be true or false")
+		}
+		filters.Passed = &value
+	}
+	if raw, ok := c.GetQuery("kind"); ok {
+		filters.Kinds = splitList(raw)
+	}
+	if raw, ok := c.GetQuery("q"); ok {
+		filters.Search = strings.TrimSpace(raw)
+	}
+	if raw := c.Query("sort"); raw != "" {
+		filters.Sort = raw
+	}
+	return filters, filters.Validate()
+}
+
+// splitList splits a comma-separated query value, dropping empty entries
+func splitList(raw string) []string {
+	var values []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// triageListParam reads and checks the :list path parameter
+func triageListParam(c *gin.Context) (string, bool) {
+	list := c.Param("list")
+	if !models.ValidTriageList(list) {
+		respondProblem(c, apperrors.InvalidInput("list must be hypotheses or relationships"))
+		return "", false
+	}
+	return list, true
+}
+
+// authorizeTriageWorkspace verifies triage is available and the workspace belongs to the
+// default user
+func (s *Server) authorizeTriageWorkspace(c *gin.Context) bool {
+	if s.triage == nil || s.workspaceRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Triage not available"})
+		return false
+	}
+	_, ok := s.ownedWorkspace(c)
+	return ok
+}
+
+// triageUser returns the default user for saved view requests
+func (s *Server) triageUser(c *gin.Context) (string, bool) {
+	if s.triage == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Saved views not available"})
+		return "", false
+	}
+	userID, err := s.getDefaultUserID(c.Request.Context())
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to get user"))
+		return "", false
+	}
+	return string(userID), true
+}
+
+var triageTemplate = template.Must(template.New("triage").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+{{.Style}}
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 0; color: var(--brand-accent); font-size: 14px; }
+header { display: flex; gap: 12px; align-items: center; padding: 12px 20px; border-bottom: 1px solid #e5e7eb; }
+header h1 { font-size: 16px; margin: 0 12px 0 0; }
+.tab { padding: 4px 10px; border-radius: 4px; cursor: pointer; }
+.tab.active { background: var(--brand-primary); color: #fff; }
+input, select { font: inherit; padding: 4px 6px; }
+main { padding: 8px 20px; }
+.row { display: flex; gap: 12px; padding: 8px 10px; border-left: 3px solid transparent; border-bottom: 1px solid #f3f4f6; }
+.row.cursor { border-left-color: var(--brand-primary); background: #f9fafb; }
+.row.selected { background: #eff6ff; }
+.row .title { flex: 1; }
+.status { width: 80px; font-size: 12px; text-transform: uppercase; color: #6b7280; }
+.status.approved { color: #15803d; }
+.status.dismissed { color: #b91c1c; }
+.meta { color: #6b7280; font-size: 12px; }
+.revalidating { margin-left: 8px; padding: 0 6px; border-radius: 3px; background: #fef3c7; color: #92400e; font-size: 12px; }
+#help { display: none; position: fixed; right: 20px; top: 60px; background: #fff; border: 1px solid #e5e7eb; padding: 12px 16px; box-shadow: 0 4px 12px rgba(0,0,0,.1); }
+#help.open { display: block; }
+kbd { border: 1px solid #d1d5db; border-radius: 3px; padding: 0 4px; font-size: 12px; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Brand.Name}} triage</h1>
+<span class="tab" data-list="hypotheses">1 Hypotheses</span>
+<span class="tab" data-list="relationships">2 Relationships</span>
+<select id="view"><option value="">All items</option></select>
+<select id="status"><option value="">Any status</option><option>pending</option><option>approved</option><option>dismissed</option></select>
+<input id="search" placeholder="Search ( / )">
+<span class="meta" id="summary"></span>
+<span class="meta" id="queue"></span>
+</header>
+<main id="items"></main>
+<div id="help">
+<div><kbd>j</kbd>/<kbd>k</kbd> next / previous</div>
+<div><kbd>a</kbd> approve, <kbd>d</kbd> dismiss, <kbd>u</kbd> back to pending</div>
+<div><kbd>x</kbd> select, <kbd>X</kbd> select all shown, <kbd>Esc</kbd> clear selection</div>
+<div>With a selection, <kbd>a</kbd>/<kbd>d</kbd>/<kbd>u</kbd> apply to all selected</div>
+<div><kbd>r</kbd> re-validate selected hypotheses in the order selected, <kbd>R</kbd> at high priority</div>
+<div><kbd>1</kbd>/<kbd>2</kbd> switch list, <kbd>v</kbd> next saved view, <kbd>s</kbd> save view</div>
+<div><kbd>/</kbd> search, <kbd>?</kbd> this help</div>
+</div>
+<script>
+(function () {
+  var workspaceID = {{.WorkspaceID}};
+  var state = {list: "hypotheses", items: [], views: [], cursor: 0, selected: {}};
+  var $ = function (id) { return document.getElementById(id); };
+
+  function api(method, url, body) {
+    return fetch(url, {method: method, headers: {"Content-Type": "application/json"}, body: body ? JSON.stringify(body) : undefined})
+      .then(function (r) { return r.status === 204 ? null : r.json().then(function (j) { if (!r.ok) { throw j; } return j; }); });
+  }
+
+  function query() {
+    var params = new URLSearchParams();
+    if ($("view").value) { params.set("view", $("view").value); }
+    if ($("status").value) { params.set("status", $("status").value); }
+    if ($("search").value) { params.set("q", $("search").value); }
+    return params.toString();
+  }
+
+  function load() {
+    return api("GET", "/api/workspaces/" + workspaceID + "/triage/" + state.list + "?" + query()).then(function (res) {
+      state.items = res.items || [];
+      state.cursor = Math.min(state.cursor, Math.max(state.items.length - 1, 0));
+      $("summary").textContent = res.count + " of " + res.total + " · " + res.counts.pending + " pending";
+      render();
+    });
+  }
+
+  function loadViews() {
+    return api("GET", "/api/views?list=" + state.list).then(function (res) {
+      state.views = res.views;
+      var select = $("view");
+      select.length = 1;
+      state.views.forEach(function (v) { select.add(new Option(v.name, v.id)); });
+    });
+  }
+
+  function render() {
+    document.querySelectorAll(".tab").forEach(function (t) { t.classList.toggle("active", t.dataset.list === state.list); });
+    var container = $("items");
+    container.textContent = "";
+    state.items.forEach(function (item, i) {
+      var row = document.createElement("div");
+      row.className = "row" + (i === state.cursor ? " cursor" : "") + (state.selected[item.id] ? " selected" : "");
+      var status = document.createElement("span");
+      status.className = "status " + item.status;
+      status.textContent = item.status;
+      var title = document.createElement("span");
+      title.className = "title";
+      title.textContent = item.title || item.id;
+      if (item.pending_revalidation) {
+        var badge = document.createElement("span");
+        badge.className = "revalidating";
+        badge.textContent = "pending re-validation";
+        title.appendChild(badge);
+      }
+      var meta = document.createElement("span");
+      meta.className = "meta";
+      meta.textContent = (item.kind ? item.kind + " · " : "") + "confidence " + (item.confidence || 0).toFixed(2);
+      row.append(status, title, meta);
+      row.onclick = function () { state.cursor = i; render(); };
+      container.appendChild(row);
+    });
+    var current = container.children[state.cursor];
+    if (current) { current.scrollIntoView({block: "nearest"}); }
+  }
+
+  function mark(status) {
+    var ids = Object.keys(state.selected);
+    var single = ids.length === 0;
+    if (single) {
+      if (!state.items[state.cursor]) { return; }
+      ids = [state.items[state.cursor].id];
+    }
+    api("POST", "/api/workspaces/" + workspaceID + "/triage/" + state.list, {item_ids: ids, status: status}).then(function () {
+      state.selected = {};
+      if (single) { state.cursor++; }
+      return load();
+    }).catch(function (err) { alert(err.detail || err.title || "Failed to save"); });
+  }
+
+  function revalidate(priority) {
+    if (state.list !== "hypotheses") { return; }
+    var ids = Object.keys(state.selected);
+    if (ids.length === 0 && state.items[state.cursor]) { ids = [state.items[state.cursor].id]; }
+    if (ids.length === 0) { return; }
+    api("POST", "/api/workspaces/" + workspaceID + "/validation-queue", {hypothesis_ids: ids, priority: priority}).then(function (res) {
+      state.selected = {};
+      $("queue").textContent = "Queued " + res.queued.length + " for validation";
+      load();
+    }).catch(function (err) { alert(err.detail || err.title || err.error || "Failed to queue"); });
+  }
+
+  if (window.EventSource) {
+    var events = new EventSource("/api/workspaces/" + workspaceID + "/validation-queue/events");
+    events.addEventListener("validation_verdict", function (e) {
+      var v = JSON.parse(e.data);
+      $("queue").textContent = v.hypothesis_id + " " + v.status + " · " + v.queued + " queued";
+      if (state.list === "hypotheses") { load(); }
+    });
+  }
+
+  function switchList(list) {
+    state.list = list; state.cursor = 0; state.selected = {};
+    $("view").value = "";
+    loadViews().then(load);
+  }
+
+  function saveView() {
+    var name = prompt("Name this view");
+    if (!name) { return; }
+    var filters = {};
+    if ($("status").value) { filters.status = [$("status").value]; }
+    if ($("search").value) { filters.search = $("search").value; }
+    api("POST", "/api/views", {list: state.list, name: name, filters: filters}).then(function (view) {
+      return loadViews().then(function () { $("view").value = view.id; $("status").value = ""; $("search").value = ""; return load(); });
+    }).catch(function (err) { alert(err.detail || err.title || "Failed to save view"); });
+  }
+
+  document.addEventListener("keydown", function (e) {
+    if (e.target.tagName === "INPUT" || e.target.tagName === "SELECT") {
+      if (e.key === "Escape" || e.key === "Enter") { e.target.blur(); load(); }
+      return;
+    }
+    if (e.metaKey || e.ctrlKey || e.altKey) { return; }
+    var item = state.items[state.cursor];
+    switch (e.key) {
+      case "j": case "ArrowDown": state.cursor = Math.min(state.cursor + 1, state.items.length - 1); render(); break;
+      case "k": case "ArrowUp": state.cursor = Math.max(state.cursor - 1, 0); render(); break;
+      case "a": mark("approved"); break;
+      case "d": mark("dismissed"); break;
+      case "u": mark("pending"); break;
+      case "x": if (item) { if (state.selected[item.id]) { delete state.selected[item.id]; } else { state.selected[item.id] = true; } render(); } break;
+      case "X": state.items.forEach(function (it) { state.selected[it.id] = true; }); render(); break;
+      case "Escape": state.selected = {}; $("help").classList.remove("open"); render(); break;
+      case "1": switchList("hypotheses"); break;
+      case "2": switchList("relationships"); break;
+      case "v":
+        var select = $("view");
+        select.selectedIndex = (select.selectedIndex + 1) % select.length;
+        state.cursor = 0; load(); break;
+      case "r": revalidate("normal"); break;
+      case "R": revalidate("high"); break;
+      case "s": saveView(); break;
+      case "/": $("search").focus(); break;
+      case "?": $("help").classList.toggle("open"); break;
+      default: return;
+    }
+    e.preventDefault();
+  });
+  document.querySelectorAll(".tab").forEach(function (t) { t.onclick = function () { switchList(t.dataset.list); }; });
+  $("view").onchange = function () { state.cursor = 0; load(); };
+  $("status").onchange = function () { state.cursor = 0; load(); };
+
+  switchList(state.list);
+})();
+</script>
+</body>
+</html>
+`))
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	"gohypo/domain/stats"
 )
 
-// determineVerdict returns a verdict ONLY if we have sufficient evidence (q-value + N)
-// Returns nil if insufficient data
-func determineVerdict(pValue, qValue, effectSize float64, sampleSize int, hasQValue bool) map[string]interface{} {
+// determineVerdict returns a verdict ONLY if we have sufficient evidence (q-value + N),
+// judging q against the policy's significance level. Returns nil if insufficient data
+func determineVerdict(policy stats.AnalysisPolicy, pValue, qValue, effectSize float64, sampleSize int, hasQValue bool) map[string]interface{} {
 	// Gate: No verdict if missing critical metrics
 	if sampleSize == 0 {
 		return map[string]interface{}{
@@ -42,15 +44,15 @@ func determineVerdict(pValue, qValue, effectSize float64, sampleSize int, hasQVa
 	// Now we can compute a verdict
 	var status, title, explanation string
 
-	if qValue <= 0.01 && sampleSize >= 200 {
+	if qValue <= policy.Alpha/5 && sampleSize >= 200 {
 		status = "PASS"
 		title = "Strong association"
 		explanation = fmt.Sprintf("q=%.4f (FDR), N=%d", qValue, sampleSize)
-	} else if qValue <= 0.05 && sampleSize >= 100 {
+	} else if qValue <= policy.Alpha && sampleSize >= 100 {
 		status = "PASS"
 		title = "Moderate association"
 		explanation = fmt.Sprintf("q=%.4f (FDR), N=%d", qValue, sampleSize)
-	} else if qValue <= 0.05 {
+	} else if qValue <= policy.Alpha {
 		status = "INCONCLUSIVE"
 		title = "Weak evidence"
 		explanation = fmt.Sprintf("q=%.4f but N=%d is low", qValue, sampleSize)
@@ -68,6 +70,7 @@ func determineVerdict(pValue, qValue, effectSize float64, sampleSize int, hasQVa
 		"SampleSize":  sampleSize,
 		"PValue":      pValue,
 		"EffectSize":  effectSize,
+		"Strength":    policy.EffectStrength(effectSize),
 	}
 }
 
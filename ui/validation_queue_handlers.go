@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"fmt"
+	"net/http"
+
+	domainDataset "gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/research"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleEnqueueValidations queues hypotheses of a workspace for re-validation in the order
+// listed, as one batch at the given priority
+func (s *Server) handleEnqueueValidations(c *gin.Context) {
+	var body struct {
+		HypothesisIDs []string `json:"hypothesis_ids"`
+		Priority      string   `json:"priority"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+	if len(body.HypothesisIDs) == 0 || len(body.HypothesisIDs) > models.MaxTriageBatch {
+		respondProblem(c, apperrors.InvalidInput(fmt.Sprintf("hypothesis_ids must list 1 to %d hypotheses", models.MaxTriageBatch)))
+		return
+	}
+	workspace, ok := s.validationQueueWorkspace(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	for _, id := range body.HypothesisIDs {
+		h, err := s.researchStorage.GetByID(ctx, id)
+		if err != nil || h == nil || h.WorkspaceID != string(workspace.ID) {
+			respondProblem(c, apperrors.InvalidInput(fmt.Sprintf("Hypothesis %s is not in this workspace", id)))
+			return
+		}
+	}
+
+	capacity := research.ValidationCapacity(workspace.Metadata)
+	batchID, queued, err := s.validationQueue.Enqueue(string(workspace.ID), body.HypothesisIDs, body.Priority, capacity)
+	if err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"batch_id":   batchID,
+		"queued":     queued,
+		"skipped":    len(body.HypothesisIDs) - len(queued), // already queued or running
+		"capacity":   capacity,
+		"events_url": "/api/workspaces/" + string(workspace.ID) + "/validation-queue/events",
+	})
+}
+
+// handleGetValidationQueue lists a workspace's running, queued and recently finished validations
+func (s *Server) handleGetValidationQueue(c *gin.Context) {
+	workspace, ok := s.validationQueueWorkspace(c)
+	if !ok {
+		return
+	}
+	items := s.validationQueue.Snapshot(string(workspace.ID))
+	c.JSON(http.StatusOK, gin.H{
+		"workspace_id": workspace.ID,
+		"capacity":     research.ValidationCapacity(workspace.Metadata),
+		"items":        items,
+		"count":        len(items),
+	})
+}
+
+// handleCancelValidationBatch withdraws the validations of a batch that have not started
+func (s *Server) handleCancelValidationBatch(c *gin.Context) {
+	workspace, ok := s.validationQueueWorkspace(c)
+	if !ok {
+		return
+	}
+	cancelled := s.validationQueue.Cancel(string(workspace.ID), c.Param("batchId"))
+	c.JSON(http.StatusOK, gin.H{"batch_id": c.Param("batchId"), "cancelled": cancelled})
+}
+
+// handleValidationQueueEvents streams the workspace's validation starts and verdicts over SSE
+func (s *Server) handleValidationQueueEvents(c *gin.Context) {
+	workspace, ok := s.validationQueueWorkspace(c)
+	if !ok {
+		return
+	}
+	if s.sseHub == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Event stream not available"})
+		return
+	}
+	s.sseHub.StreamTopic(c, research.ValidationQueueTopic(string(workspace.ID)))
+}
+
+// validationQueueWorkspace loads a workspace owned by the default user for queue requests
+func (s *Server) validationQueueWorkspace(c *gin.Context) (*domainDataset.Workspace, bool) {
+	if s.validationQueue == nil || s.researchStorage == nil || s.workspaceRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Validation queue not available"})
+		return nil, false
+	}
+	return s.ownedWorkspace(c)
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"gohypo/domain/stats"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetWarningCatalog returns every warning code with its description, severity and
+// remediation, most serious first, for clients rendering warnings of their own
+func (s *Server) handleGetWarningCatalog(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"warnings": stats.WarningCatalog()})
+}
+
+// describeWarnings looks up codes in the catalog, most serious first
+func describeWarnings(codes []string) []stats.WarningInfo {
+	infos := make([]stats.WarningInfo, 0, len(codes))
+	for _, code := range codes {
+		infos = append(infos, stats.DescribeWarning(stats.WarningCode(code)))
+	}
+	stats.SortWarnings(infos)
+	return infos
+}
+
+// warningTooltipStyle styles warning tooltips; pages showing them include it once
+const warningTooltipStyle = `details.warning { display: inline-block; margin: .1rem 0; }
+details.warning summary { cursor: pointer; list-style: none; display: inline-block; padding: .05rem .5rem; border-radius: 999px; font-size: .8rem; font-weight: 600; }
+details.warning summary::-webkit-details-marker { display: none; }
+details.warning.critical summary { background: #fee2e2; color: #991b1b; }
+details.warning.caution summary { background: #fef3c7; color: #92400e; }
+details.warning.info summary { background: #e0f2fe; color: #075985; }
+details.warning p { margin: .35rem 0; max-width: 36rem; font-size: .85rem; }`
+
+var warningTooltipTemplate = template.Must(template.New("warning_tooltip").Parse(
+	`<details class="warning {{.Severity}}"><summary title="{{.Title}}">{{.Code}}</summary>` +
+		`<p><strong>{{.Title}}</strong> ({{.Severity}}). {{.Description}}</p>` +
+		`<p><strong>What to do:</strong> {{.Remediation}}</p></details>`))
+
+// warningTooltip renders a warning code as a badge that expands to its description and
+// remediation
+func warningTooltip(code string) template.HTML {
+	var b strings.Builder
+	if err := warningTooltipTemplate.Execute(&b, stats.DescribeWarning(stats.WarningCode(code))); err != nil {
+		return template.HTML(template.HTMLEscapeString(code))
+	}
+	return template.HTML(b.String())
+}
+
+// warningsMarkdown renders warnings as a report's data caveats section; empty without any
+func warningsMarkdown(warnings []stats.WarningInfo) string {
+	if len(warnings) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("### Data caveats\n\n")
+	for _, w := range warnings {
+		fmt.Fprintf(&b, "- **%s** (`%s`, %s): %s *What to do:* %s\n", w.Title, w.Code, w.Severity, w.Description, w.Remediation)
+	}
+	return b.String()
+}
@@ -0,0 +1,311 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gohypo/domain/core"
+	apperrors "gohypo/internal/errors"
+	"gohypo/internal/webhook"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultDeliveryLimit and maxDeliveryLimit bound how many deliveries the log returns
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// SetWebhookDispatcher publishes dataset_ready events through dispatcher and lets the
+// delivery log queue redeliveries on it
+func (s *Server) SetWebhookDispatcher(dispatcher *webhook.Dispatcher) {
+	s.webhookDispatcher = dispatcher
+}
+
+// publishDatasetReady announces a processed dataset to its workspace's webhooks
+func (s *Server) publishDatasetReady(datasetID core.ID) {
+	if s.webhookDispatcher == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ds, err := s.datasetRepository.GetByID(ctx, datasetID)
+	if err != nil {
+		log.Printf("[Webhooks] ❌ Could not load dataset %s for dataset_ready: %v", datasetID, err)
+		return
+	}
+	err = s.webhookDispatcher.Publish(ctx, string(ds.WorkspaceID), models.WebhookDatasetReady, gin.H{
+		"dataset_id":   ds.ID,
+		"name":         ds.DisplayName,
+		"filename":     ds.OriginalFilename,
+		"source":       ds.Source,
+		"record_count": ds.RecordCount,
+		"field_count":  ds.FieldCount,
+	})
+	if err != nil {
+		log.Printf("[Webhooks] ❌ Failed to publish dataset_ready for dataset %s: %v", datasetID, err)
+	}
+}
+
+// handleListWebhooks returns a workspace's webhooks without their secrets
+func (s *Server) handleListWebhooks(c *gin.Context) {
+	workspaceID, ok := s.webhookWorkspace(c)
+	if !ok {
+		return
+	}
+	hooks, err := s.webhooks.ListWebhooks(c.Request.Context(), workspaceID)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list webhooks"))
+		return
+	}
+	for _, hook := range hooks {
+		hook.Secret = ""
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks": hooks, "events": models.WebhookEvents})
+}
+
+// handleCreateWebhook registers an endpoint for some of a workspace's events. The signing
+// secret is only ever returned here.
+func (s *Server) handleCreateWebhook(c *gin.Context) {
+	workspaceID, ok := s.webhookWorkspace(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		URL    string                `json:"url" binding:"required"`
+		Events []models.WebhookEvent `json:"events"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+	hook, err := models.NewWebhook(workspaceID, req.URL, req.Events)
+	if err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	if userID, err := s.getDefaultUserID(c.Request.Context()); err == nil {
+		hook.CreatedBy = userID.String()
+	}
+	if err := s.webhooks.CreateWebhook(c.Request.Context(), hook); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to create webhook"))
+		return
+	}
+	c.JSON(http.StatusCreated, hook)
+}
+
+// handleSetWebhookActive pauses or resumes a webhook; deliveries queued while it is paused
+// fail without being sent
+func (s *Server) handleSetWebhookActive(c *gin.Context) {
+	workspaceID, ok := s.webhookWorkspace(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Active *bool `json:"active" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, apperrors.InvalidInput("Invalid request body"))
+		return
+	}
+	if err := s.webhooks.SetWebhookActive(c.Request.Context(), workspaceID, c.Param("webhookId"), *req.Active); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to update webhook"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": c.Param("webhookId"), "active": *req.Active})
+}
+
+// handleDeleteWebhook removes a webhook and its delivery log
+func (s *Server) handleDeleteWebhook(c *gin.Context) {
+	workspaceID, ok := s.webhookWorkspace(c)
+	if !ok {
+		return
+	}
+	if err := s.webhooks.DeleteWebhook(c.Request.Context(), workspaceID, c.Param("webhookId")); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to delete webhook"))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// handleListWebhookDeliveries returns a workspace's most recent deliveries, optionally only
+// one webhook's (?webhook=)
+func (s *Server) handleListWebhookDeliveries(c *gin.Context) {
+	workspaceID, ok := s.webhookWorkspace(c)
+	if !ok {
+		return
+	}
+	limit, err := deliveryLimit(c.Query("limit"))
+	if err != nil {
+		respondProblem(c, apperrors.InvalidInput(err.Error()))
+		return
+	}
+	deliveries, err := s.webhooks.ListDeliveries(c.Request.Context(), workspaceID, c.Query("webhook"), limit)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list webhook deliveries"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// handleRedeliverWebhook queues a delivery to be sent again now with a fresh set of attempts
+func (s *Server) handleRedeliverWebhook(c *gin.Context) {
+	workspaceID, ok := s.webhookWorkspace(c)
+	if !ok {
+		return
+	}
+	if s.webhookDispatcher == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Webhook dispatcher not available"})
+		return
+	}
+	delivery, err := s.webhookDispatcher.Redeliver(c.Request.Context(), workspaceID, c.Param("deliveryId"))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to redeliver webhook"))
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// handleWebhookDeliveriesPage renders a workspace's webhooks and their delivery log
+func (s *Server) handleWebhookDeliveriesPage(c *gin.Context) {
+	workspaceID, ok := s.webhookWorkspace(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	hooks, err := s.webhooks.ListWebhooks(ctx, workspaceID)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list webhooks"))
+		return
+	}
+	deliveries, err := s.webhooks.ListDeliveries(ctx, workspaceID, c.Query("webhook"), defaultDeliveryLimit*2)
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to list webhook deliveries"))
+		return
+	}
+	urls := make(map[string]string, len(hooks))
+	for _, hook := range hooks {
+		urls[hook.ID] = hook.URL
+	}
+	var page strings.Builder
+	data := gin.H{
+		"WorkspaceID": workspaceID,
+		"Webhooks":    hooks,
+		"Deliveries":  deliveries,
+		"URLs":        urls,
+		"Filter":      c.Query("webhook"),
+		"Title":       s.branding.Title("Webhook deliveries"),
+		"Style":       brandStyle(s.branding),
+	}
+	if err := webhookDeliveriesTemplate.Execute(&page, data); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to render webhook deliveries page"))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page.String()))
+}
+
+// webhookWorkspace checks webhooks are available and the caller owns the route's workspace,
+// returning its ID
+func (s *Server) webhookWorkspace(c *gin.Context) (string, bool) {
+	if s.webhooks == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Webhooks not available"})
+		return "", false
+	}
+	workspace, ok := s.ownedWorkspace(c)
+	if !ok {
+		return "", false
+	}
+	return string(workspace.ID), true
+}
+
+// deliveryLimit parses the ?limit= of a delivery listing
+func deliveryLimit(raw string) (int, error) {
+	if raw == "" {
+		return defaultDeliveryLimit, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 {
+		return 0, fmt.Errorf("limit must be a positive integer")
+	}
+	if limit > maxDeliveryLimit {
+		limit = maxDeliveryLimit
+	}
+	return limit, nil
+}
+
+var webhookDeliveriesTemplate = template.Must(template.New("webhook_deliveries").Funcs(template.FuncMap{
+	"when": func(t time.Time) string { return t.Local().Format("2006-01-02 15:04:05") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+{{.Style}}
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 0; color: var(--brand-accent); font-size: 14px; }
+header { padding: 12px 20px; border-bottom: 3px solid var(--brand-primary); }
+header h1 { font-size: 16px; margin: 0; }
+main { padding: 8px 20px 40px; }
+h2 { font-size: 14px; margin: 20px 0 8px; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #f3f4f6; vertical-align: top; }
+th { color: #6b7280; font-weight: 600; }
+.meta, .empty { color: #6b7280; }
+.mono { font-family: ui-monospace, Menlo, monospace; font-size: 12px; }
+.status { padding: .05rem .5rem; border-radius: 999px; font-size: 12px; font-weight: 600; }
+.succeeded { background: #dcfce7; color: #166534; }
+.pending { background: #fef3c7; color: #92400e; }
+.failed { background: #fee2e2; color: #991b1b; }
+.error { color: #991b1b; max-width: 28rem; overflow-wrap: anywhere; }
+button { font-size: 12px; }
+</style>
+</head>
+<body>
+<header><h1>Webhooks · <span class="meta">{{.WorkspaceID}}</span></h1></header>
+<main>
+<h2>Endpoints</h2>
+{{if .Webhooks}}<table>
+<tr><th>URL</th><th>Events</th><th>State</th><th></th></tr>
+{{range .Webhooks}}<tr>
+<td class="mono">{{.URL}}</td>
+<td>{{range $i, $e := .Events}}{{if $i}}, {{end}}{{$e}}{{end}}</td>
+<td>{{if .Active}}active{{else}}<span class="meta">paused</span>{{end}}</td>
+<td><a href="?webhook={{.ID}}">deliveries</a></td>
+</tr>{{end}}
+</table>{{else}}<p class="empty">No webhooks yet. Register one with POST /api/workspaces/{{.WorkspaceID}}/webhooks.</p>{{end}}
+
+<h2>Deliveries{{if .Filter}} to {{index .URLs .Filter}} · <a href="?">all</a>{{end}}</h2>
+{{if .Deliveries}}<table>
+<tr><th>Queued</th><th>Event</th><th>Endpoint</th><th>Status</th><th>Attempts</th><th>Response</th><th>Next attempt</th><th></th></tr>
+{{range .Deliveries}}<tr>
+<td>{{when .CreatedAt}}</td>
+<td>{{.Event}}</td>
+<td class="mono">{{index $.URLs .WebhookID}}</td>
+<td><span class="status {{.Status}}">{{.Status}}</span></td>
+<td>{{.Attempts}}</td>
+<td>{{if .ResponseStatus}}{{.ResponseStatus}}{{end}}{{if .LastError}} <div class="error">{{.LastError}}</div>{{end}}</td>
+<td>{{if eq .Status "pending"}}{{when .NextAttemptAt}}{{else if .DeliveredAt}}<span class="meta">delivered {{when .DeliveredAt}}</span>{{end}}</td>
+<td><button data-id="{{.ID}}">Redeliver</button></td>
+</tr>{{end}}
+</table>{{else}}<p class="empty">No deliveries yet.</p>{{end}}
+</main>
+<script>
+document.querySelectorAll("button[data-id]").forEach(function (button) {
+  button.addEventListener("click", function () {
+    button.disabled = true;
+    fetch("/api/workspaces/" + encodeURIComponent({{.WorkspaceID}}) + "/webhooks/deliveries/" + encodeURIComponent(button.dataset.id) + "/redeliver", { method: "POST" })
+      .then(function (resp) { if (resp.ok) { location.reload(); } else { button.disabled = false; button.textContent = "Retry failed"; } });
+  });
+});
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"gohypo/domain/core"
+	domainDataset "gohypo/domain/dataset"
+	apperrors "gohypo/internal/errors"
+	"gohypo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// widgetRefreshSeconds is how often an embedded widget polls for status
+const widgetRefreshSeconds = 5
+
+// handleEnableStatusWidget issues the workspace's status widget token, replacing any earlier
+// one. The token is returned only in this response, with the URLs to embed.
+func (s *Server) handleEnableStatusWidget(c *gin.Context) {
+	workspace, ok := s.statusWidgetWorkspace(c)
+	if !ok {
+		return
+	}
+	token, hash, err := models.NewStatusWidgetToken(string(workspace.ID))
+	if err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to create widget token"))
+		return
+	}
+	if workspace.Metadata == nil {
+		workspace.Metadata = make(map[string]interface{})
+	}
+	workspace.Metadata[models.StatusWidgetTokenKey] = hash
+	if err := s.workspaceRepository.Update(c.Request.Context(), workspace); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to update workspace"))
+		return
+	}
+	embedURL := "/widget/" + token
+	c.JSON(http.StatusCreated, gin.H{
+		"workspace_id": workspace.ID,
+		"token":        token,
+		"status_url":   embedURL + "/status",
+		"embed_url":    embedURL,
+		"iframe":       fmt.Sprintf(`<iframe src="%s" width="320" height="120" frameborder="0" title="Research run status"></iframe>`, embedURL),
+	})
+}
+
+// handleDisableStatusWidget takes the workspace's status widget offline
+func (s *Server) handleDisableStatusWidget(c *gin.Context) {
+	workspace, ok := s.statusWidgetWorkspace(c)
+	if !ok {
+		return
+	}
+	if _, enabled := workspace.Metadata[models.StatusWidgetTokenKey]; enabled {
+		delete(workspace.Metadata, models.StatusWidgetTokenKey)
+		if err := s.workspaceRepository.Update(c.Request.Context(), workspace); err != nil {
+			respondProblem(c, apperrors.Wrap(err, "Failed to update workspace"))
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"workspace_id": workspace.ID, "enabled": false})
+}
+
+// statusWidgetWorkspace loads a workspace owned by the default user for widget management
+func (s *Server) statusWidgetWorkspace(c *gin.Context) (*domainDataset.Workspace, bool) {
+	if s.workspaceRepository == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Status widget not available"})
+		return nil, false
+	}
+	return s.ownedWorkspace(c)
+}
+
+// handleWidgetStatus returns the latest run status of the token's workspace as JSON. It needs
+// no login and may be fetched from any origin; an unknown or replaced token is a plain 404.
+func (s *Server) handleWidgetStatus(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Access-Control-Allow-Origin", "*")
+
+	status, err := s.widgetRunStatus(c)
+	if err != nil {
+		log.Printf("[Widget] Refused status widget token: %v", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// handleStatusWidget renders the embeddable widget page, which polls the JSON status. It is
+// meant for iframes in internal portals, so it allows framing from any origin.
+func (s *Server) handleStatusWidget(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Header("Content-Security-Policy", "frame-ancestors *")
+
+	status, err := s.widgetRunStatus(c)
+	if err != nil {
+		log.Printf("[Widget] Refused status widget token: %v", err)
+		c.Data(http.StatusNotFound, "text/html; charset=utf-8", []byte(sharedNotFoundPage))
+		return
+	}
+	var page strings.Builder
+	data := gin.H{
+		"Status":    status,
+		"StatusURL": "/widget/" + c.Param("token") + "/status",
+		"Refresh":   widgetRefreshSeconds * 1000,
+		"Brand":     s.branding,
+		"Style":     brandStyle(s.branding),
+	}
+	if err := statusWidgetTemplate.Execute(&page, data); err != nil {
+		respondProblem(c, apperrors.Wrap(err, "Failed to render status widget"))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page.String()))
+}
+
+// widgetRunStatus resolves a widget token to the status of its workspace's latest run
+func (s *Server) widgetRunStatus(c *gin.Context) (*models.RunStatus, error) {
+	if s.workspaceRepository == nil || s.sessionManager == nil {
+		return nil, fmt.Errorf("status widget not available")
+	}
+	ctx := c.Request.Context()
+	token := c.Param("token")
+	workspaceID, ok := models.StatusWidgetWorkspace(token)
+	if !ok {
+		return nil, fmt.Errorf("malformed token")
+	}
+	workspace, err := s.workspaceRepository.GetByID(ctx, core.ID(workspaceID))
+	if err != nil {
+		return nil, fmt.Errorf("workspace %s: %w", workspaceID, err)
+	}
+	hash, _ := workspace.Metadata[models.StatusWidgetTokenKey].(string)
+	if hash == "" || subtle.ConstantTimeCompare([]byte(hash), []byte(models.HashShareToken(token))) != 1 {
+		return nil, fmt.Errorf("token does not match the widget of workspace %s", workspaceID)
+	}
+
+	sessions, err := s.sessionManager.ListSessions(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	var latest *models.ResearchSession
+	for _, session := range sessions { // newest first
+		if session.WorkspaceID.String() == workspaceID {
+			latest = session
+			break
+		}
+	}
+	status := models.NewRunStatus(latest, time.Now())
+	return &status, nil
+}
+
+var statusWidgetTemplate = template.Must(template.New("status_widget").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex, nofollow">
+<title>Research run status · {{.Brand.Name}}</title>
+<style>
+{{.Style}}
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 0; padding: 12px; color: var(--brand-accent); font-size: 13px; }
+.stage { font-weight: 600; text-transform: capitalize; }
+.bar { height: 8px; background: #e5e7eb; border-radius: 4px; margin: 8px 0; overflow: hidden; }
+.fill { height: 100%; background: var(--brand-primary); }
+.meta { color: #6b7280; }
+</style>
+</head>
+<body>
+<div class="stage" id="stage">{{.Status.Stage}}</div>
+<div class="bar"><div class="fill" id="fill" style="width: {{printf "%.0f" .Status.Progress}}%"></div></div>
+<div class="meta"><span id="progress">{{printf "%.0f" .Status.Progress}}%</span> <span id="eta"></span></div>
+<script>
+(function () {
+  var statusURL = {{.StatusURL}};
+  function render(s) {
+    document.getElementById("stage").textContent = s.stage;
+    var pct = Math.round(s.progress || 0);
+    document.getElementById("fill").style.width = pct + "%";
+    document.getElementById("progress").textContent = pct + "%";
+    var eta = "";
+    if (s.eta_seconds != null) {
+      var m = Math.round(s.eta_seconds / 60);
+      eta = m < 1 ? "· under a minute left" : "· about " + m + " min left";
+    }
+    document.getElementById("eta").textContent = eta;
+  }
+  function poll() {
+    fetch(statusURL, {cache: "no-store"}).then(function (r) { return r.ok ? r.json() : null; })
+      .then(function (s) { if (s) { render(s); } }).catch(function () {});
+  }
+  render({{.Status}});
+  setInterval(poll, {{.Refresh}});
+})();
+</script>
+</body>
+</html>
+`))